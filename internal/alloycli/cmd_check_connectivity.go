@@ -0,0 +1,149 @@
+package alloycli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/secrets"
+)
+
+func checkConnectivityCommand() *cobra.Command {
+	cc := &alloyCheckConnectivity{
+		validate: alloyValidate{
+			minStability: featuregate.StabilityGenerallyAvailable,
+			configFormat: "alloy",
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "check-connectivity [flags] path",
+		Short: "Check the connectivity of the remote endpoints of a configuration",
+		Long: `The check-connectivity subcommand evaluates the configuration directory or
+file path like the validate subcommand does, without starting any component,
+then sends a request with no data to every remote endpoint of the following
+components:
+
+* prometheus.remote_write
+* loki.write
+* otelcol.exporter.otlp
+* otelcol.exporter.otlphttp
+* pyroscope.write
+
+Each request uses the authentication, TLS, and proxy settings of its endpoint,
+and the result of every request is printed.
+
+check-connectivity exits with a non-zero exit code if the configuration
+contains errors or if any endpoint fails the check.
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cc.Run(args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&cc.validate.configFormat, "config.format", cc.validate.configFormat, fmt.Sprintf("The format of the source file. Supported formats: %s.", supportedFormatsList()))
+	cmd.Flags().BoolVar(&cc.validate.configBypassConversionErrors, "config.bypass-conversion-errors", cc.validate.configBypassConversionErrors, "Enable bypassing errors when converting")
+	cmd.Flags().StringVar(&cc.validate.configExtraArgs, "config.extra-args", cc.validate.configExtraArgs, "Extra arguments from the original format used by the converter. Multiple arguments can be passed by separating them with a space.")
+	cmd.Flags().Var(&cc.validate.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
+	cmd.Flags().BoolVar(&cc.validate.enableCommunityComps, "feature.community-components.enabled", cc.validate.enableCommunityComps, "Enable community components.")
+	return cmd
+}
+
+type alloyCheckConnectivity struct {
+	validate alloyValidate
+}
+
+// connectivityCheck is the result of checking an endpoint of a component.
+type connectivityCheck struct {
+	componentID string
+	component.ConnectivityResult
+}
+
+func (cc *alloyCheckConnectivity) Run(configPath string) error {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	storagePath, err := os.MkdirTemp("", "alloy-check-connectivity-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(storagePath)
+
+	f, err := cc.validate.load(configPath, storagePath)
+	if err != nil {
+		return err
+	}
+
+	checks := runConnectivityChecks(ctx, component.GetAllComponents(f, component.InfoOptions{GetArguments: true}))
+	if len(checks) == 0 {
+		fmt.Println("No remote endpoint to check.")
+		return nil
+	}
+
+	var failed int
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, check := range checks {
+		status, detail := color.GreenString("PASS"), ""
+		if check.Err != nil {
+			failed++
+			// The errors may quote the credentials of the request.
+			status, detail = color.RedString("FAIL"), secrets.Scrub(check.Err.Error())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status, check.componentID, secrets.Scrub(check.Endpoint), detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d endpoints failed the connectivity check", failed, len(checks))
+	}
+	return nil
+}
+
+// runConnectivityChecks concurrently checks the endpoints of the components
+// whose arguments implement component.ConnectivityChecker, and returns the
+// results sorted by component.
+func runConnectivityChecks(ctx context.Context, infos []*component.Info) []connectivityCheck {
+	var (
+		wg     sync.WaitGroup
+		mut    sync.Mutex
+		checks []connectivityCheck
+	)
+	for _, info := range infos {
+		checker, ok := info.Arguments.(component.ConnectivityChecker)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string, checker component.ConnectivityChecker) {
+			defer wg.Done()
+			results := checker.CheckConnectivity(ctx)
+
+			mut.Lock()
+			defer mut.Unlock()
+			for _, res := range results {
+				checks = append(checks, connectivityCheck{componentID: id, ConnectivityResult: res})
+			}
+		}(info.ID.String(), checker)
+	}
+	wg.Wait()
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].componentID < checks[j].componentID
+	})
+	return checks
+}
@@ -0,0 +1,40 @@
+package alloycli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChecker []component.ConnectivityResult
+
+func (c fakeChecker) CheckConnectivity(context.Context) []component.ConnectivityResult { return c }
+
+func TestRunConnectivityChecks(t *testing.T) {
+	errUnauthorized := errors.New("server returned HTTP status 401 Unauthorized")
+
+	infos := []*component.Info{
+		{
+			ID: component.ID{LocalID: "prometheus.remote_write.default"},
+			Arguments: fakeChecker{
+				{Endpoint: "https://a.example.com/api/prom/push"},
+				{Endpoint: "https://b.example.com/api/prom/push", Err: errUnauthorized},
+			},
+		},
+		{ID: component.ID{LocalID: "prometheus.scrape.default"}, Arguments: struct{}{}},
+		{
+			ID:        component.ID{LocalID: "loki.write.default"},
+			Arguments: fakeChecker{{Endpoint: "https://logs.example.com/loki/api/v1/push"}},
+		},
+	}
+
+	checks := runConnectivityChecks(context.Background(), infos)
+	require.Equal(t, []connectivityCheck{
+		{componentID: "loki.write.default", ConnectivityResult: component.ConnectivityResult{Endpoint: "https://logs.example.com/loki/api/v1/push"}},
+		{componentID: "prometheus.remote_write.default", ConnectivityResult: component.ConnectivityResult{Endpoint: "https://a.example.com/api/prom/push"}},
+		{componentID: "prometheus.remote_write.default", ConnectivityResult: component.ConnectivityResult{Endpoint: "https://b.example.com/api/prom/push", Err: errUnauthorized}},
+	}, checks)
+}
@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/grafana/alloy/internal/alloyseed"
 	"github.com/grafana/alloy/internal/boringcrypto"
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/configaudit"
 	"github.com/grafana/alloy/internal/converter"
 	convert_diag "github.com/grafana/alloy/internal/converter/diag"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -37,11 +39,13 @@ import (
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/runtime/tracing"
 	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/service/clocksync"
 	httpservice "github.com/grafana/alloy/internal/service/http"
 	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/grafana/alloy/internal/service/livedebugging"
 	otel_service "github.com/grafana/alloy/internal/service/otel"
 	remotecfgservice "github.com/grafana/alloy/internal/service/remotecfg"
+	"github.com/grafana/alloy/internal/service/shadow"
 	uiservice "github.com/grafana/alloy/internal/service/ui"
 	"github.com/grafana/alloy/internal/static/config/instrumentation"
 	"github.com/grafana/alloy/internal/usagestats"
@@ -64,6 +68,8 @@ func runCommand() *cobra.Command {
 		clusterAdvInterfaces:  advertise.DefaultInterfaces,
 		clusterMaxJoinPeers:   5,
 		clusterRejoinInterval: 60 * time.Second,
+		evaluationConcurrency: 1,
+		startupGateTimeout:    time.Minute,
 	}
 
 	cmd := &cobra.Command{
@@ -91,6 +97,7 @@ debugging UI can be changed by providing a different value to
 Additionally, the HTTP server exposes the following debug endpoints:
 
   /debug/pprof   Go performance profiling tools
+  /-/support     Support bundle for troubleshooting
 
 If reloading the config dir/file-path fails, Grafana Alloy will continue running in
 its last valid state. Components which failed may be be listed as unhealthy,
@@ -111,6 +118,9 @@ depending on the nature of the reload error.
 	cmd.Flags().StringVar(&r.uiPrefix, "server.http.ui-path-prefix", r.uiPrefix, "Prefix to serve the HTTP UI at")
 	cmd.Flags().
 		BoolVar(&r.enablePprof, "server.http.enable-pprof", r.enablePprof, "Enable /debug/pprof profiling endpoints.")
+	cmd.Flags().
+		BoolVar(&r.disableSupportBundle, "server.http.disable-support-bundle", r.disableSupportBundle, "Disable the /-/support support bundle endpoint.")
+	cmd.Flags().StringVar(&r.adminTokenFile, "server.http.admin-token-file", r.adminTokenFile, "Path to a file containing the bearer token required to pause and resume components, to read their exports, to download support bundles, to read the config history, and to use shadow evaluation through the API. Disabled when empty.")
 
 	// Cluster flags
 	cmd.Flags().
@@ -140,6 +150,8 @@ depending on the nature of the reload error.
 	cmd.Flags().StringVar(&r.configFormat, "config.format", r.configFormat, fmt.Sprintf("The format of the source file. Supported formats: %s.", supportedFormatsList()))
 	cmd.Flags().BoolVar(&r.configBypassConversionErrors, "config.bypass-conversion-errors", r.configBypassConversionErrors, "Enable bypassing errors when converting")
 	cmd.Flags().StringVar(&r.configExtraArgs, "config.extra-args", r.configExtraArgs, "Extra arguments from the original format used by the converter. Multiple arguments can be passed by separating them with a space.")
+	cmd.Flags().StringVar(&r.configAuditLogPath, "config.audit-log-path", r.configAuditLogPath, "Path of the file to which every load of the config is appended. Disabled when empty.")
+	cmd.Flags().StringSliceVar(&r.configEnvAllowlist, "config.env-allowlist", r.configEnvAllowlist, "Comma-separated list of environment variables the config can read with env, which may be glob patterns. When set, unset environment variables without a default value are reported as errors.")
 
 	// Misc flags
 	cmd.Flags().
@@ -147,6 +159,9 @@ depending on the nature of the reload error.
 	cmd.Flags().StringVar(&r.storagePath, "storage.path", r.storagePath, "Base directory where components can store data")
 	cmd.Flags().Var(&r.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
 	cmd.Flags().BoolVar(&r.enableCommunityComps, "feature.community-components.enabled", r.enableCommunityComps, "Enable community components.")
+	cmd.Flags().IntVar(&r.evaluationConcurrency, "runtime.evaluation-concurrency", r.evaluationConcurrency, "Number of components evaluated at the same time when loading the config. Components are evaluated one at a time when 1.")
+	cmd.Flags().DurationVar(&r.evaluationTimeout, "runtime.evaluation-timeout", r.evaluationTimeout, "How long loading the config waits for the evaluation of a component before evaluating its dependants without it. Disabled when 0.")
+	cmd.Flags().DurationVar(&r.startupGateTimeout, "runtime.startup-gate-timeout", r.startupGateTimeout, "How long components wait for the components they send data to be ready before starting. Disabled when 0.")
 	return cmd
 }
 
@@ -157,6 +172,8 @@ type alloyRun struct {
 	minStability                 featuregate.Stability
 	uiPrefix                     string
 	enablePprof                  bool
+	disableSupportBundle         bool
+	adminTokenFile               string
 	disableReporting             bool
 	clusterEnabled               bool
 	clusterNodeName              string
@@ -171,7 +188,12 @@ type alloyRun struct {
 	configFormat                 string
 	configBypassConversionErrors bool
 	configExtraArgs              string
+	configEnvAllowlist           []string
+	configAuditLogPath           string
 	enableCommunityComps         bool
+	evaluationConcurrency        int
+	evaluationTimeout            time.Duration
+	startupGateTimeout           time.Duration
 }
 
 func (fr *alloyRun) Run(configPath string) error {
@@ -230,14 +252,29 @@ func (fr *alloyRun) Run(configPath string) error {
 	reg := prometheus.DefaultRegisterer
 	reg.MustRegister(newResourcesCollector(l))
 
+	adminToken, err := readAdminToken(fr.adminTokenFile)
+	if err != nil {
+		return err
+	}
+
+	auditLog, err := configaudit.New(l, fr.configAuditLogPath)
+	if err != nil {
+		return err
+	}
+	defer auditLog.Close()
+
 	// There's a cyclic dependency between the definition of the Alloy controller,
 	// the reload/ready functions, and the HTTP service.
 	//
 	// To work around this, we lazily create variables for the functions the HTTP
 	// service needs and set them after the Alloy controller exists.
 	var (
-		reload func() (*alloy_runtime.Source, error)
+		reload func(trigger configaudit.Trigger, actor string) (*alloy_runtime.Source, error)
 		ready  func() bool
+
+		// loadedSource is the last config successfully loaded, for support
+		// bundles.
+		loadedSource atomic.Pointer[alloy_runtime.Source]
 	)
 
 	clusterService, err := buildClusterService(clusterOptions{
@@ -268,12 +305,22 @@ func (fr *alloyRun) Run(configPath string) error {
 		Tracer:   t,
 		Gatherer: prometheus.DefaultGatherer,
 
-		ReadyFunc:  func() bool { return ready() },
-		ReloadFunc: func() (*alloy_runtime.Source, error) { return reload() },
+		ReadyFunc: func() bool { return ready() },
+		ReloadFunc: func(actor string) (*alloy_runtime.Source, error) {
+			return reload(configaudit.TriggerAPI, actor)
+		},
+		ConfigHistoryFunc: auditLog.History,
 
 		HTTPListenAddr:   fr.httpListenAddr,
 		MemoryListenAddr: fr.inMemoryAddr,
 		EnablePProf:      fr.enablePprof,
+		AdminToken:       adminToken,
+
+		DisableSupportBundle: fr.disableSupportBundle,
+		SupportBundle: httpservice.SupportBundleOptions{
+			SourceFunc: loadedSource.Load,
+			LogsFunc:   l.RecentLogs,
+		},
 	})
 
 	remoteCfgService, err := remotecfgservice.New(remotecfgservice.Options{
@@ -290,6 +337,7 @@ func (fr *alloyRun) Run(configPath string) error {
 	uiService := uiservice.New(uiservice.Options{
 		UIPrefix:        fr.uiPrefix,
 		CallbackManager: liveDebuggingService.Data().(livedebugging.CallbackManager),
+		AdminToken:      adminToken,
 	})
 
 	otelService := otel_service.New(l)
@@ -298,28 +346,45 @@ func (fr *alloyRun) Run(configPath string) error {
 	}
 
 	labelService := labelstore.New(l, reg)
+
+	clockSyncService := clocksync.New(clocksync.Options{
+		Logger:  log.With(l, "service", "clocksync"),
+		Metrics: reg,
+	})
+
+	shadowService := shadow.New(shadow.Options{
+		Logger:     log.With(l, "service", "shadow"),
+		AdminToken: adminToken,
+	})
 	alloyseed.Init(fr.storagePath, l)
 
 	f := alloy_runtime.New(alloy_runtime.Options{
-		Logger:               l,
-		Tracer:               t,
-		DataPath:             fr.storagePath,
-		Reg:                  reg,
-		MinStability:         fr.minStability,
-		EnableCommunityComps: fr.enableCommunityComps,
+		Logger:                l,
+		Tracer:                t,
+		DataPath:              fr.storagePath,
+		Reg:                   reg,
+		MinStability:          fr.minStability,
+		EnableCommunityComps:  fr.enableCommunityComps,
+		EvaluationConcurrency: fr.evaluationConcurrency,
+		EvaluationTimeout:     fr.evaluationTimeout,
+		StartupGateTimeout:    fr.startupGateTimeout,
 		Services: []service.Service{
+			clockSyncService,
 			clusterService,
 			httpService,
 			labelService,
 			liveDebuggingService,
 			otelService,
 			remoteCfgService,
+			shadowService,
 			uiService,
 		},
 	})
 
 	ready = f.Ready
-	reload = func() (*alloy_runtime.Source, error) {
+	reload = func(trigger configaudit.Trigger, actor string) (source *alloy_runtime.Source, err error) {
+		defer func() { auditLog.Record(trigger, actor, configPath, source, err) }()
+
 		alloySource, err := loadAlloySource(configPath, fr.configFormat, fr.configBypassConversionErrors, fr.configExtraArgs)
 		defer instrumentation.InstrumentSHA256(alloySource.SHA256())
 		defer instrumentation.InstrumentLoad(err == nil)
@@ -327,10 +392,14 @@ func (fr *alloyRun) Run(configPath string) error {
 		if err != nil {
 			return nil, fmt.Errorf("reading config path %q: %w", configPath, err)
 		}
+		if err := alloySource.CheckEnv(fr.configEnvAllowlist); err != nil {
+			return alloySource, fmt.Errorf("error during the initial load: %w", err)
+		}
 		if err := f.LoadSource(alloySource, nil); err != nil {
 			return alloySource, fmt.Errorf("error during the initial load: %w", err)
 		}
 
+		loadedSource.Store(alloySource)
 		return alloySource, nil
 	}
 
@@ -360,7 +429,7 @@ func (fr *alloyRun) Run(configPath string) error {
 	// Perform the initial reload. This is done after starting the HTTP server so
 	// that /metric and pprof endpoints are available while the Alloy controller
 	// is loading.
-	if source, err := reload(); err != nil {
+	if source, err := reload(configaudit.TriggerStartup, ""); err != nil {
 		var diags diag.Diagnostics
 		if errors.As(err, &diags) {
 			p := diag.NewPrinter(diag.PrinterConfig{
@@ -398,7 +467,7 @@ func (fr *alloyRun) Run(configPath string) error {
 		case <-ctx.Done():
 			return nil
 		case <-reloadSignal:
-			if _, err := reload(); err != nil {
+			if _, err := reload(configaudit.TriggerSignal, ""); err != nil {
 				level.Error(l).Log("msg", "failed to reload config", "err", err)
 			} else {
 				level.Info(l).Log("msg", "config reloaded")
@@ -500,6 +569,23 @@ func interruptContext() (context.Context, context.CancelFunc) {
 	return ctx, cancel
 }
 
+// readAdminToken returns the token of the file at path, or an empty token if
+// path is empty.
+func readAdminToken(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading admin token file: %w", err)
+	}
+	token := strings.TrimSpace(string(bb))
+	if token == "" {
+		return "", fmt.Errorf("admin token file %q is empty", path)
+	}
+	return token, nil
+}
+
 func splitPeers(s, sep string) []string {
 	if len(s) == 0 {
 		return []string{}
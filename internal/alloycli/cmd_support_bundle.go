@@ -0,0 +1,125 @@
+package alloycli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func supportBundleCommand() *cobra.Command {
+	sb := &alloySupportBundle{
+		serverAddr: "http://127.0.0.1:12345",
+		duration:   30 * time.Second,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "support-bundle [flags]",
+		Short: "Download a support bundle from a running Alloy instance",
+		Long: `The support-bundle subcommand downloads a support bundle from the /-/support
+endpoint of a running Alloy instance, and writes it to a zip file.
+
+The support bundle holds the version and command line flags of the instance,
+its config, the health and arguments of its components, a snapshot of its
+metrics, its recent logs, and pprof profiles if the instance runs with
+--server.http.enable-pprof. The secrets used by components are redacted.
+
+The endpoint requires the admin token of the instance, set with the
+--server.http.admin-token-file flag of the run command. Pass the same file
+with --admin-token-file.
+
+The command takes as long as the --duration flag, during which the CPU
+profile of the bundle is captured.
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return sb.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&sb.serverAddr, "server.http.addr", sb.serverAddr, "Address of the HTTP server of the Alloy instance")
+	cmd.Flags().StringVar(&sb.adminTokenFile, "admin-token-file", sb.adminTokenFile, "Path to a file containing the admin token of the Alloy instance")
+	cmd.Flags().DurationVar(&sb.duration, "duration", sb.duration, "Duration of the CPU profile of the bundle")
+	cmd.Flags().StringVarP(&sb.output, "output", "o", sb.output, "Path of the zip file to write. Defaults to alloy-support-bundle-<timestamp>.zip")
+	return cmd
+}
+
+type alloySupportBundle struct {
+	serverAddr     string
+	adminTokenFile string
+	duration       time.Duration
+	output         string
+}
+
+func (sb *alloySupportBundle) Run() error {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	token, err := readAdminToken(sb.adminTokenFile)
+	if err != nil {
+		return err
+	}
+
+	addr := sb.serverAddr
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("invalid server address %q: %w", sb.serverAddr, err)
+	}
+	u = u.JoinPath("/-/support")
+	u.RawQuery = url.Values{"duration": []string{sb.duration.String()}}.Encode()
+
+	output := sb.output
+	if output == "" {
+		output = fmt.Sprintf("alloy-support-bundle-%s.zip", time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	// Leave time for the instance to collect the rest of the bundle after the
+	// CPU profile.
+	cli := &http.Client{Timeout: sb.duration + time.Minute}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	fmt.Fprintf(os.Stderr, "Generating support bundle from %s, this takes %s...\n", u.Host, sb.duration)
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting support bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("requesting support bundle: unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing support bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Support bundle written to %s\n", output)
+	return nil
+}
@@ -0,0 +1,223 @@
+package alloycli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/tracing"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/service/clocksync"
+	httpservice "github.com/grafana/alloy/internal/service/http"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	otel_service "github.com/grafana/alloy/internal/service/otel"
+	remotecfgservice "github.com/grafana/alloy/internal/service/remotecfg"
+	"github.com/grafana/alloy/internal/service/shadow"
+	uiservice "github.com/grafana/alloy/internal/service/ui"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+func validateCommand() *cobra.Command {
+	v := &alloyValidate{
+		minStability: featuregate.StabilityGenerallyAvailable,
+		configFormat: "alloy",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "validate [flags] path",
+		Short: "Validate a configuration file",
+		Long: `The validate subcommand evaluates the configuration directory or file
+path like the run subcommand does, without starting any component.
+
+If path is a directory, all *.alloy files in that directory will be combined
+into a single unit. Subdirectories are not recursively searched for further merging.
+
+validate reports syntax errors, unknown components, invalid references, and
+invalid component arguments, such as OTTL statements which can't be parsed.
+Errors which can only be detected when a component starts, for example
+because a port is already in use, aren't reported.
+
+validate exits with a non-zero exit code if the configuration contains errors.
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return v.Run(args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&v.configFormat, "config.format", v.configFormat, fmt.Sprintf("The format of the source file. Supported formats: %s.", supportedFormatsList()))
+	cmd.Flags().BoolVar(&v.configBypassConversionErrors, "config.bypass-conversion-errors", v.configBypassConversionErrors, "Enable bypassing errors when converting")
+	cmd.Flags().StringVar(&v.configExtraArgs, "config.extra-args", v.configExtraArgs, "Extra arguments from the original format used by the converter. Multiple arguments can be passed by separating them with a space.")
+	cmd.Flags().StringSliceVar(&v.configEnvAllowlist, "config.env-allowlist", v.configEnvAllowlist, "Comma-separated list of environment variables the config can read with env, which may be glob patterns. When set, unset environment variables without a default value are reported as errors.")
+	cmd.Flags().Var(&v.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
+	cmd.Flags().BoolVar(&v.enableCommunityComps, "feature.community-components.enabled", v.enableCommunityComps, "Enable community components.")
+	return cmd
+}
+
+type alloyValidate struct {
+	minStability                 featuregate.Stability
+	configFormat                 string
+	configBypassConversionErrors bool
+	configExtraArgs              string
+	configEnvAllowlist           []string
+	enableCommunityComps         bool
+}
+
+func (fv *alloyValidate) Run(configPath string) error {
+	// Some services create directories when they're built, so use a temporary
+	// storage path which is removed once the config is validated.
+	storagePath, err := os.MkdirTemp("", "alloy-validate-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(storagePath)
+
+	_, err = fv.load(configPath, storagePath)
+	return err
+}
+
+// load evaluates the config at configPath with a runtime which doesn't start
+// any component, and prints the diagnostics of the config if it contains
+// errors.
+func (fv *alloyValidate) load(configPath, storagePath string) (*alloy_runtime.Runtime, error) {
+	alloySource, err := loadAlloySource(configPath, fv.configFormat, fv.configBypassConversionErrors, fv.configExtraArgs)
+	if err != nil {
+		return nil, fmt.Errorf("reading config path %q: %w", configPath, err)
+	}
+
+	f, err := fv.newRuntime(storagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = alloySource.CheckEnv(fv.configEnvAllowlist)
+	if err == nil {
+		err = f.LoadSource(alloySource, nil)
+	}
+	if err != nil {
+		var diags diag.Diagnostics
+		if errors.As(err, &diags) {
+			p := diag.NewPrinter(diag.PrinterConfig{
+				Color:              !color.NoColor,
+				ContextLinesBefore: 1,
+				ContextLinesAfter:  1,
+			})
+			_ = p.Fprint(os.Stderr, alloySource.RawConfigs(), diags)
+
+			// Print newline after the diagnostics.
+			fmt.Println()
+
+			return nil, fmt.Errorf("the configuration contains errors")
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// newRuntime creates an Alloy controller which only evaluates the config it
+// loads. The services are the same as the ones used by the run subcommand so
+// that their config blocks are validated too, but they're never started.
+func (fv *alloyValidate) newRuntime(storagePath string) (*alloy_runtime.Runtime, error) {
+	// Buffer logs until the logging block is evaluated, like the run
+	// subcommand does.
+	l, err := logging.NewDeferred(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("building logger: %w", err)
+	}
+
+	t, err := tracing.New(tracing.DefaultOptions)
+	if err != nil {
+		return nil, fmt.Errorf("building tracer: %w", err)
+	}
+
+	reg := prometheus.NewRegistry()
+
+	clusterService, err := buildClusterService(clusterOptions{
+		Log:               log.With(l, "service", "cluster"),
+		Tracer:            t,
+		Metrics:           reg,
+		ListenAddress:     "127.0.0.1:12345",
+		EnableDiscoveryV2: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpService := httpservice.New(httpservice.Options{
+		Logger:   log.With(l, "service", "http"),
+		Tracer:   t,
+		Gatherer: reg,
+
+		ReadyFunc:  func() bool { return false },
+		ReloadFunc: func(string) (*alloy_runtime.Source, error) { return nil, fmt.Errorf("reloading isn't supported") },
+
+		HTTPListenAddr:   "127.0.0.1:12345",
+		MemoryListenAddr: "alloy.internal:12345",
+	})
+
+	remoteCfgService, err := remotecfgservice.New(remotecfgservice.Options{
+		Logger:      log.With(l, "service", "remotecfg"),
+		StoragePath: storagePath,
+		Metrics:     reg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the remotecfg service: %w", err)
+	}
+
+	liveDebuggingService := livedebugging.New()
+
+	uiService := uiservice.New(uiservice.Options{
+		UIPrefix:        "/",
+		CallbackManager: liveDebuggingService.Data().(livedebugging.CallbackManager),
+	})
+
+	otelService := otel_service.New(l)
+	if otelService == nil {
+		return nil, fmt.Errorf("failed to create otel service")
+	}
+
+	labelService := labelstore.New(l, reg)
+
+	clockSyncService := clocksync.New(clocksync.Options{
+		Logger:  log.With(l, "service", "clocksync"),
+		Metrics: reg,
+	})
+
+	shadowService := shadow.New(shadow.Options{
+		Logger: log.With(l, "service", "shadow"),
+	})
+
+	return alloy_runtime.New(alloy_runtime.Options{
+		Logger:               l,
+		Tracer:               t,
+		DataPath:             storagePath,
+		Reg:                  reg,
+		MinStability:         fv.minStability,
+		EnableCommunityComps: fv.enableCommunityComps,
+		DryRun:               true,
+		Services: []service.Service{
+			clockSyncService,
+			clusterService,
+			httpService,
+			labelService,
+			liveDebuggingService,
+			otelService,
+			remoteCfgService,
+			shadowService,
+			uiService,
+		},
+	}), nil
+}
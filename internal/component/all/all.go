@@ -15,6 +15,7 @@ import (
 	_ "github.com/grafana/alloy/internal/component/discovery/file"                           // Import discovery.file
 	_ "github.com/grafana/alloy/internal/component/discovery/gce"                            // Import discovery.gce
 	_ "github.com/grafana/alloy/internal/component/discovery/hetzner"                        // Import discovery.hetzner
+	_ "github.com/grafana/alloy/internal/component/discovery/host"                           // Import discovery.host
 	_ "github.com/grafana/alloy/internal/component/discovery/http"                           // Import discovery.http
 	_ "github.com/grafana/alloy/internal/component/discovery/ionos"                          // Import discovery.ionos
 	_ "github.com/grafana/alloy/internal/component/discovery/kubelet"                        // Import discovery.kubelet
@@ -23,10 +24,14 @@ import (
 	_ "github.com/grafana/alloy/internal/component/discovery/linode"                         // Import discovery.linode
 	_ "github.com/grafana/alloy/internal/component/discovery/marathon"                       // Import discovery.marathon
 	_ "github.com/grafana/alloy/internal/component/discovery/nerve"                          // Import discovery.nerve
+	_ "github.com/grafana/alloy/internal/component/discovery/netbox"                         // Import discovery.netbox
 	_ "github.com/grafana/alloy/internal/component/discovery/nomad"                          // Import discovery.nomad
+	_ "github.com/grafana/alloy/internal/component/discovery/nomadallocations"               // Import discovery.nomad_allocations
 	_ "github.com/grafana/alloy/internal/component/discovery/openstack"                      // Import discovery.openstack
 	_ "github.com/grafana/alloy/internal/component/discovery/ovhcloud"                       // Import discovery.ovhcloud
+	_ "github.com/grafana/alloy/internal/component/discovery/podman"                         // Import discovery.podman
 	_ "github.com/grafana/alloy/internal/component/discovery/process"                        // Import discovery.process
+	_ "github.com/grafana/alloy/internal/component/discovery/proxmox"                        // Import discovery.proxmox
 	_ "github.com/grafana/alloy/internal/component/discovery/puppetdb"                       // Import discovery.puppetdb
 	_ "github.com/grafana/alloy/internal/component/discovery/relabel"                        // Import discovery.relabel
 	_ "github.com/grafana/alloy/internal/component/discovery/scaleway"                       // Import discovery.scaleway
@@ -34,12 +39,16 @@ import (
 	_ "github.com/grafana/alloy/internal/component/discovery/triton"                         // Import discovery.triton
 	_ "github.com/grafana/alloy/internal/component/discovery/uyuni"                          // Import discovery.uyuni
 	_ "github.com/grafana/alloy/internal/component/faro/receiver"                            // Import faro.receiver
+	_ "github.com/grafana/alloy/internal/component/instrumentation/process"                  // Import instrumentation.process
 	_ "github.com/grafana/alloy/internal/component/local/file"                               // Import local.file
 	_ "github.com/grafana/alloy/internal/component/local/file_match"                         // Import local.file_match
 	_ "github.com/grafana/alloy/internal/component/loki/echo"                                // Import loki.echo
+	_ "github.com/grafana/alloy/internal/component/loki/echo_to_file"                        // Import loki.echo_to_file
 	_ "github.com/grafana/alloy/internal/component/loki/process"                             // Import loki.process
+	_ "github.com/grafana/alloy/internal/component/loki/queue"                               // Import loki.queue
 	_ "github.com/grafana/alloy/internal/component/loki/relabel"                             // Import loki.relabel
 	_ "github.com/grafana/alloy/internal/component/loki/rules/kubernetes"                    // Import loki.rules.kubernetes
+	_ "github.com/grafana/alloy/internal/component/loki/secretfilter"                        // Import loki.secretfilter
 	_ "github.com/grafana/alloy/internal/component/loki/source/api"                          // Import loki.source.api
 	_ "github.com/grafana/alloy/internal/component/loki/source/aws_firehose"                 // Import loki.source.awsfirehose
 	_ "github.com/grafana/alloy/internal/component/loki/source/azure_event_hubs"             // Import loki.source.azure_event_hubs
@@ -47,13 +56,17 @@ import (
 	_ "github.com/grafana/alloy/internal/component/loki/source/docker"                       // Import loki.source.docker
 	_ "github.com/grafana/alloy/internal/component/loki/source/file"                         // Import loki.source.file
 	_ "github.com/grafana/alloy/internal/component/loki/source/gcplog"                       // Import loki.source.gcplog
+	_ "github.com/grafana/alloy/internal/component/loki/source/gcs"                          // Import loki.source.gcs
 	_ "github.com/grafana/alloy/internal/component/loki/source/gelf"                         // Import loki.source.gelf
 	_ "github.com/grafana/alloy/internal/component/loki/source/heroku"                       // Import loki.source.heroku
 	_ "github.com/grafana/alloy/internal/component/loki/source/journal"                      // Import loki.source.journal
 	_ "github.com/grafana/alloy/internal/component/loki/source/kafka"                        // Import loki.source.kafka
 	_ "github.com/grafana/alloy/internal/component/loki/source/kubernetes"                   // Import loki.source.kubernetes
 	_ "github.com/grafana/alloy/internal/component/loki/source/kubernetes_events"            // Import loki.source.kubernetes_events
+	_ "github.com/grafana/alloy/internal/component/loki/source/kubernetes_objects"           // Import loki.source.kubernetes_objects
+	_ "github.com/grafana/alloy/internal/component/loki/source/netflow"                      // Import loki.source.netflow
 	_ "github.com/grafana/alloy/internal/component/loki/source/podlogs"                      // Import loki.source.podlogs
+	_ "github.com/grafana/alloy/internal/component/loki/source/snmptrap"                     // Import loki.source.snmptrap
 	_ "github.com/grafana/alloy/internal/component/loki/source/syslog"                       // Import loki.source.syslog
 	_ "github.com/grafana/alloy/internal/component/loki/source/windowsevent"                 // Import loki.source.windowsevent
 	_ "github.com/grafana/alloy/internal/component/loki/write"                               // Import loki.write
@@ -63,12 +76,15 @@ import (
 	_ "github.com/grafana/alloy/internal/component/otelcol/auth/headers"                     // Import otelcol.auth.headers
 	_ "github.com/grafana/alloy/internal/component/otelcol/auth/oauth2"                      // Import otelcol.auth.oauth2
 	_ "github.com/grafana/alloy/internal/component/otelcol/auth/sigv4"                       // Import otelcol.auth.sigv4
+	_ "github.com/grafana/alloy/internal/component/otelcol/connector/count"                  // Import otelcol.connector.count
 	_ "github.com/grafana/alloy/internal/component/otelcol/connector/host_info"              // Import otelcol.connector.host_info
 	_ "github.com/grafana/alloy/internal/component/otelcol/connector/servicegraph"           // Import otelcol.connector.servicegraph
 	_ "github.com/grafana/alloy/internal/component/otelcol/connector/spanlogs"               // Import otelcol.connector.spanlogs
 	_ "github.com/grafana/alloy/internal/component/otelcol/connector/spanmetrics"            // Import otelcol.connector.spanmetrics
+	_ "github.com/grafana/alloy/internal/component/otelcol/connector/sum"                    // Import otelcol.connector.sum
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/awss3"                   // Import otelcol.exporter.awss3exporter
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/debug"                   // Import otelcol.exporter.debug
+	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/file"                    // Import otelcol.exporter.file
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/kafka"                   // Import otelcol.exporter.kafka
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/loadbalancing"           // Import otelcol.exporter.loadbalancing
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/logging"                 // Import otelcol.exporter.logging
@@ -76,62 +92,88 @@ import (
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/otlp"                    // Import otelcol.exporter.otlp
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/otlphttp"                // Import otelcol.exporter.otlphttp
 	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/prometheus"              // Import otelcol.exporter.prometheus
+	_ "github.com/grafana/alloy/internal/component/otelcol/exporter/prometheusremotewrite"   // Import otelcol.exporter.prometheusremotewrite
 	_ "github.com/grafana/alloy/internal/component/otelcol/extension/jaeger_remote_sampling" // Import otelcol.extension.jaeger_remote_sampling
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/attributes"             // Import otelcol.processor.attributes
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/batch"                  // Import otelcol.processor.batch
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/deltatocumulative"      // Import otelcol.processor.deltatocumulative
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/discovery"              // Import otelcol.processor.discovery
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/filter"                 // Import otelcol.processor.filter
+	_ "github.com/grafana/alloy/internal/component/otelcol/processor/interval"               // Import otelcol.processor.interval
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/k8sattributes"          // Import otelcol.processor.k8sattributes
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/memorylimiter"          // Import otelcol.processor.memory_limiter
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/probabilistic_sampler"  // Import otelcol.processor.probabilistic_sampler
+	_ "github.com/grafana/alloy/internal/component/otelcol/processor/queue"                  // Import otelcol.processor.queue
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection"      // Import otelcol.processor.resourcedetection
+	_ "github.com/grafana/alloy/internal/component/otelcol/processor/schema"                 // Import otelcol.processor.schema
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/span"                   // Import otelcol.processor.span
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/tail_sampling"          // Import otelcol.processor.tail_sampling
 	_ "github.com/grafana/alloy/internal/component/otelcol/processor/transform"              // Import otelcol.processor.transform
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/datadog"                 // Import otelcol.receiver.datadog
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/file"                    // Import otelcol.receiver.file
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/file_stats"              // Import otelcol.receiver.file_stats
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/hostmetrics"             // Import otelcol.receiver.hostmetrics
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/influxdb"                // Import otelcol.receiver.influxdb
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/jaeger"                  // Import otelcol.receiver.jaeger
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/k8s_cluster"             // Import otelcol.receiver.k8s_cluster
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/kafka"                   // Import otelcol.receiver.kafka
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/kubeletstats"            // Import otelcol.receiver.kubeletstats
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/loki"                    // Import otelcol.receiver.loki
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/opencensus"              // Import otelcol.receiver.opencensus
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/otlp"                    // Import otelcol.receiver.otlp
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/prometheus"              // Import otelcol.receiver.prometheus
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/snmp"                    // Import otelcol.receiver.snmp
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/statsd"                  // Import otelcol.receiver.statsd
+	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/syslog"                  // Import otelcol.receiver.syslog
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/vcenter"                 // Import otelcol.receiver.vcenter
 	_ "github.com/grafana/alloy/internal/component/otelcol/receiver/zipkin"                  // Import otelcol.receiver.zipkin
+	_ "github.com/grafana/alloy/internal/component/prometheus/downsample"                    // Import prometheus.downsample
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/apache"               // Import prometheus.exporter.apache
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/azure"                // Import prometheus.exporter.azure
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/blackbox"             // Import prometheus.exporter.blackbox
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/cadvisor"             // Import prometheus.exporter.cadvisor
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/catchpoint"           // Import prometheus.exporter.catchpoint
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/ceph"                 // Import prometheus.exporter.ceph
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/cloudwatch"           // Import prometheus.exporter.cloudwatch
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/consul"               // Import prometheus.exporter.consul
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/dnsmasq"              // Import prometheus.exporter.dnsmasq
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/elasticsearch"        // Import prometheus.exporter.elasticsearch
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/gcp"                  // Import prometheus.exporter.gcp
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/github"               // Import prometheus.exporter.github
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/graphite"             // Import prometheus.exporter.graphite
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/haproxy"              // Import prometheus.exporter.haproxy
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/kafka"                // Import prometheus.exporter.kafka
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/kube_state_metrics"   // Import prometheus.exporter.kube_state_metrics
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/memcached"            // Import prometheus.exporter.memcached
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/mongodb"              // Import prometheus.exporter.mongodb
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/mongodb_atlas"        // Import prometheus.exporter.mongodb_atlas
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/mssql"                // Import prometheus.exporter.mssql
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/mysql"                // Import prometheus.exporter.mysql
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/nginx"                // Import prometheus.exporter.nginx
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/oracledb"             // Import prometheus.exporter.oracledb
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/postgres"             // Import prometheus.exporter.postgres
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/process"              // Import prometheus.exporter.process
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/rabbitmq"             // Import prometheus.exporter.rabbitmq
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/redis"                // Import prometheus.exporter.redis
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/self"                 // Import prometheus.exporter.self
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/smartctl"             // Import prometheus.exporter.smartctl
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/snmp"                 // Import prometheus.exporter.snmp
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/snowflake"            // Import prometheus.exporter.snowflake
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/squid"                // Import prometheus.exporter.squid
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/statsd"               // Import prometheus.exporter.statsd
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/unix"                 // Import prometheus.exporter.unix
 	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/windows"              // Import prometheus.exporter.windows
+	_ "github.com/grafana/alloy/internal/component/prometheus/exporter/zfs"                  // Import prometheus.exporter.zfs
+	_ "github.com/grafana/alloy/internal/component/prometheus/limit"                         // Import prometheus.limit
 	_ "github.com/grafana/alloy/internal/component/prometheus/operator/podmonitors"          // Import prometheus.operator.podmonitors
 	_ "github.com/grafana/alloy/internal/component/prometheus/operator/probes"               // Import prometheus.operator.probes
+	_ "github.com/grafana/alloy/internal/component/prometheus/operator/scrapeconfigs"        // Import prometheus.operator.scrapeconfigs
 	_ "github.com/grafana/alloy/internal/component/prometheus/operator/servicemonitors"      // Import prometheus.operator.servicemonitors
 	_ "github.com/grafana/alloy/internal/component/prometheus/receive_http"                  // Import prometheus.receive_http
+	_ "github.com/grafana/alloy/internal/component/prometheus/receive_pushgateway"           // Import prometheus.receive_pushgateway
 	_ "github.com/grafana/alloy/internal/component/prometheus/relabel"                       // Import prometheus.relabel
 	_ "github.com/grafana/alloy/internal/component/prometheus/remotewrite"                   // Import prometheus.remote_write
+	_ "github.com/grafana/alloy/internal/component/prometheus/rule/local"                    // Import prometheus.rule.local
 	_ "github.com/grafana/alloy/internal/component/prometheus/scrape"                        // Import prometheus.scrape
 	_ "github.com/grafana/alloy/internal/component/pyroscope/ebpf"                           // Import pyroscope.ebpf
 	_ "github.com/grafana/alloy/internal/component/pyroscope/java"                           // Import pyroscope.java
@@ -142,6 +184,7 @@ import (
 	_ "github.com/grafana/alloy/internal/component/remote/kubernetes/secret"                 // Import remote.kubernetes.secret
 	_ "github.com/grafana/alloy/internal/component/remote/s3"                                // Import remote.s3
 	_ "github.com/grafana/alloy/internal/component/remote/vault"                             // Import remote.vault
+	_ "github.com/grafana/alloy/internal/component/synthetic/check"                          // Import synthetic.check
 
 	_ "github.com/grafana/alloy/internal/util/otelfeaturegatefix" // Gracefully handle duplicate OTEL feature gates
 )
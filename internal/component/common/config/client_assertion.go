@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultClientAssertionAlgorithm is the default algorithm of the client
+	// assertions signed by Alloy.
+	defaultClientAssertionAlgorithm = "RS256"
+	// clientAssertionLifetime is how long the client assertions signed by
+	// Alloy are valid. They're signed for each token request, so they only
+	// need to outlive the request.
+	clientAssertionLifetime = 5 * time.Minute
+)
+
+// clientAssertionAlgorithms are the supported algorithms of client
+// assertions.
+var clientAssertionAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+func (o *OAuth2Config) clientAssertionAlgorithm() string {
+	if o.ClientAssertionAlgorithm == "" {
+		return defaultClientAssertionAlgorithm
+	}
+	return o.ClientAssertionAlgorithm
+}
+
+func (o *OAuth2Config) validateClientAssertion() error {
+	if !o.signsClientAssertion() {
+		if len(o.ClientAssertionKeyID) > 0 || len(o.ClientAssertionAlgorithm) > 0 {
+			return fmt.Errorf("oauth2 client_assertion_key_id and client_assertion_algorithm can only be configured with client_assertion_key or client_assertion_key_file")
+		}
+		return nil
+	}
+
+	if len(o.ClientID) == 0 {
+		return fmt.Errorf("oauth2 client_id must be configured with client_assertion_key or client_assertion_key_file")
+	}
+	if !slices.Contains(clientAssertionAlgorithms, o.clientAssertionAlgorithm()) {
+		return fmt.Errorf("invalid oauth2 client_assertion_algorithm %q, must be one of %s", o.ClientAssertionAlgorithm, strings.Join(clientAssertionAlgorithms, ", "))
+	}
+	for _, k := range []string{"client_assertion", "client_assertion_type"} {
+		if _, ok := o.EndpointParams[k]; ok {
+			return fmt.Errorf("oauth2 endpoint_params must not contain %q, which is set by the other oauth2 settings", k)
+		}
+	}
+
+	// The key file is read when signing, so that rotated keys are picked up.
+	if len(o.ClientAssertionKey) > 0 {
+		if _, err := parseClientAssertionKey(o.clientAssertionAlgorithm(), []byte(o.ClientAssertionKey)); err != nil {
+			return fmt.Errorf("invalid oauth2 client_assertion_key: %w", err)
+		}
+	}
+	return nil
+}
+
+// signClientAssertion returns a new client assertion of o, as described in
+// RFC 7523, issued at now.
+func (o *OAuth2Config) signClientAssertion(now time.Time) (string, error) {
+	pem := []byte(o.ClientAssertionKey)
+	if len(o.ClientAssertionKeyFile) > 0 {
+		var err error
+		if pem, err = os.ReadFile(o.ClientAssertionKeyFile); err != nil {
+			return "", fmt.Errorf("failed to read client assertion key: %w", err)
+		}
+	}
+	key, err := parseClientAssertionKey(o.clientAssertionAlgorithm(), pem)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse client assertion key: %w", err)
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(o.clientAssertionAlgorithm()), jwt.RegisteredClaims{
+		Issuer:    o.ClientID,
+		Subject:   o.ClientID,
+		Audience:  jwt.ClaimStrings{o.TokenURL},
+		ID:        id,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(clientAssertionLifetime)),
+	})
+	if len(o.ClientAssertionKeyID) > 0 {
+		token.Header["kid"] = o.ClientAssertionKeyID
+	}
+	return token.SignedString(key)
+}
+
+// parseClientAssertionKey parses the PEM encoded private key signing client
+// assertions with alg.
+func parseClientAssertionKey(alg string, pem []byte) (any, error) {
+	if strings.HasPrefix(alg, "ES") {
+		return jwt.ParseECPrivateKeyFromPEM(pem)
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(pem)
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/sigv4"
+	"github.com/prometheus/prometheus/storage/remote/azuread"
+	"golang.org/x/oauth2/google"
+)
+
+// SigV4Config mirrors sigv4.SigV4Config.
+type SigV4Config struct {
+	Region    string            `alloy:"region,attr,optional"`
+	AccessKey string            `alloy:"access_key,attr,optional"`
+	SecretKey alloytypes.Secret `alloy:"secret_key,attr,optional"`
+	Profile   string            `alloy:"profile,attr,optional"`
+	RoleARN   string            `alloy:"role_arn,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (s *SigV4Config) Validate() error {
+	if (s.AccessKey == "") != (s.SecretKey == "") {
+		return fmt.Errorf("must provide an AWS SigV4 access key and secret key if credentials are specified in the SigV4 config")
+	}
+	return nil
+}
+
+// Convert converts our type to the native prometheus type
+func (s *SigV4Config) Convert() *sigv4.SigV4Config {
+	if s == nil {
+		return nil
+	}
+
+	return &sigv4.SigV4Config{
+		Region:    s.Region,
+		AccessKey: s.AccessKey,
+		SecretKey: config.Secret(s.SecretKey),
+		Profile:   s.Profile,
+		RoleARN:   s.RoleARN,
+	}
+}
+
+// ManagedIdentityConfig is used to store managed identity config values
+type ManagedIdentityConfig struct {
+	// ClientID is the clientId of the managed identity that is being used to authenticate.
+	ClientID string `alloy:"client_id,attr"`
+}
+
+// Convert converts our type to the native prometheus type
+func (m ManagedIdentityConfig) Convert() azuread.ManagedIdentityConfig {
+	return azuread.ManagedIdentityConfig{
+		ClientID: m.ClientID,
+	}
+}
+
+// AzureADConfig mirrors azuread.AzureADConfig.
+type AzureADConfig struct {
+	// ManagedIdentity is the managed identity that is being used to authenticate.
+	ManagedIdentity ManagedIdentityConfig `alloy:"managed_identity,block"`
+
+	// Cloud is the Azure cloud in which the service is running. Example: AzurePublic/AzureGovernment/AzureChina.
+	Cloud string `alloy:"cloud,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (a *AzureADConfig) Validate() error {
+	if a.Cloud != azuread.AzureChina && a.Cloud != azuread.AzureGovernment && a.Cloud != azuread.AzurePublic {
+		return fmt.Errorf("must provide a cloud in the Azure AD config")
+	}
+
+	_, err := uuid.Parse(a.ManagedIdentity.ClientID)
+	if err != nil {
+		return fmt.Errorf("the provided Azure Managed Identity client_id provided is invalid")
+	}
+
+	return nil
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *AzureADConfig) SetToDefault() {
+	*a = AzureADConfig{
+		Cloud: azuread.AzurePublic,
+	}
+}
+
+// Convert converts our type to the native prometheus type
+func (a *AzureADConfig) Convert() *azuread.AzureADConfig {
+	if a == nil {
+		return nil
+	}
+
+	mangedIdentity := a.ManagedIdentity.Convert()
+	return &azuread.AzureADConfig{
+		ManagedIdentity: &mangedIdentity,
+		Cloud:           a.Cloud,
+	}
+}
+
+// GoogleIAMConfig configures authenticating with the access tokens of a
+// Google Cloud service account.
+type GoogleIAMConfig struct {
+	// CredentialsFile is the path to the JSON key of the service account. The
+	// Application Default Credentials are used if it's empty.
+	CredentialsFile string   `alloy:"credentials_file,attr,optional"`
+	Scopes          []string `alloy:"scopes,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (g *GoogleIAMConfig) SetToDefault() {
+	*g = GoogleIAMConfig{
+		Scopes: []string{"https://www.googleapis.com/auth/monitoring.write"},
+	}
+}
+
+// Validate implements syntax.Validator.
+func (g *GoogleIAMConfig) Validate() error {
+	if len(g.Scopes) == 0 {
+		return fmt.Errorf("must provide at least one scope in the Google IAM config")
+	}
+	return nil
+}
+
+// Credentials loads the credentials of the service account. Callers should
+// load them again on every refresh of their token, so that a rotated key is
+// picked up.
+func (g GoogleIAMConfig) Credentials(ctx context.Context) (*google.Credentials, error) {
+	if g.CredentialsFile == "" {
+		return google.FindDefaultCredentials(ctx, g.Scopes...)
+	}
+	data, err := os.ReadFile(g.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google credentials file: %w", err)
+	}
+	return google.CredentialsFromJSON(ctx, data, g.Scopes...)
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/config"
+)
+
+// maxTokenResponseSize is the maximum size of the token responses forwarded
+// by the token proxy.
+const maxTokenResponseSize = 1 << 20
+
+// oauth2Proxy is the token proxy shared by all HTTP clients.
+var oauth2Proxy = &tokenProxy{clients: make(map[string]*proxiedClient)}
+
+// tokenProxy sends the token requests of the OAuth2 configs which the
+// Prometheus OAuth2 client can't send itself.
+//
+// The HTTP clients are built by the Prometheus common config, which doesn't
+// let the token requests be customized. Instead, proxied configs are
+// converted into configs requesting their tokens from the proxy, which listens
+// on a loopback address. The proxy authenticates them with a random client
+// secret, and sends the actual token request to the identity provider.
+//
+// Registered configs are kept for the lifetime of the process. Registering a
+// config equal to a registered one reuses its registration, so reloading an
+// unchanged config doesn't register it again.
+type tokenProxy struct {
+	startOnce sync.Once
+	startErr  error
+	url       string
+
+	mut     sync.Mutex
+	clients map[string]*proxiedClient // Keyed by proxy client ID.
+}
+
+// proxiedClient is an OAuth2 config registered with the token proxy.
+type proxiedClient struct {
+	id     string
+	secret string
+	cfg    OAuth2Config
+
+	client    *http.Client
+	clientErr error // Error of building client.
+}
+
+// start starts listening for token requests, unless the proxy is already
+// started.
+func (p *tokenProxy) start() error {
+	p.startOnce.Do(func() {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			if l, err = net.Listen("tcp", "[::1]:0"); err != nil {
+				p.startErr = err
+				return
+			}
+		}
+		p.url = "http://" + l.Addr().String() + "/token"
+
+		srv := &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+		go func() { _ = srv.Serve(l) }()
+	})
+	return p.startErr
+}
+
+// register registers cfg and returns the config of the Prometheus OAuth2
+// client requesting its tokens from the proxy.
+func (p *tokenProxy) register(cfg OAuth2Config) (*config.OAuth2, error) {
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	for _, c := range p.clients {
+		if reflect.DeepEqual(c.cfg, cfg) {
+			return p.oauth2(c), nil
+		}
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	c := &proxiedClient{id: id, secret: secret, cfg: cfg}
+	c.client, c.clientErr = newTokenClient(cfg)
+	p.clients[id] = c
+	return p.oauth2(c), nil
+}
+
+func (p *tokenProxy) oauth2(c *proxiedClient) *config.OAuth2 {
+	return &config.OAuth2{
+		ClientID:     c.id,
+		ClientSecret: config.Secret(c.secret),
+		TokenURL:     p.url,
+	}
+}
+
+// ServeHTTP implements http.Handler by sending the token request of the
+// client authenticated by r.
+func (p *tokenProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The Prometheus OAuth2 client sends the client credentials in the
+	// authorization header, and retries failed requests with the credentials
+	// in the form.
+	id, secret, ok := r.BasicAuth()
+	if !ok && r.ParseForm() == nil {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		ok = len(id) > 0
+	}
+
+	p.mut.Lock()
+	c := p.clients[id]
+	p.mut.Unlock()
+
+	if !ok || c == nil || subtle.ConstantTimeCompare([]byte(secret), []byte(c.secret)) != 1 {
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client", "unknown oauth2 token proxy client")
+		return
+	}
+
+	resp, err := c.requestToken(r.Context())
+	if err != nil {
+		writeTokenError(w, http.StatusBadGateway, "server_error", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, io.LimitReader(resp.Body, maxTokenResponseSize))
+}
+
+// requestToken sends a token request for c to its identity provider. The
+// client is never authenticated with a Basic authorization header.
+func (c *proxiedClient) requestToken(ctx context.Context) (*http.Response, error) {
+	if c.clientErr != nil {
+		return nil, c.clientErr
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.cfg.ClientID) > 0 {
+		form.Set("client_id", c.cfg.ClientID)
+	}
+	if len(c.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	}
+	for k, v := range c.cfg.endpointParams() {
+		form.Set(k, v)
+	}
+	if c.cfg.signsClientAssertion() {
+		assertion, err := c.cfg.signClientAssertion(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		form.Set("client_assertion_type", oauth2JWTBearerAssertion)
+		form.Set("client_assertion", assertion)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.client.Do(req)
+}
+
+// newTokenClient returns the client sending the token requests of cfg, with
+// its proxy and TLS settings.
+func newTokenClient(cfg OAuth2Config) (*http.Client, error) {
+	hc := config.HTTPClientConfig{
+		FollowRedirects: true,
+		EnableHTTP2:     true,
+		ProxyConfig:     cfg.ProxyConfig.Convert(),
+	}
+	if cfg.TLSConfig != nil {
+		hc.TLSConfig = *cfg.TLSConfig.Convert()
+	}
+	return config.NewClientFromConfig(hc, "oauth2_token_proxy")
+}
+
+// writeTokenError writes an OAuth2 error response, as described in RFC 6749.
+func writeTokenError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
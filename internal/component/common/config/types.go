@@ -356,16 +356,42 @@ func (t *TLSConfig) Validate() error {
 	return nil
 }
 
+// Grant types supported by OAuth2Config.
+const (
+	OAuth2GrantClientCredentials = "client_credentials"
+	OAuth2GrantTokenExchange     = "token_exchange"
+)
+
+const (
+	// oauth2TokenExchangeGrant is the grant type of RFC 8693 token exchange.
+	oauth2TokenExchangeGrant = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// oauth2AccessTokenType is the default type of the subject token of a
+	// token exchange.
+	oauth2AccessTokenType = "urn:ietf:params:oauth:token-type:access_token"
+	// oauth2JWTBearerAssertion is the type of the client assertions of RFC
+	// 7523.
+	oauth2JWTBearerAssertion = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
 // OAuth2Config sets up the OAuth2 client.
 type OAuth2Config struct {
 	ClientID         string            `alloy:"client_id,attr,optional"`
 	ClientSecret     alloytypes.Secret `alloy:"client_secret,attr,optional"`
 	ClientSecretFile string            `alloy:"client_secret_file,attr,optional"`
+	GrantType        string            `alloy:"grant_type,attr,optional"`
+	SubjectToken     alloytypes.Secret `alloy:"subject_token,attr,optional"`
+	SubjectTokenType string            `alloy:"subject_token_type,attr,optional"`
+	Audience         string            `alloy:"audience,attr,optional"`
 	Scopes           []string          `alloy:"scopes,attr,optional"`
 	TokenURL         string            `alloy:"token_url,attr,optional"`
 	EndpointParams   map[string]string `alloy:"endpoint_params,attr,optional"`
 	ProxyConfig      *ProxyConfig      `alloy:",squash"`
 	TLSConfig        *TLSConfig        `alloy:"tls_config,block,optional"`
+
+	ClientAssertionKey       alloytypes.Secret `alloy:"client_assertion_key,attr,optional"`
+	ClientAssertionKeyFile   string            `alloy:"client_assertion_key_file,attr,optional"`
+	ClientAssertionKeyID     string            `alloy:"client_assertion_key_id,attr,optional"`
+	ClientAssertionAlgorithm string            `alloy:"client_assertion_algorithm,attr,optional"`
 }
 
 // Convert converts our type to the native prometheus type
@@ -373,13 +399,20 @@ func (o *OAuth2Config) Convert() *config.OAuth2 {
 	if o == nil {
 		return nil
 	}
+	// Registering only fails if the token proxy can't listen, which is
+	// reported by Validate.
+	if o.proxied() {
+		if oa, err := oauth2Proxy.register(*o); err == nil {
+			return oa
+		}
+	}
 	oa := &config.OAuth2{
 		ClientID:         o.ClientID,
 		ClientSecret:     config.Secret(o.ClientSecret),
 		ClientSecretFile: o.ClientSecretFile,
 		Scopes:           o.Scopes,
 		TokenURL:         o.TokenURL,
-		EndpointParams:   o.EndpointParams,
+		EndpointParams:   o.endpointParams(),
 		ProxyConfig:      o.ProxyConfig.Convert(),
 	}
 	if o.TLSConfig != nil {
@@ -388,22 +421,111 @@ func (o *OAuth2Config) Convert() *config.OAuth2 {
 	return oa
 }
 
+// proxied returns whether the token requests of o are sent by the token
+// proxy of Alloy rather than by the Prometheus OAuth2 client.
+//
+// The Prometheus OAuth2 client reuses the same parameters for every token
+// request, so it can't sign a client assertion per request, and it sends the
+// client ID in a Basic authorization header even without a client secret,
+// which many identity providers reject.
+func (o *OAuth2Config) proxied() bool {
+	return o.signsClientAssertion() || (len(o.ClientSecret) == 0 && len(o.ClientSecretFile) == 0)
+}
+
+// signsClientAssertion returns whether the client authenticates with a
+// client assertion signed by Alloy.
+func (o *OAuth2Config) signsClientAssertion() bool {
+	return len(o.ClientAssertionKey) > 0 || len(o.ClientAssertionKeyFile) > 0
+}
+
+// endpointParams returns the endpoint parameters of the token requests.
+//
+// The Prometheus OAuth2 client only implements the client credentials grant,
+// but it lets the endpoint parameters override the grant type, so the other
+// settings are sent as endpoint parameters.
+func (o *OAuth2Config) endpointParams() map[string]string {
+	params := o.generatedParams()
+	if len(params) == 0 {
+		return o.EndpointParams
+	}
+	for k, v := range o.EndpointParams {
+		params[k] = v
+	}
+	return params
+}
+
+// generatedParams returns the endpoint parameters generated from the settings
+// of o. The client assertion isn't included, since it's signed for each token
+// request.
+func (o *OAuth2Config) generatedParams() map[string]string {
+	params := make(map[string]string)
+	if o.GrantType == OAuth2GrantTokenExchange {
+		params["grant_type"] = oauth2TokenExchangeGrant
+		params["subject_token"] = string(o.SubjectToken)
+		params["subject_token_type"] = o.SubjectTokenType
+		if o.SubjectTokenType == "" {
+			params["subject_token_type"] = oauth2AccessTokenType
+		}
+	}
+	if o.Audience != "" {
+		params["audience"] = o.Audience
+	}
+	return params
+}
+
 func (o *OAuth2Config) Validate() error {
 	if o == nil {
 		return nil
 	}
 
-	if len(o.ClientID) == 0 {
-		return fmt.Errorf("oauth2 client_id must be configured")
-	}
-	if len(o.ClientSecret) == 0 && len(o.ClientSecretFile) == 0 {
-		return fmt.Errorf("either oauth2 client_secret or client_secret_file must be configured")
+	switch o.GrantType {
+	case "", OAuth2GrantClientCredentials:
+		if len(o.ClientID) == 0 {
+			return fmt.Errorf("oauth2 client_id must be configured")
+		}
+		if len(o.ClientSecret) == 0 && len(o.ClientSecretFile) == 0 && !o.signsClientAssertion() {
+			return fmt.Errorf("either oauth2 client_secret, client_secret_file, client_assertion_key or client_assertion_key_file must be configured")
+		}
+		if len(o.SubjectToken) > 0 || len(o.SubjectTokenType) > 0 {
+			return fmt.Errorf("oauth2 subject_token and subject_token_type can only be configured with the %q grant_type", OAuth2GrantTokenExchange)
+		}
+	case OAuth2GrantTokenExchange:
+		if len(o.SubjectToken) == 0 {
+			return fmt.Errorf("oauth2 subject_token must be configured with the %q grant_type", OAuth2GrantTokenExchange)
+		}
+	default:
+		return fmt.Errorf("invalid oauth2 grant_type %q, must be one of %q or %q", o.GrantType, OAuth2GrantClientCredentials, OAuth2GrantTokenExchange)
 	}
 	if len(o.TokenURL) == 0 {
 		return fmt.Errorf("oauth2 token_url must be configured")
 	}
-	if len(o.ClientSecret) > 0 && len(o.ClientSecretFile) > 0 {
-		return fmt.Errorf("at most one of oauth2 client_secret & client_secret_file must be configured")
+
+	secrets := 0
+	for _, set := range []bool{len(o.ClientSecret) > 0, len(o.ClientSecretFile) > 0, len(o.ClientAssertionKey) > 0, len(o.ClientAssertionKeyFile) > 0} {
+		if set {
+			secrets++
+		}
+	}
+	if secrets > 1 {
+		return fmt.Errorf("at most one of oauth2 client_secret, client_secret_file, client_assertion_key & client_assertion_key_file must be configured")
+	}
+
+	if err := o.validateClientAssertion(); err != nil {
+		return err
+	}
+
+	for k := range o.generatedParams() {
+		if _, ok := o.EndpointParams[k]; ok {
+			return fmt.Errorf("oauth2 endpoint_params must not contain %q, which is set by the other oauth2 settings", k)
+		}
+	}
+
+	if o.proxied() {
+		// Token requests fail if the proxy can't listen, so report it here
+		// rather than on every request.
+		if err := oauth2Proxy.start(); err != nil {
+			return fmt.Errorf("failed to start the oauth2 token proxy: %w", err)
+		}
 	}
 
 	return o.ProxyConfig.Validate()
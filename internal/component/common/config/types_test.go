@@ -1,9 +1,20 @@
 package config
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/grafana/alloy/syntax"
+	"github.com/prometheus/common/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -178,6 +189,243 @@ func TestOath2TLSConvert(t *testing.T) {
 	require.NotNil(t, newCfg)
 }
 
+func TestOauth2TokenExchange(t *testing.T) {
+	var (
+		mut         sync.Mutex
+		tokenParams url.Values
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			// The client has no secret, so it must not send a Basic
+			// authorization header with an empty password.
+			require.Empty(t, r.Header.Get("Authorization"))
+			mut.Lock()
+			tokenParams = r.PostForm
+			mut.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "exchanged", "token_type": "Bearer", "expires_in": 3600}`))
+		default:
+			require.Equal(t, "Bearer exchanged", r.Header.Get("Authorization"))
+		}
+	}))
+	defer srv.Close()
+
+	var exampleAlloyConfig = fmt.Sprintf(`
+	oauth2 {
+		grant_type = "token_exchange"
+		subject_token = "subject"
+		subject_token_type = "urn:ietf:params:oauth:token-type:jwt"
+		audience = "metrics"
+		client_id = "client_id"
+		token_url = "%s/token"
+	}
+`, srv.URL)
+
+	var httpClientConfig HTTPClientConfig
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &httpClientConfig)
+	require.NoError(t, err)
+
+	client, err := config.NewClientFromConfig(*httpClientConfig.Convert(), "test")
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL + "/metrics")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Equal(t, "urn:ietf:params:oauth:grant-type:token-exchange", tokenParams.Get("grant_type"))
+	require.Equal(t, "client_id", tokenParams.Get("client_id"))
+	require.Equal(t, "subject", tokenParams.Get("subject_token"))
+	require.Equal(t, "urn:ietf:params:oauth:token-type:jwt", tokenParams.Get("subject_token_type"))
+	require.Equal(t, "metrics", tokenParams.Get("audience"))
+}
+
+func TestOauth2ClientAssertion(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var (
+		mut         sync.Mutex
+		tokenParams []url.Values
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			require.Empty(t, r.Header.Get("Authorization"))
+			mut.Lock()
+			tokenParams = append(tokenParams, r.PostForm)
+			mut.Unlock()
+			// The token expires right away, so that each request fetches a
+			// new one.
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "Bearer", "expires_in": 1}`))
+		default:
+			require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		}
+	}))
+	defer srv.Close()
+
+	var exampleAlloyConfig = fmt.Sprintf(`
+	oauth2 {
+		client_id = "client_id"
+		client_assertion_key = %q
+		client_assertion_key_id = "key"
+		scopes = ["metrics", "logs"]
+		token_url = "%s/token"
+	}
+`, keyPEM, srv.URL)
+
+	var httpClientConfig HTTPClientConfig
+	err = syntax.Unmarshal([]byte(exampleAlloyConfig), &httpClientConfig)
+	require.NoError(t, err)
+
+	client, err := config.NewClientFromConfig(*httpClientConfig.Convert(), "test")
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL + "/metrics")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Len(t, tokenParams, 2)
+	require.NotEqual(t, tokenParams[0].Get("client_assertion"), tokenParams[1].Get("client_assertion"))
+
+	for _, params := range tokenParams {
+		require.Equal(t, "client_credentials", params.Get("grant_type"))
+		require.Equal(t, "client_id", params.Get("client_id"))
+		require.Equal(t, "metrics logs", params.Get("scope"))
+		require.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", params.Get("client_assertion_type"))
+
+		var claims jwt.RegisteredClaims
+		token, err := jwt.ParseWithClaims(params.Get("client_assertion"), &claims, func(token *jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithAudience(srv.URL+"/token"), jwt.WithIssuer("client_id"), jwt.WithSubject("client_id"))
+		require.NoError(t, err)
+		require.Equal(t, "key", token.Header["kid"])
+		require.NotEmpty(t, claims.ID)
+	}
+}
+
+func TestOauth2Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "client assertion key file",
+			cfg: `
+				client_id = "client_id"
+				client_assertion_key_file = "/path/to/key.pem"
+				client_assertion_algorithm = "ES256"
+				token_url = "token_url"`,
+		},
+		{
+			name: "token exchange without client authentication",
+			cfg: `
+				grant_type = "token_exchange"
+				subject_token = "subject"
+				token_url = "token_url"`,
+		},
+		{
+			name: "invalid grant type",
+			cfg: `
+				grant_type = "password"
+				client_id = "client_id"
+				client_secret = "client_secret"
+				token_url = "token_url"`,
+			expectedErr: `invalid oauth2 grant_type "password"`,
+		},
+		{
+			name: "token exchange without subject token",
+			cfg: `
+				grant_type = "token_exchange"
+				token_url = "token_url"`,
+			expectedErr: "oauth2 subject_token must be configured",
+		},
+		{
+			name: "subject token with client credentials",
+			cfg: `
+				client_id = "client_id"
+				client_secret = "client_secret"
+				subject_token = "subject"
+				token_url = "token_url"`,
+			expectedErr: "oauth2 subject_token and subject_token_type can only be configured",
+		},
+		{
+			name: "client secret and client assertion key",
+			cfg: `
+				client_id = "client_id"
+				client_secret = "client_secret"
+				client_assertion_key_file = "/path/to/key.pem"
+				token_url = "token_url"`,
+			expectedErr: "at most one of oauth2 client_secret, client_secret_file, client_assertion_key & client_assertion_key_file must be configured",
+		},
+		{
+			name: "invalid client assertion key",
+			cfg: `
+				client_id = "client_id"
+				client_assertion_key = "key"
+				token_url = "token_url"`,
+			expectedErr: "invalid oauth2 client_assertion_key",
+		},
+		{
+			name: "invalid client assertion algorithm",
+			cfg: `
+				client_id = "client_id"
+				client_assertion_key_file = "/path/to/key.pem"
+				client_assertion_algorithm = "HS256"
+				token_url = "token_url"`,
+			expectedErr: `invalid oauth2 client_assertion_algorithm "HS256"`,
+		},
+		{
+			name: "client assertion algorithm without key",
+			cfg: `
+				client_id = "client_id"
+				client_secret = "client_secret"
+				client_assertion_algorithm = "RS256"
+				token_url = "token_url"`,
+			expectedErr: "oauth2 client_assertion_key_id and client_assertion_algorithm can only be configured",
+		},
+		{
+			name: "token exchange with client assertion key without client id",
+			cfg: `
+				grant_type = "token_exchange"
+				subject_token = "subject"
+				client_assertion_key_file = "/path/to/key.pem"
+				token_url = "token_url"`,
+			expectedErr: "oauth2 client_id must be configured with client_assertion_key",
+		},
+		{
+			name: "conflicting endpoint params",
+			cfg: `
+				client_id = "client_id"
+				client_secret = "client_secret"
+				audience = "metrics"
+				endpoint_params = {"audience" = "logs"}
+				token_url = "token_url"`,
+			expectedErr: `oauth2 endpoint_params must not contain "audience"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var httpClientConfig HTTPClientConfig
+			err := syntax.Unmarshal([]byte("oauth2 {"+tc.cfg+"\n}"), &httpClientConfig)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
 func TestHTTPClientBadConfig(t *testing.T) {
 	var exampleAlloyConfig = `
 	bearer_token = "token"
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/sigv4"
+	"github.com/prometheus/prometheus/storage/remote/azuread"
+	"golang.org/x/oauth2"
+
+	alloyconfig "github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/useragent"
+)
+
+const (
+	// googleTokenEarlyRefresh is how long before it expires a Google access
+	// token is replaced, so that push requests never carry an expired token.
+	googleTokenEarlyRefresh = 5 * time.Minute
+	// googleTokenTimeout is the timeout of fetching a Google access token.
+	googleTokenTimeout = 10 * time.Second
+)
+
+// newHTTPClient returns the HTTP client sending the push requests of cfg,
+// which authenticates them with the cloud provider configured in cfg, if any.
+func newHTTPClient(cfg Config) (*http.Client, error) {
+	if err := cfg.Client.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := config.NewClientFromConfig(cfg.Client, useragent.ProductName, config.WithHTTP2Disabled())
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cfg.SigV4 != nil:
+		client.Transport, err = sigv4.NewSigV4RoundTripper(cfg.SigV4, client.Transport)
+	case cfg.AzureAD != nil:
+		client.Transport, err = azuread.NewAzureADRoundTripper(cfg.AzureAD, client.Transport)
+	case cfg.GoogleIAM != nil:
+		client.Transport, err = newGoogleIAMRoundTripper(*cfg.GoogleIAM, client.Transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client.Timeout = cfg.Timeout
+	return client, nil
+}
+
+// newGoogleIAMRoundTripper returns a round tripper authenticating requests
+// with the access tokens of the service account of cfg. The credentials are
+// loaded once upfront, so that a missing or invalid key is reported right
+// away.
+func newGoogleIAMRoundTripper(cfg alloyconfig.GoogleIAMConfig, next http.RoundTripper) (http.RoundTripper, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), googleTokenTimeout)
+	defer cancel()
+	if _, err := cfg.Credentials(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load Google credentials: %w", err)
+	}
+
+	return &oauth2.Transport{
+		Source: oauth2.ReuseTokenSourceWithExpiry(nil, googleTokenSource{cfg: cfg}, googleTokenEarlyRefresh),
+		Base:   next,
+	}, nil
+}
+
+// googleTokenSource fetches a new Google access token on every call. The
+// credentials are loaded again every time, so that a rotated key is picked
+// up.
+type googleTokenSource struct {
+	cfg alloyconfig.GoogleIAMConfig
+}
+
+// Token implements oauth2.TokenSource.
+func (s googleTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), googleTokenTimeout)
+	defer cancel()
+
+	creds, err := s.cfg.Credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Google credentials: %w", err)
+	}
+	return creds.TokenSource.Token()
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/sigv4"
+	"github.com/stretchr/testify/require"
+
+	alloyconfig "github.com/grafana/alloy/internal/component/common/config"
+)
+
+func TestNewHTTPClient_Auth(t *testing.T) {
+	var tokens atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, tokens.Add(1))
+	}))
+	defer tokenServer.Close()
+
+	authorization := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization <- r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		cfg    Config
+		expect func(t *testing.T, authorization string)
+	}{
+		"sigv4": {
+			cfg: Config{SigV4: &sigv4.SigV4Config{
+				Region:    "us-east-1",
+				AccessKey: "access-key",
+				SecretKey: "secret-key",
+			}},
+			expect: func(t *testing.T, authorization string) {
+				require.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=access-key/"), authorization)
+			},
+		},
+		"google_iam": {
+			cfg: Config{GoogleIAM: &alloyconfig.GoogleIAMConfig{
+				CredentialsFile: writeServiceAccountKey(t, t.TempDir(), tokenServer.URL),
+				Scopes:          []string{"https://www.googleapis.com/auth/logging.write"},
+			}},
+			expect: func(t *testing.T, authorization string) {
+				require.Equal(t, "Bearer token-1", authorization)
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.cfg.Client = config.DefaultHTTPClientConfig
+			tc.cfg.Timeout = 5 * time.Second
+			c, err := newHTTPClient(tc.cfg)
+			require.NoError(t, err)
+
+			// Requests are sent twice, to check that tokens are reused.
+			for i := 0; i < 2; i++ {
+				resp, err := c.Post(server.URL, contentType, strings.NewReader("logs"))
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+				tc.expect(t, <-authorization)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_GoogleIAMMissingCredentials(t *testing.T) {
+	_, err := newHTTPClient(Config{
+		Client: config.DefaultHTTPClientConfig,
+		GoogleIAM: &alloyconfig.GoogleIAMConfig{
+			CredentialsFile: filepath.Join(t.TempDir(), "missing.json"),
+			Scopes:          []string{"https://www.googleapis.com/auth/logging.write"},
+		},
+	})
+	require.ErrorContains(t, err, "failed to load Google credentials")
+}
+
+// writeServiceAccountKey writes the JSON key of a service account whose
+// tokens are fetched from tokenURL, and returns its path.
+func writeServiceAccountKey(t *testing.T, dir, tokenURL string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "alloy",
+		"private_key_id": "1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "alloy@alloy.iam.gserviceaccount.com",
+		"token_uri":      tokenURL,
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "key.json")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
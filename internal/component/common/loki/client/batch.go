@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
-	"github.com/golang/snappy"
 	"github.com/prometheus/common/model"
 	"golang.org/x/exp/slices"
 
@@ -160,15 +159,18 @@ func (b *batch) age() time.Duration {
 	return time.Since(b.createdAt)
 }
 
-// encode the batch as snappy-compressed push request, and returns
+// encode the batch as a push request compressed with compression, and returns
 // the encoded bytes and the number of encoded entries
-func (b *batch) encode() ([]byte, int, error) {
+func (b *batch) encode(compression Compression) ([]byte, int, error) {
 	req, entriesCount := b.createPushRequest()
 	buf, err := proto.Marshal(req)
 	if err != nil {
 		return nil, 0, err
 	}
-	buf = snappy.Encode(nil, buf)
+	buf, err = compression.compress(buf)
+	if err != nil {
+		return nil, 0, err
+	}
 	return buf, entriesCount, nil
 }
 
@@ -15,7 +15,6 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/backoff"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 
 	lokiutil "github.com/grafana/loki/v3/pkg/util"
@@ -140,7 +139,7 @@ type Client interface {
 	Name() string
 }
 
-// Client for pushing logs in snappy-compressed protos over HTTP.
+// Client for pushing logs in compressed protos over HTTP.
 type client struct {
 	name    string
 	metrics *Metrics
@@ -149,6 +148,8 @@ type client struct {
 	client  *http.Client
 	entries chan loki.Entry
 
+	compression *negotiatedCompression
+
 	once sync.Once
 	wg   sync.WaitGroup
 
@@ -187,6 +188,8 @@ func newClient(metrics *Metrics, cfg Config, maxStreams, maxLineSize int, maxLin
 		metrics: metrics,
 		name:    GetClientName(cfg),
 
+		compression: newNegotiatedCompression(cfg.Compression),
+
 		externalLabels:      cfg.ExternalLabels.LabelSet,
 		ctx:                 ctx,
 		cancel:              cancel,
@@ -198,18 +201,12 @@ func newClient(metrics *Metrics, cfg Config, maxStreams, maxLineSize int, maxLin
 		c.name = cfg.Name
 	}
 
-	err := cfg.Client.Validate()
-	if err != nil {
-		return nil, err
-	}
-
-	c.client, err = config.NewClientFromConfig(cfg.Client, useragent.ProductName, config.WithHTTP2Disabled())
+	var err error
+	c.client, err = newHTTPClient(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	c.client.Timeout = cfg.Timeout
-
 	// Initialize counters to 0 so the metrics are exported before the first
 	// occurrence of incrementing to avoid missing metrics.
 	for _, counter := range c.metrics.countersWithHost {
@@ -351,7 +348,8 @@ func batchIsRateLimited(status int) bool {
 }
 
 func (c *client) sendBatch(tenantID string, batch *batch) {
-	buf, entriesCount, err := batch.encode()
+	compression := c.compression.get()
+	buf, entriesCount, err := batch.encode(compression)
 	if err != nil {
 		level.Error(c.logger).Log("msg", "error encoding batch", "error", err)
 		return
@@ -361,10 +359,12 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 
 	backoff := backoff.New(c.ctx, c.cfg.BackoffConfig)
 	var status int
+	span := startSendBatchSpan(c.ctx, c.cfg.URL.Host, tenantID, entriesCount, len(buf))
+	defer func() { endSendBatchSpan(span, status, err) }()
 	for {
 		start := time.Now()
 		// send uses `timeout` internally, so `context.Background` is good enough.
-		status, err = c.send(context.Background(), tenantID, buf)
+		status, err = c.send(context.Background(), tenantID, compression, buf)
 
 		c.metrics.requestDuration.WithLabelValues(strconv.Itoa(status), c.cfg.URL.Host).Observe(time.Since(start).Seconds())
 
@@ -383,6 +383,19 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 			return
 		}
 
+		// Send the batch again right away if the server doesn't support its
+		// compression.
+		if c.compression.fallBack(compression, status, err) {
+			level.Warn(c.logger).Log("msg", "server rejected the compression of the batch, falling back to snappy", "compression", compression, "status", status, "error", err)
+			compression = CompressionSnappy
+			if buf, _, err = batch.encode(compression); err != nil {
+				break
+			}
+			bufBytes = float64(len(buf))
+			c.metrics.encodedBytes.WithLabelValues(c.cfg.URL.Host).Add(bufBytes)
+			continue
+		}
+
 		// Only retry 429s, 500s and connection-level errors.
 		if status > 0 && !batchIsRateLimited(status) && status/100 != 5 {
 			break
@@ -411,7 +424,7 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 	}
 }
 
-func (c *client) send(ctx context.Context, tenantID string, buf []byte) (int, error) {
+func (c *client) send(ctx context.Context, tenantID string, compression Compression, buf []byte) (int, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
 	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, "POST", c.cfg.URL.String(), bytes.NewReader(buf))
@@ -420,6 +433,9 @@ func (c *client) send(ctx context.Context, tenantID string, buf []byte) (int, er
 	}
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", userAgent)
+	if enc := compression.contentEncoding(); enc != "" {
+		req.Header.Set("Content-Encoding", enc)
+	}
 
 	// If the tenant ID is not empty promtail is running in multi-tenant mode, so
 	// we should send it to Loki
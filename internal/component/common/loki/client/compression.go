@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the compression of the push requests sent to Loki.
+type Compression string
+
+const (
+	// CompressionSnappy compresses push requests with snappy, which is the
+	// compression Loki always expects for protobuf push requests.
+	CompressionSnappy Compression = "snappy"
+	// CompressionGzip compresses push requests with gzip, and sets their
+	// Content-Encoding accordingly.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses push requests with zstd, and sets their
+	// Content-Encoding accordingly.
+	CompressionZstd Compression = "zstd"
+)
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Compression) UnmarshalText(text []byte) error {
+	switch s := Compression(text); s {
+	case CompressionSnappy, CompressionGzip, CompressionZstd:
+		*c = s
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression %q, must be one of %q, %q or %q", s, CompressionSnappy, CompressionGzip, CompressionZstd)
+	}
+}
+
+// contentEncoding returns the Content-Encoding header of push requests
+// compressed with c, which is empty for snappy compression.
+func (c Compression) contentEncoding() string {
+	switch c {
+	case CompressionGzip, CompressionZstd:
+		return string(c)
+	default:
+		return ""
+	}
+}
+
+// zstdEncoder is shared by all clients, EncodeAll is safe for concurrent use.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// compress compresses the marshaled push request buf with c.
+//
+// Loki always snappy-decodes protobuf push requests, after removing their
+// Content-Encoding. With gzip or zstd compression, buf is first wrapped in a
+// snappy block holding it as is, so that the whole request is compressed by
+// the stronger algorithm only.
+func (c Compression) compress(buf []byte) ([]byte, error) {
+	switch c {
+	case CompressionGzip:
+		var out bytes.Buffer
+		w := gzip.NewWriter(&out)
+		if _, err := w.Write(snappyLiteral(buf)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(snappyLiteral(buf), nil), nil
+	default:
+		return snappy.Encode(nil, buf), nil
+	}
+}
+
+// snappyLiteral returns a snappy block holding buf as a single literal, that
+// is without compressing it.
+func snappyLiteral(buf []byte) []byte {
+	out := make([]byte, 0, len(buf)+binary.MaxVarintLen64+5)
+	out = binary.AppendUvarint(out, uint64(len(buf)))
+	if len(buf) == 0 {
+		return out
+	}
+
+	// The tag of a literal holds its length minus one, either in its upper
+	// six bits or, past 60, in the 1 to 4 bytes following it.
+	n := uint32(len(buf) - 1)
+	switch {
+	case n < 60:
+		out = append(out, byte(n)<<2)
+	case n < 1<<8:
+		out = append(out, 60<<2, byte(n))
+	case n < 1<<16:
+		out = append(out, 61<<2, byte(n), byte(n>>8))
+	case n < 1<<24:
+		out = append(out, 62<<2, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		out = append(out, 63<<2, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(out, buf...)
+}
+
+// negotiatedCompression is the compression of the push requests of a client.
+// It falls back to snappy compression once the server rejects a request
+// because of its Content-Encoding.
+type negotiatedCompression struct {
+	configured Compression
+	fellBack   atomic.Bool
+}
+
+func newNegotiatedCompression(configured Compression) *negotiatedCompression {
+	if configured == "" {
+		configured = CompressionSnappy
+	}
+	return &negotiatedCompression{configured: configured}
+}
+
+// get returns the compression to use for the next push request.
+func (n *negotiatedCompression) get() Compression {
+	if n.fellBack.Load() {
+		return CompressionSnappy
+	}
+	return n.configured
+}
+
+// fallBack reports whether a push request compressed with used and rejected
+// with status and err should be sent again with snappy compression, which is
+// then used for all the following push requests.
+func (n *negotiatedCompression) fallBack(used Compression, status int, err error) bool {
+	if used == CompressionSnappy || err == nil {
+		return false
+	}
+	rejected := status == http.StatusUnsupportedMediaType ||
+		(status == http.StatusBadRequest && strings.Contains(err.Error(), "Content-Encoding"))
+	if !rejected {
+		return false
+	}
+	n.fellBack.Store(true)
+	return true
+}
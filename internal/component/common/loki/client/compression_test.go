@@ -0,0 +1,138 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/flagext"
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression_Compress(t *testing.T) {
+	// Payloads around the size boundaries of the length of snappy literals.
+	sizes := []int{0, 1, 60, 61, 256, 257, 65536, 65537, 1 << 20}
+
+	decode := map[Compression]func([]byte) ([]byte, error){
+		CompressionSnappy: func(b []byte) ([]byte, error) { return b, nil },
+		CompressionGzip: func(b []byte) ([]byte, error) {
+			r, err := gzip.NewReader(bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(r)
+		},
+		CompressionZstd: func(b []byte) ([]byte, error) {
+			d, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			defer d.Close()
+			return d.DecodeAll(b, nil)
+		},
+	}
+
+	for compression, decodeContent := range decode {
+		for _, size := range sizes {
+			buf := []byte(strings.Repeat("loki", size/4+1)[:size])
+
+			body, err := compression.compress(buf)
+			require.NoError(t, err)
+
+			// Loki removes the Content-Encoding of the request, then
+			// snappy-decodes it.
+			content, err := decodeContent(body)
+			require.NoError(t, err, "compression %s, size %d", compression, size)
+			actual, err := snappy.Decode(nil, content)
+			require.NoError(t, err, "compression %s, size %d", compression, size)
+			require.Equal(t, len(buf), len(actual), "compression %s, size %d", compression, size)
+			require.True(t, bytes.Equal(buf, actual), "compression %s, size %d", compression, size)
+		}
+	}
+}
+
+func TestCompression_UnmarshalText(t *testing.T) {
+	var c Compression
+	require.NoError(t, c.UnmarshalText([]byte("zstd")))
+	require.Equal(t, CompressionZstd, c)
+	require.Equal(t, "zstd", c.contentEncoding())
+
+	require.NoError(t, c.UnmarshalText([]byte("snappy")))
+	require.Equal(t, "", c.contentEncoding())
+
+	require.Error(t, c.UnmarshalText([]byte("lz4")))
+}
+
+func TestNegotiatedCompression(t *testing.T) {
+	n := newNegotiatedCompression("")
+	require.Equal(t, CompressionSnappy, n.get())
+
+	n = newNegotiatedCompression(CompressionZstd)
+	require.Equal(t, CompressionZstd, n.get())
+
+	// Errors unrelated to the compression are retried as usual.
+	require.False(t, n.fallBack(CompressionZstd, http.StatusInternalServerError, errors.New("server returned HTTP status 500")))
+	require.False(t, n.fallBack(CompressionZstd, http.StatusBadRequest, errors.New("entry too far behind")))
+	require.Equal(t, CompressionZstd, n.get())
+
+	require.True(t, n.fallBack(CompressionZstd, http.StatusBadRequest, errors.New(`Content-Encoding "zstd" not supported`)))
+	require.Equal(t, CompressionSnappy, n.get())
+
+	// Snappy is always supported.
+	require.False(t, n.fallBack(CompressionSnappy, http.StatusUnsupportedMediaType, errors.New("unsupported")))
+}
+
+func TestClient_CompressionFallback(t *testing.T) {
+	var (
+		mut       sync.Mutex
+		encodings []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+		encoding := r.Header.Get("Content-Encoding")
+		encodings = append(encodings, encoding)
+		if encoding != "" {
+			http.Error(w, "unsupported Content-Encoding", http.StatusUnsupportedMediaType)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	serverURL := flagext.URLValue{}
+	require.NoError(t, serverURL.Set(server.URL))
+
+	cfg := Config{
+		URL:           serverURL,
+		BatchWait:     10 * time.Millisecond,
+		BatchSize:     6,
+		BackoffConfig: backoff.Config{MinBackoff: 1 * time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRetries: 1},
+		Timeout:       1 * time.Second,
+		Compression:   CompressionZstd,
+	}
+	c, err := New(NewMetrics(prometheus.NewRegistry()), cfg, 0, 0, false, log.NewNopLogger())
+	require.NoError(t, err)
+
+	c.Chan() <- logEntries[0]
+	c.Chan() <- logEntries[1]
+	c.Stop()
+
+	// The first request is sent again with snappy once rejected, and the
+	// following ones are sent with snappy right away.
+	mut.Lock()
+	defer mut.Unlock()
+	require.Equal(t, []string{"zstd", "", ""}, encodings)
+}
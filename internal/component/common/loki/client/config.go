@@ -7,8 +7,12 @@ import (
 	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/flagext"
 	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/sigv4"
+	"github.com/prometheus/prometheus/storage/remote/azuread"
 
 	lokiflag "github.com/grafana/loki/v3/pkg/util/flagext"
+
+	alloyconfig "github.com/grafana/alloy/internal/component/common/config"
 )
 
 // NOTE the helm chart for promtail and fluent-bit also have defaults for these values, please update to match if you make changes here.
@@ -45,6 +49,17 @@ type Config struct {
 	// prevent HOL blocking in multitenant deployments.
 	DropRateLimitedBatches bool `yaml:"drop_rate_limited_batches"`
 
+	// Compression of the push requests. It falls back to snappy if the server
+	// doesn't support it. Empty means snappy.
+	Compression Compression `yaml:"compression,omitempty"`
+
+	// At most one of SigV4, AzureAD and GoogleIAM authenticates the push
+	// requests with a cloud provider. They can't be combined with the
+	// authentication of Client.
+	SigV4     *sigv4.SigV4Config           `yaml:"sigv4,omitempty"`
+	AzureAD   *azuread.AzureADConfig       `yaml:"azuread,omitempty"`
+	GoogleIAM *alloyconfig.GoogleIAMConfig `yaml:"-"`
+
 	// Queue controls configuration parameters specific to the queue client
 	Queue QueueConfig
 }
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kit/log"
+)
+
+// CheckConnectivity sends an empty push request to the endpoint of cfg, with
+// the authentication, TLS and proxy settings of cfg, to check that the
+// endpoint can be reached and accepts the requests of the client.
+func CheckConnectivity(ctx context.Context, cfg Config) error {
+	if cfg.URL.URL == nil {
+		return errors.New("client needs target URL")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = Timeout
+	}
+
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Snappy is the only compression every Loki version supports.
+	buf, _, err := newBatch(0).encode(CompressionSnappy)
+	if err != nil {
+		return err
+	}
+
+	c := &client{
+		logger: log.NewNopLogger(),
+		cfg:    cfg,
+		client: httpClient,
+	}
+	_, err = c.send(ctx, cfg.TenantID, CompressionSnappy, buf)
+	return err
+}
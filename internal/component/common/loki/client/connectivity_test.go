@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/common/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConnectivity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "password" {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		require.Equal(t, "tenant-1", r.Header.Get("X-Scope-OrgID"))
+		require.Equal(t, contentType, r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL + "/loki/api/v1/push")
+	require.NoError(t, err)
+
+	cfg := Config{
+		URL:      flagext.URLValue{URL: u},
+		TenantID: "tenant-1",
+		Client: config.HTTPClientConfig{
+			BasicAuth: &config.BasicAuth{Username: "user", Password: "password"},
+		},
+	}
+	require.NoError(t, CheckConnectivity(context.Background(), cfg))
+
+	cfg.Client.BasicAuth.Password = "wrong"
+	require.ErrorContains(t, CheckConnectivity(context.Background(), cfg), "401")
+}
@@ -15,13 +15,11 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/backoff"
-	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/tsdb/record"
 
 	alloyWal "github.com/grafana/alloy/internal/component/common/loki/wal"
-	"github.com/grafana/alloy/internal/useragent"
 
 	"github.com/grafana/loki/v3/pkg/ingester/wal"
 	"github.com/grafana/loki/v3/pkg/logproto"
@@ -158,6 +156,8 @@ type queueClient struct {
 	cfg       Config
 	client    *http.Client
 
+	compression *negotiatedCompression
+
 	batches      map[string]*batch
 	batchesMtx   sync.Mutex
 	sendQueue    *queue
@@ -201,6 +201,7 @@ func newQueueClient(metrics *Metrics, qcMetrics *QueueClientMetrics, cfg Config,
 		qcMetrics:    qcMetrics,
 		drainTimeout: cfg.Queue.DrainTimeout,
 		quit:         make(chan struct{}),
+		compression:  newNegotiatedCompression(cfg.Compression),
 
 		batches:       make(map[string]*batch),
 		markerHandler: markerHandler,
@@ -221,18 +222,12 @@ func newQueueClient(metrics *Metrics, qcMetrics *QueueClientMetrics, cfg Config,
 	var queueBufferSize = cfg.Queue.Capacity / cfg.BatchSize
 	c.sendQueue = newQueue(c, queueBufferSize, logger)
 
-	err := cfg.Client.Validate()
-	if err != nil {
-		return nil, err
-	}
-
-	c.client, err = config.NewClientFromConfig(cfg.Client, useragent.ProductName, config.WithHTTP2Disabled())
+	var err error
+	c.client, err = newHTTPClient(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	c.client.Timeout = cfg.Timeout
-
 	// Initialize counters to 0 so the metrics are exported before the first
 	// occurrence of incrementing to avoid missing metrics.
 	for _, counter := range c.metrics.countersWithHost {
@@ -450,7 +445,8 @@ func (c *queueClient) enqueuePendingBatches(ctx context.Context) {
 }
 
 func (c *queueClient) sendBatch(ctx context.Context, tenantID string, batch *batch) {
-	buf, entriesCount, err := batch.encode()
+	compression := c.compression.get()
+	buf, entriesCount, err := batch.encode(compression)
 	if err != nil {
 		level.Error(c.logger).Log("msg", "error encoding batch", "error", err)
 		return
@@ -460,10 +456,12 @@ func (c *queueClient) sendBatch(ctx context.Context, tenantID string, batch *bat
 
 	backoff := backoff.New(c.ctx, c.cfg.BackoffConfig)
 	var status int
+	span := startSendBatchSpan(ctx, c.cfg.URL.Host, tenantID, entriesCount, len(buf))
+	defer func() { endSendBatchSpan(span, status, err) }()
 	for {
 		start := time.Now()
 		// send uses `timeout` internally, so `context.Background` is good enough.
-		status, err = c.send(ctx, tenantID, buf)
+		status, err = c.send(ctx, tenantID, compression, buf)
 
 		c.metrics.requestDuration.WithLabelValues(strconv.Itoa(status), c.cfg.URL.Host).Observe(time.Since(start).Seconds())
 
@@ -482,6 +480,19 @@ func (c *queueClient) sendBatch(ctx context.Context, tenantID string, batch *bat
 			return
 		}
 
+		// Send the batch again right away if the server doesn't support its
+		// compression.
+		if c.compression.fallBack(compression, status, err) {
+			level.Warn(c.logger).Log("msg", "server rejected the compression of the batch, falling back to snappy", "compression", compression, "status", status, "error", err)
+			compression = CompressionSnappy
+			if buf, _, err = batch.encode(compression); err != nil {
+				break
+			}
+			bufBytes = float64(len(buf))
+			c.metrics.encodedBytes.WithLabelValues(c.cfg.URL.Host).Add(bufBytes)
+			continue
+		}
+
 		// Only retry 429s, 500s and connection-level errors.
 		if status > 0 && !batchIsRateLimited(status) && status/100 != 5 {
 			break
@@ -510,7 +521,7 @@ func (c *queueClient) sendBatch(ctx context.Context, tenantID string, batch *bat
 	}
 }
 
-func (c *queueClient) send(ctx context.Context, tenantID string, buf []byte) (int, error) {
+func (c *queueClient) send(ctx context.Context, tenantID string, compression Compression, buf []byte) (int, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
 	defer cancel()
 	req, err := http.NewRequest("POST", c.cfg.URL.String(), bytes.NewReader(buf))
@@ -520,6 +531,9 @@ func (c *queueClient) send(ctx context.Context, tenantID string, buf []byte) (in
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", userAgent)
+	if enc := compression.contentEncoding(); enc != "" {
+		req.Header.Set("Content-Encoding", enc)
+	}
 
 	// If the tenant ID is not empty promtail is running in multi-tenant mode, so
 	// we should send it to Loki
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSendBatchSpan starts the span covering the sending of a batch,
+// retries included. Like the queues of the Prometheus remote write, it uses
+// the global tracer provider, which Alloy sets to its own.
+func startSendBatchSpan(ctx context.Context, host, tenantID string, entries, bytes int) trace.Span {
+	_, span := otel.Tracer("").Start(ctx, "Loki Send Batch", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("host", host),
+		attribute.String("tenant", tenantID),
+		attribute.Int("entries", entries),
+		attribute.Int("bytes", bytes),
+	)
+	return span
+}
+
+// endSendBatchSpan ends span with the final outcome of sending the batch.
+func endSendBatchSpan(span trace.Span, status int, err error) {
+	span.SetAttributes(attribute.Int("status", status))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
@@ -5,6 +5,7 @@ package positions
 // same place in case of a restart.
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -23,6 +25,12 @@ const (
 	positionFileMode = 0600
 	cursorKeyPrefix  = "cursor-"
 	journalKeyPrefix = "journal-"
+
+	// stateNamespace is the namespace of the positions in a state.Store, and
+	// stateImportNamespace the one recording that the positions file was
+	// imported into the store.
+	stateNamespace       = "positions"
+	stateImportNamespace = "positions_import"
 )
 
 // Config describes where to get position information from.
@@ -52,6 +60,7 @@ type positions struct {
 	cfg       Config
 	mtx       sync.Mutex
 	positions map[Entry]string
+	store     *state.Store
 	quit      chan struct{}
 	done      chan struct{}
 }
@@ -64,8 +73,8 @@ type positions struct {
 // and maintains the same format for both reading and writing from/to the
 // positions file.
 type Entry struct {
-	Path   string `yaml:"path"`
-	Labels string `yaml:"labels"`
+	Path   string `yaml:"path" json:"path"`
+	Labels string `yaml:"labels" json:"labels"`
 }
 
 // File format for the positions data.
@@ -162,16 +171,36 @@ func New(logger log.Logger, cfg Config) (Positions, error) {
 		return nil, err
 	}
 
+	return newPositions(logger, cfg, positionData, nil), nil
+}
+
+// NewWithStore makes a new Positions which persists the positions in store.
+// The positions are also written to cfg.PositionsFile, so that a previous
+// version which only reads the file resumes from them after a rollback. The
+// positions of cfg.PositionsFile are imported into store when the file was
+// written by such a version.
+//
+// store isn't closed when the Positions is stopped.
+func NewWithStore(logger log.Logger, store *state.Store, cfg Config) (Positions, error) {
+	positionData, err := readPositionsStore(cfg, store, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newPositions(logger, cfg, positionData, store), nil
+}
+
+func newPositions(logger log.Logger, cfg Config, positionData map[Entry]string, store *state.Store) *positions {
 	p := &positions{
 		logger:    logger,
 		cfg:       cfg,
 		positions: positionData,
+		store:     store,
 		quit:      make(chan struct{}),
 		done:      make(chan struct{}),
 	}
 
 	go p.run()
-	return p, nil
+	return p
 }
 
 func (p *positions) Stop() {
@@ -249,11 +278,32 @@ func (p *positions) save() {
 	}
 	p.mtx.Unlock()
 
+	if p.store != nil {
+		if err := saveWithStore(p.cfg, p.store, positions); err != nil {
+			level.Error(p.logger).Log("msg", "error writing positions to the state store", "error", err)
+		}
+		return
+	}
 	if err := writePositionFile(p.cfg.PositionsFile, positions); err != nil {
 		level.Error(p.logger).Log("msg", "error writing positions file", "error", err)
 	}
 }
 
+func writePositionStore(store *state.Store, positions map[Entry]string) error {
+	keys := make(map[string]struct{}, len(positions))
+	for e, pos := range positions {
+		key := entryKey(e)
+		keys[key] = struct{}{}
+		store.Put(stateNamespace, key, []byte(pos))
+	}
+	for _, key := range store.Keys(stateNamespace) {
+		if _, ok := keys[key]; !ok {
+			store.Delete(stateNamespace, key)
+		}
+	}
+	return store.Sync()
+}
+
 // CursorKey returns a key that can be saved as a cursor that is never deleted.
 func CursorKey(key string) string {
 	return fmt.Sprintf("%s%s", cursorKeyPrefix, key)
@@ -316,3 +366,98 @@ func readPositionsFile(cfg Config, logger log.Logger) (map[Entry]string, error)
 
 	return p.Positions, nil
 }
+
+func readPositionsStore(cfg Config, store *state.Store, logger log.Logger) (map[Entry]string, error) {
+	if cfg.PositionsFile != "" {
+		changed, err := positionsFileChanged(cfg.PositionsFile, store)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			positionData, err := readPositionsFile(cfg, logger)
+			if err != nil {
+				return nil, err
+			}
+			if cfg.ReadOnly {
+				// The positions file can't be imported without writing to the store.
+				return positionData, nil
+			}
+			if err := importPositions(cfg.PositionsFile, store, positionData); err != nil {
+				return nil, err
+			}
+			level.Info(logger).Log("msg", "imported positions file into the state store", "path", cfg.PositionsFile, "positions", len(positionData))
+		}
+	}
+
+	positionData := make(map[Entry]string)
+	for _, key := range store.Keys(stateNamespace) {
+		var e Entry
+		if err := json.Unmarshal([]byte(key), &e); err != nil {
+			level.Warn(logger).Log("msg", "ignoring invalid position in the state store", "key", key, "error", err)
+			continue
+		}
+		pos, _ := store.Get(stateNamespace, key)
+		positionData[e] = string(pos)
+	}
+	return positionData, nil
+}
+
+// positionsFileChanged returns whether the positions file at path was written
+// by something other than the Positions of store, that is by a previous
+// version which didn't use a state store. This is the case the first time
+// after an upgrade, and after a rollback.
+func positionsFileChanged(path string, store *state.Store) (bool, error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	written, _ := store.Get(stateImportNamespace, path)
+	return string(written) != fileVersion(fi), nil
+}
+
+// importPositions replaces the positions of store with positionData, read
+// from the positions file at path.
+func importPositions(path string, store *state.Store, positionData map[Entry]string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	store.Put(stateImportNamespace, path, []byte(fileVersion(fi)))
+	return writePositionStore(store, positionData)
+}
+
+// saveWithStore writes positions to the state store, and to the positions
+// file of cfg if it's set. The version of the written positions file is
+// recorded in the store, so that a positions file written by a previous
+// version after a rollback is imported again.
+//
+// TODO: stop writing the positions file once rolling back to a version
+// without the state store isn't supported anymore.
+func saveWithStore(cfg Config, store *state.Store, positions map[Entry]string) error {
+	if cfg.PositionsFile != "" {
+		if err := writePositionFile(cfg.PositionsFile, positions); err != nil {
+			return fmt.Errorf("writing positions file: %w", err)
+		}
+		fi, err := os.Stat(cfg.PositionsFile)
+		if err != nil {
+			return err
+		}
+		store.Put(stateImportNamespace, cfg.PositionsFile, []byte(fileVersion(fi)))
+	}
+	return writePositionStore(store, positions)
+}
+
+// fileVersion identifies the content of the file of fi by its modification
+// time and size.
+func fileVersion(fi os.FileInfo) string {
+	return fmt.Sprintf("%s/%d", fi.ModTime().UTC().Format(time.RFC3339Nano), fi.Size())
+}
+
+// entryKey returns the key of e in a state.Store.
+func entryKey(e Entry) string {
+	// Marshaling a struct of strings never fails.
+	bb, _ := json.Marshal(e)
+	return string(bb)
+}
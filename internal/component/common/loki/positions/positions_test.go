@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 
@@ -365,3 +366,71 @@ positions:
 		Labels: ``,
 	}])
 }
+
+func TestNewWithStore(t *testing.T) {
+	dir := t.TempDir()
+	positionsFile := filepath.Join(dir, "positions.yml")
+	yaml := []byte(`
+positions:
+  ? path: /tmp/initial.log
+    labels: '{job="tmp"}'
+  : "10030"
+`)
+	require.NoError(t, os.WriteFile(positionsFile, yaml, 0644))
+
+	store, err := state.Open(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+
+	cfg := Config{
+		SyncPeriod:    20 * time.Second,
+		PositionsFile: positionsFile,
+	}
+	p, err := NewWithStore(util_log.Logger, store, cfg)
+	require.NoError(t, err)
+
+	// The positions file is imported, and kept.
+	_, err = os.Stat(positionsFile)
+	require.NoError(t, err)
+
+	pos, err := p.Get("/tmp/initial.log", `{job="tmp"}`)
+	require.NoError(t, err)
+	require.Equal(t, int64(10030), pos)
+
+	p.Put("/tmp/foo.log", "{}", 10040)
+	p.Remove("/tmp/initial.log", `{job="tmp"}`)
+	p.Stop()
+	require.NoError(t, store.Close())
+
+	// The positions are also written to the positions file, for a rollback.
+	fromFile, err := readPositionsFile(cfg, util_log.Logger)
+	require.NoError(t, err)
+	require.Equal(t, map[Entry]string{
+		{Path: "/tmp/foo.log", Labels: "{}"}: "10040",
+	}, fromFile)
+
+	// The positions file isn't imported again when it's unchanged.
+	store, err = state.Open(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	defer store.Close()
+	store.Put(stateNamespace, entryKey(Entry{Path: "/tmp/bar.log", Labels: "{}"}), []byte("10050"))
+
+	p, err = NewWithStore(util_log.Logger, store, cfg)
+	require.NoError(t, err)
+	require.Equal(t, map[Entry]string{
+		{Path: "/tmp/foo.log", Labels: "{}"}: "10040",
+		{Path: "/tmp/bar.log", Labels: "{}"}: "10050",
+	}, p.(*positions).positions)
+	p.Stop()
+
+	// The positions file written by a previous version after a rollback is
+	// imported again.
+	require.NoError(t, os.WriteFile(positionsFile, yaml, 0644))
+
+	p, err = NewWithStore(util_log.Logger, store, cfg)
+	require.NoError(t, err)
+	defer p.Stop()
+
+	require.Equal(t, map[Entry]string{
+		{Path: "/tmp/initial.log", Labels: `{job="tmp"}`}: "10030",
+	}, p.(*positions).positions)
+}
@@ -0,0 +1,52 @@
+package queue
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds the metrics of a Queue.
+type Metrics struct {
+	length         prometheus.Gauge
+	spilledLength  prometheus.Gauge
+	spillBytes     prometheus.Gauge
+	capacity       prometheus.Gauge
+	dropped        prometheus.Counter
+	blockedSeconds prometheus.Counter
+}
+
+// NewMetrics creates the metrics of a Queue and registers them to reg. The
+// names of the metrics start with prefix, and unit names the items of the
+// queue in their help, such as "log entries".
+func NewMetrics(reg prometheus.Registerer, prefix, unit string) (*Metrics, error) {
+	m := &Metrics{
+		length: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: prefix + "_length",
+			Help: "Number of " + unit + " in the queue, including the spilled ones.",
+		}),
+		spilledLength: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: prefix + "_spilled_length",
+			Help: "Number of " + unit + " in the spill file of the queue.",
+		}),
+		spillBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: prefix + "_spill_bytes",
+			Help: "Size in bytes of the spill file of the queue.",
+		}),
+		capacity: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: prefix + "_capacity",
+			Help: "Number of " + unit + " the queue holds in memory.",
+		}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: prefix + "_dropped_total",
+			Help: "Total number of " + unit + " dropped by the queue.",
+		}),
+		blockedSeconds: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: prefix + "_blocked_seconds_total",
+			Help: "Total time in seconds producers spent blocked on a full queue.",
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.length, m.spilledLength, m.spillBytes, m.capacity, m.dropped, m.blockedSeconds} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,372 @@
+// Package queue implements the bounded queue which sits on an edge between a
+// producing and a consuming component, such as in loki.queue and
+// otelcol.processor.queue.
+//
+// A queue decouples the producer from the consumer: the producer pushes items
+// as long as the queue has room, and the Policy of the queue decides what
+// happens when it's full.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Policy decides what a Queue does with an item pushed while it's full.
+type Policy string
+
+const (
+	// PolicyBlock blocks the producer until the queue has room. No item is
+	// lost, but a slow consumer slows down the producer.
+	PolicyBlock Policy = "block"
+	// PolicyDropOldest drops the oldest item of the queue to make room for the
+	// new one.
+	PolicyDropOldest Policy = "drop_oldest"
+	// PolicyDropNewest drops the new item.
+	PolicyDropNewest Policy = "drop_newest"
+	// PolicySpillToDisk writes the new item to a spill file, which is read
+	// back once the queue has room. The producer blocks once the spill file is
+	// full.
+	PolicySpillToDisk Policy = "spill_to_disk"
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Policy) MarshalText() ([]byte, error) {
+	return []byte(p), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Policy) UnmarshalText(text []byte) error {
+	switch Policy(text) {
+	case PolicyBlock, PolicyDropOldest, PolicyDropNewest, PolicySpillToDisk:
+		*p = Policy(text)
+		return nil
+	}
+	return fmt.Errorf("unknown queue policy %q, must be one of %q, %q, %q or %q",
+		string(text), PolicyBlock, PolicyDropOldest, PolicyDropNewest, PolicySpillToDisk)
+}
+
+// ErrClosed is returned when pushing to or popping from a closed Queue.
+var ErrClosed = errors.New("queue is closed")
+
+// Codec encodes the items of a Queue into the spill file.
+type Codec[T any] interface {
+	Encode(item T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// Options configures a Queue.
+type Options struct {
+	Policy Policy
+	// Capacity is the number of items the queue holds in memory.
+	Capacity int
+	// SpillDir is the directory of the spill file. Without it, the queue can't
+	// use PolicySpillToDisk.
+	SpillDir string
+	// MaxSpillSize is the maximum size in bytes of the spill file. Only used by
+	// PolicySpillToDisk.
+	MaxSpillSize int64
+}
+
+// Validate returns an error if o isn't valid.
+func (o Options) Validate() error {
+	if o.Capacity <= 0 {
+		return fmt.Errorf("capacity must be greater than 0")
+	}
+	if o.Policy == PolicySpillToDisk && o.SpillDir == "" {
+		return fmt.Errorf("a spill directory is required when the policy is %q", PolicySpillToDisk)
+	}
+	if o.Policy == PolicySpillToDisk && o.MaxSpillSize <= 0 {
+		return fmt.Errorf("max_spill_size must be greater than 0 when the policy is %q", PolicySpillToDisk)
+	}
+	return nil
+}
+
+// Queue is a bounded FIFO queue of items of type T, safe for concurrent use.
+//
+// Items spilled to disk are always older than the items pushed after them, so
+// once the spill file holds an item, new items are spilled too until the
+// consumer catches up. Spilled items aren't kept across restarts.
+type Queue[T any] struct {
+	codec   Codec[T]
+	metrics *Metrics
+
+	mut     sync.Mutex
+	opts    Options
+	items   []T
+	spill   *spillFile
+	closed  bool
+	changed chan struct{} // Closed and replaced whenever the queue changes.
+}
+
+// New creates a new Queue. Its metrics are reported to m.
+func New[T any](codec Codec[T], opts Options, m *Metrics) (*Queue[T], error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	q := &Queue[T]{
+		codec:   codec,
+		metrics: m,
+		opts:    opts,
+		changed: make(chan struct{}),
+	}
+	if opts.SpillDir != "" {
+		spill, err := openSpillFile(opts.SpillDir)
+		if err != nil {
+			return nil, err
+		}
+		q.spill = spill
+	}
+	q.metrics.capacity.Set(float64(opts.Capacity))
+	return q, nil
+}
+
+// Update changes the options of the queue. Items already in the queue are
+// kept, even if they exceed the new capacity. The spill directory can't be
+// changed.
+func (q *Queue[T]) Update(opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	q.mut.Lock()
+	defer q.mut.Unlock()
+
+	opts.SpillDir = q.opts.SpillDir
+	q.opts = opts
+	q.metrics.capacity.Set(float64(opts.Capacity))
+	q.broadcast()
+	return nil
+}
+
+// Push adds item to the queue. Depending on the policy of the queue, Push
+// blocks while the queue is full, or drops an item. Push returns ctx.Err() if
+// ctx is canceled while it's blocked.
+func (q *Queue[T]) Push(ctx context.Context, item T) error {
+	var blockedSince time.Time
+	defer func() {
+		if !blockedSince.IsZero() {
+			q.metrics.blockedSeconds.Add(time.Since(blockedSince).Seconds())
+		}
+	}()
+
+	for {
+		q.mut.Lock()
+		if q.closed {
+			q.mut.Unlock()
+			return ErrClosed
+		}
+
+		ok, err := q.enqueue(item)
+		if !ok && err == nil {
+			switch q.opts.Policy {
+			case PolicyDropNewest:
+				q.metrics.dropped.Inc()
+				ok = true
+			case PolicyDropOldest:
+				// Dropping the oldest item moves a spilled item to memory, which may
+				// not free enough room in the spill file for item, so keep dropping
+				// until it fits.
+				for !ok && err == nil && q.len() > 0 {
+					if _, dropped := q.dequeue(); dropped {
+						q.metrics.dropped.Inc()
+					}
+					ok, err = q.enqueue(item)
+				}
+			}
+		}
+		if ok || err != nil {
+			q.updateLength()
+			q.broadcast()
+			q.mut.Unlock()
+			return err
+		}
+
+		changed := q.changed
+		q.mut.Unlock()
+
+		if blockedSince.IsZero() {
+			blockedSince = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Pop removes the oldest item of the queue and returns it, blocking until the
+// queue holds an item. Pop returns ctx.Err() if ctx is canceled while it's
+// blocked, and ErrClosed once the queue is closed and empty.
+func (q *Queue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		q.mut.Lock()
+		for q.len() > 0 {
+			item, ok := q.dequeue()
+			if !ok {
+				continue
+			}
+			q.updateLength()
+			q.broadcast()
+			q.mut.Unlock()
+			return item, nil
+		}
+		q.updateLength()
+		if q.closed {
+			q.mut.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+
+		changed := q.changed
+		q.mut.Unlock()
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Len returns the number of items in the queue, including the spilled ones.
+func (q *Queue[T]) Len() int {
+	q.mut.Lock()
+	defer q.mut.Unlock()
+	return q.len()
+}
+
+// Close closes the queue and removes its spill file. Blocked calls to Push
+// return ErrClosed, and Pop returns the items left in memory before returning
+// ErrClosed.
+func (q *Queue[T]) Close() error {
+	q.mut.Lock()
+	defer q.mut.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	q.broadcast()
+
+	var err error
+	if q.spill != nil {
+		err = q.spill.close()
+		q.spill = nil
+	}
+	q.updateLength()
+	return err
+}
+
+func (q *Queue[T]) len() int {
+	n := len(q.items)
+	if q.spill != nil {
+		n += q.spill.count
+	}
+	return n
+}
+
+func (q *Queue[T]) spilled() int {
+	if q.spill == nil {
+		return 0
+	}
+	return q.spill.count
+}
+
+// enqueue adds item to the queue if there's room for it, and returns whether
+// it did. Items go to the spill file while it holds items, so that they stay
+// in order, or when memory is full and the policy is PolicySpillToDisk.
+func (q *Queue[T]) enqueue(item T) (bool, error) {
+	if q.spilled() == 0 && len(q.items) < q.opts.Capacity {
+		q.items = append(q.items, item)
+		return true, nil
+	}
+	if q.spill == nil || (q.spilled() == 0 && q.opts.Policy != PolicySpillToDisk) {
+		return false, nil
+	}
+
+	data, err := q.codec.Encode(item)
+	if err != nil {
+		q.metrics.dropped.Inc()
+		return false, fmt.Errorf("encoding item: %w", err)
+	}
+	if ok, err := q.spill.makeRoom(len(data), q.opts.MaxSpillSize); err != nil {
+		q.metrics.dropped.Inc()
+		return false, fmt.Errorf("compacting spill file: %w", err)
+	} else if !ok {
+		return false, nil
+	}
+	if err := q.spill.write(data); err != nil {
+		q.metrics.dropped.Inc()
+		return false, fmt.Errorf("writing to spill file: %w", err)
+	}
+	return true, nil
+}
+
+// dequeue removes the oldest item of the queue, which must not be empty, and
+// refills memory from the spill file. It returns false if the oldest item was
+// spilled and couldn't be read back.
+func (q *Queue[T]) dequeue() (T, bool) {
+	var (
+		item T
+		ok   bool
+	)
+	if len(q.items) > 0 {
+		item, ok = q.items[0], true
+		var zero T
+		q.items[0] = zero
+		q.items = q.items[1:]
+	} else {
+		// Memory is empty while items are spilled if the items last read back
+		// from the spill file couldn't be decoded.
+		item, ok = q.unspill()
+	}
+
+	for q.spilled() > 0 && len(q.items) < q.opts.Capacity {
+		if decoded, decodedOK := q.unspill(); decodedOK {
+			q.items = append(q.items, decoded)
+		}
+	}
+	return item, ok
+}
+
+// unspill reads the oldest item of the spill file. Items which can't be read
+// are dropped.
+func (q *Queue[T]) unspill() (T, bool) {
+	var zero T
+	data, err := q.spill.read()
+	if err != nil {
+		// The rest of the spill file can't be trusted.
+		q.metrics.dropped.Add(float64(q.spill.count))
+		q.spill.reset()
+		return zero, false
+	}
+	item, err := q.codec.Decode(data)
+	if err != nil {
+		q.metrics.dropped.Inc()
+		return zero, false
+	}
+	return item, true
+}
+
+func (q *Queue[T]) updateLength() {
+	q.metrics.length.Set(float64(q.len()))
+	q.metrics.spilledLength.Set(float64(q.spilled()))
+	if q.spill != nil {
+		q.metrics.spillBytes.Set(float64(q.spill.size))
+	} else {
+		q.metrics.spillBytes.Set(0)
+	}
+}
+
+// broadcast wakes up the calls to Push and Pop waiting for the queue to
+// change.
+func (q *Queue[T]) broadcast() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
@@ -0,0 +1,149 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type intCodec struct{}
+
+func (intCodec) Encode(item int) ([]byte, error) { return []byte(strconv.Itoa(item)), nil }
+func (intCodec) Decode(data []byte) (int, error) { return strconv.Atoi(string(data)) }
+
+func newTestQueue(t *testing.T, opts Options) (*Queue[int], *Metrics) {
+	t.Helper()
+	m, err := NewMetrics(prometheus.NewRegistry(), "test_queue", "items")
+	require.NoError(t, err)
+	q, err := New[int](intCodec{}, opts, m)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, q.Close()) })
+	return q, m
+}
+
+func push(t *testing.T, q *Queue[int], items ...int) {
+	t.Helper()
+	for _, item := range items {
+		require.NoError(t, q.Push(context.Background(), item))
+	}
+}
+
+func popAll(t *testing.T, q *Queue[int]) []int {
+	t.Helper()
+	var items []int
+	for q.Len() > 0 {
+		item, err := q.Pop(context.Background())
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestQueue_Block(t *testing.T) {
+	q, m := newTestQueue(t, Options{Policy: PolicyBlock, Capacity: 2})
+	push(t, q, 1, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, q.Push(ctx, 3), context.DeadlineExceeded)
+	require.Greater(t, testutil.ToFloat64(m.blockedSeconds), 0.0)
+
+	done := make(chan error)
+	go func() { done <- q.Push(context.Background(), 3) }()
+
+	item, err := q.Pop(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+	require.NoError(t, <-done)
+
+	require.Equal(t, []int{2, 3}, popAll(t, q))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.dropped))
+}
+
+func TestQueue_DropOldest(t *testing.T) {
+	q, m := newTestQueue(t, Options{Policy: PolicyDropOldest, Capacity: 2})
+	push(t, q, 1, 2, 3, 4)
+
+	require.Equal(t, []int{3, 4}, popAll(t, q))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.dropped))
+}
+
+func TestQueue_DropNewest(t *testing.T) {
+	q, m := newTestQueue(t, Options{Policy: PolicyDropNewest, Capacity: 2})
+	push(t, q, 1, 2, 3, 4)
+
+	require.Equal(t, []int{1, 2}, popAll(t, q))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.dropped))
+}
+
+func TestQueue_SpillToDisk(t *testing.T) {
+	q, m := newTestQueue(t, Options{
+		Policy:       PolicySpillToDisk,
+		Capacity:     2,
+		SpillDir:     t.TempDir(),
+		MaxSpillSize: 3 * (recordHeaderSize + 1),
+	})
+	push(t, q, 1, 2, 3, 4, 5)
+	require.Equal(t, 3.0, testutil.ToFloat64(m.spilledLength))
+
+	// The spill file is full.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, q.Push(ctx, 6), context.DeadlineExceeded)
+
+	item, err := q.Pop(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+
+	// Items pushed while others are spilled are spilled too, to keep them in
+	// order.
+	push(t, q, 6)
+	require.Equal(t, 3.0, testutil.ToFloat64(m.spilledLength))
+
+	require.Equal(t, []int{2, 3, 4, 5, 6}, popAll(t, q))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.spillBytes))
+
+	push(t, q, 7)
+	require.Equal(t, []int{7}, popAll(t, q))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.dropped))
+}
+
+func TestQueue_Update(t *testing.T) {
+	q, m := newTestQueue(t, Options{Policy: PolicyDropNewest, Capacity: 1})
+	push(t, q, 1)
+
+	require.NoError(t, q.Update(Options{Policy: PolicyDropNewest, Capacity: 3}))
+	require.Equal(t, 3.0, testutil.ToFloat64(m.capacity))
+	push(t, q, 2, 3, 4)
+
+	require.Equal(t, []int{1, 2, 3}, popAll(t, q))
+	require.Error(t, q.Update(Options{Policy: PolicySpillToDisk, Capacity: 1, MaxSpillSize: 10}))
+}
+
+func TestQueue_Close(t *testing.T) {
+	q, _ := newTestQueue(t, Options{Policy: PolicyBlock, Capacity: 1})
+	push(t, q, 1)
+
+	done := make(chan error)
+	go func() { done <- q.Push(context.Background(), 2) }()
+	require.NoError(t, q.Close())
+	require.ErrorIs(t, <-done, ErrClosed)
+
+	item, err := q.Pop(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, item)
+	_, err = q.Pop(context.Background())
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func TestPolicy_UnmarshalText(t *testing.T) {
+	var p Policy
+	require.NoError(t, p.UnmarshalText([]byte("drop_oldest")))
+	require.Equal(t, PolicyDropOldest, p)
+	require.Error(t, p.UnmarshalText([]byte("drop")))
+}
@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	spillFilename = "queue.spill"
+	spillFileMode = 0600
+
+	// recordHeaderSize is the size of the length which prefixes every record
+	// of the spill file.
+	recordHeaderSize = 4
+)
+
+// spillFile is an append-only file of length-prefixed records, read from the
+// start. It's reset once all its records are read, and compacted when a new
+// record doesn't fit after the records already read.
+type spillFile struct {
+	path string
+	f    *os.File // Nil until the first record is written.
+
+	readOff int64
+	size    int64
+	count   int
+}
+
+// openSpillFile prepares the spill file in dir, which is only created once
+// the first record is written. A spill file left by a previous run is
+// removed.
+func openSpillFile(dir string) (*spillFile, error) {
+	path := filepath.Join(dir, spillFilename)
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return &spillFile{path: path}, nil
+}
+
+// makeRoom returns whether a record of n bytes can be written without the
+// file exceeding max bytes, compacting the file if needed. A single record
+// always fits in an empty file, so that items bigger than max can still go
+// through the queue.
+func (s *spillFile) makeRoom(n int, max int64) (bool, error) {
+	fits := func() bool { return s.size == 0 || s.size+recordHeaderSize+int64(n) <= max }
+	if fits() || s.readOff == 0 {
+		return fits(), nil
+	}
+	if err := s.compact(); err != nil {
+		return false, err
+	}
+	return fits(), nil
+}
+
+// compact moves the unread records to the start of the file, to reuse the
+// space of the records already read.
+func (s *spillFile) compact() error {
+	live := s.size - s.readOff
+	src := io.NewSectionReader(s.f, s.readOff, live)
+	if _, err := io.Copy(io.NewOffsetWriter(s.f, 0), src); err != nil {
+		return err
+	}
+	if err := s.f.Truncate(live); err != nil {
+		return err
+	}
+	s.readOff, s.size = 0, live
+	return nil
+}
+
+func (s *spillFile) write(data []byte) error {
+	if s.f == nil {
+		if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, spillFileMode)
+		if err != nil {
+			return err
+		}
+		s.f = f
+	}
+
+	buf := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[recordHeaderSize:], data)
+	if _, err := s.f.WriteAt(buf, s.size); err != nil {
+		return err
+	}
+	s.size += int64(len(buf))
+	s.count++
+	return nil
+}
+
+// read reads the oldest record of the file, which must not be empty.
+func (s *spillFile) read() ([]byte, error) {
+	var header [recordHeaderSize]byte
+	if _, err := s.f.ReadAt(header[:], s.readOff); err != nil {
+		return nil, err
+	}
+	n := int64(binary.BigEndian.Uint32(header[:]))
+	if s.readOff+recordHeaderSize+n > s.size {
+		return nil, fmt.Errorf("corrupted record at offset %d", s.readOff)
+	}
+	data := make([]byte, n)
+	if _, err := s.f.ReadAt(data, s.readOff+recordHeaderSize); err != nil {
+		return nil, err
+	}
+
+	s.readOff += recordHeaderSize + n
+	s.count--
+	if s.count == 0 {
+		s.reset()
+	}
+	return data, nil
+}
+
+// reset empties the file. Records are written at known offsets, so failing
+// to truncate the file only delays freeing its disk space.
+func (s *spillFile) reset() {
+	s.readOff, s.size, s.count = 0, 0, 0
+	if s.f != nil {
+		_ = s.f.Truncate(0)
+	}
+}
+
+// close closes and removes the file.
+func (s *spillFile) close() error {
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return errors.Join(err, os.Remove(s.path))
+}
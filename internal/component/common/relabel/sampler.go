@@ -0,0 +1,71 @@
+package relabel
+
+import (
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// DefaultSamplerSize is the number of label sets kept by the Sampler of the
+// relabeling components.
+const DefaultSamplerSize = 1000
+
+// InputSampler is implemented by the relabeling components which keep a
+// sample of the label sets they relabel, so that other rules can be evaluated
+// against the same inputs.
+type InputSampler interface {
+	// SampledInputs returns the sampled label sets, oldest first.
+	SampledInputs() []labels.Labels
+}
+
+// Sampler keeps the most recent distinct label sets passed to Add, up to a
+// fixed number. It's safe for concurrent use.
+type Sampler struct {
+	mut    sync.Mutex
+	size   int
+	hashes map[uint64]struct{}
+	inputs []labels.Labels
+	next   int
+}
+
+// NewSampler returns a Sampler which keeps up to size label sets.
+func NewSampler(size int) *Sampler {
+	return &Sampler{
+		size:   size,
+		hashes: make(map[uint64]struct{}, size),
+		inputs: make([]labels.Labels, 0, size),
+	}
+}
+
+// Add samples a copy of lbls, which must be sorted, evicting the oldest label
+// set once the Sampler is full. Label sets which are already sampled are
+// ignored.
+func (s *Sampler) Add(lbls labels.Labels) {
+	hash := lbls.Hash()
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if _, ok := s.hashes[hash]; ok || s.size <= 0 {
+		return
+	}
+	s.hashes[hash] = struct{}{}
+
+	if len(s.inputs) < s.size {
+		s.inputs = append(s.inputs, lbls.Copy())
+		return
+	}
+	delete(s.hashes, s.inputs[s.next].Hash())
+	s.inputs[s.next] = lbls.Copy()
+	s.next = (s.next + 1) % s.size
+}
+
+// Samples returns the sampled label sets, oldest first.
+func (s *Sampler) Samples() []labels.Labels {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	res := make([]labels.Labels, 0, len(s.inputs))
+	res = append(res, s.inputs[s.next:]...)
+	return append(res, s.inputs[:s.next]...)
+}
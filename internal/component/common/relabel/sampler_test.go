@@ -0,0 +1,32 @@
+package relabel
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler(t *testing.T) {
+	s := NewSampler(2)
+	require.Empty(t, s.Samples())
+
+	a := labels.FromStrings("job", "a")
+	b := labels.FromStrings("job", "b")
+	c := labels.FromStrings("job", "c")
+
+	s.Add(a)
+	s.Add(a)
+	require.Equal(t, []labels.Labels{a}, s.Samples())
+
+	s.Add(b)
+	require.Equal(t, []labels.Labels{a, b}, s.Samples())
+
+	// The oldest label set is evicted once the sampler is full.
+	s.Add(c)
+	require.Equal(t, []labels.Labels{b, c}, s.Samples())
+
+	// An evicted label set can be sampled again.
+	s.Add(a)
+	require.Equal(t, []labels.Labels{c, a}, s.Samples())
+}
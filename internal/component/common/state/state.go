@@ -0,0 +1,334 @@
+// Package state implements a persistent key-value store which components use
+// to keep state across restarts, such as how far they read a log file.
+//
+// A store lives in a directory of the data path of its component, so that all
+// the state of Alloy is kept under --storage.path and can be backed up and
+// restored as a single directory.
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+const (
+	logFilename = "state.log"
+	logFileMode = 0600
+
+	// compactMinRecords is the minimum number of records the log must hold
+	// before it's compacted.
+	compactMinRecords = 1024
+)
+
+// ErrClosed is returned when syncing a closed Store.
+var ErrClosed = errors.New("state store is closed")
+
+// Store is a persistent key-value store. Keys are grouped in namespaces, so
+// that the different parts of a component can share its store.
+//
+// Changes are kept in memory until Sync is called, which appends them to a log
+// file and fsyncs it. The log is compacted into a new file when it holds more
+// than twice as many records as there are keys.
+type Store struct {
+	dir string
+
+	mut     sync.Mutex
+	data    map[string]map[string][]byte
+	pending []record
+	records int // Number of records in the log file.
+	f       *os.File
+}
+
+// record is a change of a key, as written to the log file.
+type record struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     []byte `json:"value,omitempty"`
+	Deleted   bool   `json:"deleted,omitempty"`
+}
+
+// Open opens the store in dir, creating dir if it doesn't exist.
+//
+// If Alloy stopped while the log file was being written, the incomplete
+// record at the end of the log is discarded.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:  dir,
+		data: make(map[string]map[string][]byte),
+	}
+
+	f, err := os.OpenFile(s.logPath(), os.O_RDWR|os.O_CREATE, logFileMode)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := s.replay(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("reading state log %s: %w", s.logPath(), err)
+	}
+	if err := f.Truncate(valid); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(valid, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	s.f = f
+	return s, nil
+}
+
+// replay applies the records of the log file r, and returns the size of its
+// valid part.
+func (s *Store) replay(r io.Reader) (int64, error) {
+	var (
+		br    = bufio.NewReader(r)
+		valid int64
+	)
+	for {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Either the end of the log, or an incomplete record.
+			return valid, nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// Stop at the first corrupted record, as the records after it can't be
+			// trusted either.
+			return valid, nil
+		}
+		s.apply(rec)
+		s.records++
+		valid += int64(len(line))
+	}
+}
+
+func (s *Store) apply(rec record) {
+	ns := s.data[rec.Namespace]
+	if rec.Deleted {
+		delete(ns, rec.Key)
+		if len(ns) == 0 {
+			delete(s.data, rec.Namespace)
+		}
+		return
+	}
+	if ns == nil {
+		ns = make(map[string][]byte)
+		s.data[rec.Namespace] = ns
+	}
+	ns[rec.Key] = rec.Value
+}
+
+// Get returns the value of key in namespace, and whether key exists.
+func (s *Store) Get(namespace, key string) ([]byte, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	value, ok := s.data[namespace][key]
+	return bytes.Clone(value), ok
+}
+
+// Keys returns the sorted keys of namespace.
+func (s *Store) Keys(namespace string) []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	keys := make([]string, 0, len(s.data[namespace]))
+	for key := range s.data[namespace] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Put sets the value of key in namespace. The change is persisted by the next
+// call to Sync.
+func (s *Store) Put(namespace, key string, value []byte) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if current, ok := s.data[namespace][key]; ok && bytes.Equal(current, value) {
+		return
+	}
+	rec := record{Namespace: namespace, Key: key, Value: bytes.Clone(value)}
+	s.apply(rec)
+	s.pending = append(s.pending, rec)
+}
+
+// Delete removes key from namespace. The change is persisted by the next call
+// to Sync.
+func (s *Store) Delete(namespace, key string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if _, ok := s.data[namespace][key]; !ok {
+		return
+	}
+	rec := record{Namespace: namespace, Key: key, Deleted: true}
+	s.apply(rec)
+	s.pending = append(s.pending, rec)
+}
+
+// Sync persists the changes made since the last call to Sync.
+func (s *Store) Sync() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.sync()
+}
+
+func (s *Store) sync() error {
+	if s.f == nil {
+		return ErrClosed
+	}
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	if records := s.records + len(s.pending); records > compactMinRecords && records > 2*s.size() {
+		return s.compact()
+	}
+
+	var buf bytes.Buffer
+	if err := writeRecords(&buf, s.pending); err != nil {
+		return err
+	}
+	if _, err := s.f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	s.records += len(s.pending)
+	s.pending = nil
+	return nil
+}
+
+// size returns the number of keys across all namespaces.
+func (s *Store) size() int {
+	var n int
+	for _, ns := range s.data {
+		n += len(ns)
+	}
+	return n
+}
+
+// compact replaces the log file with a new one holding a single record per
+// key.
+func (s *Store) compact() error {
+	recs := make([]record, 0, s.size())
+	for namespace, ns := range s.data {
+		for key, value := range ns {
+			recs = append(recs, record{Namespace: namespace, Key: key, Value: value})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeRecords(&buf, recs); err != nil {
+		return err
+	}
+
+	tmpPath := s.logPath() + ".tmp"
+	if err := writeFileSync(tmpPath, buf.Bytes()); err != nil {
+		return err
+	}
+
+	// Close the old log file before replacing it, which Windows requires.
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	s.f = nil
+
+	// Reopen the log file even if the rename failed, so that the store keeps
+	// appending to the old log.
+	renameErr := os.Rename(tmpPath, s.logPath())
+	f, err := os.OpenFile(s.logPath(), os.O_WRONLY|os.O_APPEND, logFileMode)
+	if err != nil {
+		return errors.Join(renameErr, err)
+	}
+	s.f = f
+	if renameErr != nil {
+		return renameErr
+	}
+
+	s.records = len(recs)
+	s.pending = nil
+	return syncDir(s.dir)
+}
+
+// Close persists the pending changes and closes the store.
+func (s *Store) Close() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	syncErr := s.sync()
+	if s.f == nil {
+		// The log file couldn't be reopened after compacting it.
+		return syncErr
+	}
+	closeErr := s.f.Close()
+	s.f = nil
+	return errors.Join(syncErr, closeErr)
+}
+
+func (s *Store) logPath() string {
+	return filepath.Join(s.dir, logFilename)
+}
+
+func writeRecords(w io.Writer, recs []record) error {
+	enc := json.NewEncoder(w)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, logFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir fsyncs dir so that a rename in it is persisted.
+func syncDir(dir string) error {
+	// Directories can't be opened for syncing on Windows.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	require.NoError(t, err)
+
+	s.Put("positions", "a.log", []byte("10"))
+	s.Put("positions", "b.log", []byte("20"))
+	s.Put("bookmark", "a.log", []byte("bookmark"))
+	s.Delete("positions", "b.log")
+	require.NoError(t, s.Sync())
+
+	s.Put("positions", "c.log", []byte("30"))
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	value, ok := s.Get("positions", "a.log")
+	require.True(t, ok)
+	require.Equal(t, []byte("10"), value)
+
+	_, ok = s.Get("positions", "b.log")
+	require.False(t, ok)
+
+	require.Equal(t, []string{"a.log", "c.log"}, s.Keys("positions"))
+	require.Equal(t, []string{"a.log"}, s.Keys("bookmark"))
+	require.Empty(t, s.Keys("missing"))
+}
+
+func TestStore_IncompleteRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	require.NoError(t, err)
+	s.Put("positions", "a.log", []byte("10"))
+	require.NoError(t, s.Close())
+
+	// Simulate a crash while a record was written.
+	f, err := os.OpenFile(filepath.Join(dir, logFilename), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"namespace":"positions","key":"b.l`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.log"}, s.Keys("positions"))
+
+	// New records are appended after the last valid one.
+	s.Put("positions", "b.log", []byte("20"))
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, []string{"a.log", "b.log"}, s.Keys("positions"))
+}
+
+func TestStore_Compaction(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	require.NoError(t, err)
+
+	for i := 0; i < 2*compactMinRecords; i++ {
+		s.Put("positions", "a.log", []byte(fmt.Sprint(i)))
+		require.NoError(t, s.Sync())
+	}
+	require.Less(t, s.records, compactMinRecords+2)
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	value, ok := s.Get("positions", "a.log")
+	require.True(t, ok)
+	require.Equal(t, []byte(fmt.Sprint(2*compactMinRecords-1)), value)
+}
+
+func TestStore_Closed(t *testing.T) {
+	s, err := Open(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close())
+
+	s.Put("positions", "a.log", []byte("10"))
+	require.ErrorIs(t, s.Sync(), ErrClosed)
+}
@@ -114,6 +114,20 @@ type DebugComponent interface {
 	DebugInfo() interface{}
 }
 
+// ReadyComponent is an extension interface for components which can't
+// receive data as soon as they run, such as components which replay a WAL
+// when they start.
+type ReadyComponent interface {
+	Component
+
+	// Ready returns whether the component is ready to receive data. Components
+	// which send data to the component wait for it to be ready before they
+	// start, when the startup gate of the controller is enabled.
+	//
+	// Ready must be safe for calling concurrently.
+	Ready() bool
+}
+
 // LiveDebugging is an interface used by the components that support the live debugging feature.
 type LiveDebugging interface {
 	// LiveDebugging is invoked when the number of consumers changes.
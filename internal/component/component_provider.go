@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/grafana/alloy/syntax/encoding/alloyjson"
 )
 
@@ -122,6 +123,7 @@ type Info struct {
 
 	ComponentName string // Name of the component.
 	Health        Health // Current component health.
+	Paused        bool   // Whether the component was paused through the API.
 
 	Arguments Arguments   // Current arguments value of the component.
 	Exports   Exports     // Current exports value of the component.
@@ -147,6 +149,7 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 			References       []string             `json:"referencesTo"`
 			ReferencedBy     []string             `json:"referencedBy"`
 			Health           *componentHealthJSON `json:"health"`
+			Paused           bool                 `json:"paused,omitempty"`
 			Original         string               `json:"original"`
 			Arguments        json.RawMessage      `json:"arguments,omitempty"`
 			Exports          json.RawMessage      `json:"exports,omitempty"`
@@ -183,7 +186,7 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
-	return json.Marshal(&componentDetailJSON{
+	bb, err := json.Marshal(&componentDetailJSON{
 		Name:         info.ComponentName,
 		Type:         "block",
 		ModuleID:     info.ID.ModuleID,
@@ -196,11 +199,19 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 			Message:     info.Health.Message,
 			UpdatedTime: info.Health.UpdateTime,
 		},
+		Paused:           info.Paused,
 		Arguments:        arguments,
 		Exports:          exports,
 		DebugInfo:        debugInfo,
 		CreatedModuleIDs: info.ModuleIDs,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Secrets can leak into the health message, debug info, or original
+	// configuration of a component.
+	return secrets.ScrubBytes(bb), nil
 }
 
 // GetAllComponents enumerates over all of the modules in p and returns the set
@@ -0,0 +1,21 @@
+package component
+
+import "context"
+
+// ConnectivityChecker is an extension interface for the Arguments of
+// components which send data to remote endpoints. It's used by the
+// check-connectivity tool to verify the authentication, TLS, and proxy
+// settings of the endpoints without running the components.
+type ConnectivityChecker interface {
+	// CheckConnectivity sends a request with no data, or as little data as the
+	// protocol allows, to every endpoint of the arguments, and returns one
+	// result per endpoint.
+	CheckConnectivity(ctx context.Context) []ConnectivityResult
+}
+
+// ConnectivityResult is the result of checking the connectivity of a single
+// endpoint.
+type ConnectivityResult struct {
+	Endpoint string // URL or address of the endpoint, without credentials.
+	Err      error  // Nil if the endpoint accepted the request.
+}
@@ -1,6 +1,7 @@
 package azure
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -24,7 +25,9 @@ func init() {
 		Exports:   discovery.Exports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			return discovery.NewFromConvertibleConfig(opts, args.(Arguments))
+			return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+				return &discovererConfig{args: args.(Arguments)}, nil
+			})
 		},
 	})
 }
@@ -37,6 +40,7 @@ type Arguments struct {
 	ManagedIdentity *ManagedIdentity `alloy:"managed_identity,block,optional"`
 	RefreshInterval time.Duration    `alloy:"refresh_interval,attr,optional"`
 	ResourceGroup   string           `alloy:"resource_group,attr,optional"`
+	Subscriptions   []Subscription   `alloy:"subscription,block,optional"`
 
 	ProxyConfig     *config.ProxyConfig `alloy:",squash"`
 	FollowRedirects bool                `alloy:"follow_redirects,attr,optional"`
@@ -54,6 +58,15 @@ type ManagedIdentity struct {
 	ClientID string `alloy:"client_id,attr"`
 }
 
+// Subscription is an additional subscription to discover the VMs of. The
+// credentials of the component are used unless the block has its own.
+type Subscription struct {
+	SubscriptionID  string           `alloy:"subscription_id,attr"`
+	ResourceGroup   string           `alloy:"resource_group,attr,optional"`
+	OAuth           *OAuth           `alloy:"oauth,block,optional"`
+	ManagedIdentity *ManagedIdentity `alloy:"managed_identity,block,optional"`
+}
+
 var DefaultArguments = Arguments{
 	Environment:     azure.PublicCloud.Name,
 	Port:            80,
@@ -69,10 +82,36 @@ func (a *Arguments) SetToDefault() {
 
 // Validate implements syntax.Validator.
 func (a *Arguments) Validate() error {
-	if a.OAuth == nil && a.ManagedIdentity == nil || a.OAuth != nil && a.ManagedIdentity != nil {
+	// The credentials of the component may be omitted when every subscription
+	// block has its own.
+	credentialsRequired := a.SubscriptionID != "" || len(a.Subscriptions) == 0
+	for _, s := range a.Subscriptions {
+		if s.OAuth == nil && s.ManagedIdentity == nil {
+			credentialsRequired = true
+		}
+	}
+	if a.OAuth != nil && a.ManagedIdentity != nil || credentialsRequired && a.OAuth == nil && a.ManagedIdentity == nil {
 		return fmt.Errorf("exactly one of oauth or managed_identity must be specified")
 	}
 
+	seen := map[string]struct{}{}
+	if a.SubscriptionID != "" {
+		seen[a.SubscriptionID] = struct{}{}
+	}
+	for _, s := range a.Subscriptions {
+		if s.SubscriptionID == "" {
+			return errors.New("subscription_id must not be empty in subscription blocks")
+		}
+		if _, ok := seen[s.SubscriptionID]; ok {
+			return fmt.Errorf("subscription %q is specified more than once", s.SubscriptionID)
+		}
+		seen[s.SubscriptionID] = struct{}{}
+
+		if s.OAuth != nil && s.ManagedIdentity != nil {
+			return fmt.Errorf("at most one of oauth or managed_identity can be specified for subscription %q", s.SubscriptionID)
+		}
+	}
+
 	if err := a.TLSConfig.Validate(); err != nil {
 		return err
 	}
@@ -80,21 +119,44 @@ func (a *Arguments) Validate() error {
 	return a.ProxyConfig.Validate()
 }
 
+// Convert returns the Prometheus configuration of the subscription of the
+// subscription_id argument.
 func (a Arguments) Convert() discovery.DiscovererConfig {
+	return a.sdConfig(a.SubscriptionID, a.ResourceGroup, a.OAuth, a.ManagedIdentity)
+}
+
+// sdConfigs returns the Prometheus configurations of the subscriptions to
+// discover the VMs of.
+func (a Arguments) sdConfigs() []*prom_discovery.SDConfig {
+	var res []*prom_discovery.SDConfig
+	if a.SubscriptionID != "" || len(a.Subscriptions) == 0 {
+		res = append(res, a.sdConfig(a.SubscriptionID, a.ResourceGroup, a.OAuth, a.ManagedIdentity))
+	}
+	for _, s := range a.Subscriptions {
+		oauth, managedIdentity := s.OAuth, s.ManagedIdentity
+		if oauth == nil && managedIdentity == nil {
+			oauth, managedIdentity = a.OAuth, a.ManagedIdentity
+		}
+		res = append(res, a.sdConfig(s.SubscriptionID, s.ResourceGroup, oauth, managedIdentity))
+	}
+	return res
+}
+
+func (a Arguments) sdConfig(subscriptionID, resourceGroup string, oauth *OAuth, managedIdentity *ManagedIdentity) *prom_discovery.SDConfig {
 	var (
 		authMethod   string
 		clientID     string
 		tenantID     string
 		clientSecret common.Secret
 	)
-	if a.OAuth != nil {
+	if oauth != nil {
 		authMethod = "OAuth"
-		clientID = a.OAuth.ClientID
-		tenantID = a.OAuth.TenantID
-		clientSecret = common.Secret(a.OAuth.ClientSecret)
-	} else {
+		clientID = oauth.ClientID
+		tenantID = oauth.TenantID
+		clientSecret = common.Secret(oauth.ClientSecret)
+	} else if managedIdentity != nil {
 		authMethod = "ManagedIdentity"
-		clientID = a.ManagedIdentity.ClientID
+		clientID = managedIdentity.ClientID
 	}
 
 	httpClientConfig := config.DefaultHTTPClientConfig
@@ -106,13 +168,13 @@ func (a Arguments) Convert() discovery.DiscovererConfig {
 	return &prom_discovery.SDConfig{
 		Environment:          a.Environment,
 		Port:                 a.Port,
-		SubscriptionID:       a.SubscriptionID,
+		SubscriptionID:       subscriptionID,
 		TenantID:             tenantID,
 		ClientID:             clientID,
 		ClientSecret:         clientSecret,
 		RefreshInterval:      model.Duration(a.RefreshInterval),
 		AuthenticationMethod: authMethod,
-		ResourceGroup:        a.ResourceGroup,
+		ResourceGroup:        resourceGroup,
 		HTTPClientConfig:     *httpClientConfig.Convert(),
 	}
 }
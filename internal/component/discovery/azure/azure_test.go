@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/prometheus/common/model"
 	promdiscovery "github.com/prometheus/prometheus/discovery/azure"
 	"github.com/stretchr/testify/require"
@@ -180,3 +181,154 @@ func TestConvert(t *testing.T) {
 	assert.Equal(t, true, promArgs.HTTPClientConfig.EnableHTTP2)
 	assert.Equal(t, "http://example:8080", promArgs.HTTPClientConfig.ProxyURL.String())
 }
+
+func TestSubscriptions(t *testing.T) {
+	alloyCfg := `
+		subscription_id = "subid"
+		managed_identity {
+			client_id = "clientid"
+		}
+		subscription {
+			subscription_id = "subid2"
+			resource_group = "test"
+		}
+		subscription {
+			subscription_id = "subid3"
+			oauth {
+				client_id = "clientid3"
+				tenant_id = "tenantid3"
+				client_secret = "clientsecret3"
+			}
+		}`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyCfg), &args)
+	require.NoError(t, err)
+
+	sdConfigs := args.sdConfigs()
+	require.Len(t, sdConfigs, 3)
+
+	assert.Equal(t, "subid", sdConfigs[0].SubscriptionID)
+	assert.Equal(t, "ManagedIdentity", sdConfigs[0].AuthenticationMethod)
+	assert.Equal(t, "clientid", sdConfigs[0].ClientID)
+
+	assert.Equal(t, "subid2", sdConfigs[1].SubscriptionID)
+	assert.Equal(t, "test", sdConfigs[1].ResourceGroup)
+	assert.Equal(t, "ManagedIdentity", sdConfigs[1].AuthenticationMethod)
+	assert.Equal(t, "clientid", sdConfigs[1].ClientID)
+
+	assert.Equal(t, "subid3", sdConfigs[2].SubscriptionID)
+	assert.Equal(t, "", sdConfigs[2].ResourceGroup)
+	assert.Equal(t, "OAuth", sdConfigs[2].AuthenticationMethod)
+	assert.Equal(t, "clientid3", sdConfigs[2].ClientID)
+	assert.Equal(t, "tenantid3", sdConfigs[2].TenantID)
+	assert.Equal(t, "clientsecret3", string(sdConfigs[2].ClientSecret))
+	for _, sdConfig := range sdConfigs {
+		assert.Equal(t, 80, sdConfig.Port)
+		assert.Equal(t, model.Duration(5*time.Minute), sdConfig.RefreshInterval)
+	}
+}
+
+func TestValidateSubscriptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "credentials of every subscription block",
+			cfg: `
+				subscription {
+					subscription_id = "subid"
+					managed_identity {
+						client_id = "clientid"
+					}
+				}`,
+		},
+		{
+			name: "subscription block without credentials",
+			cfg: `
+				subscription {
+					subscription_id = "subid"
+					managed_identity {
+						client_id = "clientid"
+					}
+				}
+				subscription {
+					subscription_id = "subid2"
+				}`,
+			expectedErr: "exactly one of oauth or managed_identity must be specified",
+		},
+		{
+			name: "duplicate subscription",
+			cfg: `
+				subscription_id = "subid"
+				managed_identity {
+					client_id = "clientid"
+				}
+				subscription {
+					subscription_id = "subid"
+				}`,
+			expectedErr: `subscription "subid" is specified more than once`,
+		},
+		{
+			name: "both credentials in subscription block",
+			cfg: `
+				subscription {
+					subscription_id = "subid"
+					managed_identity {
+						client_id = "clientid"
+					}
+					oauth {
+						client_id = "clientid"
+						tenant_id = "tenantid"
+						client_secret = "clientsecret"
+					}
+				}`,
+			expectedErr: `at most one of oauth or managed_identity can be specified for subscription "subid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.cfg), &args)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestScaleSetLabels(t *testing.T) {
+	vm := virtualMachine{
+		ID:           "/subscriptions/subid/resourceGroups/test/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/3",
+		Name:         "vmss_3",
+		ComputerName: "vmss000003",
+		Location:     "westeurope",
+		OsType:       "Linux",
+		Size:         "Standard_D2s_v3",
+		Tags:         map[string]*string{"team": to.Ptr("observability")},
+		ScaleSet:     "vmss",
+		InstanceID:   "3",
+		FaultDomain:  "1",
+		ScaleSetTags: map[string]*string{"app-name": to.Ptr("frontend")},
+	}
+
+	require.Equal(t, model.LabelSet{
+		"__meta_azure_machine_id":                     model.LabelValue(vm.ID),
+		"__meta_azure_machine_name":                   "vmss_3",
+		"__meta_azure_machine_computer_name":          "vmss000003",
+		"__meta_azure_machine_os_type":                "Linux",
+		"__meta_azure_machine_location":               "westeurope",
+		"__meta_azure_machine_resource_group":         "test",
+		"__meta_azure_machine_size":                   "Standard_D2s_v3",
+		"__meta_azure_machine_tag_team":               "observability",
+		"__meta_azure_machine_scale_set":              "vmss",
+		"__meta_azure_machine_scale_set_instance_id":  "3",
+		"__meta_azure_machine_scale_set_fault_domain": "1",
+		"__meta_azure_machine_scale_set_tag_app_name": "frontend",
+	}, vm.labels("test"))
+}
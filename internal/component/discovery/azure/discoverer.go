@@ -0,0 +1,698 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	cache "github.com/Code-Hex/go-generics-cache"
+	"github.com/Code-Hex/go-generics-cache/policy/lru"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/version"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	prom_azure "github.com/prometheus/prometheus/discovery/azure"
+	"github.com/prometheus/prometheus/discovery/refresh"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	azureLabel                           = model.MetaLabelPrefix + "azure_"
+	azureLabelSubscriptionID             = azureLabel + "subscription_id"
+	azureLabelTenantID                   = azureLabel + "tenant_id"
+	azureLabelMachineID                  = azureLabel + "machine_id"
+	azureLabelMachineResourceGroup       = azureLabel + "machine_resource_group"
+	azureLabelMachineName                = azureLabel + "machine_name"
+	azureLabelMachineComputerName        = azureLabel + "machine_computer_name"
+	azureLabelMachineOSType              = azureLabel + "machine_os_type"
+	azureLabelMachineLocation            = azureLabel + "machine_location"
+	azureLabelMachinePrivateIP           = azureLabel + "machine_private_ip"
+	azureLabelMachinePublicIP            = azureLabel + "machine_public_ip"
+	azureLabelMachineTag                 = azureLabel + "machine_tag_"
+	azureLabelMachineScaleSet            = azureLabel + "machine_scale_set"
+	azureLabelMachineScaleSetInstanceID  = azureLabel + "machine_scale_set_instance_id"
+	azureLabelMachineScaleSetFaultDomain = azureLabel + "machine_scale_set_fault_domain"
+	azureLabelMachineScaleSetTag         = azureLabel + "machine_scale_set_tag_"
+	azureLabelMachineSize                = azureLabel + "machine_size"
+
+	authMethodManagedIdentity = "ManagedIdentity"
+	authMethodOAuth           = "OAuth"
+)
+
+var userAgent = fmt.Sprintf("Prometheus/%s", version.Version)
+
+// discovererConfig creates the discoverers of the component. They work like
+// the Prometheus Azure discoverer, but discover the VMs of every subscription
+// of the arguments, and add the instance labels of the VMs of scale sets.
+type discovererConfig struct {
+	args Arguments
+}
+
+var _ prom_discovery.Config = (*discovererConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (*discovererConfig) Name() string {
+	return "azure"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *discovererConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := opts.Metrics.(*discovererMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	d := &discoverer{
+		subscriptions:   c.args.sdConfigs(),
+		port:            c.args.Port,
+		refreshInterval: c.args.RefreshInterval,
+		logger:          logger,
+		metrics:         m,
+		cache:           cache.New(cache.AsLRU[string, *armnetwork.Interface](lru.WithCapacity(5000))),
+	}
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              logger,
+		Mech:                "azure",
+		Interval:            c.args.RefreshInterval,
+		RefreshF:            d.refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*discovererConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	m := &discovererMetrics{
+		refreshMetrics: rmi,
+		failuresCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_sd_azure_failures_total",
+			Help: "Number of Azure service discovery refresh failures.",
+		}),
+		cacheHitCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_sd_azure_cache_hit_total",
+			Help: "Number of cache hit during refresh.",
+		}),
+	}
+	m.metricRegisterer = prom_discovery.NewMetricRegisterer(reg, []prometheus.Collector{
+		m.failuresCount,
+		m.cacheHitCount,
+	})
+	return m
+}
+
+var _ prom_discovery.DiscovererMetrics = (*discovererMetrics)(nil)
+
+type discovererMetrics struct {
+	refreshMetrics   prom_discovery.RefreshMetricsInstantiator
+	failuresCount    prometheus.Counter
+	cacheHitCount    prometheus.Counter
+	metricRegisterer prom_discovery.MetricRegisterer
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Register() error {
+	return m.metricRegisterer.RegisterMetrics()
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Unregister() {
+	m.metricRegisterer.UnregisterMetrics()
+}
+
+// discoverer discovers the VMs of the subscriptions on every refresh.
+type discoverer struct {
+	subscriptions   []*prom_azure.SDConfig
+	port            int
+	refreshInterval time.Duration
+	logger          log.Logger
+	metrics         *discovererMetrics
+	cache           *cache.Cache[string, *armnetwork.Interface]
+}
+
+// refresh returns a target group per subscription. The subscriptions are
+// refreshed independently: the groups of the subscriptions which fail to
+// refresh are left out, so that their previous targets are kept.
+func (d *discoverer) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	defer level.Debug(d.logger).Log("msg", "Azure discovery completed")
+
+	var (
+		wg   sync.WaitGroup
+		mut  sync.Mutex
+		tgs  = make([]*targetgroup.Group, 0, len(d.subscriptions))
+		errs []error
+	)
+
+	wg.Add(len(d.subscriptions))
+	for _, cfg := range d.subscriptions {
+		go func(cfg *prom_azure.SDConfig) {
+			defer wg.Done()
+			tg, err := d.refreshSubscription(ctx, cfg)
+
+			mut.Lock()
+			defer mut.Unlock()
+			if err != nil {
+				d.metrics.failuresCount.Inc()
+				level.Error(d.logger).Log("msg", "unable to refresh Azure subscription", "subscription_id", cfg.SubscriptionID, "err", err)
+				errs = append(errs, fmt.Errorf("subscription %q: %w", cfg.SubscriptionID, err))
+				return
+			}
+			tgs = append(tgs, tg)
+		}(cfg)
+	}
+	wg.Wait()
+
+	if len(tgs) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return tgs, nil
+}
+
+// refreshSubscription returns the targets of the VMs of a subscription.
+func (d *discoverer) refreshSubscription(ctx context.Context, cfg *prom_azure.SDConfig) (*targetgroup.Group, error) {
+	client, err := createAzureClient(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Azure client: %w", err)
+	}
+	client.logger = d.logger
+
+	machines, err := client.getVMs(ctx, cfg.ResourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("could not get virtual machines: %w", err)
+	}
+
+	level.Debug(d.logger).Log("msg", "Found virtual machines during Azure discovery.", "subscription_id", cfg.SubscriptionID, "count", len(machines))
+
+	// Load the vms managed by scale sets.
+	scaleSets, err := client.getScaleSets(ctx, cfg.ResourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("could not get virtual machine scale sets: %w", err)
+	}
+
+	for _, scaleSet := range scaleSets {
+		scaleSetVms, err := client.getScaleSetVMs(ctx, scaleSet)
+		if err != nil {
+			return nil, fmt.Errorf("could not get virtual machine scale set vms: %w", err)
+		}
+		machines = append(machines, scaleSetVms...)
+	}
+
+	// We have the slice of machines. Now turn them into targets.
+	// Doing them in go routines because the network interface calls are slow.
+	type target struct {
+		labelSet model.LabelSet
+		err      error
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(machines))
+	ch := make(chan target, len(machines))
+	for _, vm := range machines {
+		go func(vm virtualMachine) {
+			defer wg.Done()
+			labels, err := d.machineLabels(ctx, client, cfg, vm)
+			ch <- target{labelSet: labels, err: err}
+		}(vm)
+	}
+
+	wg.Wait()
+	close(ch)
+
+	tg := &targetgroup.Group{Source: cfg.SubscriptionID}
+	for tgt := range ch {
+		if tgt.err != nil {
+			return nil, fmt.Errorf("unable to complete Azure service discovery: %w", tgt.err)
+		}
+		if tgt.labelSet != nil {
+			tg.Targets = append(tg.Targets, tgt.labelSet)
+		}
+	}
+	return tg, nil
+}
+
+// machineLabels returns the labels of the target of vm, or nil if vm has no
+// target.
+func (d *discoverer) machineLabels(ctx context.Context, client azureClient, cfg *prom_azure.SDConfig, vm virtualMachine) (model.LabelSet, error) {
+	r, err := newAzureResourceFromID(vm.ID, d.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := vm.labels(r.ResourceGroupName)
+	labels[azureLabelSubscriptionID] = model.LabelValue(cfg.SubscriptionID)
+	labels[azureLabelTenantID] = model.LabelValue(cfg.TenantID)
+
+	// Get the IP address information via separate call to the network provider.
+	for _, nicID := range vm.NetworkInterfaces {
+		var networkInterface *armnetwork.Interface
+		if v, ok := d.cache.Get(nicID); ok {
+			networkInterface = v
+			d.metrics.cacheHitCount.Add(1)
+		} else {
+			if vm.ScaleSet == "" {
+				networkInterface, err = client.getVMNetworkInterfaceByID(ctx, nicID)
+			} else {
+				networkInterface, err = client.getVMScaleSetVMNetworkInterfaceByID(ctx, nicID, vm.ScaleSet, vm.InstanceID)
+			}
+
+			if err != nil {
+				if errors.Is(err, errorNotFound) {
+					level.Warn(d.logger).Log("msg", "Network interface does not exist", "name", nicID, "err", err)
+					// We cannot continue without a network interface.
+					return nil, nil
+				}
+				return nil, err
+			}
+
+			// Continue processing with the network interface
+			d.addToCache(nicID, networkInterface)
+		}
+
+		if networkInterface.Properties == nil {
+			continue
+		}
+
+		// Unfortunately Azure does not return information on whether a VM is deallocated.
+		// This information is available via another API call however the Go SDK does not
+		// yet support this. On deallocated machines, this value happens to be nil so it
+		// is a cheap and easy way to determine if a machine is allocated or not.
+		if networkInterface.Properties.Primary == nil {
+			level.Debug(d.logger).Log("msg", "Skipping deallocated virtual machine", "machine", vm.Name)
+			return nil, nil
+		}
+
+		if *networkInterface.Properties.Primary {
+			for _, ip := range networkInterface.Properties.IPConfigurations {
+				// IPAddress is a field defined in PublicIPAddressPropertiesFormat,
+				// therefore we need to validate that both are not nil.
+				if ip.Properties != nil && ip.Properties.PublicIPAddress != nil && ip.Properties.PublicIPAddress.Properties != nil && ip.Properties.PublicIPAddress.Properties.IPAddress != nil {
+					labels[azureLabelMachinePublicIP] = model.LabelValue(*ip.Properties.PublicIPAddress.Properties.IPAddress)
+				}
+				if ip.Properties != nil && ip.Properties.PrivateIPAddress != nil {
+					labels[azureLabelMachinePrivateIP] = model.LabelValue(*ip.Properties.PrivateIPAddress)
+					address := net.JoinHostPort(*ip.Properties.PrivateIPAddress, strconv.Itoa(d.port))
+					labels[model.AddressLabel] = model.LabelValue(address)
+					return labels, nil
+				}
+				// If we made it here, we don't have a private IP which should be impossible.
+				// Return an error to ensure an all or nothing situation.
+				return nil, fmt.Errorf("unable to find a private IP for VM %s", vm.Name)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// addToCache will add the network interface information for the specified nicID.
+func (d *discoverer) addToCache(nicID string, netInt *armnetwork.Interface) {
+	random := rand.Int63n(int64((d.refreshInterval * 3).Seconds()))
+	rs := time.Duration(random) * time.Second
+	exptime := d.refreshInterval*10 + rs
+	d.cache.Set(nicID, netInt, cache.WithExpiration(exptime))
+	level.Debug(d.logger).Log("msg", "Adding nic", "nic", nicID, "time", exptime.Seconds())
+}
+
+// azureClient represents multiple Azure Resource Manager providers.
+type azureClient struct {
+	nic    *armnetwork.InterfacesClient
+	vm     *armcompute.VirtualMachinesClient
+	vmss   *armcompute.VirtualMachineScaleSetsClient
+	vmssvm *armcompute.VirtualMachineScaleSetVMsClient
+	logger log.Logger
+}
+
+// createAzureClient is a helper function for creating an Azure compute client to ARM.
+func createAzureClient(cfg prom_azure.SDConfig) (azureClient, error) {
+	cloudConfiguration, err := prom_azure.CloudConfigurationFromName(cfg.Environment)
+	if err != nil {
+		return azureClient{}, err
+	}
+
+	var c azureClient
+
+	telemetry := policy.TelemetryOptions{
+		ApplicationID: userAgent,
+	}
+
+	credential, err := newCredential(cfg, policy.ClientOptions{
+		Cloud:     cloudConfiguration,
+		Telemetry: telemetry,
+	})
+	if err != nil {
+		return azureClient{}, err
+	}
+
+	client, err := config_util.NewClientFromConfig(cfg.HTTPClientConfig, "azure_sd")
+	if err != nil {
+		return azureClient{}, err
+	}
+	options := &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Transport: client,
+			Cloud:     cloudConfiguration,
+			Telemetry: telemetry,
+		},
+	}
+
+	c.vm, err = armcompute.NewVirtualMachinesClient(cfg.SubscriptionID, credential, options)
+	if err != nil {
+		return azureClient{}, err
+	}
+
+	c.nic, err = armnetwork.NewInterfacesClient(cfg.SubscriptionID, credential, options)
+	if err != nil {
+		return azureClient{}, err
+	}
+
+	c.vmss, err = armcompute.NewVirtualMachineScaleSetsClient(cfg.SubscriptionID, credential, options)
+	if err != nil {
+		return azureClient{}, err
+	}
+
+	c.vmssvm, err = armcompute.NewVirtualMachineScaleSetVMsClient(cfg.SubscriptionID, credential, options)
+	if err != nil {
+		return azureClient{}, err
+	}
+
+	return c, nil
+}
+
+func newCredential(cfg prom_azure.SDConfig, policyClientOptions policy.ClientOptions) (azcore.TokenCredential, error) {
+	switch cfg.AuthenticationMethod {
+	case authMethodManagedIdentity:
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: policyClientOptions, ID: azidentity.ClientID(cfg.ClientID)}
+		return azidentity.NewManagedIdentityCredential(options)
+	case authMethodOAuth:
+		options := &azidentity.ClientSecretCredentialOptions{ClientOptions: policyClientOptions}
+		return azidentity.NewClientSecretCredential(cfg.TenantID, cfg.ClientID, string(cfg.ClientSecret), options)
+	default:
+		return nil, fmt.Errorf("unknown authentication method %q", cfg.AuthenticationMethod)
+	}
+}
+
+// virtualMachine represents an Azure virtual machine (which can also be created by a VMSS).
+type virtualMachine struct {
+	ID                string
+	Name              string
+	ComputerName      string
+	Type              string
+	Location          string
+	OsType            string
+	Tags              map[string]*string
+	NetworkInterfaces []string
+	Size              string
+
+	// Fields of the VMs of scale sets.
+	ScaleSet     string
+	InstanceID   string
+	FaultDomain  string
+	ScaleSetTags map[string]*string
+}
+
+// labels returns the labels of vm which don't depend on its network
+// interfaces.
+func (vm virtualMachine) labels(resourceGroup string) model.LabelSet {
+	labels := model.LabelSet{
+		azureLabelMachineID:            model.LabelValue(vm.ID),
+		azureLabelMachineName:          model.LabelValue(vm.Name),
+		azureLabelMachineComputerName:  model.LabelValue(vm.ComputerName),
+		azureLabelMachineOSType:        model.LabelValue(vm.OsType),
+		azureLabelMachineLocation:      model.LabelValue(vm.Location),
+		azureLabelMachineResourceGroup: model.LabelValue(resourceGroup),
+		azureLabelMachineSize:          model.LabelValue(vm.Size),
+	}
+
+	for k, v := range vm.Tags {
+		if v == nil {
+			continue
+		}
+		name := strutil.SanitizeLabelName(k)
+		labels[azureLabelMachineTag+model.LabelName(name)] = model.LabelValue(*v)
+	}
+
+	if vm.ScaleSet != "" {
+		labels[azureLabelMachineScaleSet] = model.LabelValue(vm.ScaleSet)
+		labels[azureLabelMachineScaleSetInstanceID] = model.LabelValue(vm.InstanceID)
+		if vm.FaultDomain != "" {
+			labels[azureLabelMachineScaleSetFaultDomain] = model.LabelValue(vm.FaultDomain)
+		}
+		for k, v := range vm.ScaleSetTags {
+			if v == nil {
+				continue
+			}
+			name := strutil.SanitizeLabelName(k)
+			labels[azureLabelMachineScaleSetTag+model.LabelName(name)] = model.LabelValue(*v)
+		}
+	}
+	return labels
+}
+
+// Create a new azureResource object from an ID string.
+func newAzureResourceFromID(id string, logger log.Logger) (*arm.ResourceID, error) {
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil {
+		err := fmt.Errorf("invalid ID '%s': %w", id, err)
+		level.Error(logger).Log("err", err)
+		return &arm.ResourceID{}, err
+	}
+	return resourceID, nil
+}
+
+func (client *azureClient) getVMs(ctx context.Context, resourceGroup string) ([]virtualMachine, error) {
+	var vms []virtualMachine
+	if len(resourceGroup) == 0 {
+		pager := client.vm.NewListAllPager(nil)
+		for pager.More() {
+			nextResult, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("could not list virtual machines: %w", err)
+			}
+			for _, vm := range nextResult.Value {
+				vms = append(vms, mapFromVM(*vm))
+			}
+		}
+	} else {
+		pager := client.vm.NewListPager(resourceGroup, nil)
+		for pager.More() {
+			nextResult, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("could not list virtual machines: %w", err)
+			}
+			for _, vm := range nextResult.Value {
+				vms = append(vms, mapFromVM(*vm))
+			}
+		}
+	}
+	return vms, nil
+}
+
+func (client *azureClient) getScaleSets(ctx context.Context, resourceGroup string) ([]armcompute.VirtualMachineScaleSet, error) {
+	var scaleSets []armcompute.VirtualMachineScaleSet
+	if len(resourceGroup) == 0 {
+		pager := client.vmss.NewListAllPager(nil)
+		for pager.More() {
+			nextResult, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("could not list virtual machine scale sets: %w", err)
+			}
+			for _, vmss := range nextResult.Value {
+				scaleSets = append(scaleSets, *vmss)
+			}
+		}
+	} else {
+		pager := client.vmss.NewListPager(resourceGroup, nil)
+		for pager.More() {
+			nextResult, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("could not list virtual machine scale sets: %w", err)
+			}
+			for _, vmss := range nextResult.Value {
+				scaleSets = append(scaleSets, *vmss)
+			}
+		}
+	}
+	return scaleSets, nil
+}
+
+func (client *azureClient) getScaleSetVMs(ctx context.Context, scaleSet armcompute.VirtualMachineScaleSet) ([]virtualMachine, error) {
+	var vms []virtualMachine
+	r, err := newAzureResourceFromID(*scaleSet.ID, client.logger)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse scale set ID: %w", err)
+	}
+
+	// The instance view holds the fault domain of the VMs.
+	pager := client.vmssvm.NewListPager(r.ResourceGroupName, *(scaleSet.Name), &armcompute.VirtualMachineScaleSetVMsClientListOptions{
+		Expand: to.Ptr("instanceView"),
+	})
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not list virtual machine scale set vms: %w", err)
+		}
+		for _, vmssvm := range nextResult.Value {
+			vms = append(vms, mapFromVMScaleSetVM(*vmssvm, scaleSet))
+		}
+	}
+
+	return vms, nil
+}
+
+func mapFromVM(vm armcompute.VirtualMachine) virtualMachine {
+	var osType string
+	tags := map[string]*string{}
+	networkInterfaces := []string{}
+	var computerName string
+	var size string
+
+	if vm.Tags != nil {
+		tags = vm.Tags
+	}
+
+	if vm.Properties != nil {
+		if vm.Properties.StorageProfile != nil &&
+			vm.Properties.StorageProfile.OSDisk != nil &&
+			vm.Properties.StorageProfile.OSDisk.OSType != nil {
+			osType = string(*vm.Properties.StorageProfile.OSDisk.OSType)
+		}
+
+		if vm.Properties.NetworkProfile != nil {
+			for _, vmNIC := range vm.Properties.NetworkProfile.NetworkInterfaces {
+				networkInterfaces = append(networkInterfaces, *vmNIC.ID)
+			}
+		}
+		if vm.Properties.OSProfile != nil && vm.Properties.OSProfile.ComputerName != nil {
+			computerName = *(vm.Properties.OSProfile.ComputerName)
+		}
+		if vm.Properties.HardwareProfile != nil {
+			size = string(*vm.Properties.HardwareProfile.VMSize)
+		}
+	}
+
+	return virtualMachine{
+		ID:                *(vm.ID),
+		Name:              *(vm.Name),
+		ComputerName:      computerName,
+		Type:              *(vm.Type),
+		Location:          *(vm.Location),
+		OsType:            osType,
+		Tags:              tags,
+		NetworkInterfaces: networkInterfaces,
+		Size:              size,
+	}
+}
+
+func mapFromVMScaleSetVM(vm armcompute.VirtualMachineScaleSetVM, scaleSet armcompute.VirtualMachineScaleSet) virtualMachine {
+	var osType string
+	tags := map[string]*string{}
+	networkInterfaces := []string{}
+	var computerName string
+	var size string
+	var faultDomain string
+
+	if vm.Tags != nil {
+		tags = vm.Tags
+	}
+
+	if vm.Properties != nil {
+		if vm.Properties.StorageProfile != nil &&
+			vm.Properties.StorageProfile.OSDisk != nil &&
+			vm.Properties.StorageProfile.OSDisk.OSType != nil {
+			osType = string(*vm.Properties.StorageProfile.OSDisk.OSType)
+		}
+
+		if vm.Properties.NetworkProfile != nil {
+			for _, vmNIC := range vm.Properties.NetworkProfile.NetworkInterfaces {
+				networkInterfaces = append(networkInterfaces, *vmNIC.ID)
+			}
+		}
+		if vm.Properties.OSProfile != nil && vm.Properties.OSProfile.ComputerName != nil {
+			computerName = *(vm.Properties.OSProfile.ComputerName)
+		}
+		if vm.Properties.HardwareProfile != nil {
+			size = string(*vm.Properties.HardwareProfile.VMSize)
+		}
+		if vm.Properties.InstanceView != nil && vm.Properties.InstanceView.PlatformFaultDomain != nil {
+			faultDomain = strconv.Itoa(int(*vm.Properties.InstanceView.PlatformFaultDomain))
+		}
+	}
+
+	return virtualMachine{
+		ID:                *(vm.ID),
+		Name:              *(vm.Name),
+		ComputerName:      computerName,
+		Type:              *(vm.Type),
+		Location:          *(vm.Location),
+		OsType:            osType,
+		Tags:              tags,
+		NetworkInterfaces: networkInterfaces,
+		Size:              size,
+		ScaleSet:          *(scaleSet.Name),
+		InstanceID:        *(vm.InstanceID),
+		FaultDomain:       faultDomain,
+		ScaleSetTags:      scaleSet.Tags,
+	}
+}
+
+var errorNotFound = errors.New("network interface does not exist")
+
+// getVMNetworkInterfaceByID gets the network interface.
+// If a 404 is returned from the Azure API, `errorNotFound` is returned.
+func (client *azureClient) getVMNetworkInterfaceByID(ctx context.Context, networkInterfaceID string) (*armnetwork.Interface, error) {
+	r, err := newAzureResourceFromID(networkInterfaceID, client.logger)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse network interface ID: %w", err)
+	}
+
+	resp, err := client.nic.Get(ctx, r.ResourceGroupName, r.Name, &armnetwork.InterfacesClientGetOptions{Expand: to.Ptr("IPConfigurations/PublicIPAddress")})
+	if err != nil {
+		var responseError *azcore.ResponseError
+		if errors.As(err, &responseError) && responseError.StatusCode == http.StatusNotFound {
+			return nil, errorNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve Interface %v with error: %w", networkInterfaceID, err)
+	}
+
+	return &resp.Interface, nil
+}
+
+// getVMScaleSetVMNetworkInterfaceByID gets the network interface.
+// If a 404 is returned from the Azure API, `errorNotFound` is returned.
+func (client *azureClient) getVMScaleSetVMNetworkInterfaceByID(ctx context.Context, networkInterfaceID, scaleSetName, instanceID string) (*armnetwork.Interface, error) {
+	r, err := newAzureResourceFromID(networkInterfaceID, client.logger)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse network interface ID: %w", err)
+	}
+
+	resp, err := client.nic.GetVirtualMachineScaleSetNetworkInterface(ctx, r.ResourceGroupName, scaleSetName, instanceID, r.Name, &armnetwork.InterfacesClientGetVirtualMachineScaleSetNetworkInterfaceOptions{Expand: to.Ptr("IPConfigurations/PublicIPAddress")})
+	if err != nil {
+		var responseError *azcore.ResponseError
+		if errors.As(err, &responseError) && responseError.StatusCode == http.StatusNotFound {
+			return nil, errorNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve Interface %v with error: %w", networkInterfaceID, err)
+	}
+
+	return &resp.Interface, nil
+}
@@ -42,6 +42,7 @@ type Arguments struct {
 	Services     []string          `alloy:"services,attr,optional"`
 	ServiceTags  []string          `alloy:"tags,attr,optional"`
 	NodeMeta     map[string]string `alloy:"node_meta,attr,optional"`
+	MeshMetadata bool              `alloy:"mesh_metadata,attr,optional"`
 
 	RefreshInterval  time.Duration           `alloy:"refresh_interval,attr,optional"`
 	HTTPClientConfig config.HTTPClientConfig `alloy:",squash"`
@@ -73,7 +74,7 @@ func (args *Arguments) Validate() error {
 func (args Arguments) Convert() discovery.DiscovererConfig {
 	httpClient := &args.HTTPClientConfig
 
-	return &prom_discovery.SDConfig{
+	sdConfig := &prom_discovery.SDConfig{
 		RefreshInterval:  model.Duration(args.RefreshInterval),
 		HTTPClientConfig: *httpClient.Convert(),
 		Server:           args.Server,
@@ -90,4 +91,8 @@ func (args Arguments) Convert() discovery.DiscovererConfig {
 		ServiceTags:      args.ServiceTags,
 		NodeMeta:         args.NodeMeta,
 	}
+	if args.MeshMetadata {
+		return &meshDiscoveryConfig{SDConfig: sdConfig}
+	}
+	return sdConfig
 }
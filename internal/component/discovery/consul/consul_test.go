@@ -37,3 +37,18 @@ func TestBadAlloyConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.ErrorContains(t, err, "at most one of basic_auth password & password_file must be configured")
 }
+
+func TestConvertMeshMetadata(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+	namespace     = "team-a"
+	partition     = "default"
+	mesh_metadata = true
+`), &args)
+	require.NoError(t, err)
+
+	cfg, ok := args.Convert().(*meshDiscoveryConfig)
+	require.True(t, ok)
+	require.Equal(t, "team-a", cfg.Namespace)
+	require.Equal(t, "default", cfg.Partition)
+}
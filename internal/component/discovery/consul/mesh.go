@@ -0,0 +1,188 @@
+package consul
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	consul "github.com/hashicorp/consul/api"
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	prom_consul "github.com/prometheus/prometheus/discovery/consul"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	metaLabelPrefix = model.MetaLabelPrefix + "consul_"
+	nodeLabel       = metaLabelPrefix + "node"
+	serviceIDLabel  = metaLabelPrefix + "service_id"
+
+	serviceKindLabel                   = metaLabelPrefix + "service_kind"
+	connectNativeLabel                 = metaLabelPrefix + "connect_native"
+	proxyDestinationServiceLabel       = metaLabelPrefix + "proxy_destination_service"
+	proxyDestinationServiceIDLabel     = metaLabelPrefix + "proxy_destination_service_id"
+	proxyEnvoyPrometheusBindAddrLabel  = metaLabelPrefix + "proxy_envoy_prometheus_bind_addr"
+	envoyPrometheusBindAddrProxyConfig = "envoy_prometheus_bind_addr"
+
+	// meshRequestTimeout bounds the duration of the requests used to look up
+	// the mesh metadata of a service.
+	meshRequestTimeout = 30 * time.Second
+)
+
+// meshDiscoveryConfig wraps the upstream Consul discovery and enriches the
+// discovered targets with the service mesh metadata of their service
+// instances, which isn't exposed upstream.
+type meshDiscoveryConfig struct {
+	*prom_consul.SDConfig
+}
+
+var _ prom_discovery.Config = (*meshDiscoveryConfig)(nil)
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *meshDiscoveryConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	inner, err := c.SDConfig.NewDiscoverer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := config_util.NewClientFromConfig(c.HTTPClientConfig, "consul_sd")
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = meshRequestTimeout
+
+	client, err := consul.NewClient(&consul.Config{
+		Address:    c.Server,
+		PathPrefix: c.PathPrefix,
+		Scheme:     c.Scheme,
+		Datacenter: c.Datacenter,
+		Namespace:  c.Namespace,
+		Partition:  c.Partition,
+		Token:      string(c.Token),
+		HttpClient: httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newMeshDiscovery(opts.Logger, inner, client.Health(), c.AllowStale, c.NodeMeta), nil
+}
+
+// meshDiscovery decorates the target groups of an upstream Consul discoverer
+// with the service mesh metadata of each service instance.
+type meshDiscovery struct {
+	logger     log.Logger
+	inner      prom_discovery.Discoverer
+	health     *consul.Health
+	allowStale bool
+	nodeMeta   map[string]string
+}
+
+func newMeshDiscovery(logger log.Logger, inner prom_discovery.Discoverer, health *consul.Health, allowStale bool, nodeMeta map[string]string) *meshDiscovery {
+	return &meshDiscovery{
+		logger:     logger,
+		inner:      inner,
+		health:     health,
+		allowStale: allowStale,
+		nodeMeta:   nodeMeta,
+	}
+}
+
+// Run implements discovery.Discoverer.
+func (d *meshDiscovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	innerCh := make(chan []*targetgroup.Group)
+	go d.inner.Run(ctx, innerCh)
+
+	for {
+		var tgs []*targetgroup.Group
+		select {
+		case <-ctx.Done():
+			return
+		case tgs = <-innerCh:
+		}
+
+		send := make([]*targetgroup.Group, 0, len(tgs))
+		for _, tg := range tgs {
+			if tg == nil {
+				continue
+			}
+			send = append(send, d.enrich(ctx, tg))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- send:
+		}
+	}
+}
+
+// enrich returns a copy of tg where each target has the mesh labels of its
+// service instance. The source of the target groups of the upstream
+// discoverer is the name of the service.
+func (d *meshDiscovery) enrich(ctx context.Context, tg *targetgroup.Group) *targetgroup.Group {
+	if len(tg.Targets) == 0 {
+		return tg
+	}
+
+	opts := &consul.QueryOptions{
+		AllowStale: d.allowStale,
+		NodeMeta:   d.nodeMeta,
+	}
+	entries, _, err := d.health.Service(tg.Source, "", false, opts.WithContext(ctx))
+	if err != nil {
+		// Forward the target group unmodified rather than dropping the
+		// targets of the service.
+		level.Error(d.logger).Log("msg", "failed to retrieve service mesh metadata", "service", tg.Source, "err", err)
+		return tg
+	}
+
+	services := make(map[string]*consul.AgentService, len(entries))
+	for _, e := range entries {
+		if e.Node == nil || e.Service == nil {
+			continue
+		}
+		services[e.Node.Node+"/"+e.Service.ID] = e.Service
+	}
+
+	res := &targetgroup.Group{
+		Source:  tg.Source,
+		Labels:  tg.Labels,
+		Targets: make([]model.LabelSet, 0, len(tg.Targets)),
+	}
+	for _, target := range tg.Targets {
+		if svc, ok := services[string(target[nodeLabel])+"/"+string(target[serviceIDLabel])]; ok {
+			target = target.Merge(meshLabels(svc))
+		}
+		res.Targets = append(res.Targets, target)
+	}
+	return res
+}
+
+// meshLabels returns the service mesh labels of a service instance.
+func meshLabels(svc *consul.AgentService) model.LabelSet {
+	kind := svc.Kind
+	if kind == consul.ServiceKindTypical {
+		kind = "typical"
+	}
+
+	ls := model.LabelSet{
+		serviceKindLabel:   model.LabelValue(kind),
+		connectNativeLabel: model.LabelValue(strconv.FormatBool(svc.Connect != nil && svc.Connect.Native)),
+	}
+	if svc.Proxy != nil {
+		if svc.Proxy.DestinationServiceName != "" {
+			ls[proxyDestinationServiceLabel] = model.LabelValue(svc.Proxy.DestinationServiceName)
+		}
+		if svc.Proxy.DestinationServiceID != "" {
+			ls[proxyDestinationServiceIDLabel] = model.LabelValue(svc.Proxy.DestinationServiceID)
+		}
+		if addr, ok := svc.Proxy.Config[envoyPrometheusBindAddrProxyConfig].(string); ok && addr != "" {
+			ls[proxyEnvoyPrometheusBindAddrLabel] = model.LabelValue(addr)
+		}
+	}
+	return ls
+}
@@ -0,0 +1,99 @@
+package consul
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	consul "github.com/hashicorp/consul/api"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDiscoverer sends a fixed set of target groups once.
+type staticDiscoverer []*targetgroup.Group
+
+func (s staticDiscoverer) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	select {
+	case <-ctx.Done():
+	case ch <- s:
+	}
+	<-ctx.Done()
+}
+
+func TestMeshDiscovery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/health/service/web-sidecar-proxy", r.URL.Path)
+		_, _ = w.Write([]byte(`[
+			{
+				"Node": {"Node": "node-1"},
+				"Service": {
+					"ID": "web-1-sidecar-proxy",
+					"Kind": "connect-proxy",
+					"Proxy": {
+						"DestinationServiceName": "web",
+						"DestinationServiceID": "web-1",
+						"Config": {"envoy_prometheus_bind_addr": "0.0.0.0:9102"}
+					}
+				}
+			}
+		]`))
+	}))
+	defer srv.Close()
+
+	client, err := consul.NewClient(&consul.Config{Address: srv.URL})
+	require.NoError(t, err)
+
+	inner := staticDiscoverer{
+		{
+			Source: "web-sidecar-proxy",
+			Targets: []model.LabelSet{
+				{model.AddressLabel: "10.0.0.1:21000", nodeLabel: "node-1", serviceIDLabel: "web-1-sidecar-proxy"},
+				{model.AddressLabel: "10.0.0.2:21000", nodeLabel: "node-2", serviceIDLabel: "web-2-sidecar-proxy"},
+			},
+		},
+	}
+	d := newMeshDiscovery(log.NewNopLogger(), inner, client.Health(), true, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []*targetgroup.Group)
+	go d.Run(ctx, ch)
+
+	var groups []*targetgroup.Group
+	select {
+	case groups = <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for target groups")
+	}
+
+	require.Len(t, groups, 1)
+	require.Equal(t, []model.LabelSet{
+		{
+			model.AddressLabel:                "10.0.0.1:21000",
+			nodeLabel:                         "node-1",
+			serviceIDLabel:                    "web-1-sidecar-proxy",
+			serviceKindLabel:                  "connect-proxy",
+			connectNativeLabel:                "false",
+			proxyDestinationServiceLabel:      "web",
+			proxyDestinationServiceIDLabel:    "web-1",
+			proxyEnvoyPrometheusBindAddrLabel: "0.0.0.0:9102",
+		},
+		// Instances which are missing from the health response are forwarded
+		// unmodified.
+		{model.AddressLabel: "10.0.0.2:21000", nodeLabel: "node-2", serviceIDLabel: "web-2-sidecar-proxy"},
+	}, groups[0].Targets)
+}
+
+func TestMeshLabels(t *testing.T) {
+	require.Equal(t, model.LabelSet{
+		serviceKindLabel:   "typical",
+		connectNativeLabel: "true",
+	}, meshLabels(&consul.AgentService{
+		Connect: &consul.AgentServiceConnect{Native: true},
+	}))
+}
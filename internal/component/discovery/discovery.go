@@ -197,8 +197,18 @@ func (c *Component) runDiscovery(ctx context.Context, d DiscovererWithMetrics) {
 
 	// function to convert and send targets in format scraper expects
 	send := func() {
+		// Iterate over the groups in a stable order, so that unchanged groups
+		// result in unchanged exports, and don't trigger the evaluation of
+		// the downstream components.
+		sources := make([]string, 0, len(cache))
+		for source := range cache {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+
 		allTargets := []Target{}
-		for _, group := range cache {
+		for _, source := range sources {
+			group := cache[source]
 			for _, target := range group.Targets {
 				labels := map[string]string{}
 				// first add the group labels, and then the
@@ -0,0 +1,234 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	dnsNameLabel              = model.MetaLabelPrefix + "dns_name"
+	dnsSrvRecordPrefix        = model.MetaLabelPrefix + "dns_srv_record_"
+	dnsSrvRecordTargetLabel   = dnsSrvRecordPrefix + "target"
+	dnsSrvRecordPortLabel     = dnsSrvRecordPrefix + "port"
+	dnsSrvRecordPriorityLabel = dnsSrvRecordPrefix + "priority"
+	dnsSrvRecordWeightLabel   = dnsSrvRecordPrefix + "weight"
+	dnsMxRecordPrefix         = model.MetaLabelPrefix + "dns_mx_record_"
+	dnsMxRecordTargetLabel    = dnsMxRecordPrefix + "target"
+	dnsNsRecordPrefix         = model.MetaLabelPrefix + "dns_ns_record_"
+	dnsNsRecordTargetLabel    = dnsNsRecordPrefix + "target"
+)
+
+// discovererConfig creates the discoverers of the component. They work like
+// the Prometheus DNS discoverer, with the priority and weight of SRV records
+// as labels, and the servers of the resolver block.
+type discovererConfig struct {
+	args Arguments
+}
+
+var _ prom_discovery.Config = (*discovererConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (*discovererConfig) Name() string {
+	return "dns"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *discovererConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := opts.Metrics.(*discovererMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	lookup, err := newLookupFunc(c.args.Resolver, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	qtype := dns.TypeSRV
+	switch strings.ToUpper(c.args.Type) {
+	case "A":
+		qtype = dns.TypeA
+	case "AAAA":
+		qtype = dns.TypeAAAA
+	case "MX":
+		qtype = dns.TypeMX
+	case "NS":
+		qtype = dns.TypeNS
+	}
+
+	d := &discoverer{
+		names:   c.args.Names,
+		qtype:   qtype,
+		port:    c.args.Port,
+		logger:  logger,
+		metrics: m,
+		lookup:  lookup,
+	}
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              logger,
+		Mech:                "dns",
+		Interval:            c.args.RefreshInterval,
+		RefreshF:            d.refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*discovererConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	m := &discovererMetrics{
+		refreshMetrics: rmi,
+		lookupsCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_sd_dns_lookups_total",
+			Help: "The number of DNS-SD lookups.",
+		}),
+		lookupFailuresCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_sd_dns_lookup_failures_total",
+			Help: "The number of DNS-SD lookup failures.",
+		}),
+	}
+	m.metricRegisterer = prom_discovery.NewMetricRegisterer(reg, []prometheus.Collector{
+		m.lookupsCount,
+		m.lookupFailuresCount,
+	})
+	return m
+}
+
+var _ prom_discovery.DiscovererMetrics = (*discovererMetrics)(nil)
+
+type discovererMetrics struct {
+	refreshMetrics      prom_discovery.RefreshMetricsInstantiator
+	lookupsCount        prometheus.Counter
+	lookupFailuresCount prometheus.Counter
+	metricRegisterer    prom_discovery.MetricRegisterer
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Register() error {
+	return m.metricRegisterer.RegisterMetrics()
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Unregister() {
+	m.metricRegisterer.UnregisterMetrics()
+}
+
+// discoverer looks up the records of the names on every refresh.
+type discoverer struct {
+	names   []string
+	port    int
+	qtype   uint16
+	logger  log.Logger
+	metrics *discovererMetrics
+	lookup  lookupFunc
+}
+
+func (d *discoverer) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	var (
+		wg  sync.WaitGroup
+		ch  = make(chan *targetgroup.Group)
+		tgs = make([]*targetgroup.Group, 0, len(d.names))
+	)
+
+	wg.Add(len(d.names))
+	for _, name := range d.names {
+		go func(n string) {
+			if err := d.refreshOne(ctx, n, ch); err != nil && !errors.Is(err, context.Canceled) {
+				level.Error(d.logger).Log("msg", "error refreshing DNS targets", "err", err)
+			}
+			wg.Done()
+		}(name)
+	}
+
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	for tg := range ch {
+		tgs = append(tgs, tg)
+	}
+	return tgs, nil
+}
+
+func (d *discoverer) refreshOne(ctx context.Context, name string, ch chan<- *targetgroup.Group) error {
+	response, err := d.lookup(ctx, name, d.qtype)
+	d.metrics.lookupsCount.Inc()
+	if err != nil {
+		d.metrics.lookupFailuresCount.Inc()
+		return err
+	}
+
+	tg := &targetgroup.Group{}
+	hostPort := func(a string, p int) model.LabelValue {
+		return model.LabelValue(net.JoinHostPort(a, fmt.Sprintf("%d", p)))
+	}
+
+	for _, record := range response.Answer {
+		var target, srvTarget, srvPort, srvPriority, srvWeight, mxTarget, nsTarget model.LabelValue
+
+		switch addr := record.(type) {
+		case *dns.SRV:
+			srvTarget = model.LabelValue(addr.Target)
+			srvPort = model.LabelValue(fmt.Sprintf("%d", addr.Port))
+			srvPriority = model.LabelValue(fmt.Sprintf("%d", addr.Priority))
+			srvWeight = model.LabelValue(fmt.Sprintf("%d", addr.Weight))
+
+			// Remove the final dot from rooted DNS names to make them look more usual.
+			target = hostPort(strings.TrimRight(addr.Target, "."), int(addr.Port))
+		case *dns.MX:
+			mxTarget = model.LabelValue(addr.Mx)
+			target = hostPort(strings.TrimRight(addr.Mx, "."), d.port)
+		case *dns.NS:
+			nsTarget = model.LabelValue(addr.Ns)
+			target = hostPort(strings.TrimRight(addr.Ns, "."), d.port)
+		case *dns.A:
+			target = hostPort(addr.A.String(), d.port)
+		case *dns.AAAA:
+			target = hostPort(addr.AAAA.String(), d.port)
+		case *dns.CNAME:
+			// CNAME responses can occur with "Type: A" requests.
+			continue
+		default:
+			level.Warn(d.logger).Log("msg", "invalid record", "record", record)
+			continue
+		}
+		tg.Targets = append(tg.Targets, model.LabelSet{
+			model.AddressLabel:        target,
+			dnsNameLabel:              model.LabelValue(name),
+			dnsSrvRecordTargetLabel:   srvTarget,
+			dnsSrvRecordPortLabel:     srvPort,
+			dnsSrvRecordPriorityLabel: srvPriority,
+			dnsSrvRecordWeightLabel:   srvWeight,
+			dnsMxRecordTargetLabel:    mxTarget,
+			dnsNsRecordTargetLabel:    nsTarget,
+		})
+	}
+
+	tg.Source = name
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case ch <- tg:
+	}
+
+	return nil
+}
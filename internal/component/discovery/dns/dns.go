@@ -22,7 +22,9 @@ func init() {
 		Exports:   discovery.Exports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			return discovery.NewFromConvertibleConfig(opts, args.(Arguments))
+			return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+				return &discovererConfig{args: args.(Arguments)}, nil
+			})
 		},
 	})
 }
@@ -33,6 +35,7 @@ type Arguments struct {
 	RefreshInterval time.Duration `alloy:"refresh_interval,attr,optional"`
 	Type            string        `alloy:"type,attr,optional"`
 	Port            int           `alloy:"port,attr,optional"`
+	Resolver        *Resolver     `alloy:"resolver,block,optional"`
 }
 
 var DefaultArguments = Arguments{
@@ -59,6 +62,8 @@ func (args *Arguments) Validate() error {
 	return nil
 }
 
+// Convert converts the arguments to the configuration of the Prometheus DNS
+// discoverer, which doesn't support the resolver block.
 func (args Arguments) Convert() discovery.DiscovererConfig {
 	return &dns.SDConfig{
 		Names:           args.Names,
@@ -54,6 +54,20 @@ func TestBadAlloyConfig(t *testing.T) {
 			Config: `names = ["example"]
 			type = "AAAA"`,
 		},
+		{
+			Desc: "Resolver without nameservers",
+			Config: `names = ["example"]
+			resolver {
+				nameservers = []
+			}`,
+		},
+		{
+			Desc: "Resolver with unsupported scheme",
+			Config: `names = ["example"]
+			resolver {
+				nameservers = ["quic://10.0.0.53"]
+			}`,
+		},
 	}
 	for _, tst := range tests {
 		cfg := tst.Config
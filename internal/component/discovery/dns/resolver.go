@@ -0,0 +1,337 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/miekg/dns"
+	config_util "github.com/prometheus/common/config"
+
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const resolvConf = "/etc/resolv.conf"
+
+// Resolver configures the DNS servers which resolve the names, instead of the
+// servers of /etc/resolv.conf.
+type Resolver struct {
+	Nameservers []string         `alloy:"nameservers,attr"`
+	Timeout     time.Duration    `alloy:"timeout,attr,optional"`
+	TLSConfig   config.TLSConfig `alloy:"tls_config,block,optional"`
+}
+
+// DefaultResolver holds the default values of the resolver block.
+var DefaultResolver = Resolver{
+	Timeout: 5 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (r *Resolver) SetToDefault() {
+	*r = DefaultResolver
+}
+
+// Validate implements syntax.Validator.
+func (r *Resolver) Validate() error {
+	if len(r.Nameservers) == 0 {
+		return errors.New("at least one nameserver is required in the resolver block")
+	}
+	for _, ns := range r.Nameservers {
+		if _, err := parseNameserver(ns); err != nil {
+			return err
+		}
+	}
+	if r.Timeout <= 0 {
+		return errors.New("the resolver timeout must be greater than 0")
+	}
+	return r.TLSConfig.Validate()
+}
+
+// nameserver is a DNS server, along with the protocol to query it with.
+type nameserver struct {
+	// One of udp, tcp, tls, or https.
+	protocol string
+	// The host and port of the server, or the URL of DNS-over-HTTPS servers.
+	address string
+}
+
+// parseNameserver parses the address of a DNS server. Addresses without a
+// scheme are queried over UDP.
+func parseNameserver(s string) (nameserver, error) {
+	scheme, host, found := strings.Cut(s, "://")
+	if !found {
+		scheme, host = "udp", s
+	}
+
+	switch scheme {
+	case "udp", "tcp", "tls":
+		if host == "" || strings.Contains(host, "/") {
+			return nameserver{}, fmt.Errorf("invalid nameserver %q", s)
+		}
+		port := "53"
+		if scheme == "tls" {
+			port = "853"
+		}
+		return nameserver{protocol: scheme, address: withDefaultPort(host, port)}, nil
+	case "https":
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return nameserver{}, fmt.Errorf("invalid nameserver %q", s)
+		}
+		if u.Path == "" {
+			u.Path = "/dns-query"
+		}
+		return nameserver{protocol: scheme, address: u.String()}, nil
+	default:
+		return nameserver{}, fmt.Errorf("unsupported scheme %q for nameserver %q, must be one of udp, tcp, tls, or https", scheme, s)
+	}
+}
+
+func withDefaultPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
+
+// lookupFunc looks up the records of type qtype of name.
+type lookupFunc func(ctx context.Context, name string, qtype uint16) (*dns.Msg, error)
+
+// newLookupFunc returns the lookup function of the resolver block, which
+// uses the servers of /etc/resolv.conf when r is nil.
+func newLookupFunc(r *Resolver, logger log.Logger) (lookupFunc, error) {
+	if r == nil {
+		return func(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+			return lookupWithSearchPath(ctx, name, qtype, logger)
+		}, nil
+	}
+
+	var tlsConfig *tls.Config
+	if r.TLSConfig != (config.TLSConfig{}) {
+		var err error
+		tlsConfig, err = config_util.NewTLSConfig(r.TLSConfig.Convert())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exchangers := make([]exchanger, 0, len(r.Nameservers))
+	for _, s := range r.Nameservers {
+		ns, err := parseNameserver(s)
+		if err != nil {
+			return nil, err
+		}
+		exchangers = append(exchangers, newExchanger(ns, r.Timeout, tlsConfig))
+	}
+
+	return func(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+		return lookupFromAnyExchanger(ctx, name, qtype, exchangers, logger)
+	}, nil
+}
+
+// exchanger sends a DNS query to a server and returns its response.
+type exchanger struct {
+	server   string
+	exchange func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error)
+}
+
+func newExchanger(ns nameserver, timeout time.Duration, tlsConfig *tls.Config) exchanger {
+	switch ns.protocol {
+	case "https":
+		client := &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		}
+		return exchanger{
+			server: ns.address,
+			exchange: func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+				return exchangeHTTPS(ctx, client, ns.address, msg)
+			},
+		}
+	default:
+		network := ns.protocol
+		if network == "tls" {
+			network = "tcp-tls"
+		}
+		return exchanger{
+			server: ns.address,
+			exchange: func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+				client := &dns.Client{Net: network, Timeout: timeout, TLSConfig: tlsConfig}
+				return exchangeWithFallback(ctx, client, ns.address, msg)
+			},
+		}
+	}
+}
+
+// exchangeHTTPS sends msg to a DNS-over-HTTPS server, as described in RFC 8484.
+func exchangeHTTPS(ctx context.Context, client *http.Client, url string, msg *dns.Msg) (*dns.Msg, error) {
+	// RFC 8484 recommends using 0 as the ID of the queries, so that the
+	// responses can be cached.
+	msg.Id = 0
+	b, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned HTTP status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+	response := &dns.Msg{}
+	if err := response.Unpack(body); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// lookupFromAnyExchanger queries the servers of the resolver block in order,
+// and returns the first viable answer, like lookupFromAnyServer. The name is
+// resolved as a fully qualified name.
+func lookupFromAnyExchanger(ctx context.Context, name string, qtype uint16, exchangers []exchanger, logger log.Logger) (*dns.Msg, error) {
+	for _, e := range exchangers {
+		msg := &dns.Msg{}
+		msg.SetQuestion(dns.Fqdn(name), qtype)
+		msg.SetEdns0(dns.DefaultMsgSize, false)
+
+		response, err := e.exchange(ctx, msg)
+		if err != nil {
+			level.Warn(logger).Log("msg", "DNS resolution failed", "server", e.server, "name", name, "err", err)
+			continue
+		}
+		if response.Rcode == dns.RcodeSuccess || response.Rcode == dns.RcodeNameError {
+			return response, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not resolve %s: no servers returned a viable answer", name)
+}
+
+// lookupWithSearchPath tries to get an answer for various permutations of
+// the given name, appending the system-configured search path as necessary.
+//
+// There are three possible outcomes:
+//
+//  1. One of the permutations of the given name is recognized as
+//     "valid" by the DNS, in which case we consider ourselves "done"
+//     and that answer is returned.  Note that, due to the way the DNS
+//     handles "name has resource records, but none of the specified type",
+//     the answer received may have an empty set of results.
+//
+//  2. All of the permutations of the given name are responded to by one of
+//     the servers in the "nameservers" list with the answer "that name does
+//     not exist" (NXDOMAIN).  In that case, it can be considered
+//     pseudo-authoritative that there are no records for that name.
+//
+//  3. One or more of the names was responded to by all servers with some
+//     sort of error indication.  In that case, we can't know if, in fact,
+//     there are records for the name or not, so whatever state the
+//     configuration is in, we should keep it that way until we know for
+//     sure (by, presumably, all the names getting answers in the future).
+//
+// Outcomes 1 and 2 are indicated by a valid response message (possibly an
+// empty one) and no error.  Outcome 3 is indicated by an error return.
+func lookupWithSearchPath(ctx context.Context, name string, qtype uint16, logger log.Logger) (*dns.Msg, error) {
+	conf, err := dns.ClientConfigFromFile(resolvConf)
+	if err != nil {
+		return nil, fmt.Errorf("could not load resolv.conf: %w", err)
+	}
+
+	allResponsesValid := true
+
+	for _, lname := range conf.NameList(name) {
+		response, err := lookupFromAnyServer(ctx, lname, qtype, conf, logger)
+
+		switch {
+		case err != nil:
+			// A later name may give a valid answer, but we can no longer say
+			// that the name doesn't exist.
+			allResponsesValid = false
+		case response.Rcode == dns.RcodeSuccess:
+			return response, nil
+		}
+	}
+
+	if allResponsesValid {
+		return &dns.Msg{}, nil
+	}
+	return nil, fmt.Errorf("could not resolve %q: all servers responded with errors to at least one search domain", name)
+}
+
+// lookupFromAnyServer uses all configured servers to try and resolve a
+// specific name. A viable answer is either a successful answer, possibly
+// without any records, or NXDOMAIN.
+func lookupFromAnyServer(ctx context.Context, name string, qtype uint16, conf *dns.ClientConfig, logger log.Logger) (*dns.Msg, error) {
+	for _, server := range conf.Servers {
+		servAddr := net.JoinHostPort(server, conf.Port)
+
+		msg := &dns.Msg{}
+		msg.SetQuestion(dns.Fqdn(name), qtype)
+		msg.SetEdns0(dns.DefaultMsgSize, false)
+
+		response, err := exchangeWithFallback(ctx, &dns.Client{}, servAddr, msg)
+		if err != nil {
+			level.Warn(logger).Log("msg", "DNS resolution failed", "server", server, "name", name, "err", err)
+			continue
+		}
+
+		if response.Rcode == dns.RcodeSuccess || response.Rcode == dns.RcodeNameError {
+			return response, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not resolve %s: no servers returned a viable answer", name)
+}
+
+// exchangeWithFallback sends msg to servAddr, and retries over TCP when a
+// response over UDP is truncated.
+func exchangeWithFallback(ctx context.Context, client *dns.Client, servAddr string, msg *dns.Msg) (*dns.Msg, error) {
+	response, _, err := client.ExchangeContext(ctx, msg, servAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Truncated {
+		if client.Net != "" && client.Net != "udp" {
+			return nil, errors.New("got truncated message on TCP (64kiB limit exceeded?)")
+		}
+
+		// The EDNS0 option of the query isn't needed over TCP.
+		tcpMsg := msg.Copy()
+		tcpMsg.Extra = nil
+		tcpClient := *client
+		tcpClient.Net = "tcp"
+		return exchangeWithFallback(ctx, &tcpClient, servAddr, tcpMsg)
+	}
+
+	return response, nil
+}
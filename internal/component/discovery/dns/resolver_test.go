@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/common/config"
+)
+
+func TestParseNameserver(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect nameserver
+	}{
+		{"10.0.0.53", nameserver{protocol: "udp", address: "10.0.0.53:53"}},
+		{"10.0.0.53:5353", nameserver{protocol: "udp", address: "10.0.0.53:5353"}},
+		{"tcp://[::1]", nameserver{protocol: "tcp", address: "[::1]:53"}},
+		{"tls://1.1.1.1", nameserver{protocol: "tls", address: "1.1.1.1:853"}},
+		{"https://dns.example.com", nameserver{protocol: "https", address: "https://dns.example.com/dns-query"}},
+		{"https://dns.example.com/resolve", nameserver{protocol: "https", address: "https://dns.example.com/resolve"}},
+	}
+	for _, tc := range tests {
+		ns, err := parseNameserver(tc.in)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.expect, ns, tc.in)
+	}
+
+	for _, in := range []string{"quic://10.0.0.53", "udp://", "tls://10.0.0.53/dns-query", "https://"} {
+		_, err := parseNameserver(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	answer := func(r *dns.Msg) *dns.Msg {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.Answer = append(m.Answer, &dns.SRV{
+			Hdr:      dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: 60},
+			Priority: 10,
+			Weight:   20,
+			Port:     9100,
+			Target:   "node1.example.com.",
+		})
+		return m
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	udpSrv := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		_ = w.WriteMsg(answer(r))
+	})}
+	go func() { _ = udpSrv.ActivateAndServe() }()
+	defer udpSrv.Shutdown()
+
+	dohSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		query := &dns.Msg{}
+		require.NoError(t, query.Unpack(b))
+		out, err := answer(query).Pack()
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(out)
+	}))
+	defer dohSrv.Close()
+
+	for _, ns := range []string{pc.LocalAddr().String(), dohSrv.URL} {
+		t.Run(ns, func(t *testing.T) {
+			lookup, err := newLookupFunc(&Resolver{
+				Nameservers: []string{ns},
+				Timeout:     time.Second,
+				TLSConfig:   config.TLSConfig{InsecureSkipVerify: true},
+			}, log.NewNopLogger())
+			require.NoError(t, err)
+
+			d := &discoverer{
+				names:   []string{"_node._tcp.example.com"},
+				qtype:   dns.TypeSRV,
+				logger:  log.NewNopLogger(),
+				metrics: (&discovererConfig{}).NewDiscovererMetrics(prometheus.NewRegistry(), nil).(*discovererMetrics),
+				lookup:  lookup,
+			}
+			tgs, err := d.refresh(context.Background())
+			require.NoError(t, err)
+			require.Len(t, tgs, 1)
+			require.Equal(t, []model.LabelSet{{
+				"__address__":                    "node1.example.com:9100",
+				"__meta_dns_name":                "_node._tcp.example.com",
+				"__meta_dns_srv_record_target":   "node1.example.com.",
+				"__meta_dns_srv_record_port":     "9100",
+				"__meta_dns_srv_record_priority": "10",
+				"__meta_dns_srv_record_weight":   "20",
+				"__meta_dns_mx_record_target":    "",
+				"__meta_dns_ns_record_target":    "",
+			}}, tgs[0].Targets)
+		})
+	}
+}
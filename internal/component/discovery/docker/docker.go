@@ -31,6 +31,7 @@ func init() {
 // Arguments configures the discovery.docker component.
 type Arguments struct {
 	Host               string                  `alloy:"host,attr"`
+	Role               string                  `alloy:"role,attr,optional"`
 	Port               int                     `alloy:"port,attr,optional"`
 	HostNetworkingHost string                  `alloy:"host_networking_host,attr,optional"`
 	RefreshInterval    time.Duration           `alloy:"refresh_interval,attr,optional"`
@@ -53,8 +54,18 @@ func (f Filter) Convert() moby.Filter {
 	}
 }
 
+// Roles supported by discovery.docker. The services, tasks and nodes roles
+// discover Docker Swarm resources.
+const (
+	RoleContainers = "containers"
+	RoleServices   = "services"
+	RoleTasks      = "tasks"
+	RoleNodes      = "nodes"
+)
+
 // DefaultArguments holds default values for Arguments.
 var DefaultArguments = Arguments{
+	Role:               RoleContainers,
 	Port:               80,
 	HostNetworkingHost: "localhost",
 	RefreshInterval:    time.Minute,
@@ -78,6 +89,12 @@ func (args *Arguments) Validate() error {
 		return fmt.Errorf("refresh_interval must be greater than 0")
 	}
 
+	switch args.Role {
+	case RoleContainers, RoleServices, RoleTasks, RoleNodes:
+	default:
+		return fmt.Errorf("invalid role %q, expected %s, %s, %s, or %s", args.Role, RoleContainers, RoleServices, RoleTasks, RoleNodes)
+	}
+
 	return args.HTTPClientConfig.Validate()
 }
 
@@ -87,6 +104,19 @@ func (args Arguments) Convert() discovery.DiscovererConfig {
 		filters[i] = filter.Convert()
 	}
 
+	if args.Role != RoleContainers {
+		return &moby.DockerSwarmSDConfig{
+			HTTPClientConfig: *args.HTTPClientConfig.Convert(),
+
+			Host:    args.Host,
+			Role:    args.Role,
+			Port:    args.Port,
+			Filters: filters,
+
+			RefreshInterval: model.Duration(args.RefreshInterval),
+		}
+	}
+
 	return &moby.DockerSDConfig{
 		HTTPClientConfig: *args.HTTPClientConfig.Convert(),
 
@@ -3,6 +3,8 @@ package docker
 import (
 	"testing"
 
+	"github.com/prometheus/prometheus/discovery/moby"
+
 	"github.com/grafana/alloy/syntax"
 	"github.com/stretchr/testify/require"
 )
@@ -29,3 +31,34 @@ func TestBadAlloyConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.ErrorContains(t, err, "at most one of basic_auth, authorization, oauth2, bearer_token & bearer_token_file must be configured")
 }
+
+func TestSwarmRoles(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`host = "unix:///var/run/docker.sock"`), &args)
+	require.NoError(t, err)
+	require.Equal(t, RoleContainers, args.Role)
+	require.IsType(t, &moby.DockerSDConfig{}, args.Convert())
+
+	err = syntax.Unmarshal([]byte(`
+	host = "unix:///var/run/docker.sock"
+	role = "tasks"
+	port = 9100
+	filter {
+		name   = "desired-state"
+		values = ["running"]
+	}
+`), &args)
+	require.NoError(t, err)
+
+	sd, ok := args.Convert().(*moby.DockerSwarmSDConfig)
+	require.True(t, ok)
+	require.Equal(t, "tasks", sd.Role)
+	require.Equal(t, 9100, sd.Port)
+	require.Equal(t, []moby.Filter{{Name: "desired-state", Values: []string{"running"}}}, sd.Filters)
+
+	err = syntax.Unmarshal([]byte(`
+	host = "unix:///var/run/docker.sock"
+	role = "secrets"
+`), &args)
+	require.ErrorContains(t, err, `invalid role "secrets"`)
+}
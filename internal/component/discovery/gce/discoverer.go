@@ -0,0 +1,290 @@
+package gce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	gceLabel               = model.MetaLabelPrefix + "gce_"
+	gceLabelProject        = gceLabel + "project"
+	gceLabelZone           = gceLabel + "zone"
+	gceLabelNetwork        = gceLabel + "network"
+	gceLabelSubnetwork     = gceLabel + "subnetwork"
+	gceLabelPublicIP       = gceLabel + "public_ip"
+	gceLabelPrivateIP      = gceLabel + "private_ip"
+	gceLabelInstanceID     = gceLabel + "instance_id"
+	gceLabelInstanceName   = gceLabel + "instance_name"
+	gceLabelInstanceStatus = gceLabel + "instance_status"
+	gceLabelTags           = gceLabel + "tags"
+	gceLabelNetworkTag     = gceLabel + "network_tag_"
+	gceLabelMetadata       = gceLabel + "metadata_"
+	gceLabelLabel          = gceLabel + "label_"
+	gceLabelMachineType    = gceLabel + "machine_type"
+)
+
+// discovererConfig creates the discoverers of the component. They work like
+// the Prometheus GCE discoverer, but discover the instances of every project
+// of the arguments with a single client, and list the instances of all zones
+// with a single request per page when no zone is given.
+type discovererConfig struct {
+	args Arguments
+}
+
+var _ prom_discovery.Config = (*discovererConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (*discovererConfig) Name() string {
+	return "gce"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *discovererConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := opts.Metrics.(*discovererMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	client, err := google.DefaultClient(context.Background(), compute.ComputeReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up communication with GCE service: %w", err)
+	}
+	svc, err := compute.NewService(context.Background(), option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("error setting up communication with GCE service: %w", err)
+	}
+
+	d := &discoverer{
+		projects:     c.args.projects(),
+		zone:         c.args.Zone,
+		filter:       c.args.filter(),
+		port:         c.args.Port,
+		tagSeparator: c.args.TagSeparator,
+		logger:       logger,
+		metrics:      m,
+		isvc:         compute.NewInstancesService(svc),
+	}
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              logger,
+		Mech:                "gce",
+		Interval:            c.args.RefreshInterval,
+		RefreshF:            d.refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*discovererConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	m := &discovererMetrics{
+		refreshMetrics: rmi,
+		failuresCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_sd_gce_failures_total",
+			Help: "Number of GCE service discovery refresh failures, per project.",
+		}),
+	}
+	m.metricRegisterer = prom_discovery.NewMetricRegisterer(reg, []prometheus.Collector{
+		m.failuresCount,
+	})
+	return m
+}
+
+var _ prom_discovery.DiscovererMetrics = (*discovererMetrics)(nil)
+
+type discovererMetrics struct {
+	refreshMetrics   prom_discovery.RefreshMetricsInstantiator
+	failuresCount    prometheus.Counter
+	metricRegisterer prom_discovery.MetricRegisterer
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Register() error {
+	return m.metricRegisterer.RegisterMetrics()
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Unregister() {
+	m.metricRegisterer.UnregisterMetrics()
+}
+
+// discoverer discovers the instances of the projects on every refresh.
+type discoverer struct {
+	projects     []string
+	zone         string
+	filter       string
+	port         int
+	tagSeparator string
+	logger       log.Logger
+	metrics      *discovererMetrics
+	isvc         *compute.InstancesService
+}
+
+// refresh returns a target group per project. The projects are refreshed
+// independently: the groups of the projects which fail to refresh are left
+// out, so that their previous targets are kept.
+func (d *discoverer) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	var (
+		wg   sync.WaitGroup
+		mut  sync.Mutex
+		tgs  = make([]*targetgroup.Group, 0, len(d.projects))
+		errs []error
+	)
+
+	wg.Add(len(d.projects))
+	for _, project := range d.projects {
+		go func(project string) {
+			defer wg.Done()
+			tg, err := d.refreshProject(ctx, project)
+
+			mut.Lock()
+			defer mut.Unlock()
+			if err != nil {
+				d.metrics.failuresCount.Inc()
+				level.Error(d.logger).Log("msg", "unable to refresh GCE project", "project", project, "err", err)
+				errs = append(errs, fmt.Errorf("project %q: %w", project, err))
+				return
+			}
+			tgs = append(tgs, tg)
+		}(project)
+	}
+	wg.Wait()
+
+	if len(tgs) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return tgs, nil
+}
+
+// refreshProject returns the targets of the instances of a project.
+func (d *discoverer) refreshProject(ctx context.Context, project string) (*targetgroup.Group, error) {
+	tg := &targetgroup.Group{}
+	appendInstances := func(instances []*compute.Instance) {
+		for _, inst := range instances {
+			if labels := d.instanceLabels(project, inst); labels != nil {
+				tg.Targets = append(tg.Targets, labels)
+			}
+		}
+	}
+
+	var err error
+	if d.zone != "" {
+		tg.Source = fmt.Sprintf("GCE_%s_%s", project, d.zone)
+
+		ilc := d.isvc.List(project, d.zone)
+		if len(d.filter) > 0 {
+			ilc = ilc.Filter(d.filter)
+		}
+		err = ilc.Pages(ctx, func(l *compute.InstanceList) error {
+			appendInstances(l.Items)
+			return nil
+		})
+	} else {
+		tg.Source = fmt.Sprintf("GCE_%s", project)
+
+		// The aggregated list returns the instances of every zone, which saves
+		// a request per zone.
+		ialc := d.isvc.AggregatedList(project)
+		if len(d.filter) > 0 {
+			ialc = ialc.Filter(d.filter)
+		}
+		err = ialc.Pages(ctx, func(l *compute.InstanceAggregatedList) error {
+			for _, scoped := range l.Items {
+				appendInstances(scoped.Instances)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving refresh targets from gce: %w", err)
+	}
+	return tg, nil
+}
+
+// instanceLabels returns the labels of the target of inst, or nil if inst
+// has no network interface.
+func (d *discoverer) instanceLabels(project string, inst *compute.Instance) model.LabelSet {
+	if len(inst.NetworkInterfaces) == 0 {
+		return nil
+	}
+	labels := model.LabelSet{
+		gceLabelProject:        model.LabelValue(project),
+		gceLabelZone:           model.LabelValue(inst.Zone),
+		gceLabelInstanceID:     model.LabelValue(strconv.FormatUint(inst.Id, 10)),
+		gceLabelInstanceName:   model.LabelValue(inst.Name),
+		gceLabelInstanceStatus: model.LabelValue(inst.Status),
+		gceLabelMachineType:    model.LabelValue(inst.MachineType),
+	}
+	priIface := inst.NetworkInterfaces[0]
+	labels[gceLabelNetwork] = model.LabelValue(priIface.Network)
+	labels[gceLabelSubnetwork] = model.LabelValue(priIface.Subnetwork)
+	labels[gceLabelPrivateIP] = model.LabelValue(priIface.NetworkIP)
+	addr := fmt.Sprintf("%s:%d", priIface.NetworkIP, d.port)
+	labels[model.AddressLabel] = model.LabelValue(addr)
+
+	// Append named interface metadata for all interfaces
+	for _, iface := range inst.NetworkInterfaces {
+		gceLabelNetAddress := model.LabelName(fmt.Sprintf("%sinterface_ipv4_%s", gceLabel, strutil.SanitizeLabelName(iface.Name)))
+		labels[gceLabelNetAddress] = model.LabelValue(iface.NetworkIP)
+	}
+
+	// Tags in GCE are usually only used for networking rules.
+	if inst.Tags != nil && len(inst.Tags.Items) > 0 {
+		// We surround the separated list with the separator as well. This way regular expressions
+		// in relabeling rules don't have to consider tag positions.
+		tags := d.tagSeparator + strings.Join(inst.Tags.Items, d.tagSeparator) + d.tagSeparator
+		labels[gceLabelTags] = model.LabelValue(tags)
+
+		// Each tag also has its own label, so that tags can be matched
+		// without regular expressions.
+		for _, tag := range inst.Tags.Items {
+			labels[gceLabelNetworkTag+model.LabelName(strutil.SanitizeLabelName(tag))] = "true"
+		}
+	}
+
+	// GCE metadata are key-value pairs for user supplied attributes.
+	if inst.Metadata != nil {
+		for _, i := range inst.Metadata.Items {
+			// Protect against occasional nil pointers.
+			if i.Value == nil {
+				continue
+			}
+			name := strutil.SanitizeLabelName(i.Key)
+			labels[gceLabelMetadata+model.LabelName(name)] = model.LabelValue(*i.Value)
+		}
+	}
+
+	// GCE labels are key-value pairs that group associated resources
+	for key, value := range inst.Labels {
+		name := strutil.SanitizeLabelName(key)
+		labels[gceLabelLabel+model.LabelName(name)] = model.LabelValue(value)
+	}
+
+	if len(priIface.AccessConfigs) > 0 {
+		ac := priIface.AccessConfigs[0]
+		if ac.Type == "ONE_TO_ONE_NAT" {
+			labels[gceLabelPublicIP] = model.LabelValue(ac.NatIP)
+		}
+	}
+	return labels
+}
@@ -2,6 +2,9 @@
 package gce
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -20,16 +23,20 @@ func init() {
 		Exports:   discovery.Exports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			return discovery.NewFromConvertibleConfig(opts, args.(Arguments))
+			return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+				return &discovererConfig{args: args.(Arguments)}, nil
+			})
 		},
 	})
 }
 
 // Arguments configures the discovery.gce component.
 type Arguments struct {
-	Project         string        `alloy:"project,attr"`
-	Zone            string        `alloy:"zone,attr"`
+	Project         string        `alloy:"project,attr,optional"`
+	Projects        []string      `alloy:"projects,attr,optional"`
+	Zone            string        `alloy:"zone,attr,optional"`
 	Filter          string        `alloy:"filter,attr,optional"`
+	Filters         []string      `alloy:"filters,attr,optional"`
 	RefreshInterval time.Duration `alloy:"refresh_interval,attr,optional"`
 	Port            int           `alloy:"port,attr,optional"`
 	TagSeparator    string        `alloy:"tag_separator,attr,optional"`
@@ -47,6 +54,64 @@ func (args *Arguments) SetToDefault() {
 	*args = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	projects := args.projects()
+	if len(projects) == 0 {
+		return errors.New("at least one of project or projects must be specified")
+	}
+
+	seen := make(map[string]struct{}, len(projects))
+	for _, project := range projects {
+		if project == "" {
+			return errors.New("projects must not be empty")
+		}
+		if _, ok := seen[project]; ok {
+			return fmt.Errorf("project %q is specified more than once", project)
+		}
+		seen[project] = struct{}{}
+	}
+
+	for _, filter := range args.Filters {
+		if filter == "" {
+			return errors.New("filters must not be empty")
+		}
+	}
+	return nil
+}
+
+// projects returns the projects to discover the instances of.
+func (args Arguments) projects() []string {
+	if args.Project == "" {
+		return args.Projects
+	}
+	return append([]string{args.Project}, args.Projects...)
+}
+
+// filter returns the filter expression of the filter and filters arguments,
+// which instances must all match.
+func (args Arguments) filter() string {
+	var filters []string
+	if args.Filter != "" {
+		filters = append(filters, args.Filter)
+	}
+	filters = append(filters, args.Filters...)
+
+	switch len(filters) {
+	case 0:
+		return ""
+	case 1:
+		return filters[0]
+	}
+
+	// Expressions in parentheses are combined with AND by the API.
+	for i, f := range filters {
+		filters[i] = "(" + f + ")"
+	}
+	return strings.Join(filters, " ")
+}
+
+// Convert returns the Prometheus configuration of the project argument.
 func (args Arguments) Convert() discovery.DiscovererConfig {
 	return &gce.SDConfig{
 		Project:         args.Project,
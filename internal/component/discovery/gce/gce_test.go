@@ -4,8 +4,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/discovery/gce"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/api/compute/v1"
 
 	"github.com/grafana/alloy/syntax"
 )
@@ -36,7 +38,7 @@ func TestUnmarshalAlloyInvalid(t *testing.T) {
 	var args Arguments
 	err := syntax.Unmarshal([]byte(alloyConfig), &args)
 
-	// Validate that project and zone are required.
+	// Validate that a project is required.
 	require.Error(t, err)
 }
 
@@ -58,3 +60,106 @@ func TestConvert(t *testing.T) {
 	require.Equal(t, args.Port, sdConfig.Port)
 	require.Equal(t, args.TagSeparator, sdConfig.TagSeparator)
 }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "projects without zone",
+			cfg: `
+				project = "project"
+				projects = ["project2", "project3"]`,
+		},
+		{
+			name:        "no project",
+			cfg:         `zone = "zone"`,
+			expectedErr: "at least one of project or projects must be specified",
+		},
+		{
+			name: "duplicate project",
+			cfg: `
+				project = "project"
+				projects = ["project2", "project"]`,
+			expectedErr: `project "project" is specified more than once`,
+		},
+		{
+			name: "empty filter",
+			cfg: `
+				projects = ["project"]
+				filters = [""]`,
+			expectedErr: "filters must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.cfg), &args)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestProjectsAndFilter(t *testing.T) {
+	args := Arguments{
+		Project:  "project",
+		Projects: []string{"project2"},
+		Filter:   `status = "RUNNING"`,
+		Filters:  []string{`labels.env = "prod"`, `name = "web-*"`},
+	}
+	require.Equal(t, []string{"project", "project2"}, args.projects())
+	require.Equal(t, `(status = "RUNNING") (labels.env = "prod") (name = "web-*")`, args.filter())
+
+	args = Arguments{Projects: []string{"project"}, Filters: []string{`labels.env = "prod"`}}
+	require.Equal(t, []string{"project"}, args.projects())
+	require.Equal(t, `labels.env = "prod"`, args.filter())
+}
+
+func TestInstanceLabels(t *testing.T) {
+	d := &discoverer{port: 9100, tagSeparator: ","}
+	value := "value"
+	inst := &compute.Instance{
+		Id:          42,
+		Name:        "web-1",
+		Zone:        "https://www.googleapis.com/compute/v1/projects/project/zones/us-east1-a",
+		Status:      "RUNNING",
+		MachineType: "e2-small",
+		NetworkInterfaces: []*compute.NetworkInterface{{
+			Name:       "nic0",
+			Network:    "default",
+			Subnetwork: "default",
+			NetworkIP:  "10.0.0.2",
+		}},
+		Tags:     &compute.Tags{Items: []string{"http-server", "web"}},
+		Metadata: &compute.Metadata{Items: []*compute.MetadataItems{{Key: "key", Value: &value}}},
+		Labels:   map[string]string{"env": "prod"},
+	}
+
+	require.Equal(t, model.LabelSet{
+		"__address__":                        "10.0.0.2:9100",
+		"__meta_gce_project":                 "project",
+		"__meta_gce_zone":                    model.LabelValue(inst.Zone),
+		"__meta_gce_instance_id":             "42",
+		"__meta_gce_instance_name":           "web-1",
+		"__meta_gce_instance_status":         "RUNNING",
+		"__meta_gce_machine_type":            "e2-small",
+		"__meta_gce_network":                 "default",
+		"__meta_gce_subnetwork":              "default",
+		"__meta_gce_private_ip":              "10.0.0.2",
+		"__meta_gce_interface_ipv4_nic0":     "10.0.0.2",
+		"__meta_gce_tags":                    ",http-server,web,",
+		"__meta_gce_network_tag_http_server": "true",
+		"__meta_gce_network_tag_web":         "true",
+		"__meta_gce_metadata_key":            "value",
+		"__meta_gce_label_env":               "prod",
+	}, d.instanceLabels("project", inst))
+
+	require.Nil(t, d.instanceLabels("project", &compute.Instance{Name: "no-interface"}))
+}
@@ -0,0 +1,205 @@
+// Package host provides the discovery.host component, which inventories the
+// host Alloy runs on.
+package host
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "discovery.host",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   discovery.Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the discovery.host component.
+type Arguments struct {
+	RefreshInterval time.Duration `alloy:"refresh_interval,attr,optional"`
+	Packages        bool          `alloy:"packages,attr,optional"`
+	Units           bool          `alloy:"units,attr,optional"`
+	BuiltinServices bool          `alloy:"builtin_services,attr,optional"`
+
+	Services []ServiceArguments `alloy:"service,block,optional"`
+}
+
+// ServiceArguments defines a service which is detected from the sockets the
+// host listens on.
+type ServiceArguments struct {
+	Name      string   `alloy:",label"`
+	Ports     []int    `alloy:"ports,attr,optional"`
+	Processes []string `alloy:"processes,attr,optional"`
+}
+
+// DefaultArguments holds the default arguments of discovery.host.
+var DefaultArguments = Arguments{
+	RefreshInterval: time.Minute,
+	Packages:        true,
+	Units:           true,
+	BuiltinServices: true,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+
+	names := make(map[string]struct{}, len(args.Services))
+	for _, s := range args.Services {
+		if _, ok := names[s.Name]; ok {
+			return fmt.Errorf("service %q is defined more than once", s.Name)
+		}
+		names[s.Name] = struct{}{}
+
+		if len(s.Ports) == 0 && len(s.Processes) == 0 {
+			return fmt.Errorf("service %q must define at least one of ports or processes", s.Name)
+		}
+		for _, p := range s.Ports {
+			if p <= 0 || p > 65535 {
+				return fmt.Errorf("service %q: invalid port %d", s.Name, p)
+			}
+		}
+	}
+	return nil
+}
+
+// services returns the services to detect: the built-in services, unless
+// they're disabled, overridden by the services defined in args.
+func (args *Arguments) services() []service {
+	var res []service
+	if args.BuiltinServices {
+		res = append(res, builtinServices...)
+	}
+	for _, s := range args.Services {
+		res = append(res, service{name: s.Name, ports: s.Ports, processes: s.Processes})
+	}
+	return mergeServices(res)
+}
+
+// Component implements the discovery.host component.
+type Component struct {
+	opts    component.Options
+	logger  log.Logger
+	metrics *metrics
+
+	mut  sync.Mutex
+	args Arguments
+
+	argsUpdates chan struct{}
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new discovery.host component.
+func New(opts component.Options, args Arguments) (*Component, error) {
+	m := newMetrics()
+	if err := m.register(opts.Registerer); err != nil {
+		return nil, err
+	}
+
+	return &Component{
+		opts:        opts,
+		logger:      opts.Logger,
+		metrics:     m,
+		args:        args,
+		argsUpdates: make(chan struct{}, 1),
+	}, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	c.refresh(ctx)
+
+	c.mut.Lock()
+	t := time.NewTicker(c.args.RefreshInterval)
+	c.mut.Unlock()
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			c.refresh(ctx)
+		case <-c.argsUpdates:
+			c.refresh(ctx)
+
+			c.mut.Lock()
+			t.Reset(c.args.RefreshInterval)
+			c.mut.Unlock()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.mut.Lock()
+	c.args = args.(Arguments)
+	c.mut.Unlock()
+
+	select {
+	case c.argsUpdates <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// refresh inventories the host, and updates the exported targets and the
+// inventory metrics. A source of the inventory which fails is logged and
+// skipped, so that a single failure doesn't hide the rest of the inventory.
+func (c *Component) refresh(ctx context.Context) {
+	c.mut.Lock()
+	args := c.args
+	c.mut.Unlock()
+
+	var inv inventory
+
+	listeners, err := listListeners(ctx)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "failed to list listening sockets", "err", err)
+		c.metrics.errors.WithLabelValues(sourceListeners).Inc()
+	}
+	inv.listeners = detectServices(listeners, args.services())
+
+	if args.Packages {
+		inv.packages, err = listPackages(ctx)
+		if err != nil {
+			level.Warn(c.logger).Log("msg", "failed to list installed packages", "err", err)
+			c.metrics.errors.WithLabelValues(sourcePackages).Inc()
+		}
+	}
+
+	if args.Units {
+		inv.units, err = listUnits(ctx)
+		if err != nil {
+			level.Warn(c.logger).Log("msg", "failed to list running units", "err", err)
+			c.metrics.errors.WithLabelValues(sourceUnits).Inc()
+		}
+	}
+
+	c.metrics.setInventory(inv)
+	c.opts.OnStateChange(discovery.Exports{
+		Targets: buildTargets(inv.listeners),
+	})
+}
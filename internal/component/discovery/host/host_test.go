@@ -0,0 +1,146 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	in := `
+		refresh_interval = "5m"
+		packages         = false
+
+		service "postgres" {
+			ports = [5433]
+		}
+
+		service "app" {
+			processes = ["app-server"]
+		}
+	`
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	services := args.services()
+	require.Len(t, services, len(builtinServices)+1)
+
+	byName := make(map[string]service)
+	for _, s := range services {
+		byName[s.name] = s
+	}
+	require.Equal(t, service{name: "postgres", ports: []int{5433}}, byName["postgres"])
+	require.Equal(t, service{name: "app", processes: []string{"app-server"}}, byName["app"])
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name:      "invalid refresh interval",
+			cfg:       `refresh_interval = "0s"`,
+			expectErr: "refresh_interval must be greater than 0",
+		},
+		{
+			name: "duplicate service",
+			cfg: `
+				service "a" {
+					ports = [1]
+				}
+				service "a" {
+					ports = [2]
+				}
+			`,
+			expectErr: `service "a" is defined more than once`,
+		},
+		{
+			name: "empty service",
+			cfg: `
+				service "a" {}
+			`,
+			expectErr: `service "a" must define at least one of ports or processes`,
+		},
+		{
+			name: "invalid port",
+			cfg: `
+				service "a" {
+					ports = [70000]
+				}
+			`,
+			expectErr: `service "a": invalid port 70000`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestDetectServices(t *testing.T) {
+	services := mergeServices(append([]service{}, builtinServices...))
+
+	listeners := detectServices([]listener{
+		{address: "0.0.0.0", port: 5432, process: "postgres"},
+		// Detected from its process, although it listens on the port of
+		// another service.
+		{address: "0.0.0.0", port: 6379, process: "postgres"},
+		// Detected from its port, since the process is unknown.
+		{address: "127.0.0.1", port: 3306},
+		{address: "0.0.0.0", port: 8080, process: "nginx"},
+		{address: "0.0.0.0", port: 8081, process: "unknown"},
+	}, services)
+
+	var detected []string
+	for _, l := range listeners {
+		detected = append(detected, l.service)
+	}
+	require.Equal(t, []string{"postgres", "postgres", "mysql", "nginx", ""}, detected)
+}
+
+func TestBuildTargets(t *testing.T) {
+	targets := buildTargets([]listener{
+		{address: "::", port: 5432, pid: 10, process: "postgres", unit: "postgresql.service", service: "postgres"},
+		{address: "0.0.0.0", port: 5432, pid: 10, process: "postgres", unit: "postgresql.service", service: "postgres"},
+		{address: "192.168.1.10", port: 9100, pid: 20},
+		{address: "::", port: 22, pid: 30, process: "sshd"},
+	})
+
+	expect := []discovery.Target{
+		{
+			"__address__":                "[::1]:22",
+			"__meta_host_protocol":       "tcp",
+			"__meta_host_listen_address": "::",
+			"__meta_host_port":           "22",
+			"__meta_host_process_name":   "sshd",
+			"__meta_host_process_pid":    "30",
+		},
+		{
+			"__address__":                "127.0.0.1:5432",
+			"__meta_host_protocol":       "tcp",
+			"__meta_host_listen_address": "0.0.0.0",
+			"__meta_host_port":           "5432",
+			"__meta_host_service":        "postgres",
+			"__meta_host_process_name":   "postgres",
+			"__meta_host_process_pid":    "10",
+			"__meta_host_unit":           "postgresql.service",
+		},
+		{
+			"__address__":                "192.168.1.10:9100",
+			"__meta_host_protocol":       "tcp",
+			"__meta_host_listen_address": "192.168.1.10",
+			"__meta_host_port":           "9100",
+			"__meta_host_process_pid":    "20",
+		},
+	}
+	require.Equal(t, expect, targets)
+}
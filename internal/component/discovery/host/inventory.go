@@ -0,0 +1,164 @@
+package host
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+	gopsutilnet "github.com/shirou/gopsutil/v3/net"
+	gopsutil "github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	labelService       = "__meta_host_service"
+	labelProtocol      = "__meta_host_protocol"
+	labelListenAddress = "__meta_host_listen_address"
+	labelPort          = "__meta_host_port"
+	labelProcessName   = "__meta_host_process_name"
+	labelProcessPID    = "__meta_host_process_pid"
+	labelUnit          = "__meta_host_unit"
+)
+
+// Sources of the inventory, used to report errors.
+const (
+	sourceListeners = "listeners"
+	sourcePackages  = "packages"
+	sourceUnits     = "units"
+)
+
+// inventory is the result of inventorying the host.
+type inventory struct {
+	listeners []listener
+	packages  []pkg
+	units     []unit
+}
+
+// listener is a TCP socket the host listens on.
+type listener struct {
+	address string
+	port    uint32
+	pid     int32
+	process string
+	unit    string
+	service string
+}
+
+// pkg is an installed package.
+type pkg struct {
+	name    string
+	version string
+	manager string
+}
+
+// unit is a running systemd unit.
+type unit struct {
+	name        string
+	description string
+}
+
+// listListeners returns the TCP sockets the host listens on, with the
+// process, and the systemd unit of the process, which owns each of them.
+func listListeners(ctx context.Context) ([]listener, error) {
+	conns, err := gopsutilnet.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int32]string)
+	var res []listener
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" {
+			continue
+		}
+
+		l := listener{
+			address: conn.Laddr.IP,
+			port:    conn.Laddr.Port,
+			pid:     conn.Pid,
+		}
+		if conn.Pid > 0 {
+			name, ok := names[conn.Pid]
+			if !ok {
+				// The process may have exited since the sockets were listed,
+				// or may belong to another user, in which case its name is
+				// left empty.
+				if p, err := gopsutil.NewProcessWithContext(ctx, conn.Pid); err == nil {
+					name, _ = p.NameWithContext(ctx)
+				}
+				names[conn.Pid] = name
+			}
+			l.process = name
+			l.unit = processUnit(conn.Pid)
+		}
+		res = append(res, l)
+	}
+	return res, nil
+}
+
+// buildTargets returns a target for each listener. Listeners bound to all
+// addresses are targeted on the loopback address. When a process listens on
+// the same port on all IPv4 and all IPv6 addresses, a single IPv4 target is
+// returned.
+func buildTargets(listeners []listener) []discovery.Target {
+	sorted := make([]listener, len(listeners))
+	copy(sorted, listeners)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].port != sorted[j].port {
+			return sorted[i].port < sorted[j].port
+		}
+		return isIPv4(sorted[i].address) && !isIPv4(sorted[j].address)
+	})
+
+	type wildcardKey struct {
+		port uint32
+		pid  int32
+	}
+	wildcards := make(map[wildcardKey]struct{})
+
+	res := make([]discovery.Target, 0, len(sorted))
+	for _, l := range sorted {
+		host := l.address
+		if ip := net.ParseIP(l.address); ip != nil && ip.IsUnspecified() {
+			key := wildcardKey{port: l.port, pid: l.pid}
+			if _, ok := wildcards[key]; ok {
+				continue
+			}
+			wildcards[key] = struct{}{}
+
+			if ip.To4() != nil {
+				host = "127.0.0.1"
+			} else {
+				host = "::1"
+			}
+		}
+
+		port := strconv.FormatUint(uint64(l.port), 10)
+		t := discovery.Target{
+			"__address__":      net.JoinHostPort(host, port),
+			labelProtocol:      "tcp",
+			labelListenAddress: l.address,
+			labelPort:          port,
+		}
+		if l.service != "" {
+			t[labelService] = l.service
+		}
+		if l.process != "" {
+			t[labelProcessName] = l.process
+		}
+		if l.pid > 0 {
+			t[labelProcessPID] = strconv.FormatInt(int64(l.pid), 10)
+		}
+		if l.unit != "" {
+			t[labelUnit] = l.unit
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+func isIPv4(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.To4() != nil
+}
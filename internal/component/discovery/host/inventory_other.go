@@ -0,0 +1,20 @@
+//go:build !linux
+
+package host
+
+import "context"
+
+// listPackages returns no packages, since package managers are only supported
+// on Linux.
+func listPackages(ctx context.Context) ([]pkg, error) {
+	return nil, nil
+}
+
+// listUnits returns no units, since systemd is only supported on Linux.
+func listUnits(ctx context.Context) ([]unit, error) {
+	return nil, nil
+}
+
+func processUnit(pid int32) string {
+	return ""
+}
@@ -0,0 +1,107 @@
+package host
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	packageInfoDesc = prometheus.NewDesc(
+		"discovery_host_package_info",
+		"Installed package, with a constant value of 1.",
+		[]string{"name", "version", "manager"}, nil,
+	)
+	listenerInfoDesc = prometheus.NewDesc(
+		"discovery_host_listener_info",
+		"TCP socket the host listens on, with a constant value of 1.",
+		[]string{"address", "port", "process", "unit", "service"}, nil,
+	)
+	unitInfoDesc = prometheus.NewDesc(
+		"discovery_host_unit_info",
+		"Running systemd service, with a constant value of 1.",
+		[]string{"name", "description"}, nil,
+	)
+)
+
+// metrics exposes the last inventory of the host as info metrics.
+type metrics struct {
+	errors *prometheus.CounterVec
+
+	mut       sync.RWMutex
+	inventory inventory
+}
+
+var _ prometheus.Collector = (*metrics)(nil)
+
+func newMetrics() *metrics {
+	return &metrics{
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "discovery_host_inventory_errors_total",
+			Help: "Total number of errors while inventorying the host, by source.",
+		}, []string{"source"}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	m.errors = util.MustRegisterOrGet(reg, m.errors).(*prometheus.CounterVec)
+
+	// The inventory of a previous instance of the component would be stale,
+	// so it's replaced rather than reused.
+	if err := reg.Register(m); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return err
+		}
+		reg.Unregister(are.ExistingCollector)
+		return reg.Register(m)
+	}
+	return nil
+}
+
+func (m *metrics) setInventory(inv inventory) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	m.inventory = inv
+}
+
+// Describe implements prometheus.Collector.
+func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- packageInfoDesc
+	ch <- listenerInfoDesc
+	ch <- unitInfoDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *metrics) Collect(ch chan<- prometheus.Metric) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	// Packages may be known to several package managers, and processes may
+	// listen on the same socket several times, so duplicate series are
+	// skipped.
+	seen := make(map[string]struct{})
+	emit := func(desc *prometheus.Desc, values ...string) {
+		key := desc.String()
+		for _, v := range values {
+			key += "\xff" + v
+		}
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, values...)
+	}
+
+	for _, p := range m.inventory.packages {
+		emit(packageInfoDesc, p.name, p.version, p.manager)
+	}
+	for _, l := range m.inventory.listeners {
+		emit(listenerInfoDesc, l.address, strconv.FormatUint(uint64(l.port), 10), l.process, l.unit, l.service)
+	}
+	for _, u := range m.inventory.units {
+		emit(unitInfoDesc, u.name, u.description)
+	}
+}
@@ -0,0 +1,182 @@
+//go:build linux
+
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Paths of the databases of package managers. They're variables so that tests
+// can override them.
+var (
+	dpkgStatusPath   = "/var/lib/dpkg/status"
+	apkInstalledPath = "/lib/apk/db/installed"
+)
+
+// listPackages returns the packages installed by the package managers found
+// on the host. dpkg and apk databases are read directly, while rpm packages
+// are listed with the rpm command, since the rpm database format depends on
+// the distribution.
+func listPackages(ctx context.Context) ([]pkg, error) {
+	var (
+		res  []pkg
+		errs []error
+	)
+
+	for _, db := range []struct {
+		path  string
+		parse func(io.Reader) ([]pkg, error)
+	}{
+		{path: dpkgStatusPath, parse: parseDpkgStatus},
+		{path: apkInstalledPath, parse: parseApkInstalled},
+	} {
+		pkgs, err := readPackages(db.path, db.parse)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		res = append(res, pkgs...)
+	}
+
+	if _, err := exec.LookPath("rpm"); err == nil {
+		pkgs, err := listRpmPackages(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		res = append(res, pkgs...)
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// readPackages parses the package database at path. A missing database isn't
+// an error, since it means the package manager isn't used on the host.
+func readPackages(path string, parse func(io.Reader) ([]pkg, error)) ([]pkg, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pkgs, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return pkgs, nil
+}
+
+// parseDpkgStatus parses a dpkg status file, which holds a paragraph of
+// fields for each known package. Only installed packages are returned.
+func parseDpkgStatus(r io.Reader) ([]pkg, error) {
+	var (
+		res     []pkg
+		current pkg
+		status  string
+	)
+	flush := func() {
+		if current.name != "" && strings.HasSuffix(status, " installed") {
+			current.manager = "dpkg"
+			res = append(res, current)
+		}
+		current, status = pkg{}, ""
+	}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			// Continuation of a multi-line field.
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Package":
+			current.name = value
+		case "Version":
+			current.version = value
+		case "Status":
+			status = value
+		}
+	}
+	flush()
+
+	return res, s.Err()
+}
+
+// parseApkInstalled parses an apk installed database, which holds a paragraph
+// of single letter fields for each installed package.
+func parseApkInstalled(r io.Reader) ([]pkg, error) {
+	var (
+		res     []pkg
+		current pkg
+	)
+	flush := func() {
+		if current.name != "" {
+			current.manager = "apk"
+			res = append(res, current)
+		}
+		current = pkg{}
+	}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "P:"):
+			current.name = line[2:]
+		case strings.HasPrefix(line, "V:"):
+			current.version = line[2:]
+		}
+	}
+	flush()
+
+	return res, s.Err()
+}
+
+func listRpmPackages(ctx context.Context) ([]pkg, error) {
+	cmd := exec.CommandContext(ctx, "rpm", "--query", "--all", "--queryformat", `%{NAME}\t%{VERSION}-%{RELEASE}\n`)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rpm packages: %w", err)
+	}
+	return parseRpmOutput(bytes.NewReader(out))
+}
+
+// parseRpmOutput parses the output of the rpm command run by listRpmPackages.
+func parseRpmOutput(r io.Reader) ([]pkg, error) {
+	var res []pkg
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		name, version, ok := strings.Cut(s.Text(), "\t")
+		if !ok || name == "" || name == "gpg-pubkey" {
+			continue
+		}
+		res = append(res, pkg{name: name, version: version, manager: "rpm"})
+	}
+	return res, s.Err()
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package host
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDpkgStatus(t *testing.T) {
+	in := `Package: postgresql-16
+Status: install ok installed
+Priority: optional
+Version: 16.4-1.pgdg120+1
+Description: The World's Most Advanced Open Source Relational Database
+ PostgreSQL, also known as Postgres, is a free and open-source relational
+ database management system.
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: curl
+Status: install ok installed
+Version: 7.88.1-10+deb12u7
+`
+
+	pkgs, err := parseDpkgStatus(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Equal(t, []pkg{
+		{name: "postgresql-16", version: "16.4-1.pgdg120+1", manager: "dpkg"},
+		{name: "curl", version: "7.88.1-10+deb12u7", manager: "dpkg"},
+	}, pkgs)
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	in := `C:Q1abc=
+P:musl
+V:1.2.5-r0
+A:x86_64
+
+C:Q1def=
+P:redis
+V:7.2.5-r0
+A:x86_64
+`
+
+	pkgs, err := parseApkInstalled(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Equal(t, []pkg{
+		{name: "musl", version: "1.2.5-r0", manager: "apk"},
+		{name: "redis", version: "7.2.5-r0", manager: "apk"},
+	}, pkgs)
+}
+
+func TestParseRpmOutput(t *testing.T) {
+	in := "bash\t5.1.8-9.el9\ngpg-pubkey\t8483c65d-5ccc5b19\nmariadb-server\t10.5.22-1.el9_2\n"
+
+	pkgs, err := parseRpmOutput(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Equal(t, []pkg{
+		{name: "bash", version: "5.1.8-9.el9", manager: "rpm"},
+		{name: "mariadb-server", version: "10.5.22-1.el9_2", manager: "rpm"},
+	}, pkgs)
+}
+
+func TestCgroupUnit(t *testing.T) {
+	tests := map[string]string{
+		"0::/system.slice/postgresql@16-main.service":         "postgresql@16-main.service",
+		"1:name=systemd:/system.slice/redis-server.service":   "redis-server.service",
+		"4:memory:/system.slice/redis-server.service":         "",
+		"0::/user.slice/user-1000.slice/session-2.scope":      "",
+		"0::/system.slice/docker-0123456789abcdef.scope/init": "",
+	}
+	for line, expect := range tests {
+		require.Equal(t, expect, cgroupUnit(line), line)
+	}
+}
@@ -0,0 +1,77 @@
+package host
+
+import (
+	"slices"
+	"sort"
+)
+
+// service is a service detected from the sockets the host listens on.
+type service struct {
+	name      string
+	ports     []int
+	processes []string
+}
+
+// builtinServices are the services detected by default. Services which are
+// commonly served on shared ports, such as web servers, are only detected
+// from the name of their process.
+var builtinServices = []service{
+	{name: "apache", processes: []string{"apache2", "httpd"}},
+	{name: "consul", ports: []int{8500}, processes: []string{"consul"}},
+	{name: "elasticsearch", ports: []int{9200}},
+	{name: "etcd", ports: []int{2379}, processes: []string{"etcd"}},
+	{name: "haproxy", processes: []string{"haproxy"}},
+	{name: "kafka", ports: []int{9092}},
+	{name: "memcached", ports: []int{11211}, processes: []string{"memcached"}},
+	{name: "mongodb", ports: []int{27017}, processes: []string{"mongod", "mongos"}},
+	{name: "mysql", ports: []int{3306}, processes: []string{"mysqld", "mariadbd"}},
+	{name: "nginx", processes: []string{"nginx"}},
+	{name: "postgres", ports: []int{5432}, processes: []string{"postgres", "postmaster"}},
+	{name: "rabbitmq", ports: []int{5672, 15672}},
+	{name: "redis", ports: []int{6379}, processes: []string{"redis-server"}},
+}
+
+// mergeServices returns services with a single definition for each name,
+// where later definitions replace earlier ones. The result is sorted by name.
+func mergeServices(services []service) []service {
+	byName := make(map[string]service, len(services))
+	for _, s := range services {
+		byName[s.name] = s
+	}
+
+	res := make([]service, 0, len(byName))
+	for _, s := range byName {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
+
+// detectServices sets the service of each listener. A listener is matched to
+// a service by the name of its process, or otherwise by its port, so that a
+// service listening on a port usually used by another service is still
+// detected correctly.
+func detectServices(listeners []listener, services []service) []listener {
+	res := make([]listener, len(listeners))
+	for i, l := range listeners {
+		l.service = ""
+		if l.process != "" {
+			for _, s := range services {
+				if slices.Contains(s.processes, l.process) {
+					l.service = s.name
+					break
+				}
+			}
+		}
+		if l.service == "" {
+			for _, s := range services {
+				if slices.Contains(s.ports, int(l.port)) {
+					l.service = s.name
+					break
+				}
+			}
+		}
+		res[i] = l
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package host
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+// listUnits returns the running systemd services. Hosts which don't run
+// systemd have no units.
+func listUnits(ctx context.Context) ([]unit, error) {
+	if _, err := os.Stat("/run/systemd/system"); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	conn, err := dbus.NewWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to systemd: %w", err)
+	}
+	defer conn.Close()
+
+	statuses, err := conn.ListUnitsByPatternsContext(ctx, []string{"running"}, []string{"*.service"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	res := make([]unit, 0, len(statuses))
+	for _, s := range statuses {
+		res = append(res, unit{name: s.Name, description: s.Description})
+	}
+	return res, nil
+}
+
+// processUnit returns the systemd service which runs the process, or an empty
+// string if it isn't known.
+func processUnit(pid int32) string {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if u := cgroupUnit(s.Text()); u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
+// cgroupUnit returns the systemd service from a line of a /proc/PID/cgroup
+// file, for example "0::/system.slice/postgresql.service".
+func cgroupUnit(line string) string {
+	parts := strings.SplitN(line, ":", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+
+	// With cgroup v1, only the hierarchy managed by systemd is used.
+	if parts[0] != "0" && parts[1] != "name=systemd" {
+		return ""
+	}
+
+	segments := strings.Split(parts[2], "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if strings.HasSuffix(segments[i], ".service") {
+			return segments[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,307 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/regexp"
+	"github.com/prometheus/client_golang/prometheus"
+	commonconfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/version"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	promhttp "github.com/prometheus/prometheus/discovery/http"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const httpSDURLLabel = model.MetaLabelPrefix + "url"
+
+var (
+	userAgent        = fmt.Sprintf("Prometheus/%s", version.Version)
+	matchContentType = regexp.MustCompile(`^(?i:application\/json(;\s*charset=("utf-8"|utf-8))?)$`)
+)
+
+// discovererConfig creates the discoverers of the component. Unlike the
+// Prometheus HTTP discoverer, they send conditional requests, and only send
+// the target groups when the payload of the endpoint changed.
+type discovererConfig struct {
+	sd     *promhttp.SDConfig
+	status *refreshStatus
+}
+
+var _ prom_discovery.Config = (*discovererConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (*discovererConfig) Name() string {
+	return "http"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *discovererConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := opts.Metrics.(*discovererMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	client, err := commonconfig.NewClientFromConfig(c.sd.HTTPClientConfig, "http", opts.HTTPClientOptions...)
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = time.Duration(c.sd.RefreshInterval)
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	return &discoverer{
+		logger:          logger,
+		url:             c.sd.URL,
+		client:          client,
+		refreshInterval: time.Duration(c.sd.RefreshInterval),
+		metrics:         m,
+		status:          c.status,
+	}, nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*discovererConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	m := &discovererMetrics{
+		refreshMetrics: rmi.Instantiate("http"),
+		failuresCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "prometheus_sd_http_failures_total",
+			Help: "Number of HTTP service discovery refresh failures.",
+		}),
+	}
+	m.metricRegisterer = prom_discovery.NewMetricRegisterer(reg, []prometheus.Collector{
+		m.failuresCount,
+	})
+	return m
+}
+
+var _ prom_discovery.DiscovererMetrics = (*discovererMetrics)(nil)
+
+type discovererMetrics struct {
+	refreshMetrics   *prom_discovery.RefreshMetrics
+	failuresCount    prometheus.Counter
+	metricRegisterer prom_discovery.MetricRegisterer
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Register() error {
+	return m.metricRegisterer.RegisterMetrics()
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Unregister() {
+	m.metricRegisterer.UnregisterMetrics()
+}
+
+// refreshStatus tracks the refreshes of the targets across the discoverers of
+// the component.
+type refreshStatus struct {
+	mut         sync.Mutex
+	lastRefresh time.Time
+	lastChange  time.Time
+	lastError   error
+
+	// onStaleChange is called when the targets become stale or up to date.
+	onStaleChange func()
+}
+
+// update records the result of a refresh.
+func (s *refreshStatus) update(changed bool, err error) {
+	s.mut.Lock()
+	wasStale := s.lastError != nil
+	s.lastRefresh = time.Now()
+	s.lastError = err
+	if changed {
+		s.lastChange = s.lastRefresh
+	}
+	s.mut.Unlock()
+
+	if wasStale != (err != nil) && s.onStaleChange != nil {
+		s.onStaleChange()
+	}
+}
+
+// stale returns whether the latest refresh failed.
+func (s *refreshStatus) stale() bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.lastError != nil
+}
+
+func (s *refreshStatus) debugInfo() DebugInfo {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	info := DebugInfo{
+		LastRefresh: s.lastRefresh,
+		LastChange:  s.lastChange,
+	}
+	if s.lastError != nil {
+		info.LastError = s.lastError.Error()
+	}
+	return info
+}
+
+// discoverer discovers the target groups of an HTTP endpoint.
+type discoverer struct {
+	logger          log.Logger
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+	metrics         *discovererMetrics
+	status          *refreshStatus
+
+	// Validators of the last payload, sent with the conditional requests.
+	etag         string
+	lastModified string
+	// Hash of the last payload, to detect payloads which didn't change when
+	// the endpoint doesn't support conditional requests.
+	payloadHash  []byte
+	tgLastLength int
+}
+
+var _ prom_discovery.Discoverer = (*discoverer)(nil)
+
+// Run implements discovery.Discoverer. The target groups are only sent when
+// the payload of the endpoint changed.
+func (d *discoverer) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	ticker := time.NewTicker(d.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		tgs, changed, err := d.refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		d.status.update(changed, err)
+
+		if err != nil {
+			level.Error(d.logger).Log("msg", "unable to refresh target groups", "err", err)
+		} else if changed {
+			select {
+			case ch <- tgs:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *discoverer) refresh(ctx context.Context) ([]*targetgroup.Group, bool, error) {
+	now := time.Now()
+	defer func() {
+		d.metrics.refreshMetrics.Duration.Observe(time.Since(now).Seconds())
+	}()
+
+	tgs, changed, err := d.fetch(ctx)
+	if err != nil {
+		d.metrics.refreshMetrics.Failures.Inc()
+		d.metrics.failuresCount.Inc()
+	}
+	return tgs, changed, err
+}
+
+// fetch fetches the target groups of the endpoint, and returns whether they
+// changed since the previous fetch.
+func (d *discoverer) fetch(ctx context.Context) ([]*targetgroup.Group, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Prometheus-Refresh-Interval-Seconds", strconv.FormatFloat(d.refreshInterval.Seconds(), 'f', -1, 64))
+	if d.etag != "" {
+		req.Header.Set("If-None-Match", d.etag)
+	}
+	if d.lastModified != "" {
+		req.Header.Set("If-Modified-Since", d.lastModified)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("server returned HTTP status %s", resp.Status)
+	}
+
+	if !matchContentType.MatchString(strings.TrimSpace(resp.Header.Get("Content-Type"))) {
+		return nil, false, fmt.Errorf("unsupported content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hash := sha256.Sum256(b)
+	if bytes.Equal(hash[:], d.payloadHash) {
+		d.etag, d.lastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		return nil, false, nil
+	}
+
+	var targetGroups []*targetgroup.Group
+	if err := json.Unmarshal(b, &targetGroups); err != nil {
+		return nil, false, err
+	}
+
+	for i, tg := range targetGroups {
+		if tg == nil {
+			return nil, false, errors.New("nil target group item found")
+		}
+
+		tg.Source = urlSource(d.url, i)
+		if tg.Labels == nil {
+			tg.Labels = model.LabelSet{}
+		}
+		tg.Labels[httpSDURLLabel] = model.LabelValue(d.url)
+	}
+
+	// Generate empty updates for sources that disappeared.
+	l := len(targetGroups)
+	for i := l; i < d.tgLastLength; i++ {
+		targetGroups = append(targetGroups, &targetgroup.Group{Source: urlSource(d.url, i)})
+	}
+
+	d.etag, d.lastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	d.payloadHash = hash[:]
+	d.tgLastLength = l
+	return targetGroups, true, nil
+}
+
+// urlSource returns a source ID for the i-th target group per URL.
+func urlSource(url string, i int) string {
+	return fmt.Sprintf("%s:%d", url, i)
+}
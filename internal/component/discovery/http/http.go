@@ -1,6 +1,7 @@
 package http
 
 import (
+	"sync"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -17,9 +18,9 @@ func init() {
 		Name:      "discovery.http",
 		Stability: featuregate.StabilityGenerallyAvailable,
 		Args:      Arguments{},
-		Exports:   discovery.Exports{},
+		Exports:   Exports{},
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			return discovery.NewFromConvertibleConfig(opts, args.(Arguments))
+			return New(opts, args.(Arguments))
 		},
 	})
 }
@@ -54,3 +55,79 @@ func (args Arguments) Convert() discovery.DiscovererConfig {
 	}
 	return cfg
 }
+
+// Exports holds the values exported by the discovery.http component.
+type Exports struct {
+	Targets []discovery.Target `alloy:"targets,attr"`
+	// Stale is true when the latest refresh of the targets failed, in which
+	// case the targets are the ones of the last successful refresh.
+	Stale bool `alloy:"stale,attr"`
+}
+
+// DebugInfo reports the status of the refreshes of the targets.
+type DebugInfo struct {
+	LastRefresh time.Time `alloy:"last_refresh,attr,optional"`
+	LastChange  time.Time `alloy:"last_change,attr,optional"`
+	LastError   string    `alloy:"last_error,attr,optional"`
+}
+
+// Component implements the discovery.http component.
+type Component struct {
+	*discovery.Component
+
+	opts   component.Options
+	status *refreshStatus
+
+	exportsMut sync.Mutex
+	targets    []discovery.Target
+}
+
+var (
+	_ component.Component      = (*Component)(nil)
+	_ component.DebugComponent = (*Component)(nil)
+)
+
+// New creates a new discovery.http component.
+func New(opts component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts:   opts,
+		status: &refreshStatus{},
+	}
+	c.status.onStaleChange = c.export
+
+	discOpts := opts
+	discOpts.OnStateChange = func(e component.Exports) {
+		c.exportsMut.Lock()
+		c.targets = e.(discovery.Exports).Targets
+		c.exportsMut.Unlock()
+		c.export()
+	}
+
+	var err error
+	c.Component, err = discovery.New(discOpts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+		return &discovererConfig{
+			sd:     args.(Arguments).Convert().(*http.SDConfig),
+			status: c.status,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// export exports the latest targets along with their staleness.
+func (c *Component) export() {
+	c.exportsMut.Lock()
+	defer c.exportsMut.Unlock()
+
+	c.opts.OnStateChange(Exports{
+		Targets: c.targets,
+		Stale:   c.status.stale(),
+	})
+}
+
+// DebugInfo implements component.DebugComponent.
+func (c *Component) DebugInfo() interface{} {
+	return c.status.debugInfo()
+}
@@ -118,3 +118,72 @@ func TestComponent(t *testing.T) {
 	assert.Equal(t, true, endpointCalled)
 	assert.Equal(t, true, stateChanged.Load())
 }
+
+func TestConditionalRequests(t *testing.T) {
+	discovery.MaxUpdateFrequency = time.Second / 2
+	var (
+		requests atomic.Int32
+		failing  atomic.Bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Inc()
+		switch {
+		case failing.Load():
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"targets": ["10.0.10.2:9100"]}]`))
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	args := DefaultArguments
+	args.RefreshInterval = 100 * time.Millisecond
+	args.URL = config.URL{URL: u}
+
+	exports := make(chan Exports, 10)
+	c, err := New(component.Options{
+		OnStateChange: func(e component.Exports) { exports <- e.(Exports) },
+		Registerer:    prometheus.NewRegistry(),
+	}, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	next := func() Exports {
+		select {
+		case e := <-exports:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for exports")
+			return Exports{}
+		}
+	}
+
+	e := next()
+	require.False(t, e.Stale)
+	require.Equal(t, []discovery.Target{{"__address__": "10.0.10.2:9100", "__meta_url": srv.URL}}, e.Targets)
+
+	// Unchanged payloads aren't exported again.
+	require.Eventually(t, func() bool { return requests.Load() > 5 }, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, exports)
+
+	// Failed refreshes keep the targets, and mark them as stale until the
+	// next successful refresh.
+	failing.Store(true)
+	e = next()
+	require.True(t, e.Stale)
+	require.Len(t, e.Targets, 1)
+
+	failing.Store(false)
+	e = next()
+	require.False(t, e.Stale)
+	require.Len(t, e.Targets, 1)
+	require.Empty(t, c.DebugInfo().(DebugInfo).LastError)
+}
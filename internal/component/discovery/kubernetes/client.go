@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-kit/log"
+	commonConfig "github.com/prometheus/common/config"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// newRestConfig builds the Kubernetes client configuration for args the same
+// way the upstream Kubernetes discovery does. If the own namespace is
+// requested and Alloy runs in a cluster, the namespace of the pod is returned
+// as well.
+func newRestConfig(logger log.Logger, args Arguments) (*rest.Config, string, error) {
+	var (
+		kcfg         *rest.Config
+		err          error
+		ownNamespace string
+	)
+	switch {
+	case args.KubeConfig != "":
+		kcfg, err = clientcmd.BuildConfigFromFlags("", args.KubeConfig)
+		if err != nil {
+			return nil, "", err
+		}
+	case args.APIServer.URL == nil:
+		kcfg, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, "", err
+		}
+
+		if args.NamespaceDiscovery.IncludeOwnNamespace {
+			ownNamespaceContents, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+			if err != nil {
+				return nil, "", fmt.Errorf("could not determine the pod's namespace: %w", err)
+			}
+			if len(ownNamespaceContents) == 0 {
+				return nil, "", errors.New("could not read own namespace name (empty file)")
+			}
+			ownNamespace = string(ownNamespaceContents)
+		}
+
+		level.Info(logger).Log("msg", "Using pod service account via in-cluster config")
+	default:
+		rt, err := commonConfig.NewRoundTripperFromConfig(*args.HTTPClientConfig.Convert(), "kubernetes_sd")
+		if err != nil {
+			return nil, "", err
+		}
+		kcfg = &rest.Config{
+			Host:      args.APIServer.String(),
+			Transport: rt,
+		}
+	}
+	kcfg.UserAgent = "Grafana Alloy"
+	return kcfg, ownNamespace, nil
+}
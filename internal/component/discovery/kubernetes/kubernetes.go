@@ -2,6 +2,8 @@
 package kubernetes
 
 import (
+	"fmt"
+
 	promk8s "github.com/prometheus/prometheus/discovery/kubernetes"
 
 	"github.com/grafana/alloy/internal/component"
@@ -32,6 +34,7 @@ type Arguments struct {
 	NamespaceDiscovery NamespaceDiscovery      `alloy:"namespaces,block,optional"`
 	Selectors          []SelectorConfig        `alloy:"selectors,block,optional"`
 	AttachMetadata     AttachMetadataConfig    `alloy:"attach_metadata,block,optional"`
+	MetadataOnly       bool                    `alloy:"metadata_only,attr,optional"`
 }
 
 // DefaultConfig holds defaults for SDConfig.
@@ -46,16 +49,33 @@ func (args *Arguments) SetToDefault() {
 
 // Validate implements syntax.Validator.
 func (args *Arguments) Validate() error {
+	if args.MetadataOnly {
+		if _, ok := metadataRoles[args.Role]; !ok {
+			return fmt.Errorf("metadata_only is only supported for the pod, service and node roles")
+		}
+		if args.AttachMetadata.Node {
+			return fmt.Errorf("metadata_only can't be used together with attach_metadata")
+		}
+	}
+
+	if args.AttachMetadata.EndpointTopology && args.Role != string(promk8s.RoleEndpointSlice) {
+		return fmt.Errorf("attach_metadata endpoint_topology is only supported for the endpointslice role")
+	}
+
 	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
 	return args.HTTPClientConfig.Validate()
 }
 
 func (args Arguments) Convert() discovery.DiscovererConfig {
+	if args.MetadataOnly {
+		return &metadataDiscoveryConfig{args: args}
+	}
+
 	selectors := make([]promk8s.SelectorConfig, len(args.Selectors))
 	for i, s := range args.Selectors {
 		selectors[i] = *s.convert()
 	}
-	return &promk8s.SDConfig{
+	sdConfig := &promk8s.SDConfig{
 		APIServer:          args.APIServer.Convert(),
 		Role:               promk8s.Role(args.Role),
 		KubeConfig:         args.KubeConfig,
@@ -64,6 +84,10 @@ func (args Arguments) Convert() discovery.DiscovererConfig {
 		Selectors:          selectors,
 		AttachMetadata:     *args.AttachMetadata.convert(),
 	}
+	if args.AttachMetadata.EndpointTopology {
+		return &topologyDiscoveryConfig{SDConfig: sdConfig, args: args}
+	}
+	return sdConfig
 }
 
 // NamespaceDiscovery configures filtering rules for which namespaces to discover.
@@ -96,6 +120,10 @@ func (sc *SelectorConfig) convert() *promk8s.SelectorConfig {
 
 type AttachMetadataConfig struct {
 	Node bool `alloy:"node,attr,optional"`
+
+	// EndpointTopology attaches the topology labels of endpoint slices, which
+	// requires an additional watch of the endpoint slices.
+	EndpointTopology bool `alloy:"endpoint_topology,attr,optional"`
 }
 
 func (am *AttachMetadataConfig) convert() *promk8s.AttachMetadataConfig {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/grafana/alloy/syntax"
+	promk8s "github.com/prometheus/prometheus/discovery/kubernetes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,3 +47,30 @@ func TestAttachMetadata(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.NoError(t, err)
 }
+
+func TestEndpointTopology(t *testing.T) {
+	var exampleAlloyConfig = `
+	role = "endpointslice"
+	attach_metadata {
+		endpoint_topology = true
+	}
+`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.NoError(t, err)
+	require.IsType(t, &topologyDiscoveryConfig{}, args.Convert())
+
+	// The endpoint slices are only watched a second time when the topology
+	// labels are enabled.
+	args.AttachMetadata.EndpointTopology = false
+	require.IsType(t, &promk8s.SDConfig{}, args.Convert())
+
+	err = syntax.Unmarshal([]byte(`
+	role = "pod"
+	attach_metadata {
+		endpoint_topology = true
+	}
+`), &args)
+	require.ErrorContains(t, err, "attach_metadata endpoint_topology is only supported for the endpointslice role")
+}
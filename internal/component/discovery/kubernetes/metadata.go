@@ -0,0 +1,222 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	metaLabelPrefix = model.MetaLabelPrefix + "kubernetes_"
+	namespaceLabel  = metaLabelPrefix + "namespace"
+	presentValue    = model.LabelValue("true")
+
+	// kubeletPort is the default port of the kubelet, used to build the
+	// address of node targets since node addresses aren't part of the object
+	// metadata.
+	kubeletPort = "10250"
+)
+
+// metadataRoles are the roles supported when metadata_only is enabled.
+var metadataRoles = map[string]schema.GroupVersionResource{
+	"pod":     {Version: "v1", Resource: "pods"},
+	"service": {Version: "v1", Resource: "services"},
+	"node":    {Version: "v1", Resource: "nodes"},
+}
+
+// metadataDiscoveryConfig is a discovery.DiscovererConfig which discovers
+// Kubernetes objects using metadata-only informers. Metadata-only informers
+// only cache the ObjectMeta of watched objects, which uses considerably less
+// memory than caching full objects in large clusters.
+type metadataDiscoveryConfig struct {
+	args Arguments
+}
+
+var _ prom_discovery.Config = (*metadataDiscoveryConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (*metadataDiscoveryConfig) Name() string {
+	return "kubernetes_metadata"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *metadataDiscoveryConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	kcfg, ownNamespace, err := newRestConfig(opts.Logger, c.args)
+	if err != nil {
+		return nil, err
+	}
+	client, err := metadata.NewForConfig(kcfg)
+	if err != nil {
+		return nil, err
+	}
+	return newMetadataDiscovery(opts.Logger, c.args, client, ownNamespace), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*metadataDiscoveryConfig) NewDiscovererMetrics(_ prometheus.Registerer, _ prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	return &metadataMetrics{}
+}
+
+var _ prom_discovery.DiscovererMetrics = (*metadataMetrics)(nil)
+
+type metadataMetrics struct{}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *metadataMetrics) Register() error {
+	return nil
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *metadataMetrics) Unregister() {}
+
+// metadataDiscovery implements discovery.Discoverer using metadata-only
+// informers.
+type metadataDiscovery struct {
+	logger     log.Logger
+	client     metadata.Interface
+	role       string
+	namespaces []string
+	selector   SelectorConfig
+}
+
+func newMetadataDiscovery(logger log.Logger, args Arguments, client metadata.Interface, ownNamespace string) *metadataDiscovery {
+	d := &metadataDiscovery{
+		logger: logger,
+		client: client,
+		role:   args.Role,
+	}
+	for _, s := range args.Selectors {
+		if s.Role == args.Role {
+			d.selector = s
+		}
+	}
+
+	switch {
+	case args.Role == "node":
+		// Nodes aren't namespaced.
+		d.namespaces = []string{metav1.NamespaceAll}
+	case len(args.NamespaceDiscovery.Names) == 0 && !args.NamespaceDiscovery.IncludeOwnNamespace:
+		d.namespaces = []string{metav1.NamespaceAll}
+	default:
+		d.namespaces = args.NamespaceDiscovery.Names
+		if args.NamespaceDiscovery.IncludeOwnNamespace && ownNamespace != "" {
+			d.namespaces = append(d.namespaces, ownNamespace)
+		}
+	}
+	return d
+}
+
+// Run implements discovery.Discoverer.
+func (d *metadataDiscovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	gvr := metadataRoles[d.role]
+
+	var informers []cache.SharedIndexInformer
+	for _, namespace := range d.namespaces {
+		informer := metadatainformer.NewFilteredMetadataInformer(d.client, gvr, namespace, 0, cache.Indexers{}, func(opts *metav1.ListOptions) {
+			opts.LabelSelector = d.selector.Label
+			opts.FieldSelector = d.selector.Field
+		}).Informer()
+
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				d.send(ctx, ch, obj, false)
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				d.send(ctx, ch, obj, false)
+			},
+			DeleteFunc: func(obj interface{}) {
+				d.send(ctx, ch, obj, true)
+			},
+		})
+		if err != nil {
+			level.Error(d.logger).Log("msg", "failed to add event handler", "role", d.role, "err", err)
+			return
+		}
+		informers = append(informers, informer)
+	}
+
+	for _, informer := range informers {
+		go informer.Run(ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (d *metadataDiscovery) send(ctx context.Context, ch chan<- []*targetgroup.Group, obj interface{}, deleted bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	meta, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		level.Error(d.logger).Log("msg", "received unexpected object", "object", obj)
+		return
+	}
+
+	tg := &targetgroup.Group{
+		Source: d.role + "/" + meta.Namespace + "/" + meta.Name,
+	}
+	if !deleted {
+		tg.Targets = []model.LabelSet{d.buildTarget(meta)}
+	}
+
+	select {
+	case <-ctx.Done():
+	case ch <- []*targetgroup.Group{tg}:
+	}
+}
+
+func (d *metadataDiscovery) buildTarget(meta *metav1.PartialObjectMetadata) model.LabelSet {
+	prefix := metaLabelPrefix + d.role + "_"
+
+	ls := model.LabelSet{
+		model.LabelName(prefix + "name"): lv(meta.Name),
+		model.LabelName(prefix + "uid"):  lv(string(meta.UID)),
+	}
+	if meta.Namespace != "" {
+		ls[namespaceLabel] = lv(meta.Namespace)
+	}
+
+	switch d.role {
+	case "node":
+		ls[model.AddressLabel] = lv(meta.Name + ":" + kubeletPort)
+	case "service":
+		ls[model.AddressLabel] = lv(meta.Name + "." + meta.Namespace + ".svc")
+	case "pod":
+		if createdBy := metav1.GetControllerOfNoCopy(meta); createdBy != nil {
+			if createdBy.Kind != "" {
+				ls[model.LabelName(prefix+"controller_kind")] = lv(createdBy.Kind)
+			}
+			if createdBy.Name != "" {
+				ls[model.LabelName(prefix+"controller_name")] = lv(createdBy.Name)
+			}
+		}
+	}
+
+	for k, v := range meta.Labels {
+		ln := strutil.SanitizeLabelName(k)
+		ls[model.LabelName(prefix+"label_"+ln)] = lv(v)
+		ls[model.LabelName(prefix+"labelpresent_"+ln)] = presentValue
+	}
+	for k, v := range meta.Annotations {
+		ln := strutil.SanitizeLabelName(k)
+		ls[model.LabelName(prefix+"annotation_"+ln)] = lv(v)
+		ls[model.LabelName(prefix+"annotationpresent_"+ln)] = presentValue
+	}
+	return ls
+}
+
+func lv(s string) model.LabelValue {
+	return model.LabelValue(s)
+}
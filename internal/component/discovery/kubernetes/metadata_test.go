@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/metadata/fake"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestMetadataOnlyConfig(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+	role          = "pod"
+	metadata_only = true
+`), &args)
+	require.NoError(t, err)
+	require.IsType(t, &metadataDiscoveryConfig{}, args.Convert())
+
+	err = syntax.Unmarshal([]byte(`
+	role          = "endpoints"
+	metadata_only = true
+`), &args)
+	require.ErrorContains(t, err, "metadata_only is only supported for the pod, service and node roles")
+
+	err = syntax.Unmarshal([]byte(`
+	role          = "pod"
+	metadata_only = true
+	attach_metadata {
+		node = true
+	}
+`), &args)
+	require.ErrorContains(t, err, "metadata_only can't be used together with attach_metadata")
+}
+
+func TestMetadataDiscovery(t *testing.T) {
+	scheme := fake.NewTestScheme()
+	require.NoError(t, metav1.AddMetaToScheme(scheme))
+
+	controller := true
+	pod := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "web-0",
+			UID:         "abc",
+			Labels:      map[string]string{"app.kubernetes.io/name": "web"},
+			Annotations: map[string]string{"prometheus.io/scrape": "true"},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "StatefulSet", Name: "web", Controller: &controller},
+			},
+		},
+	}
+	client := fake.NewSimpleMetadataClient(scheme, []runtime.Object{pod}...)
+
+	args := Arguments{Role: "pod", MetadataOnly: true}
+	d := newMetadataDiscovery(log.NewNopLogger(), args, client, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []*targetgroup.Group)
+	go d.Run(ctx, ch)
+
+	select {
+	case groups := <-ch:
+		require.Len(t, groups, 1)
+		require.Equal(t, "pod/default/web-0", groups[0].Source)
+		require.Equal(t, []model.LabelSet{{
+			"__meta_kubernetes_namespace":                                  "default",
+			"__meta_kubernetes_pod_name":                                   "web-0",
+			"__meta_kubernetes_pod_uid":                                    "abc",
+			"__meta_kubernetes_pod_controller_kind":                        "StatefulSet",
+			"__meta_kubernetes_pod_controller_name":                        "web",
+			"__meta_kubernetes_pod_label_app_kubernetes_io_name":           "web",
+			"__meta_kubernetes_pod_labelpresent_app_kubernetes_io_name":    "true",
+			"__meta_kubernetes_pod_annotation_prometheus_io_scrape":        "true",
+			"__meta_kubernetes_pod_annotationpresent_prometheus_io_scrape": "true",
+		}}, groups[0].Targets)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for targets")
+	}
+
+	err := client.Resource(metadataRoles["pod"]).Namespace("default").Delete(ctx, "web-0", metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	select {
+	case groups := <-ch:
+		require.Equal(t, []*targetgroup.Group{{Source: "pod/default/web-0"}}, groups)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for deletion")
+	}
+}
+
+func TestMetadataDiscoveryNodeAddress(t *testing.T) {
+	d := newMetadataDiscovery(log.NewNopLogger(), Arguments{Role: "node"}, nil, "")
+	target := d.buildTarget(&metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "def"},
+	})
+	require.Equal(t, model.LabelSet{
+		model.AddressLabel:            "node-1:10250",
+		"__meta_kubernetes_node_name": "node-1",
+		"__meta_kubernetes_node_uid":  "def",
+	}, target)
+}
@@ -0,0 +1,274 @@
+package kubernetes
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	promk8s "github.com/prometheus/prometheus/discovery/kubernetes"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	endpointSliceZoneLabel          = metaLabelPrefix + "endpointslice_endpoint_zone"
+	endpointSliceNodeNameLabel      = metaLabelPrefix + "endpointslice_endpoint_node_name"
+	endpointSliceHintsForZonesLabel = metaLabelPrefix + "endpointslice_endpoint_hints_for_zones"
+)
+
+// topologyDiscoveryConfig wraps the upstream Kubernetes discovery for the
+// endpointslice role and enriches the discovered targets with the topology
+// information of the endpoints, which isn't exposed upstream.
+//
+// The upstream discoverer doesn't expose its informers, so the endpoint slices
+// are watched a second time, which doubles the watch load on the API server
+// and the memory used by the endpoint slice cache. It's only used when
+// attach_metadata endpoint_topology is enabled.
+type topologyDiscoveryConfig struct {
+	*promk8s.SDConfig
+	args Arguments
+}
+
+var _ prom_discovery.Config = (*topologyDiscoveryConfig)(nil)
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *topologyDiscoveryConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	inner, err := c.SDConfig.NewDiscoverer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	kcfg, ownNamespace, err := newRestConfig(opts.Logger, c.args)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(kcfg)
+	if err != nil {
+		return nil, err
+	}
+	return newTopologyDiscovery(opts.Logger, c.args, inner, client, ownNamespace), nil
+}
+
+// endpointTopology is the topology information of a single endpoint of an
+// endpoint slice.
+type endpointTopology struct {
+	zone          string
+	nodeName      string
+	hintsForZones string
+}
+
+// topologyDiscovery decorates the target groups of an upstream endpointslice
+// discoverer with topology labels. It watches endpoint slices on its own and
+// re-sends the last target group of a slice whenever its topology changes.
+type topologyDiscovery struct {
+	logger     log.Logger
+	inner      prom_discovery.Discoverer
+	client     kubernetes.Interface
+	namespaces []string
+	selector   SelectorConfig
+
+	mut sync.Mutex
+	// topology holds the topology of each endpoint address, keyed by the
+	// target group source of the endpoint slice.
+	topology map[string]map[string]endpointTopology
+}
+
+func newTopologyDiscovery(logger log.Logger, args Arguments, inner prom_discovery.Discoverer, client kubernetes.Interface, ownNamespace string) *topologyDiscovery {
+	d := &topologyDiscovery{
+		logger:   logger,
+		inner:    inner,
+		client:   client,
+		topology: make(map[string]map[string]endpointTopology),
+	}
+	for _, s := range args.Selectors {
+		if s.Role == "endpointslice" {
+			d.selector = s
+		}
+	}
+
+	d.namespaces = args.NamespaceDiscovery.Names
+	if args.NamespaceDiscovery.IncludeOwnNamespace && ownNamespace != "" {
+		d.namespaces = append(d.namespaces, ownNamespace)
+	}
+	if len(d.namespaces) == 0 {
+		d.namespaces = []string{metav1.NamespaceAll}
+	}
+	return d
+}
+
+// Run implements discovery.Discoverer.
+func (d *topologyDiscovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	// Sources of slices whose topology changed.
+	updates := make(chan string)
+
+	for _, namespace := range d.namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(d.client, 0,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.LabelSelector = d.selector.Label
+				opts.FieldSelector = d.selector.Field
+			}),
+		)
+		informer := factory.Discovery().V1().EndpointSlices().Informer()
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				d.updateTopology(ctx, updates, obj, false)
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				d.updateTopology(ctx, updates, obj, false)
+			},
+			DeleteFunc: func(obj interface{}) {
+				d.updateTopology(ctx, updates, obj, true)
+			},
+		})
+		if err != nil {
+			level.Error(d.logger).Log("msg", "failed to add endpoint slice event handler", "err", err)
+			return
+		}
+		factory.Start(ctx.Done())
+	}
+
+	innerCh := make(chan []*targetgroup.Group)
+	go d.inner.Run(ctx, innerCh)
+
+	// The latest unmodified target group sent by the inner discoverer for
+	// each source.
+	groups := make(map[string]*targetgroup.Group)
+	for {
+		var send []*targetgroup.Group
+
+		select {
+		case <-ctx.Done():
+			return
+		case tgs := <-innerCh:
+			for _, tg := range tgs {
+				if tg == nil {
+					continue
+				}
+				if len(tg.Targets) == 0 {
+					delete(groups, tg.Source)
+				} else {
+					groups[tg.Source] = tg
+				}
+				send = append(send, d.enrich(tg))
+			}
+		case source := <-updates:
+			tg, ok := groups[source]
+			if !ok {
+				continue
+			}
+			send = append(send, d.enrich(tg))
+		}
+
+		if len(send) == 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- send:
+		}
+	}
+}
+
+func (d *topologyDiscovery) updateTopology(ctx context.Context, updates chan<- string, obj interface{}, deleted bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	eps, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		level.Error(d.logger).Log("msg", "received unexpected object", "object", obj)
+		return
+	}
+
+	source := "endpointslice/" + eps.Namespace + "/" + eps.Name
+
+	d.mut.Lock()
+	if deleted {
+		delete(d.topology, source)
+	} else {
+		d.topology[source] = sliceTopology(eps)
+	}
+	d.mut.Unlock()
+
+	select {
+	case <-ctx.Done():
+	case updates <- source:
+	}
+}
+
+// sliceTopology returns the topology of each address of the endpoints of eps.
+func sliceTopology(eps *discoveryv1.EndpointSlice) map[string]endpointTopology {
+	res := make(map[string]endpointTopology)
+	for _, ep := range eps.Endpoints {
+		var t endpointTopology
+		if ep.Zone != nil {
+			t.zone = *ep.Zone
+		}
+		if ep.NodeName != nil {
+			t.nodeName = *ep.NodeName
+		}
+		if ep.Hints != nil && len(ep.Hints.ForZones) > 0 {
+			zones := make([]string, 0, len(ep.Hints.ForZones))
+			for _, z := range ep.Hints.ForZones {
+				zones = append(zones, z.Name)
+			}
+			sort.Strings(zones)
+			t.hintsForZones = strings.Join(zones, ",")
+		}
+		for _, addr := range ep.Addresses {
+			res[addr] = t
+		}
+	}
+	return res
+}
+
+// enrich returns a copy of tg where each target has the topology labels of its
+// endpoint.
+func (d *topologyDiscovery) enrich(tg *targetgroup.Group) *targetgroup.Group {
+	res := &targetgroup.Group{
+		Source: tg.Source,
+		Labels: tg.Labels,
+	}
+	if len(tg.Targets) == 0 {
+		return res
+	}
+
+	d.mut.Lock()
+	topology := d.topology[tg.Source]
+	d.mut.Unlock()
+
+	res.Targets = make([]model.LabelSet, 0, len(tg.Targets))
+	for _, target := range tg.Targets {
+		target = target.Clone()
+
+		host := string(target[model.AddressLabel])
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if t, ok := topology[host]; ok {
+			if t.zone != "" {
+				target[endpointSliceZoneLabel] = lv(t.zone)
+			}
+			if t.nodeName != "" {
+				target[endpointSliceNodeNameLabel] = lv(t.nodeName)
+			}
+			if t.hintsForZones != "" {
+				target[endpointSliceHintsForZonesLabel] = lv(t.hintsForZones)
+			}
+		}
+		res.Targets = append(res.Targets, target)
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/stretchr/testify/require"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// staticDiscoverer sends a fixed set of target groups once.
+type staticDiscoverer []*targetgroup.Group
+
+func (s staticDiscoverer) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	select {
+	case <-ctx.Done():
+	case ch <- s:
+	}
+	<-ctx.Done()
+}
+
+func TestTopologyDiscovery(t *testing.T) {
+	zone := "eu-west-1a"
+	node := "node-1"
+	client := fake.NewSimpleClientset(&discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-abcde"},
+		Endpoints: []discoveryv1.Endpoint{{
+			Addresses: []string{"10.0.0.1"},
+			Zone:      &zone,
+			NodeName:  &node,
+			Hints: &discoveryv1.EndpointHints{
+				ForZones: []discoveryv1.ForZone{{Name: "eu-west-1b"}, {Name: "eu-west-1a"}},
+			},
+		}},
+	})
+
+	inner := staticDiscoverer{{
+		Source: "endpointslice/default/web-abcde",
+		Labels: model.LabelSet{namespaceLabel: "default"},
+		Targets: []model.LabelSet{
+			{model.AddressLabel: "10.0.0.1:8080"},
+			{model.AddressLabel: "10.0.0.2:8080"},
+		},
+	}}
+
+	d := newTopologyDiscovery(log.NewNopLogger(), Arguments{Role: "endpointslice"}, inner, client, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []*targetgroup.Group)
+	go d.Run(ctx, ch)
+
+	// Depending on whether the endpoint slice informer or the inner discoverer
+	// is faster, the first target groups may not be enriched yet.
+	require.Eventually(t, func() bool {
+		var groups []*targetgroup.Group
+		select {
+		case groups = <-ch:
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+		require.Len(t, groups, 1)
+		require.Len(t, groups[0].Targets, 2)
+		require.Equal(t, model.LabelSet{model.AddressLabel: "10.0.0.2:8080"}, groups[0].Targets[1])
+
+		return groups[0].Targets[0].Equal(model.LabelSet{
+			model.AddressLabel:              "10.0.0.1:8080",
+			endpointSliceZoneLabel:          "eu-west-1a",
+			endpointSliceNodeNameLabel:      "node-1",
+			endpointSliceHintsForZonesLabel: "eu-west-1a,eu-west-1b",
+		})
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -31,6 +31,7 @@ type Arguments struct {
 	Server          string        `alloy:"server,attr"`
 	RefreshInterval time.Duration `alloy:"refresh_interval,attr,optional"`
 	FetchTimeout    time.Duration `alloy:"fetch_timeout,attr,optional"`
+	ClientID        string        `alloy:"client_id,attr,optional"`
 
 	HTTPClientConfig config.HTTPClientConfig `alloy:",squash"`
 }
@@ -65,6 +66,7 @@ func (args Arguments) Convert() discovery.DiscovererConfig {
 		Server:          args.Server,
 		RefreshInterval: model.Duration(args.RefreshInterval),
 		FetchTimeout:    model.Duration(args.FetchTimeout),
+		ClientID:        args.ClientID,
 
 		HTTPClientConfig: *(args.HTTPClientConfig.Convert()),
 	}
@@ -46,6 +46,7 @@ func TestConvert(t *testing.T) {
 		Server:          "srv",
 		RefreshInterval: 30 * time.Second,
 		FetchTimeout:    10 * time.Second,
+		ClientID:        "alloy",
 		HTTPClientConfig: config.HTTPClientConfig{
 			BasicAuth: &config.BasicAuth{
 				Username: "username",
@@ -58,6 +59,7 @@ func TestConvert(t *testing.T) {
 	require.Equal(t, "srv", promArgs.Server)
 	require.Equal(t, model.Duration(30*time.Second), promArgs.RefreshInterval)
 	require.Equal(t, model.Duration(10*time.Second), promArgs.FetchTimeout)
+	require.Equal(t, "alloy", promArgs.ClientID)
 	require.Equal(t, "username", promArgs.HTTPClientConfig.BasicAuth.Username)
 	require.Equal(t, promConfig.Secret("pass"), promArgs.HTTPClientConfig.BasicAuth.Password)
 }
@@ -1,7 +1,9 @@
 package nerve
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -44,6 +46,17 @@ func (args *Arguments) SetToDefault() {
 
 // Validate implements syntax.Validator.
 func (args *Arguments) Validate() error {
+	if len(args.Servers) == 0 {
+		return errors.New("discovery.nerve config must contain at least one Zookeeper server")
+	}
+	if len(args.Paths) == 0 {
+		return errors.New("discovery.nerve config must contain at least one path")
+	}
+	for _, path := range args.Paths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("discovery.nerve config paths must begin with '/': %s", path)
+		}
+	}
 	if args.Timeout <= 0 {
 		return fmt.Errorf("timeout must be greater than 0")
 	}
@@ -32,4 +32,16 @@ func TestBadAlloyConfig(t *testing.T) {
 `
 
 	require.ErrorContains(t, syntax.Unmarshal([]byte(alloyConfig), &args), "timeout must be greater than 0")
+
+	alloyConfig = `
+	servers = []
+	paths   = ["/nerve/services/your_http_service/services"]
+`
+	require.ErrorContains(t, syntax.Unmarshal([]byte(alloyConfig), &args), "must contain at least one Zookeeper server")
+
+	alloyConfig = `
+	servers = ["1.2.3.4"]
+	paths   = ["nerve/services/your_http_service/services"]
+`
+	require.ErrorContains(t, syntax.Unmarshal([]byte(alloyConfig), &args), "paths must begin with '/'")
 }
@@ -0,0 +1,65 @@
+package netbox
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+
+	"github.com/grafana/alloy/internal/component"
+)
+
+type netboxDiscoveryConfig struct {
+	args Arguments
+	opts component.Options
+}
+
+var _ prom_discovery.Config = (*netboxDiscoveryConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (n *netboxDiscoveryConfig) Name() string {
+	return "netbox"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (n *netboxDiscoveryConfig) NewDiscoverer(discOpts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := discOpts.Metrics.(*netboxMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	netboxDiscovery, err := NewNetboxDiscovery(n.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              n.opts.Logger,
+		Mech:                "netbox",
+		Interval:            n.args.RefreshInterval,
+		RefreshF:            netboxDiscovery.Refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*netboxDiscoveryConfig) NewDiscovererMetrics(_ prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	return &netboxMetrics{
+		refreshMetrics: rmi,
+	}
+}
+
+var _ prom_discovery.DiscovererMetrics = (*netboxMetrics)(nil)
+
+type netboxMetrics struct {
+	refreshMetrics prom_discovery.RefreshMetricsInstantiator
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *netboxMetrics) Register() error {
+	return nil
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *netboxMetrics) Unregister() {}
@@ -0,0 +1,404 @@
+// Package netbox implements the discovery.netbox component.
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	commonConfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+const (
+	metaLabelPrefix          = model.MetaLabelPrefix + "netbox_"
+	objectTypeLabel          = metaLabelPrefix + "object_type"
+	idLabel                  = metaLabelPrefix + "id"
+	nameLabel                = metaLabelPrefix + "name"
+	statusLabel              = metaLabelPrefix + "status"
+	siteLabel                = metaLabelPrefix + "site"
+	roleLabel                = metaLabelPrefix + "role"
+	tenantLabel              = metaLabelPrefix + "tenant"
+	platformLabel            = metaLabelPrefix + "platform"
+	deviceTypeLabel          = metaLabelPrefix + "device_type"
+	serialLabel              = metaLabelPrefix + "serial"
+	clusterLabel             = metaLabelPrefix + "cluster"
+	primaryIPLabel           = metaLabelPrefix + "primary_ip"
+	primaryIP4Label          = metaLabelPrefix + "primary_ip4"
+	primaryIP6Label          = metaLabelPrefix + "primary_ip6"
+	tagsLabel                = metaLabelPrefix + "tags"
+	customFieldLabelPrefix   = metaLabelPrefix + "custom_field_"
+	objectTypeDevice         = "device"
+	objectTypeVirtualMachine = "virtual_machine"
+)
+
+var (
+	validObjectTypes = []string{objectTypeDevice, objectTypeVirtualMachine}
+
+	objectTypePaths = map[string]string{
+		objectTypeDevice:         "/api/dcim/devices/",
+		objectTypeVirtualMachine: "/api/virtualization/virtual-machines/",
+	}
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "discovery.netbox",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   discovery.Exports{},
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the discovery.netbox component.
+type Arguments struct {
+	URL              string                  `alloy:"url,attr"`
+	ObjectTypes      []string                `alloy:"object_types,attr,optional"`
+	Sites            []string                `alloy:"sites,attr,optional"`
+	Roles            []string                `alloy:"roles,attr,optional"`
+	Tenants          []string                `alloy:"tenants,attr,optional"`
+	Tags             []string                `alloy:"tags,attr,optional"`
+	Status           []string                `alloy:"status,attr,optional"`
+	Port             int                     `alloy:"port,attr,optional"`
+	PageSize         int                     `alloy:"page_size,attr,optional"`
+	RefreshInterval  time.Duration           `alloy:"refresh_interval,attr,optional"`
+	HTTPClientConfig config.HTTPClientConfig `alloy:",squash"`
+}
+
+// DefaultArguments holds default values for Arguments.
+var DefaultArguments = Arguments{
+	ObjectTypes:      validObjectTypes,
+	Status:           []string{"active"},
+	Port:             9100,
+	PageSize:         1000,
+	RefreshInterval:  5 * time.Minute,
+	HTTPClientConfig: config.DefaultHTTPClientConfig,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	parsedURL, err := url.Parse(args.URL)
+	if err != nil {
+		return err
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL scheme must be 'http' or 'https'")
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("host is missing in URL")
+	}
+
+	if len(args.ObjectTypes) == 0 {
+		return fmt.Errorf("object_types must not be empty")
+	}
+	for _, t := range args.ObjectTypes {
+		if !slices.Contains(validObjectTypes, t) {
+			return fmt.Errorf("invalid object type %q, must be one of %s", t, strings.Join(validObjectTypes, ", "))
+		}
+	}
+
+	if args.Port <= 0 || args.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	if args.PageSize <= 0 {
+		return fmt.Errorf("page_size must be greater than 0")
+	}
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+
+	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
+	return args.HTTPClientConfig.Validate()
+}
+
+// New returns a new instance of a discovery.netbox component.
+func New(opts component.Options, args Arguments) (*discovery.Component, error) {
+	return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+		return &netboxDiscoveryConfig{
+			args: args.(Arguments),
+			opts: opts,
+		}, nil
+	})
+}
+
+// Discovery retrieves devices and virtual machines from the NetBox REST API.
+type Discovery struct {
+	client      *http.Client
+	baseURL     string
+	objectTypes []string
+	query       url.Values
+	port        int
+}
+
+// NewNetboxDiscovery creates a new Discovery from the provided arguments.
+func NewNetboxDiscovery(args Arguments) (*Discovery, error) {
+	rt, err := commonConfig.NewRoundTripperFromConfig(*args.HTTPClientConfig.Convert(), "netbox_sd")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Discovery{
+		client: &http.Client{
+			Transport: rt,
+			Timeout:   30 * time.Second,
+		},
+		baseURL:     strings.TrimSuffix(args.URL, "/"),
+		objectTypes: args.ObjectTypes,
+		query:       buildQuery(args),
+		port:        args.Port,
+	}, nil
+}
+
+// buildQuery converts the filters from args into NetBox query parameters.
+// Repeating a parameter is treated as a logical OR for sites, roles, tenants
+// and status and as a logical AND for tags.
+func buildQuery(args Arguments) url.Values {
+	q := url.Values{}
+	for _, v := range args.Sites {
+		q.Add("site", v)
+	}
+	for _, v := range args.Roles {
+		q.Add("role", v)
+	}
+	for _, v := range args.Tenants {
+		q.Add("tenant", v)
+	}
+	for _, v := range args.Tags {
+		q.Add("tag", v)
+	}
+	for _, v := range args.Status {
+		q.Add("status", v)
+	}
+	q.Set("limit", strconv.Itoa(args.PageSize))
+	return q
+}
+
+type nestedObject struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type choice struct {
+	Value string `json:"value"`
+}
+
+type ipAddress struct {
+	Address string `json:"address"`
+}
+
+// object is the subset of fields shared by NetBox devices and virtual
+// machines that is used for discovery.
+type object struct {
+	ID           int                        `json:"id"`
+	Name         string                     `json:"name"`
+	Status       *choice                    `json:"status"`
+	Site         *nestedObject              `json:"site"`
+	Role         *nestedObject              `json:"role"`
+	DeviceRole   *nestedObject              `json:"device_role"`
+	Tenant       *nestedObject              `json:"tenant"`
+	Platform     *nestedObject              `json:"platform"`
+	DeviceType   *nestedObject              `json:"device_type"`
+	Cluster      *nestedObject              `json:"cluster"`
+	Serial       string                     `json:"serial"`
+	PrimaryIP    *ipAddress                 `json:"primary_ip"`
+	PrimaryIP4   *ipAddress                 `json:"primary_ip4"`
+	PrimaryIP6   *ipAddress                 `json:"primary_ip6"`
+	Tags         []nestedObject             `json:"tags"`
+	CustomFields map[string]json.RawMessage `json:"custom_fields"`
+}
+
+type listResponse struct {
+	Next    *string  `json:"next"`
+	Results []object `json:"results"`
+}
+
+// Refresh queries the NetBox API and returns one target group per object
+// type.
+func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	var groups []*targetgroup.Group
+	for _, objectType := range d.objectTypes {
+		objects, err := d.list(ctx, objectType)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, d.buildTargetGroup(objectType, objects))
+	}
+	return groups, nil
+}
+
+// list retrieves all objects of the given type, following pagination.
+func (d *Discovery) list(ctx context.Context, objectType string) ([]object, error) {
+	next := d.baseURL + objectTypePaths[objectType] + "?" + d.query.Encode()
+
+	var objects []object
+	for next != "" {
+		page, err := d.get(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Results...)
+
+		next = ""
+		if page.Next != nil {
+			next = *page.Next
+		}
+	}
+	return objects, nil
+}
+
+func (d *Discovery) get(ctx context.Context, u string) (*listResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating netbox request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending netbox request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response from netbox: %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var page listResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response body: %w", err)
+	}
+	return &page, nil
+}
+
+func (d *Discovery) buildTargetGroup(objectType string, objects []object) *targetgroup.Group {
+	tg := &targetgroup.Group{
+		Source: objectType,
+	}
+	for _, o := range objects {
+		// Objects without a primary IP address can't be scraped.
+		if o.PrimaryIP == nil || o.PrimaryIP.Address == "" {
+			continue
+		}
+		tg.Targets = append(tg.Targets, d.objectLabels(objectType, o))
+	}
+	return tg
+}
+
+func (d *Discovery) objectLabels(objectType string, o object) model.LabelSet {
+	ls := model.LabelSet{
+		model.AddressLabel: lv(net.JoinHostPort(stripPrefixLength(o.PrimaryIP.Address), strconv.Itoa(d.port))),
+		objectTypeLabel:    lv(objectType),
+		idLabel:            lv(strconv.Itoa(o.ID)),
+		nameLabel:          lv(o.Name),
+		primaryIPLabel:     lv(stripPrefixLength(o.PrimaryIP.Address)),
+	}
+
+	if o.Status != nil {
+		ls[statusLabel] = lv(o.Status.Value)
+	}
+	// NetBox releases before 3.6 return the role of devices as device_role.
+	role := o.Role
+	if role == nil {
+		role = o.DeviceRole
+	}
+	setSlug(ls, siteLabel, o.Site)
+	setSlug(ls, roleLabel, role)
+	setSlug(ls, tenantLabel, o.Tenant)
+	setSlug(ls, platformLabel, o.Platform)
+	setSlug(ls, deviceTypeLabel, o.DeviceType)
+	if o.Cluster != nil {
+		ls[clusterLabel] = lv(o.Cluster.Name)
+	}
+	if o.Serial != "" {
+		ls[serialLabel] = lv(o.Serial)
+	}
+	if o.PrimaryIP4 != nil {
+		ls[primaryIP4Label] = lv(stripPrefixLength(o.PrimaryIP4.Address))
+	}
+	if o.PrimaryIP6 != nil {
+		ls[primaryIP6Label] = lv(stripPrefixLength(o.PrimaryIP6.Address))
+	}
+
+	if len(o.Tags) > 0 {
+		tags := make([]string, 0, len(o.Tags))
+		for _, t := range o.Tags {
+			tags = append(tags, t.Slug)
+		}
+		// Surround the joined tags with separators so that relabeling rules
+		// can match individual tags with a regular expression such as
+		// ".*,production,.*".
+		ls[tagsLabel] = lv("," + strings.Join(tags, ",") + ",")
+	}
+
+	for name, raw := range o.CustomFields {
+		if value, ok := customFieldValue(raw); ok {
+			ls[model.LabelName(customFieldLabelPrefix+strutil.SanitizeLabelName(name))] = lv(value)
+		}
+	}
+	return ls
+}
+
+func setSlug(ls model.LabelSet, name model.LabelName, o *nestedObject) {
+	if o != nil && o.Slug != "" {
+		ls[name] = lv(o.Slug)
+	}
+}
+
+// customFieldValue converts the value of a custom field into a label value.
+// Only scalar values are supported; empty, object and list values are
+// ignored.
+func customFieldValue(raw json.RawMessage) (string, bool) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", false
+	}
+	switch v := v.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// stripPrefixLength removes the prefix length from an address in CIDR
+// notation, such as 192.0.2.1/24.
+func stripPrefixLength(addr string) string {
+	host, _, _ := strings.Cut(addr, "/")
+	return host
+}
+
+func lv(s string) model.LabelValue {
+	return model.LabelValue(s)
+}
@@ -0,0 +1,146 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestAlloyConfig(t *testing.T) {
+	var exampleAlloyConfig = `
+	url          = "https://netbox.example.com"
+	object_types = ["device"]
+	sites        = ["ams1", "fra1"]
+	roles        = ["server"]
+	tags         = ["monitored"]
+	port         = 9273
+
+	authorization {
+		type        = "Token"
+		credentials = "0123456789abcdef"
+	}
+`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"device"}, args.ObjectTypes)
+	require.Equal(t, []string{"ams1", "fra1"}, args.Sites)
+	require.Equal(t, []string{"active"}, args.Status)
+	require.Equal(t, 9273, args.Port)
+	require.Equal(t, 5*time.Minute, args.RefreshInterval)
+
+	q := buildQuery(args)
+	require.Equal(t, []string{"ams1", "fra1"}, q["site"])
+	require.Equal(t, []string{"server"}, q["role"])
+	require.Equal(t, []string{"monitored"}, q["tag"])
+	require.Equal(t, []string{"active"}, q["status"])
+	require.Equal(t, "1000", q.Get("limit"))
+}
+
+func TestBadAlloyConfig(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+	url          = "https://netbox.example.com"
+	object_types = ["interface"]
+`), &args)
+	require.ErrorContains(t, err, `invalid object type "interface"`)
+
+	err = syntax.Unmarshal([]byte(`url = "netbox.example.com"`), &args)
+	require.ErrorContains(t, err, "URL scheme must be 'http' or 'https'")
+}
+
+func TestRefresh(t *testing.T) {
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Token secret", r.Header.Get("Authorization"))
+		require.Equal(t, "ams1", r.URL.Query().Get("site"))
+
+		switch {
+		case r.URL.Path == "/api/dcim/devices/" && r.URL.Query().Get("offset") == "":
+			fmt.Fprintf(w, `{"next": "%s/api/dcim/devices/?site=ams1&offset=1", "results": [
+				{
+					"id": 1, "name": "sw1", "status": {"value": "active"},
+					"site": {"slug": "ams1"}, "device_role": {"slug": "switch"},
+					"device_type": {"slug": "ex4300"}, "serial": "ABC123",
+					"primary_ip": {"address": "10.0.0.1/24"}, "primary_ip4": {"address": "10.0.0.1/24"},
+					"tags": [{"slug": "core"}, {"slug": "monitored"}],
+					"custom_fields": {"rack_unit": 42, "owner": "network", "contacts": [], "empty": null}
+				}
+			]}`, srvURL)
+		case r.URL.Path == "/api/dcim/devices/":
+			_, _ = w.Write([]byte(`{"next": null, "results": [
+				{"id": 2, "name": "unaddressed", "status": {"value": "active"}}
+			]}`))
+		case r.URL.Path == "/api/virtualization/virtual-machines/":
+			_, _ = w.Write([]byte(`{"next": null, "results": [
+				{
+					"id": 7, "name": "vm1", "status": {"value": "active"},
+					"role": {"slug": "web"}, "tenant": {"slug": "acme"}, "cluster": {"name": "prod"},
+					"primary_ip": {"address": "2001:db8::7/64"}, "primary_ip6": {"address": "2001:db8::7/64"}
+				}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(fmt.Sprintf(`
+	url   = %q
+	sites = ["ams1"]
+	authorization {
+		type        = "Token"
+		credentials = "secret"
+	}
+`, srv.URL)), &args))
+
+	d, err := NewNetboxDiscovery(args)
+	require.NoError(t, err)
+
+	groups, err := d.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	require.Equal(t, "device", groups[0].Source)
+	require.Equal(t, []model.LabelSet{{
+		model.AddressLabel:                     "10.0.0.1:9100",
+		objectTypeLabel:                        "device",
+		idLabel:                                "1",
+		nameLabel:                              "sw1",
+		statusLabel:                            "active",
+		siteLabel:                              "ams1",
+		roleLabel:                              "switch",
+		deviceTypeLabel:                        "ex4300",
+		serialLabel:                            "ABC123",
+		primaryIPLabel:                         "10.0.0.1",
+		primaryIP4Label:                        "10.0.0.1",
+		tagsLabel:                              ",core,monitored,",
+		"__meta_netbox_custom_field_rack_unit": "42",
+		"__meta_netbox_custom_field_owner":     "network",
+	}}, groups[0].Targets)
+
+	require.Equal(t, "virtual_machine", groups[1].Source)
+	require.Equal(t, []model.LabelSet{{
+		model.AddressLabel: "[2001:db8::7]:9100",
+		objectTypeLabel:    "virtual_machine",
+		idLabel:            "7",
+		nameLabel:          "vm1",
+		statusLabel:        "active",
+		roleLabel:          "web",
+		tenantLabel:        "acme",
+		clusterLabel:       "prod",
+		primaryIPLabel:     "2001:db8::7",
+		primaryIP6Label:    "2001:db8::7",
+	}}, groups[1].Targets)
+}
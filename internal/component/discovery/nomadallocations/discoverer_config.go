@@ -0,0 +1,65 @@
+package nomadallocations
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+
+	"github.com/grafana/alloy/internal/component"
+)
+
+type nomadAllocationsDiscoveryConfig struct {
+	args Arguments
+	opts component.Options
+}
+
+var _ prom_discovery.Config = (*nomadAllocationsDiscoveryConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (p *nomadAllocationsDiscoveryConfig) Name() string {
+	return "nomad_allocations"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (p *nomadAllocationsDiscoveryConfig) NewDiscoverer(discOpts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := discOpts.Metrics.(*nomadAllocationsMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	nomadAllocationsDiscovery, err := NewNomadAllocationsDiscovery(p.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              p.opts.Logger,
+		Mech:                "nomad_allocations",
+		Interval:            p.args.RefreshInterval,
+		RefreshF:            nomadAllocationsDiscovery.Refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*nomadAllocationsDiscoveryConfig) NewDiscovererMetrics(_ prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	return &nomadAllocationsMetrics{
+		refreshMetrics: rmi,
+	}
+}
+
+var _ prom_discovery.DiscovererMetrics = (*nomadAllocationsMetrics)(nil)
+
+type nomadAllocationsMetrics struct {
+	refreshMetrics prom_discovery.RefreshMetricsInstantiator
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *nomadAllocationsMetrics) Register() error {
+	return nil
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *nomadAllocationsMetrics) Unregister() {}
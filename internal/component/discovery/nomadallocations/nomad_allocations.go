@@ -0,0 +1,324 @@
+// Package nomadallocations implements the discovery.nomad_allocations
+// component.
+package nomadallocations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	commonConfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+const (
+	metaLabelPrefix    = model.MetaLabelPrefix + "nomad_"
+	allocIDLabel       = metaLabelPrefix + "alloc_id"
+	allocNameLabel     = metaLabelPrefix + "alloc_name"
+	namespaceLabel     = metaLabelPrefix + "namespace"
+	jobIDLabel         = metaLabelPrefix + "job_id"
+	jobTypeLabel       = metaLabelPrefix + "job_type"
+	taskGroupLabel     = metaLabelPrefix + "task_group"
+	taskLabel          = metaLabelPrefix + "task"
+	taskStateLabel     = metaLabelPrefix + "task_state"
+	nodeIDLabel        = metaLabelPrefix + "node_id"
+	nodeNameLabel      = metaLabelPrefix + "node_name"
+	clientStatusLabel  = metaLabelPrefix + "client_status"
+	desiredStatusLabel = metaLabelPrefix + "desired_status"
+	portLabelLabel     = metaLabelPrefix + "port_label"
+	portValueLabel     = metaLabelPrefix + "port_value"
+	portToLabel        = metaLabelPrefix + "port_to"
+	portHostIPLabel    = metaLabelPrefix + "port_host_ip"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "discovery.nomad_allocations",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   discovery.Exports{},
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the discovery.nomad_allocations component.
+type Arguments struct {
+	Server           string                  `alloy:"server,attr,optional"`
+	Namespace        string                  `alloy:"namespace,attr,optional"`
+	Region           string                  `alloy:"region,attr,optional"`
+	AllowStale       bool                    `alloy:"allow_stale,attr,optional"`
+	ClientStatus     []string                `alloy:"client_status,attr,optional"`
+	RefreshInterval  time.Duration           `alloy:"refresh_interval,attr,optional"`
+	HTTPClientConfig config.HTTPClientConfig `alloy:",squash"`
+}
+
+// DefaultArguments holds default values for Arguments.
+var DefaultArguments = Arguments{
+	Server:           "http://localhost:4646",
+	Namespace:        "default",
+	Region:           "global",
+	AllowStale:       true,
+	ClientStatus:     []string{"running"},
+	RefreshInterval:  60 * time.Second,
+	HTTPClientConfig: config.DefaultHTTPClientConfig,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if strings.TrimSpace(args.Server) == "" {
+		return fmt.Errorf("nomad allocations discovery requires a server address")
+	}
+	if _, err := url.Parse(args.Server); err != nil {
+		return fmt.Errorf("parsing server attribute: %w", err)
+	}
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+
+	return args.HTTPClientConfig.Validate()
+}
+
+// New returns a new instance of a discovery.nomad_allocations component.
+func New(opts component.Options, args Arguments) (*discovery.Component, error) {
+	return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+		return &nomadAllocationsDiscoveryConfig{
+			args: args.(Arguments),
+			opts: opts,
+		}, nil
+	})
+}
+
+// Discovery retrieves allocations from the Nomad allocations API.
+type Discovery struct {
+	client       *http.Client
+	url          string
+	clientStatus []string
+}
+
+// NewNomadAllocationsDiscovery creates a new Discovery from the provided
+// arguments.
+func NewNomadAllocationsDiscovery(args Arguments) (*Discovery, error) {
+	rt, err := commonConfig.NewRoundTripperFromConfig(*args.HTTPClientConfig.Convert(), "nomad_allocations_sd")
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("namespace", args.Namespace)
+	q.Set("region", args.Region)
+	// Allocated resources aren't part of the allocation list by default, but
+	// they are required to know the ports of the allocations.
+	q.Set("resources", "true")
+	if args.AllowStale {
+		q.Set("stale", "")
+	}
+
+	return &Discovery{
+		client: &http.Client{
+			Transport: rt,
+			Timeout:   30 * time.Second,
+		},
+		url:          strings.TrimSuffix(args.Server, "/") + "/v1/allocations?" + q.Encode(),
+		clientStatus: args.ClientStatus,
+	}, nil
+}
+
+// allocation is a single entry of the /v1/allocations API response.
+type allocation struct {
+	ID                 string               `json:"ID"`
+	Name               string               `json:"Name"`
+	Namespace          string               `json:"Namespace"`
+	NodeID             string               `json:"NodeID"`
+	NodeName           string               `json:"NodeName"`
+	JobID              string               `json:"JobID"`
+	JobType            string               `json:"JobType"`
+	TaskGroup          string               `json:"TaskGroup"`
+	ClientStatus       string               `json:"ClientStatus"`
+	DesiredStatus      string               `json:"DesiredStatus"`
+	TaskStates         map[string]taskState `json:"TaskStates"`
+	AllocatedResources *allocatedResources  `json:"AllocatedResources"`
+}
+
+type taskState struct {
+	State string `json:"State"`
+}
+
+type allocatedResources struct {
+	Tasks  map[string]taskResources `json:"Tasks"`
+	Shared sharedResources          `json:"Shared"`
+}
+
+type taskResources struct {
+	Networks []network `json:"Networks"`
+}
+
+type sharedResources struct {
+	Ports []port `json:"Ports"`
+}
+
+// network holds the ports of task level networks, which are deprecated in
+// favor of group level networks but still supported by Nomad.
+type network struct {
+	IP            string        `json:"IP"`
+	ReservedPorts []networkPort `json:"ReservedPorts"`
+	DynamicPorts  []networkPort `json:"DynamicPorts"`
+}
+
+type networkPort struct {
+	Label string `json:"Label"`
+	Value int    `json:"Value"`
+	To    int    `json:"To"`
+}
+
+// port is a port of a group level network.
+type port struct {
+	Label  string `json:"Label"`
+	Value  int    `json:"Value"`
+	To     int    `json:"To"`
+	HostIP string `json:"HostIP"`
+}
+
+// Refresh queries the Nomad API and returns the discovered target groups.
+func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating nomad allocations request: %w", err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending nomad allocations request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response from nomad: %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var allocs []allocation
+	if err := json.Unmarshal(body, &allocs); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response body: %w", err)
+	}
+
+	tg := &targetgroup.Group{
+		Source: "nomad_allocations",
+	}
+	for _, a := range allocs {
+		if len(d.clientStatus) > 0 && !slices.Contains(d.clientStatus, a.ClientStatus) {
+			continue
+		}
+		tg.Targets = append(tg.Targets, allocationTargets(a)...)
+	}
+	return []*targetgroup.Group{tg}, nil
+}
+
+// allocationTargets returns one target per task and port of the allocation.
+// Tasks without any port get a single target without an address.
+func allocationTargets(a allocation) []model.LabelSet {
+	common := model.LabelSet{
+		allocIDLabel:       lv(a.ID),
+		allocNameLabel:     lv(a.Name),
+		namespaceLabel:     lv(a.Namespace),
+		jobIDLabel:         lv(a.JobID),
+		jobTypeLabel:       lv(a.JobType),
+		taskGroupLabel:     lv(a.TaskGroup),
+		nodeIDLabel:        lv(a.NodeID),
+		nodeNameLabel:      lv(a.NodeName),
+		clientStatusLabel:  lv(a.ClientStatus),
+		desiredStatusLabel: lv(a.DesiredStatus),
+	}
+
+	// Task states are only known once the allocation was placed, while
+	// allocated resources are known as soon as the allocation exists.
+	tasks := make(map[string]struct{})
+	for name := range a.TaskStates {
+		tasks[name] = struct{}{}
+	}
+	if a.AllocatedResources != nil {
+		for name := range a.AllocatedResources.Tasks {
+			tasks[name] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var targets []model.LabelSet
+	for _, name := range names {
+		task := common.Clone()
+		task[taskLabel] = lv(name)
+		if s, ok := a.TaskStates[name]; ok {
+			task[taskStateLabel] = lv(s.State)
+		}
+
+		ports := taskPorts(a.AllocatedResources, name)
+		if len(ports) == 0 {
+			targets = append(targets, task)
+			continue
+		}
+		for _, p := range ports {
+			target := task.Clone()
+			target[portLabelLabel] = lv(p.Label)
+			target[portValueLabel] = lv(strconv.Itoa(p.Value))
+			if p.To > 0 {
+				target[portToLabel] = lv(strconv.Itoa(p.To))
+			}
+			if p.HostIP != "" {
+				target[portHostIPLabel] = lv(p.HostIP)
+				target[model.AddressLabel] = lv(net.JoinHostPort(p.HostIP, strconv.Itoa(p.Value)))
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// taskPorts returns the ports available to a task, which are the ports of the
+// group network shared by all tasks of the allocation and the ports of the
+// networks of the task itself.
+func taskPorts(res *allocatedResources, task string) []port {
+	if res == nil {
+		return nil
+	}
+
+	ports := slices.Clone(res.Shared.Ports)
+	for _, n := range res.Tasks[task].Networks {
+		for _, p := range slices.Concat(n.ReservedPorts, n.DynamicPorts) {
+			ports = append(ports, port{Label: p.Label, Value: p.Value, To: p.To, HostIP: n.IP})
+		}
+	}
+	return ports
+}
+
+func lv(s string) model.LabelValue {
+	return model.LabelValue(s)
+}
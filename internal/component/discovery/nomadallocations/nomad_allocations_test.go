@@ -0,0 +1,137 @@
+package nomadallocations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestAlloyConfig(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+	server           = "http://nomad.example.com:4646"
+	namespace        = "*"
+	client_status    = ["running", "pending"]
+	refresh_interval = "30s"
+`), &args)
+	require.NoError(t, err)
+	require.Equal(t, "global", args.Region)
+	require.True(t, args.AllowStale)
+	require.Equal(t, 30*time.Second, args.RefreshInterval)
+}
+
+func TestBadAlloyConfig(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`server = ""`), &args)
+	require.ErrorContains(t, err, "nomad allocations discovery requires a server address")
+}
+
+func TestRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/allocations", r.URL.Path)
+		require.Equal(t, "default", r.URL.Query().Get("namespace"))
+		require.Equal(t, "true", r.URL.Query().Get("resources"))
+		_, _ = w.Write([]byte(`[
+			{
+				"ID": "a1", "Name": "web.app[0]", "Namespace": "default", "NodeID": "n1", "NodeName": "client-1",
+				"JobID": "web", "JobType": "service", "TaskGroup": "app",
+				"ClientStatus": "running", "DesiredStatus": "run",
+				"TaskStates": {"server": {"State": "running"}, "log-shipper": {"State": "running"}},
+				"AllocatedResources": {
+					"Tasks": {"server": {}, "log-shipper": {}},
+					"Shared": {"Ports": [{"Label": "http", "Value": 23456, "To": 8080, "HostIP": "10.0.0.1"}]}
+				}
+			},
+			{
+				"ID": "a2", "Name": "batch.work[0]", "Namespace": "default", "NodeID": "n1", "NodeName": "client-1",
+				"JobID": "batch", "JobType": "batch", "TaskGroup": "work",
+				"ClientStatus": "complete", "DesiredStatus": "run",
+				"TaskStates": {"worker": {"State": "dead"}}
+			},
+			{
+				"ID": "a3", "Name": "legacy.app[0]", "Namespace": "default", "NodeID": "n2", "NodeName": "client-2",
+				"JobID": "legacy", "JobType": "system", "TaskGroup": "app",
+				"ClientStatus": "running", "DesiredStatus": "run",
+				"TaskStates": {"agent": {"State": "running"}},
+				"AllocatedResources": {
+					"Tasks": {"agent": {"Networks": [{"IP": "10.0.0.2", "DynamicPorts": [{"Label": "metrics", "Value": 31000}]}]}}
+				}
+			}
+		]`))
+	}))
+	defer srv.Close()
+
+	args := DefaultArguments
+	args.Server = srv.URL
+
+	d, err := NewNomadAllocationsDiscovery(args)
+	require.NoError(t, err)
+
+	groups, err := d.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	web := model.LabelSet{
+		allocIDLabel:       "a1",
+		allocNameLabel:     "web.app[0]",
+		namespaceLabel:     "default",
+		jobIDLabel:         "web",
+		jobTypeLabel:       "service",
+		taskGroupLabel:     "app",
+		nodeIDLabel:        "n1",
+		nodeNameLabel:      "client-1",
+		clientStatusLabel:  "running",
+		desiredStatusLabel: "run",
+		taskStateLabel:     "running",
+		portLabelLabel:     "http",
+		portValueLabel:     "23456",
+		portToLabel:        "8080",
+		portHostIPLabel:    "10.0.0.1",
+		model.AddressLabel: "10.0.0.1:23456",
+	}
+	logShipper := web.Clone()
+	logShipper[taskLabel] = "log-shipper"
+	server := web.Clone()
+	server[taskLabel] = "server"
+
+	require.Equal(t, []model.LabelSet{
+		logShipper,
+		server,
+		{
+			allocIDLabel:       "a3",
+			allocNameLabel:     "legacy.app[0]",
+			namespaceLabel:     "default",
+			jobIDLabel:         "legacy",
+			jobTypeLabel:       "system",
+			taskGroupLabel:     "app",
+			taskLabel:          "agent",
+			nodeIDLabel:        "n2",
+			nodeNameLabel:      "client-2",
+			clientStatusLabel:  "running",
+			desiredStatusLabel: "run",
+			taskStateLabel:     "running",
+			portLabelLabel:     "metrics",
+			portValueLabel:     "31000",
+			portHostIPLabel:    "10.0.0.2",
+			model.AddressLabel: "10.0.0.2:31000",
+		},
+	}, groups[0].Targets)
+}
+
+func TestAllocationTargetsWithoutPorts(t *testing.T) {
+	targets := allocationTargets(allocation{
+		ID:         "a1",
+		TaskGroup:  "work",
+		TaskStates: map[string]taskState{"worker": {State: "pending"}},
+	})
+	require.Len(t, targets, 1)
+	require.Equal(t, model.LabelValue("worker"), targets[0][taskLabel])
+	require.NotContains(t, targets[0], model.LabelName(model.AddressLabel))
+}
@@ -0,0 +1,178 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/mwitkow/go-conntrack"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	prom_openstack "github.com/prometheus/prometheus/discovery/openstack"
+	"github.com/prometheus/prometheus/discovery/refresh"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+// Roles of the discovered targets.
+const (
+	roleInstance     = string(prom_openstack.OpenStackRoleInstance)
+	roleHypervisor   = string(prom_openstack.OpenStackRoleHypervisor)
+	roleLoadBalancer = "loadbalancer"
+)
+
+// discovererConfig creates the discoverers of the component. The instances
+// and hypervisors of each region are discovered by a Prometheus OpenStack
+// discoverer, and the load balancers by a load balancer discoverer.
+type discovererConfig struct {
+	args Arguments
+}
+
+var _ prom_discovery.Config = (*discovererConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (*discovererConfig) Name() string {
+	return "openstack"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (c *discovererConfig) NewDiscoverer(opts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := opts.Metrics.(*discovererMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	var discoverers multiDiscoverer
+	for _, region := range c.args.regions() {
+		sd := c.args.sdConfig(region)
+		if c.args.Role != roleLoadBalancer {
+			d, err := sd.NewDiscoverer(prom_discovery.DiscovererOptions{
+				Logger:            logger,
+				Metrics:           m.openstackMetrics,
+				HTTPClientOptions: opts.HTTPClientOptions,
+			})
+			if err != nil {
+				return nil, err
+			}
+			discoverers = append(discoverers, d)
+			continue
+		}
+
+		d, err := newLoadBalancerDiscovery(sd, logger)
+		if err != nil {
+			return nil, err
+		}
+		discoverers = append(discoverers, refresh.NewDiscovery(refresh.Options{
+			Logger:              logger,
+			Mech:                "openstack",
+			Interval:            c.args.RefreshInterval,
+			RefreshF:            d.refresh,
+			MetricsInstantiator: m.refreshMetrics,
+		}))
+	}
+
+	if len(discoverers) == 1 {
+		return discoverers[0], nil
+	}
+	return discoverers, nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*discovererConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	return &discovererMetrics{
+		refreshMetrics:   rmi,
+		openstackMetrics: (&prom_openstack.SDConfig{}).NewDiscovererMetrics(reg, rmi),
+	}
+}
+
+var _ prom_discovery.DiscovererMetrics = (*discovererMetrics)(nil)
+
+type discovererMetrics struct {
+	refreshMetrics prom_discovery.RefreshMetricsInstantiator
+	// Metrics of the Prometheus OpenStack discoverers.
+	openstackMetrics prom_discovery.DiscovererMetrics
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Register() error {
+	return m.openstackMetrics.Register()
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *discovererMetrics) Unregister() {
+	m.openstackMetrics.Unregister()
+}
+
+// multiDiscoverer runs the discoverers of several regions. The target groups
+// of each region have their own source.
+type multiDiscoverer []prom_discovery.Discoverer
+
+// Run implements discovery.Discoverer.
+func (m multiDiscoverer) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	var wg sync.WaitGroup
+	wg.Add(len(m))
+	for _, d := range m {
+		go func(d prom_discovery.Discoverer) {
+			defer wg.Done()
+			d.Run(ctx, ch)
+		}(d)
+	}
+	wg.Wait()
+}
+
+// newProviderClient returns the client and the authentication options of the
+// OpenStack API, like the Prometheus OpenStack discoverers.
+func newProviderClient(sd *prom_openstack.SDConfig) (*gophercloud.ProviderClient, *gophercloud.AuthOptions, error) {
+	var opts gophercloud.AuthOptions
+	if sd.IdentityEndpoint == "" {
+		var err error
+		opts, err = openstack.AuthOptionsFromEnv()
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		opts = gophercloud.AuthOptions{
+			IdentityEndpoint:            sd.IdentityEndpoint,
+			Username:                    sd.Username,
+			UserID:                      sd.UserID,
+			Password:                    string(sd.Password),
+			TenantName:                  sd.ProjectName,
+			TenantID:                    sd.ProjectID,
+			DomainName:                  sd.DomainName,
+			DomainID:                    sd.DomainID,
+			ApplicationCredentialID:     sd.ApplicationCredentialID,
+			ApplicationCredentialName:   sd.ApplicationCredentialName,
+			ApplicationCredentialSecret: string(sd.ApplicationCredentialSecret),
+		}
+	}
+	client, err := openstack.NewClient(opts.IdentityEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	tls, err := config_util.NewTLSConfig(&sd.TLSConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	client.HTTPClient = http.Client{
+		Transport: &http.Transport{
+			IdleConnTimeout: 2 * time.Duration(sd.RefreshInterval),
+			TLSClientConfig: tls,
+			DialContext: conntrack.NewDialContextFunc(
+				conntrack.DialWithTracing(),
+				conntrack.DialWithName("openstack_sd"),
+			),
+		},
+		Timeout: time.Duration(sd.RefreshInterval),
+	}
+	return client, &opts, nil
+}
@@ -0,0 +1,159 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/prometheus/common/model"
+	prom_openstack "github.com/prometheus/prometheus/discovery/openstack"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+const (
+	openstackLabelPrefix                         = model.MetaLabelPrefix + "openstack_"
+	openstackLabelProjectID                      = openstackLabelPrefix + "project_id"
+	openstackLabelLoadBalancerID                 = openstackLabelPrefix + "loadbalancer_id"
+	openstackLabelLoadBalancerName               = openstackLabelPrefix + "loadbalancer_name"
+	openstackLabelLoadBalancerOperatingStatus    = openstackLabelPrefix + "loadbalancer_operating_status"
+	openstackLabelLoadBalancerProvisioningStatus = openstackLabelPrefix + "loadbalancer_provisioning_status"
+	openstackLabelLoadBalancerAvailabilityZone   = openstackLabelPrefix + "loadbalancer_availability_zone"
+	openstackLabelLoadBalancerFloatingIP         = openstackLabelPrefix + "loadbalancer_floating_ip"
+	openstackLabelLoadBalancerVIP                = openstackLabelPrefix + "loadbalancer_vip"
+	openstackLabelLoadBalancerProvider           = openstackLabelPrefix + "loadbalancer_provider"
+	openstackLabelLoadBalancerTags               = openstackLabelPrefix + "loadbalancer_tags"
+	openstackLabelLoadBalancerListenerID         = openstackLabelPrefix + "loadbalancer_listener_id"
+	openstackLabelLoadBalancerListenerProtocol   = openstackLabelPrefix + "loadbalancer_listener_protocol"
+)
+
+// loadBalancerDiscovery discovers the listeners of the OpenStack Octavia load
+// balancers of a region.
+type loadBalancerDiscovery struct {
+	provider     *gophercloud.ProviderClient
+	authOpts     *gophercloud.AuthOptions
+	region       string
+	availability gophercloud.Availability
+	logger       log.Logger
+}
+
+func newLoadBalancerDiscovery(sd *prom_openstack.SDConfig, logger log.Logger) (*loadBalancerDiscovery, error) {
+	provider, authOpts, err := newProviderClient(sd)
+	if err != nil {
+		return nil, err
+	}
+	return &loadBalancerDiscovery{
+		provider:     provider,
+		authOpts:     authOpts,
+		region:       sd.Region,
+		availability: gophercloud.Availability(sd.Availability),
+		logger:       logger,
+	}, nil
+}
+
+func (d *loadBalancerDiscovery) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	d.provider.Context = ctx
+	err := openstack.Authenticate(d.provider, *d.authOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not authenticate to OpenStack: %w", err)
+	}
+
+	endpointOpts := gophercloud.EndpointOpts{Region: d.region, Availability: d.availability}
+	client, err := openstack.NewLoadBalancerV2(d.provider, endpointOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OpenStack load balancer session: %w", err)
+	}
+	networkClient, err := openstack.NewNetworkV2(d.provider, endpointOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OpenStack network session: %w", err)
+	}
+
+	// OpenStack API reference
+	// https://docs.openstack.org/api-ref/load-balancer/v2/#list-load-balancers
+	allPages, err := loadbalancers.List(client, loadbalancers.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+	allLBs, err := loadbalancers.ExtractLoadBalancers(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract load balancers: %w", err)
+	}
+
+	// The listeners of all the load balancers are listed at once, rather than
+	// with a request per load balancer.
+	listenerPages, err := listeners.List(client, listeners.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancer listeners: %w", err)
+	}
+	allListeners, err := listeners.ExtractListeners(listenerPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract load balancer listeners: %w", err)
+	}
+
+	fipPages, err := floatingips.List(networkClient, floatingips.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list floating IPs: %w", err)
+	}
+	allFIPs, err := floatingips.ExtractFloatingIPs(fipPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract floating IPs: %w", err)
+	}
+
+	tg := &targetgroup.Group{
+		Source: "OS_" + d.region,
+	}
+	tg.Targets = loadBalancerTargets(allLBs, allListeners, allFIPs)
+	return []*targetgroup.Group{tg}, nil
+}
+
+// loadBalancerTargets returns a target per listener of the load balancers.
+func loadBalancerTargets(lbs []loadbalancers.LoadBalancer, allListeners []listeners.Listener, fips []floatingips.FloatingIP) []model.LabelSet {
+	// The floating IPs of the load balancers are associated to their VIP port.
+	fipByPort := make(map[string]string, len(fips))
+	for _, fip := range fips {
+		if fip.PortID != "" {
+			fipByPort[fip.PortID] = fip.FloatingIP
+		}
+	}
+
+	listenersByLB := make(map[string][]listeners.Listener)
+	for _, listener := range allListeners {
+		for _, lb := range listener.Loadbalancers {
+			listenersByLB[lb.ID] = append(listenersByLB[lb.ID], listener)
+		}
+	}
+
+	var targets []model.LabelSet
+	for _, lb := range lbs {
+		for _, listener := range listenersByLB[lb.ID] {
+			labels := model.LabelSet{
+				model.AddressLabel:                           model.LabelValue(net.JoinHostPort(lb.VipAddress, strconv.Itoa(listener.ProtocolPort))),
+				openstackLabelProjectID:                      model.LabelValue(lb.ProjectID),
+				openstackLabelLoadBalancerID:                 model.LabelValue(lb.ID),
+				openstackLabelLoadBalancerName:               model.LabelValue(lb.Name),
+				openstackLabelLoadBalancerOperatingStatus:    model.LabelValue(lb.OperatingStatus),
+				openstackLabelLoadBalancerProvisioningStatus: model.LabelValue(lb.ProvisioningStatus),
+				openstackLabelLoadBalancerAvailabilityZone:   model.LabelValue(lb.AvailabilityZone),
+				openstackLabelLoadBalancerVIP:                model.LabelValue(lb.VipAddress),
+				openstackLabelLoadBalancerProvider:           model.LabelValue(lb.Provider),
+				openstackLabelLoadBalancerListenerID:         model.LabelValue(listener.ID),
+				openstackLabelLoadBalancerListenerProtocol:   model.LabelValue(listener.Protocol),
+			}
+			if len(lb.Tags) > 0 {
+				labels[openstackLabelLoadBalancerTags] = model.LabelValue(strings.Join(lb.Tags, ","))
+			}
+			if fip, ok := fipByPort[lb.VipPortID]; ok {
+				labels[openstackLabelLoadBalancerFloatingIP] = model.LabelValue(fip)
+			}
+			targets = append(targets, labels)
+		}
+	}
+	return targets
+}
@@ -1,6 +1,7 @@
 package openstack
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,7 +24,9 @@ func init() {
 		Exports:   discovery.Exports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			return discovery.NewFromConvertibleConfig(opts, args.(Arguments))
+			return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+				return &discovererConfig{args: args.(Arguments)}, nil
+			})
 		},
 	})
 }
@@ -41,7 +44,8 @@ type Arguments struct {
 	ApplicationCredentialID     string            `alloy:"application_credential_id,attr,optional"`
 	ApplicationCredentialSecret alloytypes.Secret `alloy:"application_credential_secret,attr,optional"`
 	Role                        string            `alloy:"role,attr"`
-	Region                      string            `alloy:"region,attr"`
+	Region                      string            `alloy:"region,attr,optional"`
+	Regions                     []string          `alloy:"regions,attr,optional"`
 	RefreshInterval             time.Duration     `alloy:"refresh_interval,attr,optional"`
 	Port                        int               `alloy:"port,attr,optional"`
 	AllTenants                  bool              `alloy:"all_tenants,attr,optional"`
@@ -69,14 +73,55 @@ func (args *Arguments) Validate() error {
 	}
 
 	switch args.Role {
-	case "instance", "hypervisor":
+	case roleInstance, roleHypervisor, roleLoadBalancer:
 	default:
-		return fmt.Errorf("unknown availability %s, must be one of instance or hypervisor", args.Role)
+		return fmt.Errorf("unknown role %s, must be one of instance, hypervisor, or loadbalancer", args.Role)
 	}
+
+	regions := args.regions()
+	if len(regions) == 0 {
+		return errors.New("at least one of region or regions must be specified")
+	}
+	seen := make(map[string]struct{}, len(regions))
+	for _, region := range regions {
+		if region == "" {
+			return errors.New("regions must not be empty")
+		}
+		if _, ok := seen[region]; ok {
+			return fmt.Errorf("region %q is specified more than once", region)
+		}
+		seen[region] = struct{}{}
+	}
+
+	// Application credentials are identified by their ID, or by their name
+	// and the user which owns them.
+	appCredential := args.ApplicationCredentialID != "" || args.ApplicationCredentialName != ""
+	switch {
+	case appCredential && args.ApplicationCredentialSecret == "":
+		return errors.New("application_credential_secret is required when authenticating with an application credential")
+	case !appCredential && args.ApplicationCredentialSecret != "":
+		return errors.New("one of application_credential_id or application_credential_name is required with application_credential_secret")
+	case args.ApplicationCredentialID == "" && args.ApplicationCredentialName != "" && args.Username == "" && args.UserID == "":
+		return errors.New("one of username or userid is required with application_credential_name")
+	}
+
 	return args.TLSConfig.Validate()
 }
 
+// regions returns the regions to discover the targets of.
+func (args Arguments) regions() []string {
+	if args.Region == "" {
+		return args.Regions
+	}
+	return append([]string{args.Region}, args.Regions...)
+}
+
+// Convert returns the Prometheus configuration of the region argument.
 func (args Arguments) Convert() discovery.DiscovererConfig {
+	return args.sdConfig(args.Region)
+}
+
+func (args Arguments) sdConfig(region string) *prom_discovery.SDConfig {
 	tlsConfig := &args.TLSConfig
 
 	return &prom_discovery.SDConfig{
@@ -92,7 +137,7 @@ func (args Arguments) Convert() discovery.DiscovererConfig {
 		ApplicationCredentialID:     args.ApplicationCredentialID,
 		ApplicationCredentialSecret: config_util.Secret(args.ApplicationCredentialSecret),
 		Role:                        prom_discovery.Role(args.Role),
-		Region:                      args.Region,
+		Region:                      region,
 		RefreshInterval:             model.Duration(args.RefreshInterval),
 		Port:                        args.Port,
 		AllTenants:                  args.AllTenants,
@@ -4,6 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	promcfg "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/discovery/openstack"
@@ -26,6 +29,7 @@ func TestUnmarshal(t *testing.T) {
 	domain_id = "exampledomainid"
 	application_credential_name = "exampleappcred"
 	application_credential_id = "exampleappcredid"
+	application_credential_secret = "exampleappcredsecret"
 	role = "hypervisor"
 	region = "us-east-1"
 	refresh_interval = "1m"
@@ -62,7 +66,131 @@ func TestValidate(t *testing.T) {
 
 	var args2 Arguments
 	err = syntax.Unmarshal([]byte(wrongRole), &args2)
-	require.ErrorContains(t, err, "unknown availability private, must be one of instance or hypervisor")
+	require.ErrorContains(t, err, "unknown role private, must be one of instance, hypervisor, or loadbalancer")
+
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "load balancers of several regions",
+			cfg: `
+				role = "loadbalancer"
+				region = "us-east-1"
+				regions = ["us-west-1", "eu-west-1"]`,
+		},
+		{
+			name:        "no region",
+			cfg:         `role = "instance"`,
+			expectedErr: "at least one of region or regions must be specified",
+		},
+		{
+			name: "duplicate region",
+			cfg: `
+				role = "instance"
+				region = "us-east-1"
+				regions = ["us-east-1"]`,
+			expectedErr: `region "us-east-1" is specified more than once`,
+		},
+		{
+			name: "application credential ID",
+			cfg: `
+				role = "instance"
+				region = "us-east-1"
+				application_credential_id = "exampleappcredid"
+				application_credential_secret = "exampleappcredsecret"`,
+		},
+		{
+			name: "application credential without secret",
+			cfg: `
+				role = "instance"
+				region = "us-east-1"
+				application_credential_id = "exampleappcredid"`,
+			expectedErr: "application_credential_secret is required when authenticating with an application credential",
+		},
+		{
+			name: "application credential secret without credential",
+			cfg: `
+				role = "instance"
+				region = "us-east-1"
+				application_credential_secret = "exampleappcredsecret"`,
+			expectedErr: "one of application_credential_id or application_credential_name is required with application_credential_secret",
+		},
+		{
+			name: "application credential name without user",
+			cfg: `
+				role = "instance"
+				region = "us-east-1"
+				application_credential_name = "exampleappcred"
+				application_credential_secret = "exampleappcredsecret"`,
+			expectedErr: "one of username or userid is required with application_credential_name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.cfg), &args)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestRegions(t *testing.T) {
+	args := Arguments{Region: "us-east-1", Regions: []string{"us-west-1"}}
+	require.Equal(t, []string{"us-east-1", "us-west-1"}, args.regions())
+	require.Equal(t, "us-west-1", args.sdConfig("us-west-1").Region)
+}
+
+func TestLoadBalancerTargets(t *testing.T) {
+	lbs := []loadbalancers.LoadBalancer{
+		{
+			ID:                 "lb1",
+			Name:               "web",
+			ProjectID:          "project",
+			VipAddress:         "10.0.0.10",
+			VipPortID:          "port1",
+			Provider:           "amphora",
+			OperatingStatus:    "ONLINE",
+			ProvisioningStatus: "ACTIVE",
+			AvailabilityZone:   "az1",
+			Tags:               []string{"prod", "web"},
+		},
+		{
+			ID:         "lb2",
+			Name:       "no-listener",
+			VipAddress: "10.0.0.11",
+		},
+	}
+	allListeners := []listeners.Listener{
+		{ID: "l1", Protocol: "HTTP", ProtocolPort: 80, Loadbalancers: []listeners.LoadBalancerID{{ID: "lb1"}}},
+		{ID: "l2", Protocol: "HTTPS", ProtocolPort: 443, Loadbalancers: []listeners.LoadBalancerID{{ID: "lb1"}}},
+	}
+	fips := []floatingips.FloatingIP{{FloatingIP: "192.0.2.10", PortID: "port1"}}
+
+	targets := loadBalancerTargets(lbs, allListeners, fips)
+	require.Len(t, targets, 2)
+	require.Equal(t, model.LabelSet{
+		"__address__":                                       "10.0.0.10:80",
+		"__meta_openstack_project_id":                       "project",
+		"__meta_openstack_loadbalancer_id":                  "lb1",
+		"__meta_openstack_loadbalancer_name":                "web",
+		"__meta_openstack_loadbalancer_operating_status":    "ONLINE",
+		"__meta_openstack_loadbalancer_provisioning_status": "ACTIVE",
+		"__meta_openstack_loadbalancer_availability_zone":   "az1",
+		"__meta_openstack_loadbalancer_vip":                 "10.0.0.10",
+		"__meta_openstack_loadbalancer_provider":            "amphora",
+		"__meta_openstack_loadbalancer_listener_id":         "l1",
+		"__meta_openstack_loadbalancer_listener_protocol":   "HTTP",
+		"__meta_openstack_loadbalancer_tags":                "prod,web",
+		"__meta_openstack_loadbalancer_floating_ip":         "192.0.2.10",
+	}, targets[0])
+	require.Equal(t, model.LabelValue("10.0.0.10:443"), targets[1][model.AddressLabel])
 }
 
 func TestConvert(t *testing.T) {
@@ -0,0 +1,65 @@
+package podman
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+
+	"github.com/grafana/alloy/internal/component"
+)
+
+type podmanDiscoveryConfig struct {
+	args Arguments
+	opts component.Options
+}
+
+var _ prom_discovery.Config = (*podmanDiscoveryConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (p *podmanDiscoveryConfig) Name() string {
+	return "podman"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (p *podmanDiscoveryConfig) NewDiscoverer(discOpts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := discOpts.Metrics.(*podmanMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	podmanDiscovery, err := NewPodmanDiscovery(p.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              p.opts.Logger,
+		Mech:                "podman",
+		Interval:            p.args.RefreshInterval,
+		RefreshF:            podmanDiscovery.Refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*podmanDiscoveryConfig) NewDiscovererMetrics(_ prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	return &podmanMetrics{
+		refreshMetrics: rmi,
+	}
+}
+
+var _ prom_discovery.DiscovererMetrics = (*podmanMetrics)(nil)
+
+type podmanMetrics struct {
+	refreshMetrics prom_discovery.RefreshMetricsInstantiator
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *podmanMetrics) Register() error {
+	return nil
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *podmanMetrics) Unregister() {}
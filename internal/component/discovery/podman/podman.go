@@ -0,0 +1,373 @@
+// Package podman implements the discovery.podman component.
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	commonConfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+const (
+	metaLabelPrefix          = model.MetaLabelPrefix + "podman_"
+	containerIDLabel         = metaLabelPrefix + "container_id"
+	containerNameLabel       = metaLabelPrefix + "container_name"
+	containerImageLabel      = metaLabelPrefix + "container_image"
+	containerStateLabel      = metaLabelPrefix + "container_state"
+	containerNetworkMode     = metaLabelPrefix + "container_network_mode"
+	containerLabelPrefix     = metaLabelPrefix + "container_label_"
+	podIDLabel               = metaLabelPrefix + "pod_id"
+	podNameLabel             = metaLabelPrefix + "pod_name"
+	networkNameLabel         = metaLabelPrefix + "network_name"
+	networkIPLabel           = metaLabelPrefix + "network_ip"
+	portPrivateLabel         = metaLabelPrefix + "port_private"
+	portPublicLabel          = metaLabelPrefix + "port_public"
+	portPublicIPLabel        = metaLabelPrefix + "port_public_ip"
+	portProtocolLabel        = metaLabelPrefix + "port_protocol"
+	hostNetworkMode          = "host"
+	libpodAPIPrefix          = "/v4.0.0/libpod"
+	unixSocketPlaceholderURL = "http://podman"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "discovery.podman",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   discovery.Exports{},
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the discovery.podman component.
+type Arguments struct {
+	Host               string                  `alloy:"host,attr,optional"`
+	Port               int                     `alloy:"port,attr,optional"`
+	HostNetworkingHost string                  `alloy:"host_networking_host,attr,optional"`
+	RefreshInterval    time.Duration           `alloy:"refresh_interval,attr,optional"`
+	Filters            []Filter                `alloy:"filter,block,optional"`
+	HTTPClientConfig   config.HTTPClientConfig `alloy:",squash"`
+}
+
+// Filter is used to limit the discovery process to a subset of available
+// containers.
+type Filter struct {
+	Name   string   `alloy:"name,attr"`
+	Values []string `alloy:"values,attr"`
+}
+
+// DefaultArguments holds default values for Arguments.
+var DefaultArguments = Arguments{
+	Host:               "unix:///run/podman/podman.sock",
+	Port:               80,
+	HostNetworkingHost: "localhost",
+	RefreshInterval:    time.Minute,
+	HTTPClientConfig:   config.DefaultHTTPClientConfig,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Host == "" {
+		return fmt.Errorf("host attribute must not be empty")
+	}
+	u, err := url.Parse(args.Host)
+	if err != nil {
+		return fmt.Errorf("parsing host attribute: %w", err)
+	}
+	switch u.Scheme {
+	case "unix", "http", "https":
+	default:
+		return fmt.Errorf("host scheme must be 'unix', 'http' or 'https'")
+	}
+
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+
+	return args.HTTPClientConfig.Validate()
+}
+
+// New returns a new instance of a discovery.podman component.
+func New(opts component.Options, args Arguments) (*discovery.Component, error) {
+	return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+		return &podmanDiscoveryConfig{
+			args: args.(Arguments),
+			opts: opts,
+		}, nil
+	})
+}
+
+// Discovery retrieves containers from the Podman libpod REST API.
+type Discovery struct {
+	client             *http.Client
+	baseURL            string
+	filters            string
+	port               int
+	hostNetworkingHost string
+}
+
+// NewPodmanDiscovery creates a new Discovery from the provided arguments.
+func NewPodmanDiscovery(args Arguments) (*Discovery, error) {
+	u, err := url.Parse(args.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		httpOpts []commonConfig.HTTPClientOption
+		baseURL  = strings.TrimSuffix(args.Host, "/")
+	)
+	if u.Scheme == "unix" {
+		socket := u.Path
+		httpOpts = append(httpOpts, commonConfig.WithDialContextFunc(func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}))
+		baseURL = unixSocketPlaceholderURL
+	}
+
+	rt, err := commonConfig.NewRoundTripperFromConfig(*args.HTTPClientConfig.Convert(), "podman_sd", httpOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := encodeFilters(args.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Discovery{
+		client: &http.Client{
+			Transport: rt,
+			Timeout:   30 * time.Second,
+		},
+		baseURL:            baseURL + libpodAPIPrefix,
+		filters:            filters,
+		port:               args.Port,
+		hostNetworkingHost: args.HostNetworkingHost,
+	}, nil
+}
+
+// encodeFilters encodes filters in the JSON format expected by the filters
+// query parameter of the libpod API.
+func encodeFilters(filters []Filter) (string, error) {
+	if len(filters) == 0 {
+		return "", nil
+	}
+	m := make(map[string][]string, len(filters))
+	for _, f := range filters {
+		m[f.Name] = append(m[f.Name], f.Values...)
+	}
+	bb, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(bb), nil
+}
+
+type listContainer struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	State   string            `json:"State"`
+	Labels  map[string]string `json:"Labels"`
+	Pod     string            `json:"Pod"`
+	PodName string            `json:"PodName"`
+	Ports   []portMapping     `json:"Ports"`
+}
+
+type portMapping struct {
+	HostIP        string `json:"host_ip"`
+	ContainerPort uint16 `json:"container_port"`
+	HostPort      uint16 `json:"host_port"`
+	Protocol      string `json:"protocol"`
+}
+
+type inspectContainer struct {
+	HostConfig struct {
+		NetworkMode string `json:"NetworkMode"`
+	} `json:"HostConfig"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// Refresh queries the Podman API and returns the discovered target groups.
+func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	q := url.Values{}
+	if d.filters != "" {
+		q.Set("filters", d.filters)
+	}
+
+	var containers []listContainer
+	if err := d.get(ctx, "/containers/json?"+q.Encode(), &containers); err != nil {
+		return nil, err
+	}
+
+	tg := &targetgroup.Group{
+		Source: "podman",
+	}
+	for _, c := range containers {
+		var inspect inspectContainer
+		if err := d.get(ctx, "/containers/"+url.PathEscape(c.ID)+"/json", &inspect); err != nil {
+			return nil, err
+		}
+		tg.Targets = append(tg.Targets, d.containerTargets(c, inspect)...)
+	}
+	return []*targetgroup.Group{tg}, nil
+}
+
+func (d *Discovery) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating podman request: %w", err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending podman request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error response from podman: %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error unmarshaling response body: %w", err)
+	}
+	return nil
+}
+
+// containerTargets returns one target per combination of network and port
+// mapping of the container.
+func (d *Discovery) containerTargets(c listContainer, inspect inspectContainer) []model.LabelSet {
+	common := model.LabelSet{
+		containerIDLabel:     lv(c.ID),
+		containerImageLabel:  lv(c.Image),
+		containerStateLabel:  lv(c.State),
+		containerNetworkMode: lv(inspect.HostConfig.NetworkMode),
+	}
+	if len(c.Names) > 0 {
+		common[containerNameLabel] = lv("/" + strings.TrimPrefix(c.Names[0], "/"))
+	}
+	if c.Pod != "" {
+		common[podIDLabel] = lv(c.Pod)
+		common[podNameLabel] = lv(c.PodName)
+	}
+	for k, v := range c.Labels {
+		common[model.LabelName(containerLabelPrefix+strutil.SanitizeLabelName(k))] = lv(v)
+	}
+
+	type network struct{ name, ip string }
+	var networks []network
+	if inspect.HostConfig.NetworkMode == hostNetworkMode {
+		networks = append(networks, network{name: hostNetworkMode, ip: d.hostNetworkingHost})
+	} else {
+		names := make([]string, 0, len(inspect.NetworkSettings.Networks))
+		for name := range inspect.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ip := inspect.NetworkSettings.Networks[name].IPAddress; ip != "" {
+				networks = append(networks, network{name: name, ip: ip})
+			}
+		}
+	}
+
+	// Rootless containers using slirp4netns or pasta have no IP address
+	// reachable from the host, so they're only reachable through their
+	// published ports.
+	if len(networks) == 0 {
+		return d.publishedTargets(common, inspect.HostConfig.NetworkMode, c.Ports)
+	}
+
+	var targets []model.LabelSet
+	for _, n := range networks {
+		if len(c.Ports) == 0 {
+			target := common.Clone()
+			target[networkNameLabel] = lv(n.name)
+			target[networkIPLabel] = lv(n.ip)
+			target[model.AddressLabel] = lv(net.JoinHostPort(n.ip, strconv.Itoa(d.port)))
+			targets = append(targets, target)
+			continue
+		}
+
+		for _, p := range c.Ports {
+			target := common.Clone()
+			target[networkNameLabel] = lv(n.name)
+			target[networkIPLabel] = lv(n.ip)
+			target[portPrivateLabel] = lv(strconv.FormatUint(uint64(p.ContainerPort), 10))
+			target[portProtocolLabel] = lv(p.Protocol)
+			if p.HostPort != 0 {
+				target[portPublicLabel] = lv(strconv.FormatUint(uint64(p.HostPort), 10))
+				target[portPublicIPLabel] = lv(p.HostIP)
+			}
+			target[model.AddressLabel] = lv(net.JoinHostPort(n.ip, strconv.FormatUint(uint64(p.ContainerPort), 10)))
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// publishedTargets returns one target per published port of a container.
+// Ports published on all the addresses of the host use hostNetworkingHost as
+// the target host.
+func (d *Discovery) publishedTargets(common model.LabelSet, networkMode string, ports []portMapping) []model.LabelSet {
+	var targets []model.LabelSet
+	for _, p := range ports {
+		if p.HostPort == 0 {
+			continue
+		}
+		host := p.HostIP
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			host = d.hostNetworkingHost
+		}
+
+		target := common.Clone()
+		target[networkNameLabel] = lv(networkMode)
+		target[networkIPLabel] = lv(host)
+		target[portPrivateLabel] = lv(strconv.FormatUint(uint64(p.ContainerPort), 10))
+		target[portProtocolLabel] = lv(p.Protocol)
+		target[portPublicLabel] = lv(strconv.FormatUint(uint64(p.HostPort), 10))
+		target[portPublicIPLabel] = lv(p.HostIP)
+		target[model.AddressLabel] = lv(net.JoinHostPort(host, strconv.FormatUint(uint64(p.HostPort), 10)))
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+func lv(s string) model.LabelValue {
+	return model.LabelValue(s)
+}
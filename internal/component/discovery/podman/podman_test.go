@@ -0,0 +1,170 @@
+package podman
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestAlloyConfig(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+	refresh_interval = "30s"
+	filter {
+		name   = "label"
+		values = ["app=web"]
+	}
+`), &args)
+	require.NoError(t, err)
+	require.Equal(t, "unix:///run/podman/podman.sock", args.Host)
+	require.Equal(t, 30*time.Second, args.RefreshInterval)
+
+	filters, err := encodeFilters(args.Filters)
+	require.NoError(t, err)
+	require.Equal(t, `{"label":["app=web"]}`, filters)
+}
+
+func TestBadAlloyConfig(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`host = "ssh://user@host/run/podman/podman.sock"`), &args)
+	require.ErrorContains(t, err, "host scheme must be 'unix', 'http' or 'https'")
+}
+
+func TestRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4.0.0/libpod/containers/json":
+			require.Equal(t, `{"pod":["monitoring"]}`, r.URL.Query().Get("filters"))
+			_, _ = w.Write([]byte(`[
+				{
+					"Id": "abc", "Names": ["node-exporter"], "Image": "quay.io/prometheus/node-exporter:latest",
+					"State": "running", "Labels": {"app.name": "node"}, "Pod": "p1", "PodName": "monitoring",
+					"Ports": [{"host_ip": "0.0.0.0", "container_port": 9100, "host_port": 19100, "protocol": "tcp"}]
+				},
+				{
+					"Id": "def", "Names": ["host-agent"], "Image": "agent", "State": "running"
+				}
+			]`))
+		case "/v4.0.0/libpod/containers/abc/json":
+			_, _ = w.Write([]byte(`{
+				"HostConfig": {"NetworkMode": "bridge"},
+				"NetworkSettings": {"Networks": {"podman": {"IPAddress": "10.88.0.2"}}}
+			}`))
+		case "/v4.0.0/libpod/containers/def/json":
+			_, _ = w.Write([]byte(`{"HostConfig": {"NetworkMode": "host"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	args := DefaultArguments
+	args.Host = srv.URL
+	args.Filters = []Filter{{Name: "pod", Values: []string{"monitoring"}}}
+
+	d, err := NewPodmanDiscovery(args)
+	require.NoError(t, err)
+
+	groups, err := d.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, []model.LabelSet{
+		{
+			model.AddressLabel:                       "10.88.0.2:9100",
+			containerIDLabel:                         "abc",
+			containerNameLabel:                       "/node-exporter",
+			containerImageLabel:                      "quay.io/prometheus/node-exporter:latest",
+			containerStateLabel:                      "running",
+			containerNetworkMode:                     "bridge",
+			podIDLabel:                               "p1",
+			podNameLabel:                             "monitoring",
+			"__meta_podman_container_label_app_name": "node",
+			networkNameLabel:                         "podman",
+			networkIPLabel:                           "10.88.0.2",
+			portPrivateLabel:                         "9100",
+			portProtocolLabel:                        "tcp",
+			portPublicLabel:                          "19100",
+			portPublicIPLabel:                        "0.0.0.0",
+		},
+		{
+			model.AddressLabel:   "localhost:80",
+			containerIDLabel:     "def",
+			containerNameLabel:   "/host-agent",
+			containerImageLabel:  "agent",
+			containerStateLabel:  "running",
+			containerNetworkMode: "host",
+			networkNameLabel:     "host",
+			networkIPLabel:       "localhost",
+		},
+	}, groups[0].Targets)
+}
+
+func TestRefreshRootless(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4.0.0/libpod/containers/json":
+			_, _ = w.Write([]byte(`[
+				{
+					"Id": "abc", "Names": ["web"], "Image": "nginx", "State": "running",
+					"Ports": [
+						{"host_ip": "", "container_port": 80, "host_port": 8080, "protocol": "tcp"},
+						{"host_ip": "127.0.0.1", "container_port": 9113, "host_port": 19113, "protocol": "tcp"},
+						{"container_port": 443, "protocol": "tcp"}
+					]
+				}
+			]`))
+		case "/v4.0.0/libpod/containers/abc/json":
+			_, _ = w.Write([]byte(`{
+				"HostConfig": {"NetworkMode": "pasta"},
+				"NetworkSettings": {"Networks": {}}
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	args := DefaultArguments
+	args.Host = srv.URL
+	args.HostNetworkingHost = "host.example"
+
+	d, err := NewPodmanDiscovery(args)
+	require.NoError(t, err)
+
+	groups, err := d.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	common := model.LabelSet{
+		containerIDLabel:     "abc",
+		containerNameLabel:   "/web",
+		containerImageLabel:  "nginx",
+		containerStateLabel:  "running",
+		containerNetworkMode: "pasta",
+		networkNameLabel:     "pasta",
+		portProtocolLabel:    "tcp",
+	}
+	require.Equal(t, []model.LabelSet{
+		common.Merge(model.LabelSet{
+			model.AddressLabel: "host.example:8080",
+			networkIPLabel:     "host.example",
+			portPrivateLabel:   "80",
+			portPublicLabel:    "8080",
+			portPublicIPLabel:  "",
+		}),
+		common.Merge(model.LabelSet{
+			model.AddressLabel: "127.0.0.1:19113",
+			networkIPLabel:     "127.0.0.1",
+			portPrivateLabel:   "9113",
+			portPublicLabel:    "19113",
+			portPublicIPLabel:  "127.0.0.1",
+		}),
+	}, groups[0].Targets)
+}
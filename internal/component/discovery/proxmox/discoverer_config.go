@@ -0,0 +1,65 @@
+package proxmox
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	prom_discovery "github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+
+	"github.com/grafana/alloy/internal/component"
+)
+
+type proxmoxDiscoveryConfig struct {
+	args Arguments
+	opts component.Options
+}
+
+var _ prom_discovery.Config = (*proxmoxDiscoveryConfig)(nil)
+
+// Name implements discovery.DiscovererConfig.
+func (p *proxmoxDiscoveryConfig) Name() string {
+	return "proxmox"
+}
+
+// NewDiscoverer implements discovery.DiscovererConfig.
+func (p *proxmoxDiscoveryConfig) NewDiscoverer(discOpts prom_discovery.DiscovererOptions) (prom_discovery.Discoverer, error) {
+	m, ok := discOpts.Metrics.(*proxmoxMetrics)
+	if !ok {
+		return nil, fmt.Errorf("invalid discovery metrics type")
+	}
+
+	proxmoxDiscovery, err := NewProxmoxDiscovery(p.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return refresh.NewDiscovery(refresh.Options{
+		Logger:              p.opts.Logger,
+		Mech:                "proxmox",
+		Interval:            p.args.RefreshInterval,
+		RefreshF:            proxmoxDiscovery.Refresh,
+		MetricsInstantiator: m.refreshMetrics,
+	}), nil
+}
+
+// NewDiscovererMetrics implements discovery.DiscovererConfig.
+func (*proxmoxDiscoveryConfig) NewDiscovererMetrics(_ prometheus.Registerer, rmi prom_discovery.RefreshMetricsInstantiator) prom_discovery.DiscovererMetrics {
+	return &proxmoxMetrics{
+		refreshMetrics: rmi,
+	}
+}
+
+var _ prom_discovery.DiscovererMetrics = (*proxmoxMetrics)(nil)
+
+type proxmoxMetrics struct {
+	refreshMetrics prom_discovery.RefreshMetricsInstantiator
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *proxmoxMetrics) Register() error {
+	return nil
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *proxmoxMetrics) Unregister() {}
@@ -0,0 +1,299 @@
+// Package proxmox implements the discovery.proxmox component.
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	commonConfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+const (
+	metaLabelPrefix = model.MetaLabelPrefix + "proxmox_"
+	typeLabel       = metaLabelPrefix + "type"
+	nodeLabel       = metaLabelPrefix + "node"
+	vmidLabel       = metaLabelPrefix + "vmid"
+	nameLabel       = metaLabelPrefix + "name"
+	statusLabel     = metaLabelPrefix + "status"
+	poolLabel       = metaLabelPrefix + "pool"
+	tagsLabel       = metaLabelPrefix + "tags"
+	tagLabelPrefix  = metaLabelPrefix + "tag_"
+	templateLabel   = metaLabelPrefix + "template"
+
+	resourceTypeNode = "node"
+	resourceTypeQEMU = "qemu"
+	resourceTypeLXC  = "lxc"
+
+	// tagSeparators are the separators Proxmox VE uses in the tags field of
+	// guests. Older releases only used a semicolon, newer ones accept commas
+	// and spaces as well.
+	tagSeparators = ";, "
+)
+
+var validResourceTypes = []string{resourceTypeNode, resourceTypeQEMU, resourceTypeLXC}
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "discovery.proxmox",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   discovery.Exports{},
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the discovery.proxmox component.
+type Arguments struct {
+	URL              string                  `alloy:"url,attr"`
+	TokenID          string                  `alloy:"token_id,attr,optional"`
+	TokenSecret      alloytypes.Secret       `alloy:"token_secret,attr,optional"`
+	ResourceTypes    []string                `alloy:"resource_types,attr,optional"`
+	Port             int                     `alloy:"port,attr,optional"`
+	IncludeTemplates bool                    `alloy:"include_templates,attr,optional"`
+	RefreshInterval  time.Duration           `alloy:"refresh_interval,attr,optional"`
+	HTTPClientConfig config.HTTPClientConfig `alloy:",squash"`
+}
+
+// DefaultArguments holds default values for Arguments.
+var DefaultArguments = Arguments{
+	ResourceTypes:    validResourceTypes,
+	Port:             9100,
+	RefreshInterval:  60 * time.Second,
+	HTTPClientConfig: config.DefaultHTTPClientConfig,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	parsedURL, err := url.Parse(args.URL)
+	if err != nil {
+		return err
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL scheme must be 'http' or 'https'")
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("host is missing in URL")
+	}
+
+	if (args.TokenID == "") != (args.TokenSecret == "") {
+		return fmt.Errorf("token_id and token_secret must be configured together")
+	}
+	if args.TokenID != "" {
+		if args.HTTPClientConfig.BasicAuth != nil || args.HTTPClientConfig.Authorization != nil ||
+			args.HTTPClientConfig.OAuth2 != nil || args.HTTPClientConfig.BearerToken != "" ||
+			args.HTTPClientConfig.BearerTokenFile != "" {
+
+			return fmt.Errorf("token_id and token_secret cannot be used together with other authentication methods")
+		}
+	}
+
+	if len(args.ResourceTypes) == 0 {
+		return fmt.Errorf("resource_types must not be empty")
+	}
+	for _, t := range args.ResourceTypes {
+		if !slices.Contains(validResourceTypes, t) {
+			return fmt.Errorf("invalid resource type %q, must be one of %s", t, strings.Join(validResourceTypes, ", "))
+		}
+	}
+
+	if args.Port <= 0 || args.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+
+	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
+	return args.HTTPClientConfig.Validate()
+}
+
+// New returns a new instance of a discovery.proxmox component.
+func New(opts component.Options, args Arguments) (*discovery.Component, error) {
+	return discovery.New(opts, args, func(args component.Arguments) (discovery.DiscovererConfig, error) {
+		return &proxmoxDiscoveryConfig{
+			args: args.(Arguments),
+			opts: opts,
+		}, nil
+	})
+}
+
+// Discovery retrieves nodes and guests from the Proxmox VE cluster resources
+// API.
+type Discovery struct {
+	client        *http.Client
+	url           string
+	resourceTypes []string
+	port          int
+	templates     bool
+}
+
+// NewProxmoxDiscovery creates a new Discovery from the provided arguments.
+func NewProxmoxDiscovery(args Arguments) (*Discovery, error) {
+	rt, err := commonConfig.NewRoundTripperFromConfig(*args.HTTPClientConfig.Convert(), "proxmox_sd")
+	if err != nil {
+		return nil, err
+	}
+	if args.TokenID != "" {
+		rt = &tokenRoundTripper{
+			token: fmt.Sprintf("PVEAPIToken=%s=%s", args.TokenID, string(args.TokenSecret)),
+			next:  rt,
+		}
+	}
+
+	return &Discovery{
+		client: &http.Client{
+			Transport: rt,
+			Timeout:   30 * time.Second,
+		},
+		url:           strings.TrimSuffix(args.URL, "/") + "/api2/json/cluster/resources",
+		resourceTypes: args.ResourceTypes,
+		port:          args.Port,
+		templates:     args.IncludeTemplates,
+	}, nil
+}
+
+// tokenRoundTripper adds a Proxmox VE API token to outgoing requests. The
+// token format of Proxmox VE is incompatible with the generic authorization
+// block because it requires the type and credentials to be separated by an
+// equals sign instead of a space.
+type tokenRoundTripper struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (rt *tokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", rt.token)
+	return rt.next.RoundTrip(req)
+}
+
+// resource is a single entry of the /cluster/resources API response.
+type resource struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Node     string `json:"node"`
+	VMID     int    `json:"vmid"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Pool     string `json:"pool"`
+	Tags     string `json:"tags"`
+	Template int    `json:"template"`
+}
+
+type resourcesResponse struct {
+	Data []resource `json:"data"`
+}
+
+// Refresh queries the Proxmox VE API and returns the discovered target
+// groups.
+func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating proxmox resources request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending proxmox resources request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response from proxmox: %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var resources resourcesResponse
+	if err := json.Unmarshal(body, &resources); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response body: %w", err)
+	}
+	return d.refresh(resources.Data), nil
+}
+
+func (d *Discovery) refresh(resources []resource) []*targetgroup.Group {
+	tg := &targetgroup.Group{
+		Source: d.url,
+	}
+	for _, r := range resources {
+		if !slices.Contains(d.resourceTypes, r.Type) {
+			continue
+		}
+		if r.Template == 1 && !d.templates {
+			continue
+		}
+		tg.Targets = append(tg.Targets, d.resourceLabels(r))
+	}
+	return []*targetgroup.Group{tg}
+}
+
+func (d *Discovery) resourceLabels(r resource) model.LabelSet {
+	ls := model.LabelSet{
+		typeLabel:   lv(r.Type),
+		nodeLabel:   lv(r.Node),
+		statusLabel: lv(r.Status),
+	}
+
+	host := r.Node
+	if r.Type != resourceTypeNode {
+		host = r.Name
+		ls[vmidLabel] = lv(strconv.Itoa(r.VMID))
+		ls[nameLabel] = lv(r.Name)
+		ls[templateLabel] = lv(strconv.FormatBool(r.Template == 1))
+		if r.Pool != "" {
+			ls[poolLabel] = lv(r.Pool)
+		}
+	}
+	ls[model.AddressLabel] = lv(net.JoinHostPort(host, strconv.Itoa(d.port)))
+
+	tags := splitTags(r.Tags)
+	if len(tags) > 0 {
+		// Surround the joined tags with separators so that relabeling rules
+		// can match individual tags with a regular expression such as
+		// ".*,production,.*".
+		ls[tagsLabel] = lv("," + strings.Join(tags, ",") + ",")
+	}
+	for _, tag := range tags {
+		ls[model.LabelName(tagLabelPrefix+strutil.SanitizeLabelName(tag))] = "true"
+	}
+	return ls
+}
+
+func splitTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return strings.ContainsRune(tagSeparators, r)
+	})
+}
+
+func lv(s string) model.LabelValue {
+	return model.LabelValue(s)
+}
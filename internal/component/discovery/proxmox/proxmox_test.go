@@ -0,0 +1,165 @@
+package proxmox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestAlloyConfig(t *testing.T) {
+	var exampleAlloyConfig = `
+	url            = "https://pve.example.com:8006"
+	token_id       = "monitoring@pve!alloy"
+	token_secret   = "00000000-0000-0000-0000-000000000000"
+	resource_types = ["qemu", "lxc"]
+	port           = 9273
+	refresh_interval = "2m"
+`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.NoError(t, err)
+	require.Equal(t, "https://pve.example.com:8006", args.URL)
+	require.Equal(t, []string{"qemu", "lxc"}, args.ResourceTypes)
+	require.Equal(t, 9273, args.Port)
+	require.Equal(t, 2*time.Minute, args.RefreshInterval)
+	require.False(t, args.IncludeTemplates)
+}
+
+func TestDefaults(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`url = "https://pve.example.com:8006"`), &args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"node", "qemu", "lxc"}, args.ResourceTypes)
+	require.Equal(t, 9100, args.Port)
+	require.Equal(t, time.Minute, args.RefreshInterval)
+}
+
+func TestBadAlloyConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "bad scheme",
+			config: `url = "ftp://pve.example.com"`,
+			err:    "URL scheme must be 'http' or 'https'",
+		},
+		{
+			name: "token id without secret",
+			config: `
+			url      = "https://pve.example.com:8006"
+			token_id = "monitoring@pve!alloy"`,
+			err: "token_id and token_secret must be configured together",
+		},
+		{
+			name: "token with basic auth",
+			config: `
+			url          = "https://pve.example.com:8006"
+			token_id     = "monitoring@pve!alloy"
+			token_secret = "secret"
+			basic_auth {
+				username = "root@pam"
+				password = "password"
+			}`,
+			err: "token_id and token_secret cannot be used together with other authentication methods",
+		},
+		{
+			name: "invalid resource type",
+			config: `
+			url            = "https://pve.example.com:8006"
+			resource_types = ["storage"]`,
+			err: `invalid resource type "storage"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api2/json/cluster/resources", r.URL.Path)
+		require.Equal(t, "PVEAPIToken=monitoring@pve!alloy=secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "node/pve1", "type": "node", "node": "pve1", "status": "online"},
+			{"id": "qemu/100", "type": "qemu", "node": "pve1", "vmid": 100, "name": "web", "status": "running", "pool": "prod", "tags": "production;web"},
+			{"id": "lxc/101", "type": "lxc", "node": "pve1", "vmid": 101, "name": "dns", "status": "stopped"},
+			{"id": "qemu/9000", "type": "qemu", "node": "pve1", "vmid": 9000, "name": "tmpl", "status": "stopped", "template": 1},
+			{"id": "storage/pve1/local", "type": "storage", "node": "pve1", "status": "available"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	args := DefaultArguments
+	args.URL = srv.URL
+	args.TokenID = "monitoring@pve!alloy"
+	args.TokenSecret = "secret"
+
+	d, err := NewProxmoxDiscovery(args)
+	require.NoError(t, err)
+
+	groups, err := d.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, []model.LabelSet{
+		{
+			model.AddressLabel: "pve1:9100",
+			typeLabel:          "node",
+			nodeLabel:          "pve1",
+			statusLabel:        "online",
+		},
+		{
+			model.AddressLabel:              "web:9100",
+			typeLabel:                       "qemu",
+			nodeLabel:                       "pve1",
+			statusLabel:                     "running",
+			vmidLabel:                       "100",
+			nameLabel:                       "web",
+			templateLabel:                   "false",
+			poolLabel:                       "prod",
+			tagsLabel:                       ",production,web,",
+			"__meta_proxmox_tag_production": "true",
+			"__meta_proxmox_tag_web":        "true",
+		},
+		{
+			model.AddressLabel: "dns:9100",
+			typeLabel:          "lxc",
+			nodeLabel:          "pve1",
+			statusLabel:        "stopped",
+			vmidLabel:          "101",
+			nameLabel:          "dns",
+			templateLabel:      "false",
+		},
+	}, groups[0].Targets)
+}
+
+func TestRefreshResourceTypes(t *testing.T) {
+	d := &Discovery{
+		resourceTypes: []string{"lxc"},
+		port:          80,
+		templates:     true,
+	}
+	groups := d.refresh([]resource{
+		{Type: "node", Node: "pve1"},
+		{Type: "qemu", Node: "pve1", VMID: 100, Name: "web"},
+		{Type: "lxc", Node: "pve1", VMID: 101, Name: "dns", Template: 1},
+	})
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Targets, 1)
+	require.Equal(t, model.LabelValue("dns:80"), groups[0].Targets[0][model.AddressLabel])
+	require.Equal(t, model.LabelValue("true"), groups[0].Targets[0][templateLabel])
+}
@@ -0,0 +1,125 @@
+// Package process implements the instrumentation.process component, which
+// launches .NET and Python processes with the OpenTelemetry automatic
+// instrumentation, and reports the instrumentation state of discovered
+// processes.
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+)
+
+// The languages of the processes which can be instrumented.
+const (
+	languageDotNet = "dotnet"
+	languagePython = "python"
+)
+
+// The protocols which the instrumented processes can use to send OTLP data.
+const (
+	protocolGRPC         = "grpc"
+	protocolHTTPProtobuf = "http/protobuf"
+)
+
+// Arguments configures the instrumentation.process component.
+type Arguments struct {
+	Targets            []discovery.Target `alloy:"targets,attr,optional"`
+	OTLPEndpoint       string             `alloy:"otlp_endpoint,attr,optional"`
+	OTLPProtocol       string             `alloy:"otlp_protocol,attr,optional"`
+	ResourceAttributes map[string]string  `alloy:"resource_attributes,attr,optional"`
+
+	DotNet    *DotNetArguments   `alloy:"dotnet,block,optional"`
+	Python    *PythonArguments   `alloy:"python,block,optional"`
+	Processes []ProcessArguments `alloy:"process,block,optional"`
+}
+
+// DotNetArguments configures the instrumentation of .NET processes.
+type DotNetArguments struct {
+	// Home is the installation directory of the OpenTelemetry .NET automatic
+	// instrumentation.
+	Home string `alloy:"home,attr"`
+}
+
+// PythonArguments configures the instrumentation of Python processes.
+type PythonArguments struct {
+	// Path is the directory where the OpenTelemetry Python distribution and
+	// instrumentation libraries are installed.
+	Path string `alloy:"path,attr"`
+}
+
+// ProcessArguments describes a process which is launched and supervised by
+// the component.
+type ProcessArguments struct {
+	Name         string            `alloy:",label"`
+	Command      []string          `alloy:"command,attr"`
+	Language     string            `alloy:"language,attr"`
+	ServiceName  string            `alloy:"service_name,attr,optional"`
+	WorkingDir   string            `alloy:"working_dir,attr,optional"`
+	Env          map[string]string `alloy:"env,attr,optional"`
+	RestartDelay time.Duration     `alloy:"restart_delay,attr,optional"`
+}
+
+// DefaultArguments holds the default settings of the component.
+var DefaultArguments = Arguments{
+	OTLPEndpoint: "http://localhost:4318",
+	OTLPProtocol: protocolHTTPProtobuf,
+}
+
+// DefaultProcessArguments holds the default settings of a process block.
+var DefaultProcessArguments = ProcessArguments{
+	RestartDelay: 5 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.OTLPEndpoint == "" {
+		return fmt.Errorf("otlp_endpoint must not be empty")
+	}
+	switch args.OTLPProtocol {
+	case protocolGRPC, protocolHTTPProtobuf:
+	default:
+		return fmt.Errorf("otlp_protocol must be %q or %q, got %q", protocolGRPC, protocolHTTPProtobuf, args.OTLPProtocol)
+	}
+
+	names := make(map[string]struct{}, len(args.Processes))
+	for _, p := range args.Processes {
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("process %q is defined more than once", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		switch {
+		case p.Language == languageDotNet && args.DotNet == nil:
+			return fmt.Errorf("process %q: the dotnet block must be set to instrument .NET processes", p.Name)
+		case p.Language == languagePython && args.Python == nil:
+			return fmt.Errorf("process %q: the python block must be set to instrument Python processes", p.Name)
+		}
+	}
+	return nil
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (p *ProcessArguments) SetToDefault() {
+	*p = DefaultProcessArguments
+}
+
+// Validate implements syntax.Validator.
+func (p *ProcessArguments) Validate() error {
+	if len(p.Command) == 0 {
+		return fmt.Errorf("process %q: command must not be empty", p.Name)
+	}
+	if p.Language != languageDotNet && p.Language != languagePython {
+		return fmt.Errorf("process %q: language must be %q or %q, got %q", p.Name, languageDotNet, languagePython, p.Language)
+	}
+	if p.RestartDelay <= 0 {
+		return fmt.Errorf("process %q: restart_delay must be greater than 0", p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	cfg := `
+		otlp_endpoint       = "http://localhost:4317"
+		otlp_protocol       = "grpc"
+		resource_attributes = { "deployment.environment" = "production" }
+
+		dotnet {
+			home = "/opt/otel-dotnet-auto"
+		}
+
+		process "checkout" {
+			command     = ["dotnet", "Checkout.dll"]
+			language    = "dotnet"
+			working_dir = "/srv/checkout"
+			env         = { "ASPNETCORE_URLS" = "http://+:8080" }
+		}
+	`
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	require.Equal(t, "http://localhost:4317", args.OTLPEndpoint)
+	require.Equal(t, "grpc", args.OTLPProtocol)
+	require.Equal(t, "/opt/otel-dotnet-auto", args.DotNet.Home)
+	require.Nil(t, args.Python)
+	require.Equal(t, []ProcessArguments{{
+		Name:         "checkout",
+		Command:      []string{"dotnet", "Checkout.dll"},
+		Language:     "dotnet",
+		WorkingDir:   "/srv/checkout",
+		Env:          map[string]string{"ASPNETCORE_URLS": "http://+:8080"},
+		RestartDelay: 5 * time.Second,
+	}}, args.Processes)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			name:     "invalid protocol",
+			cfg:      `otlp_protocol = "http/json"`,
+			errorMsg: `otlp_protocol must be "grpc" or "http/protobuf", got "http/json"`,
+		},
+		{
+			name: "missing language block",
+			cfg: `
+				process "app" {
+					command  = ["python3", "app.py"]
+					language = "python"
+				}`,
+			errorMsg: `process "app": the python block must be set to instrument Python processes`,
+		},
+		{
+			name: "unknown language",
+			cfg: `
+				process "app" {
+					command  = ["java", "-jar", "app.jar"]
+					language = "java"
+				}`,
+			errorMsg: `process "app": language must be "dotnet" or "python", got "java"`,
+		},
+		{
+			name: "duplicate process",
+			cfg: `
+				python {
+					path = "/opt/otel-python"
+				}
+				process "app" {
+					command  = ["python3", "app.py"]
+					language = "python"
+				}
+				process "app" {
+					command  = ["python3", "other.py"]
+					language = "python"
+				}`,
+			errorMsg: `process "app" is defined more than once`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tc.cfg), &args), tc.errorMsg)
+		})
+	}
+}
+
+func TestProcessEnv(t *testing.T) {
+	args := DefaultArguments
+	args.ResourceAttributes = map[string]string{"service.namespace": "shop", "deployment.environment": "production"}
+	args.DotNet = &DotNetArguments{Home: "/opt/otel-dotnet-auto"}
+	args.Python = &PythonArguments{Path: "/opt/otel-python"}
+	base := []string{"HOME=/root", "PYTHONPATH=/srv/lib", "OTEL_SERVICE_NAME=base"}
+
+	t.Run("dotnet", func(t *testing.T) {
+		env := processEnv(args, ProcessArguments{
+			Name:     "checkout",
+			Language: languageDotNet,
+			Env:      map[string]string{"OTEL_TRACES_EXPORTER": "none"},
+		}, base)
+
+		require.Subset(t, env, []string{
+			"HOME=/root",
+			"OTEL_SERVICE_NAME=checkout",
+			"OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318",
+			"OTEL_EXPORTER_OTLP_PROTOCOL=http/protobuf",
+			"OTEL_RESOURCE_ATTRIBUTES=deployment.environment=production,service.namespace=shop",
+			"OTEL_TRACES_EXPORTER=none",
+			"OTEL_DOTNET_AUTO_HOME=/opt/otel-dotnet-auto",
+			"CORECLR_ENABLE_PROFILING=1",
+			"CORECLR_PROFILER={918728DD-259F-4A6A-AC2B-B85E1B658318}",
+			"CORECLR_PROFILER_PATH=/opt/otel-dotnet-auto/" + dotNetPlatform() + "/OpenTelemetry.AutoInstrumentation.Native.so",
+			"DOTNET_STARTUP_HOOKS=/opt/otel-dotnet-auto/net/OpenTelemetry.AutoInstrumentation.StartupHook.dll",
+		})
+	})
+
+	t.Run("python", func(t *testing.T) {
+		env := processEnv(args, ProcessArguments{
+			Name:        "api",
+			ServiceName: "shop-api",
+			Language:    languagePython,
+		}, base)
+
+		require.Subset(t, env, []string{
+			"OTEL_SERVICE_NAME=shop-api",
+			"OTEL_TRACES_EXPORTER=otlp",
+			"PYTHONPATH=/opt/otel-python/opentelemetry/instrumentation/auto_instrumentation:/opt/otel-python:/srv/lib",
+			"OTEL_PYTHON_LOGGING_AUTO_INSTRUMENTATION_ENABLED=true",
+		})
+		require.NotContains(t, env, "PYTHONPATH=/srv/lib")
+	})
+}
@@ -0,0 +1,106 @@
+//go:build linux
+
+package process
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+)
+
+const (
+	labelProcessID  = "__process_pid__"
+	labelProcessExe = "__meta_process_exe"
+
+	labelLanguage = "__meta_instrumentation_language"
+	labelState    = "__meta_instrumentation_state"
+)
+
+// The instrumentation states of a process.
+const (
+	stateLaunched        = "launched"
+	stateInstrumented    = "instrumented"
+	stateNotInstrumented = "not_instrumented"
+	stateUnknown         = "unknown"
+)
+
+var pythonExe = regexp.MustCompile(`^python[0-9.]*$`)
+
+// classifyTargets returns the targets of .NET and Python processes, with their
+// language and instrumentation state. launched holds the PIDs of the
+// processes launched by the component.
+func classifyTargets(procfs string, targets []discovery.Target, launched map[string]struct{}) []discovery.Target {
+	res := make([]discovery.Target, 0, len(targets))
+	for _, t := range targets {
+		pid := t[labelProcessID]
+		if pid == "" {
+			continue
+		}
+		language := processLanguage(procfs, pid, t[labelProcessExe])
+		if language == "" {
+			continue
+		}
+
+		var state string
+		if _, ok := launched[pid]; ok {
+			state = stateLaunched
+		} else {
+			state = instrumentationState(procfs, pid, language)
+		}
+
+		target := make(discovery.Target, len(t)+2)
+		for k, v := range t {
+			target[k] = v
+		}
+		target[labelLanguage] = language
+		target[labelState] = state
+		res = append(res, target)
+	}
+	return res
+}
+
+// processLanguage returns the language of a process, or an empty string if
+// it's neither a .NET nor a Python process.
+func processLanguage(procfs, pid, exe string) string {
+	if exe == "" {
+		exe, _ = os.Readlink(filepath.Join(procfs, pid, "exe"))
+	}
+	switch name := filepath.Base(exe); {
+	case name == "dotnet":
+		return languageDotNet
+	case pythonExe.MatchString(name):
+		return languagePython
+	}
+
+	// Self-contained .NET applications have their own executable, which loads
+	// the .NET runtime.
+	maps, err := os.ReadFile(filepath.Join(procfs, pid, "maps"))
+	if err == nil && bytes.Contains(maps, []byte("/libcoreclr.so")) {
+		return languageDotNet
+	}
+	return ""
+}
+
+// instrumentationState returns whether a process was launched with the
+// environment of the OpenTelemetry automatic instrumentation of its language.
+func instrumentationState(procfs, pid, language string) string {
+	environ, err := os.ReadFile(filepath.Join(procfs, pid, "environ"))
+	if err != nil {
+		return stateUnknown
+	}
+
+	for _, kv := range bytes.Split(environ, []byte{0}) {
+		k, v, _ := strings.Cut(string(kv), "=")
+		switch {
+		case language == languageDotNet && k == "CORECLR_PROFILER" && strings.EqualFold(v, dotNetProfilerID):
+			return stateInstrumented
+		case language == languagePython && k == "PYTHONPATH" && strings.Contains(v, pythonAutoInstrumentationDir):
+			return stateInstrumented
+		}
+	}
+	return stateNotInstrumented
+}
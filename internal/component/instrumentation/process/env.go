@@ -0,0 +1,100 @@
+package process
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// dotNetProfilerID is the CLSID of the CLR profiler of the OpenTelemetry .NET
+// automatic instrumentation.
+const dotNetProfilerID = "{918728DD-259F-4A6A-AC2B-B85E1B658318}"
+
+// pythonAutoInstrumentationDir is the directory of the sitecustomize module
+// which instruments Python processes, relative to the installation directory
+// of the OpenTelemetry Python distribution.
+const pythonAutoInstrumentationDir = "opentelemetry/instrumentation/auto_instrumentation"
+
+// processEnv returns the environment of a supervised process: the base
+// environment, the environment which enables its automatic instrumentation,
+// and the environment of its process block, in order of precedence.
+func processEnv(args Arguments, p ProcessArguments, base []string) []string {
+	env := make(map[string]string, len(base))
+	for _, kv := range base {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+
+	serviceName := p.ServiceName
+	if serviceName == "" {
+		serviceName = p.Name
+	}
+	env["OTEL_SERVICE_NAME"] = serviceName
+	env["OTEL_EXPORTER_OTLP_ENDPOINT"] = args.OTLPEndpoint
+	env["OTEL_EXPORTER_OTLP_PROTOCOL"] = args.OTLPProtocol
+	env["OTEL_TRACES_EXPORTER"] = "otlp"
+	env["OTEL_METRICS_EXPORTER"] = "otlp"
+	env["OTEL_LOGS_EXPORTER"] = "otlp"
+	if len(args.ResourceAttributes) > 0 {
+		env["OTEL_RESOURCE_ATTRIBUTES"] = resourceAttributes(args.ResourceAttributes)
+	}
+
+	switch p.Language {
+	case languageDotNet:
+		home := args.DotNet.Home
+		env["OTEL_DOTNET_AUTO_HOME"] = home
+		env["CORECLR_ENABLE_PROFILING"] = "1"
+		env["CORECLR_PROFILER"] = dotNetProfilerID
+		env["CORECLR_PROFILER_PATH"] = filepath.Join(home, dotNetPlatform(), "OpenTelemetry.AutoInstrumentation.Native.so")
+		env["DOTNET_ADDITIONAL_DEPS"] = filepath.Join(home, "AdditionalDeps")
+		env["DOTNET_SHARED_STORE"] = filepath.Join(home, "store")
+		env["DOTNET_STARTUP_HOOKS"] = filepath.Join(home, "net", "OpenTelemetry.AutoInstrumentation.StartupHook.dll")
+	case languagePython:
+		path := args.Python.Path
+		pythonPath := filepath.Join(path, pythonAutoInstrumentationDir) + string(filepath.ListSeparator) + path
+		if existing := p.Env["PYTHONPATH"]; existing != "" {
+			pythonPath += string(filepath.ListSeparator) + existing
+		} else if existing := env["PYTHONPATH"]; existing != "" {
+			pythonPath += string(filepath.ListSeparator) + existing
+		}
+		env["PYTHONPATH"] = pythonPath
+		env["OTEL_PYTHON_LOGGING_AUTO_INSTRUMENTATION_ENABLED"] = "true"
+	}
+
+	for k, v := range p.Env {
+		if k == "PYTHONPATH" && p.Language == languagePython {
+			// The path of the process block was merged above.
+			continue
+		}
+		env[k] = v
+	}
+
+	res := make([]string, 0, len(env))
+	for k, v := range env {
+		res = append(res, k+"="+v)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// resourceAttributes formats resource attributes as the value of the
+// OTEL_RESOURCE_ATTRIBUTES environment variable.
+func resourceAttributes(attrs map[string]string) string {
+	pairs := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// dotNetPlatform returns the directory of the native libraries of the
+// OpenTelemetry .NET automatic instrumentation for the current architecture.
+func dotNetPlatform() string {
+	if runtime.GOARCH == "arm64" {
+		return "linux-arm64"
+	}
+	return "linux-x64"
+}
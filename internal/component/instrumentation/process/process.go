@@ -0,0 +1,127 @@
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "instrumentation.process",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   discovery.Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Component implements the instrumentation.process component.
+type Component struct {
+	opts        component.Options
+	procfs      string
+	argsUpdates chan Arguments
+	changed     chan struct{}
+
+	args        Arguments
+	supervisors map[string]*supervisor // By process name.
+}
+
+// New creates a new instrumentation.process component.
+func New(opts component.Options, args Arguments) (*Component, error) {
+	return &Component{
+		opts:        opts,
+		procfs:      "/proc",
+		argsUpdates: make(chan Arguments),
+		changed:     make(chan struct{}, 1),
+		args:        args,
+		supervisors: make(map[string]*supervisor),
+	}, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		for _, s := range c.supervisors {
+			s.stop()
+		}
+	}()
+
+	c.superviseProcesses(ctx)
+	c.export()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case a := <-c.argsUpdates:
+			c.args = a
+			c.superviseProcesses(ctx)
+			c.export()
+		case <-c.changed:
+			c.export()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.argsUpdates <- args.(Arguments)
+	return nil
+}
+
+// superviseProcesses launches the processes of the process blocks. The
+// processes whose process block changed are launched again, the processes
+// whose process block was removed are stopped.
+func (c *Component) superviseProcesses(ctx context.Context) {
+	base := os.Environ()
+	specs := make(map[string]processSpec, len(c.args.Processes))
+	for _, p := range c.args.Processes {
+		specs[p.Name] = processSpec{
+			command:      p.Command,
+			dir:          p.WorkingDir,
+			env:          processEnv(c.args, p, base),
+			restartDelay: p.RestartDelay,
+		}
+	}
+
+	for name, s := range c.supervisors {
+		if spec, ok := specs[name]; !ok || !spec.equal(s.spec) {
+			s.stop()
+			delete(c.supervisors, name)
+		}
+	}
+	for name, spec := range specs {
+		if _, ok := c.supervisors[name]; !ok {
+			c.supervisors[name] = startSupervisor(ctx, log.With(c.opts.Logger, "process", name), spec, c.notifyChanged)
+		}
+	}
+}
+
+func (c *Component) notifyChanged() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Component) export() {
+	launched := make(map[string]struct{}, len(c.supervisors))
+	for _, s := range c.supervisors {
+		if pid := s.PID(); pid != 0 {
+			launched[strconv.Itoa(pid)] = struct{}{}
+		}
+	}
+	c.opts.OnStateChange(discovery.Exports{
+		Targets: classifyTargets(c.procfs, c.args.Targets, launched),
+	})
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func writeProcFile(t *testing.T, procfs, pid, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(procfs, pid), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(procfs, pid, name), []byte(content), 0o644))
+}
+
+func TestClassifyTargets(t *testing.T) {
+	procfs := t.TempDir()
+	writeProcFile(t, procfs, "10", "environ", "HOME=/root\x00CORECLR_PROFILER={918728dd-259f-4a6a-ac2b-b85e1b658318}\x00")
+	writeProcFile(t, procfs, "11", "environ", "HOME=/root\x00")
+	writeProcFile(t, procfs, "12", "maps", "7f0000000000-7f0000001000 r-xp 00000000 08:01 42 /usr/share/dotnet/shared/Microsoft.NETCore.App/8.0.0/libcoreclr.so\n")
+	writeProcFile(t, procfs, "12", "environ", "HOME=/root\x00")
+	writeProcFile(t, procfs, "13", "environ", "PYTHONPATH=/opt/otel-python/opentelemetry/instrumentation/auto_instrumentation:/opt/otel-python\x00")
+	writeProcFile(t, procfs, "15", "maps", "")
+
+	targets := []discovery.Target{
+		{"__process_pid__": "10", "__meta_process_exe": "/usr/bin/dotnet"},
+		{"__process_pid__": "11", "__meta_process_exe": "/usr/bin/python3.11"},
+		{"__process_pid__": "12", "__meta_process_exe": "/srv/checkout/Checkout"},
+		{"__process_pid__": "13", "__meta_process_exe": "/usr/bin/python3"},
+		{"__process_pid__": "14", "__meta_process_exe": "/usr/bin/python3"},
+		{"__process_pid__": "15", "__meta_process_exe": "/usr/bin/java"},
+	}
+	launched := map[string]struct{}{"14": {}}
+
+	require.Equal(t, []discovery.Target{
+		{"__process_pid__": "10", "__meta_process_exe": "/usr/bin/dotnet", labelLanguage: "dotnet", labelState: "instrumented"},
+		{"__process_pid__": "11", "__meta_process_exe": "/usr/bin/python3.11", labelLanguage: "python", labelState: "not_instrumented"},
+		{"__process_pid__": "12", "__meta_process_exe": "/srv/checkout/Checkout", labelLanguage: "dotnet", labelState: "not_instrumented"},
+		{"__process_pid__": "13", "__meta_process_exe": "/usr/bin/python3", labelLanguage: "python", labelState: "instrumented"},
+		{"__process_pid__": "14", "__meta_process_exe": "/usr/bin/python3", labelLanguage: "python", labelState: "launched"},
+	}, classifyTargets(procfs, targets, launched))
+}
+
+func TestSupervisor(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	spec := processSpec{
+		command:      []string{"sh", "-c", `echo "$OTEL_SERVICE_NAME" >> ` + out},
+		env:          []string{"OTEL_SERVICE_NAME=checkout"},
+		restartDelay: 10 * time.Millisecond,
+	}
+	changes := atomic.NewInt32(0)
+	s := startSupervisor(context.Background(), util.TestLogger(t), spec, func() { changes.Inc() })
+
+	// The process is launched again after it exits.
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(out)
+		return err == nil && strings.Count(string(data), "checkout\n") >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	s.stop()
+
+	require.Equal(t, 0, s.PID())
+	require.GreaterOrEqual(t, changes.Load(), int32(4))
+}
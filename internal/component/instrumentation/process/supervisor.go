@@ -0,0 +1,125 @@
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// stopTimeout is how long a supervised process has to exit after it's sent
+// SIGTERM, before it's killed.
+const stopTimeout = 10 * time.Second
+
+// processSpec is how a supervised process is launched.
+type processSpec struct {
+	command      []string
+	dir          string
+	env          []string
+	restartDelay time.Duration
+}
+
+func (s processSpec) equal(other processSpec) bool {
+	return slices.Equal(s.command, other.command) &&
+		s.dir == other.dir &&
+		slices.Equal(s.env, other.env) &&
+		s.restartDelay == other.restartDelay
+}
+
+// supervisor launches a process, and launches it again when it exits.
+type supervisor struct {
+	logger   log.Logger
+	spec     processSpec
+	onChange func()
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mut sync.Mutex
+	pid int
+}
+
+// startSupervisor launches a process and supervises it until stop is called.
+// onChange is called when the process is launched or exits.
+func startSupervisor(ctx context.Context, logger log.Logger, spec processSpec, onChange func()) *supervisor {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &supervisor{
+		logger:   logger,
+		spec:     spec,
+		onChange: onChange,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+func (s *supervisor) run(ctx context.Context) {
+	defer close(s.done)
+
+	for {
+		s.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.spec.restartDelay):
+		}
+	}
+}
+
+// runOnce launches the process and waits for it to exit.
+func (s *supervisor) runOnce(ctx context.Context) {
+	cmd := exec.CommandContext(ctx, s.spec.command[0], s.spec.command[1:]...)
+	cmd.Dir = s.spec.dir
+	cmd.Env = s.spec.env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = stopTimeout
+
+	if err := cmd.Start(); err != nil {
+		level.Error(s.logger).Log("msg", "failed to launch process", "command", s.spec.command[0], "err", err)
+		return
+	}
+	level.Info(s.logger).Log("msg", "launched process", "command", s.spec.command[0], "pid", cmd.Process.Pid)
+	s.setPID(cmd.Process.Pid)
+
+	err := cmd.Wait()
+	s.setPID(0)
+	if ctx.Err() != nil {
+		level.Info(s.logger).Log("msg", "stopped process", "command", s.spec.command[0])
+		return
+	}
+	level.Warn(s.logger).Log("msg", "process exited, launching it again", "command", s.spec.command[0], "err", err, "restart_delay", s.spec.restartDelay)
+}
+
+func (s *supervisor) setPID(pid int) {
+	s.mut.Lock()
+	s.pid = pid
+	s.mut.Unlock()
+	s.onChange()
+}
+
+// PID returns the PID of the process, or 0 if it's not running.
+func (s *supervisor) PID() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.pid
+}
+
+// stop stops the process and waits for it to exit.
+func (s *supervisor) stop() {
+	s.cancel()
+	<-s.done
+}
@@ -0,0 +1,264 @@
+package echo_to_file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/units"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/util/rotatingfile"
+)
+
+const (
+	formatJSON = "json"
+	formatRaw  = "raw"
+
+	// rotateCheckInterval is how often files are checked for time-based
+	// rotation.
+	rotateCheckInterval = 10 * time.Second
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.echo_to_file",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the loki.echo_to_file
+// component.
+type Arguments struct {
+	Path           string           `alloy:"path,attr"`
+	Format         string           `alloy:"format,attr,optional"`
+	MaxSize        units.Base2Bytes `alloy:"max_size,attr,optional"`
+	RotateInterval time.Duration    `alloy:"rotate_interval,attr,optional"`
+	MaxFiles       int              `alloy:"max_files,attr,optional"`
+	Compress       bool             `alloy:"compress,attr,optional"`
+}
+
+// DefaultArguments provides the default arguments for the loki.echo_to_file
+// component.
+var DefaultArguments = Arguments{
+	Format:  formatJSON,
+	MaxSize: 100 * units.MiB,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.Path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if a.Format != formatJSON && a.Format != formatRaw {
+		return fmt.Errorf("format must be one of %q or %q, got %q", formatJSON, formatRaw, a.Format)
+	}
+	if a.MaxSize < 0 {
+		return fmt.Errorf("max_size must not be negative")
+	}
+	if a.RotateInterval < 0 {
+		return fmt.Errorf("rotate_interval must not be negative")
+	}
+	if a.MaxFiles < 0 {
+		return fmt.Errorf("max_files must not be negative")
+	}
+	return nil
+}
+
+// Exports holds the values exported by the loki.echo_to_file component.
+type Exports struct {
+	Receiver loki.LogsReceiver `alloy:"receiver,attr"`
+}
+
+var _ component.Component = (*Component)(nil)
+
+// Component implements the loki.echo_to_file component.
+type Component struct {
+	opts     component.Options
+	metrics  *metrics
+	receiver loki.LogsReceiver
+
+	mut    sync.Mutex
+	args   Arguments
+	file   *rotatingfile.File
+	format string
+}
+
+// New creates a new loki.echo_to_file component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	m, err := newMetrics(o.Registerer)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:     o,
+		metrics:  m,
+		receiver: loki.NewLogsReceiver(),
+	}
+
+	// Call to Update() once at the start.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	ticker := time.NewTicker(rotateCheckInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		c.mut.Lock()
+		defer c.mut.Unlock()
+		if err := c.file.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close file", "path", c.args.Path, "err", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.receiver.Chan():
+			c.write(entry)
+		case now := <-ticker.C:
+			c.mut.Lock()
+			if err := c.file.RotateIfExpired(now); err != nil {
+				level.Error(c.opts.Logger).Log("msg", "failed to rotate file", "path", c.args.Path, "err", err)
+			}
+			c.mut.Unlock()
+		}
+	}
+}
+
+func (c *Component) write(entry loki.Entry) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	line, err := formatEntry(entry, c.format)
+	if err == nil {
+		_, err = c.file.Write(line)
+	}
+	if err != nil {
+		c.metrics.writeErrors.Inc()
+		level.Error(c.opts.Logger).Log("msg", "failed to write log entry", "path", c.args.Path, "err", err)
+		return
+	}
+	c.metrics.entriesWritten.Inc()
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.file != nil {
+		if err := c.file.Close(); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to close file", "path", c.args.Path, "err", err)
+		}
+	}
+
+	c.args = newArgs
+	c.format = newArgs.Format
+	c.file = rotatingfile.New(rotatingfile.Options{
+		Path:     newArgs.Path,
+		MaxSize:  int64(newArgs.MaxSize),
+		MaxAge:   newArgs.RotateInterval,
+		MaxFiles: newArgs.MaxFiles,
+		Compress: newArgs.Compress,
+		OnRotate: c.metrics.rotations.Inc,
+	})
+	return nil
+}
+
+// jsonEntry is the representation of a log entry in the json format.
+type jsonEntry struct {
+	Timestamp          time.Time         `json:"timestamp"`
+	Labels             map[string]string `json:"labels"`
+	StructuredMetadata map[string]string `json:"structured_metadata,omitempty"`
+	Line               string            `json:"line"`
+}
+
+// formatEntry returns the line written to the file for an entry.
+func formatEntry(entry loki.Entry, format string) ([]byte, error) {
+	if format == formatRaw {
+		return []byte(entry.Line + "\n"), nil
+	}
+
+	e := jsonEntry{
+		Timestamp: entry.Timestamp,
+		Labels:    make(map[string]string, len(entry.Labels)),
+		Line:      entry.Line,
+	}
+	for name, value := range entry.Labels {
+		e.Labels[string(name)] = string(value)
+	}
+	if len(entry.StructuredMetadata) > 0 {
+		e.StructuredMetadata = make(map[string]string, len(entry.StructuredMetadata))
+		for _, l := range entry.StructuredMetadata {
+			e.StructuredMetadata[l.Name] = l.Value
+		}
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+type metrics struct {
+	entriesWritten prometheus.Counter
+	writeErrors    prometheus.Counter
+	rotations      prometheus.Counter
+}
+
+func newMetrics(reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		entriesWritten: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "loki_echo_to_file_entries_written_total",
+			Help: "Total number of log entries written to the file.",
+		}),
+		writeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "loki_echo_to_file_write_errors_total",
+			Help: "Total number of log entries which couldn't be written to the file.",
+		}),
+		rotations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "loki_echo_to_file_rotations_total",
+			Help: "Total number of file rotations.",
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.entriesWritten, m.writeErrors, m.rotations} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
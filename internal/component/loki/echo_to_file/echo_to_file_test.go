@@ -0,0 +1,107 @@
+package echo_to_file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cfg: `
+			path            = "/tmp/logs.json"
+			max_size        = "10MiB"
+			rotate_interval = "24h"
+			max_files       = 7
+			compress        = true`,
+		},
+		{
+			name: "invalid format",
+			cfg: `
+			path   = "/tmp/logs.json"
+			format = "xml"`,
+			expectedErr: `format must be one of "json" or "raw", got "xml"`,
+		},
+		{
+			name: "negative max_files",
+			cfg: `
+			path      = "/tmp/logs.json"
+			max_files = -1`,
+			expectedErr: "max_files must not be negative",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.json")
+	args := DefaultArguments
+	args.Path = path
+
+	c, err := New(component.Options{
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+	}, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.receiver.Chan() <- loki.Entry{
+		Labels: model.LabelSet{"job": "app"},
+		Entry: logproto.Entry{
+			Timestamp:          ts,
+			Line:               "hello",
+			StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "1234"}},
+		},
+	}
+
+	var written jsonEntry
+	require.Eventually(t, func() bool {
+		b, err := os.ReadFile(path)
+		if err != nil || len(b) == 0 {
+			return false
+		}
+		require.NoError(t, json.Unmarshal(b, &written))
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, jsonEntry{
+		Timestamp:          ts,
+		Labels:             map[string]string{"job": "app"},
+		StructuredMetadata: map[string]string{"trace_id": "1234"},
+		Line:               "hello",
+	}, written)
+}
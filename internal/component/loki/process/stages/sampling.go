@@ -1,18 +1,24 @@
 package stages
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/uber/jaeger-client-go/utils"
 )
 
 const (
-	ErrSamplingStageInvalidRate = "sampling stage failed to parse rate,Sampling Rate must be between 0.0 and 1.0, received %f"
+	ErrSamplingStageInvalidRate      = "sampling stage failed to parse rate,Sampling Rate must be between 0.0 and 1.0, received %f"
+	ErrSamplingStageInvalidValueRate = "sampling stage rate for value %q must be between 0.0 and 1.0, received %f"
+	ErrSamplingStageRatesNoSource    = "sampling stage config must contain `source` if `rates` is specified"
 )
 const maxRandomNumber = ^(uint64(1) << 63) // i.e. 0x7fffffffffffffff
 
@@ -24,6 +30,16 @@ var (
 type SamplingConfig struct {
 	DropReason   *string `alloy:"drop_counter_reason,attr,optional"`
 	SamplingRate float64 `alloy:"rate,attr"`
+
+	// Source is the extracted field or label whose value selects the sampling
+	// rate from Rates. Entries with any other value are sampled at SamplingRate.
+	Source string             `alloy:"source,attr,optional"`
+	Rates  map[string]float64 `alloy:"rates,attr,optional"`
+
+	// HashBy lists the extracted fields or labels whose values are hashed to
+	// make the sampling decision. Entries sharing the same values are either
+	// all kept or all dropped.
+	HashBy []string `alloy:"hash_by,attr,optional"`
 }
 
 func (s *SamplingConfig) SetToDefault() {
@@ -36,6 +52,14 @@ func (s *SamplingConfig) Validate() error {
 	if s.SamplingRate < 0.0 || s.SamplingRate > 1.0 {
 		return fmt.Errorf(ErrSamplingStageInvalidRate, s.SamplingRate)
 	}
+	if len(s.Rates) > 0 && s.Source == "" {
+		return errors.New(ErrSamplingStageRatesNoSource)
+	}
+	for value, rate := range s.Rates {
+		if rate < 0.0 || rate > 1.0 {
+			return fmt.Errorf(ErrSamplingStageInvalidValueRate, value, rate)
+		}
+	}
 	return nil
 }
 
@@ -43,24 +67,35 @@ func (s *SamplingConfig) Validate() error {
 // code from jaeger project.
 // github.com/uber/jaeger-client-go@v2.30.0+incompatible/tracer.go:126
 func newSamplingStage(logger log.Logger, cfg SamplingConfig, registerer prometheus.Registerer) Stage {
-	samplingRate := math.Max(0.0, math.Min(cfg.SamplingRate, 1.0))
-	samplingBoundary := uint64(float64(maxRandomNumber) * samplingRate)
 	seedGenerator := utils.NewRand(time.Now().UnixNano())
 	source := rand.NewSource(seedGenerator.Int63())
+
+	valueBoundaries := make(map[string]uint64, len(cfg.Rates))
+	for value, rate := range cfg.Rates {
+		valueBoundaries[value] = samplingBoundary(rate)
+	}
+
 	return &samplingStage{
 		logger:           log.With(logger, "component", "stage", "type", "sampling"),
 		cfg:              cfg,
 		dropCount:        getDropCountMetric(registerer),
-		samplingBoundary: samplingBoundary,
+		samplingBoundary: samplingBoundary(cfg.SamplingRate),
+		valueBoundaries:  valueBoundaries,
 		source:           source,
 	}
 }
 
+func samplingBoundary(rate float64) uint64 {
+	samplingRate := math.Max(0.0, math.Min(rate, 1.0))
+	return uint64(float64(maxRandomNumber) * samplingRate)
+}
+
 type samplingStage struct {
 	logger           log.Logger
 	cfg              SamplingConfig
 	dropCount        *prometheus.CounterVec
 	samplingBoundary uint64
+	valueBoundaries  map[string]uint64
 	source           rand.Source
 }
 
@@ -69,7 +104,7 @@ func (m *samplingStage) Run(in chan Entry) chan Entry {
 	go func() {
 		defer close(out)
 		for e := range in {
-			if m.isSampled() {
+			if m.isSampled(e) {
 				out <- e
 				continue
 			}
@@ -82,8 +117,57 @@ func (m *samplingStage) Run(in chan Entry) chan Entry {
 // code from jaeger project.
 // github.com/uber/jaeger-client-go@v2.30.0+incompatible/sampler.go:144
 // func (s *ProbabilisticSampler) IsSampled(id TraceID, operation string) (bool, []Tag)
-func (m *samplingStage) isSampled() bool {
-	return m.samplingBoundary >= m.randomID()&maxRandomNumber
+func (m *samplingStage) isSampled(e Entry) bool {
+	boundary := m.samplingBoundary
+	if m.cfg.Source != "" {
+		if value, ok := lookupEntryValue(e, m.cfg.Source); ok {
+			if b, ok := m.valueBoundaries[value]; ok {
+				boundary = b
+			}
+		}
+	}
+	return boundary >= m.entryID(e)&maxRandomNumber
+}
+
+// entryID returns the ID used to make the sampling decision for an entry. The
+// ID is derived from the values of the hash_by fields when any of them is
+// set, so that related entries get the same decision, and is random
+// otherwise.
+func (m *samplingStage) entryID(e Entry) uint64 {
+	if len(m.cfg.HashBy) == 0 {
+		return m.randomID()
+	}
+
+	var (
+		sb    strings.Builder
+		found bool
+	)
+	for _, name := range m.cfg.HashBy {
+		value, ok := lookupEntryValue(e, name)
+		found = found || ok
+		sb.WriteString(value)
+		sb.WriteByte(0xff)
+	}
+	if !found {
+		return m.randomID()
+	}
+	// Like randomID, never return 0 so that a rate of 0 drops every entry.
+	return max(xxhash.Sum64String(sb.String())&maxRandomNumber, 1)
+}
+
+// lookupEntryValue returns the value of the extracted field called name, or
+// of the label called name if there is no such field.
+func lookupEntryValue(e Entry, name string) (string, bool) {
+	if v, ok := e.Extracted[name]; ok {
+		s, err := getString(v)
+		if err == nil {
+			return s, true
+		}
+	}
+	if v, ok := e.Labels[model.LabelName(name)]; ok {
+		return string(v), true
+	}
+	return "", false
 }
 func (m *samplingStage) randomID() uint64 {
 	val := m.randomNumber()
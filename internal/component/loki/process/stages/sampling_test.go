@@ -1,11 +1,13 @@
 package stages
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -37,6 +39,68 @@ func TestSamplingPipeline(t *testing.T) {
 	assert.LessOrEqual(t, len(out), 70)
 }
 
+var testSamplingByValueAlloy = `
+stage.sampling {
+  rate   = 0.5
+  source = "level"
+  rates  = {
+    "debug" = 0.0,
+    "error" = 1.0,
+  }
+}
+`
+
+func TestSamplingPipeline_ByValue(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testSamplingByValueAlloy), &plName, registry)
+	require.NoError(t, err)
+
+	entries := make([]Entry, 0)
+	for i := 0; i < 100; i++ {
+		// The level is read from extracted fields first, then from labels.
+		entries = append(entries, newEntry(map[string]interface{}{"level": "debug"}, nil, testMatchLogLineApp1, time.Now()))
+		entries = append(entries, newEntry(nil, model.LabelSet{"level": "error"}, testMatchLogLineApp1, time.Now()))
+	}
+
+	out := processEntries(pl, entries...)
+	require.Len(t, out, 100)
+	for _, e := range out {
+		require.Equal(t, model.LabelValue("error"), e.Labels["level"])
+	}
+}
+
+var testSamplingHashByAlloy = `
+stage.sampling {
+  rate    = 0.5
+  hash_by = ["trace_id"]
+}
+`
+
+func TestSamplingPipeline_HashBy(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testSamplingHashByAlloy), &plName, registry)
+	require.NoError(t, err)
+
+	entries := make([]Entry, 0)
+	for i := 0; i < 10; i++ {
+		for trace := 0; trace < 100; trace++ {
+			entries = append(entries, newEntry(map[string]interface{}{"trace_id": fmt.Sprint(trace)}, nil, testMatchLogLineApp1, time.Now()))
+		}
+	}
+
+	out := processEntries(pl, entries...)
+	kept := map[string]int{}
+	for _, e := range out {
+		kept[e.Extracted["trace_id"].(string)]++
+	}
+	// Entries of the same trace are either all kept or all dropped.
+	for trace, n := range kept {
+		require.Equal(t, 10, n, "trace %s", trace)
+	}
+	assert.GreaterOrEqual(t, len(kept), 30)
+	assert.LessOrEqual(t, len(kept), 70)
+}
+
 func Test_validateSamplingConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -50,6 +114,23 @@ func Test_validateSamplingConfig(t *testing.T) {
 			},
 			wantErr: fmt.Errorf(ErrSamplingStageInvalidRate, 12.0),
 		},
+		{
+			name: "Invalid value rate",
+			config: &SamplingConfig{
+				SamplingRate: 1,
+				Source:       "level",
+				Rates:        map[string]float64{"debug": -1},
+			},
+			wantErr: fmt.Errorf(ErrSamplingStageInvalidValueRate, "debug", -1.0),
+		},
+		{
+			name: "Rates without source",
+			config: &SamplingConfig{
+				SamplingRate: 1,
+				Rates:        map[string]float64{"debug": 0.1},
+			},
+			wantErr: errors.New(ErrSamplingStageRatesNoSource),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,216 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/units"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/queue"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.queue",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the loki.queue
+// component.
+type Arguments struct {
+	ForwardTo    []loki.LogsReceiver `alloy:"forward_to,attr"`
+	Policy       queue.Policy        `alloy:"policy,attr,optional"`
+	Capacity     int                 `alloy:"capacity,attr,optional"`
+	MaxSpillSize units.Base2Bytes    `alloy:"max_spill_size,attr,optional"`
+}
+
+// DefaultArguments provides the default arguments for the loki.queue
+// component.
+var DefaultArguments = Arguments{
+	Policy:       queue.PolicyBlock,
+	Capacity:     1000,
+	MaxSpillSize: 256 * units.MiB,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.Capacity <= 0 {
+		return fmt.Errorf("capacity must be greater than 0")
+	}
+	if a.MaxSpillSize <= 0 {
+		return fmt.Errorf("max_spill_size must be greater than 0")
+	}
+	return nil
+}
+
+// Exports holds the values exported by the loki.queue component.
+type Exports struct {
+	Receiver loki.LogsReceiver `alloy:"receiver,attr"`
+}
+
+var _ component.Component = (*Component)(nil)
+
+// Component implements the loki.queue component.
+type Component struct {
+	opts     component.Options
+	receiver loki.LogsReceiver
+	queue    *queue.Queue[loki.Entry]
+
+	mut    sync.RWMutex
+	fanout []loki.LogsReceiver
+}
+
+// New creates a new loki.queue component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	m, err := queue.NewMetrics(o.Registerer, "loki_queue", "log entries")
+	if err != nil {
+		return nil, err
+	}
+	q, err := queue.New[loki.Entry](entryCodec{}, queueOptions(o, args), m)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:     o,
+		receiver: loki.NewLogsReceiver(),
+		queue:    q,
+		fanout:   args.ForwardTo,
+	}
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	return c, nil
+}
+
+func queueOptions(o component.Options, args Arguments) queue.Options {
+	return queue.Options{
+		Policy:       args.Policy,
+		Capacity:     args.Capacity,
+		SpillDir:     filepath.Join(o.DataPath, "spill"),
+		MaxSpillSize: int64(args.MaxSpillSize),
+	}
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		if err := c.queue.Close(); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to close queue", "err", err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.handleOut(ctx)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case entry := <-c.receiver.Chan():
+			err := c.queue.Push(ctx, entry)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				level.Error(c.opts.Logger).Log("msg", "failed to queue log entry", "err", err)
+			}
+		}
+	}
+}
+
+func (c *Component) handleOut(ctx context.Context) {
+	for {
+		entry, err := c.queue.Pop(ctx)
+		if err != nil {
+			return
+		}
+
+		c.mut.RLock()
+		fanout := c.fanout
+		c.mut.RUnlock()
+		for _, f := range fanout {
+			select {
+			case <-ctx.Done():
+				return
+			case f.Chan() <- entry:
+			}
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	if err := c.queue.Update(queueOptions(c.opts, newArgs)); err != nil {
+		return err
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.fanout = newArgs.ForwardTo
+	return nil
+}
+
+// spilledEntry is the representation of a log entry in the spill file.
+type spilledEntry struct {
+	Labels             model.LabelSet          `json:"labels"`
+	Timestamp          time.Time               `json:"timestamp"`
+	Line               string                  `json:"line"`
+	StructuredMetadata []logproto.LabelAdapter `json:"structured_metadata,omitempty"`
+}
+
+// entryCodec encodes log entries into the spill file.
+type entryCodec struct{}
+
+func (entryCodec) Encode(entry loki.Entry) ([]byte, error) {
+	return json.Marshal(spilledEntry{
+		Labels:             entry.Labels,
+		Timestamp:          entry.Timestamp,
+		Line:               entry.Line,
+		StructuredMetadata: entry.StructuredMetadata,
+	})
+}
+
+func (entryCodec) Decode(data []byte) (loki.Entry, error) {
+	var e spilledEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return loki.Entry{}, err
+	}
+	return loki.Entry{
+		Labels: e.Labels,
+		Entry: logproto.Entry{
+			Timestamp:          e.Timestamp,
+			Line:               e.Line,
+			StructuredMetadata: e.StructuredMetadata,
+		},
+	}, nil
+}
@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cfg: `
+			forward_to     = []
+			policy         = "spill_to_disk"
+			capacity       = 10
+			max_spill_size = "1MiB"`,
+		},
+		{
+			name: "invalid policy",
+			cfg: `
+			forward_to = []
+			policy     = "drop"`,
+			expectedErr: `unknown queue policy "drop"`,
+		},
+		{
+			name: "invalid capacity",
+			cfg: `
+			forward_to = []
+			capacity   = 0`,
+			expectedErr: "capacity must be greater than 0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestQueue(t *testing.T) {
+	out := loki.NewLogsReceiver()
+	args := DefaultArguments
+	args.ForwardTo = []loki.LogsReceiver{out}
+	args.Policy = "spill_to_disk"
+	args.Capacity = 1
+
+	c, err := New(component.Options{
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		DataPath:      t.TempDir(),
+		OnStateChange: func(e component.Exports) {},
+	}, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	// Nothing reads from out yet, so the entries after the first ones are
+	// spilled.
+	ts := time.Unix(1700000000, 0).UTC()
+	for i := 0; i < 5; i++ {
+		c.receiver.Chan() <- loki.Entry{
+			Labels: model.LabelSet{"job": "test"},
+			Entry: logproto.Entry{
+				Timestamp:          ts.Add(time.Duration(i) * time.Second),
+				Line:               "line",
+				StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "abc"}},
+			},
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		select {
+		case entry := <-out.Chan():
+			require.Equal(t, model.LabelSet{"job": "test"}, entry.Labels)
+			require.Equal(t, ts.Add(time.Duration(i)*time.Second), entry.Timestamp)
+			require.Equal(t, "line", entry.Line)
+			require.Equal(t, "abc", entry.StructuredMetadata[0].Value)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for log entry")
+		}
+	}
+}
@@ -73,12 +73,17 @@ type Component struct {
 	cache        *lru.Cache
 	maxCacheSize int
 
+	// inputs samples the label sets which missed the cache, so that the
+	// shadow service can evaluate candidate rules against them.
+	inputs *alloy_relabel.Sampler
+
 	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
 var (
-	_ component.Component     = (*Component)(nil)
-	_ component.LiveDebugging = (*Component)(nil)
+	_ component.Component        = (*Component)(nil)
+	_ component.LiveDebugging    = (*Component)(nil)
+	_ alloy_relabel.InputSampler = (*Component)(nil)
 )
 
 // New creates a new loki.relabel component.
@@ -98,6 +103,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		metrics:            newMetrics(o.Registerer),
 		cache:              cache,
 		maxCacheSize:       args.MaxCacheSize,
+		inputs:             alloy_relabel.NewSampler(alloy_relabel.DefaultSamplerSize),
 		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
 	}
 
@@ -237,6 +243,7 @@ func (c *Component) process(e loki.Entry) model.LabelSet {
 			Value: string(v),
 		})
 	}
+	c.inputs.Add(labels.New(lbls...))
 	lbls, _ = relabel.Process(lbls, c.rcs...)
 
 	relabeled := make(model.LabelSet, len(lbls))
@@ -247,3 +254,8 @@ func (c *Component) process(e loki.Entry) model.LabelSet {
 }
 
 func (c *Component) LiveDebugging(_ int) {}
+
+// SampledInputs implements alloy_relabel.InputSampler.
+func (c *Component) SampledInputs() []labels.Labels {
+	return c.inputs.Samples()
+}
@@ -0,0 +1,176 @@
+package secretfilter
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed gitleaks.toml
+var defaultRules string
+
+// gitleaksConfig is the subset of the gitleaks configuration format which is
+// used to detect secrets in log lines.
+type gitleaksConfig struct {
+	Rules     []gitleaksRule    `toml:"rules"`
+	Allowlist gitleaksAllowlist `toml:"allowlist"`
+}
+
+type gitleaksRule struct {
+	ID          string   `toml:"id"`
+	Description string   `toml:"description"`
+	Regex       string   `toml:"regex"`
+	SecretGroup int      `toml:"secretGroup"`
+	Keywords    []string `toml:"keywords"`
+
+	// Older gitleaks versions support a single allowlist per rule, while newer
+	// versions support several of them.
+	Allowlist  gitleaksAllowlist   `toml:"allowlist"`
+	Allowlists []gitleaksAllowlist `toml:"allowlists"`
+}
+
+type gitleaksAllowlist struct {
+	Regexes   []string `toml:"regexes"`
+	StopWords []string `toml:"stopwords"`
+}
+
+// rule detects a type of secret.
+type rule struct {
+	id          string
+	regex       *regexp.Regexp
+	secretGroup int
+	keywords    []string
+	allowlist   *allowlist
+}
+
+// allowlist holds the patterns of the secrets which must not be redacted.
+type allowlist struct {
+	regexes   []*regexp.Regexp
+	stopWords []string
+}
+
+// parseRules parses a gitleaks configuration. Rules without a regex, which
+// only match file paths, are ignored.
+func parseRules(content string) ([]*rule, *allowlist, error) {
+	var cfg gitleaksConfig
+	if _, err := toml.Decode(content, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode rules: %w", err)
+	}
+
+	global, err := newAllowlist(cfg.Allowlist)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid global allowlist: %w", err)
+	}
+
+	rules := make([]*rule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		if r.Regex == "" {
+			continue
+		}
+		if r.ID == "" {
+			return nil, nil, fmt.Errorf("rule with regex %q has no id", r.Regex)
+		}
+
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid regex for rule %q: %w", r.ID, err)
+		}
+		if r.SecretGroup < 0 || r.SecretGroup > re.NumSubexp() {
+			return nil, nil, fmt.Errorf("invalid secretGroup %d for rule %q: the regex has %d groups", r.SecretGroup, r.ID, re.NumSubexp())
+		}
+
+		merged := r.Allowlist
+		for _, al := range r.Allowlists {
+			merged.Regexes = append(merged.Regexes, al.Regexes...)
+			merged.StopWords = append(merged.StopWords, al.StopWords...)
+		}
+		al, err := newAllowlist(merged)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid allowlist for rule %q: %w", r.ID, err)
+		}
+
+		keywords := make([]string, 0, len(r.Keywords))
+		for _, k := range r.Keywords {
+			keywords = append(keywords, strings.ToLower(k))
+		}
+
+		rules = append(rules, &rule{
+			id:          r.ID,
+			regex:       re,
+			secretGroup: r.SecretGroup,
+			keywords:    keywords,
+			allowlist:   al,
+		})
+	}
+	return rules, global, nil
+}
+
+func newAllowlist(cfg gitleaksAllowlist) (*allowlist, error) {
+	al := &allowlist{}
+	for _, expr := range cfg.Regexes {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		al.regexes = append(al.regexes, re)
+	}
+	for _, w := range cfg.StopWords {
+		al.stopWords = append(al.stopWords, strings.ToLower(w))
+	}
+	return al, nil
+}
+
+// allows returns whether secret must not be redacted.
+func (al *allowlist) allows(secret string) bool {
+	if al == nil {
+		return false
+	}
+	for _, re := range al.regexes {
+		if re.MatchString(secret) {
+			return true
+		}
+	}
+	if len(al.stopWords) > 0 {
+		lower := strings.ToLower(secret)
+		for _, w := range al.stopWords {
+			if strings.Contains(lower, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mayMatch returns whether the lowercase line contains any keyword of the
+// rule. Rules without keywords are always evaluated.
+func (r *rule) mayMatch(lowerLine string) bool {
+	if len(r.keywords) == 0 {
+		return true
+	}
+	for _, k := range r.keywords {
+		if strings.Contains(lowerLine, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretIndex returns the start and end index in the line of the secret
+// captured by a match of the rule, given the submatch indexes of the match.
+//
+// Like gitleaks, when no secret group is configured the first non-empty
+// group is the secret, or the whole match when there is none.
+func (r *rule) secretIndex(match []int) (int, int) {
+	if r.secretGroup > 0 {
+		return match[2*r.secretGroup], match[2*r.secretGroup+1]
+	}
+	for i := 1; i < len(match)/2; i++ {
+		if match[2*i] >= 0 && match[2*i+1] > match[2*i] {
+			return match[2*i], match[2*i+1]
+		}
+	}
+	return match[0], match[1]
+}
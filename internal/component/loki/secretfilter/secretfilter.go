@@ -0,0 +1,314 @@
+package secretfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+// secretNamePlaceholder is replaced by the ID of the rule which detected a
+// secret in redact_with.
+const secretNamePlaceholder = "$SECRET_NAME"
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.secretfilter",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the loki.secretfilter
+// component.
+type Arguments struct {
+	// Where the filtered log entries should be forwarded to.
+	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
+
+	// Rules holds additional rules, in the gitleaks configuration format.
+	Rules string `alloy:"rules,attr,optional"`
+
+	// Whether the built-in rules are used.
+	IncludeDefaultRules bool `alloy:"include_default_rules,attr,optional"`
+
+	// Regular expressions matching the secrets which must not be redacted.
+	Allowlist []string `alloy:"allowlist,attr,optional"`
+
+	// The string which replaces secrets.
+	RedactWith string `alloy:"redact_with,attr,optional"`
+
+	// The number of trailing characters of secrets to keep after redaction.
+	PartialMask int `alloy:"partial_mask,attr,optional"`
+}
+
+// DefaultArguments provides the default arguments for the loki.secretfilter
+// component.
+var DefaultArguments = Arguments{
+	IncludeDefaultRules: true,
+	RedactWith:          "<REDACTED-SECRET:" + secretNamePlaceholder + ">",
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if !a.IncludeDefaultRules && strings.TrimSpace(a.Rules) == "" {
+		return fmt.Errorf("rules must be set when include_default_rules is false")
+	}
+	if a.PartialMask < 0 {
+		return fmt.Errorf("partial_mask must not be negative")
+	}
+	for _, expr := range a.Allowlist {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid allowlist regex %q: %w", expr, err)
+		}
+	}
+	return nil
+}
+
+// Exports holds values which are exported by the loki.secretfilter component.
+type Exports struct {
+	Receiver loki.LogsReceiver `alloy:"receiver,attr"`
+}
+
+// Component implements the loki.secretfilter component.
+type Component struct {
+	opts     component.Options
+	metrics  *metrics
+	receiver loki.LogsReceiver
+
+	mut    sync.RWMutex
+	filter *filter
+	fanout []loki.LogsReceiver
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new loki.secretfilter component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	m, err := newMetrics(o.Registerer)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:     o,
+		metrics:  m,
+		receiver: loki.NewLogsReceiver(),
+	}
+
+	// Call to Update() to load the rules once at the start.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.receiver.Chan():
+			c.mut.RLock()
+			f, fanout := c.filter, c.fanout
+			c.mut.RUnlock()
+
+			entry.Line = f.redact(entry.Line)
+			for _, r := range fanout {
+				select {
+				case <-ctx.Done():
+					return nil
+				case r.Chan() <- entry:
+				}
+			}
+		}
+	}
+}
+
+// Update implements component.Component. Invalid rules are rejected, and the
+// previous rules keep being applied.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	f, err := newFilter(newArgs, c.metrics)
+	if err != nil {
+		return err
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.filter = f
+	c.fanout = newArgs.ForwardTo
+	c.metrics.rules.Set(float64(len(f.rules)))
+	return nil
+}
+
+// filter redacts the secrets detected by a set of rules.
+type filter struct {
+	rules       []*rule
+	allowlist   *allowlist
+	redactWith  string
+	partialMask int
+	metrics     *metrics
+}
+
+func newFilter(args Arguments, m *metrics) (*filter, error) {
+	f := &filter{
+		allowlist:   &allowlist{},
+		redactWith:  args.RedactWith,
+		partialMask: args.PartialMask,
+		metrics:     m,
+	}
+
+	var packs []string
+	if args.IncludeDefaultRules {
+		packs = append(packs, defaultRules)
+	}
+	if strings.TrimSpace(args.Rules) != "" {
+		packs = append(packs, args.Rules)
+	}
+
+	ids := make(map[string]struct{})
+	for _, pack := range packs {
+		rules, al, err := parseRules(pack)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rules {
+			// Rules of the rules argument override the default rules with the
+			// same ID.
+			if _, ok := ids[r.id]; ok {
+				f.rules = removeRule(f.rules, r.id)
+			}
+			ids[r.id] = struct{}{}
+			f.rules = append(f.rules, r)
+		}
+		f.allowlist.regexes = append(f.allowlist.regexes, al.regexes...)
+		f.allowlist.stopWords = append(f.allowlist.stopWords, al.stopWords...)
+	}
+
+	for _, expr := range args.Allowlist {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist regex %q: %w", expr, err)
+		}
+		f.allowlist.regexes = append(f.allowlist.regexes, re)
+	}
+	return f, nil
+}
+
+func removeRule(rules []*rule, id string) []*rule {
+	res := rules[:0]
+	for _, r := range rules {
+		if r.id != id {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// redact returns line with all the secrets detected by the rules redacted.
+func (f *filter) redact(line string) string {
+	lower := strings.ToLower(line)
+	for _, r := range f.rules {
+		if !r.mayMatch(lower) {
+			continue
+		}
+		matches := r.regex.FindAllStringSubmatchIndex(line, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var (
+			sb      strings.Builder
+			last    int
+			changed bool
+		)
+		for _, match := range matches {
+			start, end := r.secretIndex(match)
+			if start < 0 || start < last {
+				continue
+			}
+			secret := line[start:end]
+			if r.allowlist.allows(secret) || f.allowlist.allows(secret) {
+				f.metrics.secretsAllowlisted.WithLabelValues(r.id).Inc()
+				continue
+			}
+
+			sb.WriteString(line[last:start])
+			sb.WriteString(f.mask(r.id, secret))
+			last = end
+			changed = true
+			f.metrics.secretsRedacted.WithLabelValues(r.id).Inc()
+		}
+		if changed {
+			sb.WriteString(line[last:])
+			line = sb.String()
+			lower = strings.ToLower(line)
+		}
+	}
+	return line
+}
+
+// mask returns the replacement of a secret detected by the rule with ID id.
+// The trailing characters of the secret are kept when partial masking is
+// enabled, as long as they represent at most half of the secret.
+func (f *filter) mask(id, secret string) string {
+	replacement := strings.ReplaceAll(f.redactWith, secretNamePlaceholder, id)
+	if f.partialMask > 0 && len(secret) >= 2*f.partialMask {
+		replacement += secret[len(secret)-f.partialMask:]
+	}
+	return replacement
+}
+
+type metrics struct {
+	secretsRedacted    *prometheus.CounterVec
+	secretsAllowlisted *prometheus.CounterVec
+	rules              prometheus.Gauge
+}
+
+func newMetrics(reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		secretsRedacted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_secretfilter_secrets_redacted_total",
+			Help: "Total number of secrets redacted, by rule.",
+		}, []string{"rule"}),
+		secretsAllowlisted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_secretfilter_secrets_allowlisted_total",
+			Help: "Total number of secrets which weren't redacted because of an allowlist, by rule.",
+		}, []string{"rule"}),
+		rules: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "loki_secretfilter_rules",
+			Help: "Number of rules currently loaded.",
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.secretsRedacted, m.secretsAllowlisted, m.rules} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
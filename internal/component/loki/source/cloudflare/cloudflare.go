@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/component/common/state"
 	cft "github.com/grafana/alloy/internal/component/loki/source/cloudflare/internal/cloudflaretarget"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
@@ -45,6 +46,8 @@ type Arguments struct {
 	PullRange        time.Duration       `alloy:"pull_range,attr,optional"`
 	FieldsType       string              `alloy:"fields_type,attr,optional"`
 	AdditionalFields []string            `alloy:"additional_fields,attr,optional"`
+	BackfillStart    string              `alloy:"backfill_start,attr,optional"`
+	MaxBackfill      time.Duration       `alloy:"max_backfill,attr,optional"`
 	ForwardTo        []loki.LogsReceiver `alloy:"forward_to,attr"`
 }
 
@@ -54,6 +57,8 @@ func (c Arguments) Convert() *cft.Config {
 	for k, v := range c.Labels {
 		lbls[model.LabelName(k)] = model.LabelValue(v)
 	}
+	// The backfill start was validated.
+	backfillStart, _ := parseBackfillStart(c.BackfillStart)
 	return &cft.Config{
 		APIToken:         string(c.APIToken),
 		ZoneID:           c.ZoneID,
@@ -62,14 +67,26 @@ func (c Arguments) Convert() *cft.Config {
 		PullRange:        model.Duration(c.PullRange),
 		FieldsType:       c.FieldsType,
 		AdditionalFields: c.AdditionalFields,
+		BackfillStart:    backfillStart,
+		MaxBackfill:      c.MaxBackfill,
 	}
 }
 
 // DefaultArguments sets the configuration defaults.
 var DefaultArguments = Arguments{
-	Workers:    3,
-	PullRange:  1 * time.Minute,
-	FieldsType: string(cft.FieldsTypeDefault),
+	Workers:     3,
+	PullRange:   1 * time.Minute,
+	FieldsType:  string(cft.FieldsTypeDefault),
+	MaxBackfill: 24 * time.Hour,
+}
+
+// parseBackfillStart parses the backfill_start argument, which is either
+// empty or an RFC 3339 timestamp.
+func parseBackfillStart(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -84,7 +101,13 @@ func (c *Arguments) Validate() error {
 	}
 	_, err := cft.Fields(cft.FieldsType(c.FieldsType), c.AdditionalFields)
 	if err != nil {
-		return fmt.Errorf("invalid fields_type set; the available values are 'default', 'minimal', 'extended', 'custom' and 'all'")
+		return fmt.Errorf("invalid fields_type set; the available values are 'default', 'minimal', 'extended', 'custom', 'all' and 'auto'")
+	}
+	if _, err := parseBackfillStart(c.BackfillStart); err != nil {
+		return fmt.Errorf("backfill_start must be an RFC 3339 timestamp: %w", err)
+	}
+	if c.MaxBackfill < 0 {
+		return fmt.Errorf("max_backfill must not be negative")
 	}
 	return nil
 }
@@ -98,6 +121,7 @@ type Component struct {
 	fanout []loki.LogsReceiver
 	target *cft.Target
 
+	store   *state.Store
 	posFile positions.Positions
 	handler loki.LogsReceiver
 }
@@ -108,13 +132,18 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:        10 * time.Second,
 		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
 		IgnoreInvalidYaml: false,
 		ReadOnly:          false,
 	})
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
@@ -123,6 +152,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		metrics: cft.NewMetrics(o.Registerer),
 		handler: loki.NewLogsReceiver(),
 		fanout:  args.ForwardTo,
+		store:   store,
 		posFile: positionsFile,
 	}
 
@@ -140,6 +170,10 @@ func (c *Component) Run(ctx context.Context) error {
 		c.mut.RLock()
 		level.Info(c.opts.Logger).Log("msg", "loki.source.cloudflare component shutting down, stopping the target")
 		c.target.Stop()
+		c.posFile.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close state store", "err", err)
+		}
 		c.mut.RUnlock()
 	}()
 
@@ -7,9 +7,15 @@ package cloudflaretarget
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/grafana/cloudflare-go"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // Client is a wrapper around the Cloudflare API that allow for testing and being zone/fields aware.
@@ -40,3 +46,45 @@ var getClient = func(apiKey, zoneID string, fields []string) (Client, error) {
 		fields: fields,
 	}, nil
 }
+
+// cloudflareAPIURL is the base URL of the Cloudflare API.
+var cloudflareAPIURL = "https://api.cloudflare.com/client/v4"
+
+// newHTTPClient returns a client of the Cloudflare API which gives up on
+// requests after timeout. Like the client of the Cloudflare API library, it
+// uses the proxy set by the environment.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+		Timeout:   timeout,
+	}
+}
+
+// getAvailableFields returns the fields of the logs which are available for
+// the zone, sorted by name.
+var getAvailableFields = func(ctx context.Context, client *http.Client, apiToken, zoneID string) ([]string, error) {
+	u := cloudflareAPIURL + "/zones/" + url.PathEscape(zoneID) + "/logs/received/fields"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	// The response maps the name of each field to its description.
+	var fields map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	names := maps.Keys(fields)
+	slices.Sort(names)
+	return names, nil
+}
@@ -21,6 +21,9 @@ const (
 	FieldsTypeExtended FieldsType = "extended"
 	FieldsTypeAll      FieldsType = "all"
 	FieldsTypeCustom   FieldsType = "custom"
+	// FieldsTypeAuto uses all the fields which are available for the zone,
+	// which are detected by the target.
+	FieldsTypeAuto FieldsType = "auto"
 )
 
 var (
@@ -46,6 +49,7 @@ var (
 )
 
 // Fields returns the union of a set of fields represented by the Fieldtype and the given additional fields. The returned slice will contain no duplicates.
+// For FieldsTypeAuto, only the additional fields are returned.
 func Fields(t FieldsType, additionalFields []string) ([]string, error) {
 	var fields []string
 	switch t {
@@ -57,7 +61,7 @@ func Fields(t FieldsType, additionalFields []string) ([]string, error) {
 		fields = append(extendedFields, additionalFields...)
 	case FieldsTypeAll:
 		fields = append(allFields, additionalFields...)
-	case FieldsTypeCustom:
+	case FieldsTypeCustom, FieldsTypeAuto:
 		fields = append(fields, additionalFields...)
 	default:
 		return nil, fmt.Errorf("unknown fields type: %s", t)
@@ -6,9 +6,14 @@ package cloudflaretarget
 // components.
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFields(t *testing.T) {
@@ -30,6 +35,12 @@ func TestFields(t *testing.T) {
 			additionalFields: []string{"ClientIP", "OriginResponseBytes"},
 			expected:         []string{"ClientIP", "OriginResponseBytes"},
 		},
+		{
+			name:             "Auto fields",
+			fieldsType:       FieldsTypeAuto,
+			additionalFields: []string{"ClientIP", "ClientIP"},
+			expected:         []string{"ClientIP"},
+		},
 		{
 			name:             "Default fields with added custom fields",
 			fieldsType:       FieldsTypeDefault,
@@ -52,3 +63,34 @@ func TestFields(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAvailableFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones/bar/logs/received/fields" || r.Header.Get("Authorization") != "Bearer foo" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"RayID":"ID of the request","ClientIP":"IP address of the client"}`))
+	}))
+	defer srv.Close()
+
+	oldURL := cloudflareAPIURL
+	cloudflareAPIURL = srv.URL
+	defer func() { cloudflareAPIURL = oldURL }()
+
+	fields, err := getAvailableFields(context.Background(), srv.Client(), "foo", "bar")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ClientIP", "RayID"}, fields)
+
+	_, err = getAvailableFields(context.Background(), srv.Client(), "wrong", "bar")
+	require.ErrorContains(t, err, "403")
+
+	// The request gives up after the timeout of the client.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+	cloudflareAPIURL = slow.URL
+	_, err = getAvailableFields(context.Background(), newHTTPClient(50*time.Millisecond), "foo", "bar")
+	require.ErrorContains(t, err, "Client.Timeout exceeded")
+}
@@ -7,6 +7,7 @@ package cloudflaretarget
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strings"
 	"sync"
@@ -21,6 +22,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/prometheus/common/model"
 	"go.uber.org/atomic"
+	"golang.org/x/exp/slices"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
@@ -32,6 +34,9 @@ const minDelay = time.Minute
 
 var cloudflareTooEarlyError = regexp.MustCompile(`too early: logs older than \S+ are not available`)
 
+// detectFieldsTimeout is the timeout of the detection of the available fields.
+const detectFieldsTimeout = 30 * time.Second
+
 var defaultBackoff = backoff.Config{
 	MinBackoff: 1 * time.Second,
 	MaxBackoff: 10 * time.Second,
@@ -47,6 +52,32 @@ type Config struct {
 	PullRange        model.Duration
 	FieldsType       string
 	AdditionalFields []string
+	// BackfillStart is where pulling logs starts when there's no position
+	// for the zone. Pulling starts from now when it's zero.
+	BackfillStart time.Time
+	// MaxBackfill is how far in the past pulling logs can start. Older logs
+	// are skipped. There is no limit when it's zero.
+	MaxBackfill time.Duration
+}
+
+// initialEnd returns the end of the first pull interval, given the stored
+// position and the current time, and whether it was moved forward to respect
+// the maximum backfill.
+func (c *Config) initialEnd(pos int64, now time.Time) (time.Time, bool) {
+	to := now
+	switch {
+	case pos != 0:
+		to = time.Unix(0, pos)
+	case !c.BackfillStart.IsZero():
+		to = c.BackfillStart.Add(time.Duration(c.PullRange))
+	}
+	if c.MaxBackfill > 0 {
+		earliest := now.Add(-c.MaxBackfill).Add(time.Duration(c.PullRange))
+		if to.Before(earliest) {
+			return earliest, true
+		}
+	}
+	return to, false
 }
 
 // Target enables pulling HTTP log messages from Cloudflare using the Logpull
@@ -57,6 +88,7 @@ type Target struct {
 	positions positions.Positions
 	config    *Config
 	metrics   *Metrics
+	fields    []string
 
 	client  Client
 	ctx     context.Context
@@ -73,6 +105,15 @@ func NewTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, p
 	if err != nil {
 		return nil, err
 	}
+	if FieldsType(config.FieldsType) == FieldsTypeAuto {
+		available, err := getAvailableFields(context.Background(), newHTTPClient(detectFieldsTimeout), config.APIToken, config.ZoneID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect the available fields: %w", err)
+		}
+		fields = append(fields, available...)
+		slices.Sort(fields)
+		fields = slices.Compact(fields)
+	}
 	client, err := getClient(config.APIToken, config.ZoneID, fields)
 	if err != nil {
 		return nil, err
@@ -81,9 +122,9 @@ func NewTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, p
 	if err != nil {
 		return nil, err
 	}
-	to := time.Now()
-	if pos != 0 {
-		to = time.Unix(0, pos)
+	to, skipped := config.initialEnd(pos, time.Now())
+	if skipped {
+		level.Warn(logger).Log("msg", "skipping logs older than the maximum backfill", "zone_id", config.ZoneID, "max_backfill", config.MaxBackfill, "start", to.Add(-time.Duration(config.PullRange)))
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	t := &Target{
@@ -92,6 +133,7 @@ func NewTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, p
 		positions: position,
 		config:    config,
 		metrics:   metrics,
+		fields:    fields,
 
 		ctx:     ctx,
 		cancel:  cancel,
@@ -224,7 +266,6 @@ func (t *Target) Ready() bool {
 
 // Details returns debug details about the Cloudflare target.
 func (t *Target) Details() map[string]string {
-	fields, _ := Fields(FieldsType(t.config.FieldsType), t.config.AdditionalFields)
 	var errMsg string
 	if t.err != nil {
 		errMsg = t.err.Error()
@@ -234,7 +275,7 @@ func (t *Target) Details() map[string]string {
 		"error":          errMsg,
 		"position":       t.positions.GetString(positions.CursorKey(t.config.ZoneID), t.config.Labels.String()),
 		"last_timestamp": t.to.String(),
-		"fields":         strings.Join(fields, ","),
+		"fields":         strings.Join(t.fields, ","),
 	}
 }
 
@@ -8,6 +8,7 @@ package cloudflaretarget
 import (
 	"context"
 	"errors"
+	"net/http"
 	"os"
 	"sort"
 	"testing"
@@ -340,3 +341,120 @@ func Test_splitRequests(t *testing.T) {
 		})
 	}
 }
+
+func Test_CloudflareTargetAutoFields(t *testing.T) {
+	var (
+		logger = log.NewNopLogger()
+		cfg    = &Config{
+			APIToken:         "foo",
+			ZoneID:           "bar",
+			Labels:           model.LabelSet{"job": "cloudflare"},
+			PullRange:        model.Duration(time.Minute),
+			FieldsType:       string(FieldsTypeAuto),
+			AdditionalFields: []string{"RayID", "CustomField"},
+			Workers:          3,
+		}
+		client   = fake.NewClient(func() {})
+		cfClient = newFakeCloudflareClient()
+	)
+	ps, err := positions.New(logger, positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: t.TempDir() + "/positions.yml",
+	})
+	require.NoError(t, err)
+
+	cfClient.On("LogpullReceived", mock.Anything, mock.Anything, mock.Anything).Return(&fakeLogIterator{
+		logs: []string{},
+	}, nil)
+	var clientFields []string
+	getClient = func(apiKey, zoneID string, fields []string) (Client, error) {
+		clientFields = fields
+		return cfClient, nil
+	}
+	oldGetAvailableFields := getAvailableFields
+	defer func() { getAvailableFields = oldGetAvailableFields }()
+	getAvailableFields = func(ctx context.Context, client *http.Client, apiToken, zoneID string) ([]string, error) {
+		require.Equal(t, "foo", apiToken)
+		require.Equal(t, "bar", zoneID)
+		return []string{"ClientIP", "RayID"}, nil
+	}
+
+	ta, err := NewTarget(NewMetrics(prometheus.NewRegistry()), logger, client, ps, cfg)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ClientIP", "CustomField", "RayID"}, clientFields)
+	require.Equal(t, "ClientIP,CustomField,RayID", ta.Details()["fields"])
+	ta.Stop()
+	ps.Stop()
+
+	// The target isn't created when the fields can't be detected.
+	getAvailableFields = func(ctx context.Context, client *http.Client, apiToken, zoneID string) ([]string, error) {
+		return nil, errors.New("forbidden")
+	}
+	_, err = NewTarget(NewMetrics(prometheus.NewRegistry()), logger, client, ps, cfg)
+	require.ErrorContains(t, err, "forbidden")
+}
+
+func Test_initialEnd(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		pos           int64
+		backfillStart time.Time
+		maxBackfill   time.Duration
+		want          time.Time
+		wantSkipped   bool
+	}{
+		{
+			name: "no position",
+			want: now,
+		},
+		{
+			name: "position",
+			pos:  now.Add(-time.Hour).UnixNano(),
+			want: now.Add(-time.Hour),
+		},
+		{
+			name:          "position takes precedence over backfill start",
+			pos:           now.Add(-time.Hour).UnixNano(),
+			backfillStart: now.Add(-2 * time.Hour),
+			want:          now.Add(-time.Hour),
+		},
+		{
+			name:          "backfill start",
+			backfillStart: now.Add(-2 * time.Hour),
+			maxBackfill:   24 * time.Hour,
+			want:          now.Add(-2 * time.Hour).Add(time.Minute),
+		},
+		{
+			name:          "backfill start older than max backfill",
+			backfillStart: now.Add(-7 * 24 * time.Hour),
+			maxBackfill:   24 * time.Hour,
+			want:          now.Add(-24 * time.Hour).Add(time.Minute),
+			wantSkipped:   true,
+		},
+		{
+			name:        "position older than max backfill",
+			pos:         now.Add(-48 * time.Hour).UnixNano(),
+			maxBackfill: 24 * time.Hour,
+			want:        now.Add(-24 * time.Hour).Add(time.Minute),
+			wantSkipped: true,
+		},
+		{
+			name: "no max backfill",
+			pos:  now.Add(-48 * time.Hour).UnixNano(),
+			want: now.Add(-48 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				PullRange:     model.Duration(time.Minute),
+				BackfillStart: tt.backfillStart,
+				MaxBackfill:   tt.maxBackfill,
+			}
+			got, skipped := cfg.initialEnd(tt.pos, now)
+			require.True(t, tt.want.Equal(got), "expected %s, got %s", tt.want, got)
+			require.Equal(t, tt.wantSkipped, skipped)
+		})
+	}
+}
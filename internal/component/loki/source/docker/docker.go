@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/component/discovery"
 	dt "github.com/grafana/alloy/internal/component/loki/source/docker/internal/dockertarget"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -108,6 +109,7 @@ type Component struct {
 	manager       *manager
 	lastOptions   *options
 	handler       loki.LogsReceiver
+	store         *state.Store
 	posFile       positions.Positions
 	rcs           []*relabel.Config
 	defaultLabels model.LabelSet
@@ -122,13 +124,18 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:        10 * time.Second,
 		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
 		IgnoreInvalidYaml: false,
 		ReadOnly:          false,
 	})
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
@@ -139,6 +146,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		handler:   loki.NewLogsReceiver(),
 		manager:   newManager(o.Logger, nil),
 		receivers: args.ForwardTo,
+		store:     store,
 		posFile:   positionsFile,
 	}
 
@@ -152,7 +160,12 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 // Run implements component.Component.
 func (c *Component) Run(ctx context.Context) error {
-	defer c.posFile.Stop()
+	defer func() {
+		c.posFile.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close state store", "err", err)
+		}
+	}()
 
 	defer func() {
 		c.mut.Lock()
@@ -107,8 +107,10 @@ func TestDuplicateTargets(t *testing.T) {
 
 func TestRestart(t *testing.T) {
 	runningState := true
+	firstLine := "2024-05-02T13:11:55.879889Z caller=module_service.go:114 msg=\"module stopped\" module=distributor"
+	logs := firstLine
 	client := clientMock{
-		logLine: "2024-05-02T13:11:55.879889Z caller=module_service.go:114 msg=\"module stopped\" module=distributor",
+		logs:    func() string { return logs },
 		running: func() bool { return runningState },
 	}
 	expectedLogLine := "caller=module_service.go:114 msg=\"module stopped\" module=distributor"
@@ -131,12 +133,13 @@ func TestRestart(t *testing.T) {
 	time.Sleep(targetRestartInterval + 10*time.Millisecond)
 	assert.Empty(t, entryHandler.Received()) // No log lines because the container was not running.
 
-	// Restart the container and expect log lines.
+	// Restart the container and expect only the new log lines.
+	logs = firstLine + "\n" + "2024-05-02T13:12:01.123456Z caller=module_service.go:57 msg=\"module started\" module=distributor"
 	runningState = true
 	assert.EventuallyWithT(t, func(c *assert.CollectT) {
 		logLines := entryHandler.Received()
-		if assert.NotEmpty(c, logLines) {
-			assert.Equal(c, expectedLogLine, logLines[0].Line)
+		if assert.Len(c, logLines, 1) {
+			assert.Equal(c, "caller=module_service.go:57 msg=\"module started\" module=distributor", logLines[0].Line)
 		}
 	}, time.Second, 20*time.Millisecond, "Expected log lines were not found within the time limit after restart.")
 }
@@ -144,7 +147,9 @@ func TestRestart(t *testing.T) {
 func TestTargetNeverStarted(t *testing.T) {
 	runningState := false
 	client := clientMock{
-		logLine: "2024-05-02T13:11:55.879889Z caller=module_service.go:114 msg=\"module stopped\" module=distributor",
+		logs: func() string {
+			return "2024-05-02T13:11:55.879889Z caller=module_service.go:114 msg=\"module stopped\" module=distributor"
+		},
 		running: func() bool { return runningState },
 	}
 
@@ -191,7 +196,7 @@ func setupTailer(t *testing.T, client clientMock) (tailer *tailer, entryHandler
 
 type clientMock struct {
 	client.APIClient
-	logLine string
+	logs    func() string
 	running func() bool
 }
 
@@ -208,5 +213,5 @@ func (mock clientMock) ContainerInspect(ctx context.Context, c string) (types.Co
 }
 
 func (mock clientMock) ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error) {
-	return io.NopCloser(strings.NewReader(mock.logLine)), nil
+	return io.NopCloser(strings.NewReader(mock.logs())), nil
 }
@@ -0,0 +1,79 @@
+package dockertarget
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/loki/positions"
+)
+
+// offset records how far the logs of a container were read. It's stored in
+// the positions file as JSON, keyed by the container ID only, so that
+// relabeling a container doesn't cause its logs to be read again.
+type offset struct {
+	// Generation identifies the run of the container which the offset was
+	// saved during: the time the container was last started at, as reported
+	// by Docker.
+	Generation string `json:"generation,omitempty"`
+	// Timestamp is the timestamp in nanoseconds of the last line read.
+	Timestamp int64 `json:"timestamp"`
+	// Lines is the number of lines read with Timestamp. Docker can only be
+	// asked for the lines from a timestamp onwards, so these lines are
+	// skipped when reading again from Timestamp.
+	Lines int `json:"lines"`
+}
+
+// positionsEntry returns the entry of the positions file holding the offset
+// of containerID.
+func positionsEntry(containerID string) positions.Entry {
+	return positions.Entry{Path: positions.CursorKey(containerID)}
+}
+
+// readOffset reads the offset of containerID from ps. Offsets saved by older
+// versions, which are keyed by the labels of the container too, are
+// converted and removed.
+func readOffset(ps positions.Positions, containerID, labels string) (offset, error) {
+	entry := positionsEntry(containerID)
+	if s := ps.GetString(entry.Path, entry.Labels); s != "" {
+		var off offset
+		err := json.Unmarshal([]byte(s), &off)
+		return off, err
+	}
+
+	// Older versions saved the timestamp in seconds of the last line read,
+	// and read again all the lines of that second.
+	legacy, err := ps.Get(positions.CursorKey(containerID), labels)
+	if err != nil || legacy == 0 {
+		return offset{}, err
+	}
+	off := offset{Timestamp: time.Unix(legacy, 0).UnixNano()}
+	writeOffset(ps, containerID, off)
+	ps.Remove(positions.CursorKey(containerID), labels)
+	return off, nil
+}
+
+func writeOffset(ps positions.Positions, containerID string, off offset) {
+	b, _ := json.Marshal(off)
+	entry := positionsEntry(containerID)
+	ps.PutString(entry.Path, entry.Labels, string(b))
+}
+
+// advance returns the offset after reading a line with timestamp ts.
+func (o offset) advance(ts time.Time) offset {
+	if ns := ts.UnixNano(); ns != o.Timestamp {
+		o.Timestamp, o.Lines = ns, 0
+	}
+	o.Lines++
+	return o
+}
+
+// since formats the timestamp of o for the since parameter of the Docker
+// API, which accepts nanoseconds after a decimal point.
+func (o offset) since() string {
+	if o.Timestamp == 0 {
+		return "0"
+	}
+	ts := time.Unix(0, o.Timestamp)
+	return fmt.Sprintf("%d.%09d", ts.Unix(), ts.Nanosecond())
+}
@@ -40,7 +40,6 @@ const (
 type Target struct {
 	logger        log.Logger
 	handler       loki.EntryHandler
-	since         int64
 	positions     positions.Positions
 	containerName string
 	labels        model.LabelSet
@@ -53,24 +52,25 @@ type Target struct {
 	wg      sync.WaitGroup
 	running *atomic.Bool
 	err     error
+
+	mut     sync.Mutex
+	offset  offset // How far the logs were read.
+	resume  offset // Where the current process loop resumed reading from.
+	resumed bool   // Whether the current process loop read past resume.
 }
 
 // NewTarget starts a new target to read logs from a given container ID.
 func NewTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, position positions.Positions, containerID string, labels model.LabelSet, relabelConfig []*relabel.Config, client client.APIClient) (*Target, error) {
 	labelsStr := labels.String()
-	pos, err := position.Get(positions.CursorKey(containerID), labelsStr)
+	off, err := readOffset(position, containerID, labelsStr)
 	if err != nil {
 		return nil, err
 	}
-	var since int64
-	if pos != 0 {
-		since = pos
-	}
 
 	t := &Target{
 		logger:        logger,
 		handler:       handler,
-		since:         since,
+		offset:        off,
 		positions:     position,
 		containerName: containerID,
 		labels:        labels,
@@ -95,19 +95,23 @@ func (t *Target) processLoop(ctx context.Context) {
 	t.wg.Add(1)
 	defer t.wg.Done()
 
-	opts := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Follow:     true,
-		Timestamps: true,
-		Since:      strconv.FormatInt(t.since, 10),
-	}
 	inspectInfo, err := t.client.ContainerInspect(ctx, t.containerName)
 	if err != nil {
 		level.Error(t.logger).Log("msg", "could not inspect container info", "container", t.containerName, "err", err)
 		t.err = err
 		return
 	}
+	var generation string
+	if inspectInfo.ContainerJSONBase != nil && inspectInfo.State != nil {
+		generation = inspectInfo.State.StartedAt
+	}
+	opts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Timestamps: true,
+		Since:      t.resumeFrom(generation).since(),
+	}
 	logs, err := t.client.ContainerLogs(ctx, t.containerName, opts)
 	if err != nil {
 		level.Error(t.logger).Log("msg", "could not fetch logs for container", "container", t.containerName, "err", err)
@@ -203,6 +207,9 @@ func (t *Target) process(r io.Reader, logStreamLset model.LabelSet) {
 			t.metrics.dockerErrors.Inc()
 			continue
 		}
+		if t.alreadyRead(ts) {
+			continue
+		}
 
 		t.handler.Chan() <- loki.Entry{
 			Labels: logStreamLset,
@@ -213,15 +220,60 @@ func (t *Target) process(r io.Reader, logStreamLset model.LabelSet) {
 		}
 		t.metrics.dockerEntries.Inc()
 
-		// NOTE(@tpaschalis) We don't save the positions entry with the
-		// filtered labels, but with the default label set, as this is the one
-		// used to find the original read offset from the client. This might be
-		// problematic if we have the same container with a different set of
-		// labels (e.g. duplicated and relabeled), but this shouldn't be the
-		// case anyway.
-		t.positions.Put(positions.CursorKey(t.containerName), t.labelsStr, ts.Unix())
-		t.since = ts.Unix()
+		t.mut.Lock()
+		t.offset = t.offset.advance(ts)
+		off := t.offset
+		t.mut.Unlock()
+		writeOffset(t.positions, t.containerName, off)
+	}
+}
+
+// resumeFrom prepares reading the logs of the container again, now that its
+// current run started at generation, and returns the offset to request the
+// logs from.
+func (t *Target) resumeFrom(generation string) offset {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	t.resume, t.resumed = t.offset, false
+	since := t.offset
+	if generation != "" && t.offset.Generation != "" && generation != t.offset.Generation {
+		// The container was restarted since the offset was saved. If the clock
+		// of the host went back, the lines of its new run are older than the
+		// offset, so they're requested too.
+		startedAt, err := time.Parse(time.RFC3339Nano, generation)
+		if ns := startedAt.UnixNano(); err == nil && ns < since.Timestamp {
+			since = offset{Timestamp: ns}
+		}
+	}
+	if generation != "" {
+		t.offset.Generation = generation
+	}
+	return since
+}
+
+// alreadyRead returns whether a line with timestamp ts was read before the
+// current process loop started. Docker returns the lines from the timestamp of
+// the last line read onwards, so the lines up to the last line read are
+// skipped. Docker returns the lines in the order they were written, so all
+// the lines after it are new, even if their timestamp is older.
+func (t *Target) alreadyRead(ts time.Time) bool {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if t.resumed {
+		return false
 	}
+	switch ns := ts.UnixNano(); {
+	case ns < t.resume.Timestamp:
+		return true
+	case ns == t.resume.Timestamp && t.resume.Lines > 0:
+		t.resume.Lines--
+		t.resumed = t.resume.Lines == 0
+		return true
+	}
+	t.resumed = true
+	return false
 }
 
 // StartIfNotRunning starts processing container logs. The operation is idempotent , i.e. the processing cannot be started twice.
@@ -253,6 +305,12 @@ func (t *Target) LabelsStr() string {
 	return t.labelsStr
 }
 
+// PositionsEntry returns the entry of the positions file which holds the read
+// offset of the target.
+func (t *Target) PositionsEntry() positions.Entry {
+	return positionsEntry(t.containerName)
+}
+
 // Name reports the container name.
 func (t *Target) Name() string {
 	return t.containerName
@@ -274,10 +332,11 @@ func (t *Target) Details() map[string]string {
 	if t.err != nil {
 		errMsg = t.err.Error()
 	}
+	entry := t.PositionsEntry()
 	return map[string]string{
 		"id":       t.containerName,
 		"error":    errMsg,
-		"position": t.positions.GetString(positions.CursorKey(t.containerName), t.labelsStr),
+		"position": t.positions.GetString(entry.Path, entry.Labels),
 		"running":  strconv.FormatBool(t.running.Load()),
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -138,3 +139,86 @@ func containsString(slice []string, str string) bool {
 	}
 	return false
 }
+
+func TestDockerTarget_Resume(t *testing.T) {
+	var (
+		mut       sync.Mutex
+		logs      []string
+		startedAt = "2024-05-02T13:00:00Z"
+	)
+	h := func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		if strings.HasSuffix(r.URL.Path, "/logs") {
+			// Return the whole log regardless of since, to check that the lines
+			// which were already read are skipped.
+			_, err := w.Write([]byte(strings.Join(logs, "\n") + "\n"))
+			require.NoError(t, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		info := types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				State: &types.ContainerState{Running: true, StartedAt: startedAt},
+			},
+			Config: &container.Config{Tty: true},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(info))
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(h))
+	defer ts.Close()
+
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	client, err := client.NewClientWithOpts(client.WithHost(ts.URL))
+	require.NoError(t, err)
+
+	ps, err := positions.New(logger, positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: t.TempDir() + "/positions.yml",
+	})
+	require.NoError(t, err)
+	defer ps.Stop()
+
+	// run reads the logs with a new target, as after a restart of Alloy, and
+	// returns the lines it sent.
+	run := func(labels model.LabelSet) []string {
+		entryHandler := fake.NewClient(func() {})
+		tgt, err := NewTarget(NewMetrics(prometheus.NewRegistry()), logger, entryHandler, ps, "flog", labels, nil, client)
+		require.NoError(t, err)
+		tgt.StartIfNotRunning()
+		require.Eventually(t, func() bool { return !tgt.Ready() }, 5*time.Second, 10*time.Millisecond)
+
+		var lines []string
+		for _, entry := range entryHandler.Received() {
+			lines = append(lines, entry.Line)
+		}
+		return lines
+	}
+
+	mut.Lock()
+	logs = []string{
+		"2024-05-02T13:00:01.000000001Z first",
+		"2024-05-02T13:00:01.000000001Z second",
+	}
+	mut.Unlock()
+	require.ElementsMatch(t, []string{"first", "second"}, run(model.LabelSet{"job": "docker"}))
+
+	// Lines with the timestamp of the last line read are only skipped if they
+	// were read, and relabeling the container doesn't read its logs again.
+	mut.Lock()
+	logs = append(logs,
+		"2024-05-02T13:00:01.000000001Z third",
+		"2024-05-02T13:00:02Z fourth",
+	)
+	mut.Unlock()
+	require.Equal(t, []string{"third", "fourth"}, run(model.LabelSet{"job": "docker", "env": "prod"}))
+
+	// The container was restarted, and the clock of the host went back.
+	mut.Lock()
+	startedAt = "2024-05-02T13:00:00.5Z"
+	logs = append(logs, "2024-05-02T13:00:00.6Z fifth")
+	mut.Unlock()
+	require.Equal(t, []string{"fifth"}, run(model.LabelSet{"job": "docker"}))
+}
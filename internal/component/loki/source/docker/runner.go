@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
@@ -23,6 +24,9 @@ type manager struct {
 	mut   sync.Mutex
 	opts  *options
 	tasks []*tailerTask
+	// gone holds the positions entries of the containers which are no longer
+	// targets, until the containers are removed.
+	gone map[positions.Entry]string
 
 	runner *runner.Runner[*tailerTask]
 }
@@ -36,6 +40,7 @@ func newManager(l log.Logger, opts *options) *manager {
 	return &manager{
 		log:  l,
 		opts: opts,
+		gone: make(map[positions.Entry]string),
 		runner: runner.New(func(t *tailerTask) runner.Worker {
 			return newTailer(l, t)
 		}),
@@ -149,38 +154,50 @@ func (m *manager) syncTargets(ctx context.Context, targets []*dt.Target) error {
 		}
 	}
 
-	// Delete positions for targets which have gone away.
+	// Delete positions for targets which have gone away. We do this _after_
+	// calling ApplyTasks to ensure that the old tailers have shut down,
+	// otherwise the tailer might write its position again during shutdown
+	// after we removed it.
 	newEntries := make(map[positions.Entry]struct{}, len(targets))
 	for _, target := range targets {
-		newEntries[entryForTarget(target)] = struct{}{}
+		newEntries[target.PositionsEntry()] = struct{}{}
 	}
-
 	for _, task := range m.tasks {
-		ent := entryForTarget(task.target)
-
-		// The task from the last call to SyncTargets is no longer in newEntries;
-		// remove it from the positions file. We do this _after_ calling ApplyTasks
-		// to ensure that the old tailers have shut down, otherwise the tailer
-		// might write its position again during shutdown after we removed it.
-		if _, found := newEntries[ent]; !found {
-			level.Info(m.log).Log("msg", "removing entry from positions file", "path", ent.Path, "labels", ent.Labels)
-			m.opts.positions.Remove(ent.Path, ent.Labels)
+		if ent := task.target.PositionsEntry(); !hasEntry(newEntries, ent) {
+			m.gone[ent] = task.target.Name()
+		}
+	}
+	for ent, containerID := range m.gone {
+		if hasEntry(newEntries, ent) {
+			delete(m.gone, ent)
+			continue
+		}
+		// Keep the positions of containers which still exist, such as stopped
+		// containers, so that their logs aren't read again if they start again.
+		if m.opts == nil || !m.containerRemoved(ctx, containerID) {
+			continue
 		}
+		level.Info(m.log).Log("msg", "removing entry from positions file", "path", ent.Path, "labels", ent.Labels)
+		m.opts.positions.Remove(ent.Path, ent.Labels)
+		delete(m.gone, ent)
 	}
 
 	m.tasks = tasks
 	return nil
 }
 
-func entryForTarget(t *dt.Target) positions.Entry {
-	// The positions entry is keyed by container_id; the path is fed into
-	// positions.CursorKey to treat it as a "cursor"; otherwise
-	// positions.Positions will try to read the path as a file and delete the
-	// entry when it can't find it.
-	return positions.Entry{
-		Path:   positions.CursorKey(t.Name()),
-		Labels: t.LabelsStr(),
-	}
+func hasEntry(entries map[positions.Entry]struct{}, ent positions.Entry) bool {
+	_, found := entries[ent]
+	return found
+}
+
+// containerRemoved returns whether Docker reports that containerID doesn't
+// exist anymore.
+func (m *manager) containerRemoved(ctx context.Context, containerID string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := m.opts.client.ContainerInspect(ctx, containerID)
+	return errdefs.IsNotFound(err)
 }
 
 // updateOptions updates the Options shared with all Tailers. All Tailers will
@@ -20,6 +20,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/klauspost/compress/zstd"
 	"github.com/prometheus/common/model"
 	"go.uber.org/atomic"
 	"golang.org/x/text/encoding"
@@ -36,6 +37,7 @@ func supportedCompressedFormats() map[string]struct{} {
 		"gz":  {},
 		"z":   {},
 		"bz2": {},
+		"zst": {},
 		// TODO: add support for zip.
 	}
 }
@@ -62,6 +64,7 @@ type decompressor struct {
 	position int64
 	size     int64
 	cfg      DecompressionConfig
+	readOnce bool
 }
 
 func newDecompressor(
@@ -73,6 +76,7 @@ func newDecompressor(
 	labels string,
 	encodingFormat string,
 	cfg DecompressionConfig,
+	readOnce bool,
 ) (*decompressor, error) {
 
 	logger = log.With(logger, "component", "decompressor")
@@ -106,6 +110,7 @@ func newDecompressor(
 		position:  pos,
 		decoder:   decoder,
 		cfg:       cfg,
+		readOnce:  readOnce,
 	}
 
 	go decompressor.readLines()
@@ -133,6 +138,13 @@ func mountReader(f *os.File, logger log.Logger, format CompressionFormat) (reade
 	case "bz2":
 		decompressLib = "bzip2"
 		reader = bzip2.NewReader(f)
+	case "zst":
+		decompressLib = "github.com/klauspost/compress/zstd"
+		var decoder *zstd.Decoder
+		decoder, err = zstd.NewReader(f)
+		if err == nil {
+			reader = decoder.IOReadCloser()
+		}
 	}
 
 	if err != nil && err != io.EOF {
@@ -206,6 +218,9 @@ func (d *decompressor) readLines() {
 		level.Error(d.logger).Log("msg", "error mounting new reader", "err", err)
 		return
 	}
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
 
 	level.Info(d.logger).Log("msg", "successfully mounted reader", "path", d.path, "ext", filepath.Ext(d.path))
 
@@ -219,14 +234,6 @@ func (d *decompressor) readLines() {
 			break
 		}
 
-		if scannerErr := scanner.Err(); scannerErr != nil {
-			if scannerErr != io.EOF {
-				level.Error(d.logger).Log("msg", "error scanning", "err", scannerErr)
-			}
-
-			break
-		}
-
 		if line <= int(d.position) {
 			// skip already seen lines.
 			continue
@@ -256,11 +263,33 @@ func (d *decompressor) readLines() {
 			},
 		}
 
+		d.posAndSizeMtx.Lock()
 		d.size = int64(unsafe.Sizeof(finalText))
 		d.position++
+		d.posAndSizeMtx.Unlock()
+	}
+
+	if err := scanner.Err(); err != nil {
+		level.Error(d.logger).Log("msg", "error scanning", "err", err)
+		return
+	}
+
+	if d.readOnce {
+		d.finish()
 	}
 }
 
+// finish records that the file was fully read in read_once mode.
+func (d *decompressor) finish() {
+	d.posAndSizeMtx.Lock()
+	defer d.posAndSizeMtx.Unlock()
+
+	d.positions.Put(d.path, d.labels, d.position)
+	markDone(d.positions, d.path, d.labels)
+	d.metrics.filesDone.Inc()
+	level.Info(d.logger).Log("msg", "finished reading file", "path", d.path)
+}
+
 func (d *decompressor) MarkPositionAndSize() error {
 	// Lock this update as there are 2 timers calling this routine, the sync in filetarget and the positions sync in this file.
 	d.posAndSizeMtx.Lock()
@@ -161,6 +161,31 @@ func TestOnelineFiles(t *testing.T) {
 		require.Equal(t, string(fileContent), entries[0].Line)
 	})
 
+	t.Run("zstd file", func(t *testing.T) {
+		file := "testdata/onelinelog.log.zst"
+		handler := fake.NewClient(func() {})
+		defer handler.Stop()
+
+		d := &decompressor{
+			logger:  log.NewNopLogger(),
+			running: atomic.NewBool(false),
+			handler: handler,
+			path:    file,
+			done:    make(chan struct{}),
+			metrics: newMetrics(prometheus.NewRegistry()),
+			cfg:     DecompressionConfig{Format: "zst"},
+		}
+
+		d.readLines()
+
+		<-d.done
+		time.Sleep(time.Millisecond * 200)
+
+		entries := handler.Received()
+		require.Equal(t, 1, len(entries))
+		require.Equal(t, string(fileContent), entries[0].Line)
+	})
+
 	t.Run("tar.gz file", func(t *testing.T) {
 		file := "testdata/onelinelog.tar.gz"
 		handler := fake.NewClient(func() {})
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
@@ -45,6 +46,7 @@ type Arguments struct {
 	DecompressionConfig DecompressionConfig `alloy:"decompression,block,optional"`
 	FileWatch           FileWatch           `alloy:"file_watch,block,optional"`
 	TailFromEnd         bool                `alloy:"tail_from_end,attr,optional"`
+	ReadOnce            bool                `alloy:"read_once,attr,optional"`
 	LegacyPositionsFile string              `alloy:"legacy_positions_file,attr,optional"`
 }
 
@@ -65,6 +67,14 @@ func (a *Arguments) SetToDefault() {
 	*a = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.ReadOnce && a.TailFromEnd {
+		return fmt.Errorf("read_once and tail_from_end can't be used together")
+	}
+	return nil
+}
+
 type DecompressionConfig struct {
 	Enabled      bool              `alloy:"enabled,attr"`
 	InitialDelay time.Duration     `alloy:"initial_delay,attr,optional"`
@@ -84,6 +94,7 @@ type Component struct {
 	args      Arguments
 	handler   loki.LogsReceiver
 	receivers []loki.LogsReceiver
+	store     *state.Store
 	posFile   positions.Positions
 	readers   map[positions.Entry]reader
 }
@@ -99,13 +110,18 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if args.LegacyPositionsFile != "" {
 		positions.ConvertLegacyPositionsFile(args.LegacyPositionsFile, newPositionsPath, o.Logger)
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:        10 * time.Second,
 		PositionsFile:     newPositionsPath,
 		IgnoreInvalidYaml: false,
 		ReadOnly:          false,
 	})
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
@@ -115,6 +131,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 		handler:   loki.NewLogsReceiver(),
 		receivers: args.ForwardTo,
+		store:     store,
 		posFile:   positionsFile,
 		readers:   make(map[positions.Entry]reader),
 	}
@@ -139,6 +156,9 @@ func (c *Component) Run(ctx context.Context) error {
 			r.Stop()
 		}
 		c.posFile.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close state store", "err", err)
+		}
 		close(c.handler.Chan())
 		c.mut.RUnlock()
 	}()
@@ -233,6 +253,7 @@ func (c *Component) Update(args component.Arguments) error {
 	// are no longer in the updated set of Targets.
 	for r := range missing(c.readers, oldPaths) {
 		c.posFile.Remove(r.Path, r.Labels)
+		removeDone(c.posFile, r.Path, r.Labels)
 	}
 
 	return nil
@@ -307,7 +328,8 @@ func missing(as map[positions.Entry]reader, bs map[positions.Entry]struct{}) map
 
 // startTailing starts and returns a reader for the given path. For most files,
 // this will be a tailer implementation. If the file suffix alludes to it being
-// a compressed file, then a decompressor will be started instead.
+// a compressed file, then a decompressor will be started instead. Files which
+// were fully read in read_once mode get a reader which doesn't read anything.
 func (c *Component) startTailing(path string, labels model.LabelSet, handler loki.EntryHandler) (reader, error) {
 	fi, err := os.Stat(path)
 	if err != nil {
@@ -322,6 +344,11 @@ func (c *Component) startTailing(path string, labels model.LabelSet, handler lok
 		return nil, fmt.Errorf("failed to tail file, it was a directory %s", path)
 	}
 
+	if c.args.ReadOnce && isDone(c.posFile, path, labels.String()) {
+		level.Debug(c.opts.Logger).Log("msg", "skipping file which was already read", "filename", path)
+		return doneReader{path: path}, nil
+	}
+
 	var reader reader
 	if c.args.DecompressionConfig.Enabled {
 		level.Debug(c.opts.Logger).Log("msg", "reading from compressed file", "filename", path)
@@ -334,6 +361,7 @@ func (c *Component) startTailing(path string, labels model.LabelSet, handler lok
 			labels.String(),
 			c.args.Encoding,
 			c.args.DecompressionConfig,
+			c.args.ReadOnce,
 		)
 		if err != nil {
 			level.Error(c.opts.Logger).Log("msg", "failed to start decompressor", "error", err, "filename", path)
@@ -356,6 +384,7 @@ func (c *Component) startTailing(path string, labels model.LabelSet, handler lok
 			c.args.Encoding,
 			pollOptions,
 			c.args.TailFromEnd,
+			c.args.ReadOnce,
 		)
 		if err != nil {
 			level.Error(c.opts.Logger).Log("msg", "failed to start tailer", "error", err, "filename", path)
@@ -4,7 +4,6 @@ package file
 
 import (
 	"context"
-	"errors"
 	"log"
 	"os"
 	"path/filepath"
@@ -231,19 +230,17 @@ func TestTwoTargets(t *testing.T) {
 	require.True(t, foundF1)
 	require.True(t, foundF2)
 	cancel()
-	// Verify that positions.yml is written. NOTE: if we didn't wait for it, there would be a race condition between
-	// temporary directory being cleaned up and this file being created.
+	// Verify that the positions are written. NOTE: if we didn't wait for them, there would be a race condition between
+	// temporary directory being cleaned up and this file being written.
 	require.Eventually(
 		t,
 		func() bool {
-			if _, err := os.Stat(filepath.Join(opts.DataPath, "positions.yml")); errors.Is(err, os.ErrNotExist) {
-				return false
-			}
-			return true
+			fi, err := os.Stat(filepath.Join(opts.DataPath, "state", "state.log"))
+			return err == nil && fi.Size() > 0
 		},
 		5*time.Second,
 		10*time.Millisecond,
-		"expected positions.yml file to be written eventually",
+		"expected positions to be written eventually",
 	)
 }
 
@@ -298,19 +295,71 @@ func TestEncoding(t *testing.T) {
 	// Shut down the component
 	cancel()
 
-	// Verify that positions.yml is written. NOTE: if we didn't wait for it,
-	// there would be a race condition between temporary directory being
-	// cleaned up and this file being created.
+	// Verify that the positions are written. NOTE: if we didn't wait for
+	// them, there would be a race condition between temporary directory being
+	// cleaned up and this file being written.
 	require.Eventually(
 		t,
 		func() bool {
-			if _, err := os.Stat(filepath.Join(opts.DataPath, "positions.yml")); errors.Is(err, os.ErrNotExist) {
-				return false
-			}
-			return true
+			fi, err := os.Stat(filepath.Join(opts.DataPath, "state", "state.log"))
+			return err == nil && fi.Size() > 0
 		},
 		5*time.Second,
 		10*time.Millisecond,
-		"expected positions.yml file to be written eventually",
+		"expected positions to be written eventually",
 	)
 }
+
+func TestReadOnce(t *testing.T) {
+	opts := component.Options{
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+		DataPath:      t.TempDir(),
+	}
+
+	path := filepath.Join(opts.DataPath, "archive.log")
+	require.NoError(t, os.WriteFile(path, []byte("first\nsecond\n"), 0600))
+
+	ch1 := loki.NewLogsReceiver()
+	args := DefaultArguments
+	args.Targets = []discovery.Target{{"__path__": path, "foo": "bar"}}
+	args.ForwardTo = []loki.LogsReceiver{ch1}
+	args.ReadOnce = true
+
+	c, err := New(opts, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case logEntry := <-ch1.Chan():
+			require.Equal(t, want, logEntry.Line)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "failed waiting for log line")
+		}
+	}
+
+	labels := model.LabelSet{"foo": "bar"}.String()
+	require.Eventually(t, func() bool {
+		return isDone(c.posFile, path, labels)
+	}, 5*time.Second, 10*time.Millisecond, "expected file to be marked as done")
+
+	// Lines written to a file which is done aren't read, even once the file
+	// is read again.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Write([]byte("third\n"))
+	require.NoError(t, err)
+	require.NoError(t, c.Update(args))
+
+	select {
+	case logEntry := <-ch1.Chan():
+		require.FailNow(t, "unexpected log line", logEntry.Line)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
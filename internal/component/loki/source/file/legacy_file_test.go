@@ -133,20 +133,11 @@ configs:
 	require.NoError(t, err)
 	require.NotNil(t, c)
 
-	// Before we actually start the component check to see if the legacy file and new file are logically the same.
-	buf, err := os.ReadFile(filepath.Join(opts.DataPath, "positions.yml"))
-	require.NoError(t, err)
-	newPositions := positions.File{Positions: make(map[positions.Entry]string)}
-	err = yaml.UnmarshalStrict(buf, newPositions)
-	require.NoError(t, err)
-
-	require.Len(t, newPositions.Positions, 1)
+	// Before we actually start the component check to see if the legacy positions were imported.
 	require.Len(t, legacy.Positions, 1)
 
-	for k, v := range newPositions.Positions {
-		val, found := legacy.Positions[k.Path]
-		require.True(t, found)
-		require.True(t, val == v)
+	for k, v := range legacy.Positions {
+		require.Equal(t, v, c.posFile.GetString(k, "{}"))
 	}
 
 	// Write some data, we should see this data but not old data.
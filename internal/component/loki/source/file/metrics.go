@@ -17,6 +17,7 @@ type metrics struct {
 	readLines        *prometheus.CounterVec
 	encodingFailures *prometheus.CounterVec
 	filesActive      prometheus.Gauge
+	filesDone        prometheus.Counter
 }
 
 // newMetrics creates a new set of file metrics. If reg is non-nil, the metrics
@@ -45,6 +46,10 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 		Name: "loki_source_file_files_active_total",
 		Help: "Number of active files.",
 	})
+	m.filesDone = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "loki_source_file_files_done_total",
+		Help: "Number of files fully read in read_once mode.",
+	})
 
 	if reg != nil {
 		reg.MustRegister(
@@ -53,6 +58,7 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			m.readLines,
 			m.encodingFailures,
 			m.filesActive,
+			m.filesDone,
 		)
 	}
 
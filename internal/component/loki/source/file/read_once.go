@@ -0,0 +1,52 @@
+package file
+
+import (
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/loki/positions"
+)
+
+// doneLabelsSuffix is appended to the labels of the positions entry of a file
+// to build the entry recording that the file was fully read in read_once
+// mode. The entry has the path of the file, so it's removed from the
+// positions file together with the position of the file once the file is
+// deleted.
+const doneLabelsSuffix = ":done"
+
+// doneEntry returns the positions entry recording that the file at path was
+// fully read in read_once mode.
+func doneEntry(path, labels string) positions.Entry {
+	return positions.Entry{Path: path, Labels: labels + doneLabelsSuffix}
+}
+
+// isDone reports whether the file at path was fully read in read_once mode.
+func isDone(ps positions.Positions, path, labels string) bool {
+	e := doneEntry(path, labels)
+	return ps.GetString(e.Path, e.Labels) != ""
+}
+
+// markDone records that the file at path was fully read in read_once mode.
+func markDone(ps positions.Positions, path, labels string) {
+	e := doneEntry(path, labels)
+	ps.PutString(e.Path, e.Labels, time.Now().UTC().Format(time.RFC3339))
+}
+
+// removeDone removes the record that the file at path was fully read.
+func removeDone(ps positions.Positions, path, labels string) {
+	e := doneEntry(path, labels)
+	ps.Remove(e.Path, e.Labels)
+}
+
+// doneReader is the reader of a file which was fully read in read_once mode.
+// It doesn't read anything, and keeps the file in the set of readers of the
+// component so that its position isn't removed.
+type doneReader struct {
+	path string
+}
+
+var _ reader = doneReader{}
+
+func (r doneReader) Stop()                      {}
+func (r doneReader) IsRunning() bool            { return false }
+func (r doneReader) Path() string               { return r.path }
+func (r doneReader) MarkPositionAndSize() error { return nil }
@@ -41,6 +41,12 @@ type tailer struct {
 	posAndSizeMtx sync.Mutex
 	stopOnce      sync.Once
 
+	// readOnce is set to read the file up to its end only, and finished once
+	// it was.
+	readOnce bool
+	finished *atomic.Bool
+	stopping *atomic.Bool
+
 	running *atomic.Bool
 	posquit chan struct{}
 	posdone chan struct{}
@@ -50,7 +56,7 @@ type tailer struct {
 }
 
 func newTailer(metrics *metrics, logger log.Logger, handler loki.EntryHandler, positions positions.Positions, path string,
-	labels string, encoding string, pollOptions watch.PollingFileWatcherOptions, tailFromEnd bool) (*tailer, error) {
+	labels string, encoding string, pollOptions watch.PollingFileWatcherOptions, tailFromEnd bool, readOnce bool) (*tailer, error) {
 	// Simple check to make sure the file we are tailing doesn't
 	// have a position already saved which is past the end of the file.
 	fi, err := os.Stat(path)
@@ -77,10 +83,12 @@ func newTailer(metrics *metrics, logger log.Logger, handler loki.EntryHandler, p
 		}
 	}
 
+	// In read_once mode the file isn't followed, so that the tail stops at the
+	// end of the file.
 	tail, err := tail.TailFile(path, tail.Config{
-		Follow:    true,
+		Follow:    !readOnce,
 		Poll:      true,
-		ReOpen:    true,
+		ReOpen:    !readOnce,
 		MustExist: true,
 		Location: &tail.SeekInfo{
 			Offset: pos,
@@ -102,6 +110,9 @@ func newTailer(metrics *metrics, logger log.Logger, handler loki.EntryHandler, p
 		path:      path,
 		labels:    labels,
 		tail:      tail,
+		readOnce:  readOnce,
+		finished:  atomic.NewBool(false),
+		stopping:  atomic.NewBool(false),
 		running:   atomic.NewBool(false),
 		posquit:   make(chan struct{}),
 		posdone:   make(chan struct{}),
@@ -238,6 +249,12 @@ func (t *tailer) readLines() {
 	for {
 		line, ok := <-t.tail.Lines
 		if !ok {
+			// The tail stops without error both at the end of the file and
+			// when the tailer is stopped.
+			if t.readOnce && !t.stopping.Load() && t.tail.Wait() == nil {
+				t.finish()
+				return
+			}
 			level.Info(t.logger).Log("msg", "tail routine: tail channel closed, stopping tailer", "path", t.path, "reason", t.tail.Tomb.Err())
 			return
 		}
@@ -272,11 +289,33 @@ func (t *tailer) readLines() {
 	}
 }
 
+// finish records that the file was read up to its end in read_once mode.
+func (t *tailer) finish() {
+	t.posAndSizeMtx.Lock()
+	defer t.posAndSizeMtx.Unlock()
+
+	// The tail is closed once the end of the file is reached, so the position
+	// is the size of the file.
+	if fi, err := os.Stat(t.path); err == nil {
+		t.positions.Put(t.path, t.labels, fi.Size())
+	}
+	markDone(t.positions, t.path, t.labels)
+	t.finished.Store(true)
+	t.metrics.filesDone.Inc()
+	level.Info(t.logger).Log("msg", "tail routine: finished reading file", "path", t.path)
+}
+
 func (t *tailer) MarkPositionAndSize() error {
 	// Lock this update as there are 2 timers calling this routine, the sync in filetarget and the positions sync in this file.
 	t.posAndSizeMtx.Lock()
 	defer t.posAndSizeMtx.Unlock()
 
+	// The tail of a finished file is closed, and its position was saved when
+	// it finished.
+	if t.finished.Load() {
+		return nil
+	}
+
 	size, err := t.tail.Size()
 	if err != nil {
 		// If the file no longer exists, no need to save position information
@@ -304,6 +343,8 @@ func (t *tailer) Stop() {
 	// stop can be called by two separate threads in filetarget, to avoid a panic closing channels more than once
 	// we wrap the stop in a sync.Once.
 	t.stopOnce.Do(func() {
+		t.stopping.Store(true)
+
 		// Save the current position before shutting down tailer
 		err := t.MarkPositionAndSize()
 		if err != nil {
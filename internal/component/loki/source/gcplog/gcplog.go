@@ -34,10 +34,11 @@ func init() {
 // Arguments holds values which are used to configure the loki.source.gcplog
 // component.
 type Arguments struct {
-	PullTarget   *gcptypes.PullConfig `alloy:"pull,block,optional"`
-	PushTarget   *gcptypes.PushConfig `alloy:"push,block,optional"`
-	ForwardTo    []loki.LogsReceiver  `alloy:"forward_to,attr"`
-	RelabelRules alloy_relabel.Rules  `alloy:"relabel_rules,attr,optional"`
+	PullTarget          *gcptypes.PullConfig `alloy:"pull,block,optional"`
+	PushTarget          *gcptypes.PushConfig `alloy:"push,block,optional"`
+	ForwardTo           []loki.LogsReceiver  `alloy:"forward_to,attr"`
+	DeadLetterForwardTo []loki.LogsReceiver  `alloy:"dead_letter_forward_to,attr,optional"`
+	RelabelRules        alloy_relabel.Rules  `alloy:"relabel_rules,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -59,21 +60,25 @@ type Component struct {
 	metrics       *gt.Metrics
 	serverMetrics *util.UncheckedCollector
 
-	mut    sync.RWMutex
-	fanout []loki.LogsReceiver
-	target gt.Target
+	mut              sync.RWMutex
+	fanout           []loki.LogsReceiver
+	deadLetterFanout []loki.LogsReceiver
+	target           gt.Target
 
-	handler loki.LogsReceiver
+	handler           loki.LogsReceiver
+	deadLetterHandler loki.LogsReceiver
 }
 
 // New creates a new loki.source.gcplog component.
 func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
-		opts:          o,
-		metrics:       gt.NewMetrics(o.Registerer),
-		handler:       loki.NewLogsReceiver(),
-		fanout:        args.ForwardTo,
-		serverMetrics: util.NewUncheckedCollector(nil),
+		opts:              o,
+		metrics:           gt.NewMetrics(o.Registerer),
+		handler:           loki.NewLogsReceiver(),
+		deadLetterHandler: loki.NewLogsReceiver(),
+		fanout:            args.ForwardTo,
+		deadLetterFanout:  args.DeadLetterForwardTo,
+		serverMetrics:     util.NewUncheckedCollector(nil),
 	}
 
 	o.Registerer.MustRegister(c.serverMetrics)
@@ -108,6 +113,12 @@ func (c *Component) Run(ctx context.Context) error {
 				receiver.Chan() <- entry
 			}
 			c.mut.RUnlock()
+		case entry := <-c.deadLetterHandler.Chan():
+			c.mut.RLock()
+			for _, receiver := range c.deadLetterFanout {
+				receiver.Chan() <- entry
+			}
+			c.mut.RUnlock()
 		}
 	}
 }
@@ -119,6 +130,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.deadLetterFanout = newArgs.DeadLetterForwardTo
 
 	var rcs []*relabel.Config
 	if newArgs.RelabelRules != nil && len(newArgs.RelabelRules) > 0 {
@@ -132,12 +144,18 @@ func (c *Component) Update(args component.Arguments) error {
 		}
 	}
 	entryHandler := loki.NewEntryHandler(c.handler.Chan(), func() {})
+	// Messages which can't be decoded are only forwarded if there are dead
+	// letter receivers.
+	var deadLetterHandler loki.EntryHandler
+	if len(newArgs.DeadLetterForwardTo) > 0 {
+		deadLetterHandler = loki.NewEntryHandler(c.deadLetterHandler.Chan(), func() {})
+	}
 	jobName := strings.Replace(c.opts.ID, ".", "_", -1)
 
 	if newArgs.PullTarget != nil {
 		// TODO(@tpaschalis) Are there any options from "google.golang.org/api/option"
 		// we should expose as configuration and pass here?
-		t, err := gt.NewPullTarget(c.metrics, c.opts.Logger, entryHandler, jobName, newArgs.PullTarget, rcs)
+		t, err := gt.NewPullTarget(c.metrics, c.opts.Logger, entryHandler, deadLetterHandler, jobName, newArgs.PullTarget, rcs)
 		if err != nil {
 			level.Error(c.opts.Logger).Log("msg", "failed to create gcplog target with provided config", "err", err)
 			return err
@@ -152,7 +170,7 @@ func (c *Component) Update(args component.Arguments) error {
 		registry := prometheus.NewRegistry()
 		c.serverMetrics.SetCollector(registry)
 
-		t, err := gt.NewPushTarget(c.metrics, c.opts.Logger, entryHandler, jobName, newArgs.PushTarget, rcs, registry)
+		t, err := gt.NewPushTarget(c.metrics, c.opts.Logger, entryHandler, deadLetterHandler, jobName, newArgs.PushTarget, rcs, registry)
 		if err != nil {
 			level.Error(c.opts.Logger).Log("msg", "failed to create gcplog target with provided config", "err", err)
 			return err
@@ -20,6 +20,7 @@ import (
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/gcplog/gcptypes"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
 )
 
 // TODO (@tpaschalis) We can't test this easily as there's no way to inject
@@ -89,6 +90,95 @@ func TestPush(t *testing.T) {
 	}
 }
 
+func TestPushDeadLetter(t *testing.T) {
+	opts := component.Options{
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+	}
+
+	ch, deadLetterCh := loki.NewLogsReceiver(), loki.NewLogsReceiver()
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	args := Arguments{
+		PushTarget: &gcptypes.PushConfig{
+			Server: &fnet.ServerConfig{
+				HTTP: &fnet.HTTPConfig{
+					ListenAddress: "localhost",
+					ListenPort:    port,
+				},
+				// assign random grpc port
+				GRPC: &fnet.GRPCConfig{ListenPort: 0},
+			},
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		ForwardTo:           []loki.LogsReceiver{ch},
+		DeadLetterForwardTo: []loki.LogsReceiver{deadLetterCh},
+	}
+
+	c, err := New(opts, args)
+	require.NoError(t, err)
+
+	go c.Run(context.Background())
+	time.Sleep(200 * time.Millisecond)
+
+	// The data of the message isn't a log entry, so the message is forwarded
+	// to the dead letter receivers and acknowledged.
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d/gcp/api/v1/push", port), strings.NewReader(testUndecodablePushPayload))
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	select {
+	case logEntry := <-deadLetterCh.Chan():
+		require.Equal(t, "not a log entry", logEntry.Line)
+		require.Equal(t, model.LabelSet{"foo": "bar"}, logEntry.Labels)
+	case logEntry := <-ch.Chan():
+		require.FailNow(t, "unexpected log line", logEntry.Line)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for dead letter log line")
+	}
+}
+
+func TestPullConfig_Validate(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+		pull {
+			project_id    = "my-project"
+			subscription  = "logs"
+			ack_deadline  = "1m"
+			max_extension = "30m"
+		}
+		forward_to = []
+	`), &args)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, args.PullTarget.AckDeadline)
+	require.Equal(t, 30*time.Minute, args.PullTarget.MaxExtension)
+
+	err = syntax.Unmarshal([]byte(`
+		pull {
+			project_id   = "my-project"
+			subscription = "logs"
+			ack_deadline = "5s"
+		}
+		forward_to = []
+	`), &args)
+	require.ErrorContains(t, err, "ack_deadline must be between 10s and 10m0s")
+}
+
+const testUndecodablePushPayload = `
+{
+	"message": {
+		"data": "bm90IGEgbG9nIGVudHJ5",
+		"message_id": "5187581549398350"
+	},
+	"subscription": "projects/test-project/subscriptions/test"
+}`
+
 const testPushPayload = `
 {
 	"message": {
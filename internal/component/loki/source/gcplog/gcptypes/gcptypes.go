@@ -14,6 +14,25 @@ type PullConfig struct {
 	Labels               map[string]string `alloy:"labels,attr,optional"`
 	UseIncomingTimestamp bool              `alloy:"use_incoming_timestamp,attr,optional"`
 	UseFullLine          bool              `alloy:"use_full_line,attr,optional"`
+	AckDeadline          time.Duration     `alloy:"ack_deadline,attr,optional"`
+	MaxExtension         time.Duration     `alloy:"max_extension,attr,optional"`
+}
+
+// The bounds of the ack deadline of Pub/Sub messages.
+const (
+	minAckDeadline = 10 * time.Second
+	maxAckDeadline = 600 * time.Second
+)
+
+// Validate implements syntax.Validator.
+func (p *PullConfig) Validate() error {
+	if p.AckDeadline != 0 && (p.AckDeadline < minAckDeadline || p.AckDeadline > maxAckDeadline) {
+		return fmt.Errorf("ack_deadline must be between %s and %s", minAckDeadline, maxAckDeadline)
+	}
+	if p.MaxExtension < 0 {
+		return fmt.Errorf("max_extension must not be negative")
+	}
+	return nil
 }
 
 // PushConfig configures a GCPLog target with the 'push' strategy.
@@ -130,3 +130,15 @@ func parseGCPLogsEntry(data []byte, other model.LabelSet, otherInternal labels.L
 		},
 	}, nil
 }
+
+// deadLetterEntry returns the entry forwarded to the dead letter receivers
+// for a message whose data couldn't be decoded. The data is kept as is.
+func deadLetterEntry(data []byte, other model.LabelSet) loki.Entry {
+	return loki.Entry{
+		Labels: other.Clone(),
+		Entry: logproto.Entry{
+			Timestamp: time.Now(),
+			Line:      string(data),
+		},
+	}
+}
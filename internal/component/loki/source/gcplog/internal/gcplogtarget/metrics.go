@@ -15,9 +15,11 @@ type Metrics struct {
 	gcplogEntries                 *prometheus.CounterVec
 	gcplogErrors                  *prometheus.CounterVec
 	gcplogTargetLastSuccessScrape *prometheus.GaugeVec
+	gcplogDeadLetterEntries       *prometheus.CounterVec
 
-	gcpPushEntries *prometheus.CounterVec
-	gcpPushErrors  *prometheus.CounterVec
+	gcpPushEntries           *prometheus.CounterVec
+	gcpPushErrors            *prometheus.CounterVec
+	gcpPushDeadLetterEntries *prometheus.CounterVec
 }
 
 // NewMetrics creates a new set of metrics. Metrics will be registered to reg.
@@ -41,6 +43,11 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		Help: "Timestamp of target's last successful poll",
 	}, []string{"project", "target"})
 
+	m.gcplogDeadLetterEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "loki_source_gcplog_pull_dead_letter_entries_total",
+		Help: "Number of messages which couldn't be decoded and were forwarded to the dead letter receivers",
+	}, []string{"project"})
+
 	// Push subscription metrics
 	m.gcpPushEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "loki_source_gcplog_push_entries_total",
@@ -52,12 +59,19 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		Help: "Number of parsing errors while receiving gcplog messages",
 	}, []string{"reason"})
 
+	m.gcpPushDeadLetterEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "loki_source_gcplog_push_dead_letter_entries_total",
+		Help: "Number of messages which couldn't be decoded and were forwarded to the dead letter receivers",
+	}, []string{})
+
 	reg.MustRegister(
 		m.gcplogEntries,
 		m.gcplogErrors,
 		m.gcplogTargetLastSuccessScrape,
+		m.gcplogDeadLetterEntries,
 		m.gcpPushEntries,
 		m.gcpPushErrors,
+		m.gcpPushDeadLetterEntries,
 	)
 	return &m
 }
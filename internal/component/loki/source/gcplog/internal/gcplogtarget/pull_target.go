@@ -29,6 +29,7 @@ type PullTarget struct {
 	metrics       *Metrics
 	logger        log.Logger
 	handler       loki.EntryHandler
+	deadLetter    loki.EntryHandler
 	config        *gcptypes.PullConfig
 	relabelConfig []*relabel.Config
 	jobName       string
@@ -57,8 +58,10 @@ type pubsubSubscription interface {
 	Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error
 }
 
-// NewPullTarget returns the new instance of PullTarget.
-func NewPullTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, jobName string, config *gcptypes.PullConfig, relabel []*relabel.Config, clientOptions ...option.ClientOption) (*PullTarget, error) {
+// NewPullTarget returns the new instance of PullTarget. Messages which can't
+// be decoded are forwarded to deadLetter if it's not nil, and dropped
+// otherwise.
+func NewPullTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, deadLetter loki.EntryHandler, jobName string, config *gcptypes.PullConfig, relabel []*relabel.Config, clientOptions ...option.ClientOption) (*PullTarget, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	ps, err := pubsub.NewClient(ctx, config.ProjectID, clientOptions...)
 	if err != nil {
@@ -66,17 +69,29 @@ func NewPullTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandle
 		return nil, err
 	}
 
+	sub := ps.SubscriptionInProject(config.Subscription, config.ProjectID)
+	if config.AckDeadline > 0 {
+		// The client extends the ack deadline of the messages by AckDeadline
+		// instead of adapting it to how long messages take to be processed.
+		sub.ReceiveSettings.MinExtensionPeriod = config.AckDeadline
+		sub.ReceiveSettings.MaxExtensionPeriod = config.AckDeadline
+	}
+	if config.MaxExtension > 0 {
+		sub.ReceiveSettings.MaxExtension = config.MaxExtension
+	}
+
 	target := &PullTarget{
 		metrics:       metrics,
 		logger:        logger,
 		handler:       handler,
+		deadLetter:    deadLetter,
 		relabelConfig: relabel,
 		config:        config,
 		jobName:       jobName,
 		ctx:           ctx,
 		cancel:        cancel,
 		ps:            ps,
-		sub:           ps.SubscriptionInProject(config.Subscription, config.ProjectID),
+		sub:           sub,
 		backoff:       backoff.New(ctx, defaultBackoff),
 		msgs:          make(chan *pubsub.Message),
 	}
@@ -110,6 +125,10 @@ func (t *PullTarget) run() error {
 			entry, err := parseGCPLogsEntry(m.Data, lbls, nil, t.config.UseIncomingTimestamp, t.config.UseFullLine, t.relabelConfig)
 			if err != nil {
 				level.Error(t.logger).Log("event", "error formating log entry", "cause", err)
+				if t.deadLetter != nil {
+					t.deadLetter.Chan() <- deadLetterEntry(m.Data, lbls)
+					t.metrics.gcplogDeadLetterEntries.WithLabelValues(t.config.ProjectID).Inc()
+				}
 				m.Ack()
 				break
 			}
@@ -161,6 +180,9 @@ func (t *PullTarget) Stop() error {
 	t.cancel()
 	t.wg.Wait()
 	t.handler.Stop()
+	if t.deadLetter != nil {
+		t.deadLetter.Stop()
+	}
 	t.ps.Close()
 	return nil
 }
@@ -87,6 +87,30 @@ func TestPullTarget_RunStop(t *testing.T) {
 	})
 }
 
+func TestPullTarget_DeadLetter(t *testing.T) {
+	tc := testPullTarget(t)
+	deadLetter := fake.NewClient(func() {})
+	tc.target.deadLetter = deadLetter
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- tc.target.run()
+	}()
+
+	tc.sub.messages <- &pubsub.Message{Data: []byte("not a log entry")}
+	tc.sub.messages <- &pubsub.Message{Data: []byte(gcpLogEntry)}
+	require.Eventually(t, func() bool {
+		return len(tc.promClient.Received()) > 0 && len(deadLetter.Received()) > 0
+	}, time.Second, 50*time.Millisecond)
+
+	require.Len(t, deadLetter.Received(), 1)
+	require.Equal(t, "not a log entry", deadLetter.Received()[0].Line)
+	require.Equal(t, `{job="test-gcplogtarget"}`, deadLetter.Received()[0].Labels.String())
+
+	require.NoError(t, tc.target.Stop())
+	require.EqualError(t, <-runErr, "context canceled")
+}
+
 // func TestPullTarget_Ready(t *testing.T) {
 // 	tc := testPullTarget(t)
 // 	assert.Equal(t, true, tc.target.Ready())
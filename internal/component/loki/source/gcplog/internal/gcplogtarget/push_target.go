@@ -7,6 +7,7 @@ package gcplogtarget
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,12 +34,14 @@ type PushTarget struct {
 	config         *gcptypes.PushConfig
 	entries        chan<- loki.Entry
 	handler        loki.EntryHandler
+	deadLetter     loki.EntryHandler
 	relabelConfigs []*relabel.Config
 	server         *fnet.TargetServer
 }
 
-// NewPushTarget constructs a PushTarget.
-func NewPushTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, jobName string, config *gcptypes.PushConfig, relabel []*relabel.Config, reg prometheus.Registerer) (*PushTarget, error) {
+// NewPushTarget constructs a PushTarget. Messages which can't be decoded are
+// forwarded to deadLetter if it's not nil, and rejected otherwise.
+func NewPushTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, deadLetter loki.EntryHandler, jobName string, config *gcptypes.PushConfig, relabel []*relabel.Config, reg prometheus.Registerer) (*PushTarget, error) {
 	wrappedLogger := log.With(logger, "component", "gcp_push")
 	srv, err := fnet.NewTargetServer(wrappedLogger, jobName+"_push_target", reg, config.Server)
 	if err != nil {
@@ -52,6 +55,7 @@ func NewPushTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandle
 		config:         config,
 		entries:        handler.Chan(),
 		handler:        handler,
+		deadLetter:     deadLetter,
 		relabelConfigs: relabel,
 	}
 
@@ -102,13 +106,17 @@ func (p *PushTarget) push(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		p.metrics.gcpPushErrors.WithLabelValues("translation").Inc()
 		level.Warn(p.logger).Log("msg", "failed to translate gcp push request", "err", err.Error())
+		if p.deadLetter != nil {
+			p.forwardDeadLetter(ctx, w, pushMessage)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	level.Debug(p.logger).Log("msg", fmt.Sprintf("Received line: %s", entry.Line))
 
-	if err := p.doSendEntry(ctx, entry); err != nil {
+	if err := p.doSendEntry(ctx, p.entries, entry); err != nil {
 		// NOTE: timeout errors can be tracked with from the metrics exposed by
 		// the spun dskit server.
 		// loki.source.gcplog.componentid_push_target_request_duration_seconds_count{status_code="503"}
@@ -121,12 +129,28 @@ func (p *PushTarget) push(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (p *PushTarget) doSendEntry(ctx context.Context, entry loki.Entry) error {
+// forwardDeadLetter forwards the data of a message which couldn't be decoded
+// to the dead letter receivers, so that Pub/Sub doesn't deliver it again.
+func (p *PushTarget) forwardDeadLetter(ctx context.Context, w http.ResponseWriter, m PushMessage) {
+	data, err := base64.StdEncoding.DecodeString(m.Message.Data)
+	if err != nil {
+		data = []byte(m.Message.Data)
+	}
+	if err := p.doSendEntry(ctx, p.deadLetter.Chan(), deadLetterEntry(data, p.Labels())); err != nil {
+		level.Warn(p.logger).Log("msg", "error sending dead letter entry", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	p.metrics.gcpPushDeadLetterEntries.WithLabelValues().Inc()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *PushTarget) doSendEntry(ctx context.Context, entries chan<- loki.Entry, entry loki.Entry) error {
 	select {
 	// Timeout the loki.Entry channel send operation, which is the only blocking operation in the handler
 	case <-ctx.Done():
 		return fmt.Errorf("timeout exceeded: %w", ctx.Err())
-	case p.entries <- entry:
+	case entries <- entry:
 		return nil
 	}
 }
@@ -154,5 +178,8 @@ func (p *PushTarget) Stop() error {
 	level.Info(p.logger).Log("msg", "stopping gcp push target", "job", p.jobName)
 	p.server.StopAndShutdown()
 	p.handler.Stop()
+	if p.deadLetter != nil {
+		p.deadLetter.Stop()
+	}
 	return nil
 }
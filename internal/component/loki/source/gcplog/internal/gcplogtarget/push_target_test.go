@@ -179,7 +179,7 @@ func TestPushTarget(t *testing.T) {
 
 			prometheus.DefaultRegisterer = prometheus.NewRegistry()
 			metrics := NewMetrics(prometheus.DefaultRegisterer)
-			pt, err := NewPushTarget(metrics, logger, eh, outerName+"_test_job", config, tc.args.RelabelConfigs, nil)
+			pt, err := NewPushTarget(metrics, logger, eh, nil, outerName+"_test_job", config, tc.args.RelabelConfigs, nil)
 			require.NoError(t, err)
 			defer func() {
 				_ = pt.Stop()
@@ -248,7 +248,7 @@ func TestPushTarget_UseIncomingTimestamp(t *testing.T) {
 
 	prometheus.DefaultRegisterer = prometheus.NewRegistry()
 	metrics := NewMetrics(prometheus.DefaultRegisterer)
-	pt, err := NewPushTarget(metrics, logger, eh, t.Name()+"_test_job", config, nil, nil)
+	pt, err := NewPushTarget(metrics, logger, eh, nil, t.Name()+"_test_job", config, nil, nil)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -307,7 +307,7 @@ func TestPushTarget_UseTenantIDHeaderIfPresent(t *testing.T) {
 			Action:       relabel.Replace,
 		},
 	}
-	pt, err := NewPushTarget(metrics, logger, eh, t.Name()+"_test_job", config, tenantIDRelabelConfig, nil)
+	pt, err := NewPushTarget(metrics, logger, eh, nil, t.Name()+"_test_job", config, tenantIDRelabelConfig, nil)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -356,7 +356,7 @@ func TestPushTarget_ErroneousPayloadsAreRejected(t *testing.T) {
 
 	prometheus.DefaultRegisterer = prometheus.NewRegistry()
 	metrics := NewMetrics(prometheus.DefaultRegisterer)
-	pt, err := NewPushTarget(metrics, logger, eh, t.Name()+"_test_job", config, nil, nil)
+	pt, err := NewPushTarget(metrics, logger, eh, nil, t.Name()+"_test_job", config, nil, nil)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -453,7 +453,7 @@ func TestPushTarget_UsePushTimeout(t *testing.T) {
 			Action:       relabel.Replace,
 		},
 	}
-	pt, err := NewPushTarget(metrics, logger, eh, t.Name()+"_test_job", config, tenantIDRelabelConfig, nil)
+	pt, err := NewPushTarget(metrics, logger, eh, nil, t.Name()+"_test_job", config, tenantIDRelabelConfig, nil)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -0,0 +1,77 @@
+package gcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/state"
+)
+
+// checkpointNamespace is the namespace of the state store which holds the
+// checkpoints.
+const checkpointNamespace = "gcs_checkpoints"
+
+// checkpoint records how far an object generation was read.
+type checkpoint struct {
+	// Offset is the number of uncompressed bytes of the object which were
+	// forwarded.
+	Offset int64 `json:"offset"`
+	// Done is true once the whole object was forwarded.
+	Done bool `json:"done"`
+	// Updated is when the checkpoint was last changed.
+	Updated time.Time `json:"updated"`
+}
+
+// checkpoints keeps the checkpoints of the objects in a state store, so that
+// reading an object resumes where it stopped after a restart, and objects
+// which are notified more than once are only read once.
+type checkpoints struct {
+	store *state.Store
+}
+
+func newCheckpoints(store *state.Store) *checkpoints {
+	return &checkpoints{store: store}
+}
+
+// objectKey returns the key of the checkpoint of an object generation.
+func objectKey(bucket, object string, generation int64) string {
+	return fmt.Sprintf("gs://%s/%s#%d", bucket, object, generation)
+}
+
+// get returns the checkpoint of key, or an empty checkpoint if the object
+// wasn't read yet.
+func (c *checkpoints) get(key string) checkpoint {
+	var cp checkpoint
+	if value, ok := c.store.Get(checkpointNamespace, key); ok {
+		// A checkpoint which can't be decoded is read again from the start.
+		_ = json.Unmarshal(value, &cp)
+	}
+	return cp
+}
+
+// put records how far the object of key was read.
+func (c *checkpoints) put(key string, offset int64, done bool) {
+	value, _ := json.Marshal(checkpoint{Offset: offset, Done: done, Updated: time.Now()})
+	c.store.Put(checkpointNamespace, key, value)
+}
+
+// prune removes the checkpoints which weren't updated since before.
+func (c *checkpoints) prune(before time.Time) {
+	for _, key := range c.store.Keys(checkpointNamespace) {
+		if cp := c.get(key); cp.Updated.Before(before) {
+			c.store.Delete(checkpointNamespace, key)
+		}
+	}
+}
+
+// inProgress returns the keys of the objects which were partially read.
+func (c *checkpoints) inProgress() []string {
+	var keys []string
+	for _, key := range c.store.Keys(checkpointNamespace) {
+		if !c.get(key).Done {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
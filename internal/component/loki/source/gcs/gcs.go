@@ -0,0 +1,322 @@
+// Package gcs implements the loki.source.gcs component, which reads log files
+// from Google Cloud Storage buckets when Pub/Sub notifies that they were
+// written.
+package gcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/component/common/state"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/common/model"
+	storage "google.golang.org/api/storage/v1"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.source.gcs",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the loki.source.gcs
+// component.
+type Arguments struct {
+	ProjectID            string              `alloy:"project_id,attr"`
+	Subscription         string              `alloy:"subscription,attr"`
+	Prefix               string              `alloy:"prefix,attr,optional"`
+	Labels               map[string]string   `alloy:"labels,attr,optional"`
+	MaxConcurrentObjects int                 `alloy:"max_concurrent_objects,attr,optional"`
+	CheckpointRetention  time.Duration       `alloy:"checkpoint_retention,attr,optional"`
+	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr"`
+	RelabelRules         alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+}
+
+// DefaultArguments holds default settings for loki.source.gcs.
+var DefaultArguments = Arguments{
+	MaxConcurrentObjects: 4,
+	CheckpointRetention:  7 * 24 * time.Hour,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.ProjectID == "" {
+		return fmt.Errorf("project_id must not be empty")
+	}
+	if args.Subscription == "" {
+		return fmt.Errorf("subscription must not be empty")
+	}
+	if args.MaxConcurrentObjects <= 0 {
+		return fmt.Errorf("max_concurrent_objects must be greater than 0")
+	}
+	if args.CheckpointRetention <= 0 {
+		return fmt.Errorf("checkpoint_retention must be greater than 0")
+	}
+	return nil
+}
+
+// labelSet returns the labels of the arguments as a model.LabelSet.
+func (args *Arguments) labelSet() model.LabelSet {
+	lbls := make(model.LabelSet, len(args.Labels))
+	for k, v := range args.Labels {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return lbls
+}
+
+// How often the checkpoints are persisted and the expired ones are removed.
+const (
+	checkpointSyncInterval  = 10 * time.Second
+	checkpointPruneInterval = time.Hour
+)
+
+var receiveBackoff = backoff.Config{
+	MinBackoff: 1 * time.Second,
+	MaxBackoff: 10 * time.Second,
+	MaxRetries: 0, // Retry forever
+}
+
+// Component implements the loki.source.gcs component.
+type Component struct {
+	log         log.Logger
+	opts        component.Options
+	metrics     *metrics
+	store       *state.Store
+	checkpoints *checkpoints
+	updated     chan struct{}
+
+	mut  sync.Mutex
+	args Arguments
+
+	receiversMut sync.RWMutex
+	receivers    []loki.LogsReceiver
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new loki.source.gcs component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	err := os.MkdirAll(o.DataPath, 0750)
+	if err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		log:         o.Logger,
+		opts:        o,
+		metrics:     newMetrics(o.Registerer),
+		store:       store,
+		checkpoints: newCheckpoints(store),
+		updated:     make(chan struct{}, 1),
+	}
+	if err := c.Update(args); err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	// The subscription of the initial arguments is received by Run, so it
+	// doesn't need to be restarted.
+	<-c.updated
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		if err := c.store.Close(); err != nil {
+			level.Error(c.log).Log("msg", "failed to persist checkpoints", "err", err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.maintainCheckpoints(ctx)
+	}()
+	defer wg.Wait()
+
+	for {
+		c.mut.Lock()
+		args := c.args
+		c.mut.Unlock()
+
+		receiveCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.receive(receiveCtx, args)
+		}()
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case <-c.updated:
+			cancel()
+			<-done
+		}
+	}
+}
+
+// receive reads the objects notified to the subscription of args until ctx
+// is canceled.
+func (c *Component) receive(ctx context.Context, args Arguments) {
+	bo := backoff.New(ctx, receiveBackoff)
+	for bo.Ongoing() {
+		err := c.receiveOnce(ctx, args, bo.Reset)
+		if err != nil && ctx.Err() == nil {
+			level.Error(c.log).Log("msg", "failed to receive Pub/Sub messages", "subscription", args.Subscription, "err", err)
+			bo.Wait()
+		}
+	}
+}
+
+// receiveOnce creates the clients of args and receives the messages of the
+// subscription until ctx is canceled or receiving fails. received is called
+// after each message.
+func (c *Component) receiveOnce(ctx context.Context, args Arguments, received func()) error {
+	client, err := pubsub.NewClient(ctx, args.ProjectID)
+	if err != nil {
+		return fmt.Errorf("creating Pub/Sub client: %w", err)
+	}
+	defer client.Close()
+
+	service, err := storage.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("creating Cloud Storage client: %w", err)
+	}
+
+	sub := client.SubscriptionInProject(args.Subscription, args.ProjectID)
+	sub.ReceiveSettings.MaxOutstandingMessages = args.MaxConcurrentObjects
+
+	r := &reader{
+		log:          c.log,
+		objects:      &storageObjects{service: service},
+		checkpoints:  c.checkpoints,
+		metrics:      c.metrics,
+		prefix:       args.Prefix,
+		labels:       args.labelSet(),
+		relabelRules: alloy_relabel.ComponentToPromRelabelConfigs(args.RelabelRules),
+		send:         c.send,
+	}
+	return sub.Receive(ctx, func(ctx context.Context, m *pubsub.Message) {
+		r.handleMessage(ctx, m)
+		received()
+	})
+}
+
+// maintainCheckpoints periodically persists the checkpoints and removes the
+// ones older than the checkpoint retention.
+func (c *Component) maintainCheckpoints(ctx context.Context) {
+	syncTicker := time.NewTicker(checkpointSyncInterval)
+	defer syncTicker.Stop()
+	pruneTicker := time.NewTicker(checkpointPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-syncTicker.C:
+			if err := c.store.Sync(); err != nil {
+				level.Error(c.log).Log("msg", "failed to persist checkpoints", "err", err)
+			}
+		case <-pruneTicker.C:
+			c.mut.Lock()
+			retention := c.args.CheckpointRetention
+			c.mut.Unlock()
+			c.checkpoints.prune(time.Now().Add(-retention))
+		}
+	}
+}
+
+// send forwards entry to the receivers.
+func (c *Component) send(ctx context.Context, entry loki.Entry) error {
+	c.receiversMut.RLock()
+	receivers := c.receivers
+	c.receiversMut.RUnlock()
+
+	for _, receiver := range receivers {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case receiver.Chan() <- entry:
+		}
+	}
+	return nil
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	newArgs := args.(Arguments)
+
+	c.receiversMut.Lock()
+	c.receivers = newArgs.ForwardTo
+	c.receiversMut.Unlock()
+
+	// The subscription only needs to be received again when something other
+	// than the receivers or the checkpoint retention changed.
+	oldArgs := c.args
+	oldArgs.ForwardTo, newArgs.ForwardTo = nil, nil
+	oldArgs.CheckpointRetention, newArgs.CheckpointRetention = 0, 0
+	changed := !reflect.DeepEqual(oldArgs, newArgs)
+
+	c.args = args.(Arguments)
+
+	if changed {
+		select {
+		case c.updated <- struct{}{}:
+		default:
+			// no-op: restart already queued.
+		}
+	}
+	return nil
+}
+
+// DebugInfo implements component.DebugComponent.
+func (c *Component) DebugInfo() interface{} {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return debugInfo{
+		Subscription:      fmt.Sprintf("projects/%s/subscriptions/%s", c.args.ProjectID, c.args.Subscription),
+		Prefix:            c.args.Prefix,
+		InProgressObjects: c.checkpoints.inProgress(),
+	}
+}
+
+type debugInfo struct {
+	Subscription      string   `alloy:"subscription,attr"`
+	Prefix            string   `alloy:"prefix,attr,optional"`
+	InProgressObjects []string `alloy:"in_progress_objects,attr,optional"`
+}
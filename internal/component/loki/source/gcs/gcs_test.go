@@ -0,0 +1,70 @@
+package gcs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	cfg := `
+		project_id   = "my-project"
+		subscription = "lb-logs-notifications"
+		prefix       = "requests/"
+		labels       = { job = "gcp/load-balancer" }
+		forward_to   = []
+	`
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	require.Equal(t, "my-project", args.ProjectID)
+	require.Equal(t, "lb-logs-notifications", args.Subscription)
+	require.Equal(t, "requests/", args.Prefix)
+	require.Equal(t, map[string]string{"job": "gcp/load-balancer"}, args.Labels)
+	require.Equal(t, 4, args.MaxConcurrentObjects)
+	require.Equal(t, 7*24*time.Hour, args.CheckpointRetention)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			name: "empty subscription",
+			cfg: `
+				project_id   = "my-project"
+				subscription = ""
+				forward_to   = []`,
+			errorMsg: "subscription must not be empty",
+		},
+		{
+			name: "invalid max_concurrent_objects",
+			cfg: `
+				project_id             = "my-project"
+				subscription           = "logs"
+				max_concurrent_objects = 0
+				forward_to             = []`,
+			errorMsg: "max_concurrent_objects must be greater than 0",
+		},
+		{
+			name: "invalid checkpoint_retention",
+			cfg: `
+				project_id           = "my-project"
+				subscription         = "logs"
+				checkpoint_retention = "0s"
+				forward_to           = []`,
+			errorMsg: "checkpoint_retention must be greater than 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tc.cfg), &args), tc.errorMsg)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package gcs
+
+import (
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The results of the notifications counted by the objects metric.
+const (
+	resultRead      = "read"
+	resultFailed    = "failed"
+	resultDuplicate = "duplicate"
+	resultIgnored   = "ignored"
+)
+
+type metrics struct {
+	objects *prometheus.CounterVec
+	entries *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		objects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_gcs_objects_total",
+			Help: "Total number of object notifications handled, by bucket and result.",
+		}, []string{"bucket", "result"}),
+		entries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_gcs_entries_total",
+			Help: "Total number of log entries read from objects, by bucket.",
+		}, []string{"bucket"}),
+	}
+	m.objects = util.MustRegisterOrGet(reg, m.objects).(*prometheus.CounterVec)
+	m.entries = util.MustRegisterOrGet(reg, m.entries).(*prometheus.CounterVec)
+	return m
+}
@@ -0,0 +1,240 @@
+package gcs
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// checkpointLines is the number of lines forwarded between two checkpoints
+// of an object. The lines read after the last checkpoint are forwarded again
+// if reading the object is interrupted.
+const checkpointLines = 1000
+
+// eventObjectFinalize is the event type of the notifications sent when an
+// object is created or overwritten.
+const eventObjectFinalize = "OBJECT_FINALIZE"
+
+// The labels of the objects which are available to relabel_rules.
+const (
+	labelBucket = "__meta_gcs_bucket"
+	labelObject = "__meta_gcs_object"
+)
+
+// notification is a Cloud Storage notification received from Pub/Sub.
+type notification struct {
+	eventType  string
+	bucket     string
+	object     string
+	generation int64
+}
+
+// parseNotification parses the attributes of a Pub/Sub message sent by
+// Cloud Storage.
+func parseNotification(attrs map[string]string) (notification, error) {
+	n := notification{
+		eventType: attrs["eventType"],
+		bucket:    attrs["bucketId"],
+		object:    attrs["objectId"],
+	}
+	if n.eventType == "" || n.bucket == "" || n.object == "" {
+		return n, fmt.Errorf("the eventType, bucketId, and objectId attributes must be set")
+	}
+	generation, err := strconv.ParseInt(attrs["objectGeneration"], 10, 64)
+	if err != nil {
+		return n, fmt.Errorf("invalid objectGeneration attribute: %w", err)
+	}
+	n.generation = generation
+	return n, nil
+}
+
+// objectStore opens the objects of Cloud Storage buckets.
+type objectStore interface {
+	// open returns the uncompressed content of a generation of an object.
+	open(ctx context.Context, bucket, object string, generation int64) (io.ReadCloser, error)
+}
+
+// storageObjects implements objectStore with the Cloud Storage JSON API.
+type storageObjects struct {
+	service *storage.Service
+}
+
+func (s *storageObjects) open(ctx context.Context, bucket, object string, generation int64) (io.ReadCloser, error) {
+	resp, err := s.service.Objects.Get(bucket, object).Generation(generation).Context(ctx).Download()
+	if err != nil {
+		return nil, err
+	}
+	// Objects stored with a gzip Content-Encoding are decompressed by Cloud
+	// Storage or by the HTTP client, but the ones which were uploaded
+	// compressed without it aren't.
+	if !resp.Uncompressed && strings.HasSuffix(object, ".gz") {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("decompressing object: %w", err)
+		}
+		return &gzipReadCloser{Reader: zr, body: resp.Body}, nil
+	}
+	return resp.Body, nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (r *gzipReadCloser) Close() error {
+	_ = r.Reader.Close()
+	return r.body.Close()
+}
+
+// reader forwards the lines of the objects notified by Cloud Storage as log
+// entries.
+type reader struct {
+	log          log.Logger
+	objects      objectStore
+	checkpoints  *checkpoints
+	metrics      *metrics
+	prefix       string
+	labels       model.LabelSet
+	relabelRules []*relabel.Config
+	send         func(context.Context, loki.Entry) error
+}
+
+// handleMessage reads the object of a notification. The message is
+// acknowledged once the object was read, and redelivered by Pub/Sub if
+// reading it failed.
+func (r *reader) handleMessage(ctx context.Context, m *pubsub.Message) {
+	n, err := parseNotification(m.Attributes)
+	if err != nil {
+		level.Warn(r.log).Log("msg", "ignoring Pub/Sub message which isn't a Cloud Storage notification", "id", m.ID, "err", err)
+		r.metrics.objects.WithLabelValues("", resultIgnored).Inc()
+		m.Ack()
+		return
+	}
+	if err := r.read(ctx, n); err != nil {
+		level.Error(r.log).Log("msg", "failed to read object", "bucket", n.bucket, "object", n.object, "generation", n.generation, "err", err)
+		m.Nack()
+		return
+	}
+	m.Ack()
+}
+
+// read forwards the lines of the object of n, starting after the lines
+// forwarded the last time it was read.
+func (r *reader) read(ctx context.Context, n notification) error {
+	if n.eventType != eventObjectFinalize || !strings.HasPrefix(n.object, r.prefix) {
+		r.metrics.objects.WithLabelValues(n.bucket, resultIgnored).Inc()
+		return nil
+	}
+	lbls, keep := r.objectLabels(n)
+	if !keep {
+		r.metrics.objects.WithLabelValues(n.bucket, resultIgnored).Inc()
+		return nil
+	}
+
+	key := objectKey(n.bucket, n.object, n.generation)
+	cp := r.checkpoints.get(key)
+	if cp.Done {
+		// Pub/Sub delivers messages at least once.
+		r.metrics.objects.WithLabelValues(n.bucket, resultDuplicate).Inc()
+		return nil
+	}
+
+	offset, err := r.forward(ctx, n, key, cp.Offset, lbls)
+	if err != nil {
+		r.checkpoints.put(key, offset, false)
+		r.metrics.objects.WithLabelValues(n.bucket, resultFailed).Inc()
+		return err
+	}
+	r.checkpoints.put(key, offset, true)
+	r.metrics.objects.WithLabelValues(n.bucket, resultRead).Inc()
+	return nil
+}
+
+// forward forwards the lines of the object of n after offset, and returns the
+// offset of the end of the object.
+func (r *reader) forward(ctx context.Context, n notification, key string, offset int64, lbls model.LabelSet) (int64, error) {
+	rc, err := r.objects.open(ctx, n.bucket, n.object, n.generation)
+	if err != nil {
+		return offset, fmt.Errorf("opening object: %w", err)
+	}
+	defer rc.Close()
+
+	br := bufio.NewReader(rc)
+	if _, err := io.CopyN(io.Discard, br, offset); err != nil {
+		return offset, fmt.Errorf("skipping the %d bytes which were already read: %w", offset, err)
+	}
+
+	var lines int
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return offset, fmt.Errorf("reading object: %w", err)
+		}
+		if len(line) > 0 {
+			if text := strings.TrimRight(line, "\r\n"); text != "" {
+				entry := loki.Entry{
+					Labels: lbls.Clone(),
+					Entry:  logproto.Entry{Timestamp: time.Now(), Line: text},
+				}
+				if err := r.send(ctx, entry); err != nil {
+					return offset, err
+				}
+				r.metrics.entries.WithLabelValues(n.bucket).Inc()
+			}
+			offset += int64(len(line))
+			if lines++; lines%checkpointLines == 0 {
+				r.checkpoints.put(key, offset, false)
+			}
+		}
+		if err == io.EOF {
+			return offset, nil
+		}
+	}
+}
+
+// objectLabels returns the labels of the entries of the object of n, and
+// false if the object is dropped by the relabeling rules.
+func (r *reader) objectLabels(n notification) (model.LabelSet, bool) {
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	for k, v := range r.labels {
+		lb.Set(string(k), string(v))
+	}
+	lb.Set(labelBucket, n.bucket)
+	lb.Set(labelObject, n.object)
+
+	lbls := lb.Labels()
+	if len(r.relabelRules) > 0 {
+		var keep bool
+		lbls, keep = relabel.Process(lbls, r.relabelRules...)
+		if !keep {
+			return nil, false
+		}
+	}
+
+	res := make(model.LabelSet, lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		// Internal labels are removed once relabeling is done.
+		if strings.HasPrefix(l.Name, "__") {
+			return
+		}
+		res[model.LabelName(l.Name)] = model.LabelValue(l.Value)
+	})
+	return res, true
+}
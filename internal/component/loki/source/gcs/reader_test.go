@@ -0,0 +1,188 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/state"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeObjects map[string]string
+
+func (f fakeObjects) open(_ context.Context, bucket, object string, generation int64) (io.ReadCloser, error) {
+	content, ok := f[objectKey(bucket, object, generation)]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func newTestReader(t *testing.T, objects fakeObjects) (*reader, *[]loki.Entry) {
+	store, err := state.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	var entries []loki.Entry
+	r := &reader{
+		log:         util.TestLogger(t),
+		objects:     objects,
+		checkpoints: newCheckpoints(store),
+		metrics:     newMetrics(prometheus.NewRegistry()),
+		labels:      model.LabelSet{"job": "gcs"},
+		send: func(_ context.Context, e loki.Entry) error {
+			entries = append(entries, e)
+			return nil
+		},
+	}
+	return r, &entries
+}
+
+func lines(entries []loki.Entry) []string {
+	res := make([]string, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e.Line)
+	}
+	return res
+}
+
+func TestParseNotification(t *testing.T) {
+	n, err := parseNotification(map[string]string{
+		"eventType":        "OBJECT_FINALIZE",
+		"bucketId":         "lb-logs",
+		"objectId":         "requests/2024/01/01/00:00:00_00:59:59_S0.json",
+		"objectGeneration": "1704070800000000",
+		"payloadFormat":    "JSON_API_V1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, notification{
+		eventType:  eventObjectFinalize,
+		bucket:     "lb-logs",
+		object:     "requests/2024/01/01/00:00:00_00:59:59_S0.json",
+		generation: 1704070800000000,
+	}, n)
+
+	_, err = parseNotification(map[string]string{"eventType": "OBJECT_FINALIZE", "bucketId": "lb-logs"})
+	require.Error(t, err)
+	_, err = parseNotification(map[string]string{"eventType": "OBJECT_FINALIZE", "bucketId": "lb-logs", "objectId": "a.log"})
+	require.ErrorContains(t, err, "invalid objectGeneration attribute")
+}
+
+func TestReader_Read(t *testing.T) {
+	r, entries := newTestReader(t, fakeObjects{
+		"gs://lb-logs/requests/a.json#1": "{\"a\":1}\n\n{\"a\":2}\r\n{\"a\":3}",
+	})
+	r.relabelRules = []*relabel.Config{{
+		SourceLabels: model.LabelNames{labelBucket},
+		Regex:        relabel.MustNewRegexp("(.*)"),
+		TargetLabel:  "bucket",
+		Replacement:  "$1",
+		Action:       relabel.Replace,
+	}}
+	n := notification{eventType: eventObjectFinalize, bucket: "lb-logs", object: "requests/a.json", generation: 1}
+
+	require.NoError(t, r.read(context.Background(), n))
+	require.Equal(t, []string{`{"a":1}`, `{"a":2}`, `{"a":3}`}, lines(*entries))
+	for _, e := range *entries {
+		require.Equal(t, model.LabelSet{"job": "gcs", "bucket": "lb-logs"}, e.Labels)
+	}
+	require.Equal(t, 1.0, testutil.ToFloat64(r.metrics.objects.WithLabelValues("lb-logs", resultRead)))
+	require.Equal(t, 3.0, testutil.ToFloat64(r.metrics.entries.WithLabelValues("lb-logs")))
+
+	// A notification delivered again doesn't read the object again.
+	require.NoError(t, r.read(context.Background(), n))
+	require.Len(t, *entries, 3)
+	require.Equal(t, 1.0, testutil.ToFloat64(r.metrics.objects.WithLabelValues("lb-logs", resultDuplicate)))
+
+	// A new generation of the object is read.
+	r.objects.(fakeObjects)["gs://lb-logs/requests/a.json#2"] = "{\"a\":4}\n"
+	n.generation = 2
+	require.NoError(t, r.read(context.Background(), n))
+	require.Equal(t, `{"a":4}`, (*entries)[3].Line)
+}
+
+func TestReader_Ignored(t *testing.T) {
+	r, entries := newTestReader(t, fakeObjects{
+		"gs://lb-logs/other/a.log#1": "a\n",
+		"gs://lb-logs/debug.log#1":   "a\n",
+	})
+	r.prefix = "requests/"
+	r.relabelRules = []*relabel.Config{{
+		SourceLabels: model.LabelNames{labelObject},
+		Regex:        relabel.MustNewRegexp("debug.*"),
+		Action:       relabel.Drop,
+	}}
+
+	for _, n := range []notification{
+		{eventType: "OBJECT_DELETE", bucket: "lb-logs", object: "requests/a.log", generation: 1},
+		{eventType: eventObjectFinalize, bucket: "lb-logs", object: "other/a.log", generation: 1},
+	} {
+		require.NoError(t, r.read(context.Background(), n))
+	}
+	r.prefix = ""
+	require.NoError(t, r.read(context.Background(), notification{eventType: eventObjectFinalize, bucket: "lb-logs", object: "debug.log", generation: 1}))
+
+	require.Empty(t, *entries)
+	require.Equal(t, 3.0, testutil.ToFloat64(r.metrics.objects.WithLabelValues("lb-logs", resultIgnored)))
+}
+
+func TestReader_ResumeFromCheckpoint(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < checkpointLines+2; i++ {
+		content.WriteString("line\n")
+	}
+	r, entries := newTestReader(t, fakeObjects{"gs://audit/a.log#1": content.String()})
+	n := notification{eventType: eventObjectFinalize, bucket: "audit", object: "a.log", generation: 1}
+	key := objectKey(n.bucket, n.object, n.generation)
+
+	// Sending fails after the first checkpoint.
+	sent := 0
+	send := r.send
+	r.send = func(ctx context.Context, e loki.Entry) error {
+		if sent == checkpointLines+1 {
+			return context.Canceled
+		}
+		sent++
+		return send(ctx, e)
+	}
+	require.ErrorIs(t, r.read(context.Background(), n), context.Canceled)
+	require.Equal(t, checkpoint{Offset: int64(5 * (checkpointLines + 1))}, withoutTime(r.checkpoints.get(key)))
+	require.Equal(t, []string{key}, r.checkpoints.inProgress())
+	require.Equal(t, 1.0, testutil.ToFloat64(r.metrics.objects.WithLabelValues("audit", resultFailed)))
+
+	// The object is read again from the checkpoint.
+	r.send = send
+	require.NoError(t, r.read(context.Background(), n))
+	require.Len(t, *entries, checkpointLines+2)
+	require.Equal(t, checkpoint{Offset: int64(5 * (checkpointLines + 2)), Done: true}, withoutTime(r.checkpoints.get(key)))
+	require.Empty(t, r.checkpoints.inProgress())
+}
+
+func TestCheckpoints_Prune(t *testing.T) {
+	store, err := state.Open(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+	cps := newCheckpoints(store)
+
+	cps.put("gs://audit/a.log#1", 10, true)
+	cps.prune(time.Now().Add(-time.Hour))
+	require.True(t, cps.get("gs://audit/a.log#1").Done)
+
+	cps.prune(time.Now().Add(time.Hour))
+	require.Equal(t, checkpoint{}, cps.get("gs://audit/a.log#1"))
+}
+
+func withoutTime(cp checkpoint) checkpoint {
+	cp.Updated = time.Time{}
+	return cp
+}
@@ -2,6 +2,7 @@ package heroku
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sync"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/relabel"
@@ -36,8 +38,18 @@ type Arguments struct {
 	Server               *fnet.ServerConfig  `alloy:",squash"`
 	Labels               map[string]string   `alloy:"labels,attr,optional"`
 	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
-	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr"`
+	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr,optional"`
 	RelabelRules         alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	Apps                 []AppArguments      `alloy:"app,block,optional"`
+}
+
+// AppArguments configures how the logs of a Heroku app, which are identified
+// by the token of its drain, are labeled and forwarded.
+type AppArguments struct {
+	Name       string              `alloy:"name,attr"`
+	DrainToken alloytypes.Secret   `alloy:"drain_token,attr"`
+	Labels     map[string]string   `alloy:"labels,attr,optional"`
+	ForwardTo  []loki.LogsReceiver `alloy:"forward_to,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -47,16 +59,53 @@ func (a *Arguments) SetToDefault() {
 	}
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if len(a.Apps) == 0 {
+		if a.ForwardTo == nil {
+			return fmt.Errorf("forward_to must be set when no app blocks are defined")
+		}
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(a.Apps))
+	tokens := make(map[alloytypes.Secret]struct{}, len(a.Apps))
+	for _, app := range a.Apps {
+		if app.Name == "" {
+			return fmt.Errorf("app name must not be empty")
+		}
+		if _, ok := names[app.Name]; ok {
+			return fmt.Errorf("app %q is defined more than once", app.Name)
+		}
+		names[app.Name] = struct{}{}
+
+		if app.DrainToken == "" {
+			return fmt.Errorf("drain_token of app %q must not be empty", app.Name)
+		}
+		if _, ok := tokens[app.DrainToken]; ok {
+			return fmt.Errorf("drain_token of app %q is used by another app", app.Name)
+		}
+		tokens[app.DrainToken] = struct{}{}
+
+		if app.ForwardTo == nil && a.ForwardTo == nil {
+			return fmt.Errorf("forward_to of app %q must be set when the component doesn't set forward_to", app.Name)
+		}
+	}
+	return nil
+}
+
 // Component implements the loki.source.heroku component.
 type Component struct {
 	opts          component.Options
 	metrics       *ht.Metrics              // Metrics about Heroku entries.
 	serverMetrics *util.UncheckedCollector // Metircs about the HTTP server managed by the component.
 
-	mut    sync.RWMutex
-	args   Arguments
-	fanout []loki.LogsReceiver
-	target *ht.HerokuTarget
+	mut       sync.RWMutex
+	args      Arguments
+	fanout    []loki.LogsReceiver
+	appFanout map[string][]loki.LogsReceiver // Receivers of the logs of each app.
+	target    *ht.HerokuTarget
+	stopApps  context.CancelFunc // Stops forwarding the logs of the apps of the target.
 
 	handler loki.LogsReceiver
 }
@@ -97,6 +146,9 @@ func (c *Component) Run(ctx context.Context) error {
 				level.Error(c.opts.Logger).Log("msg", "error while stopping heroku listener", "err", err)
 			}
 		}
+		if c.stopApps != nil {
+			c.stopApps()
+		}
 	}()
 
 	for {
@@ -120,6 +172,14 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.appFanout = make(map[string][]loki.LogsReceiver, len(newArgs.Apps))
+	for _, app := range newArgs.Apps {
+		if app.ForwardTo != nil {
+			c.appFanout[app.Name] = app.ForwardTo
+		} else {
+			c.appFanout[app.Name] = newArgs.ForwardTo
+		}
+	}
 
 	var rcs []*relabel.Config
 	if newArgs.RelabelRules != nil && len(newArgs.RelabelRules) > 0 {
@@ -129,7 +189,8 @@ func (c *Component) Update(args component.Arguments) error {
 	restartRequired := changed(c.args.Server, newArgs.Server) ||
 		changed(c.args.RelabelRules, newArgs.RelabelRules) ||
 		changed(c.args.Labels, newArgs.Labels) ||
-		c.args.UseIncomingTimestamp != newArgs.UseIncomingTimestamp
+		c.args.UseIncomingTimestamp != newArgs.UseIncomingTimestamp ||
+		changed(appsWithoutReceivers(c.args.Apps), appsWithoutReceivers(newArgs.Apps))
 	if restartRequired {
 		if c.target != nil {
 			err := c.target.Stop()
@@ -137,6 +198,9 @@ func (c *Component) Update(args component.Arguments) error {
 				level.Error(c.opts.Logger).Log("msg", "error while stopping heroku listener", "err", err)
 			}
 		}
+		if c.stopApps != nil {
+			c.stopApps()
+		}
 
 		// [ht.NewHerokuTarget] registers new metrics every time it is called. To
 		// avoid issues with re-registering metrics with the same name, we create a
@@ -145,8 +209,17 @@ func (c *Component) Update(args component.Arguments) error {
 		registry := prometheus.NewRegistry()
 		c.serverMetrics.SetCollector(registry)
 
+		ctx, cancel := context.WithCancel(context.Background())
+		c.stopApps = cancel
+		config := newArgs.Convert()
+		for i, app := range newArgs.Apps {
+			receiver := loki.NewLogsReceiver()
+			go c.forwardApp(ctx, app.Name, receiver)
+			config.Apps[i].Handler = loki.NewEntryHandler(receiver.Chan(), func() {})
+		}
+
 		entryHandler := loki.NewEntryHandler(c.handler.Chan(), func() {})
-		t, err := ht.NewHerokuTarget(c.metrics, c.opts.Logger, entryHandler, rcs, newArgs.Convert(), registry)
+		t, err := ht.NewHerokuTarget(c.metrics, c.opts.Logger, entryHandler, rcs, config, registry)
 		if err != nil {
 			level.Error(c.opts.Logger).Log("msg", "failed to create heroku listener with provided config", "err", err)
 			return err
@@ -159,20 +232,61 @@ func (c *Component) Update(args component.Arguments) error {
 	return nil
 }
 
+// forwardApp forwards the logs of the app named name from receiver to the
+// receivers of the app until ctx is canceled.
+func (c *Component) forwardApp(ctx context.Context, name string, receiver loki.LogsReceiver) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry := <-receiver.Chan():
+			c.mut.RLock()
+			for _, r := range c.appFanout[name] {
+				r.Chan() <- entry
+			}
+			c.mut.RUnlock()
+		}
+	}
+}
+
+// appsWithoutReceivers returns apps without their receivers, which can change
+// without restarting the target.
+func appsWithoutReceivers(apps []AppArguments) []AppArguments {
+	res := make([]AppArguments, 0, len(apps))
+	for _, app := range apps {
+		app.ForwardTo = nil
+		res = append(res, app)
+	}
+	return res
+}
+
 // Convert is used to bridge between the Alloy and Promtail types.
 func (args *Arguments) Convert() *ht.HerokuDrainTargetConfig {
-	lbls := make(model.LabelSet, len(args.Labels))
-	for k, v := range args.Labels {
-		lbls[model.LabelName(k)] = model.LabelValue(v)
+	apps := make([]ht.HerokuApp, 0, len(args.Apps))
+	for _, app := range args.Apps {
+		apps = append(apps, ht.HerokuApp{
+			Name:       app.Name,
+			DrainToken: string(app.DrainToken),
+			Labels:     toLabelSet(app.Labels),
+		})
 	}
 
 	return &ht.HerokuDrainTargetConfig{
 		Server:               args.Server,
-		Labels:               lbls,
+		Labels:               toLabelSet(args.Labels),
 		UseIncomingTimestamp: args.UseIncomingTimestamp,
+		Apps:                 apps,
 	}
 }
 
+func toLabelSet(m map[string]string) model.LabelSet {
+	lbls := make(model.LabelSet, len(m))
+	for k, v := range m {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return lbls
+}
+
 // DebugInfo returns information about the status of listener.
 func (c *Component) DebugInfo() interface{} {
 	c.mut.RLock()
@@ -182,13 +296,17 @@ func (c *Component) DebugInfo() interface{} {
 		Ready:   c.target.Ready(),
 		Address: c.target.HTTPListenAddress(),
 	}
+	for _, app := range c.args.Apps {
+		res.Apps = append(res.Apps, app.Name)
+	}
 
 	return res
 }
 
 type readerDebugInfo struct {
-	Ready   bool   `alloy:"ready,attr"`
-	Address string `alloy:"address,attr"`
+	Ready   bool     `alloy:"ready,attr"`
+	Address string   `alloy:"address,attr"`
+	Apps    []string `alloy:"apps,attr,optional"`
 }
 
 func changed(prev, next any) bool {
@@ -65,6 +65,139 @@ func TestPush(t *testing.T) {
 	}
 }
 
+func TestPushApps(t *testing.T) {
+	opts := defaultOptions(t)
+
+	defaultCh, appCh := loki.NewLogsReceiver(), loki.NewLogsReceiver()
+	args := testArgsWith(t, func(args *Arguments) {
+		args.ForwardTo = []loki.LogsReceiver{defaultCh}
+		args.RelabelRules = alloy_relabel.Rules{
+			{
+				SourceLabels: []string{"__heroku_drain_app_name"},
+				Regex:        newRegexp(),
+				Action:       alloy_relabel.Replace,
+				Replacement:  "$1",
+				TargetLabel:  "heroku_app",
+			},
+		}
+		args.Labels = map[string]string{"foo": "bar"}
+		args.Apps = []AppArguments{
+			{
+				Name:       "api",
+				DrainToken: "d.api",
+				Labels:     map[string]string{"foo": "api", "team": "backend"},
+				ForwardTo:  []loki.LogsReceiver{appCh},
+			},
+			{
+				Name:       "web",
+				DrainToken: "d.web",
+			},
+		}
+	})
+	c, err := New(opts, args)
+	require.NoError(t, err)
+
+	go func() { require.NoError(t, c.Run(context.Background())) }()
+	waitForServerToBeReady(t, c)
+
+	send := func(token string) int {
+		req, err := http.NewRequest(http.MethodPost, getEndpoint(c.target), strings.NewReader(testPayload))
+		require.NoError(t, err)
+		req.Header.Set("Logplex-Drain-Token", token)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return res.StatusCode
+	}
+
+	// The logs of an app are sent to its receivers, with its labels.
+	require.Equal(t, http.StatusNoContent, send("d.api"))
+	select {
+	case logEntry := <-appCh.Chan():
+		require.Equal(t, model.LabelSet{"foo": "api", "team": "backend", "heroku_app": "api"}, logEntry.Labels)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for log line")
+	}
+
+	// Apps without receivers use the ones of the component.
+	require.Equal(t, http.StatusNoContent, send("d.web"))
+	select {
+	case logEntry := <-defaultCh.Chan():
+		require.Equal(t, model.LabelSet{"foo": "bar", "heroku_app": "web"}, logEntry.Labels)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for log line")
+	}
+
+	// Unknown drain tokens are rejected.
+	require.Equal(t, http.StatusForbidden, send("d.unknown"))
+	require.Equal(t, http.StatusForbidden, send(""))
+}
+
+func TestValidate(t *testing.T) {
+	receivers := []loki.LogsReceiver{loki.NewLogsReceiver()}
+	tests := []struct {
+		name    string
+		args    Arguments
+		wantErr string
+	}{
+		{
+			name: "forward_to",
+			args: Arguments{ForwardTo: receivers},
+		},
+		{
+			name:    "no forward_to",
+			args:    Arguments{},
+			wantErr: "forward_to must be set when no app blocks are defined",
+		},
+		{
+			name: "apps with their own forward_to",
+			args: Arguments{Apps: []AppArguments{
+				{Name: "api", DrainToken: "d.api", ForwardTo: receivers},
+				{Name: "web", DrainToken: "d.web", ForwardTo: receivers},
+			}},
+		},
+		{
+			name: "app without forward_to",
+			args: Arguments{Apps: []AppArguments{
+				{Name: "api", DrainToken: "d.api"},
+			}},
+			wantErr: `forward_to of app "api" must be set when the component doesn't set forward_to`,
+		},
+		{
+			name: "duplicate app name",
+			args: Arguments{ForwardTo: receivers, Apps: []AppArguments{
+				{Name: "api", DrainToken: "d.api"},
+				{Name: "api", DrainToken: "d.web"},
+			}},
+			wantErr: `app "api" is defined more than once`,
+		},
+		{
+			name: "duplicate drain token",
+			args: Arguments{ForwardTo: receivers, Apps: []AppArguments{
+				{Name: "api", DrainToken: "d.api"},
+				{Name: "web", DrainToken: "d.api"},
+			}},
+			wantErr: `drain_token of app "web" is used by another app`,
+		},
+		{
+			name: "empty drain token",
+			args: Arguments{ForwardTo: receivers, Apps: []AppArguments{
+				{Name: "api"},
+			}},
+			wantErr: `drain_token of app "api" must not be empty`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.args.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestUpdate_detectsWhenTargetRequiresARestart(t *testing.T) {
 	httpPort := getFreePort(t)
 	grpcPort := getFreePort(t)
@@ -126,6 +259,34 @@ func TestUpdate_detectsWhenTargetRequiresARestart(t *testing.T) {
 			}),
 			restartRequired: true,
 		},
+		{
+			name: "change in app forwardTo does not require server restart",
+			args: testArgsWith(t, func(args *Arguments) {
+				args.Apps = []AppArguments{{Name: "api", DrainToken: "d.api"}}
+				args.Server.HTTP.ListenPort = httpPort
+				args.Server.GRPC.ListenPort = grpcPort
+			}),
+			newArgs: testArgsWith(t, func(args *Arguments) {
+				args.Apps = []AppArguments{{Name: "api", DrainToken: "d.api", ForwardTo: []loki.LogsReceiver{}}}
+				args.Server.HTTP.ListenPort = httpPort
+				args.Server.GRPC.ListenPort = grpcPort
+			}),
+			restartRequired: false,
+		},
+		{
+			name: "change in app drain token requires server restart",
+			args: testArgsWith(t, func(args *Arguments) {
+				args.Apps = []AppArguments{{Name: "api", DrainToken: "d.api"}}
+				args.Server.HTTP.ListenPort = httpPort
+				args.Server.GRPC.ListenPort = grpcPort
+			}),
+			newArgs: testArgsWith(t, func(args *Arguments) {
+				args.Apps = []AppArguments{{Name: "api", DrainToken: "d.other"}}
+				args.Server.HTTP.ListenPort = httpPort
+				args.Server.GRPC.ListenPort = grpcPort
+			}),
+			restartRequired: true,
+		},
 		{
 			name: "change in use incoming timestamp requires server restart",
 			args: testArgsWithPorts(httpPort, grpcPort),
@@ -5,6 +5,7 @@ package herokutarget
 // to other loki components.
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strings"
@@ -26,6 +27,9 @@ import (
 
 const ReservedLabelTenantID = "__tenant_id__"
 
+// drainTokenHeader is the header in which Heroku sends the token of the drain.
+const drainTokenHeader = "Logplex-Drain-Token"
+
 // HerokuDrainTargetConfig describes a scrape config to listen and consume heroku logs, in the HTTPS drain manner.
 type HerokuDrainTargetConfig struct {
 	Server *fnet.ServerConfig
@@ -36,6 +40,23 @@ type HerokuDrainTargetConfig struct {
 	// UseIncomingTimestamp sets the timestamp to the incoming heroku log entry timestamp. If false,
 	// promtail will assign the current timestamp to the log entry when it was processed.
 	UseIncomingTimestamp bool
+
+	// Apps route the logs of Heroku apps by the token of their drain. When
+	// set, requests whose drain token doesn't belong to an app are rejected.
+	Apps []HerokuApp
+}
+
+// HerokuApp describes where to send the logs received from the drain of a
+// Heroku app.
+type HerokuApp struct {
+	Name       string
+	DrainToken string
+
+	// Labels are added to the labels of the target for the logs of the app.
+	Labels model.LabelSet
+
+	// Handler receives the logs of the app.
+	Handler loki.EntryHandler
 }
 
 type HerokuTarget struct {
@@ -76,8 +97,34 @@ func NewHerokuTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHand
 	return ht, nil
 }
 
+// app returns the app whose drain token matches the one of r, or nil if
+// there's none.
+func (h *HerokuTarget) app(r *http.Request) *HerokuApp {
+	token := []byte(r.Header.Get(drainTokenHeader))
+	for i := range h.config.Apps {
+		if subtle.ConstantTimeCompare(token, []byte(h.config.Apps[i].DrainToken)) == 1 {
+			return &h.config.Apps[i]
+		}
+	}
+	return nil
+}
+
 func (h *HerokuTarget) drain(w http.ResponseWriter, r *http.Request) {
 	entries := h.handler.Chan()
+	fixedLabels := h.Labels()
+	var appName string
+	if len(h.config.Apps) > 0 {
+		app := h.app(r)
+		if app == nil {
+			h.metrics.herokuRejected.Inc()
+			level.Warn(h.logger).Log("msg", "rejected heroku request with an unknown drain token", "remote_addr", r.RemoteAddr)
+			http.Error(w, "unknown drain token", http.StatusForbidden)
+			return
+		}
+		entries = app.Handler.Chan()
+		fixedLabels = fixedLabels.Merge(app.Labels)
+		appName = app.Name
+	}
 	defer r.Body.Close()
 	herokuScanner := herokuEncoding.NewDrainScanner(r.Body)
 	for herokuScanner.Scan() {
@@ -88,6 +135,9 @@ func (h *HerokuTarget) drain(w http.ResponseWriter, r *http.Request) {
 		lb.Set("__heroku_drain_app", message.Application)
 		lb.Set("__heroku_drain_proc", message.Process)
 		lb.Set("__heroku_drain_log_id", message.ID)
+		if appName != "" {
+			lb.Set("__heroku_drain_app_name", appName)
+		}
 
 		if h.config.UseIncomingTimestamp {
 			ts = message.Timestamp
@@ -108,7 +158,7 @@ func (h *HerokuTarget) drain(w http.ResponseWriter, r *http.Request) {
 		processed, _ := relabel.Process(lb.Labels(), h.relabelConfigs...)
 
 		// Start with the set of labels fixed in the configuration
-		filtered := h.Labels().Clone()
+		filtered := fixedLabels.Clone()
 		for _, lbl := range processed {
 			if strings.HasPrefix(lbl.Name, "__") {
 				continue
@@ -178,5 +228,8 @@ func (h *HerokuTarget) Stop() error {
 	level.Info(h.logger).Log("msg", "stopping heroku drain target")
 	h.server.StopAndShutdown()
 	h.handler.Stop()
+	for _, app := range h.config.Apps {
+		app.Handler.Stop()
+	}
 	return nil
 }
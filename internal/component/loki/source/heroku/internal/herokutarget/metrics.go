@@ -7,8 +7,9 @@ package herokutarget
 import "github.com/prometheus/client_golang/prometheus"
 
 type Metrics struct {
-	herokuEntries prometheus.Counter
-	herokuErrors  prometheus.Counter
+	herokuEntries  prometheus.Counter
+	herokuErrors   prometheus.Counter
+	herokuRejected prometheus.Counter
 }
 
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -24,6 +25,11 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		Help: "Number of parsing errors while receiving Heroku messages",
 	})
 
-	reg.MustRegister(m.herokuEntries, m.herokuErrors)
+	m.herokuRejected = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "loki_source_heroku_drain_rejected_requests_total",
+		Help: "Number of requests rejected because their drain token doesn't belong to a configured app",
+	})
+
+	reg.MustRegister(m.herokuEntries, m.herokuErrors, m.herokuRejected)
 	return &m
 }
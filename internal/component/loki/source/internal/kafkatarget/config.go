@@ -1,6 +1,8 @@
 package kafkatarget
 
 import (
+	"time"
+
 	"github.com/IBM/sarama"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/dskit/flagext"
@@ -39,12 +41,37 @@ type TargetConfig struct {
 	// Rebalancing strategy to use. (e.g. sticky, roundrobin or range)
 	Assignor string `yaml:"assignor"`
 
+	// Where to start consuming the partitions without a committed offset.
+	// (e.g. earliest, latest or timestamp)
+	InitialOffset string `yaml:"initial_offset"`
+
+	// The time of the first messages consumed when InitialOffset is timestamp.
+	InitialOffsetTimestamp time.Time `yaml:"initial_offset_timestamp"`
+
+	// Timeouts of the consumer group membership. Zero values use the defaults
+	// of sarama.
+	SessionTimeout    time.Duration `yaml:"session_timeout"`
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+	RebalanceTimeout  time.Duration `yaml:"rebalance_timeout"`
+
 	// Authentication strategy with Kafka brokers
 	Authentication Authentication `yaml:"authentication"`
 
 	MessageParser MessageParser
 }
 
+// The initial offset policies of partitions without a committed offset.
+const (
+	// InitialOffsetEarliest consumes the partitions from their oldest message.
+	InitialOffsetEarliest = "earliest"
+	// InitialOffsetLatest consumes the messages produced after the consumer
+	// starts.
+	InitialOffsetLatest = "latest"
+	// InitialOffsetTimestamp consumes the partitions from their first message
+	// produced after a timestamp.
+	InitialOffsetTimestamp = "timestamp"
+)
+
 // AuthenticationType specifies method to authenticate with Kafka brokers
 type AuthenticationType string
 
@@ -39,6 +39,9 @@ type consumer struct {
 	sarama.ConsumerGroup
 	discoverer TargetDiscoverer
 	logger     log.Logger
+	// resetOffsets, if set, is called at the beginning of each session to set
+	// the offsets of the partitions without a committed offset.
+	resetOffsets func(sarama.ConsumerGroupSession) error
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -107,6 +110,9 @@ func (c *consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 // Setup is run at the beginning of a new session, before ConsumeClaim
 func (c *consumer) Setup(session sarama.ConsumerGroupSession) error {
 	c.resetTargets()
+	if c.resetOffsets != nil {
+		return c.resetOffsets(session)
+	}
 	return nil
 }
 
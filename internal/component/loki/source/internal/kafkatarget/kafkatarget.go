@@ -39,6 +39,7 @@ type KafkaTarget struct {
 	relabelConfig        []*relabel.Config
 	useIncomingTimestamp bool
 	messageParser        MessageParser
+	metrics              *Metrics
 }
 
 func NewKafkaTarget(
@@ -50,6 +51,7 @@ func NewKafkaTarget(
 	client loki.EntryHandler,
 	useIncomingTimestamp bool,
 	messageParser MessageParser,
+	metrics *Metrics,
 ) *KafkaTarget {
 
 	return &KafkaTarget{
@@ -63,6 +65,7 @@ func NewKafkaTarget(
 		relabelConfig:        relabelConfig,
 		useIncomingTimestamp: useIncomingTimestamp,
 		messageParser:        messageParser,
+		metrics:              metrics,
 	}
 }
 
@@ -74,6 +77,7 @@ const (
 
 func (t *KafkaTarget) run() {
 	defer t.client.Stop()
+	defer t.metrics.forget(t.claim.Topic(), t.claim.Partition())
 	for message := range t.claim.Messages() {
 		mk := string(message.Key)
 		if len(mk) == 0 {
@@ -101,6 +105,7 @@ func (t *KafkaTarget) run() {
 		}
 
 		t.session.MarkMessage(message, "")
+		t.metrics.observe(message.Topic, message.Partition, message.Offset, t.claim.HighWaterMarkOffset())
 	}
 }
 
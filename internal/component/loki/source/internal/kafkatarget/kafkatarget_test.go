@@ -14,6 +14,8 @@ import (
 	"github.com/grafana/alloy/internal/component/common/loki/client/fake"
 
 	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/require"
@@ -87,10 +89,11 @@ func (s *testSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string)
 func (s *testSession) Context() context.Context { return context.Background() }
 
 type testClaim struct {
-	topic     string
-	partition int32
-	offset    int64
-	messages  chan *sarama.ConsumerMessage
+	topic         string
+	partition     int32
+	offset        int64
+	highWatermark int64
+	messages      chan *sarama.ConsumerMessage
 }
 
 func newTestClaim(topic string, partition int32, offset int64) *testClaim {
@@ -105,7 +108,7 @@ func newTestClaim(topic string, partition int32, offset int64) *testClaim {
 func (t *testClaim) Topic() string                            { return t.topic }
 func (t *testClaim) Partition() int32                         { return t.partition }
 func (t *testClaim) InitialOffset() int64                     { return t.offset }
-func (t *testClaim) HighWaterMarkOffset() int64               { return 0 }
+func (t *testClaim) HighWaterMarkOffset() int64               { return t.highWatermark }
 func (t *testClaim) Messages() <-chan *sarama.ConsumerMessage { return t.messages }
 func (t *testClaim) Send(m *sarama.ConsumerMessage) {
 	t.messages <- m
@@ -213,7 +216,7 @@ func Test_TargetRun(t *testing.T) {
 				},
 			)
 
-			tg := NewKafkaTarget(nil, session, claim, tt.inDiscoveredLS, tt.inLS, tt.relabels, fc, true, &KafkaTargetMessageParser{})
+			tg := NewKafkaTarget(nil, session, claim, tt.inDiscoveredLS, tt.inLS, tt.relabels, fc, true, &KafkaTargetMessageParser{}, nil)
 
 			var wg sync.WaitGroup
 			wg.Add(1)
@@ -243,3 +246,31 @@ func Test_TargetRun(t *testing.T) {
 		})
 	}
 }
+
+func Test_TargetMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewMetrics(reg)
+	session, claim := &testSession{}, newTestClaim("footopic", 10, 12)
+	claim.highWatermark = 20
+
+	tg := NewKafkaTarget(nil, session, claim, model.LabelSet{}, model.LabelSet{}, nil, fake.NewClient(func() {}), true, &KafkaTargetMessageParser{}, metrics)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tg.run()
+	}()
+
+	claim.Send(&sarama.ConsumerMessage{Topic: "footopic", Partition: 10, Value: []byte("foo"), Offset: 15})
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.lag.WithLabelValues("footopic", "10")) == 4
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 15.0, testutil.ToFloat64(metrics.currentOffset.WithLabelValues("footopic", "10")))
+	require.Equal(t, 20.0, testutil.ToFloat64(metrics.highWatermark.WithLabelValues("footopic", "10")))
+
+	// The metrics of the partition are removed when the claim ends.
+	claim.Stop()
+	wg.Wait()
+	require.Equal(t, 0, testutil.CollectAndCount(reg))
+}
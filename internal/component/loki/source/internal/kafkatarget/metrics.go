@@ -0,0 +1,63 @@
+package kafkatarget
+
+import (
+	"strconv"
+
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the metrics of the partitions consumed by the targets.
+type Metrics struct {
+	currentOffset *prometheus.GaugeVec
+	highWatermark *prometheus.GaugeVec
+	lag           *prometheus.GaugeVec
+}
+
+// NewMetrics creates the metrics of the consumed partitions and registers
+// them with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		currentOffset: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "loki_source_kafka_partition_current_offset",
+			Help: "Offset of the last message consumed from the partition.",
+		}, []string{"topic", "partition"}),
+		highWatermark: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "loki_source_kafka_partition_high_watermark_offset",
+			Help: "Offset of the next message which will be produced to the partition.",
+		}, []string{"topic", "partition"}),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "loki_source_kafka_partition_lag",
+			Help: "Number of messages of the partition which weren't consumed yet.",
+		}, []string{"topic", "partition"}),
+	}
+	if reg != nil {
+		m.currentOffset = util.MustRegisterOrGet(reg, m.currentOffset).(*prometheus.GaugeVec)
+		m.highWatermark = util.MustRegisterOrGet(reg, m.highWatermark).(*prometheus.GaugeVec)
+		m.lag = util.MustRegisterOrGet(reg, m.lag).(*prometheus.GaugeVec)
+	}
+	return m
+}
+
+// observe records the offset of a message consumed from a partition, and the
+// high watermark offset of the partition.
+func (m *Metrics) observe(topic string, partition int32, offset, highWatermark int64) {
+	if m == nil {
+		return
+	}
+	p := strconv.Itoa(int(partition))
+	m.currentOffset.WithLabelValues(topic, p).Set(float64(offset))
+	m.highWatermark.WithLabelValues(topic, p).Set(float64(highWatermark))
+	m.lag.WithLabelValues(topic, p).Set(float64(max(highWatermark-offset-1, 0)))
+}
+
+// forget removes the metrics of a partition which isn't consumed anymore.
+func (m *Metrics) forget(topic string, partition int32) {
+	if m == nil {
+		return
+	}
+	p := strconv.Itoa(int(partition))
+	m.currentOffset.DeleteLabelValues(topic, p)
+	m.highWatermark.DeleteLabelValues(topic, p)
+	m.lag.DeleteLabelValues(topic, p)
+}
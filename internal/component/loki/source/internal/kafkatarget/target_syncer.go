@@ -42,6 +42,7 @@ type TargetSyncer struct {
 	wg             sync.WaitGroup
 	previousTopics []string
 	messageParser  MessageParser
+	metrics        *Metrics
 }
 
 func NewSyncer(
@@ -49,6 +50,7 @@ func NewSyncer(
 	cfg Config,
 	pushClient loki.EntryHandler,
 	messageParser MessageParser,
+	metrics *Metrics,
 ) (*TargetSyncer, error) {
 
 	if err := validateConfig(&cfg); err != nil {
@@ -61,6 +63,18 @@ func NewSyncer(
 	config := sarama.NewConfig()
 	config.Version = version
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if cfg.KafkaConfig.InitialOffset == InitialOffsetLatest {
+		config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+	if cfg.KafkaConfig.SessionTimeout > 0 {
+		config.Consumer.Group.Session.Timeout = cfg.KafkaConfig.SessionTimeout
+	}
+	if cfg.KafkaConfig.HeartbeatInterval > 0 {
+		config.Consumer.Group.Heartbeat.Interval = cfg.KafkaConfig.HeartbeatInterval
+	}
+	if cfg.KafkaConfig.RebalanceTimeout > 0 {
+		config.Consumer.Group.Rebalance.Timeout = cfg.KafkaConfig.RebalanceTimeout
+	}
 
 	switch cfg.KafkaConfig.Assignor {
 	case sarama.StickyBalanceStrategyName:
@@ -109,8 +123,12 @@ func NewSyncer(
 			logger:        logger,
 		},
 		messageParser: messageParser,
+		metrics:       metrics,
 	}
 	t.discoverer = t
+	if cfg.KafkaConfig.InitialOffset == InitialOffsetTimestamp {
+		t.resetOffsets = resetOffsetsToTimestamp(client, cfg.KafkaConfig.GroupID, cfg.KafkaConfig.InitialOffsetTimestamp, logger)
+	}
 	t.loop()
 	return t, nil
 }
@@ -300,11 +318,55 @@ func (ts *TargetSyncer) NewTarget(session sarama.ConsumerGroupSession, claim sar
 		ts.client,
 		ts.cfg.KafkaConfig.UseIncomingTimestamp,
 		ts.messageParser,
+		ts.metrics,
 	)
 
 	return t, nil
 }
 
+// resetOffsetsToTimestamp returns a function which sets the offsets of the
+// partitions of a session without a committed offset to the offset of their
+// first message produced after ts.
+func resetOffsetsToTimestamp(client sarama.Client, groupID string, ts time.Time, logger log.Logger) func(sarama.ConsumerGroupSession) error {
+	return func(session sarama.ConsumerGroupSession) error {
+		// Closing the admin would close the client, which is closed when the
+		// syncer stops.
+		admin, err := sarama.NewClusterAdminFromClient(client)
+		if err != nil {
+			return fmt.Errorf("error creating kafka admin client: %w", err)
+		}
+		committed, err := admin.ListConsumerGroupOffsets(groupID, session.Claims())
+		if err != nil {
+			return fmt.Errorf("error fetching the committed offsets of the consumer group: %w", err)
+		}
+
+		for topic, partitions := range session.Claims() {
+			for _, partition := range partitions {
+				if block := committed.GetBlock(topic, partition); block != nil && block.Offset >= 0 {
+					continue
+				}
+				offset, err := client.GetOffset(topic, partition, ts.UnixMilli())
+				if err != nil {
+					return fmt.Errorf("error fetching the offset of topic %s partition %d at %s: %w", topic, partition, ts, err)
+				}
+				if offset < 0 {
+					// No message was produced after ts.
+					offset, err = client.GetOffset(topic, partition, sarama.OffsetNewest)
+					if err != nil {
+						return fmt.Errorf("error fetching the newest offset of topic %s partition %d: %w", topic, partition, err)
+					}
+				}
+				level.Info(logger).Log("msg", "setting initial offset from timestamp", "topic", topic, "partition", partition, "offset", offset)
+				// The offset manager of the session only moves an offset
+				// backwards with ResetOffset, so MarkOffset is used for
+				// partitions without a committed offset.
+				session.MarkOffset(topic, partition, offset, "")
+			}
+		}
+		return nil
+	}
+}
+
 func validateConfig(cfg *Config) error {
 	if cfg.KafkaConfig.Version == "" {
 		cfg.KafkaConfig.Version = "2.1.1"
@@ -320,6 +382,18 @@ func validateConfig(cfg *Config) error {
 	if cfg.KafkaConfig.GroupID == "" {
 		cfg.KafkaConfig.GroupID = "promtail"
 	}
+
+	switch cfg.KafkaConfig.InitialOffset {
+	case "":
+		cfg.KafkaConfig.InitialOffset = InitialOffsetEarliest
+	case InitialOffsetEarliest, InitialOffsetLatest:
+	case InitialOffsetTimestamp:
+		if cfg.KafkaConfig.InitialOffsetTimestamp.IsZero() {
+			return errors.New("no timestamp given for the timestamp initial offset")
+		}
+	default:
+		return fmt.Errorf("unrecognized initial offset: %s", cfg.KafkaConfig.InitialOffset)
+	}
 	return nil
 }
 
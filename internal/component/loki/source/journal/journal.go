@@ -15,8 +15,10 @@ import (
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/component/loki/source/journal/internal/target"
 	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
 
 	"github.com/grafana/alloy/internal/component"
 )
@@ -42,6 +44,7 @@ type Component struct {
 	metrics   *target.Metrics
 	o         component.Options
 	handler   chan loki.Entry
+	store     *state.Store
 	positions positions.Positions
 	receivers []loki.LogsReceiver
 }
@@ -53,13 +56,18 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		return nil, err
 	}
 
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:        10 * time.Second,
 		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
 		IgnoreInvalidYaml: false,
 		ReadOnly:          false,
 	})
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
@@ -67,6 +75,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		metrics:   target.NewMetrics(o.Registerer),
 		o:         o,
 		handler:   make(chan loki.Entry),
+		store:     store,
 		positions: positionsFile,
 		receivers: args.Receivers,
 	}
@@ -81,8 +90,11 @@ func (c *Component) Run(ctx context.Context) error {
 		if c.t != nil {
 			c.t.Stop()
 		}
+		c.positions.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.o.Logger).Log("msg", "failed to close state store", "err", err)
+		}
 		c.mut.RUnlock()
-
 	}()
 	for {
 		select {
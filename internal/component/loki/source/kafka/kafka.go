@@ -2,7 +2,9 @@ package kafka
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/grafana/alloy/internal/component"
@@ -32,14 +34,19 @@ func init() {
 // Arguments holds values which are used to configure the loki.source.kafka
 // component.
 type Arguments struct {
-	Brokers              []string            `alloy:"brokers,attr"`
-	Topics               []string            `alloy:"topics,attr"`
-	GroupID              string              `alloy:"group_id,attr,optional"`
-	Assignor             string              `alloy:"assignor,attr,optional"`
-	Version              string              `alloy:"version,attr,optional"`
-	Authentication       KafkaAuthentication `alloy:"authentication,block,optional"`
-	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
-	Labels               map[string]string   `alloy:"labels,attr,optional"`
+	Brokers                []string            `alloy:"brokers,attr"`
+	Topics                 []string            `alloy:"topics,attr"`
+	GroupID                string              `alloy:"group_id,attr,optional"`
+	Assignor               string              `alloy:"assignor,attr,optional"`
+	Version                string              `alloy:"version,attr,optional"`
+	InitialOffset          string              `alloy:"initial_offset,attr,optional"`
+	InitialOffsetTimestamp string              `alloy:"initial_offset_timestamp,attr,optional"`
+	SessionTimeout         time.Duration       `alloy:"session_timeout,attr,optional"`
+	HeartbeatInterval      time.Duration       `alloy:"heartbeat_interval,attr,optional"`
+	RebalanceTimeout       time.Duration       `alloy:"rebalance_timeout,attr,optional"`
+	Authentication         KafkaAuthentication `alloy:"authentication,block,optional"`
+	UseIncomingTimestamp   bool                `alloy:"use_incoming_timestamp,attr,optional"`
+	Labels                 map[string]string   `alloy:"labels,attr,optional"`
 
 	ForwardTo    []loki.LogsReceiver `alloy:"forward_to,attr"`
 	RelabelRules alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
@@ -69,9 +76,13 @@ type OAuthConfigConfig struct {
 
 // DefaultArguments provides the default arguments for a kafka component.
 var DefaultArguments = Arguments{
-	GroupID:  "loki.source.kafka",
-	Assignor: "range",
-	Version:  "2.2.1",
+	GroupID:           "loki.source.kafka",
+	Assignor:          "range",
+	Version:           "2.2.1",
+	InitialOffset:     kt.InitialOffsetEarliest,
+	SessionTimeout:    10 * time.Second,
+	HeartbeatInterval: 3 * time.Second,
+	RebalanceTimeout:  time.Minute,
 	Authentication: KafkaAuthentication{
 		Type: "none",
 		SASLConfig: KafkaSASLConfig{
@@ -87,9 +98,34 @@ func (a *Arguments) SetToDefault() {
 	*a = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	switch a.InitialOffset {
+	case kt.InitialOffsetEarliest, kt.InitialOffsetLatest:
+		if a.InitialOffsetTimestamp != "" {
+			return fmt.Errorf("initial_offset_timestamp can only be set when initial_offset is %q", kt.InitialOffsetTimestamp)
+		}
+	case kt.InitialOffsetTimestamp:
+		if _, err := time.Parse(time.RFC3339, a.InitialOffsetTimestamp); err != nil {
+			return fmt.Errorf("initial_offset_timestamp must be an RFC 3339 timestamp when initial_offset is %q: %w", kt.InitialOffsetTimestamp, err)
+		}
+	default:
+		return fmt.Errorf("initial_offset must be one of %q, %q, or %q, got %q", kt.InitialOffsetEarliest, kt.InitialOffsetLatest, kt.InitialOffsetTimestamp, a.InitialOffset)
+	}
+
+	if a.SessionTimeout <= 0 || a.HeartbeatInterval <= 0 || a.RebalanceTimeout <= 0 {
+		return fmt.Errorf("session_timeout, heartbeat_interval, and rebalance_timeout must be greater than 0")
+	}
+	if a.HeartbeatInterval >= a.SessionTimeout {
+		return fmt.Errorf("heartbeat_interval must be lower than session_timeout")
+	}
+	return nil
+}
+
 // Component implements the loki.source.kafka component.
 type Component struct {
-	opts component.Options
+	opts    component.Options
+	metrics *kt.Metrics
 
 	mut    sync.RWMutex
 	fanout []loki.LogsReceiver
@@ -102,6 +138,7 @@ type Component struct {
 func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
+		metrics: kt.NewMetrics(o.Registerer),
 		mut:     sync.RWMutex{},
 		fanout:  args.ForwardTo,
 		target:  nil,
@@ -161,7 +198,7 @@ func (c *Component) Update(args component.Arguments) error {
 	}
 
 	entryHandler := loki.NewEntryHandler(c.handler.Chan(), func() {})
-	t, err := kt.NewSyncer(c.opts.Logger, newArgs.Convert(), entryHandler, &kt.KafkaTargetMessageParser{})
+	t, err := kt.NewSyncer(c.opts.Logger, newArgs.Convert(), entryHandler, &kt.KafkaTargetMessageParser{}, c.metrics)
 	if err != nil {
 		level.Error(c.opts.Logger).Log("msg", "failed to create kafka client with provided config", "err", err)
 		return err
@@ -178,17 +215,24 @@ func (args *Arguments) Convert() kt.Config {
 	for k, v := range args.Labels {
 		lbls[model.LabelName(k)] = model.LabelValue(v)
 	}
+	// The timestamp was validated.
+	initialTimestamp, _ := time.Parse(time.RFC3339, args.InitialOffsetTimestamp)
 
 	return kt.Config{
 		KafkaConfig: kt.TargetConfig{
-			Labels:               lbls,
-			UseIncomingTimestamp: args.UseIncomingTimestamp,
-			Brokers:              args.Brokers,
-			GroupID:              args.GroupID,
-			Topics:               args.Topics,
-			Version:              args.Version,
-			Assignor:             args.Assignor,
-			Authentication:       args.Authentication.Convert(),
+			Labels:                 lbls,
+			UseIncomingTimestamp:   args.UseIncomingTimestamp,
+			Brokers:                args.Brokers,
+			GroupID:                args.GroupID,
+			Topics:                 args.Topics,
+			Version:                args.Version,
+			Assignor:               args.Assignor,
+			InitialOffset:          args.InitialOffset,
+			InitialOffsetTimestamp: initialTimestamp,
+			SessionTimeout:         args.SessionTimeout,
+			HeartbeatInterval:      args.HeartbeatInterval,
+			RebalanceTimeout:       args.RebalanceTimeout,
+			Authentication:         args.Authentication.Convert(),
 		},
 		RelabelConfigs: alloy_relabel.ComponentToPromRelabelConfigs(args.RelabelRules),
 	}
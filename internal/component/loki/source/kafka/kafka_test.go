@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/syntax"
 	"github.com/stretchr/testify/require"
@@ -84,3 +85,68 @@ func TestSASLOAuthAlloyConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.NoError(t, err)
 }
+
+func TestInitialOffsetAlloyConfig(t *testing.T) {
+	var exampleAlloyConfig = `
+	brokers                  = ["localhost:9092"]
+	topics                   = ["quickstart-events"]
+	initial_offset           = "timestamp"
+	initial_offset_timestamp = "2024-01-01T00:00:00Z"
+	session_timeout          = "30s"
+	heartbeat_interval       = "5s"
+	rebalance_timeout        = "2m"
+	forward_to               = []
+`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.NoError(t, err)
+
+	cfg := args.Convert().KafkaConfig
+	require.Equal(t, "timestamp", cfg.InitialOffset)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), cfg.InitialOffsetTimestamp.UTC())
+	require.Equal(t, 30*time.Second, cfg.SessionTimeout)
+	require.Equal(t, 5*time.Second, cfg.HeartbeatInterval)
+	require.Equal(t, 2*time.Minute, cfg.RebalanceTimeout)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			name:     "unknown initial offset",
+			cfg:      `initial_offset = "oldest"`,
+			errorMsg: `initial_offset must be one of "earliest", "latest", or "timestamp", got "oldest"`,
+		},
+		{
+			name:     "missing timestamp",
+			cfg:      `initial_offset = "timestamp"`,
+			errorMsg: `initial_offset_timestamp must be an RFC 3339 timestamp when initial_offset is "timestamp"`,
+		},
+		{
+			name:     "timestamp without timestamp offset",
+			cfg:      `initial_offset_timestamp = "2024-01-01T00:00:00Z"`,
+			errorMsg: `initial_offset_timestamp can only be set when initial_offset is "timestamp"`,
+		},
+		{
+			name:     "heartbeat longer than session",
+			cfg:      `heartbeat_interval = "10s"`,
+			errorMsg: "heartbeat_interval must be lower than session_timeout",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := `
+	brokers    = ["localhost:9092"]
+	topics     = ["quickstart-events"]
+	forward_to = []
+` + tc.cfg
+			var args Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(cfg), &args), tc.errorMsg)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	commonk8s "github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/component/loki/source/kubernetes/kubetail"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -61,6 +62,7 @@ func (args *Arguments) SetToDefault() {
 type Component struct {
 	log       log.Logger
 	opts      component.Options
+	store     *state.Store
 	positions positions.Positions
 	cluster   cluster.Cluster
 
@@ -87,12 +89,16 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:    10 * time.Second,
 		PositionsFile: filepath.Join(o.DataPath, "positions.yml"),
 	})
-
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
@@ -106,6 +112,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		log:       o.Logger,
 		opts:      o,
 		handler:   loki.NewLogsReceiver(),
+		store:     store,
 		positions: positionsFile,
 	}
 	if err := c.Update(args); err != nil {
@@ -116,7 +123,12 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 // Run implements component.Component.
 func (c *Component) Run(ctx context.Context) error {
-	defer c.positions.Stop()
+	defer func() {
+		c.positions.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close state store", "err", err)
+		}
+	}()
 
 	defer func() {
 		c.mut.Lock()
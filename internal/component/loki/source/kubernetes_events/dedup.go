@@ -0,0 +1,97 @@
+package kubernetes_events
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// deduper folds repeated events. The first occurrence of an event is
+// forwarded immediately, and the occurrences received during the following
+// window are folded into a single entry, which is forwarded when the window
+// ends.
+//
+// Occurrences are repeated when they're about the same object, with the same
+// reason, type, and message. This covers both updates of the count of an
+// Event and similar Events created for the same object.
+type deduper struct {
+	window time.Duration
+
+	mut    sync.Mutex
+	states map[string]*dedupState
+}
+
+type dedupState struct {
+	windowEnd time.Time
+	pending   *corev1.Event // Latest folded occurrence, if any.
+	folded    int           // Number of occurrences folded into pending.
+}
+
+// foldedEvent is an event which represents several occurrences.
+type foldedEvent struct {
+	event  *corev1.Event
+	folded int
+}
+
+func newDeduper(window time.Duration) *deduper {
+	return &deduper{
+		window: window,
+		states: make(map[string]*dedupState),
+	}
+}
+
+// observe records an occurrence of event, and returns true if it must be
+// forwarded immediately.
+func (d *deduper) observe(event *corev1.Event, now time.Time) bool {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	key := dedupKey(event)
+	state, ok := d.states[key]
+	if !ok || (!now.Before(state.windowEnd) && state.pending == nil) {
+		d.states[key] = &dedupState{windowEnd: now.Add(d.window)}
+		return true
+	}
+
+	state.pending = event
+	state.folded++
+	return false
+}
+
+// expire returns the folded occurrences of the windows which ended before
+// now. Windows with folded occurrences are restarted, so that an event which
+// keeps repeating is forwarded once per window.
+func (d *deduper) expire(now time.Time) []foldedEvent {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	var res []foldedEvent
+	for key, state := range d.states {
+		if now.Before(state.windowEnd) {
+			continue
+		}
+		if state.pending == nil {
+			delete(d.states, key)
+			continue
+		}
+
+		res = append(res, foldedEvent{event: state.pending, folded: state.folded})
+		*state = dedupState{windowEnd: now.Add(d.window)}
+	}
+	return res
+}
+
+func dedupKey(event *corev1.Event) string {
+	obj := event.InvolvedObject
+	return strings.Join([]string{
+		obj.Namespace,
+		obj.Kind,
+		obj.Name,
+		string(obj.UID),
+		event.Reason,
+		event.Type,
+		event.Message,
+	}, "\xff")
+}
@@ -0,0 +1,53 @@
+package kubernetes_events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeduper(t *testing.T) {
+	var (
+		d     = newDeduper(time.Minute)
+		start = time.Now()
+
+		backoff = &corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Namespace: "default", Kind: "Pod", Name: "app"},
+			Reason:         "BackOff",
+			Type:           "Warning",
+			Message:        "Back-off restarting failed container",
+		}
+		pulled = &corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Namespace: "default", Kind: "Pod", Name: "app"},
+			Reason:         "Pulled",
+			Type:           "Normal",
+			Message:        "Container image already present on machine",
+		}
+	)
+
+	// The first occurrence of each event is forwarded immediately.
+	require.True(t, d.observe(backoff, start))
+	require.True(t, d.observe(pulled, start))
+
+	// Repeated occurrences are folded until the window ends.
+	repeated := backoff.DeepCopy()
+	repeated.Count = 3
+	require.False(t, d.observe(backoff, start.Add(10*time.Second)))
+	require.False(t, d.observe(repeated, start.Add(20*time.Second)))
+	require.Empty(t, d.expire(start.Add(30*time.Second)))
+
+	// The latest folded occurrence is forwarded when the window ends.
+	require.Equal(t, []foldedEvent{{event: repeated, folded: 2}}, d.expire(start.Add(time.Minute)))
+
+	// The window of an event which keeps repeating is restarted.
+	require.False(t, d.observe(backoff, start.Add(90*time.Second)))
+	require.Equal(t, []foldedEvent{{event: backoff, folded: 1}}, d.expire(start.Add(2*time.Minute)))
+
+	// Once an event stops repeating, its next occurrence is forwarded
+	// immediately again.
+	require.Empty(t, d.expire(start.Add(3*time.Minute)))
+	require.True(t, d.observe(backoff, start.Add(3*time.Minute)))
+	require.Len(t, d.states, 1)
+}
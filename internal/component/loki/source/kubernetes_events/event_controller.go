@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/prometheus/common/model"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	cachetools "k8s.io/client-go/tools/cache"
@@ -31,14 +32,16 @@ const (
 )
 
 type eventControllerTask struct {
-	Log          log.Logger
-	Config       *rest.Config // Config to connect to Kubernetes.
-	Namespace    string       // Namespace to watch for events in.
-	JobName      string       // Label value to use for job.
-	InstanceName string       // Label value to use for instance.
-	Receiver     loki.LogsReceiver
-	Positions    positions.Positions
-	LogFormat    string
+	Log           log.Logger
+	Config        *rest.Config // Config to connect to Kubernetes.
+	Namespace     string       // Namespace to watch for events in.
+	JobName       string       // Label value to use for job.
+	InstanceName  string       // Label value to use for instance.
+	Receiver      loki.LogsReceiver
+	Positions     positions.Positions
+	LogFormat     string
+	FieldSelector string        // Field selector of the watched events.
+	DedupWindow   time.Duration // Window to fold repeated events in, 0 to disable.
 }
 
 // Hash implements [runner.Task].
@@ -56,6 +59,7 @@ type eventController struct {
 	log     log.Logger
 	task    eventControllerTask
 	handler loki.EntryHandler
+	deduper *deduper // nil when folding is disabled.
 
 	positionsKey  string
 	initTimestamp time.Time
@@ -71,13 +75,17 @@ func newEventController(task eventControllerTask) *eventController {
 
 	lastTimestamp, _ := task.Positions.Get(key, "")
 
-	return &eventController{
+	ctrl := &eventController{
 		log:           task.Log,
 		task:          task,
 		handler:       loki.NewEntryHandler(task.Receiver.Chan(), func() {}),
 		positionsKey:  key,
 		initTimestamp: time.UnixMicro(lastTimestamp),
 	}
+	if task.DedupWindow > 0 {
+		ctrl.deduper = newDeduper(task.DedupWindow)
+	}
+	return ctrl
 }
 
 func (ctrl *eventController) Run(ctx context.Context) {
@@ -105,6 +113,13 @@ func (ctrl *eventController) runError(ctx context.Context) error {
 		Scheme:            scheme,
 		DefaultNamespaces: defaultNamespaces,
 	}
+	if ctrl.task.FieldSelector != "" {
+		selector, err := fields.ParseSelector(ctrl.task.FieldSelector)
+		if err != nil {
+			return fmt.Errorf("parsing field selector: %w", err)
+		}
+		opts.DefaultFieldSelector = selector
+	}
 	informers, err := cache.New(ctrl.task.Config, opts)
 	if err != nil {
 		return fmt.Errorf("creating informers cache: %w", err)
@@ -124,10 +139,41 @@ func (ctrl *eventController) runError(ctx context.Context) error {
 		return fmt.Errorf("failed to configure informers: %w", err)
 	}
 
-	<-ctx.Done()
+	if ctrl.deduper == nil {
+		<-ctx.Done()
+		return nil
+	}
+	ctrl.runDeduper(ctx)
 	return nil
 }
 
+// runDeduper periodically forwards the events folded by the deduper, until
+// ctx is canceled.
+func (ctrl *eventController) runDeduper(ctx context.Context) {
+	interval := time.Second
+	if ctrl.task.DedupWindow < interval {
+		interval = ctrl.task.DedupWindow
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, fe := range ctrl.deduper.expire(now) {
+				if err := ctrl.sendEvent(ctx, fe.event, fe.folded); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					level.Error(ctrl.log).Log("msg", "error handling event", "err", err)
+				}
+			}
+		}
+	}
+}
+
 func (ctrl *eventController) configureInformers(ctx context.Context, informers cache.Informers) error {
 	types := []client.Object{
 		&corev1.Event{},
@@ -217,7 +263,18 @@ func (ctrl *eventController) handleEvent(ctx context.Context, event *corev1.Even
 		return nil
 	}
 
-	lset, msg, err := ctrl.parseEvent(event)
+	if ctrl.deduper != nil && !ctrl.deduper.observe(event, time.Now()) {
+		return nil
+	}
+	return ctrl.sendEvent(ctx, event, 0)
+}
+
+// sendEvent forwards event. folded is the number of occurrences of the event
+// folded into the entry, or 0 if the event wasn't folded.
+func (ctrl *eventController) sendEvent(ctx context.Context, event *corev1.Event, folded int) error {
+	eventTs := eventTimestamp(event)
+
+	lset, msg, err := ctrl.parseEvent(event, folded)
 	if err != nil {
 		return err
 	}
@@ -241,7 +298,7 @@ func (ctrl *eventController) handleEvent(ctx context.Context, event *corev1.Even
 	}
 }
 
-func (ctrl *eventController) parseEvent(event *corev1.Event) (model.LabelSet, string, error) {
+func (ctrl *eventController) parseEvent(event *corev1.Event, folded int) (model.LabelSet, string, error) {
 	var (
 		msg      strings.Builder
 		lset     = make(model.LabelSet)
@@ -299,6 +356,9 @@ func (ctrl *eventController) parseEvent(event *corev1.Event) (model.LabelSet, st
 	if event.Count != 0 {
 		appender(&msg, fields, "count", event.Count, "%d")
 	}
+	if folded != 0 {
+		appender(&msg, fields, "foldedcount", folded, "%d")
+	}
 
 	appender(&msg, fields, "msg", event.Message, "%q")
 
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,10 +17,12 @@ import (
 	"github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runner"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/oklog/run"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/rest"
 )
 
@@ -43,9 +46,12 @@ func init() {
 type Arguments struct {
 	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
 
-	JobName    string   `alloy:"job_name,attr,optional"`
-	Namespaces []string `alloy:"namespaces,attr,optional"`
-	LogFormat  string   `alloy:"log_format,attr,optional"`
+	JobName           string        `alloy:"job_name,attr,optional"`
+	Namespaces        []string      `alloy:"namespaces,attr,optional"`
+	ExcludeNamespaces []string      `alloy:"exclude_namespaces,attr,optional"`
+	FieldSelector     string        `alloy:"field_selector,attr,optional"`
+	LogFormat         string        `alloy:"log_format,attr,optional"`
+	DedupWindow       time.Duration `alloy:"dedup_window,attr,optional"`
 
 	// Client settings to connect to Kubernetes.
 	Client kubernetes.ClientArguments `alloy:"client,block,optional"`
@@ -72,6 +78,17 @@ func (args *Arguments) Validate() error {
 	if args.LogFormat != logFormatFmt && args.LogFormat != logFormatJson {
 		return fmt.Errorf("supported values of log_format are %s and %s", logFormatFmt, logFormatJson)
 	}
+	if len(args.Namespaces) > 0 && len(args.ExcludeNamespaces) > 0 {
+		return fmt.Errorf("namespaces and exclude_namespaces can't both be set")
+	}
+	if args.FieldSelector != "" {
+		if _, err := fields.ParseSelector(args.FieldSelector); err != nil {
+			return fmt.Errorf("invalid field_selector: %w", err)
+		}
+	}
+	if args.DedupWindow < 0 {
+		return fmt.Errorf("dedup_window must not be negative")
+	}
 	return nil
 }
 
@@ -81,6 +98,7 @@ func (args *Arguments) Validate() error {
 type Component struct {
 	log        log.Logger
 	opts       component.Options
+	store      *state.Store
 	positions  positions.Positions
 	handler    loki.LogsReceiver
 	runner     *runner.Runner[eventControllerTask]
@@ -108,17 +126,23 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:    10 * time.Second,
 		PositionsFile: filepath.Join(o.DataPath, "positions.yml"),
 	})
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
 	c := &Component{
 		log:       o.Logger,
 		opts:      o,
+		store:     store,
 		positions: positionsFile,
 		handler:   loki.NewLogsReceiver(),
 		runner: runner.New(func(t eventControllerTask) runner.Worker {
@@ -137,7 +161,12 @@ func (c *Component) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	defer c.positions.Stop()
+	defer func() {
+		c.positions.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.log).Log("msg", "failed to close state store", "err", err)
+		}
+	}()
 	defer c.runner.Stop()
 
 	var rg run.Group
@@ -211,14 +240,16 @@ func (c *Component) Update(args component.Arguments) error {
 	var newTasks []eventControllerTask
 	for _, namespace := range getNamespaces(newArgs) {
 		newTasks = append(newTasks, eventControllerTask{
-			Log:          c.log,
-			Config:       restConfig,
-			JobName:      newArgs.JobName,
-			InstanceName: c.opts.ID,
-			Namespace:    namespace,
-			Receiver:     c.handler,
-			Positions:    c.positions,
-			LogFormat:    newArgs.LogFormat,
+			Log:           c.log,
+			Config:        restConfig,
+			JobName:       newArgs.JobName,
+			InstanceName:  c.opts.ID,
+			Namespace:     namespace,
+			Receiver:      c.handler,
+			Positions:     c.positions,
+			LogFormat:     newArgs.LogFormat,
+			FieldSelector: getFieldSelector(newArgs),
+			DedupWindow:   newArgs.DedupWindow,
 		})
 	}
 
@@ -245,6 +276,20 @@ func getNamespaces(args Arguments) []string {
 	return args.Namespaces
 }
 
+// getFieldSelector gets the field selector of the watched events from the
+// arguments. Excluded namespaces are filtered out by the Kubernetes API, so
+// that their events aren't sent to Alloy.
+func getFieldSelector(args Arguments) string {
+	var selectors []string
+	if args.FieldSelector != "" {
+		selectors = append(selectors, args.FieldSelector)
+	}
+	for _, namespace := range args.ExcludeNamespaces {
+		selectors = append(selectors, "metadata.namespace!="+namespace)
+	}
+	return strings.Join(selectors, ",")
+}
+
 // DebugInfo implements [component.DebugComponent].
 func (c *Component) DebugInfo() interface{} {
 	type Info struct {
@@ -0,0 +1,56 @@
+package kubernetes_events
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name: "namespaces and exclude_namespaces",
+			cfg: `
+				namespaces         = ["default"]
+				exclude_namespaces = ["kube-system"]
+			`,
+			expectErr: "namespaces and exclude_namespaces can't both be set",
+		},
+		{
+			name:      "invalid field_selector",
+			cfg:       `field_selector = "type"`,
+			expectErr: `invalid field_selector: invalid selector: 'type'; can't understand 'type'`,
+		},
+		{
+			name:      "negative dedup_window",
+			cfg:       `dedup_window = "-1m"`,
+			expectErr: "dedup_window must not be negative",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg + `
+				forward_to = []
+			`
+
+			var args Arguments
+			err := syntax.Unmarshal([]byte(cfg), &args)
+			require.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestGetFieldSelector(t *testing.T) {
+	args := DefaultArguments
+	require.Equal(t, "", getFieldSelector(args))
+
+	args.FieldSelector = "type=Warning"
+	args.ExcludeNamespaces = []string{"kube-system", "monitoring"}
+	require.Equal(t, "type=Warning,metadata.namespace!=kube-system,metadata.namespace!=monitoring", getFieldSelector(args))
+}
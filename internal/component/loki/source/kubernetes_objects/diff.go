@@ -0,0 +1,172 @@
+package kubernetes_objects
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const redactedValue = "<redacted>"
+
+// change is a difference between two versions of an object. Paths are JSON
+// pointers, as defined in RFC 6901.
+type change struct {
+	Op   string `json:"op"` // One of add, remove, or replace.
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// diff returns the changes between two values decoded from JSON. Lists of
+// different lengths are replaced as a whole.
+func diff(oldValue, newValue any) []change {
+	var changes []change
+	diffValues(nil, oldValue, newValue, &changes)
+	return changes
+}
+
+func diffValues(path []string, oldValue, newValue any, changes *[]change) {
+	switch oldTyped := oldValue.(type) {
+	case map[string]any:
+		if newTyped, ok := newValue.(map[string]any); ok {
+			diffMaps(path, oldTyped, newTyped, changes)
+			return
+		}
+
+	case []any:
+		if newTyped, ok := newValue.([]any); ok && len(oldTyped) == len(newTyped) {
+			for i := range oldTyped {
+				diffValues(append(path[:len(path):len(path)], strconv.Itoa(i)), oldTyped[i], newTyped[i], changes)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, change{Op: "replace", Path: formatPointer(path), Old: oldValue, New: newValue})
+	}
+}
+
+func diffMaps(path []string, oldMap, newMap map[string]any, changes *[]change) {
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for k := range oldMap {
+		keys = append(keys, k)
+	}
+	for k := range newMap {
+		if _, ok := oldMap[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		oldValue, inOld := oldMap[k]
+		newValue, inNew := newMap[k]
+		keyPath := append(path[:len(path):len(path)], k)
+
+		switch {
+		case !inOld:
+			*changes = append(*changes, change{Op: "add", Path: formatPointer(keyPath), New: newValue})
+		case !inNew:
+			*changes = append(*changes, change{Op: "remove", Path: formatPointer(keyPath), Old: oldValue})
+		default:
+			diffValues(keyPath, oldValue, newValue, changes)
+		}
+	}
+}
+
+// redactor replaces the values of an object which match patterns. Patterns
+// are JSON pointers whose segments can be a * wildcard, which matches any key
+// or index.
+type redactor struct {
+	patterns [][]string
+}
+
+func newRedactor(patterns []string) *redactor {
+	r := &redactor{}
+	for _, p := range patterns {
+		r.patterns = append(r.patterns, parsePointer(p))
+	}
+	return r
+}
+
+// redact returns a copy of value, found at path, where the values which match
+// the patterns of the redactor are replaced.
+func (r *redactor) redact(path []string, value any) any {
+	if r == nil || len(r.patterns) == 0 {
+		return value
+	}
+	return r.redactValue(path, value)
+}
+
+func (r *redactor) redactValue(path []string, value any) any {
+	if value == nil {
+		return nil
+	}
+	if r.matches(path) {
+		return redactedValue
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(typed))
+		for k, v := range typed {
+			res[k] = r.redactValue(append(path[:len(path):len(path)], k), v)
+		}
+		return res
+	case []any:
+		res := make([]any, len(typed))
+		for i, v := range typed {
+			res[i] = r.redactValue(append(path[:len(path):len(path)], strconv.Itoa(i)), v)
+		}
+		return res
+	default:
+		return value
+	}
+}
+
+// matches returns true if path, or one of its parents, matches a pattern.
+func (r *redactor) matches(path []string) bool {
+	for _, pattern := range r.patterns {
+		if len(pattern) > len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+func formatPointer(path []string) string {
+	var sb strings.Builder
+	for _, segment := range path {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(segment))
+	}
+	return sb.String()
+}
+
+func parsePointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = pointerUnescaper.Replace(segment)
+	}
+	return segments
+}
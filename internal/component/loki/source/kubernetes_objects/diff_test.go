@@ -0,0 +1,83 @@
+package kubernetes_objects
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	oldValue := map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{
+				"app.kubernetes.io/name": "app",
+				"tier":                   "backend",
+			},
+		},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"ports":    []any{int64(80), int64(443)},
+			"args":     []any{"--verbose"},
+		},
+	}
+	newValue := map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{
+				"app.kubernetes.io/name": "app-v2",
+				"team":                   "payments",
+			},
+		},
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"ports":    []any{int64(80), int64(8443)},
+			"args":     []any{"--verbose", "--debug"},
+		},
+	}
+
+	require.Equal(t, []change{
+		{Op: "replace", Path: "/metadata/labels/app.kubernetes.io~1name", Old: "app", New: "app-v2"},
+		{Op: "add", Path: "/metadata/labels/team", New: "payments"},
+		{Op: "remove", Path: "/metadata/labels/tier", Old: "backend"},
+		{Op: "replace", Path: "/spec/args", Old: []any{"--verbose"}, New: []any{"--verbose", "--debug"}},
+		{Op: "replace", Path: "/spec/ports/1", Old: int64(443), New: int64(8443)},
+		{Op: "replace", Path: "/spec/replicas", Old: int64(2), New: int64(3)},
+	}, diff(oldValue, newValue))
+
+	require.Empty(t, diff(oldValue, oldValue))
+}
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor([]string{"/data/*", "/spec/containers/*/env"})
+
+	value := map[string]any{
+		"data": map[string]any{"password": "hunter2"},
+		"spec": map[string]any{
+			"containers": []any{
+				map[string]any{
+					"name": "app",
+					"env":  []any{map[string]any{"name": "TOKEN", "value": "secret"}},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, map[string]any{
+		"data": map[string]any{"password": redactedValue},
+		"spec": map[string]any{
+			"containers": []any{
+				map[string]any{"name": "app", "env": redactedValue},
+			},
+		},
+	}, r.redact(nil, value))
+
+	// Values found below a redacted path are redacted as a whole.
+	require.Equal(t, redactedValue, r.redact([]string{"data", "password"}, "hunter2"))
+	require.Equal(t, "app", r.redact([]string{"spec", "containers", "0", "name"}, "app"))
+}
+
+func TestPointers(t *testing.T) {
+	path := []string{"metadata", "annotations", "example.com/a~b"}
+	pointer := formatPointer(path)
+	require.Equal(t, "/metadata/annotations/example.com~1a~0b", pointer)
+	require.Equal(t, path, parsePointer(pointer))
+}
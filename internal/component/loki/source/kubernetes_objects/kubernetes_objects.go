@@ -0,0 +1,269 @@
+// Package kubernetes_objects implements the loki.source.kubernetes_objects
+// component.
+package kubernetes_objects //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.source.kubernetes_objects",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the
+// loki.source.kubernetes_objects component.
+type Arguments struct {
+	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
+
+	JobName       string   `alloy:"job_name,attr,optional"`
+	Namespaces    []string `alloy:"namespaces,attr,optional"`
+	IncludeStatus bool     `alloy:"include_status,attr,optional"`
+	RedactPaths   []string `alloy:"redact_paths,attr,optional"`
+
+	Resources []ResourceArguments `alloy:"resource,block"`
+
+	// Client settings to connect to Kubernetes.
+	Client kubernetes.ClientArguments `alloy:"client,block,optional"`
+}
+
+// ResourceArguments selects the objects of a kind of resource to watch.
+type ResourceArguments struct {
+	Group         string `alloy:"group,attr,optional"`
+	Version       string `alloy:"version,attr"`
+	Resource      string `alloy:"resource,attr"`
+	LabelSelector string `alloy:"label_selector,attr,optional"`
+	FieldSelector string `alloy:"field_selector,attr,optional"`
+}
+
+func (args ResourceArguments) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: args.Group, Version: args.Version, Resource: args.Resource}
+}
+
+// String returns the resource in the format GROUP/VERSION/RESOURCE, or
+// VERSION/RESOURCE for resources of the core group.
+func (args ResourceArguments) String() string {
+	return schema.GroupVersion{Group: args.Group, Version: args.Version}.String() + "/" + args.Resource
+}
+
+// DefaultArguments holds default settings for loki.source.kubernetes_objects.
+var DefaultArguments = Arguments{
+	JobName: "loki.source.kubernetes_objects",
+
+	Client: kubernetes.DefaultClientArguments,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.JobName == "" {
+		return fmt.Errorf("job_name must not be an empty string")
+	}
+
+	for _, p := range args.RedactPaths {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("redact_paths must be JSON pointers starting with /, got %q", p)
+		}
+	}
+
+	seen := make(map[schema.GroupVersionResource]struct{}, len(args.Resources))
+	for _, r := range args.Resources {
+		if _, ok := seen[r.gvr()]; ok {
+			return fmt.Errorf("resource %s is defined more than once", r)
+		}
+		seen[r.gvr()] = struct{}{}
+
+		if _, err := labels.Parse(r.LabelSelector); err != nil {
+			return fmt.Errorf("resource %s: invalid label_selector: %w", r, err)
+		}
+		if _, err := fields.ParseSelector(r.FieldSelector); err != nil {
+			return fmt.Errorf("resource %s: invalid field_selector: %w", r, err)
+		}
+	}
+	return nil
+}
+
+// Component implements the loki.source.kubernetes_objects component, which
+// watches Kubernetes objects and forwards their changes to other Loki
+// components.
+type Component struct {
+	log     log.Logger
+	opts    component.Options
+	metrics *metrics
+	updated chan struct{}
+
+	mut        sync.Mutex
+	args       Arguments
+	restConfig *rest.Config
+
+	receiversMut sync.RWMutex
+	receivers    []loki.LogsReceiver
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new loki.source.kubernetes_objects component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		log:     o.Logger,
+		opts:    o,
+		metrics: newMetrics(o.Registerer),
+		updated: make(chan struct{}, 1),
+	}
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	// The watchers of the initial arguments are started by Run, so they don't
+	// need to be restarted.
+	<-c.updated
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	for {
+		c.mut.Lock()
+		args, restConfig := c.args, c.restConfig
+		c.mut.Unlock()
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		if err := c.startWatchers(watchCtx, &wg, args, restConfig); err != nil {
+			level.Error(c.log).Log("msg", "failed to watch objects", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			wg.Wait()
+			return nil
+		case <-c.updated:
+			cancel()
+			wg.Wait()
+		}
+	}
+}
+
+// startWatchers starts a watcher for each watched resource and namespace.
+func (c *Component) startWatchers(ctx context.Context, wg *sync.WaitGroup, args Arguments, restConfig *rest.Config) error {
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("creating Kubernetes client: %w", err)
+	}
+
+	rec := newRecorder(args.IncludeStatus, args.RedactPaths)
+	for _, resource := range args.Resources {
+		for _, namespace := range getNamespaces(args) {
+			w := &watcher{
+				log:       log.With(c.log, "resource", resource.String(), "namespace", namespace),
+				client:    client,
+				resource:  resource,
+				namespace: namespace,
+				jobName:   args.JobName,
+				instance:  c.opts.ID,
+				recorder:  rec,
+				metrics:   c.metrics,
+				send:      c.send,
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.run(ctx)
+			}()
+		}
+	}
+	return nil
+}
+
+// send forwards entry to the receivers.
+func (c *Component) send(ctx context.Context, entry loki.Entry) {
+	c.receiversMut.RLock()
+	receivers := c.receivers
+	c.receiversMut.RUnlock()
+
+	for _, receiver := range receivers {
+		select {
+		case <-ctx.Done():
+			return
+		case receiver.Chan() <- entry:
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	newArgs := args.(Arguments)
+
+	c.receiversMut.Lock()
+	c.receivers = newArgs.ForwardTo
+	c.receiversMut.Unlock()
+
+	restConfig := c.restConfig
+
+	// Create a new restConfig if we don't have one or if our arguments changed.
+	if restConfig == nil || !reflect.DeepEqual(c.args.Client, newArgs.Client) {
+		var err error
+		restConfig, err = newArgs.Client.BuildRESTConfig(c.log)
+		if err != nil {
+			return fmt.Errorf("building Kubernetes client config: %w", err)
+		}
+	}
+
+	// Watchers only need to be restarted when something other than the
+	// receivers changed.
+	oldArgs := c.args
+	oldArgs.ForwardTo, newArgs.ForwardTo = nil, nil
+	changed := c.restConfig != restConfig || !reflect.DeepEqual(oldArgs, newArgs)
+
+	c.args = args.(Arguments)
+	c.restConfig = restConfig
+
+	if changed {
+		select {
+		case c.updated <- struct{}{}:
+		default:
+			// no-op: restart already queued.
+		}
+	}
+	return nil
+}
+
+// getNamespaces gets a list of namespaces to watch from the arguments. If the
+// list of namespaces is empty, returns a slice to watch all namespaces.
+func getNamespaces(args Arguments) []string {
+	if len(args.Namespaces) == 0 {
+		return []string{""} // Empty string means to watch all namespaces
+	}
+	return args.Namespaces
+}
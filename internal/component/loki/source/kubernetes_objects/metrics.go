@@ -0,0 +1,21 @@
+package kubernetes_objects
+
+import (
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	changes *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		changes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_kubernetes_objects_changes_total",
+			Help: "Total number of object changes forwarded, by resource and action.",
+		}, []string{"resource", "action"}),
+	}
+	m.changes = util.MustRegisterOrGet(reg, m.changes).(*prometheus.CounterVec)
+	return m
+}
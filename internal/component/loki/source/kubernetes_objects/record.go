@@ -0,0 +1,133 @@
+package kubernetes_objects
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Actions of records.
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+// secretPatterns are the redaction patterns which are always applied to
+// Secrets. The keys of Secrets are kept, so that changes to them are visible.
+var secretPatterns = []string{
+	"/data/*",
+	"/stringData/*",
+	"/metadata/annotations/kubectl.kubernetes.io~1last-applied-configuration",
+}
+
+// record is the log line written for a change of an object.
+type record struct {
+	Action          string `json:"action"`
+	APIVersion      string `json:"apiVersion"`
+	Kind            string `json:"kind"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name"`
+	UID             string `json:"uid"`
+	ResourceVersion string `json:"resourceVersion"`
+
+	// Manager is the field manager which last changed the object, if known.
+	Manager string `json:"manager,omitempty"`
+
+	// Object is the snapshot of created and deleted objects.
+	Object map[string]any `json:"object,omitempty"`
+	// Changes are the changes of updated objects.
+	Changes []change `json:"changes,omitempty"`
+}
+
+// recorder builds the records of changes of objects.
+type recorder struct {
+	includeStatus bool
+	redactor      *redactor
+	secrets       *redactor
+}
+
+func newRecorder(includeStatus bool, redactPaths []string) *recorder {
+	return &recorder{
+		includeStatus: includeStatus,
+		redactor:      newRedactor(redactPaths),
+		secrets:       newRedactor(append(redactPaths[:len(redactPaths):len(redactPaths)], secretPatterns...)),
+	}
+}
+
+// record returns the record of an action on an object. For updates, oldObj is
+// the previous version of the object, and false is returned if nothing but
+// ignored fields changed.
+func (r *recorder) record(action string, oldObj, obj *unstructured.Unstructured) (*record, bool) {
+	res := &record{
+		Action:          action,
+		APIVersion:      obj.GetAPIVersion(),
+		Kind:            obj.GetKind(),
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		UID:             string(obj.GetUID()),
+		ResourceVersion: obj.GetResourceVersion(),
+		Manager:         lastManager(obj),
+	}
+
+	redactor := r.redactor
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		redactor = r.secrets
+	}
+
+	if action != actionUpdate {
+		res.Object = redactor.redact(nil, r.prune(obj.Object)).(map[string]any)
+		return res, true
+	}
+
+	for _, c := range diff(r.prune(oldObj.Object), r.prune(obj.Object)) {
+		path := parsePointer(c.Path)
+		c.Old = redactor.redact(path, c.Old)
+		c.New = redactor.redact(path, c.New)
+		res.Changes = append(res.Changes, c)
+	}
+	return res, len(res.Changes) > 0
+}
+
+// prune returns a shallow copy of obj without the fields which change on every
+// update or aren't relevant to auditing.
+func (r *recorder) prune(obj map[string]any) map[string]any {
+	res := make(map[string]any, len(obj))
+	for k, v := range obj {
+		res[k] = v
+	}
+	if !r.includeStatus {
+		delete(res, "status")
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]any); ok {
+		prunedMetadata := make(map[string]any, len(metadata))
+		for k, v := range metadata {
+			switch k {
+			case "managedFields", "resourceVersion", "generation":
+			default:
+				prunedMetadata[k] = v
+			}
+		}
+		res["metadata"] = prunedMetadata
+	}
+	return res
+}
+
+// lastManager returns the field manager with the most recent managed fields
+// entry of obj.
+func lastManager(obj *unstructured.Unstructured) string {
+	var (
+		manager string
+		latest  time.Time
+	)
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time == nil {
+			continue
+		}
+		if manager == "" || entry.Time.Time.After(latest) {
+			manager, latest = entry.Manager, entry.Time.Time
+		}
+	}
+	return manager
+}
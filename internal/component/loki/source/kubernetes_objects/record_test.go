@@ -0,0 +1,84 @@
+package kubernetes_objects
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRecorder_Secret(t *testing.T) {
+	oldObj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"namespace":       "default",
+			"name":            "credentials",
+			"uid":             "1234",
+			"resourceVersion": "1",
+			"managedFields": []any{
+				map[string]any{"manager": "kubectl-create", "operation": "Update", "time": "2024-01-01T00:00:00Z"},
+			},
+		},
+		"data": map[string]any{"password": "aHVudGVyMg=="},
+	}}
+	newObj := oldObj.DeepCopy()
+	newObj.SetResourceVersion("2")
+	newObj.Object["data"] = map[string]any{"password": "aHVudGVyMw==", "username": "YWRtaW4="}
+	newObj.Object["metadata"].(map[string]any)["managedFields"] = []any{
+		map[string]any{"manager": "kubectl-create", "operation": "Update", "time": "2024-01-01T00:00:00Z"},
+		map[string]any{"manager": "kubectl-edit", "operation": "Update", "time": "2024-01-02T00:00:00Z"},
+	}
+
+	r := newRecorder(false, nil)
+
+	rec, ok := r.record(actionCreate, nil, oldObj)
+	require.True(t, ok)
+	require.Equal(t, &record{
+		Action:          actionCreate,
+		APIVersion:      "v1",
+		Kind:            "Secret",
+		Namespace:       "default",
+		Name:            "credentials",
+		UID:             "1234",
+		ResourceVersion: "1",
+		Manager:         "kubectl-create",
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]any{"namespace": "default", "name": "credentials", "uid": "1234"},
+			"data":       map[string]any{"password": redactedValue},
+		},
+	}, rec)
+
+	rec, ok = r.record(actionUpdate, oldObj, newObj)
+	require.True(t, ok)
+	require.Equal(t, "kubectl-edit", rec.Manager)
+	require.Nil(t, rec.Object)
+	require.Equal(t, []change{
+		{Op: "replace", Path: "/data/password", Old: redactedValue, New: redactedValue},
+		{Op: "add", Path: "/data/username", New: redactedValue},
+	}, rec.Changes)
+}
+
+func TestRecorder_IgnoredChanges(t *testing.T) {
+	oldObj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"namespace": "default", "name": "app", "resourceVersion": "1", "generation": int64(1)},
+		"spec":       map[string]any{"replicas": int64(2)},
+		"status":     map[string]any{"readyReplicas": int64(1)},
+	}}
+	newObj := oldObj.DeepCopy()
+	newObj.SetResourceVersion("2")
+	newObj.Object["status"] = map[string]any{"readyReplicas": int64(2)}
+
+	_, ok := newRecorder(false, nil).record(actionUpdate, oldObj, newObj)
+	require.False(t, ok)
+
+	rec, ok := newRecorder(true, nil).record(actionUpdate, oldObj, newObj)
+	require.True(t, ok)
+	require.Equal(t, []change{
+		{Op: "replace", Path: "/status/readyReplicas", Old: int64(1), New: int64(2)},
+	}, rec.Changes)
+}
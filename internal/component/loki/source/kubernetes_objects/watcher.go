@@ -0,0 +1,122 @@
+package kubernetes_objects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watcher watches the objects of a resource in a namespace, and forwards
+// their changes.
+type watcher struct {
+	log       log.Logger
+	client    dynamic.Interface
+	resource  ResourceArguments
+	namespace string // Empty to watch all namespaces.
+	jobName   string
+	instance  string
+	recorder  *recorder
+	metrics   *metrics
+	send      func(context.Context, loki.Entry)
+}
+
+func (w *watcher) run(ctx context.Context) {
+	level.Info(w.log).Log("msg", "watching objects")
+	defer level.Info(w.log).Log("msg", "stopping watcher for objects")
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(
+		w.client, w.resource.gvr(), w.namespace, 0, cache.Indexers{},
+		func(opts *metav1.ListOptions) {
+			opts.LabelSelector = w.resource.LabelSelector
+			opts.FieldSelector = w.resource.FieldSelector
+		},
+	).Informer()
+
+	_ = informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		level.Warn(w.log).Log("msg", "failed to watch objects", "err", err)
+	})
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj any, isInInitialList bool) {
+			// Objects which existed before the watcher started weren't
+			// created, they're only listed.
+			if isInInitialList {
+				return
+			}
+			w.handle(ctx, actionCreate, nil, obj)
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			w.handle(ctx, actionUpdate, oldObj, newObj)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			w.handle(ctx, actionDelete, nil, obj)
+		},
+	})
+	if err != nil {
+		level.Error(w.log).Log("msg", "failed to watch objects", "err", err)
+		return
+	}
+
+	informer.Run(ctx.Done())
+}
+
+func (w *watcher) handle(ctx context.Context, action string, oldObj, obj any) {
+	newTyped, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		level.Warn(w.log).Log("msg", "received an unexpected object", "type", fmt.Sprintf("%T", obj))
+		return
+	}
+	var oldTyped *unstructured.Unstructured
+	if action == actionUpdate {
+		oldTyped, ok = oldObj.(*unstructured.Unstructured)
+		if !ok {
+			level.Warn(w.log).Log("msg", "received an unexpected object", "type", fmt.Sprintf("%T", oldObj))
+			return
+		}
+		if oldTyped.GetResourceVersion() == newTyped.GetResourceVersion() {
+			return
+		}
+	}
+
+	rec, ok := w.recorder.record(action, oldTyped, newTyped)
+	if !ok {
+		return
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		level.Error(w.log).Log("msg", "failed to marshal object change to JSON", "err", err)
+		return
+	}
+
+	lset := model.LabelSet{
+		"job":      model.LabelValue(w.jobName),
+		"instance": model.LabelValue(w.instance),
+	}
+	if rec.Namespace != "" {
+		lset["namespace"] = model.LabelValue(rec.Namespace)
+	}
+
+	w.metrics.changes.WithLabelValues(w.resource.String(), action).Inc()
+	w.send(ctx, loki.Entry{
+		Labels: lset,
+		Entry: logproto.Entry{
+			Timestamp: time.Now(),
+			Line:      string(line),
+		},
+	})
+}
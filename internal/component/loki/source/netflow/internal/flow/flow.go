@@ -0,0 +1,173 @@
+// Package flow decodes NetFlow v5, NetFlow v9, and IPFIX packets into flow
+// records.
+package flow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Packet types.
+const (
+	TypeNetFlowV5 = "NETFLOW_V5"
+	TypeNetFlowV9 = "NETFLOW_V9"
+	TypeIPFIX     = "IPFIX"
+)
+
+// Flow is a decoded flow record.
+type Flow struct {
+	Type         string `json:"type"`
+	Exporter     string `json:"exporter"`
+	SequenceNum  uint32 `json:"sequence_num"`
+	SamplingRate uint64 `json:"sampling_rate"`
+
+	TimeFlowStartMs int64 `json:"time_flow_start_ms,omitempty"`
+	TimeFlowEndMs   int64 `json:"time_flow_end_ms,omitempty"`
+
+	Bytes   uint64 `json:"bytes"`
+	Packets uint64 `json:"packets"`
+
+	SrcAddr  string `json:"src_addr,omitempty"`
+	DstAddr  string `json:"dst_addr,omitempty"`
+	NextHop  string `json:"next_hop,omitempty"`
+	SrcMask  uint8  `json:"src_mask,omitempty"`
+	DstMask  uint8  `json:"dst_mask,omitempty"`
+	SrcPort  uint16 `json:"src_port,omitempty"`
+	DstPort  uint16 `json:"dst_port,omitempty"`
+	Proto    uint8  `json:"proto"`
+	Etype    uint16 `json:"etype,omitempty"`
+	IPTos    uint8  `json:"ip_tos,omitempty"`
+	TCPFlags uint8  `json:"tcp_flags,omitempty"`
+	IcmpType uint8  `json:"icmp_type,omitempty"`
+	IcmpCode uint8  `json:"icmp_code,omitempty"`
+
+	InIf  uint32 `json:"in_if,omitempty"`
+	OutIf uint32 `json:"out_if,omitempty"`
+	SrcAS uint32 `json:"src_as,omitempty"`
+	DstAS uint32 `json:"dst_as,omitempty"`
+
+	SrcMac  string `json:"src_mac,omitempty"`
+	DstMac  string `json:"dst_mac,omitempty"`
+	SrcVlan uint16 `json:"src_vlan,omitempty"`
+	DstVlan uint16 `json:"dst_vlan,omitempty"`
+
+	FlowDirection    uint8 `json:"flow_direction,omitempty"`
+	ForwardingStatus uint8 `json:"forwarding_status,omitempty"`
+}
+
+// Packet is a decoded NetFlow or IPFIX packet.
+type Packet struct {
+	Type  string
+	Flows []Flow
+
+	// MissingTemplates is the number of data sets of the packet which were
+	// skipped because their template is unknown.
+	MissingTemplates int
+}
+
+// ErrUnsupportedVersion is returned when decoding a packet of an unsupported
+// version.
+var ErrUnsupportedVersion = errors.New("unsupported version")
+
+// Decoder decodes packets. Templates and sampling rates received from
+// exporters are cached by the decoder, so a single decoder must be used for
+// all the packets received by a listener. Decoder is safe for concurrent use.
+type Decoder struct {
+	templateTimeout time.Duration
+	now             func() time.Time
+
+	mut           sync.Mutex
+	templates     map[templateKey]*template
+	samplingRates map[domainKey]uint64
+	lastPrune     time.Time
+}
+
+// domainKey identifies an observation domain of an exporter.
+type domainKey struct {
+	exporter string
+	version  uint16
+	domainID uint32
+}
+
+// templateKey identifies a template of an observation domain.
+type templateKey struct {
+	domainKey
+	templateID uint16
+}
+
+// NewDecoder creates a new Decoder. Templates which aren't refreshed by their
+// exporter within templateTimeout are discarded. Templates never expire if
+// templateTimeout is 0.
+func NewDecoder(templateTimeout time.Duration) *Decoder {
+	return &Decoder{
+		templateTimeout: templateTimeout,
+		now:             time.Now,
+		templates:       make(map[templateKey]*template),
+		samplingRates:   make(map[domainKey]uint64),
+	}
+}
+
+// Decode decodes a packet received from exporter.
+func (d *Decoder) Decode(exporter string, payload []byte) (*Packet, error) {
+	if len(payload) < 2 {
+		return nil, fmt.Errorf("packet too short: %d bytes", len(payload))
+	}
+
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	d.maybePrune()
+
+	switch version := binary.BigEndian.Uint16(payload); version {
+	case 5:
+		return decodeNetFlowV5(exporter, payload)
+	case 9:
+		return d.decodeNetFlowV9(exporter, payload)
+	case 10:
+		return d.decodeIPFIX(exporter, payload)
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnsupportedVersion, version)
+	}
+}
+
+func (d *Decoder) addTemplate(key templateKey, t *template) {
+	t.updated = d.now()
+	d.templates[key] = t
+}
+
+func (d *Decoder) getTemplate(key templateKey) *template {
+	t, ok := d.templates[key]
+	if !ok {
+		return nil
+	}
+	if d.expired(t) {
+		delete(d.templates, key)
+		return nil
+	}
+	return t
+}
+
+func (d *Decoder) expired(t *template) bool {
+	return d.templateTimeout > 0 && d.now().Sub(t.updated) > d.templateTimeout
+}
+
+// maybePrune removes the expired templates at most once per template timeout,
+// so that templates of exporters which went away don't accumulate.
+func (d *Decoder) maybePrune() {
+	if d.templateTimeout <= 0 {
+		return
+	}
+	now := d.now()
+	if now.Sub(d.lastPrune) < d.templateTimeout {
+		return
+	}
+	d.lastPrune = now
+
+	for key, t := range d.templates {
+		if d.expired(t) {
+			delete(d.templates, key)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package flow
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// be appends big-endian encoded values to b. Values must be uint8, uint16,
+// uint32, uint64, or []byte.
+func be(b []byte, values ...any) []byte {
+	for _, v := range values {
+		switch v := v.(type) {
+		case uint8:
+			b = append(b, v)
+		case uint16:
+			b = binary.BigEndian.AppendUint16(b, v)
+		case uint32:
+			b = binary.BigEndian.AppendUint32(b, v)
+		case uint64:
+			b = binary.BigEndian.AppendUint64(b, v)
+		case []byte:
+			b = append(b, v...)
+		default:
+			panic("unsupported type")
+		}
+	}
+	return b
+}
+
+// set returns a NetFlow v9 or IPFIX set with the given ID and body.
+func set(id uint16, body []byte) []byte {
+	return be(nil, id, uint16(len(body)+4), body)
+}
+
+func netFlowV9Packet(sets ...[]byte) []byte {
+	// sysUptime is 10s, and the export time is 1700000000s, so the exporter
+	// booted at 1699999990s.
+	b := be(nil, uint16(9), uint16(len(sets)), uint32(10_000), uint32(1700000000), uint32(7), uint32(1))
+	for _, s := range sets {
+		b = append(b, s...)
+	}
+	return b
+}
+
+func ipfixPacket(sets ...[]byte) []byte {
+	var body []byte
+	for _, s := range sets {
+		body = append(body, s...)
+	}
+	return be(nil, uint16(10), uint16(ipfixHeaderLen+len(body)), uint32(1700000000), uint32(3), uint32(1), body)
+}
+
+func TestDecode_NetFlowV5(t *testing.T) {
+	header := be(nil,
+		uint16(5), uint16(1),
+		uint32(10_000),     // sysUptime
+		uint32(1700000000), // unix_secs
+		uint32(0),          // unix_nsecs
+		uint32(42),         // flow_sequence
+		uint8(0), uint8(0), // engine_type, engine_id
+		uint16(0x4000|100), // sampling_interval with mode bits
+	)
+	record := be(nil,
+		[]byte{10, 0, 0, 1}, []byte{10, 0, 0, 2}, []byte{10, 0, 0, 254},
+		uint16(3), uint16(4), // input, output
+		uint32(10), uint32(1500), // dPkts, dOctets
+		uint32(1_000), uint32(9_000), // first, last
+		uint16(51234), uint16(443), // srcport, dstport
+		uint8(0), uint8(0x18), uint8(6), uint8(0), // pad1, tcp_flags, prot, tos
+		uint16(65001), uint16(65002), // src_as, dst_as
+		uint8(24), uint8(16), uint16(0), // src_mask, dst_mask, pad2
+	)
+
+	pkt, err := NewDecoder(0).Decode("192.168.0.1", append(header, record...))
+	require.NoError(t, err)
+	require.Equal(t, TypeNetFlowV5, pkt.Type)
+	require.Equal(t, []Flow{{
+		Type:            TypeNetFlowV5,
+		Exporter:        "192.168.0.1",
+		SequenceNum:     42,
+		SamplingRate:    100,
+		TimeFlowStartMs: 1699999991000,
+		TimeFlowEndMs:   1699999999000,
+		Bytes:           1500,
+		Packets:         10,
+		SrcAddr:         "10.0.0.1",
+		DstAddr:         "10.0.0.2",
+		NextHop:         "10.0.0.254",
+		SrcMask:         24,
+		DstMask:         16,
+		SrcPort:         51234,
+		DstPort:         443,
+		Proto:           6,
+		Etype:           etypeIPv4,
+		TCPFlags:        0x18,
+		InIf:            3,
+		OutIf:           4,
+		SrcAS:           65001,
+		DstAS:           65002,
+	}}, pkt.Flows)
+
+	_, err = NewDecoder(0).Decode("192.168.0.1", header)
+	require.EqualError(t, err, "netflow v5: expected 72 bytes for 1 records, got 24")
+}
+
+var (
+	netFlowV9Template = set(netFlowV9TemplateSetID, be(nil,
+		uint16(256), uint16(7),
+		uint16(fieldIPv4SrcAddr), uint16(4),
+		uint16(fieldIPv4DstAddr), uint16(4),
+		uint16(fieldProtocol), uint16(1),
+		uint16(fieldInBytes), uint16(8),
+		uint16(fieldInPkts), uint16(4),
+		uint16(fieldFirstSwitched), uint16(4),
+		uint16(fieldLastSwitched), uint16(4),
+		uint16(0), // Padding.
+	))
+
+	netFlowV9Data = set(256, be(nil,
+		[]byte{10, 0, 0, 1}, []byte{10, 0, 0, 2}, uint8(17), uint64(1000), uint32(2), uint32(2_000), uint32(3_000),
+		[]byte{10, 0, 0, 3}, []byte{10, 0, 0, 4}, uint8(6), uint64(500), uint32(1), uint32(4_000), uint32(5_000),
+		uint8(0), uint8(0), uint8(0), // Padding.
+	))
+
+	netFlowV9OptionsTemplate = set(netFlowV9OptionsTemplateSetID, be(nil,
+		uint16(257), uint16(4), uint16(8),
+		uint16(1), uint16(4), // Scope: System. Type 1 is also fieldInBytes.
+		uint16(fieldSamplingInterval), uint16(4),
+		uint16(fieldInBytes), uint16(4),
+	))
+
+	netFlowV9OptionsData = set(257, be(nil, uint32(1), uint32(512), uint32(99)))
+)
+
+func TestDecode_NetFlowV9(t *testing.T) {
+	d := NewDecoder(time.Hour)
+
+	// Data received before the template is skipped.
+	pkt, err := d.Decode("192.168.0.1", netFlowV9Packet(netFlowV9Data))
+	require.NoError(t, err)
+	require.Empty(t, pkt.Flows)
+	require.Equal(t, 1, pkt.MissingTemplates)
+
+	pkt, err = d.Decode("192.168.0.1", netFlowV9Packet(netFlowV9Template, netFlowV9Data))
+	require.NoError(t, err)
+	require.Equal(t, TypeNetFlowV9, pkt.Type)
+	require.Zero(t, pkt.MissingTemplates)
+	require.Equal(t, []Flow{
+		{
+			Type:            TypeNetFlowV9,
+			Exporter:        "192.168.0.1",
+			SequenceNum:     7,
+			TimeFlowStartMs: 1699999992000,
+			TimeFlowEndMs:   1699999993000,
+			Bytes:           1000,
+			Packets:         2,
+			SrcAddr:         "10.0.0.1",
+			DstAddr:         "10.0.0.2",
+			Proto:           17,
+			Etype:           etypeIPv4,
+		},
+		{
+			Type:            TypeNetFlowV9,
+			Exporter:        "192.168.0.1",
+			SequenceNum:     7,
+			TimeFlowStartMs: 1699999994000,
+			TimeFlowEndMs:   1699999995000,
+			Bytes:           500,
+			Packets:         1,
+			SrcAddr:         "10.0.0.3",
+			DstAddr:         "10.0.0.4",
+			Proto:           6,
+			Etype:           etypeIPv4,
+		},
+	}, pkt.Flows)
+
+	// Templates are cached per exporter.
+	pkt, err = d.Decode("192.168.0.2", netFlowV9Packet(netFlowV9Data))
+	require.NoError(t, err)
+	require.Equal(t, 1, pkt.MissingTemplates)
+
+	// The sampling rate reported by options records applies to the following
+	// flows of the exporter.
+	pkt, err = d.Decode("192.168.0.1", netFlowV9Packet(netFlowV9OptionsTemplate, netFlowV9OptionsData, netFlowV9Data))
+	require.NoError(t, err)
+	require.Len(t, pkt.Flows, 2)
+	require.Equal(t, uint64(512), pkt.Flows[0].SamplingRate)
+	require.Equal(t, uint64(512), pkt.Flows[1].SamplingRate)
+}
+
+func TestDecode_TemplateTimeout(t *testing.T) {
+	now := time.Now()
+	d := NewDecoder(time.Minute)
+	d.now = func() time.Time { return now }
+
+	_, err := d.Decode("192.168.0.1", netFlowV9Packet(netFlowV9Template))
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	pkt, err := d.Decode("192.168.0.1", netFlowV9Packet(netFlowV9Data))
+	require.NoError(t, err)
+	require.Len(t, pkt.Flows, 2)
+
+	now = now.Add(time.Minute)
+	pkt, err = d.Decode("192.168.0.1", netFlowV9Packet(netFlowV9Data))
+	require.NoError(t, err)
+	require.Empty(t, pkt.Flows)
+	require.Equal(t, 1, pkt.MissingTemplates)
+	require.Empty(t, d.templates)
+}
+
+func TestDecode_IPFIX(t *testing.T) {
+	template := set(ipfixTemplateSetID, be(nil,
+		uint16(300), uint16(8),
+		uint16(fieldIPv6SrcAddr), uint16(16),
+		uint16(fieldIPv6DstAddr), uint16(16),
+		uint16(fieldL4SrcPort), uint16(2),
+		uint16(fieldL4DstPort), uint16(2),
+		uint16(fieldInBytes), uint16(4), // Reduced-size encoding.
+		uint16(fieldFlowStartMillis), uint16(8),
+		uint16(0x8000|fieldInBytes), uint16(variableLength), uint32(9), // Enterprise-specific.
+		uint16(fieldInSrcMac), uint16(6),
+	))
+
+	src := []byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}
+	dst := []byte{0x20, 0x01, 0x0d, 0xb8, 15: 2}
+	data := set(300, be(nil,
+		src, dst, uint16(1234), uint16(53), uint32(4096), uint64(1700000000123),
+		uint8(3), []byte("abc"),
+		[]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+	))
+
+	optionsTemplate := set(ipfixOptionsTemplateSetID, be(nil,
+		uint16(301), uint16(2), uint16(1),
+		uint16(149), uint16(4), // Scope: observationDomainId.
+		uint16(fieldSamplingPacketInterval), uint16(4),
+	))
+	optionsData := set(301, be(nil, uint32(1), uint32(1000)))
+
+	d := NewDecoder(0)
+	pkt, err := d.Decode("192.168.0.1", ipfixPacket(template, optionsTemplate, optionsData, data))
+	require.NoError(t, err)
+	require.Equal(t, TypeIPFIX, pkt.Type)
+	require.Equal(t, []Flow{{
+		Type:            TypeIPFIX,
+		Exporter:        "192.168.0.1",
+		SequenceNum:     3,
+		SamplingRate:    1000,
+		TimeFlowStartMs: 1700000000123,
+		Bytes:           4096,
+		SrcAddr:         "2001:db8::1",
+		DstAddr:         "2001:db8::2",
+		SrcPort:         1234,
+		DstPort:         53,
+		Etype:           etypeIPv6,
+		SrcMac:          "00:11:22:33:44:55",
+	}}, pkt.Flows)
+
+	// A template without fields withdraws the template.
+	withdrawal := set(ipfixTemplateSetID, be(nil, uint16(300), uint16(0)))
+	pkt, err = d.Decode("192.168.0.1", ipfixPacket(withdrawal, data))
+	require.NoError(t, err)
+	require.Empty(t, pkt.Flows)
+	require.Equal(t, 1, pkt.MissingTemplates)
+}
+
+func TestDecode_IPFIXSystemInitTime(t *testing.T) {
+	template := set(ipfixTemplateSetID, be(nil,
+		uint16(256), uint16(3),
+		uint16(fieldFirstSwitched), uint16(4),
+		uint16(fieldLastSwitched), uint16(4),
+		uint16(fieldSystemInitMillis), uint16(8),
+	))
+	data := set(256, be(nil, uint32(1_000), uint32(2_000), uint64(1700000000000)))
+
+	pkt, err := NewDecoder(0).Decode("192.168.0.1", ipfixPacket(template, data))
+	require.NoError(t, err)
+	require.Len(t, pkt.Flows, 1)
+	require.Equal(t, int64(1700000001000), pkt.Flows[0].TimeFlowStartMs)
+	require.Equal(t, int64(1700000002000), pkt.Flows[0].TimeFlowEndMs)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		payload   []byte
+		expectErr string
+	}{
+		"empty": {
+			payload:   nil,
+			expectErr: "packet too short: 0 bytes",
+		},
+		"unsupported version": {
+			payload:   be(nil, uint16(7), uint16(0)),
+			expectErr: "unsupported version 7",
+		},
+		"netflow v9 truncated header": {
+			payload:   be(nil, uint16(9), uint16(0)),
+			expectErr: "netflow v9: header too short: 4 bytes",
+		},
+		"netflow v9 invalid set length": {
+			payload:   append(netFlowV9Packet(), be(nil, uint16(256), uint16(100))...),
+			expectErr: "netflow v9: set 256: invalid length 100 for 4 bytes",
+		},
+		"ipfix invalid message length": {
+			payload:   be(nil, uint16(10), uint16(100), uint32(0), uint32(0), uint32(0)),
+			expectErr: "ipfix: invalid message length 100 for 16 bytes",
+		},
+		"ipfix truncated template": {
+			payload:   ipfixPacket(set(ipfixTemplateSetID, be(nil, uint16(256), uint16(2), uint16(1), uint16(4)))),
+			expectErr: "ipfix: template 256: truncated fields",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewDecoder(0).Decode("192.168.0.1", tc.payload)
+			require.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package flow
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ipfixHeaderLen = 16
+
+	ipfixTemplateSetID        = 2
+	ipfixOptionsTemplateSetID = 3
+)
+
+// decodeIPFIX decodes an IPFIX packet, as defined in RFC 7011.
+func (d *Decoder) decodeIPFIX(exporter string, payload []byte) (*Packet, error) {
+	if len(payload) < ipfixHeaderLen {
+		return nil, fmt.Errorf("ipfix: header too short: %d bytes", len(payload))
+	}
+
+	var (
+		length      = int(binary.BigEndian.Uint16(payload[2:]))
+		sequenceNum = binary.BigEndian.Uint32(payload[8:])
+		domainID    = binary.BigEndian.Uint32(payload[12:])
+	)
+	if length < ipfixHeaderLen || length > len(payload) {
+		return nil, fmt.Errorf("ipfix: invalid message length %d for %d bytes", length, len(payload))
+	}
+
+	domain := domainKey{exporter: exporter, version: 10, domainID: domainID}
+	base := Flow{
+		Type:        TypeIPFIX,
+		Exporter:    exporter,
+		SequenceNum: sequenceNum,
+	}
+
+	pkt := &Packet{Type: TypeIPFIX}
+	err := forEachSet(payload[ipfixHeaderLen:length], func(id uint16, body []byte) error {
+		switch {
+		case id == ipfixTemplateSetID:
+			return d.decodeIPFIXTemplates(domain, body, false)
+		case id == ipfixOptionsTemplateSetID:
+			return d.decodeIPFIXTemplates(domain, body, true)
+		case id >= minDataSetID:
+			return d.decodeDataSet(pkt, domain, id, body, base, 0)
+		default:
+			// Set IDs 0, 1 and 4-255 are reserved.
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ipfix: %w", err)
+	}
+	return pkt, nil
+}
+
+func (d *Decoder) decodeIPFIXTemplates(domain domainKey, body []byte, options bool) error {
+	headerLen := 4
+	if options {
+		headerLen = 6
+	}
+
+	for len(body) >= headerLen {
+		var (
+			templateID = binary.BigEndian.Uint16(body)
+			fieldCount = int(binary.BigEndian.Uint16(body[2:]))
+			scopeCount int
+		)
+		if templateID < minDataSetID {
+			// The remaining bytes are padding.
+			return nil
+		}
+		key := templateKey{domainKey: domain, templateID: templateID}
+
+		// A template without fields withdraws the template.
+		if fieldCount == 0 {
+			delete(d.templates, key)
+			body = body[4:]
+			continue
+		}
+
+		if options {
+			scopeCount = int(binary.BigEndian.Uint16(body[4:]))
+		}
+		body = body[headerLen:]
+
+		fields := make([]field, fieldCount)
+		for i := range fields {
+			if len(body) < 4 {
+				return fmt.Errorf("template %d: truncated fields", templateID)
+			}
+			f := field{
+				typ:    binary.BigEndian.Uint16(body),
+				length: binary.BigEndian.Uint16(body[2:]),
+				scope:  i < scopeCount,
+			}
+			body = body[4:]
+
+			// The enterprise bit indicates an enterprise-specific field,
+			// followed by its enterprise number.
+			if f.typ&0x8000 != 0 {
+				if len(body) < 4 {
+					return fmt.Errorf("template %d: truncated fields", templateID)
+				}
+				f.typ &= 0x7fff
+				f.enterprise = binary.BigEndian.Uint32(body)
+				body = body[4:]
+			}
+			fields[i] = f
+		}
+
+		d.addTemplate(key, newTemplate(fields, options))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package flow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+)
+
+const (
+	netFlowV5HeaderLen = 24
+	netFlowV5RecordLen = 48
+)
+
+// decodeNetFlowV5 decodes a NetFlow v5 packet. NetFlow v5 packets have a fixed
+// format and don't use templates.
+func decodeNetFlowV5(exporter string, payload []byte) (*Packet, error) {
+	if len(payload) < netFlowV5HeaderLen {
+		return nil, fmt.Errorf("netflow v5: header too short: %d bytes", len(payload))
+	}
+
+	var (
+		count        = int(binary.BigEndian.Uint16(payload[2:]))
+		sysUptime    = int64(binary.BigEndian.Uint32(payload[4:]))
+		unixSecs     = int64(binary.BigEndian.Uint32(payload[8:]))
+		unixNsecs    = int64(binary.BigEndian.Uint32(payload[12:]))
+		sequenceNum  = binary.BigEndian.Uint32(payload[16:])
+		samplingRate = uint64(binary.BigEndian.Uint16(payload[22:]) & 0x3fff)
+	)
+
+	if want := netFlowV5HeaderLen + count*netFlowV5RecordLen; len(payload) < want {
+		return nil, fmt.Errorf("netflow v5: expected %d bytes for %d records, got %d", want, count, len(payload))
+	}
+
+	// The flow times are sysUptime values, which are converted to Unix times
+	// using the export time of the packet.
+	bootMs := unixSecs*1000 + unixNsecs/1e6 - sysUptime
+
+	pkt := &Packet{
+		Type:  TypeNetFlowV5,
+		Flows: make([]Flow, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		r := payload[netFlowV5HeaderLen+i*netFlowV5RecordLen:]
+
+		pkt.Flows = append(pkt.Flows, Flow{
+			Type:         TypeNetFlowV5,
+			Exporter:     exporter,
+			SequenceNum:  sequenceNum,
+			SamplingRate: samplingRate,
+
+			TimeFlowStartMs: bootMs + int64(binary.BigEndian.Uint32(r[24:])),
+			TimeFlowEndMs:   bootMs + int64(binary.BigEndian.Uint32(r[28:])),
+
+			Packets: uint64(binary.BigEndian.Uint32(r[16:])),
+			Bytes:   uint64(binary.BigEndian.Uint32(r[20:])),
+
+			SrcAddr:  netip.AddrFrom4([4]byte(r[0:4])).String(),
+			DstAddr:  netip.AddrFrom4([4]byte(r[4:8])).String(),
+			NextHop:  netip.AddrFrom4([4]byte(r[8:12])).String(),
+			SrcPort:  binary.BigEndian.Uint16(r[32:]),
+			DstPort:  binary.BigEndian.Uint16(r[34:]),
+			TCPFlags: r[37],
+			Proto:    r[38],
+			IPTos:    r[39],
+			Etype:    etypeIPv4,
+			SrcMask:  r[44],
+			DstMask:  r[45],
+
+			InIf:  uint32(binary.BigEndian.Uint16(r[12:])),
+			OutIf: uint32(binary.BigEndian.Uint16(r[14:])),
+			SrcAS: uint32(binary.BigEndian.Uint16(r[40:])),
+			DstAS: uint32(binary.BigEndian.Uint16(r[42:])),
+		})
+	}
+	return pkt, nil
+}
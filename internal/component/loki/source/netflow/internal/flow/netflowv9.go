@@ -0,0 +1,114 @@
+package flow
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	netFlowV9HeaderLen = 20
+
+	netFlowV9TemplateSetID        = 0
+	netFlowV9OptionsTemplateSetID = 1
+)
+
+// decodeNetFlowV9 decodes a NetFlow v9 packet, as defined in RFC 3954.
+func (d *Decoder) decodeNetFlowV9(exporter string, payload []byte) (*Packet, error) {
+	if len(payload) < netFlowV9HeaderLen {
+		return nil, fmt.Errorf("netflow v9: header too short: %d bytes", len(payload))
+	}
+
+	var (
+		sysUptime   = int64(binary.BigEndian.Uint32(payload[4:]))
+		unixSecs    = int64(binary.BigEndian.Uint32(payload[8:]))
+		sequenceNum = binary.BigEndian.Uint32(payload[12:])
+		sourceID    = binary.BigEndian.Uint32(payload[16:])
+	)
+
+	domain := domainKey{exporter: exporter, version: 9, domainID: sourceID}
+	base := Flow{
+		Type:        TypeNetFlowV9,
+		Exporter:    exporter,
+		SequenceNum: sequenceNum,
+	}
+	bootMs := unixSecs*1000 - sysUptime
+
+	pkt := &Packet{Type: TypeNetFlowV9}
+	err := forEachSet(payload[netFlowV9HeaderLen:], func(id uint16, body []byte) error {
+		switch {
+		case id == netFlowV9TemplateSetID:
+			return d.decodeNetFlowV9Templates(domain, body)
+		case id == netFlowV9OptionsTemplateSetID:
+			return d.decodeNetFlowV9OptionsTemplates(domain, body)
+		case id >= minDataSetID:
+			return d.decodeDataSet(pkt, domain, id, body, base, bootMs)
+		default:
+			// Set IDs 2-255 are reserved.
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("netflow v9: %w", err)
+	}
+	return pkt, nil
+}
+
+func (d *Decoder) decodeNetFlowV9Templates(domain domainKey, body []byte) error {
+	for len(body) >= 4 {
+		var (
+			templateID = binary.BigEndian.Uint16(body)
+			fieldCount = int(binary.BigEndian.Uint16(body[2:]))
+		)
+		if templateID < minDataSetID {
+			// The remaining bytes are padding.
+			return nil
+		}
+		body = body[4:]
+
+		if len(body) < fieldCount*4 {
+			return fmt.Errorf("template %d: truncated fields", templateID)
+		}
+		fields := make([]field, fieldCount)
+		for i := range fields {
+			fields[i] = field{
+				typ:    binary.BigEndian.Uint16(body[i*4:]),
+				length: binary.BigEndian.Uint16(body[i*4+2:]),
+			}
+		}
+		body = body[fieldCount*4:]
+
+		d.addTemplate(templateKey{domainKey: domain, templateID: templateID}, newTemplate(fields, false))
+	}
+	return nil
+}
+
+func (d *Decoder) decodeNetFlowV9OptionsTemplates(domain domainKey, body []byte) error {
+	for len(body) >= 6 {
+		var (
+			templateID = binary.BigEndian.Uint16(body)
+			scopeLen   = int(binary.BigEndian.Uint16(body[2:]))
+			optionsLen = int(binary.BigEndian.Uint16(body[4:]))
+		)
+		if templateID < minDataSetID {
+			// The remaining bytes are padding.
+			return nil
+		}
+		body = body[6:]
+
+		if scopeLen%4 != 0 || optionsLen%4 != 0 || len(body) < scopeLen+optionsLen {
+			return fmt.Errorf("options template %d: invalid fields length", templateID)
+		}
+		fields := make([]field, (scopeLen+optionsLen)/4)
+		for i := range fields {
+			fields[i] = field{
+				typ:    binary.BigEndian.Uint16(body[i*4:]),
+				length: binary.BigEndian.Uint16(body[i*4+2:]),
+				scope:  i < scopeLen/4,
+			}
+		}
+		body = body[scopeLen+optionsLen:]
+
+		d.addTemplate(templateKey{domainKey: domain, templateID: templateID}, newTemplate(fields, true))
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package flow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+const (
+	// minDataSetID is the lowest ID of data sets, which is also the lowest
+	// template ID, for both NetFlow v9 and IPFIX.
+	minDataSetID = 256
+
+	// variableLength is the length of IPFIX fields with a variable length.
+	variableLength = 0xffff
+)
+
+const (
+	etypeIPv4 = 0x0800
+	etypeIPv6 = 0x86dd
+)
+
+// Information elements shared by NetFlow v9 and IPFIX.
+const (
+	fieldInBytes                = 1
+	fieldInPkts                 = 2
+	fieldProtocol               = 4
+	fieldSrcTos                 = 5
+	fieldTCPFlags               = 6
+	fieldL4SrcPort              = 7
+	fieldIPv4SrcAddr            = 8
+	fieldSrcMask                = 9
+	fieldInputSNMP              = 10
+	fieldL4DstPort              = 11
+	fieldIPv4DstAddr            = 12
+	fieldDstMask                = 13
+	fieldOutputSNMP             = 14
+	fieldIPv4NextHop            = 15
+	fieldSrcAS                  = 16
+	fieldDstAS                  = 17
+	fieldLastSwitched           = 21
+	fieldFirstSwitched          = 22
+	fieldOutBytes               = 23
+	fieldOutPkts                = 24
+	fieldIPv6SrcAddr            = 27
+	fieldIPv6DstAddr            = 28
+	fieldIPv6SrcMask            = 29
+	fieldIPv6DstMask            = 30
+	fieldIcmpType               = 32
+	fieldSamplingInterval       = 34
+	fieldSamplerInterval        = 50
+	fieldInSrcMac               = 56
+	fieldOutDstMac              = 57
+	fieldSrcVlan                = 58
+	fieldDstVlan                = 59
+	fieldIPVersion              = 60
+	fieldDirection              = 61
+	fieldIPv6NextHop            = 62
+	fieldInDstMac               = 80
+	fieldForwardingStatus       = 89
+	fieldIcmpTypeCodeIPv6       = 139
+	fieldFlowStartSeconds       = 150
+	fieldFlowEndSeconds         = 151
+	fieldFlowStartMillis        = 152
+	fieldFlowEndMillis          = 153
+	fieldSystemInitMillis       = 160
+	fieldEthernetType           = 256
+	fieldSamplingPacketInterval = 305
+)
+
+// field is a field of a template.
+type field struct {
+	typ        uint16
+	length     uint16
+	enterprise uint32
+	// scope is true for the scope fields of options templates, whose types
+	// don't share the numbering of the other fields.
+	scope bool
+}
+
+// ignored returns true if the value of the field isn't decoded.
+func (f field) ignored() bool {
+	return f.enterprise != 0 || f.scope
+}
+
+// template describes the format of the records of a data set.
+type template struct {
+	fields []field
+	// options is true for options templates, whose records hold metadata
+	// about the exporter, such as its sampling rate, rather than flows.
+	options bool
+	// minLength is the minimum length of a record.
+	minLength int
+
+	updated time.Time
+}
+
+func newTemplate(fields []field, options bool) *template {
+	t := &template{fields: fields, options: options}
+	for _, f := range fields {
+		if f.length == variableLength {
+			t.minLength++
+		} else {
+			t.minLength += int(f.length)
+		}
+	}
+	return t
+}
+
+// records splits data into the records of the template, where each record is
+// a slice of field values. Padding at the end of data is ignored.
+func (t *template) records(data []byte) ([][][]byte, error) {
+	var records [][][]byte
+
+	for t.minLength > 0 && len(data) >= t.minLength {
+		values := make([][]byte, len(t.fields))
+		for i, f := range t.fields {
+			length := int(f.length)
+			if f.length == variableLength {
+				if len(data) < 1 {
+					return nil, fmt.Errorf("truncated variable length field %d", f.typ)
+				}
+				length, data = int(data[0]), data[1:]
+				if length == 0xff {
+					if len(data) < 2 {
+						return nil, fmt.Errorf("truncated variable length field %d", f.typ)
+					}
+					length, data = int(binary.BigEndian.Uint16(data)), data[2:]
+				}
+			}
+			if len(data) < length {
+				return nil, fmt.Errorf("truncated field %d: expected %d bytes, got %d", f.typ, length, len(data))
+			}
+			values[i], data = data[:length], data[length:]
+		}
+		records = append(records, values)
+	}
+	return records, nil
+}
+
+// forEachSet calls fn for each set of the NetFlow v9 or IPFIX packet body
+// data. Both versions use the same set header, made of the set ID and the
+// length of the set including its header.
+func forEachSet(data []byte, fn func(id uint16, body []byte) error) error {
+	for len(data) >= 4 {
+		var (
+			id     = binary.BigEndian.Uint16(data)
+			length = int(binary.BigEndian.Uint16(data[2:]))
+		)
+		if length < 4 || length > len(data) {
+			return fmt.Errorf("set %d: invalid length %d for %d bytes", id, length, len(data))
+		}
+		if err := fn(id, data[4:length]); err != nil {
+			return err
+		}
+		data = data[length:]
+	}
+	return nil
+}
+
+// decodeDataSet decodes the records of a data set into flows, which are
+// appended to pkt. The flows are initialized from base. Records of options
+// templates update the sampling rate of domain instead.
+func (d *Decoder) decodeDataSet(pkt *Packet, domain domainKey, id uint16, body []byte, base Flow, bootMs int64) error {
+	t := d.getTemplate(templateKey{domainKey: domain, templateID: id})
+	if t == nil {
+		pkt.MissingTemplates++
+		return nil
+	}
+
+	records, err := t.records(body)
+	if err != nil {
+		return fmt.Errorf("set %d: %w", id, err)
+	}
+
+	for _, values := range records {
+		if t.options {
+			if rate := decodeSamplingRate(t, values); rate != 0 {
+				d.samplingRates[domain] = rate
+			}
+			continue
+		}
+
+		f := base
+		f.SamplingRate = d.samplingRates[domain]
+		decodeRecord(t, values, &f, bootMs)
+		pkt.Flows = append(pkt.Flows, f)
+	}
+	return nil
+}
+
+// recordTimes holds the fields of a record which are needed to compute the
+// times of its flow.
+type recordTimes struct {
+	// bootMs is the Unix time of the boot of the exporter in milliseconds, as
+	// computed from the packet header. It is 0 for IPFIX.
+	bootMs int64
+
+	firstSwitched, lastSwitched       int64
+	hasFirstSwitched, hasLastSwitched bool
+	systemInitMs                      int64
+	outBytes, outPkts                 uint64
+}
+
+// decodeRecord decodes a data record into a flow. Fields which aren't
+// supported are ignored.
+func decodeRecord(t *template, values [][]byte, f *Flow, bootMs int64) {
+	times := recordTimes{bootMs: bootMs}
+
+	for i, fld := range t.fields {
+		if fld.ignored() {
+			continue
+		}
+		v := values[i]
+
+		switch fld.typ {
+		case fieldInBytes:
+			f.Bytes = decodeUint(v)
+		case fieldInPkts:
+			f.Packets = decodeUint(v)
+		case fieldOutBytes:
+			times.outBytes = decodeUint(v)
+		case fieldOutPkts:
+			times.outPkts = decodeUint(v)
+		case fieldProtocol:
+			f.Proto = uint8(decodeUint(v))
+		case fieldSrcTos:
+			f.IPTos = uint8(decodeUint(v))
+		case fieldTCPFlags:
+			f.TCPFlags = uint8(decodeUint(v))
+		case fieldL4SrcPort:
+			f.SrcPort = uint16(decodeUint(v))
+		case fieldL4DstPort:
+			f.DstPort = uint16(decodeUint(v))
+		case fieldIPv4SrcAddr:
+			f.SrcAddr = decodeAddr(v)
+			f.Etype = etypeIPv4
+		case fieldIPv4DstAddr:
+			f.DstAddr = decodeAddr(v)
+			f.Etype = etypeIPv4
+		case fieldIPv6SrcAddr:
+			f.SrcAddr = decodeAddr(v)
+			f.Etype = etypeIPv6
+		case fieldIPv6DstAddr:
+			f.DstAddr = decodeAddr(v)
+			f.Etype = etypeIPv6
+		case fieldIPv4NextHop, fieldIPv6NextHop:
+			f.NextHop = decodeAddr(v)
+		case fieldSrcMask, fieldIPv6SrcMask:
+			f.SrcMask = uint8(decodeUint(v))
+		case fieldDstMask, fieldIPv6DstMask:
+			f.DstMask = uint8(decodeUint(v))
+		case fieldInputSNMP:
+			f.InIf = uint32(decodeUint(v))
+		case fieldOutputSNMP:
+			f.OutIf = uint32(decodeUint(v))
+		case fieldSrcAS:
+			f.SrcAS = uint32(decodeUint(v))
+		case fieldDstAS:
+			f.DstAS = uint32(decodeUint(v))
+		case fieldIcmpType, fieldIcmpTypeCodeIPv6:
+			typeCode := decodeUint(v)
+			f.IcmpType, f.IcmpCode = uint8(typeCode>>8), uint8(typeCode)
+		case fieldSamplingInterval, fieldSamplerInterval, fieldSamplingPacketInterval:
+			f.SamplingRate = decodeUint(v)
+		case fieldInSrcMac:
+			f.SrcMac = net.HardwareAddr(v).String()
+		case fieldOutDstMac, fieldInDstMac:
+			f.DstMac = net.HardwareAddr(v).String()
+		case fieldSrcVlan:
+			f.SrcVlan = uint16(decodeUint(v))
+		case fieldDstVlan:
+			f.DstVlan = uint16(decodeUint(v))
+		case fieldIPVersion:
+			switch decodeUint(v) {
+			case 4:
+				f.Etype = etypeIPv4
+			case 6:
+				f.Etype = etypeIPv6
+			}
+		case fieldEthernetType:
+			f.Etype = uint16(decodeUint(v))
+		case fieldDirection:
+			f.FlowDirection = uint8(decodeUint(v))
+		case fieldForwardingStatus:
+			f.ForwardingStatus = uint8(decodeUint(v))
+		case fieldFirstSwitched:
+			times.firstSwitched, times.hasFirstSwitched = int64(decodeUint(v)), true
+		case fieldLastSwitched:
+			times.lastSwitched, times.hasLastSwitched = int64(decodeUint(v)), true
+		case fieldSystemInitMillis:
+			times.systemInitMs = int64(decodeUint(v))
+		case fieldFlowStartSeconds:
+			f.TimeFlowStartMs = int64(decodeUint(v)) * 1000
+		case fieldFlowEndSeconds:
+			f.TimeFlowEndMs = int64(decodeUint(v)) * 1000
+		case fieldFlowStartMillis:
+			f.TimeFlowStartMs = int64(decodeUint(v))
+		case fieldFlowEndMillis:
+			f.TimeFlowEndMs = int64(decodeUint(v))
+		}
+	}
+
+	// Egress flows only report the outgoing counters.
+	if f.Bytes == 0 && f.Packets == 0 {
+		f.Bytes, f.Packets = times.outBytes, times.outPkts
+	}
+
+	// The first and last switched fields are relative to the boot of the
+	// exporter, whose time is either computed from the NetFlow v9 header or
+	// reported by the systemInitTimeMilliseconds IPFIX field.
+	boot := times.bootMs
+	if times.systemInitMs != 0 {
+		boot = times.systemInitMs
+	}
+	if boot != 0 {
+		if times.hasFirstSwitched && f.TimeFlowStartMs == 0 {
+			f.TimeFlowStartMs = boot + times.firstSwitched
+		}
+		if times.hasLastSwitched && f.TimeFlowEndMs == 0 {
+			f.TimeFlowEndMs = boot + times.lastSwitched
+		}
+	}
+}
+
+// decodeSamplingRate returns the sampling rate reported by an options record,
+// or 0 if the record doesn't report a sampling rate.
+func decodeSamplingRate(t *template, values [][]byte) uint64 {
+	for i, fld := range t.fields {
+		if fld.ignored() {
+			continue
+		}
+		switch fld.typ {
+		case fieldSamplingInterval, fieldSamplerInterval, fieldSamplingPacketInterval:
+			return decodeUint(values[i])
+		}
+	}
+	return 0
+}
+
+// decodeUint decodes an unsigned integer encoded in up to 8 bytes, as allowed
+// by reduced-size encoding.
+func decodeUint(v []byte) uint64 {
+	if len(v) > 8 {
+		v = v[len(v)-8:]
+	}
+	var n uint64
+	for _, b := range v {
+		n = n<<8 | uint64(b)
+	}
+	return n
+}
+
+func decodeAddr(v []byte) string {
+	addr, ok := netip.AddrFromSlice(v)
+	if !ok {
+		return ""
+	}
+	return addr.String()
+}
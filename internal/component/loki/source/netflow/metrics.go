@@ -0,0 +1,43 @@
+package netflow
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/util"
+)
+
+type metrics struct {
+	packets          *prometheus.CounterVec
+	flows            *prometheus.CounterVec
+	missingTemplates *prometheus.CounterVec
+	decodeErrors     *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		packets: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_netflow_packets_total",
+			Help: "Total number of NetFlow and IPFIX packets decoded, by exporter and type.",
+		}, []string{"exporter", "type"}),
+		flows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_netflow_flows_total",
+			Help: "Total number of flow records decoded, by exporter and type.",
+		}, []string{"exporter", "type"}),
+		missingTemplates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_netflow_missing_templates_total",
+			Help: "Total number of data sets skipped because their template is unknown, by exporter and type.",
+		}, []string{"exporter", "type"}),
+		decodeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_netflow_decode_errors_total",
+			Help: "Total number of packets which couldn't be decoded, by exporter.",
+		}, []string{"exporter"}),
+	}
+
+	if reg != nil {
+		m.packets = util.MustRegisterOrGet(reg, m.packets).(*prometheus.CounterVec)
+		m.flows = util.MustRegisterOrGet(reg, m.flows).(*prometheus.CounterVec)
+		m.missingTemplates = util.MustRegisterOrGet(reg, m.missingTemplates).(*prometheus.CounterVec)
+		m.decodeErrors = util.MustRegisterOrGet(reg, m.decodeErrors).(*prometheus.CounterVec)
+	}
+	return m
+}
@@ -0,0 +1,342 @@
+package netflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/component/loki/source/netflow/internal/flow"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.source.netflow",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// maxPacketSize is the maximum size of a UDP datagram.
+const maxPacketSize = 65535
+
+// Arguments holds values which are used to configure the loki.source.netflow
+// component.
+type Arguments struct {
+	// ListenAddress only supports UDP.
+	ListenAddress     string              `alloy:"listen_address,attr,optional"`
+	TemplateTimeout   time.Duration       `alloy:"template_timeout,attr,optional"`
+	NormalizeSampling bool                `alloy:"normalize_sampling,attr,optional"`
+	Labels            map[string]string   `alloy:"labels,attr,optional"`
+	RelabelRules      alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	ForwardTo         []loki.LogsReceiver `alloy:"forward_to,attr"`
+}
+
+// DefaultArguments holds default settings for loki.source.netflow.
+var DefaultArguments = Arguments{
+	ListenAddress:   "0.0.0.0:2055",
+	TemplateTimeout: 30 * time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if _, _, err := net.SplitHostPort(a.ListenAddress); err != nil {
+		return fmt.Errorf("invalid listen_address %q: %w", a.ListenAddress, err)
+	}
+	if a.TemplateTimeout < 0 {
+		return fmt.Errorf("template_timeout must not be negative")
+	}
+	return nil
+}
+
+// Component implements the loki.source.netflow component.
+type Component struct {
+	opts    component.Options
+	metrics *metrics
+	entries chan loki.Entry
+
+	mut               sync.RWMutex
+	normalizeSampling bool
+	labels            model.LabelSet
+	relabel           []*relabel.Config
+	receivers         []loki.LogsReceiver
+
+	listenerMut     sync.Mutex
+	listener        *listener
+	listenAddress   string
+	decoder         *flow.Decoder
+	templateTimeout time.Duration
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new loki.source.netflow component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts:    o,
+		metrics: newMetrics(o.Registerer),
+		entries: make(chan loki.Entry),
+	}
+
+	// Call to Update() to start the listener and set receivers once at the start.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.listenerMut.Lock()
+		defer c.listenerMut.Unlock()
+
+		if c.listener != nil {
+			c.listener.Stop()
+			c.listener = nil
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.entries:
+			c.mut.RLock()
+			for _, r := range c.receivers {
+				select {
+				case <-ctx.Done():
+					c.mut.RUnlock()
+					return nil
+				case r.Chan() <- entry:
+				}
+			}
+			c.mut.RUnlock()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	var rcs []*relabel.Config
+	if len(newArgs.RelabelRules) > 0 {
+		rcs = alloy_relabel.ComponentToPromRelabelConfigs(newArgs.RelabelRules)
+	}
+
+	lbls := make(model.LabelSet, len(newArgs.Labels))
+	for k, v := range newArgs.Labels {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	c.mut.Lock()
+	c.normalizeSampling = newArgs.NormalizeSampling
+	c.labels = lbls
+	c.relabel = rcs
+	c.receivers = newArgs.ForwardTo
+	c.mut.Unlock()
+
+	c.listenerMut.Lock()
+	defer c.listenerMut.Unlock()
+
+	// The listener and its decoder are kept across updates when possible, so
+	// that the templates sent by exporters, which may only be sent every few
+	// minutes, aren't lost.
+	restart := c.listener == nil || c.listenAddress != newArgs.ListenAddress
+	if c.decoder == nil || c.templateTimeout != newArgs.TemplateTimeout {
+		c.decoder = flow.NewDecoder(newArgs.TemplateTimeout)
+		c.templateTimeout = newArgs.TemplateTimeout
+		restart = true
+	}
+	if !restart {
+		return nil
+	}
+
+	if c.listener != nil {
+		c.listener.Stop()
+		c.listener = nil
+	}
+
+	l, err := newListener(c.opts.Logger, newArgs.ListenAddress, c.decoder, c.handlePacket)
+	if err != nil {
+		return err
+	}
+	level.Info(c.opts.Logger).Log("msg", "listening for NetFlow and IPFIX packets", "address", newArgs.ListenAddress)
+
+	c.listener = l
+	c.listenAddress = newArgs.ListenAddress
+	return nil
+}
+
+// handlePacket converts the flows of a packet received from exporter into log
+// entries, which are sent to the receivers. It returns false if done was
+// closed before all entries could be sent.
+func (c *Component) handlePacket(exporter string, pkt *flow.Packet, err error, done <-chan struct{}) bool {
+	if err != nil {
+		level.Debug(c.opts.Logger).Log("msg", "failed to decode packet", "exporter", exporter, "err", err)
+		c.metrics.decodeErrors.WithLabelValues(exporter).Inc()
+		return true
+	}
+
+	c.metrics.packets.WithLabelValues(exporter, pkt.Type).Inc()
+	c.metrics.flows.WithLabelValues(exporter, pkt.Type).Add(float64(len(pkt.Flows)))
+	if pkt.MissingTemplates > 0 {
+		c.metrics.missingTemplates.WithLabelValues(exporter, pkt.Type).Add(float64(pkt.MissingTemplates))
+	}
+
+	for _, f := range pkt.Flows {
+		entry, ok := c.newEntry(f)
+		if !ok {
+			continue
+		}
+
+		select {
+		case c.entries <- entry:
+		case <-done:
+			return false
+		}
+	}
+	return true
+}
+
+// newEntry converts a flow into a log entry. It returns false if the flow
+// must be dropped.
+func (c *Component) newEntry(f flow.Flow) (loki.Entry, bool) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	if c.normalizeSampling && f.SamplingRate > 1 {
+		f.Bytes *= f.SamplingRate
+		f.Packets *= f.SamplingRate
+	}
+
+	line, err := json.Marshal(f)
+	if err != nil {
+		level.Warn(c.opts.Logger).Log("msg", "failed to encode flow", "exporter", f.Exporter, "err", err)
+		return loki.Entry{}, false
+	}
+
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	for k, v := range c.labels {
+		lb.Set(string(k), string(v))
+	}
+	lb.Set("__netflow_exporter", f.Exporter)
+	lb.Set("__netflow_type", f.Type)
+
+	processed, keep := relabel.Process(lb.Labels(), c.relabel...)
+	if !keep {
+		return loki.Entry{}, false
+	}
+
+	filtered := make(model.LabelSet)
+	processed.Range(func(lbl labels.Label) {
+		if strings.HasPrefix(lbl.Name, "__") {
+			return
+		}
+		filtered[model.LabelName(lbl.Name)] = model.LabelValue(lbl.Value)
+	})
+	if filtered["job"] == "" {
+		filtered["job"] = model.LabelValue(c.opts.ID)
+	}
+
+	return loki.Entry{
+		Labels: filtered,
+		Entry: logproto.Entry{
+			Timestamp: time.Now(),
+			Line:      string(line),
+		},
+	}, true
+}
+
+// packetHandler handles a decoded packet. It returns false if the listener
+// must stop.
+type packetHandler func(exporter string, pkt *flow.Packet, err error, done <-chan struct{}) bool
+
+// listener receives packets on a UDP socket and decodes them.
+type listener struct {
+	logger  log.Logger
+	conn    net.PacketConn
+	decoder *flow.Decoder
+	handler packetHandler
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newListener(logger log.Logger, address string, decoder *flow.Decoder, handler packetHandler) (*listener, error) {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	l := &listener{
+		logger:  logger,
+		conn:    conn,
+		decoder: decoder,
+		handler: handler,
+		done:    make(chan struct{}),
+	}
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.run()
+	}()
+	return l, nil
+}
+
+func (l *listener) run() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			level.Warn(l.logger).Log("msg", "failed to read packet", "err", err)
+			continue
+		}
+
+		exporter := addr.String()
+		if udpAddr, ok := addr.(*net.UDPAddr); ok {
+			exporter = udpAddr.IP.String()
+		}
+
+		pkt, err := l.decoder.Decode(exporter, buf[:n])
+		if !l.handler(exporter, pkt, err, l.done) {
+			return
+		}
+	}
+}
+
+// Stop stops the listener and waits for it to exit.
+func (l *listener) Stop() {
+	close(l.done)
+	l.conn.Close()
+	l.wg.Wait()
+}
@@ -0,0 +1,130 @@
+package netflow
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/loki/source/netflow/internal/flow"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`forward_to = []`), &args))
+	require.Equal(t, DefaultArguments.ListenAddress, args.ListenAddress)
+	require.Equal(t, DefaultArguments.TemplateTimeout, args.TemplateTimeout)
+
+	err := syntax.Unmarshal([]byte(`forward_to = [] listen_address = "2055"`), &args)
+	require.ErrorContains(t, err, `invalid listen_address "2055"`)
+
+	err = syntax.Unmarshal([]byte(`forward_to = [] template_timeout = "-1m"`), &args)
+	require.ErrorContains(t, err, "template_timeout must not be negative")
+}
+
+// netFlowV5Packet returns a NetFlow v5 packet with a single TCP flow from
+// 10.0.0.1:51234 to 10.0.0.2:443.
+func netFlowV5Packet() []byte {
+	b := make([]byte, 24+48)
+	binary.BigEndian.PutUint16(b[0:], 5)
+	binary.BigEndian.PutUint16(b[2:], 1)
+	binary.BigEndian.PutUint32(b[8:], 1700000000)
+
+	r := b[24:]
+	copy(r[0:], []byte{10, 0, 0, 1})
+	copy(r[4:], []byte{10, 0, 0, 2})
+	binary.BigEndian.PutUint32(r[16:], 10)
+	binary.BigEndian.PutUint32(r[20:], 1500)
+	binary.BigEndian.PutUint16(r[32:], 51234)
+	binary.BigEndian.PutUint16(r[34:], 443)
+	r[38] = 6
+	return b
+}
+
+func TestNetFlow(t *testing.T) {
+	opts := component.Options{
+		ID:            "loki.source.netflow.test",
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+	}
+
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	ch := loki.NewLogsReceiver()
+	args := DefaultArguments
+	args.ListenAddress = addr
+	args.Labels = map[string]string{"source": "netflow"}
+	args.ForwardTo = []loki.LogsReceiver{ch}
+
+	c, err := New(opts, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go c.Run(ctx)
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write(netFlowV5Packet())
+	require.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for flow")
+	case e := <-ch.Chan():
+		require.Equal(t, model.LabelSet{
+			"job":    "loki.source.netflow.test",
+			"source": "netflow",
+		}, e.Labels)
+
+		var f flow.Flow
+		require.NoError(t, json.Unmarshal([]byte(e.Line), &f))
+		require.Equal(t, flow.TypeNetFlowV5, f.Type)
+		require.Equal(t, "127.0.0.1", f.Exporter)
+		require.Equal(t, "10.0.0.1", f.SrcAddr)
+		require.Equal(t, uint16(443), f.DstPort)
+		require.Equal(t, uint64(1500), f.Bytes)
+	}
+}
+
+func TestNewEntry_NormalizeSampling(t *testing.T) {
+	c := &Component{
+		opts:    component.Options{ID: "loki.source.netflow.test", Logger: util.TestAlloyLogger(t)},
+		metrics: newMetrics(prometheus.NewRegistry()),
+	}
+	f := flow.Flow{Type: flow.TypeNetFlowV5, Exporter: "127.0.0.1", SamplingRate: 100, Bytes: 1500, Packets: 10}
+
+	decode := func(e loki.Entry) flow.Flow {
+		var f flow.Flow
+		require.NoError(t, json.Unmarshal([]byte(e.Line), &f))
+		return f
+	}
+
+	entry, ok := c.newEntry(f)
+	require.True(t, ok)
+	require.Equal(t, uint64(1500), decode(entry).Bytes)
+
+	c.normalizeSampling = true
+	entry, ok = c.newEntry(f)
+	require.True(t, ok)
+	require.Equal(t, uint64(150000), decode(entry).Bytes)
+	require.Equal(t, uint64(1000), decode(entry).Packets)
+	require.Equal(t, uint64(100), decode(entry).SamplingRate)
+}
@@ -15,6 +15,7 @@ import (
 	commonk8s "github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/component/loki/source/kubernetes"
 	"github.com/grafana/alloy/internal/component/loki/source/kubernetes/kubetail"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -70,6 +71,7 @@ type Component struct {
 	reconciler *reconciler
 	controller *controller
 
+	store     *state.Store
 	positions positions.Positions
 	handler   loki.LogsReceiver
 
@@ -94,11 +96,16 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
+	positionsFile, err := positions.NewWithStore(o.Logger, store, positions.Config{
 		SyncPeriod:    10 * time.Second,
 		PositionsFile: filepath.Join(o.DataPath, "positions.yml"),
 	})
 	if err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 
@@ -121,6 +128,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		reconciler: reconciler,
 		controller: controller,
 
+		store:     store,
 		positions: positionsFile,
 		handler:   loki.NewLogsReceiver(),
 	}
@@ -135,7 +143,12 @@ func (c *Component) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	defer c.positions.Stop()
+	defer func() {
+		c.positions.Stop()
+		if err := c.store.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close state store", "err", err)
+		}
+	}()
 
 	defer func() {
 		c.mut.RLock()
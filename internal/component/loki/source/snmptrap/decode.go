@@ -0,0 +1,159 @@
+package snmptrap
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	oidSysUpTime   = "1.3.6.1.2.1.1.3.0"
+	oidSnmpTrapOID = "1.3.6.1.6.3.1.1.4.1.0"
+
+	// oidGenericTraps is the prefix of the OIDs used by SNMPv2 for the generic
+	// traps of SNMPv1, as defined by RFC 3584.
+	oidGenericTraps = "1.3.6.1.6.3.1.1.5"
+)
+
+// trap is a decoded SNMP trap.
+type trap struct {
+	Agent     string    `json:"agent"`
+	Version   string    `json:"version"`
+	Community string    `json:"community,omitempty"`
+	User      string    `json:"user,omitempty"`
+	Trap      string    `json:"trap"`
+	TrapOID   string    `json:"trap_oid"`
+	Uptime    uint32    `json:"uptime"`
+	Varbinds  []varbind `json:"varbinds"`
+}
+
+// varbind is a decoded variable binding of an SNMP trap.
+type varbind struct {
+	OID   string `json:"oid"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// line returns the log line of the trap.
+func (t trap) line() (string, error) {
+	bb, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return string(bb), nil
+}
+
+// decodeTrap decodes an SNMP trap sent by agent. The sysUpTime and
+// snmpTrapOID variable bindings of SNMPv2 traps are decoded into the fields of
+// the trap and aren't included in its variable bindings. SNMPv1 traps are
+// converted into the equivalent SNMPv2 trap, as defined by RFC 3584.
+func decodeTrap(p *gosnmp.SnmpPacket, agent string, tr *translator) (trap, error) {
+	t := trap{
+		Agent:    agent,
+		Varbinds: make([]varbind, 0, len(p.Variables)),
+	}
+
+	switch p.Version {
+	case gosnmp.Version1:
+		t.Version = "v1"
+		t.Community = p.Community
+		t.Uptime = uint32(p.Timestamp)
+		t.TrapOID = v1TrapOID(p.SnmpTrap)
+		if p.AgentAddress != "" {
+			t.Agent = p.AgentAddress
+		}
+	case gosnmp.Version2c:
+		t.Version = "v2c"
+		t.Community = p.Community
+	case gosnmp.Version3:
+		t.Version = "v3"
+		if sp, ok := p.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok {
+			t.User = sp.UserName
+		}
+	default:
+		return trap{}, fmt.Errorf("unsupported SNMP version %s", p.Version)
+	}
+
+	for _, v := range p.Variables {
+		oid := strings.TrimPrefix(v.Name, ".")
+
+		switch {
+		case oid == oidSysUpTime && p.Version != gosnmp.Version1:
+			ticks, ok := v.Value.(uint32)
+			if !ok {
+				return trap{}, fmt.Errorf("unexpected sysUpTime value of type %T", v.Value)
+			}
+			t.Uptime = ticks
+		case oid == oidSnmpTrapOID && p.Version != gosnmp.Version1:
+			trapOID, ok := v.Value.(string)
+			if !ok {
+				return trap{}, fmt.Errorf("unexpected snmpTrapOID value of type %T", v.Value)
+			}
+			t.TrapOID = strings.TrimPrefix(trapOID, ".")
+		default:
+			t.Varbinds = append(t.Varbinds, varbind{
+				OID:   oid,
+				Name:  tr.translate(oid),
+				Type:  v.Type.String(),
+				Value: decodeValue(v),
+			})
+		}
+	}
+
+	if t.TrapOID == "" {
+		return trap{}, fmt.Errorf("trap has no snmpTrapOID")
+	}
+	t.Trap = tr.translate(t.TrapOID)
+	return t, nil
+}
+
+// v1TrapOID returns the SNMPv2 trap OID of an SNMPv1 trap.
+func v1TrapOID(t gosnmp.SnmpTrap) string {
+	// Generic traps other than enterpriseSpecific(6) map to a well-known OID.
+	if t.GenericTrap >= 0 && t.GenericTrap < 6 {
+		return fmt.Sprintf("%s.%d", oidGenericTraps, t.GenericTrap+1)
+	}
+	return fmt.Sprintf("%s.0.%d", strings.TrimPrefix(t.Enterprise, "."), t.SpecificTrap)
+}
+
+// decodeValue returns the value of a variable binding in a form that can be
+// encoded as JSON.
+func decodeValue(v gosnmp.SnmpPDU) any {
+	switch v.Type {
+	case gosnmp.OctetString, gosnmp.Opaque:
+		bb, ok := v.Value.([]byte)
+		if !ok {
+			return v.Value
+		}
+		if isPrintable(bb) {
+			return string(bb)
+		}
+		return hex.EncodeToString(bb)
+	case gosnmp.ObjectIdentifier:
+		if oid, ok := v.Value.(string); ok {
+			return strings.TrimPrefix(oid, ".")
+		}
+		return v.Value
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return nil
+	default:
+		return v.Value
+	}
+}
+
+func isPrintable(bb []byte) bool {
+	if !utf8.Valid(bb) {
+		return false
+	}
+	for _, r := range string(bb) {
+		if r < ' ' && r != '\t' && r != '\n' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,398 @@
+package snmptrap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.source.snmptrap",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the loki.source.snmptrap
+// component.
+type Arguments struct {
+	// ListenAddress only supports UDP.
+	ListenAddress    string              `alloy:"listen_address,attr,optional"`
+	Communities      []string            `alloy:"communities,attr,optional"`
+	TranslationFiles []string            `alloy:"translation_files,attr,optional"`
+	Labels           map[string]string   `alloy:"labels,attr,optional"`
+	RelabelRules     alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	ForwardTo        []loki.LogsReceiver `alloy:"forward_to,attr"`
+
+	Users []User `alloy:"user,block,optional"`
+}
+
+// User configures an SNMPv3 user which is allowed to send traps.
+type User struct {
+	Username     string            `alloy:"username,attr"`
+	AuthProtocol string            `alloy:"auth_protocol,attr,optional"`
+	AuthPassword alloytypes.Secret `alloy:"auth_password,attr,optional"`
+	PrivProtocol string            `alloy:"priv_protocol,attr,optional"`
+	PrivPassword alloytypes.Secret `alloy:"priv_password,attr,optional"`
+	EngineID     string            `alloy:"engine_id,attr,optional"`
+}
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"":       gosnmp.NoAuth,
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"":        gosnmp.NoPriv,
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+// DefaultArguments holds default settings for loki.source.snmptrap.
+var DefaultArguments = Arguments{
+	ListenAddress: "0.0.0.0:162",
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if _, _, err := net.SplitHostPort(a.ListenAddress); err != nil {
+		return fmt.Errorf("invalid listen_address %q: %w", a.ListenAddress, err)
+	}
+
+	usernames := make(map[string]struct{}, len(a.Users))
+	for _, u := range a.Users {
+		if u.Username == "" {
+			return fmt.Errorf("user: username must not be empty")
+		}
+		if _, ok := usernames[u.Username]; ok {
+			return fmt.Errorf("user: duplicate username %q", u.Username)
+		}
+		usernames[u.Username] = struct{}{}
+
+		if err := u.validate(); err != nil {
+			return fmt.Errorf("user %q: %w", u.Username, err)
+		}
+	}
+	return nil
+}
+
+func (u *User) validate() error {
+	if _, ok := authProtocols[u.AuthProtocol]; !ok {
+		return fmt.Errorf("unsupported auth_protocol %q", u.AuthProtocol)
+	}
+	if _, ok := privProtocols[u.PrivProtocol]; !ok {
+		return fmt.Errorf("unsupported priv_protocol %q", u.PrivProtocol)
+	}
+
+	switch {
+	case u.AuthProtocol == "" && u.AuthPassword != "":
+		return fmt.Errorf("auth_password requires auth_protocol to be set")
+	case u.AuthProtocol != "" && u.AuthPassword == "":
+		return fmt.Errorf("auth_password must be set when auth_protocol is set")
+	case u.PrivProtocol != "" && u.AuthProtocol == "":
+		return fmt.Errorf("priv_protocol requires auth_protocol to be set")
+	case u.PrivProtocol == "" && u.PrivPassword != "":
+		return fmt.Errorf("priv_password requires priv_protocol to be set")
+	case u.PrivProtocol != "" && u.PrivPassword == "":
+		return fmt.Errorf("priv_password must be set when priv_protocol is set")
+	}
+	return nil
+}
+
+// securityParameters returns the USM security parameters of the user.
+func (u *User) securityParameters() *gosnmp.UsmSecurityParameters {
+	return &gosnmp.UsmSecurityParameters{
+		UserName:                 u.Username,
+		AuthenticationProtocol:   authProtocols[u.AuthProtocol],
+		AuthenticationPassphrase: string(u.AuthPassword),
+		PrivacyProtocol:          privProtocols[u.PrivProtocol],
+		PrivacyPassphrase:        string(u.PrivPassword),
+		AuthoritativeEngineID:    u.EngineID,
+		Logger:                   gosnmp.Default.Logger,
+	}
+}
+
+// Component implements the loki.source.snmptrap component.
+type Component struct {
+	opts    component.Options
+	metrics *metrics
+	entries chan loki.Entry
+
+	mut         sync.RWMutex
+	communities []string
+	translator  *translator
+	labels      model.LabelSet
+	relabel     []*relabel.Config
+	receivers   []loki.LogsReceiver
+
+	listenerMut sync.Mutex
+	listener    *gosnmp.TrapListener
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new loki.source.snmptrap component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts:    o,
+		metrics: newMetrics(o.Registerer),
+		entries: make(chan loki.Entry),
+	}
+
+	// Call to Update() to start the listener and set receivers once at the start.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.listenerMut.Lock()
+		defer c.listenerMut.Unlock()
+
+		if c.listener != nil {
+			c.listener.Close()
+			c.listener = nil
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.entries:
+			c.mut.RLock()
+			for _, r := range c.receivers {
+				select {
+				case <-ctx.Done():
+					c.mut.RUnlock()
+					return nil
+				case r.Chan() <- entry:
+				}
+			}
+			c.mut.RUnlock()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	tr, err := newTranslator(newArgs.TranslationFiles)
+	if err != nil {
+		return err
+	}
+
+	params := &gosnmp.GoSNMP{
+		Version: gosnmp.Version2c,
+		Logger:  gosnmp.Default.Logger,
+	}
+	if len(newArgs.Users) > 0 {
+		table := gosnmp.NewSnmpV3SecurityParametersTable(gosnmp.Default.Logger)
+		for _, u := range newArgs.Users {
+			if err := table.Add(u.Username, u.securityParameters()); err != nil {
+				return fmt.Errorf("failed to add user %q: %w", u.Username, err)
+			}
+		}
+		params.Version = gosnmp.Version3
+		params.SecurityModel = gosnmp.UserSecurityModel
+		params.TrapSecurityParametersTable = table
+	}
+
+	var rcs []*relabel.Config
+	if len(newArgs.RelabelRules) > 0 {
+		rcs = alloy_relabel.ComponentToPromRelabelConfigs(newArgs.RelabelRules)
+	}
+
+	lbls := make(model.LabelSet, len(newArgs.Labels))
+	for k, v := range newArgs.Labels {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	c.listenerMut.Lock()
+	defer c.listenerMut.Unlock()
+
+	// The old listener is closed before the configuration is swapped, without
+	// holding mut, so that traps which are being handled can be delivered.
+	if c.listener != nil {
+		c.listener.Close()
+		c.listener = nil
+	}
+
+	c.mut.Lock()
+	c.communities = newArgs.Communities
+	c.translator = tr
+	c.labels = lbls
+	c.relabel = rcs
+	c.receivers = newArgs.ForwardTo
+	c.mut.Unlock()
+
+	listener := gosnmp.NewTrapListener()
+	listener.Params = params
+	listener.OnNewTrap = c.handleTrap
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- listener.Listen(newArgs.ListenAddress)
+	}()
+
+	select {
+	case <-listener.Listening():
+	case err := <-errCh:
+		return fmt.Errorf("failed to listen on %s: %w", newArgs.ListenAddress, err)
+	case <-time.After(5 * time.Second):
+		listener.Close()
+		return fmt.Errorf("timed out waiting to listen on %s", newArgs.ListenAddress)
+	}
+
+	level.Info(c.opts.Logger).Log("msg", "listening for SNMP traps", "address", newArgs.ListenAddress)
+	c.listener = listener
+	return nil
+}
+
+func (c *Component) handleTrap(p *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+	entry, ok := c.newEntry(p, addr)
+	if ok {
+		c.entries <- entry
+	}
+}
+
+// newEntry converts a trap received from addr into a log entry. It returns
+// false if the trap must be dropped.
+func (c *Component) newEntry(p *gosnmp.SnmpPacket, addr *net.UDPAddr) (loki.Entry, bool) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	agent := addr.IP.String()
+
+	if p.Version != gosnmp.Version3 && len(c.communities) > 0 && !slices.Contains(c.communities, p.Community) {
+		level.Debug(c.opts.Logger).Log("msg", "dropping trap with unknown community", "agent", agent)
+		c.metrics.droppedTraps.WithLabelValues(reasonCommunity).Inc()
+		return loki.Entry{}, false
+	}
+
+	t, err := decodeTrap(p, agent, c.translator)
+	if err != nil {
+		level.Warn(c.opts.Logger).Log("msg", "failed to decode trap", "agent", agent, "err", err)
+		c.metrics.droppedTraps.WithLabelValues(reasonDecode).Inc()
+		return loki.Entry{}, false
+	}
+
+	line, err := t.line()
+	if err != nil {
+		level.Warn(c.opts.Logger).Log("msg", "failed to encode trap", "agent", agent, "err", err)
+		c.metrics.droppedTraps.WithLabelValues(reasonDecode).Inc()
+		return loki.Entry{}, false
+	}
+
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	for k, v := range c.labels {
+		lb.Set(string(k), string(v))
+	}
+	lb.Set("__snmptrap_agent", t.Agent)
+	lb.Set("__snmptrap_trap", t.Trap)
+	lb.Set("__snmptrap_trap_oid", t.TrapOID)
+	lb.Set("__snmptrap_version", t.Version)
+	if t.Community != "" {
+		lb.Set("__snmptrap_community", t.Community)
+	}
+	if t.User != "" {
+		lb.Set("__snmptrap_user", t.User)
+	}
+
+	processed, keep := relabel.Process(lb.Labels(), c.relabel...)
+	if !keep {
+		return loki.Entry{}, false
+	}
+
+	filtered := make(model.LabelSet)
+	processed.Range(func(lbl labels.Label) {
+		if strings.HasPrefix(lbl.Name, "__") {
+			return
+		}
+		filtered[model.LabelName(lbl.Name)] = model.LabelValue(lbl.Value)
+	})
+	if filtered["job"] == "" {
+		filtered["job"] = model.LabelValue(c.opts.ID)
+	}
+
+	c.metrics.traps.WithLabelValues(t.Agent, t.Trap).Inc()
+
+	return loki.Entry{
+		Labels: filtered,
+		Entry: logproto.Entry{
+			Timestamp: time.Now(),
+			Line:      line,
+		},
+	}, true
+}
+
+const (
+	reasonCommunity = "community"
+	reasonDecode    = "decode"
+)
+
+type metrics struct {
+	traps        *prometheus.CounterVec
+	droppedTraps *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		traps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_snmptrap_traps_total",
+			Help: "Total number of SNMP traps received, by agent and trap.",
+		}, []string{"agent", "trap"}),
+		droppedTraps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_source_snmptrap_dropped_traps_total",
+			Help: "Total number of SNMP traps dropped, by reason.",
+		}, []string{"reason"}),
+	}
+
+	if reg != nil {
+		m.traps = util.MustRegisterOrGet(reg, m.traps).(*prometheus.CounterVec)
+		m.droppedTraps = util.MustRegisterOrGet(reg, m.droppedTraps).(*prometheus.CounterVec)
+	}
+	return m
+}
@@ -0,0 +1,343 @@
+package snmptrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/phayes/freeport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name: "defaults",
+			cfg:  `forward_to = []`,
+		},
+		{
+			name: "v3 users",
+			cfg: `
+				forward_to = []
+				user {
+					username = "noauth"
+				}
+				user {
+					username      = "authpriv"
+					auth_protocol = "SHA256"
+					auth_password = "authpassword"
+					priv_protocol = "AES"
+					priv_password = "privpassword"
+				}
+			`,
+		},
+		{
+			name:      "invalid listen address",
+			cfg:       `forward_to = [] listen_address = "162"`,
+			expectErr: `invalid listen_address "162"`,
+		},
+		{
+			name: "duplicate user",
+			cfg: `
+				forward_to = []
+				user {
+					username = "a"
+				}
+				user {
+					username = "a"
+				}
+			`,
+			expectErr: `user: duplicate username "a"`,
+		},
+		{
+			name: "unsupported auth protocol",
+			cfg: `
+				forward_to = []
+				user {
+					username      = "a"
+					auth_protocol = "SHA1"
+					auth_password = "authpassword"
+				}
+			`,
+			expectErr: `user "a": unsupported auth_protocol "SHA1"`,
+		},
+		{
+			name: "priv without auth",
+			cfg: `
+				forward_to = []
+				user {
+					username      = "a"
+					priv_protocol = "AES"
+					priv_password = "privpassword"
+				}
+			`,
+			expectErr: `user "a": priv_protocol requires auth_protocol to be set`,
+		},
+		{
+			name: "missing auth password",
+			cfg: `
+				forward_to = []
+				user {
+					username      = "a"
+					auth_protocol = "SHA"
+				}
+			`,
+			expectErr: `user "a": auth_password must be set when auth_protocol is set`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oids.txt")
+	content := `# Generated with snmptranslate -Tz -m ALL
+"myTraps"		"1.3.6.1.4.1.99999.0"
+"myDiskFull"		"1.3.6.1.4.1.99999.0.1"
+"ifDescr"		".1.3.6.1.2.1.2.2.1.2"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	tr, err := newTranslator([]string{path})
+	require.NoError(t, err)
+
+	require.Equal(t, "myDiskFull", tr.translate("1.3.6.1.4.1.99999.0.1"))
+	require.Equal(t, "myTraps.2", tr.translate("1.3.6.1.4.1.99999.0.2"))
+	require.Equal(t, "ifDescr.3", tr.translate(".1.3.6.1.2.1.2.2.1.2.3"))
+	require.Equal(t, "linkDown", tr.translate("1.3.6.1.6.3.1.1.5.3"))
+	require.Equal(t, "1.3.6.1.4.1.12345", tr.translate("1.3.6.1.4.1.12345"))
+
+	invalid := filepath.Join(t.TempDir(), "invalid.txt")
+	require.NoError(t, os.WriteFile(invalid, []byte(`"name" "1.3.x"`), 0o644))
+	_, err = newTranslator([]string{invalid})
+	require.ErrorContains(t, err, "line 1: invalid name or OID")
+}
+
+func TestDecodeTrap(t *testing.T) {
+	tr, err := newTranslator(nil)
+	require.NoError(t, err)
+
+	t.Run("v2c", func(t *testing.T) {
+		p := &gosnmp.SnmpPacket{
+			Version:   gosnmp.Version2c,
+			Community: "public",
+			Variables: []gosnmp.SnmpPDU{
+				{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1234)},
+				{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.3"},
+				{Name: ".1.3.6.1.2.1.2.2.1.1.2", Type: gosnmp.Integer, Value: 2},
+				{Name: ".1.3.6.1.2.1.2.2.1.2.2", Type: gosnmp.OctetString, Value: []byte("eth0")},
+				{Name: ".1.3.6.1.4.1.12345.1", Type: gosnmp.OctetString, Value: []byte{0x00, 0x1b, 0x21}},
+			},
+		}
+
+		trap, err := decodeTrap(p, "10.0.0.1", tr)
+		require.NoError(t, err)
+
+		line, err := trap.line()
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"agent": "10.0.0.1",
+			"version": "v2c",
+			"community": "public",
+			"trap": "linkDown",
+			"trap_oid": "1.3.6.1.6.3.1.1.5.3",
+			"uptime": 1234,
+			"varbinds": [
+				{"oid": "1.3.6.1.2.1.2.2.1.1.2", "name": "ifIndex.2", "type": "Integer", "value": 2},
+				{"oid": "1.3.6.1.2.1.2.2.1.2.2", "name": "ifDescr.2", "type": "OctetString", "value": "eth0"},
+				{"oid": "1.3.6.1.4.1.12345.1", "name": "1.3.6.1.4.1.12345.1", "type": "OctetString", "value": "001b21"}
+			]
+		}`, line)
+	})
+
+	t.Run("v1 generic", func(t *testing.T) {
+		p := &gosnmp.SnmpPacket{
+			Version:   gosnmp.Version1,
+			Community: "public",
+			SnmpTrap: gosnmp.SnmpTrap{
+				Enterprise:   ".1.3.6.1.4.1.12345",
+				AgentAddress: "192.168.0.1",
+				GenericTrap:  0,
+				Timestamp:    42,
+			},
+		}
+
+		trap, err := decodeTrap(p, "10.0.0.1", tr)
+		require.NoError(t, err)
+		require.Equal(t, "192.168.0.1", trap.Agent)
+		require.Equal(t, "coldStart", trap.Trap)
+		require.Equal(t, "1.3.6.1.6.3.1.1.5.1", trap.TrapOID)
+		require.Equal(t, uint32(42), trap.Uptime)
+	})
+
+	t.Run("v1 enterprise specific", func(t *testing.T) {
+		p := &gosnmp.SnmpPacket{
+			Version: gosnmp.Version1,
+			SnmpTrap: gosnmp.SnmpTrap{
+				Enterprise:   ".1.3.6.1.4.1.12345",
+				GenericTrap:  6,
+				SpecificTrap: 7,
+			},
+		}
+
+		trap, err := decodeTrap(p, "10.0.0.1", tr)
+		require.NoError(t, err)
+		require.Equal(t, "10.0.0.1", trap.Agent)
+		require.Equal(t, "1.3.6.1.4.1.12345.0.7", trap.TrapOID)
+	})
+
+	t.Run("v3", func(t *testing.T) {
+		p := &gosnmp.SnmpPacket{
+			Version:            gosnmp.Version3,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{UserName: "alice"},
+			Variables: []gosnmp.SnmpPDU{
+				{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.4"},
+			},
+		}
+
+		trap, err := decodeTrap(p, "10.0.0.1", tr)
+		require.NoError(t, err)
+		require.Equal(t, "v3", trap.Version)
+		require.Equal(t, "alice", trap.User)
+		require.Equal(t, "linkUp", trap.Trap)
+	})
+
+	t.Run("missing trap OID", func(t *testing.T) {
+		p := &gosnmp.SnmpPacket{Version: gosnmp.Version2c}
+
+		_, err := decodeTrap(p, "10.0.0.1", tr)
+		require.EqualError(t, err, "trap has no snmpTrapOID")
+	})
+}
+
+func TestNewEntry(t *testing.T) {
+	tr, err := newTranslator(nil)
+	require.NoError(t, err)
+
+	c := &Component{
+		opts:        component.Options{ID: "loki.source.snmptrap.test", Logger: util.TestAlloyLogger(t)},
+		metrics:     newMetrics(prometheus.NewRegistry()),
+		communities: []string{"private"},
+		translator:  tr,
+		labels:      model.LabelSet{"env": "test"},
+		relabel: alloy_relabel.ComponentToPromRelabelConfigs(alloy_relabel.Rules{
+			{
+				SourceLabels: []string{"__snmptrap_agent"},
+				TargetLabel:  "agent",
+				Action:       alloy_relabel.Replace,
+				Regex:        alloy_relabel.DefaultRelabelConfig.Regex,
+				Replacement:  "$1",
+				Separator:    ";",
+			},
+		}),
+	}
+
+	newPacket := func(community string) *gosnmp.SnmpPacket {
+		return &gosnmp.SnmpPacket{
+			Version:   gosnmp.Version2c,
+			Community: community,
+			Variables: []gosnmp.SnmpPDU{
+				{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.1"},
+			},
+		}
+	}
+	addr := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+
+	entry, ok := c.newEntry(newPacket("private"), addr)
+	require.True(t, ok)
+	require.Equal(t, model.LabelSet{
+		"agent": "10.0.0.1",
+		"env":   "test",
+		"job":   "loki.source.snmptrap.test",
+	}, entry.Labels)
+
+	_, ok = c.newEntry(newPacket("public"), addr)
+	require.False(t, ok)
+}
+
+func TestSNMPTrap(t *testing.T) {
+	opts := component.Options{
+		ID:            "loki.source.snmptrap.test",
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+	}
+
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+
+	ch := loki.NewLogsReceiver()
+	args := Arguments{
+		ListenAddress: fmt.Sprintf("127.0.0.1:%d", port),
+		Communities:   []string{"public"},
+		ForwardTo:     []loki.LogsReceiver{ch},
+	}
+
+	c, err := New(opts, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go c.Run(ctx)
+
+	client := &gosnmp.GoSNMP{
+		Target:    "127.0.0.1",
+		Port:      uint16(port),
+		Transport: "udp",
+		Community: "public",
+		Version:   gosnmp.Version2c,
+		Timeout:   time.Second,
+	}
+	require.NoError(t, client.Connect())
+	defer client.Conn.Close()
+
+	_, err = client.SendTrap(gosnmp.SnmpTrap{
+		Variables: []gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(100)},
+			{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.3"},
+			{Name: ".1.3.6.1.2.1.2.2.1.1.2", Type: gosnmp.Integer, Value: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for trap")
+	case e := <-ch.Chan():
+		require.Equal(t, model.LabelValue("loki.source.snmptrap.test"), e.Labels["job"])
+
+		var trap map[string]any
+		require.NoError(t, json.Unmarshal([]byte(e.Line), &trap))
+		require.Equal(t, "linkDown", trap["trap"])
+		require.Equal(t, "public", trap["community"])
+		require.EqualValues(t, 100, trap["uptime"])
+	}
+}
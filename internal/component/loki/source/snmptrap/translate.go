@@ -0,0 +1,123 @@
+package snmptrap
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// builtinNames holds the names of the OIDs used by every trap, and by the
+// generic traps of RFC 1907 and RFC 2863.
+var builtinNames = map[string]string{
+	"1.3.6.1.2.1.1.3":     "sysUpTime",
+	"1.3.6.1.6.3.1.1.4.1": "snmpTrapOID",
+	"1.3.6.1.6.3.1.1.4.3": "snmpTrapEnterprise",
+	"1.3.6.1.6.3.18.1.3":  "snmpTrapAddress",
+	"1.3.6.1.6.3.18.1.4":  "snmpTrapCommunity",
+
+	"1.3.6.1.6.3.1.1.5.1": "coldStart",
+	"1.3.6.1.6.3.1.1.5.2": "warmStart",
+	"1.3.6.1.6.3.1.1.5.3": "linkDown",
+	"1.3.6.1.6.3.1.1.5.4": "linkUp",
+	"1.3.6.1.6.3.1.1.5.5": "authenticationFailure",
+
+	"1.3.6.1.2.1.2.2.1.1":    "ifIndex",
+	"1.3.6.1.2.1.2.2.1.2":    "ifDescr",
+	"1.3.6.1.2.1.2.2.1.7":    "ifAdminStatus",
+	"1.3.6.1.2.1.2.2.1.8":    "ifOperStatus",
+	"1.3.6.1.2.1.31.1.1.1.1": "ifName",
+}
+
+// translator translates numeric OIDs into names.
+type translator struct {
+	names map[string]string
+}
+
+// newTranslator creates a translator using the built-in names and the names
+// read from files. Names from files take precedence over the built-in ones.
+//
+// Files use the format of the output of `snmptranslate -Tz`, where each line
+// holds a quoted name and a quoted numeric OID:
+//
+//	"ifDescr"		"1.3.6.1.2.1.2.2.1.2"
+func newTranslator(files []string) (*translator, error) {
+	t := &translator{names: make(map[string]string, len(builtinNames))}
+	for oid, name := range builtinNames {
+		t.names[oid] = name
+	}
+
+	for _, path := range files {
+		if err := t.loadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to load translation file %s: %w", path, err)
+		}
+	}
+	return t, nil
+}
+
+func (t *translator) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: expected a name and an OID, got %q", lineNum, line)
+		}
+		name := strings.Trim(fields[0], `"`)
+		oid := strings.TrimPrefix(strings.Trim(fields[1], `"`), ".")
+		if name == "" || !isNumericOID(oid) {
+			return fmt.Errorf("line %d: invalid name or OID in %q", lineNum, line)
+		}
+		t.names[oid] = name
+	}
+	return scanner.Err()
+}
+
+// translate returns the name of oid. If only a prefix of oid is known, the
+// name of the prefix is returned with the remaining suffix, for example
+// "ifDescr.3". Unknown OIDs are returned unchanged, without leading dot.
+func (t *translator) translate(oid string) string {
+	oid = strings.TrimPrefix(oid, ".")
+
+	prefix := oid
+	for {
+		if name, ok := t.names[prefix]; ok {
+			return name + oid[len(prefix):]
+		}
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			return oid
+		}
+		prefix = prefix[:i]
+	}
+}
+
+func isNumericOID(oid string) bool {
+	if oid == "" {
+		return false
+	}
+	for _, part := range strings.Split(oid, ".") {
+		if part == "" {
+			return false
+		}
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -8,76 +8,107 @@ package windowsevent
 import (
 	"bytes"
 	"errors"
-	"io"
 	"io/fs"
 	"os"
 
 	"github.com/natefinch/atomic"
 
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/windows/win_eventlog"
 )
 
-type bookMark struct {
-	handle win_eventlog.EvtHandle
-	isNew  bool
-	path   string
-	buf    []byte
+const (
+	bookmarkNamespace = "windowsevent"
+	bookmarkKey       = "bookmark"
+)
+
+// bookmarkStorage persists the rendered XML of a bookmark.
+type bookmarkStorage interface {
+	load() (string, error)
+	save(bookmark string) error
 }
 
-// newBookMark creates a new windows event bookmark.
-// The bookmark will be saved at the given path. Use save to save the current position for a given event.
-func newBookMark(path string) (*bookMark, error) {
-	// 16kb buffer for rendering bookmark
-	buf := make([]byte, 16<<10)
+// fileBookmarkStorage stores the bookmark in the file set by bookmark_path.
+type fileBookmarkStorage struct {
+	path string
+}
 
-	_, err := os.Stat(path)
+func (s fileBookmarkStorage) load() (string, error) {
+	bb, err := os.ReadFile(s.path)
 	// creates a new bookmark file if none exists.
 	if errors.Is(err, fs.ErrNotExist) {
-		_, err := os.Create(path)
+		f, err := os.Create(s.path)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		bm, err := win_eventlog.CreateBookmark("")
-		if err != nil {
-			return nil, err
-		}
-		return &bookMark{
-			handle: bm,
-			path:   path,
-			isNew:  true,
-			buf:    buf,
-		}, nil
-	}
-	if err != nil {
-		return nil, err
+		return "", f.Close()
 	}
-	// otherwise open the current one.
-	file, err := os.OpenFile(path, os.O_RDWR, 0666)
-	if err != nil {
-		return nil, err
+	return string(bb), err
+}
+
+func (s fileBookmarkStorage) save(bookmark string) error {
+	return atomic.WriteFile(s.path, bytes.NewReader([]byte(bookmark)))
+}
+
+// stateBookmarkStorage stores the bookmark in the state store of the
+// component. The bookmark is also written to the file at path, so that a
+// previous version which only reads the file resumes from it after a rollback.
+//
+// TODO: stop writing the bookmark file once rolling back to a version without
+// the state store isn't supported anymore.
+type stateBookmarkStorage struct {
+	store *state.Store
+	path  string
+}
+
+func (s stateBookmarkStorage) load() (string, error) {
+	bb, _ := s.store.Get(bookmarkNamespace, bookmarkKey)
+	return string(bb), nil
+}
+
+func (s stateBookmarkStorage) save(bookmark string) error {
+	// The file is written first: if writing to the store fails, the file holds
+	// the newer bookmark, which is imported again on the next start.
+	if err := atomic.WriteFile(s.path, bytes.NewReader([]byte(bookmark))); err != nil {
+		return err
 	}
-	defer file.Close()
-	fileContent, err := io.ReadAll(file)
+	s.store.Put(bookmarkNamespace, bookmarkKey, []byte(bookmark))
+	return s.store.Sync()
+}
+
+type bookMark struct {
+	handle  win_eventlog.EvtHandle
+	isNew   bool
+	storage bookmarkStorage
+	buf     []byte
+}
+
+// newBookMark creates a new windows event bookmark.
+// The bookmark will be saved in the given storage. Use save to save the current position for a given event.
+func newBookMark(storage bookmarkStorage) (*bookMark, error) {
+	// 16kb buffer for rendering bookmark
+	buf := make([]byte, 16<<10)
+
+	bookmarkString, err := storage.load()
 	if err != nil {
 		return nil, err
 	}
-	fileString := string(fileContent)
 	// load the current bookmark.
-	bm, err := win_eventlog.CreateBookmark(fileString)
+	bm, err := win_eventlog.CreateBookmark(bookmarkString)
 	if err != nil {
 		// If we errored likely due to incorrect data then create a blank one
 		bm, err = win_eventlog.CreateBookmark("")
-		fileString = ""
+		bookmarkString = ""
 		// This should never fail but just in case.
 		if err != nil {
 			return nil, err
 		}
 	}
 	return &bookMark{
-		handle: bm,
-		path:   path,
-		isNew:  fileString == "",
-		buf:    buf,
+		handle:  bm,
+		storage: storage,
+		isNew:   bookmarkString == "",
+		buf:     buf,
 	}, nil
 }
 
@@ -87,5 +118,5 @@ func (b *bookMark) save(event win_eventlog.EvtHandle) error {
 	if err != nil {
 		return err
 	}
-	return atomic.WriteFile(b.path, bytes.NewReader([]byte(newBookmark)))
+	return b.storage.save(newBookmark)
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -109,7 +110,44 @@ func TestLegacyBookmarkConversion(t *testing.T) {
 		LegacyBookmarkPath:   legacyPath,
 	})
 	require.NoError(t, err)
-	dd, _ := os.ReadFile(c.args.BookmarkPath)
+	dd, ok := c.store.Get(bookmarkNamespace, bookmarkKey)
 	// The New function will convert via calling update.
-	require.True(t, string(dd) == bookmarkText)
+	require.True(t, ok)
+	require.Equal(t, bookmarkText, string(dd))
+}
+
+func TestImportBookmark(t *testing.T) {
+	dir := t.TempDir()
+	store, err := state.Open(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	dataPathBookmark := filepath.Join(dir, "bookmark.xml")
+	storage := stateBookmarkStorage{store: store, path: dataPathBookmark}
+
+	// The bookmark file of a previous version is imported, and kept.
+	require.NoError(t, os.WriteFile(dataPathBookmark, []byte("first"), 0644))
+	require.NoError(t, importBookmark(store, dataPathBookmark, ""))
+	bookmark, err := storage.load()
+	require.NoError(t, err)
+	require.Equal(t, "first", bookmark)
+
+	// Saved bookmarks are also written to the file, which isn't imported
+	// again.
+	require.NoError(t, storage.save("second"))
+	bb, err := os.ReadFile(dataPathBookmark)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(bb))
+	require.NoError(t, importBookmark(store, dataPathBookmark, ""))
+	bookmark, err = storage.load()
+	require.NoError(t, err)
+	require.Equal(t, "second", bookmark)
+
+	// The bookmark file written by a previous version after a rollback is
+	// imported again.
+	require.NoError(t, os.WriteFile(dataPathBookmark, []byte("third"), 0644))
+	require.NoError(t, importBookmark(store, dataPathBookmark, ""))
+	bookmark, err = storage.load()
+	require.NoError(t, err)
+	require.Equal(t, "third", bookmark)
 }
@@ -1,9 +1,13 @@
 package windowsevent
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"sync"
 
 	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
@@ -12,7 +16,9 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/utils"
+	"github.com/grafana/alloy/internal/component/common/state"
 	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
 )
 
 func init() {
@@ -40,6 +46,7 @@ type Component struct {
 	target    *Target
 	handle    *handler
 	receivers []loki.LogsReceiver
+	store     *state.Store
 }
 
 type handler struct {
@@ -56,16 +63,22 @@ func (h *handler) Stop() {
 
 // New creates a new loki.source.windowsevent component.
 func New(o component.Options, args Arguments) (*Component, error) {
+	store, err := state.Open(filepath.Join(o.DataPath, "state"))
+	if err != nil {
+		return nil, err
+	}
 
 	c := &Component{
 		opts:      o,
 		receivers: args.ForwardTo,
 		handle:    &handler{handler: make(chan api.Entry)},
 		args:      args,
+		store:     store,
 	}
 
 	// Call to Update() to start readers and set receivers once at the start.
 	if err := c.Update(args); err != nil {
+		_ = store.Close()
 		return nil, err
 	}
 	return c, nil
@@ -79,6 +92,9 @@ func (c *Component) Run(ctx context.Context) error {
 		if c.target != nil {
 			_ = c.target.Stop()
 		}
+		if err := c.store.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close state store", "err", err)
+		}
 	}()
 	for {
 		select {
@@ -106,17 +122,24 @@ func (c *Component) Update(args component.Arguments) error {
 	c.mut.Lock()
 	defer c.mut.Unlock()
 
-	// If no bookmark specified create one in the datapath.
+	// If no bookmark path is specified, store the bookmark in the state store.
+	var bookmarks bookmarkStorage
 	if newArgs.BookmarkPath == "" {
-		newArgs.BookmarkPath = path.Join(c.opts.DataPath, "bookmark.xml")
-	}
-
-	err := createBookmark(newArgs)
-	if err != nil {
-		return err
+		dataPathBookmark := filepath.Join(c.opts.DataPath, "bookmark.xml")
+		err := importBookmark(c.store, dataPathBookmark, newArgs.LegacyBookmarkPath)
+		if err != nil {
+			return err
+		}
+		bookmarks = stateBookmarkStorage{store: c.store, path: dataPathBookmark}
+	} else {
+		err := createBookmark(newArgs)
+		if err != nil {
+			return err
+		}
+		bookmarks = fileBookmarkStorage{path: newArgs.BookmarkPath}
 	}
 
-	winTarget, err := NewTarget(c.opts.Logger, c.handle, nil, convertConfig(newArgs))
+	winTarget, err := NewTarget(c.opts.Logger, c.handle, nil, convertConfig(newArgs), bookmarks)
 	if err != nil {
 		return err
 	}
@@ -163,6 +186,38 @@ func createBookmark(args Arguments) error {
 	return nil
 }
 
+// importBookmark imports the bookmark of dataPathBookmark, the bookmark file
+// which is used by previous versions when bookmark_path isn't specified, into
+// store when it differs from the stored bookmark, that is when it was written
+// by a previous version before an upgrade or after a rollback. Otherwise, the
+// bookmark of legacyBookmark is imported if store doesn't hold a bookmark yet.
+func importBookmark(store *state.Store, dataPathBookmark string, legacyBookmark string) error {
+	stored, ok := store.Get(bookmarkNamespace, bookmarkKey)
+
+	bb, err := os.ReadFile(dataPathBookmark)
+	if err == nil && len(bb) > 0 {
+		if bytes.Equal(bb, stored) {
+			return nil
+		}
+		store.Put(bookmarkNamespace, bookmarkKey, bb)
+		return store.Sync()
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if ok || legacyBookmark == "" {
+		return nil
+	}
+	bb, err = os.ReadFile(legacyBookmark)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	store.Put(bookmarkNamespace, bookmarkKey, bb)
+	return store.Sync()
+}
+
 func convertConfig(arg Arguments) *scrapeconfig.WindowsEventsTargetConfig {
 	return &scrapeconfig.WindowsEventsTargetConfig{
 		Locale:               uint32(arg.Locale),
@@ -49,6 +49,7 @@ func NewTarget(
 	handler api.EntryHandler,
 	relabel []*relabel.Config,
 	cfg *scrapeconfig.WindowsEventsTargetConfig,
+	bookmarks bookmarkStorage,
 ) (*Target, error) {
 	sigEvent, err := windows.CreateEvent(nil, 0, 0, nil)
 	if err != nil {
@@ -56,9 +57,9 @@ func NewTarget(
 	}
 	defer windows.CloseHandle(sigEvent)
 
-	bm, err := newBookMark(cfg.BookmarkPath)
+	bm, err := newBookMark(bookmarks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create bookmark using path=%s: %w", cfg.BookmarkPath, err)
+		return nil, fmt.Errorf("failed to create bookmark: %w", err)
 	}
 
 	t := &Target{
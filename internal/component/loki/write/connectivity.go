@@ -0,0 +1,27 @@
+package write
+
+import (
+	"context"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki/client"
+)
+
+var _ component.ConnectivityChecker = Arguments{}
+
+// CheckConnectivity implements component.ConnectivityChecker by sending an
+// empty push request to every endpoint.
+func (args Arguments) CheckConnectivity(ctx context.Context) []component.ConnectivityResult {
+	var res []component.ConnectivityResult
+	for _, cfg := range args.convertClientConfigs() {
+		endpoint := ""
+		if cfg.URL.URL != nil {
+			endpoint = cfg.URL.Redacted()
+		}
+		res = append(res, component.ConnectivityResult{
+			Endpoint: endpoint,
+			Err:      client.CheckConnectivity(ctx, cfg),
+		})
+	}
+	return res
+}
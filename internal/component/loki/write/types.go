@@ -29,8 +29,12 @@ type EndpointOptions struct {
 	MaxBackoffRetries int                     `alloy:"max_backoff_retries,attr,optional"` // give up after this many; zero means infinite retries
 	TenantID          string                  `alloy:"tenant_id,attr,optional"`
 	RetryOnHTTP429    bool                    `alloy:"retry_on_http_429,attr,optional"`
+	Compression       client.Compression      `alloy:"compression,attr,optional"`
 	HTTPClientConfig  *types.HTTPClientConfig `alloy:",squash"`
 	QueueConfig       QueueConfig             `alloy:"queue_config,block,optional"`
+	SigV4             *types.SigV4Config      `alloy:"sigv4,block,optional"`
+	AzureAD           *types.AzureADConfig    `alloy:"azuread,block,optional"`
+	GoogleIAM         *types.GoogleIAMConfig  `alloy:"google_iam,block,optional"`
 }
 
 // GetDefaultEndpointOptions defines the default settings for sending logs to a
@@ -66,12 +70,32 @@ func (r *EndpointOptions) Validate() error {
 
 	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
 	if r.HTTPClientConfig != nil {
-		return r.HTTPClientConfig.Validate()
+		if err := r.HTTPClientConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	auths := 0
+	for _, set := range []bool{r.SigV4 != nil, r.AzureAD != nil, r.GoogleIAM != nil, isAuthSetInHTTPClientConfig(r.HTTPClientConfig)} {
+		if set {
+			auths++
+		}
+	}
+	if auths > 1 {
+		return fmt.Errorf("at most one of sigv4, azuread, google_iam, basic_auth, authorization, oauth2, bearer_token & bearer_token_file must be configured")
 	}
 
 	return nil
 }
 
+func isAuthSetInHTTPClientConfig(cfg *types.HTTPClientConfig) bool {
+	return cfg != nil && (cfg.BasicAuth != nil ||
+		cfg.OAuth2 != nil ||
+		cfg.Authorization != nil ||
+		len(cfg.BearerToken) > 0 ||
+		len(cfg.BearerTokenFile) > 0)
+}
+
 // QueueConfig controls how the queue logs remote write client is configured. Note that this client is only used when the
 // loki.write component has WAL support enabled.
 type QueueConfig struct {
@@ -91,6 +115,12 @@ func (args Arguments) convertClientConfigs() []client.Config {
 	var res []client.Config
 	for _, cfg := range args.Endpoints {
 		url, _ := url.Parse(cfg.URL)
+		// Endpoints without their own compression use the one of the
+		// component.
+		compression := cfg.Compression
+		if compression == "" {
+			compression = args.Compression
+		}
 		cc := client.Config{
 			Name:      cfg.Name,
 			URL:       flagext.URLValue{URL: url},
@@ -107,6 +137,10 @@ func (args Arguments) convertClientConfigs() []client.Config {
 			Timeout:                cfg.RemoteTimeout,
 			TenantID:               cfg.TenantID,
 			DropRateLimitedBatches: !cfg.RetryOnHTTP429,
+			Compression:            compression,
+			SigV4:                  cfg.SigV4.Convert(),
+			AzureAD:                cfg.AzureAD.Convert(),
+			GoogleIAM:              cfg.GoogleIAM,
 			Queue: client.QueueConfig{
 				Capacity:     int(cfg.QueueConfig.Capacity),
 				DrainTimeout: cfg.QueueConfig.DrainTimeout,
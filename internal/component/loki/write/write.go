@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/wal"
 	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/service/clocksync"
 )
 
 func init() {
@@ -31,10 +32,11 @@ func init() {
 
 // Arguments holds values which are used to configure the loki.write component.
 type Arguments struct {
-	Endpoints      []EndpointOptions `alloy:"endpoint,block,optional"`
-	ExternalLabels map[string]string `alloy:"external_labels,attr,optional"`
-	MaxStreams     int               `alloy:"max_streams,attr,optional"`
-	WAL            WalArguments      `alloy:"wal,block,optional"`
+	Endpoints      []EndpointOptions  `alloy:"endpoint,block,optional"`
+	ExternalLabels map[string]string  `alloy:"external_labels,attr,optional"`
+	MaxStreams     int                `alloy:"max_streams,attr,optional"`
+	Compression    client.Compression `alloy:"compression,attr,optional"`
+	WAL            WalArguments       `alloy:"wal,block,optional"`
 }
 
 // WalArguments holds the settings for configuring the Write-Ahead Log (WAL) used
@@ -73,13 +75,15 @@ type Exports struct {
 }
 
 var (
-	_ component.Component = (*Component)(nil)
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
 )
 
 // Component implements the loki.write component.
 type Component struct {
 	opts    component.Options
 	metrics *client.Metrics
+	clock   clocksync.Monitor
 
 	mut      sync.RWMutex
 	args     Arguments
@@ -99,6 +103,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
 		metrics: client.NewMetrics(o.Registerer),
+		clock:   clocksync.GetMonitor(o),
 	}
 
 	// Create and immediately export the receiver which remains the same for
@@ -214,3 +219,11 @@ func (c *Component) Update(args component.Arguments) error {
 
 	return err
 }
+
+// CurrentHealth implements component.HealthComponent. The component is
+// unhealthy when the skew of the local clock exceeds the maximum skew of the
+// clocksync block, since Loki rejects or reorders the log lines timestamped
+// with a skewed clock.
+func (c *Component) CurrentHealth() component.Health {
+	return c.clock.Health()
+}
@@ -17,6 +17,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/client"
 	"github.com/grafana/alloy/internal/component/common/loki/wal"
 	"github.com/grafana/alloy/internal/component/discovery"
 	lsf "github.com/grafana/alloy/internal/component/loki/source/file"
@@ -56,6 +57,70 @@ func TestBadAlloyConfig(t *testing.T) {
 	require.ErrorContains(t, err, "at most one of basic_auth, authorization, oauth2, bearer_token & bearer_token_file must be configured")
 }
 
+func TestCompressionOverrides(t *testing.T) {
+	var exampleAlloyConfig = `
+	compression = "zstd"
+
+	endpoint {
+		url = "http://0.0.0.0:11111/loki/api/v1/push"
+	}
+
+	endpoint {
+		url         = "http://0.0.0.0:22222/loki/api/v1/push"
+		compression = "gzip"
+	}
+`
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(exampleAlloyConfig), &args))
+
+	cfgs := args.convertClientConfigs()
+	require.Len(t, cfgs, 2)
+	require.Equal(t, client.CompressionZstd, cfgs[0].Compression)
+	require.Equal(t, client.CompressionGzip, cfgs[1].Compression)
+
+	err := syntax.Unmarshal([]byte(`compression = "lz4"`), &args)
+	require.ErrorContains(t, err, "unsupported compression")
+}
+
+func TestCloudAuth(t *testing.T) {
+	var exampleAlloyConfig = `
+	endpoint {
+		url = "http://0.0.0.0:11111/loki/api/v1/push"
+		sigv4 {
+			region = "us-east-1"
+		}
+	}
+
+	endpoint {
+		url = "http://0.0.0.0:22222/loki/api/v1/push"
+		azuread {
+			managed_identity {
+				client_id = "00000000-0000-0000-0000-000000000000"
+			}
+		}
+	}
+`
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(exampleAlloyConfig), &args))
+
+	cfgs := args.convertClientConfigs()
+	require.Len(t, cfgs, 2)
+	require.Equal(t, "us-east-1", cfgs[0].SigV4.Region)
+	require.Equal(t, "AzurePublic", cfgs[1].AzureAD.Cloud)
+	require.Equal(t, "00000000-0000-0000-0000-000000000000", cfgs[1].AzureAD.ManagedIdentity.ClientID)
+
+	err := syntax.Unmarshal([]byte(`
+	endpoint {
+		url = "http://0.0.0.0:11111/loki/api/v1/push"
+		bearer_token = "token"
+		google_iam {}
+	}
+`), &args)
+	require.ErrorContains(t, err, "at most one of sigv4, azuread, google_iam")
+}
+
 func TestUnmarshallWalAttrributes(t *testing.T) {
 	type testcase struct {
 		raw           string
@@ -0,0 +1,37 @@
+package otelcol
+
+import "fmt"
+
+// ScraperMetricsArguments enables or disables the metrics of a scraper.
+// Metrics which aren't listed keep their default state.
+type ScraperMetricsArguments struct {
+	EnabledMetrics  []string `alloy:"enabled_metrics,attr,optional"`
+	DisabledMetrics []string `alloy:"disabled_metrics,attr,optional"`
+}
+
+// Validate returns an error if a metric is both enabled and disabled.
+func (args *ScraperMetricsArguments) Validate() error {
+	enabled := make(map[string]struct{}, len(args.EnabledMetrics))
+	for _, name := range args.EnabledMetrics {
+		enabled[name] = struct{}{}
+	}
+	for _, name := range args.DisabledMetrics {
+		if _, ok := enabled[name]; ok {
+			return fmt.Errorf("metric %q can't be both enabled and disabled", name)
+		}
+	}
+	return nil
+}
+
+// Convert converts args to the map of the upstream metrics builder config,
+// for use with mapstructure or confmap.
+func (args *ScraperMetricsArguments) Convert() map[string]any {
+	metrics := make(map[string]any, len(args.EnabledMetrics)+len(args.DisabledMetrics))
+	for _, name := range args.EnabledMetrics {
+		metrics[name] = map[string]any{"enabled": true}
+	}
+	for _, name := range args.DisabledMetrics {
+		metrics[name] = map[string]any{"enabled": false}
+	}
+	return metrics
+}
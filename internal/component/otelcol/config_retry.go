@@ -64,3 +64,56 @@ func (args *RetryArguments) Convert() *configretry.BackOffConfig {
 		MaxElapsedTime:      args.MaxElapsedTime,
 	}
 }
+
+// ConsumerRetryArguments holds shared settings for stanza-based receivers
+// which retry sending data to the next consumers.
+type ConsumerRetryArguments struct {
+	Enabled         bool          `alloy:"enabled,attr,optional"`
+	InitialInterval time.Duration `alloy:"initial_interval,attr,optional"`
+	MaxInterval     time.Duration `alloy:"max_interval,attr,optional"`
+	MaxElapsedTime  time.Duration `alloy:"max_elapsed_time,attr,optional"`
+}
+
+var (
+	_ syntax.Defaulter = (*ConsumerRetryArguments)(nil)
+	_ syntax.Validator = (*ConsumerRetryArguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *ConsumerRetryArguments) SetToDefault() {
+	*args = ConsumerRetryArguments{
+		Enabled:         false,
+		InitialInterval: 1 * time.Second,
+		MaxInterval:     30 * time.Second,
+		MaxElapsedTime:  5 * time.Minute,
+	}
+}
+
+// Validate returns an error if args is invalid.
+func (args *ConsumerRetryArguments) Validate() error {
+	if args.InitialInterval <= 0 {
+		return fmt.Errorf("initial_interval must be greater than 0")
+	}
+	if args.MaxInterval < args.InitialInterval {
+		return fmt.Errorf("max_interval must be greater than or equal to initial_interval")
+	}
+	if args.MaxElapsedTime < 0 {
+		return fmt.Errorf("max_elapsed_time must not be negative")
+	}
+	return nil
+}
+
+// Convert converts args into the upstream type. The upstream type lives in an
+// internal package, so it's returned as a map to be decoded with mapstructure.
+func (args *ConsumerRetryArguments) Convert() map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"enabled":          args.Enabled,
+		"initial_interval": args.InitialInterval,
+		"max_interval":     args.MaxInterval,
+		"max_elapsed_time": args.MaxElapsedTime,
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/grafana/alloy/internal/component/otelcol/internal/fanoutconsumer"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/lazycollector"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/lazyconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/livedebuggingconsumer"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/scheduler"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util/zapadapter"
 	"github.com/prometheus/client_golang/prometheus"
 	otelcomponent "go.opentelemetry.io/collector/component"
@@ -34,6 +36,9 @@ const (
 	ConnectorLogsToTraces
 	ConnectorLogsToMetrics
 	ConnectorLogsToLogs
+	// ConnectorAnyToMetrics is the type of the connectors which accept traces,
+	// metrics, and logs, and output metrics.
+	ConnectorAnyToMetrics
 )
 
 // Arguments is an extension of component.Arguments which contains necessary
@@ -74,11 +79,14 @@ type Connector struct {
 
 	sched     *scheduler.Scheduler
 	collector *lazycollector.Collector
+
+	liveDebuggingConsumer *livedebuggingconsumer.Consumer
 }
 
 var (
 	_ component.Component       = (*Connector)(nil)
 	_ component.HealthComponent = (*Connector)(nil)
+	_ component.LiveDebugging   = (*Connector)(nil)
 )
 
 // New creates a new Alloy component which encapsulates an OpenTelemetry
@@ -88,6 +96,11 @@ var (
 // The registered component must be registered to export the
 // otelcol.ConsumerExports type, otherwise New will panic.
 func New(opts component.Options, f otelconnector.Factory, args Arguments) (*Connector, error) {
+	debugDataPublisher, err := opts.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	consumer := lazyconsumer.New(ctx)
@@ -114,6 +127,8 @@ func New(opts component.Options, f otelconnector.Factory, args Arguments) (*Conn
 
 		sched:     scheduler.New(opts.Logger),
 		collector: collector,
+
+		liveDebuggingConsumer: livedebuggingconsumer.New(debugDataPublisher.(livedebugging.DebugDataPublisher), opts.ID),
 	}
 	if err := p.Update(args); err != nil {
 		return nil, err
@@ -186,19 +201,37 @@ func (p *Connector) Update(args component.Arguments) error {
 	var logsConnector otelconnector.Logs
 
 	switch pargs.ConnectorType() {
-	case ConnectorTracesToMetrics:
+	case ConnectorTracesToMetrics, ConnectorAnyToMetrics:
 		if len(next.Traces) > 0 || len(next.Logs) > 0 {
 			return errors.New("this connector can only output metrics")
 		}
 
 		if len(next.Metrics) > 0 {
-			nextMetrics := fanoutconsumer.Metrics(next.Metrics)
+			// The data produced by the connector is published to live
+			// debugging.
+			nextMetrics := p.liveDebuggingConsumer.Metrics(fanoutconsumer.Metrics(next.Metrics))
 			tracesConnector, err = p.factory.CreateTracesToMetrics(p.ctx, settings, connectorConfig, nextMetrics)
 			if err != nil && !errors.Is(err, otelcomponent.ErrDataTypeIsNotSupported) {
 				return err
 			} else if tracesConnector != nil {
 				components = append(components, tracesConnector)
 			}
+
+			if pargs.ConnectorType() == ConnectorAnyToMetrics {
+				metricsConnector, err = p.factory.CreateMetricsToMetrics(p.ctx, settings, connectorConfig, nextMetrics)
+				if err != nil && !errors.Is(err, otelcomponent.ErrDataTypeIsNotSupported) {
+					return err
+				} else if metricsConnector != nil {
+					components = append(components, metricsConnector)
+				}
+
+				logsConnector, err = p.factory.CreateLogsToMetrics(p.ctx, settings, connectorConfig, nextMetrics)
+				if err != nil && !errors.Is(err, otelcomponent.ErrDataTypeIsNotSupported) {
+					return err
+				} else if logsConnector != nil {
+					components = append(components, logsConnector)
+				}
+			}
 		}
 	default:
 		return errors.New("unsupported connector type")
@@ -214,3 +247,8 @@ func (p *Connector) Update(args component.Arguments) error {
 func (p *Connector) CurrentHealth() component.Health {
 	return p.sched.CurrentHealth()
 }
+
+// LiveDebugging implements component.LiveDebugging. The data produced by the
+// connector is always passed through the live debugging consumer, which only
+// publishes it when a live debugging stream is active.
+func (p *Connector) LiveDebugging(_ int) {}
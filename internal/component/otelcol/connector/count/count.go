@@ -0,0 +1,201 @@
+// Package count provides an otelcol.connector.count component.
+package count
+
+import (
+	"fmt"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/connector"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.connector.count",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := countconnector.NewFactory()
+			return connector.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Names and descriptions of the metrics emitted for a kind of telemetry data
+// when no metric is configured for it.
+const (
+	defaultMetricNameSpans      = "trace.span.count"
+	defaultMetricDescSpans      = "The number of spans observed."
+	defaultMetricNameSpanEvents = "trace.span.event.count"
+	defaultMetricDescSpanEvents = "The number of span events observed."
+	defaultMetricNameMetrics    = "metric.count"
+	defaultMetricDescMetrics    = "The number of metrics observed."
+	defaultMetricNameDataPoints = "metric.datapoint.count"
+	defaultMetricDescDataPoints = "The number of data points observed."
+	defaultMetricNameLogs       = "log.record.count"
+	defaultMetricDescLogs       = "The number of log records observed."
+)
+
+// Arguments configures the otelcol.connector.count component.
+type Arguments struct {
+	Spans      []MetricInfo `alloy:"span,block,optional"`
+	SpanEvents []MetricInfo `alloy:"spanevent,block,optional"`
+	Metrics    []MetricInfo `alloy:"metric,block,optional"`
+	DataPoints []MetricInfo `alloy:"datapoint,block,optional"`
+	Logs       []MetricInfo `alloy:"log,block,optional"`
+
+	// Output configures where to send processed data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var (
+	_ syntax.Validator    = (*Arguments)(nil)
+	_ syntax.Defaulter    = (*Arguments)(nil)
+	_ connector.Arguments = (*Arguments)(nil)
+)
+
+// MetricInfo configures a metric which counts the telemetry data matching its
+// conditions.
+type MetricInfo struct {
+	Name        string      `alloy:"name,attr"`
+	Description string      `alloy:"description,attr,optional"`
+	Conditions  []string    `alloy:"conditions,attr,optional"`
+	Attributes  []Attribute `alloy:"attribute,block,optional"`
+}
+
+// Attribute configures an attribute of the telemetry data by which the counts
+// are grouped.
+type Attribute struct {
+	Key          string      `alloy:"key,attr"`
+	DefaultValue interface{} `alloy:"default_value,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{}
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	for _, kind := range []struct {
+		block string
+		infos []MetricInfo
+	}{
+		{"span", args.Spans},
+		{"spanevent", args.SpanEvents},
+		{"metric", args.Metrics},
+		{"datapoint", args.DataPoints},
+		{"log", args.Logs},
+	} {
+		names := make(map[string]struct{}, len(kind.infos))
+		for _, info := range kind.infos {
+			if info.Name == "" {
+				return fmt.Errorf("the name of a %s block must not be empty", kind.block)
+			}
+			if _, ok := names[info.Name]; ok {
+				return fmt.Errorf("metric %q is defined in more than one %s block", info.Name, kind.block)
+			}
+			names[info.Name] = struct{}{}
+
+			if err := info.validateAttributes(); err != nil {
+				return fmt.Errorf("metric %q: %w", info.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (info MetricInfo) validateAttributes() error {
+	keys := make(map[string]struct{}, len(info.Attributes))
+	for _, attr := range info.Attributes {
+		if attr.Key == "" {
+			return fmt.Errorf("attribute key must not be empty")
+		}
+		if _, ok := keys[attr.Key]; ok {
+			return fmt.Errorf("attribute %q is defined more than once", attr.Key)
+		}
+		keys[attr.Key] = struct{}{}
+	}
+	return nil
+}
+
+// Convert implements connector.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	return &countconnector.Config{
+		Spans:      convertMetricInfos(args.Spans, defaultMetricNameSpans, defaultMetricDescSpans),
+		SpanEvents: convertMetricInfos(args.SpanEvents, defaultMetricNameSpanEvents, defaultMetricDescSpanEvents),
+		Metrics:    convertMetricInfos(args.Metrics, defaultMetricNameMetrics, defaultMetricDescMetrics),
+		DataPoints: convertMetricInfos(args.DataPoints, defaultMetricNameDataPoints, defaultMetricDescDataPoints),
+		Logs:       convertMetricInfos(args.Logs, defaultMetricNameLogs, defaultMetricDescLogs),
+	}, nil
+}
+
+// convertMetricInfos converts the metrics of a kind of telemetry data. The
+// default metric of the kind is used when none is configured, like upstream.
+func convertMetricInfos(infos []MetricInfo, defaultName, defaultDesc string) map[string]countconnector.MetricInfo {
+	if len(infos) == 0 {
+		return map[string]countconnector.MetricInfo{
+			defaultName: {Description: defaultDesc},
+		}
+	}
+
+	res := make(map[string]countconnector.MetricInfo, len(infos))
+	for _, info := range infos {
+		res[info.Name] = info.Convert()
+	}
+	return res
+}
+
+// Convert converts info into the configuration of an upstream metric.
+func (info MetricInfo) Convert() countconnector.MetricInfo {
+	var attrs []countconnector.AttributeConfig
+	for _, attr := range info.Attributes {
+		attrs = append(attrs, countconnector.AttributeConfig{
+			Key:          attr.Key,
+			DefaultValue: attr.DefaultValue,
+		})
+	}
+
+	return countconnector.MetricInfo{
+		Description: info.Description,
+		Conditions:  append([]string(nil), info.Conditions...),
+		Attributes:  attrs,
+	}
+}
+
+// Extensions implements connector.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements connector.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements connector.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// ConnectorType() int implements connector.Arguments.
+func (Arguments) ConnectorType() int {
+	return connector.ConnectorAnyToMetrics
+}
+
+// DebugMetricsConfig implements connector.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
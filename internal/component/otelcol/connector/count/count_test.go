@@ -0,0 +1,137 @@
+package count_test
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/otelcol/connector/count"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected countconnector.Config
+		errorMsg string
+	}{
+		{
+			testName: "defaultConfig",
+			cfg: `
+			output {}
+			`,
+			expected: countconnector.Config{
+				Spans: map[string]countconnector.MetricInfo{
+					"trace.span.count": {Description: "The number of spans observed."},
+				},
+				SpanEvents: map[string]countconnector.MetricInfo{
+					"trace.span.event.count": {Description: "The number of span events observed."},
+				},
+				Metrics: map[string]countconnector.MetricInfo{
+					"metric.count": {Description: "The number of metrics observed."},
+				},
+				DataPoints: map[string]countconnector.MetricInfo{
+					"metric.datapoint.count": {Description: "The number of data points observed."},
+				},
+				Logs: map[string]countconnector.MetricInfo{
+					"log.record.count": {Description: "The number of log records observed."},
+				},
+			},
+		},
+		{
+			testName: "customLogs",
+			cfg: `
+			log {
+				name        = "log.error.count"
+				description = "The number of error logs."
+				conditions  = [
+					"severity_number >= SEVERITY_NUMBER_ERROR",
+				]
+				attribute {
+					key           = "service.name"
+					default_value = "unknown"
+				}
+			}
+			log {
+				name = "log.count"
+			}
+
+			output {}
+			`,
+			expected: countconnector.Config{
+				Spans: map[string]countconnector.MetricInfo{
+					"trace.span.count": {Description: "The number of spans observed."},
+				},
+				SpanEvents: map[string]countconnector.MetricInfo{
+					"trace.span.event.count": {Description: "The number of span events observed."},
+				},
+				Metrics: map[string]countconnector.MetricInfo{
+					"metric.count": {Description: "The number of metrics observed."},
+				},
+				DataPoints: map[string]countconnector.MetricInfo{
+					"metric.datapoint.count": {Description: "The number of data points observed."},
+				},
+				Logs: map[string]countconnector.MetricInfo{
+					"log.error.count": {
+						Description: "The number of error logs.",
+						Conditions:  []string{"severity_number >= SEVERITY_NUMBER_ERROR"},
+						Attributes: []countconnector.AttributeConfig{
+							{Key: "service.name", DefaultValue: "unknown"},
+						},
+					},
+					"log.count": {},
+				},
+			},
+		},
+		{
+			testName: "duplicateName",
+			cfg: `
+			span {
+				name = "span.count"
+			}
+			span {
+				name = "span.count"
+			}
+
+			output {}
+			`,
+			errorMsg: `metric "span.count" is defined in more than one span block`,
+		},
+		{
+			testName: "duplicateAttribute",
+			cfg: `
+			datapoint {
+				name = "datapoint.count"
+				attribute {
+					key = "env"
+				}
+				attribute {
+					key = "env"
+				}
+			}
+
+			output {}
+			`,
+			errorMsg: `metric "datapoint.count": attribute "env" is defined more than once`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args count.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			actualPtr, err := args.Convert()
+			require.NoError(t, err)
+
+			actual := actualPtr.(*countconnector.Config)
+			require.Equal(t, tc.expected, *actual)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package sum
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines the configuration options for the sum connector.
+type Config struct {
+	Spans      map[string]MetricInfo `mapstructure:"spans"`
+	SpanEvents map[string]MetricInfo `mapstructure:"spanevents"`
+	DataPoints map[string]MetricInfo `mapstructure:"datapoints"`
+	Logs       map[string]MetricInfo `mapstructure:"logs"`
+}
+
+// MetricInfo configures a metric which sums the values of an attribute of the
+// telemetry data matching its conditions.
+type MetricInfo struct {
+	Description string `mapstructure:"description"`
+	// SourceAttribute is the attribute whose values are summed. The telemetry
+	// data without it is ignored.
+	SourceAttribute string `mapstructure:"source_attribute"`
+	// Conditions are OTTL conditions. The telemetry data is summed if any of
+	// them matches. All the telemetry data is summed when there's none.
+	Conditions []string          `mapstructure:"conditions"`
+	Attributes []AttributeConfig `mapstructure:"attributes"`
+}
+
+// AttributeConfig configures an attribute by which the sums are grouped.
+type AttributeConfig struct {
+	Key          string `mapstructure:"key"`
+	DefaultValue any    `mapstructure:"default_value"`
+}
+
+var _ component.ConfigValidator = (*Config)(nil)
+
+// Validate checks if the configuration is valid
+func (c Config) Validate() error {
+	for _, metrics := range []map[string]MetricInfo{c.Spans, c.SpanEvents, c.DataPoints, c.Logs} {
+		for name, info := range metrics {
+			if name == "" {
+				return fmt.Errorf("metric name must not be empty")
+			}
+			if info.SourceAttribute == "" {
+				return fmt.Errorf("metric %q: source_attribute must not be empty", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,420 @@
+package sum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	_ connector.Traces  = (*connectorImp)(nil)
+	_ connector.Metrics = (*connectorImp)(nil)
+	_ connector.Logs    = (*connectorImp)(nil)
+)
+
+// connectorImp sums the values of attributes of the telemetry data it
+// consumes, and sends the sums of each batch as delta sum metrics.
+type connectorImp struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	metricsConsumer consumer.Metrics
+
+	spans      []metricDef[ottlspan.TransformContext]
+	spanEvents []metricDef[ottlspanevent.TransformContext]
+	dataPoints []metricDef[ottldatapoint.TransformContext]
+	logs       []metricDef[ottllog.TransformContext]
+}
+
+func newConnector(set component.TelemetrySettings, cfg *Config, next consumer.Metrics) (*connectorImp, error) {
+	spanParser, err := ottlspan.NewParser(ottlfuncs.StandardConverters[ottlspan.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+	spanEventParser, err := ottlspanevent.NewParser(ottlfuncs.StandardConverters[ottlspanevent.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+	dataPointParser, err := ottldatapoint.NewParser(ottlfuncs.StandardConverters[ottldatapoint.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+	logParser, err := ottllog.NewParser(ottlfuncs.StandardConverters[ottllog.TransformContext](), set)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &connectorImp{metricsConsumer: next}
+	if c.spans, err = newMetricDefs(&spanParser, cfg.Spans, set); err != nil {
+		return nil, err
+	}
+	if c.spanEvents, err = newMetricDefs(&spanEventParser, cfg.SpanEvents, set); err != nil {
+		return nil, err
+	}
+	if c.dataPoints, err = newMetricDefs(&dataPointParser, cfg.DataPoints, set); err != nil {
+		return nil, err
+	}
+	if c.logs, err = newMetricDefs(&logParser, cfg.Logs, set); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Capabilities implements consumer.Traces, consumer.Metrics, and
+// consumer.Logs.
+func (c *connectorImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements consumer.Traces.
+func (c *connectorImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	var errs []error
+	md := pmetric.NewMetrics()
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		resource := rs.Resource()
+		sums := newResourceSums()
+
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			scope := ss.Scope()
+
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				lookup := attributeLookup{span.Attributes(), scope.Attributes(), resource.Attributes()}
+
+				if len(c.spans) > 0 {
+					tCtx := ottlspan.NewTransformContext(span, scope, resource)
+					for _, def := range c.spans {
+						errs = append(errs, def.add(ctx, tCtx, sums, lookup))
+					}
+				}
+
+				if len(c.spanEvents) > 0 {
+					for l := 0; l < span.Events().Len(); l++ {
+						event := span.Events().At(l)
+						tCtx := ottlspanevent.NewTransformContext(event, span, scope, resource)
+						eventLookup := attributeLookup{event.Attributes(), scope.Attributes(), resource.Attributes()}
+						for _, def := range c.spanEvents {
+							errs = append(errs, def.add(ctx, tCtx, sums, eventLookup))
+						}
+					}
+				}
+			}
+		}
+
+		sums.appendTo(md, resource, metricInfos(c.spans), metricInfos(c.spanEvents))
+	}
+	return c.send(ctx, md, errs)
+}
+
+// ConsumeMetrics implements consumer.Metrics.
+func (c *connectorImp) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	var errs []error
+	out := pmetric.NewMetrics()
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		resource := rm.Resource()
+		sums := newResourceSums()
+
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			scope := sm.Scope()
+			metrics := sm.Metrics()
+
+			for k := 0; k < metrics.Len() && len(c.dataPoints) > 0; k++ {
+				metric := metrics.At(k)
+				add := func(dp any, attrs pcommon.Map) {
+					tCtx := ottldatapoint.NewTransformContext(dp, metric, metrics, scope, resource)
+					lookup := attributeLookup{attrs, scope.Attributes(), resource.Attributes()}
+					for _, def := range c.dataPoints {
+						errs = append(errs, def.add(ctx, tCtx, sums, lookup))
+					}
+				}
+
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						add(dps.At(l), dps.At(l).Attributes())
+					}
+				case pmetric.MetricTypeSum:
+					dps := metric.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						add(dps.At(l), dps.At(l).Attributes())
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						add(dps.At(l), dps.At(l).Attributes())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := metric.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						add(dps.At(l), dps.At(l).Attributes())
+					}
+				case pmetric.MetricTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						add(dps.At(l), dps.At(l).Attributes())
+					}
+				}
+			}
+		}
+
+		sums.appendTo(out, resource, metricInfos(c.dataPoints))
+	}
+	return c.send(ctx, out, errs)
+}
+
+// ConsumeLogs implements consumer.Logs.
+func (c *connectorImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	var errs []error
+	md := pmetric.NewMetrics()
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resource := rl.Resource()
+		sums := newResourceSums()
+
+		for j := 0; j < rl.ScopeLogs().Len() && len(c.logs) > 0; j++ {
+			sl := rl.ScopeLogs().At(j)
+			scope := sl.Scope()
+
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				record := sl.LogRecords().At(k)
+				tCtx := ottllog.NewTransformContext(record, scope, resource)
+				lookup := attributeLookup{record.Attributes(), scope.Attributes(), resource.Attributes()}
+				for _, def := range c.logs {
+					errs = append(errs, def.add(ctx, tCtx, sums, lookup))
+				}
+			}
+		}
+
+		sums.appendTo(md, resource, metricInfos(c.logs))
+	}
+	return c.send(ctx, md, errs)
+}
+
+// send sends the sums of a batch to the next consumer, along with the errors
+// of the evaluation of the conditions.
+func (c *connectorImp) send(ctx context.Context, md pmetric.Metrics, errs []error) error {
+	if md.ResourceMetrics().Len() > 0 {
+		errs = append(errs, c.metricsConsumer.ConsumeMetrics(ctx, md))
+	}
+	return errors.Join(errs...)
+}
+
+// metricInfo describes a metric emitted by the connector.
+type metricInfo struct {
+	name        string
+	description string
+}
+
+// metricDef is a metric which sums the values of an attribute of the
+// telemetry data of the context K.
+type metricDef[K any] struct {
+	metricInfo
+	sourceAttribute string
+	attributes      []AttributeConfig
+	// condition is nil when all the telemetry data is summed.
+	condition *ottl.ConditionSequence[K]
+}
+
+// newMetricDefs returns the definitions of metrics, ordered by name.
+func newMetricDefs[K any](parser *ottl.Parser[K], metrics map[string]MetricInfo, set component.TelemetrySettings) ([]metricDef[K], error) {
+	defs := make([]metricDef[K], 0, len(metrics))
+	for name, info := range metrics {
+		def := metricDef[K]{
+			metricInfo:      metricInfo{name: name, description: info.Description},
+			sourceAttribute: info.SourceAttribute,
+			attributes:      info.Attributes,
+		}
+		if len(info.Conditions) > 0 {
+			conditions, err := parser.ParseConditions(info.Conditions)
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: %w", name, err)
+			}
+			seq := ottl.NewConditionSequence(conditions, set, ottl.WithLogicOperation[K](ottl.Or))
+			def.condition = &seq
+		}
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].name < defs[j].name })
+	return defs, nil
+}
+
+func metricInfos[K any](defs []metricDef[K]) []metricInfo {
+	infos := make([]metricInfo, 0, len(defs))
+	for _, def := range defs {
+		infos = append(infos, def.metricInfo)
+	}
+	return infos
+}
+
+// add adds the value of the source attribute of the telemetry data of tCtx
+// to sums, if it matches the conditions of the metric.
+func (d metricDef[K]) add(ctx context.Context, tCtx K, sums *resourceSums, lookup attributeLookup) error {
+	value, ok := sourceValue(lookup.item, d.sourceAttribute)
+	if !ok {
+		return nil
+	}
+	if d.condition != nil {
+		match, err := d.condition.Eval(ctx, tCtx)
+		if err != nil {
+			return fmt.Errorf("metric %q: %w", d.name, err)
+		}
+		if !match {
+			return nil
+		}
+	}
+	sums.add(d.name, lookup.attributes(d.attributes), value)
+	return nil
+}
+
+// sourceValue returns the numeric value of the attribute key of attrs. String
+// values are parsed.
+func sourceValue(attrs pcommon.Map, key string) (float64, bool) {
+	v, ok := attrs.Get(key)
+	if !ok {
+		return 0, false
+	}
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(v.Int()), true
+	case pcommon.ValueTypeDouble:
+		return v.Double(), true
+	case pcommon.ValueTypeStr:
+		f, err := strconv.ParseFloat(v.Str(), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// attributeLookup looks attributes up in the attributes of an item of
+// telemetry data, then of its scope, and then of its resource.
+type attributeLookup struct {
+	item, scope, resource pcommon.Map
+}
+
+// attributes returns the attributes of a data point of a metric. The
+// attributes which aren't found and have no default value are left out.
+func (l attributeLookup) attributes(configs []AttributeConfig) pcommon.Map {
+	attrs := pcommon.NewMap()
+	for _, attr := range configs {
+		if v, ok := l.get(attr.Key); ok {
+			v.CopyTo(attrs.PutEmpty(attr.Key))
+		} else if attr.DefaultValue != nil {
+			if err := attrs.PutEmpty(attr.Key).FromRaw(attr.DefaultValue); err != nil {
+				attrs.PutStr(attr.Key, fmt.Sprint(attr.DefaultValue))
+			}
+		}
+	}
+	return attrs
+}
+
+func (l attributeLookup) get(key string) (pcommon.Value, bool) {
+	for _, attrs := range []pcommon.Map{l.item, l.scope, l.resource} {
+		if v, ok := attrs.Get(key); ok {
+			return v, true
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+// resourceSums holds the sums of the metrics of a resource.
+type resourceSums struct {
+	metrics map[string]*metricSums
+}
+
+// metricSums holds the sums of a metric, by attributes.
+type metricSums struct {
+	points []*dataPoint
+	index  map[[16]byte]*dataPoint
+}
+
+type dataPoint struct {
+	attributes pcommon.Map
+	value      float64
+}
+
+func newResourceSums() *resourceSums {
+	return &resourceSums{metrics: make(map[string]*metricSums)}
+}
+
+func (s *resourceSums) add(name string, attrs pcommon.Map, value float64) {
+	ms, ok := s.metrics[name]
+	if !ok {
+		ms = &metricSums{index: make(map[[16]byte]*dataPoint)}
+		s.metrics[name] = ms
+	}
+
+	key := pdatautil.MapHash(attrs)
+	if dp, ok := ms.index[key]; ok {
+		dp.value += value
+		return
+	}
+	dp := &dataPoint{attributes: attrs, value: value}
+	ms.index[key] = dp
+	ms.points = append(ms.points, dp)
+}
+
+// appendTo appends the sums of the metrics of infos to md, as delta sums of
+// resource. Nothing is appended when there are no sums.
+func (s *resourceSums) appendTo(md pmetric.Metrics, resource pcommon.Resource, infos ...[]metricInfo) {
+	if len(s.metrics) == 0 {
+		return
+	}
+
+	rm := md.ResourceMetrics().AppendEmpty()
+	resource.Attributes().CopyTo(rm.Resource().Attributes())
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(typeStr)
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+	for _, kind := range infos {
+		for _, info := range kind {
+			ms, ok := s.metrics[info.name]
+			if !ok {
+				continue
+			}
+			// A metric of several kinds of telemetry data is only appended
+			// once.
+			delete(s.metrics, info.name)
+
+			m := sm.Metrics().AppendEmpty()
+			m.SetName(info.name)
+			m.SetDescription(info.description)
+			sum := m.SetEmptySum()
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+			sum.SetIsMonotonic(false)
+
+			dps := sum.DataPoints()
+			dps.EnsureCapacity(len(ms.points))
+			for _, point := range ms.points {
+				dp := dps.AppendEmpty()
+				point.attributes.CopyTo(dp.Attributes())
+				dp.SetTimestamp(timestamp)
+				dp.SetDoubleValue(point.value)
+			}
+		}
+	}
+}
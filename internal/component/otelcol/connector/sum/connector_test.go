@@ -0,0 +1,126 @@
+package sum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestConnector_Logs(t *testing.T) {
+	cfg := &Config{
+		Logs: map[string]MetricInfo{
+			"checkout.amount": {
+				Description:     "The total amount of the checkouts.",
+				SourceAttribute: "amount",
+				Conditions:      []string{`attributes["event"] == "checkout"`},
+				Attributes: []AttributeConfig{
+					{Key: "service.name"},
+					{Key: "currency", DefaultValue: "USD"},
+				},
+			},
+		},
+	}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "shop")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	for _, l := range []struct {
+		event    string
+		amount   any
+		currency string
+	}{
+		{"checkout", int64(10), ""},
+		{"checkout", 2.5, ""},
+		{"checkout", "7.5", "EUR"},
+		{"refund", int64(100), ""},
+		{"checkout", nil, ""},
+	} {
+		record := records.AppendEmpty()
+		record.Attributes().PutStr("event", l.event)
+		if l.amount != nil {
+			require.NoError(t, record.Attributes().PutEmpty("amount").FromRaw(l.amount))
+		}
+		if l.currency != "" {
+			record.Attributes().PutStr("currency", l.currency)
+		}
+	}
+
+	sink := new(consumertest.MetricsSink)
+	c, err := NewFactory().CreateLogsToMetrics(context.Background(), connectortest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, c.ConsumeLogs(context.Background(), ld))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	md := sink.AllMetrics()[0]
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	serviceName, _ := rm.Resource().Attributes().Get("service.name")
+	require.Equal(t, "shop", serviceName.Str())
+
+	metrics := rm.ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	m := metrics.At(0)
+	require.Equal(t, "checkout.amount", m.Name())
+	require.Equal(t, "The total amount of the checkouts.", m.Description())
+	require.Equal(t, pmetric.AggregationTemporalityDelta, m.Sum().AggregationTemporality())
+
+	sums := map[string]float64{}
+	for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+		dp := m.Sum().DataPoints().At(i)
+		currency, _ := dp.Attributes().Get("currency")
+		service, _ := dp.Attributes().Get("service.name")
+		require.Equal(t, "shop", service.Str())
+		sums[currency.Str()] = dp.DoubleValue()
+	}
+	require.Equal(t, map[string]float64{"USD": 12.5, "EUR": 7.5}, sums)
+}
+
+func TestConnector_Traces(t *testing.T) {
+	cfg := &Config{
+		Spans: map[string]MetricInfo{
+			"span.bytes": {SourceAttribute: "bytes"},
+		},
+		SpanEvents: map[string]MetricInfo{
+			"span.event.retries": {SourceAttribute: "retries"},
+		},
+	}
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < 3; i++ {
+		span := spans.AppendEmpty()
+		span.Attributes().PutInt("bytes", 100)
+		span.Events().AppendEmpty().Attributes().PutInt("retries", 2)
+	}
+
+	sink := new(consumertest.MetricsSink)
+	c, err := NewFactory().CreateTracesToMetrics(context.Background(), connectortest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, c.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	metrics := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	require.Equal(t, "span.bytes", metrics.At(0).Name())
+	require.Equal(t, 300.0, metrics.At(0).Sum().DataPoints().At(0).DoubleValue())
+	require.Equal(t, "span.event.retries", metrics.At(1).Name())
+	require.Equal(t, 6.0, metrics.At(1).Sum().DataPoints().At(0).DoubleValue())
+}
+
+func TestConnector_InvalidCondition(t *testing.T) {
+	cfg := &Config{
+		DataPoints: map[string]MetricInfo{
+			"bytes": {SourceAttribute: "bytes", Conditions: []string{"invalid condition"}},
+		},
+	}
+
+	_, err := NewFactory().CreateMetricsToMetrics(context.Background(), connectortest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.ErrorContains(t, err, `metric "bytes"`)
+}
@@ -0,0 +1,51 @@
+package sum
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	typeStr = "sumconnector"
+)
+
+func NewFactory() connector.Factory {
+	return connector.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		connector.WithTracesToMetrics(createTracesToMetricsConnector, component.StabilityLevelAlpha),
+		connector.WithMetricsToMetrics(createMetricsToMetricsConnector, component.StabilityLevelAlpha),
+		connector.WithLogsToMetrics(createLogsToMetricsConnector, component.StabilityLevelAlpha),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{}
+}
+
+func createTracesToMetricsConnector(_ context.Context, params connector.CreateSettings, cfg component.Config, next consumer.Metrics) (connector.Traces, error) {
+	c, err := newConnector(params.TelemetrySettings, cfg.(*Config), next)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func createMetricsToMetricsConnector(_ context.Context, params connector.CreateSettings, cfg component.Config, next consumer.Metrics) (connector.Metrics, error) {
+	c, err := newConnector(params.TelemetrySettings, cfg.(*Config), next)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func createLogsToMetricsConnector(_ context.Context, params connector.CreateSettings, cfg component.Config, next consumer.Metrics) (connector.Logs, error) {
+	c, err := newConnector(params.TelemetrySettings, cfg.(*Config), next)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
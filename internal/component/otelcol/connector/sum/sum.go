@@ -0,0 +1,180 @@
+// Package sum provides an otelcol.connector.sum component.
+package sum
+
+import (
+	"fmt"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/connector"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.connector.sum",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := NewFactory()
+			return connector.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.connector.sum component.
+type Arguments struct {
+	Spans      []MetricArguments `alloy:"span,block,optional"`
+	SpanEvents []MetricArguments `alloy:"spanevent,block,optional"`
+	DataPoints []MetricArguments `alloy:"datapoint,block,optional"`
+	Logs       []MetricArguments `alloy:"log,block,optional"`
+
+	// Output configures where to send processed data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var (
+	_ syntax.Validator    = (*Arguments)(nil)
+	_ syntax.Defaulter    = (*Arguments)(nil)
+	_ connector.Arguments = (*Arguments)(nil)
+)
+
+// MetricArguments configures a metric which sums the values of an attribute
+// of the telemetry data matching its conditions.
+type MetricArguments struct {
+	Name            string               `alloy:"name,attr"`
+	Description     string               `alloy:"description,attr,optional"`
+	SourceAttribute string               `alloy:"source_attribute,attr"`
+	Conditions      []string             `alloy:"conditions,attr,optional"`
+	Attributes      []AttributeArguments `alloy:"attribute,block,optional"`
+}
+
+// AttributeArguments configures an attribute by which the sums are grouped.
+type AttributeArguments struct {
+	Key          string      `alloy:"key,attr"`
+	DefaultValue interface{} `alloy:"default_value,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{}
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	kinds := []struct {
+		block   string
+		metrics []MetricArguments
+	}{
+		{"span", args.Spans},
+		{"spanevent", args.SpanEvents},
+		{"datapoint", args.DataPoints},
+		{"log", args.Logs},
+	}
+
+	var count int
+	for _, kind := range kinds {
+		count += len(kind.metrics)
+
+		names := make(map[string]struct{}, len(kind.metrics))
+		for _, metric := range kind.metrics {
+			if metric.Name == "" {
+				return fmt.Errorf("the name of a %s block must not be empty", kind.block)
+			}
+			if _, ok := names[metric.Name]; ok {
+				return fmt.Errorf("metric %q is defined in more than one %s block", metric.Name, kind.block)
+			}
+			names[metric.Name] = struct{}{}
+
+			if metric.SourceAttribute == "" {
+				return fmt.Errorf("metric %q: source_attribute must not be empty", metric.Name)
+			}
+
+			keys := make(map[string]struct{}, len(metric.Attributes))
+			for _, attr := range metric.Attributes {
+				if attr.Key == "" {
+					return fmt.Errorf("metric %q: attribute key must not be empty", metric.Name)
+				}
+				if _, ok := keys[attr.Key]; ok {
+					return fmt.Errorf("metric %q: attribute %q is defined more than once", metric.Name, attr.Key)
+				}
+				keys[attr.Key] = struct{}{}
+			}
+		}
+	}
+
+	if count == 0 {
+		return fmt.Errorf("at least one span, spanevent, datapoint, or log block must be defined")
+	}
+	return nil
+}
+
+// Convert implements connector.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	return &Config{
+		Spans:      convertMetrics(args.Spans),
+		SpanEvents: convertMetrics(args.SpanEvents),
+		DataPoints: convertMetrics(args.DataPoints),
+		Logs:       convertMetrics(args.Logs),
+	}, nil
+}
+
+func convertMetrics(metrics []MetricArguments) map[string]MetricInfo {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	res := make(map[string]MetricInfo, len(metrics))
+	for _, metric := range metrics {
+		var attrs []AttributeConfig
+		for _, attr := range metric.Attributes {
+			attrs = append(attrs, AttributeConfig{
+				Key:          attr.Key,
+				DefaultValue: attr.DefaultValue,
+			})
+		}
+
+		res[metric.Name] = MetricInfo{
+			Description:     metric.Description,
+			SourceAttribute: metric.SourceAttribute,
+			Conditions:      append([]string(nil), metric.Conditions...),
+			Attributes:      attrs,
+		}
+	}
+	return res
+}
+
+// Extensions implements connector.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements connector.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements connector.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// ConnectorType() int implements connector.Arguments.
+func (Arguments) ConnectorType() int {
+	return connector.ConnectorAnyToMetrics
+}
+
+// DebugMetricsConfig implements connector.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
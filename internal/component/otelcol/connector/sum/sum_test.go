@@ -0,0 +1,103 @@
+package sum_test
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/otelcol/connector/sum"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected sum.Config
+		errorMsg string
+	}{
+		{
+			testName: "logs",
+			cfg: `
+			log {
+				name             = "checkout.amount"
+				description      = "The total amount of the checkouts."
+				source_attribute = "amount"
+				conditions       = [
+					"attributes[\"event\"] == \"checkout\"",
+				]
+				attribute {
+					key           = "currency"
+					default_value = "USD"
+				}
+			}
+
+			output {}
+			`,
+			expected: sum.Config{
+				Logs: map[string]sum.MetricInfo{
+					"checkout.amount": {
+						Description:     "The total amount of the checkouts.",
+						SourceAttribute: "amount",
+						Conditions:      []string{`attributes["event"] == "checkout"`},
+						Attributes: []sum.AttributeConfig{
+							{Key: "currency", DefaultValue: "USD"},
+						},
+					},
+				},
+			},
+		},
+		{
+			testName: "noMetric",
+			cfg: `
+			output {}
+			`,
+			errorMsg: "at least one span, spanevent, datapoint, or log block must be defined",
+		},
+		{
+			testName: "missingSourceAttribute",
+			cfg: `
+			span {
+				name             = "span.bytes"
+				source_attribute = ""
+			}
+
+			output {}
+			`,
+			errorMsg: `metric "span.bytes": source_attribute must not be empty`,
+		},
+		{
+			testName: "duplicateName",
+			cfg: `
+			datapoint {
+				name             = "bytes"
+				source_attribute = "bytes"
+			}
+			datapoint {
+				name             = "bytes"
+				source_attribute = "size"
+			}
+
+			output {}
+			`,
+			errorMsg: `metric "bytes" is defined in more than one datapoint block`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args sum.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			actualPtr, err := args.Convert()
+			require.NoError(t, err)
+
+			actual := actualPtr.(*sum.Config)
+			require.Equal(t, tc.expected, *actual)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package exporter
+
+import (
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/scheduler"
+	"github.com/grafana/alloy/internal/util/zapadapter"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	metricNoop "go.opentelemetry.io/otel/metric/noop"
+	traceNoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// ConnectivitySettings returns the host and telemetry settings to build the
+// client of an exporter outside of a running component, such as to check the
+// connectivity of its endpoint.
+func ConnectivitySettings(args Arguments) (otelcomponent.Host, otelcomponent.TelemetrySettings) {
+	logger := log.NewNopLogger()
+	host := scheduler.NewHost(logger, scheduler.WithHostExtensions(args.Extensions()))
+	return host, otelcomponent.TelemetrySettings{
+		Logger:         zapadapter.New(logger),
+		TracerProvider: traceNoop.NewTracerProvider(),
+		MeterProvider:  metricNoop.NewMeterProvider(),
+		ReportStatus:   func(*otelcomponent.StatusEvent) {},
+	}
+}
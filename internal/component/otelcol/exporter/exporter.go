@@ -13,8 +13,10 @@ import (
 	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/lazycollector"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/lazyconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/livedebuggingconsumer"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/scheduler"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/views"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util/zapadapter"
 	"github.com/prometheus/client_golang/prometheus"
 	otelcomponent "go.opentelemetry.io/collector/component"
@@ -85,6 +87,8 @@ type Exporter struct {
 	sched     *scheduler.Scheduler
 	collector *lazycollector.Collector
 
+	liveDebuggingConsumer *livedebuggingconsumer.Consumer
+
 	// Signals which the exporter is able to export.
 	// Can be logs, metrics, traces or any combination of them.
 	supportedSignals TypeSignal
@@ -93,6 +97,7 @@ type Exporter struct {
 var (
 	_ component.Component       = (*Exporter)(nil)
 	_ component.HealthComponent = (*Exporter)(nil)
+	_ component.LiveDebugging   = (*Exporter)(nil)
 )
 
 // New creates a new component which encapsulates an OpenTelemetry Collector
@@ -102,6 +107,11 @@ var (
 // The registered component must be registered to export the
 // otelcol.ConsumerExports type, otherwise New will panic.
 func New(opts component.Options, f otelexporter.Factory, args Arguments, supportedSignals TypeSignal) (*Exporter, error) {
+	debugDataPublisher, err := opts.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	consumer := lazyconsumer.New(ctx)
@@ -129,6 +139,8 @@ func New(opts component.Options, f otelexporter.Factory, args Arguments, support
 		sched:     scheduler.New(opts.Logger),
 		collector: collector,
 
+		liveDebuggingConsumer: livedebuggingconsumer.New(debugDataPublisher.(livedebugging.DebugDataPublisher), opts.ID),
+
 		supportedSignals: supportedSignals,
 	}
 	if err := e.Update(args); err != nil {
@@ -234,7 +246,13 @@ func (e *Exporter) Update(args component.Arguments) error {
 
 	// Schedule the components to run once our component is running.
 	e.sched.Schedule(host, components...)
-	e.consumer.SetConsumers(tracesExporter, metricsExporter, logsExporter)
+	// The data consumed by the exporter is published to live debugging
+	// before being exported.
+	e.consumer.SetConsumers(
+		e.liveDebuggingConsumer.Traces(tracesExporter),
+		e.liveDebuggingConsumer.Metrics(metricsExporter),
+		e.liveDebuggingConsumer.Logs(logsExporter),
+	)
 	return nil
 }
 
@@ -242,3 +260,8 @@ func (e *Exporter) Update(args component.Arguments) error {
 func (e *Exporter) CurrentHealth() component.Health {
 	return e.sched.CurrentHealth()
 }
+
+// LiveDebugging implements component.LiveDebugging. The data consumed by the
+// exporter is always passed through the live debugging consumer, which only
+// publishes it when a live debugging stream is active.
+func (e *Exporter) LiveDebugging(_ int) {}
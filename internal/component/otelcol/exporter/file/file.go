@@ -0,0 +1,204 @@
+// Package file provides an otelcol.exporter.file component.
+package file
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/units"
+	otelconsumer "go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/lazyconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/otlpfile"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/util/rotatingfile"
+)
+
+// rotateCheckInterval is how often the file is checked for time-based
+// rotation.
+const rotateCheckInterval = 10 * time.Second
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.exporter.file",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(o component.Options, a component.Arguments) (component.Component, error) {
+			return New(o, a.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.exporter.file component.
+type Arguments struct {
+	Path           string           `alloy:"path,attr"`
+	Format         string           `alloy:"format,attr,optional"`
+	MaxSize        units.Base2Bytes `alloy:"max_size,attr,optional"`
+	RotateInterval time.Duration    `alloy:"rotate_interval,attr,optional"`
+	MaxFiles       int              `alloy:"max_files,attr,optional"`
+	Compress       bool             `alloy:"compress,attr,optional"`
+}
+
+// DefaultArguments holds default values for Arguments.
+var DefaultArguments = Arguments{
+	Format:  otlpfile.FormatJSON,
+	MaxSize: 100 * units.MiB,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if err := otlpfile.ValidateFormat(args.Format); err != nil {
+		return err
+	}
+	if args.MaxSize < 0 {
+		return fmt.Errorf("max_size must not be negative")
+	}
+	if args.RotateInterval < 0 {
+		return fmt.Errorf("rotate_interval must not be negative")
+	}
+	if args.MaxFiles < 0 {
+		return fmt.Errorf("max_files must not be negative")
+	}
+	return nil
+}
+
+// Component is the otelcol.exporter.file component.
+type Component struct {
+	opts component.Options
+
+	mut    sync.Mutex
+	args   Arguments
+	file   *rotatingfile.File
+	format string
+}
+
+var (
+	_ component.Component = (*Component)(nil)
+
+	_ otelconsumer.Traces  = (*Component)(nil)
+	_ otelconsumer.Metrics = (*Component)(nil)
+	_ otelconsumer.Logs    = (*Component)(nil)
+)
+
+// New creates a new otelcol.exporter.file component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{opts: o}
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+
+	// The component writes the data it receives itself, so it's exported as
+	// the consumer for the component's lifetime.
+	export := lazyconsumer.New(context.Background())
+	export.SetConsumers(c, c, c)
+	o.OnStateChange(otelcol.ConsumerExports{Input: export})
+
+	return c, nil
+}
+
+// Run implements Component.
+func (c *Component) Run(ctx context.Context) error {
+	ticker := time.NewTicker(rotateCheckInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		c.mut.Lock()
+		defer c.mut.Unlock()
+		if err := c.file.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close file", "path", c.args.Path, "err", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			c.mut.Lock()
+			if err := c.file.RotateIfExpired(now); err != nil {
+				level.Error(c.opts.Logger).Log("msg", "failed to rotate file", "path", c.args.Path, "err", err)
+			}
+			c.mut.Unlock()
+		}
+	}
+}
+
+// Update implements Component.
+func (c *Component) Update(newConfig component.Arguments) error {
+	args := newConfig.(Arguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.file != nil {
+		if err := c.file.Close(); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to close file", "path", c.args.Path, "err", err)
+		}
+	}
+
+	c.args = args
+	c.format = args.Format
+	c.file = rotatingfile.New(rotatingfile.Options{
+		Path:     args.Path,
+		MaxSize:  int64(args.MaxSize),
+		MaxAge:   args.RotateInterval,
+		MaxFiles: args.MaxFiles,
+		Compress: args.Compress,
+	})
+	return nil
+}
+
+// Capabilities implements otelconsumer.baseConsumer.
+func (c *Component) Capabilities() otelconsumer.Capabilities {
+	return otelconsumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements otelconsumer.Traces.
+func (c *Component) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.write(otlpfile.MarshalTraces(c.format, td))
+}
+
+// ConsumeMetrics implements otelconsumer.Metrics.
+func (c *Component) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.write(otlpfile.MarshalMetrics(c.format, md))
+}
+
+// ConsumeLogs implements otelconsumer.Logs.
+func (c *Component) ConsumeLogs(_ context.Context, ld plog.Logs) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.write(otlpfile.MarshalLogs(c.format, ld))
+}
+
+// write writes a record to the file. c.mut must be held.
+func (c *Component) write(record []byte, err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to encode data: %w", err)
+	}
+	if _, err := c.file.Write(record); err != nil {
+		return fmt.Errorf("failed to write to %s: %w", c.args.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/otlpfile"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+		path   = "/tmp/capture.json"
+		format = "xml"
+	`), &args)
+	require.ErrorContains(t, err, `format must be one of "json" or "proto", got "xml"`)
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.bin")
+	args := DefaultArguments
+	args.Path = path
+	args.Format = otlpfile.FormatProto
+
+	c, err := New(component.Options{
+		Logger:        util.TestAlloyLogger(t),
+		OnStateChange: func(e component.Exports) {},
+	}, args)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	require.NoError(t, c.ConsumeTraces(context.Background(), td))
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("log")
+	require.NoError(t, c.ConsumeLogs(context.Background(), ld))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	dec := otlpfile.NewDecoder(otlpfile.FormatProto, f)
+	record, err := dec.Next()
+	require.NoError(t, err)
+	require.Equal(t, "span", record.Traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	record, err = dec.Next()
+	require.NoError(t, err)
+	require.Equal(t, "log", record.Logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
@@ -10,25 +10,54 @@ package convert
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/loki/pkg/push"
 	loki_translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/loki"
+	prometheus_translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
 
+// Hint attributes of the loki translator, which list the attributes to
+// convert to labels.
+const (
+	hintResourceLabels  = "loki.resource.labels"
+	hintAttributeLabels = "loki.attribute.labels"
+)
+
+// Rules selects OTLP attributes to convert to Loki labels and structured
+// metadata, in addition to the attributes selected by hint attributes.
+type Rules struct {
+	ResourceLabels    []string
+	AttributeLabels   []string
+	ResourceMetadata  []string
+	AttributeMetadata []string
+}
+
+func (r Rules) resourceRules() bool {
+	return len(r.ResourceLabels) > 0 || len(r.ResourceMetadata) > 0
+}
+
+func (r Rules) attributeRules() bool {
+	return len(r.AttributeLabels) > 0 || len(r.AttributeMetadata) > 0
+}
+
 // Converter implements consumer.Logs and converts received OTel logs into
 // Loki-compatible log entries.
 type Converter struct {
 	log     log.Logger
 	metrics *metrics
 
-	mut  sync.RWMutex
-	next []loki.LogsReceiver // Location to write converted logs.
+	mut   sync.RWMutex
+	next  []loki.LogsReceiver // Location to write converted logs.
+	rules Rules
 }
 
 var _ consumer.Logs = (*Converter)(nil)
@@ -56,10 +85,23 @@ func (conv *Converter) Capabilities() consumer.Capabilities {
 // This is reusing the logic from the OpenTelemetry Collector "contrib"
 // distribution and its LogsToLokiRequests function.
 func (conv *Converter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	conv.mut.RLock()
+	rules := conv.rules
+	conv.mut.RUnlock()
+
 	var entries []loki.Entry
 
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
+		resource := rls.At(i).Resource()
+
+		// The rules are applied to copies, as the logs must not be mutated.
+		var resourceMetadata push.LabelsAdapter
+		if rules.resourceRules() {
+			resource = copyResource(resource)
+			resourceMetadata = applyRules(resource.Attributes(), hintResourceLabels, rules.ResourceLabels, rules.ResourceMetadata)
+		}
+
 		ills := rls.At(i).ScopeLogs()
 		for j := 0; j < ills.Len(); j++ {
 			logs := ills.At(j).LogRecords()
@@ -67,10 +109,18 @@ func (conv *Converter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 			for k := 0; k < logs.Len(); k++ {
 				conv.metrics.entriesTotal.Inc()
 
+				record := logs.At(k)
+				metadata := resourceMetadata
+				if rules.attributeRules() {
+					record = copyLogRecord(record)
+					metadata = append(metadata[:len(metadata):len(metadata)],
+						applyRules(record.Attributes(), hintAttributeLabels, rules.AttributeLabels, rules.AttributeMetadata)...)
+				}
+
 				// TODO: loki added a parameter `defaultLabelsEnabled` to this function to add the possibility to disable default labels (exporter, job, instance, level)
 				// Is this interesting for us in any ways? (@wildum)
 				// https://github.com/open-telemetry/opentelemetry-collector-contrib/pull/23863/files#diff-ef7831fcba373f6e8aa7f799b5b89f4e113b2064cd7ef1688286ce193d2256a8
-				entry, err := loki_translator.LogToLokiEntry(logs.At(k), rls.At(i).Resource(), scope, nil)
+				entry, err := loki_translator.LogToLokiEntry(record, resource, scope, nil)
 				if err != nil {
 					level.Error(conv.log).Log("msg", "failed to convert log to loki entry", "err", err)
 					conv.metrics.entriesFailed.Inc()
@@ -78,6 +128,9 @@ func (conv *Converter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 				}
 
 				conv.metrics.entriesProcessed.Inc()
+				if len(metadata) > 0 {
+					entry.Entry.StructuredMetadata = metadata
+				}
 				entries = append(entries, loki.Entry{
 					Labels: entry.Labels,
 					Entry:  *entry.Entry,
@@ -108,3 +161,59 @@ func (conv *Converter) UpdateFanout(fanout []loki.LogsReceiver) {
 
 	conv.next = fanout
 }
+
+// UpdateRules sets the rules which select the attributes to convert to Loki
+// labels and structured metadata.
+func (conv *Converter) UpdateRules(rules Rules) {
+	conv.mut.Lock()
+	defer conv.mut.Unlock()
+
+	conv.rules = rules
+}
+
+// applyRules adds the labels to the hint attribute of attrs, and moves the
+// metadata attributes out of attrs, so they aren't part of the log line. It
+// returns the structured metadata, whose names are translated like labels.
+func applyRules(attrs pcommon.Map, hint string, labels, metadata []string) push.LabelsAdapter {
+	if len(labels) > 0 {
+		// Like the translator, accept hints which are either a comma-separated
+		// list or a slice of attribute names.
+		existing, ok := attrs.Get(hint)
+		switch {
+		case ok && existing.Type() == pcommon.ValueTypeSlice:
+			for _, name := range labels {
+				existing.Slice().AppendEmpty().SetStr(name)
+			}
+		case ok && existing.AsString() != "":
+			attrs.PutStr(hint, existing.AsString()+","+strings.Join(labels, ","))
+		default:
+			attrs.PutStr(hint, strings.Join(labels, ","))
+		}
+	}
+
+	var res push.LabelsAdapter
+	for _, name := range metadata {
+		value, ok := attrs.Get(name)
+		if !ok {
+			continue
+		}
+		res = append(res, push.LabelAdapter{
+			Name:  prometheus_translator.NormalizeLabel(name),
+			Value: value.AsString(),
+		})
+		attrs.Remove(name)
+	}
+	return res
+}
+
+func copyResource(from pcommon.Resource) pcommon.Resource {
+	to := pcommon.NewResource()
+	from.CopyTo(to)
+	return to
+}
+
+func copyLogRecord(from plog.LogRecord) plog.LogRecord {
+	to := plog.NewLogRecord()
+	from.CopyTo(to)
+	return to
+}
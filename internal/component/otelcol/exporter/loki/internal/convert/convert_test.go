@@ -340,6 +340,94 @@ func TestConsumeLogs(t *testing.T) {
 	}
 }
 
+func TestConsumeLogs_Rules(t *testing.T) {
+	inputLogJson := `{
+		"resourceLogs": [{
+			"resource": {
+				"attributes": [{
+					"key": "service.name",
+					"value": { "stringValue": "auth" }
+				},
+				{
+					"key": "k8s.pod.name",
+					"value": { "stringValue": "auth-7d4f9" }
+				}]
+			},
+			"scopeLogs": [{
+				"log_records": [{
+					"timeUnixNano": "1581452773000000111",
+					"severityNumber": 9,
+					"severityText": "Info",
+					"name": "logA",
+					"body": { "stringValue": "AUTH log message" },
+					"attributes": [{
+						"key": "event.name",
+						"value": { "stringValue": "login" }
+					},
+					{
+						"key": "user.id",
+						"value": { "stringValue": "42" }
+					},
+					{
+						"key": "attr.1",
+						"value": { "stringValue": "12345" }
+					},
+					{
+						"key": "attr.2",
+						"value": { "stringValue": "fake_token" }
+					},
+					{
+						"key": "loki.attribute.labels",
+						"value": { "stringValue": "attr.1" }
+					}]
+				}]
+			}]
+		}]
+	}`
+
+	receiver := loki.NewLogsReceiverWithChannel(make(chan loki.Entry, 1))
+	converter := convert.New(util.TestAlloyLogger(t), prometheus.NewRegistry(), []loki.LogsReceiver{receiver})
+	converter.UpdateRules(convert.Rules{
+		ResourceLabels:    []string{"service.name"},
+		AttributeLabels:   []string{"event.name"},
+		ResourceMetadata:  []string{"k8s.pod.name"},
+		AttributeMetadata: []string{"user.id", "missing"},
+	})
+
+	log := processortest.CreateTestLogs(inputLogJson)
+	require.NoError(t, converter.ConsumeLogs(context.Background(), log))
+
+	expectedEntry := loki.Entry{
+		Labels: map[model.LabelName]model.LabelValue{
+			"exporter":     model.LabelValue("OTLP"),
+			"job":          model.LabelValue("auth"),
+			"level":        model.LabelValue("INFO"),
+			"service_name": model.LabelValue("auth"),
+			"event_name":   model.LabelValue("login"),
+			"attr_1":       model.LabelValue("12345"),
+		},
+		Entry: push.Entry{
+			Timestamp: time.Unix(0, int64(1581452773000000111)),
+			Line:      `{"body":"AUTH log message","severity":"Info","attributes":{"attr.2":"fake_token"}}`,
+			StructuredMetadata: push.LabelsAdapter{
+				{Name: "k8s_pod_name", Value: "auth-7d4f9"},
+				{Name: "user_id", Value: "42"},
+			},
+		},
+	}
+	entry := <-receiver.Chan()
+	compareLokiEntries(t, &expectedEntry, &entry)
+
+	// The rules must not modify the logs.
+	attrs := log.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	hint, _ := attrs.Get("loki.attribute.labels")
+	require.Equal(t, "attr.1", hint.AsString())
+	_, ok := attrs.Get("user.id")
+	require.True(t, ok)
+	_, ok = log.ResourceLogs().At(0).Resource().Attributes().Get("loki.resource.labels")
+	require.False(t, ok)
+}
+
 // Compare two loki entries by converting them to json strings.
 func compareLokiEntries(t *testing.T, expectedEntry, actualEntry *loki.Entry) {
 	expectedStream := entryToStream(expectedEntry)
@@ -3,6 +3,7 @@ package loki
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component"
@@ -29,6 +30,55 @@ func init() {
 // Arguments configures the otelcol.exporter.loki component.
 type Arguments struct {
 	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
+
+	// Labels and StructuredMetadata select the attributes to convert, in
+	// addition to the attributes selected by hint attributes.
+	Labels             AttributesArguments `alloy:"labels,block,optional"`
+	StructuredMetadata AttributesArguments `alloy:"structured_metadata,block,optional"`
+}
+
+// AttributesArguments selects OTLP resource and log attributes by name.
+type AttributesArguments struct {
+	ResourceAttributes []string `alloy:"resource_attributes,attr,optional"`
+	Attributes         []string `alloy:"attributes,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if err := validateAttributes("resource_attributes", args.Labels.ResourceAttributes, args.StructuredMetadata.ResourceAttributes); err != nil {
+		return err
+	}
+	return validateAttributes("attributes", args.Labels.Attributes, args.StructuredMetadata.Attributes)
+}
+
+// validateAttributes checks that attributes aren't empty, and aren't
+// converted to both a label and structured metadata.
+func validateAttributes(argument string, labels, metadata []string) error {
+	selected := make(map[string]struct{}, len(labels))
+	for _, name := range labels {
+		if name == "" {
+			return fmt.Errorf("labels %s must not contain empty names", argument)
+		}
+		selected[name] = struct{}{}
+	}
+	for _, name := range metadata {
+		if name == "" {
+			return fmt.Errorf("structured_metadata %s must not contain empty names", argument)
+		}
+		if _, ok := selected[name]; ok {
+			return fmt.Errorf("attribute %q can't be in both labels and structured_metadata %s", name, argument)
+		}
+	}
+	return nil
+}
+
+func (args Arguments) convertRules() convert.Rules {
+	return convert.Rules{
+		ResourceLabels:    args.Labels.ResourceAttributes,
+		AttributeLabels:   args.Labels.Attributes,
+		ResourceMetadata:  args.StructuredMetadata.ResourceAttributes,
+		AttributeMetadata: args.StructuredMetadata.Attributes,
+	}
 }
 
 // Component is the otelcol.exporter.loki component.
@@ -75,5 +125,6 @@ func (c *Component) Run(ctx context.Context) error {
 func (c *Component) Update(newConfig component.Arguments) error {
 	cfg := newConfig.(Arguments)
 	c.converter.UpdateFanout(cfg.ForwardTo)
+	c.converter.UpdateRules(cfg.convertRules())
 	return nil
 }
@@ -0,0 +1,70 @@
+package loki
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	cfg := `
+	forward_to = []
+
+	labels {
+		resource_attributes = ["service.name", "service.namespace"]
+		attributes          = ["event.name"]
+	}
+
+	structured_metadata {
+		resource_attributes = ["k8s.pod.name"]
+	}
+	`
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	rules := args.convertRules()
+	require.Equal(t, []string{"service.name", "service.namespace"}, rules.ResourceLabels)
+	require.Equal(t, []string{"event.name"}, rules.AttributeLabels)
+	require.Equal(t, []string{"k8s.pod.name"}, rules.ResourceMetadata)
+	require.Empty(t, rules.AttributeMetadata)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			testName: "empty_name",
+			cfg: `
+			forward_to = []
+			labels {
+				attributes = [""]
+			}
+			`,
+			errorMsg: "labels attributes must not contain empty names",
+		},
+		{
+			testName: "label_and_structured_metadata",
+			cfg: `
+			forward_to = []
+			labels {
+				resource_attributes = ["service.name"]
+			}
+			structured_metadata {
+				resource_attributes = ["service.name"]
+			}
+			`,
+			errorMsg: `attribute "service.name" can't be in both labels and structured_metadata resource_attributes`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args Arguments
+			require.EqualError(t, syntax.Unmarshal([]byte(tc.cfg), &args), tc.errorMsg)
+		})
+	}
+}
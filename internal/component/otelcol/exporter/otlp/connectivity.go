@@ -0,0 +1,59 @@
+package otlp
+
+import (
+	"context"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+	"google.golang.org/grpc/metadata"
+)
+
+var _ component.ConnectivityChecker = Arguments{}
+
+// CheckConnectivity implements component.ConnectivityChecker by exporting an
+// empty batch of a signal to each of the endpoints of the signals. An endpoint
+// which is used by several signals is checked once.
+func (args Arguments) CheckConnectivity(ctx context.Context) []component.ConnectivityResult {
+	var (
+		results []component.ConnectivityResult
+		checked = make(map[string]struct{})
+	)
+	for _, signal := range []struct {
+		args  *SignalArguments
+		check checkFunc
+	}{
+		{args.Traces, checkTraces},
+		{args.Metrics, checkMetrics},
+		{args.Logs, checkLogs},
+	} {
+		for _, endpoint := range args.endpoints(signal.args) {
+			if _, ok := checked[endpoint]; ok {
+				continue
+			}
+			checked[endpoint] = struct{}{}
+			results = append(results, component.ConnectivityResult{
+				Endpoint: endpoint,
+				Err:      args.checkConnectivity(ctx, endpoint, signal.check),
+			})
+		}
+	}
+	return results
+}
+
+func (args Arguments) checkConnectivity(ctx context.Context, endpoint string, check checkFunc) error {
+	host, settings := exporter.ConnectivitySettings(args)
+	client := (*otelcol.GRPCClientArguments)(&args.Client).Convert()
+	client.Endpoint = endpoint
+	conn, err := client.ToClientConn(ctx, host, settings)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, args.Timeout)
+	defer cancel()
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(args.Client.Headers))
+
+	return check(ctx, conn)
+}
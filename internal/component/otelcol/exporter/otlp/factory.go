@@ -0,0 +1,102 @@
+package otlp
+
+import (
+	"context"
+	"time"
+
+	otelcomponent "go.opentelemetry.io/collector/component"
+	otelexporter "go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+// Config is the configuration of the exporters created by the factory of the
+// component.
+type Config struct {
+	Upstream otlpexporter.Config
+
+	// TracesEndpoints, MetricsEndpoints, and LogsEndpoints are the endpoints of
+	// each signal, in order of preference. The endpoint of Upstream is used
+	// when they're empty.
+	TracesEndpoints  []string
+	MetricsEndpoints []string
+	LogsEndpoints    []string
+
+	// HealthCheckInterval is the interval between the health checks of the
+	// endpoints of a signal which has more than one endpoint.
+	HealthCheckInterval time.Duration
+}
+
+// endpoints returns the endpoints of a signal.
+func (c *Config) endpoints(signal []string) []string {
+	if len(signal) == 0 {
+		return []string{c.Upstream.ClientConfig.Endpoint}
+	}
+	return signal
+}
+
+// exporterConfig returns the configuration of the upstream exporter of an
+// endpoint.
+func (c *Config) exporterConfig(endpoint string) *otlpexporter.Config {
+	cfg := c.Upstream
+	cfg.ClientConfig.Endpoint = endpoint
+	return &cfg
+}
+
+// newFactory returns an exporter factory which creates an upstream otlp
+// exporter for each signal with a single endpoint, and a failover exporter
+// over an upstream otlp exporter per endpoint otherwise.
+func newFactory() otelexporter.Factory {
+	upstream := otlpexporter.NewFactory()
+
+	return otelexporter.NewFactory(
+		upstream.Type(),
+		func() otelcomponent.Config {
+			return &Config{
+				Upstream:            *upstream.CreateDefaultConfig().(*otlpexporter.Config),
+				HealthCheckInterval: DefaultHealthCheckInterval,
+			}
+		},
+		otelexporter.WithTraces(func(ctx context.Context, set otelexporter.CreateSettings, cfg otelcomponent.Config) (otelexporter.Traces, error) {
+			c := cfg.(*Config)
+			endpoints := c.endpoints(c.TracesEndpoints)
+			if len(endpoints) == 1 {
+				return upstream.CreateTracesExporter(ctx, set, c.exporterConfig(endpoints[0]))
+			}
+			f, err := newFailover(set, c, endpoints, checkTraces, func(cfg *otlpexporter.Config) (otelcomponent.Component, error) {
+				return upstream.CreateTracesExporter(ctx, set, cfg)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return tracesFailover{f}, nil
+		}, upstream.TracesExporterStability()),
+		otelexporter.WithMetrics(func(ctx context.Context, set otelexporter.CreateSettings, cfg otelcomponent.Config) (otelexporter.Metrics, error) {
+			c := cfg.(*Config)
+			endpoints := c.endpoints(c.MetricsEndpoints)
+			if len(endpoints) == 1 {
+				return upstream.CreateMetricsExporter(ctx, set, c.exporterConfig(endpoints[0]))
+			}
+			f, err := newFailover(set, c, endpoints, checkMetrics, func(cfg *otlpexporter.Config) (otelcomponent.Component, error) {
+				return upstream.CreateMetricsExporter(ctx, set, cfg)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return metricsFailover{f}, nil
+		}, upstream.MetricsExporterStability()),
+		otelexporter.WithLogs(func(ctx context.Context, set otelexporter.CreateSettings, cfg otelcomponent.Config) (otelexporter.Logs, error) {
+			c := cfg.(*Config)
+			endpoints := c.endpoints(c.LogsEndpoints)
+			if len(endpoints) == 1 {
+				return upstream.CreateLogsExporter(ctx, set, c.exporterConfig(endpoints[0]))
+			}
+			f, err := newFailover(set, c, endpoints, checkLogs, func(cfg *otlpexporter.Config) (otelcomponent.Component, error) {
+				return upstream.CreateLogsExporter(ctx, set, cfg)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return logsFailover{f}, nil
+		}, upstream.LogsExporterStability()),
+	)
+}
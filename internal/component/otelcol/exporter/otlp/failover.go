@@ -0,0 +1,242 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	otelexporter "go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// checkFunc checks the health of an endpoint by exporting an empty request of
+// a signal to it.
+type checkFunc func(ctx context.Context, conn *grpc.ClientConn) error
+
+func checkTraces(ctx context.Context, conn *grpc.ClientConn) error {
+	_, err := ptraceotlp.NewGRPCClient(conn).Export(ctx, ptraceotlp.NewExportRequest())
+	return err
+}
+
+func checkMetrics(ctx context.Context, conn *grpc.ClientConn) error {
+	_, err := pmetricotlp.NewGRPCClient(conn).Export(ctx, pmetricotlp.NewExportRequest())
+	return err
+}
+
+func checkLogs(ctx context.Context, conn *grpc.ClientConn) error {
+	_, err := plogotlp.NewGRPCClient(conn).Export(ctx, plogotlp.NewExportRequest())
+	return err
+}
+
+// failover exports to the first healthy endpoint of a list, with an upstream
+// exporter per endpoint.
+//
+// An endpoint becomes unhealthy when an export to it fails, or when its health
+// check fails. The health of every endpoint is checked periodically, so that
+// the data goes back to the preferred endpoints once they recover.
+type failover struct {
+	logger   *zap.Logger
+	settings otelcomponent.TelemetrySettings
+	interval time.Duration
+	timeout  time.Duration
+	check    checkFunc
+	members  []*member
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// member is an endpoint of a failover exporter.
+type member struct {
+	endpoint string
+	config   *otlpexporter.Config
+	exporter otelcomponent.Component
+	healthy  atomic.Bool
+
+	// conn is the connection used for the health checks of the endpoint.
+	conn *grpc.ClientConn
+}
+
+func newFailover(set otelexporter.CreateSettings, c *Config, endpoints []string, check checkFunc, create func(cfg *otlpexporter.Config) (otelcomponent.Component, error)) (*failover, error) {
+	f := &failover{
+		logger:   set.Logger,
+		settings: set.TelemetrySettings,
+		interval: c.HealthCheckInterval,
+		timeout:  c.Upstream.Timeout,
+		check:    check,
+	}
+	for _, endpoint := range endpoints {
+		cfg := c.exporterConfig(endpoint)
+		exp, err := create(cfg)
+		if err != nil {
+			return nil, err
+		}
+		f.members = append(f.members, &member{endpoint: endpoint, config: cfg, exporter: exp})
+	}
+	return f, nil
+}
+
+// Start implements otelcomponent.Component.
+func (f *failover) Start(ctx context.Context, host otelcomponent.Host) error {
+	for _, m := range f.members {
+		if err := m.exporter.Start(ctx, host); err != nil {
+			return err
+		}
+		conn, err := m.config.ClientConfig.ToClientConn(ctx, host, f.settings)
+		if err != nil {
+			return err
+		}
+		m.conn = conn
+		m.healthy.Store(true)
+	}
+
+	ctx, f.cancel = context.WithCancel(context.Background())
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.run(ctx)
+	}()
+	return nil
+}
+
+// Shutdown implements otelcomponent.Component.
+func (f *failover) Shutdown(ctx context.Context) error {
+	if f.cancel != nil {
+		f.cancel()
+		f.wg.Wait()
+	}
+
+	var errs []error
+	for _, m := range f.members {
+		errs = append(errs, m.exporter.Shutdown(ctx))
+		if m.conn != nil {
+			errs = append(errs, m.conn.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Capabilities implements consumer.Traces, consumer.Metrics, and
+// consumer.Logs.
+func (f *failover) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// run checks the health of the endpoints when it starts, and then on every
+// interval until ctx is canceled.
+func (f *failover) run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, m := range f.members {
+			f.setHealthy(m, f.checkHealth(ctx, m))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *failover) checkHealth(ctx context.Context, m *member) error {
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	md := metadata.MD{}
+	for name, value := range m.config.ClientConfig.Headers {
+		md.Set(name, string(value))
+	}
+	return f.check(metadata.NewOutgoingContext(ctx, md), m.conn)
+}
+
+// setHealthy updates the health of m from the error of its last export or
+// health check, and logs its changes.
+func (f *failover) setHealthy(m *member, err error) {
+	healthy := err == nil
+	if m.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		f.logger.Info("endpoint is healthy again", zap.String("endpoint", m.endpoint))
+	} else {
+		f.logger.Warn("endpoint is unhealthy, failing over to the next endpoint", zap.String("endpoint", m.endpoint), zap.Error(err))
+	}
+}
+
+// consume sends data to the first healthy endpoint with send, and to the next
+// healthy endpoints when it fails. Permanent errors aren't retried, as the
+// other endpoints would reject the data too.
+//
+// When no endpoint is healthy, the data is sent to the first endpoint, whose
+// exporter queues or retries it.
+func (f *failover) consume(send func(exp otelcomponent.Component) error) error {
+	var errs []error
+	for _, m := range f.members {
+		if !m.healthy.Load() {
+			continue
+		}
+		err := send(m.exporter)
+		if err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+		f.setHealthy(m, err)
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return send(f.members[0].exporter)
+}
+
+type tracesFailover struct{ *failover }
+
+var _ otelexporter.Traces = tracesFailover{}
+
+// ConsumeTraces implements consumer.Traces.
+func (f tracesFailover) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return f.consume(func(exp otelcomponent.Component) error {
+		return exp.(otelexporter.Traces).ConsumeTraces(ctx, td)
+	})
+}
+
+type metricsFailover struct{ *failover }
+
+var _ otelexporter.Metrics = metricsFailover{}
+
+// ConsumeMetrics implements consumer.Metrics.
+func (f metricsFailover) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return f.consume(func(exp otelcomponent.Component) error {
+		return exp.(otelexporter.Metrics).ConsumeMetrics(ctx, md)
+	})
+}
+
+type logsFailover struct{ *failover }
+
+var _ otelexporter.Logs = logsFailover{}
+
+// ConsumeLogs implements consumer.Logs.
+func (f logsFailover) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return f.consume(func(exp otelcomponent.Component) error {
+		return exp.(otelexporter.Logs).ConsumeLogs(ctx, ld)
+	})
+}
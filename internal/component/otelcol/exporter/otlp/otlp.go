@@ -2,6 +2,8 @@
 package otlp
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/grafana/alloy/internal/component"
@@ -23,8 +25,7 @@ func init() {
 		Exports:   otelcol.ConsumerExports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			fact := otlpexporter.NewFactory()
-			return exporter.New(opts, fact, args.(Arguments), exporter.TypeAll)
+			return exporter.New(opts, newFactory(), args.(Arguments), exporter.TypeAll)
 		},
 	})
 }
@@ -40,10 +41,54 @@ type Arguments struct {
 	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
 
 	Client GRPCClientArguments `alloy:"client,block"`
+
+	// Failover configures the endpoints to export to when the endpoint of the
+	// client is unhealthy. Optional.
+	Failover *FailoverArguments `alloy:"failover,block,optional"`
+
+	// Traces, Metrics, and Logs override the endpoints of a signal. Optional.
+	Traces  *SignalArguments `alloy:"traces,block,optional"`
+	Metrics *SignalArguments `alloy:"metrics,block,optional"`
+	Logs    *SignalArguments `alloy:"logs,block,optional"`
 }
 
 var _ exporter.Arguments = Arguments{}
 
+// DefaultHealthCheckInterval is the default interval between the health
+// checks of the endpoints when failover endpoints are configured.
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// FailoverArguments configures the failover endpoints of the exporter.
+type FailoverArguments struct {
+	// Endpoints to export to, in order, when the endpoints before them are
+	// unhealthy.
+	Endpoints []string `alloy:"endpoints,attr"`
+
+	HealthCheckInterval time.Duration `alloy:"health_check_interval,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *FailoverArguments) SetToDefault() {
+	*args = FailoverArguments{
+		HealthCheckInterval: DefaultHealthCheckInterval,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *FailoverArguments) Validate() error {
+	if args.HealthCheckInterval <= 0 {
+		return fmt.Errorf("health_check_interval must be greater than 0")
+	}
+	return nil
+}
+
+// SignalArguments configures the endpoints of a signal. They replace the
+// endpoint of the client and the failover endpoints for that signal.
+type SignalArguments struct {
+	Endpoint          string   `alloy:"endpoint,attr"`
+	FailoverEndpoints []string `alloy:"failover_endpoints,attr,optional"`
+}
+
 // SetToDefault implements syntax.Defaulter.
 func (args *Arguments) SetToDefault() {
 	*args = Arguments{
@@ -56,15 +101,65 @@ func (args *Arguments) SetToDefault() {
 	args.DebugMetrics.SetToDefault()
 }
 
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	var errs []error
+	for _, signal := range []struct {
+		name string
+		args *SignalArguments
+	}{
+		{"traces", args.Traces},
+		{"metrics", args.Metrics},
+		{"logs", args.Logs},
+	} {
+		seen := make(map[string]struct{})
+		for _, endpoint := range args.endpoints(signal.args) {
+			if endpoint == "" {
+				errs = append(errs, fmt.Errorf("the endpoints of %s must not be empty", signal.name))
+				continue
+			}
+			if _, ok := seen[endpoint]; ok {
+				errs = append(errs, fmt.Errorf("endpoint %q is used more than once for %s", endpoint, signal.name))
+			}
+			seen[endpoint] = struct{}{}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// endpoints returns the endpoints of a signal, in order of preference. The
+// endpoints of signal are used if it's not nil.
+func (args Arguments) endpoints(signal *SignalArguments) []string {
+	if signal != nil {
+		return append([]string{signal.Endpoint}, signal.FailoverEndpoints...)
+	}
+	endpoints := []string{args.Client.Endpoint}
+	if args.Failover != nil {
+		endpoints = append(endpoints, args.Failover.Endpoints...)
+	}
+	return endpoints
+}
+
 // Convert implements exporter.Arguments.
 func (args Arguments) Convert() (otelcomponent.Config, error) {
-	return &otlpexporter.Config{
-		TimeoutSettings: otelpexporterhelper.TimeoutSettings{
-			Timeout: args.Timeout,
+	healthCheckInterval := DefaultHealthCheckInterval
+	if args.Failover != nil {
+		healthCheckInterval = args.Failover.HealthCheckInterval
+	}
+
+	return &Config{
+		Upstream: otlpexporter.Config{
+			TimeoutSettings: otelpexporterhelper.TimeoutSettings{
+				Timeout: args.Timeout,
+			},
+			QueueConfig:  *args.Queue.Convert(),
+			RetryConfig:  *args.Retry.Convert(),
+			ClientConfig: *(*otelcol.GRPCClientArguments)(&args.Client).Convert(),
 		},
-		QueueConfig:  *args.Queue.Convert(),
-		RetryConfig:  *args.Retry.Convert(),
-		ClientConfig: *(*otelcol.GRPCClientArguments)(&args.Client).Convert(),
+		TracesEndpoints:     args.endpoints(args.Traces),
+		MetricsEndpoints:    args.endpoints(args.Metrics),
+		LogsEndpoints:       args.endpoints(args.Logs),
+		HealthCheckInterval: healthCheckInterval,
 	}, nil
 }
 
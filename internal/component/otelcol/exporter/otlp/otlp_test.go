@@ -92,6 +92,176 @@ func Test(t *testing.T) {
 	}
 }
 
+// TestFailover ensures that traces are exported to the failover endpoint when
+// the endpoint of the client is unreachable.
+func TestFailover(t *testing.T) {
+	traceCh := make(chan ptrace.Traces)
+	tracesServer := makeTracesServer(t, traceCh)
+
+	// Reserve an address which nothing listens to.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	ctx := componenttest.TestContext(t)
+	l := util.TestLogger(t)
+
+	ctrl, err := componenttest.NewControllerFromID(l, "otelcol.exporter.otlp")
+	require.NoError(t, err)
+
+	cfg := fmt.Sprintf(`
+		timeout = "250ms"
+
+		client {
+			endpoint = "%s"
+
+			compression = "none"
+
+			tls {
+				insecure             = true
+				insecure_skip_verify = true
+			}
+		}
+
+		failover {
+			endpoints = ["%s"]
+		}
+
+		sending_queue {
+			enabled = false
+		}
+
+		retry_on_failure {
+			enabled = false
+		}
+	`, unreachable, tracesServer)
+	var args otlp.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	go func() {
+		err := ctrl.Run(ctx, args)
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, ctrl.WaitRunning(time.Second), "component never started")
+	require.NoError(t, ctrl.WaitExports(time.Second), "component never exported anything")
+
+	go func() {
+		exports := ctrl.Exports().(otelcol.ConsumerExports)
+
+		bo := backoff.New(ctx, backoff.Config{
+			MinBackoff: 10 * time.Millisecond,
+			MaxBackoff: 100 * time.Millisecond,
+		})
+		for bo.Ongoing() {
+			err := exports.Input.ConsumeTraces(ctx, createTestTraces())
+			if err != nil {
+				level.Error(l).Log("msg", "failed to send traces", "err", err)
+				bo.Wait()
+				continue
+			}
+
+			return
+		}
+	}()
+
+	// The health checks of the failover endpoint export empty traces, which
+	// are skipped.
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			require.FailNow(t, "failed waiting for traces")
+		case tr := <-traceCh:
+			if tr.SpanCount() == 0 {
+				continue
+			}
+			require.Equal(t, 1, tr.SpanCount())
+			return
+		}
+	}
+}
+
+func TestArguments_Endpoints(t *testing.T) {
+	cfg := `
+		client {
+			endpoint = "primary:4317"
+		}
+
+		failover {
+			endpoints             = ["secondary:4317"]
+			health_check_interval = "10s"
+		}
+
+		traces {
+			endpoint           = "tempo:4317"
+			failover_endpoints = ["tempo-backup:4317"]
+		}
+
+		logs {
+			endpoint = "loki:4317"
+		}
+	`
+	var args otlp.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	converted, err := args.Convert()
+	require.NoError(t, err)
+	otelCfg := converted.(*otlp.Config)
+
+	require.Equal(t, "primary:4317", otelCfg.Upstream.ClientConfig.Endpoint)
+	require.Equal(t, []string{"tempo:4317", "tempo-backup:4317"}, otelCfg.TracesEndpoints)
+	require.Equal(t, []string{"primary:4317", "secondary:4317"}, otelCfg.MetricsEndpoints)
+	require.Equal(t, []string{"loki:4317"}, otelCfg.LogsEndpoints)
+	require.Equal(t, 10*time.Second, otelCfg.HealthCheckInterval)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		testName string
+		alloyCfg string
+		errorMsg string
+	}{
+		{
+			testName: "duplicate failover endpoint",
+			alloyCfg: `
+			client {
+				endpoint = "primary:4317"
+			}
+			failover {
+				endpoints = ["secondary:4317"]
+			}
+			traces {
+				endpoint           = "tempo:4317"
+				failover_endpoints = ["tempo:4317"]
+			}
+			`,
+			errorMsg: `endpoint "tempo:4317" is used more than once for traces`,
+		},
+		{
+			testName: "invalid health check interval",
+			alloyCfg: `
+			client {
+				endpoint = "primary:4317"
+			}
+			failover {
+				endpoints             = ["secondary:4317"]
+				health_check_interval = "0s"
+			}
+			`,
+			errorMsg: "health_check_interval must be greater than 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args otlp.Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tc.alloyCfg), &args), tc.errorMsg)
+		})
+	}
+}
+
 // makeTracesServer returns a host:port which will accept traces over insecure
 // gRPC.
 func makeTracesServer(t *testing.T, ch chan ptrace.Traces) string {
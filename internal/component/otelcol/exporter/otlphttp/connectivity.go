@@ -0,0 +1,77 @@
+package otlphttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+)
+
+var _ component.ConnectivityChecker = Arguments{}
+
+// CheckConnectivity implements component.ConnectivityChecker by exporting an
+// empty request to the endpoint of the first configured signal, which is
+// traces unless only the endpoint of another signal is set.
+func (args Arguments) CheckConnectivity(ctx context.Context) []component.ConnectivityResult {
+	endpoint := args.checkEndpoint()
+	res := component.ConnectivityResult{
+		Endpoint: endpoint,
+		Err:      args.checkConnectivity(ctx, endpoint),
+	}
+	if u, err := url.Parse(endpoint); err == nil {
+		res.Endpoint = u.Redacted()
+	}
+	return []component.ConnectivityResult{res}
+}
+
+func (args Arguments) checkEndpoint() string {
+	switch {
+	case args.TracesEndpoint != "":
+		return args.TracesEndpoint
+	case args.Client.Endpoint != "":
+		return strings.TrimSuffix(args.Client.Endpoint, "/") + "/v1/traces"
+	case args.MetricsEndpoint != "":
+		return args.MetricsEndpoint
+	default:
+		return args.LogsEndpoint
+	}
+}
+
+func (args Arguments) checkConnectivity(ctx context.Context, endpoint string) error {
+	host, settings := exporter.ConnectivitySettings(args)
+	client, err := (*otelcol.HTTPClientArguments)(&args.Client).Convert().ToClient(ctx, host, settings)
+	if err != nil {
+		return err
+	}
+
+	// Empty export requests of every signal are encoded the same way.
+	body, contentType := []byte{}, "application/x-protobuf"
+	if args.Encoding == EncodingJSON {
+		body, contentType = []byte("{}"), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("server returned HTTP status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -348,6 +348,12 @@ func writeSeries(app storage.Appender, series *memorySeries, dp otelcolDataPoint
 
 func (conv *Converter) writeExemplar(app storage.Appender, series *memorySeries, otelExemplar pmetric.Exemplar) error {
 	ts := otelExemplar.Timestamp().AsTime()
+	if otelExemplar.Timestamp() == 0 {
+		// Exemplars without a timestamp, such as the ones generated by
+		// otelcol.connector.spanmetrics, are attached to the sample they
+		// belong to.
+		ts = series.Timestamp()
+	}
 	if ts.Before(series.Timestamp()) {
 		// Out-of-order; skip.
 		return nil
@@ -705,6 +705,48 @@ func TestConverter(t *testing.T) {
 			`,
 			enableOpenMetrics: true,
 		},
+		{
+			name: "Histogram: exemplars without timestamp",
+			input: `{
+				"resource_metrics": [{
+					"scope_metrics": [{
+						"metrics": [{
+							"name": "test_metric",
+							"unit": "seconds",
+							"histogram": {
+								"aggregation_temporality": 2,
+								"data_points": [{
+									"start_time_unix_nano": 1000000000,
+									"time_unix_nano": 1000000000,
+									"count": 333,
+									"sum": 100,
+									"bucket_counts": [0, 111, 0, 222],
+									"explicit_bounds": [0.25, 0.5, 0.75, 1.0],
+									"exemplars":[
+										{
+											"as_double": 0.3,
+											"span_id": "aaaaaaaaaaaaaaaa",
+											"trace_id": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+										}
+									]
+								}]
+							}
+						}]
+					}]
+				}]
+			}`,
+			expect: `
+				# TYPE test_metric histogram
+				test_metric_bucket{le="0.25"} 0
+				test_metric_bucket{le="0.5"} 111 # {span_id="aaaaaaaaaaaaaaaa",trace_id="aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"} 0.3
+				test_metric_bucket{le="0.75"} 111
+				test_metric_bucket{le="1.0"} 333
+				test_metric_bucket{le="+Inf"} 333
+				test_metric_sum 100.0
+				test_metric_count 333
+			`,
+			enableOpenMetrics: true,
+		},
 		{
 			name: "Histogram: add_metric_suffixes = true",
 			input: `{
@@ -0,0 +1,184 @@
+// Package prometheusremotewrite provides an otelcol.exporter.prometheusremotewrite component.
+package prometheusremotewrite
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	otelpexporterhelper "go.opentelemetry.io/collector/exporter/exporterhelper"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.exporter.prometheusremotewrite",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := prometheusremotewriteexporter.NewFactory()
+			return exporter.New(opts, fact, args.(Arguments), exporter.TypeMetrics)
+		},
+	})
+}
+
+// Arguments configures the otelcol.exporter.prometheusremotewrite component.
+type Arguments struct {
+	Timeout time.Duration `alloy:"timeout,attr,optional"`
+
+	Namespace                     string                    `alloy:"namespace,attr,optional"`
+	ExternalLabels                map[string]string         `alloy:"external_labels,attr,optional"`
+	AddMetricSuffixes             bool                      `alloy:"add_metric_suffixes,attr,optional"`
+	SendMetadata                  bool                      `alloy:"send_metadata,attr,optional"`
+	ResourceToTelemetryConversion bool                      `alloy:"resource_to_telemetry_conversion,attr,optional"`
+	MaxBatchSizeBytes             int                       `alloy:"max_batch_size_bytes,attr,optional"`
+	TargetInfoEnabled             bool                      `alloy:"target_info_enabled,attr,optional"`
+	ExportCreatedMetricEnabled    bool                      `alloy:"export_created_metric_enabled,attr,optional"`
+	Client                        HTTPClientArguments       `alloy:"client,block"`
+	RemoteWriteQueue              RemoteWriteQueueArguments `alloy:"remote_write_queue,block,optional"`
+	Retry                         otelcol.RetryArguments    `alloy:"retry_on_failure,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var _ exporter.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Timeout:           otelcol.DefaultTimeout,
+		AddMetricSuffixes: true,
+		MaxBatchSizeBytes: 3000000,
+		TargetInfoEnabled: true,
+	}
+
+	args.Client.SetToDefault()
+	args.RemoteWriteQueue.SetToDefault()
+	args.Retry.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.MaxBatchSizeBytes <= 0 {
+		return errors.New("max_batch_size_bytes must be greater than 0")
+	}
+	switch args.Client.Compression {
+	case otelcol.CompressionTypeEmpty, otelcol.CompressionTypeNone:
+	default:
+		return fmt.Errorf("compression %q is not supported, requests are always compressed with snappy", args.Client.Compression)
+	}
+	return nil
+}
+
+// Convert implements exporter.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	return &prometheusremotewriteexporter.Config{
+		TimeoutSettings: otelpexporterhelper.TimeoutSettings{
+			Timeout: args.Timeout,
+		},
+		BackOffConfig:  *args.Retry.Convert(),
+		Namespace:      args.Namespace,
+		ExternalLabels: args.ExternalLabels,
+		ClientConfig:   *(*otelcol.HTTPClientArguments)(&args.Client).Convert(),
+		RemoteWriteQueue: prometheusremotewriteexporter.RemoteWriteQueue{
+			Enabled:      args.RemoteWriteQueue.Enabled,
+			QueueSize:    args.RemoteWriteQueue.QueueSize,
+			NumConsumers: args.RemoteWriteQueue.NumConsumers,
+		},
+		ResourceToTelemetrySettings: resourcetotelemetry.Settings{
+			Enabled: args.ResourceToTelemetryConversion,
+		},
+		TargetInfo: &prometheusremotewriteexporter.TargetInfo{
+			Enabled: args.TargetInfoEnabled,
+		},
+		CreatedMetric: &prometheusremotewriteexporter.CreatedMetric{
+			Enabled: args.ExportCreatedMetricEnabled,
+		},
+		AddMetricSuffixes: args.AddMetricSuffixes,
+		SendMetadata:      args.SendMetadata,
+		MaxBatchSizeBytes: args.MaxBatchSizeBytes,
+	}, nil
+}
+
+// Extensions implements exporter.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return (*otelcol.HTTPClientArguments)(&args.Client).Extensions()
+}
+
+// Exporters implements exporter.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// DebugMetricsConfig implements exporter.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
+
+// RemoteWriteQueueArguments configures the queue of the requests sent to the
+// remote write endpoint.
+type RemoteWriteQueueArguments struct {
+	Enabled      bool `alloy:"enabled,attr,optional"`
+	QueueSize    int  `alloy:"queue_size,attr,optional"`
+	NumConsumers int  `alloy:"num_consumers,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *RemoteWriteQueueArguments) SetToDefault() {
+	*args = RemoteWriteQueueArguments{
+		Enabled:      true,
+		QueueSize:    10000,
+		NumConsumers: 5,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *RemoteWriteQueueArguments) Validate() error {
+	if args.QueueSize < 0 {
+		return errors.New("remote_write_queue queue_size can't be negative")
+	}
+	if args.Enabled && args.QueueSize == 0 {
+		return errors.New("remote_write_queue queue_size must be greater than 0 when the queue is enabled")
+	}
+	if args.NumConsumers < 0 {
+		return errors.New("remote_write_queue num_consumers can't be negative")
+	}
+	return nil
+}
+
+// HTTPClientArguments is used to configure
+// otelcol.exporter.prometheusremotewrite with component-specific defaults.
+type HTTPClientArguments otelcol.HTTPClientArguments
+
+// Default server settings.
+var (
+	DefaultMaxIdleConns    = 100
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *HTTPClientArguments) SetToDefault() {
+	maxIdleConns := DefaultMaxIdleConns
+	idleConnTimeout := DefaultIdleConnTimeout
+	*args = HTTPClientArguments{
+		MaxIdleConns:    &maxIdleConns,
+		IdleConnTimeout: &idleConnTimeout,
+
+		Timeout:          otelcol.DefaultTimeout,
+		Headers:          map[string]string{},
+		WriteBufferSize:  512 * 1024,
+		HTTP2PingTimeout: 15 * time.Second,
+	}
+}
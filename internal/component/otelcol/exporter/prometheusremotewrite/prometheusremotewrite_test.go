@@ -0,0 +1,137 @@
+package prometheusremotewrite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/prometheusremotewrite"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected func(t *testing.T, cfg *prometheusremotewriteexporter.Config)
+	}{
+		{
+			testName: "defaults",
+			cfg: `
+			client {
+				endpoint = "http://localhost:9009/api/v1/push"
+			}
+			`,
+			expected: func(t *testing.T, cfg *prometheusremotewriteexporter.Config) {
+				require.Equal(t, "http://localhost:9009/api/v1/push", cfg.ClientConfig.Endpoint)
+				require.Equal(t, 5*time.Second, cfg.TimeoutSettings.Timeout)
+				require.True(t, cfg.AddMetricSuffixes)
+				require.False(t, cfg.SendMetadata)
+				require.False(t, cfg.ResourceToTelemetrySettings.Enabled)
+				require.Equal(t, 3000000, cfg.MaxBatchSizeBytes)
+				require.True(t, cfg.TargetInfo.Enabled)
+				require.False(t, cfg.CreatedMetric.Enabled)
+				require.Equal(t, prometheusremotewriteexporter.RemoteWriteQueue{Enabled: true, QueueSize: 10000, NumConsumers: 5}, cfg.RemoteWriteQueue)
+				require.True(t, cfg.BackOffConfig.Enabled)
+				require.Nil(t, cfg.WAL)
+			},
+		},
+		{
+			testName: "overrides",
+			cfg: `
+			namespace                        = "otel"
+			external_labels                  = { cluster = "prod" }
+			add_metric_suffixes              = false
+			send_metadata                    = true
+			resource_to_telemetry_conversion = true
+			target_info_enabled              = false
+			export_created_metric_enabled    = true
+			max_batch_size_bytes             = 1000000
+
+			client {
+				endpoint = "http://localhost:9009/api/v1/push"
+			}
+
+			remote_write_queue {
+				enabled = false
+			}
+
+			retry_on_failure {
+				enabled = false
+			}
+			`,
+			expected: func(t *testing.T, cfg *prometheusremotewriteexporter.Config) {
+				require.Equal(t, "otel", cfg.Namespace)
+				require.Equal(t, map[string]string{"cluster": "prod"}, cfg.ExternalLabels)
+				require.False(t, cfg.AddMetricSuffixes)
+				require.True(t, cfg.SendMetadata)
+				require.True(t, cfg.ResourceToTelemetrySettings.Enabled)
+				require.False(t, cfg.TargetInfo.Enabled)
+				require.True(t, cfg.CreatedMetric.Enabled)
+				require.Equal(t, 1000000, cfg.MaxBatchSizeBytes)
+				require.False(t, cfg.RemoteWriteQueue.Enabled)
+				require.False(t, cfg.BackOffConfig.Enabled)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args prometheusremotewrite.Arguments
+			require.NoError(t, syntax.Unmarshal([]byte(tc.cfg), &args))
+
+			actual, err := args.Convert()
+			require.NoError(t, err)
+			tc.expected(t, actual.(*prometheusremotewriteexporter.Config))
+		})
+	}
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		testName    string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			testName: "compression",
+			cfg: `
+			client {
+				endpoint    = "http://localhost:9009/api/v1/push"
+				compression = "gzip"
+			}
+			`,
+			expectedErr: `compression "gzip" is not supported`,
+		},
+		{
+			testName: "max_batch_size_bytes",
+			cfg: `
+			max_batch_size_bytes = 0
+			client {
+				endpoint = "http://localhost:9009/api/v1/push"
+			}
+			`,
+			expectedErr: "max_batch_size_bytes must be greater than 0",
+		},
+		{
+			testName: "queue_size",
+			cfg: `
+			client {
+				endpoint = "http://localhost:9009/api/v1/push"
+			}
+			remote_write_queue {
+				queue_size = 0
+			}
+			`,
+			expectedErr: "queue_size must be greater than 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args prometheusremotewrite.Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tc.cfg), &args), tc.expectedErr)
+		})
+	}
+}
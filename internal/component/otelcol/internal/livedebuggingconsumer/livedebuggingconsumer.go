@@ -39,29 +39,102 @@ func (c *Consumer) Capabilities() otelconsumer.Capabilities {
 	return otelconsumer.Capabilities{MutatesData: false}
 }
 
-// ConsumeTraces implements otelcol.ConsumeTraces.
+// ConsumeTraces implements otelcol.ConsumeTraces. Every span is published as
+// a separate item, so that items can be sampled and filtered individually.
 func (c *Consumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
 	if c.debugDataPublisher.IsActive(c.componentID) {
-		data, _ := c.tracesMarshaler.MarshalTraces(td)
-		c.debugDataPublisher.Publish(c.componentID, string(data))
+		forEachSpan(td, func(span ptrace.Traces) {
+			data, _ := c.tracesMarshaler.MarshalTraces(span)
+			c.debugDataPublisher.Publish(c.componentID, string(data))
+		})
 	}
 	return nil
 }
 
-// ConsumeMetrics implements otelcol.ConsumeMetrics.
+// ConsumeMetrics implements otelcol.ConsumeMetrics. Every metric is published
+// as a separate item, with all its data points.
 func (c *Consumer) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
 	if c.debugDataPublisher.IsActive(c.componentID) {
-		data, _ := c.metricsMarshaler.MarshalMetrics(md)
-		c.debugDataPublisher.Publish(c.componentID, string(data))
+		forEachMetric(md, func(metric pmetric.Metrics) {
+			data, _ := c.metricsMarshaler.MarshalMetrics(metric)
+			c.debugDataPublisher.Publish(c.componentID, string(data))
+		})
 	}
 	return nil
 }
 
-// ConsumeLogs implements otelcol.ConsumeLogs.
+// ConsumeLogs implements otelcol.ConsumeLogs. Every log record is published
+// as a separate item.
 func (c *Consumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	if c.debugDataPublisher.IsActive(c.componentID) {
-		data, _ := c.logsMarshaler.MarshalLogs(ld)
-		c.debugDataPublisher.Publish(c.componentID, string(data))
+		forEachLogRecord(ld, func(record plog.Logs) {
+			data, _ := c.logsMarshaler.MarshalLogs(record)
+			c.debugDataPublisher.Publish(c.componentID, string(data))
+		})
 	}
 	return nil
 }
+
+// Traces returns a consumer which publishes the traces consumed by next, such
+// as the input of an exporter, before passing them to next. It returns nil if
+// next is nil.
+func (c *Consumer) Traces(next otelconsumer.Traces) otelconsumer.Traces {
+	if next == nil {
+		return nil
+	}
+	return &tracesConsumer{ld: c, next: next}
+}
+
+// Metrics returns a consumer which publishes the metrics consumed by next
+// before passing them to next. It returns nil if next is nil.
+func (c *Consumer) Metrics(next otelconsumer.Metrics) otelconsumer.Metrics {
+	if next == nil {
+		return nil
+	}
+	return &metricsConsumer{ld: c, next: next}
+}
+
+// Logs returns a consumer which publishes the logs consumed by next before
+// passing them to next. It returns nil if next is nil.
+func (c *Consumer) Logs(next otelconsumer.Logs) otelconsumer.Logs {
+	if next == nil {
+		return nil
+	}
+	return &logsConsumer{ld: c, next: next}
+}
+
+type tracesConsumer struct {
+	ld   *Consumer
+	next otelconsumer.Traces
+}
+
+func (c *tracesConsumer) Capabilities() otelconsumer.Capabilities { return c.next.Capabilities() }
+
+func (c *tracesConsumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	_ = c.ld.ConsumeTraces(ctx, td)
+	return c.next.ConsumeTraces(ctx, td)
+}
+
+type metricsConsumer struct {
+	ld   *Consumer
+	next otelconsumer.Metrics
+}
+
+func (c *metricsConsumer) Capabilities() otelconsumer.Capabilities { return c.next.Capabilities() }
+
+func (c *metricsConsumer) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	_ = c.ld.ConsumeMetrics(ctx, md)
+	return c.next.ConsumeMetrics(ctx, md)
+}
+
+type logsConsumer struct {
+	ld   *Consumer
+	next otelconsumer.Logs
+}
+
+func (c *logsConsumer) Capabilities() otelconsumer.Capabilities { return c.next.Capabilities() }
+
+func (c *logsConsumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	_ = c.ld.ConsumeLogs(ctx, ld)
+	return c.next.ConsumeLogs(ctx, ld)
+}
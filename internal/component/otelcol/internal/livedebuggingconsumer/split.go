@@ -0,0 +1,76 @@
+package livedebuggingconsumer
+
+import (
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// forEachSpan calls fn with a copy of every span of td, along with its
+// resource and scope.
+func forEachSpan(td ptrace.Traces, fn func(ptrace.Traces)) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				out := ptrace.NewTraces()
+				rsOut := out.ResourceSpans().AppendEmpty()
+				rsOut.SetSchemaUrl(rs.SchemaUrl())
+				rs.Resource().CopyTo(rsOut.Resource())
+				ssOut := rsOut.ScopeSpans().AppendEmpty()
+				ssOut.SetSchemaUrl(ss.SchemaUrl())
+				ss.Scope().CopyTo(ssOut.Scope())
+				ss.Spans().At(k).CopyTo(ssOut.Spans().AppendEmpty())
+				fn(out)
+			}
+		}
+	}
+}
+
+// forEachMetric calls fn with a copy of every metric of md, along with its
+// resource and scope.
+func forEachMetric(md pmetric.Metrics, fn func(pmetric.Metrics)) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				out := pmetric.NewMetrics()
+				rmOut := out.ResourceMetrics().AppendEmpty()
+				rmOut.SetSchemaUrl(rm.SchemaUrl())
+				rm.Resource().CopyTo(rmOut.Resource())
+				smOut := rmOut.ScopeMetrics().AppendEmpty()
+				smOut.SetSchemaUrl(sm.SchemaUrl())
+				sm.Scope().CopyTo(smOut.Scope())
+				sm.Metrics().At(k).CopyTo(smOut.Metrics().AppendEmpty())
+				fn(out)
+			}
+		}
+	}
+}
+
+// forEachLogRecord calls fn with a copy of every log record of ld, along
+// with its resource and scope.
+func forEachLogRecord(ld plog.Logs, fn func(plog.Logs)) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				out := plog.NewLogs()
+				rlOut := out.ResourceLogs().AppendEmpty()
+				rlOut.SetSchemaUrl(rl.SchemaUrl())
+				rl.Resource().CopyTo(rlOut.Resource())
+				slOut := rlOut.ScopeLogs().AppendEmpty()
+				slOut.SetSchemaUrl(sl.SchemaUrl())
+				sl.Scope().CopyTo(slOut.Scope())
+				sl.LogRecords().At(k).CopyTo(slOut.LogRecords().AppendEmpty())
+				fn(out)
+			}
+		}
+	}
+}
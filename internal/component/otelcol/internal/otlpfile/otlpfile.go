@@ -0,0 +1,217 @@
+// Package otlpfile encodes and decodes OTLP data stored in files.
+//
+// Two formats are supported:
+//
+//   - json: each record is an OTLP/JSON export request on a single line, as
+//     written by the fileexporter of the OpenTelemetry Collector.
+//   - proto: each record is a single byte identifying the signal, followed by
+//     the length of the payload as a 4 bytes big-endian integer, followed by
+//     the OTLP/protobuf export request.
+package otlpfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Supported formats.
+const (
+	FormatJSON  = "json"
+	FormatProto = "proto"
+)
+
+// maxRecordSize is the maximum size of a proto record, to avoid allocating
+// huge buffers when reading corrupted files.
+const maxRecordSize = 256 << 20
+
+// Signal identifies the type of data in a proto record.
+type Signal byte
+
+// Supported signals.
+const (
+	SignalTraces Signal = iota + 1
+	SignalMetrics
+	SignalLogs
+)
+
+// ValidateFormat returns an error if format isn't supported.
+func ValidateFormat(format string) error {
+	if format != FormatJSON && format != FormatProto {
+		return fmt.Errorf("format must be one of %q or %q, got %q", FormatJSON, FormatProto, format)
+	}
+	return nil
+}
+
+// MarshalTraces encodes traces as a record.
+func MarshalTraces(format string, td ptrace.Traces) ([]byte, error) {
+	if format == FormatJSON {
+		return jsonRecord((&ptrace.JSONMarshaler{}).MarshalTraces(td))
+	}
+	return protoRecord(SignalTraces)((&ptrace.ProtoMarshaler{}).MarshalTraces(td))
+}
+
+// MarshalMetrics encodes metrics as a record.
+func MarshalMetrics(format string, md pmetric.Metrics) ([]byte, error) {
+	if format == FormatJSON {
+		return jsonRecord((&pmetric.JSONMarshaler{}).MarshalMetrics(md))
+	}
+	return protoRecord(SignalMetrics)((&pmetric.ProtoMarshaler{}).MarshalMetrics(md))
+}
+
+// MarshalLogs encodes logs as a record.
+func MarshalLogs(format string, ld plog.Logs) ([]byte, error) {
+	if format == FormatJSON {
+		return jsonRecord((&plog.JSONMarshaler{}).MarshalLogs(ld))
+	}
+	return protoRecord(SignalLogs)((&plog.ProtoMarshaler{}).MarshalLogs(ld))
+}
+
+func jsonRecord(b []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func protoRecord(signal Signal) func([]byte, error) ([]byte, error) {
+	return func(b []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		record := make([]byte, 5, 5+len(b))
+		record[0] = byte(signal)
+		binary.BigEndian.PutUint32(record[1:], uint32(len(b)))
+		return append(record, b...), nil
+	}
+}
+
+// Record is a decoded record. Exactly one of its fields is set.
+type Record struct {
+	Traces  *ptrace.Traces
+	Metrics *pmetric.Metrics
+	Logs    *plog.Logs
+}
+
+// Decoder reads records from a stream.
+type Decoder struct {
+	format string
+	r      *bufio.Reader
+	offset int64
+}
+
+// NewDecoder creates a new Decoder reading records in format from r.
+func NewDecoder(format string, r io.Reader) *Decoder {
+	return &Decoder{format: format, r: bufio.NewReader(r)}
+}
+
+// Offset returns the number of bytes of the complete records read so far.
+func (d *Decoder) Offset() int64 {
+	return d.offset
+}
+
+// Next returns the next record. It returns io.EOF once all complete records
+// have been read, and io.ErrUnexpectedEOF if the stream ends with a partial
+// record, which may be completed later if the stream is still being written.
+// The Decoder must not be used after an error; a new Decoder can resume
+// reading from Offset.
+func (d *Decoder) Next() (Record, error) {
+	if d.format == FormatJSON {
+		return d.nextJSON()
+	}
+	return d.nextProto()
+}
+
+func (d *Decoder) nextJSON() (Record, error) {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) == 0 {
+				return Record{}, io.EOF
+			}
+			return Record{}, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return Record{}, err
+		}
+		d.offset += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		return decodeJSON(line)
+	}
+}
+
+func decodeJSON(line []byte) (Record, error) {
+	var keys struct {
+		ResourceSpans   json.RawMessage `json:"resourceSpans"`
+		ResourceMetrics json.RawMessage `json:"resourceMetrics"`
+		ResourceLogs    json.RawMessage `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(line, &keys); err != nil {
+		return Record{}, err
+	}
+
+	switch {
+	case keys.ResourceSpans != nil:
+		td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(line)
+		return Record{Traces: &td}, err
+	case keys.ResourceMetrics != nil:
+		md, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(line)
+		return Record{Metrics: &md}, err
+	case keys.ResourceLogs != nil:
+		ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(line)
+		return Record{Logs: &ld}, err
+	default:
+		return Record{}, errors.New("record contains no resourceSpans, resourceMetrics, or resourceLogs")
+	}
+}
+
+func (d *Decoder) nextProto() (Record, error) {
+	header, err := d.r.Peek(5)
+	if err == io.EOF && len(header) == 0 {
+		return Record{}, io.EOF
+	} else if err == io.EOF {
+		return Record{}, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return Record{}, err
+	}
+
+	signal := Signal(header[0])
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxRecordSize {
+		return Record{}, fmt.Errorf("record of %d bytes exceeds the maximum size of %d bytes", size, maxRecordSize)
+	}
+
+	record := make([]byte, 5+int(size))
+	if _, err := io.ReadFull(d.r, record); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return Record{}, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return Record{}, err
+	}
+	d.offset += int64(len(record))
+
+	payload := record[5:]
+	switch signal {
+	case SignalTraces:
+		td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(payload)
+		return Record{Traces: &td}, err
+	case SignalMetrics:
+		md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(payload)
+		return Record{Metrics: &md}, err
+	case SignalLogs:
+		ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(payload)
+		return Record{Logs: &ld}, err
+	default:
+		return Record{}, fmt.Errorf("unknown signal %d", signal)
+	}
+}
@@ -0,0 +1,68 @@
+package otlpfile
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestRoundTrip(t *testing.T) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("metric")
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("log")
+
+	for _, format := range []string{FormatJSON, FormatProto} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			for _, marshal := range []func() ([]byte, error){
+				func() ([]byte, error) { return MarshalTraces(format, td) },
+				func() ([]byte, error) { return MarshalMetrics(format, md) },
+				func() ([]byte, error) { return MarshalLogs(format, ld) },
+			} {
+				b, err := marshal()
+				require.NoError(t, err)
+				buf.Write(b)
+			}
+			complete := int64(buf.Len())
+
+			// Write the beginning of another record, as if the file was still
+			// being written.
+			partial, err := MarshalLogs(format, ld)
+			require.NoError(t, err)
+			buf.Write(partial[:len(partial)/2])
+
+			dec := NewDecoder(format, &buf)
+
+			record, err := dec.Next()
+			require.NoError(t, err)
+			require.Equal(t, "span", record.Traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+
+			record, err = dec.Next()
+			require.NoError(t, err)
+			require.Equal(t, "metric", record.Metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+
+			record, err = dec.Next()
+			require.NoError(t, err)
+			require.Equal(t, "log", record.Logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+
+			_, err = dec.Next()
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			require.Equal(t, complete, dec.Offset())
+		})
+	}
+}
+
+func TestDecoder_EOF(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatProto} {
+		_, err := NewDecoder(format, bytes.NewReader(nil)).Next()
+		require.ErrorIs(t, err, io.EOF)
+	}
+}
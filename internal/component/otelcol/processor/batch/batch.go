@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/alecthomas/units"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/otelcol"
 	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
@@ -23,8 +24,7 @@ func init() {
 		Exports:   otelcol.ConsumerExports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			fact := batchprocessor.NewFactory()
-			return processor.New(opts, fact, args.(Arguments))
+			return processor.New(opts, newFactory(), args.(Arguments))
 		},
 	})
 }
@@ -37,6 +37,10 @@ type Arguments struct {
 	MetadataKeys             []string      `alloy:"metadata_keys,attr,optional"`
 	MetadataCardinalityLimit uint32        `alloy:"metadata_cardinality_limit,attr,optional"`
 
+	// MaxBatchBytes is the maximum size of the batches once serialized as OTLP
+	// protobuf. Larger batches are split. Batches aren't split when 0.
+	MaxBatchBytes units.Base2Bytes `alloy:"max_batch_bytes,attr,optional"`
+
 	// Output configures where to send processed data. Required.
 	Output *otelcol.ConsumerArguments `alloy:"output,block"`
 
@@ -66,17 +70,23 @@ func (args *Arguments) Validate() error {
 	if args.SendBatchMaxSize > 0 && args.SendBatchMaxSize < args.SendBatchSize {
 		return fmt.Errorf("send_batch_max_size must be greater or equal to send_batch_size when not 0")
 	}
+	if args.MaxBatchBytes < 0 {
+		return fmt.Errorf("max_batch_bytes must not be negative")
+	}
 	return nil
 }
 
 // Convert implements processor.Arguments.
 func (args Arguments) Convert() (otelcomponent.Config, error) {
-	return &batchprocessor.Config{
-		Timeout:                  args.Timeout,
-		SendBatchSize:            args.SendBatchSize,
-		SendBatchMaxSize:         args.SendBatchMaxSize,
-		MetadataKeys:             args.MetadataKeys,
-		MetadataCardinalityLimit: args.MetadataCardinalityLimit,
+	return &Config{
+		Upstream: batchprocessor.Config{
+			Timeout:                  args.Timeout,
+			SendBatchSize:            args.SendBatchSize,
+			SendBatchMaxSize:         args.SendBatchMaxSize,
+			MetadataKeys:             args.MetadataKeys,
+			MetadataCardinalityLimit: args.MetadataCardinalityLimit,
+		},
+		MaxBatchBytes: int(args.MaxBatchBytes),
 	}, nil
 }
 
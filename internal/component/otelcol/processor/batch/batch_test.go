@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alecthomas/units"
 	"github.com/grafana/alloy/internal/component/otelcol"
 	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/batch"
@@ -15,7 +16,6 @@ import (
 	"github.com/grafana/dskit/backoff"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.opentelemetry.io/collector/processor/batchprocessor"
 )
 
 // Test performs a basic integration test which runs the
@@ -167,6 +167,18 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 				MetadataCardinalityLimit: 123,
 			},
 		},
+		{
+			cfg: `
+			max_batch_bytes = "4MiB"
+			output {}
+			`,
+			expectedArguments: batch.Arguments{
+				Timeout:                  batch.DefaultArguments.Timeout,
+				SendBatchSize:            batch.DefaultArguments.SendBatchSize,
+				MetadataCardinalityLimit: batch.DefaultArguments.MetadataCardinalityLimit,
+				MaxBatchBytes:            4 * units.MiB,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -177,9 +189,11 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 		ext, err := args.Convert()
 		require.NoError(t, err)
 
-		otelArgs, ok := (ext).(*batchprocessor.Config)
+		cfg, ok := (ext).(*batch.Config)
 		require.True(t, ok)
+		otelArgs := cfg.Upstream
 
+		require.Equal(t, int(tc.expectedArguments.MaxBatchBytes), cfg.MaxBatchBytes)
 		require.Equal(t, otelArgs.Timeout, tc.expectedArguments.Timeout)
 		require.Equal(t, otelArgs.SendBatchSize, tc.expectedArguments.SendBatchSize)
 		require.Equal(t, otelArgs.SendBatchMaxSize, tc.expectedArguments.SendBatchMaxSize)
@@ -187,3 +201,12 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 		require.Equal(t, otelArgs.MetadataCardinalityLimit, tc.expectedArguments.MetadataCardinalityLimit)
 	}
 }
+
+func TestArguments_Validate(t *testing.T) {
+	var args batch.Arguments
+	err := syntax.Unmarshal([]byte(`
+		max_batch_bytes = "-1KiB"
+		output {}
+	`), &args)
+	require.EqualError(t, err, "max_batch_bytes must not be negative")
+}
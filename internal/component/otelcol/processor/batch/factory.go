@@ -0,0 +1,56 @@
+package batch
+
+import (
+	"context"
+
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	otelprocessor "go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/batchprocessor"
+)
+
+// Config is the configuration of the processors created by the factory of the
+// component.
+type Config struct {
+	Upstream batchprocessor.Config
+
+	// MaxBatchBytes is the maximum size of the batches once serialized as OTLP
+	// protobuf. The batches of the upstream processor which are larger are
+	// split. It's 0 when the batches aren't split.
+	MaxBatchBytes int
+}
+
+// newFactory returns a processor factory which creates an upstream batch
+// processor, whose batches are split when they exceed the maximum size in
+// bytes of the configuration.
+func newFactory() otelprocessor.Factory {
+	upstream := batchprocessor.NewFactory()
+
+	return otelprocessor.NewFactory(
+		upstream.Type(),
+		func() otelcomponent.Config {
+			return &Config{Upstream: *upstream.CreateDefaultConfig().(*batchprocessor.Config)}
+		},
+		otelprocessor.WithTraces(func(ctx context.Context, set otelprocessor.CreateSettings, cfg otelcomponent.Config, next consumer.Traces) (otelprocessor.Traces, error) {
+			c := cfg.(*Config)
+			if c.MaxBatchBytes > 0 {
+				next = &tracesSplitter{next: next, maxBytes: c.MaxBatchBytes}
+			}
+			return upstream.CreateTracesProcessor(ctx, set, &c.Upstream, next)
+		}, upstream.TracesProcessorStability()),
+		otelprocessor.WithMetrics(func(ctx context.Context, set otelprocessor.CreateSettings, cfg otelcomponent.Config, next consumer.Metrics) (otelprocessor.Metrics, error) {
+			c := cfg.(*Config)
+			if c.MaxBatchBytes > 0 {
+				next = &metricsSplitter{next: next, maxBytes: c.MaxBatchBytes}
+			}
+			return upstream.CreateMetricsProcessor(ctx, set, &c.Upstream, next)
+		}, upstream.MetricsProcessorStability()),
+		otelprocessor.WithLogs(func(ctx context.Context, set otelprocessor.CreateSettings, cfg otelcomponent.Config, next consumer.Logs) (otelprocessor.Logs, error) {
+			c := cfg.(*Config)
+			if c.MaxBatchBytes > 0 {
+				next = &logsSplitter{next: next, maxBytes: c.MaxBatchBytes}
+			}
+			return upstream.CreateLogsProcessor(ctx, set, &c.Upstream, next)
+		}, upstream.LogsProcessorStability()),
+	)
+}
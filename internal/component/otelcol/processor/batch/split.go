@@ -0,0 +1,210 @@
+package batch
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	tracesMarshaler  = &ptrace.ProtoMarshaler{}
+	metricsMarshaler = &pmetric.ProtoMarshaler{}
+	logsMarshaler    = &plog.ProtoMarshaler{}
+)
+
+// splitter splits batches whose size once serialized as OTLP protobuf exceeds
+// a maximum, by halving them until every part fits. A batch is split at the
+// level of its items: spans, data points or log records.
+type splitter[T any] struct {
+	// count returns the number of items of a batch.
+	count func(T) int
+	// size returns the size of a batch once serialized.
+	size func(T) int
+	// slice returns a copy of a batch with the items in [from, to) only.
+	slice func(batch T, from, to int) T
+}
+
+// split returns the parts of batch. A single item which exceeds maxBytes is
+// returned alone, as it can't be split further.
+func (s splitter[T]) split(batch T, maxBytes int) []T {
+	n := s.count(batch)
+	if n <= 1 || s.size(batch) <= maxBytes {
+		return []T{batch}
+	}
+	left := s.split(s.slice(batch, 0, n/2), maxBytes)
+	return append(left, s.split(s.slice(batch, n/2, n), maxBytes)...)
+}
+
+// inRange returns a function which reports whether the item at the current
+// index is in [from, to), and moves to the next index on every call.
+func inRange(from, to int) func() bool {
+	i := 0
+	return func() bool {
+		ok := i >= from && i < to
+		i++
+		return ok
+	}
+}
+
+var tracesSplit = splitter[ptrace.Traces]{
+	count: ptrace.Traces.SpanCount,
+	size:  tracesMarshaler.TracesSize,
+	slice: func(td ptrace.Traces, from, to int) ptrace.Traces {
+		dest := ptrace.NewTraces()
+		td.CopyTo(dest)
+		keep := inRange(from, to)
+		dest.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+			rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+				ss.Spans().RemoveIf(func(ptrace.Span) bool { return !keep() })
+				return ss.Spans().Len() == 0
+			})
+			return rs.ScopeSpans().Len() == 0
+		})
+		return dest
+	},
+}
+
+var metricsSplit = splitter[pmetric.Metrics]{
+	count: pmetric.Metrics.DataPointCount,
+	size:  metricsMarshaler.MetricsSize,
+	slice: func(md pmetric.Metrics, from, to int) pmetric.Metrics {
+		dest := pmetric.NewMetrics()
+		md.CopyTo(dest)
+		keep := inRange(from, to)
+		dest.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+			rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+				sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+					return keepDataPoints(m, keep) == 0
+				})
+				return sm.Metrics().Len() == 0
+			})
+			return rm.ScopeMetrics().Len() == 0
+		})
+		return dest
+	},
+}
+
+// keepDataPoints removes the data points of m for which keep returns false,
+// and returns the number of data points left.
+func keepDataPoints(m pmetric.Metric, keep func() bool) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		dps.RemoveIf(func(pmetric.NumberDataPoint) bool { return !keep() })
+		return dps.Len()
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		dps.RemoveIf(func(pmetric.NumberDataPoint) bool { return !keep() })
+		return dps.Len()
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		dps.RemoveIf(func(pmetric.HistogramDataPoint) bool { return !keep() })
+		return dps.Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(pmetric.ExponentialHistogramDataPoint) bool { return !keep() })
+		return dps.Len()
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		dps.RemoveIf(func(pmetric.SummaryDataPoint) bool { return !keep() })
+		return dps.Len()
+	}
+	return 0
+}
+
+var logsSplit = splitter[plog.Logs]{
+	count: plog.Logs.LogRecordCount,
+	size:  logsMarshaler.LogsSize,
+	slice: func(ld plog.Logs, from, to int) plog.Logs {
+		dest := plog.NewLogs()
+		ld.CopyTo(dest)
+		keep := inRange(from, to)
+		dest.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+			rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+				sl.LogRecords().RemoveIf(func(plog.LogRecord) bool { return !keep() })
+				return sl.LogRecords().Len() == 0
+			})
+			return rl.ScopeLogs().Len() == 0
+		})
+		return dest
+	},
+}
+
+// tracesSplitter sends the batches of traces it consumes to the next consumer,
+// split so that they don't exceed maxBytes.
+type tracesSplitter struct {
+	next     consumer.Traces
+	maxBytes int
+}
+
+var _ consumer.Traces = (*tracesSplitter)(nil)
+
+// Capabilities implements consumer.Traces.
+func (s *tracesSplitter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements consumer.Traces.
+func (s *tracesSplitter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	var errs []error
+	for _, batch := range tracesSplit.split(td, s.maxBytes) {
+		if err := s.next.ConsumeTraces(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// metricsSplitter sends the batches of metrics it consumes to the next
+// consumer, split so that they don't exceed maxBytes.
+type metricsSplitter struct {
+	next     consumer.Metrics
+	maxBytes int
+}
+
+var _ consumer.Metrics = (*metricsSplitter)(nil)
+
+// Capabilities implements consumer.Metrics.
+func (s *metricsSplitter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeMetrics implements consumer.Metrics.
+func (s *metricsSplitter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	var errs []error
+	for _, batch := range metricsSplit.split(md, s.maxBytes) {
+		if err := s.next.ConsumeMetrics(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// logsSplitter sends the batches of logs it consumes to the next consumer,
+// split so that they don't exceed maxBytes.
+type logsSplitter struct {
+	next     consumer.Logs
+	maxBytes int
+}
+
+var _ consumer.Logs = (*logsSplitter)(nil)
+
+// Capabilities implements consumer.Logs.
+func (s *logsSplitter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeLogs implements consumer.Logs.
+func (s *logsSplitter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	var errs []error
+	for _, batch := range logsSplit.split(ld, s.maxBytes) {
+		if err := s.next.ConsumeLogs(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
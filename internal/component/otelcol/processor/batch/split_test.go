@@ -0,0 +1,137 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestTracesSplitter(t *testing.T) {
+	td := ptrace.NewTraces()
+	for r := 0; r < 3; r++ {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", fmt.Sprintf("svc-%d", r))
+		ss := rs.ScopeSpans().AppendEmpty()
+		for s := 0; s < 10; s++ {
+			span := ss.Spans().AppendEmpty()
+			span.SetName(fmt.Sprintf("span-%d-%d", r, s))
+			span.Attributes().PutStr("payload", strings.Repeat("x", 100))
+		}
+	}
+	maxBytes := tracesMarshaler.TracesSize(td) / 5
+
+	sink := new(consumertest.TracesSink)
+	s := &tracesSplitter{next: sink, maxBytes: maxBytes}
+	require.NoError(t, s.ConsumeTraces(context.Background(), td))
+
+	batches := sink.AllTraces()
+	require.Greater(t, len(batches), 1)
+
+	var names []string
+	for _, batch := range batches {
+		require.LessOrEqual(t, tracesMarshaler.TracesSize(batch), maxBytes)
+		for i := 0; i < batch.ResourceSpans().Len(); i++ {
+			rs := batch.ResourceSpans().At(i)
+			require.Positive(t, rs.ScopeSpans().Len())
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				spans := rs.ScopeSpans().At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					names = append(names, spans.At(k).Name())
+				}
+			}
+		}
+	}
+
+	// Every span is sent once and in order.
+	require.Len(t, names, 30)
+	for i, name := range names {
+		require.Equal(t, fmt.Sprintf("span-%d-%d", i/10, i%10), name)
+	}
+
+	// The consumed traces aren't modified.
+	require.Equal(t, 30, td.SpanCount())
+}
+
+func TestTracesSplitter_SmallBatch(t *testing.T) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+
+	sink := new(consumertest.TracesSink)
+	s := &tracesSplitter{next: sink, maxBytes: 1024}
+	require.NoError(t, s.ConsumeTraces(context.Background(), td))
+	require.Len(t, sink.AllTraces(), 1)
+}
+
+func TestMetricsSplitter(t *testing.T) {
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("gauge")
+	gdps := gauge.SetEmptyGauge().DataPoints()
+	for i := 0; i < 10; i++ {
+		dp := gdps.AppendEmpty()
+		dp.SetIntValue(int64(i))
+		dp.Attributes().PutStr("payload", strings.Repeat("x", 100))
+	}
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("histogram")
+	hdps := histogram.SetEmptyHistogram().DataPoints()
+	for i := 0; i < 10; i++ {
+		dp := hdps.AppendEmpty()
+		dp.SetCount(uint64(i))
+		dp.Attributes().PutStr("payload", strings.Repeat("x", 100))
+	}
+	maxBytes := metricsMarshaler.MetricsSize(md) / 4
+
+	sink := new(consumertest.MetricsSink)
+	s := &metricsSplitter{next: sink, maxBytes: maxBytes}
+	require.NoError(t, s.ConsumeMetrics(context.Background(), md))
+
+	batches := sink.AllMetrics()
+	require.Greater(t, len(batches), 1)
+
+	var dataPoints int
+	for _, batch := range batches {
+		require.LessOrEqual(t, metricsMarshaler.MetricsSize(batch), maxBytes)
+		dataPoints += batch.DataPointCount()
+	}
+	require.Equal(t, 20, dataPoints)
+	require.Equal(t, 20, md.DataPointCount())
+}
+
+func TestLogsSplitter(t *testing.T) {
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	for i := 0; i < 8; i++ {
+		sl.LogRecords().AppendEmpty().Body().SetStr(strings.Repeat("x", 100))
+	}
+	// A record larger than the maximum is sent alone.
+	sl.LogRecords().AppendEmpty().Body().SetStr(strings.Repeat("x", 1000))
+	maxBytes := 500
+
+	sink := new(consumertest.LogsSink)
+	s := &logsSplitter{next: sink, maxBytes: maxBytes}
+	require.NoError(t, s.ConsumeLogs(context.Background(), ld))
+
+	batches := sink.AllLogs()
+	var records int
+	for _, batch := range batches {
+		if batch.LogRecordCount() > 1 {
+			require.LessOrEqual(t, logsMarshaler.LogsSize(batch), maxBytes)
+		}
+		records += batch.LogRecordCount()
+	}
+	require.Equal(t, 9, records)
+
+	last := batches[len(batches)-1]
+	require.Equal(t, 1, last.LogRecordCount())
+	require.Greater(t, logsMarshaler.LogsSize(last), maxBytes)
+}
@@ -0,0 +1,66 @@
+package interval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/processor/interval"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/intervalprocessor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected intervalprocessor.Config
+		errorMsg string
+	}{
+		{
+			testName: "Defaults",
+			cfg: `
+					output {}
+				`,
+			expected: intervalprocessor.Config{
+				Interval: 60 * time.Second,
+			},
+		},
+		{
+			testName: "ExplicitValues",
+			cfg: `
+					interval = "15s"
+					output {}
+				`,
+			expected: intervalprocessor.Config{
+				Interval: 15 * time.Second,
+			},
+		},
+		{
+			testName: "ZeroInterval",
+			cfg: `
+					interval = "0s"
+					output {}
+				`,
+			errorMsg: "interval must be a positive duration (got 0s)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args interval.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.EqualError(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			actualPtr, err := args.Convert()
+			require.NoError(t, err)
+
+			actual := actualPtr.(*intervalprocessor.Config)
+			require.Equal(t, tc.expected, *actual)
+		})
+	}
+}
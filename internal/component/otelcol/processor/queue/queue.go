@@ -0,0 +1,229 @@
+// Package queue provides an otelcol.processor.queue component.
+package queue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/alecthomas/units"
+	otelconsumer "go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/queue"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fanoutconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/lazyconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/otlpfile"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.processor.queue",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(o component.Options, a component.Arguments) (component.Component, error) {
+			return New(o, a.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.processor.queue component.
+type Arguments struct {
+	Policy       queue.Policy     `alloy:"policy,attr,optional"`
+	Capacity     int              `alloy:"capacity,attr,optional"`
+	MaxSpillSize units.Base2Bytes `alloy:"max_spill_size,attr,optional"`
+
+	// Output configures where to send processed data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var (
+	_ syntax.Defaulter = (*Arguments)(nil)
+	_ syntax.Validator = (*Arguments)(nil)
+)
+
+// DefaultArguments holds default settings for otelcol.processor.queue.
+var DefaultArguments = Arguments{
+	Policy:       queue.PolicyBlock,
+	Capacity:     100,
+	MaxSpillSize: 256 * units.MiB,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Capacity <= 0 {
+		return fmt.Errorf("capacity must be greater than 0")
+	}
+	if args.MaxSpillSize <= 0 {
+		return fmt.Errorf("max_spill_size must be greater than 0")
+	}
+	return nil
+}
+
+// Component is the otelcol.processor.queue component.
+type Component struct {
+	opts  component.Options
+	queue *queue.Queue[otlpfile.Record]
+
+	mut     sync.RWMutex
+	traces  otelconsumer.Traces
+	metrics otelconsumer.Metrics
+	logs    otelconsumer.Logs
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new otelcol.processor.queue component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	m, err := queue.NewMetrics(o.Registerer, "otelcol_processor_queue", "batches")
+	if err != nil {
+		return nil, err
+	}
+	q, err := queue.New[otlpfile.Record](recordCodec{}, queueOptions(o, args), m)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:  o,
+		queue: q,
+	}
+	c.setOutput(args.Output)
+
+	// Export the consumer.
+	// This will remain the same throughout the component's lifetime,
+	// so we do this during component construction.
+	export := lazyconsumer.New(context.Background())
+	in := &consumer{queue: q}
+	export.SetConsumers(in, in, in)
+	o.OnStateChange(otelcol.ConsumerExports{Input: export})
+
+	return c, nil
+}
+
+func queueOptions(o component.Options, args Arguments) queue.Options {
+	return queue.Options{
+		Policy:       args.Policy,
+		Capacity:     args.Capacity,
+		SpillDir:     filepath.Join(o.DataPath, "spill"),
+		MaxSpillSize: int64(args.MaxSpillSize),
+	}
+}
+
+// Run implements Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		if err := c.queue.Close(); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to close queue", "err", err)
+		}
+	}()
+
+	for {
+		record, err := c.queue.Pop(ctx)
+		if err != nil {
+			return nil
+		}
+		if err := c.forward(ctx, record); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to forward telemetry data", "err", err)
+		}
+	}
+}
+
+func (c *Component) forward(ctx context.Context, record otlpfile.Record) error {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	switch {
+	case record.Traces != nil:
+		return c.traces.ConsumeTraces(ctx, *record.Traces)
+	case record.Metrics != nil:
+		return c.metrics.ConsumeMetrics(ctx, *record.Metrics)
+	case record.Logs != nil:
+		return c.logs.ConsumeLogs(ctx, *record.Logs)
+	}
+	return nil
+}
+
+// Update implements Component.
+func (c *Component) Update(newConfig component.Arguments) error {
+	args := newConfig.(Arguments)
+	if err := c.queue.Update(queueOptions(c.opts, args)); err != nil {
+		return err
+	}
+	c.setOutput(args.Output)
+	return nil
+}
+
+func (c *Component) setOutput(output *otelcol.ConsumerArguments) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.traces = fanoutconsumer.Traces(output.Traces)
+	c.metrics = fanoutconsumer.Metrics(output.Metrics)
+	c.logs = fanoutconsumer.Logs(output.Logs)
+}
+
+// consumer pushes the data it receives to the queue.
+type consumer struct {
+	queue *queue.Queue[otlpfile.Record]
+}
+
+var _ otelcol.Consumer = (*consumer)(nil)
+
+// Capabilities implements otelconsumer.baseConsumer.
+func (c *consumer) Capabilities() otelconsumer.Capabilities {
+	// The data is kept in the queue after the call returns, so it must not be
+	// shared with other consumers which could change it.
+	return otelconsumer.Capabilities{MutatesData: true}
+}
+
+// ConsumeTraces implements otelconsumer.Traces.
+func (c *consumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return c.queue.Push(ctx, otlpfile.Record{Traces: &td})
+}
+
+// ConsumeMetrics implements otelconsumer.Metrics.
+func (c *consumer) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return c.queue.Push(ctx, otlpfile.Record{Metrics: &md})
+}
+
+// ConsumeLogs implements otelconsumer.Logs.
+func (c *consumer) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return c.queue.Push(ctx, otlpfile.Record{Logs: &ld})
+}
+
+// recordCodec encodes telemetry data into the spill file, using the proto
+// format of otelcol.exporter.file.
+type recordCodec struct{}
+
+func (recordCodec) Encode(record otlpfile.Record) ([]byte, error) {
+	switch {
+	case record.Traces != nil:
+		return otlpfile.MarshalTraces(otlpfile.FormatProto, *record.Traces)
+	case record.Metrics != nil:
+		return otlpfile.MarshalMetrics(otlpfile.FormatProto, *record.Metrics)
+	case record.Logs != nil:
+		return otlpfile.MarshalLogs(otlpfile.FormatProto, *record.Logs)
+	}
+	return nil, errors.New("empty record")
+}
+
+func (recordCodec) Decode(data []byte) (otlpfile.Record, error) {
+	return otlpfile.NewDecoder(otlpfile.FormatProto, bytes.NewReader(data)).Next()
+}
@@ -0,0 +1,89 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/otelcol/processor/processortest"
+	"github.com/grafana/alloy/internal/component/otelcol/processor/queue"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func testRunProcessor(t *testing.T, processorConfig string, testSignal processortest.Signal) {
+	ctx := componenttest.TestContext(t)
+	l := util.TestLogger(t)
+
+	ctrl, err := componenttest.NewControllerFromID(l, "otelcol.processor.queue")
+	require.NoError(t, err)
+
+	var args queue.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(processorConfig), &args))
+
+	// Override the arguments so signals get forwarded to the test channel.
+	args.Output = testSignal.MakeOutput()
+
+	prc := processortest.ProcessorRunConfig{
+		Ctx:        ctx,
+		T:          t,
+		Args:       args,
+		TestSignal: testSignal,
+		Ctrl:       ctrl,
+		L:          l,
+	}
+	processortest.TestRunProcessor(prc)
+}
+
+func Test_Logs(t *testing.T) {
+	cfg := `
+		policy   = "drop_oldest"
+		capacity = 10
+
+		output {
+			// no-op: will be overridden by test code.
+		}
+	`
+
+	var inputLog = `{
+		"resourceLogs": [{
+			"scopeLogs": [{
+				"log_records": [{
+					"body": { "stringValue": "hello" }
+				}]
+			}]
+		}]
+	}`
+
+	testRunProcessor(t, cfg, processortest.NewLogSignal(inputLog, inputLog))
+}
+
+func Test_Traces(t *testing.T) {
+	cfg := `
+		output {
+			// no-op: will be overridden by test code.
+		}
+	`
+
+	var inputTrace = `{
+		"resourceSpans": [{
+			"scopeSpans": [{
+				"spans": [{
+					"name": "TestSpan"
+				}]
+			}]
+		}]
+	}`
+
+	testRunProcessor(t, cfg, processortest.NewTraceSignal(inputTrace, inputTrace))
+}
+
+func Test_Arguments(t *testing.T) {
+	cfg := `
+		policy = "drop"
+
+		output {}
+	`
+	var args queue.Arguments
+	require.ErrorContains(t, syntax.Unmarshal([]byte(cfg), &args), `unknown queue policy "drop"`)
+}
@@ -0,0 +1,210 @@
+package resourcedetection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// customDetector is a detector implemented in Alloy, which the upstream
+// processor doesn't support.
+type customDetector struct {
+	name            string
+	detect          func(ctx context.Context) (map[string]any, error)
+	refreshInterval time.Duration
+}
+
+// customResource holds the resource attributes detected by the custom
+// detectors. The detectors run when the first processor using it starts, and
+// then every refresh interval until all the processors using it shut down.
+type customResource struct {
+	detectors []customDetector
+
+	mut      sync.RWMutex
+	detected []map[string]any // Last detected attributes of each detector.
+	attrs    pcommon.Map      // Merged attributes of the detectors.
+
+	refsMut sync.Mutex
+	refs    int
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newCustomResource(detectors []customDetector) *customResource {
+	return &customResource{
+		detectors: detectors,
+		detected:  make([]map[string]any, len(detectors)),
+		attrs:     pcommon.NewMap(),
+	}
+}
+
+func (r *customResource) start(ctx context.Context, logger *zap.Logger) {
+	r.refsMut.Lock()
+	defer r.refsMut.Unlock()
+
+	r.refs++
+	if r.refs > 1 {
+		return
+	}
+
+	// Like the upstream detectors, the first detection happens before the
+	// processors start processing telemetry.
+	for i := range r.detectors {
+		r.detect(ctx, logger, i)
+	}
+
+	var refreshCtx context.Context
+	refreshCtx, r.cancel = context.WithCancel(context.Background())
+	for i := range r.detectors {
+		r.wg.Add(1)
+		go func(i int) {
+			defer r.wg.Done()
+			r.refresh(refreshCtx, logger, i)
+		}(i)
+	}
+}
+
+func (r *customResource) stop() {
+	r.refsMut.Lock()
+	defer r.refsMut.Unlock()
+
+	r.refs--
+	if r.refs > 0 || r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *customResource) refresh(ctx context.Context, logger *zap.Logger, i int) {
+	ticker := time.NewTicker(r.detectors[i].refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.detect(ctx, logger, i)
+		}
+	}
+}
+
+// detect runs the i-th detector. The last detected attributes of the
+// detector are kept when it fails.
+func (r *customResource) detect(ctx context.Context, logger *zap.Logger, i int) {
+	d := r.detectors[i]
+	detected, err := d.detect(ctx)
+	if err != nil {
+		logger.Warn("failed to detect resource", zap.String("detector", d.name), zap.Error(err))
+		return
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.detected[i] = detected
+
+	// The first detector to set an attribute wins, like with the upstream
+	// detectors.
+	attrs := pcommon.NewMap()
+	for _, detected := range r.detected {
+		for k, v := range detected {
+			if _, ok := attrs.Get(k); ok {
+				continue
+			}
+			if err := attrs.PutEmpty(k).FromRaw(v); err != nil {
+				logger.Warn("failed to set resource attribute", zap.String("detector", d.name), zap.String("attribute", k), zap.Error(err))
+				attrs.Remove(k)
+			}
+		}
+	}
+	r.attrs = attrs
+}
+
+// apply adds the detected attributes to res. Existing attributes are only
+// overridden if override is true.
+func (r *customResource) apply(res pcommon.Resource, override bool) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	attrs := res.Attributes()
+	r.attrs.Range(func(k string, v pcommon.Value) bool {
+		if _, ok := attrs.Get(k); ok && !override {
+			return true
+		}
+		v.CopyTo(attrs.PutEmpty(k))
+		return true
+	})
+}
+
+// customProcessor adds the resource attributes of the custom detectors to
+// telemetry, and forwards it to the next consumer.
+type customProcessor struct {
+	logger   *zap.Logger
+	resource *customResource
+	override bool
+
+	nextTraces  consumer.Traces
+	nextMetrics consumer.Metrics
+	nextLogs    consumer.Logs
+}
+
+var (
+	_ consumer.Traces  = (*customProcessor)(nil)
+	_ consumer.Metrics = (*customProcessor)(nil)
+	_ consumer.Logs    = (*customProcessor)(nil)
+)
+
+// Start implements otelcomponent.Component.
+func (p *customProcessor) Start(ctx context.Context, _ otelcomponent.Host) error {
+	p.resource.start(ctx, p.logger)
+	return nil
+}
+
+// Shutdown implements otelcomponent.Component.
+func (p *customProcessor) Shutdown(context.Context) error {
+	p.resource.stop()
+	return nil
+}
+
+// Capabilities implements consumer.Traces, consumer.Metrics, and
+// consumer.Logs.
+func (p *customProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+// ConsumeTraces implements consumer.Traces.
+func (p *customProcessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		p.resource.apply(rss.At(i).Resource(), p.override)
+	}
+	return p.nextTraces.ConsumeTraces(ctx, td)
+}
+
+// ConsumeMetrics implements consumer.Metrics.
+func (p *customProcessor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		p.resource.apply(rms.At(i).Resource(), p.override)
+	}
+	return p.nextMetrics.ConsumeMetrics(ctx, md)
+}
+
+// ConsumeLogs implements consumer.Logs.
+func (p *customProcessor) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		p.resource.apply(rls.At(i).Resource(), p.override)
+	}
+	return p.nextLogs.ConsumeLogs(ctx, ld)
+}
@@ -0,0 +1,170 @@
+package resourcedetection
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	otelprocessor "go.opentelemetry.io/collector/processor"
+)
+
+// Config is the configuration of the processors created by the factory of the
+// component. The upstream processor runs the upstream detectors, and the
+// detectors which the upstream processor doesn't support are run by Alloy.
+type Config struct {
+	Upstream resourcedetectionprocessor.Config
+
+	// custom holds the attributes of the custom detectors. It's nil when no
+	// custom detectors are used.
+	custom *customResource
+}
+
+// newFactory returns a processor factory which creates an upstream
+// resourcedetection processor for the upstream detectors, chained with a
+// processor for the custom detectors.
+//
+// The custom detectors have a lower priority than the upstream detectors,
+// whatever their position in the list of detectors. If existing attributes are
+// overridden, the custom processor runs first so the upstream processor can
+// override its attributes. Otherwise, it runs last and only adds the
+// attributes which are still missing.
+func newFactory() otelprocessor.Factory {
+	upstream := resourcedetectionprocessor.NewFactory()
+
+	return otelprocessor.NewFactory(
+		upstream.Type(),
+		func() otelcomponent.Config { return &Config{} },
+		otelprocessor.WithTraces(func(ctx context.Context, set otelprocessor.CreateSettings, cfg otelcomponent.Config, next consumer.Traces) (otelprocessor.Traces, error) {
+			c := cfg.(*Config)
+			if c.custom == nil {
+				return upstream.CreateTracesProcessor(ctx, set, &c.Upstream, next)
+			}
+
+			p := newCustomProcessor(set, c)
+			if len(c.Upstream.Detectors) == 0 {
+				p.nextTraces = next
+				return p, nil
+			}
+			if c.Upstream.Override {
+				up, err := upstream.CreateTracesProcessor(ctx, set, &c.Upstream, next)
+				if err != nil {
+					return nil, err
+				}
+				p.nextTraces = up
+				return &tracesChain{Traces: p, chain: chain{p, up}}, nil
+			}
+			p.nextTraces = next
+			up, err := upstream.CreateTracesProcessor(ctx, set, &c.Upstream, p)
+			if err != nil {
+				return nil, err
+			}
+			return &tracesChain{Traces: up, chain: chain{up, p}}, nil
+		}, upstream.TracesProcessorStability()),
+		otelprocessor.WithMetrics(func(ctx context.Context, set otelprocessor.CreateSettings, cfg otelcomponent.Config, next consumer.Metrics) (otelprocessor.Metrics, error) {
+			c := cfg.(*Config)
+			if c.custom == nil {
+				return upstream.CreateMetricsProcessor(ctx, set, &c.Upstream, next)
+			}
+
+			p := newCustomProcessor(set, c)
+			if len(c.Upstream.Detectors) == 0 {
+				p.nextMetrics = next
+				return p, nil
+			}
+			if c.Upstream.Override {
+				up, err := upstream.CreateMetricsProcessor(ctx, set, &c.Upstream, next)
+				if err != nil {
+					return nil, err
+				}
+				p.nextMetrics = up
+				return &metricsChain{Metrics: p, chain: chain{p, up}}, nil
+			}
+			p.nextMetrics = next
+			up, err := upstream.CreateMetricsProcessor(ctx, set, &c.Upstream, p)
+			if err != nil {
+				return nil, err
+			}
+			return &metricsChain{Metrics: up, chain: chain{up, p}}, nil
+		}, upstream.MetricsProcessorStability()),
+		otelprocessor.WithLogs(func(ctx context.Context, set otelprocessor.CreateSettings, cfg otelcomponent.Config, next consumer.Logs) (otelprocessor.Logs, error) {
+			c := cfg.(*Config)
+			if c.custom == nil {
+				return upstream.CreateLogsProcessor(ctx, set, &c.Upstream, next)
+			}
+
+			p := newCustomProcessor(set, c)
+			if len(c.Upstream.Detectors) == 0 {
+				p.nextLogs = next
+				return p, nil
+			}
+			if c.Upstream.Override {
+				up, err := upstream.CreateLogsProcessor(ctx, set, &c.Upstream, next)
+				if err != nil {
+					return nil, err
+				}
+				p.nextLogs = up
+				return &logsChain{Logs: p, chain: chain{p, up}}, nil
+			}
+			p.nextLogs = next
+			up, err := upstream.CreateLogsProcessor(ctx, set, &c.Upstream, p)
+			if err != nil {
+				return nil, err
+			}
+			return &logsChain{Logs: up, chain: chain{up, p}}, nil
+		}, upstream.LogsProcessorStability()),
+	)
+}
+
+func newCustomProcessor(set otelprocessor.CreateSettings, c *Config) *customProcessor {
+	return &customProcessor{
+		logger:   set.Logger,
+		resource: c.custom,
+		override: c.Upstream.Override,
+	}
+}
+
+// chain runs chained processors as a single processor.
+type chain []otelcomponent.Component
+
+// Start implements otelcomponent.Component. If a processor fails to start,
+// the processors which were already started are shut down.
+func (c chain) Start(ctx context.Context, host otelcomponent.Host) error {
+	for i, p := range c {
+		if err := p.Start(ctx, host); err != nil {
+			return errors.Join(err, c[:i].Shutdown(ctx))
+		}
+	}
+	return nil
+}
+
+// Shutdown implements otelcomponent.Component.
+func (c chain) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, p := range c {
+		errs = append(errs, p.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// tracesChain is a chain of processors, where the embedded consumer is the
+// first processor of the chain.
+type tracesChain struct {
+	consumer.Traces
+	chain
+}
+
+// metricsChain is a chain of processors, where the embedded consumer is the
+// first processor of the chain.
+type metricsChain struct {
+	consumer.Metrics
+	chain
+}
+
+// logsChain is a chain of processors, where the embedded consumer is the
+// first processor of the chain.
+type logsChain struct {
+	consumer.Logs
+	chain
+}
@@ -0,0 +1,91 @@
+package resourcedetection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestFactory_CustomDetectors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"service.owner": "payments", "deployment.environment": "cmdb", "host.name": "cmdb-host"}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=env")
+
+	tests := []struct {
+		testName  string
+		detectors string
+		override  bool
+		expected  map[string]any
+	}{
+		{
+			testName:  "custom_only_override",
+			detectors: `["http"]`,
+			override:  true,
+			expected:  map[string]any{"service.owner": "payments", "deployment.environment": "cmdb", "host.name": "cmdb-host"},
+		},
+		{
+			testName:  "custom_only_no_override",
+			detectors: `["http"]`,
+			override:  false,
+			expected:  map[string]any{"service.owner": "payments", "deployment.environment": "cmdb", "host.name": "original"},
+		},
+		{
+			// The upstream detectors take precedence over the custom ones,
+			// whatever their order.
+			testName:  "mixed_override",
+			detectors: `["http", "env"]`,
+			override:  true,
+			expected:  map[string]any{"service.owner": "payments", "deployment.environment": "env", "host.name": "cmdb-host"},
+		},
+		{
+			testName:  "mixed_no_override",
+			detectors: `["http", "env"]`,
+			override:  false,
+			expected:  map[string]any{"service.owner": "payments", "deployment.environment": "env", "host.name": "original"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(fmt.Sprintf(`
+			detectors = %s
+			override  = %t
+			http {
+				endpoint = %q
+			}
+			output {}
+			`, tc.detectors, tc.override, srv.URL)), &args)
+			require.NoError(t, err)
+
+			cfg, err := args.Convert()
+			require.NoError(t, err)
+
+			sink := new(consumertest.TracesSink)
+			p, err := newFactory().CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, sink)
+			require.NoError(t, err)
+			require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+			td := ptrace.NewTraces()
+			td.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("host.name", "original")
+			require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+			require.Len(t, sink.AllTraces(), 1)
+			actual := sink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().AsRaw()
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
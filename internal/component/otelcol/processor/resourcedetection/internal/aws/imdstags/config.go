@@ -0,0 +1,58 @@
+package imdstags
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+const Name = "imds_tags"
+
+// Config defines user-specified configurations unique to the imds_tags detector
+type Config struct {
+	// Endpoint is the address of the EC2 instance metadata service
+	Endpoint string `alloy:"endpoint,attr,optional"`
+
+	// Tags is a list of regex's to match ec2 instance tag keys that users want
+	// to add as resource attributes to processed data. All the tags are added
+	// when it's empty.
+	Tags []string `alloy:"tags,attr,optional"`
+
+	// RefreshInterval is the interval between requests to the instance metadata
+	// service. The last detected tags are used in between.
+	RefreshInterval time.Duration `alloy:"refresh_interval,attr,optional"`
+}
+
+// DefaultArguments holds default settings for Config.
+var DefaultArguments = Config{
+	Endpoint:        "http://169.254.169.254",
+	RefreshInterval: 5 * time.Minute,
+}
+
+var (
+	_ syntax.Defaulter = (*Config)(nil)
+	_ syntax.Validator = (*Config)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Config) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Config) Validate() error {
+	if args.Endpoint == "" {
+		return fmt.Errorf("endpoint must not be empty")
+	}
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+	for _, tag := range args.Tags {
+		if _, err := regexp.Compile(tag); err != nil {
+			return fmt.Errorf("invalid tags regex %q: %w", tag, err)
+		}
+	}
+	return nil
+}
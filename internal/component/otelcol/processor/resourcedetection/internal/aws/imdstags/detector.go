@@ -0,0 +1,146 @@
+package imdstags
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// tagsPath is the path of the tags of the instance in the instance
+	// metadata service.
+	tagsPath = "/latest/meta-data/tags/instance"
+
+	// tagAttributePrefix is the prefix of the resource attributes of the tags,
+	// which is the same as the one of the ec2 detector.
+	tagAttributePrefix = "ec2.tag."
+
+	tokenTTLSeconds = "21600"
+)
+
+// Detector reads the tags of the EC2 instance from the instance metadata
+// service (IMDS). Unlike the ec2 detector, it doesn't need the permission to
+// call the DescribeTags API, but access to tags must be enabled in the
+// metadata options of the instance.
+type Detector struct {
+	client   *http.Client
+	endpoint string
+	tags     []*regexp.Regexp
+}
+
+// NewDetector returns a Detector for the configuration. timeout limits the
+// duration of the requests to the instance metadata service. The
+// configuration must be valid.
+func (args Config) NewDetector(timeout time.Duration) *Detector {
+	tags := make([]*regexp.Regexp, 0, len(args.Tags))
+	for _, tag := range args.Tags {
+		tags = append(tags, regexp.MustCompile(tag))
+	}
+	return &Detector{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: strings.TrimSuffix(args.Endpoint, "/"),
+		tags:     tags,
+	}
+}
+
+// Detect returns the tags of the instance which match the configured regular
+// expressions, as ec2.tag.<key> attributes.
+func (d *Detector) Detect(ctx context.Context) (map[string]any, error) {
+	token, err := d.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get an IMDSv2 token: %w", err)
+	}
+
+	keys, err := d.get(ctx, token, tagsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tags of the instance: %w", err)
+	}
+
+	attrs := make(map[string]any)
+	scanner := bufio.NewScanner(bytes.NewReader(keys))
+	for scanner.Scan() {
+		key := scanner.Text()
+		if key == "" || !d.match(key) {
+			continue
+		}
+		value, err := d.get(ctx, token, tagsPath+"/"+url.PathEscape(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the value of tag %q: %w", key, err)
+		}
+		attrs[tagAttributePrefix+key] = string(value)
+	}
+	return attrs, scanner.Err()
+}
+
+func (d *Detector) match(key string) bool {
+	if len(d.tags) == 0 {
+		return true
+	}
+	for _, tag := range d.tags {
+		if tag.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// token returns an IMDSv2 session token. An empty token is returned when the
+// instance metadata service doesn't support IMDSv2, to fall back to IMDSv1.
+func (d *Detector) token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, d.endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", tokenTTLSeconds)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		token, err := io.ReadAll(resp.Body)
+		return string(token), err
+	case http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+func (d *Detector) get(ctx context.Context, token, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		if path == tagsPath {
+			return nil, fmt.Errorf("not found, check that access to tags in instance metadata is enabled for the instance")
+		}
+		return nil, fmt.Errorf("not found")
+	default:
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
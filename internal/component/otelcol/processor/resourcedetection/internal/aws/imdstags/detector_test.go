@@ -0,0 +1,91 @@
+package imdstags
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newIMDS returns a fake instance metadata service with the tags of an
+// instance. If token is empty, the service only supports IMDSv1.
+func newIMDS(t *testing.T, token string, tags map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(token))
+	})
+	mux.HandleFunc(tagsPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-aws-ec2-metadata-token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		value, ok := tags[r.URL.Path[len(tagsPath)+1:]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(value))
+	})
+	mux.HandleFunc(tagsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-aws-ec2-metadata-token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		for key := range tags {
+			_, _ = w.Write([]byte(key + "\n"))
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDetect(t *testing.T) {
+	tags := map[string]string{"team": "payments", "cost center": "42", "Name": "web-1"}
+
+	for _, token := range []string{"imdsv2-token", ""} {
+		srv := newIMDS(t, token, tags)
+
+		cfg := DefaultArguments
+		cfg.Endpoint = srv.URL
+		attrs, err := cfg.NewDetector(time.Second).Detect(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"ec2.tag.team":        "payments",
+			"ec2.tag.cost center": "42",
+			"ec2.tag.Name":        "web-1",
+		}, attrs)
+
+		cfg.Tags = []string{"^team$", "^cost"}
+		attrs, err = cfg.NewDetector(time.Second).Detect(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"ec2.tag.team":        "payments",
+			"ec2.tag.cost center": "42",
+		}, attrs)
+	}
+}
+
+func TestDetect_TagsDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest/api/token" {
+			_, _ = w.Write([]byte("token"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultArguments
+	cfg.Endpoint = srv.URL
+	_, err := cfg.NewDetector(time.Second).Detect(context.Background())
+	require.EqualError(t, err, "failed to list the tags of the instance: not found, check that access to tags in instance metadata is enabled for the instance")
+}
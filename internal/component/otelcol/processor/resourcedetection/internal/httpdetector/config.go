@@ -0,0 +1,58 @@
+package httpdetector
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+const Name = "http"
+
+// Config defines user-specified configurations unique to the http detector
+type Config struct {
+	// Endpoint is the URL which returns the resource attributes as a JSON object
+	Endpoint string `alloy:"endpoint,attr,optional"`
+
+	// Headers are added to the requests to the endpoint
+	Headers map[string]alloytypes.Secret `alloy:"headers,attr,optional"`
+
+	// RefreshInterval is the interval between requests to the endpoint.
+	// The last detected attributes are used in between.
+	RefreshInterval time.Duration `alloy:"refresh_interval,attr,optional"`
+}
+
+// DefaultArguments holds default settings for Config.
+var DefaultArguments = Config{
+	RefreshInterval: 5 * time.Minute,
+}
+
+var (
+	_ syntax.Defaulter = (*Config)(nil)
+	_ syntax.Validator = (*Config)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Config) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Config) Validate() error {
+	if args.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+	if args.Endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(args.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q of endpoint, must be http or https", u.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package httpdetector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxResponseSize is the maximum size of the responses of the endpoint.
+const maxResponseSize = 1 << 20
+
+// Detector fetches resource attributes from a user-provided endpoint, such as
+// an internal CMDB.
+type Detector struct {
+	client   *http.Client
+	endpoint string
+	headers  map[string]string
+}
+
+// NewDetector returns a Detector for the configuration. timeout limits the
+// duration of the requests to the endpoint.
+func (args Config) NewDetector(timeout time.Duration) *Detector {
+	headers := make(map[string]string, len(args.Headers))
+	for k, v := range args.Headers {
+		headers[k] = string(v)
+	}
+	return &Detector{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: args.Endpoint,
+		headers:  headers,
+	}
+}
+
+// Detect requests the resource attributes from the endpoint. The response
+// must be a JSON object, whose values are strings, numbers, or booleans. Null
+// values are ignored.
+func (d *Detector) Detect(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range d.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	dec := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize))
+	dec.UseNumber()
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("invalid response, must be a JSON object: %w", err)
+	}
+
+	attrs := make(map[string]any, len(values))
+	for k, v := range values {
+		switch v := v.(type) {
+		case nil:
+			continue
+		case string, bool:
+			attrs[k] = v
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				attrs[k] = i
+			} else if f, err := v.Float64(); err == nil {
+				attrs[k] = f
+			} else {
+				return nil, fmt.Errorf("invalid value of attribute %q: %w", k, err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported value of attribute %q, must be a string, number, or boolean", k)
+		}
+	}
+	return attrs, nil
+}
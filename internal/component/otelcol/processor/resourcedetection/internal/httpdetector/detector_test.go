@@ -0,0 +1,58 @@
+package httpdetector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"service.owner": "payments", "cmdb.ci_id": 1234, "cmdb.weight": 0.5, "cmdb.critical": true, "cmdb.retired": null}`))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultArguments
+	cfg.Endpoint = srv.URL
+	cfg.Headers = map[string]alloytypes.Secret{"Authorization": "Bearer secret"}
+
+	attrs, err := cfg.NewDetector(time.Second).Detect(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"service.owner": "payments",
+		"cmdb.ci_id":    int64(1234),
+		"cmdb.weight":   0.5,
+		"cmdb.critical": true,
+	}, attrs)
+
+	cfg.Headers = nil
+	_, err = cfg.NewDetector(time.Second).Detect(context.Background())
+	require.EqualError(t, err, "unexpected status 401 Unauthorized")
+}
+
+func TestDetect_InvalidResponse(t *testing.T) {
+	for body, expected := range map[string]string{
+		`["payments"]`:                  "invalid response, must be a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}",
+		`{"service.owner": ["a", "b"]}`: `unsupported value of attribute "service.owner", must be a string, number, or boolean`,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+
+		cfg := DefaultArguments
+		cfg.Endpoint = srv.URL
+		_, err := cfg.NewDetector(time.Second).Detect(context.Background())
+		require.EqualError(t, err, expected)
+
+		srv.Close()
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/aws/ecs"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/aws/eks"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/aws/elasticbeanstalk"
+	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/aws/imdstags"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/aws/lambda"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/azure"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/azure/aks"
@@ -19,13 +20,13 @@ import (
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/docker"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/gcp"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/heroku"
+	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/httpdetector"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/k8snode"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/openshift"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/resourcedetection/internal/system"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/syntax"
 	"github.com/mitchellh/mapstructure"
-	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor"
 	otelcomponent "go.opentelemetry.io/collector/component"
 	otelextension "go.opentelemetry.io/collector/extension"
 )
@@ -38,7 +39,7 @@ func init() {
 		Exports:   otelcol.ConsumerExports{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
-			fact := resourcedetectionprocessor.NewFactory()
+			fact := newFactory()
 			return processor.New(opts, fact, args.(Arguments))
 		},
 	})
@@ -59,6 +60,7 @@ type Arguments struct {
 
 	// HTTP client settings for the detector
 	// Timeout default is 5s
+	// Timeout also limits the requests of the http and imds_tags detectors.
 	Timeout time.Duration `alloy:"timeout,attr,optional"`
 	// Client otelcol.HTTPClientArguments `alloy:",squash"`
 	//TODO: Uncomment this later, and remove Timeout?
@@ -118,6 +120,12 @@ type DetectorConfig struct {
 
 	// KubernetesNode contains user-specified configurations for the K8SNode detector
 	KubernetesNodeConfig k8snode.Config `alloy:"kubernetes_node,block,optional"`
+
+	// HTTPConfig contains user-specified configurations for the http detector
+	HTTPConfig httpdetector.Config `alloy:"http,block,optional"`
+
+	// IMDSTagsConfig contains user-specified configurations for the imds_tags detector
+	IMDSTagsConfig imdstags.Config `alloy:"imds_tags,block,optional"`
 }
 
 func (dc *DetectorConfig) SetToDefault() {
@@ -135,6 +143,8 @@ func (dc *DetectorConfig) SetToDefault() {
 		HerokuConfig:           heroku.DefaultArguments,
 		OpenShiftConfig:        openshift.DefaultArguments,
 		KubernetesNodeConfig:   k8snode.DefaultArguments,
+		HTTPConfig:             httpdetector.DefaultArguments,
+		IMDSTagsConfig:         imdstags.DefaultArguments,
 	}
 	dc.SystemConfig.SetToDefault()
 }
@@ -178,8 +188,13 @@ func (args *Arguments) Validate() error {
 			heroku.Name,
 			system.Name,
 			openshift.Name,
-			k8snode.Name:
+			k8snode.Name,
+			imdstags.Name:
 		// Valid option - nothing to do
+		case httpdetector.Name:
+			if args.DetectorConfig.HTTPConfig.Endpoint == "" {
+				return fmt.Errorf("the http block must set endpoint to use the http detector")
+			}
 		default:
 			return fmt.Errorf("invalid detector: %s", detector)
 		}
@@ -188,6 +203,7 @@ func (args *Arguments) Validate() error {
 	return nil
 }
 
+// ConvertDetectors returns the detectors run by the upstream processor.
 func (args Arguments) ConvertDetectors() []string {
 	if args.Detectors == nil {
 		return nil
@@ -198,6 +214,8 @@ func (args Arguments) ConvertDetectors() []string {
 		switch detector {
 		case k8snode.Name:
 			res = append(res, "k8snode")
+		case httpdetector.Name, imdstags.Name:
+			// Run by Alloy, see customDetectors.
 		default:
 			res = append(res, detector)
 		}
@@ -228,16 +246,45 @@ func (args Arguments) Convert() (otelcomponent.Config, error) {
 	input["openshift"] = args.DetectorConfig.OpenShiftConfig.Convert()
 	input["k8snode"] = args.DetectorConfig.KubernetesNodeConfig.Convert()
 
-	var result resourcedetectionprocessor.Config
-	err := mapstructure.Decode(input, &result)
+	var result Config
+	err := mapstructure.Decode(input, &result.Upstream)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if detectors := args.customDetectors(); len(detectors) > 0 {
+		result.custom = newCustomResource(detectors)
+	}
+
 	return &result, nil
 }
 
+// customDetectors returns the detectors which the upstream processor doesn't
+// support, in the order they're listed.
+func (args Arguments) customDetectors() []customDetector {
+	var res []customDetector
+	for _, detector := range args.Detectors {
+		switch detector {
+		case httpdetector.Name:
+			cfg := args.DetectorConfig.HTTPConfig
+			res = append(res, customDetector{
+				name:            detector,
+				detect:          cfg.NewDetector(args.Timeout).Detect,
+				refreshInterval: cfg.RefreshInterval,
+			})
+		case imdstags.Name:
+			cfg := args.DetectorConfig.IMDSTagsConfig
+			res = append(res, customDetector{
+				name:            detector,
+				detect:          cfg.NewDetector(args.Timeout).Detect,
+				refreshInterval: cfg.RefreshInterval,
+			})
+		}
+	}
+	return res
+}
+
 // Extensions implements processor.Arguments.
 func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
 	return nil
@@ -1249,6 +1249,69 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 				"openshift":        openshift.DefaultArguments.Convert(),
 			},
 		},
+		{
+			testName: "http_without_endpoint",
+			cfg: `
+			detectors = ["http"]
+			output {}
+			`,
+			errorMsg: "the http block must set endpoint to use the http detector",
+		},
+		{
+			testName: "http_invalid_endpoint",
+			cfg: `
+			detectors = ["http"]
+			http {
+				endpoint = "ftp://cmdb.example.com/attributes"
+			}
+			output {}
+			`,
+			errorMsg: `unsupported scheme "ftp" of endpoint, must be http or https`,
+		},
+		{
+			testName: "imds_tags_invalid_regex",
+			cfg: `
+			detectors = ["imds_tags"]
+			imds_tags {
+				tags = ["team("]
+			}
+			output {}
+			`,
+			errorMsg: "invalid tags regex \"team(\"",
+		},
+		{
+			testName: "custom_detectors_are_not_passed_upstream",
+			cfg: `
+			detectors = ["http", "env", "imds_tags", "system"]
+			http {
+				endpoint         = "http://cmdb.example.com/attributes"
+				refresh_interval = "1h"
+			}
+			imds_tags {
+				tags = ["^team$"]
+			}
+			output {}
+			`,
+			expected: map[string]interface{}{
+				"detectors":        []string{"env", "system"},
+				"timeout":          5 * time.Second,
+				"override":         true,
+				"ec2":              ec2.DefaultArguments.Convert(),
+				"ecs":              ecs.DefaultArguments.Convert(),
+				"eks":              eks.DefaultArguments.Convert(),
+				"elasticbeanstalk": elasticbeanstalk.DefaultArguments.Convert(),
+				"lambda":           lambda.DefaultArguments.Convert(),
+				"azure":            azure.DefaultArguments.Convert(),
+				"aks":              aks.DefaultArguments.Convert(),
+				"consul":           consul.DefaultArguments.Convert(),
+				"docker":           docker.DefaultArguments.Convert(),
+				"gcp":              gcp.DefaultArguments.Convert(),
+				"heroku":           heroku.DefaultArguments.Convert(),
+				"system":           defaultArgs.Convert(),
+				"openshift":        openshift.DefaultArguments.Convert(),
+				"k8snode":          kubernetes_node.DefaultArguments.Convert(),
+			},
+		},
 		{
 			testName: "system_invalid_hostname_source",
 			cfg: `
@@ -1507,13 +1570,13 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 			actualPtr, err := args.Convert()
 			require.NoError(t, err)
 
-			actual := actualPtr.(*resourcedetectionprocessor.Config)
+			actual := actualPtr.(*resourcedetection.Config)
 
 			var expected resourcedetectionprocessor.Config
 			err = mapstructure.Decode(tc.expected, &expected)
 			require.NoError(t, err)
 
-			require.Equal(t, expected, *actual)
+			require.Equal(t, expected, actual.Upstream)
 		})
 	}
 }
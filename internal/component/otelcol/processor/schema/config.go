@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines the configuration options for the schema processor.
+type Config struct {
+	// Targets are the schema URLs to translate the telemetry data to. There's
+	// at most one target per schema family.
+	Targets []string `mapstructure:"targets"`
+	// Prefetch are the schema URLs whose schema files are fetched at start.
+	Prefetch []string `mapstructure:"prefetch"`
+	// Timeout is the timeout of the requests which fetch the schema files.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+var _ component.ConfigValidator = (*Config)(nil)
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Targets) == 0 {
+		return fmt.Errorf("at least one target must be set")
+	}
+
+	families := make(map[string]struct{}, len(c.Targets))
+	for _, target := range c.Targets {
+		id, err := parseSchemaURL(target)
+		if err != nil {
+			return fmt.Errorf("targets: %w", err)
+		}
+		if _, ok := families[id.family]; ok {
+			return fmt.Errorf("targets: schema family %q has more than one target", id.family)
+		}
+		families[id.family] = struct{}{}
+	}
+
+	for _, url := range c.Prefetch {
+		if _, err := parseSchemaURL(url); err != nil {
+			return fmt.Errorf("prefetch: %w", err)
+		}
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be a positive duration (got %s)", c.Timeout)
+	}
+	return nil
+}
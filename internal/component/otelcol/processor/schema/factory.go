@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	typeStr = "schema"
+)
+
+var processorCapabilities = consumer.Capabilities{MutatesData: true}
+
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		processor.WithTraces(createTracesProcessor, component.StabilityLevelDevelopment),
+		processor.WithMetrics(createMetricsProcessor, component.StabilityLevelDevelopment),
+		processor.WithLogs(createLogsProcessor, component.StabilityLevelDevelopment),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Timeout: 30 * time.Second,
+	}
+}
+
+func createTracesProcessor(ctx context.Context, set processor.CreateSettings, cfg component.Config, next consumer.Traces) (processor.Traces, error) {
+	p := newSchemaProcessor(set.TelemetrySettings, cfg.(*Config))
+	return processorhelper.NewTracesProcessor(ctx, set, cfg, next, p.processTraces,
+		processorhelper.WithStart(p.start),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createMetricsProcessor(ctx context.Context, set processor.CreateSettings, cfg component.Config, next consumer.Metrics) (processor.Metrics, error) {
+	p := newSchemaProcessor(set.TelemetrySettings, cfg.(*Config))
+	return processorhelper.NewMetricsProcessor(ctx, set, cfg, next, p.processMetrics,
+		processorhelper.WithStart(p.start),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createLogsProcessor(ctx context.Context, set processor.CreateSettings, cfg component.Config, next consumer.Logs) (processor.Logs, error) {
+	p := newSchemaProcessor(set.TelemetrySettings, cfg.(*Config))
+	return processorhelper.NewLogsProcessor(ctx, set, cfg, next, p.processLogs,
+		processorhelper.WithStart(p.start),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
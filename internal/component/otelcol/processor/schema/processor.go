@@ -0,0 +1,271 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxSchemaFileSize is the maximum size of a schema file.
+	maxSchemaFileSize = 4 << 20
+
+	// retryInterval is how long to wait before fetching a schema file again
+	// after a failure.
+	retryInterval = time.Minute
+)
+
+var errFetchFailed = errors.New("fetching the schema file failed recently")
+
+type schemaProcessor struct {
+	logger   *zap.Logger
+	client   *http.Client
+	targets  map[string]schemaID // By family.
+	prefetch []string
+
+	mut          sync.Mutex
+	files        map[string]*schemaFile // By URL.
+	failures     map[string]time.Time   // Last fetch failure by URL.
+	translations map[string]*target     // By source schema URL.
+}
+
+// target is the translation of telemetry data with a schema URL to its
+// target schema URL. A nil target means that the telemetry data is left as
+// is.
+type target struct {
+	translation *translation
+	schemaURL   string
+}
+
+func newSchemaProcessor(set component.TelemetrySettings, cfg *Config) *schemaProcessor {
+	targets := make(map[string]schemaID, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		// The config was validated.
+		id, _ := parseSchemaURL(t)
+		targets[id.family] = id
+	}
+
+	return &schemaProcessor{
+		logger:       set.Logger,
+		client:       &http.Client{Timeout: cfg.Timeout},
+		targets:      targets,
+		prefetch:     cfg.Prefetch,
+		files:        make(map[string]*schemaFile),
+		failures:     make(map[string]time.Time),
+		translations: make(map[string]*target),
+	}
+}
+
+// start fetches the schema files of the targets and of the prefetched schema
+// URLs. Failures are only logged, the schema files are fetched again when
+// they're needed.
+func (p *schemaProcessor) start(ctx context.Context, _ component.Host) error {
+	urls := append([]string(nil), p.prefetch...)
+	for _, t := range p.targets {
+		urls = append(urls, t.String())
+	}
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	for _, u := range urls {
+		_, _ = p.schemaFile(ctx, u)
+	}
+	return nil
+}
+
+func (p *schemaProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceURL := rs.SchemaUrl()
+		if t := p.target(ctx, resourceURL); t != nil {
+			t.translation.resource(rs.Resource())
+			rs.SetSchemaUrl(t.schemaURL)
+		}
+
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			ss := sss.At(j)
+			t := p.target(ctx, scopeSchemaURL(ss.SchemaUrl(), resourceURL))
+			if t == nil {
+				continue
+			}
+			spans := ss.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				t.translation.span(spans.At(k))
+			}
+			if ss.SchemaUrl() != "" {
+				ss.SetSchemaUrl(t.schemaURL)
+			}
+		}
+	}
+	return td, nil
+}
+
+func (p *schemaProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceURL := rm.SchemaUrl()
+		if t := p.target(ctx, resourceURL); t != nil {
+			t.translation.resource(rm.Resource())
+			rm.SetSchemaUrl(t.schemaURL)
+		}
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			t := p.target(ctx, scopeSchemaURL(sm.SchemaUrl(), resourceURL))
+			if t == nil {
+				continue
+			}
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				t.translation.metric(metrics.At(k))
+			}
+			if sm.SchemaUrl() != "" {
+				sm.SetSchemaUrl(t.schemaURL)
+			}
+		}
+	}
+	return md, nil
+}
+
+func (p *schemaProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceURL := rl.SchemaUrl()
+		if t := p.target(ctx, resourceURL); t != nil {
+			t.translation.resource(rl.Resource())
+			rl.SetSchemaUrl(t.schemaURL)
+		}
+
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			t := p.target(ctx, scopeSchemaURL(sl.SchemaUrl(), resourceURL))
+			if t == nil {
+				continue
+			}
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				t.translation.log(records.At(k))
+			}
+			if sl.SchemaUrl() != "" {
+				sl.SetSchemaUrl(t.schemaURL)
+			}
+		}
+	}
+	return ld, nil
+}
+
+// scopeSchemaURL returns the schema URL of the telemetry data of a scope,
+// which defaults to the schema URL of its resource.
+func scopeSchemaURL(scopeURL, resourceURL string) string {
+	if scopeURL != "" {
+		return scopeURL
+	}
+	return resourceURL
+}
+
+// target returns the target of telemetry data with the schema URL
+// schemaURL, or nil if the telemetry data must be left as is.
+func (p *schemaProcessor) target(ctx context.Context, schemaURL string) *target {
+	if schemaURL == "" {
+		return nil
+	}
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if t, ok := p.translations[schemaURL]; ok {
+		return t
+	}
+
+	from, err := parseSchemaURL(schemaURL)
+	if err != nil {
+		p.translations[schemaURL] = nil
+		return nil
+	}
+	to, ok := p.targets[from.family]
+	if !ok || from.version.EQ(to.version) {
+		p.translations[schemaURL] = nil
+		return nil
+	}
+
+	// A schema file includes the changes of all the previous versions.
+	latest := to
+	if from.version.GT(to.version) {
+		latest = from
+	}
+	file, err := p.schemaFile(ctx, latest.String())
+	if err != nil {
+		// The schema file is fetched again later.
+		return nil
+	}
+
+	translation, err := file.translation(from.version, to.version)
+	if err != nil {
+		p.logger.Warn("telemetry data can't be translated to the target schema",
+			zap.String("schema_url", schemaURL), zap.String("target", to.String()), zap.Error(err))
+		p.translations[schemaURL] = nil
+		return nil
+	}
+
+	t := &target{translation: translation, schemaURL: to.String()}
+	p.translations[schemaURL] = t
+	return t
+}
+
+// schemaFile returns the schema file of a schema URL, and fetches it if it
+// wasn't fetched yet. p.mut must be held.
+func (p *schemaProcessor) schemaFile(ctx context.Context, schemaURL string) (*schemaFile, error) {
+	if file, ok := p.files[schemaURL]; ok {
+		return file, nil
+	}
+	if failure, ok := p.failures[schemaURL]; ok && time.Since(failure) < retryInterval {
+		return nil, errFetchFailed
+	}
+
+	file, err := p.fetch(ctx, schemaURL)
+	if err != nil {
+		p.logger.Warn("failed to fetch schema file", zap.String("schema_url", schemaURL), zap.Error(err))
+		p.failures[schemaURL] = time.Now()
+		return nil, err
+	}
+	delete(p.failures, schemaURL)
+	p.files[schemaURL] = file
+	return file, nil
+}
+
+func (p *schemaProcessor) fetch(ctx context.Context, schemaURL string) (*schemaFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSchemaFileSize))
+	if err != nil {
+		return nil, err
+	}
+	return parseSchemaFile(data)
+}
@@ -0,0 +1,161 @@
+package schema
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const testSchemaFile = `
+file_format: 1.1.0
+schema_url: https://example.com/schemas/1.2.0
+versions:
+  1.2.0:
+    all:
+      changes:
+        - rename_attributes:
+            attribute_map:
+              http.method: http.request.method
+    spans:
+      changes:
+        - rename_attributes:
+            attribute_map:
+              http.target: url.path
+            apply_to_spans: [GET /checkout]
+    span_events:
+      changes:
+        - rename_events:
+            name_map:
+              exception.thrown: exception
+    metrics:
+      changes:
+        - rename_metrics:
+            http.server.duration: http.server.request.duration
+  1.1.0:
+    resources:
+      changes:
+        - rename_attributes:
+            attribute_map:
+              telemetry.auto.version: telemetry.distro.version
+  1.0.0:
+`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testSchemaFile))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestProcessor(t *testing.T, target string) *schemaProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []string{target}
+	require.NoError(t, cfg.Validate())
+
+	p := newSchemaProcessor(componenttest.NewNopTelemetrySettings(), cfg)
+	require.NoError(t, p.start(context.Background(), componenttest.NewNopHost()))
+	return p
+}
+
+func TestProcessor_Upgrade(t *testing.T) {
+	srv := newTestServer(t)
+	p := newTestProcessor(t, srv.URL+"/schemas/1.2.0")
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.SetSchemaUrl(srv.URL + "/schemas/1.0.0")
+	rs.Resource().Attributes().PutStr("telemetry.auto.version", "1.0")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /checkout")
+	span.Attributes().PutStr("http.method", "GET")
+	span.Attributes().PutStr("http.target", "/checkout")
+	span.Events().AppendEmpty().SetName("exception.thrown")
+
+	_, err := p.processTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	require.Equal(t, srv.URL+"/schemas/1.2.0", rs.SchemaUrl())
+	require.Equal(t, map[string]any{"telemetry.distro.version": "1.0"}, rs.Resource().Attributes().AsRaw())
+	require.Equal(t, map[string]any{"http.request.method": "GET", "url.path": "/checkout"}, span.Attributes().AsRaw())
+	require.Equal(t, "exception", span.Events().At(0).Name())
+}
+
+func TestProcessor_Downgrade(t *testing.T) {
+	srv := newTestServer(t)
+	p := newTestProcessor(t, srv.URL+"/schemas/1.1.0")
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.SetSchemaUrl(srv.URL + "/schemas/1.2.0")
+	metric := sm.Metrics().AppendEmpty()
+	metric.SetName("http.server.request.duration")
+	metric.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("http.request.method", "GET")
+
+	_, err := p.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	require.Equal(t, srv.URL+"/schemas/1.1.0", sm.SchemaUrl())
+	require.Equal(t, "http.server.duration", metric.Name())
+	require.Equal(t, map[string]any{"http.method": "GET"}, metric.Gauge().DataPoints().At(0).Attributes().AsRaw())
+}
+
+func TestProcessor_OtherFamily(t *testing.T) {
+	srv := newTestServer(t)
+	p := newTestProcessor(t, srv.URL+"/schemas/1.2.0")
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.SetSchemaUrl("https://example.org/schemas/1.0.0")
+	record := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Attributes().PutStr("http.method", "GET")
+
+	_, err := p.processLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://example.org/schemas/1.0.0", rl.SchemaUrl())
+	require.Equal(t, map[string]any{"http.method": "GET"}, record.Attributes().AsRaw())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []string
+		errorMsg string
+	}{
+		{
+			name:     "NoTargets",
+			errorMsg: "at least one target must be set",
+		},
+		{
+			name:     "NoVersion",
+			targets:  []string{"https://opentelemetry.io/schemas/latest"},
+			errorMsg: `targets: invalid schema URL "https://opentelemetry.io/schemas/latest": the last path segment must be a version: No Major.Minor.Patch elements found`,
+		},
+		{
+			name:     "SameFamily",
+			targets:  []string{"https://opentelemetry.io/schemas/1.26.0", "https://opentelemetry.io/schemas/1.21.0"},
+			errorMsg: `targets: schema family "https://opentelemetry.io/schemas" has more than one target`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Targets = tc.targets
+			require.EqualError(t, cfg.Validate(), tc.errorMsg)
+		})
+	}
+}
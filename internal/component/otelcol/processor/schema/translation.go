@@ -0,0 +1,304 @@
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaID is a schema URL split into its family and its version, like
+// https://opentelemetry.io/schemas and 1.26.0.
+type schemaID struct {
+	family  string
+	version semver.Version
+}
+
+func parseSchemaURL(schemaURL string) (schemaID, error) {
+	u, err := url.Parse(schemaURL)
+	if err != nil {
+		return schemaID{}, fmt.Errorf("invalid schema URL %q: %w", schemaURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return schemaID{}, fmt.Errorf("invalid schema URL %q: scheme must be http or https", schemaURL)
+	}
+
+	i := strings.LastIndex(schemaURL, "/")
+	version, err := semver.Parse(schemaURL[i+1:])
+	if err != nil {
+		return schemaID{}, fmt.Errorf("invalid schema URL %q: the last path segment must be a version: %w", schemaURL, err)
+	}
+	return schemaID{family: schemaURL[:i], version: version}, nil
+}
+
+func (id schemaID) String() string {
+	return id.family + "/" + id.version.String()
+}
+
+// schemaFile is a parsed schema file. See
+// https://opentelemetry.io/docs/specs/otel/schemas/file_format_v1.1.0/.
+type schemaFile struct {
+	// versions holds the changes of each version, sorted by version.
+	versions []versionChanges
+}
+
+type versionChanges struct {
+	version semver.Version
+	changes versionDef
+}
+
+type schemaFileDef struct {
+	FileFormat string                `yaml:"file_format"`
+	SchemaURL  string                `yaml:"schema_url"`
+	Versions   map[string]versionDef `yaml:"versions"`
+}
+
+// versionDef holds the changes from the previous version to a version.
+type versionDef struct {
+	All        changeSet `yaml:"all"`
+	Resources  changeSet `yaml:"resources"`
+	Spans      changeSet `yaml:"spans"`
+	SpanEvents changeSet `yaml:"span_events"`
+	Metrics    changeSet `yaml:"metrics"`
+	Logs       changeSet `yaml:"logs"`
+}
+
+type changeSet struct {
+	Changes []change `yaml:"changes"`
+}
+
+type change struct {
+	RenameAttributes *renameAttributes `yaml:"rename_attributes"`
+	RenameEvents     *renameEvents     `yaml:"rename_events"`
+	RenameMetrics    map[string]string `yaml:"rename_metrics"`
+}
+
+type renameAttributes struct {
+	AttributeMap   map[string]string `yaml:"attribute_map"`
+	ApplyToSpans   []string          `yaml:"apply_to_spans"`
+	ApplyToEvents  []string          `yaml:"apply_to_events"`
+	ApplyToMetrics []string          `yaml:"apply_to_metrics"`
+}
+
+type renameEvents struct {
+	NameMap map[string]string `yaml:"name_map"`
+}
+
+func parseSchemaFile(data []byte) (*schemaFile, error) {
+	var def schemaFileDef
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("parsing schema file: %w", err)
+	}
+
+	format, err := semver.Parse(def.FileFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file_format %q: %w", def.FileFormat, err)
+	}
+	if format.Major != 1 {
+		return nil, fmt.Errorf("unsupported file_format %q", def.FileFormat)
+	}
+
+	file := &schemaFile{versions: make([]versionChanges, 0, len(def.Versions))}
+	for v, changes := range def.Versions {
+		version, err := semver.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", v, err)
+		}
+		file.versions = append(file.versions, versionChanges{version: version, changes: changes})
+	}
+	slices.SortFunc(file.versions, func(a, b versionChanges) int {
+		return a.version.Compare(b.version)
+	})
+	return file, nil
+}
+
+// translation returns the translation of telemetry data from a version to
+// another. The file must include the most recent of the two versions.
+func (f *schemaFile) translation(from, to semver.Version) (*translation, error) {
+	latest := from
+	if to.GT(latest) {
+		latest = to
+	}
+	if len(f.versions) == 0 || f.versions[len(f.versions)-1].version.LT(latest) {
+		return nil, fmt.Errorf("the schema file doesn't include version %s", latest)
+	}
+
+	var t translation
+	if from.LT(to) {
+		for _, v := range f.versions {
+			if v.version.GT(from) && v.version.LTE(to) {
+				t.steps = append(t.steps, step{changes: v.changes})
+			}
+		}
+	} else {
+		for i := len(f.versions) - 1; i >= 0; i-- {
+			v := f.versions[i]
+			if v.version.GT(to) && v.version.LTE(from) {
+				t.steps = append(t.steps, step{changes: v.changes, reverse: true})
+			}
+		}
+	}
+	return &t, nil
+}
+
+// translation applies the changes of a range of versions to telemetry data.
+type translation struct {
+	steps []step
+}
+
+// step applies the changes of a version, or reverts them.
+type step struct {
+	changes versionDef
+	reverse bool
+}
+
+// ordered returns the changes of the all section followed by the changes of
+// section, or in the reverse order when the step is reverted.
+func (s step) ordered(section changeSet) []change {
+	changes := make([]change, 0, len(s.changes.All.Changes)+len(section.Changes))
+	changes = append(changes, s.changes.All.Changes...)
+	changes = append(changes, section.Changes...)
+	if s.reverse {
+		slices.Reverse(changes)
+	}
+	return changes
+}
+
+func (t *translation) resource(res pcommon.Resource) {
+	for _, s := range t.steps {
+		for _, c := range s.ordered(s.changes.Resources) {
+			if c.RenameAttributes != nil {
+				renameAttrs(res.Attributes(), c.RenameAttributes.AttributeMap, s.reverse)
+			}
+		}
+	}
+}
+
+func (t *translation) span(span ptrace.Span) {
+	for _, s := range t.steps {
+		for _, c := range s.ordered(s.changes.Spans) {
+			if ra := c.RenameAttributes; ra != nil && appliesTo(ra.ApplyToSpans, span.Name()) {
+				renameAttrs(span.Attributes(), ra.AttributeMap, s.reverse)
+			}
+		}
+
+		events := span.Events()
+		for i := 0; i < events.Len(); i++ {
+			event := events.At(i)
+			for _, c := range s.ordered(s.changes.SpanEvents) {
+				if re := c.RenameEvents; re != nil {
+					if name, ok := rename(event.Name(), re.NameMap, s.reverse); ok {
+						event.SetName(name)
+					}
+				}
+				if ra := c.RenameAttributes; ra != nil && appliesTo(ra.ApplyToSpans, span.Name()) && appliesTo(ra.ApplyToEvents, event.Name()) {
+					renameAttrs(event.Attributes(), ra.AttributeMap, s.reverse)
+				}
+			}
+		}
+	}
+}
+
+func (t *translation) metric(metric pmetric.Metric) {
+	for _, s := range t.steps {
+		for _, c := range s.ordered(s.changes.Metrics) {
+			if name, ok := rename(metric.Name(), c.RenameMetrics, s.reverse); ok {
+				metric.SetName(name)
+			}
+			if ra := c.RenameAttributes; ra != nil && appliesTo(ra.ApplyToMetrics, metric.Name()) {
+				forEachDataPoint(metric, func(attrs pcommon.Map) {
+					renameAttrs(attrs, ra.AttributeMap, s.reverse)
+				})
+			}
+		}
+	}
+}
+
+func (t *translation) log(record plog.LogRecord) {
+	for _, s := range t.steps {
+		for _, c := range s.ordered(s.changes.Logs) {
+			if c.RenameAttributes != nil {
+				renameAttrs(record.Attributes(), c.RenameAttributes.AttributeMap, s.reverse)
+			}
+		}
+	}
+}
+
+// appliesTo returns whether a change restricted to names applies to name. A
+// change without names applies to everything.
+func appliesTo(names []string, name string) bool {
+	return len(names) == 0 || slices.Contains(names, name)
+}
+
+// rename returns the new name of name in renames, which maps old names to new
+// names.
+func rename(name string, renames map[string]string, reverse bool) (string, bool) {
+	if !reverse {
+		newName, ok := renames[name]
+		return newName, ok
+	}
+	for oldName, newName := range renames {
+		if newName == name {
+			return oldName, true
+		}
+	}
+	return "", false
+}
+
+// renameAttrs renames the attributes of attrs. An attribute isn't renamed if
+// the new key is already set.
+func renameAttrs(attrs pcommon.Map, renames map[string]string, reverse bool) {
+	for oldKey, newKey := range renames {
+		if reverse {
+			oldKey, newKey = newKey, oldKey
+		}
+		v, ok := attrs.Get(oldKey)
+		if !ok {
+			continue
+		}
+		if _, ok := attrs.Get(newKey); ok {
+			continue
+		}
+		value := pcommon.NewValueEmpty()
+		v.CopyTo(value)
+		attrs.Remove(oldKey)
+		value.CopyTo(attrs.PutEmpty(newKey))
+	}
+}
+
+func forEachDataPoint(metric pmetric.Metric, f func(attrs pcommon.Map)) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	}
+}
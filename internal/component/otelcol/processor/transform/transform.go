@@ -10,6 +10,7 @@ import (
 	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
 	"github.com/grafana/alloy/internal/component/otelcol/processor"
 	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax/vm"
 	"github.com/mitchellh/mapstructure"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
@@ -54,6 +55,20 @@ func (c *ContextID) UnmarshalText(text []byte) error {
 	}
 }
 
+// statementsKey returns the key of the processor configuration under which
+// statements for the context are validated. Resource and scope statements
+// support the same functions for all signals.
+func (c ContextID) statementsKey() string {
+	switch c {
+	case Metric, DataPoint:
+		return "metric_statements"
+	case Log:
+		return "log_statements"
+	default:
+		return "trace_statements"
+	}
+}
+
 type ContextStatementsSlice []ContextStatements
 
 type ContextStatements struct {
@@ -61,6 +76,37 @@ type ContextStatements struct {
 	Statements []string  `alloy:"statements,attr"`
 }
 
+// Validate implements syntax.Validator. Invalid statements are reported at
+// their position in the configuration file.
+func (args *ContextStatements) Validate() error {
+	if validateStatements(args.Context, args.Statements) == nil {
+		return nil
+	}
+
+	// Find the offending statement.
+	for i, stmt := range args.Statements {
+		if err := validateStatements(args.Context, []string{stmt}); err != nil {
+			return vm.AttributeError{Name: "statements", Index: i, Inner: err}
+		}
+	}
+	return nil
+}
+
+// validateStatements parses the OTTL statements for the context.
+func validateStatements(context ContextID, statements []string) error {
+	input := map[string]interface{}{
+		context.statementsKey(): []interface{}{
+			(&ContextStatements{Context: context, Statements: statements}).convert(),
+		},
+	}
+
+	var result transformprocessor.Config
+	if err := mapstructure.Decode(input, &result); err != nil {
+		return err
+	}
+	return result.Validate()
+}
+
 // Arguments configures the otelcol.processor.transform component.
 type Arguments struct {
 	// ErrorMode determines how the processor reacts to errors that occur while processing a statement.
@@ -0,0 +1,276 @@
+// Package file provides an otelcol.receiver.file component.
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	otelconsumer "go.opentelemetry.io/collector/consumer"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fanoutconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/otlpfile"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.file",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(o component.Options, a component.Arguments) (component.Component, error) {
+			return New(o, a.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.file component.
+type Arguments struct {
+	Include      []string      `alloy:"include,attr"`
+	Format       string        `alloy:"format,attr,optional"`
+	PollInterval time.Duration `alloy:"poll_interval,attr,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// DefaultArguments holds default values for Arguments.
+var DefaultArguments = Arguments{
+	Format:       otlpfile.FormatJSON,
+	PollInterval: 10 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if len(args.Include) == 0 {
+		return fmt.Errorf("include must not be empty")
+	}
+	for _, pattern := range args.Include {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+	}
+	if err := otlpfile.ValidateFormat(args.Format); err != nil {
+		return err
+	}
+	if args.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval must be greater than 0")
+	}
+	return nil
+}
+
+// Component is the otelcol.receiver.file component.
+type Component struct {
+	opts component.Options
+
+	mut  sync.RWMutex
+	args Arguments
+
+	updated chan struct{}
+
+	// offsets holds the number of bytes read from each file. Compressed files
+	// are read at once and their offset is set to -1.
+	offsets map[string]int64
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new otelcol.receiver.file component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts:    o,
+		updated: make(chan struct{}, 1),
+		offsets: make(map[string]int64),
+	}
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements Component.
+func (c *Component) Run(ctx context.Context) error {
+	c.mut.RLock()
+	ticker := time.NewTicker(c.args.PollInterval)
+	c.mut.RUnlock()
+	defer ticker.Stop()
+
+	for {
+		c.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.updated:
+			c.mut.RLock()
+			ticker.Reset(c.args.PollInterval)
+			c.mut.RUnlock()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Update implements Component.
+func (c *Component) Update(newConfig component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = newConfig.(Arguments)
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// poll reads the new records of the files matching the include patterns.
+func (c *Component) poll(ctx context.Context) {
+	c.mut.RLock()
+	args := c.args
+	c.mut.RUnlock()
+
+	var paths []string
+	for _, pattern := range args.Include {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			level.Error(c.opts.Logger).Log("msg", "invalid include pattern", "pattern", pattern, "err", err)
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	// Forget the files which don't exist anymore.
+	for path := range c.offsets {
+		if _, found := slices.BinarySearch(paths, path); !found {
+			delete(c.offsets, path)
+		}
+	}
+
+	sinks := newSinks(args.Output)
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := c.readFile(ctx, path, args, sinks); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to read file", "path", path, "err", err)
+		}
+	}
+}
+
+// readFile sends the records of path which weren't read yet to the output.
+// A record which can't be consumed is retried on the next poll.
+//
+// Offsets are tracked in bytes of uncompressed data. Compressed files are
+// expected to be complete, and aren't read anymore once fully consumed.
+func (c *Component) readFile(ctx context.Context, path string, args Arguments, sinks sinks) error {
+	offset := c.offsets[path]
+	if offset < 0 {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	compressed := strings.HasSuffix(path, ".gz")
+	if compressed {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		if _, err := io.CopyN(io.Discard, gz, offset); err != nil {
+			return err
+		}
+		r = gz
+	} else {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		// The file was truncated or replaced by a smaller one.
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			return nil
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	dec := otlpfile.NewDecoder(args.Format, r)
+	var consumed int64
+	defer func() { c.offsets[path] = offset + consumed }()
+
+	for {
+		record, err := dec.Next()
+		switch {
+		case errors.Is(err, io.EOF) && compressed:
+			offset, consumed = -1, 0
+			return nil
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) && !compressed:
+			// The rest of the file may still be being written.
+			return nil
+		case err != nil:
+			// Skip the invalid record if possible.
+			consumed = dec.Offset()
+			return err
+		}
+
+		if err := sinks.consume(ctx, record); err != nil {
+			return err
+		}
+		consumed = dec.Offset()
+	}
+}
+
+// sinks holds the consumers of the records.
+type sinks struct {
+	traces  otelconsumer.Traces
+	metrics otelconsumer.Metrics
+	logs    otelconsumer.Logs
+}
+
+func newSinks(output *otelcol.ConsumerArguments) sinks {
+	return sinks{
+		traces:  fanoutconsumer.Traces(output.Traces),
+		metrics: fanoutconsumer.Metrics(output.Metrics),
+		logs:    fanoutconsumer.Logs(output.Logs),
+	}
+}
+
+func (s sinks) consume(ctx context.Context, record otlpfile.Record) error {
+	switch {
+	case record.Traces != nil:
+		return s.traces.ConsumeTraces(ctx, *record.Traces)
+	case record.Metrics != nil:
+		return s.metrics.ConsumeMetrics(ctx, *record.Metrics)
+	case record.Logs != nil:
+		return s.logs.ConsumeLogs(ctx, *record.Logs)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/otlpfile"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+		include = ["/tmp/capture/*.json"]
+		format  = "xml"
+		output {}
+	`), &args)
+	require.ErrorContains(t, err, `format must be one of "json" or "proto", got "xml"`)
+}
+
+func TestReadFiles(t *testing.T) {
+	for _, format := range []string{otlpfile.FormatJSON, otlpfile.FormatProto} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			live := filepath.Join(dir, "capture")
+			rotated := filepath.Join(dir, "capture.20240102T030405.000000000.gz")
+
+			writeLogs(t, format, live, false, "a", "b")
+			writeLogs(t, format, rotated, true, "c")
+
+			var (
+				received []string
+				fail     bool
+			)
+			consumer := &fakeconsumer.Consumer{
+				ConsumeLogsFunc: func(_ context.Context, ld plog.Logs) error {
+					if fail {
+						return errors.New("unavailable")
+					}
+					received = append(received, ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+					return nil
+				},
+			}
+
+			c, err := New(component.Options{Logger: util.TestAlloyLogger(t)}, Arguments{
+				Include:      []string{filepath.Join(dir, "capture*")},
+				Format:       format,
+				PollInterval: DefaultArguments.PollInterval,
+				Output:       &otelcol.ConsumerArguments{Logs: []otelcol.Consumer{consumer}},
+			})
+			require.NoError(t, err)
+
+			c.poll(context.Background())
+			require.Equal(t, []string{"a", "b", "c"}, received)
+
+			// Only new records are read, and records which fail to be consumed
+			// are retried.
+			writeLogs(t, format, live, false, "d")
+			fail = true
+			c.poll(context.Background())
+			require.Equal(t, []string{"a", "b", "c"}, received)
+
+			fail = false
+			c.poll(context.Background())
+			require.Equal(t, []string{"a", "b", "c", "d"}, received)
+		})
+	}
+}
+
+func writeLogs(t *testing.T, format, path string, compress bool, bodies ...string) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var w io.Writer = f
+	if compress {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		w = gz
+	}
+
+	for _, body := range bodies {
+		ld := plog.NewLogs()
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(body)
+		b, err := otlpfile.MarshalLogs(format, ld)
+		require.NoError(t, err)
+		_, err = w.Write(b)
+		require.NoError(t, err)
+	}
+}
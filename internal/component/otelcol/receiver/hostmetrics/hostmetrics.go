@@ -0,0 +1,353 @@
+// Package hostmetrics provides an otelcol.receiver.hostmetrics component.
+package hostmetrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.hostmetrics",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := hostmetricsreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.hostmetrics component.
+type Arguments struct {
+	RootPath string `alloy:"root_path,attr,optional"`
+
+	Controller otelcol.ControllerArguments `alloy:",squash"`
+
+	CPU     *CPUScraperArguments     `alloy:"cpu,block,optional"`
+	Memory  *MemoryScraperArguments  `alloy:"memory,block,optional"`
+	Disk    *DiskScraperArguments    `alloy:"disk,block,optional"`
+	Network *NetworkScraperArguments `alloy:"network,block,optional"`
+	Process *ProcessScraperArguments `alloy:"process,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var (
+	_ receiver.Arguments = Arguments{}
+	_ syntax.Defaulter   = (*Arguments)(nil)
+	_ syntax.Validator   = (*Arguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{}
+	args.Controller.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.CPU == nil && args.Memory == nil && args.Disk == nil && args.Network == nil && args.Process == nil {
+		return fmt.Errorf("at least one cpu, memory, disk, network, or process block must be defined")
+	}
+	return nil
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	scrapers := map[string]any{}
+	if args.CPU != nil {
+		scrapers["cpu"] = args.CPU.toMap()
+	}
+	if args.Memory != nil {
+		scrapers["memory"] = args.Memory.toMap()
+	}
+	if args.Disk != nil {
+		scrapers["disk"] = args.Disk.toMap()
+	}
+	if args.Network != nil {
+		scrapers["network"] = args.Network.toMap()
+	}
+	if args.Process != nil {
+		scrapers["process"] = args.Process.toMap()
+	}
+
+	// The upstream scraper configs are in internal packages, so the receiver
+	// has to create them by unmarshaling its own config.
+	out := hostmetricsreceiver.NewFactory().CreateDefaultConfig().(*hostmetricsreceiver.Config)
+	conf := confmap.NewFromStringMap(map[string]any{
+		"root_path": args.RootPath,
+		"scrapers":  scrapers,
+	})
+	if err := out.Unmarshal(conf); err != nil {
+		return nil, err
+	}
+	out.ControllerConfig = *args.Controller.Convert()
+
+	return out, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
+
+// CPUScraperArguments configures the cpu scraper.
+type CPUScraperArguments struct {
+	Metrics otelcol.ScraperMetricsArguments `alloy:",squash"`
+}
+
+var _ syntax.Validator = (*CPUScraperArguments)(nil)
+
+// Validate implements syntax.Validator.
+func (args *CPUScraperArguments) Validate() error {
+	return args.Metrics.Validate()
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *CPUScraperArguments) toMap() map[string]any {
+	return map[string]any{"metrics": args.Metrics.Convert()}
+}
+
+// MemoryScraperArguments configures the memory scraper.
+type MemoryScraperArguments struct {
+	Metrics otelcol.ScraperMetricsArguments `alloy:",squash"`
+}
+
+var _ syntax.Validator = (*MemoryScraperArguments)(nil)
+
+// Validate implements syntax.Validator.
+func (args *MemoryScraperArguments) Validate() error {
+	return args.Metrics.Validate()
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *MemoryScraperArguments) toMap() map[string]any {
+	return map[string]any{"metrics": args.Metrics.Convert()}
+}
+
+// DiskScraperArguments configures the disk scraper.
+type DiskScraperArguments struct {
+	Include *DeviceMatchArguments           `alloy:"include,block,optional"`
+	Exclude *DeviceMatchArguments           `alloy:"exclude,block,optional"`
+	Metrics otelcol.ScraperMetricsArguments `alloy:",squash"`
+}
+
+var _ syntax.Validator = (*DiskScraperArguments)(nil)
+
+// Validate implements syntax.Validator.
+func (args *DiskScraperArguments) Validate() error {
+	return args.Metrics.Validate()
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *DiskScraperArguments) toMap() map[string]any {
+	out := map[string]any{"metrics": args.Metrics.Convert()}
+	if args.Include != nil {
+		out["include"] = args.Include.toMap()
+	}
+	if args.Exclude != nil {
+		out["exclude"] = args.Exclude.toMap()
+	}
+	return out
+}
+
+// DeviceMatchArguments matches disk devices by name.
+type DeviceMatchArguments struct {
+	Devices   []string `alloy:"devices,attr"`
+	MatchType string   `alloy:"match_type,attr,optional"`
+}
+
+var (
+	_ syntax.Defaulter = (*DeviceMatchArguments)(nil)
+	_ syntax.Validator = (*DeviceMatchArguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *DeviceMatchArguments) SetToDefault() {
+	*args = DeviceMatchArguments{MatchType: matchTypeStrict}
+}
+
+// Validate implements syntax.Validator.
+func (args *DeviceMatchArguments) Validate() error {
+	return validateMatchType(args.MatchType)
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *DeviceMatchArguments) toMap() map[string]any {
+	return map[string]any{
+		"devices":    args.Devices,
+		"match_type": args.MatchType,
+	}
+}
+
+// NetworkScraperArguments configures the network scraper.
+type NetworkScraperArguments struct {
+	Include *InterfaceMatchArguments        `alloy:"include,block,optional"`
+	Exclude *InterfaceMatchArguments        `alloy:"exclude,block,optional"`
+	Metrics otelcol.ScraperMetricsArguments `alloy:",squash"`
+}
+
+var _ syntax.Validator = (*NetworkScraperArguments)(nil)
+
+// Validate implements syntax.Validator.
+func (args *NetworkScraperArguments) Validate() error {
+	return args.Metrics.Validate()
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *NetworkScraperArguments) toMap() map[string]any {
+	out := map[string]any{"metrics": args.Metrics.Convert()}
+	if args.Include != nil {
+		out["include"] = args.Include.toMap()
+	}
+	if args.Exclude != nil {
+		out["exclude"] = args.Exclude.toMap()
+	}
+	return out
+}
+
+// InterfaceMatchArguments matches network interfaces by name.
+type InterfaceMatchArguments struct {
+	Interfaces []string `alloy:"interfaces,attr"`
+	MatchType  string   `alloy:"match_type,attr,optional"`
+}
+
+var (
+	_ syntax.Defaulter = (*InterfaceMatchArguments)(nil)
+	_ syntax.Validator = (*InterfaceMatchArguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *InterfaceMatchArguments) SetToDefault() {
+	*args = InterfaceMatchArguments{MatchType: matchTypeStrict}
+}
+
+// Validate implements syntax.Validator.
+func (args *InterfaceMatchArguments) Validate() error {
+	return validateMatchType(args.MatchType)
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *InterfaceMatchArguments) toMap() map[string]any {
+	return map[string]any{
+		"interfaces": args.Interfaces,
+		"match_type": args.MatchType,
+	}
+}
+
+// ProcessScraperArguments configures the process scraper.
+type ProcessScraperArguments struct {
+	Include              *ProcessMatchArguments          `alloy:"include,block,optional"`
+	Exclude              *ProcessMatchArguments          `alloy:"exclude,block,optional"`
+	MuteProcessNameError bool                            `alloy:"mute_process_name_error,attr,optional"`
+	MuteProcessExeError  bool                            `alloy:"mute_process_exe_error,attr,optional"`
+	MuteProcessIOError   bool                            `alloy:"mute_process_io_error,attr,optional"`
+	MuteProcessUserError bool                            `alloy:"mute_process_user_error,attr,optional"`
+	ScrapeProcessDelay   time.Duration                   `alloy:"scrape_process_delay,attr,optional"`
+	Metrics              otelcol.ScraperMetricsArguments `alloy:",squash"`
+}
+
+var _ syntax.Validator = (*ProcessScraperArguments)(nil)
+
+// Validate implements syntax.Validator.
+func (args *ProcessScraperArguments) Validate() error {
+	if args.ScrapeProcessDelay < 0 {
+		return fmt.Errorf("scrape_process_delay must not be negative (got %s)", args.ScrapeProcessDelay)
+	}
+	return args.Metrics.Validate()
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *ProcessScraperArguments) toMap() map[string]any {
+	out := map[string]any{
+		"mute_process_name_error": args.MuteProcessNameError,
+		"mute_process_exe_error":  args.MuteProcessExeError,
+		"mute_process_io_error":   args.MuteProcessIOError,
+		"mute_process_user_error": args.MuteProcessUserError,
+		"scrape_process_delay":    args.ScrapeProcessDelay,
+		"metrics":                 args.Metrics.Convert(),
+	}
+	if args.Include != nil {
+		out["include"] = args.Include.toMap()
+	}
+	if args.Exclude != nil {
+		out["exclude"] = args.Exclude.toMap()
+	}
+	return out
+}
+
+// ProcessMatchArguments matches processes by executable name.
+type ProcessMatchArguments struct {
+	Names     []string `alloy:"names,attr"`
+	MatchType string   `alloy:"match_type,attr,optional"`
+}
+
+var (
+	_ syntax.Defaulter = (*ProcessMatchArguments)(nil)
+	_ syntax.Validator = (*ProcessMatchArguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *ProcessMatchArguments) SetToDefault() {
+	*args = ProcessMatchArguments{MatchType: matchTypeStrict}
+}
+
+// Validate implements syntax.Validator.
+func (args *ProcessMatchArguments) Validate() error {
+	return validateMatchType(args.MatchType)
+}
+
+// toMap encodes args to a map for use with confmap.
+func (args *ProcessMatchArguments) toMap() map[string]any {
+	return map[string]any{
+		"names":      args.Names,
+		"match_type": args.MatchType,
+	}
+}
+
+const (
+	matchTypeStrict = "strict"
+	matchTypeRegexp = "regexp"
+)
+
+func validateMatchType(matchType string) error {
+	switch matchType {
+	case matchTypeStrict, matchTypeRegexp:
+		return nil
+	default:
+		return fmt.Errorf("match_type must be %q or %q (got %q)", matchTypeStrict, matchTypeRegexp, matchType)
+	}
+}
@@ -0,0 +1,105 @@
+package hostmetrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/hostmetrics"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments(t *testing.T) {
+	in := `
+		collection_interval = "30s"
+
+		cpu {
+			enabled_metrics = ["system.cpu.utilization"]
+		}
+
+		memory {}
+
+		disk {
+			exclude {
+				devices    = ["^loop[0-9]+$"]
+				match_type = "regexp"
+			}
+		}
+
+		network {
+			include {
+				interfaces = ["eth0"]
+			}
+		}
+
+		process {
+			mute_process_name_error = true
+			disabled_metrics        = ["process.disk.io"]
+		}
+
+		output {}
+	`
+
+	var args hostmetrics.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	require.Equal(t, "strict", args.Network.Include.MatchType)
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+
+	// The upstream scraper configs are in internal packages, so we only check
+	// that every scraper was created.
+	out := outAny.(*hostmetricsreceiver.Config)
+	require.Equal(t, 30*time.Second, out.CollectionInterval)
+	require.Len(t, out.Scrapers, 5)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			testName: "NoScrapers",
+			cfg: `
+				output {}
+			`,
+			errorMsg: "at least one cpu, memory, disk, network, or process block must be defined",
+		},
+		{
+			testName: "EnabledAndDisabled",
+			cfg: `
+				cpu {
+					enabled_metrics  = ["system.cpu.utilization"]
+					disabled_metrics = ["system.cpu.utilization"]
+				}
+				output {}
+			`,
+			errorMsg: `metric "system.cpu.utilization" can't be both enabled and disabled`,
+		},
+		{
+			testName: "InvalidMatchType",
+			cfg: `
+				disk {
+					include {
+						devices    = ["sda"]
+						match_type = "glob"
+					}
+				}
+				output {}
+			`,
+			errorMsg: `match_type must be "strict" or "regexp" (got "glob")`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args hostmetrics.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.EqualError(t, err, tc.errorMsg)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package influxdb_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/influxdb"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/influxdbreceiver"
+	"github.com/phayes/freeport"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestArguments_UnmarshalDefaults(t *testing.T) {
+	var args influxdb.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte("output {}"), &args))
+
+	cfg, err := args.Convert()
+	require.NoError(t, err)
+
+	expected := &influxdbreceiver.Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint:              "localhost:8086",
+			CompressionAlgorithms: []string{"", "gzip", "zstd", "zlib", "snappy", "deflate"},
+		},
+	}
+	require.Equal(t, expected, cfg)
+}
+
+func TestReceive(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	addr := fmt.Sprintf("localhost:%d", port)
+
+	ctx := componenttest.TestContext(t)
+	l := util.TestLogger(t)
+
+	ctrl, err := componenttest.NewControllerFromID(l, "otelcol.receiver.influxdb")
+	require.NoError(t, err)
+
+	metricsCh := make(chan pmetric.Metrics, 1)
+
+	var args influxdb.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(fmt.Sprintf(`
+		endpoint = "%s"
+		output {}
+	`, addr)), &args))
+	args.Output = &otelcol.ConsumerArguments{
+		Metrics: []otelcol.Consumer{
+			&fakeconsumer.Consumer{
+				ConsumeMetricsFunc: func(_ context.Context, md pmetric.Metrics) error {
+					select {
+					case metricsCh <- md:
+					default:
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	go func() {
+		err := ctrl.Run(ctx, args)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, ctrl.WaitRunning(time.Second))
+
+	// Telegraf sends the line protocol to the v1 write API.
+	body := []byte("cpu,host=server1 usage_idle=90.5 1700000000000000000\n")
+	require.Eventually(t, func() bool {
+		resp, err := http.Post(fmt.Sprintf("http://%s/write", addr), "text/plain", bytes.NewReader(body))
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusNoContent
+	}, 5*time.Second, 50*time.Millisecond)
+
+	select {
+	case <-time.After(time.Second):
+		require.FailNow(t, "failed waiting for metrics")
+	case md := <-metricsCh:
+		require.Equal(t, 1, md.MetricCount())
+	}
+}
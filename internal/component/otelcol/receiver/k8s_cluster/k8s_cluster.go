@@ -0,0 +1,136 @@
+// Package k8s_cluster provides an otelcol.receiver.k8s_cluster component.
+package k8s_cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.k8s_cluster",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := k8sclusterreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+const (
+	distributionKubernetes = "kubernetes"
+	distributionOpenShift  = "openshift"
+)
+
+// Arguments configures the otelcol.receiver.k8s_cluster component.
+type Arguments struct {
+	KubernetesAPIConfig        otelcol.KubernetesAPIConfig `alloy:",squash"`
+	CollectionInterval         time.Duration               `alloy:"collection_interval,attr,optional"`
+	MetadataCollectionInterval time.Duration               `alloy:"metadata_collection_interval,attr,optional"`
+	NodeConditionsToReport     []string                    `alloy:"node_conditions_to_report,attr,optional"`
+	AllocatableTypesToReport   []string                    `alloy:"allocatable_types_to_report,attr,optional"`
+	Distribution               string                      `alloy:"distribution,attr,optional"`
+
+	Metrics otelcol.ScraperMetricsArguments `alloy:",squash"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var (
+	_ receiver.Arguments = Arguments{}
+	_ syntax.Defaulter   = (*Arguments)(nil)
+	_ syntax.Validator   = (*Arguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		KubernetesAPIConfig: otelcol.KubernetesAPIConfig{
+			AuthType: otelcol.KubernetesAPIConfig_AuthType_ServiceAccount,
+		},
+		CollectionInterval:         10 * time.Second,
+		MetadataCollectionInterval: 5 * time.Minute,
+		NodeConditionsToReport:     []string{"Ready"},
+		Distribution:               distributionKubernetes,
+	}
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if err := args.KubernetesAPIConfig.Validate(); err != nil {
+		return err
+	}
+	if args.CollectionInterval <= 0 {
+		return fmt.Errorf("collection_interval must be a positive duration (got %s)", args.CollectionInterval)
+	}
+	if args.MetadataCollectionInterval <= 0 {
+		return fmt.Errorf("metadata_collection_interval must be a positive duration (got %s)", args.MetadataCollectionInterval)
+	}
+	switch args.Distribution {
+	case distributionKubernetes, distributionOpenShift:
+	default:
+		return fmt.Errorf("distribution must be %q or %q (got %q)", distributionKubernetes, distributionOpenShift, args.Distribution)
+	}
+	return args.Metrics.Validate()
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	input := map[string]any{
+		"auth_type":                    args.KubernetesAPIConfig.AuthType,
+		"context":                      args.KubernetesAPIConfig.Context,
+		"collection_interval":          args.CollectionInterval,
+		"metadata_collection_interval": args.MetadataCollectionInterval,
+		"node_conditions_to_report":    args.NodeConditionsToReport,
+		"allocatable_types_to_report":  args.AllocatableTypesToReport,
+		"distribution":                 args.Distribution,
+		"metrics":                      args.Metrics.Convert(),
+	}
+
+	// We have to unmarshal the config because the upstream metrics builder
+	// config is in an internal package.
+	out := k8sclusterreceiver.NewFactory().CreateDefaultConfig().(*k8sclusterreceiver.Config)
+	if err := confmap.NewFromStringMap(input).Unmarshal(out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
@@ -0,0 +1,76 @@
+package k8s_cluster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/k8s_cluster"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		check    func(t *testing.T, out *k8sclusterreceiver.Config)
+		errorMsg string
+	}{
+		{
+			testName: "Defaults",
+			cfg: `
+				output {}
+			`,
+			check: func(t *testing.T, out *k8sclusterreceiver.Config) {
+				require.Equal(t, "serviceAccount", string(out.AuthType))
+				require.Equal(t, 10*time.Second, out.CollectionInterval)
+				require.Equal(t, 5*time.Minute, out.MetadataCollectionInterval)
+				require.Equal(t, []string{"Ready"}, out.NodeConditionTypesToReport)
+				require.Equal(t, "kubernetes", out.Distribution)
+			},
+		},
+		{
+			testName: "ExplicitValues",
+			cfg: `
+				auth_type                   = "kubeConfig"
+				collection_interval         = "30s"
+				node_conditions_to_report   = ["Ready", "MemoryPressure"]
+				allocatable_types_to_report = ["cpu", "memory"]
+				distribution                = "openshift"
+				output {}
+			`,
+			check: func(t *testing.T, out *k8sclusterreceiver.Config) {
+				require.Equal(t, "kubeConfig", string(out.AuthType))
+				require.Equal(t, 30*time.Second, out.CollectionInterval)
+				require.Equal(t, []string{"Ready", "MemoryPressure"}, out.NodeConditionTypesToReport)
+				require.Equal(t, []string{"cpu", "memory"}, out.AllocatableTypesToReport)
+				require.Equal(t, "openshift", out.Distribution)
+			},
+		},
+		{
+			testName: "InvalidDistribution",
+			cfg: `
+				distribution = "eks"
+				output {}
+			`,
+			errorMsg: `distribution must be "kubernetes" or "openshift" (got "eks")`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args k8s_cluster.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.EqualError(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			outAny, err := args.Convert()
+			require.NoError(t, err)
+			tc.check(t, outAny.(*k8sclusterreceiver.Config))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Package kubeletstats provides an otelcol.receiver.kubeletstats component.
+package kubeletstats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.kubeletstats",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := kubeletstatsreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.kubeletstats component.
+type Arguments struct {
+	Controller otelcol.ControllerArguments `alloy:",squash"`
+
+	Endpoint            string                      `alloy:"endpoint,attr,optional"`
+	KubernetesAPIConfig otelcol.KubernetesAPIConfig `alloy:",squash"`
+	CAFile              string                      `alloy:"ca_file,attr,optional"`
+	CertFile            string                      `alloy:"cert_file,attr,optional"`
+	KeyFile             string                      `alloy:"key_file,attr,optional"`
+	InsecureSkipVerify  bool                        `alloy:"insecure_skip_verify,attr,optional"`
+
+	ExtraMetadataLabels []string                     `alloy:"extra_metadata_labels,attr,optional"`
+	MetricGroups        []string                     `alloy:"metric_groups,attr,optional"`
+	K8sAPIConfig        *otelcol.KubernetesAPIConfig `alloy:"k8s_api_config,block,optional"`
+
+	Metrics otelcol.ScraperMetricsArguments `alloy:",squash"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var (
+	_ receiver.Arguments = Arguments{}
+	_ syntax.Defaulter   = (*Arguments)(nil)
+	_ syntax.Validator   = (*Arguments)(nil)
+)
+
+var (
+	metricGroups        = []string{"container", "pod", "node", "volume"}
+	extraMetadataLabels = []string{"container.id", "k8s.volume.type"}
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		KubernetesAPIConfig: otelcol.KubernetesAPIConfig{
+			AuthType: otelcol.KubernetesAPIConfig_AuthType_TLS,
+		},
+		MetricGroups: []string{"container", "pod", "node"},
+	}
+	args.Controller.SetToDefault()
+	args.Controller.CollectionInterval = 10 * time.Second
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if err := args.KubernetesAPIConfig.Validate(); err != nil {
+		return err
+	}
+	if args.K8sAPIConfig != nil {
+		if err := args.K8sAPIConfig.Validate(); err != nil {
+			return fmt.Errorf("k8s_api_config: %w", err)
+		}
+	}
+	if err := validateValues("metric_groups", args.MetricGroups, metricGroups); err != nil {
+		return err
+	}
+	if err := validateValues("extra_metadata_labels", args.ExtraMetadataLabels, extraMetadataLabels); err != nil {
+		return err
+	}
+	return args.Metrics.Validate()
+}
+
+func validateValues(name string, values, allowed []string) error {
+	for _, value := range values {
+		var ok bool
+		for _, a := range allowed {
+			if value == a {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s: unsupported value %q, must be one of %q", name, value, allowed)
+		}
+	}
+	return nil
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	input := map[string]any{
+		"endpoint":              args.Endpoint,
+		"auth_type":             args.KubernetesAPIConfig.AuthType,
+		"context":               args.KubernetesAPIConfig.Context,
+		"ca_file":               args.CAFile,
+		"cert_file":             args.CertFile,
+		"key_file":              args.KeyFile,
+		"insecure_skip_verify":  args.InsecureSkipVerify,
+		"extra_metadata_labels": args.ExtraMetadataLabels,
+		"metric_groups":         args.MetricGroups,
+		"metrics":               args.Metrics.Convert(),
+	}
+	if args.K8sAPIConfig != nil {
+		input["k8s_api_config"] = map[string]any{
+			"auth_type": args.K8sAPIConfig.AuthType,
+			"context":   args.K8sAPIConfig.Context,
+		}
+	}
+
+	// We have to unmarshal the config because most of the upstream types are
+	// in internal packages.
+	out := kubeletstatsreceiver.NewFactory().CreateDefaultConfig().(*kubeletstatsreceiver.Config)
+	if err := confmap.NewFromStringMap(input).Unmarshal(out); err != nil {
+		return nil, err
+	}
+	out.ControllerConfig = *args.Controller.Convert()
+
+	return out, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
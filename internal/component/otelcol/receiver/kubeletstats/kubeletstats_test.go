@@ -0,0 +1,72 @@
+package kubeletstats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/kubeletstats"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments(t *testing.T) {
+	in := `
+		endpoint      = "https://node-1:10250"
+		auth_type     = "serviceAccount"
+		metric_groups = ["pod", "node", "volume"]
+
+		extra_metadata_labels = ["container.id"]
+		enabled_metrics       = ["k8s.pod.cpu_limit_utilization"]
+
+		output {}
+	`
+
+	var args kubeletstats.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+
+	// Most of the upstream types are in internal packages, so we check some
+	// fields individually.
+	out := outAny.(*kubeletstatsreceiver.Config)
+	require.Equal(t, 10*time.Second, out.CollectionInterval)
+	require.Equal(t, "https://node-1:10250", out.Endpoint)
+	require.Equal(t, "serviceAccount", string(out.AuthType))
+	require.Len(t, out.MetricGroupsToCollect, 3)
+	require.Len(t, out.ExtraMetadataLabels, 1)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			testName: "InvalidAuthType",
+			cfg: `
+				auth_type = "token"
+				output {}
+			`,
+			errorMsg: `invalid auth_type "token"`,
+		},
+		{
+			testName: "InvalidMetricGroup",
+			cfg: `
+				metric_groups = ["cluster"]
+				output {}
+			`,
+			errorMsg: `metric_groups: unsupported value "cluster", must be one of ["container" "pod" "node" "volume"]`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args kubeletstats.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.EqualError(t, err, tc.errorMsg)
+		})
+	}
+}
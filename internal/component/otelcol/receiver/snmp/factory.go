@@ -0,0 +1,71 @@
+package snmp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/snmpreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	otelreceiver "go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// targetsConfig is the configuration of a receiver which polls several
+// targets. The upstream receiver only polls a single target, so one upstream
+// receiver is created for each target.
+type targetsConfig struct {
+	Targets []*snmpreceiver.Config
+}
+
+// newFactory returns a receiver factory which creates an upstream snmp
+// receiver for each target of a targetsConfig.
+func newFactory() otelreceiver.Factory {
+	upstream := snmpreceiver.NewFactory()
+
+	return otelreceiver.NewFactory(
+		upstream.Type(),
+		func() otelcomponent.Config { return &targetsConfig{} },
+		otelreceiver.WithMetrics(func(ctx context.Context, set otelreceiver.CreateSettings, cfg otelcomponent.Config, next consumer.Metrics) (otelreceiver.Metrics, error) {
+			targets := cfg.(*targetsConfig).Targets
+
+			receivers := make(multiReceiver, 0, len(targets))
+			for _, target := range targets {
+				targetSet := set
+				targetSet.Logger = set.Logger.With(zap.String("target", target.Endpoint))
+
+				r, err := upstream.CreateMetricsReceiver(ctx, targetSet, target, next)
+				if err != nil {
+					return nil, err
+				}
+				receivers = append(receivers, r)
+			}
+			return receivers, nil
+		}, upstream.MetricsReceiverStability()),
+	)
+}
+
+// multiReceiver runs several receivers as a single receiver.
+type multiReceiver []otelreceiver.Metrics
+
+var _ otelreceiver.Metrics = multiReceiver(nil)
+
+// Start implements otelcomponent.Component. If a receiver fails to start, the
+// receivers which were already started are shut down.
+func (m multiReceiver) Start(ctx context.Context, host otelcomponent.Host) error {
+	for i, r := range m {
+		if err := r.Start(ctx, host); err != nil {
+			return errors.Join(err, m[:i].Shutdown(ctx))
+		}
+	}
+	return nil
+}
+
+// Shutdown implements otelcomponent.Component.
+func (m multiReceiver) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, r := range m {
+		errs = append(errs, r.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
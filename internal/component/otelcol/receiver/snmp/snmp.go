@@ -0,0 +1,308 @@
+// Package snmp provides an otelcol.receiver.snmp component.
+package snmp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/snmpreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.snmp",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return receiver.New(opts, newFactory(), args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.snmp component.
+type Arguments struct {
+	ScraperControllerArguments otelcol.ScraperControllerArguments `alloy:",squash"`
+
+	Targets            []TargetArguments            `alloy:"target,block"`
+	ResourceAttributes []ResourceAttributeArguments `alloy:"resource_attribute,block,optional"`
+	Attributes         []AttributeArguments         `alloy:"attribute,block,optional"`
+	Metrics            []MetricArguments            `alloy:"metric,block"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var _ receiver.Arguments = Arguments{}
+
+// TargetArguments configures an SNMP agent to poll, and how to authenticate
+// to it.
+type TargetArguments struct {
+	Name     string `alloy:",label"`
+	Endpoint string `alloy:"endpoint,attr"`
+	Version  string `alloy:"version,attr,optional"`
+
+	// SNMPv1 and SNMPv2c settings.
+	Community alloytypes.Secret `alloy:"community,attr,optional"`
+
+	// SNMPv3 settings.
+	User            string            `alloy:"user,attr,optional"`
+	SecurityLevel   string            `alloy:"security_level,attr,optional"`
+	AuthType        string            `alloy:"auth_type,attr,optional"`
+	AuthPassword    alloytypes.Secret `alloy:"auth_password,attr,optional"`
+	PrivacyType     string            `alloy:"privacy_type,attr,optional"`
+	PrivacyPassword alloytypes.Secret `alloy:"privacy_password,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *TargetArguments) SetToDefault() {
+	*args = TargetArguments{
+		Version:       "v2c",
+		Community:     "public",
+		SecurityLevel: "no_auth_no_priv",
+		AuthType:      "MD5",
+		PrivacyType:   "DES",
+	}
+}
+
+// ResourceAttributeArguments configures a resource attribute, which is used
+// to group metric data points into resources.
+type ResourceAttributeArguments struct {
+	Name               string `alloy:",label"`
+	Description        string `alloy:"description,attr,optional"`
+	OID                string `alloy:"oid,attr,optional"`
+	ScalarOID          string `alloy:"scalar_oid,attr,optional"`
+	IndexedValuePrefix string `alloy:"indexed_value_prefix,attr,optional"`
+}
+
+// AttributeArguments configures an attribute of metric data points.
+type AttributeArguments struct {
+	Name               string   `alloy:",label"`
+	Description        string   `alloy:"description,attr,optional"`
+	Value              string   `alloy:"value,attr,optional"`
+	OID                string   `alloy:"oid,attr,optional"`
+	IndexedValuePrefix string   `alloy:"indexed_value_prefix,attr,optional"`
+	Enum               []string `alloy:"enum,attr,optional"`
+}
+
+// MetricArguments configures a metric, and the OIDs its data points are read
+// from.
+type MetricArguments struct {
+	Name        string               `alloy:",label"`
+	Description string               `alloy:"description,attr,optional"`
+	Unit        string               `alloy:"unit,attr,optional"`
+	Gauge       *GaugeArguments      `alloy:"gauge,block,optional"`
+	Sum         *SumArguments        `alloy:"sum,block,optional"`
+	ScalarOIDs  []ScalarOIDArguments `alloy:"scalar_oid,block,optional"`
+	ColumnOIDs  []ColumnOIDArguments `alloy:"column_oid,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *MetricArguments) SetToDefault() {
+	*args = MetricArguments{Unit: "1"}
+}
+
+// GaugeArguments configures a gauge metric.
+type GaugeArguments struct {
+	ValueType string `alloy:"value_type,attr"`
+}
+
+// SumArguments configures a sum metric.
+type SumArguments struct {
+	Aggregation string `alloy:"aggregation,attr,optional"`
+	Monotonic   bool   `alloy:"monotonic,attr,optional"`
+	ValueType   string `alloy:"value_type,attr"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *SumArguments) SetToDefault() {
+	*args = SumArguments{Aggregation: "cumulative"}
+}
+
+// ScalarOIDArguments configures a scalar OID, which is read with an SNMP GET
+// request and produces a single data point.
+type ScalarOIDArguments struct {
+	OID                string                    `alloy:"oid,attr"`
+	ResourceAttributes []string                  `alloy:"resource_attributes,attr,optional"`
+	Attributes         []AttributeValueArguments `alloy:"attribute,block,optional"`
+}
+
+// ColumnOIDArguments configures a column OID, which is read with an SNMP walk
+// and produces a data point for each index of the column.
+type ColumnOIDArguments struct {
+	OID                string                    `alloy:"oid,attr"`
+	ResourceAttributes []string                  `alloy:"resource_attributes,attr,optional"`
+	Attributes         []AttributeValueArguments `alloy:"attribute,block,optional"`
+}
+
+// AttributeValueArguments references an attribute from a scalar or column
+// OID.
+type AttributeValueArguments struct {
+	Name  string `alloy:"name,attr"`
+	Value string `alloy:"value,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		ScraperControllerArguments: otelcol.ScraperControllerArguments{
+			CollectionInterval: 10 * time.Second,
+			InitialDelay:       time.Second,
+			Timeout:            5 * time.Second,
+		},
+	}
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if err := args.ScraperControllerArguments.Validate(); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(args.Targets))
+	for _, t := range args.Targets {
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("target %q is defined more than once", t.Name)
+		}
+		names[t.Name] = struct{}{}
+	}
+
+	cfg, err := args.Convert()
+	if err != nil {
+		return err
+	}
+	for i, target := range cfg.(*targetsConfig).Targets {
+		if err := otelcomponent.ValidateConfig(target); err != nil {
+			return fmt.Errorf("target %q: %w", args.Targets[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	var (
+		resourceAttributes = make(map[string]*snmpreceiver.ResourceAttributeConfig, len(args.ResourceAttributes))
+		attributes         = make(map[string]*snmpreceiver.AttributeConfig, len(args.Attributes))
+		metrics            = make(map[string]*snmpreceiver.MetricConfig, len(args.Metrics))
+	)
+
+	for _, ra := range args.ResourceAttributes {
+		resourceAttributes[ra.Name] = &snmpreceiver.ResourceAttributeConfig{
+			Description:        ra.Description,
+			OID:                ra.OID,
+			ScalarOID:          ra.ScalarOID,
+			IndexedValuePrefix: ra.IndexedValuePrefix,
+		}
+	}
+
+	for _, a := range args.Attributes {
+		attributes[a.Name] = &snmpreceiver.AttributeConfig{
+			Description:        a.Description,
+			Value:              a.Value,
+			OID:                a.OID,
+			IndexedValuePrefix: a.IndexedValuePrefix,
+			Enum:               a.Enum,
+		}
+	}
+
+	for _, m := range args.Metrics {
+		metric := &snmpreceiver.MetricConfig{
+			Description: m.Description,
+			Unit:        m.Unit,
+		}
+		if m.Gauge != nil {
+			metric.Gauge = &snmpreceiver.GaugeMetric{ValueType: m.Gauge.ValueType}
+		}
+		if m.Sum != nil {
+			metric.Sum = &snmpreceiver.SumMetric{
+				Aggregation: m.Sum.Aggregation,
+				Monotonic:   m.Sum.Monotonic,
+				ValueType:   m.Sum.ValueType,
+			}
+		}
+		for _, oid := range m.ScalarOIDs {
+			metric.ScalarOIDs = append(metric.ScalarOIDs, snmpreceiver.ScalarOID{
+				OID:                oid.OID,
+				ResourceAttributes: oid.ResourceAttributes,
+				Attributes:         convertAttributeValues(oid.Attributes),
+			})
+		}
+		for _, oid := range m.ColumnOIDs {
+			metric.ColumnOIDs = append(metric.ColumnOIDs, snmpreceiver.ColumnOID{
+				OID:                oid.OID,
+				ResourceAttributes: oid.ResourceAttributes,
+				Attributes:         convertAttributeValues(oid.Attributes),
+			})
+		}
+		metrics[m.Name] = metric
+	}
+
+	result := &targetsConfig{}
+	for _, t := range args.Targets {
+		result.Targets = append(result.Targets, &snmpreceiver.Config{
+			ControllerConfig: *args.ScraperControllerArguments.Convert(),
+
+			Endpoint:        t.Endpoint,
+			Version:         t.Version,
+			Community:       string(t.Community),
+			User:            t.User,
+			SecurityLevel:   t.SecurityLevel,
+			AuthType:        t.AuthType,
+			AuthPassword:    configopaque.String(t.AuthPassword),
+			PrivacyType:     t.PrivacyType,
+			PrivacyPassword: configopaque.String(t.PrivacyPassword),
+
+			// The upstream receiver doesn't modify its configuration, so the
+			// OID definitions can be shared by all targets.
+			ResourceAttributes: resourceAttributes,
+			Attributes:         attributes,
+			Metrics:            metrics,
+		})
+	}
+	return result, nil
+}
+
+func convertAttributeValues(in []AttributeValueArguments) []snmpreceiver.Attribute {
+	if len(in) == 0 {
+		return nil
+	}
+	res := make([]snmpreceiver.Attribute, 0, len(in))
+	for _, a := range in {
+		res = append(res, snmpreceiver.Attribute{Name: a.Name, Value: a.Value})
+	}
+	return res
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
@@ -0,0 +1,219 @@
+package snmp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/snmpreceiver"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	in := `
+		collection_interval = "30s"
+
+		target "switch" {
+			endpoint  = "udp://switch:161"
+			community = "private"
+		}
+
+		target "router" {
+			endpoint         = "udp://router:161"
+			version          = "v3"
+			user             = "alloy"
+			security_level   = "auth_priv"
+			auth_type        = "SHA"
+			auth_password    = "authpassword"
+			privacy_type     = "AES"
+			privacy_password = "privpassword"
+		}
+
+		resource_attribute "host.name" {
+			scalar_oid = "1.3.6.1.2.1.1.5.0"
+		}
+
+		attribute "interface" {
+			oid = "1.3.6.1.2.1.31.1.1.1.1"
+		}
+
+		attribute "direction" {
+			enum = ["in", "out"]
+		}
+
+		metric "network.io" {
+			unit = "By"
+
+			sum {
+				monotonic  = true
+				value_type = "int"
+			}
+
+			column_oid {
+				oid                 = "1.3.6.1.2.1.31.1.1.1.6"
+				resource_attributes = ["host.name"]
+
+				attribute {
+					name = "interface"
+				}
+				attribute {
+					name  = "direction"
+					value = "in"
+				}
+			}
+		}
+
+		metric "system.uptime" {
+			gauge {
+				value_type = "int"
+			}
+
+			scalar_oid {
+				oid                 = "1.3.6.1.2.1.1.3.0"
+				resource_attributes = ["host.name"]
+			}
+		}
+
+		output {}
+	`
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	cfg, err := args.Convert()
+	require.NoError(t, err)
+
+	resourceAttributes := map[string]*snmpreceiver.ResourceAttributeConfig{
+		"host.name": {ScalarOID: "1.3.6.1.2.1.1.5.0"},
+	}
+	attributes := map[string]*snmpreceiver.AttributeConfig{
+		"interface": {OID: "1.3.6.1.2.1.31.1.1.1.1"},
+		"direction": {Enum: []string{"in", "out"}},
+	}
+	metrics := map[string]*snmpreceiver.MetricConfig{
+		"network.io": {
+			Unit: "By",
+			Sum: &snmpreceiver.SumMetric{
+				Aggregation: "cumulative",
+				Monotonic:   true,
+				ValueType:   "int",
+			},
+			ColumnOIDs: []snmpreceiver.ColumnOID{{
+				OID:                "1.3.6.1.2.1.31.1.1.1.6",
+				ResourceAttributes: []string{"host.name"},
+				Attributes: []snmpreceiver.Attribute{
+					{Name: "interface"},
+					{Name: "direction", Value: "in"},
+				},
+			}},
+		},
+		"system.uptime": {
+			Unit:  "1",
+			Gauge: &snmpreceiver.GaugeMetric{ValueType: "int"},
+			ScalarOIDs: []snmpreceiver.ScalarOID{{
+				OID:                "1.3.6.1.2.1.1.3.0",
+				ResourceAttributes: []string{"host.name"},
+			}},
+		},
+	}
+	controller := scraperhelper.ControllerConfig{
+		CollectionInterval: 30 * time.Second,
+		InitialDelay:       time.Second,
+		Timeout:            5 * time.Second,
+	}
+
+	expected := &targetsConfig{
+		Targets: []*snmpreceiver.Config{
+			{
+				ControllerConfig:   controller,
+				Endpoint:           "udp://switch:161",
+				Version:            "v2c",
+				Community:          "private",
+				SecurityLevel:      "no_auth_no_priv",
+				AuthType:           "MD5",
+				PrivacyType:        "DES",
+				ResourceAttributes: resourceAttributes,
+				Attributes:         attributes,
+				Metrics:            metrics,
+			},
+			{
+				ControllerConfig:   controller,
+				Endpoint:           "udp://router:161",
+				Version:            "v3",
+				Community:          "public",
+				User:               "alloy",
+				SecurityLevel:      "auth_priv",
+				AuthType:           "SHA",
+				AuthPassword:       "authpassword",
+				PrivacyType:        "AES",
+				PrivacyPassword:    "privpassword",
+				ResourceAttributes: resourceAttributes,
+				Attributes:         attributes,
+				Metrics:            metrics,
+			},
+		},
+	}
+	require.Equal(t, expected, cfg)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name: "duplicate target",
+			cfg: `
+				target "a" {
+					endpoint = "udp://a:161"
+				}
+				target "a" {
+					endpoint = "udp://b:161"
+				}
+			`,
+			expectErr: `target "a" is defined more than once`,
+		},
+		{
+			name: "missing v3 user",
+			cfg: `
+				target "a" {
+					endpoint = "udp://a:161"
+					version  = "v3"
+				}
+			`,
+			expectErr: `target "a": `,
+		},
+		{
+			name: "invalid collection interval",
+			cfg: `
+				collection_interval = "0s"
+				target "a" {
+					endpoint = "udp://a:161"
+				}
+			`,
+			expectErr: `"collection_interval": requires positive value`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg + `
+				metric "system.uptime" {
+					gauge {
+						value_type = "int"
+					}
+					scalar_oid {
+						oid = "1.3.6.1.2.1.1.3.0"
+					}
+				}
+				output {}
+			`
+
+			var args Arguments
+			err := syntax.Unmarshal([]byte(cfg), &args)
+			require.ErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
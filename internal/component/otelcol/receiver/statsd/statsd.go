@@ -0,0 +1,209 @@
+// Package statsd provides an otelcol.receiver.statsd component.
+package statsd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/mitchellh/mapstructure"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.statsd",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := statsdreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.statsd component.
+type Arguments struct {
+	Endpoint            string        `alloy:"endpoint,attr,optional"`
+	Transport           string        `alloy:"transport,attr,optional"`
+	AggregationInterval time.Duration `alloy:"aggregation_interval,attr,optional"`
+	EnableMetricType    bool          `alloy:"enable_metric_type,attr,optional"`
+	IsMonotonicCounter  bool          `alloy:"is_monotonic_counter,attr,optional"`
+
+	TimerHistogramMappings []TimerHistogramMapping `alloy:"timer_histogram_mapping,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// TimerHistogramMapping configures how StatsD timers and histograms are
+// converted into OTLP metrics.
+type TimerHistogramMapping struct {
+	StatsdType   string `alloy:"statsd_type,attr"`
+	ObserverType string `alloy:"observer_type,attr"`
+
+	// MaxSize is only used when ObserverType is "histogram".
+	MaxSize int32 `alloy:"max_size,attr,optional"`
+	// Percentiles are only used when ObserverType is "summary".
+	Percentiles []float64 `alloy:"percentiles,attr,optional"`
+}
+
+// Supported StatsD types of TimerHistogramMapping.
+var statsdTypes = []string{"timer", "timing", "histogram", "distribution"}
+
+// Supported observer types of TimerHistogramMapping.
+const (
+	ObserverTypeGauge     = "gauge"
+	ObserverTypeSummary   = "summary"
+	ObserverTypeHistogram = "histogram"
+)
+
+// Validate implements syntax.Validator.
+func (m *TimerHistogramMapping) Validate() error {
+	validType := false
+	for _, t := range statsdTypes {
+		if m.StatsdType == t {
+			validType = true
+			break
+		}
+	}
+	if !validType {
+		return fmt.Errorf("statsd_type must be one of %q, got %q", statsdTypes, m.StatsdType)
+	}
+
+	switch m.ObserverType {
+	case ObserverTypeGauge, ObserverTypeSummary, ObserverTypeHistogram:
+	default:
+		return fmt.Errorf("observer_type must be one of %q, %q, or %q, got %q", ObserverTypeGauge, ObserverTypeSummary, ObserverTypeHistogram, m.ObserverType)
+	}
+
+	if m.MaxSize != 0 && m.ObserverType != ObserverTypeHistogram {
+		return fmt.Errorf("max_size can only be set when observer_type is %q", ObserverTypeHistogram)
+	}
+	if m.MaxSize < 0 {
+		return fmt.Errorf("max_size must not be negative")
+	}
+	if len(m.Percentiles) > 0 && m.ObserverType != ObserverTypeSummary {
+		return fmt.Errorf("percentiles can only be set when observer_type is %q", ObserverTypeSummary)
+	}
+	for _, p := range m.Percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("percentiles must be between 0 and 100, got %v", p)
+		}
+	}
+	return nil
+}
+
+// Convert converts m into the upstream type. The upstream type lives in an
+// internal package, so it's returned as a map to be decoded with mapstructure.
+func (m TimerHistogramMapping) Convert() map[string]interface{} {
+	return map[string]interface{}{
+		"statsd_type":   m.StatsdType,
+		"observer_type": m.ObserverType,
+		"histogram": map[string]interface{}{
+			"max_size": m.MaxSize,
+		},
+		"summary": map[string]interface{}{
+			"percentiles": m.Percentiles,
+		},
+	}
+}
+
+var _ receiver.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Endpoint:            "localhost:8125",
+		Transport:           "udp",
+		AggregationInterval: 60 * time.Second,
+	}
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	switch args.Transport {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		return fmt.Errorf("transport must be one of udp, udp4, udp6, tcp, tcp4, or tcp6, got %q", args.Transport)
+	}
+
+	if args.AggregationInterval <= 0 {
+		return fmt.Errorf("aggregation_interval must be greater than 0")
+	}
+
+	seen := make(map[string]struct{}, len(args.TimerHistogramMappings))
+	for _, m := range args.TimerHistogramMappings {
+		if _, ok := seen[m.StatsdType]; ok {
+			return fmt.Errorf("statsd_type %q is mapped more than once", m.StatsdType)
+		}
+		seen[m.StatsdType] = struct{}{}
+	}
+	return nil
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	// Timers and histograms are converted into gauges by default, like the
+	// upstream receiver does.
+	mappings := args.TimerHistogramMappings
+	if len(mappings) == 0 {
+		mappings = []TimerHistogramMapping{
+			{StatsdType: "timer", ObserverType: ObserverTypeGauge},
+			{StatsdType: "histogram", ObserverType: ObserverTypeGauge},
+		}
+	}
+
+	convertedMappings := make([]map[string]interface{}, 0, len(mappings))
+	for _, m := range mappings {
+		convertedMappings = append(convertedMappings, m.Convert())
+	}
+
+	input := map[string]interface{}{
+		"endpoint":                args.Endpoint,
+		"transport":               args.Transport,
+		"aggregation_interval":    args.AggregationInterval,
+		"enable_metric_type":      args.EnableMetricType,
+		"is_monotonic_counter":    args.IsMonotonicCounter,
+		"timer_histogram_mapping": convertedMappings,
+	}
+
+	result := statsdreceiver.NewFactory().CreateDefaultConfig().(*statsdreceiver.Config)
+	// Replace the default mappings instead of merging the new ones into them.
+	result.TimerHistogramMapping = nil
+	if err := mapstructure.Decode(input, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
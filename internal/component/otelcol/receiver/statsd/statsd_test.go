@@ -0,0 +1,144 @@
+package statsd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/statsd"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_Defaults(t *testing.T) {
+	in := `
+		output {}
+	`
+
+	var args statsd.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+	out := outAny.(*statsdreceiver.Config)
+
+	// The mappings have fields in an internal package, so we check some fields
+	// individually here.
+	assert.Equal(t, "localhost:8125", out.NetAddr.Endpoint)
+	assert.EqualValues(t, "udp", out.NetAddr.Transport)
+	assert.Equal(t, 60*time.Second, out.AggregationInterval)
+	require.Len(t, out.TimerHistogramMapping, 2)
+	assert.EqualValues(t, "timer", out.TimerHistogramMapping[0].StatsdType)
+	assert.EqualValues(t, "gauge", out.TimerHistogramMapping[0].ObserverType)
+	assert.EqualValues(t, "histogram", out.TimerHistogramMapping[1].StatsdType)
+	assert.EqualValues(t, "gauge", out.TimerHistogramMapping[1].ObserverType)
+}
+
+func TestArguments(t *testing.T) {
+	in := `
+		endpoint             = "0.0.0.0:9125"
+		transport            = "tcp"
+		aggregation_interval = "10s"
+		enable_metric_type   = true
+		is_monotonic_counter = true
+
+		timer_histogram_mapping {
+			statsd_type   = "timer"
+			observer_type = "summary"
+			percentiles   = [50, 99]
+		}
+
+		timer_histogram_mapping {
+			statsd_type   = "distribution"
+			observer_type = "histogram"
+			max_size      = 100
+		}
+
+		output {}
+	`
+
+	var args statsd.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+	out := outAny.(*statsdreceiver.Config)
+
+	assert.Equal(t, "0.0.0.0:9125", out.NetAddr.Endpoint)
+	assert.EqualValues(t, "tcp", out.NetAddr.Transport)
+	assert.Equal(t, 10*time.Second, out.AggregationInterval)
+	assert.True(t, out.EnableMetricType)
+	assert.True(t, out.IsMonotonicCounter)
+
+	require.Len(t, out.TimerHistogramMapping, 2)
+	assert.EqualValues(t, "timer", out.TimerHistogramMapping[0].StatsdType)
+	assert.EqualValues(t, "summary", out.TimerHistogramMapping[0].ObserverType)
+	assert.Equal(t, []float64{50, 99}, out.TimerHistogramMapping[0].Summary.Percentiles)
+	assert.EqualValues(t, "distribution", out.TimerHistogramMapping[1].StatsdType)
+	assert.EqualValues(t, "histogram", out.TimerHistogramMapping[1].ObserverType)
+	assert.EqualValues(t, 100, out.TimerHistogramMapping[1].Histogram.MaxSize)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "invalid transport",
+			cfg: `
+				transport = "unix"
+				output {}
+			`,
+			expectedErr: `transport must be one of udp, udp4, udp6, tcp, tcp4, or tcp6, got "unix"`,
+		},
+		{
+			name: "invalid statsd type",
+			cfg: `
+				timer_histogram_mapping {
+					statsd_type   = "counter"
+					observer_type = "gauge"
+				}
+				output {}
+			`,
+			expectedErr: `statsd_type must be one of ["timer" "timing" "histogram" "distribution"], got "counter"`,
+		},
+		{
+			name: "percentiles without summary",
+			cfg: `
+				timer_histogram_mapping {
+					statsd_type   = "timer"
+					observer_type = "histogram"
+					percentiles   = [99]
+				}
+				output {}
+			`,
+			expectedErr: `percentiles can only be set when observer_type is "summary"`,
+		},
+		{
+			name: "duplicate mapping",
+			cfg: `
+				timer_histogram_mapping {
+					statsd_type   = "timer"
+					observer_type = "gauge"
+				}
+				timer_histogram_mapping {
+					statsd_type   = "timer"
+					observer_type = "summary"
+				}
+				output {}
+			`,
+			expectedErr: `statsd_type "timer" is mapped more than once`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args statsd.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// Package syslog provides an otelcol.receiver.syslog component.
+package syslog
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/units"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/mitchellh/mapstructure"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/syslogreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	otelextension "go.opentelemetry.io/collector/extension"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.syslog",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := syslogreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Supported syslog protocols.
+const (
+	ProtocolRFC5424 = "rfc5424"
+	ProtocolRFC3164 = "rfc3164"
+)
+
+// Supported trailers for non-transparent framing.
+const (
+	FramingTrailerLF  = "LF"
+	FramingTrailerNUL = "NUL"
+)
+
+// Arguments configures the otelcol.receiver.syslog component.
+type Arguments struct {
+	Protocol                     string `alloy:"protocol,attr,optional"`
+	Location                     string `alloy:"location,attr,optional"`
+	EnableOctetCounting          bool   `alloy:"enable_octet_counting,attr,optional"`
+	MaxOctets                    int    `alloy:"max_octets,attr,optional"`
+	AllowSkipPriHeader           bool   `alloy:"allow_skip_pri_header,attr,optional"`
+	NonTransparentFramingTrailer string `alloy:"non_transparent_framing_trailer,attr,optional"`
+
+	TCP *TCPArguments `alloy:"tcp,block,optional"`
+	UDP *UDPArguments `alloy:"udp,block,optional"`
+
+	ConsumerRetry otelcol.ConsumerRetryArguments `alloy:"retry_on_failure,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// TCPArguments configures receiving syslog messages over TCP.
+type TCPArguments struct {
+	ListenAddress string                      `alloy:"listen_address,attr"`
+	MaxLogSize    units.Base2Bytes            `alloy:"max_log_size,attr,optional"`
+	TLS           *otelcol.TLSServerArguments `alloy:"tls,block,optional"`
+	AddAttributes bool                        `alloy:"add_attributes,attr,optional"`
+	Encoding      string                      `alloy:"encoding,attr,optional"`
+
+	OneLogPerPacket             bool `alloy:"one_log_per_packet,attr,optional"`
+	PreserveLeadingWhitespaces  bool `alloy:"preserve_leading_whitespaces,attr,optional"`
+	PreserveTrailingWhitespaces bool `alloy:"preserve_trailing_whitespaces,attr,optional"`
+
+	Multiline *MultilineArguments `alloy:"multiline,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *TCPArguments) SetToDefault() {
+	*args = TCPArguments{
+		MaxLogSize: 1 * units.MiB,
+		Encoding:   "utf-8",
+	}
+}
+
+// UDPArguments configures receiving syslog messages over UDP.
+type UDPArguments struct {
+	ListenAddress string `alloy:"listen_address,attr"`
+	AddAttributes bool   `alloy:"add_attributes,attr,optional"`
+	Encoding      string `alloy:"encoding,attr,optional"`
+
+	OneLogPerPacket             bool `alloy:"one_log_per_packet,attr,optional"`
+	PreserveLeadingWhitespaces  bool `alloy:"preserve_leading_whitespaces,attr,optional"`
+	PreserveTrailingWhitespaces bool `alloy:"preserve_trailing_whitespaces,attr,optional"`
+
+	Multiline *MultilineArguments `alloy:"multiline,block,optional"`
+	Async     *AsyncArguments     `alloy:"async,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *UDPArguments) SetToDefault() {
+	*args = UDPArguments{
+		Encoding: "utf-8",
+	}
+}
+
+// MultilineArguments configures how incoming data is split into log entries.
+type MultilineArguments struct {
+	LineStartPattern string `alloy:"line_start_pattern,attr,optional"`
+	LineEndPattern   string `alloy:"line_end_pattern,attr,optional"`
+	OmitPattern      bool   `alloy:"omit_pattern,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *MultilineArguments) Validate() error {
+	if args.LineStartPattern != "" && args.LineEndPattern != "" {
+		return fmt.Errorf("only one of line_start_pattern and line_end_pattern can be set")
+	}
+	return nil
+}
+
+// Convert converts args into the upstream type.
+func (args *MultilineArguments) Convert() map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"line_start_pattern": args.LineStartPattern,
+		"line_end_pattern":   args.LineEndPattern,
+		"omit_pattern":       args.OmitPattern,
+	}
+}
+
+// AsyncArguments configures concurrent reading and processing of UDP packets.
+type AsyncArguments struct {
+	Readers        int `alloy:"readers,attr,optional"`
+	Processors     int `alloy:"processors,attr,optional"`
+	MaxQueueLength int `alloy:"max_queue_length,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *AsyncArguments) SetToDefault() {
+	*args = AsyncArguments{
+		Readers:        1,
+		Processors:     1,
+		MaxQueueLength: 100,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *AsyncArguments) Validate() error {
+	if args.Readers <= 0 || args.Processors <= 0 || args.MaxQueueLength <= 0 {
+		return fmt.Errorf("readers, processors, and max_queue_length must be greater than 0")
+	}
+	return nil
+}
+
+var _ receiver.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Protocol:  ProtocolRFC5424,
+		Location:  "UTC",
+		MaxOctets: 8192,
+	}
+	args.ConsumerRetry.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	switch args.Protocol {
+	case ProtocolRFC5424, ProtocolRFC3164:
+	default:
+		return fmt.Errorf("protocol must be one of %q or %q, got %q", ProtocolRFC5424, ProtocolRFC3164, args.Protocol)
+	}
+
+	if (args.TCP == nil) == (args.UDP == nil) {
+		return fmt.Errorf("exactly one of the tcp or udp blocks must be set")
+	}
+
+	switch args.NonTransparentFramingTrailer {
+	case "", FramingTrailerLF, FramingTrailerNUL:
+	default:
+		return fmt.Errorf("non_transparent_framing_trailer must be one of %q or %q, got %q", FramingTrailerLF, FramingTrailerNUL, args.NonTransparentFramingTrailer)
+	}
+
+	if args.NonTransparentFramingTrailer != "" {
+		if args.TCP == nil {
+			return fmt.Errorf("non_transparent_framing_trailer can only be used with the tcp block")
+		}
+		if args.EnableOctetCounting {
+			return fmt.Errorf("non_transparent_framing_trailer can't be used with enable_octet_counting")
+		}
+		if args.Protocol != ProtocolRFC5424 {
+			return fmt.Errorf("non_transparent_framing_trailer can only be used with the %q protocol", ProtocolRFC5424)
+		}
+	}
+
+	if args.EnableOctetCounting && args.Protocol != ProtocolRFC5424 {
+		return fmt.Errorf("enable_octet_counting can only be used with the %q protocol", ProtocolRFC5424)
+	}
+
+	if args.MaxOctets < 0 {
+		return fmt.Errorf("max_octets must not be negative")
+	}
+
+	return nil
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	input := map[string]interface{}{
+		"protocol":              args.Protocol,
+		"location":              args.Location,
+		"enable_octet_counting": args.EnableOctetCounting,
+		"max_octets":            args.MaxOctets,
+		"allow_skip_pri_header": args.AllowSkipPriHeader,
+		"retry_on_failure":      args.ConsumerRetry.Convert(),
+	}
+	if args.NonTransparentFramingTrailer != "" {
+		input["non_transparent_framing_trailer"] = &args.NonTransparentFramingTrailer
+	}
+	if args.TCP != nil {
+		input["tcp"] = map[string]interface{}{
+			"listen_address":                args.TCP.ListenAddress,
+			"max_log_size":                  int64(args.TCP.MaxLogSize),
+			"add_attributes":                args.TCP.AddAttributes,
+			"encoding":                      args.TCP.Encoding,
+			"one_log_per_packet":            args.TCP.OneLogPerPacket,
+			"preserve_leading_whitespaces":  args.TCP.PreserveLeadingWhitespaces,
+			"preserve_trailing_whitespaces": args.TCP.PreserveTrailingWhitespaces,
+			"multiline":                     args.TCP.Multiline.Convert(),
+		}
+	}
+	if args.UDP != nil {
+		udp := map[string]interface{}{
+			"listen_address":                args.UDP.ListenAddress,
+			"add_attributes":                args.UDP.AddAttributes,
+			"encoding":                      args.UDP.Encoding,
+			"one_log_per_packet":            args.UDP.OneLogPerPacket,
+			"preserve_leading_whitespaces":  args.UDP.PreserveLeadingWhitespaces,
+			"preserve_trailing_whitespaces": args.UDP.PreserveTrailingWhitespaces,
+			"multiline":                     args.UDP.Multiline.Convert(),
+		}
+		if args.UDP.Async != nil {
+			udp["async"] = map[string]interface{}{
+				"readers":          args.UDP.Async.Readers,
+				"processors":       args.UDP.Async.Processors,
+				"max_queue_length": args.UDP.Async.MaxQueueLength,
+			}
+		}
+		input["udp"] = udp
+	}
+
+	// Start from the upstream defaults so that the operator settings which
+	// aren't exposed by the component are set.
+	result := syslogreceiver.NewFactory().CreateDefaultConfig().(*syslogreceiver.SysLogConfig)
+	if err := mapstructure.Decode(input, result); err != nil {
+		return nil, err
+	}
+
+	// The TLS settings are set after decoding because mapstructure can't
+	// decode their upstream type.
+	if args.TCP != nil && result.InputConfig.TCP != nil {
+		result.InputConfig.TCP.TLS = args.TCP.TLS.Convert()
+	}
+
+	return result, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelextension.Extension {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[otelcomponent.DataType]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
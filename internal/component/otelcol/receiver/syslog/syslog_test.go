@@ -0,0 +1,173 @@
+package syslog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/syslog"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/syslogreceiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_TCP(t *testing.T) {
+	in := `
+		protocol              = "rfc5424"
+		location              = "Europe/Paris"
+		enable_octet_counting = true
+		max_octets            = 4096
+
+		tcp {
+			listen_address = "0.0.0.0:1514"
+			max_log_size   = "2MiB"
+			add_attributes = true
+
+			tls {
+				cert_file = "/etc/certs/cert.pem"
+				key_file  = "/etc/certs/key.pem"
+			}
+		}
+
+		retry_on_failure {
+			enabled          = true
+			initial_interval = "2s"
+		}
+
+		output {
+			// no-op
+		}
+	`
+
+	var args syslog.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+	out := outAny.(*syslogreceiver.SysLogConfig)
+
+	// The upstream type has fields in internal packages, so we check some
+	// fields individually here.
+	assert.Equal(t, "rfc5424", out.InputConfig.Protocol)
+	assert.Equal(t, "Europe/Paris", out.InputConfig.Location)
+	assert.True(t, out.InputConfig.EnableOctetCounting)
+	assert.Equal(t, 4096, out.InputConfig.MaxOctets)
+	assert.Nil(t, out.InputConfig.UDP)
+
+	require.NotNil(t, out.InputConfig.TCP)
+	assert.Equal(t, "0.0.0.0:1514", out.InputConfig.TCP.ListenAddress)
+	assert.EqualValues(t, 2*1024*1024, out.InputConfig.TCP.MaxLogSize)
+	assert.True(t, out.InputConfig.TCP.AddAttributes)
+	require.NotNil(t, out.InputConfig.TCP.TLS)
+	assert.Equal(t, "/etc/certs/cert.pem", out.InputConfig.TCP.TLS.CertFile)
+
+	assert.True(t, out.RetryOnFailure.Enabled)
+	assert.Equal(t, 2*time.Second, out.RetryOnFailure.InitialInterval)
+	assert.Equal(t, 30*time.Second, out.RetryOnFailure.MaxInterval)
+}
+
+func TestArguments_UDP(t *testing.T) {
+	in := `
+		protocol = "rfc3164"
+
+		udp {
+			listen_address = "0.0.0.0:1514"
+
+			async {
+				readers = 2
+			}
+		}
+
+		output {
+			// no-op
+		}
+	`
+
+	var args syslog.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+	out := outAny.(*syslogreceiver.SysLogConfig)
+
+	assert.Equal(t, "rfc3164", out.InputConfig.Protocol)
+	assert.Equal(t, "UTC", out.InputConfig.Location)
+	assert.Nil(t, out.InputConfig.TCP)
+
+	require.NotNil(t, out.InputConfig.UDP)
+	assert.Equal(t, "0.0.0.0:1514", out.InputConfig.UDP.ListenAddress)
+	require.NotNil(t, out.InputConfig.UDP.AsyncConfig)
+	assert.Equal(t, 2, out.InputConfig.UDP.AsyncConfig.Readers)
+	assert.Equal(t, 1, out.InputConfig.UDP.AsyncConfig.Processors)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "no listener",
+			cfg: `
+				output {}
+			`,
+			expectedErr: "exactly one of the tcp or udp blocks must be set",
+		},
+		{
+			name: "both listeners",
+			cfg: `
+				tcp { listen_address = "0.0.0.0:1514" }
+				udp { listen_address = "0.0.0.0:1514" }
+				output {}
+			`,
+			expectedErr: "exactly one of the tcp or udp blocks must be set",
+		},
+		{
+			name: "invalid protocol",
+			cfg: `
+				protocol = "rfc1234"
+				udp { listen_address = "0.0.0.0:1514" }
+				output {}
+			`,
+			expectedErr: `protocol must be one of "rfc5424" or "rfc3164", got "rfc1234"`,
+		},
+		{
+			name: "framing trailer over udp",
+			cfg: `
+				non_transparent_framing_trailer = "LF"
+				udp { listen_address = "0.0.0.0:1514" }
+				output {}
+			`,
+			expectedErr: "non_transparent_framing_trailer can only be used with the tcp block",
+		},
+		{
+			name: "framing trailer with octet counting",
+			cfg: `
+				non_transparent_framing_trailer = "NUL"
+				enable_octet_counting           = true
+				tcp { listen_address = "0.0.0.0:1514" }
+				output {}
+			`,
+			expectedErr: "non_transparent_framing_trailer can't be used with enable_octet_counting",
+		},
+		{
+			name: "octet counting with rfc3164",
+			cfg: `
+				protocol              = "rfc3164"
+				enable_octet_counting = true
+				tcp { listen_address = "0.0.0.0:1514" }
+				output {}
+			`,
+			expectedErr: `enable_octet_counting can only be used with the "rfc5424" protocol`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args syslog.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
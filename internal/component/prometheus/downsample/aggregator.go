@@ -0,0 +1,207 @@
+package downsample
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// aggregator aggregates the float samples it receives into output series.
+//
+// Only the latest sample of each input series is kept. When the interval is
+// flushed, the samples of all input series sharing the same output labels are
+// aggregated into a single sample.
+//
+// The latest sample of an input series is kept across intervals until the
+// series receives a staleness marker or doesn't receive any sample for
+// seriesTTL, so that output series don't flap when input series are scraped
+// less often than the interval.
+type aggregator struct {
+	mut sync.Mutex
+
+	next      storage.Appendable
+	reduce    func(labels.Labels) labels.Labels
+	op        operation
+	seriesTTL time.Duration
+	groups    map[string]*group
+
+	now func() time.Time // Replaced in tests.
+}
+
+type group struct {
+	labels labels.Labels
+	// inputs holds the latest sample of each input series of the group.
+	inputs map[string]inputSample
+	// active is true if the group was written to in the previous interval, in
+	// which case a staleness marker is written once it has no input series
+	// left.
+	active bool
+}
+
+type inputSample struct {
+	value float64
+	// seen is the time the sample was received at, in milliseconds.
+	seen int64
+}
+
+type pendingSample struct {
+	labels labels.Labels
+	value  float64
+}
+
+var _ storage.Appendable = (*aggregator)(nil)
+
+func newAggregator(next storage.Appendable) *aggregator {
+	return &aggregator{
+		next:   next,
+		groups: make(map[string]*group),
+		now:    time.Now,
+	}
+}
+
+// configure changes how samples are aggregated. The samples of the current
+// interval are discarded.
+func (a *aggregator) configure(op operation, reduce func(labels.Labels) labels.Labels) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	a.op = op
+	a.reduce = reduce
+	a.groups = make(map[string]*group)
+}
+
+// setSeriesTTL changes how long the latest sample of an input series is kept
+// without receiving new samples.
+func (a *aggregator) setSeriesTTL(ttl time.Duration) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	a.seriesTTL = ttl
+}
+
+// Appender implements storage.Appendable.
+func (a *aggregator) Appender(ctx context.Context) storage.Appender {
+	return &aggregatorAppender{a: a, ctx: ctx}
+}
+
+func (a *aggregator) add(samples []pendingSample) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	seen := timestamp.FromTime(a.now())
+	for _, s := range samples {
+		out := a.reduce(s.labels)
+		key := string(out.Bytes(nil))
+		g, ok := a.groups[key]
+		if !ok {
+			g = &group{labels: out, inputs: make(map[string]inputSample)}
+			a.groups[key] = g
+		}
+
+		inputKey := string(s.labels.Bytes(nil))
+		if value.IsStaleNaN(s.value) {
+			delete(g.inputs, inputKey)
+			continue
+		}
+		g.inputs[inputKey] = inputSample{value: s.value, seen: seen}
+	}
+}
+
+// flush writes the aggregated samples of the current interval with timestamp
+// ts, and starts a new interval. Input series which expired are removed
+// first.
+func (a *aggregator) flush(ctx context.Context, ts int64) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	expired := timestamp.FromTime(a.now().Add(-a.seriesTTL))
+	app := a.next.Appender(ctx)
+	for key, g := range a.groups {
+		for inputKey, in := range g.inputs {
+			if a.seriesTTL > 0 && in.seen < expired {
+				delete(g.inputs, inputKey)
+			}
+		}
+
+		v := math.Float64frombits(value.StaleNaN)
+		switch {
+		case len(g.inputs) > 0:
+			v = a.op.aggregate(g.inputs)
+			g.active = true
+		case g.active:
+			delete(a.groups, key)
+		default:
+			delete(a.groups, key)
+			continue
+		}
+
+		if _, err := app.Append(0, g.labels, ts, v); err != nil {
+			_ = app.Rollback()
+			return err
+		}
+	}
+	return app.Commit()
+}
+
+// aggregatorAppender adds samples to the aggregator when the transaction is
+// committed. Native histograms are forwarded unchanged, while exemplars and
+// metadata are dropped since they belong to the input series.
+type aggregatorAppender struct {
+	a       *aggregator
+	ctx     context.Context
+	pending []pendingSample
+
+	histograms storage.Appender
+}
+
+var _ storage.Appender = (*aggregatorAppender)(nil)
+
+func (app *aggregatorAppender) Append(ref storage.SeriesRef, l labels.Labels, _ int64, v float64) (storage.SeriesRef, error) {
+	app.pending = append(app.pending, pendingSample{labels: l, value: v})
+	return ref, nil
+}
+
+func (app *aggregatorAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	if app.histograms == nil {
+		app.histograms = app.a.next.Appender(app.ctx)
+	}
+	return app.histograms.AppendHistogram(ref, l, t, h, fh)
+}
+
+func (app *aggregatorAppender) Commit() error {
+	app.a.add(app.pending)
+	app.pending = nil
+	if app.histograms != nil {
+		return app.histograms.Commit()
+	}
+	return nil
+}
+
+func (app *aggregatorAppender) Rollback() error {
+	app.pending = nil
+	if app.histograms != nil {
+		return app.histograms.Rollback()
+	}
+	return nil
+}
+
+func (app *aggregatorAppender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (app *aggregatorAppender) UpdateMetadata(ref storage.SeriesRef, _ labels.Labels, _ metadata.Metadata) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (app *aggregatorAppender) AppendCTZeroSample(ref storage.SeriesRef, _ labels.Labels, _, _ int64) (storage.SeriesRef, error) {
+	return ref, nil
+}
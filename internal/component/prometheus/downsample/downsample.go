@@ -0,0 +1,244 @@
+package downsample
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.downsample",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// operation is the function used to aggregate the samples of an output
+// series.
+type operation string
+
+const (
+	operationSum   operation = "sum"
+	operationAvg   operation = "avg"
+	operationMin   operation = "min"
+	operationMax   operation = "max"
+	operationCount operation = "count"
+)
+
+func (op operation) aggregate(values map[string]inputSample) float64 {
+	var res float64
+	switch op {
+	case operationMin:
+		res = math.Inf(1)
+	case operationMax:
+		res = math.Inf(-1)
+	}
+	for _, in := range values {
+		v := in.value
+		switch op {
+		case operationSum, operationAvg:
+			res += v
+		case operationMin:
+			res = math.Min(res, v)
+		case operationMax:
+			res = math.Max(res, v)
+		case operationCount:
+			res++
+		}
+	}
+	if op == operationAvg {
+		res /= float64(len(values))
+	}
+	return res
+}
+
+// Arguments holds values which are used to configure the
+// prometheus.downsample component.
+type Arguments struct {
+	// Where the aggregated metrics should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often aggregated samples are written.
+	Interval time.Duration `alloy:"interval,attr,optional"`
+
+	// The function used to aggregate samples.
+	Operation string `alloy:"operation,attr,optional"`
+
+	// The labels to aggregate by. All other labels are removed.
+	By []string `alloy:"by,attr,optional"`
+
+	// The labels to aggregate away. All other labels are kept.
+	Without []string `alloy:"without,attr,optional"`
+
+	// How long the latest sample of an input series is used without
+	// receiving new samples.
+	SeriesTTL time.Duration `alloy:"series_ttl,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Interval:  time.Minute,
+		Operation: string(operationSum),
+		SeriesTTL: 5 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if args.SeriesTTL <= 0 {
+		return fmt.Errorf("series_ttl must be greater than 0")
+	}
+
+	switch operation(args.Operation) {
+	case operationSum, operationAvg, operationMin, operationMax, operationCount:
+	default:
+		return fmt.Errorf("unsupported operation %q, must be one of sum, avg, min, max or count", args.Operation)
+	}
+
+	if len(args.By) > 0 && len(args.Without) > 0 {
+		return fmt.Errorf("only one of by and without can be set")
+	}
+	for _, name := range slices.Concat(args.By, args.Without) {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+		if name == model.MetricNameLabel {
+			return fmt.Errorf("the %s label is always kept and can't be used in by or without", model.MetricNameLabel)
+		}
+	}
+	return nil
+}
+
+// reduceFunc returns the function computing the labels of the output series
+// of an input series.
+func (args *Arguments) reduceFunc() func(labels.Labels) labels.Labels {
+	switch {
+	case len(args.By) > 0:
+		keep := append([]string{model.MetricNameLabel}, args.By...)
+		return func(l labels.Labels) labels.Labels {
+			return labels.NewBuilder(l).Keep(keep...).Labels()
+		}
+	case len(args.Without) > 0:
+		without := args.Without
+		return func(l labels.Labels) labels.Labels {
+			return labels.NewBuilder(l).Del(without...).Labels()
+		}
+	default:
+		return func(l labels.Labels) labels.Labels { return l }
+	}
+}
+
+// Exports holds values which are exported by the prometheus.downsample
+// component.
+type Exports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+// Component implements the prometheus.downsample component.
+type Component struct {
+	opts       component.Options
+	fanout     *prometheus.Fanout
+	aggregator *aggregator
+	updated    chan struct{}
+
+	mut  sync.RWMutex
+	args Arguments
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new prometheus.downsample component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	fanout := prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, data.(labelstore.LabelStore))
+	c := &Component{
+		opts:       o,
+		fanout:     fanout,
+		aggregator: newAggregator(fanout),
+		updated:    make(chan struct{}, 1),
+	}
+
+	o.OnStateChange(Exports{Receiver: c.aggregator})
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	c.mut.RLock()
+	ticker := time.NewTicker(c.args.Interval)
+	c.mut.RUnlock()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.updated:
+			c.mut.RLock()
+			ticker.Reset(c.args.Interval)
+			c.mut.RUnlock()
+		case now := <-ticker.C:
+			if err := c.aggregator.flush(ctx, timestamp.FromTime(now)); err != nil {
+				level.Warn(c.opts.Logger).Log("msg", "failed to write aggregated samples", "err", err)
+			}
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	// Samples of the current interval are only discarded if the way they're
+	// aggregated changes.
+	if c.args.Operation != newArgs.Operation || !slices.Equal(c.args.By, newArgs.By) || !slices.Equal(c.args.Without, newArgs.Without) {
+		c.aggregator.configure(operation(newArgs.Operation), newArgs.reduceFunc())
+	}
+	c.aggregator.setSeriesTTL(newArgs.SeriesTTL)
+	intervalChanged := c.args.Interval != newArgs.Interval
+	c.args = newArgs
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+
+	if intervalChanged {
+		select {
+		case c.updated <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
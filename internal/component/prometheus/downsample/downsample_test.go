@@ -0,0 +1,217 @@
+package downsample
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cfg: `
+			forward_to = []
+			operation  = "avg"
+			without    = ["instance"]`,
+		},
+		{
+			name: "unsupported operation",
+			cfg: `
+			forward_to = []
+			operation  = "median"`,
+			expectedErr: `unsupported operation "median"`,
+		},
+		{
+			name: "by and without",
+			cfg: `
+			forward_to = []
+			by         = ["job"]
+			without    = ["instance"]`,
+			expectedErr: "only one of by and without can be set",
+		},
+		{
+			name: "metric name",
+			cfg: `
+			forward_to = []
+			without    = ["__name__"]`,
+			expectedErr: "label is always kept",
+		},
+		{
+			name: "series ttl",
+			cfg: `
+			forward_to = []
+			series_ttl = "0s"`,
+			expectedErr: "series_ttl must be greater than 0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAggregation(t *testing.T) {
+	series := []labels.Labels{
+		labels.FromStrings("__name__", "requests", "job", "a", "instance", "1"),
+		labels.FromStrings("__name__", "requests", "job", "a", "instance", "2"),
+		labels.FromStrings("__name__", "requests", "job", "b", "instance", "3"),
+	}
+
+	tests := []struct {
+		operation string
+		expected  map[string]float64
+	}{
+		{
+			operation: "sum",
+			expected:  map[string]float64{`{__name__="requests", job="a"}`: 5, `{__name__="requests", job="b"}`: 4},
+		},
+		{
+			operation: "avg",
+			expected:  map[string]float64{`{__name__="requests", job="a"}`: 2.5, `{__name__="requests", job="b"}`: 4},
+		},
+		{
+			operation: "min",
+			expected:  map[string]float64{`{__name__="requests", job="a"}`: 2, `{__name__="requests", job="b"}`: 4},
+		},
+		{
+			operation: "max",
+			expected:  map[string]float64{`{__name__="requests", job="a"}`: 3, `{__name__="requests", job="b"}`: 4},
+		},
+		{
+			operation: "count",
+			expected:  map[string]float64{`{__name__="requests", job="a"}`: 2, `{__name__="requests", job="b"}`: 1},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.operation, func(t *testing.T) {
+			c, written := newTestComponent(t, Arguments{Operation: tc.operation, Without: []string{"instance"}})
+
+			app := c.aggregator.Appender(context.Background())
+			for i, l := range series {
+				// Only the latest sample of each series is aggregated.
+				_, err := app.Append(0, l, 0, 100)
+				require.NoError(t, err)
+				_, err = app.Append(0, l, 1, float64(i+2))
+				require.NoError(t, err)
+			}
+			require.NoError(t, app.Commit())
+
+			require.NoError(t, c.aggregator.flush(context.Background(), 10))
+			require.Equal(t, tc.expected, written)
+		})
+	}
+}
+
+func TestStaleness(t *testing.T) {
+	c, written := newTestComponent(t, Arguments{Operation: "sum", By: []string{"job"}})
+	l := labels.FromStrings("__name__", "requests", "job", "a", "instance", "1")
+
+	app := c.aggregator.Appender(context.Background())
+	_, err := app.Append(0, l, 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.NoError(t, c.aggregator.flush(context.Background(), 10))
+	require.Equal(t, map[string]float64{`{__name__="requests", job="a"}`: 1}, written)
+
+	// The latest sample is kept in intervals without new samples.
+	clear(written)
+	require.NoError(t, c.aggregator.flush(context.Background(), 20))
+	require.Equal(t, map[string]float64{`{__name__="requests", job="a"}`: 1}, written)
+
+	// Output series are marked as stale once when their inputs are stale.
+	app = c.aggregator.Appender(context.Background())
+	_, err = app.Append(0, l, 0, math.Float64frombits(value.StaleNaN))
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.NoError(t, c.aggregator.flush(context.Background(), 30))
+	require.True(t, value.IsStaleNaN(written[`{__name__="requests", job="a"}`]))
+
+	clear(written)
+	require.NoError(t, c.aggregator.flush(context.Background(), 40))
+	require.Empty(t, written)
+}
+
+func TestSeriesTTL(t *testing.T) {
+	c, written := newTestComponent(t, Arguments{Operation: "sum", By: []string{"job"}})
+	now := time.Now()
+	c.aggregator.now = func() time.Time { return now }
+	l1 := labels.FromStrings("__name__", "requests", "job", "a", "instance", "1")
+	l2 := labels.FromStrings("__name__", "requests", "job", "a", "instance", "2")
+
+	app := c.aggregator.Appender(context.Background())
+	_, err := app.Append(0, l1, 0, 1)
+	require.NoError(t, err)
+	_, err = app.Append(0, l2, 0, 2)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// Only the second series keeps receiving samples.
+	now = now.Add(4 * time.Minute)
+	app = c.aggregator.Appender(context.Background())
+	_, err = app.Append(0, l2, 0, 3)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.NoError(t, c.aggregator.flush(context.Background(), 10))
+	require.Equal(t, map[string]float64{`{__name__="requests", job="a"}`: 4}, written)
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, c.aggregator.flush(context.Background(), 20))
+	require.Equal(t, map[string]float64{`{__name__="requests", job="a"}`: 3}, written)
+
+	now = now.Add(4 * time.Minute)
+	require.NoError(t, c.aggregator.flush(context.Background(), 30))
+	require.True(t, value.IsStaleNaN(written[`{__name__="requests", job="a"}`]))
+}
+
+func newTestComponent(t *testing.T, args Arguments) (*Component, map[string]float64) {
+	written := map[string]float64{}
+	ls := labelstore.New(nil, prom.NewRegistry())
+	receiver := prometheus.NewInterceptor(nil, ls, prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+		written[l.String()] = v
+		return ref, nil
+	}))
+
+	args.ForwardTo = []storage.Appendable{receiver}
+	args.Interval = time.Minute
+	args.SeriesTTL = 5 * time.Minute
+	c, err := New(component.Options{
+		ID:            "prometheus.downsample.test",
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prom.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+		GetServiceData: func(name string) (interface{}, error) {
+			if name == labelstore.ServiceName {
+				return ls, nil
+			}
+			return nil, fmt.Errorf("service not found %s", name)
+		},
+	}, args)
+	require.NoError(t, err)
+	return c, written
+}
@@ -39,6 +39,8 @@ type Arguments struct {
 	AzureCloudEnvironment    string   `alloy:"azure_cloud_environment,attr,optional"`
 	ValidateDimensions       bool     `alloy:"validate_dimensions,attr,optional"`
 	Regions                  []string `alloy:"regions,attr,optional"`
+	BatchMode                bool     `alloy:"batch_mode,attr,optional"`
+	BatchSize                int      `alloy:"batch_size,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -53,6 +55,7 @@ func (a *Arguments) SetToDefault() {
 		//  to fully monitor a service which is tedious. Turning off validation eliminates this complexity. The underlying
 		//  sdk will only give back the dimensions which are valid for particular metrics.
 		ValidateDimensions: false,
+		BatchSize:          azure_exporter.DefaultConfig.BatchSize,
 	}
 }
 
@@ -80,5 +83,7 @@ func (a *Arguments) Convert() *azure_exporter.Config {
 		AzureCloudEnvironment:    a.AzureCloudEnvironment,
 		ValidateDimensions:       a.ValidateDimensions,
 		Regions:                  a.Regions,
+		BatchMode:                a.BatchMode,
+		BatchSize:                a.BatchSize,
 	}
 }
@@ -31,7 +31,11 @@ func init() {
 
 func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
 	a := args.(Arguments)
-	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+	cfg := a.Convert()
+	if a.ProbeLogs != nil {
+		cfg.ProbeResultHandler = newProbeLogHandler(opts.Logger, a.ProbeLogs)
+	}
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, cfg, defaultInstanceKey)
 }
 
 // buildBlackboxTargets creates the exporter's discovery targets based on the defined blackbox targets.
@@ -103,6 +107,8 @@ type Arguments struct {
 
 	// New way of passing targets. This allows the component to receive targets from other components.
 	TargetsList TargetsList `alloy:"targets,attr,optional"`
+
+	ProbeLogs *ProbeLogs `alloy:"probe_logs,block,optional"`
 }
 
 type TargetsList []map[string]string
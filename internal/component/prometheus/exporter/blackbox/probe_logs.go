@@ -0,0 +1,102 @@
+package blackbox
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/static/integrations/blackbox_exporter"
+)
+
+// probeLogSendTimeout is how long a probe waits for the receivers to accept
+// its log line before dropping it.
+const probeLogSendTimeout = 2 * time.Second
+
+// ProbeLogs configures the log lines sent for the results of probes.
+type ProbeLogs struct {
+	ForwardTo    []loki.LogsReceiver `alloy:"forward_to,attr"`
+	FailuresOnly bool                `alloy:"failures_only,attr,optional"`
+}
+
+// newProbeLogHandler returns a handler sending a log line with the result of
+// every probe to the receivers.
+func newProbeLogHandler(logger log.Logger, p *ProbeLogs) blackbox_exporter.ProbeResultHandler {
+	return func(res blackbox_exporter.ProbeResult) {
+		if p.FailuresOnly && res.Success {
+			return
+		}
+
+		entry := loki.Entry{
+			Labels: model.LabelSet{
+				"target": model.LabelValue(res.Target),
+				"module": model.LabelValue(res.Module),
+			},
+			Entry: logproto.Entry{
+				Timestamp: res.Time,
+				Line:      formatProbeResult(res),
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), probeLogSendTimeout)
+		defer cancel()
+
+		for _, receiver := range p.ForwardTo {
+			select {
+			case <-ctx.Done():
+				level.Warn(logger).Log("msg", "dropped the log line of a probe, receivers are blocked", "target", res.Target)
+				return
+			case receiver.Chan() <- entry.Clone():
+			}
+		}
+	}
+}
+
+// formatProbeResult formats the result of a probe as a logfmt line. Fields
+// which don't apply to the prober are omitted.
+func formatProbeResult(res blackbox_exporter.ProbeResult) string {
+	keyvals := []interface{}{
+		"target", res.Target,
+		"module", res.Module,
+		"success", strconv.FormatBool(res.Success),
+		"duration_seconds", formatFloat(res.Duration.Seconds()),
+	}
+
+	phases := make([]string, 0, len(res.Phases))
+	for phase := range res.Phases {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		keyvals = append(keyvals, "phase_"+phase+"_seconds", formatFloat(res.Phases[phase]))
+	}
+
+	if res.ResolvedIP != "" {
+		keyvals = append(keyvals, "resolved_ip", res.ResolvedIP)
+	}
+	if res.HTTPStatusCode != 0 {
+		keyvals = append(keyvals, "http_status_code", strconv.Itoa(res.HTTPStatusCode))
+	}
+	if !res.TLSExpiry.IsZero() {
+		keyvals = append(keyvals, "tls_expiry", res.TLSExpiry.Format(time.RFC3339))
+	}
+	if res.FailureReason != "" {
+		keyvals = append(keyvals, "failure_reason", res.FailureReason)
+	}
+
+	var buf bytes.Buffer
+	_ = log.NewLogfmtLogger(&buf).Log(keyvals...)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,50 @@
+package blackbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/static/integrations/blackbox_exporter"
+)
+
+func TestProbeLogHandler(t *testing.T) {
+	receiver := loki.NewLogsReceiverWithChannel(make(chan loki.Entry, 1))
+	handler := newProbeLogHandler(log.NewNopLogger(), &ProbeLogs{ForwardTo: []loki.LogsReceiver{receiver}})
+
+	ts := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	handler(blackbox_exporter.ProbeResult{
+		Target:         "https://example.com",
+		Module:         "http_2xx",
+		Time:           ts,
+		Duration:       250 * time.Millisecond,
+		Phases:         map[string]float64{"resolve": 0.004, "connect": 0.02},
+		ResolvedIP:     "93.184.215.14",
+		HTTPStatusCode: 503,
+		TLSExpiry:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		FailureReason:  "Invalid HTTP response status code, wanted 2xx",
+	})
+
+	entry := <-receiver.Chan()
+	require.Equal(t, model.LabelSet{"target": "https://example.com", "module": "http_2xx"}, entry.Labels)
+	require.Equal(t, ts, entry.Timestamp)
+	require.Equal(t, `target=https://example.com module=http_2xx success=false duration_seconds=0.25 `+
+		`phase_connect_seconds=0.02 phase_resolve_seconds=0.004 resolved_ip=93.184.215.14 http_status_code=503 `+
+		`tls_expiry=2025-01-01T00:00:00Z failure_reason="Invalid HTTP response status code, wanted 2xx"`, entry.Line)
+}
+
+func TestProbeLogHandler_FailuresOnly(t *testing.T) {
+	receiver := loki.NewLogsReceiverWithChannel(make(chan loki.Entry, 2))
+	handler := newProbeLogHandler(log.NewNopLogger(), &ProbeLogs{ForwardTo: []loki.LogsReceiver{receiver}, FailuresOnly: true})
+
+	handler(blackbox_exporter.ProbeResult{Target: "example.com:443", Module: "tcp_connect", Success: true})
+	handler(blackbox_exporter.ProbeResult{Target: "example.com:443", Module: "tcp_connect", FailureReason: "Error dialing TCP: i/o timeout"})
+
+	require.Len(t, receiver.Chan(), 1)
+	entry := <-receiver.Chan()
+	require.Equal(t, `target=example.com:443 module=tcp_connect success=false duration_seconds=0 failure_reason="Error dialing TCP: i/o timeout"`, entry.Line)
+}
@@ -1,6 +1,8 @@
 package cadvisor
 
 import (
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/grafana/alloy/internal/component"
@@ -46,6 +48,13 @@ type Arguments struct {
 	DockerTLSCA                string        `alloy:"docker_tls_ca,attr,optional"`
 	DockerOnly                 bool          `alloy:"docker_only,attr,optional"`
 	DisableRootCgroupStats     bool          `alloy:"disable_root_cgroup_stats,attr,optional"`
+
+	KubernetesNamespaceAllowlist []string `alloy:"kubernetes_namespace_allowlist,attr,optional"`
+	KubernetesNamespaceDenylist  []string `alloy:"kubernetes_namespace_denylist,attr,optional"`
+	KubernetesPodAllowlist       []string `alloy:"kubernetes_pod_allowlist,attr,optional"`
+	CgroupPathAllowlist          []string `alloy:"cgroup_path_allowlist,attr,optional"`
+	CgroupPathDenylist           []string `alloy:"cgroup_path_denylist,attr,optional"`
+	DisableFilesystemStats       bool     `alloy:"disable_filesystem_stats,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -73,6 +82,22 @@ func (a *Arguments) SetToDefault() {
 	}
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	for name, exprs := range map[string][]string{
+		"kubernetes_namespace_allowlist": a.KubernetesNamespaceAllowlist,
+		"kubernetes_namespace_denylist":  a.KubernetesNamespaceDenylist,
+		"kubernetes_pod_allowlist":       a.KubernetesPodAllowlist,
+	} {
+		for _, expr := range exprs {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("invalid regular expression %q in %s: %w", expr, name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // Convert returns the upstream-compatible configuration struct.
 func (a *Arguments) Convert() *cadvisor.Config {
 	if len(a.AllowlistedContainerLabels) == 0 {
@@ -104,6 +129,13 @@ func (a *Arguments) Convert() *cadvisor.Config {
 		DockerTLSCA:                a.DockerTLSCA,
 		DockerOnly:                 a.DockerOnly,
 		DisableRootCgroupStats:     a.DisableRootCgroupStats,
+
+		KubernetesNamespaceAllowlist: a.KubernetesNamespaceAllowlist,
+		KubernetesNamespaceDenylist:  a.KubernetesNamespaceDenylist,
+		KubernetesPodAllowlist:       a.KubernetesPodAllowlist,
+		CgroupPathAllowlist:          a.CgroupPathAllowlist,
+		CgroupPathDenylist:           a.CgroupPathDenylist,
+		DisableFilesystemStats:       a.DisableFilesystemStats,
 	}
 
 	return cfg
@@ -27,6 +27,12 @@ use_docker_tls = true
 docker_tls_cert = "docker_tls_cert"
 docker_tls_key = "docker_tls_key"
 docker_tls_ca = "docker_tls_ca"
+kubernetes_namespace_allowlist = ["prod-.*"]
+kubernetes_namespace_denylist = ["kube-system"]
+kubernetes_pod_allowlist = ["api-.*"]
+cgroup_path_allowlist = ["/kubepods.slice"]
+cgroup_path_denylist = ["/system.slice"]
+disable_filesystem_stats = true
 `
 	var args Arguments
 	err := syntax.Unmarshal([]byte(alloyCfg), &args)
@@ -48,6 +54,13 @@ docker_tls_ca = "docker_tls_ca"
 		DockerTLSCert:              "docker_tls_cert",
 		DockerTLSKey:               "docker_tls_key",
 		DockerTLSCA:                "docker_tls_ca",
+
+		KubernetesNamespaceAllowlist: []string{"prod-.*"},
+		KubernetesNamespaceDenylist:  []string{"kube-system"},
+		KubernetesPodAllowlist:       []string{"api-.*"},
+		CgroupPathAllowlist:          []string{"/kubepods.slice"},
+		CgroupPathDenylist:           []string{"/system.slice"},
+		DisableFilesystemStats:       true,
 	}
 	require.Equal(t, expected, args)
 }
@@ -70,6 +83,11 @@ func TestConvert(t *testing.T) {
 		DockerTLSCert:              "docker_tls_cert",
 		DockerTLSKey:               "docker_tls_key",
 		DockerTLSCA:                "docker_tls_ca",
+
+		KubernetesNamespaceAllowlist: []string{"prod-.*"},
+		KubernetesPodAllowlist:       []string{"api-.*"},
+		CgroupPathDenylist:           []string{"/system.slice"},
+		DisableFilesystemStats:       true,
 	}
 
 	res := args.Convert()
@@ -90,6 +108,20 @@ func TestConvert(t *testing.T) {
 		DockerTLSCert:              "docker_tls_cert",
 		DockerTLSKey:               "docker_tls_key",
 		DockerTLSCA:                "docker_tls_ca",
+
+		KubernetesNamespaceAllowlist: []string{"prod-.*"},
+		KubernetesPodAllowlist:       []string{"api-.*"},
+		CgroupPathDenylist:           []string{"/system.slice"},
+		DisableFilesystemStats:       true,
 	}
 	require.Equal(t, expected, res)
 }
+
+func TestValidate(t *testing.T) {
+	alloyCfg := `
+kubernetes_namespace_allowlist = ["prod-(.*"]
+`
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyCfg), &args)
+	require.ErrorContains(t, err, "invalid regular expression \"prod-(.*\" in kubernetes_namespace_allowlist")
+}
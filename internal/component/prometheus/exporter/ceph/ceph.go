@@ -0,0 +1,92 @@
+package ceph
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/ceph_exporter"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.ceph",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "ceph"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// DefaultArguments holds the default arguments for the prometheus.exporter.ceph component.
+var DefaultArguments = Arguments{
+	URL:              ceph_exporter.DefaultConfig.URL,
+	Timeout:          ceph_exporter.DefaultConfig.Timeout,
+	PoolIncludeRegex: ceph_exporter.DefaultConfig.PoolIncludeRegex,
+	OSDIncludeRegex:  ceph_exporter.DefaultConfig.OSDIncludeRegex,
+}
+
+// Arguments configures the prometheus.exporter.ceph component.
+type Arguments struct {
+	URL       string            `alloy:"url,attr,optional"`
+	Timeout   time.Duration     `alloy:"timeout,attr,optional"`
+	TLSConfig *config.TLSConfig `alloy:"tls_config,block,optional"`
+
+	PoolIncludeRegex string `alloy:"pool_include_regex,attr,optional"`
+	PoolExcludeRegex string `alloy:"pool_exclude_regex,attr,optional"`
+	OSDIncludeRegex  string `alloy:"osd_include_regex,attr,optional"`
+	OSDExcludeRegex  string `alloy:"osd_exclude_regex,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if _, err := url.ParseRequestURI(a.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if a.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	for name, expr := range map[string]string{
+		"pool_include_regex": a.PoolIncludeRegex,
+		"pool_exclude_regex": a.PoolExcludeRegex,
+		"osd_include_regex":  a.OSDIncludeRegex,
+		"osd_exclude_regex":  a.OSDExcludeRegex,
+	} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	if a.TLSConfig == nil {
+		return nil
+	}
+	return a.TLSConfig.Validate()
+}
+
+func (a *Arguments) Convert() *ceph_exporter.Config {
+	return &ceph_exporter.Config{
+		URL:              a.URL,
+		Timeout:          a.Timeout,
+		TLSConfig:        a.TLSConfig.Convert(),
+		PoolIncludeRegex: a.PoolIncludeRegex,
+		PoolExcludeRegex: a.PoolExcludeRegex,
+		OSDIncludeRegex:  a.OSDIncludeRegex,
+		OSDExcludeRegex:  a.OSDExcludeRegex,
+	}
+}
@@ -0,0 +1,73 @@
+package ceph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/ceph_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	url                = "https://ceph-mgr:9283/metrics"
+	timeout            = "30s"
+	pool_exclude_regex = "\\.mgr|test-.*"
+	osd_include_regex  = "osd\\.[0-9]"
+
+	tls_config {
+		ca_file = "/etc/alloy/ca.crt"
+	}
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://ceph-mgr:9283/metrics", args.URL)
+	require.Equal(t, 30*time.Second, args.Timeout)
+	require.Equal(t, ".*", args.PoolIncludeRegex)
+	require.Equal(t, `\.mgr|test-.*`, args.PoolExcludeRegex)
+	require.Equal(t, `osd\.[0-9]`, args.OSDIncludeRegex)
+
+	c := args.Convert()
+	require.Equal(t, "/etc/alloy/ca.crt", c.TLSConfig.CAFile)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "invalid url",
+			alloyCfg: `url = "ceph-mgr"`,
+			err:      `invalid url: parse "ceph-mgr": invalid URI for request`,
+		},
+		{
+			name:     "invalid timeout",
+			alloyCfg: `timeout = "0s"`,
+			err:      "timeout must be greater than 0",
+		},
+		{
+			name:     "invalid regex",
+			alloyCfg: `osd_exclude_regex = "osd\\.("`,
+			err:      "invalid osd_exclude_regex: error parsing regexp: missing closing ): `osd\\.(`",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Checks that the defaults have not drifted between the component and the
+// integration.
+func TestDefaultsSame(t *testing.T) {
+	require.Equal(t, ceph_exporter.DefaultConfig, *DefaultArguments.Convert())
+}
@@ -23,6 +23,14 @@ func init() {
 
 func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
 	a := args.(Arguments)
+	if a.MetricStream.Enabled {
+		tagCache := cloudwatch_exporter.NewTagCache(opts.Logger, cloudwatch_exporter.TagCacheConfig{
+			TTL:               a.TagCache.TTL,
+			RequestsPerSecond: a.TagCache.RequestsPerSecond,
+		}, cloudwatch_exporter.FetchTaggedResources)
+		return cloudwatch_exporter.NewMetricStreamExporter(opts.ID, opts.Logger, toMetricStreamConfig(a), tagCache), getHash(a), nil
+	}
+
 	exporterConfig, err := ConvertToYACE(a)
 	if err != nil {
 		return nil, "", fmt.Errorf("invalid cloudwatch exporter configuration: %w", err)
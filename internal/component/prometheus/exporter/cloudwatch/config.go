@@ -3,10 +3,12 @@ package cloudwatch
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"fmt"
 	"time"
 
 	"github.com/grafana/alloy/internal/static/integrations/cloudwatch_exporter"
 	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	yaceConf "github.com/nerdswords/yet-another-cloudwatch-exporter/pkg/config"
 	yaceModel "github.com/nerdswords/yet-another-cloudwatch-exporter/pkg/model"
 )
@@ -26,6 +28,17 @@ var defaults = Arguments{
 		Enabled:        false,
 		ScrapeInterval: 5 * time.Minute,
 	},
+	MetricStream: defaultMetricStream,
+	TagCache:     defaultTagCache,
+}
+
+var defaultMetricStream = MetricStreamConfig{
+	SeriesTTL: 10 * time.Minute,
+}
+
+var defaultTagCache = TagCacheConfig{
+	TTL:               time.Hour,
+	RequestsPerSecond: 1,
 }
 
 // Arguments are the Alloy based options to configure the embedded CloudWatch exporter.
@@ -37,6 +50,8 @@ type Arguments struct {
 	Discovery             []DiscoveryJob        `alloy:"discovery,block,optional"`
 	Static                []StaticJob           `alloy:"static,block,optional"`
 	DecoupledScrape       DecoupledScrapeConfig `alloy:"decoupled_scraping,block,optional"`
+	MetricStream          MetricStreamConfig    `alloy:"metric_stream,block,optional"`
+	TagCache              TagCacheConfig        `alloy:"tag_cache,block,optional"`
 }
 
 // DecoupledScrapeConfig is the configuration for decoupled scraping feature.
@@ -46,6 +61,33 @@ type DecoupledScrapeConfig struct {
 	ScrapeInterval time.Duration `alloy:"scrape_interval,attr,optional"`
 }
 
+// MetricStreamConfig is the configuration of the metric stream mode, where
+// the metrics are delivered by CloudWatch Metric Streams instead of being
+// polled.
+type MetricStreamConfig struct {
+	Enabled   bool              `alloy:"enabled,attr,optional"`
+	AccessKey alloytypes.Secret `alloy:"access_key,attr,optional"`
+	// SeriesTTL is how long a series is exposed after its last datapoint.
+	SeriesTTL time.Duration `alloy:"series_ttl,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (c *MetricStreamConfig) SetToDefault() {
+	*c = defaultMetricStream
+}
+
+// TagCacheConfig configures the cache of the resource tags added to the
+// metrics delivered by metric streams.
+type TagCacheConfig struct {
+	TTL               time.Duration `alloy:"ttl,attr,optional"`
+	RequestsPerSecond float64       `alloy:"requests_per_second,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (c *TagCacheConfig) SetToDefault() {
+	*c = defaultTagCache
+}
+
 type TagsPerNamespace = cloudwatch_exporter.TagsPerNamespace
 
 // DiscoveryJob configures a discovery job for a given service.
@@ -103,6 +145,38 @@ func (a *Arguments) SetToDefault() {
 	*a = defaults
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.MetricStream.Enabled {
+		if len(a.Discovery) > 0 || len(a.Static) > 0 {
+			return fmt.Errorf("discovery and static jobs can't be used with metric_stream")
+		}
+		if a.DecoupledScrape.Enabled {
+			return fmt.Errorf("decoupled_scraping can't be used with metric_stream")
+		}
+		if a.MetricStream.SeriesTTL <= 0 {
+			return fmt.Errorf("metric_stream series_ttl must be greater than 0")
+		}
+		if a.TagCache.TTL <= 0 {
+			return fmt.Errorf("tag_cache ttl must be greater than 0")
+		}
+		if a.TagCache.RequestsPerSecond <= 0 {
+			return fmt.Errorf("tag_cache requests_per_second must be greater than 0")
+		}
+	}
+	return nil
+}
+
+// toMetricStreamConfig converts the Alloy config into the configuration of
+// the metric stream mode.
+func toMetricStreamConfig(a Arguments) cloudwatch_exporter.MetricStreamConfig {
+	return cloudwatch_exporter.MetricStreamConfig{
+		AccessKey:    string(a.MetricStream.AccessKey),
+		SeriesTTL:    a.MetricStream.SeriesTTL,
+		ExportedTags: a.DiscoveryExportedTags,
+	}
+}
+
 // ConvertToYACE converts the Alloy config into YACE config model. Note that
 // the conversion is not direct, some values have been opinionated to simplify
 // the config model Alloy exposes for this integration.
@@ -2,6 +2,7 @@ package cloudwatch
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/syntax"
 	yaceConf "github.com/nerdswords/yet-another-cloudwatch-exporter/pkg/config"
@@ -474,3 +475,40 @@ func TestCloudwatchComponentConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCloudwatchMetricStreamConfig(t *testing.T) {
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		sts_region = "us-east-2"
+		discovery_exported_tags = { "AWS/EC2" = ["Name"] }
+		metric_stream {
+			enabled    = true
+			access_key = "secret-key"
+		}
+		tag_cache {
+			requests_per_second = 2
+		}
+	`), &args))
+	require.Equal(t, 10*time.Minute, args.MetricStream.SeriesTTL)
+	require.Equal(t, time.Hour, args.TagCache.TTL)
+	require.Equal(t, float64(2), args.TagCache.RequestsPerSecond)
+	require.Equal(t, "secret-key", toMetricStreamConfig(args).AccessKey)
+
+	err := syntax.Unmarshal([]byte(`
+		sts_region = "us-east-2"
+		metric_stream {
+			enabled = true
+		}
+		static "super_ec2_instance_id" {
+			regions    = ["us-east-2"]
+			namespace  = "AWS/EC2"
+			dimensions = { "InstanceId" = "i01u29u12ue1u2c" }
+			metric {
+				name       = "CPUUsage"
+				statistics = ["Sum"]
+				period     = "1m"
+			}
+		}
+	`), &args)
+	require.ErrorContains(t, err, "discovery and static jobs can't be used with metric_stream")
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/static/integrations"
 	"github.com/grafana/alloy/internal/static/integrations/gcp_exporter"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	config_util "github.com/prometheus/common/config"
 )
 
 func init() {
@@ -27,14 +29,37 @@ func createExporter(opts component.Options, args component.Arguments, defaultIns
 }
 
 type Arguments struct {
-	ProjectIDs            []string      `alloy:"project_ids,attr"`
-	MetricPrefixes        []string      `alloy:"metrics_prefixes,attr"`
-	ExtraFilters          []string      `alloy:"extra_filters,attr,optional"`
-	RequestInterval       time.Duration `alloy:"request_interval,attr,optional"`
-	RequestOffset         time.Duration `alloy:"request_offset,attr,optional"`
-	IngestDelay           bool          `alloy:"ingest_delay,attr,optional"`
-	DropDelegatedProjects bool          `alloy:"drop_delegated_projects,attr,optional"`
-	ClientTimeout         time.Duration `alloy:"gcp_client_timeout,attr,optional"`
+	ProjectIDs            []string             `alloy:"project_ids,attr"`
+	MetricPrefixes        []string             `alloy:"metrics_prefixes,attr"`
+	ExtraFilters          []string             `alloy:"extra_filters,attr,optional"`
+	RequestInterval       time.Duration        `alloy:"request_interval,attr,optional"`
+	RequestOffset         time.Duration        `alloy:"request_offset,attr,optional"`
+	IngestDelay           bool                 `alloy:"ingest_delay,attr,optional"`
+	DropDelegatedProjects bool                 `alloy:"drop_delegated_projects,attr,optional"`
+	ClientTimeout         time.Duration        `alloy:"gcp_client_timeout,attr,optional"`
+	ProjectCredentials    []ProjectCredentials `alloy:"project_credentials,block,optional"`
+	AlignedPeriods        []AlignedPeriod      `alloy:"aligned_period,block,optional"`
+}
+
+// ProjectCredentials configures the credentials of a set of projects.
+type ProjectCredentials struct {
+	ProjectIDs      []string          `alloy:"project_ids,attr"`
+	CredentialsFile string            `alloy:"credentials_file,attr,optional"`
+	Credentials     alloytypes.Secret `alloy:"credentials,attr,optional"`
+}
+
+// AlignedPeriod configures the alignment of the points of a set of metrics.
+type AlignedPeriod struct {
+	MetricPrefixes   []string      `alloy:"metrics_prefixes,attr"`
+	Period           time.Duration `alloy:"period,attr"`
+	PerSeriesAligner string        `alloy:"per_series_aligner,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (ap *AlignedPeriod) SetToDefault() {
+	*ap = AlignedPeriod{
+		PerSeriesAligner: gcp_exporter.DefaultAlignedPeriod.PerSeriesAligner,
+	}
 }
 
 var DefaultArguments = Arguments{
@@ -68,5 +93,31 @@ func (a *Arguments) Convert() *gcp_exporter.Config {
 		IngestDelay:           a.IngestDelay,
 		DropDelegatedProjects: a.DropDelegatedProjects,
 		ClientTimeout:         a.ClientTimeout,
+		ProjectCredentials:    convertProjectCredentials(a.ProjectCredentials),
+		AlignedPeriods:        convertAlignedPeriods(a.AlignedPeriods),
+	}
+}
+
+func convertProjectCredentials(projectCredentials []ProjectCredentials) []gcp_exporter.ProjectCredentials {
+	var out []gcp_exporter.ProjectCredentials
+	for _, pc := range projectCredentials {
+		out = append(out, gcp_exporter.ProjectCredentials{
+			ProjectIDs:      pc.ProjectIDs,
+			CredentialsFile: pc.CredentialsFile,
+			Credentials:     config_util.Secret(pc.Credentials),
+		})
+	}
+	return out
+}
+
+func convertAlignedPeriods(alignedPeriods []AlignedPeriod) []gcp_exporter.AlignedPeriod {
+	var out []gcp_exporter.AlignedPeriod
+	for _, ap := range alignedPeriods {
+		out = append(out, gcp_exporter.AlignedPeriod{
+			MetricPrefixes:   ap.MetricPrefixes,
+			Period:           ap.Period,
+			PerSeriesAligner: ap.PerSeriesAligner,
+		})
 	}
+	return out
 }
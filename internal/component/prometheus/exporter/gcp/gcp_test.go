@@ -4,7 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/static/integrations/gcp_exporter"
 	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -107,6 +109,71 @@ func TestConvertConfig(t *testing.T) {
 			}(),
 			expectedUnmarshalError: "",
 		},
+		"healthy project credentials and aligned periods": {
+			alloyCfg: `
+				project_ids = [
+					"foo",
+					"bar",
+				]
+				metrics_prefixes = [
+					"compute.googleapis.com/instance/cpu",
+					"pubsub.googleapis.com/subscription",
+				]
+				project_credentials {
+					project_ids      = ["bar"]
+					credentials_file = "/etc/gcp/bar.json"
+				}
+				aligned_period {
+					metrics_prefixes = ["compute.googleapis.com/instance/cpu"]
+					period           = "1m"
+				}
+				aligned_period {
+					metrics_prefixes   = ["pubsub.googleapis.com/subscription"]
+					period             = "5m"
+					per_series_aligner = "ALIGN_MAX"
+				}
+			`,
+			expectedArgs: func() Arguments {
+				args := DefaultArguments
+				args.ProjectIDs = []string{
+					"foo",
+					"bar",
+				}
+				args.MetricPrefixes = []string{
+					"compute.googleapis.com/instance/cpu",
+					"pubsub.googleapis.com/subscription",
+				}
+				args.ProjectCredentials = []ProjectCredentials{{
+					ProjectIDs:      []string{"bar"},
+					CredentialsFile: "/etc/gcp/bar.json",
+				}}
+				args.AlignedPeriods = []AlignedPeriod{
+					{
+						MetricPrefixes:   []string{"compute.googleapis.com/instance/cpu"},
+						Period:           time.Minute,
+						PerSeriesAligner: "ALIGN_MEAN",
+					},
+					{
+						MetricPrefixes:   []string{"pubsub.googleapis.com/subscription"},
+						Period:           5 * time.Minute,
+						PerSeriesAligner: "ALIGN_MAX",
+					},
+				}
+				return args
+			}(),
+			expectedUnmarshalError: "",
+		},
+		"err project credentials for unknown project": {
+			alloyCfg: `
+				project_ids = ["foo"]
+				metrics_prefixes = ["compute.googleapis.com/instance/cpu"]
+				project_credentials {
+					project_ids = ["bar"]
+					credentials = "{}"
+				}
+			`,
+			expectedUnmarshalError: "project bar has project_credentials but is not in project_ids",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			var args Arguments
@@ -116,8 +183,33 @@ func TestConvertConfig(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, tc.expectedArgs, args)
-				require.Equal(t, args, Arguments(*args.Convert()))
+				require.Equal(t, args, toArguments(args.Convert()))
 			}
 		})
 	}
 }
+
+// toArguments maps the integration config back to Arguments, to check Convert maps all the fields.
+func toArguments(c *gcp_exporter.Config) Arguments {
+	args := Arguments{
+		ProjectIDs:            c.ProjectIDs,
+		MetricPrefixes:        c.MetricPrefixes,
+		ExtraFilters:          c.ExtraFilters,
+		RequestInterval:       c.RequestInterval,
+		RequestOffset:         c.RequestOffset,
+		IngestDelay:           c.IngestDelay,
+		DropDelegatedProjects: c.DropDelegatedProjects,
+		ClientTimeout:         c.ClientTimeout,
+	}
+	for _, pc := range c.ProjectCredentials {
+		args.ProjectCredentials = append(args.ProjectCredentials, ProjectCredentials{
+			ProjectIDs:      pc.ProjectIDs,
+			CredentialsFile: pc.CredentialsFile,
+			Credentials:     alloytypes.Secret(pc.Credentials),
+		})
+	}
+	for _, ap := range c.AlignedPeriods {
+		args.AlignedPeriods = append(args.AlignedPeriods, AlignedPeriod(ap))
+	}
+	return args
+}
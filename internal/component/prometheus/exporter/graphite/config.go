@@ -0,0 +1,96 @@
+package graphite
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/graphite_exporter"
+	"gopkg.in/yaml.v3"
+)
+
+// Arguments configures the prometheus.exporter.graphite component.
+type Arguments struct {
+	ListenTCP     string `alloy:"listen_tcp,attr,optional"`
+	ListenUDP     string `alloy:"listen_udp,attr,optional"`
+	ListenPickle  string `alloy:"listen_pickle,attr,optional"`
+	MappingConfig string `alloy:"mapping_config_path,attr,optional"`
+	StrictMatch   bool   `alloy:"strict_match,attr,optional"`
+
+	SampleExpiry time.Duration `alloy:"sample_expiry,attr,optional"`
+	CacheSize    int           `alloy:"cache_size,attr,optional"`
+	CacheType    string        `alloy:"cache_type,attr,optional"`
+}
+
+// DefaultArguments holds the default settings for the graphite exporter.
+var DefaultArguments = Arguments{
+	ListenTCP: graphite_exporter.DefaultConfig.ListenTCP,
+	ListenUDP: graphite_exporter.DefaultConfig.ListenUDP,
+
+	SampleExpiry: graphite_exporter.DefaultConfig.SampleExpiry,
+	CacheSize:    graphite_exporter.DefaultConfig.CacheSize,
+	CacheType:    graphite_exporter.DefaultConfig.CacheType,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.ListenTCP == "" && a.ListenUDP == "" && a.ListenPickle == "" {
+		return fmt.Errorf("at least one of listen_tcp, listen_udp, or listen_pickle must be set")
+	}
+	if a.SampleExpiry <= 0 {
+		return fmt.Errorf("sample_expiry must be greater than 0")
+	}
+	if a.CacheType != "lru" && a.CacheType != "random" {
+		return fmt.Errorf("cache_type must be one of \"lru\" or \"random\", got %q", a.CacheType)
+	}
+	if a.CacheSize < 0 {
+		return fmt.Errorf("cache_size must not be negative")
+	}
+	return nil
+}
+
+// Convert gives a config suitable for use with github.com/grafana/alloy/internal/static/integrations/graphite_exporter.
+func (a *Arguments) Convert() (*graphite_exporter.Config, error) {
+	var (
+		mappingConfig any
+		err           error
+	)
+
+	if a.MappingConfig != "" {
+		mappingConfig, err = readMappingFile(a.MappingConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert graphite config: %w", err)
+		}
+	}
+
+	return &graphite_exporter.Config{
+		ListenTCP:     a.ListenTCP,
+		ListenUDP:     a.ListenUDP,
+		ListenPickle:  a.ListenPickle,
+		MappingConfig: mappingConfig,
+		StrictMatch:   a.StrictMatch,
+		SampleExpiry:  a.SampleExpiry,
+		CacheSize:     a.CacheSize,
+		CacheType:     a.CacheType,
+	}, nil
+}
+
+func readMappingFile(path string) (any, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping config file: %w", err)
+	}
+
+	var graphiteMapper any
+	err = yaml.Unmarshal(file, &graphiteMapper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mapping config: %w", err)
+	}
+
+	return &graphiteMapper, nil
+}
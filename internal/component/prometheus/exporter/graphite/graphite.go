@@ -0,0 +1,28 @@
+package graphite
+
+import (
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.graphite",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "graphite"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	cfg, err := a.Convert()
+	if err != nil {
+		return nil, "", err
+	}
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, cfg, defaultInstanceKey)
+}
@@ -0,0 +1,78 @@
+package graphite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+		listen_tcp          = ":2003"
+		listen_udp          = ""
+		listen_pickle       = ":2004"
+		mapping_config_path = "./testdata/mapping.yaml"
+		strict_match        = true
+		sample_expiry       = "10m"
+		cache_size          = 10
+		cache_type          = "random"
+	`
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(alloyConfig), &args))
+
+	cfg, err := args.Convert()
+	require.NoError(t, err)
+
+	require.Equal(t, ":2003", cfg.ListenTCP)
+	require.Equal(t, "", cfg.ListenUDP)
+	require.Equal(t, ":2004", cfg.ListenPickle)
+	require.True(t, cfg.StrictMatch)
+	require.Equal(t, 10*time.Minute, cfg.SampleExpiry)
+	require.Equal(t, 10, cfg.CacheSize)
+	require.Equal(t, "random", cfg.CacheType)
+	require.NotNil(t, cfg.MappingConfig)
+}
+
+func TestAlloyUnmarshal_Defaults(t *testing.T) {
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(""), &args))
+
+	cfg, err := args.Convert()
+	require.NoError(t, err)
+
+	require.Equal(t, ":9109", cfg.ListenTCP)
+	require.Equal(t, ":9109", cfg.ListenUDP)
+	require.Equal(t, "", cfg.ListenPickle)
+	require.Equal(t, 5*time.Minute, cfg.SampleExpiry)
+	require.Nil(t, cfg.MappingConfig)
+}
+
+func TestAlloyUnmarshal_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		cfg         string
+		expectedErr string
+	}{
+		"no listener": {
+			cfg: `
+				listen_tcp = ""
+				listen_udp = ""
+			`,
+			expectedErr: "at least one of listen_tcp, listen_udp, or listen_pickle must be set",
+		},
+		"invalid cache type": {
+			cfg:         `cache_type = "fifo"`,
+			expectedErr: `cache_type must be one of "lru" or "random", got "fifo"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
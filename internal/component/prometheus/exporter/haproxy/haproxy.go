@@ -0,0 +1,101 @@
+package haproxy
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/haproxy_exporter"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.haproxy",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "haproxy"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// DefaultArguments holds the default arguments for the prometheus.exporter.haproxy component.
+var DefaultArguments = Arguments{
+	ScrapeURI:            haproxy_exporter.DefaultConfig.ScrapeURI,
+	Timeout:              haproxy_exporter.DefaultConfig.Timeout,
+	FrontendIncludeRegex: haproxy_exporter.DefaultConfig.FrontendIncludeRegex,
+	BackendIncludeRegex:  haproxy_exporter.DefaultConfig.BackendIncludeRegex,
+}
+
+// Arguments configures the prometheus.exporter.haproxy component.
+type Arguments struct {
+	ScrapeURI string            `alloy:"scrape_uri,attr,optional"`
+	Timeout   time.Duration     `alloy:"timeout,attr,optional"`
+	TLSConfig *config.TLSConfig `alloy:"tls_config,block,optional"`
+
+	FrontendIncludeRegex string `alloy:"frontend_include_regex,attr,optional"`
+	FrontendExcludeRegex string `alloy:"frontend_exclude_regex,attr,optional"`
+	BackendIncludeRegex  string `alloy:"backend_include_regex,attr,optional"`
+	BackendExcludeRegex  string `alloy:"backend_exclude_regex,attr,optional"`
+
+	DisableServerMetrics bool `alloy:"disable_server_metrics,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	u, err := url.ParseRequestURI(a.ScrapeURI)
+	if err != nil {
+		return fmt.Errorf("invalid scrape_uri: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "unix":
+	default:
+		return fmt.Errorf("unsupported scheme %q of scrape_uri, must be http, https, or unix", u.Scheme)
+	}
+	if a.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	for name, expr := range map[string]string{
+		"frontend_include_regex": a.FrontendIncludeRegex,
+		"frontend_exclude_regex": a.FrontendExcludeRegex,
+		"backend_include_regex":  a.BackendIncludeRegex,
+		"backend_exclude_regex":  a.BackendExcludeRegex,
+	} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	if a.TLSConfig == nil {
+		return nil
+	}
+	return a.TLSConfig.Validate()
+}
+
+func (a *Arguments) Convert() *haproxy_exporter.Config {
+	return &haproxy_exporter.Config{
+		ScrapeURI:            a.ScrapeURI,
+		Timeout:              a.Timeout,
+		TLSConfig:            a.TLSConfig.Convert(),
+		FrontendIncludeRegex: a.FrontendIncludeRegex,
+		FrontendExcludeRegex: a.FrontendExcludeRegex,
+		BackendIncludeRegex:  a.BackendIncludeRegex,
+		BackendExcludeRegex:  a.BackendExcludeRegex,
+		DisableServerMetrics: a.DisableServerMetrics,
+	}
+}
@@ -0,0 +1,74 @@
+package haproxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/haproxy_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	scrape_uri             = "unix:///run/haproxy/admin.sock"
+	timeout                = "2s"
+	frontend_exclude_regex = "stats"
+	backend_include_regex  = "app|api"
+	disable_server_metrics = true
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, "unix:///run/haproxy/admin.sock", args.ScrapeURI)
+	require.Equal(t, 2*time.Second, args.Timeout)
+	require.Equal(t, ".*", args.FrontendIncludeRegex)
+	require.Equal(t, "stats", args.FrontendExcludeRegex)
+	require.Equal(t, "app|api", args.BackendIncludeRegex)
+	require.True(t, args.DisableServerMetrics)
+	require.Nil(t, args.TLSConfig)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "invalid scrape uri",
+			alloyCfg: `scrape_uri = "localhost"`,
+			err:      `invalid scrape_uri: parse "localhost": invalid URI for request`,
+		},
+		{
+			name:     "unsupported scheme",
+			alloyCfg: `scrape_uri = "tcp://localhost:9999"`,
+			err:      `unsupported scheme "tcp" of scrape_uri, must be http, https, or unix`,
+		},
+		{
+			name:     "invalid timeout",
+			alloyCfg: `timeout = "0s"`,
+			err:      "timeout must be greater than 0",
+		},
+		{
+			name:     "invalid regex",
+			alloyCfg: `backend_exclude_regex = "app("`,
+			err:      "invalid backend_exclude_regex: error parsing regexp: missing closing ): `app(`",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Checks that the defaults have not drifted between the component and the
+// integration.
+func TestDefaultsSame(t *testing.T) {
+	require.Equal(t, haproxy_exporter.DefaultConfig, *DefaultArguments.Convert())
+}
@@ -0,0 +1,214 @@
+package kube_state_metrics
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/cluster"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/kube_state_metrics"
+	"github.com/grafana/ckit/peer"
+	"k8s.io/client-go/rest"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.kube_state_metrics",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// availableResources are the resources kube-state-metrics can generate
+// metrics for, including the ones which aren't enabled by default.
+var availableResources = append([]string{
+	"clusterrolebindings",
+	"clusterroles",
+	"endpointslices",
+	"ingressclasses",
+	"rolebindings",
+	"roles",
+	"serviceaccounts",
+}, kube_state_metrics.DefaultResources...)
+
+// Arguments configures the prometheus.exporter.kube_state_metrics component.
+type Arguments struct {
+	Resources                  []string            `alloy:"resources,attr,optional"`
+	Namespaces                 []string            `alloy:"namespaces,attr,optional"`
+	NamespacesDenylist         []string            `alloy:"namespaces_denylist,attr,optional"`
+	MetricAllowlist            []string            `alloy:"metric_allowlist,attr,optional"`
+	MetricDenylist             []string            `alloy:"metric_denylist,attr,optional"`
+	MetricOptIn                []string            `alloy:"metric_opt_in,attr,optional"`
+	MetricLabelsAllowlist      map[string][]string `alloy:"metric_labels_allowlist,attr,optional"`
+	MetricAnnotationsAllowlist map[string][]string `alloy:"metric_annotations_allowlist,attr,optional"`
+
+	// Client settings to connect to Kubernetes.
+	Client kubernetes.ClientArguments `alloy:"client,block,optional"`
+
+	Clustering cluster.ComponentBlock `alloy:"clustering,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = Arguments{
+		Resources: kube_state_metrics.DefaultResources,
+		Client:    kubernetes.DefaultClientArguments,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if len(a.Resources) == 0 {
+		return fmt.Errorf("resources must not be empty")
+	}
+	for _, r := range a.Resources {
+		if !slices.Contains(availableResources, r) {
+			return fmt.Errorf("unknown resource %q", r)
+		}
+	}
+	if len(a.Namespaces) > 0 && len(a.NamespacesDenylist) > 0 {
+		return fmt.Errorf("only one of namespaces and namespaces_denylist can be set")
+	}
+	if len(a.MetricAllowlist) > 0 && len(a.MetricDenylist) > 0 {
+		return fmt.Errorf("only one of metric_allowlist and metric_denylist can be set")
+	}
+	return nil
+}
+
+// Convert returns the upstream-compatible configuration struct.
+func (a *Arguments) Convert(restConfig *rest.Config) *kube_state_metrics.Config {
+	return &kube_state_metrics.Config{
+		Resources:                  a.Resources,
+		Namespaces:                 a.Namespaces,
+		NamespacesDenylist:         a.NamespacesDenylist,
+		MetricAllowlist:            a.MetricAllowlist,
+		MetricDenylist:             a.MetricDenylist,
+		MetricOptIn:                a.MetricOptIn,
+		MetricLabelsAllowlist:      a.MetricLabelsAllowlist,
+		MetricAnnotationsAllowlist: a.MetricAnnotationsAllowlist,
+		Shard:                      0,
+		TotalShards:                1,
+		RESTConfig:                 restConfig,
+	}
+}
+
+// Component is the prometheus.exporter.kube_state_metrics component. When
+// clustering is enabled, the objects are distributed across the instances of
+// the cluster, so that each instance only generates the metrics of its
+// shard.
+type Component struct {
+	*exporter.Component
+
+	opts    component.Options
+	cluster cluster.Cluster
+
+	mut   sync.Mutex
+	args  Arguments
+	shard shard
+}
+
+var _ cluster.Component = (*Component)(nil)
+
+// New creates a new prometheus.exporter.kube_state_metrics component.
+func New(opts component.Options, args Arguments) (*Component, error) {
+	data, err := opts.GetServiceData(cluster.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:    opts,
+		cluster: data.(cluster.Cluster),
+	}
+
+	exp, err := exporter.New(c.createExporter, "kube_state_metrics")(opts, args)
+	if err != nil {
+		return nil, err
+	}
+	c.Component = exp.(*exporter.Component)
+	return c, nil
+}
+
+func (c *Component) createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+
+	restConfig, err := a.Client.BuildRESTConfig(opts.Logger)
+	if err != nil {
+		return nil, "", fmt.Errorf("building Kubernetes client config: %w", err)
+	}
+
+	c.mut.Lock()
+	c.args = a
+	c.shard = c.currentShard(a)
+	cfg := a.Convert(restConfig)
+	cfg.Shard, cfg.TotalShards = c.shard.index, c.shard.total
+	c.mut.Unlock()
+
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, cfg, defaultInstanceKey)
+}
+
+// NotifyClusterChange implements cluster.Component.
+func (c *Component) NotifyClusterChange() {
+	c.mut.Lock()
+	args := c.args
+	changed := args.Clustering.Enabled && c.currentShard(args) != c.shard
+	c.mut.Unlock()
+
+	if !changed {
+		return
+	}
+	if err := c.Component.Update(args); err != nil {
+		level.Error(c.opts.Logger).Log("msg", "failed to update shard after cluster change", "err", err)
+	}
+}
+
+// shard identifies the subset of objects an instance generates metrics for.
+type shard struct {
+	index int32
+	total int
+}
+
+// currentShard returns the shard of the instance.
+func (c *Component) currentShard(args Arguments) shard {
+	if !args.Clustering.Enabled {
+		return shard{index: 0, total: 1}
+	}
+	return shardOf(c.cluster.Peers())
+}
+
+// shardOf returns the shard of the local peer, the index of its name among the
+// sorted names of the participating peers. Every object is assigned to the
+// local peer if it isn't participating.
+func shardOf(peers []peer.Peer) shard {
+	var (
+		names []string
+		self  string
+	)
+	for _, p := range peers {
+		if p.State != peer.StateParticipant {
+			continue
+		}
+		names = append(names, p.Name)
+		if p.Self {
+			self = p.Name
+		}
+	}
+	if self == "" {
+		return shard{index: 0, total: 1}
+	}
+
+	sort.Strings(names)
+	return shard{index: int32(sort.SearchStrings(names, self)), total: len(names)}
+}
@@ -0,0 +1,149 @@
+package kube_state_metrics
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/static/integrations/kube_state_metrics"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/ckit/peer"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestUnmarshalAlloy(t *testing.T) {
+	alloyCfg := `
+resources                    = ["pods", "deployments"]
+namespaces                   = ["default", "monitoring"]
+metric_denylist              = ["kube_pod_status_.*"]
+metric_opt_in                = ["kube_pod_nodeselectors"]
+metric_labels_allowlist      = { "pods" = ["app", "team"] }
+metric_annotations_allowlist = { "deployments" = ["*"] }
+
+clustering {
+	enabled = true
+}
+`
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyCfg), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"pods", "deployments"}, args.Resources)
+	require.Equal(t, []string{"default", "monitoring"}, args.Namespaces)
+	require.Equal(t, []string{"kube_pod_status_.*"}, args.MetricDenylist)
+	require.Equal(t, []string{"kube_pod_nodeselectors"}, args.MetricOptIn)
+	require.Equal(t, map[string][]string{"pods": {"app", "team"}}, args.MetricLabelsAllowlist)
+	require.Equal(t, map[string][]string{"deployments": {"*"}}, args.MetricAnnotationsAllowlist)
+	require.True(t, args.Clustering.Enabled)
+}
+
+func TestUnmarshalAlloy_Defaults(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(""), &args)
+	require.NoError(t, err)
+	require.Equal(t, kube_state_metrics.DefaultResources, args.Resources)
+	require.False(t, args.Clustering.Enabled)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "empty resources",
+			alloyCfg: `resources = []`,
+			err:      "resources must not be empty",
+		},
+		{
+			name:     "unknown resource",
+			alloyCfg: `resources = ["pods", "widgets"]`,
+			err:      `unknown resource "widgets"`,
+		},
+		{
+			name: "namespaces and denylist",
+			alloyCfg: `
+namespaces          = ["default"]
+namespaces_denylist = ["kube-system"]`,
+			err: "only one of namespaces and namespaces_denylist can be set",
+		},
+		{
+			name: "metric allowlist and denylist",
+			alloyCfg: `
+metric_allowlist = ["kube_pod_info"]
+metric_denylist  = ["kube_pod_labels"]`,
+			err: "only one of metric_allowlist and metric_denylist can be set",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	args := Arguments{
+		Resources:          []string{"pods"},
+		NamespacesDenylist: []string{"kube-system"},
+		MetricAllowlist:    []string{"kube_pod_info"},
+	}
+	restConfig := &rest.Config{Host: "https://kubernetes.default.svc"}
+
+	expected := &kube_state_metrics.Config{
+		Resources:          []string{"pods"},
+		NamespacesDenylist: []string{"kube-system"},
+		MetricAllowlist:    []string{"kube_pod_info"},
+		TotalShards:        1,
+		RESTConfig:         restConfig,
+	}
+	require.Equal(t, expected, args.Convert(restConfig))
+}
+
+func TestShardOf(t *testing.T) {
+	var (
+		peerA     = peer.Peer{Name: "a", State: peer.StateParticipant}
+		peerB     = peer.Peer{Name: "b", State: peer.StateParticipant}
+		peerC     = peer.Peer{Name: "c", State: peer.StateParticipant}
+		peerCSelf = peer.Peer{Name: "c", Self: true, State: peer.StateParticipant}
+		peerBSelf = peer.Peer{Name: "b", Self: true, State: peer.StateParticipant}
+	)
+
+	tt := []struct {
+		name     string
+		peers    []peer.Peer
+		expected shard
+	}{
+		{
+			name:     "no peers",
+			expected: shard{index: 0, total: 1},
+		},
+		{
+			name:     "single peer",
+			peers:    []peer.Peer{peerBSelf},
+			expected: shard{index: 0, total: 1},
+		},
+		{
+			name:     "unsorted peers",
+			peers:    []peer.Peer{peerCSelf, peerA, peerB},
+			expected: shard{index: 2, total: 3},
+		},
+		{
+			name:     "terminating peers are ignored",
+			peers:    []peer.Peer{{Name: "a", State: peer.StateTerminating}, peerBSelf, peerC},
+			expected: shard{index: 0, total: 2},
+		},
+		{
+			name:     "self isn't participating",
+			peers:    []peer.Peer{peerA, {Name: "b", Self: true, State: peer.StateViewer}, peerC},
+			expected: shard{index: 0, total: 1},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, shardOf(tc.peers))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package mongodb_atlas
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/mongodb_atlas_exporter"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	config_util "github.com/prometheus/common/config"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.mongodb_atlas",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "mongodb_atlas"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// supportedGranularities are the granularities of the measurements of the
+// Atlas Admin API.
+var supportedGranularities = []time.Duration{time.Minute, 5 * time.Minute, time.Hour, 24 * time.Hour}
+
+// DefaultArguments holds the default settings for the mongodb_atlas exporter.
+var DefaultArguments = Arguments{
+	BaseURL:         mongodb_atlas_exporter.DefaultConfig.BaseURL,
+	Granularity:     mongodb_atlas_exporter.DefaultConfig.Granularity,
+	RefreshInterval: mongodb_atlas_exporter.DefaultConfig.RefreshInterval,
+}
+
+// Arguments controls the mongodb_atlas exporter.
+type Arguments struct {
+	BaseURL                string            `alloy:"base_url,attr,optional"`
+	PublicKey              string            `alloy:"public_key,attr"`
+	PrivateKey             alloytypes.Secret `alloy:"private_key,attr"`
+	Projects               []string          `alloy:"projects,attr,optional"`
+	Clusters               []string          `alloy:"clusters,attr,optional"`
+	Granularity            time.Duration     `alloy:"granularity,attr,optional"`
+	RefreshInterval        time.Duration     `alloy:"refresh_interval,attr,optional"`
+	DisableDiskMetrics     bool              `alloy:"disable_disk_metrics,attr,optional"`
+	DisableDatabaseMetrics bool              `alloy:"disable_database_metrics,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if _, err := url.ParseRequestURI(a.BaseURL); err != nil {
+		return fmt.Errorf("invalid base_url: %w", err)
+	}
+	if a.PublicKey == "" || a.PrivateKey == "" {
+		return fmt.Errorf("public_key and private_key must not be empty")
+	}
+	if !slices.Contains(supportedGranularities, a.Granularity) {
+		return fmt.Errorf("granularity must be one of 1m, 5m, 1h or 24h, got %s", a.Granularity)
+	}
+	if a.RefreshInterval <= 0 {
+		return fmt.Errorf("refresh_interval must be greater than 0")
+	}
+	return nil
+}
+
+func (a *Arguments) Convert() *mongodb_atlas_exporter.Config {
+	return &mongodb_atlas_exporter.Config{
+		BaseURL:                a.BaseURL,
+		PublicKey:              a.PublicKey,
+		PrivateKey:             config_util.Secret(a.PrivateKey),
+		Projects:               a.Projects,
+		Clusters:               a.Clusters,
+		Granularity:            a.Granularity,
+		RefreshInterval:        a.RefreshInterval,
+		DisableDiskMetrics:     a.DisableDiskMetrics,
+		DisableDatabaseMetrics: a.DisableDatabaseMetrics,
+	}
+}
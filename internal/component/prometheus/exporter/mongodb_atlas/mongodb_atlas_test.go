@@ -0,0 +1,90 @@
+package mongodb_atlas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/mongodb_atlas_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	public_key           = "public"
+	private_key          = "private"
+	projects             = ["5f1a2b3c4d5e6f7a8b9c0d1e"]
+	clusters             = ["orders"]
+	granularity          = "5m"
+	refresh_interval     = "2m"
+	disable_disk_metrics = true
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	expected := Arguments{
+		BaseURL:            "https://cloud.mongodb.com",
+		PublicKey:          "public",
+		PrivateKey:         "private",
+		Projects:           []string{"5f1a2b3c4d5e6f7a8b9c0d1e"},
+		Clusters:           []string{"orders"},
+		Granularity:        5 * time.Minute,
+		RefreshInterval:    2 * time.Minute,
+		DisableDiskMetrics: true,
+	}
+	require.Equal(t, expected, args)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name: "missing private key",
+			alloyCfg: `
+	public_key  = "public"
+	private_key = ""`,
+			err: "public_key and private_key must not be empty",
+		},
+		{
+			name: "unsupported granularity",
+			alloyCfg: `
+	public_key  = "public"
+	private_key = "private"
+	granularity = "2m"`,
+			err: "granularity must be one of 1m, 5m, 1h or 24h, got 2m0s",
+		},
+		{
+			name: "invalid refresh interval",
+			alloyCfg: `
+	public_key       = "public"
+	private_key      = "private"
+	refresh_interval = "0s"`,
+			err: "refresh_interval must be greater than 0",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	args := DefaultArguments
+	args.PublicKey = "public"
+	args.PrivateKey = "private"
+	args.Clusters = []string{"orders"}
+
+	expected := mongodb_atlas_exporter.DefaultConfig
+	expected.PublicKey = "public"
+	expected.PrivateKey = "private"
+	expected.Clusters = []string{"orders"}
+	require.Equal(t, &expected, args.Convert())
+}
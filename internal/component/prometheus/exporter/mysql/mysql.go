@@ -1,8 +1,12 @@
 package mysql
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/go-sql-driver/mysql"
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/component/prometheus/exporter"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/static/integrations"
@@ -18,7 +22,7 @@ func init() {
 		Args:      Arguments{},
 		Exports:   exporter.Exports{},
 
-		Build: exporter.New(createExporter, "mysql"),
+		Build: exporter.NewWithTargetBuilder(createExporter, "mysql", buildMySQLTargets),
 	})
 }
 
@@ -27,6 +31,35 @@ func createExporter(opts component.Options, args component.Arguments, defaultIns
 	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
 }
 
+// buildMySQLTargets creates one target per MySQL server when the component
+// collects metrics from multiple servers.
+func buildMySQLTargets(baseTarget discovery.Target, args component.Arguments) []discovery.Target {
+	instances := args.(Arguments).instances()
+	if len(instances) == 0 {
+		return []discovery.Target{baseTarget}
+	}
+
+	targets := make([]discovery.Target, 0, len(instances))
+	for _, instance := range instances {
+		target := make(discovery.Target)
+		// Set extra labels first, meaning that any other labels will override
+		for k, v := range instance.Labels {
+			target[k] = v
+		}
+		for k, v := range baseTarget {
+			target[k] = v
+		}
+
+		if key, err := mysqld_exporter.InstanceKeyForDSN(string(instance.DataSourceName)); err == nil {
+			target["instance"] = key
+		}
+		target["__param_target"] = instance.Name
+
+		targets = append(targets, target)
+	}
+	return targets
+}
+
 // DefaultArguments holds the default settings for the mysqld_exporter integration.
 var DefaultArguments = Arguments{
 	LockWaitTimeout: 2,
@@ -60,6 +93,10 @@ type Arguments struct {
 	// DataSourceName to use to connect to MySQL.
 	DataSourceName alloytypes.Secret `alloy:"data_source_name,attr,optional"`
 
+	// MySQL servers to collect metrics from, instead of DataSourceName.
+	DataSourceNames map[string]alloytypes.Secret `alloy:"data_source_names,attr,optional"`
+	Targets         []Target                     `alloy:"target,block,optional"`
+
 	// Collectors to mark as enabled in addition to the default.
 	EnableCollectors []string `alloy:"enable_collectors,attr,optional"`
 	// Collectors to explicitly mark as disabled.
@@ -83,6 +120,13 @@ type Arguments struct {
 	MySQLUser MySQLUser `alloy:"mysql.user,block,optional"`
 }
 
+// Target is a MySQL server to collect metrics from.
+type Target struct {
+	Name           string            `alloy:"name,attr"`
+	DataSourceName alloytypes.Secret `alloy:"data_source_name,attr"`
+	Labels         map[string]string `alloy:"labels,attr,optional"`
+}
+
 // InfoSchemaProcessList configures the info_schema.processlist collector
 type InfoSchemaProcessList struct {
 	MinTime         int  `alloy:"min_time,attr,optional"`
@@ -136,10 +180,57 @@ func (a *Arguments) Validate() error {
 	if err != nil {
 		return err
 	}
+
+	if len(a.Targets) == 0 && len(a.DataSourceNames) == 0 {
+		return nil
+	}
+	if a.DataSourceName != "" {
+		return fmt.Errorf("data_source_name can't be set along with data_source_names or target blocks")
+	}
+
+	seen := make(map[string]struct{}, len(a.Targets)+len(a.DataSourceNames))
+	for _, instance := range a.instances() {
+		if instance.Name == "" {
+			return fmt.Errorf("the name of a MySQL server must not be empty")
+		}
+		if _, ok := seen[instance.Name]; ok {
+			return fmt.Errorf("MySQL server %q is defined more than once", instance.Name)
+		}
+		seen[instance.Name] = struct{}{}
+
+		if _, err := mysql.ParseDSN(string(instance.DataSourceName)); err != nil {
+			return fmt.Errorf("invalid data source name of MySQL server %q: %w", instance.Name, err)
+		}
+	}
 	return nil
 }
 
+// instances returns the MySQL servers of the target blocks, followed by the
+// ones of DataSourceNames sorted by name.
+func (a *Arguments) instances() []Target {
+	instances := make([]Target, 0, len(a.Targets)+len(a.DataSourceNames))
+	instances = append(instances, a.Targets...)
+
+	names := make([]string, 0, len(a.DataSourceNames))
+	for name := range a.DataSourceNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		instances = append(instances, Target{Name: name, DataSourceName: a.DataSourceNames[name]})
+	}
+	return instances
+}
+
 func (a *Arguments) Convert() *mysqld_exporter.Config {
+	var instances []mysqld_exporter.Instance
+	for _, instance := range a.instances() {
+		instances = append(instances, mysqld_exporter.Instance{
+			Name:           instance.Name,
+			DataSourceName: config_util.Secret(instance.DataSourceName),
+		})
+	}
+
 	return &mysqld_exporter.Config{
 		DataSourceName:                       config_util.Secret(a.DataSourceName),
 		EnableCollectors:                     a.EnableCollectors,
@@ -161,5 +252,6 @@ func (a *Arguments) Convert() *mysqld_exporter.Config {
 		HeartbeatTable:                       a.Heartbeat.Table,
 		HeartbeatUTC:                         a.Heartbeat.UTC,
 		MySQLUserPrivileges:                  a.MySQLUser.Privileges,
+		Instances:                            instances,
 	}
 }
@@ -3,6 +3,7 @@ package mysql
 import (
 	"testing"
 
+	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/static/integrations/mysqld_exporter"
 	"github.com/grafana/alloy/syntax"
 	"github.com/grafana/alloy/syntax/alloytypes"
@@ -171,3 +172,103 @@ func TestValidate_InvalidDataSource(t *testing.T) {
 	}
 	require.Error(t, args.Validate())
 }
+
+func TestAlloyConfigUnmarshal_MultipleInstances(t *testing.T) {
+	var exampleAlloyConfig = `
+	data_source_names = {
+		"reporting" = "root:secret_password@tcp(reporting:3306)/",
+	}
+
+	target {
+		name             = "orders"
+		data_source_name = "root:secret_password@tcp(orders:3306)/"
+		labels           = { "team" = "checkout" }
+	}
+`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.NoError(t, err)
+
+	c := args.Convert()
+	require.Equal(t, []mysqld_exporter.Instance{
+		{Name: "orders", DataSourceName: "root:secret_password@tcp(orders:3306)/"},
+		{Name: "reporting", DataSourceName: "root:secret_password@tcp(reporting:3306)/"},
+	}, c.Instances)
+}
+
+func TestValidate_MultipleInstances(t *testing.T) {
+	tt := []struct {
+		name string
+		args Arguments
+		err  string
+	}{
+		{
+			name: "data_source_name along with targets",
+			args: Arguments{
+				DataSourceName: "root@tcp(localhost:3306)/",
+				Targets:        []Target{{Name: "orders", DataSourceName: "root@tcp(orders:3306)/"}},
+			},
+			err: "data_source_name can't be set along with data_source_names or target blocks",
+		},
+		{
+			name: "duplicate name",
+			args: Arguments{
+				DataSourceNames: map[string]alloytypes.Secret{"orders": "root@tcp(orders-2:3306)/"},
+				Targets:         []Target{{Name: "orders", DataSourceName: "root@tcp(orders:3306)/"}},
+			},
+			err: `MySQL server "orders" is defined more than once`,
+		},
+		{
+			name: "empty name",
+			args: Arguments{
+				Targets: []Target{{DataSourceName: "root@tcp(orders:3306)/"}},
+			},
+			err: "the name of a MySQL server must not be empty",
+		},
+		{
+			name: "invalid data source name",
+			args: Arguments{
+				DataSourceNames: map[string]alloytypes.Secret{"orders": "root@invalid/mydb"},
+			},
+			err: `invalid data source name of MySQL server "orders"`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorContains(t, tc.args.Validate(), tc.err)
+		})
+	}
+}
+
+func TestBuildMySQLTargets(t *testing.T) {
+	baseTarget := discovery.Target{
+		"job":      "integrations/mysql",
+		"instance": "mysql",
+	}
+
+	args := Arguments{
+		DataSourceNames: map[string]alloytypes.Secret{"reporting": "root@tcp(reporting:3306)/"},
+		Targets: []Target{{
+			Name:           "orders",
+			DataSourceName: "root@tcp(orders:3306)/",
+			Labels:         map[string]string{"team": "checkout", "job": "ignored"},
+		}},
+	}
+
+	require.Equal(t, []discovery.Target{
+		{
+			"job":            "integrations/mysql",
+			"instance":       "tcp(orders:3306)/",
+			"team":           "checkout",
+			"__param_target": "orders",
+		},
+		{
+			"job":            "integrations/mysql",
+			"instance":       "tcp(reporting:3306)/",
+			"__param_target": "reporting",
+		},
+	}, buildMySQLTargets(baseTarget, args))
+
+	require.Equal(t, []discovery.Target{baseTarget}, buildMySQLTargets(baseTarget, Arguments{}))
+}
@@ -0,0 +1,100 @@
+package nginx
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/nginx_exporter"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.nginx",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "nginx"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// DefaultArguments holds the default arguments for the prometheus.exporter.nginx component.
+var DefaultArguments = Arguments{
+	URL:                    nginx_exporter.DefaultConfig.URL,
+	PlusAPIVersion:         nginx_exporter.DefaultConfig.PlusAPIVersion,
+	Timeout:                nginx_exporter.DefaultConfig.Timeout,
+	ServerZoneIncludeRegex: nginx_exporter.DefaultConfig.ServerZoneIncludeRegex,
+	UpstreamIncludeRegex:   nginx_exporter.DefaultConfig.UpstreamIncludeRegex,
+}
+
+// Arguments configures the prometheus.exporter.nginx component.
+type Arguments struct {
+	URL            string            `alloy:"url,attr,optional"`
+	Plus           bool              `alloy:"plus,attr,optional"`
+	PlusAPIVersion int               `alloy:"plus_api_version,attr,optional"`
+	Timeout        time.Duration     `alloy:"timeout,attr,optional"`
+	TLSConfig      *config.TLSConfig `alloy:"tls_config,block,optional"`
+
+	ServerZoneIncludeRegex string `alloy:"server_zone_include_regex,attr,optional"`
+	ServerZoneExcludeRegex string `alloy:"server_zone_exclude_regex,attr,optional"`
+	UpstreamIncludeRegex   string `alloy:"upstream_include_regex,attr,optional"`
+	UpstreamExcludeRegex   string `alloy:"upstream_exclude_regex,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if _, err := url.ParseRequestURI(a.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if a.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	if a.Plus && a.PlusAPIVersion < 1 {
+		return fmt.Errorf("plus_api_version must be greater than 0")
+	}
+	for name, expr := range map[string]string{
+		"server_zone_include_regex": a.ServerZoneIncludeRegex,
+		"server_zone_exclude_regex": a.ServerZoneExcludeRegex,
+		"upstream_include_regex":    a.UpstreamIncludeRegex,
+		"upstream_exclude_regex":    a.UpstreamExcludeRegex,
+	} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	if a.TLSConfig == nil {
+		return nil
+	}
+	return a.TLSConfig.Validate()
+}
+
+func (a *Arguments) Convert() *nginx_exporter.Config {
+	return &nginx_exporter.Config{
+		URL:                    a.URL,
+		Plus:                   a.Plus,
+		PlusAPIVersion:         a.PlusAPIVersion,
+		Timeout:                a.Timeout,
+		TLSConfig:              a.TLSConfig.Convert(),
+		ServerZoneIncludeRegex: a.ServerZoneIncludeRegex,
+		ServerZoneExcludeRegex: a.ServerZoneExcludeRegex,
+		UpstreamIncludeRegex:   a.UpstreamIncludeRegex,
+		UpstreamExcludeRegex:   a.UpstreamExcludeRegex,
+	}
+}
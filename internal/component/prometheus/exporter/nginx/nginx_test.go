@@ -0,0 +1,81 @@
+package nginx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/nginx_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	url                       = "https://nginx:8443/api"
+	plus                      = true
+	plus_api_version          = 9
+	timeout                   = "2s"
+	server_zone_exclude_regex = "internal-.*"
+	upstream_include_regex    = "backend|api"
+
+	tls_config {
+		cert_file = "/etc/alloy/client.crt"
+		key_file  = "/etc/alloy/client.key"
+	}
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://nginx:8443/api", args.URL)
+	require.True(t, args.Plus)
+	require.Equal(t, 9, args.PlusAPIVersion)
+	require.Equal(t, 2*time.Second, args.Timeout)
+	require.Equal(t, ".*", args.ServerZoneIncludeRegex)
+	require.Equal(t, "internal-.*", args.ServerZoneExcludeRegex)
+	require.Equal(t, "backend|api", args.UpstreamIncludeRegex)
+	require.Equal(t, "/etc/alloy/client.crt", args.TLSConfig.CertFile)
+
+	c := args.Convert()
+	require.Equal(t, "/etc/alloy/client.key", c.TLSConfig.KeyFile)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "invalid url",
+			alloyCfg: `url = "localhost"`,
+			err:      `invalid url: parse "localhost": invalid URI for request`,
+		},
+		{
+			name:     "invalid regex",
+			alloyCfg: `upstream_exclude_regex = "backend("`,
+			err:      "invalid upstream_exclude_regex: error parsing regexp: missing closing ): `backend(`",
+		},
+		{
+			name: "invalid api version",
+			alloyCfg: `
+	plus             = true
+	plus_api_version = 0`,
+			err: "plus_api_version must be greater than 0",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Checks that the defaults have not drifted between the component and the
+// integration.
+func TestDefaultsSame(t *testing.T) {
+	require.Equal(t, nginx_exporter.DefaultConfig, *DefaultArguments.Convert())
+}
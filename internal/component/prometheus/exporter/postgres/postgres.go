@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/prometheus/exporter"
@@ -66,7 +68,8 @@ func parsePostgresURL(url string) (map[string]string, error) {
 var DefaultArguments = Arguments{
 	DisableSettingsMetrics: false,
 	AutoDiscovery: AutoDiscovery{
-		Enabled: false,
+		Enabled:         false,
+		RefreshInterval: time.Minute,
 	},
 	DisableDefaultMetrics:   false,
 	CustomQueriesConfigPath: "",
@@ -96,6 +99,19 @@ func (a *Arguments) Validate() error {
 	if a.DisableDefaultMetrics && len(a.EnabledCollectors) != 0 {
 		return fmt.Errorf("enabled_collectors cannot be set when disable_default_metrics is true")
 	}
+	for _, expr := range a.AutoDiscovery.DatabaseAllowlistRegex {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid database_allowlist_regex %q: %w", expr, err)
+		}
+	}
+	for _, expr := range a.AutoDiscovery.DatabaseDenylistRegex {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid database_denylist_regex %q: %w", expr, err)
+		}
+	}
+	if a.AutoDiscovery.Enabled && a.AutoDiscovery.RefreshInterval <= 0 {
+		return fmt.Errorf("autodiscovery refresh_interval must be greater than 0")
+	}
 	return nil
 }
 
@@ -104,6 +120,15 @@ type AutoDiscovery struct {
 	Enabled           bool     `alloy:"enabled,attr,optional"`
 	DatabaseAllowlist []string `alloy:"database_allowlist,attr,optional"`
 	DatabaseDenylist  []string `alloy:"database_denylist,attr,optional"`
+
+	DatabaseAllowlistRegex []string      `alloy:"database_allowlist_regex,attr,optional"`
+	DatabaseDenylistRegex  []string      `alloy:"database_denylist_regex,attr,optional"`
+	RefreshInterval        time.Duration `alloy:"refresh_interval,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *AutoDiscovery) SetToDefault() {
+	*a = DefaultArguments.AutoDiscovery
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -122,6 +147,10 @@ func (a *Arguments) convert(instanceName string) *postgres_exporter.Config {
 		QueryPath:              a.CustomQueriesConfigPath,
 		Instance:               instanceName,
 		EnabledCollectors:      a.EnabledCollectors,
+
+		IncludeDatabasesRegex:    a.AutoDiscovery.DatabaseAllowlistRegex,
+		ExcludeDatabasesRegex:    a.AutoDiscovery.DatabaseDenylistRegex,
+		DiscoveryRefreshInterval: a.AutoDiscovery.RefreshInterval,
 	}
 }
 
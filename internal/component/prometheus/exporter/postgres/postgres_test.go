@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/internal/static/integrations/postgres_exporter"
 	"github.com/grafana/alloy/syntax"
@@ -21,6 +22,8 @@ func TestAlloyConfigUnmarshal(t *testing.T) {
 		enabled = false
 		database_allowlist = ["include1"]
 		database_denylist  = ["exclude1", "exclude2"]
+		database_allowlist_regex = ["app_.*"]
+		database_denylist_regex  = ["app_test_.*"]
 	}`
 
 	var args Arguments
@@ -34,6 +37,10 @@ func TestAlloyConfigUnmarshal(t *testing.T) {
 			Enabled:           false,
 			DatabaseDenylist:  []string{"exclude1", "exclude2"},
 			DatabaseAllowlist: []string{"include1"},
+
+			DatabaseAllowlistRegex: []string{"app_.*"},
+			DatabaseDenylistRegex:  []string{"app_test_.*"},
+			RefreshInterval:        time.Minute,
 		},
 		DisableDefaultMetrics:   true,
 		CustomQueriesConfigPath: "/tmp/queries.yaml",
@@ -54,6 +61,8 @@ func TestAlloyConfigConvert(t *testing.T) {
 		enabled = false
 		database_allowlist = ["include1"]
 		database_denylist  = ["exclude1", "exclude2"]
+		database_denylist_regex = ["app_test_.*"]
+		refresh_interval = "30s"
 	}`
 
 	var args Arguments
@@ -72,6 +81,9 @@ func TestAlloyConfigConvert(t *testing.T) {
 		QueryPath:              "/tmp/queries.yaml",
 		Instance:               "test-instance",
 		EnabledCollectors:      []string{"collector1", "collector2"},
+
+		ExcludeDatabasesRegex:    []string{"app_test_.*"},
+		DiscoveryRefreshInterval: 30 * time.Second,
 	}
 	require.Equal(t, expected, *c)
 }
@@ -84,7 +96,7 @@ func TestRiverConfigValidate(t *testing.T) {
 	}{
 		{
 			name: "no errors on default config",
-			args: Arguments{},
+			args: DefaultArguments,
 		},
 		{
 			name: "missing custom queries file path",
@@ -102,6 +114,26 @@ func TestRiverConfigValidate(t *testing.T) {
 			},
 			expectedErr: "enabled_collectors cannot be set when disable_default_metrics is true",
 		},
+		{
+			name: "invalid database allowlist regex",
+			args: Arguments{
+				AutoDiscovery: AutoDiscovery{
+					Enabled:                true,
+					DatabaseAllowlistRegex: []string{"app_(.*"},
+					RefreshInterval:        time.Minute,
+				},
+			},
+			expectedErr: `invalid database_allowlist_regex "app_(.*"`,
+		},
+		{
+			name: "invalid refresh interval",
+			args: Arguments{
+				AutoDiscovery: AutoDiscovery{
+					Enabled: true,
+				},
+			},
+			expectedErr: "autodiscovery refresh_interval must be greater than 0",
+		},
 	}
 
 	for _, tt := range tc {
@@ -0,0 +1,109 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/rabbitmq_exporter"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	config_util "github.com/prometheus/common/config"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.rabbitmq",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "rabbitmq"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// DefaultArguments holds the default arguments for the prometheus.exporter.rabbitmq component.
+var DefaultArguments = Arguments{
+	URL:               rabbitmq_exporter.DefaultConfig.URL,
+	Username:          rabbitmq_exporter.DefaultConfig.Username,
+	Password:          alloytypes.Secret(rabbitmq_exporter.DefaultConfig.Password),
+	Timeout:           rabbitmq_exporter.DefaultConfig.Timeout,
+	Collectors:        rabbitmq_exporter.DefaultConfig.Collectors,
+	VhostIncludeRegex: rabbitmq_exporter.DefaultConfig.VhostIncludeRegex,
+	QueueIncludeRegex: rabbitmq_exporter.DefaultConfig.QueueIncludeRegex,
+}
+
+// Arguments configures the prometheus.exporter.rabbitmq component.
+type Arguments struct {
+	URL       string            `alloy:"url,attr,optional"`
+	Username  string            `alloy:"username,attr,optional"`
+	Password  alloytypes.Secret `alloy:"password,attr,optional"`
+	Timeout   time.Duration     `alloy:"timeout,attr,optional"`
+	TLSConfig *config.TLSConfig `alloy:"tls_config,block,optional"`
+
+	Collectors        []string `alloy:"collectors,attr,optional"`
+	VhostIncludeRegex string   `alloy:"vhost_include_regex,attr,optional"`
+	VhostExcludeRegex string   `alloy:"vhost_exclude_regex,attr,optional"`
+	QueueIncludeRegex string   `alloy:"queue_include_regex,attr,optional"`
+	QueueExcludeRegex string   `alloy:"queue_exclude_regex,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if _, err := url.ParseRequestURI(a.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if a.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	for _, c := range a.Collectors {
+		if !slices.Contains(rabbitmq_exporter.AvailableCollectors, c) {
+			return fmt.Errorf("unknown collector %q", c)
+		}
+	}
+	for name, expr := range map[string]string{
+		"vhost_include_regex": a.VhostIncludeRegex,
+		"vhost_exclude_regex": a.VhostExcludeRegex,
+		"queue_include_regex": a.QueueIncludeRegex,
+		"queue_exclude_regex": a.QueueExcludeRegex,
+	} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	if a.TLSConfig == nil {
+		return nil
+	}
+	return a.TLSConfig.Validate()
+}
+
+func (a *Arguments) Convert() *rabbitmq_exporter.Config {
+	return &rabbitmq_exporter.Config{
+		URL:               a.URL,
+		Username:          a.Username,
+		Password:          config_util.Secret(a.Password),
+		Timeout:           a.Timeout,
+		TLSConfig:         a.TLSConfig.Convert(),
+		Collectors:        a.Collectors,
+		VhostIncludeRegex: a.VhostIncludeRegex,
+		VhostExcludeRegex: a.VhostExcludeRegex,
+		QueueIncludeRegex: a.QueueIncludeRegex,
+		QueueExcludeRegex: a.QueueExcludeRegex,
+	}
+}
@@ -0,0 +1,76 @@
+package rabbitmq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/rabbitmq_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	url                 = "https://rabbitmq:15671"
+	username            = "monitoring"
+	password            = "secret"
+	timeout             = "5s"
+	collectors          = ["overview", "queue"]
+	vhost_exclude_regex = "staging-.*"
+	queue_exclude_regex = "amq\\..*"
+
+	tls_config {
+		insecure_skip_verify = true
+	}
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://rabbitmq:15671", args.URL)
+	require.Equal(t, "monitoring", args.Username)
+	require.Equal(t, 5*time.Second, args.Timeout)
+	require.Equal(t, []string{"overview", "queue"}, args.Collectors)
+	require.Equal(t, ".*", args.VhostIncludeRegex)
+	require.Equal(t, "staging-.*", args.VhostExcludeRegex)
+	require.Equal(t, `amq\..*`, args.QueueExcludeRegex)
+	require.True(t, args.TLSConfig.InsecureSkipVerify)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "unknown collector",
+			alloyCfg: `collectors = ["queue", "shovel"]`,
+			err:      `unknown collector "shovel"`,
+		},
+		{
+			name:     "invalid regex",
+			alloyCfg: `queue_include_regex = "orders("`,
+			err:      "invalid queue_include_regex: error parsing regexp: missing closing ): `orders(`",
+		},
+		{
+			name:     "invalid timeout",
+			alloyCfg: `timeout = "0s"`,
+			err:      "timeout must be greater than 0",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Checks that the defaults have not drifted between the component and the
+// integration.
+func TestDefaultsSame(t *testing.T) {
+	require.Equal(t, rabbitmq_exporter.DefaultConfig, *DefaultArguments.Convert())
+}
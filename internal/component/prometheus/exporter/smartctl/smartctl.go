@@ -0,0 +1,79 @@
+package smartctl
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/smartctl_exporter"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.smartctl",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "smartctl"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// DefaultArguments holds the default arguments for the prometheus.exporter.smartctl component.
+var DefaultArguments = Arguments{
+	SmartctlPath:       smartctl_exporter.DefaultConfig.SmartctlPath,
+	DeviceIncludeRegex: smartctl_exporter.DefaultConfig.DeviceIncludeRegex,
+	ScanInterval:       smartctl_exporter.DefaultConfig.ScanInterval,
+}
+
+// Arguments configures the prometheus.exporter.smartctl component.
+type Arguments struct {
+	SmartctlPath       string        `alloy:"smartctl_path,attr,optional"`
+	Devices            []string      `alloy:"devices,attr,optional"`
+	DeviceIncludeRegex string        `alloy:"device_include_regex,attr,optional"`
+	DeviceExcludeRegex string        `alloy:"device_exclude_regex,attr,optional"`
+	ScanInterval       time.Duration `alloy:"scan_interval,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.SmartctlPath == "" {
+		return fmt.Errorf("smartctl_path must not be empty")
+	}
+	if a.ScanInterval <= 0 {
+		return fmt.Errorf("scan_interval must be greater than 0")
+	}
+	for name, expr := range map[string]string{
+		"device_include_regex": a.DeviceIncludeRegex,
+		"device_exclude_regex": a.DeviceExcludeRegex,
+	} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (a *Arguments) Convert() *smartctl_exporter.Config {
+	return &smartctl_exporter.Config{
+		SmartctlPath:       a.SmartctlPath,
+		Devices:            a.Devices,
+		DeviceIncludeRegex: a.DeviceIncludeRegex,
+		DeviceExcludeRegex: a.DeviceExcludeRegex,
+		ScanInterval:       a.ScanInterval,
+	}
+}
@@ -0,0 +1,64 @@
+package smartctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/smartctl_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	smartctl_path = "/usr/sbin/smartctl"
+	devices       = ["/dev/sda", "/dev/nvme0"]
+	scan_interval = "10m"
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, "/usr/sbin/smartctl", args.SmartctlPath)
+	require.Equal(t, []string{"/dev/sda", "/dev/nvme0"}, args.Devices)
+	require.Equal(t, ".*", args.DeviceIncludeRegex)
+	require.Equal(t, 10*time.Minute, args.ScanInterval)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "empty path",
+			alloyCfg: `smartctl_path = ""`,
+			err:      "smartctl_path must not be empty",
+		},
+		{
+			name:     "invalid scan interval",
+			alloyCfg: `scan_interval = "-1m"`,
+			err:      "scan_interval must be greater than 0",
+		},
+		{
+			name:     "invalid regex",
+			alloyCfg: `device_exclude_regex = "/dev/sd[a"`,
+			err:      "invalid device_exclude_regex: error parsing regexp: missing closing ]: `[a`",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Checks that the defaults have not drifted between the component and the
+// integration.
+func TestDefaultsSame(t *testing.T) {
+	require.Equal(t, smartctl_exporter.DefaultConfig, *DefaultArguments.Convert())
+}
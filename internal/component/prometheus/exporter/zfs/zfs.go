@@ -0,0 +1,91 @@
+package zfs
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/zfs_exporter"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.exporter.zfs",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, "zfs"),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	return integrations.NewIntegrationWithInstanceKey(opts.Logger, a.Convert(), defaultInstanceKey)
+}
+
+// DefaultArguments holds the default arguments for the prometheus.exporter.zfs component.
+var DefaultArguments = Arguments{
+	ZpoolPath:           zfs_exporter.DefaultConfig.ZpoolPath,
+	ZFSPath:             zfs_exporter.DefaultConfig.ZFSPath,
+	ScanInterval:        zfs_exporter.DefaultConfig.ScanInterval,
+	PoolIncludeRegex:    zfs_exporter.DefaultConfig.PoolIncludeRegex,
+	DatasetIncludeRegex: zfs_exporter.DefaultConfig.DatasetIncludeRegex,
+}
+
+// Arguments configures the prometheus.exporter.zfs component.
+type Arguments struct {
+	ZpoolPath    string        `alloy:"zpool_path,attr,optional"`
+	ZFSPath      string        `alloy:"zfs_path,attr,optional"`
+	ScanInterval time.Duration `alloy:"scan_interval,attr,optional"`
+
+	PoolIncludeRegex    string `alloy:"pool_include_regex,attr,optional"`
+	PoolExcludeRegex    string `alloy:"pool_exclude_regex,attr,optional"`
+	DatasetIncludeRegex string `alloy:"dataset_include_regex,attr,optional"`
+	DatasetExcludeRegex string `alloy:"dataset_exclude_regex,attr,optional"`
+
+	DisableDatasetMetrics bool `alloy:"disable_dataset_metrics,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.ZpoolPath == "" || a.ZFSPath == "" {
+		return fmt.Errorf("zpool_path and zfs_path must not be empty")
+	}
+	if a.ScanInterval <= 0 {
+		return fmt.Errorf("scan_interval must be greater than 0")
+	}
+	for name, expr := range map[string]string{
+		"pool_include_regex":    a.PoolIncludeRegex,
+		"pool_exclude_regex":    a.PoolExcludeRegex,
+		"dataset_include_regex": a.DatasetIncludeRegex,
+		"dataset_exclude_regex": a.DatasetExcludeRegex,
+	} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (a *Arguments) Convert() *zfs_exporter.Config {
+	return &zfs_exporter.Config{
+		ZpoolPath:             a.ZpoolPath,
+		ZFSPath:               a.ZFSPath,
+		ScanInterval:          a.ScanInterval,
+		PoolIncludeRegex:      a.PoolIncludeRegex,
+		PoolExcludeRegex:      a.PoolExcludeRegex,
+		DatasetIncludeRegex:   a.DatasetIncludeRegex,
+		DatasetExcludeRegex:   a.DatasetExcludeRegex,
+		DisableDatasetMetrics: a.DisableDatasetMetrics,
+	}
+}
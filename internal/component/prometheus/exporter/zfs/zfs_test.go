@@ -0,0 +1,68 @@
+package zfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/integrations/zfs_exporter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	zpool_path            = "/usr/sbin/zpool"
+	scan_interval         = "5m"
+	pool_exclude_regex    = "backup"
+	dataset_exclude_regex = ".*/docker/.*"
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, "/usr/sbin/zpool", args.ZpoolPath)
+	require.Equal(t, "zfs", args.ZFSPath)
+	require.Equal(t, 5*time.Minute, args.ScanInterval)
+	require.Equal(t, ".*", args.PoolIncludeRegex)
+	require.Equal(t, "backup", args.PoolExcludeRegex)
+	require.Equal(t, ".*/docker/.*", args.DatasetExcludeRegex)
+	require.False(t, args.DisableDatasetMetrics)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "empty path",
+			alloyCfg: `zfs_path = ""`,
+			err:      "zpool_path and zfs_path must not be empty",
+		},
+		{
+			name:     "invalid scan interval",
+			alloyCfg: `scan_interval = "0s"`,
+			err:      "scan_interval must be greater than 0",
+		},
+		{
+			name:     "invalid regex",
+			alloyCfg: `pool_include_regex = "tank("`,
+			err:      "invalid pool_include_regex: error parsing regexp: missing closing ): `tank(`",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Checks that the defaults have not drifted between the component and the
+// integration.
+func TestDefaultsSame(t *testing.T) {
+	require.Equal(t, zfs_exporter.DefaultConfig, *DefaultArguments.Convert())
+}
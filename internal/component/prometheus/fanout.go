@@ -2,6 +2,7 @@ package prometheus
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/prometheus/prometheus/storage"
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/alloy/internal/service/labelstore"
 )
@@ -29,6 +32,15 @@ type Fanout struct {
 	writeLatency   prometheus.Histogram
 	samplesCounter prometheus.Counter
 	ls             labelstore.LabelStore
+
+	// exemplarLimiter caps the rate of forwarded exemplars. A nil limiter
+	// forwards all exemplars.
+	exemplarLimiter  *rate.Limiter
+	exemplarsDropped prometheus.Counter
+
+	// dropStaleMarkers prevents staleness markers from being forwarded. They
+	// are still used to track the staleness of series in the label store.
+	dropStaleMarkers bool
 }
 
 // NewFanout creates a fanout appendable.
@@ -46,15 +58,41 @@ func NewFanout(children []storage.Appendable, componentID string, register prome
 	})
 	_ = register.Register(s)
 
+	ed := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_fanout_exemplars_rate_limited_total",
+		Help: "Total number of exemplars dropped because they exceeded the exemplar rate limit.",
+	})
+	_ = register.Register(ed)
+
 	return &Fanout{
-		children:       children,
-		componentID:    componentID,
-		writeLatency:   wl,
-		samplesCounter: s,
-		ls:             ls,
+		children:         children,
+		componentID:      componentID,
+		writeLatency:     wl,
+		samplesCounter:   s,
+		ls:               ls,
+		exemplarsDropped: ed,
 	}
 }
 
+// SetExemplarLimit caps the number of exemplars forwarded per second. A limit
+// of 0 forwards all exemplars.
+func (f *Fanout) SetExemplarLimit(perSecond float64) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if perSecond <= 0 {
+		f.exemplarLimiter = nil
+		return
+	}
+	burst := int(math.Max(1, math.Ceil(perSecond)))
+	if f.exemplarLimiter == nil {
+		f.exemplarLimiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+		return
+	}
+	f.exemplarLimiter.SetLimit(rate.Limit(perSecond))
+	f.exemplarLimiter.SetBurst(burst)
+}
+
 // UpdateChildren allows changing of the children of the fanout.
 func (f *Fanout) UpdateChildren(children []storage.Appendable) {
 	f.mut.Lock()
@@ -62,6 +100,14 @@ func (f *Fanout) UpdateChildren(children []storage.Appendable) {
 	f.children = children
 }
 
+// SetDropStaleMarkers sets whether staleness markers are dropped instead of
+// being forwarded to the children of the fanout.
+func (f *Fanout) SetDropStaleMarkers(drop bool) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.dropStaleMarkers = drop
+}
+
 // Appender satisfies the Appendable interface.
 func (f *Fanout) Appender(ctx context.Context) storage.Appender {
 	f.mut.RLock()
@@ -82,6 +128,9 @@ func (f *Fanout) Appender(ctx context.Context) storage.Appender {
 		samplesCounter:    f.samplesCounter,
 		ls:                f.ls,
 		stalenessTrackers: make([]labelstore.StalenessTracker, 0),
+		exemplarLimiter:   f.exemplarLimiter,
+		exemplarsDropped:  f.exemplarsDropped,
+		dropStaleMarkers:  f.dropStaleMarkers,
 	}
 
 	for _, x := range f.children {
@@ -101,6 +150,9 @@ type appender struct {
 	start             time.Time
 	ls                labelstore.LabelStore
 	stalenessTrackers []labelstore.StalenessTracker
+	exemplarLimiter   *rate.Limiter
+	exemplarsDropped  prometheus.Counter
+	dropStaleMarkers  bool
 }
 
 var _ storage.Appender = (*appender)(nil)
@@ -118,6 +170,9 @@ func (a *appender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v flo
 		Labels:      l,
 		Value:       v,
 	})
+	if a.dropStaleMarkers && value.IsStaleNaN(v) {
+		return ref, nil
+	}
 	var multiErr error
 	updated := false
 	for _, x := range a.children {
@@ -178,6 +233,10 @@ func (a *appender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exem
 	if ref == 0 {
 		ref = storage.SeriesRef(a.ls.GetOrAddGlobalRefID(l))
 	}
+	if a.exemplarLimiter != nil && !a.exemplarLimiter.Allow() {
+		a.exemplarsDropped.Inc()
+		return ref, nil
+	}
 	var multiErr error
 	for _, x := range a.children {
 		_, err := x.AppendExemplar(ref, l, e)
@@ -213,6 +272,9 @@ func (a *appender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int
 	if ref == 0 {
 		ref = storage.SeriesRef(a.ls.GetOrAddGlobalRefID(l))
 	}
+	if a.dropStaleMarkers && isStaleHistogram(h, fh) {
+		return ref, nil
+	}
 	var multiErr error
 	for _, x := range a.children {
 		_, err := x.AppendHistogram(ref, l, t, h, fh)
@@ -240,6 +302,13 @@ func (a *appender) AppendCTZeroSample(ref storage.SeriesRef, l labels.Labels, t,
 	return ref, multiErr
 }
 
+func isStaleHistogram(h *histogram.Histogram, fh *histogram.FloatHistogram) bool {
+	if h != nil {
+		return value.IsStaleNaN(h.Sum)
+	}
+	return fh != nil && value.IsStaleNaN(fh.Sum)
+}
+
 // NoopMetadataStore implements the MetricMetadataStore interface.
 type NoopMetadataStore map[string]scrape.MetricMetadata
 
@@ -1,11 +1,15 @@
 package prometheus
 
 import (
+	"math"
 	"testing"
 
 	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
 
 	"context"
@@ -28,3 +32,67 @@ func TestCommit(t *testing.T) {
 	err := app.Commit()
 	require.NoError(t, err)
 }
+
+func TestExemplarLimit(t *testing.T) {
+	ls := labelstore.New(nil, prometheus.DefaultRegisterer)
+	child := &countingAppender{}
+	fanout := NewFanout([]storage.Appendable{child}, "", prometheus.NewRegistry(), ls)
+
+	appendExemplars := func(n int) {
+		app := fanout.Appender(context.Background())
+		for i := 0; i < n; i++ {
+			_, err := app.AppendExemplar(0, labels.FromStrings("a", "1"), exemplar.Exemplar{Value: 1})
+			require.NoError(t, err)
+		}
+		require.NoError(t, app.Commit())
+	}
+
+	appendExemplars(10)
+	require.Equal(t, 10, child.exemplars, "exemplars shouldn't be limited by default")
+
+	child.exemplars = 0
+	fanout.SetExemplarLimit(0.001)
+	appendExemplars(10)
+	require.Equal(t, 1, child.exemplars, "only the burst of exemplars should be forwarded")
+
+	child.exemplars = 0
+	fanout.SetExemplarLimit(0)
+	appendExemplars(10)
+	require.Equal(t, 10, child.exemplars, "exemplars shouldn't be limited once the limit is removed")
+}
+
+func TestDropStaleMarkers(t *testing.T) {
+	ls := labelstore.New(nil, prometheus.DefaultRegisterer)
+	child := &countingAppender{}
+	fanout := NewFanout([]storage.Appendable{child}, "", prometheus.NewRegistry(), ls)
+	fanout.SetDropStaleMarkers(true)
+
+	app := fanout.Appender(context.Background())
+	_, err := app.Append(0, labels.FromStrings("a", "1"), 0, 1)
+	require.NoError(t, err)
+	_, err = app.Append(0, labels.FromStrings("a", "1"), 1, math.Float64frombits(value.StaleNaN))
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	require.Equal(t, 1, child.samples, "staleness markers shouldn't be forwarded")
+}
+
+type countingAppender struct {
+	storage.Appender
+	samples   int
+	exemplars int
+}
+
+func (c *countingAppender) Append(ref storage.SeriesRef, _ labels.Labels, _ int64, _ float64) (storage.SeriesRef, error) {
+	c.samples++
+	return ref, nil
+}
+
+func (c *countingAppender) Appender(context.Context) storage.Appender { return c }
+
+func (c *countingAppender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar) (storage.SeriesRef, error) {
+	c.exemplars++
+	return ref, nil
+}
+
+func (c *countingAppender) Commit() error { return nil }
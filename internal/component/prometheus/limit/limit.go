@@ -0,0 +1,189 @@
+package limit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+const (
+	actionDrop      = "drop"
+	actionAggregate = "aggregate"
+
+	// expireInterval is how often inactive series are removed.
+	expireInterval = 30 * time.Second
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.limit",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the prometheus.limit
+// component.
+type Arguments struct {
+	// Where the metrics within the limits should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// Maximum number of active series. 0 means no limit.
+	MaxSeries int `alloy:"max_series,attr,optional"`
+
+	// Maximum number of active series per metric name. 0 means no limit.
+	MaxSeriesPerMetric int `alloy:"max_series_per_metric,attr,optional"`
+
+	// What to do with the series exceeding the limits.
+	OverflowAction string `alloy:"overflow_action,attr,optional"`
+
+	// How long a series stays active without receiving any sample.
+	SeriesTTL time.Duration `alloy:"series_ttl,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		OverflowAction: actionDrop,
+		SeriesTTL:      10 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.MaxSeries < 0 {
+		return fmt.Errorf("max_series must not be negative")
+	}
+	if args.MaxSeriesPerMetric < 0 {
+		return fmt.Errorf("max_series_per_metric must not be negative")
+	}
+	if args.OverflowAction != actionDrop && args.OverflowAction != actionAggregate {
+		return fmt.Errorf("overflow_action must be one of %q or %q, got %q", actionDrop, actionAggregate, args.OverflowAction)
+	}
+	if args.SeriesTTL <= 0 {
+		return fmt.Errorf("series_ttl must be greater than 0")
+	}
+	return nil
+}
+
+// Exports holds values which are exported by the prometheus.limit component.
+type Exports struct {
+	Receiver       storage.Appendable `alloy:"receiver,attr"`
+	LimitedMetrics []string           `alloy:"limited_metrics,attr"`
+}
+
+// Component implements the prometheus.limit component.
+type Component struct {
+	opts    component.Options
+	fanout  *prometheus.Fanout
+	limiter *limiter
+
+	mut            sync.RWMutex
+	args           Arguments
+	limitedMetrics []string
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new prometheus.limit component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	droppedSamples := prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "prometheus_limit_dropped_samples_total",
+		Help: "Total number of samples dropped because their series exceeded the limits.",
+	})
+	activeSeries := prometheus_client.NewGauge(prometheus_client.GaugeOpts{
+		Name: "prometheus_limit_active_series",
+		Help: "Number of active series within the limits.",
+	})
+	for _, metric := range []prometheus_client.Collector{droppedSamples, activeSeries} {
+		if err := o.Registerer.Register(metric); err != nil {
+			return nil, err
+		}
+	}
+
+	fanout := prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, data.(labelstore.LabelStore))
+	c := &Component{
+		opts:           o,
+		fanout:         fanout,
+		limiter:        newLimiter(fanout, droppedSamples, activeSeries),
+		limitedMetrics: []string{},
+	}
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			c.mut.RLock()
+			ttl := c.args.SeriesTTL
+			c.mut.RUnlock()
+
+			c.limiter.expire(now.Add(-ttl))
+			c.exportLimitedMetrics()
+		}
+	}
+}
+
+// exportLimitedMetrics updates the exports when the set of metrics exceeding
+// the limits changes.
+func (c *Component) exportLimitedMetrics() {
+	limited := c.limiter.limitedMetrics()
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if slices.Equal(limited, c.limitedMetrics) {
+		return
+	}
+	c.limitedMetrics = limited
+	c.opts.OnStateChange(Exports{Receiver: c.limiter, LimitedMetrics: limited})
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.args = newArgs
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+	c.limiter.setLimits(limits{
+		maxSeries:          newArgs.MaxSeries,
+		maxSeriesPerMetric: newArgs.MaxSeriesPerMetric,
+		aggregate:          newArgs.OverflowAction == actionAggregate,
+	})
+
+	c.opts.OnStateChange(Exports{Receiver: c.limiter, LimitedMetrics: c.limitedMetrics})
+	return nil
+}
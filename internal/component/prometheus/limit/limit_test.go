@@ -0,0 +1,125 @@
+package limit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+)
+
+func TestLimits(t *testing.T) {
+	c, written := newTestComponent(t, Arguments{MaxSeries: 3, MaxSeriesPerMetric: 2, OverflowAction: actionDrop})
+
+	appendSamples(t, c,
+		labels.FromStrings("__name__", "a", "id", "1"),
+		labels.FromStrings("__name__", "a", "id", "2"),
+		labels.FromStrings("__name__", "a", "id", "3"), // Exceeds max_series_per_metric.
+		labels.FromStrings("__name__", "b", "id", "1"),
+		labels.FromStrings("__name__", "c", "id", "1"), // Exceeds max_series.
+	)
+	require.Equal(t, map[string]float64{
+		`{__name__="a", id="1"}`: 1,
+		`{__name__="a", id="2"}`: 1,
+		`{__name__="b", id="1"}`: 1,
+	}, written)
+
+	c.exportLimitedMetrics()
+	require.Equal(t, []string{"a", "c"}, c.limitedMetrics)
+
+	// A staleness marker frees a series, and expired series are removed.
+	app := c.limiter.Appender(context.Background())
+	_, err := app.Append(0, labels.FromStrings("__name__", "a", "id", "1"), 1, math.Float64frombits(value.StaleNaN))
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	c.limiter.expire(time.Now().Add(time.Minute))
+	c.exportLimitedMetrics()
+	require.Empty(t, c.limitedMetrics)
+
+	clear(written)
+	appendSamples(t, c, labels.FromStrings("__name__", "c", "id", "1"))
+	require.Equal(t, map[string]float64{`{__name__="c", id="1"}`: 1}, written)
+}
+
+func TestLimits_Rollback(t *testing.T) {
+	c, written := newTestComponent(t, Arguments{MaxSeries: 1, OverflowAction: actionDrop})
+
+	// The series of a rolled back transaction don't use up the limits.
+	app := c.limiter.Appender(context.Background())
+	_, err := app.Append(0, labels.FromStrings("__name__", "a"), 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Rollback())
+
+	// A reserved series counts against the limits of concurrent
+	// transactions until it's committed.
+	first := c.limiter.Appender(context.Background())
+	_, err = first.Append(0, labels.FromStrings("__name__", "b"), 0, 1)
+	require.NoError(t, err)
+	second := c.limiter.Appender(context.Background())
+	_, err = second.Append(0, labels.FromStrings("__name__", "c"), 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, second.Commit())
+	require.NoError(t, first.Commit())
+
+	require.Equal(t, map[string]float64{`{__name__="b"}`: 1}, written)
+}
+
+func TestAggregateOverflow(t *testing.T) {
+	c, written := newTestComponent(t, Arguments{MaxSeriesPerMetric: 1, OverflowAction: actionAggregate})
+
+	appendSamples(t, c,
+		labels.FromStrings("__name__", "a", "id", "1"),
+		labels.FromStrings("__name__", "a", "id", "2"),
+		labels.FromStrings("__name__", "a", "id", "3"),
+	)
+	require.Equal(t, map[string]float64{
+		`{__name__="a", id="1"}`:          1,
+		`{__name__="a", overflow="true"}`: 2,
+	}, written)
+}
+
+func appendSamples(t *testing.T, c *Component, series ...labels.Labels) {
+	app := c.limiter.Appender(context.Background())
+	for _, l := range series {
+		_, err := app.Append(0, l, 0, 1)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+}
+
+func newTestComponent(t *testing.T, args Arguments) (*Component, map[string]float64) {
+	written := map[string]float64{}
+	ls := labelstore.New(nil, prom.NewRegistry())
+	receiver := prometheus.NewInterceptor(nil, ls, prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+		written[l.String()] = v
+		return ref, nil
+	}))
+
+	args.ForwardTo = []storage.Appendable{receiver}
+	args.SeriesTTL = 10 * time.Minute
+	c, err := New(component.Options{
+		ID:            "prometheus.limit.test",
+		Logger:        util.TestAlloyLogger(t),
+		Registerer:    prom.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+		GetServiceData: func(name string) (interface{}, error) {
+			if name == labelstore.ServiceName {
+				return ls, nil
+			}
+			return nil, fmt.Errorf("service not found %s", name)
+		},
+	}, args)
+	require.NoError(t, err)
+	return c, written
+}
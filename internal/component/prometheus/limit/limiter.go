@@ -0,0 +1,398 @@
+package limit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// overflowLabel is set to "true" on the series aggregating the overflow of a
+// metric.
+const overflowLabel = "overflow"
+
+// limits configures the limiter.
+type limits struct {
+	maxSeries          int
+	maxSeriesPerMetric int
+	aggregate          bool
+}
+
+// limiter tracks the active series of a metrics stream and only forwards
+// series within the configured limits.
+//
+// Series are identified by the hash of their labels. A series stays active
+// until it receives a staleness marker, or until it hasn't received any sample
+// for longer than the TTL.
+//
+// A new series is reserved when it's appended, so that it counts against the
+// limits of concurrent transactions, and only becomes active when its
+// transaction is committed. Rolled back transactions release their
+// reservations.
+type limiter struct {
+	next storage.Appendable
+
+	droppedSamples prometheus_client.Counter
+	activeSeries   prometheus_client.Gauge
+
+	mut      sync.Mutex
+	limits   limits
+	series   map[uint64]*trackedSeries
+	reserved map[uint64]*reservation
+	// perMetric holds the number of active and reserved series of each
+	// metric.
+	perMetric map[string]int
+	// overflow holds the series which were rejected because of the limits,
+	// by metric name.
+	overflow map[string]map[uint64]*trackedSeries
+}
+
+type trackedSeries struct {
+	metric   string
+	value    float64
+	lastSeen time.Time
+}
+
+// reservation is a new series admitted by uncommitted transactions.
+type reservation struct {
+	metric string
+	refs   int // Number of transactions holding the reservation.
+}
+
+func newLimiter(next storage.Appendable, droppedSamples prometheus_client.Counter, activeSeries prometheus_client.Gauge) *limiter {
+	return &limiter{
+		next:           next,
+		droppedSamples: droppedSamples,
+		activeSeries:   activeSeries,
+		series:         make(map[uint64]*trackedSeries),
+		reserved:       make(map[uint64]*reservation),
+		perMetric:      make(map[string]int),
+		overflow:       make(map[string]map[uint64]*trackedSeries),
+	}
+}
+
+// setLimits changes the limits. Series which are already active are kept,
+// even if they exceed the new limits.
+func (l *limiter) setLimits(lim limits) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	l.limits = lim
+}
+
+// Appender implements storage.Appendable.
+func (l *limiter) Appender(ctx context.Context) storage.Appender {
+	return &limiterAppender{
+		l:        l,
+		next:     l.next.Appender(ctx),
+		staged:   make(map[uint64]*stagedSeries),
+		overflow: make(map[string]int64),
+	}
+}
+
+// admit returns whether a sample of the series with labels lbls is within
+// the limits, and should be forwarded. The changes to the tracked series are
+// staged on a, and only applied when a is committed.
+func (l *limiter) admit(a *limiterAppender, lbls labels.Labels, v float64, now time.Time) bool {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	hash := lbls.Hash()
+	stale := value.IsStaleNaN(v)
+
+	st := a.staged[hash]
+	if st == nil {
+		st = &stagedSeries{metric: lbls.Get(labels.MetricName)}
+		a.staged[hash] = st
+	}
+	st.lastSeen, st.stale = now, stale
+
+	if _, ok := l.series[hash]; ok {
+		st.rejected = false
+		return true
+	}
+	if r, ok := l.reserved[hash]; ok {
+		if !st.reserved {
+			r.refs++
+			st.reserved = true
+		}
+		st.rejected = false
+		return true
+	}
+
+	// Staleness markers of rejected series are never forwarded.
+	if !stale {
+		_, overflow := l.overflow[st.metric][hash]
+		if !overflow && !st.rejected && l.withinLimits(st.metric) {
+			l.reserved[hash] = &reservation{metric: st.metric, refs: 1}
+			l.perMetric[st.metric]++
+			st.reserved = true
+			return true
+		}
+		st.value = v
+		l.droppedSamples.Inc()
+	}
+	st.rejected = true
+	return false
+}
+
+// commit applies the changes staged by a transaction.
+func (l *limiter) commit(staged map[uint64]*stagedSeries) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for hash, st := range staged {
+		if st.rejected {
+			overflow := l.overflow[st.metric]
+			if st.stale {
+				delete(overflow, hash)
+				if len(overflow) == 0 {
+					delete(l.overflow, st.metric)
+				}
+				continue
+			}
+			if overflow == nil {
+				overflow = make(map[uint64]*trackedSeries)
+				l.overflow[st.metric] = overflow
+			}
+			overflow[hash] = &trackedSeries{metric: st.metric, value: st.value, lastSeen: st.lastSeen}
+			continue
+		}
+
+		// The first committed transaction holding a reservation activates
+		// the series.
+		if _, ok := l.reserved[hash]; ok && st.reserved {
+			delete(l.reserved, hash)
+			l.series[hash] = &trackedSeries{metric: st.metric}
+			l.activeSeries.Inc()
+		}
+
+		s, ok := l.series[hash]
+		if !ok {
+			// The series expired during the transaction.
+			continue
+		}
+		if st.stale {
+			l.removeSeries(hash, s)
+		} else {
+			s.lastSeen = st.lastSeen
+		}
+	}
+}
+
+// rollback releases the reservations of a rolled back transaction.
+func (l *limiter) rollback(staged map[uint64]*stagedSeries) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for hash, st := range staged {
+		if !st.reserved {
+			continue
+		}
+		r, ok := l.reserved[hash]
+		if !ok {
+			continue
+		}
+		if r.refs--; r.refs <= 0 {
+			delete(l.reserved, hash)
+			l.decMetric(r.metric)
+		}
+	}
+}
+
+func (l *limiter) withinLimits(metric string) bool {
+	if l.limits.maxSeries > 0 && len(l.series)+len(l.reserved) >= l.limits.maxSeries {
+		return false
+	}
+	if l.limits.maxSeriesPerMetric > 0 && l.perMetric[metric] >= l.limits.maxSeriesPerMetric {
+		return false
+	}
+	return true
+}
+
+// isActive returns whether the series with labels lbls is within the limits,
+// or was admitted by the transaction a.
+func (l *limiter) isActive(a *limiterAppender, lbls labels.Labels) bool {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	hash := lbls.Hash()
+	if _, ok := l.series[hash]; ok {
+		return true
+	}
+	st, ok := a.staged[hash]
+	return ok && st.reserved
+}
+
+func (l *limiter) removeSeries(hash uint64, s *trackedSeries) {
+	delete(l.series, hash)
+	l.decMetric(s.metric)
+	l.activeSeries.Dec()
+}
+
+func (l *limiter) decMetric(metric string) {
+	l.perMetric[metric]--
+	if l.perMetric[metric] <= 0 {
+		delete(l.perMetric, metric)
+	}
+}
+
+// overflowSum returns the sum of the latest values of the rejected series of
+// a metric.
+func (l *limiter) overflowSum(metric string) float64 {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	var sum float64
+	for _, s := range l.overflow[metric] {
+		sum += s.value
+	}
+	return sum
+}
+
+// expire removes the series which haven't received any sample since before
+// deadline.
+func (l *limiter) expire(deadline time.Time) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for hash, s := range l.series {
+		if s.lastSeen.Before(deadline) {
+			l.removeSeries(hash, s)
+		}
+	}
+	for metric, overflow := range l.overflow {
+		for hash, s := range overflow {
+			if s.lastSeen.Before(deadline) {
+				delete(overflow, hash)
+			}
+		}
+		if len(overflow) == 0 {
+			delete(l.overflow, metric)
+		}
+	}
+}
+
+// limitedMetrics returns the sorted names of the metrics which currently have
+// series exceeding the limits.
+func (l *limiter) limitedMetrics() []string {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	names := make([]string, 0, len(l.overflow))
+	for metric := range l.overflow {
+		names = append(names, metric)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// limiterAppender forwards the samples of series within the limits.
+type limiterAppender struct {
+	l    *limiter
+	next storage.Appender
+
+	// staged holds the changes to the tracked series made by the
+	// transaction, which are applied when it's committed.
+	staged map[uint64]*stagedSeries
+
+	// overflow holds the latest timestamp of the rejected samples of each
+	// metric during the transaction, when the overflow is aggregated.
+	overflow map[string]int64
+}
+
+// stagedSeries is the change of a series made by a transaction.
+type stagedSeries struct {
+	metric   string
+	lastSeen time.Time
+	stale    bool    // Whether the latest sample is a staleness marker.
+	reserved bool    // Whether the transaction holds a reservation.
+	rejected bool    // Whether the series exceeds the limits.
+	value    float64 // Latest value of a rejected series.
+}
+
+var _ storage.Appender = (*limiterAppender)(nil)
+
+func (a *limiterAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	if a.l.admit(a, l, v, time.Now()) {
+		return a.next.Append(ref, l, t, v)
+	}
+	if !a.aggregate() || value.IsStaleNaN(v) {
+		return ref, nil
+	}
+	metric := l.Get(labels.MetricName)
+	if latest, ok := a.overflow[metric]; !ok || t > latest {
+		a.overflow[metric] = t
+	}
+	return ref, nil
+}
+
+func (a *limiterAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	var v float64
+	switch {
+	case h != nil:
+		v = h.Sum
+	case fh != nil:
+		v = fh.Sum
+	}
+	// Histograms exceeding the limits are always dropped, since they can't be
+	// aggregated into a float series.
+	if a.l.admit(a, l, v, time.Now()) {
+		return a.next.AppendHistogram(ref, l, t, h, fh)
+	}
+	return ref, nil
+}
+
+func (a *limiterAppender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+	if !a.l.isActive(a, l) {
+		return ref, nil
+	}
+	return a.next.AppendExemplar(ref, l, e)
+}
+
+func (a *limiterAppender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
+	if !a.l.isActive(a, l) {
+		return ref, nil
+	}
+	return a.next.UpdateMetadata(ref, l, m)
+}
+
+func (a *limiterAppender) AppendCTZeroSample(ref storage.SeriesRef, l labels.Labels, t, ct int64) (storage.SeriesRef, error) {
+	if !a.l.isActive(a, l) {
+		return ref, nil
+	}
+	return a.next.AppendCTZeroSample(ref, l, t, ct)
+}
+
+func (a *limiterAppender) Commit() error {
+	a.l.commit(a.staged)
+	a.staged = nil
+
+	for metric, t := range a.overflow {
+		lbls := labels.FromStrings(labels.MetricName, metric, overflowLabel, "true")
+		if _, err := a.next.Append(0, lbls, t, a.l.overflowSum(metric)); err != nil {
+			_ = a.next.Rollback()
+			return err
+		}
+	}
+	return a.next.Commit()
+}
+
+func (a *limiterAppender) Rollback() error {
+	a.l.rollback(a.staged)
+	a.staged = nil
+	return a.next.Rollback()
+}
+
+func (a *limiterAppender) aggregate() bool {
+	a.l.mut.Lock()
+	defer a.l.mut.Unlock()
+	return a.l.limits.aggregate
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -12,11 +13,14 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/ckit/shard"
 	promopv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promopv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	commonConfig "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/scrape"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -65,6 +69,9 @@ type crdManager struct {
 	cluster cluster.Cluster
 
 	client *kubernetes.Clientset
+	// status reports the status of discovered resources. It's nil unless
+	// report_events is enabled.
+	status *statusReporter
 
 	kind string
 }
@@ -73,11 +80,12 @@ const (
 	KindPodMonitor     string = "podMonitor"
 	KindServiceMonitor string = "serviceMonitor"
 	KindProbe          string = "probe"
+	KindScrapeConfig   string = "scrapeConfig"
 )
 
 func newCrdManager(opts component.Options, cluster cluster.Cluster, logger log.Logger, args *operator.Arguments, kind string, ls labelstore.LabelStore) *crdManager {
 	switch kind {
-	case KindPodMonitor, KindServiceMonitor, KindProbe:
+	case KindPodMonitor, KindServiceMonitor, KindProbe, KindScrapeConfig:
 	default:
 		panic(fmt.Sprintf("Unknown kind for crdManager: %s", kind))
 	}
@@ -106,6 +114,20 @@ func (c *crdManager) Run(ctx context.Context) error {
 		return fmt.Errorf("creating kubernetes client: %w", err)
 	}
 
+	var statusTicker <-chan time.Time
+	if c.args.ReportEvents {
+		recorder, stop, err := newEventRecorder(c.client, c.opts.ID)
+		if err != nil {
+			return fmt.Errorf("creating event recorder: %w", err)
+		}
+		defer stop()
+		c.status = newStatusReporter(recorder)
+
+		ticker := time.NewTicker(statusReportInterval)
+		defer ticker.Stop()
+		statusTicker = ticker.C
+	}
+
 	unregisterer := util.WrapWithUnregisterer(c.opts.Registerer)
 	defer unregisterer.UnregisterAll()
 
@@ -126,6 +148,15 @@ func (c *crdManager) Run(ctx context.Context) error {
 	// Start prometheus scrape manager.
 	alloyAppendable := prometheus.NewFanout(c.args.ForwardTo, c.opts.ID, c.opts.Registerer, c.ls)
 	opts := &scrape.Options{}
+	if data, err := c.opts.GetServiceData(http.ServiceName); err == nil {
+		if hdata, ok := data.(http.Data); ok {
+			// Allow scraping components through the in-memory listener, which
+			// is used when probes are routed to a blackbox exporter component.
+			opts.HTTPClientOptions = []commonConfig.HTTPClientOption{
+				commonConfig.WithDialContextFunc(hdata.DialFunc),
+			}
+		}
+	}
 	c.scrapeManager, err = scrape.NewManager(opts, c.logger, alloyAppendable, unregisterer)
 	if err != nil {
 		return fmt.Errorf("creating scrape manager: %w", err)
@@ -154,6 +185,7 @@ func (c *crdManager) Run(ctx context.Context) error {
 			return nil
 		case m := <-c.discoveryManager.SyncCh():
 			cachedTargets = m
+			c.status.reportTargets(c.targetCounts(m))
 			if c.args.Clustering.Enabled {
 				m = filterTargets(m, c.cluster)
 			}
@@ -162,10 +194,65 @@ func (c *crdManager) Run(ctx context.Context) error {
 			// if clustering updates while running, just re-filter the targets and pass them
 			// into scrape manager again, instead of reloading everything
 			targetSetsChan <- filterTargets(cachedTargets, c.cluster)
+		case <-statusTicker:
+			c.status.reportScrapeErrors(c.scrapeErrors())
 		}
 	}
 }
 
+// resourcesByJob returns the `ns/name` key of the resource of each job name.
+func (c *crdManager) resourcesByJob() map[string]string {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	res := make(map[string]string)
+	for key, jobs := range c.crdsToMapKeys {
+		for _, job := range jobs {
+			res[job] = key
+		}
+	}
+	return res
+}
+
+// targetCounts returns the number of targets discovered for each resource,
+// keyed by `ns/name`.
+func (c *crdManager) targetCounts(m map[string][]*targetgroup.Group) map[string]int {
+	resources := c.resourcesByJob()
+	counts := make(map[string]int)
+	for job, groups := range m {
+		key, ok := resources[job]
+		if !ok {
+			continue
+		}
+		for _, group := range groups {
+			counts[key] += len(group.Targets)
+		}
+	}
+	return counts
+}
+
+// scrapeErrors returns the last scrape error of a failing target of each
+// resource, keyed by `ns/name`.
+func (c *crdManager) scrapeErrors() map[string]string {
+	resources := c.resourcesByJob()
+	errs := make(map[string]string)
+	for job, targets := range c.scrapeManager.TargetsActive() {
+		key, ok := resources[job]
+		if !ok {
+			continue
+		}
+		for _, t := range targets {
+			if t.Health() != scrape.HealthBad || t.LastError() == nil {
+				continue
+			}
+			if _, ok := errs[key]; !ok {
+				errs[key] = fmt.Sprintf("Failed to scrape %s: %v", t.URL(), t.LastError())
+			}
+		}
+	}
+	return errs
+}
+
 func (c *crdManager) ClusteringUpdated() {
 	select {
 	case c.clusteringUpdated <- struct{}{}:
@@ -253,6 +340,7 @@ func (c *crdManager) runInformers(restConfig *rest.Config, ctx context.Context)
 	scheme := runtime.NewScheme()
 	for _, add := range []func(*runtime.Scheme) error{
 		promopv1.AddToScheme,
+		promopv1alpha1.AddToScheme,
 	} {
 		if err := add(scheme); err != nil {
 			return fmt.Errorf("unable to register scheme: %w", err)
@@ -311,6 +399,12 @@ func (c *crdManager) configureInformers(ctx context.Context, informers cache.Inf
 		prototype = &promopv1.ServiceMonitor{}
 	case KindProbe:
 		prototype = &promopv1.Probe{}
+	case KindScrapeConfig:
+		// ScrapeConfigs are watched as unstructured objects to keep the fields
+		// the vendored CRD types don't define. See scrapeConfigFromObject.
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(promopv1alpha1.SchemeGroupVersion.WithKind("ScrapeConfig"))
+		prototype = u
 	default:
 		return fmt.Errorf("unknown kind to configure Informers: %s", c.kind)
 	}
@@ -347,6 +441,12 @@ func (c *crdManager) configureInformers(ctx context.Context, informers cache.Inf
 			UpdateFunc: c.onUpdateProbe,
 			DeleteFunc: c.onDeleteProbe,
 		}), resync)
+	case KindScrapeConfig:
+		_, err = informer.AddEventHandlerWithResyncPeriod((toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    c.onAddScrapeConfig,
+			UpdateFunc: c.onUpdateScrapeConfig,
+			DeleteFunc: c.onDeleteScrapeConfig,
+		}), resync)
 	default:
 		return fmt.Errorf("unknown kind to configure Informers: %s", c.kind)
 	}
@@ -403,6 +503,8 @@ func (c *crdManager) addDebugInfo(ns string, name string, err error) {
 }
 
 func (c *crdManager) addPodMonitor(pm *promopv1.PodMonitor) {
+	c.status.setObject(fmt.Sprintf("%s/%s", pm.Namespace, pm.Name), pm)
+
 	var err error
 	gen := configgen.ConfigGenerator{
 		Secrets:                  configgen.NewSecretManager(c.client),
@@ -415,8 +517,8 @@ func (c *crdManager) addPodMonitor(pm *promopv1.PodMonitor) {
 		var scrapeConfig *config.ScrapeConfig
 		scrapeConfig, err = gen.GeneratePodMonitorConfig(pm, ep, i)
 		if err != nil {
-			// TODO(jcreixell): Generate Kubernetes event to inform of this error when running `kubectl get <podmonitor>`.
 			level.Error(c.logger).Log("name", pm.Name, "err", err, "msg", "error generating scrapeconfig from podmonitor")
+			c.status.configError(pm, err)
 			break
 		}
 		mapKeys = append(mapKeys, scrapeConfig.JobName)
@@ -452,12 +554,15 @@ func (c *crdManager) onUpdatePodMonitor(oldObj, newObj interface{}) {
 func (c *crdManager) onDeletePodMonitor(obj interface{}) {
 	pm := obj.(*promopv1.PodMonitor)
 	c.clearConfigs(pm.Namespace, pm.Name)
+	c.status.removeObject(fmt.Sprintf("%s/%s", pm.Namespace, pm.Name))
 	if err := c.apply(); err != nil {
 		level.Error(c.logger).Log("name", pm.Name, "err", err, "msg", "error applying scrape configs after deleting "+c.kind)
 	}
 }
 
 func (c *crdManager) addServiceMonitor(sm *promopv1.ServiceMonitor) {
+	c.status.setObject(fmt.Sprintf("%s/%s", sm.Namespace, sm.Name), sm)
+
 	var err error
 	gen := configgen.ConfigGenerator{
 		Secrets:                  configgen.NewSecretManager(c.client),
@@ -471,8 +576,8 @@ func (c *crdManager) addServiceMonitor(sm *promopv1.ServiceMonitor) {
 		var scrapeConfig *config.ScrapeConfig
 		scrapeConfig, err = gen.GenerateServiceMonitorConfig(sm, ep, i)
 		if err != nil {
-			// TODO(jcreixell): Generate Kubernetes event to inform of this error when running `kubectl get <servicemonitor>`.
 			level.Error(c.logger).Log("name", sm.Name, "err", err, "msg", "error generating scrapeconfig from serviceMonitor")
+			c.status.configError(sm, err)
 			break
 		}
 		mapKeys = append(mapKeys, scrapeConfig.JobName)
@@ -508,12 +613,15 @@ func (c *crdManager) onUpdateServiceMonitor(oldObj, newObj interface{}) {
 func (c *crdManager) onDeleteServiceMonitor(obj interface{}) {
 	pm := obj.(*promopv1.ServiceMonitor)
 	c.clearConfigs(pm.Namespace, pm.Name)
+	c.status.removeObject(fmt.Sprintf("%s/%s", pm.Namespace, pm.Name))
 	if err := c.apply(); err != nil {
 		level.Error(c.logger).Log("name", pm.Name, "err", err, "msg", "error applying scrape configs after deleting "+c.kind)
 	}
 }
 
 func (c *crdManager) addProbe(p *promopv1.Probe) {
+	c.status.setObject(fmt.Sprintf("%s/%s", p.Namespace, p.Name), p)
+
 	var err error
 	gen := configgen.ConfigGenerator{
 		Secrets:                  configgen.NewSecretManager(c.client),
@@ -522,10 +630,13 @@ func (c *crdManager) addProbe(p *promopv1.Probe) {
 		ScrapeOptions:            c.args.Scrape,
 	}
 	var pmc *config.ScrapeConfig
-	pmc, err = gen.GenerateProbeConfig(p)
+	gen.Prober, err = c.proberSpec()
+	if err == nil {
+		pmc, err = gen.GenerateProbeConfig(p)
+	}
 	if err != nil {
-		// TODO(jcreixell): Generate Kubernetes event to inform of this error when running `kubectl get <probe>`.
 		level.Error(c.logger).Log("name", p.Name, "err", err, "msg", "error generating scrapeconfig from probe")
+		c.status.configError(p, err)
 		c.addDebugInfo(p.Namespace, p.Name, err)
 		return
 	}
@@ -541,6 +652,37 @@ func (c *crdManager) addProbe(p *promopv1.Probe) {
 	c.addDebugInfo(p.Namespace, p.Name, err)
 }
 
+// proberSpec returns the prober configured in the component arguments, if
+// any. Probes routed to a prometheus.exporter.blackbox component are scraped
+// through the in-memory listener of the HTTP service.
+func (c *crdManager) proberSpec() (*promopv1.ProberSpec, error) {
+	p := c.args.Prober
+	if p == nil {
+		return nil, nil
+	}
+	if p.ComponentID == "" {
+		return &promopv1.ProberSpec{
+			URL:    p.URL,
+			Scheme: p.Scheme,
+			Path:   p.Path,
+		}, nil
+	}
+
+	data, err := c.opts.GetServiceData(http.ServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HTTP information: %w", err)
+	}
+	hdata, ok := data.(http.Data)
+	if !ok {
+		return nil, fmt.Errorf("unexpected HTTP service data %T", data)
+	}
+	return &promopv1.ProberSpec{
+		URL:    hdata.MemoryListenAddr,
+		Scheme: "http",
+		Path:   path.Join(hdata.HTTPPathForComponent(p.ComponentID), "metrics"),
+	}, nil
+}
+
 func (c *crdManager) onAddProbe(obj interface{}) {
 	pm := obj.(*promopv1.Probe)
 	level.Info(c.logger).Log("msg", "found probe", "name", pm.Name)
@@ -555,11 +697,104 @@ func (c *crdManager) onUpdateProbe(oldObj, newObj interface{}) {
 func (c *crdManager) onDeleteProbe(obj interface{}) {
 	pm := obj.(*promopv1.Probe)
 	c.clearConfigs(pm.Namespace, pm.Name)
+	c.status.removeObject(fmt.Sprintf("%s/%s", pm.Namespace, pm.Name))
 	if err := c.apply(); err != nil {
 		level.Error(c.logger).Log("name", pm.Name, "err", err, "msg", "error applying scrape configs after deleting "+c.kind)
 	}
 }
 
+func (c *crdManager) addScrapeConfig(sc *promopv1alpha1.ScrapeConfig, sds configgen.ScrapeConfigSDConfigs) {
+	c.status.setObject(fmt.Sprintf("%s/%s", sc.Namespace, sc.Name), sc)
+
+	gen := configgen.ConfigGenerator{
+		Secrets:                  configgen.NewSecretManager(c.client),
+		Client:                   &c.args.Client,
+		AdditionalRelabelConfigs: c.args.RelabelConfigs,
+		ScrapeOptions:            c.args.Scrape,
+	}
+	pmc, err := gen.GenerateScrapeConfigConfig(sc, sds)
+	if err != nil {
+		level.Error(c.logger).Log("name", sc.Name, "err", err, "msg", "error generating scrapeconfig from scrapeConfig")
+		c.status.configError(sc, err)
+		c.addDebugInfo(sc.Namespace, sc.Name, err)
+		return
+	}
+	c.mut.Lock()
+	c.discoveryConfigs[pmc.JobName] = pmc.ServiceDiscoveryConfigs
+	c.scrapeConfigs[pmc.JobName] = pmc
+	c.crdsToMapKeys[fmt.Sprintf("%s/%s", sc.Namespace, sc.Name)] = []string{pmc.JobName}
+	c.mut.Unlock()
+
+	if err = c.apply(); err != nil {
+		level.Error(c.logger).Log("name", sc.Name, "err", err, "msg", "error applying scrape configs from "+c.kind)
+	}
+	c.addDebugInfo(sc.Namespace, sc.Name, err)
+}
+
+func (c *crdManager) onAddScrapeConfig(obj interface{}) {
+	sc, sds, err := scrapeConfigFromObject(obj)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to decode scrape config", "err", err)
+		return
+	}
+	level.Info(c.logger).Log("msg", "found scrape config", "name", sc.Name)
+	c.addScrapeConfig(sc, sds)
+}
+func (c *crdManager) onUpdateScrapeConfig(oldObj, newObj interface{}) {
+	old, _, err := scrapeConfigFromObject(oldObj)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to decode scrape config", "err", err)
+		return
+	}
+	c.clearConfigs(old.Namespace, old.Name)
+
+	sc, sds, err := scrapeConfigFromObject(newObj)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to decode scrape config", "name", old.Name, "err", err)
+		return
+	}
+	c.addScrapeConfig(sc, sds)
+}
+
+func (c *crdManager) onDeleteScrapeConfig(obj interface{}) {
+	sc, _, err := scrapeConfigFromObject(obj)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to decode scrape config", "err", err)
+		return
+	}
+	c.clearConfigs(sc.Namespace, sc.Name)
+	c.status.removeObject(fmt.Sprintf("%s/%s", sc.Namespace, sc.Name))
+	if err := c.apply(); err != nil {
+		level.Error(c.logger).Log("name", sc.Name, "err", err, "msg", "error applying scrape configs after deleting "+c.kind)
+	}
+}
+
+// scrapeConfigFromObject decodes an object of the ScrapeConfig informer. The
+// informer watches unstructured objects, so that the service discovery
+// configurations which the vendored CRD types don't define yet are decoded
+// from the spec too.
+func scrapeConfigFromObject(obj interface{}) (*promopv1alpha1.ScrapeConfig, configgen.ScrapeConfigSDConfigs, error) {
+	var sds configgen.ScrapeConfigSDConfigs
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, sds, fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	var sc promopv1alpha1.ScrapeConfig
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &sc); err != nil {
+		return nil, sds, err
+	}
+	if spec, ok := u.Object["spec"].(map[string]interface{}); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &sds); err != nil {
+			return nil, sds, fmt.Errorf("decoding service discovery configs of %s/%s: %w", sc.Namespace, sc.Name, err)
+		}
+	}
+	return &sc, sds, nil
+}
+
 func (c *crdManager) clearConfigs(ns, name string) {
 	c.mut.Lock()
 	defer c.mut.Unlock()
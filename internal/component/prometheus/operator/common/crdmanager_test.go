@@ -8,6 +8,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/prometheus/operator"
+	"github.com/grafana/alloy/internal/component/prometheus/operator/configgen"
 	"github.com/grafana/alloy/internal/service/cluster"
 	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,6 +20,7 @@ import (
 
 	promopv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/stretchr/testify/require"
 )
@@ -167,3 +169,42 @@ func (m *mockScrapeManager) TargetsActive() map[string][]*scrape.Target {
 func (m *mockScrapeManager) ApplyConfig(cfg *config.Config) error {
 	return nil
 }
+
+func TestScrapeConfigFromObject(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1alpha1",
+		"kind":       "ScrapeConfig",
+		"metadata":   map[string]interface{}{"namespace": "operator", "name": "nodes"},
+		"spec": map[string]interface{}{
+			"kubernetesSDConfigs": []interface{}{
+				map[string]interface{}{
+					"role":      "Node",
+					"selectors": []interface{}{map[string]interface{}{"role": "Node", "label": "pool=infra"}},
+				},
+			},
+			"dnsSDConfigs": []interface{}{
+				map[string]interface{}{"names": []interface{}{"_metrics._tcp.example.com"}, "refreshInterval": "1m"},
+			},
+		},
+	}}
+
+	sc, sds, err := scrapeConfigFromObject(obj)
+	require.NoError(t, err)
+	require.Equal(t, "operator", sc.Namespace)
+	require.Equal(t, "nodes", sc.Name)
+
+	refresh := promopv1.Duration("1m")
+	require.Equal(t, configgen.ScrapeConfigSDConfigs{
+		KubernetesSDConfigs: []configgen.KubernetesSDConfig{{
+			Role:      "Node",
+			Selectors: []configgen.K8SSelectorConfig{{Role: "Node", Label: "pool=infra"}},
+		}},
+		DNSSDConfigs: []configgen.DNSSDConfig{{
+			Names:           []string{"_metrics._tcp.example.com"},
+			RefreshInterval: &refresh,
+		}},
+	}, sds)
+
+	_, _, err = scrapeConfigFromObject(&promopv1.ServiceMonitor{})
+	require.Error(t, err)
+}
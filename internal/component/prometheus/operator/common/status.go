@@ -0,0 +1,149 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	promopv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promopv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// statusReportInterval is how often the scrape status of the targets of each
+// resource is reported.
+const statusReportInterval = time.Minute
+
+// Reasons of the events reported on discovered resources.
+const (
+	reasonConfigGenerationFailed = "ConfigGenerationFailed"
+	reasonTargetsDiscovered      = "TargetsDiscovered"
+	reasonNoTargetsDiscovered    = "NoTargetsDiscovered"
+	reasonScrapeFailed           = "ScrapeFailed"
+)
+
+// statusReporter reports the status of discovered resources as Kubernetes
+// events on the resources themselves, so that the owners of a resource can
+// inspect why it doesn't produce any metrics with kubectl describe.
+//
+// To avoid flooding the Kubernetes API, events are only recorded when the
+// status of a resource changes. A nil *statusReporter discards all events.
+type statusReporter struct {
+	recorder record.EventRecorder
+
+	mut sync.Mutex
+	// The following maps are keyed by `ns/name`.
+	objects      map[string]runtime.Object
+	targetCounts map[string]int
+	scrapeErrors map[string]string
+}
+
+func newStatusReporter(recorder record.EventRecorder) *statusReporter {
+	return &statusReporter{
+		recorder:     recorder,
+		objects:      map[string]runtime.Object{},
+		targetCounts: map[string]int{},
+		scrapeErrors: map[string]string{},
+	}
+}
+
+// newEventRecorder returns an event recorder which writes events through
+// client. The returned function stops the recorder.
+func newEventRecorder(client kubernetes.Interface, componentID string) (record.EventRecorder, func(), error) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		promopv1.AddToScheme,
+		promopv1alpha1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			return nil, nil, fmt.Errorf("unable to register scheme: %w", err)
+		}
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(scheme, corev1.EventSource{Component: componentID})
+	return recorder, broadcaster.Shutdown, nil
+}
+
+// setObject registers the resource to report the status of under key.
+func (r *statusReporter) setObject(key string, obj runtime.Object) {
+	if r == nil {
+		return
+	}
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.objects[key] = obj
+}
+
+// removeObject stops reporting the status of the resource under key.
+func (r *statusReporter) removeObject(key string) {
+	if r == nil {
+		return
+	}
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	delete(r.objects, key)
+	delete(r.targetCounts, key)
+	delete(r.scrapeErrors, key)
+}
+
+// configError reports that no scrape configuration could be generated for a
+// resource.
+func (r *statusReporter) configError(obj runtime.Object, err error) {
+	if r == nil {
+		return
+	}
+	r.recorder.Eventf(obj, corev1.EventTypeWarning, reasonConfigGenerationFailed, "Failed to generate scrape configuration: %v", err)
+}
+
+// reportTargets reports the number of targets discovered for each registered
+// resource. Resources missing from counts have no targets.
+func (r *statusReporter) reportTargets(counts map[string]int) {
+	if r == nil {
+		return
+	}
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for key, obj := range r.objects {
+		n := counts[key]
+		if prev, ok := r.targetCounts[key]; ok && prev == n {
+			continue
+		}
+		r.targetCounts[key] = n
+
+		if n == 0 {
+			r.recorder.Event(obj, corev1.EventTypeWarning, reasonNoTargetsDiscovered, "No targets discovered, check that the selectors match existing resources")
+		} else {
+			r.recorder.Eventf(obj, corev1.EventTypeNormal, reasonTargetsDiscovered, "Discovered %d targets", n)
+		}
+	}
+}
+
+// reportScrapeErrors reports the last scrape error of each registered
+// resource. Resources missing from errs are scraped successfully.
+func (r *statusReporter) reportScrapeErrors(errs map[string]string) {
+	if r == nil {
+		return
+	}
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for key, obj := range r.objects {
+		msg := errs[key]
+		if msg == r.scrapeErrors[key] {
+			continue
+		}
+		if msg == "" {
+			delete(r.scrapeErrors, key)
+			continue
+		}
+		r.scrapeErrors[key] = msg
+		r.recorder.Event(obj, corev1.EventTypeWarning, reasonScrapeFailed, msg)
+	}
+}
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-kit/log"
+	promopv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus/operator"
+	"github.com/grafana/alloy/internal/service/cluster"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+func TestStatusReporter(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := newStatusReporter(recorder)
+
+	sm := &promopv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "svcmonitor"}}
+	r.setObject("monitoring/svcmonitor", sm)
+
+	r.reportTargets(map[string]int{})
+	require.Equal(t, "Warning NoTargetsDiscovered No targets discovered, check that the selectors match existing resources", <-recorder.Events)
+
+	// Unchanged counts don't produce events.
+	r.reportTargets(map[string]int{})
+	r.reportTargets(map[string]int{"monitoring/svcmonitor": 3})
+	require.Equal(t, "Normal TargetsDiscovered Discovered 3 targets", <-recorder.Events)
+
+	r.reportScrapeErrors(map[string]string{"monitoring/svcmonitor": "Failed to scrape http://10.0.0.1:9090/metrics: connection refused"})
+	r.reportScrapeErrors(map[string]string{"monitoring/svcmonitor": "Failed to scrape http://10.0.0.1:9090/metrics: connection refused"})
+	require.Equal(t, "Warning ScrapeFailed Failed to scrape http://10.0.0.1:9090/metrics: connection refused", <-recorder.Events)
+
+	r.configError(sm, errors.New("invalid endpoint"))
+	require.Equal(t, "Warning ConfigGenerationFailed Failed to generate scrape configuration: invalid endpoint", <-recorder.Events)
+
+	r.removeObject("monitoring/svcmonitor")
+	r.reportTargets(map[string]int{})
+	require.Empty(t, recorder.Events)
+
+	// A nil reporter discards all events.
+	var nilReporter *statusReporter
+	nilReporter.setObject("monitoring/svcmonitor", sm)
+	nilReporter.reportTargets(map[string]int{})
+}
+
+func TestTargetCounts(t *testing.T) {
+	logger := log.NewNopLogger()
+	m := newCrdManager(
+		component.Options{
+			Logger:         logger,
+			GetServiceData: func(name string) (interface{}, error) { return nil, nil },
+		},
+		cluster.Mock(),
+		logger,
+		&operator.DefaultArguments,
+		KindProbe,
+		labelstore.New(logger, prometheus.DefaultRegisterer),
+	)
+
+	m.discoveryManager = newMockDiscoveryManager()
+	m.scrapeManager = newMockScrapeManager()
+
+	m.onAddProbe(&promopv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "monitoring",
+			Name:      "probe",
+		},
+		Spec: promopv1.ProbeSpec{},
+	})
+
+	counts := m.targetCounts(map[string][]*targetgroup.Group{
+		"probe/monitoring/probe": {
+			{Targets: []model.LabelSet{{model.AddressLabel: "a"}, {model.AddressLabel: "b"}}},
+			{Targets: []model.LabelSet{{model.AddressLabel: "c"}}},
+		},
+		"probe/monitoring/unknown": {
+			{Targets: []model.LabelSet{{model.AddressLabel: "d"}}},
+		},
+	})
+	require.Equal(t, map[string]int{"monitoring/probe": 3}, counts)
+}
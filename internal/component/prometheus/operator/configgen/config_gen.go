@@ -22,6 +22,8 @@ type ConfigGenerator struct {
 	Secrets                  SecretFetcher
 	AdditionalRelabelConfigs []*alloy_relabel.Config
 	ScrapeOptions            operator.ScrapeOptions
+	// Prober, when set, replaces the prober of all Probe resources.
+	Prober *promopv1.ProberSpec
 }
 
 var (
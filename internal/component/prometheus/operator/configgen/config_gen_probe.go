@@ -23,20 +23,25 @@ import (
 func (cg *ConfigGenerator) GenerateProbeConfig(m *promopv1.Probe) (cfg *config.ScrapeConfig, err error) {
 	cfg = cg.generateDefaultScrapeConfig()
 
+	prober := m.Spec.ProberSpec
+	if cg.Prober != nil {
+		prober = *cg.Prober
+	}
+
 	cfg.JobName = fmt.Sprintf("probe/%s/%s", m.Namespace, m.Name)
 	cfg.HonorTimestamps = true
-	cfg.MetricsPath = m.Spec.ProberSpec.Path
+	cfg.MetricsPath = prober.Path
 	if m.Spec.Interval != "" {
 		cfg.ScrapeInterval, _ = model.ParseDuration(string(m.Spec.Interval))
 	}
 	if m.Spec.ScrapeTimeout != "" {
 		cfg.ScrapeInterval, _ = model.ParseDuration(string(m.Spec.ScrapeTimeout))
 	}
-	if m.Spec.ProberSpec.Scheme != "" {
-		cfg.Scheme = m.Spec.ProberSpec.Scheme
+	if prober.Scheme != "" {
+		cfg.Scheme = prober.Scheme
 	}
-	if m.Spec.ProberSpec.ProxyURL != "" {
-		if u, err := url.Parse(m.Spec.ProberSpec.ProxyURL); err != nil {
+	if prober.ProxyURL != "" {
+		if u, err := url.Parse(prober.ProxyURL); err != nil {
 			return nil, fmt.Errorf("parsing ProxyURL from probe: %w", err)
 		} else {
 			cfg.HTTPClientConfig.ProxyURL = commonConfig.URL{URL: u}
@@ -90,7 +95,7 @@ func (cg *ConfigGenerator) GenerateProbeConfig(m *promopv1.Probe) (cfg *config.S
 			TargetLabel:  "instance",
 		})
 		relabels.add(&relabel.Config{
-			Replacement: m.Spec.ProberSpec.URL,
+			Replacement: prober.URL,
 			TargetLabel: "__address__",
 		})
 		// Add configured relabelings.
@@ -187,7 +192,7 @@ func (cg *ConfigGenerator) GenerateProbeConfig(m *promopv1.Probe) (cfg *config.S
 				TargetLabel:  "instance",
 			},
 			&relabel.Config{
-				Replacement: m.Spec.ProberSpec.URL,
+				Replacement: prober.URL,
 				TargetLabel: "__address__",
 			})
 		// Add configured relabelings.
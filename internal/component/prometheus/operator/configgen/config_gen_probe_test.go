@@ -29,6 +29,7 @@ func TestGenerateProbeConfig(t *testing.T) {
 	suite := []struct {
 		name                   string
 		m                      *promopv1.Probe
+		prober                 *promopv1.ProberSpec
 		ep                     promopv1.Endpoint
 		expectedRelabels       string
 		expectedMetricRelabels string
@@ -183,7 +184,74 @@ func TestGenerateProbeConfig(t *testing.T) {
 								{"__address__": "promcon.io"},
 							},
 							Labels: model.LabelSet{
-								"static": "label",
+								"static":    "label",
+								"namespace": "default",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "prober override",
+			m: &promopv1.Probe{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testprobe1",
+					Namespace: "default",
+				},
+				Spec: promopv1.ProbeSpec{
+					ProberSpec: promopv1.ProberSpec{
+						URL: "blackbox.exporter.io",
+					},
+					Module: "http_2xx",
+					Targets: promopv1.ProbeTargets{
+						StaticConfig: &promopv1.ProbeTargetStaticConfig{
+							Targets: []string{"prometheus.io"},
+						},
+					},
+				},
+			},
+			prober: &promopv1.ProberSpec{
+				URL:    "alloy.internal:12345",
+				Scheme: "http",
+				Path:   "/api/v0/component/prometheus.exporter.blackbox.default/metrics",
+			},
+			expectedRelabels: util.Untab(`
+- target_label: __meta_foo
+  replacement: bar
+- source_labels:
+  - job
+  target_label: __tmp_prometheus_job_name
+- source_labels:
+  - __address__
+  target_label: __param_target
+- source_labels:
+  - __param_target
+  target_label: instance
+- target_label: __address__
+  replacement: alloy.internal:12345
+`),
+			expected: &config.ScrapeConfig{
+				JobName:           "probe/default/testprobe1",
+				HonorTimestamps:   true,
+				ScrapeInterval:    model.Duration(time.Minute),
+				ScrapeTimeout:     model.Duration(10 * time.Second),
+				ScrapeProtocols:   config.DefaultScrapeProtocols,
+				EnableCompression: true,
+				MetricsPath:       "/api/v0/component/prometheus.exporter.blackbox.default/metrics",
+				Scheme:            "http",
+				Params:            url.Values{"module": []string{"http_2xx"}},
+				HTTPClientConfig: commonConfig.HTTPClientConfig{
+					FollowRedirects: true,
+					EnableHTTP2:     true,
+				},
+				ServiceDiscoveryConfigs: discovery.Configs{
+					discovery.StaticConfig{
+						{
+							Targets: []model.LabelSet{
+								{"__address__": "prometheus.io"},
+							},
+							Labels: model.LabelSet{
 								"namespace": "default",
 							},
 						},
@@ -199,6 +267,7 @@ func TestGenerateProbeConfig(t *testing.T) {
 				AdditionalRelabelConfigs: []*alloy_relabel.Config{
 					{TargetLabel: "__meta_foo", Replacement: "bar"},
 				},
+				Prober: tc.prober,
 			}
 			cfg, err := cg.GenerateProbeConfig(tc.m)
 			require.NoError(t, err)
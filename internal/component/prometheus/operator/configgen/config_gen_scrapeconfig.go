@@ -0,0 +1,225 @@
+package configgen
+
+import (
+	"fmt"
+	"strings"
+
+	promopv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promopv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/prometheus-operator/prometheus-operator/pkg/namespacelabeler"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
+	promdns "github.com/prometheus/prometheus/discovery/dns"
+	promfile "github.com/prometheus/prometheus/discovery/file"
+	promhttp "github.com/prometheus/prometheus/discovery/http"
+	promk8s "github.com/prometheus/prometheus/discovery/kubernetes"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+// ScrapeConfigSDConfigs holds the service discovery configurations of a
+// ScrapeConfig which the vendored version of the CRD types doesn't define.
+// They're decoded from the spec of the resource with the JSON names of newer
+// versions of the CRD.
+type ScrapeConfigSDConfigs struct {
+	KubernetesSDConfigs []KubernetesSDConfig `json:"kubernetesSDConfigs,omitempty"`
+	DNSSDConfigs        []DNSSDConfig        `json:"dnsSDConfigs,omitempty"`
+}
+
+// KubernetesSDConfig mirrors the kubernetesSDConfigs entries of a
+// ScrapeConfig. Targets are discovered with the Kubernetes client of the
+// component.
+type KubernetesSDConfig struct {
+	Role           string                   `json:"role"`
+	Namespaces     *NamespaceDiscovery      `json:"namespaces,omitempty"`
+	AttachMetadata *promopv1.AttachMetadata `json:"attachMetadata,omitempty"`
+	Selectors      []K8SSelectorConfig      `json:"selectors,omitempty"`
+}
+
+// NamespaceDiscovery mirrors the namespaces of a kubernetesSDConfigs entry.
+type NamespaceDiscovery struct {
+	IncludeOwnNamespace *bool    `json:"ownNamespace,omitempty"`
+	Names               []string `json:"names,omitempty"`
+}
+
+// K8SSelectorConfig mirrors the selectors of a kubernetesSDConfigs entry.
+type K8SSelectorConfig struct {
+	Role  string `json:"role"`
+	Label string `json:"label,omitempty"`
+	Field string `json:"field,omitempty"`
+}
+
+// DNSSDConfig mirrors the dnsSDConfigs entries of a ScrapeConfig.
+type DNSSDConfig struct {
+	Names           []string           `json:"names"`
+	RefreshInterval *promopv1.Duration `json:"refreshInterval,omitempty"`
+	Type            *string            `json:"type,omitempty"`
+	Port            *int               `json:"port,omitempty"`
+}
+
+// See https://github.com/prometheus-operator/prometheus-operator/blob/v0.66.0/pkg/prometheus/promcfg.go#L2117
+
+func (cg *ConfigGenerator) GenerateScrapeConfigConfig(m *promopv1alpha1.ScrapeConfig, sds ScrapeConfigSDConfigs) (cfg *config.ScrapeConfig, err error) {
+	cfg = cg.generateDefaultScrapeConfig()
+
+	cfg.JobName = fmt.Sprintf("scrapeConfig/%s/%s", m.Namespace, m.Name)
+	if m.Spec.MetricsPath != "" {
+		cfg.MetricsPath = m.Spec.MetricsPath
+	}
+	if m.Spec.HonorTimestamps != nil {
+		cfg.HonorTimestamps = *m.Spec.HonorTimestamps
+	}
+	if m.Spec.HonorLabels != nil {
+		cfg.HonorLabels = *m.Spec.HonorLabels
+	}
+
+	relabels := cg.initRelabelings()
+	labeler := namespacelabeler.New("", nil, false)
+	if err = relabels.addFromV1(labeler.GetRelabelingConfigs(m.TypeMeta, m.ObjectMeta, m.Spec.RelabelConfigs)...); err != nil {
+		return nil, fmt.Errorf("parsing relabel configs: %w", err)
+	}
+	cfg.RelabelConfigs = relabels.configs
+
+	if m.Spec.BasicAuth != nil {
+		cfg.HTTPClientConfig.BasicAuth, err = cg.generateBasicAuth(*m.Spec.BasicAuth, m.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if m.Spec.Authorization != nil {
+		cfg.HTTPClientConfig.Authorization, err = cg.generateAuthorization(*m.Spec.Authorization, m.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Generate static_config section.
+	if len(m.Spec.StaticConfigs) > 0 {
+		sc := discovery.StaticConfig{}
+		for i, static := range m.Spec.StaticConfigs {
+			grp := &targetgroup.Group{
+				Source: fmt.Sprintf("%d", i),
+				Labels: model.LabelSet{},
+			}
+			for k, v := range static.Labels {
+				grp.Labels[model.LabelName(k)] = model.LabelValue(v)
+			}
+			for _, t := range static.Targets {
+				grp.Targets = append(grp.Targets, model.LabelSet{
+					model.AddressLabel: model.LabelValue(t),
+				})
+			}
+			sc = append(sc, grp)
+		}
+		cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, sc)
+	}
+
+	// Generate file_sd_config section. Files are read from the filesystem of
+	// Alloy rather than the one of a Prometheus pod.
+	for _, f := range m.Spec.FileSDConfigs {
+		dConfig := promfile.DefaultSDConfig
+		for _, file := range f.Files {
+			dConfig.Files = append(dConfig.Files, string(file))
+		}
+		if f.RefreshInterval != nil {
+			if dConfig.RefreshInterval, err = model.ParseDuration(string(*f.RefreshInterval)); err != nil {
+				return nil, fmt.Errorf("parsing file_sd refresh interval: %w", err)
+			}
+		}
+		cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, &dConfig)
+	}
+
+	// Generate http_sd_config section.
+	for _, h := range m.Spec.HTTPSDConfigs {
+		dConfig := promhttp.DefaultSDConfig
+		dConfig.URL = h.URL
+		if h.RefreshInterval != nil {
+			if dConfig.RefreshInterval, err = model.ParseDuration(string(*h.RefreshInterval)); err != nil {
+				return nil, fmt.Errorf("parsing http_sd refresh interval: %w", err)
+			}
+		}
+		if h.BasicAuth != nil {
+			dConfig.HTTPClientConfig.BasicAuth, err = cg.generateBasicAuth(*h.BasicAuth, m.Namespace)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if h.Authorization != nil {
+			dConfig.HTTPClientConfig.Authorization, err = cg.generateAuthorization(*h.Authorization, m.Namespace)
+			if err != nil {
+				return nil, err
+			}
+		}
+		cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, &dConfig)
+	}
+
+	// Generate kubernetes_sd_config section.
+	for i, k := range sds.KubernetesSDConfigs {
+		role, err := k8sSDRole(k.Role)
+		if err != nil {
+			return nil, fmt.Errorf("kubernetesSDConfigs[%d]: %w", i, err)
+		}
+		dConfig := cg.generateK8SSDConfig(promopv1.NamespaceSelector{Any: true}, m.Namespace, role, k.AttachMetadata)
+		if k.Namespaces != nil {
+			dConfig.NamespaceDiscovery.Names = k.Namespaces.Names
+			if k.Namespaces.IncludeOwnNamespace != nil {
+				dConfig.NamespaceDiscovery.IncludeOwnNamespace = *k.Namespaces.IncludeOwnNamespace
+			}
+		}
+		for _, sel := range k.Selectors {
+			selRole, err := k8sSDRole(sel.Role)
+			if err != nil {
+				return nil, fmt.Errorf("kubernetesSDConfigs[%d] selector: %w", i, err)
+			}
+			dConfig.Selectors = append(dConfig.Selectors, promk8s.SelectorConfig{
+				Role:  selRole,
+				Label: sel.Label,
+				Field: sel.Field,
+			})
+		}
+		cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, dConfig)
+	}
+
+	// Generate dns_sd_config section.
+	for i, d := range sds.DNSSDConfigs {
+		dConfig := promdns.DefaultSDConfig
+		if len(d.Names) == 0 {
+			return nil, fmt.Errorf("dnsSDConfigs[%d]: at least one name must be provided", i)
+		}
+		dConfig.Names = d.Names
+		if d.RefreshInterval != nil {
+			if dConfig.RefreshInterval, err = model.ParseDuration(string(*d.RefreshInterval)); err != nil {
+				return nil, fmt.Errorf("parsing dns_sd refresh interval: %w", err)
+			}
+		}
+		if d.Type != nil {
+			dConfig.Type = strings.ToUpper(*d.Type)
+		}
+		switch dConfig.Type {
+		case "SRV":
+		case "A", "AAAA", "MX", "NS":
+			if d.Port == nil {
+				return nil, fmt.Errorf("dnsSDConfigs[%d]: a port is required for %s records", i, dConfig.Type)
+			}
+		default:
+			return nil, fmt.Errorf("dnsSDConfigs[%d]: invalid record type %q", i, dConfig.Type)
+		}
+		if d.Port != nil {
+			dConfig.Port = *d.Port
+		}
+		cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, &dConfig)
+	}
+
+	return cfg, cfg.Validate(cg.ScrapeOptions.GlobalConfig())
+}
+
+// k8sSDRole converts the role of a kubernetesSDConfigs entry, such as
+// "EndpointSlice", to its Prometheus equivalent.
+func k8sSDRole(role string) (promk8s.Role, error) {
+	switch r := promk8s.Role(strings.ToLower(role)); r {
+	case promk8s.RoleNode, promk8s.RolePod, promk8s.RoleService, promk8s.RoleEndpoint, promk8s.RoleEndpointSlice, promk8s.RoleIngress:
+		return r, nil
+	default:
+		return "", fmt.Errorf("invalid role %q", role)
+	}
+}
@@ -0,0 +1,288 @@
+package configgen
+
+import (
+	"testing"
+	"time"
+
+	promopv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promopv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	commonConfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
+	promdns "github.com/prometheus/prometheus/discovery/dns"
+	promfile "github.com/prometheus/prometheus/discovery/file"
+	promhttp "github.com/prometheus/prometheus/discovery/http"
+	promk8s "github.com/prometheus/prometheus/discovery/kubernetes"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/util"
+)
+
+func TestGenerateScrapeConfigConfig(t *testing.T) {
+	refresh := promopv1.Duration("30s")
+	honorLabels := true
+
+	suite := []struct {
+		name             string
+		m                *promopv1alpha1.ScrapeConfig
+		sds              ScrapeConfigSDConfigs
+		expectedRelabels string
+		expected         *config.ScrapeConfig
+	}{
+		{
+			name: "static targets",
+			m: &promopv1alpha1.ScrapeConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "operator",
+					Name:      "static",
+				},
+				Spec: promopv1alpha1.ScrapeConfigSpec{
+					StaticConfigs: []promopv1alpha1.StaticConfig{
+						{
+							Targets: []promopv1alpha1.Target{"10.0.0.1:9100", "10.0.0.2:9100"},
+							Labels:  map[promopv1.LabelName]string{"env": "prod"},
+						},
+					},
+					RelabelConfigs: []*promopv1.RelabelConfig{
+						{TargetLabel: "team", Replacement: "infra"},
+					},
+					MetricsPath: "/federate",
+					HonorLabels: &honorLabels,
+				},
+			},
+			expectedRelabels: util.Untab(`
+- target_label: __meta_foo
+  replacement: bar
+- source_labels: [job]
+  target_label: __tmp_prometheus_job_name
+- target_label: team
+  replacement: infra
+`),
+			expected: &config.ScrapeConfig{
+				JobName:           "scrapeConfig/operator/static",
+				HonorTimestamps:   true,
+				HonorLabels:       true,
+				ScrapeInterval:    model.Duration(time.Minute),
+				ScrapeTimeout:     model.Duration(10 * time.Second),
+				ScrapeProtocols:   config.DefaultScrapeProtocols,
+				EnableCompression: true,
+				MetricsPath:       "/federate",
+				Scheme:            "http",
+				HTTPClientConfig: commonConfig.HTTPClientConfig{
+					FollowRedirects: true,
+					EnableHTTP2:     true,
+				},
+				ServiceDiscoveryConfigs: discovery.Configs{
+					discovery.StaticConfig{
+						{
+							Source: "0",
+							Targets: []model.LabelSet{
+								{model.AddressLabel: "10.0.0.1:9100"},
+								{model.AddressLabel: "10.0.0.2:9100"},
+							},
+							Labels: model.LabelSet{"env": "prod"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "file and http discovery",
+			m: &promopv1alpha1.ScrapeConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "operator",
+					Name:      "discovery",
+				},
+				Spec: promopv1alpha1.ScrapeConfigSpec{
+					FileSDConfigs: []promopv1alpha1.FileSDConfig{
+						{Files: []promopv1alpha1.SDFile{"/etc/targets/*.json"}},
+					},
+					HTTPSDConfigs: []promopv1alpha1.HTTPSDConfig{
+						{
+							URL:             "http://sd.example.com/targets",
+							RefreshInterval: &refresh,
+							BasicAuth: &promopv1.BasicAuth{
+								Username: *s("creds", "user"),
+								Password: *s("creds", "pass"),
+							},
+						},
+					},
+				},
+			},
+			expectedRelabels: util.Untab(`
+- target_label: __meta_foo
+  replacement: bar
+- source_labels: [job]
+  target_label: __tmp_prometheus_job_name
+`),
+			expected: &config.ScrapeConfig{
+				JobName:           "scrapeConfig/operator/discovery",
+				HonorTimestamps:   true,
+				ScrapeInterval:    model.Duration(time.Minute),
+				ScrapeTimeout:     model.Duration(10 * time.Second),
+				ScrapeProtocols:   config.DefaultScrapeProtocols,
+				EnableCompression: true,
+				MetricsPath:       "/metrics",
+				Scheme:            "http",
+				HTTPClientConfig: commonConfig.HTTPClientConfig{
+					FollowRedirects: true,
+					EnableHTTP2:     true,
+				},
+				ServiceDiscoveryConfigs: discovery.Configs{
+					&promfile.SDConfig{
+						Files:           []string{"/etc/targets/*.json"},
+						RefreshInterval: model.Duration(5 * time.Minute),
+					},
+					&promhttp.SDConfig{
+						URL:             "http://sd.example.com/targets",
+						RefreshInterval: model.Duration(30 * time.Second),
+						HTTPClientConfig: commonConfig.HTTPClientConfig{
+							BasicAuth: &commonConfig.BasicAuth{
+								Username: "secret/operator/creds/user",
+								Password: "secret/operator/creds/pass",
+							},
+							FollowRedirects: true,
+							EnableHTTP2:     true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kubernetes and dns discovery",
+			m: &promopv1alpha1.ScrapeConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "operator",
+					Name:      "nodes",
+				},
+			},
+			sds: ScrapeConfigSDConfigs{
+				KubernetesSDConfigs: []KubernetesSDConfig{
+					{
+						Role:           "Node",
+						AttachMetadata: &promopv1.AttachMetadata{Node: true},
+						Selectors:      []K8SSelectorConfig{{Role: "Node", Label: "pool=infra"}},
+					},
+					{
+						Role:       "EndpointSlice",
+						Namespaces: &NamespaceDiscovery{Names: []string{"default"}},
+					},
+				},
+				DNSSDConfigs: []DNSSDConfig{
+					{Names: []string{"_metrics._tcp.example.com"}, RefreshInterval: &refresh},
+					{Names: []string{"db.example.com"}, Type: ptr.To("a"), Port: ptr.To(9104)},
+				},
+			},
+			expectedRelabels: util.Untab(`
+- target_label: __meta_foo
+  replacement: bar
+- source_labels: [job]
+  target_label: __tmp_prometheus_job_name
+`),
+			expected: &config.ScrapeConfig{
+				JobName:           "scrapeConfig/operator/nodes",
+				HonorTimestamps:   true,
+				ScrapeInterval:    model.Duration(time.Minute),
+				ScrapeTimeout:     model.Duration(10 * time.Second),
+				ScrapeProtocols:   config.DefaultScrapeProtocols,
+				EnableCompression: true,
+				MetricsPath:       "/metrics",
+				Scheme:            "http",
+				HTTPClientConfig: commonConfig.HTTPClientConfig{
+					FollowRedirects: true,
+					EnableHTTP2:     true,
+				},
+				ServiceDiscoveryConfigs: discovery.Configs{
+					&promk8s.SDConfig{
+						Role:           promk8s.RoleNode,
+						AttachMetadata: promk8s.AttachMetadataConfig{Node: true},
+						Selectors:      []promk8s.SelectorConfig{{Role: promk8s.RoleNode, Label: "pool=infra"}},
+					},
+					&promk8s.SDConfig{
+						Role:               promk8s.RoleEndpointSlice,
+						NamespaceDiscovery: promk8s.NamespaceDiscovery{Names: []string{"default"}},
+					},
+					&promdns.SDConfig{
+						Names:           []string{"_metrics._tcp.example.com"},
+						RefreshInterval: model.Duration(30 * time.Second),
+						Type:            "SRV",
+					},
+					&promdns.SDConfig{
+						Names:           []string{"db.example.com"},
+						RefreshInterval: model.Duration(30 * time.Second),
+						Type:            "A",
+						Port:            9104,
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range suite {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := &ConfigGenerator{
+				Client:  &kubernetes.ClientArguments{},
+				Secrets: &fakeSecrets{},
+				AdditionalRelabelConfigs: []*alloy_relabel.Config{
+					{TargetLabel: "__meta_foo", Replacement: "bar"},
+				},
+			}
+			cfg, err := cg.GenerateScrapeConfigConfig(tc.m, tc.sds)
+			require.NoError(t, err)
+			// check relabel configs separately
+			rlcs := cfg.RelabelConfigs
+			cfg.RelabelConfigs = nil
+
+			assert.Equal(t, tc.expected, cfg)
+
+			ex := []*relabel.Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(tc.expectedRelabels), &ex))
+			expected, err := yaml.Marshal(ex)
+			require.NoError(t, err)
+			actual, err := yaml.Marshal(rlcs)
+			require.NoError(t, err)
+			assert.YAMLEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestGenerateScrapeConfigConfigInvalidSDConfigs(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		sds         ScrapeConfigSDConfigs
+		expectedErr string
+	}{
+		{
+			name:        "invalid kubernetes role",
+			sds:         ScrapeConfigSDConfigs{KubernetesSDConfigs: []KubernetesSDConfig{{Role: "Deployment"}}},
+			expectedErr: `kubernetesSDConfigs[0]: invalid role "Deployment"`,
+		},
+		{
+			name:        "dns without names",
+			sds:         ScrapeConfigSDConfigs{DNSSDConfigs: []DNSSDConfig{{}}},
+			expectedErr: "dnsSDConfigs[0]: at least one name must be provided",
+		},
+		{
+			name:        "dns A records without port",
+			sds:         ScrapeConfigSDConfigs{DNSSDConfigs: []DNSSDConfig{{Names: []string{"db.example.com"}, Type: ptr.To("A")}}},
+			expectedErr: "dnsSDConfigs[0]: a port is required for A records",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cg := &ConfigGenerator{
+				Client:  &kubernetes.ClientArguments{},
+				Secrets: &fakeSecrets{},
+			}
+			m := &promopv1alpha1.ScrapeConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "operator", Name: "invalid"}}
+			_, err := cg.GenerateScrapeConfigConfig(m, tc.sds)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package operator
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/alloy/internal/component/common/config"
@@ -32,6 +34,49 @@ type Arguments struct {
 	RelabelConfigs []*alloy_relabel.Config `alloy:"rule,block,optional"`
 
 	Scrape ScrapeOptions `alloy:"scrape,block,optional"`
+
+	// ReportEvents enables reporting the status of discovered resources as
+	// Kubernetes events on the resources.
+	ReportEvents bool `alloy:"report_events,attr,optional"`
+
+	// Prober replaces the prober of discovered Probe resources. It's only used
+	// by prometheus.operator.probes.
+	Prober *ProberArguments `alloy:"prober,block,optional"`
+}
+
+// ProberArguments configures the prober which Probe resources are routed to.
+type ProberArguments struct {
+	// ComponentID is the ID of a prometheus.exporter.blackbox component to
+	// use as the prober.
+	ComponentID string `alloy:"component_id,attr,optional"`
+
+	// URL is the address of an external prober, in the same format as the
+	// url field of Probe resources.
+	URL    string `alloy:"url,attr,optional"`
+	Scheme string `alloy:"scheme,attr,optional"`
+	Path   string `alloy:"path,attr,optional"`
+}
+
+// DefaultProberArguments holds default values for ProberArguments.
+var DefaultProberArguments = ProberArguments{
+	Scheme: "http",
+	Path:   "/probe",
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (p *ProberArguments) SetToDefault() {
+	*p = DefaultProberArguments
+}
+
+// Validate implements syntax.Validator.
+func (p *ProberArguments) Validate() error {
+	if (p.ComponentID == "") == (p.URL == "") {
+		return fmt.Errorf("exactly one of component_id or url must be set in the prober block")
+	}
+	if p.ComponentID != "" && !strings.HasPrefix(p.ComponentID, "prometheus.exporter.blackbox.") {
+		return fmt.Errorf("component_id must reference a prometheus.exporter.blackbox component, got %q", p.ComponentID)
+	}
+	return nil
 }
 
 // ScrapeOptions holds values that configure scraping behavior.
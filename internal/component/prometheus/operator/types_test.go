@@ -27,3 +27,28 @@ func TestAlloyUnmarshal(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.NoError(t, err)
 }
+
+func TestAlloyUnmarshalProber(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+    forward_to = []
+    prober {
+        component_id = "prometheus.exporter.blackbox.default"
+    }
+`), &args)
+	require.NoError(t, err)
+	require.Equal(t, &ProberArguments{
+		ComponentID: "prometheus.exporter.blackbox.default",
+		Scheme:      "http",
+		Path:        "/probe",
+	}, args.Prober)
+
+	err = syntax.Unmarshal([]byte(`
+    forward_to = []
+    prober {
+        component_id = "prometheus.exporter.blackbox.default"
+        url          = "blackbox-exporter:9115"
+    }
+`), &args)
+	require.ErrorContains(t, err, "exactly one of component_id or url must be set in the prober block")
+}
@@ -0,0 +1,329 @@
+package receive_pushgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	fnet "github.com/grafana/alloy/internal/component/common/net"
+	alloyprom "github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.receive_pushgateway",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the
+// prometheus.receive_pushgateway component.
+type Arguments struct {
+	Server    *fnet.ServerConfig   `alloy:",squash"`
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often the pushed metrics are forwarded.
+	ForwardInterval time.Duration `alloy:"forward_interval,attr,optional"`
+
+	// How long the metrics of a group are kept after its last push.
+	MetricsTTL time.Duration `alloy:"metrics_ttl,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Server:          fnet.DefaultServerConfig(),
+		ForwardInterval: time.Minute,
+		MetricsTTL:      10 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.ForwardInterval <= 0 {
+		return fmt.Errorf("forward_interval must be greater than 0")
+	}
+	if args.MetricsTTL < 0 {
+		return fmt.Errorf("metrics_ttl must not be negative")
+	}
+	return nil
+}
+
+// Component implements the prometheus.receive_pushgateway component.
+type Component struct {
+	opts               component.Options
+	fanout             *alloyprom.Fanout
+	store              *store
+	groups             prometheus.GaugeFunc
+	uncheckedCollector *util.UncheckedCollector
+	updated            chan struct{}
+
+	// forwardMut serializes forward, so that the samples are appended in the
+	// order of their timestamps.
+	forwardMut sync.Mutex
+
+	updateMut sync.RWMutex
+	args      Arguments
+	server    *fnet.TargetServer
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new prometheus.receive_pushgateway component.
+func New(opts component.Options, args Arguments) (*Component, error) {
+	service, err := opts.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	ls := service.(labelstore.LabelStore)
+
+	uncheckedCollector := util.NewUncheckedCollector(nil)
+	opts.Registerer.MustRegister(uncheckedCollector)
+
+	c := &Component{
+		opts:               opts,
+		fanout:             alloyprom.NewFanout(args.ForwardTo, opts.ID, opts.Registerer, ls),
+		store:              newStore(),
+		uncheckedCollector: uncheckedCollector,
+		updated:            make(chan struct{}, 1),
+	}
+	c.groups = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "prometheus_receive_pushgateway_groups",
+		Help: "Number of groups of pushed metrics held by the component.",
+	}, func() float64 { return float64(c.store.len()) })
+	opts.Registerer.MustRegister(c.groups)
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component. It forwards the pushed metrics every
+// forward_interval, and expires the groups which weren't pushed to for longer
+// than metrics_ttl.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.updateMut.Lock()
+		defer c.updateMut.Unlock()
+		c.shutdownServer()
+	}()
+
+	c.updateMut.RLock()
+	ticker := time.NewTicker(c.args.ForwardInterval)
+	c.updateMut.RUnlock()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			level.Info(c.opts.Logger).Log("msg", "terminating due to context done")
+			return nil
+		case <-c.updated:
+			c.updateMut.RLock()
+			ticker.Reset(c.args.ForwardInterval)
+			c.updateMut.RUnlock()
+		case <-ticker.C:
+			c.updateMut.RLock()
+			ttl := c.args.MetricsTTL
+			c.updateMut.RUnlock()
+
+			err := c.forward(ctx, func(now time.Time) ([]sample, []labels.Labels) {
+				expired := c.store.expire(now, ttl)
+				return c.store.series(), expired
+			})
+			if err != nil {
+				level.Warn(c.opts.Logger).Log("msg", "failed to forward pushed metrics", "err", err)
+			}
+		}
+	}
+}
+
+// forward updates the store with update, and appends the samples it returns
+// and stale markers for the series it removed, with the time passed to
+// update. Calls are serialized, and the time is taken once the previous call
+// returned, so that a snapshot of the store is never appended after a newer
+// one.
+func (c *Component) forward(ctx context.Context, update func(now time.Time) ([]sample, []labels.Labels)) error {
+	c.forwardMut.Lock()
+	defer c.forwardMut.Unlock()
+
+	now := time.Now()
+	samples, removed := update(now)
+	if len(samples) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	ts := timestamp.FromTime(now)
+	app := c.fanout.Appender(ctx)
+	for _, s := range samples {
+		if _, err := app.Append(0, s.labels, ts, s.value); err != nil {
+			_ = app.Rollback()
+			return err
+		}
+	}
+	for _, l := range removed {
+		if _, err := app.Append(0, l, ts, value.StaleNaN); err != nil {
+			_ = app.Rollback()
+			return err
+		}
+	}
+	return app.Commit()
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+
+	c.updateMut.Lock()
+	defer c.updateMut.Unlock()
+
+	if c.args.ForwardInterval != newArgs.ForwardInterval {
+		select {
+		case c.updated <- struct{}{}:
+		default:
+		}
+	}
+
+	serverNeedsUpdate := !reflect.DeepEqual(c.args.Server, newArgs.Server)
+	if !serverNeedsUpdate {
+		c.args = newArgs
+		return nil
+	}
+	c.shutdownServer()
+
+	s, err := c.createNewServer(newArgs)
+	if err != nil {
+		return err
+	}
+	c.server = s
+
+	err = c.server.MountAndRun(func(router *mux.Router) {
+		router.PathPrefix("/metrics/").Methods(http.MethodPut, http.MethodPost, http.MethodDelete).Handler(c.pushHandler())
+	})
+	if err != nil {
+		return err
+	}
+
+	c.args = newArgs
+	return nil
+}
+
+// pushHandler implements the push API of the Pushgateway. PUT replaces all
+// the metrics of a group, POST only replaces the metrics with the same names
+// as the pushed ones, and DELETE removes the group.
+func (c *Component) pushHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groupingKey, ok := strings.CutPrefix(r.URL.Path, "/metrics/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		groupLabels, err := parseGroupingKey(groupingKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			err := c.forward(r.Context(), func(time.Time) ([]sample, []labels.Labels) {
+				return nil, c.store.delete(groupLabels)
+			})
+			if err != nil {
+				level.Warn(c.opts.Logger).Log("msg", "failed to forward stale markers of deleted group", "group", groupLabels, "err", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		families, err := decodeFamilies(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The pushed metrics are forwarded right away, so that the metrics of
+		// short-lived jobs are sent even when they're deleted before the next
+		// forward_interval.
+		err = c.forward(r.Context(), func(now time.Time) ([]sample, []labels.Labels) {
+			return c.store.push(groupLabels, families, r.Method == http.MethodPut, now)
+		})
+		if err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to forward pushed metrics", "group", groupLabels, "err", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// decodeFamilies decodes the metric families of a push request, in the text
+// or protobuf format depending on its Content-Type header.
+func decodeFamilies(r *http.Request) ([]*dto.MetricFamily, error) {
+	dec := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+
+	var families []*dto.MetricFamily
+	for {
+		var mf dto.MetricFamily
+		err := dec.Decode(&mf)
+		if errors.Is(err, io.EOF) {
+			return families, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode pushed metrics: %w", err)
+		}
+		families = append(families, &mf)
+	}
+}
+
+func (c *Component) createNewServer(args Arguments) (*fnet.TargetServer, error) {
+	// [server.Server] registers new metrics every time it is created. To
+	// avoid issues with re-registering metrics with the same name, we create a
+	// new registry for the server every time we create one, and pass it to an
+	// unchecked collector to bypass uniqueness checking.
+	serverRegistry := prometheus.NewRegistry()
+	c.uncheckedCollector.SetCollector(serverRegistry)
+
+	s, err := fnet.NewTargetServer(
+		c.opts.Logger,
+		"prometheus_receive_pushgateway",
+		serverRegistry,
+		args.Server,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server: %v", err)
+	}
+	return s, nil
+}
+
+// shutdownServer will shut down the currently used server.
+// It is not goroutine-safe and an updateMut write lock must be held when it's called.
+func (c *Component) shutdownServer() {
+	if c.server != nil {
+		c.server.StopAndShutdown()
+		c.server = nil
+	}
+}
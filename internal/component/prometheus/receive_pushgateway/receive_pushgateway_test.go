@@ -0,0 +1,277 @@
+package receive_pushgateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	alloyprom "github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestParseGroupingKey(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected labels.Labels
+		err      string
+	}{
+		{path: "job/batch", expected: labels.FromStrings("job", "batch")},
+		{path: "job/batch/instance/a", expected: labels.FromStrings("job", "batch", "instance", "a")},
+		{path: "job/batch/path@base64/L3Zhci90bXA", expected: labels.FromStrings("job", "batch", "path", "/var/tmp")},
+		{path: "job/batch/empty@base64/=", expected: labels.FromStrings("job", "batch", "empty", "")},
+		{path: "job", err: "must contain pairs"},
+		{path: "instance/a", err: "must start with the job label"},
+		{path: "job/batch/instance/a/instance/b", err: `duplicate label "instance"`},
+		{path: "job/batch/__name__/a", err: `invalid label name "__name__"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			actual, err := parseGroupingKey(tc.path)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	parse := func(text string) []*dto.MetricFamily {
+		families, err := decodeFamilies(mustRequest(t, text))
+		require.NoError(t, err)
+		return families
+	}
+	names := func(samples []sample) []string {
+		var res []string
+		for _, s := range samples {
+			res = append(res, s.labels.String())
+		}
+		return res
+	}
+
+	var (
+		s     = newStore()
+		group = labels.FromStrings("job", "batch")
+		now   = time.Now()
+	)
+
+	current, removed := s.push(group, parse("a 1\nb 2\n"), true, now)
+	require.ElementsMatch(t, []string{
+		`{__name__="a", job="batch"}`,
+		`{__name__="b", job="batch"}`,
+		`{__name__="push_time_seconds", job="batch"}`,
+	}, names(current))
+	require.Empty(t, removed)
+
+	// POST only replaces the pushed families.
+	current, removed = s.push(group, parse("a 3\n"), false, now)
+	require.Len(t, current, 3)
+	require.Empty(t, removed)
+
+	// PUT replaces the whole group.
+	current, removed = s.push(group, parse("a 4\n"), true, now)
+	require.Len(t, current, 2)
+	require.Equal(t, []labels.Labels{labels.FromStrings("__name__", "b", "job", "batch")}, removed)
+
+	require.Empty(t, s.expire(now.Add(time.Minute), 2*time.Minute))
+	require.Len(t, s.expire(now.Add(3*time.Minute), 2*time.Minute), 2)
+	require.Equal(t, 0, s.len())
+}
+
+func TestFamilySamples(t *testing.T) {
+	families, err := decodeFamilies(mustRequest(t, `
+# TYPE job_duration_seconds histogram
+job_duration_seconds_bucket{le="1"} 2
+job_duration_seconds_bucket{le="+Inf"} 3
+job_duration_seconds_sum 4.5
+job_duration_seconds_count 3
+# TYPE last_success gauge
+last_success{job="ignored"} 1700000000
+`))
+	require.NoError(t, err)
+
+	actual := map[string]float64{}
+	for _, mf := range families {
+		for _, s := range familySamples(mf, labels.FromStrings("job", "batch")) {
+			actual[s.labels.String()] = s.value
+		}
+	}
+	require.Equal(t, map[string]float64{
+		`{__name__="job_duration_seconds_bucket", job="batch", le="1"}`:    2,
+		`{__name__="job_duration_seconds_bucket", job="batch", le="+Inf"}`: 3,
+		`{__name__="job_duration_seconds_sum", job="batch"}`:               4.5,
+		`{__name__="job_duration_seconds_count", job="batch"}`:             3,
+		`{__name__="last_success", job="batch"}`:                           1700000000,
+	}, actual)
+}
+
+func TestPush(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(fmt.Sprintf(`
+		http {
+			listen_address = "127.0.0.1"
+			listen_port    = %d
+		}
+		forward_to = []
+	`, port)), &args))
+
+	samples := make(chan testSample, 100)
+	args.ForwardTo = testAppendable(samples)
+
+	c, err := New(testOptions(t), args)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		require.NoError(t, c.Run(ctx))
+	}()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/metrics/job/batch/instance/a", port)
+	send := func(method, body string) int {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	util.Eventually(t, func(t require.TestingT) {
+		req, err := http.NewRequest(http.MethodPut, url, strings.NewReader("processed_records 42\n"))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	received := receive(t, samples, 2)
+	require.Equal(t, float64(42), received[`{__name__="processed_records", instance="a", job="batch"}`])
+	require.Contains(t, received, `{__name__="push_time_seconds", instance="a", job="batch"}`)
+
+	require.Equal(t, http.StatusAccepted, send(http.MethodDelete, ""))
+	received = receive(t, samples, 2)
+	require.True(t, value.IsStaleNaN(received[`{__name__="processed_records", instance="a", job="batch"}`]))
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPut, "not a metric"))
+}
+
+func TestForward_Ordered(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(fmt.Sprintf(`
+		http {
+			listen_address = "127.0.0.1"
+			listen_port    = %d
+		}
+		forward_to       = []
+		forward_interval = "1ms"
+	`, port)), &args))
+
+	var (
+		mut        sync.Mutex
+		lastTs     = map[string]int64{}
+		outOfOrder []string
+	)
+	hookFn := func(ref storage.SeriesRef, l labels.Labels, ts int64, _ float64, _ storage.Appender) (storage.SeriesRef, error) {
+		mut.Lock()
+		defer mut.Unlock()
+		if ts < lastTs[l.String()] {
+			outOfOrder = append(outOfOrder, l.String())
+		}
+		lastTs[l.String()] = ts
+		return ref, nil
+	}
+	ls := labelstore.New(nil, prometheus.DefaultRegisterer)
+	args.ForwardTo = []storage.Appendable{alloyprom.NewInterceptor(nil, ls, alloyprom.WithAppendHook(hookFn))}
+
+	c, err := New(testOptions(t), args)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		require.NoError(t, c.Run(ctx))
+	}()
+
+	// The pushes race with the periodic forwards of the pushed metrics.
+	handler := c.pushHandler()
+	for i := 0; i < 200; i++ {
+		req := httptest.NewRequest(http.MethodPut, "/metrics/job/batch", strings.NewReader(fmt.Sprintf("processed_records %d\n", i)))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Empty(t, outOfOrder)
+}
+
+type testSample struct {
+	l   labels.Labels
+	val float64
+}
+
+// receive returns the next n samples, keyed by their labels.
+func receive(t *testing.T, samples chan testSample, n int) map[string]float64 {
+	res := map[string]float64{}
+	for i := 0; i < n; i++ {
+		select {
+		case s := <-samples:
+			res[s.l.String()] = s.val
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for samples")
+		}
+	}
+	return res
+}
+
+func mustRequest(t *testing.T, body string) *http.Request {
+	req, err := http.NewRequest(http.MethodPut, "/", strings.NewReader(strings.TrimPrefix(body, "\n")))
+	require.NoError(t, err)
+	return req
+}
+
+func testAppendable(samples chan testSample) []storage.Appendable {
+	hookFn := func(ref storage.SeriesRef, l labels.Labels, _ int64, val float64, _ storage.Appender) (storage.SeriesRef, error) {
+		samples <- testSample{l: l, val: val}
+		return ref, nil
+	}
+
+	ls := labelstore.New(nil, prometheus.DefaultRegisterer)
+	return []storage.Appendable{alloyprom.NewInterceptor(nil, ls, alloyprom.WithAppendHook(hookFn))}
+}
+
+func testOptions(t *testing.T) component.Options {
+	return component.Options{
+		ID:         "prometheus.receive_pushgateway.test",
+		Logger:     util.TestAlloyLogger(t),
+		Registerer: prometheus.NewRegistry(),
+		GetServiceData: func(name string) (interface{}, error) {
+			return labelstore.New(nil, prometheus.DefaultRegisterer), nil
+		},
+	}
+}
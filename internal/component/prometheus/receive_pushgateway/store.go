@@ -0,0 +1,262 @@
+package receive_pushgateway
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// pushTimeMetric is the name of the metric holding the time of the last push
+// of a group, like the one of the Pushgateway.
+const pushTimeMetric = "push_time_seconds"
+
+// sample is a sample of a pushed series, without timestamp: pushed samples
+// are forwarded with the time they're sent at.
+type sample struct {
+	labels labels.Labels
+	value  float64
+}
+
+// group holds the metrics pushed with the same grouping key.
+type group struct {
+	labels labels.Labels
+	// samples are keyed by the name of the metric family they belong to.
+	samples  map[string][]sample
+	lastPush time.Time
+}
+
+// series returns the samples of all the series of the group, including the
+// push time.
+func (g *group) series() []sample {
+	res := []sample{{
+		labels: labels.NewBuilder(g.labels).Set(model.MetricNameLabel, pushTimeMetric).Labels(),
+		value:  float64(g.lastPush.UnixNano()) / 1e9,
+	}}
+	for _, samples := range g.samples {
+		res = append(res, samples...)
+	}
+	return res
+}
+
+// store holds the groups of pushed metrics.
+type store struct {
+	mut    sync.Mutex
+	groups map[string]*group // Keyed by the string of the grouping labels.
+}
+
+func newStore() *store {
+	return &store{groups: map[string]*group{}}
+}
+
+// push stores families in the group of groupLabels. When replace is true, the
+// metrics previously pushed to the group are all replaced, otherwise only the
+// families with the same names are. It returns the series of the group and
+// the series which were removed from it.
+func (s *store) push(groupLabels labels.Labels, families []*dto.MetricFamily, replace bool, now time.Time) (current []sample, removed []labels.Labels) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	g, ok := s.groups[groupLabels.String()]
+	if !ok {
+		g = &group{labels: groupLabels, samples: map[string][]sample{}}
+		s.groups[groupLabels.String()] = g
+	}
+	previous := g.series()
+
+	if replace {
+		g.samples = map[string][]sample{}
+	}
+	for _, mf := range families {
+		g.samples[mf.GetName()] = familySamples(mf, groupLabels)
+	}
+	g.lastPush = now
+
+	current = g.series()
+	return current, removedSeries(previous, current)
+}
+
+// delete removes the group of groupLabels, and returns its series.
+func (s *store) delete(groupLabels labels.Labels) []labels.Labels {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	g, ok := s.groups[groupLabels.String()]
+	if !ok {
+		return nil
+	}
+	delete(s.groups, groupLabels.String())
+	return removedSeries(g.series(), nil)
+}
+
+// expire removes the groups which weren't pushed to since ttl, and returns
+// their series. Groups never expire when ttl is 0.
+func (s *store) expire(now time.Time, ttl time.Duration) []labels.Labels {
+	if ttl <= 0 {
+		return nil
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var removed []labels.Labels
+	for key, g := range s.groups {
+		if now.Sub(g.lastPush) < ttl {
+			continue
+		}
+		delete(s.groups, key)
+		removed = append(removed, removedSeries(g.series(), nil)...)
+	}
+	return removed
+}
+
+// series returns the series of all the groups.
+func (s *store) series() []sample {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var res []sample
+	for _, g := range s.groups {
+		res = append(res, g.series()...)
+	}
+	return res
+}
+
+// len returns the number of groups.
+func (s *store) len() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return len(s.groups)
+}
+
+// removedSeries returns the labels of the series of previous which aren't in
+// current.
+func removedSeries(previous, current []sample) []labels.Labels {
+	kept := make(map[uint64]struct{}, len(current))
+	for _, s := range current {
+		kept[s.labels.Hash()] = struct{}{}
+	}
+
+	var removed []labels.Labels
+	for _, s := range previous {
+		if _, ok := kept[s.labels.Hash()]; !ok {
+			removed = append(removed, s.labels)
+		}
+	}
+	return removed
+}
+
+// familySamples converts the metrics of mf to samples. The grouping labels
+// override the labels of the metrics with the same names.
+func familySamples(mf *dto.MetricFamily, groupLabels labels.Labels) []sample {
+	var res []sample
+	for _, m := range mf.GetMetric() {
+		lb := labels.NewScratchBuilder(len(m.GetLabel()))
+		for _, l := range m.GetLabel() {
+			lb.Add(l.GetName(), l.GetValue())
+		}
+		lb.Sort()
+		base := labels.NewBuilder(lb.Labels())
+		groupLabels.Range(func(l labels.Label) {
+			base.Set(l.Name, l.Value)
+		})
+
+		add := func(name string, value float64, extra ...string) {
+			b := labels.NewBuilder(base.Labels()).Set(model.MetricNameLabel, name)
+			for i := 0; i+1 < len(extra); i += 2 {
+				b.Set(extra[i], extra[i+1])
+			}
+			res = append(res, sample{labels: b.Labels(), value: value})
+		}
+
+		name := mf.GetName()
+		switch mf.GetType() {
+		case dto.MetricType_COUNTER:
+			add(name, m.GetCounter().GetValue())
+		case dto.MetricType_GAUGE:
+			add(name, m.GetGauge().GetValue())
+		case dto.MetricType_UNTYPED:
+			add(name, m.GetUntyped().GetValue())
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			for _, q := range s.GetQuantile() {
+				add(name, q.GetValue(), model.QuantileLabel, formatFloat(q.GetQuantile()))
+			}
+			add(name+"_sum", s.GetSampleSum())
+			add(name+"_count", float64(s.GetSampleCount()))
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			h := m.GetHistogram()
+			var hasInf bool
+			for _, b := range h.GetBucket() {
+				if math.IsInf(b.GetUpperBound(), 1) {
+					hasInf = true
+				}
+				add(name+"_bucket", float64(b.GetCumulativeCount()), model.BucketLabel, formatFloat(b.GetUpperBound()))
+			}
+			if !hasInf && len(h.GetBucket()) > 0 {
+				add(name+"_bucket", float64(h.GetSampleCount()), model.BucketLabel, "+Inf")
+			}
+			add(name+"_sum", h.GetSampleSum())
+			add(name+"_count", float64(h.GetSampleCount()))
+		}
+	}
+	return res
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// parseGroupingKey parses the grouping key of a Pushgateway URL path, like
+// job/<JOB>/<LABEL>/<VALUE>. Values are base64 encoded when the name of their
+// label has the @base64 suffix, so that they can contain slashes or be empty.
+func parseGroupingKey(path string) (labels.Labels, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts)%2 != 0 {
+		return labels.EmptyLabels(), fmt.Errorf("grouping key %q must contain pairs of label names and values", path)
+	}
+
+	lb := labels.NewScratchBuilder(len(parts) / 2)
+	seen := make(map[string]struct{}, len(parts)/2)
+	for i := 0; i < len(parts); i += 2 {
+		name, value := parts[i], parts[i+1]
+		if trimmed, ok := strings.CutSuffix(name, "@base64"); ok {
+			name = trimmed
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+			if err != nil {
+				return labels.EmptyLabels(), fmt.Errorf("invalid base64 value of label %q: %w", name, err)
+			}
+			value = string(decoded)
+		}
+
+		switch {
+		case i == 0 && name != "job":
+			return labels.EmptyLabels(), fmt.Errorf("grouping key must start with the job label")
+		case i == 0 && value == "":
+			return labels.EmptyLabels(), fmt.Errorf("job label must not be empty")
+		case !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix):
+			return labels.EmptyLabels(), fmt.Errorf("invalid label name %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			return labels.EmptyLabels(), fmt.Errorf("duplicate label %q", name)
+		}
+		seen[name] = struct{}{}
+		lb.Add(name, value)
+	}
+	lb.Sort()
+	return lb.Labels(), nil
+}
@@ -49,6 +49,9 @@ type Arguments struct {
 
 	// Cache size to use for LRU cache.
 	CacheSize int `alloy:"max_cache_size,attr,optional"`
+
+	// Maximum number of exemplars forwarded per second. 0 means no limit.
+	MaxExemplarsPerSecond float64 `alloy:"max_exemplars_per_second,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -63,6 +66,9 @@ func (arg *Arguments) Validate() error {
 	if arg.CacheSize <= 0 {
 		return fmt.Errorf("max_cache_size must be greater than 0 and is %d", arg.CacheSize)
 	}
+	if arg.MaxExemplarsPerSecond < 0 {
+		return fmt.Errorf("max_exemplars_per_second must not be negative and is %v", arg.MaxExemplarsPerSecond)
+	}
 	return nil
 }
 
@@ -92,11 +98,16 @@ type Component struct {
 
 	cacheMut sync.RWMutex
 	cache    *lru.Cache[uint64, *labelAndID]
+
+	// inputs samples the label sets which missed the cache, so that the
+	// shadow service can evaluate candidate rules against them.
+	inputs *alloy_relabel.Sampler
 }
 
 var (
-	_ component.Component     = (*Component)(nil)
-	_ component.LiveDebugging = (*Component)(nil)
+	_ component.Component        = (*Component)(nil)
+	_ component.LiveDebugging    = (*Component)(nil)
+	_ alloy_relabel.InputSampler = (*Component)(nil)
 )
 
 // New creates a new prometheus.relabel component.
@@ -118,6 +129,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:               o,
 		cache:              cache,
+		inputs:             alloy_relabel.NewSampler(alloy_relabel.DefaultSamplerSize),
 		ls:                 data.(labelstore.LabelStore),
 		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
 	}
@@ -233,6 +245,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.clearCache(newArgs.CacheSize)
 	c.mrc = alloy_relabel.ComponentToPromRelabelConfigs(newArgs.MetricRelabelConfigs)
 	c.fanout.UpdateChildren(newArgs.ForwardTo)
+	c.fanout.SetExemplarLimit(newArgs.MaxExemplarsPerSecond)
 
 	c.opts.OnStateChange(Exports{Receiver: c.receiver, Rules: newArgs.MetricRelabelConfigs})
 
@@ -260,6 +273,7 @@ func (c *Component) relabel(val float64, lbls labels.Labels) labels.Labels {
 		// slice.
 		relabelled, keep = relabel.Process(lbls.Copy(), c.mrc...)
 		c.cacheMisses.Inc()
+		c.inputs.Add(lbls)
 		c.addToCache(globalRef, relabelled, keep)
 	}
 
@@ -320,6 +334,11 @@ func (c *Component) addToCache(originalID uint64, lbls labels.Labels, keep bool)
 
 func (c *Component) LiveDebugging(_ int) {}
 
+// SampledInputs implements alloy_relabel.InputSampler.
+func (c *Component) SampledInputs() []labels.Labels {
+	return c.inputs.Samples()
+}
+
 // labelAndID stores both the globalrefid for the label and the id itself. We store the id so that it doesn't have
 // to be recalculated again.
 type labelAndID struct {
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
 	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/model/value"
@@ -60,6 +61,10 @@ func TestValidator(t *testing.T) {
 	args.CacheSize = 1
 	err = args.Validate()
 	require.NoError(t, err)
+
+	args.MaxExemplarsPerSecond = -1
+	err = args.Validate()
+	require.Error(t, err)
 }
 
 func TestNil(t *testing.T) {
@@ -111,6 +116,58 @@ func TestLRUNaN(t *testing.T) {
 	require.True(t, relabeller.cache.Len() == 0)
 }
 
+func TestExemplars(t *testing.T) {
+	ls := labelstore.New(nil, prom.DefaultRegisterer)
+	var forwarded []labels.Labels
+	fanout := prometheus.NewInterceptor(nil, ls, prometheus.WithExemplarHook(func(ref storage.SeriesRef, l labels.Labels, _ exemplar.Exemplar, _ storage.Appender) (storage.SeriesRef, error) {
+		forwarded = append(forwarded, l)
+		return ref, nil
+	}))
+	var entry storage.Appendable
+	args := Arguments{
+		ForwardTo: []storage.Appendable{fanout},
+		MetricRelabelConfigs: []*alloy_relabel.Config{
+			{
+				SourceLabels: []string{"__address__"},
+				Regex:        alloy_relabel.Regexp(relabel.MustNewRegexp("(.+)")),
+				TargetLabel:  "new_label",
+				Replacement:  "new_value",
+				Action:       "replace",
+			},
+		},
+		CacheSize: 100_000,
+	}
+	relabeller, err := New(component.Options{
+		ID:     "1",
+		Logger: util.TestAlloyLogger(t),
+		OnStateChange: func(e component.Exports) {
+			entry = e.(Exports).Receiver
+		},
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, args)
+	require.NoError(t, err)
+
+	appendExemplars := func(n int) {
+		app := entry.Appender(context.Background())
+		for i := 0; i < n; i++ {
+			_, err := app.AppendExemplar(0, labels.FromStrings("__address__", "localhost"), exemplar.Exemplar{Value: 1})
+			require.NoError(t, err)
+		}
+		require.NoError(t, app.Commit())
+	}
+
+	appendExemplars(5)
+	require.Len(t, forwarded, 5)
+	require.Equal(t, "new_value", forwarded[0].Get("new_label"))
+
+	forwarded = nil
+	args.MaxExemplarsPerSecond = 0.001
+	require.NoError(t, relabeller.Update(args))
+	appendExemplars(5)
+	require.Len(t, forwarded, 1)
+}
+
 func BenchmarkCache(b *testing.B) {
 	ls := labelstore.New(nil, prom.DefaultRegisterer)
 	fanout := prometheus.NewInterceptor(nil, ls, prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, _ float64, _ storage.Appender) (storage.SeriesRef, error) {
@@ -0,0 +1,92 @@
+package remotewrite
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/golang/snappy"
+	"github.com/grafana/alloy/internal/component"
+	types "github.com/grafana/alloy/internal/component/common/config"
+	common "github.com/prometheus/common/config"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage/remote"
+)
+
+var _ component.ConnectivityChecker = Arguments{}
+
+// CheckConnectivity implements component.ConnectivityChecker by sending a
+// remote write request without samples to every endpoint.
+func (rc Arguments) CheckConnectivity(ctx context.Context) []component.ConnectivityResult {
+	var res []component.ConnectivityResult
+	for _, ep := range rc.Endpoints {
+		res = append(res, component.ConnectivityResult{
+			Endpoint: redactURL(ep.URL),
+			Err:      checkEndpoint(ctx, ep),
+		})
+	}
+	return res
+}
+
+func checkEndpoint(ctx context.Context, ep *EndpointOptions) error {
+	cfg, err := convertConfigs(Arguments{Endpoints: []*EndpointOptions{ep}})
+	if err != nil {
+		return err
+	}
+	rw := cfg.RemoteWriteConfigs[0]
+
+	// The component refreshes the access tokens of google_iam in a file read
+	// by the client, a single token is enough for one request.
+	if ep.GoogleIAM != nil {
+		token, err := googleToken(ctx, *ep.GoogleIAM)
+		if err != nil {
+			return err
+		}
+		rw.HTTPClientConfig.Authorization = &common.Authorization{
+			Type:        "Bearer",
+			Credentials: common.Secret(token),
+		}
+	}
+
+	client, err := remote.NewWriteClient("check-connectivity", &remote.ClientConfig{
+		URL:              rw.URL,
+		Timeout:          rw.RemoteTimeout,
+		HTTPClientConfig: rw.HTTPClientConfig,
+		SigV4Config:      rw.SigV4Config,
+		AzureADConfig:    rw.AzureADConfig,
+		Headers:          rw.Headers,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := (&prompb.WriteRequest{}).Marshal()
+	if err != nil {
+		return err
+	}
+	return client.Store(ctx, snappy.Encode(nil, req), 0)
+}
+
+func googleToken(ctx context.Context, cfg types.GoogleIAMConfig) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, googleTokenTimeout)
+	defer cancel()
+
+	creds, err := cfg.Credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	tok, err := creds.TokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Google access token: %w", err)
+	}
+	return tok.AccessToken, nil
+}
+
+// redactURL returns rawURL with its password, if any, redacted.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
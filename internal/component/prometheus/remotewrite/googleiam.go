@@ -0,0 +1,231 @@
+package remotewrite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	types "github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	// googleTokenEarlyRefresh is how long before it expires an access token is
+	// replaced, so that requests never carry an expired token.
+	googleTokenEarlyRefresh = 5 * time.Minute
+	// googleTokenMinRefresh is the minimum interval between two refreshes of
+	// an access token.
+	googleTokenMinRefresh = 30 * time.Second
+	// googleTokenMaxRefresh is the refresh interval of access tokens which
+	// don't expire.
+	googleTokenMaxRefresh = 30 * time.Minute
+	// googleTokenRetryInterval is how long to wait before retrying a failed
+	// refresh.
+	googleTokenRetryInterval = 10 * time.Second
+	// googleTokenTimeout is the timeout of fetching an access token.
+	googleTokenTimeout = 10 * time.Second
+)
+
+// googleTokens keeps the access tokens of the endpoints authenticating with
+// google_iam up to date in files of the data directory of the component.
+//
+// The remote write clients of Prometheus can't be given a custom transport,
+// but they read the bearer token of an endpoint from its credentials file on
+// every request, so endpoints are configured to read it from these files.
+//
+// Tokens are fetched in the background, so that evaluating the component never
+// waits for the token endpoint. Requests sent before the first token is
+// written fail to read the token file, and are retried like other network
+// errors.
+type googleTokens struct {
+	log log.Logger
+	dir string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mut        sync.Mutex
+	refreshers map[string]*googleTokenRefresher // Keyed by token file.
+}
+
+func newGoogleTokens(logger log.Logger, dir string) *googleTokens {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &googleTokens{
+		log:        logger,
+		dir:        dir,
+		ctx:        ctx,
+		cancel:     cancel,
+		refreshers: make(map[string]*googleTokenRefresher),
+	}
+}
+
+// tokenFile returns the file holding the access token of cfg. Endpoints with
+// the same credentials and scopes share their token.
+func (g *googleTokens) tokenFile(cfg types.GoogleIAMConfig) string {
+	h := sha256.Sum256([]byte(cfg.CredentialsFile + "\x00" + strings.Join(cfg.Scopes, " ")))
+	return filepath.Join(g.dir, hex.EncodeToString(h[:8])+".token")
+}
+
+// Update starts refreshing the tokens of cfgs, keyed by their token file, in
+// the background, and stops refreshing the other ones. Failures to refresh a
+// token are reported by Health.
+func (g *googleTokens) Update(cfgs map[string]types.GoogleIAMConfig) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	for path, r := range g.refreshers {
+		if _, ok := cfgs[path]; ok {
+			continue
+		}
+		r.stop()
+		delete(g.refreshers, path)
+		_ = os.Remove(path)
+	}
+
+	for path, cfg := range cfgs {
+		if _, ok := g.refreshers[path]; ok {
+			continue
+		}
+		if err := os.MkdirAll(g.dir, 0700); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(g.ctx)
+		r := &googleTokenRefresher{
+			log:    g.log,
+			cfg:    cfg,
+			path:   path,
+			cancel: cancel,
+			done:   make(chan struct{}),
+		}
+		go r.run(ctx)
+		g.refreshers[path] = r
+	}
+	return nil
+}
+
+// Health returns the health of refreshing the tokens, which is unhealthy
+// while the last refresh of a token failed.
+func (g *googleTokens) Health() component.Health {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	health := component.Health{Health: component.HealthTypeHealthy}
+	for _, r := range g.refreshers {
+		updated, err := r.lastError()
+		if err == nil {
+			continue
+		}
+		health = component.LeastHealthy(health, component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    fmt.Sprintf("failed to refresh Google access token: %s", err),
+			UpdateTime: updated,
+		})
+	}
+	return health
+}
+
+// Close stops refreshing all tokens and removes their files.
+func (g *googleTokens) Close() {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	g.cancel()
+	for path, r := range g.refreshers {
+		<-r.done
+		_ = os.Remove(path)
+		delete(g.refreshers, path)
+	}
+}
+
+// googleTokenRefresher refreshes a single access token.
+type googleTokenRefresher struct {
+	log  log.Logger
+	cfg  types.GoogleIAMConfig
+	path string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mut       sync.Mutex
+	err       error     // Error of the last refresh.
+	refreshed time.Time // Time of the last refresh.
+}
+
+// run refreshes the token until ctx is canceled, starting right away.
+func (r *googleTokenRefresher) run(ctx context.Context) {
+	defer close(r.done)
+
+	var next time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next):
+		}
+
+		var err error
+		next, err = r.refresh(ctx)
+		if err != nil {
+			level.Warn(r.log).Log("msg", "failed to refresh Google access token", "err", err)
+			next = googleTokenRetryInterval
+		}
+
+		r.mut.Lock()
+		r.err, r.refreshed = err, time.Now()
+		r.mut.Unlock()
+	}
+}
+
+// lastError returns when the last refresh happened, and its error.
+func (r *googleTokenRefresher) lastError() (time.Time, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.refreshed, r.err
+}
+
+func (r *googleTokenRefresher) stop() {
+	r.cancel()
+	<-r.done
+}
+
+// refresh fetches a new access token and writes it to the token file, then
+// returns how long to wait before the next refresh.
+func (r *googleTokenRefresher) refresh(ctx context.Context) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, googleTokenTimeout)
+	defer cancel()
+
+	creds, err := r.cfg.Credentials(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tok, err := creds.TokenSource.Token()
+	if err != nil {
+		return 0, err
+	}
+	if err := writeTokenFile(r.path, tok.AccessToken); err != nil {
+		return 0, err
+	}
+
+	if tok.Expiry.IsZero() {
+		return googleTokenMaxRefresh, nil
+	}
+	return max(time.Until(tok.Expiry)-googleTokenEarlyRefresh, googleTokenMinRefresh), nil
+}
+
+// writeTokenFile atomically replaces the content of path with token.
+func writeTokenFile(path, token string) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(token), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
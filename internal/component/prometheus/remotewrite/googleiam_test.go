@@ -0,0 +1,91 @@
+package remotewrite
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	types "github.com/grafana/alloy/internal/component/common/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleTokens(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	credentialsFile := writeServiceAccountKey(t, dir, srv.URL)
+
+	var cfg types.GoogleIAMConfig
+	cfg.SetToDefault()
+	cfg.CredentialsFile = credentialsFile
+
+	g := newGoogleTokens(log.NewNopLogger(), filepath.Join(dir, "google_iam"))
+	defer g.Close()
+	path := g.tokenFile(cfg)
+
+	// The first token is fetched in the background.
+	require.NoError(t, g.Update(map[string]types.GoogleIAMConfig{path: cfg}))
+	require.Eventually(t, func() bool {
+		token, err := os.ReadFile(path)
+		return err == nil && string(token) == "token-1"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, component.HealthTypeHealthy, g.Health().Health)
+
+	// Updating with the same configuration keeps refreshing the same token.
+	require.NoError(t, g.Update(map[string]types.GoogleIAMConfig{path: cfg}))
+	require.Equal(t, int32(1), requests.Load())
+
+	// The token file is removed once no endpoint uses it.
+	require.NoError(t, g.Update(nil))
+	require.NoFileExists(t, path)
+
+	// Credentials which can't be loaded are reported through the health.
+	missing := types.GoogleIAMConfig{CredentialsFile: filepath.Join(dir, "missing.json"), Scopes: cfg.Scopes}
+	require.NoError(t, g.Update(map[string]types.GoogleIAMConfig{g.tokenFile(missing): missing}))
+	require.Eventually(t, func() bool {
+		health := g.Health()
+		return health.Health == component.HealthTypeUnhealthy &&
+			strings.Contains(health.Message, "failed to read Google credentials file")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// writeServiceAccountKey writes the JSON key of a service account whose
+// tokens are fetched from tokenURL, and returns its path.
+func writeServiceAccountKey(t *testing.T, dir, tokenURL string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "alloy",
+		"private_key_id": "1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "alloy@alloy.iam.gserviceaccount.com",
+		"token_uri":      tokenURL,
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "key.json")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
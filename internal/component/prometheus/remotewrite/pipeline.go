@@ -0,0 +1,365 @@
+package remotewrite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/static/metrics/wal"
+	"github.com/grafana/alloy/internal/util"
+)
+
+// sharedPipeline is the key of the pipeline shared by all endpoints when
+// endpoints aren't isolated.
+const sharedPipeline = ""
+
+// errWALReplaying is returned when appending to a pipeline whose WAL is still
+// being replayed.
+var errWALReplaying = errors.New("the WAL is being replayed")
+
+// pipeline is a WAL together with the remote_write queues reading from it.
+//
+// By default, all endpoints of the component share a single pipeline. When
+// endpoints are isolated, each endpoint gets its own pipeline so that the WAL
+// of a healthy endpoint is never held back by an unreachable one.
+//
+// The WAL is replayed in the background when the pipeline is created. Appends
+// fail until the replay is done, so the samples sent during the replay are
+// dropped; the startup gate keeps the components sending samples from
+// starting until then, up to its timeout.
+type pipeline struct {
+	log log.Logger
+	dir string
+	reg util.Unregisterer
+
+	remoteStore *remote.Storage
+
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the replay returned.
+
+	mut       sync.RWMutex
+	walStore  *wal.Storage    // Set once the WAL is replayed.
+	storage   storage.Storage // Set once the WAL is replayed.
+	cfg       *config.Config  // The config to apply once the WAL is replayed.
+	progress  wal.ReplayProgress
+	replayErr error
+
+	// lastTs is the last timestamp the WAL was truncated for. It prevents
+	// segments from getting deleted until at least some new data has been
+	// sent.
+	lastTs int64
+}
+
+// newPipeline creates a pipeline storing its WAL in dir, and starts replaying
+// the WAL with opts. notify is called whenever the progress of the replay
+// changes.
+func newPipeline(logger log.Logger, reg prometheus.Registerer, dir string, opts wal.ReplayOptions, notify func()) *pipeline {
+	unregisterer := util.WrapWithUnregisterer(reg)
+
+	remoteLogger := log.With(logger, "subcomponent", "rw")
+	remoteStore := remote.NewStorage(remoteLogger, unregisterer, startTime, dir, remoteFlushDeadline, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pipeline{
+		log:         logger,
+		dir:         dir,
+		reg:         unregisterer,
+		remoteStore: remoteStore,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		lastTs:      math.MinInt64,
+	}
+	go func() {
+		defer close(p.done)
+		p.replay(ctx, opts, notify)
+	}()
+	return p
+}
+
+// replay replays the WAL, and starts sending it once it's replayed.
+func (p *pipeline) replay(ctx context.Context, opts wal.ReplayOptions, notify func()) {
+	opts.OnProgress = func(progress wal.ReplayProgress) {
+		// The replay is only reported as done once the pipeline can be
+		// appended to.
+		progress.Done = false
+
+		p.mut.Lock()
+		p.progress = progress
+		p.mut.Unlock()
+		notify()
+	}
+
+	walLogger := log.With(p.log, "subcomponent", "wal")
+	walStorage, err := wal.NewStorageWithOptions(ctx, walLogger, p.reg, p.dir, opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		level.Error(p.log).Log("msg", "failed to replay the WAL", "err", err)
+		p.mut.Lock()
+		p.replayErr = err
+		p.mut.Unlock()
+		notify()
+		return
+	}
+	walStorage.SetNotifier(p.remoteStore)
+
+	p.mut.Lock()
+	p.walStore = walStorage
+	p.storage = storage.NewFanout(p.log, walStorage, p.remoteStore)
+	p.progress.Done = true
+	if p.cfg != nil {
+		if err := p.remoteStore.ApplyConfig(p.cfg); err != nil {
+			level.Error(p.log).Log("msg", "failed to apply the remote_write config", "err", err)
+		}
+	}
+	p.mut.Unlock()
+	notify()
+}
+
+// ApplyConfig applies the remote_write configuration of the endpoints reading
+// from the pipeline. The configuration is applied once the WAL is replayed,
+// so that the endpoints start sending from the replayed WAL.
+func (p *pipeline) ApplyConfig(cfg *config.Config) error {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	p.cfg = cfg
+	if p.storage == nil {
+		return nil
+	}
+	return p.remoteStore.ApplyConfig(cfg)
+}
+
+// replayStatus returns the progress of the replay of the WAL, and the error
+// which made it fail.
+func (p *pipeline) replayStatus() (wal.ReplayProgress, error) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.progress, p.replayErr
+}
+
+// Appender implements storage.Appendable. The returned appender fails until
+// the WAL is replayed.
+func (p *pipeline) Appender(ctx context.Context) storage.Appender {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	switch {
+	case p.replayErr != nil:
+		return errorAppender{err: fmt.Errorf("WAL replay failed: %w", p.replayErr)}
+	case p.storage == nil:
+		return errorAppender{err: errWALReplaying}
+	}
+	return p.storage.Appender(ctx)
+}
+
+// Truncate deletes data from the WAL which has either been sent by all
+// endpoints of the pipeline, or is older than maxKeepalive.
+func (p *pipeline) Truncate(minKeepalive, maxKeepalive time.Duration) {
+	// The timestamp ts is used to determine which series are not receiving
+	// samples and may be deleted from the WAL. Their most recent append
+	// timestamp is compared to ts, and if that timestamp is older than ts,
+	// they are considered inactive and may be deleted.
+	//
+	// Subtracting a duration from ts will delay when it will be considered
+	// inactive and scheduled for deletion.
+	p.mut.RLock()
+	walStore := p.walStore
+	p.mut.RUnlock()
+	if walStore == nil {
+		level.Debug(p.log).Log("msg", "not truncating the WAL, it's being replayed")
+		return
+	}
+
+	ts := p.remoteStore.LowestSentTimestamp() - minKeepalive.Milliseconds()
+	if ts < 0 {
+		ts = 0
+	}
+
+	// Network issues can prevent the result of LowestSentTimestamp from
+	// changing. We don't want data in the WAL to grow forever, so we set a cap
+	// on the maximum age data can be. If our ts is older than this cutoff point,
+	// we'll shift it forward to start deleting very stale data.
+	if maxTS := timestamp.FromTime(time.Now().Add(-maxKeepalive)); ts < maxTS {
+		ts = maxTS
+	}
+
+	if ts == p.lastTs {
+		level.Debug(p.log).Log("msg", "not truncating the WAL, remote_write timestamp is unchanged", "ts", ts)
+		return
+	}
+	p.lastTs = ts
+
+	level.Debug(p.log).Log("msg", "truncating the WAL", "ts", ts)
+	err := walStore.Truncate(ts)
+	if err != nil {
+		// The only issue here is larger disk usage and a greater replay time,
+		// so we'll only log this as a warning.
+		level.Warn(p.log).Log("msg", "could not truncate WAL", "err", err)
+	}
+}
+
+// Close stops the replay of the WAL, closes the pipeline, and unregisters
+// its metrics.
+func (p *pipeline) Close() error {
+	p.cancel()
+	<-p.done
+
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	var err error
+	if p.storage != nil {
+		err = p.storage.Close()
+	} else {
+		err = p.remoteStore.Close()
+	}
+	p.reg.UnregisterAll()
+	return err
+}
+
+// appendableFunc is a function implementing storage.Appendable.
+type appendableFunc func(ctx context.Context) storage.Appender
+
+func (f appendableFunc) Appender(ctx context.Context) storage.Appender { return f(ctx) }
+
+// isolatedAppendable appends to the WAL of each pipeline independently.
+//
+// Ref IDs are local to a WAL, so a ref ID returned by one pipeline can't be
+// used to append to another one. No ref ID is returned to callers, and series
+// are looked up by their labels the first time they're appended to during a
+// transaction.
+type isolatedAppendable []*pipeline
+
+func (a isolatedAppendable) Appender(ctx context.Context) storage.Appender {
+	app := &isolatedAppender{
+		apps: make([]storage.Appender, 0, len(a)),
+		refs: make([]map[uint64]storage.SeriesRef, 0, len(a)),
+	}
+	for _, p := range a {
+		app.apps = append(app.apps, p.Appender(ctx))
+		app.refs = append(app.refs, map[uint64]storage.SeriesRef{})
+	}
+	return app
+}
+
+// isolatedAppender forwards appends to the appenders of all pipelines. An
+// error of one pipeline doesn't prevent appending to the others.
+type isolatedAppender struct {
+	apps []storage.Appender
+	// refs holds the ref IDs returned by each appender during the
+	// transaction, keyed by the hash of the series labels. Exemplars can
+	// only be appended to series with a known ref ID.
+	refs []map[uint64]storage.SeriesRef
+}
+
+var _ storage.Appender = (*isolatedAppender)(nil)
+
+func (a *isolatedAppender) forEach(f func(app storage.Appender, refs map[uint64]storage.SeriesRef) error) error {
+	var errs []error
+	for i, app := range a.apps {
+		if err := f(app, a.refs[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *isolatedAppender) Append(_ storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	return 0, a.forEach(func(app storage.Appender, refs map[uint64]storage.SeriesRef) error {
+		ref, err := app.Append(refs[l.Hash()], l, t, v)
+		if err == nil {
+			refs[l.Hash()] = ref
+		}
+		return err
+	})
+}
+
+func (a *isolatedAppender) AppendExemplar(_ storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+	return 0, a.forEach(func(app storage.Appender, refs map[uint64]storage.SeriesRef) error {
+		_, err := app.AppendExemplar(refs[l.Hash()], l, e)
+		return err
+	})
+}
+
+func (a *isolatedAppender) AppendHistogram(_ storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return 0, a.forEach(func(app storage.Appender, refs map[uint64]storage.SeriesRef) error {
+		ref, err := app.AppendHistogram(refs[l.Hash()], l, t, h, fh)
+		if err == nil {
+			refs[l.Hash()] = ref
+		}
+		return err
+	})
+}
+
+func (a *isolatedAppender) UpdateMetadata(_ storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
+	return 0, a.forEach(func(app storage.Appender, refs map[uint64]storage.SeriesRef) error {
+		_, err := app.UpdateMetadata(refs[l.Hash()], l, m)
+		return err
+	})
+}
+
+func (a *isolatedAppender) AppendCTZeroSample(_ storage.SeriesRef, l labels.Labels, t, ct int64) (storage.SeriesRef, error) {
+	return 0, a.forEach(func(app storage.Appender, refs map[uint64]storage.SeriesRef) error {
+		_, err := app.AppendCTZeroSample(refs[l.Hash()], l, t, ct)
+		return err
+	})
+}
+
+func (a *isolatedAppender) Commit() error {
+	return a.forEach(func(app storage.Appender, _ map[uint64]storage.SeriesRef) error {
+		return app.Commit()
+	})
+}
+
+func (a *isolatedAppender) Rollback() error {
+	return a.forEach(func(app storage.Appender, _ map[uint64]storage.SeriesRef) error {
+		return app.Rollback()
+	})
+}
+
+// errorAppender is the appender of a pipeline which can't be appended to.
+type errorAppender struct {
+	err error
+}
+
+var _ storage.Appender = errorAppender{}
+
+func (a errorAppender) Append(storage.SeriesRef, labels.Labels, int64, float64) (storage.SeriesRef, error) {
+	return 0, a.err
+}
+
+func (a errorAppender) AppendExemplar(storage.SeriesRef, labels.Labels, exemplar.Exemplar) (storage.SeriesRef, error) {
+	return 0, a.err
+}
+
+func (a errorAppender) AppendHistogram(storage.SeriesRef, labels.Labels, int64, *histogram.Histogram, *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return 0, a.err
+}
+
+func (a errorAppender) UpdateMetadata(storage.SeriesRef, labels.Labels, metadata.Metadata) (storage.SeriesRef, error) {
+	return 0, a.err
+}
+
+func (a errorAppender) AppendCTZeroSample(storage.SeriesRef, labels.Labels, int64, int64) (storage.SeriesRef, error) {
+	return 0, a.err
+}
+
+func (a errorAppender) Commit() error   { return nil }
+func (a errorAppender) Rollback() error { return nil }
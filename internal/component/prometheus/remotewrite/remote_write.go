@@ -3,7 +3,6 @@ package remotewrite
 import (
 	"context"
 	"fmt"
-	"math"
 	"os"
 	"path/filepath"
 	"sync"
@@ -12,17 +11,21 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/alloyseed"
 	"github.com/grafana/alloy/internal/component"
+	types "github.com/grafana/alloy/internal/component/common/config"
 	"github.com/grafana/alloy/internal/component/prometheus"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/clocksync"
 	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/grafana/alloy/internal/static/metrics/wal"
 	"github.com/grafana/alloy/internal/useragent"
+	prom_client "github.com/prometheus/client_golang/prometheus"
+	common "github.com/prometheus/common/config"
+	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
-	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
 	"go.uber.org/atomic"
@@ -50,18 +53,27 @@ func init() {
 
 // Component is the prometheus.remote_write component.
 type Component struct {
-	log  log.Logger
-	opts component.Options
+	log   log.Logger
+	opts  component.Options
+	clock clocksync.Monitor
 
-	walStore    *wal.Storage
-	remoteStore *remote.Storage
-	storage     storage.Storage
-	exited      atomic.Bool
+	exited atomic.Bool
 
 	mut sync.RWMutex
 	cfg Arguments
+	// pipelines are keyed by endpoint name when endpoints are isolated, and
+	// hold a single sharedPipeline otherwise.
+	pipelines map[string]*pipeline
+
+	googleTokens *googleTokens
 
 	receiver *prometheus.Interceptor
+
+	// replayUpdated is notified when the progress of the replay of a WAL
+	// changes.
+	replayUpdated chan struct{}
+	// replayDone is the last exported WALReplayStatus.Done.
+	replayDone bool
 }
 
 // New creates a new prometheus.remote_write component.
@@ -75,17 +87,6 @@ func New(o component.Options, c Arguments) (*Component, error) {
 	oldDataPath := filepath.Join(o.DataPath, "wal", o.ID)
 	_ = os.RemoveAll(oldDataPath)
 
-	walLogger := log.With(o.Logger, "subcomponent", "wal")
-	walStorage, err := wal.NewStorage(walLogger, o.Registerer, o.DataPath)
-	if err != nil {
-		return nil, err
-	}
-
-	remoteLogger := log.With(o.Logger, "subcomponent", "rw")
-	remoteStore := remote.NewStorage(remoteLogger, o.Registerer, startTime, o.DataPath, remoteFlushDeadline, nil)
-
-	walStorage.SetNotifier(remoteStore)
-
 	service, err := o.GetServiceData(labelstore.ServiceName)
 	if err != nil {
 		return nil, err
@@ -93,14 +94,17 @@ func New(o component.Options, c Arguments) (*Component, error) {
 	ls := service.(labelstore.LabelStore)
 
 	res := &Component{
-		log:         o.Logger,
-		opts:        o,
-		walStore:    walStorage,
-		remoteStore: remoteStore,
-		storage:     storage.NewFanout(o.Logger, walStorage, remoteStore),
+		log:       o.Logger,
+		opts:      o,
+		clock:     clocksync.GetMonitor(o),
+		pipelines: map[string]*pipeline{},
+
+		replayUpdated: make(chan struct{}, 1),
+
+		googleTokens: newGoogleTokens(o.Logger, filepath.Join(o.DataPath, "google_iam")),
 	}
 	res.receiver = prometheus.NewInterceptor(
-		res.storage,
+		appendableFunc(res.appender),
 		ls,
 
 		// In the methods below, conversion is needed because remote_writes assume
@@ -108,6 +112,12 @@ func New(o component.Options, c Arguments) (*Component, error) {
 		// remote_writes may return the same ref ID for two different series. We
 		// treat the remote_write ID as a "local ID" and translate it to a "global
 		// ID" to ensure Alloy compatibility.
+		//
+		// The link is refreshed whenever the WAL returns a different ID than the
+		// linked one, which happens once a series was garbage collected from the
+		// WAL and recreated by its labels.
+		//
+		// Isolated endpoints don't return any ref ID, so nothing is linked.
 
 		prometheus.WithAppendHook(func(globalRef storage.SeriesRef, l labels.Labels, t int64, v float64, next storage.Appender) (storage.SeriesRef, error) {
 			if res.exited.Load() {
@@ -116,7 +126,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.Append(storage.SeriesRef(localID), l, t, v)
-			if localID == 0 {
+			if newRef != 0 && uint64(newRef) != localID {
 				ls.GetOrAddLink(res.opts.ID, uint64(newRef), l)
 			}
 			return globalRef, nextErr
@@ -128,7 +138,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.AppendHistogram(storage.SeriesRef(localID), l, t, h, fh)
-			if localID == 0 {
+			if newRef != 0 && uint64(newRef) != localID {
 				ls.GetOrAddLink(res.opts.ID, uint64(newRef), l)
 			}
 			return globalRef, nextErr
@@ -140,7 +150,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.UpdateMetadata(storage.SeriesRef(localID), l, m)
-			if localID == 0 {
+			if newRef != 0 && uint64(newRef) != localID {
 				ls.GetOrAddLink(res.opts.ID, uint64(newRef), l)
 			}
 			return globalRef, nextErr
@@ -152,7 +162,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.AppendExemplar(storage.SeriesRef(localID), l, e)
-			if localID == 0 {
+			if newRef != 0 && uint64(newRef) != localID {
 				ls.GetOrAddLink(res.opts.ID, uint64(newRef), l)
 			}
 			return globalRef, nextErr
@@ -166,80 +176,120 @@ func New(o component.Options, c Arguments) (*Component, error) {
 	if err := res.Update(c); err != nil {
 		return nil, err
 	}
+	res.exportReplayStatus()
 	return res, nil
 }
 
 func startTime() (int64, error) { return 0, nil }
 
-var _ component.Component = (*Component)(nil)
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.DebugComponent  = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+	_ component.ReadyComponent  = (*Component)(nil)
+)
+
+// appender returns an appender for the current pipelines.
+func (c *Component) appender(ctx context.Context) storage.Appender {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	if p, ok := c.pipelines[sharedPipeline]; ok {
+		return p.Appender(ctx)
+	}
+	apps := make(isolatedAppendable, 0, len(c.pipelines))
+	for _, p := range c.pipelines {
+		apps = append(apps, p)
+	}
+	return apps.Appender(ctx)
+}
 
 // Run implements Component.
 func (c *Component) Run(ctx context.Context) error {
 	defer func() {
 		c.exited.Store(true)
 
+		c.mut.Lock()
+		defer c.mut.Unlock()
+
 		level.Debug(c.log).Log("msg", "closing storage")
-		err := c.storage.Close()
-		level.Debug(c.log).Log("msg", "storage closed")
-		if err != nil {
-			level.Error(c.log).Log("msg", "error when closing storage", "err", err)
+		for name, p := range c.pipelines {
+			if err := p.Close(); err != nil {
+				level.Error(c.log).Log("msg", "error when closing storage", "endpoint", name, "err", err)
+			}
 		}
-	}()
+		level.Debug(c.log).Log("msg", "storage closed")
 
-	// Track the last timestamp we truncated for to prevent segments from getting
-	// deleted until at least some new data has been sent.
-	var lastTs = int64(math.MinInt64)
+		c.googleTokens.Close()
+	}()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-c.replayUpdated:
+			c.exportReplayStatus()
 		case <-time.After(c.truncateFrequency()):
-			// We retrieve the current min/max keepalive time at once, since
-			// retrieving them separately could lead to issues where we have an older
-			// value for min which is now larger than max.
 			c.mut.RLock()
 			var (
 				minWALTime = c.cfg.WALOptions.MinKeepaliveTime
 				maxWALTime = c.cfg.WALOptions.MaxKeepaliveTime
 			)
+			// Each pipeline is truncated based on the timestamps sent by its
+			// own endpoints.
+			for _, p := range c.pipelines {
+				p.Truncate(minWALTime, maxWALTime)
+			}
 			c.mut.RUnlock()
+		}
+	}
+}
 
-			// The timestamp ts is used to determine which series are not receiving
-			// samples and may be deleted from the WAL. Their most recent append
-			// timestamp is compared to ts, and if that timestamp is older than ts,
-			// they are considered inactive and may be deleted.
-			//
-			// Subtracting a duration from ts will delay when it will be considered
-			// inactive and scheduled for deletion.
-			ts := c.remoteStore.LowestSentTimestamp() - minWALTime.Milliseconds()
-			if ts < 0 {
-				ts = 0
-			}
+// notifyReplay notifies Run that the progress of the replay of a WAL
+// changed. It doesn't block, as pipelines may be closed while c.mut is held.
+func (c *Component) notifyReplay() {
+	select {
+	case c.replayUpdated <- struct{}{}:
+	default:
+	}
+}
 
-			// Network issues can prevent the result of LowestSentTimestamp from
-			// changing. We don't want data in the WAL to grow forever, so we set a cap
-			// on the maximum age data can be. If our ts is older than this cutoff point,
-			// we'll shift it forward to start deleting very stale data.
-			if maxTS := timestamp.FromTime(time.Now().Add(-maxWALTime)); ts < maxTS {
-				ts = maxTS
-			}
+// exportReplayStatus exports whether the WALs of the pipelines are replayed.
+// The exports are only updated when that changes, as every change makes the
+// components referencing them be evaluated again.
+func (c *Component) exportReplayStatus() {
+	done := c.replayProgress().Done
+	if done == c.replayDone {
+		return
+	}
+	c.replayDone = done
+	c.opts.OnStateChange(Exports{Receiver: c.receiver, WALReplay: WALReplayStatus{Done: done}})
+}
 
-			if ts == lastTs {
-				level.Debug(c.log).Log("msg", "not truncating the WAL, remote_write timestamp is unchanged", "ts", ts)
-				continue
-			}
-			lastTs = ts
-
-			level.Debug(c.log).Log("msg", "truncating the WAL", "ts", ts)
-			err := c.walStore.Truncate(ts)
-			if err != nil {
-				// The only issue here is larger disk usage and a greater replay time,
-				// so we'll only log this as a warning.
-				level.Warn(c.log).Log("msg", "could not truncate WAL", "err", err)
-			}
-		}
+// replayProgress returns the progress of the replay of the WALs of the
+// pipelines.
+func (c *Component) replayProgress() WALReplayProgress {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	res := WALReplayProgress{Done: true}
+	now := time.Now()
+	for _, p := range c.pipelines {
+		progress, err := p.replayStatus()
+		res.Done = res.Done && progress.Done && err == nil
+		res.SegmentsTotal += progress.Segments
+		res.SegmentsReplayed += progress.ReplayedSegments
+		res.SegmentsSkipped += progress.SkippedSegments
+		res.SegmentsRemaining += progress.RemainingSegments()
+		// The WALs are replayed concurrently.
+		res.ETA = max(res.ETA, progress.ETA(now))
 	}
+	return res
+}
+
+// DebugInfo implements component.DebugComponent.
+func (c *Component) DebugInfo() interface{} {
+	return DebugInfo{WALReplay: c.replayProgress()}
 }
 
 func (c *Component) truncateFrequency() time.Duration {
@@ -259,6 +309,24 @@ func (c *Component) Update(newConfig component.Arguments) error {
 	if err != nil {
 		return err
 	}
+
+	// Endpoints authenticating with google_iam read their bearer token from
+	// the file it's refreshed into.
+	tokenFiles := map[string]types.GoogleIAMConfig{}
+	for i, ep := range cfg.Endpoints {
+		if ep.GoogleIAM == nil {
+			continue
+		}
+		path := c.googleTokens.tokenFile(*ep.GoogleIAM)
+		tokenFiles[path] = *ep.GoogleIAM
+		convertedConfig.RemoteWriteConfigs[i].HTTPClientConfig.Authorization = &common.Authorization{
+			Type:            "Bearer",
+			CredentialsFile: path,
+		}
+	}
+	if err := c.googleTokens.Update(tokenFiles); err != nil {
+		return fmt.Errorf("failed to refresh Google access tokens: %w", err)
+	}
 	uid := alloyseed.Get().UID
 	for _, cfg := range convertedConfig.RemoteWriteConfigs {
 		if cfg.Headers == nil {
@@ -267,11 +335,126 @@ func (c *Component) Update(newConfig component.Arguments) error {
 		cfg.Headers[alloyseed.LegacyHeaderName] = uid
 		cfg.Headers[alloyseed.HeaderName] = uid
 	}
-	err = c.remoteStore.ApplyConfig(convertedConfig)
-	if err != nil {
-		return err
+
+	// Group the endpoints by the pipeline they read from.
+	configs := map[string]*config.Config{}
+	if cfg.WALOptions.IsolateEndpoints {
+		for _, rw := range convertedConfig.RemoteWriteConfigs {
+			configs[rw.Name] = &config.Config{
+				GlobalConfig:       convertedConfig.GlobalConfig,
+				RemoteWriteConfigs: []*config.RemoteWriteConfig{rw},
+			}
+		}
+	} else {
+		configs[sharedPipeline] = convertedConfig
+	}
+
+	for name, p := range c.pipelines {
+		if _, ok := configs[name]; ok {
+			continue
+		}
+		if err := p.Close(); err != nil {
+			level.Warn(c.log).Log("msg", "error when closing storage", "endpoint", name, "err", err)
+		}
+		delete(c.pipelines, name)
+
+		// The WAL of a removed endpoint is deleted, as nothing will ever read
+		// from it again. The shared WAL is kept: the ref IDs linked in the
+		// label store refer to its series, and must not be reused for other
+		// series if endpoints stop being isolated.
+		if name != sharedPipeline {
+			if err := os.RemoveAll(p.dir); err != nil {
+				level.Warn(c.log).Log("msg", "could not delete WAL", "endpoint", name, "err", err)
+			}
+		}
+	}
+
+	for name, pcfg := range configs {
+		p, ok := c.pipelines[name]
+		if ok {
+			// A pipeline whose WAL failed to be replayed is created again, to
+			// retry the replay.
+			if _, replayErr := p.replayStatus(); replayErr != nil {
+				if err := p.Close(); err != nil {
+					level.Warn(c.log).Log("msg", "error when closing storage", "endpoint", name, "err", err)
+				}
+				ok = false
+			}
+		}
+		if !ok {
+			p = c.newPipeline(name, cfg.WALOptions.replayOptions())
+			c.pipelines[name] = p
+		}
+		if err := p.ApplyConfig(pcfg); err != nil {
+			return err
+		}
 	}
 
 	c.cfg = cfg
+	// The pipelines which were removed or created change the progress of the
+	// replay.
+	c.notifyReplay()
 	return nil
 }
+
+// CurrentHealth implements component.HealthComponent. The component is
+// unhealthy when the skew of the local clock exceeds the maximum skew of the
+// clocksync block, since the samples are stored in the WAL by timestamp, when
+// a WAL failed to be replayed, or when a Google access token failed to be
+// refreshed.
+func (c *Component) CurrentHealth() component.Health {
+	health := component.LeastHealthy(c.clock.Health(), c.googleTokens.Health())
+	if err := c.replayError(); err != nil {
+		return component.LeastHealthy(health, component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    fmt.Sprintf("failed to replay the WAL: %s", err),
+			UpdateTime: time.Now(),
+		})
+	}
+	return health
+}
+
+// replayError returns the error of the first pipeline whose WAL failed to be
+// replayed.
+func (c *Component) replayError() error {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	for _, p := range c.pipelines {
+		if _, err := p.replayStatus(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ready implements component.ReadyComponent. The component is ready once the
+// WAL of every pipeline is replayed, so that samples sent by the components
+// which start after it are accepted.
+func (c *Component) Ready() bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	for _, p := range c.pipelines {
+		if progress, err := p.replayStatus(); err != nil || !progress.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// newPipeline creates the pipeline of the endpoint name. The shared pipeline
+// stores its WAL in the data directory of the component, while isolated
+// pipelines store it in a subdirectory per endpoint.
+func (c *Component) newPipeline(name string, opts wal.ReplayOptions) *pipeline {
+	if name == sharedPipeline {
+		return newPipeline(c.log, c.opts.Registerer, c.opts.DataPath, opts, c.notifyReplay)
+	}
+
+	var (
+		logger = log.With(c.log, "endpoint", name)
+		reg    = prom_client.WrapRegistererWith(prom_client.Labels{"endpoint": name}, c.opts.Registerer)
+		dir    = filepath.Join(c.opts.DataPath, "endpoints", name)
+	)
+	return newPipeline(logger, reg, dir, opts, c.notifyReplay)
+}
@@ -181,6 +181,66 @@ func TestUpdate(t *testing.T) {
 	}})
 }
 
+// TestIsolateEndpoints ensures that an unreachable endpoint doesn't prevent
+// healthy endpoints from receiving metrics when endpoints are isolated.
+func TestIsolateEndpoints(t *testing.T) {
+	writeResult := make(chan *prompb.WriteRequest)
+
+	srv := newTestServer(t, writeResult)
+	defer srv.Close()
+
+	// The second endpoint always fails to receive metrics.
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	args := testArgsForConfig(t, fmt.Sprintf(`
+		endpoint {
+			name           = "healthy"
+			url            = "%s/api/v1/write"
+			remote_timeout = "100ms"
+
+			queue_config {
+				batch_send_deadline = "100ms"
+			}
+		}
+
+		endpoint {
+			name           = "unreachable"
+			url            = "%s/api/v1/write"
+			remote_timeout = "100ms"
+
+			queue_config {
+				batch_send_deadline = "100ms"
+			}
+		}
+
+		wal {
+			isolate_endpoints = true
+		}
+	`, srv.URL, failing.URL))
+	tc, err := componenttest.NewControllerFromID(util.TestLogger(t), "prometheus.remote_write")
+	require.NoError(t, err)
+	go func() {
+		err = tc.Run(componenttest.TestContext(t), args)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, tc.WaitRunning(5*time.Second))
+
+	sampleTimestamp := time.Now().Add(time.Minute).UnixMilli()
+	sendMetric(t, tc, labels.FromStrings("foo", "bar"), sampleTimestamp, 12)
+
+	assertReceived(t, writeResult, []prompb.TimeSeries{{
+		Labels: []prompb.Label{
+			{Name: "foo", Value: "bar"},
+		},
+		Samples: []prompb.Sample{
+			{Timestamp: sampleTimestamp, Value: 12},
+		},
+	}})
+}
+
 func assertReceived(t *testing.T, writeResult chan *prompb.WriteRequest, expect []prompb.TimeSeries) {
 	select {
 	case <-time.After(time.Minute):
@@ -214,6 +274,12 @@ func sendMetric(
 	value float64,
 ) {
 
+	// The WAL is replayed in the background, and can't be appended to until
+	// the replay is done.
+	require.Eventually(t, func() bool {
+		return tc.Exports().(remotewrite.Exports).WALReplay.Done
+	}, 5*time.Second, 10*time.Millisecond)
+
 	rwExports := tc.Exports().(remotewrite.Exports)
 	appender := rwExports.Receiver.Appender(context.Background())
 	_, err := appender.Append(0, labels, time, value)
@@ -4,20 +4,18 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 
 	types "github.com/grafana/alloy/internal/component/common/config"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
-	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/grafana/alloy/internal/static/metrics/wal"
 
-	"github.com/google/uuid"
 	common "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
-	promsigv4 "github.com/prometheus/common/sigv4"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
-	"github.com/prometheus/prometheus/storage/remote/azuread"
 )
 
 // Defaults for config blocks.
@@ -45,9 +43,10 @@ var (
 	}
 
 	DefaultWALOptions = WALOptions{
-		TruncateFrequency: 2 * time.Hour,
-		MinKeepaliveTime:  5 * time.Minute,
-		MaxKeepaliveTime:  8 * time.Hour,
+		TruncateFrequency:    2 * time.Hour,
+		MinKeepaliveTime:     5 * time.Minute,
+		MaxKeepaliveTime:     8 * time.Hour,
+		CorruptSegmentPolicy: CorruptSegmentRepair,
 	}
 )
 
@@ -64,6 +63,30 @@ func (rc *Arguments) SetToDefault() {
 	*rc = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (rc *Arguments) Validate() error {
+	if !rc.WALOptions.IsolateEndpoints {
+		return nil
+	}
+
+	// The name of isolated endpoints is used as the directory of their WAL, so
+	// it must be explicit and stable across configuration changes.
+	names := make(map[string]struct{}, len(rc.Endpoints))
+	for _, e := range rc.Endpoints {
+		switch {
+		case e.Name == "":
+			return fmt.Errorf("endpoint %q must have a name when isolate_endpoints is enabled", e.URL)
+		case strings.ContainsAny(e.Name, `/\`) || e.Name == "." || e.Name == "..":
+			return fmt.Errorf("endpoint name %q must be a valid directory name when isolate_endpoints is enabled", e.Name)
+		}
+		if _, ok := names[e.Name]; ok {
+			return fmt.Errorf("found duplicate endpoint name %q", e.Name)
+		}
+		names[e.Name] = struct{}{}
+	}
+	return nil
+}
+
 // EndpointOptions describes an individual location for where metrics in the WAL
 // should be delivered to using the remote_write protocol.
 type EndpointOptions struct {
@@ -77,8 +100,9 @@ type EndpointOptions struct {
 	QueueOptions         *QueueOptions           `alloy:"queue_config,block,optional"`
 	MetadataOptions      *MetadataOptions        `alloy:"metadata_config,block,optional"`
 	WriteRelabelConfigs  []*alloy_relabel.Config `alloy:"write_relabel_config,block,optional"`
-	SigV4                *SigV4Config            `alloy:"sigv4,block,optional"`
-	AzureAD              *AzureADConfig          `alloy:"azuread,block,optional"`
+	SigV4                *types.SigV4Config      `alloy:"sigv4,block,optional"`
+	AzureAD              *types.AzureADConfig    `alloy:"azuread,block,optional"`
+	GoogleIAM            *types.GoogleIAMConfig  `alloy:"google_iam,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -107,16 +131,22 @@ func (r *EndpointOptions) Validate() error {
 		}
 	}
 
-	const tooManyAuthErr = "at most one of sigv4, azuread, basic_auth, oauth2, bearer_token & bearer_token_file must be configured"
+	const tooManyAuthErr = "at most one of sigv4, azuread, google_iam, basic_auth, oauth2, bearer_token & bearer_token_file must be configured"
 
 	if r.SigV4 != nil {
-		if r.AzureAD != nil || isAuthSetInHttpClientConfig(r.HTTPClientConfig) {
+		if r.AzureAD != nil || r.GoogleIAM != nil || isAuthSetInHttpClientConfig(r.HTTPClientConfig) {
 			return fmt.Errorf(tooManyAuthErr)
 		}
 	}
 
 	if r.AzureAD != nil {
-		if r.SigV4 != nil || isAuthSetInHttpClientConfig(r.HTTPClientConfig) {
+		if r.SigV4 != nil || r.GoogleIAM != nil || isAuthSetInHttpClientConfig(r.HTTPClientConfig) {
+			return fmt.Errorf(tooManyAuthErr)
+		}
+	}
+
+	if r.GoogleIAM != nil {
+		if r.SigV4 != nil || r.AzureAD != nil || isAuthSetInHttpClientConfig(r.HTTPClientConfig) {
 			return fmt.Errorf(tooManyAuthErr)
 		}
 	}
@@ -199,9 +229,30 @@ func (o *MetadataOptions) toPrometheusType() config.MetadataConfig {
 
 // WALOptions configures behavior within the WAL.
 type WALOptions struct {
-	TruncateFrequency time.Duration `alloy:"truncate_frequency,attr,optional"`
-	MinKeepaliveTime  time.Duration `alloy:"min_keepalive_time,attr,optional"`
-	MaxKeepaliveTime  time.Duration `alloy:"max_keepalive_time,attr,optional"`
+	TruncateFrequency    time.Duration `alloy:"truncate_frequency,attr,optional"`
+	MinKeepaliveTime     time.Duration `alloy:"min_keepalive_time,attr,optional"`
+	MaxKeepaliveTime     time.Duration `alloy:"max_keepalive_time,attr,optional"`
+	IsolateEndpoints     bool          `alloy:"isolate_endpoints,attr,optional"`
+	MaxReplayDuration    time.Duration `alloy:"max_replay_duration,attr,optional"`
+	CorruptSegmentPolicy string        `alloy:"corrupt_segment_policy,attr,optional"`
+}
+
+// The policies to handle the corrupted segments found when replaying the WAL.
+const (
+	// CorruptSegmentRepair repairs the WAL, which deletes the segments after
+	// the corrupted one.
+	CorruptSegmentRepair = "repair"
+	// CorruptSegmentSkip deletes the corrupted segment and the segments
+	// before it, and keeps replaying the newer ones.
+	CorruptSegmentSkip = "skip"
+)
+
+// replayOptions returns the options to replay the WAL with.
+func (o WALOptions) replayOptions() wal.ReplayOptions {
+	return wal.ReplayOptions{
+		MaxDuration:         o.MaxReplayDuration,
+		SkipCorruptSegments: o.CorruptSegmentPolicy == CorruptSegmentSkip,
+	}
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -216,6 +267,10 @@ func (o *WALOptions) Validate() error {
 		return fmt.Errorf("truncate_frequency must not be 0")
 	case o.MaxKeepaliveTime <= o.MinKeepaliveTime:
 		return fmt.Errorf("min_keepalive_time must be smaller than max_keepalive_time")
+	case o.MaxReplayDuration < 0:
+		return fmt.Errorf("max_replay_duration must not be negative")
+	case o.CorruptSegmentPolicy != CorruptSegmentRepair && o.CorruptSegmentPolicy != CorruptSegmentSkip:
+		return fmt.Errorf("corrupt_segment_policy must be %q or %q", CorruptSegmentRepair, CorruptSegmentSkip)
 	}
 
 	return nil
@@ -224,7 +279,34 @@ func (o *WALOptions) Validate() error {
 // Exports are the set of fields exposed by the prometheus.remote_write
 // component.
 type Exports struct {
-	Receiver storage.Appendable `alloy:"receiver,attr"`
+	Receiver  storage.Appendable `alloy:"receiver,attr"`
+	WALReplay WALReplayStatus    `alloy:"wal_replay,attr"`
+}
+
+// WALReplayStatus tells whether the WAL was replayed when the component
+// started. It only changes when the replay starts and when it's done, so that
+// the components referencing the exports aren't evaluated again during the
+// replay. The progress of the replay is reported in DebugInfo.
+type WALReplayStatus struct {
+	Done bool `alloy:"done,attr"`
+}
+
+// DebugInfo is the debug information of the prometheus.remote_write
+// component.
+type DebugInfo struct {
+	WALReplay WALReplayProgress `alloy:"wal_replay,block"`
+}
+
+// WALReplayProgress is the progress of the replay of the WAL when the
+// component starts. The progress of all the WALs is summed up when endpoints
+// are isolated.
+type WALReplayProgress struct {
+	Done              bool          `alloy:"done,attr"`
+	SegmentsTotal     int           `alloy:"segments_total,attr"`
+	SegmentsReplayed  int           `alloy:"segments_replayed,attr"`
+	SegmentsSkipped   int           `alloy:"segments_skipped,attr"`
+	SegmentsRemaining int           `alloy:"segments_remaining,attr"`
+	ETA               time.Duration `alloy:"eta,attr"`
 }
 
 func convertConfigs(cfg Arguments) (*config.Config, error) {
@@ -246,8 +328,8 @@ func convertConfigs(cfg Arguments) (*config.Config, error) {
 			HTTPClientConfig:    *rw.HTTPClientConfig.Convert(),
 			QueueConfig:         rw.QueueOptions.toPrometheusType(),
 			MetadataConfig:      rw.MetadataOptions.toPrometheusType(),
-			SigV4Config:         rw.SigV4.toPrometheusType(),
-			AzureADConfig:       rw.AzureAD.toPrometheusType(),
+			SigV4Config:         rw.SigV4.Convert(),
+			AzureADConfig:       rw.AzureAD.Convert(),
 		})
 	}
 
@@ -267,84 +349,3 @@ func toLabels(in map[string]string) labels.Labels {
 	sort.Sort(res)
 	return res
 }
-
-// ManagedIdentityConfig is used to store managed identity config values
-type ManagedIdentityConfig struct {
-	// ClientID is the clientId of the managed identity that is being used to authenticate.
-	ClientID string `alloy:"client_id,attr"`
-}
-
-func (m ManagedIdentityConfig) toPrometheusType() azuread.ManagedIdentityConfig {
-	return azuread.ManagedIdentityConfig{
-		ClientID: m.ClientID,
-	}
-}
-
-type AzureADConfig struct {
-	// ManagedIdentity is the managed identity that is being used to authenticate.
-	ManagedIdentity ManagedIdentityConfig `alloy:"managed_identity,block"`
-
-	// Cloud is the Azure cloud in which the service is running. Example: AzurePublic/AzureGovernment/AzureChina.
-	Cloud string `alloy:"cloud,attr,optional"`
-}
-
-func (a *AzureADConfig) Validate() error {
-	if a.Cloud != azuread.AzureChina && a.Cloud != azuread.AzureGovernment && a.Cloud != azuread.AzurePublic {
-		return fmt.Errorf("must provide a cloud in the Azure AD config")
-	}
-
-	_, err := uuid.Parse(a.ManagedIdentity.ClientID)
-	if err != nil {
-		return fmt.Errorf("the provided Azure Managed Identity client_id provided is invalid")
-	}
-
-	return nil
-}
-
-// SetToDefault implements syntax.Defaulter.
-func (a *AzureADConfig) SetToDefault() {
-	*a = AzureADConfig{
-		Cloud: azuread.AzurePublic,
-	}
-}
-
-func (a *AzureADConfig) toPrometheusType() *azuread.AzureADConfig {
-	if a == nil {
-		return nil
-	}
-
-	mangedIdentity := a.ManagedIdentity.toPrometheusType()
-	return &azuread.AzureADConfig{
-		ManagedIdentity: &mangedIdentity,
-		Cloud:           a.Cloud,
-	}
-}
-
-type SigV4Config struct {
-	Region    string            `alloy:"region,attr,optional"`
-	AccessKey string            `alloy:"access_key,attr,optional"`
-	SecretKey alloytypes.Secret `alloy:"secret_key,attr,optional"`
-	Profile   string            `alloy:"profile,attr,optional"`
-	RoleARN   string            `alloy:"role_arn,attr,optional"`
-}
-
-func (s *SigV4Config) Validate() error {
-	if (s.AccessKey == "") != (s.SecretKey == "") {
-		return fmt.Errorf("must provide an AWS SigV4 access key and secret key if credentials are specified in the SigV4 config")
-	}
-	return nil
-}
-
-func (s *SigV4Config) toPrometheusType() *promsigv4.SigV4Config {
-	if s == nil {
-		return nil
-	}
-
-	return &promsigv4.SigV4Config{
-		Region:    s.Region,
-		AccessKey: s.AccessKey,
-		SecretKey: common.Secret(s.SecretKey),
-		Profile:   s.Profile,
-		RoleARN:   s.RoleARN,
-	}
-}
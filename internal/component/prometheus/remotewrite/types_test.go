@@ -206,7 +206,7 @@ func TestAlloyConfig(t *testing.T) {
 				sigv4 {}
 				bearer_token = "token"
 			}`,
-			errorMsg: "at most one of sigv4, azuread, basic_auth, oauth2, bearer_token & bearer_token_file must be configured",
+			errorMsg: "at most one of sigv4, azuread, google_iam, basic_auth, oauth2, bearer_token & bearer_token_file must be configured",
 		},
 		{
 			testName: "TooManyAuth2",
@@ -221,7 +221,7 @@ func TestAlloyConfig(t *testing.T) {
 					}
 				}
 			}`,
-			errorMsg: "at most one of sigv4, azuread, basic_auth, oauth2, bearer_token & bearer_token_file must be configured",
+			errorMsg: "at most one of sigv4, azuread, google_iam, basic_auth, oauth2, bearer_token & bearer_token_file must be configured",
 		},
 		{
 			testName: "BadAzureClientId",
@@ -258,6 +258,48 @@ func TestAlloyConfig(t *testing.T) {
 			}`,
 			errorMsg: "at most one of basic_auth, authorization, oauth2, bearer_token & bearer_token_file must be configured",
 		},
+		{
+			testName: "IsolatedEndpointWithoutName",
+			cfg: `
+			endpoint {
+				url = "http://0.0.0.0:11111/api/v1/write"
+			}
+
+			wal {
+				isolate_endpoints = true
+			}`,
+			errorMsg: `endpoint "http://0.0.0.0:11111/api/v1/write" must have a name when isolate_endpoints is enabled`,
+		},
+		{
+			testName: "IsolatedEndpointsWithDuplicateNames",
+			cfg: `
+			endpoint {
+				name = "primary"
+				url  = "http://0.0.0.0:11111/api/v1/write"
+			}
+
+			endpoint {
+				name = "primary"
+				url  = "http://0.0.0.0:22222/api/v1/write"
+			}
+
+			wal {
+				isolate_endpoints = true
+			}`,
+			errorMsg: `found duplicate endpoint name "primary"`,
+		},
+		{
+			testName: "InvalidCorruptSegmentPolicy",
+			cfg: `
+			endpoint {
+				url = "http://0.0.0.0:11111/api/v1/write"
+			}
+
+			wal {
+				corrupt_segment_policy = "ignore"
+			}`,
+			errorMsg: `corrupt_segment_policy must be "repair" or "skip"`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,250 @@
+package local
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/util/annotations"
+)
+
+// buffer holds the float samples received by the component in memory so that
+// rules can be evaluated against them. Samples older than the look-back
+// window are removed by calling truncate.
+type buffer struct {
+	mut    sync.RWMutex
+	series map[string]*bufferedSeries
+}
+
+type bufferedSeries struct {
+	labels  labels.Labels
+	samples []sample // Sorted by timestamp.
+}
+
+var (
+	_ storage.Appendable = (*buffer)(nil)
+	_ storage.Queryable  = (*buffer)(nil)
+)
+
+func newBuffer() *buffer {
+	return &buffer{series: make(map[string]*bufferedSeries)}
+}
+
+// Appender implements storage.Appendable.
+func (b *buffer) Appender(_ context.Context) storage.Appender {
+	return &bufferAppender{b: b}
+}
+
+// Querier implements storage.Queryable.
+func (b *buffer) Querier(mint, maxt int64) (storage.Querier, error) {
+	return &bufferQuerier{b: b, mint: mint, maxt: maxt}, nil
+}
+
+// truncate removes all samples older than mint, as well as series which are
+// left without samples.
+func (b *buffer) truncate(mint int64) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	for key, s := range b.series {
+		i := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].t >= mint })
+		if i == len(s.samples) {
+			delete(b.series, key)
+			continue
+		}
+		s.samples = slices.Clone(s.samples[i:])
+	}
+}
+
+// numSeries returns the number of series held by the buffer.
+func (b *buffer) numSeries() int {
+	b.mut.RLock()
+	defer b.mut.RUnlock()
+	return len(b.series)
+}
+
+type pendingSample struct {
+	labels labels.Labels
+	sample sample
+}
+
+// bufferAppender adds samples to the buffer when the transaction is committed.
+// Only float samples are kept.
+type bufferAppender struct {
+	b       *buffer
+	pending []pendingSample
+}
+
+var _ storage.Appender = (*bufferAppender)(nil)
+
+func (a *bufferAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	a.pending = append(a.pending, pendingSample{labels: l, sample: sample{t: t, f: v}})
+	return ref, nil
+}
+
+func (a *bufferAppender) Commit() error {
+	a.b.mut.Lock()
+	defer a.b.mut.Unlock()
+
+	for _, p := range a.pending {
+		key := string(p.labels.Bytes(nil))
+		s, ok := a.b.series[key]
+		if !ok {
+			s = &bufferedSeries{labels: p.labels}
+			a.b.series[key] = s
+		}
+		// Out-of-order samples can't be queried and are dropped.
+		if n := len(s.samples); n > 0 && s.samples[n-1].t >= p.sample.t {
+			continue
+		}
+		s.samples = append(s.samples, p.sample)
+	}
+	a.pending = nil
+	return nil
+}
+
+func (a *bufferAppender) Rollback() error {
+	a.pending = nil
+	return nil
+}
+
+func (a *bufferAppender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a *bufferAppender) AppendHistogram(ref storage.SeriesRef, _ labels.Labels, _ int64, _ *histogram.Histogram, _ *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a *bufferAppender) UpdateMetadata(ref storage.SeriesRef, _ labels.Labels, _ metadata.Metadata) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a *bufferAppender) AppendCTZeroSample(ref storage.SeriesRef, _ labels.Labels, _, _ int64) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+// bufferQuerier queries the samples of the buffer between mint and maxt.
+type bufferQuerier struct {
+	b          *buffer
+	mint, maxt int64
+}
+
+var _ storage.Querier = (*bufferQuerier)(nil)
+
+func (q *bufferQuerier) Select(_ context.Context, _ bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	mint, maxt := q.mint, q.maxt
+	if hints != nil {
+		mint, maxt = hints.Start, hints.End
+	}
+
+	q.b.mut.RLock()
+	defer q.b.mut.RUnlock()
+
+	var series []storage.Series
+	for _, s := range q.b.series {
+		if !matches(s.labels, matchers) {
+			continue
+		}
+		var samples []chunks.Sample
+		for _, smpl := range s.samples {
+			if smpl.t >= mint && smpl.t <= maxt {
+				samples = append(samples, smpl)
+			}
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		series = append(series, storage.NewListSeries(s.labels, samples))
+	}
+
+	// Series are always sorted, as expected by PromQL.
+	sort.Slice(series, func(i, j int) bool {
+		return labels.Compare(series[i].Labels(), series[j].Labels()) < 0
+	})
+	return &seriesSet{series: series, i: -1}
+}
+
+func (q *bufferQuerier) LabelValues(_ context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	q.b.mut.RLock()
+	defer q.b.mut.RUnlock()
+
+	values := map[string]struct{}{}
+	for _, s := range q.b.series {
+		if v := s.labels.Get(name); v != "" && matches(s.labels, matchers) {
+			values[v] = struct{}{}
+		}
+	}
+	return sortedKeys(values), nil, nil
+}
+
+func (q *bufferQuerier) LabelNames(_ context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	q.b.mut.RLock()
+	defer q.b.mut.RUnlock()
+
+	names := map[string]struct{}{}
+	for _, s := range q.b.series {
+		if !matches(s.labels, matchers) {
+			continue
+		}
+		s.labels.Range(func(l labels.Label) {
+			names[l.Name] = struct{}{}
+		})
+	}
+	return sortedKeys(names), nil, nil
+}
+
+func (q *bufferQuerier) Close() error { return nil }
+
+func matches(l labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(l.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// seriesSet is a storage.SeriesSet iterating over a list of series.
+type seriesSet struct {
+	series []storage.Series
+	i      int
+}
+
+func (s *seriesSet) Next() bool {
+	s.i++
+	return s.i < len(s.series)
+}
+
+func (s *seriesSet) At() storage.Series                { return s.series[s.i] }
+func (s *seriesSet) Err() error                        { return nil }
+func (s *seriesSet) Warnings() annotations.Annotations { return nil }
+
+// sample is a float sample implementing chunks.Sample.
+type sample struct {
+	t int64
+	f float64
+}
+
+func (s sample) T() int64                      { return s.t }
+func (s sample) F() float64                    { return s.f }
+func (s sample) H() *histogram.Histogram       { return nil }
+func (s sample) FH() *histogram.FloatHistogram { return nil }
+func (s sample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
@@ -0,0 +1,313 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+const (
+	// defaultLookbackDelta is the default lookback delta of instant vector
+	// selectors in Prometheus.
+	defaultLookbackDelta = 5 * time.Minute
+	queryTimeout         = 2 * time.Minute
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.rule.local",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the
+// prometheus.rule.local component.
+type Arguments struct {
+	// Where the results of the rules should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often the rules are evaluated.
+	EvaluationInterval time.Duration `alloy:"evaluation_interval,attr,optional"`
+
+	// How long received samples are kept in memory for rules to query.
+	Lookback time.Duration `alloy:"lookback,attr,optional"`
+
+	Rules []Rule `alloy:"rule,block,optional"`
+}
+
+// Rule is a recording or alerting rule.
+type Rule struct {
+	Record        string            `alloy:"record,attr,optional"`
+	Alert         string            `alloy:"alert,attr,optional"`
+	Expr          string            `alloy:"expr,attr"`
+	For           time.Duration     `alloy:"for,attr,optional"`
+	KeepFiringFor time.Duration     `alloy:"keep_firing_for,attr,optional"`
+	Labels        map[string]string `alloy:"labels,attr,optional"`
+	Annotations   map[string]string `alloy:"annotations,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		EvaluationInterval: time.Minute,
+		Lookback:           5 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.EvaluationInterval <= 0 {
+		return fmt.Errorf("evaluation_interval must be greater than 0")
+	}
+	if args.Lookback < args.EvaluationInterval {
+		return fmt.Errorf("lookback (%s) must not be shorter than evaluation_interval (%s)", args.Lookback, args.EvaluationInterval)
+	}
+	for i, r := range args.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("invalid rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	switch {
+	case r.Record == "" && r.Alert == "":
+		return errors.New("one of record or alert must be set")
+	case r.Record != "" && r.Alert != "":
+		return errors.New("only one of record or alert can be set")
+	}
+
+	if r.Record != "" {
+		if !model.IsValidMetricName(model.LabelValue(r.Record)) {
+			return fmt.Errorf("invalid recording rule name %q", r.Record)
+		}
+		if r.For != 0 || r.KeepFiringFor != 0 || len(r.Annotations) > 0 {
+			return fmt.Errorf("for, keep_firing_for and annotations can only be set for alerting rules")
+		}
+	}
+	if r.For < 0 || r.KeepFiringFor < 0 {
+		return errors.New("for and keep_firing_for must not be negative")
+	}
+
+	for name := range r.Labels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	if _, err := parser.ParseExpr(r.Expr); err != nil {
+		return fmt.Errorf("invalid expr: %w", err)
+	}
+	return nil
+}
+
+// Exports holds values which are exported by the prometheus.rule.local
+// component.
+type Exports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+// Component implements the prometheus.rule.local component.
+type Component struct {
+	opts    component.Options
+	buffer  *buffer
+	fanout  *prometheus.Fanout
+	engine  *promql.Engine
+	metrics *rules.Metrics
+
+	bufferedSeries prometheus_client.Gauge
+	updated        chan struct{}
+
+	// mut is held for reading during evaluations, so that the rules aren't
+	// updated while they're evaluated.
+	mut   sync.RWMutex
+	args  Arguments
+	group *rules.Group
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new prometheus.rule.local component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	bufferedSeries := prometheus_client.NewGauge(prometheus_client.GaugeOpts{
+		Name: "prometheus_rule_local_buffered_series",
+		Help: "Number of series held in memory for rules to query.",
+	})
+	if err := o.Registerer.Register(bufferedSeries); err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:   o,
+		buffer: newBuffer(),
+		fanout: prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, data.(labelstore.LabelStore)),
+		engine: promql.NewEngine(promql.EngineOpts{
+			Logger:               log.With(o.Logger, "subcomponent", "engine"),
+			Reg:                  o.Registerer,
+			MaxSamples:           50_000_000,
+			Timeout:              queryTimeout,
+			LookbackDelta:        defaultLookbackDelta,
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
+		}),
+		metrics:        rules.NewGroupMetrics(o.Registerer),
+		bufferedSeries: bufferedSeries,
+		updated:        make(chan struct{}, 1),
+	}
+
+	o.OnStateChange(Exports{Receiver: c.buffer})
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	c.mut.RLock()
+	ticker := time.NewTicker(c.args.EvaluationInterval)
+	c.mut.RUnlock()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.updated:
+			c.mut.RLock()
+			ticker.Reset(c.args.EvaluationInterval)
+			c.mut.RUnlock()
+		case now := <-ticker.C:
+			c.evaluate(ctx, now)
+		}
+	}
+}
+
+func (c *Component) evaluate(ctx context.Context, now time.Time) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	c.buffer.truncate(timestamp.FromTime(now.Add(-c.args.Lookback)))
+	c.bufferedSeries.Set(float64(c.buffer.numSeries()))
+
+	c.group.Eval(ctx, now)
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	group, err := c.newGroup(newArgs)
+	if err != nil {
+		return err
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.group != nil {
+		// Keep track of active alerts and of the series written by previous
+		// evaluations of unchanged rules.
+		group.CopyState(c.group)
+	}
+	c.group = group
+	c.args = newArgs
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *Component) newGroup(args Arguments) (*rules.Group, error) {
+	rs := make([]rules.Rule, 0, len(args.Rules))
+	for _, r := range args.Rules {
+		expr, err := parser.ParseExpr(r.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Record != "" {
+			rs = append(rs, rules.NewRecordingRule(r.Record, expr, labels.FromMap(r.Labels)))
+			continue
+		}
+		rs = append(rs, rules.NewAlertingRule(
+			r.Alert, expr, r.For, r.KeepFiringFor,
+			labels.FromMap(r.Labels), labels.FromMap(r.Annotations), labels.EmptyLabels(), "",
+			true, log.With(c.opts.Logger, "alert", r.Alert),
+		))
+	}
+
+	return rules.NewGroup(rules.GroupOptions{
+		Name:     c.opts.ID,
+		Interval: args.EvaluationInterval,
+		Rules:    rs,
+		Opts: &rules.ManagerOptions{
+			ExternalURL: &url.URL{},
+			QueryFunc:   c.queryFunc(min(args.Lookback, defaultLookbackDelta)),
+			// Alerts are only written as ALERTS series and aren't sent
+			// anywhere.
+			NotifyFunc: func(context.Context, string, ...*rules.Alert) {},
+			Context:    context.Background(),
+			Appendable: c.fanout,
+			Queryable:  c.buffer,
+			Logger:     c.opts.Logger,
+			Metrics:    c.metrics,
+		},
+	}), nil
+}
+
+// queryFunc returns a rules.QueryFunc evaluating queries against the buffer.
+// Instant vector selectors never look back further than lookbackDelta.
+func (c *Component) queryFunc(lookbackDelta time.Duration) rules.QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		q, err := c.engine.NewInstantQuery(ctx, c.buffer, promql.NewPrometheusQueryOpts(false, lookbackDelta), qs, t)
+		if err != nil {
+			return nil, err
+		}
+		res := q.Exec(ctx)
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		switch v := res.Value.(type) {
+		case promql.Vector:
+			return v, nil
+		case promql.Scalar:
+			return promql.Vector{promql.Sample{T: v.T, F: v.V, Metric: labels.EmptyLabels()}}, nil
+		default:
+			return nil, errors.New("rule result is not a vector or scalar")
+		}
+	}
+}
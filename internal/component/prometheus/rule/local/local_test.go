@@ -0,0 +1,153 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cfg: `
+			forward_to = []
+			rule {
+				record = "job:up:sum"
+				expr   = "sum by (job) (up)"
+			}
+			rule {
+				alert  = "TargetDown"
+				expr   = "up == 0"
+				for    = "5m"
+				labels = { severity = "warning" }
+			}`,
+		},
+		{
+			name: "missing name",
+			cfg: `
+			forward_to = []
+			rule {
+				expr = "up"
+			}`,
+			expectedErr: "one of record or alert must be set",
+		},
+		{
+			name: "invalid expr",
+			cfg: `
+			forward_to = []
+			rule {
+				record = "foo"
+				expr   = "sum(("
+			}`,
+			expectedErr: "invalid expr",
+		},
+		{
+			name: "for on recording rule",
+			cfg: `
+			forward_to = []
+			rule {
+				record = "foo"
+				expr   = "up"
+				for    = "1m"
+			}`,
+			expectedErr: "can only be set for alerting rules",
+		},
+		{
+			name: "lookback shorter than interval",
+			cfg: `
+			forward_to          = []
+			evaluation_interval = "1m"
+			lookback            = "30s"`,
+			expectedErr: "must not be shorter than evaluation_interval",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestRecordingRule(t *testing.T) {
+	var (
+		mut      sync.Mutex
+		recorded = map[string]float64{}
+	)
+	ls := labelstore.New(nil, prom.NewRegistry())
+	receiver := prometheus.NewInterceptor(nil, ls, prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+		mut.Lock()
+		defer mut.Unlock()
+		recorded[l.String()] = v
+		return ref, nil
+	}))
+
+	var exports Exports
+	c, err := New(component.Options{
+		ID:         "prometheus.rule.local.test",
+		Logger:     util.TestAlloyLogger(t),
+		Registerer: prom.NewRegistry(),
+		OnStateChange: func(e component.Exports) {
+			exports = e.(Exports)
+		},
+		GetServiceData: func(name string) (interface{}, error) {
+			if name == labelstore.ServiceName {
+				return ls, nil
+			}
+			return nil, fmt.Errorf("service not found %s", name)
+		},
+	}, Arguments{
+		ForwardTo:          []storage.Appendable{receiver},
+		EvaluationInterval: time.Minute,
+		Lookback:           5 * time.Minute,
+		Rules: []Rule{
+			{Record: "job:requests:sum", Expr: "sum by (job) (requests)"},
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	app := exports.Receiver.Appender(context.Background())
+	for i, job := range []string{"a", "a", "b"} {
+		_, err := app.Append(0, labels.FromStrings("__name__", "requests", "job", job, "instance", fmt.Sprint(i)), timestamp.FromTime(now.Add(-time.Minute)), 2)
+		require.NoError(t, err)
+	}
+	// Samples older than the lookback are ignored.
+	_, err = app.Append(0, labels.FromStrings("__name__", "requests", "job", "c"), timestamp.FromTime(now.Add(-10*time.Minute)), 2)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	c.evaluate(context.Background(), now)
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Equal(t, map[string]float64{
+		`{__name__="job:requests:sum", job="a"}`: 4,
+		`{__name__="job:requests:sum", job="b"}`: 2,
+	}, recorded)
+	require.Equal(t, 3, c.buffer.numSeries())
+}
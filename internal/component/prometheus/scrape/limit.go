@@ -0,0 +1,80 @@
+package scrape
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/prometheus/storage"
+)
+
+// limitAppendable limits the number of concurrent scrapes of the scrape
+// loops. Like for tracing, the lifetime of an appender is the duration of a
+// scrape, so scrape loops wait for a slot when they get their appender, and
+// release it when they commit or roll it back.
+type limitAppendable struct {
+	inner storage.Appendable
+
+	mut   sync.Mutex
+	slots chan struct{} // Nil when scrapes aren't limited.
+}
+
+var _ storage.Appendable = (*limitAppendable)(nil)
+
+func newLimitAppendable(inner storage.Appendable) *limitAppendable {
+	return &limitAppendable{inner: inner}
+}
+
+// SetLimit sets the maximum number of concurrent scrapes. 0 means no limit.
+// Scrapes in progress release their slot of the previous limit.
+func (a *limitAppendable) SetLimit(limit int) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	switch {
+	case limit <= 0:
+		a.slots = nil
+	case a.slots == nil || cap(a.slots) != limit:
+		a.slots = make(chan struct{}, limit)
+	}
+}
+
+// Appender implements storage.Appendable.
+func (a *limitAppendable) Appender(ctx context.Context) storage.Appender {
+	a.mut.Lock()
+	slots := a.slots
+	a.mut.Unlock()
+
+	if slots == nil {
+		return a.inner.Appender(ctx)
+	}
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		// The scrape loop is stopping, don't hold it back.
+		return a.inner.Appender(ctx)
+	}
+
+	var once sync.Once
+	return &limitAppender{
+		Appender: a.inner.Appender(ctx),
+		release:  func() { once.Do(func() { <-slots }) },
+	}
+}
+
+type limitAppender struct {
+	storage.Appender
+	release func()
+}
+
+// Commit implements storage.Appender.
+func (a *limitAppender) Commit() error {
+	defer a.release()
+	return a.Appender.Commit()
+}
+
+// Rollback implements storage.Appender.
+func (a *limitAppender) Rollback() error {
+	defer a.release()
+	return a.Appender.Rollback()
+}
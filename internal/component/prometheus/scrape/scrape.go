@@ -18,6 +18,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/grafana/alloy/internal/component"
 	component_config "github.com/grafana/alloy/internal/component/common/config"
@@ -25,6 +26,7 @@ import (
 	"github.com/grafana/alloy/internal/component/prometheus"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/clocksync"
 	"github.com/grafana/alloy/internal/service/cluster"
 	"github.com/grafana/alloy/internal/service/http"
 	"github.com/grafana/alloy/internal/service/labelstore"
@@ -65,6 +67,9 @@ type Arguments struct {
 	HonorTimestamps bool `alloy:"honor_timestamps,attr,optional"`
 	// Indicator whether to track the staleness of the scraped timestamps.
 	TrackTimestampsStaleness bool `alloy:"track_timestamps_staleness,attr,optional"`
+	// Indicator whether staleness markers should be dropped instead of being
+	// forwarded.
+	DisableStalenessMarkers bool `alloy:"disable_staleness_markers,attr,optional"`
 	// A set of query parameters with which the target is scraped.
 	Params url.Values `alloy:"params,attr,optional"`
 	// Whether to scrape a classic histogram that is also exposed as a native histogram.
@@ -100,6 +105,16 @@ type Arguments struct {
 	// More than this label value length post metric-relabeling will cause the
 	// scrape to fail.
 	LabelValueLengthLimit uint `alloy:"label_value_length_limit,attr,optional"`
+	// More than this many exemplars per second across all targets will be
+	// dropped before being forwarded. 0 means no limit.
+	MaxExemplarsPerSecond float64 `alloy:"max_exemplars_per_second,attr,optional"`
+
+	// A seed mixed into the hash-based offsets which spread the scrapes of the
+	// targets over the scrape interval.
+	ScrapeOffsetSeed string `alloy:"scrape_offset_seed,attr,optional"`
+	// More than this many concurrent scrapes will make the next scrapes wait
+	// for one to complete. 0 means no limit.
+	MaxConcurrentScrapes int `alloy:"max_concurrent_scrapes,attr,optional"`
 
 	HTTPClientConfig component_config.HTTPClientConfig `alloy:",squash"`
 
@@ -135,6 +150,18 @@ func (arg *Arguments) Validate() error {
 		return fmt.Errorf("scrape_timeout (%s) greater than scrape_interval (%s) for scrape config with job name %q", arg.ScrapeTimeout, arg.ScrapeInterval, arg.JobName)
 	}
 
+	if arg.TrackTimestampsStaleness && arg.DisableStalenessMarkers {
+		return fmt.Errorf("track_timestamps_staleness and disable_staleness_markers can't be set at the same time")
+	}
+
+	if arg.MaxExemplarsPerSecond < 0 {
+		return fmt.Errorf("max_exemplars_per_second must not be negative and is %v", arg.MaxExemplarsPerSecond)
+	}
+
+	if arg.MaxConcurrentScrapes < 0 {
+		return fmt.Errorf("max_concurrent_scrapes must not be negative and is %d", arg.MaxConcurrentScrapes)
+	}
+
 	if arg.EnableProtobufNegotiation {
 		// Check if scrape_protocols is set to anything other than default and error if it is. We do not allow combining
 		// the enable_protobuf_negotiation and scrape_protocols options.
@@ -175,6 +202,7 @@ func convertScrapeProtocols(promProtocols []config.ScrapeProtocol) []string {
 type Component struct {
 	opts    component.Options
 	cluster cluster.Cluster
+	clock   clocksync.Monitor
 
 	reloadTargets       chan struct{}
 	targetsGauge        client_prometheus.Gauge
@@ -185,13 +213,15 @@ type Component struct {
 	args       Arguments
 	scraper    *scrape.Manager
 	appendable *prometheus.Fanout
+	limiter    *limitAppendable
 
 	dtMutex            sync.Mutex
 	distributedTargets *discovery.DistributedTargets
 }
 
 var (
-	_ component.Component = (*Component)(nil)
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
 )
 
 // New creates a new prometheus.scrape component.
@@ -222,8 +252,17 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		},
 	}
 
+	tracer := o.Tracer
+	if tracer == nil {
+		tracer = noop.NewTracerProvider()
+	}
+
+	// The limiter wraps the tracing appendable so that the time spent waiting
+	// for a scrape slot isn't traced as part of the scrape.
+	limiter := newLimitAppendable(newTracingAppendable(alloyAppendable, tracer))
+
 	unregisterer := util.WrapWithUnregisterer(o.Registerer)
-	scraper, err := scrape.NewManager(scrapeOptions, o.Logger, alloyAppendable, unregisterer)
+	scraper, err := scrape.NewManager(scrapeOptions, o.Logger, limiter, unregisterer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scrape manager: %w", err)
 	}
@@ -247,9 +286,11 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:                o,
 		cluster:             clusterData,
+		clock:               clocksync.GetMonitor(o),
 		reloadTargets:       make(chan struct{}, 1),
 		scraper:             scraper,
 		appendable:          alloyAppendable,
+		limiter:             limiter,
 		targetsGauge:        targetsGauge,
 		movedTargetsCounter: movedTargetsCounter,
 		unregisterer:        unregisterer,
@@ -305,7 +346,9 @@ func (c *Component) Run(ctx context.Context) error {
 			// Prometheus handles marking series as stale: it is the client's responsibility to inject the
 			// staleness markers. In our case, for targets that moved to another instance in the cluster, we hand
 			// over this responsibility to the new owning instance. We must not inject staleness marker here.
-			c.scraper.DisableEndOfRunStalenessMarkers(jobName, movedTargets)
+			for setName, setMovedTargets := range movedTargets {
+				c.scraper.DisableEndOfRunStalenessMarkers(setName, setMovedTargets)
+			}
 
 			select {
 			case targetSetsChan <- newTargetGroups:
@@ -320,7 +363,7 @@ func (c *Component) distributeTargets(
 	targets []discovery.Target,
 	jobName string,
 	args Arguments,
-) (map[string][]*targetgroup.Group, []*scrape.Target) {
+) (map[string][]*targetgroup.Group, map[string][]*scrape.Target) {
 	var (
 		newDistTargets        = discovery.NewDistributedTargets(args.Clustering.Enabled, c.cluster, targets)
 		oldDistributedTargets *discovery.DistributedTargets
@@ -332,7 +375,7 @@ func (c *Component) distributeTargets(
 
 	newLocalTargets := newDistTargets.LocalTargets()
 	c.targetsGauge.Set(float64(len(newLocalTargets)))
-	promNewTargets := c.componentTargetsToPromTargetGroups(jobName, newLocalTargets)
+	promNewTargets := c.componentTargetsToPromTargetGroups(jobName, newLocalTargets, args)
 
 	movedTargets := newDistTargets.MovedToRemoteInstance(oldDistributedTargets)
 	c.movedTargetsCounter.Add(float64(len(movedTargets)))
@@ -353,10 +396,13 @@ func (c *Component) Update(args component.Arguments) error {
 	c.args = newArgs
 
 	c.appendable.UpdateChildren(newArgs.ForwardTo)
+	c.appendable.SetExemplarLimit(newArgs.MaxExemplarsPerSecond)
+	c.appendable.SetDropStaleMarkers(newArgs.DisableStalenessMarkers)
+	c.limiter.SetLimit(newArgs.MaxConcurrentScrapes)
 
-	sc := getPromScrapeConfigs(c.opts.ID, newArgs)
 	err := c.scraper.ApplyConfig(&config.Config{
-		ScrapeConfigs: []*config.ScrapeConfig{sc},
+		GlobalConfig:  offsetSeedConfig(newArgs.ScrapeOffsetSeed),
+		ScrapeConfigs: getPromScrapeConfigsWithLimits(c.opts.ID, newArgs),
 	})
 	if err != nil {
 		return fmt.Errorf("error applying scrape configs: %w", err)
@@ -387,6 +433,19 @@ func (c *Component) NotifyClusterChange() {
 	}
 }
 
+// offsetSeedConfig returns the global config setting the offset seed of the
+// scrape manager to seed. The scrape manager only uses the external labels of
+// the global config to compute the seed of the offsets of the targets, along
+// with the hostname.
+func offsetSeedConfig(seed string) config.GlobalConfig {
+	if seed == "" {
+		return config.GlobalConfig{}
+	}
+	return config.GlobalConfig{
+		ExternalLabels: labels.FromStrings("__scrape_offset_seed__", seed),
+	}
+}
+
 // Helper function to bridge the in-house configuration with the Prometheus
 // scrape_config.
 // As explained in the Config struct, the following fields are purposefully
@@ -472,6 +531,13 @@ func BuildTargetStatuses(targets map[string][]*scrape.Target) []TargetStatus {
 	return res
 }
 
+// CurrentHealth implements component.HealthComponent. The component is
+// unhealthy when the skew of the local clock exceeds the maximum skew of the
+// clocksync block, since the samples are timestamped with the local clock.
+func (c *Component) CurrentHealth() component.Health {
+	return c.clock.Health()
+}
+
 // DebugInfo implements component.DebugComponent
 func (c *Component) DebugInfo() interface{} {
 	return ScraperStatus{
@@ -479,28 +545,70 @@ func (c *Component) DebugInfo() interface{} {
 	}
 }
 
-func (c *Component) componentTargetsToPromTargetGroups(jobName string, tgs []discovery.Target) map[string][]*targetgroup.Group {
-	promGroup := &targetgroup.Group{Source: jobName}
+// componentTargetsToPromTargetGroups returns the target groups of the scrape
+// pools of the targets. Targets overriding the limits of the arguments are
+// scraped by the scrape pool of their limits, with the job label of jobName.
+func (c *Component) componentTargetsToPromTargetGroups(jobName string, tgs []discovery.Target, args Arguments) map[string][]*targetgroup.Group {
+	// Every scrape pool gets a target group, so that the pools whose targets
+	// are all gone stop scraping them.
+	groups := make(map[string]*targetgroup.Group)
+	for _, sc := range getPromScrapeConfigsWithLimits(jobName, args) {
+		groups[sc.JobName] = &targetgroup.Group{Source: sc.JobName}
+	}
+
+	defaults := argumentsLimits(args)
 	for _, tg := range tgs {
-		promGroup.Targets = append(promGroup.Targets, convertLabelSet(tg))
+		lset := convertLabelSet(tg)
+
+		setName := jobName
+		limits, err := limitsOf(tg, args)
+		if err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "scraping target with the limits of the arguments", "target", lset.String(), "err", err)
+		} else if limits != defaults {
+			setName = limits.jobName(jobName)
+			if _, ok := lset[model.JobLabel]; !ok {
+				lset[model.JobLabel] = model.LabelValue(jobName)
+			}
+		}
+		if groups[setName] == nil {
+			// Targets which moved to another instance may no longer be
+			// part of the arguments.
+			groups[setName] = &targetgroup.Group{Source: setName}
+		}
+		groups[setName].Targets = append(groups[setName].Targets, lset)
 	}
 
-	return map[string][]*targetgroup.Group{jobName: {promGroup}}
+	res := make(map[string][]*targetgroup.Group, len(groups))
+	for setName, group := range groups {
+		res[setName] = []*targetgroup.Group{group}
+	}
+	return res
 }
 
-func (c *Component) populatePromLabels(targets []discovery.Target, jobName string, args Arguments) []*scrape.Target {
-	lb := labels.NewBuilder(labels.EmptyLabels())
-	promTargets, errs := scrape.TargetsFromGroup(
-		c.componentTargetsToPromTargetGroups(jobName, targets)[jobName][0],
-		getPromScrapeConfigs(c.opts.ID, args),
-		false,                                /* noDefaultScrapePort - always false in this component */
-		make([]*scrape.Target, len(targets)), /* targets slice to reuse */
-		lb,
+func (c *Component) populatePromLabels(targets []discovery.Target, jobName string, args Arguments) map[string][]*scrape.Target {
+	var (
+		lb  = labels.NewBuilder(labels.EmptyLabels())
+		res = make(map[string][]*scrape.Target)
 	)
-	for _, err := range errs {
-		level.Warn(c.opts.Logger).Log("msg", "error while populating labels of targets using prom config", "err", err)
+	groups := c.componentTargetsToPromTargetGroups(jobName, targets, args)
+	for _, sc := range getPromScrapeConfigsWithLimits(jobName, args) {
+		group := groups[sc.JobName][0]
+		if len(group.Targets) == 0 {
+			continue
+		}
+		promTargets, errs := scrape.TargetsFromGroup(
+			group,
+			sc,
+			false,                                /* noDefaultScrapePort - always false in this component */
+			make([]*scrape.Target, len(targets)), /* targets slice to reuse */
+			lb,
+		)
+		for _, err := range errs {
+			level.Warn(c.opts.Logger).Log("msg", "error while populating labels of targets using prom config", "err", err)
+		}
+		res[sc.JobName] = promTargets
 	}
-	return promTargets
+	return res
 }
 
 func convertLabelSet(tg discovery.Target) model.LabelSet {
@@ -11,12 +11,17 @@ import (
 	"github.com/grafana/ckit/memconn"
 	prometheus_client "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/grafana/alloy/internal/component"
 	component_config "github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/component/prometheus"
 	"github.com/grafana/alloy/internal/service/cluster"
 	http_service "github.com/grafana/alloy/internal/service/http"
@@ -63,6 +68,7 @@ func TestDefaults(t *testing.T) {
 	require.Equal(t, false, args.HonorLabels)
 	require.Equal(t, true, args.HonorTimestamps)
 	require.Equal(t, false, args.TrackTimestampsStaleness)
+	require.Equal(t, false, args.DisableStalenessMarkers)
 	require.Equal(t, component_config.DefaultHTTPClientConfig, args.HTTPClientConfig)
 	require.Equal(t, time.Minute, args.ScrapeInterval)
 	require.Equal(t, time.Second*10, args.ScrapeTimeout)
@@ -185,6 +191,129 @@ func TestForwardingToAppendable(t *testing.T) {
 	require.Equal(t, receivedSamples, sample)
 }
 
+func TestTracingAppendable(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ls := labelstore.New(nil, prometheus_client.NewRegistry())
+	appendable := newTracingAppendable(prometheus.NewInterceptor(nil, ls), tp)
+
+	// A successful scrape.
+	app := appendable.Appender(context.Background())
+	_, err := app.Append(0, labels.FromStrings("__name__", "up"), 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// A scrape of a target which is down.
+	app = appendable.Appender(context.Background())
+	_, err = app.Append(0, labels.FromStrings("__name__", "up"), 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// A scrape which is rolled back.
+	app = appendable.Appender(context.Background())
+	require.NoError(t, app.Rollback())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	for _, span := range spans {
+		require.Equal(t, "Scrape", span.Name())
+	}
+	require.Equal(t, codes.Ok, spans[0].Status().Code)
+	require.Equal(t, sdktrace.Status{Code: codes.Error, Description: "target is down"}, spans[1].Status())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestLimitAppendable(t *testing.T) {
+	ls := labelstore.New(nil, prometheus_client.NewRegistry())
+	appendable := newLimitAppendable(prometheus.NewInterceptor(nil, ls))
+	appendable.SetLimit(2)
+
+	first := appendable.Appender(context.Background())
+	second := appendable.Appender(context.Background())
+
+	// A third scrape waits for one of the first two to complete.
+	acquired := make(chan storage.Appender)
+	go func() { acquired <- appendable.Appender(context.Background()) }()
+	select {
+	case <-acquired:
+		t.Fatal("third scrape should wait for a slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Commit())
+	var third storage.Appender
+	select {
+	case third = <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("third scrape should get the released slot")
+	}
+
+	// Slots are released once, even if the appender is committed and rolled
+	// back.
+	require.NoError(t, second.Rollback())
+	require.NoError(t, second.Commit())
+	require.NoError(t, third.Commit())
+	require.Empty(t, appendable.slots)
+
+	// Stopping scrape loops don't wait for a slot.
+	appendable.SetLimit(1)
+	held := appendable.Appender(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, appendable.Appender(ctx).Commit())
+	require.NoError(t, held.Commit())
+
+	// Removing the limit doesn't block scrapes.
+	appendable.SetLimit(0)
+	for i := 0; i < 10; i++ {
+		appendable.Appender(context.Background())
+	}
+}
+
+func TestTargetLimits(t *testing.T) {
+	var args Arguments
+	args.SetToDefault()
+	args.SampleLimit = 100
+	args.Targets = []discovery.Target{
+		{"__address__": "a:80"},
+		{"__address__": "b:80", "__sample_limit__": "1000"},
+		{"__address__": "c:80", "__sample_limit__": "1000", "job": "custom"},
+		{"__address__": "d:80", "__sample_limit__": "100"},
+		{"__address__": "e:80", "__sample_limit__": "many"},
+	}
+
+	// Targets overriding the limits of the arguments get a scrape pool per
+	// distinct set of limits.
+	overrideJob := targetLimits{SampleLimit: 1000}.jobName("job")
+	configs := getPromScrapeConfigsWithLimits("job", args)
+	require.Len(t, configs, 2)
+	require.Equal(t, "job", configs[0].JobName)
+	require.Equal(t, uint(100), configs[0].SampleLimit)
+	require.Equal(t, overrideJob, configs[1].JobName)
+	require.Equal(t, uint(1000), configs[1].SampleLimit)
+
+	c := &Component{opts: component.Options{Logger: util.TestAlloyLogger(t)}}
+	groups := c.componentTargetsToPromTargetGroups("job", args.Targets, args)
+	require.Len(t, groups, 2)
+	require.Equal(t, []model.LabelSet{
+		{"__address__": "a:80"},
+		{"__address__": "d:80", "__sample_limit__": "100"},
+		{"__address__": "e:80", "__sample_limit__": "many"},
+	}, groups["job"][0].Targets)
+	// The targets of the additional scrape pools keep the job of the
+	// arguments.
+	require.Equal(t, []model.LabelSet{
+		{"__address__": "b:80", "__sample_limit__": "1000", "job": "job"},
+		{"__address__": "c:80", "__sample_limit__": "1000", "job": "custom"},
+	}, groups[overrideJob][0].Targets)
+
+	// Scrape pools whose targets are gone get an empty target group.
+	groups = c.componentTargetsToPromTargetGroups("job", args.Targets[:1], args)
+	require.Len(t, groups, 2)
+	require.Empty(t, groups[overrideJob][0].Targets)
+}
+
 // TestCustomDialer ensures that prometheus.scrape respects the custom dialer
 // given to it.
 func TestCustomDialer(t *testing.T) {
@@ -267,3 +396,15 @@ func TestValidateScrapeConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.ErrorContains(t, err, "scrape_timeout (20s) greater than scrape_interval (10s) for scrape config with job name \"local\"")
 }
+
+func TestValidateStalenessMarkers(t *testing.T) {
+	var exampleAlloyConfig = `
+	targets                    = [{ "target1" = "target1" }]
+	forward_to                 = []
+	track_timestamps_staleness = true
+	disable_staleness_markers  = true
+`
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.ErrorContains(t, err, "track_timestamps_staleness and disable_staleness_markers can't be set at the same time")
+}
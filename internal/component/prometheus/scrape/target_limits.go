@@ -0,0 +1,109 @@
+package scrape
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/alecthomas/units"
+	"github.com/prometheus/prometheus/config"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+)
+
+// Labels of a target which override the limits of the arguments for the
+// scrapes of this target.
+const (
+	bodySizeLimitLabel = "__body_size_limit__"
+	sampleLimitLabel   = "__sample_limit__"
+	labelLimitLabel    = "__label_limit__"
+)
+
+// targetLimits are the limits of the scrapes of a target.
+//
+// The limits of the scrape loops are set by their scrape pool, so targets
+// overriding the limits of the arguments are scraped by an additional scrape
+// pool per distinct set of limits.
+type targetLimits struct {
+	BodySizeLimit units.Base2Bytes
+	SampleLimit   uint
+	LabelLimit    uint
+}
+
+func argumentsLimits(args Arguments) targetLimits {
+	return targetLimits{
+		BodySizeLimit: args.BodySizeLimit,
+		SampleLimit:   args.SampleLimit,
+		LabelLimit:    args.LabelLimit,
+	}
+}
+
+// limitsOf returns the limits of the scrapes of target, which are the limits
+// of the arguments overridden by the limit labels of the target.
+func limitsOf(target discovery.Target, args Arguments) (targetLimits, error) {
+	limits := argumentsLimits(args)
+
+	if v, ok := target[bodySizeLimitLabel]; ok {
+		limit, err := units.ParseBase2Bytes(v)
+		if err != nil || limit < 0 {
+			return argumentsLimits(args), fmt.Errorf("invalid %s label value %q", bodySizeLimitLabel, v)
+		}
+		limits.BodySizeLimit = limit
+	}
+	for name, limit := range map[string]*uint{
+		sampleLimitLabel: &limits.SampleLimit,
+		labelLimitLabel:  &limits.LabelLimit,
+	} {
+		v, ok := target[name]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(v, 10, 0)
+		if err != nil {
+			return argumentsLimits(args), fmt.Errorf("invalid %s label value %q", name, v)
+		}
+		*limit = uint(n)
+	}
+	return limits, nil
+}
+
+// jobName returns the name of the scrape pool of the targets of jobName
+// scraped with limits l.
+func (l targetLimits) jobName(jobName string) string {
+	return fmt.Sprintf("%s/limits{body_size=%s,sample=%d,label=%d}", jobName, l.BodySizeLimit, l.SampleLimit, l.LabelLimit)
+}
+
+// getPromScrapeConfigsWithLimits returns the scrape config of the arguments,
+// followed by the scrape configs of the scrape pools of the targets which
+// override its limits.
+func getPromScrapeConfigsWithLimits(jobName string, args Arguments) []*config.ScrapeConfig {
+	sc := getPromScrapeConfigs(jobName, args)
+	defaults := argumentsLimits(args)
+
+	seen := map[targetLimits]struct{}{defaults: {}}
+	var overrides []targetLimits
+	for _, target := range args.Targets {
+		// Targets with invalid limit labels are scraped with the limits of the
+		// arguments.
+		limits, _ := limitsOf(target, args)
+		if _, ok := seen[limits]; ok {
+			continue
+		}
+		seen[limits] = struct{}{}
+		overrides = append(overrides, limits)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].jobName(sc.JobName) < overrides[j].jobName(sc.JobName)
+	})
+
+	res := []*config.ScrapeConfig{sc}
+	for _, limits := range overrides {
+		osc := *sc
+		osc.JobName = limits.jobName(sc.JobName)
+		osc.BodySizeLimit = limits.BodySizeLimit
+		osc.SampleLimit = limits.SampleLimit
+		osc.LabelLimit = limits.LabelLimit
+		res = append(res, &osc)
+	}
+	return res
+}
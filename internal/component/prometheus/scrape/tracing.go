@@ -0,0 +1,81 @@
+package scrape
+
+import (
+	"context"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingAppendable traces the scrapes of the scrape loops. Scrape loops get
+// one appender per scrape before sending the request to the target, and
+// commit it once the samples are appended, so the lifetime of an appender is
+// the duration of a scrape.
+type tracingAppendable struct {
+	inner  storage.Appendable
+	tracer trace.Tracer
+}
+
+var _ storage.Appendable = (*tracingAppendable)(nil)
+
+func newTracingAppendable(inner storage.Appendable, tp trace.TracerProvider) *tracingAppendable {
+	return &tracingAppendable{
+		inner:  inner,
+		tracer: tp.Tracer("prometheus.scrape"),
+	}
+}
+
+// Appender implements storage.Appendable.
+func (a *tracingAppendable) Appender(ctx context.Context) storage.Appender {
+	ctx, span := a.tracer.Start(ctx, "Scrape", trace.WithSpanKind(trace.SpanKindInternal))
+	if target, ok := scrape.TargetFromContext(ctx); ok {
+		span.SetAttributes(attribute.String("target", target.String()))
+	}
+	return &tracingAppender{Appender: a.inner.Appender(ctx), span: span}
+}
+
+type tracingAppender struct {
+	storage.Appender
+	span trace.Span
+	down bool // Whether the scrape reported the target as down.
+}
+
+// Append implements storage.Appender.
+func (a *tracingAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	// Scrape loops report failed scrapes with an up series set to 0.
+	if v == 0 && l.Get(model.MetricNameLabel) == "up" {
+		a.down = true
+	}
+	return a.Appender.Append(ref, l, t, v)
+}
+
+// Commit implements storage.Appender.
+func (a *tracingAppender) Commit() error {
+	defer a.span.End()
+
+	err := a.Appender.Commit()
+	switch {
+	case err != nil:
+		a.span.SetStatus(codes.Error, err.Error())
+	case a.down:
+		a.span.SetStatus(codes.Error, "target is down")
+	default:
+		a.span.SetStatus(codes.Ok, "")
+	}
+	return err
+}
+
+// Rollback implements storage.Appender.
+func (a *tracingAppender) Rollback() error {
+	defer a.span.End()
+
+	// Scrape loops roll back the appender when the samples of the target can't
+	// be appended, such as when the sample limit is exceeded.
+	a.span.SetStatus(codes.Error, "scrape rolled back")
+	return a.Appender.Rollback()
+}
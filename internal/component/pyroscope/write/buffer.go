@@ -0,0 +1,270 @@
+package write
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// bufferFileExt is the extension of the files holding buffered requests.
+const bufferFileExt = ".profiles"
+
+// diskBuffer stores the push requests which couldn't be sent to an endpoint on
+// disk, so they can be sent again once the endpoint is available. Each request
+// is stored in its own file, named after the time it was buffered.
+type diskBuffer struct {
+	dir      string
+	endpoint string
+	logger   log.Logger
+	metrics  *metrics
+
+	mut     sync.Mutex
+	maxSize int64
+	maxAge  time.Duration
+	size    int64
+	seq     uint64
+}
+
+// bufferEntry is a buffered request.
+type bufferEntry struct {
+	name     string
+	buffered time.Time
+	size     int64
+}
+
+// bufferDir returns the directory of the buffer of an endpoint.
+func bufferDir(dataPath string, endpoint *EndpointOptions) string {
+	sum := sha256.Sum256([]byte(endpoint.Name + "\x00" + endpoint.URL))
+	return filepath.Join(dataPath, "buffer", hex.EncodeToString(sum[:8]))
+}
+
+func newDiskBuffer(dir string, endpoint string, logger log.Logger, metrics *metrics) (*diskBuffer, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	b := &diskBuffer{
+		dir:      dir,
+		endpoint: endpoint,
+		logger:   logger,
+		metrics:  metrics,
+	}
+
+	// Remove the files of requests which were being written when the process
+	// stopped.
+	tmpFiles, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range tmpFiles {
+		_ = os.Remove(f)
+	}
+
+	entries, err := b.entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		b.size += e.size
+	}
+	b.metrics.bufferBytes.WithLabelValues(endpoint).Set(float64(b.size))
+	return b, nil
+}
+
+// setLimits updates the maximum size and age of the buffered requests.
+func (b *diskBuffer) setLimits(maxSize int64, maxAge time.Duration) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	b.maxSize = maxSize
+	b.maxAge = maxAge
+}
+
+// write buffers a request. The oldest requests are dropped to make room for it
+// if the buffer is full.
+func (b *diskBuffer) write(tenantID string, req *pushv1.PushRequest) error {
+	data, err := encodeBufferedRequest(tenantID, req)
+	if err != nil {
+		return err
+	}
+
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	size := int64(len(data))
+	if size > b.maxSize {
+		return fmt.Errorf("request of %d bytes is larger than the buffer", size)
+	}
+	if b.size+size > b.maxSize {
+		entries, err := b.entries()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if b.size+size <= b.maxSize {
+				break
+			}
+			b.drop(e, "buffer is full")
+		}
+	}
+
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), b.seq%1_000_000, bufferFileExt)
+	b.seq++
+	path := filepath.Join(b.dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0o640); err != nil {
+		_ = os.Remove(path + ".tmp")
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		_ = os.Remove(path + ".tmp")
+		return err
+	}
+
+	b.size += size
+	b.metrics.bufferBytes.WithLabelValues(b.endpoint).Set(float64(b.size))
+	_, profiles := requestSize(req)
+	b.metrics.bufferedProfiles.WithLabelValues(b.endpoint).Add(float64(profiles))
+	return nil
+}
+
+// replay calls send with the buffered requests, oldest first. The requests are
+// removed from the buffer once send succeeds. replay stops at the first
+// failure of send and returns its error, the remaining requests are kept in
+// the buffer. The requests older than the maximum age are dropped.
+func (b *diskBuffer) replay(send func(tenantID string, req *pushv1.PushRequest) error) error {
+	b.mut.Lock()
+	entries, err := b.entries()
+	maxAge := b.maxAge
+	b.mut.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if time.Since(e.buffered) > maxAge {
+			b.mut.Lock()
+			b.drop(e, "request is too old")
+			b.mut.Unlock()
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(b.dir, e.name))
+		if errors.Is(err, os.ErrNotExist) {
+			// The request was dropped to make room for a new one.
+			continue
+		} else if err != nil {
+			return err
+		}
+		tenantID, req, err := decodeBufferedRequest(data)
+		if err != nil {
+			level.Warn(b.logger).Log("msg", "removing invalid buffered request", "endpoint", b.endpoint, "file", e.name, "err", err)
+			b.mut.Lock()
+			b.remove(e)
+			b.mut.Unlock()
+			continue
+		}
+
+		if err := send(tenantID, req); err != nil {
+			return err
+		}
+		b.mut.Lock()
+		b.remove(e)
+		b.mut.Unlock()
+	}
+	return nil
+}
+
+// entries returns the buffered requests, oldest first. b.mut must be held.
+func (b *diskBuffer) entries() ([]bufferEntry, error) {
+	files, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// os.ReadDir sorts the files by name, which starts with the time the
+	// request was buffered.
+	entries := make([]bufferEntry, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != bufferFileExt {
+			continue
+		}
+		timestamp, _, _ := strings.Cut(f.Name(), "-")
+		nanos, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, bufferEntry{
+			name:     f.Name(),
+			buffered: time.Unix(0, nanos),
+			size:     info.Size(),
+		})
+	}
+	return entries, nil
+}
+
+// drop removes a buffered request which won't be sent. b.mut must be held.
+func (b *diskBuffer) drop(e bufferEntry, reason string) {
+	data, err := os.ReadFile(filepath.Join(b.dir, e.name))
+	if err != nil {
+		// The request was already sent.
+		return
+	}
+	if !b.remove(e) {
+		return
+	}
+
+	level.Warn(b.logger).Log("msg", "dropping buffered profiles", "endpoint", b.endpoint, "reason", reason)
+	if _, req, err := decodeBufferedRequest(data); err == nil {
+		size, profiles := requestSize(req)
+		b.metrics.droppedBytes.WithLabelValues(b.endpoint).Add(float64(size))
+		b.metrics.droppedProfiles.WithLabelValues(b.endpoint).Add(float64(profiles))
+	}
+}
+
+// remove deletes the file of a buffered request, and reports whether it
+// existed. b.mut must be held.
+func (b *diskBuffer) remove(e bufferEntry) bool {
+	if err := os.Remove(filepath.Join(b.dir, e.name)); err != nil {
+		return false
+	}
+	b.size -= e.size
+	b.metrics.bufferBytes.WithLabelValues(b.endpoint).Set(float64(b.size))
+	return true
+}
+
+// encodeBufferedRequest encodes a request and the tenant it's sent as. The
+// tenant ID is prefixed with its length.
+func encodeBufferedRequest(tenantID string, req *pushv1.PushRequest) ([]byte, error) {
+	data := binary.AppendUvarint(nil, uint64(len(tenantID)))
+	data = append(data, tenantID...)
+	return proto.MarshalOptions{}.MarshalAppend(data, req)
+}
+
+func decodeBufferedRequest(data []byte) (string, *pushv1.PushRequest, error) {
+	n, read := binary.Uvarint(data)
+	if read <= 0 || uint64(len(data)-read) < n {
+		return "", nil, errors.New("invalid tenant ID")
+	}
+	tenantID := string(data[read : read+int(n)])
+
+	req := &pushv1.PushRequest{}
+	if err := proto.Unmarshal(data[read+int(n):], req); err != nil {
+		return "", nil, err
+	}
+	return tenantID, req, nil
+}
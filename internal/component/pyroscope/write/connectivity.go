@@ -0,0 +1,52 @@
+package write
+
+import (
+	"context"
+	"net/url"
+
+	"connectrpc.com/connect"
+	"github.com/grafana/alloy/internal/component"
+	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
+	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
+	commonconfig "github.com/prometheus/common/config"
+)
+
+var _ component.ConnectivityChecker = Arguments{}
+
+// CheckConnectivity implements component.ConnectivityChecker by sending a
+// push request without profiles to every endpoint.
+func (rc Arguments) CheckConnectivity(ctx context.Context) []component.ConnectivityResult {
+	var res []component.ConnectivityResult
+	for _, endpoint := range rc.Endpoints {
+		endpointURL := endpoint.URL
+		if u, err := url.Parse(endpoint.URL); err == nil {
+			endpointURL = u.Redacted()
+		}
+		res = append(res, component.ConnectivityResult{
+			Endpoint: endpointURL,
+			Err:      checkEndpoint(ctx, endpoint),
+		})
+	}
+	return res
+}
+
+func checkEndpoint(ctx context.Context, endpoint *EndpointOptions) error {
+	httpClient, err := commonconfig.NewClientFromConfig(*endpoint.HTTPClientConfig.Convert(), endpoint.Name)
+	if err != nil {
+		return err
+	}
+	client := pushv1connect.NewPusherServiceClient(httpClient, endpoint.URL, WithUserAgent(userAgent))
+
+	req := connect.NewRequest(&pushv1.PushRequest{})
+	for k, v := range endpoint.Headers {
+		req.Header().Set(k, v)
+	}
+	if endpoint.TenantID != "" {
+		req.Header().Set(tenantHeaderName, endpoint.TenantID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, endpoint.RemoteTimeout)
+	defer cancel()
+	_, err = client.Push(ctx, req)
+	return err
+}
@@ -8,6 +8,9 @@ type metrics struct {
 	sentProfiles    *prometheus.CounterVec
 	droppedProfiles *prometheus.CounterVec
 	retries         *prometheus.CounterVec
+
+	bufferedProfiles *prometheus.CounterVec
+	bufferBytes      *prometheus.GaugeVec
 }
 
 func newMetrics(reg prometheus.Registerer) *metrics {
@@ -32,6 +35,14 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			Name: "pyroscope_write_retries_total",
 			Help: "Total number of retries to Pyroscope.",
 		}, []string{"endpoint"}),
+		bufferedProfiles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_write_buffered_profiles_total",
+			Help: "Total number of profiles buffered on disk after failing to be sent to Pyroscope.",
+		}, []string{"endpoint"}),
+		bufferBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pyroscope_write_buffer_bytes",
+			Help: "Size in bytes of the profiles buffered on disk.",
+		}, []string{"endpoint"}),
 	}
 
 	if reg != nil {
@@ -41,6 +52,8 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			m.sentProfiles,
 			m.droppedProfiles,
 			m.retries,
+			m.bufferedProfiles,
+			m.bufferBytes,
 		)
 	}
 
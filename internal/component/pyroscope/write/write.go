@@ -3,11 +3,16 @@ package write
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/alecthomas/units"
 	"github.com/grafana/alloy/internal/alloyseed"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/pyroscope"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
@@ -16,6 +21,7 @@ import (
 	commonconfig "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
 	"go.uber.org/multierr"
 
 	"github.com/grafana/alloy/internal/component"
@@ -29,9 +35,23 @@ import (
 var (
 	userAgent        = useragent.Get()
 	DefaultArguments = func() Arguments {
-		return Arguments{}
+		return Arguments{
+			DiskBuffer: DefaultDiskBufferArguments,
+		}
 	}
 	_ component.Component = (*Component)(nil)
+
+	// externalLabelTemplate matches the references to profile labels in the
+	// values of external labels, for example ${service_name}.
+	externalLabelTemplate = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+)
+
+const (
+	// LabelNameTenantID is the label which sets the tenant a profile series is
+	// pushed to. It's removed from the series before the push.
+	LabelNameTenantID = "__tenant_id__"
+
+	tenantHeaderName = "X-Scope-OrgID"
 )
 
 func init() {
@@ -49,8 +69,10 @@ func init() {
 // Arguments represents the input state of the pyroscope.write
 // component.
 type Arguments struct {
-	ExternalLabels map[string]string  `alloy:"external_labels,attr,optional"`
-	Endpoints      []*EndpointOptions `alloy:"endpoint,block,optional"`
+	ExternalLabels map[string]string       `alloy:"external_labels,attr,optional"`
+	Endpoints      []*EndpointOptions      `alloy:"endpoint,block,optional"`
+	RelabelConfigs []*alloy_relabel.Config `alloy:"rule,block,optional"`
+	DiskBuffer     DiskBufferArguments     `alloy:"disk_buffer,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -58,11 +80,48 @@ func (rc *Arguments) SetToDefault() {
 	*rc = DefaultArguments()
 }
 
+// DiskBufferArguments configures the buffering on disk of the profiles which
+// couldn't be sent to an endpoint.
+type DiskBufferArguments struct {
+	Enabled        bool             `alloy:"enabled,attr,optional"`
+	MaxSize        units.Base2Bytes `alloy:"max_size,attr,optional"`
+	MaxAge         time.Duration    `alloy:"max_age,attr,optional"`
+	ReplayInterval time.Duration    `alloy:"replay_interval,attr,optional"`
+}
+
+// DefaultDiskBufferArguments holds the default settings of the disk buffer.
+var DefaultDiskBufferArguments = DiskBufferArguments{
+	Enabled:        false,
+	MaxSize:        1 * units.GiB,
+	MaxAge:         24 * time.Hour,
+	ReplayInterval: 30 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (b *DiskBufferArguments) SetToDefault() {
+	*b = DefaultDiskBufferArguments
+}
+
+// Validate implements syntax.Validator.
+func (b *DiskBufferArguments) Validate() error {
+	if b.MaxSize <= 0 {
+		return fmt.Errorf("max_size must be greater than 0")
+	}
+	if b.MaxAge <= 0 {
+		return fmt.Errorf("max_age must be greater than 0")
+	}
+	if b.ReplayInterval <= 0 {
+		return fmt.Errorf("replay_interval must be greater than 0")
+	}
+	return nil
+}
+
 // EndpointOptions describes an individual location for where profiles
 // should be delivered to using the Pyroscope push API.
 type EndpointOptions struct {
 	Name              string                   `alloy:"name,attr,optional"`
 	URL               string                   `alloy:"url,attr"`
+	TenantID          string                   `alloy:"tenant_id,attr,optional"`
 	RemoteTimeout     time.Duration            `alloy:"remote_timeout,attr,optional"`
 	Headers           map[string]string        `alloy:"headers,attr,optional"`
 	HTTPClientConfig  *config.HTTPClientConfig `alloy:",squash"`
@@ -101,8 +160,13 @@ func (r *EndpointOptions) Validate() error {
 // Component is the pyroscope.write component.
 type Component struct {
 	opts    component.Options
-	cfg     Arguments
 	metrics *metrics
+	updated chan struct{}
+
+	mut      sync.Mutex
+	cfg      Arguments
+	receiver *fanOutClient
+	buffers  map[string]*diskBuffer // By directory.
 }
 
 // Exports are the set of fields exposed by the pyroscope.write component.
@@ -112,44 +176,109 @@ type Exports struct {
 
 // New creates a new pyroscope.write component.
 func New(o component.Options, c Arguments) (*Component, error) {
-	metrics := newMetrics(o.Registerer)
-	receiver, err := NewFanOut(o, c, metrics)
-	if err != nil {
-		return nil, err
+	res := &Component{
+		opts:    o,
+		metrics: newMetrics(o.Registerer),
+		updated: make(chan struct{}, 1),
+		buffers: make(map[string]*diskBuffer),
 	}
 	// Immediately export the receiver
-	o.OnStateChange(Exports{Receiver: receiver})
-
-	return &Component{
-		cfg:     c,
-		opts:    o,
-		metrics: metrics,
-	}, nil
+	if err := res.update(c); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 var _ component.Component = (*Component)(nil)
 
 // Run implements Component.
 func (c *Component) Run(ctx context.Context) error {
-	<-ctx.Done()
-	return ctx.Err()
+	for {
+		// The profiles buffered on disk are sent again periodically.
+		var replay <-chan time.Time
+		c.mut.Lock()
+		if c.cfg.DiskBuffer.Enabled {
+			replay = time.After(c.cfg.DiskBuffer.ReplayInterval)
+		}
+		c.mut.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.updated:
+		case <-replay:
+			c.mut.Lock()
+			receiver := c.receiver
+			c.mut.Unlock()
+			receiver.replay(ctx)
+		}
+	}
 }
 
 // Update implements Component.
 func (c *Component) Update(newConfig component.Arguments) error {
-	c.cfg = newConfig.(Arguments)
 	level.Debug(c.opts.Logger).Log("msg", "updating pyroscope.write config", "old", c.cfg, "new", newConfig)
-	receiver, err := NewFanOut(c.opts, newConfig.(Arguments), c.metrics)
+	if err := c.update(newConfig.(Arguments)); err != nil {
+		return err
+	}
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *Component) update(args Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	buffers, err := c.updateBuffers(args)
 	if err != nil {
 		return err
 	}
+	receiver, err := NewFanOut(c.opts, args, c.metrics, buffers)
+	if err != nil {
+		return err
+	}
+	c.cfg = args
+	c.receiver = receiver
 	c.opts.OnStateChange(Exports{Receiver: receiver})
 	return nil
 }
 
+// updateBuffers returns the disk buffers of the endpoints, in the order of the
+// endpoints. The buffers are kept across updates so that a buffer is never
+// used by two clients. c.mut must be held.
+func (c *Component) updateBuffers(args Arguments) ([]*diskBuffer, error) {
+	if !args.DiskBuffer.Enabled {
+		return nil, nil
+	}
+
+	buffers := make([]*diskBuffer, 0, len(args.Endpoints))
+	for _, endpoint := range args.Endpoints {
+		dir := bufferDir(c.opts.DataPath, endpoint)
+		buffer, ok := c.buffers[dir]
+		if !ok {
+			var err error
+			buffer, err = newDiskBuffer(dir, endpoint.URL, c.opts.Logger, c.metrics)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create the disk buffer of endpoint %s: %w", endpoint.URL, err)
+			}
+			c.buffers[dir] = buffer
+		}
+		buffer.setLimits(int64(args.DiskBuffer.MaxSize), args.DiskBuffer.MaxAge)
+		buffers = append(buffers, buffer)
+	}
+	return buffers, nil
+}
+
 type fanOutClient struct {
 	// The list of push clients to fan out to.
 	clients []pushv1connect.PusherServiceClient
+	// The relabeling rules applied to the profile series before the push.
+	relabelConfigs []*relabel.Config
+	// The disk buffers of the endpoints, nil if buffering is disabled.
+	buffers []*diskBuffer
 
 	config  Arguments
 	opts    component.Options
@@ -157,7 +286,7 @@ type fanOutClient struct {
 }
 
 // NewFanOut creates a new fan out client that will fan out to all endpoints.
-func NewFanOut(opts component.Options, config Arguments, metrics *metrics) (*fanOutClient, error) {
+func NewFanOut(opts component.Options, config Arguments, metrics *metrics, buffers []*diskBuffer) (*fanOutClient, error) {
 	clients := make([]pushv1connect.PusherServiceClient, 0, len(config.Endpoints))
 	uid := alloyseed.Get().UID
 	for _, endpoint := range config.Endpoints {
@@ -173,10 +302,12 @@ func NewFanOut(opts component.Options, config Arguments, metrics *metrics) (*fan
 		clients = append(clients, pushv1connect.NewPusherServiceClient(httpClient, endpoint.URL, WithUserAgent(userAgent)))
 	}
 	return &fanOutClient{
-		clients: clients,
-		config:  config,
-		opts:    opts,
-		metrics: metrics,
+		clients:        clients,
+		relabelConfigs: alloy_relabel.ComponentToPromRelabelConfigs(config.RelabelConfigs),
+		buffers:        buffers,
+		config:         config,
+		opts:           opts,
+		metrics:        metrics,
 	}, nil
 }
 
@@ -187,7 +318,8 @@ func (f *fanOutClient) Push(ctx context.Context, req *connect.Request[pushv1.Pus
 	var (
 		g                     run.Group
 		errs                  error
-		reqSize, profileCount = requestSize(req)
+		reqSize, profileCount = requestSize(req.Msg)
+		tenantID              = req.Header().Get(tenantHeaderName)
 	)
 
 	for i, client := range f.clients {
@@ -202,10 +334,7 @@ func (f *fanOutClient) Push(ctx context.Context, req *connect.Request[pushv1.Pus
 			err error
 		)
 		g.Add(func() error {
-			req := connect.NewRequest(req.Msg)
-			for k, v := range f.config.Endpoints[i].Headers {
-				req.Header().Set(k, v)
-			}
+			req := f.endpointRequest(i, req.Msg, tenantID)
 			for {
 				err = func() error {
 					ctx, cancel := context.WithTimeout(ctx, f.config.Endpoints[i].RemoteTimeout)
@@ -229,6 +358,16 @@ func (f *fanOutClient) Push(ctx context.Context, req *connect.Request[pushv1.Pus
 				}
 				f.metrics.retries.WithLabelValues(f.config.Endpoints[i].URL).Inc()
 			}
+			if err != nil && f.buffers != nil && shouldRetry(err) {
+				// Keep the profiles on disk until the endpoint is available again.
+				bufferErr := f.buffers[i].write(tenantID, req.Msg)
+				if bufferErr == nil {
+					level.Warn(f.opts.Logger).Log("msg", "buffered profiles on disk after failing to push to endpoint", "endpoint", f.config.Endpoints[i].URL, "err", err)
+					err = nil
+				} else {
+					level.Warn(f.opts.Logger).Log("msg", "failed to buffer profiles on disk", "endpoint", f.config.Endpoints[i].URL, "err", bufferErr)
+				}
+			}
 			if err != nil {
 				f.metrics.droppedBytes.WithLabelValues(f.config.Endpoints[i].URL).Add(float64(reqSize))
 				f.metrics.droppedProfiles.WithLabelValues(f.config.Endpoints[i].URL).Add(float64(profileCount))
@@ -247,6 +386,54 @@ func (f *fanOutClient) Push(ctx context.Context, req *connect.Request[pushv1.Pus
 	return connect.NewResponse(&pushv1.PushResponse{}), nil
 }
 
+// endpointRequest returns the request to push a message to an endpoint. The
+// tenant of the series takes precedence over the tenant of the endpoint.
+func (f *fanOutClient) endpointRequest(i int, msg *pushv1.PushRequest, tenantID string) *connect.Request[pushv1.PushRequest] {
+	endpoint := f.config.Endpoints[i]
+	if tenantID == "" {
+		tenantID = endpoint.TenantID
+	}
+	req := connect.NewRequest(msg)
+	for k, v := range endpoint.Headers {
+		req.Header().Set(k, v)
+	}
+	if tenantID != "" {
+		req.Header().Set(tenantHeaderName, tenantID)
+	}
+	return req
+}
+
+// replay sends the profiles buffered on disk to their endpoints. The replay to
+// an endpoint stops at the first failure, the remaining profiles are sent at
+// the next replay.
+func (f *fanOutClient) replay(ctx context.Context) {
+	for i, buffer := range f.buffers {
+		endpoint := f.config.Endpoints[i]
+		err := buffer.replay(func(tenantID string, msg *pushv1.PushRequest) error {
+			ctx, cancel := context.WithTimeout(ctx, endpoint.RemoteTimeout)
+			defer cancel()
+
+			size, profiles := requestSize(msg)
+			_, err := f.clients[i].Push(ctx, f.endpointRequest(i, msg, tenantID))
+			if err != nil && shouldRetry(err) {
+				return err
+			}
+			if err != nil {
+				f.metrics.droppedBytes.WithLabelValues(endpoint.URL).Add(float64(size))
+				f.metrics.droppedProfiles.WithLabelValues(endpoint.URL).Add(float64(profiles))
+				level.Warn(f.opts.Logger).Log("msg", "dropping buffered profiles rejected by endpoint", "endpoint", endpoint.URL, "err", err)
+				return nil
+			}
+			f.metrics.sentBytes.WithLabelValues(endpoint.URL).Add(float64(size))
+			f.metrics.sentProfiles.WithLabelValues(endpoint.URL).Add(float64(profiles))
+			return nil
+		})
+		if err != nil {
+			level.Debug(f.opts.Logger).Log("msg", "failed to replay buffered profiles to endpoint", "endpoint", endpoint.URL, "err", err)
+		}
+	}
+}
+
 func shouldRetry(err error) bool {
 	if errors.Is(err, context.DeadlineExceeded) {
 		return true
@@ -260,9 +447,9 @@ func shouldRetry(err error) bool {
 	return false
 }
 
-func requestSize(req *connect.Request[pushv1.PushRequest]) (int64, int64) {
+func requestSize(req *pushv1.PushRequest) (int64, int64) {
 	var size, profiles int64
-	for _, raw := range req.Msg.Series {
+	for _, raw := range req.Series {
 		for _, sample := range raw.Samples {
 			size += int64(len(sample.RawProfile))
 			profiles++
@@ -296,9 +483,23 @@ func (f *fanOutClient) Append(ctx context.Context, lbs labels.Labels, samples []
 		lbsBuilder.Set(label.Name, label.Value)
 	}
 	for name, value := range f.config.ExternalLabels {
-		lbsBuilder.Set(name, value)
+		lbsBuilder.Set(name, expandExternalLabel(value, lbs))
+	}
+
+	finalLabels := lbsBuilder.Labels()
+	if len(f.relabelConfigs) > 0 {
+		var keep bool
+		finalLabels, keep = relabel.Process(finalLabels, f.relabelConfigs...)
+		if !keep {
+			return nil
+		}
+	}
+	tenantID := finalLabels.Get(LabelNameTenantID)
+	if tenantID != "" {
+		finalLabels = labels.NewBuilder(finalLabels).Del(LabelNameTenantID).Labels()
 	}
-	for _, l := range lbsBuilder.Labels() {
+
+	for _, l := range finalLabels {
 		protoLabels = append(protoLabels, &typesv1.LabelPair{
 			Name:  l.Name,
 			Value: l.Value,
@@ -309,15 +510,31 @@ func (f *fanOutClient) Append(ctx context.Context, lbs labels.Labels, samples []
 			RawProfile: sample.RawProfile,
 		})
 	}
-	// push to all clients
-	_, err := f.Push(ctx, connect.NewRequest(&pushv1.PushRequest{
+	req := connect.NewRequest(&pushv1.PushRequest{
 		Series: []*pushv1.RawProfileSeries{
 			{Labels: protoLabels, Samples: protoSamples},
 		},
-	}))
+	})
+	if tenantID != "" {
+		req.Header().Set(tenantHeaderName, tenantID)
+	}
+	// push to all clients
+	_, err := f.Push(ctx, req)
 	return err
 }
 
+// expandExternalLabel replaces the references to profile labels in the value
+// of an external label with the values of the labels. Labels which aren't set
+// are replaced with an empty string.
+func expandExternalLabel(value string, lbs labels.Labels) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+	return externalLabelTemplate.ReplaceAllStringFunc(value, func(ref string) string {
+		return lbs.Get(ref[2 : len(ref)-1])
+	})
+}
+
 // WithUserAgent returns a `connect.ClientOption` that sets the User-Agent header on.
 func WithUserAgent(agent string) connect.ClientOption {
 	return connect.WithInterceptors(&agentInterceptor{agent})
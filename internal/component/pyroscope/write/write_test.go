@@ -208,6 +208,193 @@ func Test_Write_Update(t *testing.T) {
 	require.Equal(t, int32(1), pushTotal.Load())
 }
 
+func Test_Write_RelabelAndTenant(t *testing.T) {
+	var (
+		export   Exports
+		argument = DefaultArguments()
+		mut      sync.Mutex
+		tenants  []string
+		series   [][]*typesv1.LabelPair
+	)
+	_, handler := pushv1connect.NewPusherServiceHandler(PushFunc(
+		func(_ context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+			mut.Lock()
+			defer mut.Unlock()
+			tenants = append(tenants, req.Header().Get("X-Scope-OrgID"))
+			series = append(series, req.Msg.Series[0].Labels)
+			return &connect.Response[pushv1.PushResponse]{}, nil
+		},
+	))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	require.NoError(t, syntax.Unmarshal([]byte(`
+	external_labels = {
+		"cluster" = "eu-${region}",
+	}
+	endpoint {
+		url       = "`+server.URL+`"
+		tenant_id = "default"
+	}
+	rule {
+		source_labels = ["team"]
+		regex         = "(.+)"
+		target_label  = "__tenant_id__"
+	}
+	rule {
+		source_labels = ["job"]
+		regex         = "drop"
+		action        = "drop"
+	}`), &argument))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	c, err := New(component.Options{
+		ID:         "1",
+		Logger:     util.TestAlloyLogger(t),
+		Registerer: prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {
+			defer wg.Done()
+			export = e.(Exports)
+		},
+	}, argument)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+	wg.Wait() // wait for the state change to happen
+
+	for _, lbs := range []map[string]string{
+		{"__name__": "test", "job": "foo", "region": "west", "team": "a"},
+		{"__name__": "test", "job": "bar"},
+		{"__name__": "test", "job": "drop", "team": "a"},
+	} {
+		err := export.Receiver.Appender().Append(context.Background(), labels.FromMap(lbs), []*pyroscope.RawSample{
+			{RawProfile: []byte("pprofraw")},
+		})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"a", "default"}, tenants)
+	require.Equal(t, [][]*typesv1.LabelPair{
+		{
+			{Name: "__name__", Value: "test"},
+			{Name: "cluster", Value: "eu-west"},
+			{Name: "job", Value: "foo"},
+			{Name: "region", Value: "west"},
+			{Name: "team", Value: "a"},
+		},
+		{
+			{Name: "__name__", Value: "test"},
+			{Name: "cluster", Value: "eu-"},
+			{Name: "job", Value: "bar"},
+		},
+	}, series)
+}
+
+func Test_Write_DiskBuffer(t *testing.T) {
+	var (
+		export    Exports
+		argument  = DefaultArguments()
+		available = atomic.NewBool(false)
+		pushTotal = atomic.NewInt32(0)
+	)
+	_, handler := pushv1connect.NewPusherServiceHandler(PushFunc(
+		func(_ context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+			if !available.Load() {
+				return nil, connect.NewError(connect.CodeUnavailable, errors.New("maintenance"))
+			}
+			pushTotal.Inc()
+			require.Equal(t, "tenant-a", req.Header().Get("X-Scope-OrgID"))
+			require.Equal(t, []byte("pprofraw"), req.Msg.Series[0].Samples[0].RawProfile)
+			return &connect.Response[pushv1.PushResponse]{}, nil
+		},
+	))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	argument.DiskBuffer.Enabled = true
+	argument.Endpoints = []*EndpointOptions{
+		{
+			URL:               server.URL,
+			TenantID:          "tenant-a",
+			MinBackoff:        10 * time.Millisecond,
+			MaxBackoff:        20 * time.Millisecond,
+			MaxBackoffRetries: 1,
+			RemoteTimeout:     GetDefaultEndpointOptions().RemoteTimeout,
+		},
+	}
+	c, err := New(component.Options{
+		ID:         "1",
+		Logger:     util.TestAlloyLogger(t),
+		Registerer: prometheus.NewRegistry(),
+		DataPath:   t.TempDir(),
+		OnStateChange: func(e component.Exports) {
+			export = e.(Exports)
+		},
+	}, argument)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		err = export.Receiver.Appender().Append(context.Background(), labels.FromMap(map[string]string{
+			"__name__": "test",
+		}), []*pyroscope.RawSample{
+			{RawProfile: []byte("pprofraw")},
+		})
+		require.NoError(t, err)
+	}
+	entries, err := c.receiver.buffers[0].entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// The buffered profiles are kept while the endpoint is unavailable.
+	c.receiver.replay(context.Background())
+	require.Equal(t, int32(0), pushTotal.Load())
+
+	available.Store(true)
+	c.receiver.replay(context.Background())
+	require.Equal(t, int32(2), pushTotal.Load())
+	entries, err = c.receiver.buffers[0].entries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func Test_DiskBuffer_Limits(t *testing.T) {
+	b, err := newDiskBuffer(t.TempDir(), "test", util.TestAlloyLogger(t), newMetrics(nil))
+	require.NoError(t, err)
+
+	req := &pushv1.PushRequest{
+		Series: []*pushv1.RawProfileSeries{{
+			Labels:  []*typesv1.LabelPair{{Name: "__name__", Value: "test"}},
+			Samples: []*pushv1.RawSample{{RawProfile: []byte("pprofraw")}},
+		}},
+	}
+	data, err := encodeBufferedRequest("tenant-a", req)
+	require.NoError(t, err)
+
+	// The oldest profiles are dropped when the buffer is full.
+	b.setLimits(int64(2*len(data)), time.Hour)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.write("tenant-a", req))
+	}
+	entries, err := b.entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, int64(2*len(data)), b.size)
+
+	// The profiles older than the maximum age are dropped.
+	b.setLimits(int64(2*len(data)), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	require.NoError(t, b.replay(func(string, *pushv1.PushRequest) error {
+		t.Fatal("expired profiles must not be sent")
+		return nil
+	}))
+	entries, err = b.entries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, int64(0), b.size)
+}
+
 func Test_Unmarshal_Config(t *testing.T) {
 	var arg Arguments
 	syntax.Unmarshal([]byte(`
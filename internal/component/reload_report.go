@@ -0,0 +1,92 @@
+package component
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ReloadReport describes the changes applied by the last load of a config.
+type ReloadReport struct {
+	// StartTime is the time when the load started.
+	StartTime time.Time `json:"startTime"`
+
+	// DurationSeconds is how long the load took.
+	DurationSeconds float64 `json:"durationSeconds"`
+
+	// Error is set when the config couldn't be loaded at all, in which case the
+	// previous config is kept and Components is empty.
+	Error string `json:"error,omitempty"`
+
+	// Components holds the change and the outcome of every component of the
+	// previous and the new config.
+	Components []ComponentChange `json:"components"`
+}
+
+// ChangeType describes how a component changed between two loads.
+type ChangeType string
+
+// Supported values for ChangeType.
+const (
+	ChangeTypeAdded     ChangeType = "added"
+	ChangeTypeRemoved   ChangeType = "removed"
+	ChangeTypeUpdated   ChangeType = "updated"
+	ChangeTypeUnchanged ChangeType = "unchanged"
+)
+
+// ApplyOutcome describes what happened to a component during a load.
+type ApplyOutcome string
+
+// Supported values for ApplyOutcome.
+const (
+	// ApplyOutcomeApplied is used for components which were evaluated
+	// successfully, or removed.
+	ApplyOutcomeApplied ApplyOutcome = "applied"
+
+	// ApplyOutcomeSkipped is used for components which weren't evaluated since
+	// neither their block nor their dependencies changed.
+	ApplyOutcomeSkipped ApplyOutcome = "skipped"
+
+	// ApplyOutcomeFailed is used for components which failed to evaluate.
+	ApplyOutcomeFailed ApplyOutcome = "failed"
+
+	// ApplyOutcomeTimedOut is used for components whose evaluation didn't
+	// finish within the evaluation timeout. Their evaluation continues in the
+	// background.
+	ApplyOutcomeTimedOut ApplyOutcome = "timed_out"
+
+	// ApplyOutcomeDeferred is used for components which reference a new
+	// component whose evaluation timed out. They're evaluated once the
+	// evaluation of that component finishes.
+	ApplyOutcomeDeferred ApplyOutcome = "deferred"
+)
+
+// ComponentChange describes the change and the outcome of a component during
+// a load.
+type ComponentChange struct {
+	// ID of the component.
+	ID string `json:"id"`
+
+	Change  ChangeType   `json:"change"`
+	Outcome ApplyOutcome `json:"outcome"`
+
+	// Error is set when Outcome is ApplyOutcomeFailed.
+	Error string `json:"error,omitempty"`
+
+	// Arguments holds the arguments which changed when Change is
+	// ChangeTypeUpdated.
+	Arguments []ArgumentChange `json:"arguments,omitempty"`
+}
+
+// ArgumentChange describes a change of a top-level argument or block of a
+// component. The values are encoded with the alloyjson package, which masks
+// secrets.
+type ArgumentChange struct {
+	// Name of the argument or block. Labeled blocks are suffixed with their
+	// label in quotes.
+	Name string `json:"name"`
+
+	// Old and New hold the previous and the new values, and are empty when the
+	// argument was unset.
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
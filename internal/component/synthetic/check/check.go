@@ -0,0 +1,204 @@
+// Package check implements the synthetic.check component, which runs
+// multi-step HTTP scenarios and reports their results as metrics and logs.
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/prometheus/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+// name is the name of the integration, used in the job label of the
+// exported target.
+const name = "synthetic_check"
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "synthetic.check",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   exporter.Exports{},
+
+		Build: exporter.New(createExporter, name),
+	})
+}
+
+func createExporter(opts component.Options, args component.Arguments, defaultInstanceKey string) (integrations.Integration, string, error) {
+	a := args.(Arguments)
+	c, err := newChecker(opts.Logger, opts.ID, a)
+	if err != nil {
+		return nil, "", err
+	}
+	return integrations.NewCollectorIntegration(
+		name,
+		integrations.WithCollectors(c),
+		integrations.WithRunner(c.Run),
+	), defaultInstanceKey, nil
+}
+
+// DefaultArguments holds the default arguments for the synthetic.check component.
+var DefaultArguments = Arguments{
+	Interval:        time.Minute,
+	Timeout:         30 * time.Second,
+	FollowRedirects: true,
+}
+
+// Arguments configures the synthetic.check component.
+type Arguments struct {
+	Interval        time.Duration                `alloy:"interval,attr,optional"`
+	Timeout         time.Duration                `alloy:"timeout,attr,optional"`
+	Variables       map[string]alloytypes.Secret `alloy:"variables,attr,optional"`
+	FollowRedirects bool                         `alloy:"follow_redirects,attr,optional"`
+	ForwardTo       []loki.LogsReceiver          `alloy:"forward_to,attr,optional"`
+
+	TLSConfig *config.TLSConfig `alloy:"tls_config,block,optional"`
+	Steps     []Step            `alloy:"step,block"`
+}
+
+// Step is an HTTP request of a scenario, with the assertions on its response
+// and the variables extracted from it.
+type Step struct {
+	Name    string            `alloy:",label"`
+	Method  string            `alloy:"method,attr,optional"`
+	URL     string            `alloy:"url,attr"`
+	Headers map[string]string `alloy:"headers,attr,optional"`
+	Body    string            `alloy:"body,attr,optional"`
+
+	ExpectedStatusCodes []int         `alloy:"expected_status_codes,attr,optional"`
+	MaxLatency          time.Duration `alloy:"max_latency,attr,optional"`
+	BodyContains        []string      `alloy:"body_contains,attr,optional"`
+	BodyRegex           string        `alloy:"body_regex,attr,optional"`
+
+	Extracts []Extract `alloy:"extract,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (s *Step) SetToDefault() {
+	*s = Step{Method: http.MethodGet}
+}
+
+// Extract extracts a variable from the response of a step, from exactly one
+// of a field of the JSON body, a header, or the first capturing group of a
+// regular expression matching the body.
+type Extract struct {
+	Name     string `alloy:",label"`
+	JSONPath string `alloy:"json_path,attr,optional"`
+	Header   string `alloy:"header,attr,optional"`
+	Regex    string `alloy:"regex,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if a.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	if len(a.Steps) == 0 {
+		return fmt.Errorf("at least one step block is required")
+	}
+
+	// Variables are defined by the variables argument, and by the extract
+	// blocks of the previous steps.
+	defined := make(map[string]struct{}, len(a.Variables))
+	for name := range a.Variables {
+		defined[name] = struct{}{}
+	}
+
+	steps := make(map[string]struct{}, len(a.Steps))
+	for _, s := range a.Steps {
+		if _, ok := steps[s.Name]; ok {
+			return fmt.Errorf("duplicate step %q", s.Name)
+		}
+		steps[s.Name] = struct{}{}
+
+		if err := s.validate(defined); err != nil {
+			return fmt.Errorf("invalid step %q: %w", s.Name, err)
+		}
+		for _, e := range s.Extracts {
+			defined[e.Name] = struct{}{}
+		}
+	}
+
+	if a.TLSConfig == nil {
+		return nil
+	}
+	return a.TLSConfig.Validate()
+}
+
+func (s *Step) validate(defined map[string]struct{}) error {
+	if s.URL == "" {
+		return fmt.Errorf("url must not be empty")
+	}
+	for _, code := range s.ExpectedStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid expected status code %d", code)
+		}
+	}
+	if s.MaxLatency < 0 {
+		return fmt.Errorf("max_latency must not be negative")
+	}
+	if _, err := regexp.Compile(s.BodyRegex); err != nil {
+		return fmt.Errorf("invalid body_regex: %w", err)
+	}
+
+	templates := []string{s.URL, s.Body}
+	for k, v := range s.Headers {
+		templates = append(templates, k, v)
+	}
+	for _, t := range templates {
+		for _, name := range referencedVariables(t) {
+			if _, ok := defined[name]; !ok {
+				return fmt.Errorf("undefined variable %q", name)
+			}
+		}
+	}
+
+	for _, e := range s.Extracts {
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("invalid extract %q: %w", e.Name, err)
+		}
+	}
+	return nil
+}
+
+func (e *Extract) validate() error {
+	var set []string
+	for name, value := range map[string]string{"json_path": e.JSONPath, "header": e.Header, "regex": e.Regex} {
+		if value != "" {
+			set = append(set, name)
+		}
+	}
+	if len(set) != 1 {
+		return fmt.Errorf("exactly one of json_path, header, or regex must be set")
+	}
+	if e.Regex != "" {
+		re, err := regexp.Compile(e.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		if re.NumSubexp() == 0 {
+			return fmt.Errorf("regex must have a capturing group")
+		}
+	}
+	if strings.Contains(e.JSONPath, "..") || strings.HasPrefix(e.JSONPath, ".") || strings.HasSuffix(e.JSONPath, ".") {
+		return fmt.Errorf("invalid json_path %q", e.JSONPath)
+	}
+	return nil
+}
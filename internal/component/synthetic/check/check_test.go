@@ -0,0 +1,156 @@
+package check
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlloyUnmarshal(t *testing.T) {
+	alloyConfig := `
+	interval  = "5m"
+	variables = { "password" = "secret" }
+
+	step "login" {
+		method = "POST"
+		url    = "https://example.com/login"
+		body   = "{\"password\": \"${password}\"}"
+
+		expected_status_codes = [200, 201]
+
+		extract "token" {
+			json_path = "data.token"
+		}
+	}
+
+	step "profile" {
+		url           = "https://example.com/profile"
+		headers       = { "Authorization" = "Bearer ${token}" }
+		max_latency   = "500ms"
+		body_contains = ["username"]
+	}
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	require.Equal(t, 5*time.Minute, args.Interval)
+	require.Equal(t, 30*time.Second, args.Timeout)
+	require.True(t, args.FollowRedirects)
+	require.Equal(t, map[string]alloytypes.Secret{"password": "secret"}, args.Variables)
+
+	require.Len(t, args.Steps, 2)
+	require.Equal(t, "login", args.Steps[0].Name)
+	require.Equal(t, http.MethodPost, args.Steps[0].Method)
+	require.Equal(t, []int{200, 201}, args.Steps[0].ExpectedStatusCodes)
+	require.Equal(t, []Extract{{Name: "token", JSONPath: "data.token"}}, args.Steps[0].Extracts)
+
+	require.Equal(t, "profile", args.Steps[1].Name)
+	require.Equal(t, http.MethodGet, args.Steps[1].Method)
+	require.Equal(t, 500*time.Millisecond, args.Steps[1].MaxLatency)
+	require.Equal(t, []string{"username"}, args.Steps[1].BodyContains)
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name     string
+		alloyCfg string
+		err      string
+	}{
+		{
+			name:     "no steps",
+			alloyCfg: `interval = "1m"`,
+			err:      "at least one step block is required",
+		},
+		{
+			name: "invalid timeout",
+			alloyCfg: `
+			timeout = "0s"
+			step "a" { url = "http://localhost" }`,
+			err: "timeout must be greater than 0",
+		},
+		{
+			name: "duplicate step",
+			alloyCfg: `
+			step "a" { url = "http://localhost" }
+			step "a" { url = "http://localhost" }`,
+			err: `duplicate step "a"`,
+		},
+		{
+			name:     "invalid status code",
+			alloyCfg: `step "a" { url = "http://localhost" expected_status_codes = [2000] }`,
+			err:      `invalid step "a": invalid expected status code 2000`,
+		},
+		{
+			name:     "invalid body regex",
+			alloyCfg: `step "a" { url = "http://localhost" body_regex = "ok(" }`,
+			err:      "invalid step \"a\": invalid body_regex: error parsing regexp: missing closing ): `ok(`",
+		},
+		{
+			name:     "undefined variable",
+			alloyCfg: `step "a" { url = "http://localhost/${id}" }`,
+			err:      `invalid step "a": undefined variable "id"`,
+		},
+		{
+			name: "variable extracted by a later step",
+			alloyCfg: `
+			step "a" { url = "http://localhost/${id}" }
+			step "b" {
+				url = "http://localhost"
+				extract "id" { header = "X-Id" }
+			}`,
+			err: `invalid step "a": undefined variable "id"`,
+		},
+		{
+			name: "extract without source",
+			alloyCfg: `
+			step "a" {
+				url = "http://localhost"
+				extract "id" { }
+			}`,
+			err: `invalid step "a": invalid extract "id": exactly one of json_path, header, or regex must be set`,
+		},
+		{
+			name: "extract with several sources",
+			alloyCfg: `
+			step "a" {
+				url = "http://localhost"
+				extract "id" {
+					header = "X-Id"
+					regex  = "id=(\\d+)"
+				}
+			}`,
+			err: `invalid step "a": invalid extract "id": exactly one of json_path, header, or regex must be set`,
+		},
+		{
+			name: "extract regex without group",
+			alloyCfg: `
+			step "a" {
+				url = "http://localhost"
+				extract "id" { regex = "id=\\d+" }
+			}`,
+			err: `invalid step "a": invalid extract "id": regex must have a capturing group`,
+		},
+		{
+			name: "invalid json path",
+			alloyCfg: `
+			step "a" {
+				url = "http://localhost"
+				extract "id" { json_path = "data..id" }
+			}`,
+			err: `invalid step "a": invalid extract "id": invalid json_path "data..id"`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	// maxBodySize is the maximum size of the response bodies read by the
+	// assertions and extractions.
+	maxBodySize = 10 << 20
+
+	// logSendTimeout is how long a run waits for the receivers to accept its
+	// log lines before dropping them.
+	logSendTimeout = 2 * time.Second
+)
+
+var (
+	successDesc  = prometheus.NewDesc("synthetic_check_success", "Whether all the steps of the last run of the check succeeded.", nil, nil)
+	durationDesc = prometheus.NewDesc("synthetic_check_duration_seconds", "Duration of the last run of the check.", nil, nil)
+	runsDesc     = prometheus.NewDesc("synthetic_check_runs_total", "Total number of runs of the check.", nil, nil)
+	failuresDesc = prometheus.NewDesc("synthetic_check_failures_total", "Total number of failed runs of the check.", nil, nil)
+
+	stepSuccessDesc    = prometheus.NewDesc("synthetic_check_step_success", "Whether the step succeeded in the last run of the check.", []string{"step"}, nil)
+	stepDurationDesc   = prometheus.NewDesc("synthetic_check_step_duration_seconds", "Duration of the step in the last run of the check.", []string{"step"}, nil)
+	stepStatusCodeDesc = prometheus.NewDesc("synthetic_check_step_status_code", "HTTP status code of the response of the step in the last run of the check.", []string{"step"}, nil)
+)
+
+// variableRegexp matches the references to variables, such as ${token}.
+var variableRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// referencedVariables returns the names of the variables referenced by s.
+func referencedVariables(s string) []string {
+	var names []string
+	for _, m := range variableRegexp.FindAllStringSubmatch(s, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// expand replaces the references to variables in s with their values.
+// References to undefined variables are left as is, which Validate prevents.
+func expand(s string, vars map[string]string) string {
+	return variableRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		if v, ok := vars[ref[2:len(ref)-1]]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// checker runs the steps of a check every interval, and exposes the metrics
+// of the last run.
+type checker struct {
+	log       log.Logger
+	id        string
+	args      Arguments
+	transport http.RoundTripper
+
+	bodyRegexps    []*regexp.Regexp
+	extractRegexps map[string]*regexp.Regexp
+
+	mut            sync.RWMutex
+	metrics        []prometheus.Metric
+	runs, failures float64
+}
+
+var _ prometheus.Collector = (*checker)(nil)
+
+func newChecker(l log.Logger, id string, args Arguments) (*checker, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if args.TLSConfig != nil {
+		tlsConfig, err := config_util.NewTLSConfig(args.TLSConfig.Convert())
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls_config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	c := &checker{
+		log:            l,
+		id:             id,
+		args:           args,
+		transport:      transport,
+		extractRegexps: make(map[string]*regexp.Regexp),
+	}
+	for _, s := range args.Steps {
+		var re *regexp.Regexp
+		if s.BodyRegex != "" {
+			re = regexp.MustCompile(s.BodyRegex)
+		}
+		c.bodyRegexps = append(c.bodyRegexps, re)
+
+		for _, e := range s.Extracts {
+			if e.Regex != "" {
+				c.extractRegexps[e.Regex] = regexp.MustCompile(e.Regex)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Run runs the check every interval until ctx is canceled.
+func (c *checker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.args.Interval)
+	defer ticker.Stop()
+
+	for {
+		res := c.runOnce(ctx)
+		if ctx.Err() != nil {
+			// The run was interrupted by the shutdown or an update of the
+			// component, so its result is meaningless.
+			return nil
+		}
+		c.record(res)
+		c.sendLogs(ctx, res)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runResult is the result of a run of the check.
+type runResult struct {
+	start    time.Time
+	duration time.Duration
+	steps    []stepResult
+}
+
+// success returns whether all the steps ran and succeeded.
+func (r *runResult) success(steps int) bool {
+	return len(r.steps) == steps && r.steps[len(r.steps)-1].err == nil
+}
+
+type stepResult struct {
+	name       string
+	start      time.Time
+	duration   time.Duration
+	statusCode int
+	err        error
+}
+
+// runOnce runs the steps of the check in order, until a step fails.
+func (c *checker) runOnce(ctx context.Context) runResult {
+	ctx, cancel := context.WithTimeout(ctx, c.args.Timeout)
+	defer cancel()
+
+	vars := make(map[string]string, len(c.args.Variables))
+	for name, value := range c.args.Variables {
+		vars[name] = string(value)
+	}
+
+	// Cookies are kept between the steps of a run, so scenarios can log in.
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Transport: c.transport, Jar: jar}
+	if !c.args.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	res := runResult{start: time.Now()}
+	for i, s := range c.args.Steps {
+		sr := c.runStep(ctx, client, &s, c.bodyRegexps[i], vars)
+		res.steps = append(res.steps, sr)
+		if sr.err != nil {
+			break
+		}
+	}
+	res.duration = time.Since(res.start)
+	return res
+}
+
+func (c *checker) runStep(ctx context.Context, client *http.Client, s *Step, bodyRegexp *regexp.Regexp, vars map[string]string) stepResult {
+	res := stepResult{name: s.Name, start: time.Now()}
+
+	var body io.Reader
+	if s.Body != "" {
+		body = strings.NewReader(expand(s.Body, vars))
+	}
+	req, err := http.NewRequestWithContext(ctx, s.Method, expand(s.URL, vars), body)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	for k, v := range s.Headers {
+		req.Header.Set(expand(k, vars), expand(v, vars))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		res.duration = time.Since(res.start)
+		res.err = err
+		return res
+	}
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	resp.Body.Close()
+	res.duration = time.Since(res.start)
+	res.statusCode = resp.StatusCode
+	if err != nil {
+		res.err = fmt.Errorf("failed to read the response body: %w", err)
+		return res
+	}
+
+	if res.err = assert(s, bodyRegexp, resp.StatusCode, respBody, res.duration); res.err != nil {
+		return res
+	}
+	for _, e := range s.Extracts {
+		value, err := c.extract(&e, resp.Header, respBody)
+		if err != nil {
+			res.err = fmt.Errorf("failed to extract %s: %w", e.Name, err)
+			return res
+		}
+		vars[e.Name] = value
+	}
+	return res
+}
+
+// assert checks the response of a step against the assertions of the step.
+// The status code must be 2xx when no status codes are expected.
+func assert(s *Step, bodyRegexp *regexp.Regexp, statusCode int, body []byte, latency time.Duration) error {
+	if len(s.ExpectedStatusCodes) == 0 {
+		if statusCode < 200 || statusCode > 299 {
+			return fmt.Errorf("unexpected status code %d, wanted 2xx", statusCode)
+		}
+	} else if !containsInt(s.ExpectedStatusCodes, statusCode) {
+		return fmt.Errorf("unexpected status code %d, wanted one of %v", statusCode, s.ExpectedStatusCodes)
+	}
+	if s.MaxLatency > 0 && latency > s.MaxLatency {
+		return fmt.Errorf("latency %s exceeds max_latency %s", latency, s.MaxLatency)
+	}
+	for _, substr := range s.BodyContains {
+		if !bytes.Contains(body, []byte(substr)) {
+			return fmt.Errorf("body doesn't contain %q", substr)
+		}
+	}
+	if bodyRegexp != nil && !bodyRegexp.Match(body) {
+		return fmt.Errorf("body doesn't match %q", bodyRegexp.String())
+	}
+	return nil
+}
+
+func (c *checker) extract(e *Extract, header http.Header, body []byte) (string, error) {
+	switch {
+	case e.Header != "":
+		value := header.Get(e.Header)
+		if value == "" {
+			return "", fmt.Errorf("header %q not found", e.Header)
+		}
+		return value, nil
+	case e.Regex != "":
+		m := c.extractRegexps[e.Regex].FindSubmatch(body)
+		if m == nil {
+			return "", fmt.Errorf("regex %q didn't match the body", e.Regex)
+		}
+		return string(m[1]), nil
+	default:
+		return lookupJSON(body, e.JSONPath)
+	}
+}
+
+// lookupJSON returns the value at path in the JSON document body. The keys
+// of objects and indexes of arrays are separated by dots, for example
+// data.items.0.id. Values which aren't strings, numbers, or booleans are
+// returned as JSON.
+func lookupJSON(body []byte, path string) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			value, ok := t[key]
+			if !ok {
+				return "", fmt.Errorf("key %q of %q not found", key, path)
+			}
+			v = value
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(t) {
+				return "", fmt.Errorf("index %q of %q out of range", key, path)
+			}
+			v = t[i]
+		default:
+			return "", fmt.Errorf("key %q of %q not found", key, path)
+		}
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case nil:
+		return "", fmt.Errorf("value of %q is null", path)
+	default:
+		b, err := json.Marshal(t)
+		return string(b), err
+	}
+}
+
+// record replaces the metrics with the metrics of a run.
+func (c *checker) record(res runResult) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	success := res.success(len(c.args.Steps))
+	c.runs++
+	if !success {
+		c.failures++
+	}
+
+	c.metrics = []prometheus.Metric{
+		prometheus.MustNewConstMetric(successDesc, prometheus.GaugeValue, boolToFloat(success)),
+		prometheus.MustNewConstMetric(durationDesc, prometheus.GaugeValue, res.duration.Seconds()),
+		prometheus.MustNewConstMetric(runsDesc, prometheus.CounterValue, c.runs),
+		prometheus.MustNewConstMetric(failuresDesc, prometheus.CounterValue, c.failures),
+	}
+	for _, s := range res.steps {
+		c.metrics = append(c.metrics,
+			prometheus.MustNewConstMetric(stepSuccessDesc, prometheus.GaugeValue, boolToFloat(s.err == nil), s.name),
+			prometheus.MustNewConstMetric(stepDurationDesc, prometheus.GaugeValue, s.duration.Seconds(), s.name),
+		)
+		if s.statusCode != 0 {
+			c.metrics = append(c.metrics, prometheus.MustNewConstMetric(stepStatusCodeDesc, prometheus.GaugeValue, float64(s.statusCode), s.name))
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *checker) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{successDesc, durationDesc, runsDesc, failuresDesc, stepSuccessDesc, stepDurationDesc, stepStatusCodeDesc} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *checker) Collect(ch chan<- prometheus.Metric) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
+
+// sendLogs sends a log line per step of a run, and a log line with the result
+// of the run, to the receivers.
+func (c *checker) sendLogs(ctx context.Context, res runResult) {
+	if len(c.args.ForwardTo) == 0 {
+		return
+	}
+
+	entries := make([]loki.Entry, 0, len(res.steps)+1)
+	for _, s := range res.steps {
+		keyvals := []interface{}{
+			"check", c.id,
+			"step", s.name,
+			"success", strconv.FormatBool(s.err == nil),
+			"duration_seconds", formatFloat(s.duration.Seconds()),
+		}
+		if s.statusCode != 0 {
+			keyvals = append(keyvals, "status_code", strconv.Itoa(s.statusCode))
+		}
+		if s.err != nil {
+			keyvals = append(keyvals, "failure_reason", s.err.Error())
+		}
+		entries = append(entries, c.newEntry(s.start, keyvals))
+	}
+
+	success := res.success(len(c.args.Steps))
+	keyvals := []interface{}{
+		"check", c.id,
+		"success", strconv.FormatBool(success),
+		"duration_seconds", formatFloat(res.duration.Seconds()),
+		"steps", strconv.Itoa(len(res.steps)),
+	}
+	if !success {
+		keyvals = append(keyvals, "failed_step", res.steps[len(res.steps)-1].name)
+	}
+	entries = append(entries, c.newEntry(res.start, keyvals))
+
+	ctx, cancel := context.WithTimeout(ctx, logSendTimeout)
+	defer cancel()
+
+	for _, entry := range entries {
+		for _, receiver := range c.args.ForwardTo {
+			select {
+			case <-ctx.Done():
+				level.Warn(c.log).Log("msg", "dropped the log lines of a check run, receivers are blocked")
+				return
+			case receiver.Chan() <- entry.Clone():
+			}
+		}
+	}
+}
+
+func (c *checker) newEntry(ts time.Time, keyvals []interface{}) loki.Entry {
+	var buf bytes.Buffer
+	_ = log.NewLogfmtLogger(&buf).Log(keyvals...)
+
+	return loki.Entry{
+		Labels: model.LabelSet{"check": model.LabelValue(c.id)},
+		Entry: logproto.Entry{
+			Timestamp: ts,
+			Line:      strings.TrimSuffix(buf.String(), "\n"),
+		},
+	}
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+// newTestServer returns a server with a login endpoint, which returns a
+// token for the right password, and a profile endpoint which requires it.
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Password string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-Request-Id", "42")
+		_, _ = w.Write([]byte(`{"data": {"token": "abc", "roles": ["admin"]}}`))
+	})
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`<p>username: alice</p>`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestArguments(url, password string) Arguments {
+	args := DefaultArguments
+	args.Variables = map[string]alloytypes.Secret{"password": alloytypes.Secret(password)}
+	args.Steps = []Step{
+		{
+			Name:   "login",
+			Method: http.MethodPost,
+			URL:    url + "/login",
+			Body:   `{"password": "${password}"}`,
+			Extracts: []Extract{
+				{Name: "token", JSONPath: "data.token"},
+				{Name: "role", JSONPath: "data.roles.0"},
+				{Name: "request_id", Header: "X-Request-Id"},
+			},
+		},
+		{
+			Name:      "profile",
+			Method:    http.MethodGet,
+			URL:       url + "/profile",
+			Headers:   map[string]string{"Authorization": "Bearer ${token}"},
+			BodyRegex: `username: \w+`,
+			Extracts:  []Extract{{Name: "username", Regex: `username: (\w+)`}},
+		},
+	}
+	return args
+}
+
+func TestChecker_Success(t *testing.T) {
+	srv := newTestServer(t)
+
+	c, err := newChecker(log.NewNopLogger(), "synthetic.check.test", newTestArguments(srv.URL, "secret"))
+	require.NoError(t, err)
+
+	res := c.runOnce(context.Background())
+	require.True(t, res.success(2))
+	require.Len(t, res.steps, 2)
+	for _, s := range res.steps {
+		require.NoError(t, s.err)
+		require.Equal(t, http.StatusOK, s.statusCode)
+	}
+	c.record(res)
+
+	expected := `
+# HELP synthetic_check_success Whether all the steps of the last run of the check succeeded.
+# TYPE synthetic_check_success gauge
+synthetic_check_success 1
+# HELP synthetic_check_failures_total Total number of failed runs of the check.
+# TYPE synthetic_check_failures_total counter
+synthetic_check_failures_total 0
+# HELP synthetic_check_step_success Whether the step succeeded in the last run of the check.
+# TYPE synthetic_check_step_success gauge
+synthetic_check_step_success{step="login"} 1
+synthetic_check_step_success{step="profile"} 1
+# HELP synthetic_check_step_status_code HTTP status code of the response of the step in the last run of the check.
+# TYPE synthetic_check_step_status_code gauge
+synthetic_check_step_status_code{step="login"} 200
+synthetic_check_step_status_code{step="profile"} 200
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"synthetic_check_success", "synthetic_check_failures_total", "synthetic_check_step_success", "synthetic_check_step_status_code"))
+}
+
+func TestChecker_Failure(t *testing.T) {
+	srv := newTestServer(t)
+
+	receiver := loki.NewLogsReceiverWithChannel(make(chan loki.Entry, 2))
+	args := newTestArguments(srv.URL, "wrong")
+	args.ForwardTo = []loki.LogsReceiver{receiver}
+
+	c, err := newChecker(log.NewNopLogger(), "synthetic.check.test", args)
+	require.NoError(t, err)
+
+	res := c.runOnce(context.Background())
+	require.False(t, res.success(2))
+	require.Len(t, res.steps, 1)
+	require.EqualError(t, res.steps[0].err, "unexpected status code 401, wanted 2xx")
+	c.record(res)
+
+	expected := `
+# HELP synthetic_check_success Whether all the steps of the last run of the check succeeded.
+# TYPE synthetic_check_success gauge
+synthetic_check_success 0
+# HELP synthetic_check_failures_total Total number of failed runs of the check.
+# TYPE synthetic_check_failures_total counter
+synthetic_check_failures_total 1
+# HELP synthetic_check_step_success Whether the step succeeded in the last run of the check.
+# TYPE synthetic_check_step_success gauge
+synthetic_check_step_success{step="login"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"synthetic_check_success", "synthetic_check_failures_total", "synthetic_check_step_success"))
+
+	c.sendLogs(context.Background(), res)
+	require.Len(t, receiver.Chan(), 2)
+
+	step := <-receiver.Chan()
+	require.Equal(t, model.LabelSet{"check": "synthetic.check.test"}, step.Labels)
+	require.Regexp(t, `^check=synthetic.check.test step=login success=false duration_seconds=\S+ status_code=401 failure_reason="unexpected status code 401, wanted 2xx"$`, step.Line)
+	require.NotContains(t, step.Line, "wrong")
+
+	summary := <-receiver.Chan()
+	require.Regexp(t, `^check=synthetic.check.test success=false duration_seconds=\S+ steps=1 failed_step=login$`, summary.Line)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	args := DefaultArguments
+	args.Timeout = 100 * time.Millisecond
+	args.Steps = []Step{{Name: "slow", Method: http.MethodGet, URL: srv.URL}}
+
+	c, err := newChecker(log.NewNopLogger(), "synthetic.check.test", args)
+	require.NoError(t, err)
+
+	res := c.runOnce(context.Background())
+	require.False(t, res.success(1))
+	require.ErrorIs(t, res.steps[0].err, context.DeadlineExceeded)
+}
+
+func TestAssert(t *testing.T) {
+	s := &Step{ExpectedStatusCodes: []int{301}, MaxLatency: time.Second, BodyContains: []string{"moved"}}
+	require.NoError(t, assert(s, nil, 301, []byte("moved permanently"), time.Millisecond))
+	require.EqualError(t, assert(s, nil, 200, nil, time.Millisecond), "unexpected status code 200, wanted one of [301]")
+	require.EqualError(t, assert(s, nil, 301, []byte("moved"), 2*time.Second), "latency 2s exceeds max_latency 1s")
+	require.EqualError(t, assert(s, nil, 301, []byte("gone"), time.Millisecond), `body doesn't contain "moved"`)
+}
+
+func TestLookupJSON(t *testing.T) {
+	body := []byte(`{"a": {"b": [{"c": 12345678901234}, {"c": true}]}, "n": null}`)
+
+	for path, expected := range map[string]string{
+		"a.b.0.c": "12345678901234",
+		"a.b.1.c": "true",
+		"a.b.1":   `{"c":true}`,
+	} {
+		value, err := lookupJSON(body, path)
+		require.NoError(t, err)
+		require.Equal(t, expected, value, path)
+	}
+
+	_, err := lookupJSON(body, "a.b.2")
+	require.EqualError(t, err, `index "2" of "a.b.2" out of range`)
+	_, err = lookupJSON(body, "a.x")
+	require.EqualError(t, err, `key "x" of "a.x" not found`)
+	_, err = lookupJSON(body, "n")
+	require.EqualError(t, err, `value of "n" is null`)
+}
@@ -0,0 +1,260 @@
+// Package configaudit records every load of the configuration in an
+// append-only audit log, as evidence of the configuration changes.
+package configaudit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// maxHistory is the number of records kept in memory for the history API.
+const maxHistory = 100
+
+// Trigger is what caused the configuration to be loaded.
+type Trigger string
+
+// The triggers of the configuration loads.
+const (
+	TriggerStartup Trigger = "startup" // The initial load.
+	TriggerSignal  Trigger = "signal"  // A SIGHUP signal.
+	TriggerAPI     Trigger = "api"     // A request to the /-/reload endpoint.
+)
+
+// Record describes a load of the configuration.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Trigger Trigger   `json:"trigger"`
+	// Actor identifies who requested the load, for the loads triggered by the
+	// API.
+	Actor string `json:"actor,omitempty"`
+	// Path is the path of the configuration file or directory.
+	Path string `json:"path"`
+	// SHA256 is the checksum of the configuration, if it could be read.
+	SHA256  string `json:"sha256,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Diff summarizes the changes since the last configuration which was
+	// loaded successfully.
+	Diff *DiffSummary `json:"diff,omitempty"`
+}
+
+// DiffSummary summarizes the differences between two configurations.
+type DiffSummary struct {
+	AddedFiles    []string `json:"added_files,omitempty"`
+	RemovedFiles  []string `json:"removed_files,omitempty"`
+	ModifiedFiles []string `json:"modified_files,omitempty"`
+	AddedLines    int      `json:"added_lines"`
+	RemovedLines  int      `json:"removed_lines"`
+}
+
+// Diff summarizes the changes from the files of prev to the ones of next.
+// Lines are compared regardless of their position in the files, so a line
+// which moved isn't counted as a change.
+func Diff(prev, next map[string][]byte) DiffSummary {
+	var d DiffSummary
+	for name, content := range next {
+		old, ok := prev[name]
+		switch {
+		case !ok:
+			d.AddedFiles = append(d.AddedFiles, name)
+		case !bytes.Equal(old, content):
+			d.ModifiedFiles = append(d.ModifiedFiles, name)
+		default:
+			continue
+		}
+		added, removed := diffLines(old, content)
+		d.AddedLines += added
+		d.RemovedLines += removed
+	}
+	for name, content := range prev {
+		if _, ok := next[name]; !ok {
+			d.RemovedFiles = append(d.RemovedFiles, name)
+			_, removed := diffLines(content, nil)
+			d.RemovedLines += removed
+		}
+	}
+	sort.Strings(d.AddedFiles)
+	sort.Strings(d.RemovedFiles)
+	sort.Strings(d.ModifiedFiles)
+	return d
+}
+
+// diffLines returns the number of lines of next which aren't in prev, and
+// the number of lines of prev which aren't in next.
+func diffLines(prev, next []byte) (added, removed int) {
+	counts := make(map[string]int)
+	for _, line := range splitLines(prev) {
+		counts[line]++
+	}
+	for _, line := range splitLines(next) {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+	for _, n := range counts {
+		removed += n
+	}
+	return added, removed
+}
+
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	var lines []string
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, len(b)+1)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	return lines
+}
+
+// Log records the loads of the configuration. The records are appended to a
+// file, and the most recent ones are kept in memory.
+type Log struct {
+	log  log.Logger
+	path string
+
+	mut     sync.Mutex
+	file    *os.File
+	history []Record
+	// loaded is the last configuration which was loaded successfully.
+	loaded map[string][]byte
+}
+
+// New returns a Log which appends the records to the file at path, which is
+// created if it doesn't exist. The records of the file are loaded in the
+// history. If path is empty, the records are only kept in memory.
+func New(logger log.Logger, path string) (*Log, error) {
+	l := &Log{log: logger, path: path}
+	if path == "" {
+		return l, nil
+	}
+
+	history, err := readHistory(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the config audit log: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening the config audit log: %w", err)
+	}
+	l.file = f
+	l.history = history
+	return l, nil
+}
+
+// readHistory returns the last records of the file at path. Lines which
+// can't be decoded are ignored.
+func readHistory(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []Record
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1024*1024)
+	for s.Scan() {
+		var r Record
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			continue
+		}
+		history = appendHistory(history, r)
+	}
+	return history, s.Err()
+}
+
+func appendHistory(history []Record, r Record) []Record {
+	history = append(history, r)
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	return history
+}
+
+// Record records a load of the configuration at path. source is the
+// configuration which was read, if any, and err is the error of the load.
+// Failing to write the record is logged, and doesn't fail the load.
+func (l *Log) Record(trigger Trigger, actor, path string, source *alloy_runtime.Source, err error) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	r := Record{
+		Time:    time.Now().UTC(),
+		Trigger: trigger,
+		Actor:   actor,
+		Path:    path,
+		Success: err == nil,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if source != nil {
+		r.SHA256 = fmt.Sprintf("%x", source.SHA256())
+		diff := Diff(l.loaded, source.RawConfigs())
+		r.Diff = &diff
+		if err == nil {
+			l.loaded = source.RawConfigs()
+		}
+	}
+	l.history = appendHistory(l.history, r)
+
+	if l.file == nil {
+		return
+	}
+	if err := l.write(r); err != nil {
+		level.Error(l.log).Log("msg", "failed to write to the config audit log", "path", l.path, "err", err)
+	}
+}
+
+// write appends r to the file and waits for it to be persisted.
+func (l *Log) write(r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// History returns the most recent records, from the oldest to the newest.
+func (l *Log) History() []Record {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	history := make([]Record, len(l.history))
+	copy(history, l.history)
+	return history
+}
+
+// Close closes the file of the log.
+func (l *Log) Close() error {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,102 @@
+package configaudit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	prev := map[string][]byte{
+		"a.alloy": []byte("line 1\nline 2\nline 3\n"),
+		"b.alloy": []byte("unchanged\n"),
+		"c.alloy": []byte("removed 1\nremoved 2\n"),
+	}
+	next := map[string][]byte{
+		"a.alloy": []byte("line 3\nline 1\nline 4\n"),
+		"b.alloy": []byte("unchanged\n"),
+		"d.alloy": []byte("added\n"),
+	}
+
+	require.Equal(t, DiffSummary{
+		AddedFiles:    []string{"d.alloy"},
+		RemovedFiles:  []string{"c.alloy"},
+		ModifiedFiles: []string{"a.alloy"},
+		AddedLines:    2,
+		RemovedLines:  3,
+	}, Diff(prev, next))
+
+	require.Equal(t, DiffSummary{}, Diff(prev, prev))
+}
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := New(util.TestLogger(t), path)
+	require.NoError(t, err)
+
+	first := parseSource(t, "logging {\n  level = \"info\"\n}\n")
+	second := parseSource(t, "logging {\n  level = \"debug\"\n}\n")
+
+	l.Record(TriggerStartup, "", "config.alloy", first, nil)
+	l.Record(TriggerAPI, "127.0.0.1:5000", "config.alloy", second, errors.New("invalid config"))
+	l.Record(TriggerSignal, "", "config.alloy", nil, errors.New("file not found"))
+	require.NoError(t, l.Close())
+
+	history := l.History()
+	require.Len(t, history, 3)
+
+	require.Equal(t, TriggerStartup, history[0].Trigger)
+	require.True(t, history[0].Success)
+	require.Equal(t, []string{"config.alloy"}, history[0].Diff.AddedFiles)
+
+	require.Equal(t, TriggerAPI, history[1].Trigger)
+	require.Equal(t, "127.0.0.1:5000", history[1].Actor)
+	require.False(t, history[1].Success)
+	require.Equal(t, "invalid config", history[1].Error)
+	require.NotEqual(t, history[0].SHA256, history[1].SHA256)
+	require.Equal(t, &DiffSummary{ModifiedFiles: []string{"config.alloy"}, AddedLines: 1, RemovedLines: 1}, history[1].Diff)
+
+	require.Equal(t, TriggerSignal, history[2].Trigger)
+	require.Empty(t, history[2].SHA256)
+	require.Nil(t, history[2].Diff)
+
+	// The records are appended to the file, and loaded again when it's
+	// reopened.
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 3)
+
+	reopened, err := New(util.TestLogger(t), path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	require.Equal(t, history, reopened.History())
+
+	reopened.Record(TriggerSignal, "", "config.alloy", first, nil)
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 4)
+}
+
+func TestLog_HistoryLimit(t *testing.T) {
+	l, err := New(util.TestLogger(t), "")
+	require.NoError(t, err)
+
+	for i := 0; i < maxHistory+10; i++ {
+		l.Record(TriggerSignal, "", "config.alloy", nil, nil)
+	}
+	require.Len(t, l.History(), maxHistory)
+}
+
+func parseSource(t *testing.T, content string) *alloy_runtime.Source {
+	t.Helper()
+	source, err := alloy_runtime.ParseSource("config.alloy", []byte(content))
+	require.NoError(t, err)
+	return source
+}
@@ -0,0 +1,36 @@
+package component
+
+import (
+	"time"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/component/discovery/eureka"
+	"github.com/grafana/alloy/internal/converter/diag"
+	"github.com/grafana/alloy/internal/converter/internal/common"
+	"github.com/grafana/alloy/internal/converter/internal/prometheusconvert/build"
+	prom_eureka "github.com/prometheus/prometheus/discovery/eureka"
+)
+
+func appendDiscoveryEureka(pb *build.PrometheusBlocks, label string, sdConfig *prom_eureka.SDConfig) discovery.Exports {
+	discoveryEurekaArgs := toDiscoveryEureka(sdConfig)
+	name := []string{"discovery", "eureka"}
+	block := common.NewBlockWithOverride(name, label, discoveryEurekaArgs)
+	pb.DiscoveryBlocks = append(pb.DiscoveryBlocks, build.NewPrometheusBlock(block, name, label, "", ""))
+	return common.NewDiscoveryExports("discovery.eureka." + label + ".targets")
+}
+
+func ValidateDiscoveryEureka(sdConfig *prom_eureka.SDConfig) diag.Diagnostics {
+	return common.ValidateHttpClientConfig(&sdConfig.HTTPClientConfig)
+}
+
+func toDiscoveryEureka(sdConfig *prom_eureka.SDConfig) *eureka.Arguments {
+	if sdConfig == nil {
+		return nil
+	}
+
+	return &eureka.Arguments{
+		Server:           sdConfig.Server,
+		RefreshInterval:  time.Duration(sdConfig.RefreshInterval),
+		HTTPClientConfig: *common.ToHttpClientConfig(&sdConfig.HTTPClientConfig),
+	}
+}
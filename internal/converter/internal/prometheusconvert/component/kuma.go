@@ -32,6 +32,7 @@ func toDiscoveryKuma(sdConfig *prom_kuma.SDConfig) *kuma.Arguments {
 		Server:          sdConfig.Server,
 		RefreshInterval: time.Duration(sdConfig.RefreshInterval),
 		FetchTimeout:    time.Duration(sdConfig.FetchTimeout),
+		ClientID:        sdConfig.ClientID,
 
 		HTTPClientConfig: *common.ToHttpClientConfig(&sdConfig.HTTPClientConfig),
 	}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	commonCfg "github.com/grafana/alloy/internal/component/common/config"
 	"github.com/grafana/alloy/internal/component/prometheus/remotewrite"
 	"github.com/grafana/alloy/internal/converter/diag"
 	"github.com/grafana/alloy/internal/converter/internal/common"
@@ -109,12 +110,12 @@ func toMetadataOptions(metadataConfig *prom_config.MetadataConfig) *remotewrite.
 }
 
 // toSigV4 converts a Prometheus SigV4 config to an Alloy SigV4 config.
-func toSigV4(sigv4Config *sigv4.SigV4Config) *remotewrite.SigV4Config {
+func toSigV4(sigv4Config *sigv4.SigV4Config) *commonCfg.SigV4Config {
 	if sigv4Config == nil {
 		return nil
 	}
 
-	return &remotewrite.SigV4Config{
+	return &commonCfg.SigV4Config{
 		Region:    sigv4Config.Region,
 		AccessKey: sigv4Config.AccessKey,
 		SecretKey: alloytypes.Secret(sigv4Config.SecretKey),
@@ -124,14 +125,14 @@ func toSigV4(sigv4Config *sigv4.SigV4Config) *remotewrite.SigV4Config {
 }
 
 // toAzureAD converts a Prometheus AzureAD config to an Alloy AzureAD config.
-func toAzureAD(azureADConfig *azuread.AzureADConfig) *remotewrite.AzureADConfig {
+func toAzureAD(azureADConfig *azuread.AzureADConfig) *commonCfg.AzureADConfig {
 	if azureADConfig == nil {
 		return nil
 	}
 
-	return &remotewrite.AzureADConfig{
+	return &commonCfg.AzureADConfig{
 		Cloud: azureADConfig.Cloud,
-		ManagedIdentity: remotewrite.ManagedIdentityConfig{
+		ManagedIdentity: commonCfg.ManagedIdentityConfig{
 			ClientID: azureADConfig.ManagedIdentity.ClientID,
 		},
 	}
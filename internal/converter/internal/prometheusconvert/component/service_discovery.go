@@ -17,6 +17,7 @@ import (
 	prom_consul "github.com/prometheus/prometheus/discovery/consul"
 	prom_digitalocean "github.com/prometheus/prometheus/discovery/digitalocean"
 	prom_dns "github.com/prometheus/prometheus/discovery/dns"
+	prom_eureka "github.com/prometheus/prometheus/discovery/eureka"
 	prom_file "github.com/prometheus/prometheus/discovery/file"
 	prom_gce "github.com/prometheus/prometheus/discovery/gce"
 	prom_ionos "github.com/prometheus/prometheus/discovery/ionos"
@@ -53,6 +54,9 @@ func AppendServiceDiscoveryConfig(pb *build.PrometheusBlocks, serviceDiscoveryCo
 	case *prom_docker.DockerSDConfig:
 		labelCounts["docker"]++
 		return appendDiscoveryDocker(pb, common.LabelWithIndex(labelCounts["docker"]-1, label), sdc)
+	case *prom_eureka.SDConfig:
+		labelCounts["eureka"]++
+		return appendDiscoveryEureka(pb, common.LabelWithIndex(labelCounts["eureka"]-1, label), sdc)
 	case *prom_aws.EC2SDConfig:
 		labelCounts["ec2"]++
 		return appendDiscoveryEC2(pb, common.LabelWithIndex(labelCounts["ec2"]-1, label), sdc)
@@ -123,6 +127,8 @@ func ValidateServiceDiscoveryConfig(serviceDiscoveryConfig prom_discover.Config)
 		return ValidateDiscoveryDns(sdc)
 	case *prom_docker.DockerSDConfig:
 		return ValidateDiscoveryDocker(sdc)
+	case *prom_eureka.SDConfig:
+		return ValidateDiscoveryEureka(sdc)
 	case *prom_aws.EC2SDConfig:
 		return ValidateDiscoveryEC2(sdc)
 	case *prom_file.SDConfig:
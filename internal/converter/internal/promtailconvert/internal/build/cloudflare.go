@@ -15,12 +15,13 @@ func (s *ScrapeConfigBuilder) AppendCloudFlareConfig() {
 	}
 
 	args := cloudflare.Arguments{
-		APIToken:   alloytypes.Secret(s.cfg.CloudflareConfig.APIToken),
-		ZoneID:     s.cfg.CloudflareConfig.ZoneID,
-		Labels:     convertPromLabels(s.cfg.CloudflareConfig.Labels),
-		Workers:    s.cfg.CloudflareConfig.Workers,
-		PullRange:  time.Duration(s.cfg.CloudflareConfig.PullRange),
-		FieldsType: s.cfg.CloudflareConfig.FieldsType,
+		APIToken:    alloytypes.Secret(s.cfg.CloudflareConfig.APIToken),
+		ZoneID:      s.cfg.CloudflareConfig.ZoneID,
+		Labels:      convertPromLabels(s.cfg.CloudflareConfig.Labels),
+		Workers:     s.cfg.CloudflareConfig.Workers,
+		PullRange:   time.Duration(s.cfg.CloudflareConfig.PullRange),
+		FieldsType:  s.cfg.CloudflareConfig.FieldsType,
+		MaxBackfill: cloudflare.DefaultArguments.MaxBackfill,
 	}
 	override := func(val interface{}) interface{} {
 		switch conv := val.(type) {
@@ -20,6 +20,10 @@ func (s *ScrapeConfigBuilder) AppendKafka() {
 		GroupID:              kafkaCfg.GroupID,
 		Assignor:             kafkaCfg.Assignor,
 		Version:              kafkaCfg.Version,
+		InitialOffset:        kafka.DefaultArguments.InitialOffset,
+		SessionTimeout:       kafka.DefaultArguments.SessionTimeout,
+		HeartbeatInterval:    kafka.DefaultArguments.HeartbeatInterval,
+		RebalanceTimeout:     kafka.DefaultArguments.RebalanceTimeout,
 		Authentication:       convertKafkaAuthConfig(kafkaCfg),
 		UseIncomingTimestamp: kafkaCfg.UseIncomingTimestamp,
 		Labels:               convertPromLabels(kafkaCfg.Labels),
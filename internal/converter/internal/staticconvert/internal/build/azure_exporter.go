@@ -27,5 +27,7 @@ func toAzureExporter(config *azure_exporter.Config) *azure.Arguments {
 		AzureCloudEnvironment:    config.AzureCloudEnvironment,
 		ValidateDimensions:       config.ValidateDimensions,
 		Regions:                  config.Regions,
+		BatchMode:                config.BatchMode,
+		BatchSize:                config.BatchSize,
 	}
 }
@@ -34,5 +34,12 @@ func toCadvisorExporter(config *cadvisor_integration.Config) *cadvisor.Arguments
 		DockerTLSCA:                config.DockerTLSCA,
 		DockerOnly:                 config.DockerOnly,
 		DisableRootCgroupStats:     config.DisableRootCgroupStats,
+
+		KubernetesNamespaceAllowlist: config.KubernetesNamespaceAllowlist,
+		KubernetesNamespaceDenylist:  config.KubernetesNamespaceDenylist,
+		KubernetesPodAllowlist:       config.KubernetesPodAllowlist,
+		CgroupPathAllowlist:          config.CgroupPathAllowlist,
+		CgroupPathDenylist:           config.CgroupPathDenylist,
+		DisableFilesystemStats:       config.DisableFilesystemStats,
 	}
 }
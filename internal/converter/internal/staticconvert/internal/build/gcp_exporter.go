@@ -4,6 +4,7 @@ import (
 	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/component/prometheus/exporter/gcp"
 	"github.com/grafana/alloy/internal/static/integrations/gcp_exporter"
+	"github.com/grafana/alloy/syntax/alloytypes"
 )
 
 func (b *ConfigBuilder) appendGcpExporter(config *gcp_exporter.Config, instanceKey *string) discovery.Exports {
@@ -21,5 +22,31 @@ func toGcpExporter(config *gcp_exporter.Config) *gcp.Arguments {
 		IngestDelay:           config.IngestDelay,
 		DropDelegatedProjects: config.DropDelegatedProjects,
 		ClientTimeout:         config.ClientTimeout,
+		ProjectCredentials:    toGcpProjectCredentials(config.ProjectCredentials),
+		AlignedPeriods:        toGcpAlignedPeriods(config.AlignedPeriods),
 	}
 }
+
+func toGcpProjectCredentials(projectCredentials []gcp_exporter.ProjectCredentials) []gcp.ProjectCredentials {
+	var out []gcp.ProjectCredentials
+	for _, pc := range projectCredentials {
+		out = append(out, gcp.ProjectCredentials{
+			ProjectIDs:      pc.ProjectIDs,
+			CredentialsFile: pc.CredentialsFile,
+			Credentials:     alloytypes.Secret(pc.Credentials),
+		})
+	}
+	return out
+}
+
+func toGcpAlignedPeriods(alignedPeriods []gcp_exporter.AlignedPeriod) []gcp.AlignedPeriod {
+	var out []gcp.AlignedPeriod
+	for _, ap := range alignedPeriods {
+		out = append(out, gcp.AlignedPeriod{
+			MetricPrefixes:   ap.MetricPrefixes,
+			Period:           ap.Period,
+			PerSeriesAligner: ap.PerSeriesAligner,
+		})
+	}
+	return out
+}
@@ -27,6 +27,7 @@ func toPostgresExporter(config *postgres_exporter.Config) *postgres.Arguments {
 			Enabled:           config.AutodiscoverDatabases,
 			DatabaseAllowlist: config.IncludeDatabases,
 			DatabaseDenylist:  config.ExcludeDatabases,
+			RefreshInterval:   postgres.DefaultArguments.AutoDiscovery.RefreshInterval,
 		},
 	}
 }
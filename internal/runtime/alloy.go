@@ -56,6 +56,7 @@ import (
 
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/internal/controller"
+	"github.com/grafana/alloy/internal/runtime/internal/dag"
 	"github.com/grafana/alloy/internal/runtime/internal/worker"
 	"github.com/grafana/alloy/internal/runtime/logging"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
@@ -110,6 +111,28 @@ type Options struct {
 
 	// EnableCommunityComps enables the use of community components.
 	EnableCommunityComps bool
+
+	// DryRun evaluates loaded config sources without building components or
+	// updating services. It's used to validate config sources without side
+	// effects, and the controller must not be run when it's set.
+	DryRun bool
+
+	// EvaluationConcurrency is the number of nodes evaluated at the same time
+	// when a config source is loaded. Nodes which don't depend on each other
+	// are evaluated concurrently when it is greater than 1. Defaults to 1.
+	EvaluationConcurrency int
+
+	// EvaluationTimeout is how long loading a config source waits for the
+	// evaluation of a component before evaluating its dependants without it.
+	// The component is reported as unhealthy until its evaluation finishes.
+	// Zero means no timeout.
+	EvaluationTimeout time.Duration
+
+	// StartupGateTimeout is how long a component waits for the components it
+	// references to be ready before it starts, so that sources don't send
+	// data to sinks which can't receive it yet. The component starts anyway
+	// once the timeout elapses. Zero disables the startup gate.
+	StartupGateTimeout time.Duration
 }
 
 // Runtime is the Alloy system.
@@ -197,6 +220,7 @@ func newController(o controllerOptions) *Runtime {
 			DataPath:             o.DataPath,
 			MinStability:         o.MinStability,
 			EnableCommunityComps: o.EnableCommunityComps,
+			DryRun:               o.DryRun,
 			OnBlockNodeUpdate: func(cn controller.BlockNode) {
 				// Changed node should be queued for reevaluation.
 				f.updateQueue.Enqueue(&controller.QueuedNode{Node: cn, LastUpdatedTime: time.Now()})
@@ -206,17 +230,21 @@ func newController(o controllerOptions) *Runtime {
 			ControllerID:    o.ControllerID,
 			NewModuleController: func(id string) controller.ModuleController {
 				return newModuleController(&moduleControllerOptions{
-					ComponentRegistry:    o.ComponentRegistry,
-					ModuleRegistry:       o.ModuleRegistry,
-					Logger:               log,
-					Tracer:               tracer,
-					Reg:                  o.Reg,
-					DataPath:             o.DataPath,
-					MinStability:         o.MinStability,
-					EnableCommunityComps: o.EnableCommunityComps,
-					ID:                   id,
-					ServiceMap:           serviceMap,
-					WorkerPool:           workerPool,
+					ComponentRegistry:     o.ComponentRegistry,
+					ModuleRegistry:        o.ModuleRegistry,
+					Logger:                log,
+					Tracer:                tracer,
+					Reg:                   o.Reg,
+					DataPath:              o.DataPath,
+					MinStability:          o.MinStability,
+					EnableCommunityComps:  o.EnableCommunityComps,
+					DryRun:                o.DryRun,
+					EvaluationConcurrency: o.EvaluationConcurrency,
+					EvaluationTimeout:     o.EvaluationTimeout,
+					StartupGateTimeout:    o.StartupGateTimeout,
+					ID:                    id,
+					ServiceMap:            serviceMap,
+					WorkerPool:            workerPool,
 				})
 			},
 			GetServiceData: func(name string) (interface{}, error) {
@@ -228,10 +256,22 @@ func newController(o controllerOptions) *Runtime {
 			},
 		},
 
-		Services:          o.Services,
-		Host:              f,
-		ComponentRegistry: o.ComponentRegistry,
-		WorkerPool:        workerPool,
+		Services:              o.Services,
+		Host:                  f,
+		ComponentRegistry:     o.ComponentRegistry,
+		WorkerPool:            workerPool,
+		EvaluationConcurrency: o.EvaluationConcurrency,
+		EvaluationTimeout:     o.EvaluationTimeout,
+		OnDelayedEvaluation: func(bn controller.BlockNode) {
+			// The component may have been built after the scheduler tried to
+			// run it, and its dependants were evaluated without its exports.
+			f.updateQueue.Enqueue(&controller.QueuedNode{Node: bn, LastUpdatedTime: time.Now()})
+			select {
+			case f.loadFinished <- struct{}{}:
+			default:
+				// A refresh is already scheduled
+			}
+		},
 	})
 
 	return f
@@ -240,6 +280,9 @@ func newController(o controllerOptions) *Runtime {
 // Run starts the Alloy controller, blocking until the provided context is
 // canceled. Run must only be called once.
 func (f *Runtime) Run(ctx context.Context) {
+	// The secrets of the components are removed once they stopped, so that
+	// they're still redacted from what the components log while stopping.
+	defer f.loader.RemoveSecrets()
 	defer func() { _ = f.sched.Close() }()
 	defer f.loader.Cleanup(!f.opts.IsModule)
 	defer level.Debug(f.log).Log("msg", "Alloy controller exiting")
@@ -258,35 +301,64 @@ func (f *Runtime) Run(ctx context.Context) {
 		case <-f.loadFinished:
 			level.Info(f.log).Log("msg", "scheduling loaded components and services")
 
-			var (
-				components = f.loader.Components()
-				services   = f.loader.Services()
-				imports    = f.loader.Imports()
-
-				runnables = make([]controller.RunnableNode, 0, len(components)+len(services)+len(imports))
-			)
-			for _, c := range components {
-				runnables = append(runnables, c)
+			err := f.sched.Synchronize(f.runnables())
+			if err != nil {
+				level.Error(f.log).Log("msg", "failed to load components and services", "err", err)
 			}
+		}
+	}
+}
 
-			for _, i := range imports {
-				runnables = append(runnables, i)
-			}
+// runnables returns the nodes which should be run by the scheduler. Paused
+// components aren't run.
+func (f *Runtime) runnables() []controller.RunnableNode {
+	var (
+		components = f.loader.Components()
+		services   = f.loader.Services()
+		imports    = f.loader.Imports()
 
-			// Only the root controller should run services, since modules share the
-			// same service instance as the root.
-			if !f.opts.IsModule {
-				for _, svc := range services {
-					runnables = append(runnables, svc)
-				}
-			}
+		graph = f.loader.Graph()
 
-			err := f.sched.Synchronize(runnables)
-			if err != nil {
-				level.Error(f.log).Log("msg", "failed to load components and services", "err", err)
-			}
+		runnables = make([]controller.RunnableNode, 0, len(components)+len(services)+len(imports))
+	)
+	for _, c := range components {
+		if bc, ok := c.(*controller.BuiltinComponentNode); ok && bc.Paused() {
+			continue
+		}
+		runnables = append(runnables, f.gateRunnable(graph, c))
+	}
+
+	for _, i := range imports {
+		runnables = append(runnables, i)
+	}
+
+	// Only the root controller should run services, since modules share the
+	// same service instance as the root.
+	if !f.opts.IsModule {
+		for _, svc := range services {
+			runnables = append(runnables, svc)
 		}
 	}
+	return runnables
+}
+
+// gateRunnable returns r gated on the readiness of the nodes it references,
+// if the startup gate is enabled.
+func (f *Runtime) gateRunnable(graph *dag.Graph, r controller.RunnableNode) controller.RunnableNode {
+	if f.opts.StartupGateTimeout <= 0 {
+		return r
+	}
+
+	var deps []controller.ReadyNode
+	for _, dep := range graph.Dependencies(r) {
+		if rn, ok := dep.(controller.ReadyNode); ok {
+			deps = append(deps, rn)
+		}
+	}
+	if len(deps) == 0 {
+		return r
+	}
+	return controller.GateRunnable(f.log, r, deps, f.opts.StartupGateTimeout)
 }
 
 // LoadSource synchronizes the state of the controller with the current config
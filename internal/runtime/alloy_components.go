@@ -37,6 +37,83 @@ func (f *Runtime) GetComponent(id component.ID, opts component.InfoOptions) (*co
 	return f.getComponentDetail(cn, graph, opts), nil
 }
 
+// PauseComponent implements [service.Host].
+func (f *Runtime) PauseComponent(id component.ID) error {
+	f.loadMut.RLock()
+	defer f.loadMut.RUnlock()
+
+	if id.ModuleID != "" {
+		mod, ok := f.modules.Get(id.ModuleID)
+		if !ok {
+			return component.ErrComponentNotFound
+		}
+
+		return mod.f.PauseComponent(component.ID{LocalID: id.LocalID})
+	}
+
+	cn, err := f.getBuiltinComponent(id)
+	if err != nil {
+		return err
+	}
+	if cn.Paused() {
+		return nil
+	}
+
+	cn.Pause()
+	// Synchronize waits for the component to stop.
+	return f.sched.Synchronize(f.runnables())
+}
+
+// ResumeComponent implements [service.Host].
+func (f *Runtime) ResumeComponent(id component.ID) error {
+	f.loadMut.RLock()
+	defer f.loadMut.RUnlock()
+
+	if id.ModuleID != "" {
+		mod, ok := f.modules.Get(id.ModuleID)
+		if !ok {
+			return component.ErrComponentNotFound
+		}
+
+		return mod.f.ResumeComponent(component.ID{LocalID: id.LocalID})
+	}
+
+	cn, err := f.getBuiltinComponent(id)
+	if err != nil {
+		return err
+	}
+	if !cn.Paused() {
+		return nil
+	}
+
+	cn.Resume()
+	// The component is built again with its latest arguments. It's still
+	// scheduled if that fails, so that its health reports the error.
+	evalErr := f.loader.EvaluateNode(cn.NodeID())
+	if err := f.sched.Synchronize(f.runnables()); err != nil {
+		return err
+	}
+	return evalErr
+}
+
+func (f *Runtime) getBuiltinComponent(id component.ID) (*controller.BuiltinComponentNode, error) {
+	node := f.loader.OriginalGraph().GetByID(id.LocalID)
+	if node == nil {
+		return nil, component.ErrComponentNotFound
+	}
+
+	cn, ok := node.(*controller.BuiltinComponentNode)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a builtin component", id)
+	}
+	return cn, nil
+}
+
+// GetReloadReport implements [service.Host].
+func (f *Runtime) GetReloadReport() *component.ReloadReport {
+	return f.loader.ReloadReport()
+}
+
 // ListComponents implements [component.Provider].
 func (f *Runtime) ListComponents(moduleID string, opts component.InfoOptions) ([]*component.Info, error) {
 	f.loadMut.RLock()
@@ -124,6 +201,7 @@ func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Gra
 
 	if builtinComponent, ok := cn.(*controller.BuiltinComponentNode); ok {
 		componentInfo.Component = builtinComponent.Component()
+		componentInfo.Paused = builtinComponent.Paused()
 		if opts.GetDebugInfo {
 			componentInfo.DebugInfo = builtinComponent.DebugInfo()
 		}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 
+	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/runtime/internal/controller"
 	"github.com/grafana/alloy/internal/runtime/internal/dag"
 	"github.com/grafana/alloy/internal/service"
@@ -72,14 +73,18 @@ func (f *Runtime) NewController(id string) service.Controller {
 	return serviceController{
 		f: newController(controllerOptions{
 			Options: Options{
-				ControllerID:    id,
-				Logger:          f.opts.Logger,
-				Tracer:          f.opts.Tracer,
-				DataPath:        f.opts.DataPath,
-				MinStability:    f.opts.MinStability,
-				Reg:             f.opts.Reg,
-				Services:        f.opts.Services,
-				OnExportsChange: nil, // NOTE(@tpaschalis, @wildum) The isolated controller shouldn't be able to export any values.
+				ControllerID:          id,
+				Logger:                f.opts.Logger,
+				Tracer:                f.opts.Tracer,
+				DataPath:              f.opts.DataPath,
+				MinStability:          f.opts.MinStability,
+				Reg:                   f.opts.Reg,
+				Services:              f.opts.Services,
+				DryRun:                f.opts.DryRun,
+				EvaluationConcurrency: f.opts.EvaluationConcurrency,
+				EvaluationTimeout:     f.opts.EvaluationTimeout,
+				StartupGateTimeout:    f.opts.StartupGateTimeout,
+				OnExportsChange:       nil, // NOTE(@tpaschalis, @wildum) The isolated controller shouldn't be able to export any values.
 			},
 			IsModule:       true,
 			ModuleRegistry: newModuleRegistry(),
@@ -101,3 +106,9 @@ func (sc serviceController) LoadSource(b []byte, args map[string]any) error {
 	return sc.f.LoadSource(source, args)
 }
 func (sc serviceController) Ready() bool { return sc.f.Ready() }
+func (sc serviceController) GetComponent(id component.ID, opts component.InfoOptions) (*component.Info, error) {
+	return sc.f.GetComponent(id, opts)
+}
+func (sc serviceController) ListComponents(moduleID string, opts component.InfoOptions) ([]*component.Info, error) {
+	return sc.f.ListComponents(moduleID, opts)
+}
@@ -2,8 +2,10 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -54,6 +56,213 @@ func TestController_LoadSource_Evaluation(t *testing.T) {
 	require.Equal(t, "hello, world!", out.(testcomponents.PassthroughExports).Output)
 }
 
+func TestController_LoadSource_DryRun(t *testing.T) {
+	opts := testOptions(t)
+	opts.DryRun = true
+	ctrl := New(opts)
+
+	f, err := ParseSource(t.Name(), []byte(testFile))
+	require.NoError(t, err)
+
+	err = ctrl.LoadSource(f, nil)
+	require.NoError(t, err)
+	require.Len(t, ctrl.loader.Components(), 4)
+
+	// Arguments are evaluated, but components aren't built so they never
+	// update their exports.
+	in, out := getFields(t, ctrl.loader.Graph(), "testcomponents.passthrough.static")
+	require.Equal(t, "hello, world!", in.(testcomponents.PassthroughConfig).Input)
+	require.Equal(t, "", out.(testcomponents.PassthroughExports).Output)
+
+	f, err = ParseSource(t.Name(), []byte(`
+		testcomponents.tick "ticker" {
+			frequency = "not a duration"
+		}
+	`))
+	require.NoError(t, err)
+	require.ErrorContains(t, ctrl.LoadSource(f, nil), `"not a duration"`)
+}
+
+func TestController_PauseComponent(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+	ctrl := New(testOptions(t))
+
+	load := func(input string) {
+		f, err := ParseSource(t.Name(), []byte(fmt.Sprintf(`
+			testcomponents.passthrough "static" {
+				input = %q
+			}
+		`, input)))
+		require.NoError(t, err)
+		require.NoError(t, ctrl.LoadSource(f, nil))
+	}
+	load("hello")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	id := component.ID{LocalID: "testcomponents.passthrough.static"}
+	getInfo := func() *component.Info {
+		info, err := ctrl.GetComponent(id, component.InfoOptions{GetHealth: true})
+		require.NoError(t, err)
+		return info
+	}
+	require.Eventually(t, func() bool {
+		return getInfo().Health.Message == "started component"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// The component stopped once PauseComponent returns.
+	require.NoError(t, ctrl.PauseComponent(id))
+	info := getInfo()
+	require.True(t, info.Paused)
+	require.Equal(t, component.HealthTypeExited, info.Health.Health)
+	require.Equal(t, "component paused", info.Health.Message)
+
+	// The paused component isn't updated or run again by a reload.
+	load("world")
+	in, out := getFields(t, ctrl.loader.Graph(), id.LocalID)
+	require.Equal(t, "world", in.(testcomponents.PassthroughConfig).Input)
+	require.Equal(t, "hello", out.(testcomponents.PassthroughExports).Output)
+	require.True(t, getInfo().Paused)
+
+	// Resuming the component builds it again with its latest arguments.
+	require.NoError(t, ctrl.ResumeComponent(id))
+	_, out = getFields(t, ctrl.loader.Graph(), id.LocalID)
+	require.Equal(t, "world", out.(testcomponents.PassthroughExports).Output)
+	require.False(t, getInfo().Paused)
+	require.Eventually(t, func() bool {
+		return getInfo().Health.Message == "started component"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	missing := component.ID{LocalID: "testcomponents.passthrough.missing"}
+	require.ErrorIs(t, ctrl.PauseComponent(missing), component.ErrComponentNotFound)
+	require.ErrorIs(t, ctrl.ResumeComponent(missing), component.ErrComponentNotFound)
+}
+
+type slowExports struct {
+	Value string `alloy:"value,attr"`
+}
+
+type dependantArguments struct {
+	Input string `alloy:"input,attr"`
+}
+
+func TestController_EvaluationTimeout(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+
+	var (
+		release = make(chan struct{})
+		inputs  = make(chan string, 1)
+	)
+	registry := controller.NewRegistryMap(
+		featuregate.StabilityGenerallyAvailable,
+		false,
+		map[string]component.Registration{
+			"slow": {
+				Name:      "slow",
+				Stability: featuregate.StabilityGenerallyAvailable,
+				Args:      struct{}{},
+				Exports:   slowExports{},
+				Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+					<-release
+					opts.OnStateChange(slowExports{Value: "ready"})
+					return &testcomponents.Fake{}, nil
+				},
+			},
+			"dependant": {
+				Name:      "dependant",
+				Stability: featuregate.StabilityGenerallyAvailable,
+				Args:      dependantArguments{},
+				Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+					inputs <- args.(dependantArguments).Input
+					return &testcomponents.Fake{}, nil
+				},
+			},
+			"fast": {
+				Name:      "fast",
+				Stability: featuregate.StabilityGenerallyAvailable,
+				Args:      struct{}{},
+				Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+					return &testcomponents.Fake{}, nil
+				},
+			},
+		},
+	)
+
+	opts := testOptions(t)
+	opts.EvaluationTimeout = 100 * time.Millisecond
+	ctrl := newController(controllerOptions{
+		Options:           opts,
+		ComponentRegistry: registry,
+		ModuleRegistry:    newModuleRegistry(),
+	})
+
+	f, err := ParseSource(t.Name(), []byte(`
+		slow "a" {}
+		fast "b" {}
+		dependant "c" {
+			input = slow.a.value
+		}
+	`))
+	require.NoError(t, err)
+
+	// The load doesn't wait for the slow component to be built, and defers
+	// the evaluation of the component which references it.
+	require.NoError(t, ctrl.LoadSource(f, nil))
+	report := ctrl.loader.ReloadReport()
+	require.Len(t, report.Components, 3)
+	require.Equal(t, component.ApplyOutcomeDeferred, report.Components[0].Outcome)
+	require.Equal(t, component.ApplyOutcomeApplied, report.Components[1].Outcome)
+	require.Equal(t, component.ApplyOutcomeTimedOut, report.Components[2].Outcome)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	getHealth := func(id string) component.Health {
+		info, err := ctrl.GetComponent(component.ID{LocalID: id}, component.InfoOptions{GetHealth: true})
+		require.NoError(t, err)
+		return info.Health
+	}
+	// The slow component is being built, so its info can't be retrieved yet.
+	slow := ctrl.loader.Graph().GetByID("slow.a").(*controller.BuiltinComponentNode)
+	require.Equal(t, component.HealthTypeUnhealthy, slow.CurrentHealth().Health)
+	dependant := ctrl.loader.Graph().GetByID("dependant.c").(*controller.BuiltinComponentNode)
+	require.Equal(t, component.HealthTypeUnhealthy, dependant.CurrentHealth().Health)
+	require.Eventually(t, func() bool {
+		return getHealth("fast.b").Message == "started component"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// Once built, the slow component is run, and the deferred component is
+	// built with its exports.
+	close(release)
+	select {
+	case input := <-inputs:
+		require.Equal(t, "ready", input)
+	case <-time.After(3 * time.Second):
+		require.FailNow(t, "the deferred component wasn't built")
+	}
+	require.Eventually(t, func() bool {
+		return getHealth("slow.a").Message == "started component" &&
+			getHealth("dependant.c").Message == "started component"
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
 func getFields(t *testing.T, g *dag.Graph, nodeID string) (component.Arguments, component.Exports) {
 	t.Helper()
 
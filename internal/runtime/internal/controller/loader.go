@@ -5,15 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/internal/dag"
 	"github.com/grafana/alloy/internal/runtime/internal/worker"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/grafana/alloy/internal/runtime/tracing"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/syntax/ast"
@@ -39,6 +42,16 @@ type Loader struct {
 	// also prevents log spamming with errors.
 	backoffConfig backoff.Config
 
+	evaluationConcurrency int
+	evaluationTimeout     time.Duration
+	onDelayedEvaluation   func(bn BlockNode)
+
+	// pending holds the IDs of the new components whose evaluation timed out
+	// during the last Apply, and of the components whose evaluation was
+	// deferred until those finish.
+	pendingMut sync.Mutex
+	pending    map[string]struct{}
+
 	mut                  sync.RWMutex
 	graph                *dag.Graph
 	originalGraph        *dag.Graph
@@ -52,6 +65,7 @@ type Loader struct {
 	cc                   *controllerCollector
 	moduleExportIndex    int
 	componentNodeManager *ComponentNodeManager
+	report               *component.ReloadReport // Report of the most recent call to Apply
 }
 
 // LoaderOptions holds options for creating a Loader.
@@ -63,6 +77,19 @@ type LoaderOptions struct {
 	Host              service.Host      // Service host (when running services).
 	ComponentRegistry ComponentRegistry // Registry to search for components.
 	WorkerPool        worker.Pool       // Worker pool to use for async tasks.
+
+	// EvaluationConcurrency is the number of nodes evaluated at the same time
+	// by Apply. Defaults to 1.
+	EvaluationConcurrency int
+
+	// EvaluationTimeout is how long Apply waits for the evaluation of a
+	// component before evaluating its dependants without it. Zero means no
+	// timeout.
+	EvaluationTimeout time.Duration
+
+	// OnDelayedEvaluation is invoked when the evaluation of a component which
+	// exceeded EvaluationTimeout finishes.
+	OnDelayedEvaluation func(bn BlockNode)
 }
 
 // NewLoader creates a new Loader. Components built by the Loader will be built
@@ -89,6 +116,10 @@ func NewLoader(opts LoaderOptions) *Loader {
 		host:       host,
 		workerPool: opts.WorkerPool,
 
+		evaluationConcurrency: max(opts.EvaluationConcurrency, 1),
+		evaluationTimeout:     opts.EvaluationTimeout,
+		onDelayedEvaluation:   opts.OnDelayedEvaluation,
+
 		componentNodeManager: NewComponentNodeManager(globals, reg),
 
 		// This is a reasonable default which should work for most cases. If a component is completely stuck, we would
@@ -136,7 +167,10 @@ type ApplyOptions struct {
 // matches the component ID specified by any of the provided Alloy blocks.
 // Reused components will be updated to point at the new Alloy block.
 //
-// Apply will perform an evaluation of all loaded components before returning.
+// Apply will perform an evaluation of all loaded components before returning,
+// except for the existing components whose block and dependencies didn't
+// change. A report of the changes is available from ReloadReport once Apply
+// returns.
 // The provided parentContext can be used to provide global variables and
 // functions to components. A child context will be constructed from the parent
 // to expose values of other components.
@@ -154,9 +188,36 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 
 	// Create a new CustomComponentRegistry based on the provided one.
 	// The provided one should be nil for the root config.
+	report := &component.ReloadReport{StartTime: start}
+	defer func() {
+		report.DurationSeconds = time.Since(start).Seconds()
+		l.report = report
+	}()
+
+	// Keep the blocks and arguments of the existing components before they're
+	// updated, to report how they changed and to skip the unchanged ones.
+	previous := make(map[string]previousComponent, len(l.componentNodes))
+	for _, n := range l.componentNodes {
+		previous[n.NodeID()] = previousComponent{block: n.Block(), args: n.Arguments()}
+	}
+
+	// The whole load is traced, so that the time spent building the graph
+	// shows next to the time spent evaluating it.
+	tracer := l.tracer.Tracer("")
+	applyCtx, applySpan := tracer.Start(context.Background(), "ApplyConfig", trace.WithSpanKind(trace.SpanKindInternal))
+	applySpan.SetAttributes(attribute.Int("component_blocks", len(options.ComponentBlocks)))
+	defer applySpan.End()
+
 	l.componentNodeManager.setCustomComponentRegistry(NewCustomComponentRegistry(options.CustomComponentRegistry))
+	_, loadSpan := tracer.Start(applyCtx, "LoadGraph", trace.WithSpanKind(trace.SpanKindInternal))
 	newGraph, diags := l.loadNewGraph(options.Args, options.ComponentBlocks, options.ConfigBlocks, options.DeclareBlocks)
 	if diags.HasErrors() {
+		loadSpan.SetStatus(codes.Error, diags.Error())
+		applySpan.SetStatus(codes.Error, diags.Error())
+	}
+	loadSpan.End()
+	if diags.HasErrors() {
+		report.Error = diags.Error()
 		return diags
 	}
 
@@ -164,14 +225,20 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 		components   = make([]ComponentNode, 0)
 		componentIDs = make([]ComponentID, 0)
 		services     = make([]*ServiceNode, 0, len(l.services))
+
+		// evalMut protects the results of the evaluations, which run
+		// concurrently.
+		evalMut   sync.Mutex
+		skipped   = make(map[string]struct{})
+		pending   = make(map[string]struct{})
+		nodeDiags = make(map[string]diag.Diagnostics)
 	)
 
-	tracer := l.tracer.Tracer("")
-	spanCtx, span := tracer.Start(context.Background(), "GraphEvaluate", trace.WithSpanKind(trace.SpanKindInternal))
+	spanCtx, span := tracer.Start(applyCtx, "GraphEvaluate", trace.WithSpanKind(trace.SpanKindInternal))
 	defer span.End()
 
 	logger := log.With(l.log, "trace_id", span.SpanContext().TraceID())
-	level.Info(logger).Log("msg", "starting complete graph evaluation")
+	level.Info(logger).Log("msg", "starting complete graph evaluation", "concurrency", l.evaluationConcurrency)
 	defer func() {
 		span.SetStatus(codes.Ok, "")
 
@@ -180,8 +247,9 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 
 	l.cache.ClearModuleExports()
 
-	// Evaluate all the components.
-	_ = dag.WalkTopological(&newGraph, newGraph.Leaves(), func(n dag.Node) error {
+	// Evaluate all the nodes. A node is evaluated once all of its dependencies
+	// were, so independent parts of the graph are evaluated concurrently.
+	_ = dag.WalkTopologicalConcurrent(&newGraph, newGraph.Leaves(), l.evaluationConcurrency, evaluationPriority(&newGraph), func(n dag.Node) error {
 		_, span := tracer.Start(spanCtx, "EvaluateNode", trace.WithSpanKind(trace.SpanKindInternal))
 		span.SetAttributes(attribute.String("node_id", n.NodeID()))
 		defer span.End()
@@ -191,14 +259,65 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 			level.Info(logger).Log("msg", "finished node evaluation", "node_id", n.NodeID(), "duration", time.Since(start))
 		}()
 
-		var err error
+		var (
+			err   error
+			diags diag.Diagnostics
+		)
 
 		switch n := n.(type) {
 		case ComponentNode:
-			components = append(components, n)
-			componentIDs = append(componentIDs, n.ID())
+			prev, existed := previous[n.NodeID()]
+
+			evalMut.Lock()
+			deferred := dependsOnPending(&newGraph, n, pending)
+			if deferred {
+				// The exports of the dependency aren't known yet, so the
+				// component would fail to evaluate.
+				pending[n.NodeID()] = struct{}{}
+				report.Components = append(report.Components, unevaluatedComponentChange(n, existed, component.ApplyOutcomeDeferred))
+			}
+			evalMut.Unlock()
+			if deferred {
+				if n, ok := n.(evalHealthSetter); ok {
+					n.setEvalHealth(component.HealthTypeUnhealthy, "component evaluation deferred until the components it references are evaluated")
+				}
+				break
+			}
 
-			if err = l.evaluate(logger, n); err != nil {
+			evalMut.Lock()
+			unchanged := existed && unchangedComponent(&newGraph, n, prev.block, skipped)
+			if unchanged {
+				// Evaluating the component again would give the same arguments.
+				skipped[n.NodeID()] = struct{}{}
+				report.Components = append(report.Components, component.ComponentChange{
+					ID:      n.NodeID(),
+					Change:  component.ChangeTypeUnchanged,
+					Outcome: component.ApplyOutcomeSkipped,
+				})
+			}
+			evalMut.Unlock()
+			if unchanged {
+				break
+			}
+
+			err = l.evaluateWithTimeout(logger, n)
+			var change component.ComponentChange
+			switch {
+			case errors.Is(err, errEvaluationTimeout):
+				// The timeout is reported in the health of the component
+				// rather than as an error of the load. The dependants of a
+				// new component are deferred, as its exports aren't cached
+				// yet; the others are evaluated with its previous exports.
+				change = unevaluatedComponentChange(n, existed, component.ApplyOutcomeTimedOut)
+				if !existed {
+					evalMut.Lock()
+					pending[n.NodeID()] = struct{}{}
+					evalMut.Unlock()
+				}
+			default:
+				change = evaluatedComponentChange(n, existed, prev.args, err)
+			}
+			if err != nil && !errors.Is(err, errEvaluationTimeout) {
 				var evalDiags diag.Diagnostics
 				if errors.As(err, &evalDiags) {
 					diags = append(diags, evalDiags...)
@@ -212,9 +331,11 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 				}
 			}
 
-		case *ServiceNode:
-			services = append(services, n)
+			evalMut.Lock()
+			report.Components = append(report.Components, change)
+			evalMut.Unlock()
 
+		case *ServiceNode:
 			if err = l.evaluate(logger, n); err != nil {
 				var evalDiags diag.Diagnostics
 				if errors.As(err, &evalDiags) {
@@ -243,6 +364,12 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 			}
 		}
 
+		if len(diags) > 0 {
+			evalMut.Lock()
+			nodeDiags[n.NodeID()] = diags
+			evalMut.Unlock()
+		}
+
 		// We only use the error for updating the span status; we don't return the
 		// error because we want to evaluate as many nodes as we can.
 		if err != nil {
@@ -253,6 +380,45 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 		return nil
 	})
 
+	// The nodes and their diagnostics are kept in topological order,
+	// regardless of the order in which they were evaluated.
+	_ = dag.WalkTopological(&newGraph, newGraph.Leaves(), func(n dag.Node) error {
+		switch n := n.(type) {
+		case ComponentNode:
+			components = append(components, n)
+			componentIDs = append(componentIDs, n.ID())
+		case *ServiceNode:
+			services = append(services, n)
+		}
+		diags = append(diags, nodeDiags[n.NodeID()]...)
+		return nil
+	})
+
+	for id := range previous {
+		if newGraph.GetByID(id) == nil {
+			report.Components = append(report.Components, component.ComponentChange{
+				ID:      id,
+				Change:  component.ChangeTypeRemoved,
+				Outcome: component.ApplyOutcomeApplied,
+			})
+		}
+	}
+	sort.Slice(report.Components, func(i, j int) bool {
+		return report.Components[i].ID < report.Components[j].ID
+	})
+	logReloadReport(logger, report)
+
+	// Forget the secrets of the removed components.
+	for _, n := range l.componentNodes {
+		if bn, ok := n.(*BuiltinComponentNode); ok && newGraph.GetByID(n.NodeID()) == nil {
+			secrets.Default.Remove(bn.globalID)
+		}
+	}
+
+	l.pendingMut.Lock()
+	l.pending = pending
+	l.pendingMut.Unlock()
+
 	l.componentNodes = components
 	l.serviceNodes = services
 	l.graph = &newGraph
@@ -262,6 +428,12 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 		l.moduleExportIndex = l.cache.ExportChangeIndex()
 		l.globals.OnExportsChange(l.cache.CreateModuleExports())
 	}
+
+	if diags.HasErrors() {
+		applySpan.SetStatus(codes.Error, diags.Error())
+	} else {
+		applySpan.SetStatus(codes.Ok, "")
+	}
 	return diags
 }
 
@@ -277,6 +449,20 @@ func (l *Loader) Cleanup(stopWorkerPool bool) {
 	l.globals.Registerer.Unregister(l.cc)
 }
 
+// RemoveSecrets removes the secrets of all the components from
+// secrets.Default. It's called once the components of the controller stopped
+// running, for good.
+func (l *Loader) RemoveSecrets() {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	for _, n := range l.componentNodes {
+		if bn, ok := n.(*BuiltinComponentNode); ok {
+			secrets.Default.Remove(bn.globalID)
+		}
+	}
+}
+
 // loadNewGraph creates a new graph from the provided blocks and validates it.
 func (l *Loader) loadNewGraph(args map[string]any, componentBlocks []*ast.BlockStmt, configBlocks []*ast.BlockStmt, declareBlocks []*ast.BlockStmt) (dag.Graph, diag.Diagnostics) {
 	var g dag.Graph
@@ -421,7 +607,7 @@ func (l *Loader) populateServiceNodes(g *dag.Graph, serviceBlocks []*ast.BlockSt
 		if exist := l.graph.GetByID(id); exist != nil {
 			node = exist.(*ServiceNode)
 		} else {
-			node = NewServiceNode(l.host, svc)
+			node = NewServiceNode(l.host, svc, l.globals.DryRun)
 		}
 
 		node.UpdateBlock(nil) // Reset configuration to nil.
@@ -824,6 +1010,169 @@ func (l *Loader) concurrentEvalFn(n dag.Node, spanCtx context.Context, tracer tr
 	}
 }
 
+// EvaluateNode evaluates the block node with the given ID again. Its
+// dependants are queued for evaluation if its exports change.
+func (l *Loader) EvaluateNode(nodeID string) error {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	bn, ok := l.graph.GetByID(nodeID).(BlockNode)
+	if !ok {
+		return fmt.Errorf("%q is not a block node", nodeID)
+	}
+	return l.evaluate(l.log, bn)
+}
+
+// errEvaluationTimeout is returned by evaluateWithTimeout when the evaluation
+// of a node exceeds the evaluation timeout.
+var errEvaluationTimeout = errors.New("evaluation timed out")
+
+// evaluationPriority returns the priority of the nodes of g for Apply. Nodes
+// with a lower priority are evaluated first when more nodes are ready to be
+// evaluated than the evaluation concurrency.
+func evaluationPriority(g *dag.Graph) func(dag.Node) int {
+	const (
+		priorityConfig     = iota // Config blocks and services, which are fast to evaluate.
+		priorityDependency        // Components which other nodes depend on.
+		priorityComponent         // Other components.
+	)
+
+	return func(n dag.Node) int {
+		switch {
+		case !isComponentNode(n):
+			return priorityConfig
+		case len(g.Dependants(n)) > 0:
+			return priorityDependency
+		default:
+			return priorityComponent
+		}
+	}
+}
+
+func isComponentNode(n dag.Node) bool {
+	_, ok := n.(ComponentNode)
+	return ok
+}
+
+// evalHealthSetter is implemented by the nodes which report the health of
+// their last evaluation.
+type evalHealthSetter interface {
+	setEvalHealth(t component.HealthType, msg string)
+}
+
+// evaluateWithTimeout evaluates bn like evaluate, but stops waiting for the
+// evaluation once the evaluation timeout elapses, so that a slow component
+// doesn't hold back the evaluation of the rest of the graph.
+//
+// When the timeout elapses, bn is reported as unhealthy and
+// errEvaluationTimeout is returned. The evaluation continues in the
+// background; once it finishes, the components deferred until then are
+// evaluated and onDelayedEvaluation is invoked. Only component nodes may be
+// evaluated with a timeout, as postEvaluate then only updates the value cache,
+// which is safe to do without holding mut.
+func (l *Loader) evaluateWithTimeout(logger log.Logger, bn ComponentNode) error {
+	if l.evaluationTimeout <= 0 {
+		return l.evaluate(logger, bn)
+	}
+
+	var (
+		mut      sync.Mutex
+		timedOut bool
+		done     = make(chan error, 1)
+	)
+	go func() {
+		err := l.evaluate(logger, bn)
+
+		mut.Lock()
+		defer mut.Unlock()
+		if !timedOut {
+			done <- err
+			return
+		}
+
+		// The health was overwritten when the timeout elapsed.
+		if n, ok := bn.(evalHealthSetter); ok {
+			if err != nil {
+				n.setEvalHealth(component.HealthTypeUnhealthy, fmt.Sprintf("component evaluation failed: %s", err))
+			} else {
+				n.setEvalHealth(component.HealthTypeHealthy, "component evaluated")
+			}
+		}
+		level.Info(logger).Log("msg", "finished delayed node evaluation", "node_id", bn.NodeID(), "err", err)
+		l.evaluatePending(logger, bn)
+		if l.onDelayedEvaluation != nil {
+			l.onDelayedEvaluation(bn)
+		}
+	}()
+
+	timer := time.NewTimer(l.evaluationTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	select {
+	case err := <-done:
+		// The evaluation finished while the timeout elapsed.
+		return err
+	default:
+	}
+
+	timedOut = true
+	level.Warn(logger).Log("msg", "node evaluation exceeded the evaluation timeout, evaluating its dependants without it", "node_id", bn.NodeID(), "timeout", l.evaluationTimeout)
+	if n, ok := bn.(evalHealthSetter); ok {
+		n.setEvalHealth(component.HealthTypeUnhealthy, fmt.Sprintf("component evaluation didn't finish within %s", l.evaluationTimeout))
+	}
+	return errEvaluationTimeout
+}
+
+// dependsOnPending returns whether n references a node of pending.
+func dependsOnPending(g *dag.Graph, n dag.Node, pending map[string]struct{}) bool {
+	for _, dep := range g.Dependencies(n) {
+		if _, ok := pending[dep.NodeID()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluatePending evaluates the components which were deferred by Apply until
+// the evaluation of bn, a new component whose evaluation timed out, finished.
+// Components which still reference another pending component stay deferred.
+func (l *Loader) evaluatePending(logger log.Logger, bn ComponentNode) {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	l.pendingMut.Lock()
+	if _, ok := l.pending[bn.NodeID()]; !ok {
+		// A later Apply evaluated the graph again.
+		l.pendingMut.Unlock()
+		return
+	}
+	delete(l.pending, bn.NodeID())
+
+	var ready []BlockNode
+	_ = dag.WalkTopological(l.graph, l.graph.Leaves(), func(n dag.Node) error {
+		if _, ok := l.pending[n.NodeID()]; !ok || dependsOnPending(l.graph, n, l.pending) {
+			return nil
+		}
+		delete(l.pending, n.NodeID())
+		ready = append(ready, n.(BlockNode))
+		return nil
+	})
+	l.pendingMut.Unlock()
+
+	for _, n := range ready {
+		level.Info(logger).Log("msg", "evaluating deferred node", "node_id", n.NodeID())
+		_ = l.evaluate(logger, n)
+	}
+}
+
 // evaluate constructs the final context for the BlockNode and
 // evaluates it. mut must be held when calling evaluate.
 func (l *Loader) evaluate(logger log.Logger, bn BlockNode) error {
@@ -842,7 +1191,7 @@ func (l *Loader) postEvaluate(logger log.Logger, bn BlockNode, err error) error
 		l.cache.CacheArguments(c.ID(), c.Arguments())
 		l.cache.CacheExports(c.ID(), c.Exports())
 	case *ArgumentConfigNode:
-		if _, found := l.cache.moduleArguments[c.Label()]; !found {
+		if !l.cache.HasModuleArgument(c.Label()) {
 			if c.Optional() {
 				l.cache.CacheModuleArgument(c.Label(), c.Default())
 			} else {
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/alloy/internal/runtime/internal/controller"
 	"github.com/grafana/alloy/internal/runtime/internal/dag"
 	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/diag"
@@ -150,6 +151,122 @@ func TestLoader(t *testing.T) {
 		require.Nil(t, newGraph.GetByID("testcomponents.tick.remove_me")) // The new graph shouldn't have the old node
 	})
 
+	t.Run("Remove secrets of removed components", func(t *testing.T) {
+		startFile := `
+			testcomponents.passthrough "with_secret" {
+				input  = "hello"
+				secret = "loader-test-secret"
+			}
+		`
+		l := controller.NewLoader(newLoaderOptions())
+		diags := applyFromContent(t, l, []byte(startFile), nil, nil)
+		require.NoError(t, diags.ErrorOrNil())
+		require.Equal(t, secrets.Redacted, secrets.Scrub("loader-test-secret"))
+
+		diags = applyFromContent(t, l, []byte(testFile), nil, nil)
+		require.NoError(t, diags.ErrorOrNil())
+		require.Equal(t, "loader-test-secret", secrets.Scrub("loader-test-secret"))
+	})
+
+	t.Run("Reload report", func(t *testing.T) {
+		startFile := `
+			testcomponents.passthrough "changed" {
+				input = "hello"
+			}
+
+			testcomponents.passthrough "dependant" {
+				input = testcomponents.passthrough.changed.output
+			}
+
+			testcomponents.passthrough "unchanged" {
+				input = "static"
+			}
+
+			testcomponents.passthrough "removed" {
+				input = "bye"
+			}
+		`
+		updatedFile := `
+			testcomponents.passthrough "added" {
+				input = "hi"
+			}
+
+			testcomponents.passthrough "changed" {
+				input = "hello, world"
+			}
+
+			testcomponents.passthrough "dependant" {
+				input = testcomponents.passthrough.changed.output
+			}
+
+			testcomponents.passthrough "unchanged" {
+				input = "static"
+			}
+		`
+		l := controller.NewLoader(newLoaderOptions())
+		require.Nil(t, l.ReloadReport())
+
+		diags := applyFromContent(t, l, []byte(startFile), nil, nil)
+		require.NoError(t, diags.ErrorOrNil())
+		for _, c := range l.ReloadReport().Components {
+			require.Equal(t, component.ChangeTypeAdded, c.Change)
+			require.Equal(t, component.ApplyOutcomeApplied, c.Outcome)
+		}
+
+		diags = applyFromContent(t, l, []byte(updatedFile), nil, nil)
+		require.NoError(t, diags.ErrorOrNil())
+
+		report := l.ReloadReport()
+		require.Empty(t, report.Error)
+		require.Len(t, report.Components, 5)
+
+		expect := []struct {
+			id      string
+			change  component.ChangeType
+			outcome component.ApplyOutcome
+			old     string
+			new     string
+		}{
+			{"testcomponents.passthrough.added", component.ChangeTypeAdded, component.ApplyOutcomeApplied, "", ""},
+			{"testcomponents.passthrough.changed", component.ChangeTypeUpdated, component.ApplyOutcomeApplied, "hello", "hello, world"},
+			{"testcomponents.passthrough.dependant", component.ChangeTypeUpdated, component.ApplyOutcomeApplied, "hello", "hello, world"},
+			{"testcomponents.passthrough.removed", component.ChangeTypeRemoved, component.ApplyOutcomeApplied, "", ""},
+			{"testcomponents.passthrough.unchanged", component.ChangeTypeUnchanged, component.ApplyOutcomeSkipped, "", ""},
+		}
+		for i, e := range expect {
+			c := report.Components[i]
+			require.Equal(t, e.id, c.ID)
+			require.Equal(t, e.change, c.Change, e.id)
+			require.Equal(t, e.outcome, c.Outcome, e.id)
+
+			if e.change != component.ChangeTypeUpdated {
+				require.Empty(t, c.Arguments, e.id)
+				continue
+			}
+			require.Len(t, c.Arguments, 1, e.id)
+			require.Equal(t, "input", c.Arguments[0].Name)
+			require.JSONEq(t, `{"type":"string","value":"`+e.old+`"}`, string(c.Arguments[0].Old))
+			require.JSONEq(t, `{"type":"string","value":"`+e.new+`"}`, string(c.Arguments[0].New))
+		}
+	})
+
+	t.Run("Reload report with invalid config", func(t *testing.T) {
+		l := controller.NewLoader(newLoaderOptions())
+		diags := applyFromContent(t, l, []byte(testFile), nil, nil)
+		require.NoError(t, diags.ErrorOrNil())
+
+		invalidFile := `
+			doesnotexist "bad_component" {
+			}
+		`
+		diags = applyFromContent(t, l, []byte(invalidFile), nil, nil)
+		require.Error(t, diags.ErrorOrNil())
+
+		report := l.ReloadReport()
+		require.Contains(t, report.Error, `cannot find the definition of component name "doesnotexist`)
+		require.Empty(t, report.Components)
+	})
+
 	t.Run("Load with invalid components", func(t *testing.T) {
 		invalidFile := `
 			doesnotexist "bad_component" {
@@ -119,7 +119,7 @@ func (cc *controllerCollector) Collect(ch chan<- prometheus.Metric) {
 		health := component.CurrentHealth().Health.String()
 		componentsByHealth[health]++
 		if builtinComponent, ok := component.(*BuiltinComponentNode); ok {
-			builtinComponent.registry.Collect(ch)
+			builtinComponent.registry.Load().Collect(ch)
 		}
 	}
 
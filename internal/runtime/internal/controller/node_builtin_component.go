@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -19,6 +20,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/grafana/alloy/internal/runtime/tracing"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/vm"
@@ -74,6 +76,7 @@ type ComponentGlobals struct {
 	NewModuleController  func(id string) ModuleController       // Func to generate a module controller.
 	GetServiceData       func(name string) (interface{}, error) // Get data for a service.
 	EnableCommunityComps bool                                   // Enables the use of community components.
+	DryRun               bool                                   // Evaluate arguments without building components.
 }
 
 // BuiltinComponentNode is a controller node which manages a builtin component.
@@ -89,16 +92,21 @@ type BuiltinComponentNode struct {
 	nodeID            string // Cached from id.String() to avoid allocating new strings every time NodeID is called.
 	reg               component.Registration
 	managedOpts       component.Options
-	registry          *prometheus.Registry
+	registry          atomic.Pointer[prometheus.Registry]
 	exportsType       reflect.Type
 	moduleController  ModuleController
 	OnBlockNodeUpdate func(cn BlockNode) // Informs controller that we need to reevaluate
+	dryRun            bool               // Evaluate arguments without building the managed component
 
 	mut     sync.RWMutex
 	block   *ast.BlockStmt // Current Alloy block to derive args from
 	eval    *vm.Evaluator
 	managed component.Component // Inner managed component
 	args    component.Arguments // Evaluated arguments for the managed component
+	// paused is whether the managed component was paused. It's only written
+	// with mut held, but can be read without it, so that a component which is
+	// slow to build doesn't block the scheduler.
+	paused atomic.Bool
 
 	// NOTE(rfratto): health and exports have their own mutex because they may be
 	// set asynchronously while mut is still being held (i.e., when calling Evaluate
@@ -148,6 +156,7 @@ func NewBuiltinComponentNode(globals ComponentGlobals, reg component.Registratio
 		exportsType:       getExportsType(reg),
 		moduleController:  globals.NewModuleController(globalID),
 		OnBlockNodeUpdate: globals.OnBlockNodeUpdate,
+		dryRun:            globals.DryRun,
 
 		block: b,
 		eval:  vm.New(b.Body),
@@ -165,16 +174,12 @@ func NewBuiltinComponentNode(globals ComponentGlobals, reg component.Registratio
 }
 
 func getManagedOptions(globals ComponentGlobals, cn *BuiltinComponentNode) component.Options {
-	cn.registry = prometheus.NewRegistry()
 	parent, id := splitPath(cn.globalID)
 	return component.Options{
-		ID:     cn.globalID,
-		Logger: log.With(globals.Logger, "component_path", parent, "component_id", id),
-		Registerer: prometheus.WrapRegistererWith(prometheus.Labels{
-			"component_path": parent,
-			"component_id":   id,
-		}, cn.registry),
-		Tracer: tracing.WrapTracer(globals.TraceProvider, cn.globalID),
+		ID:         cn.globalID,
+		Logger:     log.With(globals.Logger, "component_path", parent, "component_id", id),
+		Registerer: cn.newRegisterer(),
+		Tracer:     tracing.WrapTracer(globals.TraceProvider, cn.globalID),
 
 		DataPath: filepath.Join(globals.DataPath, cn.globalID),
 
@@ -189,6 +194,19 @@ func getManagedOptions(globals ComponentGlobals, cn *BuiltinComponentNode) compo
 	}
 }
 
+// newRegisterer replaces the registry of the node with an empty one, and
+// returns the registerer of the managed component which registers in it.
+func (cn *BuiltinComponentNode) newRegisterer() prometheus.Registerer {
+	registry := prometheus.NewRegistry()
+	cn.registry.Store(registry)
+
+	parent, id := splitPath(cn.globalID)
+	return prometheus.WrapRegistererWith(prometheus.Labels{
+		"component_path": parent,
+		"component_id":   id,
+	}, registry)
+}
+
 func getExportsType(reg component.Registration) reflect.Type {
 	if reg.Exports != nil {
 		return reflect.TypeOf(reg.Exports)
@@ -270,6 +288,25 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 	// components expect a non-pointer.
 	argsCopyValue := reflect.ValueOf(argsPointer).Elem().Interface()
 
+	if cn.dryRun {
+		// Only validate the arguments; the component is never built, so its
+		// secrets aren't registered either.
+		cn.args = argsCopyValue
+		return nil
+	}
+
+	// Register the secrets of the arguments before building or updating the
+	// component, so that they're redacted from the errors and logs it emits.
+	// They're removed by the Loader once the component is removed.
+	secrets.Default.Set(cn.globalID, secrets.Collect(argsCopyValue))
+
+	if cn.paused.Load() {
+		// The stopped instance isn't updated; the managed component is built
+		// again with the latest arguments when it's resumed.
+		cn.args = argsCopyValue
+		return nil
+	}
+
 	if cn.managed == nil {
 		// We haven't built the managed component successfully yet.
 		managed, err := cn.reg.Build(cn.managedOpts, argsCopyValue)
@@ -306,25 +343,78 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 // successfully. Otherwise, Run will return nil.
 func (cn *BuiltinComponentNode) Run(ctx context.Context) error {
 	cn.mut.RLock()
-	managed := cn.managed
+	managed, paused := cn.managed, cn.paused.Load()
 	cn.mut.RUnlock()
 
+	if paused {
+		cn.setRunHealth(component.HealthTypeExited, "component paused")
+		return nil
+	}
 	if managed == nil {
 		return ErrUnevaluated
 	}
 
 	cn.setRunHealth(component.HealthTypeHealthy, "started component")
-	err := cn.managed.Run(ctx)
+	err := managed.Run(ctx)
 
 	// Note: logging of this error is handled by the scheduler.
-	if err != nil {
+	switch {
+	case cn.Paused():
+		cn.setRunHealth(component.HealthTypeExited, "component paused")
+	case err != nil:
 		cn.setRunHealth(component.HealthTypeExited, fmt.Sprintf("component shut down with error: %s", err))
-	} else {
+	default:
 		cn.setRunHealth(component.HealthTypeExited, "component shut down cleanly")
 	}
 	return err
 }
 
+// Pause pauses the managed component. A paused component must not be run:
+// its arguments are only stored when it's evaluated, until Resume is called.
+func (cn *BuiltinComponentNode) Pause() {
+	cn.mut.Lock()
+	defer cn.mut.Unlock()
+	cn.paused.Store(true)
+}
+
+// Resume resumes the managed component after Pause was called and it stopped
+// running. The stopped instance is discarded, and a new one is built with the
+// latest arguments the next time Evaluate is called.
+func (cn *BuiltinComponentNode) Resume() {
+	cn.mut.Lock()
+	defer cn.mut.Unlock()
+
+	if !cn.paused.Load() {
+		return
+	}
+	cn.paused.Store(false)
+	cn.managed = nil
+	// The new instance registers its metrics again.
+	cn.managedOpts.Registerer = cn.newRegisterer()
+}
+
+// Paused returns whether the managed component is paused.
+func (cn *BuiltinComponentNode) Paused() bool {
+	return cn.paused.Load()
+}
+
+// Ready returns whether the managed component is running and ready to
+// receive data. Components which implement component.ReadyComponent are only
+// ready once they report it.
+func (cn *BuiltinComponentNode) Ready() bool {
+	cn.healthMut.RLock()
+	running := cn.runHealth.Health == component.HealthTypeHealthy
+	cn.healthMut.RUnlock()
+	if !running {
+		return false
+	}
+
+	if rc, ok := cn.Component().(component.ReadyComponent); ok {
+		return rc.Ready()
+	}
+	return true
+}
+
 // ErrUnevaluated is returned if BuiltinComponentNode.Run is called before a managed
 // component is built.
 var ErrUnevaluated = errors.New("managed component not built")
@@ -431,6 +521,13 @@ func (cn *BuiltinComponentNode) setEvalHealth(t component.HealthType, msg string
 	}
 }
 
+// evaluated returns whether the last call to Evaluate succeeded.
+func (cn *BuiltinComponentNode) evaluated() bool {
+	cn.healthMut.RLock()
+	defer cn.healthMut.RUnlock()
+	return cn.evalHealth.Health == component.HealthTypeHealthy
+}
+
 // setRunHealth sets the internal health from a call to Run. See Health for
 // information on how overall health is calculated.
 func (cn *BuiltinComponentNode) setRunHealth(t component.HealthType, msg string) {
@@ -14,9 +14,10 @@ import (
 
 // ServiceNode is a DAG node which represents a running service.
 type ServiceNode struct {
-	host service.Host
-	svc  service.Service
-	def  service.Definition
+	host   service.Host
+	svc    service.Service
+	def    service.Definition
+	dryRun bool // Evaluate arguments without updating the service.
 
 	mut   sync.RWMutex
 	block *ast.BlockStmt // Current Alloy block to derive args from
@@ -27,12 +28,14 @@ type ServiceNode struct {
 var _ RunnableNode = (*ServiceNode)(nil)
 
 // NewServiceNode creates a new instance of a ServiceNode from an instance of a
-// Service. The provided host is used when running the service.
-func NewServiceNode(host service.Host, svc service.Service) *ServiceNode {
+// Service. The provided host is used when running the service. If dryRun is
+// true, the service is never updated with the evaluated arguments.
+func NewServiceNode(host service.Host, svc service.Service, dryRun bool) *ServiceNode {
 	return &ServiceNode{
-		host: host,
-		svc:  svc,
-		def:  svc.Definition(),
+		host:   host,
+		svc:    svc,
+		def:    svc.Definition(),
+		dryRun: dryRun,
 	}
 }
 
@@ -111,6 +114,11 @@ func (sn *ServiceNode) Evaluate(scope *vm.Scope) error {
 		return nil
 	}
 
+	if sn.dryRun {
+		sn.args = argsCopyValue
+		return nil
+	}
+
 	// Update the service.
 	if err := sn.svc.Update(argsCopyValue); err != nil {
 		return fmt.Errorf("updating service: %w", err)
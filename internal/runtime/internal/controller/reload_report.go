@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/internal/dag"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/encoding/alloyjson"
+	"github.com/grafana/alloy/syntax/printer"
+)
+
+// ReloadReport returns the report of the most recent call to Apply, or nil if
+// Apply was never called.
+func (l *Loader) ReloadReport() *component.ReloadReport {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+	return l.report
+}
+
+// previousComponent holds the state of a component before a call to Apply.
+type previousComponent struct {
+	block *ast.BlockStmt
+	args  component.Arguments
+}
+
+// unchangedComponent returns whether n doesn't need to be evaluated by Apply:
+// n must be a builtin component whose last evaluation succeeded, its block
+// must be the same as prevBlock, and all its dependencies must have been
+// skipped too.
+func unchangedComponent(g *dag.Graph, n ComponentNode, prevBlock *ast.BlockStmt, skipped map[string]struct{}) bool {
+	bn, ok := n.(*BuiltinComponentNode)
+	if !ok || !bn.evaluated() || !blocksEqual(prevBlock, n.Block()) {
+		return false
+	}
+	for _, dep := range g.Dependencies(n) {
+		if _, ok := skipped[dep.NodeID()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluatedComponentChange returns the ComponentChange of a component which
+// was evaluated by Apply with the error err.
+func evaluatedComponentChange(n ComponentNode, existed bool, prevArgs component.Arguments, err error) component.ComponentChange {
+	change := component.ComponentChange{
+		ID:      n.NodeID(),
+		Outcome: component.ApplyOutcomeApplied,
+	}
+	change.Change, change.Arguments = argumentsChange(existed, prevArgs, n.Arguments())
+	if err != nil {
+		change.Outcome = component.ApplyOutcomeFailed
+		change.Error = err.Error()
+	}
+	return change
+}
+
+// unevaluatedComponentChange returns the change of a component whose
+// evaluation didn't finish during the load, either because it exceeded the
+// evaluation timeout or because it was deferred. Its new arguments aren't
+// known yet.
+func unevaluatedComponentChange(n ComponentNode, existed bool, outcome component.ApplyOutcome) component.ComponentChange {
+	change := component.ComponentChange{
+		ID:      n.NodeID(),
+		Change:  component.ChangeTypeAdded,
+		Outcome: outcome,
+	}
+	if existed {
+		change.Change = component.ChangeTypeUpdated
+	}
+	return change
+}
+
+// logReloadReport logs a summary of report, and the components which changed.
+func logReloadReport(logger log.Logger, report *component.ReloadReport) {
+	counts := make(map[component.ChangeType]int)
+	failed := 0
+	for _, c := range report.Components {
+		counts[c.Change]++
+		if c.Outcome == component.ApplyOutcomeFailed {
+			failed++
+		}
+
+		switch c.Change {
+		case component.ChangeTypeUnchanged:
+			continue
+		case component.ChangeTypeUpdated:
+			names := make([]string, 0, len(c.Arguments))
+			for _, arg := range c.Arguments {
+				names = append(names, arg.Name)
+			}
+			level.Info(logger).Log("msg", "component changed", "node_id", c.ID, "change", c.Change, "outcome", c.Outcome, "arguments", strings.Join(names, ","))
+		default:
+			level.Info(logger).Log("msg", "component changed", "node_id", c.ID, "change", c.Change, "outcome", c.Outcome)
+		}
+	}
+
+	level.Info(logger).Log(
+		"msg", "applied config changes",
+		"added", counts[component.ChangeTypeAdded],
+		"removed", counts[component.ChangeTypeRemoved],
+		"updated", counts[component.ChangeTypeUpdated],
+		"unchanged", counts[component.ChangeTypeUnchanged],
+		"failed", failed,
+	)
+}
+
+// blocksEqual returns whether two blocks have the same content, regardless of
+// their position in the config.
+func blocksEqual(a, b *ast.BlockStmt) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	var bufA, bufB bytes.Buffer
+	if err := printer.Fprint(&bufA, a); err != nil {
+		return false
+	}
+	if err := printer.Fprint(&bufB, b); err != nil {
+		return false
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
+
+// argumentsChange returns the ChangeType and the changed arguments of a
+// component which was evaluated, given its arguments before and after the
+// evaluation.
+func argumentsChange(existed bool, oldArgs, newArgs component.Arguments) (component.ChangeType, []component.ArgumentChange) {
+	switch {
+	case !existed:
+		return component.ChangeTypeAdded, nil
+	case reflect.DeepEqual(oldArgs, newArgs):
+		return component.ChangeTypeUnchanged, nil
+	default:
+		return component.ChangeTypeUpdated, diffArguments(oldArgs, newArgs)
+	}
+}
+
+// diffArguments returns the top-level arguments and blocks which differ
+// between oldArgs and newArgs. Arguments which only differ by the value of a
+// secret aren't returned, since secrets are masked once encoded.
+func diffArguments(oldArgs, newArgs component.Arguments) []component.ArgumentChange {
+	oldStmts, err := encodeArguments(oldArgs)
+	if err != nil {
+		return nil
+	}
+	newStmts, err := encodeArguments(newArgs)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		changes []component.ArgumentChange
+		seen    = make(map[string]struct{}, len(newStmts.values))
+	)
+	for _, name := range statementNames(oldStmts, newStmts) {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		oldValue, newValue := oldStmts.values[name], newStmts.values[name]
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, component.ArgumentChange{
+			Name: name,
+			Old:  oldValue,
+			New:  newValue,
+		})
+	}
+	return changes
+}
+
+// encodedArguments holds the JSON values of the top-level statements of
+// encoded arguments by name, in the order they're encoded.
+type encodedArguments struct {
+	names  []string
+	values map[string]json.RawMessage
+}
+
+// statementNames returns the names of the statements of a and b, in the order
+// they're encoded. Names can be repeated.
+func statementNames(a, b encodedArguments) []string {
+	return append(append([]string{}, a.names...), b.names...)
+}
+
+// encodeArguments encodes args with the alloyjson package. The values of
+// blocks which are repeated are grouped in a JSON array.
+func encodeArguments(args component.Arguments) (res encodedArguments, err error) {
+	res.values = make(map[string]json.RawMessage)
+	if args == nil {
+		return res, nil
+	}
+
+	defer func() {
+		// MarshalBody panics when args isn't a struct or a map.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("encoding arguments: %v", r)
+		}
+	}()
+
+	bb, err := alloyjson.MarshalBody(args)
+	if err != nil {
+		return res, err
+	}
+
+	var stmts []struct {
+		Name  string          `json:"name"`
+		Type  string          `json:"type"`
+		Label string          `json:"label"`
+		Value json.RawMessage `json:"value"`
+		Body  json.RawMessage `json:"body"`
+	}
+	if err := json.Unmarshal(bb, &stmts); err != nil {
+		return res, err
+	}
+
+	grouped := make(map[string][]json.RawMessage)
+	for _, stmt := range stmts {
+		name, value := stmt.Name, stmt.Value
+		if stmt.Type == "block" {
+			value = stmt.Body
+			if stmt.Label != "" {
+				name = fmt.Sprintf("%s %q", stmt.Name, stmt.Label)
+			}
+		}
+		if _, ok := grouped[name]; !ok {
+			res.names = append(res.names, name)
+		}
+		grouped[name] = append(grouped[name], value)
+	}
+
+	for name, values := range grouped {
+		if len(values) == 1 {
+			res.values[name] = values[0]
+			continue
+		}
+		bb, err := json.Marshal(values)
+		if err != nil {
+			return res, err
+		}
+		res.values[name] = bb
+	}
+	return res, nil
+}
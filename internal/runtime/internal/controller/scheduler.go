@@ -3,10 +3,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 
+	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 )
 
@@ -158,3 +161,72 @@ func (t *task) Stop() {
 	t.cancel()
 	<-t.exited
 }
+
+// ReadyNode is any BlockNode which reports whether it's ready to receive
+// data.
+type ReadyNode interface {
+	BlockNode
+	Ready() bool
+}
+
+// startupGatePollInterval is how often a gated runnable checks whether its
+// dependencies are ready.
+const startupGatePollInterval = 100 * time.Millisecond
+
+// GateRunnable returns a RunnableNode which waits for deps to be ready before
+// running r, so that r doesn't send data to components which can't receive it
+// yet. r is run anyway once timeout elapses.
+func GateRunnable(logger log.Logger, r RunnableNode, deps []ReadyNode, timeout time.Duration) RunnableNode {
+	return &gatedRunnable{RunnableNode: r, logger: logger, deps: deps, timeout: timeout}
+}
+
+type gatedRunnable struct {
+	RunnableNode
+	logger  log.Logger
+	deps    []ReadyNode
+	timeout time.Duration
+}
+
+// runHealthSetter is implemented by the nodes which report the health of
+// their run.
+type runHealthSetter interface {
+	setRunHealth(t component.HealthType, msg string)
+}
+
+func (g *gatedRunnable) Run(ctx context.Context) error {
+	timeout := time.NewTimer(g.timeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(startupGatePollInterval)
+	defer ticker.Stop()
+
+	for {
+		waiting := g.notReady()
+		if len(waiting) == 0 {
+			break
+		}
+		if n, ok := g.RunnableNode.(runHealthSetter); ok {
+			n.setRunHealth(component.HealthTypeUnknown, fmt.Sprintf("waiting for dependencies to be ready: %s", strings.Join(waiting, ", ")))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timeout.C:
+			level.Warn(g.logger).Log("msg", "dependencies aren't ready after the startup gate timeout, starting node anyway", "node", g.NodeID(), "waiting_for", strings.Join(waiting, ","), "timeout", g.timeout)
+			return g.RunnableNode.Run(ctx)
+		case <-ticker.C:
+		}
+	}
+	return g.RunnableNode.Run(ctx)
+}
+
+// notReady returns the IDs of the dependencies which aren't ready.
+func (g *gatedRunnable) notReady() []string {
+	var ids []string
+	for _, dep := range g.deps {
+		if !dep.Ready() {
+			ids = append(ids, dep.NodeID())
+		}
+	}
+	return ids
+}
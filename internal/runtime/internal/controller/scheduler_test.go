@@ -5,9 +5,11 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/runtime/internal/controller"
@@ -92,6 +94,48 @@ func TestScheduler_Synchronize(t *testing.T) {
 	})
 }
 
+func TestGateRunnable(t *testing.T) {
+	logger := log.NewLogfmtLogger(os.Stdout)
+
+	t.Run("Waits for dependencies to be ready", func(t *testing.T) {
+		var started atomic.Bool
+		runFunc := func(ctx context.Context) error {
+			started.Store(true)
+			<-ctx.Done()
+			return nil
+		}
+
+		dep := &fakeReadyNode{fakeRunnable: fakeRunnable{ID: "sink"}}
+		sched := controller.NewScheduler(logger)
+		sched.Synchronize([]controller.RunnableNode{
+			controller.GateRunnable(logger, fakeRunnable{ID: "source", Component: mockComponent{RunFunc: runFunc}}, []controller.ReadyNode{dep}, time.Minute),
+		})
+
+		require.Never(t, started.Load, 300*time.Millisecond, 10*time.Millisecond)
+		dep.ready.Store(true)
+		require.Eventually(t, started.Load, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, sched.Close())
+	})
+
+	t.Run("Runs after the timeout", func(t *testing.T) {
+		var started atomic.Bool
+		runFunc := func(ctx context.Context) error {
+			started.Store(true)
+			<-ctx.Done()
+			return nil
+		}
+
+		dep := &fakeReadyNode{fakeRunnable: fakeRunnable{ID: "sink"}}
+		sched := controller.NewScheduler(logger)
+		sched.Synchronize([]controller.RunnableNode{
+			controller.GateRunnable(logger, fakeRunnable{ID: "source", Component: mockComponent{RunFunc: runFunc}}, []controller.ReadyNode{dep}, 200*time.Millisecond),
+		})
+
+		require.Eventually(t, started.Load, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, sched.Close())
+	})
+}
+
 type fakeRunnable struct {
 	ID        string
 	Component component.Component
@@ -114,3 +158,12 @@ var _ component.Component = (*mockComponent)(nil)
 
 func (mc mockComponent) Run(ctx context.Context) error              { return mc.RunFunc(ctx) }
 func (mc mockComponent) Update(newConfig component.Arguments) error { return mc.UpdateFunc(newConfig) }
+
+type fakeReadyNode struct {
+	fakeRunnable
+	ready atomic.Bool
+}
+
+var _ controller.ReadyNode = (*fakeReadyNode)(nil)
+
+func (fr *fakeReadyNode) Ready() bool { return fr.ready.Load() }
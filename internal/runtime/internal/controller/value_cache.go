@@ -78,6 +78,16 @@ func (vc *valueCache) CacheModuleArgument(key string, value any) {
 	}
 }
 
+// HasModuleArgument returns whether a value for the module argument key is
+// cached.
+func (vc *valueCache) HasModuleArgument(key string) bool {
+	vc.mut.RLock()
+	defer vc.mut.RUnlock()
+
+	_, found := vc.moduleArguments[key]
+	return found
+}
+
 // CacheModuleExportValue saves the value to the map
 func (vc *valueCache) CacheModuleExportValue(name string, value any) {
 	vc.mut.Lock()
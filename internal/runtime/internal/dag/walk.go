@@ -1,5 +1,7 @@
 package dag
 
+import "sort"
+
 // WalkFunc is a function that gets invoked when walking a Graph. Walking will
 // stop if WalkFunc returns a non-nil error.
 type WalkFunc func(n Node) error
@@ -105,3 +107,112 @@ func WalkTopological(g *Graph, start []Node, fn WalkFunc) error {
 
 	return nil
 }
+
+// WalkTopologicalConcurrent performs a topological walk of all nodes in start
+// like WalkTopological, but invokes fn for up to concurrency nodes at the same
+// time. A node is passed to fn once fn returned for all of its outgoing
+// edges, so independent parts of the graph are walked concurrently.
+//
+// Among the nodes which are ready to be walked, the ones with the lowest
+// priority are passed to fn first. Nodes with the same priority are passed to
+// fn in the order they became ready.
+//
+// Once fn returns an error, no other node is passed to fn, and the error is
+// returned after the running calls to fn returned.
+func WalkTopologicalConcurrent(g *Graph, start []Node, concurrency int, priority func(Node) int, fn WalkFunc) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type result struct {
+		node Node
+		err  error
+	}
+
+	var (
+		visited = make(nodeSet)
+		ready   = readyQueue{priority: priority}
+		results = make(chan result)
+		running int
+		walkErr error
+
+		remainingDeps = make(map[Node]int)
+	)
+
+	for _, n := range start {
+		if !visited.Has(n) {
+			visited.Add(n)
+			ready.Push(n)
+		}
+	}
+
+	for {
+		for walkErr == nil && running < concurrency && ready.Len() > 0 {
+			n := ready.Pop()
+			running++
+			go func() {
+				results <- result{node: n, err: fn(n)}
+			}()
+		}
+		if running == 0 {
+			return walkErr
+		}
+
+		res := <-results
+		running--
+		if res.err != nil {
+			if walkErr == nil {
+				walkErr = res.err
+			}
+			continue
+		}
+
+		// Queue the nodes with an incoming edge once all of their outgoing
+		// edges were walked.
+		for n := range g.inEdges[res.node] {
+			if _, ok := remainingDeps[n]; !ok {
+				remainingDeps[n] = len(g.outEdges[n])
+			}
+			remainingDeps[n]--
+
+			if remainingDeps[n] == 0 && !visited.Has(n) {
+				visited.Add(n)
+				ready.Push(n)
+			}
+		}
+	}
+}
+
+// readyQueue is a queue of nodes ordered by priority, and then by insertion
+// order.
+type readyQueue struct {
+	priority func(Node) int
+	nodes    []Node
+}
+
+func (q *readyQueue) Len() int { return len(q.nodes) }
+
+// Push adds n after the nodes with the same or a lower priority.
+func (q *readyQueue) Push(n Node) {
+	p := q.priorityOf(n)
+	i := sort.Search(len(q.nodes), func(i int) bool {
+		return q.priorityOf(q.nodes[i]) > p
+	})
+	q.nodes = append(q.nodes, nil)
+	copy(q.nodes[i+1:], q.nodes[i:])
+	q.nodes[i] = n
+}
+
+// Pop removes and returns the first node of the queue.
+func (q *readyQueue) Pop() Node {
+	n := q.nodes[0]
+	q.nodes = q.nodes[1:]
+	return n
+}
+
+func (q *readyQueue) priorityOf(n Node) int {
+	if q.priority == nil {
+		return 0
+	}
+	return q.priority(n)
+}
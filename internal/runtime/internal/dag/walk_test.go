@@ -0,0 +1,116 @@
+package dag
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWalkTopologicalConcurrent(t *testing.T) {
+	var g Graph
+	var (
+		nodeA = stringNode("a")
+		nodeB = stringNode("b")
+		nodeC = stringNode("c")
+		nodeD = stringNode("d")
+	)
+	g.Add(nodeA)
+	g.Add(nodeB)
+	g.Add(nodeC)
+	g.Add(nodeD)
+	// b and c depend on a, and d depends on b and c.
+	g.AddEdge(Edge{nodeB, nodeA})
+	g.AddEdge(Edge{nodeC, nodeA})
+	g.AddEdge(Edge{nodeD, nodeB})
+	g.AddEdge(Edge{nodeD, nodeC})
+
+	var (
+		mut     sync.Mutex
+		walked  = map[Node]bool{}
+		started = make(chan struct{}, 2)
+	)
+	err := WalkTopologicalConcurrent(&g, g.Leaves(), 2, nil, func(n Node) error {
+		mut.Lock()
+		for _, dep := range g.Dependencies(n) {
+			if !walked[dep] {
+				t.Errorf("%s walked before its dependency %s", n, dep)
+			}
+		}
+		mut.Unlock()
+
+		// b and c must be walked at the same time: each waits for the other one
+		// to start.
+		if n == nodeB || n == nodeC {
+			started <- struct{}{}
+			deadline := time.After(5 * time.Second)
+			for len(started) < 2 {
+				select {
+				case <-deadline:
+					t.Errorf("%s wasn't walked concurrently", n)
+					return nil
+				case <-time.After(time.Millisecond):
+				}
+			}
+		}
+
+		mut.Lock()
+		walked[n] = true
+		mut.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(walked) != 4 {
+		t.Fatalf("expected 4 nodes to be walked, got %d", len(walked))
+	}
+}
+
+func TestWalkTopologicalConcurrentPriority(t *testing.T) {
+	var g Graph
+	var (
+		nodeA = stringNode("a")
+		nodeB = stringNode("b")
+		nodeC = stringNode("c")
+	)
+	g.Add(nodeA)
+	g.Add(nodeB)
+	g.Add(nodeC)
+
+	priority := map[Node]int{nodeA: 2, nodeB: 0, nodeC: 1}
+
+	var order []Node
+	err := WalkTopologicalConcurrent(&g, []Node{nodeA, nodeB, nodeC}, 1, func(n Node) int { return priority[n] }, func(n Node) error {
+		order = append(order, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(order) != 3 || order[0] != nodeB || order[1] != nodeC || order[2] != nodeA {
+		t.Fatalf("unexpected walk order %v", order)
+	}
+}
+
+func TestWalkTopologicalConcurrentError(t *testing.T) {
+	var g Graph
+	var (
+		nodeA = stringNode("a")
+		nodeB = stringNode("b")
+	)
+	g.Add(nodeA)
+	g.Add(nodeB)
+	g.AddEdge(Edge{nodeB, nodeA})
+
+	errFailed := errors.New("failed")
+	err := WalkTopologicalConcurrent(&g, g.Leaves(), 2, nil, func(n Node) error {
+		if n == nodeB {
+			t.Errorf("%s walked after its dependency failed", n)
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected error %q, got %v", errFailed, err)
+	}
+}
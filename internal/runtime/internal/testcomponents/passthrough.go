@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/alloytypes"
 )
 
 func init() {
@@ -27,6 +28,9 @@ func init() {
 type PassthroughConfig struct {
 	Input string        `alloy:"input,attr"`
 	Lag   time.Duration `alloy:"lag,attr,optional"`
+
+	// Secret is unused; it lets tests give the component a secret.
+	Secret alloytypes.Secret `alloy:"secret,attr,optional"`
 }
 
 // PassthroughExports describes exported fields for the
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/grafana/alloy/internal/runtime/secrets"
 )
 
 // We need an implementation of slog.Handler that always matches the current
@@ -140,6 +142,7 @@ func (h *handler) WithGroup(name string) slog.Handler {
 }
 
 func replace(groups []string, a slog.Attr) slog.Attr {
+	a = scrub(a)
 	if len(groups) > 0 {
 		return a
 	}
@@ -194,3 +197,29 @@ func replace(groups []string, a slog.Attr) slog.Attr {
 
 	return a
 }
+
+// scrub redacts the secrets used by components from the value of a, such as
+// an error message which includes the headers of a request.
+func scrub(a slog.Attr) slog.Attr {
+	var s string
+	switch a.Value.Kind() {
+	case slog.KindString:
+		s = a.Value.String()
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			s = v.Error()
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			return a
+		}
+	default:
+		return a
+	}
+
+	if scrubbed := secrets.Scrub(s); scrubbed != s {
+		return slog.String(a.Key, scrubbed)
+	}
+	return a
+}
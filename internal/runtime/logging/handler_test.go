@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
 	"testing/slogtest"
 	"time"
 
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,6 +47,24 @@ func TestGroups(t *testing.T) {
 	require.Equal(t, expect, buf.String())
 }
 
+func TestSecretsRedacted(t *testing.T) {
+	secrets.Default.Set("test", []string{"hunter2"})
+	defer secrets.Default.Remove("test")
+
+	var buf bytes.Buffer
+	handler := getTestHandler(t, &buf)
+	handler = handler.WithGroup("test")
+	handler.Handle(context.Background(), newTestRecord("sending with password hunter2"))
+
+	record := newTestRecord("request failed")
+	record.AddAttrs(slog.Any("err", errors.New("Authorization: Bearer hunter2")))
+	handler.Handle(context.Background(), record)
+
+	expect := `level=info msg="sending with password (secret)"` + "\n" +
+		`level=info msg="request failed" test.err="Authorization: Bearer (secret)"` + "\n"
+	require.Equal(t, expect, buf.String())
+}
+
 func TestSlogTester(t *testing.T) {
 	var buf bytes.Buffer
 	l, err := New(&buf, Options{
@@ -61,7 +61,7 @@ func NewDeferred(w io.Writer) (*Logger, error) {
 	var (
 		leveler slog.LevelVar
 		format  formatVar
-		writer  writerVar
+		writer  = writerVar{recent: newRecentLogs(recentLogsSize)}
 	)
 
 	l := &Logger{
@@ -89,6 +89,10 @@ func NewDeferred(w io.Writer) (*Logger, error) {
 // updated.
 func (l *Logger) Handler() slog.Handler { return l.deferredSlog }
 
+// RecentLogs returns the last log lines written by l, oldest first. Secrets
+// are already redacted from them.
+func (l *Logger) RecentLogs() []byte { return l.writer.recent.Bytes() }
+
 // Update re-configures the options used for the logger.
 func (l *Logger) Update(o Options) error {
 	l.bufferMut.Lock()
@@ -216,6 +220,8 @@ func (f *formatVar) Set(format Format) {
 type writerVar struct {
 	mut sync.RWMutex
 	w   io.Writer
+
+	recent *recentLogs // Copy of the last lines written, for support bundles.
 }
 
 func (w *writerVar) Set(inner io.Writer) {
@@ -232,6 +238,9 @@ func (w *writerVar) Write(p []byte) (n int, err error) {
 		return 0, fmt.Errorf("no writer available")
 	}
 
+	if w.recent != nil {
+		_, _ = w.recent.Write(p)
+	}
 	return w.w.Write(p)
 }
 
@@ -185,6 +185,20 @@ func Test_lokiWriter_nil(t *testing.T) {
 	})
 }
 
+func TestRecentLogs(t *testing.T) {
+	logger, err := logging.New(io.Discard, debugLevel())
+	require.NoError(t, err)
+
+	for i := 0; i < 1005; i++ {
+		require.NoError(t, logger.Log("msg", "test message", "i", i))
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(logger.RecentLogs()), "\n"), "\n")
+	require.Len(t, lines, 1000)
+	require.Equal(t, "level=info msg=\"test message\" i=5", lines[0])
+	require.Equal(t, "level=info msg=\"test message\" i=1004", lines[999])
+}
+
 func BenchmarkLogging_NoLevel_Prints(b *testing.B) {
 	logger, err := logging.New(io.Discard, infoLevel())
 	require.NoError(b, err)
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+)
+
+// recentLogsSize is the number of log lines kept in memory for support
+// bundles.
+const recentLogsSize = 1000
+
+// recentLogs is a ring buffer of the last log lines written by a Logger.
+type recentLogs struct {
+	mut   sync.Mutex
+	lines [][]byte
+	next  int // Index of the next line to overwrite once lines is full.
+}
+
+func newRecentLogs(size int) *recentLogs {
+	return &recentLogs{lines: make([][]byte, 0, size)}
+}
+
+// Write stores a copy of p, which holds a single log line.
+func (r *recentLogs) Write(p []byte) (int, error) {
+	line := bytes.Clone(p)
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, line)
+		return len(p), nil
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	return len(p), nil
+}
+
+// Bytes returns the stored log lines, oldest first.
+func (r *recentLogs) Bytes() []byte {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	var buf bytes.Buffer
+	for i := range r.lines {
+		buf.Write(r.lines[(r.next+i)%len(r.lines)])
+	}
+	return buf.Bytes()
+}
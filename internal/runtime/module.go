@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -136,13 +137,17 @@ func newModule(o *moduleOptions) *module {
 			ComponentRegistry: o.ComponentRegistry,
 			WorkerPool:        o.WorkerPool,
 			Options: Options{
-				ControllerID:         o.ID,
-				Tracer:               o.Tracer,
-				Reg:                  o.Reg,
-				Logger:               o.Logger,
-				DataPath:             o.DataPath,
-				MinStability:         o.MinStability,
-				EnableCommunityComps: o.EnableCommunityComps,
+				ControllerID:          o.ID,
+				Tracer:                o.Tracer,
+				Reg:                   o.Reg,
+				Logger:                o.Logger,
+				DataPath:              o.DataPath,
+				MinStability:          o.MinStability,
+				EnableCommunityComps:  o.EnableCommunityComps,
+				DryRun:                o.DryRun,
+				EvaluationConcurrency: o.EvaluationConcurrency,
+				EvaluationTimeout:     o.EvaluationTimeout,
+				StartupGateTimeout:    o.StartupGateTimeout,
 				OnExportsChange: func(exports map[string]any) {
 					if o.export != nil {
 						o.export(exports)
@@ -230,4 +235,15 @@ type moduleControllerOptions struct {
 
 	// EnableCommunityComps enables the use of community components.
 	EnableCommunityComps bool
+
+	// DryRun evaluates the module without building its components.
+	DryRun bool
+
+	// EvaluationConcurrency and EvaluationTimeout configure how the module
+	// evaluates its components.
+	EvaluationConcurrency int
+	EvaluationTimeout     time.Duration
+
+	// StartupGateTimeout configures how the module starts its components.
+	StartupGateTimeout time.Duration
 }
@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"reflect"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+var (
+	secretType         = reflect.TypeOf(alloytypes.Secret(""))
+	optionalSecretType = reflect.TypeOf(alloytypes.OptionalSecret{})
+)
+
+// Collect returns the values of all the alloytypes.Secret, and of the
+// alloytypes.OptionalSecret marked as secret, found in v, such as the
+// arguments of a component.
+//
+// Values held in interfaces aren't walked, as they're capsules such as the
+// receivers of other components, which may be concurrently modified.
+func Collect(v any) []string {
+	c := collector{visited: make(map[uintptr]struct{})}
+	c.collect(reflect.ValueOf(v))
+	return c.values
+}
+
+type collector struct {
+	values  []string
+	visited map[uintptr]struct{} // Pointers already walked, to stop on cycles.
+}
+
+func (c *collector) collect(v reflect.Value) {
+	if !v.IsValid() {
+		return
+	}
+
+	switch v.Type() {
+	case secretType:
+		c.values = append(c.values, v.String())
+		return
+	case optionalSecretType:
+		if v.FieldByName("IsSecret").Bool() {
+			c.values = append(c.values, v.FieldByName("Value").String())
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if _, ok := c.visited[v.Pointer()]; ok {
+			return
+		}
+		c.visited[v.Pointer()] = struct{}{}
+		c.collect(v.Elem())
+	case reflect.Struct:
+		// Unexported fields are the internal state of a type, not
+		// configuration.
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				c.collect(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		// Skip the slices of types which can't hold secrets, such as large
+		// byte slices.
+		if !mayHoldSecrets(v.Type().Elem()) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.collect(v.Index(i))
+		}
+	case reflect.Map:
+		if !mayHoldSecrets(v.Type().Elem()) {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			c.collect(iter.Value())
+		}
+	}
+}
+
+// mayHoldSecrets reports whether values of type t may hold secrets.
+func mayHoldSecrets(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return false
+	case reflect.String:
+		return t == secretType
+	default:
+		return true
+	}
+}
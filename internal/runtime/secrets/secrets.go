@@ -0,0 +1,154 @@
+// Package secrets keeps track of the secret values used by the components of
+// the process, so that they can be redacted from logs, debug information and
+// the other outputs meant for humans.
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Redacted replaces secret values in scrubbed text. It matches how secrets
+// are rendered in Alloy syntax.
+const Redacted = "(secret)"
+
+// minLength is the length under which secret values aren't redacted, as
+// redacting them would mangle unrelated text.
+const minLength = 4
+
+// Registry is a set of live secret values, grouped by the owner which uses
+// them.
+type Registry struct {
+	mut    sync.Mutex
+	owners map[string][]string
+
+	// replacer redacts all the secret values of all the owners. It's rebuilt
+	// on every change so that Scrub doesn't need to lock.
+	replacer atomic.Pointer[strings.Replacer]
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{owners: make(map[string][]string)}
+}
+
+// Default is the process-wide Registry.
+var Default = NewRegistry()
+
+// Set replaces the secret values used by owner.
+func (r *Registry) Set(owner string, values []string) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	values = filter(values)
+	if len(values) == 0 && len(r.owners[owner]) == 0 {
+		return
+	}
+	if len(values) == 0 {
+		delete(r.owners, owner)
+	} else {
+		r.owners[owner] = values
+	}
+	r.rebuild()
+}
+
+// Remove forgets the secret values used by owner.
+func (r *Registry) Remove(owner string) {
+	r.Set(owner, nil)
+}
+
+// rebuild rebuilds the replacer from the values of all the owners. r.mut
+// must be held.
+func (r *Registry) rebuild() {
+	unique := make(map[string]struct{})
+	for _, values := range r.owners {
+		for _, v := range values {
+			unique[v] = struct{}{}
+			// Secrets embedded in JSON documents are escaped, with or without
+			// HTML escaping depending on the encoder.
+			for _, escapeHTML := range []bool{true, false} {
+				unique[jsonEscape(v, escapeHTML)] = struct{}{}
+			}
+		}
+	}
+	if len(unique) == 0 {
+		r.replacer.Store(nil)
+		return
+	}
+
+	// strings.Replacer tries the replacements in order, so longer values go
+	// first for a secret containing another one to be redacted as a whole.
+	all := make([]string, 0, len(unique))
+	for v := range unique {
+		all = append(all, v)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if len(all[i]) != len(all[j]) {
+			return len(all[i]) > len(all[j])
+		}
+		return all[i] < all[j]
+	})
+
+	oldnew := make([]string, 0, 2*len(all))
+	for _, v := range all {
+		oldnew = append(oldnew, v, Redacted)
+	}
+	r.replacer.Store(strings.NewReplacer(oldnew...))
+}
+
+// Scrub returns s with all the secret values of r redacted.
+func (r *Registry) Scrub(s string) string {
+	replacer := r.replacer.Load()
+	if replacer == nil {
+		return s
+	}
+	return replacer.Replace(s)
+}
+
+// ScrubBytes returns b with all the secret values of r redacted. b is
+// returned as is if it doesn't hold any secret.
+func (r *Registry) ScrubBytes(b []byte) []byte {
+	replacer := r.replacer.Load()
+	if replacer == nil {
+		return b
+	}
+	s := string(b)
+	if scrubbed := replacer.Replace(s); scrubbed != s {
+		return []byte(scrubbed)
+	}
+	return b
+}
+
+// Scrub returns s with all the secret values of the Default registry
+// redacted.
+func Scrub(s string) string { return Default.Scrub(s) }
+
+// ScrubBytes returns b with all the secret values of the Default registry
+// redacted.
+func ScrubBytes(b []byte) []byte { return Default.ScrubBytes(b) }
+
+func filter(values []string) []string {
+	var res []string
+	for _, v := range values {
+		if len(v) >= minLength {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func jsonEscape(s string, escapeHTML bool) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(escapeHTML)
+	if err := enc.Encode(s); err != nil {
+		return s
+	}
+	// Trim the trailing newline and the quotes.
+	b := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return string(b[1 : len(b)-1])
+}
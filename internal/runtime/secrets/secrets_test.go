@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	require.Equal(t, "Authorization: Bearer hunter2", r.Scrub("Authorization: Bearer hunter2"))
+
+	r.Set("prometheus.remote_write.default", []string{"hunter2", "abc"})
+	r.Set("loki.write.default", []string{"hunter2-extended", `pa"ss<word>`})
+
+	// Values too short to be redacted safely are ignored.
+	require.Equal(t, "Authorization: Bearer (secret), abc", r.Scrub("Authorization: Bearer hunter2, abc"))
+	// Secrets containing another one are redacted as a whole.
+	require.Equal(t, "token=(secret)", r.Scrub("token=hunter2-extended"))
+
+	// Secrets embedded in JSON documents are redacted.
+	doc, err := json.Marshal(map[string]string{"password": `pa"ss<word>`})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"password":"(secret)"}`, string(r.ScrubBytes(doc)))
+
+	r.Remove("prometheus.remote_write.default")
+	require.Equal(t, "Authorization: Bearer hunter2", r.Scrub("Authorization: Bearer hunter2"))
+	require.Equal(t, "token=(secret)", r.Scrub("token=hunter2-extended"))
+
+	// Setting the values of an owner replaces its previous ones.
+	r.Set("loki.write.default", []string{"swordfish"})
+	require.Equal(t, "hunter2-extended (secret)", r.Scrub("hunter2-extended swordfish"))
+}
+
+func TestCollect(t *testing.T) {
+	type basicAuth struct {
+		Username string
+		Password alloytypes.Secret
+	}
+	type arguments struct {
+		URL        string
+		BasicAuth  *basicAuth
+		Headers    map[string]alloytypes.Secret
+		ClientID   alloytypes.OptionalSecret
+		ClientKey  alloytypes.OptionalSecret
+		Endpoints  []basicAuth
+		Receivers  []any
+		unexported alloytypes.Secret
+	}
+
+	args := arguments{
+		URL:       "http://localhost",
+		BasicAuth: &basicAuth{Username: "user", Password: "password"},
+		Headers:   map[string]alloytypes.Secret{"X-Api-Key": "api-key"},
+		ClientID:  alloytypes.OptionalSecret{Value: "client-id"},
+		ClientKey: alloytypes.OptionalSecret{IsSecret: true, Value: "client-key"},
+		Endpoints: []basicAuth{{Username: "user", Password: "endpoint-password"}},
+		Receivers: []any{alloytypes.Secret("in-interface")},
+
+		unexported: "unexported",
+	}
+
+	require.ElementsMatch(t, []string{"password", "api-key", "client-key", "endpoint-password"}, Collect(args))
+	require.ElementsMatch(t, []string{"password", "api-key", "client-key", "endpoint-password"}, Collect(&args))
+	require.Empty(t, Collect(nil))
+}
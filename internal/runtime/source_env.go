@@ -0,0 +1,119 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/diag"
+	"github.com/grafana/alloy/syntax/token"
+)
+
+// CheckEnv checks the calls to the env function in s against allowlist, which
+// holds the names of the environment variables the source can read. Names may
+// be glob patterns, such as ALLOY_*.
+//
+// When allowlist is empty, CheckEnv doesn't check anything. Otherwise, every
+// call to env must use a string literal naming an allowed environment
+// variable, which must be set unless the call is given a default value through
+// coalesce, as in coalesce(env("X"), "default"). All the calls which don't
+// satisfy these rules are reported at once as diag.Diagnostics.
+func (s *Source) CheckEnv(allowlist []string) error {
+	if s == nil || len(allowlist) == 0 {
+		return nil
+	}
+
+	c := &envChecker{allowlist: allowlist, defaulted: map[*ast.CallExpr]struct{}{}}
+	for _, blocks := range [][]*ast.BlockStmt{s.configBlocks, s.components, s.declareBlocks} {
+		for _, block := range blocks {
+			ast.Walk(c, block)
+		}
+	}
+	return c.diags.ErrorOrNil()
+}
+
+type envChecker struct {
+	allowlist []string
+
+	// defaulted holds the calls to env which are given a default value by an
+	// enclosing call to coalesce.
+	defaulted map[*ast.CallExpr]struct{}
+	diags     diag.Diagnostics
+}
+
+var _ ast.Visitor = (*envChecker)(nil)
+
+// Visit implements ast.Visitor.
+func (c *envChecker) Visit(node ast.Node) ast.Visitor {
+	call, ok := node.(*ast.CallExpr)
+	if !ok {
+		return c
+	}
+
+	switch calledFunction(call) {
+	case "coalesce":
+		// Every argument but the last one falls back to the ones after it.
+		for i := 0; i < len(call.Args)-1; i++ {
+			if arg, ok := call.Args[i].(*ast.CallExpr); ok && calledFunction(arg) == "env" {
+				c.defaulted[arg] = struct{}{}
+			}
+		}
+	case "env":
+		c.checkEnvCall(call)
+	}
+	return c
+}
+
+func (c *envChecker) checkEnvCall(call *ast.CallExpr) {
+	var name string
+	if len(call.Args) == 1 {
+		if lit, ok := call.Args[0].(*ast.LiteralExpr); ok && lit.Kind == token.STRING {
+			name, _ = strconv.Unquote(lit.Value)
+		}
+	}
+	if name == "" {
+		c.addError(call, "env must be called with a string literal when --config.env-allowlist is set")
+		return
+	}
+
+	if !c.allowed(name) {
+		c.addError(call, fmt.Sprintf("environment variable %q isn't in --config.env-allowlist", name))
+		return
+	}
+
+	if _, defaulted := c.defaulted[call]; defaulted {
+		return
+	}
+	if _, set := os.LookupEnv(name); !set {
+		c.addError(call, fmt.Sprintf("environment variable %q isn't set and has no default value", name))
+	}
+}
+
+func (c *envChecker) allowed(name string) bool {
+	for _, pattern := range c.allowlist {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *envChecker) addError(call *ast.CallExpr, msg string) {
+	c.diags.Add(diag.Diagnostic{
+		Severity: diag.SeverityLevelError,
+		StartPos: ast.StartPos(call).Position(),
+		EndPos:   ast.EndPos(call).Position(),
+		Message:  msg,
+	})
+}
+
+// calledFunction returns the name of the stdlib function invoked by call, or
+// an empty string if call doesn't invoke a function by its identifier.
+func calledFunction(call *ast.CallExpr) string {
+	if ident, ok := call.Value.(*ast.IdentifierExpr); ok {
+		return ident.Ident.Name
+	}
+	return ""
+}
@@ -124,6 +124,38 @@ func TestParseSources_UniqueComponent(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSource_CheckEnv(t *testing.T) {
+	t.Setenv("ALLOY_SET", "set")
+
+	content := `
+		logging {
+			level = coalesce(env("ALLOY_LEVEL"), "info")
+		}
+
+		testcomponents.passthrough "static" {
+			input = env("ALLOY_SET") + env("ALLOY_UNSET") + env("HOME") + env(format("ALLOY_%s", "SET"))
+		}
+	`
+
+	f, err := ParseSource(t.Name(), []byte(content))
+	require.NoError(t, err)
+
+	require.NoError(t, f.CheckEnv(nil))
+
+	var diags diag.Diagnostics
+	require.ErrorAs(t, f.CheckEnv([]string{"ALLOY_*"}), &diags)
+
+	var messages []string
+	for _, d := range diags {
+		messages = append(messages, d.Message)
+	}
+	require.Equal(t, []string{
+		`environment variable "ALLOY_UNSET" isn't set and has no default value`,
+		`environment variable "HOME" isn't in --config.env-allowlist`,
+		"env must be called with a string literal when --config.env-allowlist is set",
+	}, messages)
+}
+
 func getBlockID(b *ast.BlockStmt) string {
 	var parts []string
 	parts = append(parts, b.Name...)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/grafana/alloy/internal/component/otelcol"
 	"github.com/hashicorp/go-multierror"
@@ -20,6 +21,7 @@ type client struct {
 
 	mut     sync.RWMutex
 	writeTo []otelcol.Consumer
+	otlp    otlptrace.Client // Client to an OTLP endpoint (may be nil).
 }
 
 var _ otlptrace.Client = (*client)(nil)
@@ -30,17 +32,51 @@ func (cli *client) UpdateWriteTo(consumers []otelcol.Consumer) {
 	cli.writeTo = consumers
 }
 
+// UpdateOTLP replaces the client used to send traces to an OTLP endpoint,
+// which may be nil. The previous client is stopped, and the new one is
+// started if cli is running.
+func (cli *client) UpdateOTLP(otlp otlptrace.Client) error {
+	cli.mut.Lock()
+	defer cli.mut.Unlock()
+
+	if cli.otlp != nil && cli.started.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// The previous client may fail to flush pending traces; that's not a
+		// reason to fail the update.
+		_ = cli.otlp.Stop(ctx)
+	}
+
+	cli.otlp = otlp
+	if otlp != nil && cli.started.Load() {
+		return otlp.Start(context.Background())
+	}
+	return nil
+}
+
 func (cli *client) Start(ctx context.Context) error {
+	cli.mut.Lock()
+	defer cli.mut.Unlock()
+
 	if !cli.started.CompareAndSwap(false, true) {
 		return fmt.Errorf("already started")
 	}
+	if cli.otlp != nil {
+		return cli.otlp.Start(ctx)
+	}
 	return nil
 }
 
 func (cli *client) Stop(ctx context.Context) error {
+	cli.mut.Lock()
+	defer cli.mut.Unlock()
+
 	if !cli.started.CompareAndSwap(true, false) {
 		return fmt.Errorf("not running")
 	}
+	if cli.otlp != nil {
+		return cli.otlp.Stop(ctx)
+	}
 	return nil
 }
 
@@ -57,6 +93,12 @@ func (cli *client) UploadTraces(ctx context.Context, protoSpans []*tracepb.Resou
 
 	var errs error
 
+	if cli.otlp != nil {
+		if err := cli.otlp.UploadTraces(ctx, protoSpans); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
 	for _, target := range cli.writeTo {
 		send := payload
 
@@ -5,13 +5,19 @@ package tracing
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/grafana/alloy/internal/build"
 	"github.com/grafana/alloy/internal/component/otelcol"
 	"github.com/grafana/alloy/internal/runtime/tracing/internal/jaegerremote"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
@@ -32,6 +38,17 @@ var (
 		MaxOperations:   256,
 		RefreshInterval: time.Minute,
 	}
+
+	DefaultOTLPOptions = OTLPOptions{
+		Protocol: OTLPProtocolGRPC,
+		Timeout:  10 * time.Second,
+	}
+)
+
+// Supported values of OTLPOptions.Protocol.
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http/protobuf"
 )
 
 // Options control the tracing subsystem.
@@ -47,6 +64,18 @@ type Options struct {
 	// WriteTo holds a set of OpenTelemetry Collector consumers where internal
 	// traces should be sent.
 	WriteTo []otelcol.Consumer `alloy:"write_to,attr,optional"`
+
+	// OTLP optionally sends internal traces straight to an OTLP endpoint,
+	// without going through components.
+	OTLP *OTLPOptions `alloy:"otlp,block,optional"`
+}
+
+// OTLPOptions configure the OTLP endpoint internal traces are sent to.
+type OTLPOptions struct {
+	Endpoint string                       `alloy:"endpoint,attr"`
+	Protocol string                       `alloy:"protocol,attr,optional"`
+	Headers  map[string]alloytypes.Secret `alloy:"headers,attr,optional"`
+	Timeout  time.Duration                `alloy:"timeout,attr,optional"`
 }
 
 type SamplerOptions struct {
@@ -72,6 +101,55 @@ func (opts *JaegerRemoteSamplerOptions) SetToDefault() {
 	*opts = DefaultJaegerRemoteSamplerOptions
 }
 
+// SetToDefault implements syntax.Defaulter.
+func (opts *OTLPOptions) SetToDefault() {
+	*opts = DefaultOTLPOptions
+}
+
+// Validate implements syntax.Validator.
+func (opts *OTLPOptions) Validate() error {
+	u, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", opts.Endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("endpoint %q must start with http:// or https://", opts.Endpoint)
+	}
+
+	switch opts.Protocol {
+	case OTLPProtocolGRPC, OTLPProtocolHTTP:
+	default:
+		return fmt.Errorf("unsupported protocol %q, must be %q or %q", opts.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+
+	if opts.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	return nil
+}
+
+// newClient returns an unstarted client sending traces to the endpoint of
+// opts. Endpoints using the http scheme are reached without TLS.
+func (opts *OTLPOptions) newClient() otlptrace.Client {
+	headers := make(map[string]string, len(opts.Headers))
+	for k, v := range opts.Headers {
+		headers[k] = string(v)
+	}
+
+	if opts.Protocol == OTLPProtocolHTTP {
+		return otlptracehttp.NewClient(
+			otlptracehttp.WithEndpointURL(opts.Endpoint),
+			otlptracehttp.WithHeaders(headers),
+			otlptracehttp.WithTimeout(opts.Timeout),
+		)
+	}
+	return otlptracegrpc.NewClient(
+		otlptracegrpc.WithEndpointURL(opts.Endpoint),
+		otlptracegrpc.WithHeaders(headers),
+		otlptracegrpc.WithTimeout(opts.Timeout),
+	)
+}
+
 // Tracer is the tracing subsystem of Grafana Alloy. It implements
 // [trace.TracerProvider] and can be used to forward internally generated
 // traces to a OpenTelemetry Collector-compatible Alloy component.
@@ -84,6 +162,7 @@ type Tracer struct {
 
 	samplerMut          sync.Mutex
 	jaegerRemoteSampler *jaegerremote.Sampler // In-use jaeger remote sampler (may be nil).
+	otlp                *OTLPOptions          // In-use OTLP endpoint options (may be nil).
 }
 
 var _ trace.TracerProvider = (*Tracer)(nil)
@@ -138,6 +217,19 @@ func (t *Tracer) Update(opts Options) error {
 
 	t.client.UpdateWriteTo(opts.WriteTo)
 
+	// Only replace the OTLP client when its options change, so that its
+	// connection is kept across updates.
+	if !reflect.DeepEqual(opts.OTLP, t.otlp) {
+		var otlpClient otlptrace.Client
+		if opts.OTLP != nil {
+			otlpClient = opts.OTLP.newClient()
+		}
+		if err := t.client.UpdateOTLP(otlpClient); err != nil {
+			return fmt.Errorf("starting OTLP client: %w", err)
+		}
+		t.otlp = opts.OTLP
+	}
+
 	// Stop the previous instance of the Jaeger remote sampler if it exists. The
 	// sampler can still make sampling decisions after being closed; it just
 	// won't poll anymore.
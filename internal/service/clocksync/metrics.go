@@ -0,0 +1,28 @@
+package clocksync
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type metrics struct {
+	skew     prometheus.Gauge
+	exceeded prometheus.Gauge
+	errors   *prometheus.CounterVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		skew: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alloy_clocksync_skew_seconds",
+			Help: "Skew of the local clock compared to the last source which answered. A positive skew means that the local clock is ahead.",
+		}),
+		exceeded: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "alloy_clocksync_skew_exceeded",
+			Help: "Whether the skew of the local clock exceeds the maximum skew.",
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "alloy_clocksync_errors_total",
+			Help: "Total number of failed measurements of the skew of the local clock, by source.",
+		}, []string{"source"}),
+	}
+	r.MustRegister(m.skew, m.exceeded, m.errors)
+	return m
+}
@@ -0,0 +1,273 @@
+// Package clocksync implements the clocksync service, which monitors the skew
+// of the local clock and reports it to the components which depend on the
+// local clock to order the data they send.
+package clocksync
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ServiceName defines the name used for the clocksync service.
+const ServiceName = "clocksync"
+
+// Arguments holds the configuration of the clocksync block.
+type Arguments struct {
+	NTPServers    []string      `alloy:"ntp_servers,attr,optional"`
+	HTTPEndpoints []string      `alloy:"http_endpoints,attr,optional"`
+	MaxSkew       time.Duration `alloy:"max_skew,attr,optional"`
+	Interval      time.Duration `alloy:"interval,attr,optional"`
+	Timeout       time.Duration `alloy:"timeout,attr,optional"`
+
+	// ProxyConfig is the proxy of the requests to the HTTP endpoints.
+	ProxyConfig *config.ProxyConfig `alloy:",squash"`
+}
+
+// DefaultArguments holds the default settings of the clocksync block.
+var DefaultArguments = Arguments{
+	MaxSkew:  5 * time.Second,
+	Interval: time.Minute,
+	Timeout:  5 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.MaxSkew <= 0 {
+		return fmt.Errorf("max_skew must be greater than 0")
+	}
+	if args.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if args.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	for _, endpoint := range args.HTTPEndpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid http_endpoints %q: %w", endpoint, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid http_endpoints %q: scheme must be http or https", endpoint)
+		}
+	}
+	return args.ProxyConfig.Validate()
+}
+
+// Monitor reports whether the local clock is in sync. It's the data exposed
+// by the clocksync service.
+type Monitor interface {
+	// Health returns an unhealthy Health with a message describing the skew
+	// when the skew of the local clock exceeds the maximum skew, and a healthy
+	// Health without any message otherwise.
+	Health() component.Health
+}
+
+// GetMonitor returns the Monitor of the clocksync service. It returns a
+// Monitor which always reports a healthy clock when the service isn't
+// available, for example in tests.
+func GetMonitor(opts component.Options) Monitor {
+	if opts.GetServiceData == nil {
+		return nopMonitor{}
+	}
+	data, err := opts.GetServiceData(ServiceName)
+	if err != nil {
+		return nopMonitor{}
+	}
+	return data.(Monitor)
+}
+
+type nopMonitor struct{}
+
+func (nopMonitor) Health() component.Health {
+	return component.Health{Health: component.HealthTypeHealthy}
+}
+
+// Options are used to configure the clocksync service.
+type Options struct {
+	Logger  log.Logger
+	Metrics prometheus.Registerer
+}
+
+// Service implements the clocksync service.
+type Service struct {
+	logger  log.Logger
+	metrics *metrics
+
+	// updated is signaled when the arguments change, to check the clock again
+	// without waiting for the next interval.
+	updated chan struct{}
+
+	mut    sync.RWMutex
+	args   Arguments
+	status status
+}
+
+// status is the result of the last check of the local clock.
+type status struct {
+	skew       time.Duration
+	source     string
+	exceeded   bool
+	updateTime time.Time
+}
+
+var (
+	_ service.Service = (*Service)(nil)
+	_ Monitor         = (*Service)(nil)
+)
+
+// New returns a new, unstarted instance of the clocksync service.
+func New(opts Options) *Service {
+	l := opts.Logger
+	if l == nil {
+		l = log.NewNopLogger()
+	}
+	r := opts.Metrics
+	if r == nil {
+		r = prometheus.NewRegistry()
+	}
+
+	return &Service{
+		logger:  l,
+		metrics: newMetrics(r),
+		updated: make(chan struct{}, 1),
+		args:    DefaultArguments,
+	}
+}
+
+// Definition implements service.Service.
+func (*Service) Definition() service.Definition {
+	return service.Definition{
+		Name:       ServiceName,
+		ConfigType: Arguments{},
+		DependsOn:  []string{},
+		Stability:  featuregate.StabilityExperimental,
+	}
+}
+
+// Data implements service.Service. It returns the Monitor of the service for
+// the components to report the skew of the local clock in their health.
+func (s *Service) Data() any {
+	return s
+}
+
+// Health implements Monitor.
+func (s *Service) Health() component.Health {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	if !s.status.exceeded {
+		return component.Health{Health: component.HealthTypeHealthy}
+	}
+	return component.Health{
+		Health: component.HealthTypeUnhealthy,
+		Message: fmt.Sprintf(
+			"the local clock is skewed by %s compared to %s, which exceeds the maximum skew of %s: samples and log lines may be rejected or stored out of order",
+			s.status.skew, s.status.source, s.args.MaxSkew,
+		),
+		UpdateTime: s.status.updateTime,
+	}
+}
+
+// Update implements service.Service.
+func (s *Service) Update(newConfig any) error {
+	newArgs := newConfig.(Arguments)
+
+	s.mut.Lock()
+	s.args = newArgs
+	if !newArgs.enabled() {
+		s.status = status{}
+		s.metrics.exceeded.Set(0)
+	}
+	s.mut.Unlock()
+
+	select {
+	case s.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run implements service.Service.
+func (s *Service) Run(ctx context.Context, _ service.Host) error {
+	s.check(ctx)
+
+	for {
+		s.mut.RLock()
+		interval := s.args.Interval
+		s.mut.RUnlock()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.updated:
+		case <-time.After(interval):
+		}
+		s.check(ctx)
+	}
+}
+
+// enabled returns whether at least one source to compare the local clock with
+// is configured.
+func (args Arguments) enabled() bool {
+	return len(args.NTPServers) > 0 || len(args.HTTPEndpoints) > 0
+}
+
+// check measures the skew of the local clock against the first source which
+// answers, in the order they're configured, and updates the status.
+func (s *Service) check(ctx context.Context) {
+	s.mut.RLock()
+	args := s.args
+	s.mut.RUnlock()
+
+	if !args.enabled() {
+		return
+	}
+
+	for _, src := range sources(args) {
+		skew, err := src.query(ctx, args.Timeout)
+		if err != nil {
+			s.metrics.errors.WithLabelValues(src.name).Inc()
+			level.Warn(s.logger).Log("msg", "failed to measure the skew of the local clock", "source", src.name, "err", err)
+			continue
+		}
+
+		exceeded := skew.Abs() > args.MaxSkew
+		s.metrics.skew.Set(skew.Seconds())
+		if exceeded {
+			s.metrics.exceeded.Set(1)
+			level.Warn(s.logger).Log("msg", "the skew of the local clock exceeds the maximum skew", "source", src.name, "skew", skew, "max_skew", args.MaxSkew)
+		} else {
+			s.metrics.exceeded.Set(0)
+		}
+
+		s.mut.Lock()
+		if !s.status.exceeded || !exceeded {
+			s.status.updateTime = time.Now()
+		}
+		s.status.skew = skew
+		s.status.source = src.name
+		s.status.exceeded = exceeded
+		s.mut.Unlock()
+		return
+	}
+
+	// Keep the previous status when no source answered, since the skew can't
+	// be measured.
+	level.Error(s.logger).Log("msg", "failed to measure the skew of the local clock with all the sources")
+}
@@ -0,0 +1,152 @@
+package clocksync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Arguments)
+		err    string
+	}{
+		{
+			name:   "defaults",
+			modify: func(*Arguments) {},
+		},
+		{
+			name:   "http endpoint",
+			modify: func(a *Arguments) { a.HTTPEndpoints = []string{"https://example.com"} },
+		},
+		{
+			name:   "invalid max_skew",
+			modify: func(a *Arguments) { a.MaxSkew = 0 },
+			err:    "max_skew must be greater than 0",
+		},
+		{
+			name:   "invalid interval",
+			modify: func(a *Arguments) { a.Interval = -time.Second },
+			err:    "interval must be greater than 0",
+		},
+		{
+			name:   "invalid scheme",
+			modify: func(a *Arguments) { a.HTTPEndpoints = []string{"ftp://example.com"} },
+			err:    `invalid http_endpoints "ftp://example.com": scheme must be http or https`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			args.SetToDefault()
+			tt.modify(&args)
+
+			err := args.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestService_Health(t *testing.T) {
+	var skew time.Duration
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-skew).UTC().Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+
+	s := New(Options{})
+	args := DefaultArguments
+	args.HTTPEndpoints = []string{srv.URL}
+	require.NoError(t, s.Update(args))
+
+	s.check(context.Background())
+	require.Equal(t, component.HealthTypeHealthy, s.Health().Health)
+
+	skew = time.Minute
+	s.check(context.Background())
+	health := s.Health()
+	require.Equal(t, component.HealthTypeUnhealthy, health.Health)
+	require.Contains(t, health.Message, fmt.Sprintf("compared to %s, which exceeds the maximum skew of 5s", srv.URL))
+
+	// Removing the sources disables the check.
+	require.NoError(t, s.Update(DefaultArguments))
+	require.Equal(t, component.HealthTypeHealthy, s.Health().Health)
+}
+
+func TestService_KeepsStatusOnErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	}))
+
+	s := New(Options{})
+	args := DefaultArguments
+	args.HTTPEndpoints = []string{srv.URL}
+	require.NoError(t, s.Update(args))
+
+	s.check(context.Background())
+	require.Equal(t, component.HealthTypeUnhealthy, s.Health().Health)
+
+	srv.Close()
+	s.check(context.Background())
+	require.Equal(t, component.HealthTypeUnhealthy, s.Health().Health)
+}
+
+func TestHTTPQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-30*time.Second).UTC().Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+
+	skew, err := httpQuery(newHTTPClient(DefaultArguments), srv.URL)(context.Background(), time.Second)
+	require.NoError(t, err)
+	require.InDelta(t, 30, skew.Seconds(), 1)
+
+	noDate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header()["Date"] = nil
+	}))
+	defer noDate.Close()
+
+	_, err = httpQuery(newHTTPClient(DefaultArguments), noDate.URL)(context.Background(), time.Second)
+	require.EqualError(t, err, "the response doesn't have a Date header")
+}
+
+func TestHTTPQuery_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	args := DefaultArguments
+	args.ProxyConfig = &config.ProxyConfig{ProxyURL: config.URL{URL: proxyURL}}
+
+	_, err = httpQuery(newHTTPClient(args), "http://clock.example.com/")(context.Background(), time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "http://clock.example.com/", proxied)
+}
+
+func TestGetMonitor_NoService(t *testing.T) {
+	opts := component.Options{
+		GetServiceData: func(name string) (interface{}, error) {
+			return nil, fmt.Errorf("no service named %s defined", name)
+		},
+	}
+	require.Equal(t, component.HealthTypeHealthy, GetMonitor(opts).Health().Health)
+}
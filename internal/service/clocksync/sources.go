@@ -0,0 +1,112 @@
+package clocksync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+// httpDatePrecision is the precision of the Date header, which is truncated
+// to the second.
+const httpDatePrecision = time.Second
+
+// source is a reference clock to compare the local clock with.
+type source struct {
+	name string
+
+	// query returns the skew of the local clock compared to the source. A
+	// positive skew means that the local clock is ahead of the source.
+	query func(ctx context.Context, timeout time.Duration) (time.Duration, error)
+}
+
+// sources returns the sources configured in args, the NTP servers first.
+func sources(args Arguments) []source {
+	res := make([]source, 0, len(args.NTPServers)+len(args.HTTPEndpoints))
+	for _, server := range args.NTPServers {
+		res = append(res, source{name: server, query: ntpQuery(server)})
+	}
+	if len(args.HTTPEndpoints) == 0 {
+		return res
+	}
+
+	client := newHTTPClient(args)
+	for _, endpoint := range args.HTTPEndpoints {
+		res = append(res, source{name: endpoint, query: httpQuery(client, endpoint)})
+	}
+	return res
+}
+
+// newHTTPClient returns the client querying the HTTP endpoints, through the
+// configured proxy.
+func newHTTPClient(args Arguments) *http.Client {
+	proxy := args.ProxyConfig.Convert()
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:              proxy.Proxy(),
+			ProxyConnectHeader: proxy.GetProxyConnectHeader(),
+			DisableKeepAlives:  true,
+		},
+		Timeout: args.Timeout,
+	}
+}
+
+func ntpQuery(server string) func(context.Context, time.Duration) (time.Duration, error) {
+	return func(_ context.Context, timeout time.Duration) (time.Duration, error) {
+		resp, err := ntp.QueryWithOptions(server, ntp.QueryOptions{Timeout: timeout})
+		if err != nil {
+			return 0, err
+		}
+		if err := resp.Validate(); err != nil {
+			return 0, err
+		}
+		// ClockOffset is the offset to add to the local clock to match the
+		// server.
+		return -resp.ClockOffset, nil
+	}
+}
+
+// httpQuery compares the local clock with the Date header of the responses of
+// endpoint. The local time of the response is estimated as the middle of the
+// request.
+func httpQuery(client *http.Client, endpoint string) func(context.Context, time.Duration) (time.Duration, error) {
+	return func(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		end := time.Now()
+		resp.Body.Close()
+
+		header := resp.Header.Get("Date")
+		if header == "" {
+			return 0, fmt.Errorf("the response doesn't have a Date header")
+		}
+		date, err := http.ParseTime(header)
+		if err != nil {
+			return 0, fmt.Errorf("invalid Date header %q: %w", header, err)
+		}
+
+		local := start.Add(end.Sub(start) / 2)
+		remote := date.Add(httpDatePrecision / 2)
+		skew := local.Sub(remote)
+
+		// Ignore the skews which can be explained by the precision of the
+		// header.
+		if skew.Abs() <= httpDatePrecision/2 {
+			return 0, nil
+		}
+		return skew, nil
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/secrets"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/web/adminauth"
+	"github.com/grafana/alloy/syntax/encoding/alloyjson"
+)
+
+// exportsResponse is the response of the component exports endpoint.
+type exportsResponse struct {
+	ID      string          `json:"id"`
+	Exports json.RawMessage `json:"exports"`
+}
+
+// componentExportsHandler returns the current exports of a component, once
+// the request was authenticated with the admin token. The exports are encoded
+// like the exports shown in the UI, with secrets masked.
+func (s *Service) componentExportsHandler(host service.Host) http.HandlerFunc {
+	return adminauth.Require(s.opts.AdminToken, "the component exports API", func(w http.ResponseWriter, r *http.Request) {
+		id := component.ParseID(mux.Vars(r)["id"])
+		info, err := host.GetComponent(id, component.InfoOptions{GetExports: true})
+		switch {
+		case errors.Is(err, component.ErrComponentNotFound):
+			http.NotFound(w, r)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		exports, err := alloyjson.MarshalBody(info.Exports)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		bb, err := json.Marshal(exportsResponse{ID: info.ID.String(), Exports: exports})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		// Secrets which aren't typed as secrets, such as credentials in URLs,
+		// are also masked.
+		_, _ = w.Write(secrets.ScrubBytes(bb))
+	})
+}
@@ -4,6 +4,7 @@ package http
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -17,11 +18,13 @@ import (
 	"github.com/go-kit/log"
 	"github.com/gorilla/mux"
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/configaudit"
 	"github.com/grafana/alloy/internal/featuregate"
 	alloy_runtime "github.com/grafana/alloy/internal/runtime"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/internal/static/server"
+	"github.com/grafana/alloy/internal/web/adminauth"
 	"github.com/grafana/ckit/memconn"
 	_ "github.com/grafana/pyroscope-go/godeltaprof/http/pprof" // Register godeltaprof handler
 	"github.com/prometheus/client_golang/prometheus"
@@ -43,12 +46,35 @@ type Options struct {
 	Tracer   trace.TracerProvider // Where to send traces.
 	Gatherer prometheus.Gatherer  // Where to collect metrics from.
 
-	ReadyFunc  func() bool
-	ReloadFunc func() (*alloy_runtime.Source, error)
+	ReadyFunc func() bool
+	// ReloadFunc reloads the config. actor identifies the client which
+	// requested the reload.
+	ReloadFunc func(actor string) (*alloy_runtime.Source, error)
+	// ConfigHistoryFunc returns the most recent loads of the config, from the
+	// oldest to the newest.
+	ConfigHistoryFunc func() []configaudit.Record
 
 	HTTPListenAddr   string // Address to listen for HTTP traffic on.
 	MemoryListenAddr string // Address to accept in-memory traffic on.
 	EnablePProf      bool   // Whether pprof endpoints should be exposed.
+
+	// AdminToken is the bearer token required to read the exports of
+	// components. The component exports API is disabled when it's empty.
+	AdminToken string
+
+	DisableSupportBundle bool                 // Whether the /-/support endpoint should be disabled.
+	SupportBundle        SupportBundleOptions // Extra content of support bundles.
+}
+
+// SupportBundleOptions provides the content of support bundles which isn't
+// owned by the HTTP service.
+type SupportBundleOptions struct {
+	// SourceFunc returns the currently loaded config, or nil if none was
+	// loaded yet.
+	SourceFunc func() *alloy_runtime.Source
+
+	// LogsFunc returns the recent logs of the process.
+	LogsFunc func() []byte
 }
 
 // Arguments holds runtime settings for the HTTP service.
@@ -175,6 +201,7 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 	}
 
 	r.PathPrefix(s.componentHttpPathPrefix).Handler(s.componentHandler(host))
+	r.Handle("/api/v0/components/{id:.+}/exports", s.componentExportsHandler(host)).Methods(http.MethodGet)
 
 	if s.opts.ReadyFunc != nil {
 		r.HandleFunc("/-/ready", func(w http.ResponseWriter, _ *http.Request) {
@@ -189,10 +216,10 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 	}
 
 	if s.opts.ReloadFunc != nil {
-		r.HandleFunc("/-/reload", func(w http.ResponseWriter, _ *http.Request) {
-			level.Info(s.log).Log("msg", "reload requested via /-/reload endpoint")
+		r.HandleFunc("/-/reload", func(w http.ResponseWriter, r *http.Request) {
+			level.Info(s.log).Log("msg", "reload requested via /-/reload endpoint", "remote_addr", r.RemoteAddr)
 
-			_, err := s.opts.ReloadFunc()
+			_, err := s.opts.ReloadFunc(r.RemoteAddr)
 			if err != nil {
 				level.Error(s.log).Log("msg", "failed to reload config", "err", err.Error())
 				http.Error(w, err.Error(), http.StatusBadRequest)
@@ -204,6 +231,19 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 		}).Methods(http.MethodGet, http.MethodPost)
 	}
 
+	if s.opts.ConfigHistoryFunc != nil {
+		// The history holds the clients which requested reloads and the paths
+		// of the configuration, so it requires the admin token.
+		r.HandleFunc("/-/config/history", adminauth.Require(s.opts.AdminToken, "the config history", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(s.opts.ConfigHistoryFunc())
+		})).Methods(http.MethodGet)
+	}
+
+	if !s.opts.DisableSupportBundle {
+		r.HandleFunc("/-/support", s.supportBundleHandler(host)).Methods(http.MethodGet)
+	}
+
 	// Wire custom service handlers for services which depend on the http
 	// service.
 	//
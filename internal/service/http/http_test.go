@@ -1,17 +1,24 @@
 package http
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"testing"
 
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/configaudit"
 	"github.com/grafana/alloy/internal/runtime"
 	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/phayes/freeport"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/config"
@@ -155,6 +162,174 @@ func Test_Toggle_TLS(t *testing.T) {
 	}
 }
 
+func TestSupportBundle(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t)
+	require.NoError(t, err)
+	require.NoError(t, env.ApplyConfig(`/* empty */`))
+
+	source, err := runtime.ParseSource("/etc/alloy/config.alloy", []byte(`logging { level = "hunter2" }`))
+	require.NoError(t, err)
+	env.svc.opts.SupportBundle = SupportBundleOptions{
+		SourceFunc: func() *runtime.Source { return source },
+		LogsFunc:   func() []byte { return []byte("level=info msg=\"using token hunter2\"\n") },
+	}
+
+	secrets.Default.Set(t.Name(), []string{"hunter2"})
+	t.Cleanup(func() { secrets.Default.Remove(t.Name()) })
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	get := func(t require.TestingT, query, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/-/support?%s", env.ListenAddr(), query), nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	var body []byte
+	util.Eventually(t, func(t require.TestingT) {
+		resp := get(t, "duration=0s", "admin-token")
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+		body, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+	})
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		bb, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(bb)
+	}
+
+	for _, name := range []string{
+		"alloy-components.json",
+		"alloy-metrics.txt",
+		"alloy-runtime-flags.txt",
+		"alloy-version.txt",
+		"pprof/cpu.pprof",
+		"pprof/goroutine.pprof",
+		"pprof/heap.pprof",
+	} {
+		require.Contains(t, files, name)
+	}
+	require.Equal(t, `logging { level = "(secret)" }`, files["config/config.alloy"])
+	require.Equal(t, "level=info msg=\"using token (secret)\"\n", files["alloy-logs.txt"])
+
+	t.Run("invalid duration", func(t *testing.T) {
+		resp := get(t, "duration=1h", "admin-token")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		resp := get(t, "duration=0s", "")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestConfigHistory(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t)
+	require.NoError(t, err)
+	require.NoError(t, env.ApplyConfig(`/* empty */`))
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	get := func(t require.TestingT, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/-/config/history", env.ListenAddr()), nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	util.Eventually(t, func(t require.TestingT) {
+		resp := get(t, "admin-token")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var history []configaudit.Record
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&history))
+		require.Equal(t, []configaudit.Record{{Trigger: configaudit.TriggerStartup, Path: "config.alloy", Success: true}}, history)
+	})
+
+	resp := get(t, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestComponentExports(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t)
+	require.NoError(t, err)
+	require.NoError(t, env.ApplyConfig(`/* empty */`))
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	get := func(t require.TestingT, id string, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/api/v0/components/%s/exports", env.ListenAddr(), id), nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	util.Eventually(t, func(t require.TestingT) {
+		resp := get(t, "test.exports", "admin-token")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		bb, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(bb), `"id":"test.exports"`)
+		require.Contains(t, string(bb), `"targets"`)
+		require.Contains(t, string(bb), "(secret)")
+		require.NotContains(t, string(bb), "hunter2")
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		resp := get(t, "test.exports", "wrong-token")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("unknown component", func(t *testing.T) {
+		resp := get(t, "test.unknown", "admin-token")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
 type testEnvironment struct {
 	svc  *Service
 	addr string
@@ -172,11 +347,15 @@ func newTestEnvironment(t *testing.T) (*testEnvironment, error) {
 		Gatherer: prometheus.NewRegistry(),
 
 		ReadyFunc:  func() bool { return true },
-		ReloadFunc: func() (*runtime.Source, error) { return nil, nil },
+		ReloadFunc: func(string) (*runtime.Source, error) { return nil, nil },
+		ConfigHistoryFunc: func() []configaudit.Record {
+			return []configaudit.Record{{Trigger: configaudit.TriggerStartup, Path: "config.alloy", Success: true}}
+		},
 
 		HTTPListenAddr:   fmt.Sprintf("127.0.0.1:%d", port),
 		MemoryListenAddr: "alloy.internal:12345",
 		EnablePProf:      true,
+		AdminToken:       "admin-token",
 	})
 
 	return &testEnvironment{
@@ -203,8 +382,22 @@ type fakeHost struct{}
 
 var _ service.Host = (fakeHost{})
 
+type testExports struct {
+	Targets  []map[string]string `alloy:"targets,attr"`
+	Password alloytypes.Secret   `alloy:"password,attr"`
+}
+
 func (fakeHost) GetComponent(id component.ID, opts component.InfoOptions) (*component.Info, error) {
-	return nil, fmt.Errorf("no such component %s", id)
+	if id.String() == "test.exports" {
+		return &component.Info{
+			ID: id,
+			Exports: testExports{
+				Targets:  []map[string]string{{"__address__": "localhost:9090"}},
+				Password: "hunter2",
+			},
+		}, nil
+	}
+	return nil, component.ErrComponentNotFound
 }
 
 func (fakeHost) ListComponents(moduleID string, opts component.InfoOptions) ([]*component.Info, error) {
@@ -216,6 +409,12 @@ func (fakeHost) ListComponents(moduleID string, opts component.InfoOptions) ([]*
 
 func (fakeHost) GetServiceConsumers(serviceName string) []service.Consumer { return nil }
 
+func (fakeHost) GetReloadReport() *component.ReloadReport { return nil }
+
+func (fakeHost) PauseComponent(id component.ID) error { return component.ErrComponentNotFound }
+
+func (fakeHost) ResumeComponent(id component.ID) error { return component.ErrComponentNotFound }
+
 func (fakeHost) NewController(id string) service.Controller { return nil }
 
 func (fakeHost) GetService(_ string) (service.Service, bool) { return nil, false }
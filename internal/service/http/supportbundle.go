@@ -0,0 +1,207 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/build"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/runtime/secrets"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/web/adminauth"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// defaultSupportBundleDuration is how long the CPU profile of a support
+	// bundle lasts when the duration isn't specified.
+	defaultSupportBundleDuration = 30 * time.Second
+
+	// maxSupportBundleDuration is the longest accepted support bundle
+	// duration.
+	maxSupportBundleDuration = 5 * time.Minute
+)
+
+// supportBundleProfiles are the runtime/pprof profiles included in support
+// bundles, in addition to the CPU profile.
+var supportBundleProfiles = []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"}
+
+// supportBundleHandler returns the handler for the /-/support endpoint, which
+// serves a zip archive with the information needed to troubleshoot the
+// process.
+//
+// The optional duration query parameter sets how long the CPU profile of the
+// bundle lasts. The bundle holds the config, the arguments of components and
+// the logs, so the request must be authenticated with the admin token.
+func (s *Service) supportBundleHandler(host service.Host) http.HandlerFunc {
+	return adminauth.Require(s.opts.AdminToken, "the support bundle", func(w http.ResponseWriter, r *http.Request) {
+		duration := defaultSupportBundleDuration
+		if v := r.URL.Query().Get("duration"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid duration %q: %s", v, err), http.StatusBadRequest)
+				return
+			}
+			if d < 0 || d > maxSupportBundleDuration {
+				http.Error(w, fmt.Sprintf("duration must be between 0s and %s", maxSupportBundleDuration), http.StatusBadRequest)
+				return
+			}
+			duration = d
+		}
+
+		level.Info(s.log).Log("msg", "generating support bundle", "duration", duration)
+
+		// The bundle is built in memory first so that failures can still be
+		// reported with an error status.
+		var buf bytes.Buffer
+		if err := s.writeSupportBundle(r.Context(), &buf, host, duration); err != nil {
+			level.Error(s.log).Log("msg", "failed to generate support bundle", "err", err)
+			http.Error(w, fmt.Sprintf("failed to generate support bundle: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="alloy-support-bundle.zip"`)
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+// writeSupportBundle writes the zip archive of a support bundle to w. The
+// secrets used by components are redacted from every file of the archive,
+// except the binary profiles.
+func (s *Service) writeSupportBundle(ctx context.Context, w io.Writer, host service.Host, duration time.Duration) error {
+	zw := zip.NewWriter(w)
+
+	// The CPU profile is captured first, so that the other files reflect the
+	// state of the process at the end of the capture.
+	if s.opts.EnablePProf {
+		var cpu bytes.Buffer
+		if err := pprof.StartCPUProfile(&cpu); err != nil {
+			// Only one CPU profile can run at a time, so a concurrent capture
+			// from /debug/pprof/profile makes this one fail.
+			level.Warn(s.log).Log("msg", "skipping CPU profile of support bundle", "err", err)
+		} else {
+			select {
+			case <-ctx.Done():
+			case <-time.After(duration):
+			}
+			pprof.StopCPUProfile()
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := writeZipFile(zw, "pprof/cpu.pprof", cpu.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range supportBundleProfiles {
+			var profile bytes.Buffer
+			if err := pprof.Lookup(name).WriteTo(&profile, 0); err != nil {
+				return fmt.Errorf("writing %s profile: %w", name, err)
+			}
+			if err := writeZipFile(zw, "pprof/"+name+".pprof", profile.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+
+	files := map[string][]byte{
+		"alloy-version.txt":       []byte(build.Print("alloy") + "\n"),
+		"alloy-runtime-flags.txt": []byte(strings.Join(os.Args[1:], "\n") + "\n"),
+	}
+
+	components, err := json.MarshalIndent(component.GetAllComponents(host, component.InfoOptions{
+		GetHealth:    true,
+		GetArguments: true,
+		GetDebugInfo: true,
+	}), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding components: %w", err)
+	}
+	files["alloy-components.json"] = components
+
+	if report := host.GetReloadReport(); report != nil {
+		bb, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding reload report: %w", err)
+		}
+		files["alloy-reload-report.json"] = bb
+	}
+
+	metrics, err := s.gatherMetrics()
+	if err != nil {
+		return err
+	}
+	files["alloy-metrics.txt"] = metrics
+
+	if s.opts.SupportBundle.LogsFunc != nil {
+		files["alloy-logs.txt"] = s.opts.SupportBundle.LogsFunc()
+	}
+
+	if s.opts.SupportBundle.SourceFunc != nil {
+		if source := s.opts.SupportBundle.SourceFunc(); source != nil {
+			for name, bb := range source.RawConfigs() {
+				files["config/"+filepath.Base(name)] = bb
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writeZipFile(zw, name, secrets.ScrubBytes(files[name])); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// gatherMetrics returns the current metrics of s in the Prometheus text
+// format.
+func (s *Service) gatherMetrics() ([]byte, error) {
+	families, err := s.gatherer.Gather()
+	if err != nil {
+		// Gather returns the metrics it could collect along with the error.
+		level.Warn(s.log).Log("msg", "failed to gather some metrics for support bundle", "err", err)
+	}
+
+	var buf bytes.Buffer
+	enc := expfmt.NewEncoder(&buf, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return nil, fmt.Errorf("encoding metrics: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package livedebugging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AttributeFilter matches live debugging data holding a set of attributes or
+// labels with given values.
+type AttributeFilter struct {
+	matchers []*regexp.Regexp
+}
+
+// ParseAttributeFilter parses a comma-separated list of key=value pairs into
+// an AttributeFilter. Data matches the filter when it holds all the pairs,
+// either as OpenTelemetry attributes as printed by otelcol components
+// (-> key: Str(value)) or as labels (key="value"). An empty list matches all
+// data.
+func ParseAttributeFilter(s string) (*AttributeFilter, error) {
+	f := &AttributeFilter{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid attribute %q, expected key=value", pair)
+		}
+		k, v := regexp.QuoteMeta(key), regexp.QuoteMeta(value)
+		f.matchers = append(f.matchers, regexp.MustCompile(
+			`(?m)(?:-> `+k+`: \w+\(`+v+`\)$|(?:^|[\s{,])`+k+`="`+v+`")`,
+		))
+	}
+	return f, nil
+}
+
+// Match reports whether data holds all the attributes of f.
+func (f *AttributeFilter) Match(data string) bool {
+	for _, m := range f.matchers {
+		if !m.MatchString(data) {
+			return false
+		}
+	}
+	return true
+}
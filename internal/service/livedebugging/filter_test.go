@@ -0,0 +1,38 @@
+package livedebugging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeFilter(t *testing.T) {
+	span := "ResourceSpans #0\nResource attributes:\n     -> service.name: Str(checkout)\nSpan #0\n    Name: GET /cart\nAttributes:\n     -> http.status_code: Int(500)\n"
+	series := `{__name__="http_requests_total", job="checkout", status="500"} => 12 @ 1700000000000`
+
+	tests := []struct {
+		filter string
+		data   string
+		match  bool
+	}{
+		{filter: "", data: span, match: true},
+		{filter: "service.name=checkout", data: span, match: true},
+		{filter: "service.name=checkout, http.status_code=500", data: span, match: true},
+		{filter: "service.name=checkout,http.status_code=200", data: span, match: false},
+		{filter: "service.name=check", data: span, match: false},
+		{filter: "job=checkout,status=500", data: series, match: true},
+		{filter: "__name__=http_requests_total", data: series, match: true},
+		{filter: "job=cart", data: series, match: false},
+		{filter: "ob=checkout", data: series, match: false},
+	}
+	for _, tt := range tests {
+		f, err := ParseAttributeFilter(tt.filter)
+		require.NoError(t, err)
+		require.Equal(t, tt.match, f.Match(tt.data), "filter %q", tt.filter)
+	}
+
+	_, err := ParseAttributeFilter("service.name")
+	require.Error(t, err)
+	_, err = ParseAttributeFilter("=checkout")
+	require.Error(t, err)
+}
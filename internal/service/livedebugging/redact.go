@@ -0,0 +1,43 @@
+package livedebugging
+
+import (
+	"regexp"
+
+	"github.com/grafana/alloy/internal/runtime/secrets"
+)
+
+// redactedValue replaces the values of secrets in debugging data.
+const redactedValue = "<redacted>"
+
+// sensitiveKey matches the names of labels, attributes, and fields which
+// usually hold secrets.
+const sensitiveKey = `(?i:[\w.\-]*(?:password|passwd|secret|token|api[_.\-]?key|authorization|credential|private[_.\-]?key)[\w.\-]*)`
+
+var redactions = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Quoted values, such as Prometheus labels and logfmt fields:
+	// password="value".
+	{regexp.MustCompile(`\b(` + sensitiveKey + `\s*=\s*)"(?:[^"\\]|\\.)*"`), `${1}"` + redactedValue + `"`},
+	// JSON fields: "password": "value".
+	{regexp.MustCompile(`("` + sensitiveKey + `"\s*:\s*)"(?:[^"\\]|\\.)*"`), `${1}"` + redactedValue + `"`},
+	// OpenTelemetry attributes as printed by otelcol components:
+	// -> password: Str(value).
+	{regexp.MustCompile(`(?m)(-> ` + sensitiveKey + `: \w+)\(.*\)$`), `${1}(` + redactedValue + `)`},
+	// Unquoted values: password=value.
+	{regexp.MustCompile(`\b(` + sensitiveKey + `=)[^\s",}]+`), `${1}` + redactedValue},
+	// Credentials of HTTP authorization headers.
+	{regexp.MustCompile(`(?i)\b(Bearer|Basic)\s+[\w\-.~+/]+=*`), `${1} ` + redactedValue},
+}
+
+// Redact replaces the values of likely secrets in debugging data, such as the
+// values of labels or attributes named after passwords or tokens, so that the
+// data can be shared. The secrets used by components are always redacted.
+func Redact(data string) string {
+	data = secrets.Scrub(data)
+	for _, r := range redactions {
+		data = r.re.ReplaceAllString(data, r.repl)
+	}
+	return data
+}
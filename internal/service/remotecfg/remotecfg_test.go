@@ -186,6 +186,9 @@ func (fakeHost) ListComponents(moduleID string, opts component.InfoOptions) ([]*
 
 func (fakeHost) GetServiceConsumers(_ string) []service.Consumer { return nil }
 func (fakeHost) GetService(_ string) (service.Service, bool)     { return nil, false }
+func (fakeHost) GetReloadReport() *component.ReloadReport        { return nil }
+func (fakeHost) PauseComponent(_ component.ID) error             { return component.ErrComponentNotFound }
+func (fakeHost) ResumeComponent(_ component.ID) error            { return component.ErrComponentNotFound }
 
 func (f fakeHost) NewController(id string) service.Controller {
 	logger, _ := logging.New(io.Discard, logging.DefaultOptions)
@@ -248,3 +251,9 @@ func (sc serviceController) LoadSource(b []byte, args map[string]any) error {
 	return sc.f.LoadSource(source, args)
 }
 func (sc serviceController) Ready() bool { return sc.f.Ready() }
+func (sc serviceController) GetComponent(id component.ID, opts component.InfoOptions) (*component.Info, error) {
+	return sc.f.GetComponent(id, opts)
+}
+func (sc serviceController) ListComponents(moduleID string, opts component.InfoOptions) ([]*component.Info, error) {
+	return sc.f.ListComponents(moduleID, opts)
+}
@@ -60,6 +60,25 @@ type Host interface {
 	// exist.
 	ListComponents(moduleID string, opts component.InfoOptions) ([]*component.Info, error)
 
+	// GetReloadReport returns the report of the most recent load of the config
+	// of the root module, or nil if no config was loaded yet.
+	GetReloadReport() *component.ReloadReport
+
+	// PauseComponent stops a builtin component until ResumeComponent is
+	// called, including across loads of the config. PauseComponent returns
+	// once the component stopped.
+	//
+	// PauseComponent returns [component.ErrComponentNotFound] if a component
+	// is not found.
+	PauseComponent(id component.ID) error
+
+	// ResumeComponent builds and runs a component paused by PauseComponent
+	// again, with its latest arguments.
+	//
+	// ResumeComponent returns [component.ErrComponentNotFound] if a component
+	// is not found.
+	ResumeComponent(id component.ID) error
+
 	// GetService gets a running service using its name.
 	GetService(name string) (Service, bool)
 
@@ -74,6 +93,8 @@ type Host interface {
 
 // Controller is implemented by alloy.Alloy.
 type Controller interface {
+	component.Provider
+
 	Run(ctx context.Context)
 	LoadSource(source []byte, args map[string]any) error
 	Ready() bool
@@ -0,0 +1,192 @@
+package shadow
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/service"
+)
+
+// maxDiffTargets is the maximum number of added and removed targets returned
+// per export, to keep the diff readable for large changes.
+const maxDiffTargets = 100
+
+var targetsType = reflect.TypeOf([]discovery.Target(nil))
+
+// Diff describes how the targets and relabeling rules of the components of the
+// candidate config differ from the ones of the live components with the same
+// IDs.
+type Diff struct {
+	LoadedAt   time.Time       `json:"loadedAt"`
+	Components []ComponentDiff `json:"components"`
+	Ignored    []string        `json:"ignored"`
+}
+
+// ComponentDiff describes how the targets of a component of the candidate
+// config differ from the ones of the live component with the same ID.
+type ComponentDiff struct {
+	ID string `json:"id"`
+
+	// LiveFound is false when there's no live component with the same ID, in
+	// which case all the targets of the shadow component are added.
+	LiveFound bool `json:"liveFound"`
+
+	// Health of the shadow component.
+	Health  string `json:"health"`
+	Message string `json:"message,omitempty"`
+
+	// Identical is true when all the exports hold the same targets, and the
+	// rules of a relabeling component relabel the sampled inputs the same way.
+	Identical bool         `json:"identical"`
+	Exports   []ExportDiff `json:"exports"`
+
+	// Relabel is set for the relabeling components which have a live
+	// counterpart.
+	Relabel *RelabelDiff `json:"relabel,omitempty"`
+}
+
+// ExportDiff describes how the targets of an export differ.
+type ExportDiff struct {
+	Name        string `json:"name"`
+	LiveCount   int    `json:"liveCount"`
+	ShadowCount int    `json:"shadowCount"`
+
+	// Added holds the targets only exported by the shadow component, and
+	// Removed the ones only exported by the live component.
+	Added   []discovery.Target `json:"added"`
+	Removed []discovery.Target `json:"removed"`
+
+	// Truncated is true when Added or Removed hold only the first
+	// maxDiffTargets targets.
+	Truncated bool `json:"truncated"`
+}
+
+// computeDiff compares the targets of the components of the shadow controller
+// with the ones of the live components of host.
+func computeDiff(host service.Host, shadow component.Provider) (Diff, error) {
+	opts := component.InfoOptions{GetHealth: true, GetExports: true}
+
+	infos, err := shadow.ListComponents("", opts)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	d := Diff{Components: make([]ComponentDiff, 0, len(infos))}
+	for _, info := range infos {
+		cd := ComponentDiff{
+			ID:      info.ID.LocalID,
+			Health:  info.Health.Health.String(),
+			Message: info.Health.Message,
+		}
+
+		var liveExports component.Exports
+		live, err := host.GetComponent(component.ID{LocalID: info.ID.LocalID}, opts)
+		switch {
+		case err == nil:
+			cd.LiveFound = live.ComponentName == info.ComponentName
+			if cd.LiveFound {
+				liveExports = live.Exports
+				cd.Relabel = diffRelabel(live, info)
+			}
+		case !errors.Is(err, component.ErrComponentNotFound):
+			return Diff{}, err
+		}
+
+		cd.Exports = diffExports(liveExports, info.Exports)
+		cd.Identical = cd.LiveFound
+		for _, e := range cd.Exports {
+			if len(e.Added) > 0 || len(e.Removed) > 0 {
+				cd.Identical = false
+			}
+		}
+		if cd.Relabel != nil && cd.Relabel.Changed > 0 {
+			cd.Identical = false
+		}
+		d.Components = append(d.Components, cd)
+	}
+
+	sort.Slice(d.Components, func(i, j int) bool {
+		return d.Components[i].ID < d.Components[j].ID
+	})
+	return d, nil
+}
+
+// diffExports compares the exports holding targets of live and shadow, which
+// must be nil or of the same type.
+func diffExports(live, shadow component.Exports) []ExportDiff {
+	res := []ExportDiff{}
+
+	shadowValue := reflect.Indirect(reflect.ValueOf(shadow))
+	if shadowValue.Kind() != reflect.Struct {
+		return res
+	}
+	liveValue := reflect.Indirect(reflect.ValueOf(live))
+
+	for i := 0; i < shadowValue.NumField(); i++ {
+		field := shadowValue.Type().Field(i)
+		if field.Type != targetsType {
+			continue
+		}
+
+		var liveTargets []discovery.Target
+		if liveValue.IsValid() && liveValue.Type() == shadowValue.Type() {
+			liveTargets = liveValue.Field(i).Interface().([]discovery.Target)
+		}
+		shadowTargets := shadowValue.Field(i).Interface().([]discovery.Target)
+
+		res = append(res, diffTargets(exportName(field), liveTargets, shadowTargets))
+	}
+	return res
+}
+
+// exportName returns the name of the export of field from its alloy tag.
+func exportName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("alloy"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func diffTargets(name string, live, shadow []discovery.Target) ExportDiff {
+	d := ExportDiff{
+		Name:        name,
+		LiveCount:   len(live),
+		ShadowCount: len(shadow),
+		Added:       []discovery.Target{},
+		Removed:     []discovery.Target{},
+	}
+
+	liveKeys := make(map[string]struct{}, len(live))
+	for _, t := range live {
+		liveKeys[t.Labels().String()] = struct{}{}
+	}
+	shadowKeys := make(map[string]struct{}, len(shadow))
+	for _, t := range shadow {
+		shadowKeys[t.Labels().String()] = struct{}{}
+	}
+
+	for _, t := range shadow {
+		if _, ok := liveKeys[t.Labels().String()]; !ok {
+			d.Added, d.Truncated = appendTarget(d.Added, t, d.Truncated)
+		}
+	}
+	for _, t := range live {
+		if _, ok := shadowKeys[t.Labels().String()]; !ok {
+			d.Removed, d.Truncated = appendTarget(d.Removed, t, d.Truncated)
+		}
+	}
+	return d
+}
+
+func appendTarget(targets []discovery.Target, t discovery.Target, truncated bool) ([]discovery.Target, bool) {
+	if len(targets) >= maxDiffTargets {
+		return targets, true
+	}
+	return append(targets, t), truncated
+}
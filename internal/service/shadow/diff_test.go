@@ -0,0 +1,68 @@
+package shadow
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/stretchr/testify/require"
+)
+
+type relabelExports struct {
+	Output []discovery.Target `alloy:"output,attr"`
+	Rules  []string           `alloy:"rules,attr"`
+}
+
+func TestDiffExports(t *testing.T) {
+	live := relabelExports{
+		Output: []discovery.Target{
+			{"__address__": "10.0.0.1:8080", "namespace": "default"},
+			{"__address__": "10.0.0.2:8080", "namespace": "default"},
+		},
+	}
+	shadow := relabelExports{
+		Output: []discovery.Target{
+			{"__address__": "10.0.0.1:8080", "namespace": "default"},
+			{"__address__": "10.0.0.2:8080", "namespace": "kube-system"},
+			{"__address__": "10.0.0.3:8080", "namespace": "default"},
+		},
+	}
+
+	diffs := diffExports(live, shadow)
+	require.Len(t, diffs, 1)
+
+	d := diffs[0]
+	require.Equal(t, "output", d.Name)
+	require.Equal(t, 2, d.LiveCount)
+	require.Equal(t, 3, d.ShadowCount)
+	require.Equal(t, []discovery.Target{
+		{"__address__": "10.0.0.2:8080", "namespace": "kube-system"},
+		{"__address__": "10.0.0.3:8080", "namespace": "default"},
+	}, d.Added)
+	require.Equal(t, []discovery.Target{
+		{"__address__": "10.0.0.2:8080", "namespace": "default"},
+	}, d.Removed)
+	require.False(t, d.Truncated)
+}
+
+func TestDiffExports_NoLive(t *testing.T) {
+	shadow := discovery.Exports{
+		Targets: []discovery.Target{{"__address__": "10.0.0.1:8080"}},
+	}
+
+	diffs := diffExports(nil, shadow)
+	require.Len(t, diffs, 1)
+	require.Equal(t, "targets", diffs[0].Name)
+	require.Equal(t, shadow.Targets, diffs[0].Added)
+	require.Empty(t, diffs[0].Removed)
+}
+
+func TestDiffTargets_Truncated(t *testing.T) {
+	var shadow []discovery.Target
+	for i := 0; i < maxDiffTargets+10; i++ {
+		shadow = append(shadow, discovery.Target{"__address__": string(rune('a'+i%26)) + string(rune('a'+i/26))})
+	}
+
+	d := diffTargets("targets", nil, shadow)
+	require.Len(t, d.Added, maxDiffTargets)
+	require.True(t, d.Truncated)
+}
@@ -0,0 +1,78 @@
+package shadow
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/printer"
+)
+
+// relabelComponents are the relabeling components which run in shadow. Their
+// forward_to argument is emptied, so that they never send data anywhere.
+var relabelComponents = map[string]struct{}{
+	"prometheus.relabel": {},
+	"loki.relabel":       {},
+}
+
+// allowedInShadow returns whether the component name can run in shadow. Only
+// the discovery and relabeling components are allowed.
+func allowedInShadow(name string) bool {
+	_, relabel := relabelComponents[name]
+	return strings.HasPrefix(name, "discovery.") || relabel
+}
+
+// dropForwardTo replaces the forward_to argument of block with an empty list.
+func dropForwardTo(block *ast.BlockStmt) {
+	for _, stmt := range block.Body {
+		attr, ok := stmt.(*ast.AttributeStmt)
+		if !ok || attr.Name.Name != "forward_to" {
+			continue
+		}
+		attr.Value = &ast.ArrayExpr{
+			LBrackPos: ast.StartPos(attr.Value),
+			RBrackPos: ast.StartPos(attr.Value),
+		}
+	}
+}
+
+// filterConfig returns the blocks of the candidate config src which can run in
+// shadow.
+func filterConfig(src []byte) ([]byte, LoadResult, error) {
+	res := LoadResult{Loaded: []string{}, Ignored: []string{}}
+
+	f, err := parser.ParseFile("shadow.alloy", src)
+	if err != nil {
+		return nil, res, err
+	}
+
+	kept := make([]ast.Stmt, 0, len(f.Body))
+	for _, stmt := range f.Body {
+		block, ok := stmt.(*ast.BlockStmt)
+		if !ok {
+			return nil, res, fmt.Errorf("unexpected attribute at the top level of the candidate config")
+		}
+
+		id := strings.Join(block.Name, ".")
+		if block.Label != "" {
+			id += "." + block.Label
+		}
+		if !allowedInShadow(block.GetBlockName()) {
+			res.Ignored = append(res.Ignored, id)
+			continue
+		}
+		if _, ok := relabelComponents[block.GetBlockName()]; ok {
+			dropForwardTo(block)
+		}
+		res.Loaded = append(res.Loaded, id)
+		kept = append(kept, block)
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, &ast.File{Name: f.Name, Body: kept}); err != nil {
+		return nil, res, err
+	}
+	return buf.Bytes(), res, nil
+}
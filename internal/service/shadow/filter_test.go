@@ -0,0 +1,88 @@
+package shadow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterConfig(t *testing.T) {
+	src := `
+		discovery.kubernetes "pods" {
+			role = "pod"
+		}
+
+		discovery.relabel "pods" {
+			targets = discovery.kubernetes.pods.targets
+
+			rule {
+				source_labels = ["__meta_kubernetes_namespace"]
+				target_label  = "namespace"
+			}
+		}
+
+		prometheus.scrape "pods" {
+			targets    = discovery.relabel.pods.output
+			forward_to = [prometheus.relabel.pods.receiver]
+		}
+
+		prometheus.relabel "pods" {
+			forward_to = [prometheus.remote_write.default.receiver]
+
+			rule {
+				action = "labeldrop"
+				regex  = "tmp_.*"
+			}
+		}
+
+		local.file "token" {
+			filename = "/etc/alloy/token"
+		}
+
+		prometheus.remote_write "default" {
+			endpoint {
+				url = "http://localhost:9009/api/v1/push"
+			}
+		}
+
+		logging {
+			level = "debug"
+		}
+	`
+
+	filtered, res, err := filterConfig([]byte(src))
+	require.NoError(t, err)
+	require.Equal(t, []string{"discovery.kubernetes.pods", "discovery.relabel.pods", "prometheus.relabel.pods"}, res.Loaded)
+	require.Equal(t, []string{"prometheus.scrape.pods", "local.file.token", "prometheus.remote_write.default", "logging"}, res.Ignored)
+
+	expect := `discovery.kubernetes "pods" {
+	role = "pod"
+}
+
+discovery.relabel "pods" {
+	targets = discovery.kubernetes.pods.targets
+
+	rule {
+		source_labels = ["__meta_kubernetes_namespace"]
+		target_label  = "namespace"
+	}
+}
+
+prometheus.relabel "pods" {
+	forward_to = []
+
+	rule {
+		action = "labeldrop"
+		regex  = "tmp_.*"
+	}
+}`
+	require.Equal(t, expect, string(filtered))
+}
+
+func TestFilterConfig_Invalid(t *testing.T) {
+	_, _, err := filterConfig([]byte(`discovery.kubernetes "pods" {`))
+	require.Error(t, err)
+
+	_, _, err = filterConfig([]byte(`foo = "bar"`))
+	require.EqualError(t, err, "unexpected attribute at the top level of the candidate config")
+}
@@ -0,0 +1,102 @@
+package shadow
+
+import (
+	"reflect"
+
+	"github.com/grafana/alloy/internal/component"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+)
+
+var rulesType = reflect.TypeOf(alloy_relabel.Rules(nil))
+
+// RelabelDiff describes how the rules of a relabeling component of the
+// candidate config relabel the inputs sampled by the live component with the
+// same ID, compared with the rules of the live component.
+type RelabelDiff struct {
+	// Sampled is the number of sampled inputs, and Changed the number of
+	// inputs relabeled differently.
+	Sampled int `json:"sampled"`
+	Changed int `json:"changed"`
+
+	Series []SeriesDiff `json:"series"`
+
+	// Truncated is true when Series holds only the first maxDiffTargets
+	// changed inputs.
+	Truncated bool `json:"truncated"`
+}
+
+// SeriesDiff describes how an input is relabeled by the live and the shadow
+// rules. Live and Shadow are empty when the input is dropped.
+type SeriesDiff struct {
+	Input  string `json:"input"`
+	Live   string `json:"live,omitempty"`
+	Shadow string `json:"shadow,omitempty"`
+
+	LiveDropped   bool `json:"liveDropped"`
+	ShadowDropped bool `json:"shadowDropped"`
+}
+
+// diffRelabel evaluates the rules of the live and shadow relabeling
+// components against the inputs sampled by the live component. It returns nil
+// when live doesn't sample its inputs or when either component doesn't export
+// its rules.
+func diffRelabel(live, shadow *component.Info) *RelabelDiff {
+	sampler, ok := live.Component.(alloy_relabel.InputSampler)
+	if !ok {
+		return nil
+	}
+	liveRules, ok := exportedRules(live.Exports)
+	if !ok {
+		return nil
+	}
+	shadowRules, ok := exportedRules(shadow.Exports)
+	if !ok {
+		return nil
+	}
+
+	var (
+		liveConfigs   = alloy_relabel.ComponentToPromRelabelConfigs(liveRules)
+		shadowConfigs = alloy_relabel.ComponentToPromRelabelConfigs(shadowRules)
+	)
+
+	inputs := sampler.SampledInputs()
+	d := &RelabelDiff{Sampled: len(inputs), Series: []SeriesDiff{}}
+	for _, input := range inputs {
+		liveOut, liveKeep := relabel.Process(input.Copy(), liveConfigs...)
+		shadowOut, shadowKeep := relabel.Process(input.Copy(), shadowConfigs...)
+		if liveKeep == shadowKeep && labels.Equal(liveOut, shadowOut) {
+			continue
+		}
+
+		d.Changed++
+		if len(d.Series) >= maxDiffTargets {
+			d.Truncated = true
+			continue
+		}
+		sd := SeriesDiff{Input: input.String(), LiveDropped: !liveKeep, ShadowDropped: !shadowKeep}
+		if liveKeep {
+			sd.Live = liveOut.String()
+		}
+		if shadowKeep {
+			sd.Shadow = shadowOut.String()
+		}
+		d.Series = append(d.Series, sd)
+	}
+	return d
+}
+
+// exportedRules returns the relabeling rules held by exports.
+func exportedRules(exports component.Exports) (alloy_relabel.Rules, bool) {
+	v := reflect.Indirect(reflect.ValueOf(exports))
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Type == rulesType {
+			return v.Field(i).Interface().(alloy_relabel.Rules), true
+		}
+	}
+	return nil, false
+}
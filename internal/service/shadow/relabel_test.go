@@ -0,0 +1,70 @@
+package shadow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+type relabelComponent struct {
+	inputs []labels.Labels
+}
+
+func (relabelComponent) Run(context.Context) error        { return nil }
+func (relabelComponent) Update(component.Arguments) error { return nil }
+
+func (c relabelComponent) SampledInputs() []labels.Labels { return c.inputs }
+
+type rulesExports struct {
+	Rules alloy_relabel.Rules `alloy:"rules,attr"`
+}
+
+func TestDiffRelabel(t *testing.T) {
+	live := &component.Info{
+		Component: relabelComponent{inputs: []labels.Labels{
+			labels.FromStrings("__name__", "up", "job", "api"),
+			labels.FromStrings("__name__", "up", "job", "db", "tmp_id", "1"),
+			labels.FromStrings("__name__", "debug_info", "job", "api"),
+		}},
+		Exports: rulesExports{Rules: alloy_relabel.Rules{
+			relabelRule(t, alloy_relabel.LabelDrop, nil, "tmp_.*"),
+		}},
+	}
+	shadow := &component.Info{
+		Exports: rulesExports{Rules: alloy_relabel.Rules{
+			relabelRule(t, alloy_relabel.LabelDrop, nil, "tmp_.*"),
+			relabelRule(t, alloy_relabel.Drop, []string{"__name__"}, "debug_.*"),
+		}},
+	}
+
+	d := diffRelabel(live, shadow)
+	require.NotNil(t, d)
+	require.Equal(t, 3, d.Sampled)
+	require.Equal(t, 1, d.Changed)
+	require.Equal(t, []SeriesDiff{{
+		Input:         `{__name__="debug_info", job="api"}`,
+		Live:          `{__name__="debug_info", job="api"}`,
+		ShadowDropped: true,
+	}}, d.Series)
+	require.False(t, d.Truncated)
+}
+
+func TestDiffRelabel_NotSampled(t *testing.T) {
+	info := &component.Info{Exports: rulesExports{}}
+	require.Nil(t, diffRelabel(info, info))
+
+	live := &component.Info{Component: relabelComponent{}, Exports: relabelExports{}}
+	require.Nil(t, diffRelabel(live, &component.Info{Exports: rulesExports{}}))
+}
+
+func relabelRule(t *testing.T, action alloy_relabel.Action, sourceLabels []string, regex string) *alloy_relabel.Config {
+	rc := alloy_relabel.DefaultRelabelConfig
+	rc.Action = action
+	rc.SourceLabels = sourceLabels
+	require.NoError(t, rc.Regex.UnmarshalText([]byte(regex)))
+	return &rc
+}
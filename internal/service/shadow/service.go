@@ -0,0 +1,259 @@
+// Package shadow implements the shadow service, which runs the discovery and
+// relabeling components of a candidate config alongside the live config, and
+// reports how their outputs differ from the ones of the live components.
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service"
+	http_service "github.com/grafana/alloy/internal/service/http"
+	"github.com/grafana/alloy/internal/web/adminauth"
+)
+
+// ServiceName defines the name used for the shadow service.
+const ServiceName = "shadow"
+
+// maxConfigSize is the maximum size of a candidate config.
+const maxConfigSize = 10 << 20
+
+// Arguments holds the configuration of the shadow block.
+type Arguments struct {
+	Enabled bool `alloy:"enabled,attr,optional"`
+}
+
+// Options are used to configure the shadow service.
+type Options struct {
+	Logger log.Logger
+
+	// AdminToken is the bearer token required to use the shadow endpoints.
+	// The endpoints are disabled when it's empty.
+	AdminToken string
+}
+
+// Service implements the shadow service.
+type Service struct {
+	opts Options
+
+	mut      sync.RWMutex
+	enabled  bool
+	ctrl     service.Controller
+	loadedAt time.Time
+	ignored  []string
+}
+
+var (
+	_ service.Service             = (*Service)(nil)
+	_ http_service.ServiceHandler = (*Service)(nil)
+)
+
+// New returns a new, unstarted instance of the shadow service.
+func New(opts Options) *Service {
+	if opts.Logger == nil {
+		opts.Logger = log.NewNopLogger()
+	}
+	return &Service{opts: opts}
+}
+
+// Definition implements service.Service.
+func (*Service) Definition() service.Definition {
+	return service.Definition{
+		Name:       ServiceName,
+		ConfigType: Arguments{},
+		DependsOn:  []string{http_service.ServiceName},
+		Stability:  featuregate.StabilityExperimental,
+	}
+}
+
+// Data implements service.Service. It returns nil, as the shadow service
+// doesn't expose any data to components.
+func (*Service) Data() any {
+	return nil
+}
+
+// Run implements service.Service. It runs the controller of the candidate
+// config until ctx is canceled.
+func (s *Service) Run(ctx context.Context, host service.Host) error {
+	ctrl := host.NewController(ServiceName)
+
+	s.mut.Lock()
+	s.ctrl = ctrl
+	s.mut.Unlock()
+
+	ctrl.Run(ctx)
+	return nil
+}
+
+// Update implements service.Service. Disabling the service unloads the
+// candidate config.
+func (s *Service) Update(newConfig any) error {
+	newArgs := newConfig.(Arguments)
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.enabled = newArgs.Enabled
+	if !s.enabled && s.ctrl != nil && !s.loadedAt.IsZero() {
+		if err := s.unload(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServiceHandler implements http_service.ServiceHandler. It returns the HTTP
+// endpoints to load and unload a candidate config, and to get the diff. The
+// endpoints require the admin token.
+func (s *Service) ServiceHandler(host service.Host) (base string, handler http.Handler) {
+	r := mux.NewRouter()
+	r.Handle("/api/v0/shadow/config", s.authorized(s.loadHandler())).Methods(http.MethodPost)
+	r.Handle("/api/v0/shadow/config", s.authorized(s.unloadHandler())).Methods(http.MethodDelete)
+	r.Handle("/api/v0/shadow/diff", s.authorized(s.diffHandler(host))).Methods(http.MethodGet)
+	return "/api/v0/shadow/", r
+}
+
+// authorized wraps next so that it's only called for requests authenticated
+// with the admin token while shadow evaluation is enabled.
+func (s *Service) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return adminauth.Require(s.opts.AdminToken, "the shadow API", s.withEnabled(next))
+}
+
+func (s *Service) withEnabled(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mut.RLock()
+		enabled, ready := s.enabled, s.ctrl != nil
+		s.mut.RUnlock()
+
+		switch {
+		case !enabled:
+			http.Error(w, "shadow evaluation is disabled", http.StatusNotFound)
+		case !ready:
+			http.Error(w, "shadow evaluation isn't running yet", http.StatusServiceUnavailable)
+		default:
+			next(w, r)
+		}
+	}
+}
+
+func (s *Service) loadHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bb, err := io.ReadAll(io.LimitReader(r.Body, maxConfigSize+1))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(bb) > maxConfigSize {
+			http.Error(w, "the candidate config is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		loaded, err := s.load(bb)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, loaded)
+	}
+}
+
+func (s *Service) unloadHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.mut.Lock()
+		err := s.unload()
+		s.mut.Unlock()
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Service) diffHandler(host service.Host) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.mut.RLock()
+		defer s.mut.RUnlock()
+
+		if s.loadedAt.IsZero() {
+			http.Error(w, "no candidate config is loaded", http.StatusNotFound)
+			return
+		}
+
+		d, err := computeDiff(host, s.ctrl)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		d.LoadedAt = s.loadedAt
+		d.Ignored = s.ignored
+		writeJSON(w, d)
+	}
+}
+
+// LoadResult is the response to the load of a candidate config.
+type LoadResult struct {
+	// Loaded holds the IDs of the components which run in shadow.
+	Loaded []string `json:"loaded"`
+
+	// Ignored holds the IDs of the blocks which don't run in shadow.
+	Ignored []string `json:"ignored"`
+}
+
+// load loads the discovery and relabeling components of the candidate config
+// src into the shadow controller, replacing the previous candidate config.
+func (s *Service) load(src []byte) (LoadResult, error) {
+	filtered, res, err := filterConfig(src)
+	if err != nil {
+		return res, err
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if err := s.ctrl.LoadSource(filtered, nil); err != nil {
+		// Don't leave a partially evaluated candidate config running.
+		if unloadErr := s.unload(); unloadErr != nil {
+			level.Error(s.opts.Logger).Log("msg", "failed to unload invalid candidate config", "err", unloadErr)
+		}
+		return res, fmt.Errorf("loading the candidate config: %w", err)
+	}
+	s.loadedAt = time.Now()
+	s.ignored = res.Ignored
+
+	level.Info(s.opts.Logger).Log("msg", "loaded candidate config in shadow", "components", len(res.Loaded), "ignored", len(res.Ignored))
+	return res, nil
+}
+
+// unload stops all the components of the candidate config. mut must be held
+// when calling unload.
+func (s *Service) unload() error {
+	if err := s.ctrl.LoadSource(nil, nil); err != nil {
+		return fmt.Errorf("unloading the candidate config: %w", err)
+	}
+	s.loadedAt = time.Time{}
+	s.ignored = nil
+
+	level.Info(s.opts.Logger).Log("msg", "unloaded candidate config")
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	bb, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bb)
+}
@@ -24,6 +24,7 @@ const ServiceName = "ui"
 type Options struct {
 	UIPrefix        string                        // Path prefix to host the UI at.
 	CallbackManager livedebugging.CallbackManager // CallbackManager is used for live debugging in the UI.
+	AdminToken      string                        // Bearer token required to pause and resume components. Disabled when empty.
 }
 
 // Service implements the UI service.
@@ -77,7 +78,7 @@ func (s *Service) Data() any {
 func (s *Service) ServiceHandler(host service.Host) (base string, handler http.Handler) {
 	r := mux.NewRouter()
 
-	fa := api.NewAlloyAPI(host, s.opts.CallbackManager)
+	fa := api.NewAlloyAPI(host, s.opts.CallbackManager, s.opts.AdminToken)
 	fa.RegisterRoutes(path.Join(s.opts.UIPrefix, "/api/v0/web"), r)
 	ui.RegisterRoutes(s.opts.UIPrefix, r)
 
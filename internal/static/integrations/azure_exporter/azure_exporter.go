@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"go.uber.org/zap"
 
@@ -30,6 +31,14 @@ func (e Exporter) MetricsHandler() (http.Handler, error) {
 		return nil, fmt.Errorf("failed to create azure client, %v", err)
 	}
 
+	// The batch client is created on first use, as batch mode can be enabled with a query parameter, and reused so its
+	//  credential caches the tokens across scrapes
+	var (
+		batchOnce      sync.Once
+		batch          *batchClient
+		batchClientErr error
+	)
+
 	h := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		reg := prometheus.NewRegistry()
 		ctx := context.Background()
@@ -50,6 +59,27 @@ func (e Exporter) MetricsHandler() (http.Handler, error) {
 			return
 		}
 
+		if mergedConfig.BatchMode {
+			batchOnce.Do(func() {
+				batch, batchClientErr = newBatchClient(client, e.cfg.AzureCloudEnvironment, e.logger)
+			})
+			if batchClientErr != nil {
+				e.logger.Error(batchClientErr)
+				http.Error(resp, batchClientErr.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			collector, err := batch.gather(ctx, mergedConfig)
+			if err != nil {
+				e.logger.Error(fmt.Errorf("batch metrics gathering failed, %v", err))
+				http.Error(resp, "Failed to gather azure metrics in batch mode", http.StatusInternalServerError)
+				return
+			}
+			reg.MustRegister(collector)
+			promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(resp, req)
+			return
+		}
+
 		tagManager, err := client.TagManager.ParseTagConfig(mergedConfig.IncludedResourceTags)
 		if err != nil {
 			err = fmt.Errorf("unable to create azure tag manager from included_resource_tags %s, %v", strings.Join(mergedConfig.IncludedResourceTags, ","), err)
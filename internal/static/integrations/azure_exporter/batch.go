@@ -0,0 +1,562 @@
+package azure_exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/azuresdk/armclient"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxBatchSize is the maximum number of resources of a metrics:getBatch request.
+	maxBatchSize = 50
+	// batchConcurrency limits the number of concurrent metrics:getBatch requests - matches the concurrency of the
+	//  per resource requests
+	batchConcurrency = 10
+
+	resourceGraphAPIVersion = "2021-03-01"
+	metricsBatchAPIVersion  = "2024-02-01"
+)
+
+// batchCloud holds the endpoints used in batch mode for an azure cloud environment.
+type batchCloud struct {
+	cloud cloud.Configuration
+	// resourceManagerEndpoint is used for Azure Resource Graph queries.
+	resourceManagerEndpoint string
+	// metricsEndpoint is the regional endpoint of the metrics:getBatch API, %s is replaced with the region.
+	metricsEndpoint string
+	metricsAudience string
+}
+
+// batchClouds are the azure cloud environments supporting batch mode keyed by their azure_cloud_environment name.
+var batchClouds = map[string]batchCloud{
+	"azurecloud": {
+		cloud:                   cloud.AzurePublic,
+		resourceManagerEndpoint: "https://management.azure.com",
+		metricsEndpoint:         "https://%s.metrics.monitor.azure.com",
+		metricsAudience:         "https://metrics.monitor.azure.com",
+	},
+	"azurechinacloud": {
+		cloud:                   cloud.AzureChina,
+		resourceManagerEndpoint: "https://management.chinacloudapi.cn",
+		metricsEndpoint:         "https://%s.metrics.monitor.azure.cn",
+		metricsAudience:         "https://metrics.monitor.azure.cn",
+	},
+	"azuregovernmentcloud": {
+		cloud:                   cloud.AzureGovernment,
+		resourceManagerEndpoint: "https://management.usgovcloudapi.net",
+		metricsEndpoint:         "https://%s.metrics.monitor.azure.us",
+		metricsAudience:         "https://metrics.monitor.azure.us",
+	},
+}
+
+// batchClient gathers metrics with the metrics:getBatch API of Azure Monitor. It is created once per exporter so the
+// credential can cache its tokens across scrapes.
+type batchClient struct {
+	logger     *zap.SugaredLogger
+	pipeline   runtime.Pipeline
+	credential azcore.TokenCredential
+	cloud      batchCloud
+	now        func() time.Time
+}
+
+// newBatchClient creates a batch client sending its requests with the client options of armClient, the client of the
+// per resource requests, so both modes share the transport, retries and user agent of the Azure SDK.
+func newBatchClient(armClient *armclient.ArmClient, cloudEnvironment string, logger *zap.SugaredLogger) (*batchClient, error) {
+	bc, ok := batchClouds[strings.ToLower(cloudEnvironment)]
+	if !ok {
+		return nil, fmt.Errorf("batch_mode is not supported for azure cloud environment %s", cloudEnvironment)
+	}
+	clientOptions := armClient.NewArmClientOptions().ClientOptions
+	clientOptions.Cloud = bc.cloud
+	credential, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		ClientOptions: clientOptions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure credential, %v", err)
+	}
+	return &batchClient{
+		logger:     logger,
+		pipeline:   runtime.NewPipeline("azure_exporter", "", runtime.PipelineOptions{}, &clientOptions),
+		credential: credential,
+		cloud:      bc,
+		now:        time.Now,
+	}, nil
+}
+
+// batchResource is a resource found by an Azure Resource Graph query.
+type batchResource struct {
+	ID       string            `json:"id"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags"`
+}
+
+// discover finds the resources of cfg.ResourceType matching cfg.ResourceGraphQueryFilter with Azure Resource Graph.
+func (c *batchClient) discover(ctx context.Context, cfg Config) ([]batchResource, error) {
+	query := fmt.Sprintf(`Resources | where type =~ "%s" %s | project id, location, tags`, cfg.ResourceType, cfg.ResourceGraphQueryFilter)
+	endpoint := c.cloud.resourceManagerEndpoint + "/providers/Microsoft.ResourceGraph/resources?api-version=" + resourceGraphAPIVersion
+
+	var (
+		resources []batchResource
+		skipToken string
+	)
+	for {
+		options := map[string]any{"resultFormat": "objectArray"}
+		if skipToken != "" {
+			options["$skipToken"] = skipToken
+		}
+		body, err := json.Marshal(map[string]any{
+			"subscriptions": cfg.Subscriptions,
+			"query":         query,
+			"options":       options,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Data      []batchResource `json:"data"`
+			SkipToken string          `json:"$skipToken"`
+		}
+		if err := c.post(ctx, endpoint, c.cloud.resourceManagerEndpoint, body, &page); err != nil {
+			return nil, fmt.Errorf("resource graph query failed, %v", err)
+		}
+		resources = append(resources, page.Data...)
+
+		if page.SkipToken == "" {
+			return resources, nil
+		}
+		skipToken = page.SkipToken
+	}
+}
+
+// gather gets the metrics of cfg for all the discovered resources. The resources are split into batches of at most
+// cfg.BatchSize resources sharing a subscription and region as required by the metrics:getBatch API. Failed batches
+// are logged and don't prevent the metrics of the other batches from being returned.
+func (c *batchClient) gather(ctx context.Context, cfg Config) (*batchCollector, error) {
+	resources, err := c.discover(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	timespan, err := parseISO8601Duration(cfg.Timespan)
+	if err != nil {
+		return nil, err
+	}
+	end := c.now().UTC()
+	start := end.Add(-timespan)
+
+	byScope := map[[2]string][]batchResource{}
+	var scopes [][2]string
+	for _, res := range resources {
+		parsed := parseResourceID(res.ID)
+		scope := [2]string{parsed.subscriptionID, strings.ToLower(strings.ReplaceAll(res.Location, " ", ""))}
+		if _, ok := byScope[scope]; !ok {
+			scopes = append(scopes, scope)
+		}
+		byScope[scope] = append(byScope[scope], res)
+	}
+
+	var (
+		collector = newBatchCollector()
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, batchConcurrency)
+	)
+	for _, scope := range scopes {
+		scopeResources := byScope[scope]
+		for len(scopeResources) > 0 {
+			n := min(cfg.BatchSize, len(scopeResources))
+			batch := scopeResources[:n]
+			scopeResources = scopeResources[n:]
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(subscription, region string, batch []batchResource) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				if err := c.gatherBatch(ctx, cfg, subscription, region, batch, start, end, collector); err != nil {
+					c.logger.Errorw("metrics batch request failed", "subscription", subscription, "region", region, "resources", len(batch), "err", err)
+				}
+			}(scope[0], scope[1], batch)
+		}
+	}
+	wg.Wait()
+
+	return collector, nil
+}
+
+type batchResponse struct {
+	Values []struct {
+		ResourceID string `json:"resourceid"`
+		Value      []struct {
+			Name struct {
+				Value string `json:"value"`
+			} `json:"name"`
+			Unit         string `json:"unit"`
+			ErrorCode    string `json:"errorCode"`
+			ErrorMessage string `json:"errorMessage"`
+			Timeseries   []struct {
+				MetadataValues []struct {
+					Name struct {
+						Value string `json:"value"`
+					} `json:"name"`
+					Value string `json:"value"`
+				} `json:"metadatavalues"`
+				Data []batchDataPoint `json:"data"`
+			} `json:"timeseries"`
+		} `json:"value"`
+	} `json:"values"`
+}
+
+type batchDataPoint struct {
+	Average *float64 `json:"average"`
+	Minimum *float64 `json:"minimum"`
+	Maximum *float64 `json:"maximum"`
+	Total   *float64 `json:"total"`
+	Count   *float64 `json:"count"`
+}
+
+func (d batchDataPoint) value(aggregation string) *float64 {
+	switch aggregation {
+	case "average":
+		return d.Average
+	case "minimum":
+		return d.Minimum
+	case "maximum":
+		return d.Maximum
+	case "total":
+		return d.Total
+	case "count":
+		return d.Count
+	}
+	return nil
+}
+
+func (c *batchClient) gatherBatch(ctx context.Context, cfg Config, subscription, region string, resources []batchResource, start, end time.Time, collector *batchCollector) error {
+	aggregations := make([]string, 0, len(cfg.MetricAggregations))
+	for _, aggregation := range cfg.MetricAggregations {
+		aggregations = append(aggregations, strings.ToLower(aggregation))
+	}
+	// The getBatch API doesn't fall back to the primary aggregation of each metric
+	if len(aggregations) == 0 {
+		aggregations = []string{"average"}
+	}
+	namespace := cfg.MetricNamespace
+	if namespace == "" {
+		namespace = cfg.ResourceType
+	}
+
+	params := url.Values{}
+	params.Set("api-version", metricsBatchAPIVersion)
+	params.Set("metricnamespace", namespace)
+	params.Set("metricnames", strings.Join(cfg.Metrics, ","))
+	params.Set("starttime", start.Format(time.RFC3339))
+	params.Set("endtime", end.Format(time.RFC3339))
+	params.Set("interval", cfg.Timespan)
+	params.Set("aggregation", strings.Join(aggregations, ","))
+	if filter := dimensionFilter(cfg.IncludedDimensions); filter != "" {
+		params.Set("filter", filter)
+		// Same reasoning as the top used in ToScrapeSettings, the default of 10 would cut off dimension values
+		params.Set("top", strconv.Itoa(100_000_000))
+	}
+
+	ids := make([]string, 0, len(resources))
+	byID := make(map[string]batchResource, len(resources))
+	for _, res := range resources {
+		ids = append(ids, res.ID)
+		byID[strings.ToLower(res.ID)] = res
+	}
+	body, err := json.Marshal(map[string][]string{"resourceids": ids})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf(c.cloud.metricsEndpoint, region) + "/subscriptions/" + url.PathEscape(subscription) + "/metrics:getBatch?" + params.Encode()
+	var resp batchResponse
+	if err := c.post(ctx, endpoint, c.cloud.metricsAudience, body, &resp); err != nil {
+		return err
+	}
+
+	for _, resourceValues := range resp.Values {
+		res, ok := byID[strings.ToLower(resourceValues.ResourceID)]
+		if !ok {
+			continue
+		}
+		baseLabels := resourceLabels(res, cfg.IncludedResourceTags)
+
+		for _, metric := range resourceValues.Value {
+			if metric.ErrorCode != "" && !strings.EqualFold(metric.ErrorCode, "Success") {
+				c.logger.Warnw("metric returned an error", "resource", res.ID, "metric", metric.Name.Value, "code", metric.ErrorCode, "message", metric.ErrorMessage)
+				continue
+			}
+			for _, aggregation := range aggregations {
+				name := expandTemplate(cfg.MetricNameTemplate, namespace, metric.Name.Value, aggregation, metric.Unit, true)
+				help := expandTemplate(cfg.MetricHelpTemplate, namespace, metric.Name.Value, aggregation, metric.Unit, false)
+
+				for _, series := range metric.Timeseries {
+					v := lastValue(series.Data, aggregation)
+					if v == nil {
+						continue
+					}
+					labels := make(map[string]string, len(baseLabels)+len(series.MetadataValues))
+					for k, lv := range baseLabels {
+						labels[k] = lv
+					}
+					for _, md := range series.MetadataValues {
+						labels[dimensionLabel(md.Name.Value, len(cfg.IncludedDimensions))] = md.Value
+					}
+					collector.add(name, help, labels, *v)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// post sends body to endpoint authenticated with a token for audience and decodes the JSON response into out.
+func (c *batchClient) post(ctx context.Context, endpoint, audience string, body []byte, out any) error {
+	token, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{audience + "/.default"}})
+	if err != nil {
+		return fmt.Errorf("failed to get azure token, %v", err)
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
+	if err != nil {
+		return err
+	}
+	req.Raw().Header.Set("Authorization", "Bearer "+token.Token)
+	if err := req.SetBody(streaming.NopCloser(bytes.NewReader(body)), "application/json"); err != nil {
+		return err
+	}
+
+	resp, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d, %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// lastValue returns the most recent value of aggregation in data.
+func lastValue(data []batchDataPoint, aggregation string) *float64 {
+	for i := len(data) - 1; i >= 0; i-- {
+		if v := data[i].value(aggregation); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// dimensionFilter builds the metric filter which splits the metrics by dimensions, see ToScrapeSettings.
+func dimensionFilter(dimensions []string) string {
+	filters := make([]string, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		filters = append(filters, dimension+" eq '*'")
+	}
+	return strings.Join(filters, " and ")
+}
+
+// dimensionLabel returns the label name of a dimension matching the exporter, a single dimension is named dimension
+// and multiple dimensions are named dimension<dimension_name>.
+func dimensionLabel(name string, dimensions int) string {
+	if dimensions == 1 {
+		return "dimension"
+	}
+	return "dimension" + sanitizeName(name, false)
+}
+
+type parsedResourceID struct {
+	subscriptionID string
+	resourceGroup  string
+	resourceName   string
+}
+
+// parseResourceID extracts the fields of a resource ID of the form
+//
+//	/subscriptions/<subscription>/resourceGroups/<group>/providers/<namespace>/<type>/<name>
+func parseResourceID(id string) parsedResourceID {
+	var parsed parsedResourceID
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	for i := 0; i+1 < len(parts); i += 2 {
+		switch strings.ToLower(parts[i]) {
+		case "subscriptions":
+			parsed.subscriptionID = parts[i+1]
+		case "resourcegroups":
+			parsed.resourceGroup = parts[i+1]
+		}
+	}
+	if len(parts) > 0 {
+		parsed.resourceName = parts[len(parts)-1]
+	}
+	return parsed
+}
+
+// resourceLabels returns the labels identifying res including the included tags.
+func resourceLabels(res batchResource, includedTags []string) map[string]string {
+	parsed := parseResourceID(res.ID)
+	labels := map[string]string{
+		"resourceID":     strings.ToLower(res.ID),
+		"subscriptionID": strings.ToLower(parsed.subscriptionID),
+		"resourceGroup":  strings.ToLower(parsed.resourceGroup),
+		"resourceName":   strings.ToLower(parsed.resourceName),
+	}
+	for _, tag := range includedTags {
+		var value string
+		for k, v := range res.Tags {
+			if strings.EqualFold(k, tag) {
+				value = v
+				break
+			}
+		}
+		labels["tag_"+sanitizeName(tag, true)] = value
+	}
+	return labels
+}
+
+var (
+	invalidNameChars    = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+	repeatedUnderscores = regexp.MustCompile(`_+`)
+)
+
+// sanitizeName replaces the characters which aren't valid in metric and label names with underscores.
+func sanitizeName(s string, lower bool) string {
+	if lower {
+		s = strings.ToLower(s)
+	}
+	return strings.Trim(invalidNameChars.ReplaceAllString(s, "_"), "_")
+}
+
+// expandTemplate replaces the {type}, {metric}, {aggregation} and {unit} placeholders of a metric name or help
+// template.
+func expandTemplate(template, resourceType, metric, aggregation, unit string, isName bool) string {
+	replacements := []string{
+		"{type}", resourceType,
+		"{metric}", metric,
+		"{aggregation}", aggregation,
+		"{unit}", unit,
+	}
+	if isName {
+		for i := 1; i < len(replacements); i += 2 {
+			replacements[i] = sanitizeName(replacements[i], true)
+		}
+		return strings.Trim(repeatedUnderscores.ReplaceAllString(strings.NewReplacer(replacements...).Replace(template), "_"), "_")
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+var iso8601Duration = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISO8601Duration parses the ISO8601 durations used by Azure Monitor such as PT1M, PT1H or P1D.
+func parseISO8601Duration(s string) (time.Duration, error) {
+	matches := iso8601Duration.FindStringSubmatch(s)
+	if matches == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%q is not a valid ISO8601 duration", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q is not a positive ISO8601 duration", s)
+	}
+	return d, nil
+}
+
+// batchCollector exposes the metrics gathered in batch mode. The series of a metric which don't have all the
+// dimensions of the other series get empty values for the missing labels.
+type batchCollector struct {
+	mut     sync.Mutex
+	help    map[string]string
+	samples map[string][]batchSample
+}
+
+type batchSample struct {
+	labels map[string]string
+	value  float64
+}
+
+func newBatchCollector() *batchCollector {
+	return &batchCollector{
+		help:    map[string]string{},
+		samples: map[string][]batchSample{},
+	}
+}
+
+func (c *batchCollector) add(name, help string, labels map[string]string, value float64) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if _, ok := c.help[name]; !ok {
+		c.help[name] = help
+	}
+	c.samples[name] = append(c.samples[name], batchSample{labels: labels, value: value})
+}
+
+// Describe implements prometheus.Collector. The collector is unchecked as its metrics depend on the API responses.
+func (c *batchCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *batchCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	for name, samples := range c.samples {
+		labelSet := map[string]struct{}{}
+		for _, s := range samples {
+			for l := range s.labels {
+				labelSet[l] = struct{}{}
+			}
+		}
+		labelNames := make([]string, 0, len(labelSet))
+		for l := range labelSet {
+			labelNames = append(labelNames, l)
+		}
+		sort.Strings(labelNames)
+
+		desc := prometheus.NewDesc(name, c.help[name], labelNames, nil)
+		for _, s := range samples {
+			values := make([]string, len(labelNames))
+			for i, l := range labelNames {
+				values[i] = s.labels[l]
+			}
+			m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, s.value, values...)
+			if err != nil {
+				m = prometheus.NewInvalidMetric(desc, err)
+			}
+			ch <- m
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package azure_exporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type staticCredential struct{}
+
+func (staticCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestBatchClient_Gather(t *testing.T) {
+	const (
+		vm1 = "/subscriptions/subA/resourceGroups/groupA/providers/Microsoft.Compute/virtualMachines/vm1"
+		vm2 = "/subscriptions/subA/resourceGroups/groupA/providers/Microsoft.Compute/virtualMachines/vm2"
+	)
+
+	var (
+		mut          sync.Mutex
+		batchQueries []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/providers/Microsoft.ResourceGraph/resources", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query   string         `json:"query"`
+			Options map[string]any `json:"options"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, `Resources | where type =~ "Microsoft.Compute/virtualMachines" | where tags.env == "prod" | project id, location, tags`, req.Query)
+
+		// The resources are returned over two pages
+		if req.Options["$skipToken"] == nil {
+			_, _ = w.Write([]byte(`{"data": [{"id": "` + vm1 + `", "location": "westeurope", "tags": {"Owner": "team-a"}}], "$skipToken": "next"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "` + vm2 + `", "location": "westeurope", "tags": {}}]}`))
+	})
+	mux.HandleFunc("/westeurope/subscriptions/subA/metrics:getBatch", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req struct {
+			ResourceIDs []string `json:"resourceids"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.ResourceIDs, 1)
+
+		mut.Lock()
+		batchQueries = append(batchQueries, r.URL.RawQuery)
+		mut.Unlock()
+
+		value := 1.5
+		if req.ResourceIDs[0] == vm2 {
+			value = 2.5
+		}
+		resp := map[string]any{"values": []any{map[string]any{
+			"resourceid": req.ResourceIDs[0],
+			"value": []any{map[string]any{
+				"name":      map[string]any{"value": "Disk Read Bytes"},
+				"unit":      "Bytes",
+				"errorCode": "Success",
+				"timeseries": []any{map[string]any{
+					"metadatavalues": []any{map[string]any{"name": map[string]any{"value": "LUN"}, "value": "0"}},
+					"data": []any{
+						map[string]any{"timeStamp": "2024-01-01T00:00:00Z", "total": value},
+						map[string]any{"timeStamp": "2024-01-01T00:01:00Z"},
+					},
+				}},
+			}},
+		}}}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &batchClient{
+		logger:     zap.NewNop().Sugar(),
+		pipeline:   runtime.NewPipeline("azure_exporter", "", runtime.PipelineOptions{}, &policy.ClientOptions{Transport: srv.Client()}),
+		credential: staticCredential{},
+		cloud: batchCloud{
+			resourceManagerEndpoint: srv.URL,
+			metricsEndpoint:         srv.URL + "/%s",
+			metricsAudience:         srv.URL,
+		},
+		now: func() time.Time { return time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC) },
+	}
+
+	cfg := DefaultConfig
+	cfg.Subscriptions = []string{"subA"}
+	cfg.ResourceType = "Microsoft.Compute/virtualMachines"
+	cfg.ResourceGraphQueryFilter = `| where tags.env == "prod"`
+	cfg.Metrics = []string{"Disk Read Bytes"}
+	cfg.MetricAggregations = []string{"Total"}
+	cfg.IncludedDimensions = []string{"LUN"}
+	cfg.BatchMode = true
+	cfg.BatchSize = 1
+	require.NoError(t, cfg.Validate())
+
+	collector, err := client.gather(context.Background(), cfg)
+	require.NoError(t, err)
+
+	require.Len(t, batchQueries, 2)
+	for _, query := range batchQueries {
+		require.Contains(t, query, "aggregation=total")
+		require.Contains(t, query, "filter=LUN+eq+%27%2A%27")
+		require.Contains(t, query, "interval=PT1M")
+		require.Contains(t, query, "starttime=2024-01-01T00%3A04%3A00Z")
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collector)
+	expected := `
+# HELP azure_microsoft_compute_virtualmachines_disk_read_bytes_total_bytes Azure metric Disk Read Bytes for Microsoft.Compute/virtualMachines with aggregation total as Bytes
+# TYPE azure_microsoft_compute_virtualmachines_disk_read_bytes_total_bytes gauge
+azure_microsoft_compute_virtualmachines_disk_read_bytes_total_bytes{dimension="0",resourceGroup="groupa",resourceID="/subscriptions/suba/resourcegroups/groupa/providers/microsoft.compute/virtualmachines/vm1",resourceName="vm1",subscriptionID="suba",tag_owner="team-a"} 1.5
+azure_microsoft_compute_virtualmachines_disk_read_bytes_total_bytes{dimension="0",resourceGroup="groupa",resourceID="/subscriptions/suba/resourcegroups/groupa/providers/microsoft.compute/virtualmachines/vm2",resourceName="vm2",subscriptionID="suba",tag_owner=""} 2.5
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"PT1M":    time.Minute,
+		"PT5M":    5 * time.Minute,
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+	} {
+		actual, err := parseISO8601Duration(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, actual, input)
+	}
+
+	for _, input := range []string{"", "P", "PT", "PT0M", "1M", "PT1.5M"} {
+		_, err := parseISO8601Duration(input)
+		require.Error(t, err, input)
+	}
+}
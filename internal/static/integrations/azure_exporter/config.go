@@ -38,6 +38,7 @@ var DefaultConfig = Config{
 	//  to fully monitor a service which is tedious. Turning off validation eliminates this complexity. The underlying
 	//  sdk will only give back the dimensions which are valid for particular metrics.
 	ValidateDimensions: false,
+	BatchSize:          maxBatchSize,
 }
 
 type Config struct {
@@ -77,6 +78,12 @@ type Config struct {
 	ValidateDimensions bool   `yaml:"validate_dimensions"`
 
 	AzureCloudEnvironment string `yaml:"azure_cloud_environment"`
+
+	// BatchMode gathers the metrics of the resources found by the Azure Resource Graph query with the metrics:getBatch
+	//  API of Azure Monitor, which returns the metrics of up to BatchSize resources of a subscription and region per
+	//  request instead of one request per resource.
+	BatchMode bool `yaml:"batch_mode"`
+	BatchSize int  `yaml:"batch_size"`
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler for Config.
@@ -131,6 +138,21 @@ func (c *Config) Validate() error {
 		configErrors = append(configErrors, "regions and resource_graph_query_filter cannot be used together. If you want to target specific resources add a region filter to the resource_graph_query_filter. Otherwise, remove your resource_graph_query_filter to get metrics without further filtering.")
 	}
 
+	if c.BatchMode {
+		if len(c.Regions) > 0 {
+			configErrors = append(configErrors, "regions and batch_mode cannot be used together. Use a resource_graph_query_filter to target the resources of specific regions in batch mode.")
+		}
+		if c.BatchSize < 1 || c.BatchSize > maxBatchSize {
+			configErrors = append(configErrors, fmt.Sprintf("batch_size must be between 1 and %d", maxBatchSize))
+		}
+		if _, ok := batchClouds[strings.ToLower(c.AzureCloudEnvironment)]; !ok {
+			configErrors = append(configErrors, fmt.Sprintf("batch_mode is not supported for azure cloud environment %s", c.AzureCloudEnvironment))
+		}
+		if _, err := parseISO8601Duration(c.Timespan); err != nil {
+			configErrors = append(configErrors, fmt.Sprintf("invalid timespan for batch_mode, %v", err))
+		}
+	}
+
 	validAggregations := []string{"minimum", "maximum", "average", "total", "count"}
 
 	for _, aggregation := range c.MetricAggregations {
@@ -282,6 +304,24 @@ func MergeConfigWithQueryParams(cfg Config, params url.Values) (Config, error) {
 		cfg.ValidateDimensions = v
 	}
 
+	batchMode := params.Get("batch_mode")
+	if len(batchMode) != 0 {
+		v, err := strconv.ParseBool(batchMode)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid boolean value %s for batch_mode", batchMode)
+		}
+		cfg.BatchMode = v
+	}
+
+	batchSize := params.Get("batch_size")
+	if len(batchSize) != 0 {
+		v, err := strconv.Atoi(batchSize)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid integer value %s for batch_size", batchSize)
+		}
+		cfg.BatchSize = v
+	}
+
 	return cfg, nil
 }
 
@@ -185,6 +185,34 @@ func TestConfig_Validate(t *testing.T) {
 				return config
 			},
 		},
+		{
+			name: "includes Regions and BatchMode",
+			toInvalidConfig: func(config azure_exporter.Config) azure_exporter.Config {
+				config.BatchMode = true
+				config.BatchSize = 50
+				config.Timespan = "PT1M"
+				config.Regions = []string{"uswest", "useast"}
+				return config
+			},
+		},
+		{
+			name: "BatchSize over the getBatch limit",
+			toInvalidConfig: func(config azure_exporter.Config) azure_exporter.Config {
+				config.BatchMode = true
+				config.BatchSize = 51
+				config.Timespan = "PT1M"
+				return config
+			},
+		},
+		{
+			name: "BatchMode with an invalid Timespan",
+			toInvalidConfig: func(config azure_exporter.Config) azure_exporter.Config {
+				config.BatchMode = true
+				config.BatchSize = 50
+				config.Timespan = "1 minute"
+				return config
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -227,6 +255,9 @@ func TestMergeConfigWithQueryParams_MapsAllExpectedFieldsByYamlNameFromConfig(t
 			case "bool":
 				urlParams[yamlFieldName] = []string{"false"}
 				fieldValue = false
+			case "int":
+				urlParams[yamlFieldName] = []string{"25"}
+				fieldValue = 25
 			default:
 				t.Fatalf("Attempting to map %s, discovered unexpected type %s", mappableField.Name, mappableField.Type.String())
 			}
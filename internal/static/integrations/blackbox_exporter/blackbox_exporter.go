@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/static/integrations"
 	"github.com/grafana/alloy/internal/static/integrations/config"
 	"github.com/grafana/alloy/internal/util"
 	blackbox_config "github.com/prometheus/blackbox_exporter/config"
 	"github.com/prometheus/blackbox_exporter/prober"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
 	"gopkg.in/yaml.v3"
 )
 
@@ -45,6 +48,9 @@ type Config struct {
 	BlackboxTargets    []BlackboxTarget `yaml:"blackbox_targets"`
 	BlackboxConfig     util.RawYAML     `yaml:"blackbox_config,omitempty"`
 	ProbeTimeoutOffset float64          `yaml:"probe_timeout_offset,omitempty"`
+
+	// ProbeResultHandler, if set, receives the result of every probe.
+	ProbeResultHandler ProbeResultHandler `yaml:"-"`
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler for Config.
@@ -138,10 +144,45 @@ type Integration struct {
 // MetricsHandler implements Integration.
 func (i *Integration) MetricsHandler() (http.Handler, error) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		prober.Handler(w, r, i.modules, i.log, &prober.ResultHistory{}, i.cfg.ProbeTimeoutOffset, nil, nil)
+		if i.cfg.ProbeResultHandler == nil || r.URL.Query().Get("debug") == "true" {
+			prober.Handler(w, r, i.modules, i.log, &prober.ResultHistory{}, i.cfg.ProbeTimeoutOffset, nil, nil)
+			return
+		}
+		i.probeWithResult(w, r)
 	}), nil
 }
 
+// probeWithResult runs a probe and reports its result to the
+// ProbeResultHandler. The metrics of the probe are always returned in the
+// text exposition format, so they can be read.
+func (i *Integration) probeWithResult(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	req := r.Clone(r.Context())
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	rec := newResponseRecorder()
+	logs := &probeLogger{next: i.log}
+
+	prober.Handler(rec, req, i.modules, logs, &prober.ResultHistory{}, i.cfg.ProbeTimeoutOffset, nil, nil)
+	rec.writeTo(w)
+
+	// The probe didn't run if the target or the module is invalid.
+	if rec.status != http.StatusOK {
+		return
+	}
+
+	module := r.URL.Query().Get("module")
+	if module == "" {
+		module = defaultModule
+	}
+	result, err := newProbeResult(r.URL.Query().Get("target"), module, start, rec.body.Bytes(), logs)
+	if err != nil {
+		level.Warn(i.log).Log("msg", "failed to read the result of a probe", "err", err)
+		return
+	}
+	i.cfg.ProbeResultHandler(result)
+}
+
 // Run satisfies Integration.Run.
 func (i *Integration) Run(ctx context.Context) error {
 	// We don't need to do anything here, so we can just wait for the context to
@@ -0,0 +1,184 @@
+package blackbox_exporter
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// defaultModule is the module used by the prober when the module parameter
+// of a probe is empty.
+const defaultModule = "http_2xx"
+
+// ProbeResult is the result of a probe.
+type ProbeResult struct {
+	Target  string
+	Module  string
+	Time    time.Time
+	Success bool
+
+	// Duration of the probe.
+	Duration time.Duration
+	// Phases are the durations of the phases of the probe, such as resolve,
+	// connect, or tls, in seconds. The phases depend on the prober.
+	Phases map[string]float64
+
+	// ResolvedIP is the IP address the target resolved to, if any.
+	ResolvedIP string
+	// HTTPStatusCode is the status code of the final HTTP response, if any.
+	HTTPStatusCode int
+	// TLSExpiry is the earliest expiry of the TLS certificate chain of the
+	// target, if any.
+	TLSExpiry time.Time
+	// FailureReason is the last message logged by the prober before the probe
+	// failed.
+	FailureReason string
+}
+
+// ProbeResultHandler receives the result of every probe.
+type ProbeResultHandler func(ProbeResult)
+
+// probeLogger records the log lines of the prober, and forwards them to the
+// next logger.
+type probeLogger struct {
+	next log.Logger
+
+	mut   sync.Mutex
+	lines []map[string]string
+}
+
+func (l *probeLogger) Log(keyvals ...interface{}) error {
+	line := make(map[string]string, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		line[fmt.Sprint(keyvals[i])] = fmt.Sprint(keyvals[i+1])
+	}
+
+	l.mut.Lock()
+	l.lines = append(l.lines, line)
+	l.mut.Unlock()
+
+	return l.next.Log(keyvals...)
+}
+
+// resolvedIP returns the IP address logged when resolving the target.
+func (l *probeLogger) resolvedIP() string {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for _, line := range l.lines {
+		if ip, ok := line["ip"]; ok {
+			return ip
+		}
+	}
+	return ""
+}
+
+// failureReason returns the last message logged before the probe failed,
+// with its error if any. The prober logs every line at the debug level, so
+// errors can't be told apart by their level.
+func (l *probeLogger) failureReason() string {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for i := len(l.lines) - 1; i >= 0; i-- {
+		line := l.lines[i]
+		if line["msg"] == "" || line["msg"] == "Probe failed" {
+			continue
+		}
+		if err, ok := line["err"]; ok {
+			return line["msg"] + ": " + err
+		}
+		return line["msg"]
+	}
+	return ""
+}
+
+// responseRecorder records the response of the prober, so the metrics of the
+// probe can be read before they're written to the scraper.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *responseRecorder) WriteHeader(status int)      { r.status = status }
+
+// writeTo writes the recorded response to w.
+func (r *responseRecorder) writeTo(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body.Bytes())
+}
+
+// newProbeResult builds the result of a probe from the metrics in the text
+// exposition format returned by the prober, and its log lines.
+func newProbeResult(target, module string, start time.Time, metrics []byte, logs *probeLogger) (ProbeResult, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		return ProbeResult{}, fmt.Errorf("failed to parse the metrics of the probe: %w", err)
+	}
+
+	res := ProbeResult{
+		Target:     target,
+		Module:     module,
+		Time:       start,
+		Success:    gaugeValue(families["probe_success"]) == 1,
+		Duration:   time.Duration(gaugeValue(families["probe_duration_seconds"]) * float64(time.Second)),
+		Phases:     make(map[string]float64),
+		ResolvedIP: logs.resolvedIP(),
+	}
+
+	for name, mf := range families {
+		if !strings.HasPrefix(name, "probe_") || !strings.HasSuffix(name, "_duration_seconds") {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "phase" {
+					res.Phases[lp.GetValue()] += m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	if _, ok := res.Phases["resolve"]; !ok {
+		if mf, ok := families["probe_dns_lookup_time_seconds"]; ok {
+			res.Phases["resolve"] = gaugeValue(mf)
+		}
+	}
+
+	if code := gaugeValue(families["probe_http_status_code"]); code > 0 {
+		res.HTTPStatusCode = int(code)
+	}
+	if expiry := gaugeValue(families["probe_ssl_earliest_cert_expiry"]); expiry > 0 {
+		res.TLSExpiry = time.Unix(int64(expiry), 0).UTC()
+	}
+	if !res.Success {
+		res.FailureReason = logs.failureReason()
+	}
+	return res, nil
+}
+
+// gaugeValue returns the value of the first gauge of mf, or 0 if there's
+// none.
+func gaugeValue(mf *dto.MetricFamily) float64 {
+	if mf == nil || len(mf.GetMetric()) == 0 {
+		return 0
+	}
+	return mf.GetMetric()[0].GetGauge().GetValue()
+}
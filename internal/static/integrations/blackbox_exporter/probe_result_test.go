@@ -0,0 +1,92 @@
+package blackbox_exporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+const testProbeMetrics = `# HELP probe_dns_lookup_time_seconds Returns the time taken for probe dns lookup in seconds
+# TYPE probe_dns_lookup_time_seconds gauge
+probe_dns_lookup_time_seconds 0.004
+# HELP probe_duration_seconds Returns how long the probe took to complete in seconds
+# TYPE probe_duration_seconds gauge
+probe_duration_seconds 0.25
+# HELP probe_http_duration_seconds Duration of http request by phase, summed over all redirects
+# TYPE probe_http_duration_seconds gauge
+probe_http_duration_seconds{phase="connect"} 0.02
+probe_http_duration_seconds{phase="processing"} 0.15
+probe_http_duration_seconds{phase="resolve"} 0.004
+probe_http_duration_seconds{phase="tls"} 0.05
+probe_http_duration_seconds{phase="transfer"} 0.001
+# HELP probe_http_status_code Response HTTP status code
+# TYPE probe_http_status_code gauge
+probe_http_status_code 503
+# HELP probe_ssl_earliest_cert_expiry Returns last SSL chain expiry in unixtime
+# TYPE probe_ssl_earliest_cert_expiry gauge
+probe_ssl_earliest_cert_expiry 1.7356896e+09
+# HELP probe_success Displays whether or not the probe was a success
+# TYPE probe_success gauge
+probe_success 0
+`
+
+func TestNewProbeResult(t *testing.T) {
+	logs := &probeLogger{next: log.NewNopLogger()}
+	for _, line := range [][]interface{}{
+		{"module", "http_2xx", "target", "https://example.com", "level", "debug", "msg", "Resolving target address", "target", "example.com"},
+		{"module", "http_2xx", "target", "https://example.com", "level", "debug", "msg", "Resolved target address", "target", "example.com", "ip", "93.184.215.14"},
+		{"module", "http_2xx", "target", "https://example.com", "level", "debug", "msg", "Received HTTP response", "status_code", 503},
+		{"module", "http_2xx", "target", "https://example.com", "level", "debug", "msg", "Invalid HTTP response status code, wanted 2xx", "status_code", 503},
+		{"module", "http_2xx", "target", "https://example.com", "level", "debug", "msg", "Probe failed", "duration_seconds", 0.25},
+	} {
+		require.NoError(t, logs.Log(line...))
+	}
+
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	res, err := newProbeResult("https://example.com", "http_2xx", start, []byte(testProbeMetrics), logs)
+	require.NoError(t, err)
+
+	require.Equal(t, ProbeResult{
+		Target:   "https://example.com",
+		Module:   "http_2xx",
+		Time:     start,
+		Success:  false,
+		Duration: 250 * time.Millisecond,
+		Phases: map[string]float64{
+			"connect":    0.02,
+			"processing": 0.15,
+			"resolve":    0.004,
+			"tls":        0.05,
+			"transfer":   0.001,
+		},
+		ResolvedIP:     "93.184.215.14",
+		HTTPStatusCode: 503,
+		TLSExpiry:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		FailureReason:  "Invalid HTTP response status code, wanted 2xx",
+	}, res)
+}
+
+func TestNewProbeResult_DNSFailure(t *testing.T) {
+	logs := &probeLogger{next: log.NewNopLogger()}
+	require.NoError(t, logs.Log("msg", "Resolving target address", "target", "missing.example.com"))
+	require.NoError(t, logs.Log("msg", "Error resolving address", "err", "lookup missing.example.com: no such host"))
+	require.NoError(t, logs.Log("msg", "Probe failed", "duration_seconds", 0.01))
+
+	metrics := `# TYPE probe_dns_lookup_time_seconds gauge
+probe_dns_lookup_time_seconds 0.01
+# TYPE probe_duration_seconds gauge
+probe_duration_seconds 0.01
+# TYPE probe_success gauge
+probe_success 0
+`
+	res, err := newProbeResult("missing.example.com:443", "tcp_connect", time.Now(), []byte(metrics), logs)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]float64{"resolve": 0.01}, res.Phases)
+	require.Empty(t, res.ResolvedIP)
+	require.Zero(t, res.HTTPStatusCode)
+	require.True(t, res.TLSExpiry.IsZero())
+	require.Equal(t, "Error resolving address: lookup missing.example.com: no such host", res.FailureReason)
+}
@@ -70,6 +70,13 @@ func (c *Config) GetIncludedMetrics() (container.MetricSet, error) {
 		includedMetrics = container.AllMetrics.Difference(disabledMetrics)
 	}
 
+	// Filesystem stats are disabled last, so that they're disabled even when
+	// they're explicitly enabled.
+	if c.DisableFilesystemStats {
+		delete(includedMetrics, container.DiskUsageMetrics)
+		delete(includedMetrics, container.DiskIOMetrics)
+	}
+
 	return includedMetrics, nil
 }
 
@@ -113,6 +120,11 @@ func New(logger log.Logger, c *Config) (integrations.Integration, error) {
 		return nil, fmt.Errorf("unable to determine included metrics: %w", err)
 	}
 
+	filter, err := newContainerFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
 	rawOpts := raw.Options{
 		DockerOnly:             c.DockerOnly,
 		DisableRootCgroupStats: c.DisableRootCgroupStats,
@@ -141,7 +153,11 @@ func New(logger log.Logger, c *Config) (integrations.Integration, error) {
 		Count:     1,
 		Recursive: true,
 	}
-	contCol := metrics.NewPrometheusCollector(rm, containerLabelFunc, includedMetrics, clock.RealClock{}, reqOpts)
+	var provider infoProvider = rm
+	if filter != nil {
+		provider = &filteredInfoProvider{infoProvider: rm, filter: filter}
+	}
+	contCol := metrics.NewPrometheusCollector(provider, containerLabelFunc, includedMetrics, clock.RealClock{}, reqOpts)
 
 	start := func(ctx context.Context) error {
 		<-ctx.Done()
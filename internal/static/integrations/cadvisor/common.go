@@ -96,6 +96,25 @@ type Config struct {
 	// DisableRootCgroupStats informs the exporter not collecting stats from the root cgroup.
 	DisableRootCgroupStats bool `yaml:"disable_root_cgroup_stats,omitempty"`
 
+	// Container filtering options
+	// KubernetesNamespaceAllowlist list of regular expressions matching the Kubernetes namespaces of the containers to collect.
+	KubernetesNamespaceAllowlist []string `yaml:"kubernetes_namespace_allowlist,omitempty"`
+
+	// KubernetesNamespaceDenylist list of regular expressions matching the Kubernetes namespaces of the containers not to collect.
+	KubernetesNamespaceDenylist []string `yaml:"kubernetes_namespace_denylist,omitempty"`
+
+	// KubernetesPodAllowlist list of regular expressions matching the Kubernetes pods of the containers to collect.
+	KubernetesPodAllowlist []string `yaml:"kubernetes_pod_allowlist,omitempty"`
+
+	// CgroupPathAllowlist list of cgroup path prefixes of the containers to collect.
+	CgroupPathAllowlist []string `yaml:"cgroup_path_allowlist,omitempty"`
+
+	// CgroupPathDenylist list of cgroup path prefixes of the containers not to collect.
+	CgroupPathDenylist []string `yaml:"cgroup_path_denylist,omitempty"`
+
+	// DisableFilesystemStats disables the per-filesystem disk usage and disk IO metrics, even if they're enabled by enabled_metrics.
+	DisableFilesystemStats bool `yaml:"disable_filesystem_stats,omitempty"`
+
 	// Hold on to the logger passed to config.NewIntegration, to be passed to klog, as yet another unsafe global that needs to be set.
 	logger log.Logger //nolint:unused,structcheck // logger is only used on linux
 }
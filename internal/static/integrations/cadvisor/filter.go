@@ -0,0 +1,140 @@
+//go:build linux
+
+package cadvisor //nolint:golint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	info "github.com/google/cadvisor/info/v1"
+	v2 "github.com/google/cadvisor/info/v2"
+)
+
+// Labels set by the kubelet on the containers of pods.
+const (
+	kubernetesNamespaceLabel = "io.kubernetes.pod.namespace"
+	kubernetesPodLabel       = "io.kubernetes.pod.name"
+)
+
+// containerFilter selects the containers metrics are collected for.
+type containerFilter struct {
+	namespaceAllowlist []*regexp.Regexp
+	namespaceDenylist  []*regexp.Regexp
+	podAllowlist       []*regexp.Regexp
+	cgroupAllowlist    []string
+	cgroupDenylist     []string
+}
+
+// newContainerFilter returns the filter of the containers of c, or nil if all
+// containers are collected.
+func newContainerFilter(c *Config) (*containerFilter, error) {
+	var (
+		f   containerFilter
+		err error
+	)
+	if f.namespaceAllowlist, err = compileAnchored(c.KubernetesNamespaceAllowlist); err != nil {
+		return nil, fmt.Errorf("invalid kubernetes_namespace_allowlist: %w", err)
+	}
+	if f.namespaceDenylist, err = compileAnchored(c.KubernetesNamespaceDenylist); err != nil {
+		return nil, fmt.Errorf("invalid kubernetes_namespace_denylist: %w", err)
+	}
+	if f.podAllowlist, err = compileAnchored(c.KubernetesPodAllowlist); err != nil {
+		return nil, fmt.Errorf("invalid kubernetes_pod_allowlist: %w", err)
+	}
+	f.cgroupAllowlist = c.CgroupPathAllowlist
+	f.cgroupDenylist = c.CgroupPathDenylist
+
+	if len(f.namespaceAllowlist) == 0 && len(f.namespaceDenylist) == 0 && len(f.podAllowlist) == 0 &&
+		len(f.cgroupAllowlist) == 0 && len(f.cgroupDenylist) == 0 {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// compileAnchored compiles regular expressions which must match whole values.
+func compileAnchored(exprs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// keep returns true if metrics must be collected for the container. The
+// containers which match a denylist are dropped. Otherwise, they're kept when
+// they match every configured allowlist.
+func (f *containerFilter) keep(c *info.ContainerInfo) bool {
+	var (
+		cgroup    = c.Name
+		namespace = c.Spec.Labels[kubernetesNamespaceLabel]
+		pod       = c.Spec.Labels[kubernetesPodLabel]
+	)
+
+	if hasAnyPrefix(cgroup, f.cgroupDenylist) {
+		return false
+	}
+	if namespace != "" && matchesAny(namespace, f.namespaceDenylist) {
+		return false
+	}
+
+	if len(f.cgroupAllowlist) > 0 && !hasAnyPrefix(cgroup, f.cgroupAllowlist) {
+		return false
+	}
+	if len(f.namespaceAllowlist) > 0 && !matchesAny(namespace, f.namespaceAllowlist) {
+		return false
+	}
+	if len(f.podAllowlist) > 0 && !matchesAny(pod, f.podAllowlist) {
+		return false
+	}
+	return true
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(s string, res []*regexp.Regexp) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// infoProvider is the interface the cadvisor Prometheus collector uses to get
+// the information of containers.
+type infoProvider interface {
+	GetRequestedContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error)
+	GetVersionInfo() (*info.VersionInfo, error)
+	GetMachineInfo() (*info.MachineInfo, error)
+}
+
+// filteredInfoProvider only provides the information of the containers
+// selected by a filter, so that no metrics are built for other containers.
+type filteredInfoProvider struct {
+	infoProvider
+	filter *containerFilter
+}
+
+// GetRequestedContainersInfo implements infoProvider. Like the cadvisor
+// manager, it returns partial results along with errors.
+func (p *filteredInfoProvider) GetRequestedContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	containers, err := p.infoProvider.GetRequestedContainersInfo(containerName, options)
+	for name, c := range containers {
+		if !p.filter.keep(c) {
+			delete(containers, name)
+		}
+	}
+	return containers, err
+}
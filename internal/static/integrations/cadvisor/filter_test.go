@@ -0,0 +1,127 @@
+//go:build linux
+
+package cadvisor
+
+import (
+	"testing"
+
+	"github.com/google/cadvisor/container"
+	info "github.com/google/cadvisor/info/v1"
+	v2 "github.com/google/cadvisor/info/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContainerFilter_NoFilter(t *testing.T) {
+	f, err := newContainerFilter(&Config{})
+	require.NoError(t, err)
+	require.Nil(t, f)
+}
+
+func TestNewContainerFilter_InvalidRegex(t *testing.T) {
+	_, err := newContainerFilter(&Config{KubernetesPodAllowlist: []string{"api-("}})
+	require.ErrorContains(t, err, "invalid kubernetes_pod_allowlist")
+}
+
+func TestContainerFilter_Keep(t *testing.T) {
+	f, err := newContainerFilter(&Config{
+		KubernetesNamespaceAllowlist: []string{"prod-.*"},
+		KubernetesNamespaceDenylist:  []string{"prod-internal"},
+		KubernetesPodAllowlist:       []string{"api-.*"},
+		CgroupPathDenylist:           []string{"/kubepods.slice/kubepods-besteffort.slice"},
+	})
+	require.NoError(t, err)
+
+	tt := []struct {
+		name      string
+		cgroup    string
+		namespace string
+		pod       string
+		keep      bool
+	}{
+		{"allowed", "/kubepods.slice/pod1", "prod-eu", "api-1", true},
+		{"namespace not allowed", "/kubepods.slice/pod1", "dev", "api-1", false},
+		{"namespace allowlist is anchored", "/kubepods.slice/pod1", "preprod-eu", "api-1", false},
+		{"namespace denied", "/kubepods.slice/pod1", "prod-internal", "api-1", false},
+		{"pod not allowed", "/kubepods.slice/pod1", "prod-eu", "worker-1", false},
+		{"cgroup denied", "/kubepods.slice/kubepods-besteffort.slice/pod1", "prod-eu", "api-1", false},
+		{"not a pod", "/system.slice/docker.service", "", "", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.keep, f.keep(newContainerInfo(tc.cgroup, tc.namespace, tc.pod)))
+		})
+	}
+}
+
+func TestContainerFilter_CgroupAllowlist(t *testing.T) {
+	f, err := newContainerFilter(&Config{
+		CgroupPathAllowlist: []string{"/kubepods.slice"},
+		CgroupPathDenylist:  []string{"/kubepods.slice/kubepods-besteffort.slice"},
+	})
+	require.NoError(t, err)
+
+	require.True(t, f.keep(newContainerInfo("/kubepods.slice/pod1", "", "")))
+	require.False(t, f.keep(newContainerInfo("/kubepods.slice/kubepods-besteffort.slice/pod1", "", "")))
+	require.False(t, f.keep(newContainerInfo("/system.slice/docker.service", "", "")))
+}
+
+func TestFilteredInfoProvider(t *testing.T) {
+	f, err := newContainerFilter(&Config{KubernetesNamespaceDenylist: []string{"kube-system"}})
+	require.NoError(t, err)
+
+	p := &filteredInfoProvider{
+		infoProvider: fakeInfoProvider{
+			"/":     newContainerInfo("/", "", ""),
+			"/pod1": newContainerInfo("/pod1", "default", "api-1"),
+			"/pod2": newContainerInfo("/pod2", "kube-system", "coredns-1"),
+		},
+		filter: f,
+	}
+	containers, err := p.GetRequestedContainersInfo("/", v2.RequestOptions{})
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+	require.Contains(t, containers, "/")
+	require.Contains(t, containers, "/pod1")
+}
+
+func TestGetIncludedMetrics_DisableFilesystemStats(t *testing.T) {
+	cfg := Config{
+		EnabledMetrics:         []string{"cpu", "disk", "diskIO"},
+		DisableFilesystemStats: true,
+	}
+	metrics, err := cfg.GetIncludedMetrics()
+	require.NoError(t, err)
+	require.True(t, metrics.Has(container.CpuUsageMetrics))
+	require.False(t, metrics.Has(container.DiskUsageMetrics))
+	require.False(t, metrics.Has(container.DiskIOMetrics))
+}
+
+func newContainerInfo(cgroup, namespace, pod string) *info.ContainerInfo {
+	labels := map[string]string{}
+	if namespace != "" {
+		labels[kubernetesNamespaceLabel] = namespace
+		labels[kubernetesPodLabel] = pod
+	}
+	return &info.ContainerInfo{
+		ContainerReference: info.ContainerReference{Name: cgroup},
+		Spec:               info.ContainerSpec{Labels: labels},
+	}
+}
+
+type fakeInfoProvider map[string]*info.ContainerInfo
+
+func (p fakeInfoProvider) GetRequestedContainersInfo(string, v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	containers := make(map[string]*info.ContainerInfo, len(p))
+	for name, c := range p {
+		containers[name] = c
+	}
+	return containers, nil
+}
+
+func (p fakeInfoProvider) GetVersionInfo() (*info.VersionInfo, error) {
+	return &info.VersionInfo{}, nil
+}
+
+func (p fakeInfoProvider) GetMachineInfo() (*info.MachineInfo, error) {
+	return &info.MachineInfo{}, nil
+}
@@ -0,0 +1,129 @@
+// Package ceph_exporter collects metrics of a Ceph cluster from the
+// prometheus module of the Ceph manager.
+package ceph_exporter //nolint:golint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/static/integrations"
+	config_util "github.com/prometheus/common/config"
+)
+
+// DefaultConfig is the default config for the ceph integration.
+var DefaultConfig = Config{
+	URL:              "http://localhost:9283/metrics",
+	Timeout:          10 * time.Second,
+	PoolIncludeRegex: ".*",
+	OSDIncludeRegex:  ".*",
+}
+
+// Config controls the ceph integration.
+type Config struct {
+	// URL of the metrics endpoint of the prometheus module of the Ceph
+	// manager.
+	URL string `yaml:"url,omitempty"`
+
+	// Timeout of the requests to the Ceph manager during a scrape.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// TLSConfig is used to connect to the Ceph manager.
+	TLSConfig *config_util.TLSConfig `yaml:"tls_config,omitempty"`
+
+	// Regular expressions selecting the pools, by name, and the OSDs, by
+	// daemon name such as osd.0, to collect metrics from. Exclude regular
+	// expressions take precedence, and are ignored when empty.
+	PoolIncludeRegex string `yaml:"pool_include_regex,omitempty"`
+	PoolExcludeRegex string `yaml:"pool_exclude_regex,omitempty"`
+	OSDIncludeRegex  string `yaml:"osd_include_regex,omitempty"`
+	OSDExcludeRegex  string `yaml:"osd_exclude_regex,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "ceph"
+}
+
+// InstanceKey returns the host of the Ceph manager.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+	return u.Host, nil
+}
+
+// NewIntegration creates a new ceph integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// New creates a new ceph integration. Metrics are collected from the Ceph
+// manager on every scrape.
+func New(l log.Logger, c *Config) (integrations.Integration, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.TLSConfig != nil {
+		tlsConfig, err := config_util.NewTLSConfig(c.TLSConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls_config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	f, err := newFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	col := newCollector(l, &http.Client{Transport: transport, Timeout: c.Timeout}, c.URL, f)
+	return integrations.NewCollectorIntegration(c.Name(), integrations.WithCollectors(col)), nil
+}
+
+// filter selects the pools and OSDs to collect metrics from.
+type filter struct {
+	poolInclude, poolExclude *regexp.Regexp
+	osdInclude, osdExclude   *regexp.Regexp
+}
+
+func newFilter(c *Config) (*filter, error) {
+	var f filter
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{"pool include", c.PoolIncludeRegex, &f.poolInclude},
+		{"pool exclude", c.PoolExcludeRegex, &f.poolExclude},
+		{"OSD include", c.OSDIncludeRegex, &f.osdInclude},
+		{"OSD exclude", c.OSDExcludeRegex, &f.osdExclude},
+	} {
+		if re.expr == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("^(?:" + re.expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s regex %q: %w", re.name, re.expr, err)
+		}
+		*re.dst = compiled
+	}
+	return &f, nil
+}
+
+func (f *filter) pool(name string) bool {
+	return matches(name, f.poolInclude, f.poolExclude)
+}
+
+func (f *filter) osd(name string) bool {
+	return matches(name, f.osdInclude, f.osdExclude)
+}
+
+func matches(name string, include, exclude *regexp.Regexp) bool {
+	if exclude != nil && exclude.MatchString(name) {
+		return false
+	}
+	return include == nil || include.MatchString(name)
+}
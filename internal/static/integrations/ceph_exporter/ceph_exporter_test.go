@@ -0,0 +1,114 @@
+package ceph_exporter //nolint:golint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testMetrics = `# HELP ceph_health_status Cluster health status
+# TYPE ceph_health_status untyped
+ceph_health_status 0.0
+# HELP ceph_pool_metadata POOL Metadata
+# TYPE ceph_pool_metadata untyped
+ceph_pool_metadata{pool_id="1",name=".mgr",type="replicated",description="replica:3",compression_mode="none"} 1.0
+ceph_pool_metadata{pool_id="2",name="rbd",type="replicated",description="replica:3",compression_mode="none"} 1.0
+# HELP ceph_pool_stored DF pool stored
+# TYPE ceph_pool_stored gauge
+ceph_pool_stored{pool_id="1"} 1024.0
+ceph_pool_stored{pool_id="2"} 4096.0
+# HELP ceph_osd_up OSD status up
+# TYPE ceph_osd_up untyped
+ceph_osd_up{ceph_daemon="osd.0"} 1.0
+ceph_osd_up{ceph_daemon="osd.1"} 0.0
+# HELP ceph_osd_op_r Client read operations
+# TYPE ceph_osd_op_r counter
+ceph_osd_op_r{ceph_daemon="osd.0"} 120.0
+ceph_osd_op_r{ceph_daemon="osd.1"} 80.0
+# HELP ceph_mon_quorum_status Monitors in quorum
+# TYPE ceph_mon_quorum_status gauge
+ceph_mon_quorum_status{ceph_daemon="mon.a"} 1.0
+`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testMetrics))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollector(t *testing.T) {
+	srv := newTestServer(t)
+
+	f, err := newFilter(&DefaultConfig)
+	require.NoError(t, err)
+	col := newCollector(log.NewNopLogger(), http.DefaultClient, srv.URL, f)
+
+	expected := `
+# HELP ceph_up Whether the last scrape of the Ceph manager was successful.
+# TYPE ceph_up gauge
+ceph_up 1
+# HELP ceph_health_status Cluster health status
+# TYPE ceph_health_status untyped
+ceph_health_status 0
+# HELP ceph_pool_stored DF pool stored
+# TYPE ceph_pool_stored gauge
+ceph_pool_stored{pool_id="1"} 1024
+ceph_pool_stored{pool_id="2"} 4096
+# HELP ceph_osd_op_r Client read operations
+# TYPE ceph_osd_op_r counter
+ceph_osd_op_r{ceph_daemon="osd.0"} 120
+ceph_osd_op_r{ceph_daemon="osd.1"} 80
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"ceph_up", "ceph_health_status", "ceph_pool_stored", "ceph_osd_op_r"))
+}
+
+func TestCollector_Filters(t *testing.T) {
+	srv := newTestServer(t)
+
+	f, err := newFilter(&Config{PoolExcludeRegex: `\.mgr`, OSDIncludeRegex: `osd\.1`})
+	require.NoError(t, err)
+	col := newCollector(log.NewNopLogger(), http.DefaultClient, srv.URL, f)
+
+	expected := `
+# HELP ceph_pool_metadata POOL Metadata
+# TYPE ceph_pool_metadata untyped
+ceph_pool_metadata{compression_mode="none",description="replica:3",name="rbd",pool_id="2",type="replicated"} 1
+# HELP ceph_pool_stored DF pool stored
+# TYPE ceph_pool_stored gauge
+ceph_pool_stored{pool_id="2"} 4096
+# HELP ceph_osd_up OSD status up
+# TYPE ceph_osd_up untyped
+ceph_osd_up{ceph_daemon="osd.1"} 0
+# HELP ceph_mon_quorum_status Monitors in quorum
+# TYPE ceph_mon_quorum_status gauge
+ceph_mon_quorum_status{ceph_daemon="mon.a"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"ceph_pool_metadata", "ceph_pool_stored", "ceph_osd_up", "ceph_mon_quorum_status"))
+}
+
+func TestCollector_Down(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "module not enabled", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f, err := newFilter(&DefaultConfig)
+	require.NoError(t, err)
+	col := newCollector(log.NewNopLogger(), http.DefaultClient, srv.URL, f)
+
+	expected := `
+# HELP ceph_up Whether the last scrape of the Ceph manager was successful.
+# TYPE ceph_up gauge
+ceph_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected)))
+}
@@ -0,0 +1,176 @@
+package ceph_exporter //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// poolMetadataMetric maps the IDs of the pools to their names.
+	poolMetadataMetric = "ceph_pool_metadata"
+
+	poolIDLabel     = "pool_id"
+	poolNameLabel   = "name"
+	cephDaemonLabel = "ceph_daemon"
+	osdDaemonPrefix = "osd."
+)
+
+var upDesc = prometheus.NewDesc("ceph_up", "Whether the last scrape of the Ceph manager was successful.", nil, nil)
+
+// collector collects the metrics of the prometheus module of the Ceph
+// manager on every scrape, dropping the series of the filtered pools and
+// OSDs.
+//
+// The metrics exposed by the Ceph manager depend on its version and
+// configuration, so collector is an unchecked collector.
+type collector struct {
+	log    log.Logger
+	client *http.Client
+	url    string
+	filter *filter
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+func newCollector(l log.Logger, cl *http.Client, url string, f *filter) *collector {
+	return &collector{log: l, client: cl, url: url, filter: f}
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	families, err := c.fetch(context.Background())
+	if err != nil {
+		level.Error(c.log).Log("msg", "failed to collect metrics from the Ceph manager", "err", err)
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1)
+
+	pools := poolNames(families[poolMetadataMetric])
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			if !c.keep(m, pools) {
+				continue
+			}
+			metric, err := constMetric(mf, m)
+			if err != nil {
+				level.Warn(c.log).Log("msg", "failed to convert metric of the Ceph manager", "metric", mf.GetName(), "err", err)
+				continue
+			}
+			ch <- metric
+		}
+	}
+}
+
+func (c *collector) fetch(ctx context.Context) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// keep returns whether the series of m belongs to a pool and an OSD
+// selected by the filter. Series of neither pools nor OSDs are always kept.
+func (c *collector) keep(m *dto.Metric, pools map[string]string) bool {
+	for _, lp := range m.GetLabel() {
+		switch lp.GetName() {
+		case poolIDLabel:
+			name, ok := pools[lp.GetValue()]
+			if !ok {
+				name = lp.GetValue()
+			}
+			if !c.filter.pool(name) {
+				return false
+			}
+		case cephDaemonLabel:
+			if strings.HasPrefix(lp.GetValue(), osdDaemonPrefix) && !c.filter.osd(lp.GetValue()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// poolNames returns the names of the pools by ID.
+func poolNames(mf *dto.MetricFamily) map[string]string {
+	pools := make(map[string]string)
+	for _, m := range mf.GetMetric() {
+		var id, name string
+		for _, lp := range m.GetLabel() {
+			switch lp.GetName() {
+			case poolIDLabel:
+				id = lp.GetValue()
+			case poolNameLabel:
+				name = lp.GetValue()
+			}
+		}
+		if id != "" && name != "" {
+			pools[id] = name
+		}
+	}
+	return pools
+}
+
+// constMetric converts a parsed series to a constant metric.
+func constMetric(mf *dto.MetricFamily, m *dto.Metric) (prometheus.Metric, error) {
+	labelNames := make([]string, 0, len(m.GetLabel()))
+	labelValues := make([]string, 0, len(m.GetLabel()))
+	for _, lp := range m.GetLabel() {
+		labelNames = append(labelNames, lp.GetName())
+		labelValues = append(labelValues, lp.GetValue())
+	}
+	desc := prometheus.NewDesc(mf.GetName(), mf.GetHelp(), labelNames, nil)
+
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		return prometheus.NewConstMetric(desc, prometheus.CounterValue, m.GetCounter().GetValue(), labelValues...)
+	case dto.MetricType_GAUGE:
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.GetGauge().GetValue(), labelValues...)
+	case dto.MetricType_UNTYPED:
+		return prometheus.NewConstMetric(desc, prometheus.UntypedValue, m.GetUntyped().GetValue(), labelValues...)
+	case dto.MetricType_SUMMARY:
+		s := m.GetSummary()
+		quantiles := make(map[float64]float64, len(s.GetQuantile()))
+		for _, q := range s.GetQuantile() {
+			quantiles[q.GetQuantile()] = q.GetValue()
+		}
+		return prometheus.NewConstSummary(desc, s.GetSampleCount(), s.GetSampleSum(), quantiles, labelValues...)
+	case dto.MetricType_HISTOGRAM:
+		h := m.GetHistogram()
+		buckets := make(map[float64]uint64, len(h.GetBucket()))
+		for _, b := range h.GetBucket() {
+			if math.IsInf(b.GetUpperBound(), +1) {
+				continue
+			}
+			buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+		}
+		return prometheus.NewConstHistogram(desc, h.GetSampleCount(), h.GetSampleSum(), buckets, labelValues...)
+	default:
+		return nil, fmt.Errorf("unsupported metric type %s", mf.GetType())
+	}
+}
@@ -0,0 +1,365 @@
+package cloudwatch_exporter
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/static/integrations/config"
+)
+
+// MetricStreamConfig configures the ingestion of CloudWatch Metric Streams.
+type MetricStreamConfig struct {
+	// AccessKey is the access key configured in the Amazon Data Firehose HTTP
+	// endpoint destination. Requests aren't authenticated when it's empty.
+	AccessKey string
+	// SeriesTTL is how long a series is exposed after its last datapoint.
+	SeriesTTL time.Duration
+	// ExportedTags are the tags added as labels to the metrics of each
+	// namespace.
+	ExportedTags TagsPerNamespace
+}
+
+// maxFirehoseRequestSize is the maximum size of an Amazon Data Firehose
+// request, which buffers at most 64MiB of records before base64 encoding.
+const maxFirehoseRequestSize = 128 << 20
+
+// streamExporter exposes the metrics delivered by CloudWatch Metric Streams
+// through the HTTP endpoint destination of Amazon Data Firehose, instead of
+// polling them with GetMetricData.
+type streamExporter struct {
+	name     string
+	logger   log.Logger
+	cfg      MetricStreamConfig
+	tagCache *TagCache
+
+	// ctx is the context of Run, used to fetch tags in the background.
+	ctx context.Context
+
+	mut    sync.Mutex
+	series map[string]*streamSeries
+}
+
+// streamSeries is the latest value of a series delivered by a metric stream.
+type streamSeries struct {
+	name    string
+	labels  map[string]string
+	value   float64
+	updated time.Time
+}
+
+// NewMetricStreamExporter creates an Integration exposing the metrics
+// delivered by CloudWatch Metric Streams in the JSON output format. The
+// metrics of the namespaces in cfg.ExportedTags are enriched with the tags
+// of tagCache.
+func NewMetricStreamExporter(name string, logger log.Logger, cfg MetricStreamConfig, tagCache *TagCache) *streamExporter {
+	return &streamExporter{
+		name:     name,
+		logger:   logger,
+		cfg:      cfg,
+		tagCache: tagCache,
+		ctx:      context.Background(),
+		series:   map[string]*streamSeries{},
+	}
+}
+
+func (e *streamExporter) MetricsHandler() (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		reg := prometheus.NewRegistry()
+		reg.MustRegister(e)
+		promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+	mux.HandleFunc("/firehose", e.firehoseHandler)
+	return mux, nil
+}
+
+func (e *streamExporter) ScrapeConfigs() []config.ScrapeConfig {
+	return []config.ScrapeConfig{{
+		JobName:     e.name,
+		MetricsPath: "/metrics",
+	}}
+}
+
+func (e *streamExporter) Run(ctx context.Context) error {
+	e.mut.Lock()
+	e.ctx = ctx
+	e.mut.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+type firehoseRequest struct {
+	RequestID string `json:"requestId"`
+	Records   []struct {
+		Data string `json:"data"`
+	} `json:"records"`
+}
+
+type firehoseResponse struct {
+	RequestID    string `json:"requestId"`
+	Timestamp    int64  `json:"timestamp"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// firehoseHandler implements the HTTP endpoint delivery request and response
+// specifications of Amazon Data Firehose.
+func (e *streamExporter) firehoseHandler(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get("X-Amz-Firehose-Request-Id")
+	respond := func(status int, errMsg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(firehoseResponse{
+			RequestID:    requestID,
+			Timestamp:    time.Now().UnixMilli(),
+			ErrorMessage: errMsg,
+		})
+	}
+
+	if r.Method != http.MethodPost {
+		respond(http.StatusMethodNotAllowed, "only POST requests are supported")
+		return
+	}
+	if e.cfg.AccessKey != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Amz-Firehose-Access-Key")), []byte(e.cfg.AccessKey)) != 1 {
+		respond(http.StatusUnauthorized, "invalid access key")
+		return
+	}
+
+	body := io.Reader(http.MaxBytesReader(w, r.Body, maxFirehoseRequestSize))
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			respond(http.StatusBadRequest, fmt.Sprintf("invalid gzip body: %s", err))
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var req firehoseRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		respond(http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err))
+		return
+	}
+	if requestID == "" {
+		requestID = req.RequestID
+	}
+
+	now := time.Now()
+	for _, record := range req.Records {
+		data, err := base64.StdEncoding.DecodeString(record.Data)
+		if err != nil {
+			respond(http.StatusBadRequest, fmt.Sprintf("invalid record data: %s", err))
+			return
+		}
+		if err := e.ingest(data, now); err != nil {
+			respond(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	respond(http.StatusOK, "")
+}
+
+// streamDatapoint is a datapoint of a metric stream in the JSON output
+// format.
+type streamDatapoint struct {
+	AccountID  string             `json:"account_id"`
+	Region     string             `json:"region"`
+	Namespace  string             `json:"namespace"`
+	MetricName string             `json:"metric_name"`
+	Dimensions map[string]string  `json:"dimensions"`
+	Value      map[string]float64 `json:"value"`
+}
+
+// statisticNames are the names of the statistics of a datapoint in the
+// metrics names. Other statistics, such as percentiles, keep their name.
+var statisticNames = map[string]string{
+	"max":   "maximum",
+	"min":   "minimum",
+	"sum":   "sum",
+	"count": "sample_count",
+}
+
+// ingest stores the datapoints of a record, which are separated by newlines.
+func (e *streamExporter) ingest(data []byte, now time.Time) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+
+	e.mut.Lock()
+	ctx := e.ctx
+	e.mut.Unlock()
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var dp streamDatapoint
+		if err := json.Unmarshal([]byte(line), &dp); err != nil {
+			return fmt.Errorf("invalid metric stream datapoint: %w", err)
+		}
+		e.store(ctx, dp, now)
+	}
+	return scanner.Err()
+}
+
+func (e *streamExporter) store(ctx context.Context, dp streamDatapoint, now time.Time) {
+	labels := map[string]string{
+		"account_id": dp.AccountID,
+		"region":     dp.Region,
+	}
+	values := make([]string, 0, len(dp.Dimensions))
+	for name, value := range dp.Dimensions {
+		labels["dimension_"+promLabelName(name)] = value
+		values = append(values, value)
+	}
+	// Longer values identify resources more precisely.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	if tagKeys, ok := e.cfg.ExportedTags[dp.Namespace]; ok && e.tagCache != nil {
+		tags := e.tagCache.Lookup(ctx, dp.Region, values)
+		for _, key := range tagKeys {
+			labels["tag_"+promLabelName(key)] = tags[key]
+		}
+	}
+
+	prefix := "aws_" + promString(strings.TrimPrefix(dp.Namespace, "AWS/")) + "_" + promString(dp.MetricName) + "_"
+	stats := make(map[string]float64, len(dp.Value)+1)
+	for stat, value := range dp.Value {
+		if name, ok := statisticNames[stat]; ok {
+			stats[name] = value
+		} else {
+			stats[promString(stat)] = value
+		}
+	}
+	if count, ok := dp.Value["count"]; ok && count > 0 {
+		stats["average"] = dp.Value["sum"] / count
+	}
+
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	for stat, value := range stats {
+		s := &streamSeries{name: prefix + stat, labels: labels, value: value, updated: now}
+		e.series[seriesKey(s.name, labels)] = s
+	}
+}
+
+func seriesKey(name string, labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for n := range labels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, n := range names {
+		sb.WriteByte(0xff)
+		sb.WriteString(n)
+		sb.WriteByte(0xff)
+		sb.WriteString(labels[n])
+	}
+	return sb.String()
+}
+
+// Describe implements prometheus.Collector. The exporter is an unchecked
+// collector, as its metrics depend on the delivered datapoints.
+func (e *streamExporter) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector. It removes the series which
+// weren't updated within the series TTL. The series of the same metric with
+// different dimensions are given the same label names, with empty values
+// for the missing labels.
+func (e *streamExporter) Collect(ch chan<- prometheus.Metric) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	now := time.Now()
+	byName := map[string][]*streamSeries{}
+	for key, s := range e.series {
+		if now.Sub(s.updated) > e.cfg.SeriesTTL {
+			delete(e.series, key)
+			continue
+		}
+		byName[s.name] = append(byName[s.name], s)
+	}
+
+	for name, series := range byName {
+		labelSet := map[string]struct{}{}
+		for _, s := range series {
+			for l := range s.labels {
+				labelSet[l] = struct{}{}
+			}
+		}
+		labelNames := make([]string, 0, len(labelSet))
+		for l := range labelSet {
+			labelNames = append(labelNames, l)
+		}
+		sort.Strings(labelNames)
+
+		desc := prometheus.NewDesc(name, "CloudWatch metric delivered by a metric stream", labelNames, nil)
+		for _, s := range series {
+			values := make([]string, len(labelNames))
+			for i, l := range labelNames {
+				values[i] = s.labels[l]
+			}
+			m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, s.value, values...)
+			if err != nil {
+				level.Warn(e.logger).Log("msg", "invalid metric stream series", "name", name, "err", err)
+				continue
+			}
+			ch <- m
+		}
+	}
+}
+
+// promString converts a CloudWatch name to snake case, replacing the
+// characters which aren't valid in Prometheus metric names. For example,
+// NetworkIn becomes network_in and CPUUtilization becomes cpuutilization.
+func promString(s string) string {
+	var sb strings.Builder
+	var prev rune
+	for i, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+		prev = r
+	}
+	return sb.String()
+}
+
+// promLabelName replaces the characters of s which aren't valid in
+// Prometheus label names.
+func promLabelName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return r
+		}
+		return '_'
+	}, s)
+}
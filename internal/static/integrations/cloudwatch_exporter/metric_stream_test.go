@@ -0,0 +1,106 @@
+package cloudwatch_exporter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricStreamExporter(t *testing.T) {
+	fetch := func(ctx context.Context, region string, wait func(context.Context) error) ([]TaggedResource, error) {
+		if err := wait(ctx); err != nil {
+			return nil, err
+		}
+		return []TaggedResource{{
+			ARN:  "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc",
+			Tags: map[string]string{"Name": "web", "team": "payments"},
+		}}, nil
+	}
+	tagCache := NewTagCache(log.NewNopLogger(), TagCacheConfig{TTL: time.Hour, RequestsPerSecond: 10}, fetch)
+
+	e := NewMetricStreamExporter("cloudwatch", log.NewNopLogger(), MetricStreamConfig{
+		AccessKey:    "secret-key",
+		SeriesTTL:    time.Minute,
+		ExportedTags: TagsPerNamespace{"AWS/EC2": {"Name"}},
+	}, tagCache)
+	handler, err := e.MetricsHandler()
+	require.NoError(t, err)
+
+	datapoint := `{"metric_stream_name":"all","account_id":"123456789012","region":"us-east-1","namespace":"AWS/EC2","metric_name":"NetworkIn","dimensions":{"InstanceId":"i-0abc"},"timestamp":1611929698000,"value":{"max":10,"min":2,"sum":30,"count":5},"unit":"Bytes"}`
+	push := func(accessKey string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]any{
+			"requestId": "req-1",
+			"timestamp": time.Now().UnixMilli(),
+			"records":   []map[string]string{{"data": base64.StdEncoding.EncodeToString([]byte(datapoint + "\n"))}},
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/firehose", strings.NewReader(string(body)))
+		req.Header.Set("X-Amz-Firehose-Request-Id", "req-1")
+		req.Header.Set("X-Amz-Firehose-Access-Key", accessKey)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		bb, err := io.ReadAll(rec.Body)
+		require.NoError(t, err)
+		return string(bb)
+	}
+
+	rec := push("wrong-key")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = push("secret-key")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"requestId":"req-1"`)
+
+	// The tags are fetched in the background after the first lookup, so the
+	// series are enriched once a datapoint is delivered after the fetch.
+	labels := `account_id="123456789012",dimension_InstanceId="i-0abc",region="us-east-1",tag_Name="web"`
+	require.Eventually(t, func() bool {
+		return push("secret-key").Code == http.StatusOK &&
+			strings.Contains(scrape(), fmt.Sprintf("aws_ec2_network_in_sum{%s} 30", labels))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	metrics := scrape()
+	for _, expected := range []string{
+		fmt.Sprintf("aws_ec2_network_in_maximum{%s} 10", labels),
+		fmt.Sprintf("aws_ec2_network_in_minimum{%s} 2", labels),
+		fmt.Sprintf("aws_ec2_network_in_sample_count{%s} 5", labels),
+		fmt.Sprintf("aws_ec2_network_in_average{%s} 6", labels),
+	} {
+		require.Contains(t, metrics, expected)
+	}
+}
+
+func TestARNSuffixes(t *testing.T) {
+	require.Equal(t,
+		[]string{"loadbalancer/app/lb/50dc", "50dc", "lb/50dc", "app/lb/50dc"},
+		arnSuffixes("arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/lb/50dc"),
+	)
+	require.Equal(t, []string{"db:orders", "orders"}, arnSuffixes("arn:aws:rds:us-east-1:123:db:orders"))
+	require.Nil(t, arnSuffixes("not-an-arn"))
+}
+
+func TestPromString(t *testing.T) {
+	for input, expected := range map[string]string{
+		"NetworkIn":      "network_in",
+		"CPUUtilization": "cpuutilization",
+		"ApplicationELB": "application_elb",
+		"Disk.Read-Ops":  "disk_read_ops",
+	} {
+		require.Equal(t, expected, promString(input), input)
+	}
+}
@@ -0,0 +1,181 @@
+package cloudwatch_exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/go-kit/log"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// TaggedResource is an AWS resource and its tags.
+type TaggedResource struct {
+	ARN  string
+	Tags map[string]string
+}
+
+// FetchResourcesFunc returns the tagged resources of an AWS region. wait must
+// be called before each request to the AWS API, to rate limit the requests.
+type FetchResourcesFunc func(ctx context.Context, region string, wait func(context.Context) error) ([]TaggedResource, error)
+
+// TagCacheConfig configures a TagCache.
+type TagCacheConfig struct {
+	// TTL is how long the tags of a region are used before being fetched
+	// again.
+	TTL time.Duration
+	// RequestsPerSecond limits the requests to the AWS tagging API, for all
+	// the regions.
+	RequestsPerSecond float64
+}
+
+// TagCache caches the tags of the AWS resources of each region, so that
+// metrics can be enriched with the tags of their resource without calling
+// the AWS tagging API for each of them. The cache is shared by all the
+// namespaces, and the requests to the tagging API are rate limited.
+type TagCache struct {
+	logger  log.Logger
+	cfg     TagCacheConfig
+	fetch   FetchResourcesFunc
+	limiter *rate.Limiter
+
+	mut     sync.Mutex
+	regions map[string]*regionTags
+}
+
+// regionTags holds the tagged resources of a region, indexed by the suffixes
+// of their ARN.
+type regionTags struct {
+	bySuffix   map[string]*TaggedResource
+	fetched    time.Time
+	refreshing bool
+}
+
+// NewTagCache creates a TagCache fetching the tagged resources with fetch.
+func NewTagCache(logger log.Logger, cfg TagCacheConfig, fetch FetchResourcesFunc) *TagCache {
+	return &TagCache{
+		logger:  logger,
+		cfg:     cfg,
+		fetch:   fetch,
+		limiter: rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), 1),
+		regions: map[string]*regionTags{},
+	}
+}
+
+// Lookup returns the tags of the resource of region identified by one of
+// values, typically the dimension values of a metric. A resource is
+// identified by a value when its ARN ends with the value, after a / or a :.
+//
+// Lookup never blocks on the AWS API: when the tags of region are missing or
+// expired, they're fetched in the background with ctx, and the tags cached
+// so far are returned.
+func (c *TagCache) Lookup(ctx context.Context, region string, values []string) map[string]string {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	rt, ok := c.regions[region]
+	if !ok {
+		rt = &regionTags{}
+		c.regions[region] = rt
+	}
+	if !rt.refreshing && time.Since(rt.fetched) >= c.cfg.TTL {
+		rt.refreshing = true
+		go c.refresh(ctx, region)
+	}
+
+	for _, v := range values {
+		if res, ok := rt.bySuffix[v]; ok {
+			return res.Tags
+		}
+	}
+	return nil
+}
+
+func (c *TagCache) refresh(ctx context.Context, region string) {
+	resources, err := c.fetch(ctx, region, c.limiter.Wait)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	rt := c.regions[region]
+	rt.refreshing = false
+	if err != nil && ctx.Err() != nil {
+		return
+	} else if err != nil {
+		// The tags are fetched again at the next lookup after the TTL, and the
+		// previous tags are kept until then.
+		rt.fetched = time.Now()
+		level.Warn(c.logger).Log("msg", "failed to fetch the tags of AWS resources", "region", region, "err", err)
+		return
+	}
+
+	rt.bySuffix = make(map[string]*TaggedResource, len(resources))
+	for i := range resources {
+		res := &resources[i]
+		for _, suffix := range arnSuffixes(res.ARN) {
+			// The first resource wins when several resources share a suffix.
+			if _, ok := rt.bySuffix[suffix]; !ok {
+				rt.bySuffix[suffix] = res
+			}
+		}
+	}
+	rt.fetched = time.Now()
+}
+
+// arnSuffixes returns the suffixes of the resource part of arn which start
+// after a / or a :. For example, the suffixes of
+// arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/lb/50dc are
+// 50dc, lb/50dc, app/lb/50dc, and loadbalancer/app/lb/50dc.
+func arnSuffixes(arn string) []string {
+	// The resource part of an ARN comes after arn:partition:service:region:account:.
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return nil
+	}
+	resource := parts[5]
+
+	suffixes := []string{resource}
+	for i := len(resource) - 1; i >= 0; i-- {
+		if resource[i] == '/' || resource[i] == ':' {
+			suffixes = append(suffixes, resource[i+1:])
+		}
+	}
+	return suffixes
+}
+
+// FetchTaggedResources fetches the tagged resources of a region with the AWS
+// Resource Groups Tagging API, using the default AWS credentials.
+func FetchTaggedResources(ctx context.Context, region string, wait func(context.Context) error) ([]TaggedResource, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := resourcegroupstaggingapi.NewFromConfig(cfg)
+
+	var resources []TaggedResource
+	paginator := resourcegroupstaggingapi.NewGetResourcesPaginator(client, &resourcegroupstaggingapi.GetResourcesInput{})
+	for paginator.HasMorePages() {
+		if err := wait(ctx); err != nil {
+			return nil, err
+		}
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, mapping := range page.ResourceTagMappingList {
+			tags := make(map[string]string, len(mapping.Tags))
+			for _, t := range mapping.Tags {
+				tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+			}
+			resources = append(resources, TaggedResource{ARN: aws.ToString(mapping.ResourceARN), Tags: tags})
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,101 @@
+package gcp_exporter
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AlignedPeriod aligns the points of the metrics matching MetricPrefixes to Period with PerSeriesAligner, so Cloud
+// Monitoring returns a single point per period instead of the raw points.
+type AlignedPeriod struct {
+	MetricPrefixes   []string      `yaml:"metrics_prefixes"`
+	Period           time.Duration `yaml:"period"`
+	PerSeriesAligner string        `yaml:"per_series_aligner"`
+}
+
+// DefaultAlignedPeriod holds the default settings of an AlignedPeriod.
+var DefaultAlignedPeriod = AlignedPeriod{
+	PerSeriesAligner: "ALIGN_MEAN",
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for AlignedPeriod
+func (ap *AlignedPeriod) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*ap = DefaultAlignedPeriod
+
+	type plain AlignedPeriod
+	return unmarshal((*plain)(ap))
+}
+
+// validAligners are the aligners which keep the metric kind of the series. Aligners such as ALIGN_RATE turn counters
+// into gauges, which doesn't match the metric descriptors used to expose the series.
+var validAligners = []string{"ALIGN_MEAN", "ALIGN_MIN", "ALIGN_MAX", "ALIGN_SUM", "ALIGN_NEXT_OLDER"}
+
+func (ap AlignedPeriod) validate(requestInterval time.Duration) error {
+	if len(ap.MetricPrefixes) == 0 {
+		return fmt.Errorf("aligned_periods must have at least 1 metrics_prefixes")
+	}
+	// Cloud Monitoring doesn't accept alignment periods shorter than a minute.
+	if ap.Period < time.Minute || ap.Period%time.Second != 0 {
+		return fmt.Errorf("the period of the aligned_periods for %s must be a whole number of seconds of at least 1m", strings.Join(ap.MetricPrefixes, ","))
+	}
+	if ap.Period > requestInterval {
+		return fmt.Errorf("the period of the aligned_periods for %s must not be longer than request_interval", strings.Join(ap.MetricPrefixes, ","))
+	}
+	for _, aligner := range validAligners {
+		if ap.PerSeriesAligner == aligner {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is an invalid per_series_aligner. Valid options are %s", ap.PerSeriesAligner, strings.Join(validAligners, ","))
+}
+
+// metricTypeFilter matches the metric type in the filter of a timeSeries.list request.
+var metricTypeFilter = regexp.MustCompile(`metric\.type\s*=\s*"([^"]+)"`)
+
+// alignedPeriodTransport adds an aggregation to the timeSeries.list requests of the metrics matching an AlignedPeriod.
+// The stackdriver collectors don't support aggregations, so they're added to the requests they send.
+type alignedPeriodTransport struct {
+	next    http.RoundTripper
+	periods []AlignedPeriod
+}
+
+func (t *alignedPeriodTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !strings.HasSuffix(req.URL.Path, "/timeSeries") {
+		return t.next.RoundTrip(req)
+	}
+
+	query := req.URL.Query()
+	match := metricTypeFilter.FindStringSubmatch(query.Get("filter"))
+	if match == nil {
+		return t.next.RoundTrip(req)
+	}
+	ap, ok := t.match(match[1])
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	query.Set("aggregation.alignmentPeriod", fmt.Sprintf("%ds", int64(ap.Period/time.Second)))
+	query.Set("aggregation.perSeriesAligner", ap.PerSeriesAligner)
+	req = req.Clone(req.Context())
+	req.URL.RawQuery = query.Encode()
+	return t.next.RoundTrip(req)
+}
+
+// match returns the AlignedPeriod with the longest prefix of metricType.
+func (t *alignedPeriodTransport) match(metricType string) (AlignedPeriod, bool) {
+	var (
+		matched  AlignedPeriod
+		matchLen = -1
+	)
+	for _, ap := range t.periods {
+		for _, prefix := range ap.MetricPrefixes {
+			if strings.HasPrefix(metricType, prefix) && len(prefix) > matchLen {
+				matched, matchLen = ap, len(prefix)
+			}
+		}
+	}
+	return matched, matchLen >= 0
+}
@@ -0,0 +1,54 @@
+package gcp_exporter
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestAlignedPeriodTransport(t *testing.T) {
+	var lastQuery url.Values
+	transport := &alignedPeriodTransport{
+		next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			lastQuery = req.URL.Query()
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		periods: []AlignedPeriod{
+			{MetricPrefixes: []string{"compute.googleapis.com/"}, Period: 5 * time.Minute, PerSeriesAligner: "ALIGN_MEAN"},
+			{MetricPrefixes: []string{"compute.googleapis.com/instance/cpu"}, Period: time.Minute, PerSeriesAligner: "ALIGN_MAX"},
+		},
+	}
+
+	send := func(method, path, filter string) url.Values {
+		u := &url.URL{Scheme: "https", Host: "monitoring.googleapis.com", Path: path}
+		u.RawQuery = url.Values{"filter": []string{filter}}.Encode()
+		req, err := http.NewRequest(method, u.String(), nil)
+		require.NoError(t, err)
+		_, err = transport.RoundTrip(req)
+		require.NoError(t, err)
+		return lastQuery
+	}
+
+	// The longest matching prefix is used.
+	query := send(http.MethodGet, "/v3/projects/foo/timeSeries", `metric.type="compute.googleapis.com/instance/cpu/utilization" AND resource.labels.zone="us-east1-b"`)
+	require.Equal(t, "60s", query.Get("aggregation.alignmentPeriod"))
+	require.Equal(t, "ALIGN_MAX", query.Get("aggregation.perSeriesAligner"))
+
+	query = send(http.MethodGet, "/v3/projects/foo/timeSeries", `metric.type = "compute.googleapis.com/instance/disk/read_bytes_count"`)
+	require.Equal(t, "300s", query.Get("aggregation.alignmentPeriod"))
+	require.Equal(t, "ALIGN_MEAN", query.Get("aggregation.perSeriesAligner"))
+
+	// Metrics without an aligned period and other requests are sent as is.
+	query = send(http.MethodGet, "/v3/projects/foo/timeSeries", `metric.type="pubsub.googleapis.com/subscription/num_undelivered_messages"`)
+	require.Empty(t, query.Get("aggregation.alignmentPeriod"))
+
+	query = send(http.MethodGet, "/v3/projects/foo/metricDescriptors", `metric.type = starts_with("compute.googleapis.com/")`)
+	require.Empty(t, query.Get("aggregation.alignmentPeriod"))
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -17,6 +18,8 @@ import (
 	"github.com/prometheus-community/stackdriver_exporter/delta"
 	"github.com/prometheus-community/stackdriver_exporter/utils"
 	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/monitoring/v3"
 	"google.golang.org/api/option"
@@ -34,14 +37,33 @@ func init() {
 }
 
 type Config struct {
-	ProjectIDs            []string      `yaml:"project_ids"`
-	MetricPrefixes        []string      `yaml:"metrics_prefixes"`
-	ExtraFilters          []string      `yaml:"extra_filters"`
-	RequestInterval       time.Duration `yaml:"request_interval"`
-	RequestOffset         time.Duration `yaml:"request_offset"`
-	IngestDelay           bool          `yaml:"ingest_delay"`
-	DropDelegatedProjects bool          `yaml:"drop_delegated_projects"`
-	ClientTimeout         time.Duration `yaml:"gcp_client_timeout"`
+	ProjectIDs            []string             `yaml:"project_ids"`
+	MetricPrefixes        []string             `yaml:"metrics_prefixes"`
+	ExtraFilters          []string             `yaml:"extra_filters"`
+	RequestInterval       time.Duration        `yaml:"request_interval"`
+	RequestOffset         time.Duration        `yaml:"request_offset"`
+	IngestDelay           bool                 `yaml:"ingest_delay"`
+	DropDelegatedProjects bool                 `yaml:"drop_delegated_projects"`
+	ClientTimeout         time.Duration        `yaml:"gcp_client_timeout"`
+	ProjectCredentials    []ProjectCredentials `yaml:"project_credentials"`
+	AlignedPeriods        []AlignedPeriod      `yaml:"aligned_periods"`
+}
+
+// ProjectCredentials are the credentials used for the requests of a set of projects instead of the application
+// default credentials.
+type ProjectCredentials struct {
+	ProjectIDs []string `yaml:"project_ids"`
+	// Only one of CredentialsFile and Credentials can be set, both hold a service account key in JSON format.
+	CredentialsFile string             `yaml:"credentials_file"`
+	Credentials     config_util.Secret `yaml:"credentials"`
+}
+
+// load returns the service account key of the credentials.
+func (pc ProjectCredentials) load() ([]byte, error) {
+	if pc.CredentialsFile != "" {
+		return os.ReadFile(pc.CredentialsFile)
+	}
+	return []byte(pc.Credentials), nil
 }
 
 var DefaultConfig = Config{
@@ -80,17 +102,43 @@ func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error)
 		return nil, err
 	}
 
-	svc, err := createMonitoringService(context.Background(), c.ClientTimeout)
-	if err != nil {
-		return nil, err
+	// Projects without credentials share a monitoring service using the application default credentials, which is only
+	// created when needed so that it isn't required when all the projects have their own credentials.
+	projectServices := map[string]*monitoring.Service{}
+	for _, pc := range c.ProjectCredentials {
+		credentials, err := pc.load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials of projects %s: %w", strings.Join(pc.ProjectIDs, ","), err)
+		}
+		svc, err := createMonitoringService(context.Background(), c.ClientTimeout, credentials, c.AlignedPeriods)
+		if err != nil {
+			return nil, err
+		}
+		for _, projectID := range pc.ProjectIDs {
+			projectServices[projectID] = svc
+		}
 	}
 
+	var defaultService *monitoring.Service
 	var gcpCollectors []prometheus.Collector
 	var counterStores []*SelfPruningDeltaStore[collectors.ConstMetric]
 	var histogramStores []*SelfPruningDeltaStore[collectors.HistogramMetric]
 	for _, projectID := range c.ProjectIDs {
 		counterStore := NewSelfPruningDeltaStore[collectors.ConstMetric](l, delta.NewInMemoryCounterStore(l, 30*time.Minute))
 		histogramStore := NewSelfPruningDeltaStore[collectors.HistogramMetric](l, delta.NewInMemoryHistogramStore(l, 30*time.Minute))
+
+		svc, ok := projectServices[projectID]
+		if !ok {
+			if defaultService == nil {
+				var err error
+				defaultService, err = createMonitoringService(context.Background(), c.ClientTimeout, nil, c.AlignedPeriods)
+				if err != nil {
+					return nil, err
+				}
+			}
+			svc = defaultService
+		}
+
 		monitoringCollector, err := collectors.NewMonitoringCollector(
 			projectID,
 			svc,
@@ -187,16 +235,62 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	projectsWithCredentials := map[string]struct{}{}
+	for _, pc := range c.ProjectCredentials {
+		if len(pc.ProjectIDs) == 0 {
+			configErrors.Add(errors.New("project_credentials must have at least 1 project_ids"))
+		}
+		if (pc.CredentialsFile == "") == (pc.Credentials == "") {
+			configErrors.Add(fmt.Errorf("exactly one of credentials_file or credentials must be set for the project_credentials of %s", strings.Join(pc.ProjectIDs, ",")))
+		}
+		for _, projectID := range pc.ProjectIDs {
+			if _, exists := projectsWithCredentials[projectID]; exists {
+				configErrors.Add(fmt.Errorf("project %s has more than one project_credentials", projectID))
+			}
+			projectsWithCredentials[projectID] = struct{}{}
+
+			found := false
+			for _, id := range c.ProjectIDs {
+				if id == projectID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				configErrors.Add(fmt.Errorf("project %s has project_credentials but is not in project_ids", projectID))
+			}
+		}
+	}
+
+	for _, ap := range c.AlignedPeriods {
+		configErrors.Add(ap.validate(c.RequestInterval))
+	}
+
 	return configErrors.Err()
 }
 
-func createMonitoringService(ctx context.Context, httpTimeout time.Duration) (*monitoring.Service, error) {
-	googleClient, err := google.DefaultClient(ctx, monitoring.MonitoringReadScope)
-	if err != nil {
-		return nil, fmt.Errorf("error creating Google client: %v", err)
+// createMonitoringService creates a monitoring service authenticated with the credentials service account key, or
+// with the application default credentials when credentials is empty.
+func createMonitoringService(ctx context.Context, httpTimeout time.Duration, credentials []byte, alignedPeriods []AlignedPeriod) (*monitoring.Service, error) {
+	var googleClient *http.Client
+	if len(credentials) > 0 {
+		creds, err := google.CredentialsFromJSON(ctx, credentials, monitoring.MonitoringReadScope)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Google credentials: %v", err)
+		}
+		googleClient = oauth2.NewClient(ctx, creds.TokenSource)
+	} else {
+		var err error
+		googleClient, err = google.DefaultClient(ctx, monitoring.MonitoringReadScope)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Google client: %v", err)
+		}
 	}
 
 	googleClient.Timeout = httpTimeout
+	if len(alignedPeriods) > 0 {
+		googleClient.Transport = &alignedPeriodTransport{next: googleClient.Transport, periods: alignedPeriods}
+	}
 	googleClient.Transport = rehttp.NewTransport(
 		googleClient.Transport,
 		rehttp.RetryAll(
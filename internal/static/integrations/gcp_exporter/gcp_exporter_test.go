@@ -2,6 +2,7 @@ package gcp_exporter_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -115,6 +116,77 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			shouldError: false,
 		},
+		{
+			name: "project credentials for a project in ProjectIDs",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.ProjectCredentials = []gcp_exporter.ProjectCredentials{{ProjectIDs: []string{"project1"}, CredentialsFile: "/etc/gcp/project1.json"}}
+				return config
+			},
+			shouldError: false,
+		},
+		{
+			name: "project credentials for a project not in ProjectIDs",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.ProjectCredentials = []gcp_exporter.ProjectCredentials{{ProjectIDs: []string{"project2"}, CredentialsFile: "/etc/gcp/project2.json"}}
+				return config
+			},
+			shouldError: true,
+		},
+		{
+			name: "project credentials with both a file and inline credentials",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.ProjectCredentials = []gcp_exporter.ProjectCredentials{{ProjectIDs: []string{"project1"}, CredentialsFile: "/etc/gcp/project1.json", Credentials: "{}"}}
+				return config
+			},
+			shouldError: true,
+		},
+		{
+			name: "project with two project credentials",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.ProjectCredentials = []gcp_exporter.ProjectCredentials{
+					{ProjectIDs: []string{"project1"}, CredentialsFile: "/etc/gcp/project1.json"},
+					{ProjectIDs: []string{"project1"}, Credentials: "{}"},
+				}
+				return config
+			},
+			shouldError: true,
+		},
+		{
+			name: "aligned period",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.RequestInterval = 5 * time.Minute
+				config.AlignedPeriods = []gcp_exporter.AlignedPeriod{{MetricPrefixes: []string{"prefix1"}, Period: 5 * time.Minute, PerSeriesAligner: "ALIGN_MEAN"}}
+				return config
+			},
+			shouldError: false,
+		},
+		{
+			name: "aligned period longer than RequestInterval",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.RequestInterval = 5 * time.Minute
+				config.AlignedPeriods = []gcp_exporter.AlignedPeriod{{MetricPrefixes: []string{"prefix1"}, Period: 10 * time.Minute, PerSeriesAligner: "ALIGN_MEAN"}}
+				return config
+			},
+			shouldError: true,
+		},
+		{
+			name: "aligned period shorter than a minute",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.RequestInterval = 5 * time.Minute
+				config.AlignedPeriods = []gcp_exporter.AlignedPeriod{{MetricPrefixes: []string{"prefix1"}, Period: 30 * time.Second, PerSeriesAligner: "ALIGN_MEAN"}}
+				return config
+			},
+			shouldError: true,
+		},
+		{
+			name: "aligned period with an aligner changing the metric kind",
+			configModifier: func(config gcp_exporter.Config) gcp_exporter.Config {
+				config.RequestInterval = 5 * time.Minute
+				config.AlignedPeriods = []gcp_exporter.AlignedPeriod{{MetricPrefixes: []string{"prefix1"}, Period: 5 * time.Minute, PerSeriesAligner: "ALIGN_RATE"}}
+				return config
+			},
+			shouldError: true,
+		},
 	}
 	for _, tt := range tests {
 		testName := tt.name
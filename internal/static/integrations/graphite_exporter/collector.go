@@ -0,0 +1,222 @@
+package graphite_exporter //nolint:golint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/statsd_exporter/pkg/mapper"
+)
+
+var invalidMetricChars = regexp.MustCompile("[^a-zA-Z0-9_:]")
+
+// sample is the last value received for a series.
+type sample struct {
+	name       string
+	labels     prometheus.Labels
+	value      float64
+	receivedAt time.Time
+}
+
+// collector holds the samples received from Graphite clients and exposes
+// them as Prometheus gauges.
+type collector struct {
+	mapper      *mapper.MetricMapper
+	strictMatch bool
+	expiry      time.Duration
+	now         func() time.Time
+
+	mut     sync.Mutex
+	samples map[string]*sample
+
+	lastProcessed      prometheus.Gauge
+	invalidLines       prometheus.Counter
+	invalidMessages    prometheus.Counter
+	tagParseFailures   prometheus.Counter
+	sampleExpiryMetric prometheus.Gauge
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+func newCollector(m *mapper.MetricMapper, strictMatch bool, expiry time.Duration, reg prometheus.Registerer) (*collector, error) {
+	c := &collector{
+		mapper:      m,
+		strictMatch: strictMatch,
+		expiry:      expiry,
+		now:         time.Now,
+		samples:     make(map[string]*sample),
+
+		lastProcessed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "graphite_last_processed_timestamp_seconds",
+			Help: "Unix timestamp of the last processed Graphite metric.",
+		}),
+		invalidLines: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "graphite_invalid_lines_total",
+			Help: "Total number of plaintext lines which couldn't be parsed.",
+		}),
+		invalidMessages: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "graphite_invalid_pickle_messages_total",
+			Help: "Total number of pickle messages which couldn't be decoded.",
+		}),
+		tagParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "graphite_tag_parse_failures_total",
+			Help: "Total number of Graphite metrics with tags which couldn't be parsed.",
+		}),
+		sampleExpiryMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "graphite_sample_expiry_seconds",
+			Help: "How long in seconds a metric sample is valid for.",
+		}),
+	}
+	c.sampleExpiryMetric.Set(expiry.Seconds())
+
+	for _, m := range []prometheus.Collector{c.lastProcessed, c.invalidLines, c.invalidMessages, c.tagParseFailures, c.sampleExpiryMetric} {
+		if err := reg.Register(m); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// processLine processes a line of the plaintext protocol, in the
+// "<path> <value> <timestamp>" format.
+func (c *collector) processLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	parts := strings.Fields(line)
+	if len(parts) != 3 {
+		c.invalidLines.Inc()
+		return
+	}
+
+	value, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		c.invalidLines.Inc()
+		return
+	}
+	timestamp, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		c.invalidLines.Inc()
+		return
+	}
+
+	c.processSample(parts[0], value, timestamp)
+}
+
+// processSample stores the value of a Graphite metric. The timestamp is only
+// used to track the last processed metric; samples expire based on when they
+// were received.
+func (c *collector) processSample(path string, value float64, timestamp float64) {
+	originalName, tags, err := parseTags(path)
+	if err != nil {
+		c.tagParseFailures.Inc()
+		return
+	}
+
+	mapping, mappingLabels, mappingPresent := c.mapper.GetMapping(originalName, mapper.MetricTypeGauge)
+	if mappingPresent && mapping.Action == mapper.ActionTypeDrop {
+		return
+	}
+	if !mappingPresent && c.strictMatch {
+		return
+	}
+
+	var (
+		name   string
+		labels = make(prometheus.Labels, len(tags)+len(mappingLabels))
+	)
+	if mappingPresent {
+		name = invalidMetricChars.ReplaceAllString(mapping.Name, "_")
+	} else {
+		name = invalidMetricChars.ReplaceAllString(originalName, "_")
+	}
+	for k, v := range tags {
+		labels[invalidMetricChars.ReplaceAllString(k, "_")] = v
+	}
+	// Labels from the mapping take precedence over the tags of the metric.
+	for k, v := range mappingLabels {
+		labels[k] = v
+	}
+
+	c.lastProcessed.Set(timestamp)
+
+	key := seriesKey(name, labels)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.samples[key] = &sample{
+		name:       name,
+		labels:     labels,
+		value:      value,
+		receivedAt: c.now(),
+	}
+}
+
+// parseTags splits a path in the "name;tag1=value1;tag2=value2" format of
+// Graphite tagged series into the name and the tags.
+func parseTags(path string) (string, map[string]string, error) {
+	name, rest, found := strings.Cut(path, ";")
+	if !found {
+		return name, nil, nil
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(rest, ";") {
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || k == "" || v == "" {
+			return "", nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		tags[k] = v
+	}
+	return name, tags, nil
+}
+
+func seriesKey(name string, labels prometheus.Labels) string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, k := range names {
+		sb.WriteByte(0xff)
+		sb.WriteString(k)
+		sb.WriteByte(0xff)
+		sb.WriteString(labels[k])
+	}
+	return sb.String()
+}
+
+// Describe implements prometheus.Collector. The metrics depend on the received
+// data, so the collector is unchecked.
+func (c *collector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector. Expired samples are removed.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	ageLimit := c.now().Add(-c.expiry)
+	for key, s := range c.samples {
+		if s.receivedAt.Before(ageLimit) {
+			delete(c.samples, key)
+			continue
+		}
+		desc := prometheus.NewDesc(s.name, fmt.Sprintf("Graphite metric %s", s.name), nil, s.labels)
+		m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, s.value)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(desc, err)
+			continue
+		}
+		ch <- m
+	}
+}
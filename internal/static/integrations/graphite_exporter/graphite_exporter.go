@@ -0,0 +1,336 @@
+// Package graphite_exporter implements a Graphite bridge which exposes the
+// metrics it receives as Prometheus metrics, like
+// https://github.com/prometheus/graphite_exporter.
+package graphite_exporter //nolint:golint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/alloy/internal/build"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/statsd_exporter/pkg/mapper"
+	"github.com/prometheus/statsd_exporter/pkg/mappercache/lru"
+	"github.com/prometheus/statsd_exporter/pkg/mappercache/randomreplacement"
+	"gopkg.in/yaml.v2"
+)
+
+// maxPickleSize is the maximum size of a pickle message, to avoid allocating
+// huge buffers when receiving invalid data.
+const maxPickleSize = 16 << 20
+
+// DefaultConfig holds the default settings for the graphite_exporter
+// integration.
+var DefaultConfig = Config{
+	ListenTCP: ":9109",
+	ListenUDP: ":9109",
+
+	SampleExpiry: 5 * time.Minute,
+	CacheSize:    1000,
+	CacheType:    "lru",
+}
+
+// Config controls the graphite_exporter integration.
+type Config struct {
+	ListenTCP     string `yaml:"listen_tcp,omitempty"`
+	ListenUDP     string `yaml:"listen_udp,omitempty"`
+	ListenPickle  string `yaml:"listen_pickle,omitempty"`
+	MappingConfig any    `yaml:"mapping_config,omitempty"`
+	StrictMatch   bool   `yaml:"strict_match,omitempty"`
+
+	SampleExpiry time.Duration `yaml:"sample_expiry,omitempty"`
+	CacheSize    int           `yaml:"cache_size,omitempty"`
+	CacheType    string        `yaml:"cache_type,omitempty"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for Config.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultConfig
+
+	type plain Config
+	return unmarshal((*plain)(c))
+}
+
+// Name returns the name of the integration that this config represents.
+func (c *Config) Name() string {
+	return "graphite_exporter"
+}
+
+// InstanceKey returns the hostname:port of the agent.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	return agentKey, nil
+}
+
+// NewIntegration converts this config into an instance of an integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// Exporter defines the graphite_exporter integration.
+type Exporter struct {
+	cfg       *Config
+	reg       *prometheus.Registry
+	collector *collector
+	log       log.Logger
+}
+
+// New creates a new graphite_exporter integration. The integration listens for
+// Graphite metrics and exposes them as Prometheus metrics.
+func New(log log.Logger, c *Config) (integrations.Integration, error) {
+	if c.ListenTCP == "" && c.ListenUDP == "" && c.ListenPickle == "" {
+		return nil, fmt.Errorf("at least one of TCP/UDP/pickle listeners must be used")
+	}
+
+	reg := prometheus.NewRegistry()
+
+	mappingsCount := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "graphite_exporter_loaded_mappings",
+		Help: "The current number of configured metric mappings.",
+	})
+	if err := reg.Register(mappingsCount); err != nil {
+		return nil, err
+	}
+
+	graphiteMapper := &mapper.MetricMapper{
+		Registerer:    reg,
+		MappingsCount: mappingsCount,
+		Logger:        log,
+	}
+
+	if c.MappingConfig != nil {
+		cfgBytes, err := yaml.Marshal(c.MappingConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize mapping config: %w", err)
+		}
+
+		err = graphiteMapper.InitFromYAMLString(string(cfgBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mapping config: %w", err)
+		}
+	}
+
+	var (
+		cache mapper.MetricMapperCache
+		err   error
+	)
+	if c.CacheSize != 0 {
+		switch c.CacheType {
+		case "lru":
+			cache, err = lru.NewMetricMapperLRUCache(graphiteMapper.Registerer, c.CacheSize)
+		case "random":
+			cache, err = randomreplacement.NewMetricMapperRRCache(graphiteMapper.Registerer, c.CacheSize)
+		default:
+			err = fmt.Errorf("unsupported cache type %q", c.CacheType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cache != nil {
+		graphiteMapper.UseCache(cache)
+	}
+
+	col, err := newCollector(graphiteMapper, c.StrictMatch, c.SampleExpiry, reg)
+	if err != nil {
+		return nil, err
+	}
+	if err := reg.Register(col); err != nil {
+		return nil, err
+	}
+
+	if err := reg.Register(build.NewCollector("graphite_exporter")); err != nil {
+		return nil, fmt.Errorf("couldn't register version metrics: %w", err)
+	}
+
+	return &Exporter{
+		cfg:       c,
+		reg:       reg,
+		collector: col,
+		log:       log,
+	}, nil
+}
+
+// MetricsHandler returns the HTTP handler for the integration.
+func (e *Exporter) MetricsHandler() (http.Handler, error) {
+	return promhttp.HandlerFor(e.reg, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	}), nil
+}
+
+// ScrapeConfigs satisfies Integration.ScrapeConfigs.
+func (e *Exporter) ScrapeConfigs() []config.ScrapeConfig {
+	return []config.ScrapeConfig{{JobName: e.cfg.Name(), MetricsPath: "/metrics"}}
+}
+
+// Run satisfies Run.
+func (e *Exporter) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// Closing the listeners stops the goroutines serving them.
+	var closers []io.Closer
+	defer func() {
+		for _, c := range closers {
+			if err := c.Close(); err != nil {
+				level.Warn(e.log).Log("msg", "failed to close listener", "err", err)
+			}
+		}
+	}()
+
+	if e.cfg.ListenTCP != "" {
+		l, err := net.Listen("tcp", e.cfg.ListenTCP)
+		if err != nil {
+			return fmt.Errorf("failed to start TCP listener: %w", err)
+		}
+		closers = append(closers, l)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.serveTCP(l, e.handlePlaintextConn)
+		}()
+	}
+
+	if e.cfg.ListenUDP != "" {
+		conn, err := net.ListenPacket("udp", e.cfg.ListenUDP)
+		if err != nil {
+			return fmt.Errorf("failed to start UDP listener: %w", err)
+		}
+		closers = append(closers, conn)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.serveUDP(conn)
+		}()
+	}
+
+	if e.cfg.ListenPickle != "" {
+		l, err := net.Listen("tcp", e.cfg.ListenPickle)
+		if err != nil {
+			return fmt.Errorf("failed to start pickle listener: %w", err)
+		}
+		closers = append(closers, l)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.serveTCP(l, e.handlePickleConn)
+		}()
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// serveTCP accepts connections on l until it's closed.
+func (e *Exporter) serveTCP(l net.Listener, handle func(net.Conn)) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	var (
+		mut   sync.Mutex
+		conns = make(map[net.Conn]struct{})
+	)
+	defer func() {
+		mut.Lock()
+		defer mut.Unlock()
+		for c := range conns {
+			c.Close()
+		}
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			level.Warn(e.log).Log("msg", "failed to accept connection", "err", err)
+			continue
+		}
+
+		mut.Lock()
+		conns[conn] = struct{}{}
+		mut.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mut.Lock()
+				delete(conns, conn)
+				mut.Unlock()
+				conn.Close()
+			}()
+			handle(conn)
+		}()
+	}
+}
+
+func (e *Exporter) handlePlaintextConn(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		e.collector.processLine(scanner.Text())
+	}
+}
+
+func (e *Exporter) handlePickleConn(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size > maxPickleSize {
+			level.Warn(e.log).Log("msg", "pickle message exceeds the maximum size", "size", size, "remote", conn.RemoteAddr())
+			return
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+
+		samples, err := decodePickle(payload)
+		if err != nil {
+			level.Debug(e.log).Log("msg", "failed to decode pickle message", "remote", conn.RemoteAddr(), "err", err)
+			e.collector.invalidMessages.Inc()
+			continue
+		}
+		for _, s := range samples {
+			e.collector.processSample(s.path, s.value, s.timestamp)
+		}
+	}
+}
+
+func (e *Exporter) serveUDP(conn net.PacketConn) {
+	buf := make([]byte, 65535)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			level.Warn(e.log).Log("msg", "failed to read UDP packet", "err", err)
+			continue
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(buf[:n]))
+		for scanner.Scan() {
+			e.collector.processLine(scanner.Text())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package graphite_exporter //nolint:golint
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/statsd_exporter/pkg/mapper"
+	"github.com/stretchr/testify/require"
+)
+
+const testMappingConfig = `
+mappings:
+- match: "*.requests.*"
+  name: requests_total
+  labels:
+    service: $1
+    status: $2
+- match: "debug.*"
+  action: drop
+  name: dropped
+`
+
+func newTestCollector(t *testing.T, strictMatch bool) *collector {
+	t.Helper()
+
+	m := &mapper.MetricMapper{}
+	require.NoError(t, m.InitFromYAMLString(testMappingConfig))
+
+	c, err := newCollector(m, strictMatch, 5*time.Minute, prometheus.NewRegistry())
+	require.NoError(t, err)
+	return c
+}
+
+func TestCollector(t *testing.T) {
+	c := newTestCollector(t, false)
+
+	c.processLine("api.requests.200 10 1700000000")
+	c.processLine("api.requests.500 2 1700000000")
+	c.processLine("web.requests.200 5 1700000000")
+	c.processLine("host-1.cpu.user 0.5 1700000000")
+	c.processLine("memory.used;host=a;region=eu 1024 1700000000")
+	c.processLine("debug.something 1 1700000000")
+
+	// The last value of a series wins.
+	c.processLine("api.requests.200 12 1700000010")
+
+	expect := `
+# HELP host_1_cpu_user Graphite metric host_1_cpu_user
+# TYPE host_1_cpu_user gauge
+host_1_cpu_user 0.5
+# HELP memory_used Graphite metric memory_used
+# TYPE memory_used gauge
+memory_used{host="a",region="eu"} 1024
+# HELP requests_total Graphite metric requests_total
+# TYPE requests_total gauge
+requests_total{service="api",status="200"} 12
+requests_total{service="api",status="500"} 2
+requests_total{service="web",status="200"} 5
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expect)))
+}
+
+func TestCollector_StrictMatch(t *testing.T) {
+	c := newTestCollector(t, true)
+
+	c.processLine("api.requests.200 10 1700000000")
+	c.processLine("host-1.cpu.user 0.5 1700000000")
+
+	expect := `
+# HELP requests_total Graphite metric requests_total
+# TYPE requests_total gauge
+requests_total{service="api",status="200"} 10
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expect)))
+}
+
+func TestCollector_Expiry(t *testing.T) {
+	c := newTestCollector(t, false)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.processLine("old.metric 1 1700000000")
+
+	now = now.Add(4 * time.Minute)
+	c.processLine("new.metric 2 1700000240")
+
+	now = now.Add(2 * time.Minute)
+
+	expect := `
+# HELP new_metric Graphite metric new_metric
+# TYPE new_metric gauge
+new_metric 2
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expect)))
+}
+
+func TestCollector_InvalidLines(t *testing.T) {
+	c := newTestCollector(t, false)
+
+	c.processLine("missing.timestamp 1")
+	c.processLine("invalid.value abc 1700000000")
+	c.processLine("invalid.tags;host 1 1700000000")
+	c.processLine("")
+
+	require.Equal(t, 2.0, testutil.ToFloat64(c.invalidLines))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.tagParseFailures))
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader("")))
+}
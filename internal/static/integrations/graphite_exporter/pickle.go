@@ -0,0 +1,518 @@
+package graphite_exporter //nolint:golint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// pickleSample is a metric decoded from a pickle message.
+type pickleSample struct {
+	path      string
+	timestamp float64
+	value     float64
+}
+
+// decodePickle decodes a message of the Graphite pickle protocol, which is a
+// pickled list of (path, (timestamp, value)) tuples.
+//
+// Only the subset of the pickle format used to serialize lists, tuples,
+// strings, and numbers is supported, which is enough to decode the messages
+// sent by carbon-relay and the Graphite client libraries.
+func decodePickle(data []byte) ([]pickleSample, error) {
+	v, err := newUnpickler(data).load()
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %T", v)
+	}
+
+	samples := make([]pickleSample, 0, len(list))
+	for _, item := range list {
+		s, err := toPickleSample(item)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, nil
+}
+
+func toPickleSample(item any) (pickleSample, error) {
+	tuple, ok := item.([]any)
+	if !ok || len(tuple) != 2 {
+		return pickleSample{}, fmt.Errorf("expected a (path, (timestamp, value)) tuple, got %v", item)
+	}
+	path, ok := tuple[0].(string)
+	if !ok {
+		return pickleSample{}, fmt.Errorf("expected the path to be a string, got %T", tuple[0])
+	}
+	datapoint, ok := tuple[1].([]any)
+	if !ok || len(datapoint) != 2 {
+		return pickleSample{}, fmt.Errorf("expected a (timestamp, value) tuple for %s, got %v", path, tuple[1])
+	}
+	timestamp, err := toFloat(datapoint[0])
+	if err != nil {
+		return pickleSample{}, fmt.Errorf("invalid timestamp for %s: %w", path, err)
+	}
+	value, err := toFloat(datapoint[1])
+	if err != nil {
+		return pickleSample{}, fmt.Errorf("invalid value for %s: %w", path, err)
+	}
+	return pickleSample{path: path, timestamp: timestamp, value: value}, nil
+}
+
+func toFloat(v any) (float64, error) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
+
+// Pickle opcodes.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opPop            = '0'
+	opFloat          = 'F'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opLong           = 'L'
+	opBinInt2        = 'M'
+	opNone           = 'N'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opAppend         = 'a'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opList           = 'l'
+	opEmptyList      = ']'
+	opAppends        = 'e'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opTuple          = 't'
+	opEmptyTuple     = ')'
+	opBinFloat       = 'G'
+	opBinBytes       = 'B'
+	opShortBinBytes  = 'C'
+
+	opProto           = 0x80
+	opTuple1          = 0x85
+	opTuple2          = 0x86
+	opTuple3          = 0x87
+	opNewTrue         = 0x88
+	opNewFalse        = 0x89
+	opLong1           = 0x8a
+	opShortBinUnicode = 0x8c
+	opMemoize         = 0x94
+	opFrame           = 0x95
+)
+
+// mark is pushed on the stack by the MARK opcode.
+type mark struct{}
+
+var errTruncated = errors.New("pickle data is truncated")
+
+type unpickler struct {
+	r     *bytes.Reader
+	stack []any
+	memo  map[int]any
+}
+
+func newUnpickler(data []byte) *unpickler {
+	return &unpickler{
+		r:    bytes.NewReader(data),
+		memo: make(map[int]any),
+	}
+}
+
+func (u *unpickler) load() (any, error) {
+	for {
+		op, err := u.r.ReadByte()
+		if err != nil {
+			return nil, errTruncated
+		}
+
+		switch op {
+		case opStop:
+			return u.pop()
+
+		case opProto:
+			if _, err := u.readN(1); err != nil {
+				return nil, err
+			}
+		case opFrame:
+			if _, err := u.readN(8); err != nil {
+				return nil, err
+			}
+
+		case opMark:
+			u.push(mark{})
+		case opPop:
+			if _, err := u.pop(); err != nil {
+				return nil, err
+			}
+		case opNone:
+			u.push(nil)
+		case opNewTrue:
+			u.push(true)
+		case opNewFalse:
+			u.push(false)
+
+		case opInt:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			// Protocol 0 encodes booleans as "01" and "00".
+			switch line {
+			case "01":
+				u.push(true)
+			case "00":
+				u.push(false)
+			default:
+				i, err := strconv.ParseInt(line, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				u.push(i)
+			}
+		case opLong:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			i, ok := new(big.Int).SetString(strings.TrimSuffix(line, "L"), 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid long %q", line)
+			}
+			u.pushInt(i)
+		case opBinInt:
+			b, err := u.readN(4)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(int32(binary.LittleEndian.Uint32(b))))
+		case opBinInt1:
+			b, err := u.readN(1)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(b[0]))
+		case opBinInt2:
+			b, err := u.readN(2)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(binary.LittleEndian.Uint16(b)))
+		case opLong1:
+			n, err := u.readN(1)
+			if err != nil {
+				return nil, err
+			}
+			b, err := u.readN(int(n[0]))
+			if err != nil {
+				return nil, err
+			}
+			u.pushInt(decodeLong(b))
+
+		case opFloat:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			f, err := strconv.ParseFloat(line, 64)
+			if err != nil {
+				return nil, err
+			}
+			u.push(f)
+		case opBinFloat:
+			b, err := u.readN(8)
+			if err != nil {
+				return nil, err
+			}
+			u.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+
+		case opString:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			s, err := unquote(line)
+			if err != nil {
+				return nil, err
+			}
+			u.push(s)
+		case opUnicode:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			u.push(line)
+		case opShortBinString, opShortBinBytes, opShortBinUnicode:
+			n, err := u.readN(1)
+			if err != nil {
+				return nil, err
+			}
+			b, err := u.readN(int(n[0]))
+			if err != nil {
+				return nil, err
+			}
+			u.push(string(b))
+		case opBinString, opBinBytes, opBinUnicode:
+			n, err := u.readN(4)
+			if err != nil {
+				return nil, err
+			}
+			b, err := u.readN(int(binary.LittleEndian.Uint32(n)))
+			if err != nil {
+				return nil, err
+			}
+			u.push(string(b))
+
+		case opEmptyList:
+			u.push([]any{})
+		case opList:
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			u.push(items)
+		case opAppend:
+			v, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			if err := u.appendTop(v); err != nil {
+				return nil, err
+			}
+		case opAppends:
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			if err := u.appendTop(items...); err != nil {
+				return nil, err
+			}
+
+		case opEmptyTuple:
+			u.push([]any{})
+		case opTuple:
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			u.push(items)
+		case opTuple1, opTuple2, opTuple3:
+			n := int(op-opTuple1) + 1
+			if len(u.stack) < n {
+				return nil, fmt.Errorf("stack underflow")
+			}
+			items := append([]any(nil), u.stack[len(u.stack)-n:]...)
+			u.stack = u.stack[:len(u.stack)-n]
+			u.push(items)
+
+		case opPut:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.memoize(idx); err != nil {
+				return nil, err
+			}
+		case opBinPut:
+			b, err := u.readN(1)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.memoize(int(b[0])); err != nil {
+				return nil, err
+			}
+		case opLongBinPut:
+			b, err := u.readN(4)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.memoize(int(binary.LittleEndian.Uint32(b))); err != nil {
+				return nil, err
+			}
+		case opMemoize:
+			if err := u.memoize(len(u.memo)); err != nil {
+				return nil, err
+			}
+		case opGet:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.get(idx); err != nil {
+				return nil, err
+			}
+		case opBinGet:
+			b, err := u.readN(1)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.get(int(b[0])); err != nil {
+				return nil, err
+			}
+		case opLongBinGet:
+			b, err := u.readN(4)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.get(int(binary.LittleEndian.Uint32(b))); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported pickle opcode 0x%02x", op)
+		}
+	}
+}
+
+func (u *unpickler) readN(n int) ([]byte, error) {
+	if n > u.r.Len() {
+		return nil, errTruncated
+	}
+	b := make([]byte, n)
+	_, _ = u.r.Read(b)
+	return b, nil
+}
+
+func (u *unpickler) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := u.r.ReadByte()
+		if err != nil {
+			return "", errTruncated
+		}
+		if c == '\n' {
+			return sb.String(), nil
+		}
+		sb.WriteByte(c)
+	}
+}
+
+func (u *unpickler) push(v any) {
+	u.stack = append(u.stack, v)
+}
+
+// pushInt pushes i as an int64 if it fits, and as a *big.Int otherwise.
+func (u *unpickler) pushInt(i *big.Int) {
+	if i.IsInt64() {
+		u.push(i.Int64())
+		return
+	}
+	u.push(i)
+}
+
+func (u *unpickler) pop() (any, error) {
+	if len(u.stack) == 0 {
+		return nil, fmt.Errorf("stack underflow")
+	}
+	v := u.stack[len(u.stack)-1]
+	u.stack = u.stack[:len(u.stack)-1]
+	return v, nil
+}
+
+// popMark pops the items pushed since the last mark, and the mark itself.
+func (u *unpickler) popMark() ([]any, error) {
+	for i := len(u.stack) - 1; i >= 0; i-- {
+		if _, ok := u.stack[i].(mark); ok {
+			items := append([]any{}, u.stack[i+1:]...)
+			u.stack = u.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, fmt.Errorf("mark not found")
+}
+
+func (u *unpickler) appendTop(items ...any) error {
+	if len(u.stack) == 0 {
+		return fmt.Errorf("stack underflow")
+	}
+	list, ok := u.stack[len(u.stack)-1].([]any)
+	if !ok {
+		return fmt.Errorf("can't append to %T", u.stack[len(u.stack)-1])
+	}
+	u.stack[len(u.stack)-1] = append(list, items...)
+	return nil
+}
+
+// memoize stores the top of the stack in the memo. Lists are stored by value,
+// which is fine as the Graphite messages never reference a list which is
+// modified afterwards.
+func (u *unpickler) memoize(idx int) error {
+	if len(u.stack) == 0 {
+		return fmt.Errorf("stack underflow")
+	}
+	u.memo[idx] = u.stack[len(u.stack)-1]
+	return nil
+}
+
+func (u *unpickler) get(idx int) error {
+	v, ok := u.memo[idx]
+	if !ok {
+		return fmt.Errorf("memo key %d not found", idx)
+	}
+	u.push(v)
+	return nil
+}
+
+// decodeLong decodes a little-endian two's complement integer.
+func decodeLong(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	i := new(big.Int).SetBytes(be)
+	if len(b) > 0 && b[len(b)-1]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return i
+}
+
+// unquote decodes the quoted representation of a string used by the STRING
+// opcode of the protocol 0.
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != s[len(s)-1] || (s[0] != '\'' && s[0] != '"') {
+		return "", fmt.Errorf("invalid string %q", s)
+	}
+	if s[0] == '\'' {
+		// strconv.Unquote only supports single quotes for characters.
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
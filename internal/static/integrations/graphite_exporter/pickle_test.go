@@ -0,0 +1,61 @@
+package graphite_exporter //nolint:golint
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePickle(t *testing.T) {
+	// The messages are generated with Python by pickling the following list
+	// with different protocols:
+	//
+	//   [
+	//     ('carbon.agents.host.cpu', (1700000000, 1.5)),
+	//     ('app.requests;env=prod', (1700000001.0, 42)),
+	//     (u'big', (1700000002, 2**70)),
+	//   ]
+	tests := map[string]string{
+		"protocol 0": "286c70300a2856636172626f6e2e6167656e74732e686f73742e6370750a70310a2849313730303030303030300a46312e350a7470320a7470330a6128566170702e72657175657374733b656e763d70726f640a70340a2846313730303030303030312e300a4934320a7470350a7470360a6128566269670a70370a2849313730303030303030320a4c313138303539313632303731373431313330333432344c0a7470380a7470390a612e",
+		"protocol 2": "80025d7100285816000000636172626f6e2e6167656e74732e686f73742e63707571014a00f15365473ff800000000000086710286710358150000006170702e72657175657374733b656e763d70726f6471044741d954fc404000004b2a867105867106580300000062696771074a02f153658a09000000000000000040867108867109652e",
+		"protocol 4": "80049571000000000000005d94288c16636172626f6e2e6167656e74732e686f73742e637075944a00f15365473ff8000000000000869486948c156170702e72657175657374733b656e763d70726f64944741d954fc404000004b2a869486948c03626967944a02f153658a0900000000000000004086948694652e",
+	}
+
+	expect := []pickleSample{
+		{path: "carbon.agents.host.cpu", timestamp: 1700000000, value: 1.5},
+		{path: "app.requests;env=prod", timestamp: 1700000001, value: 42},
+		{path: "big", timestamp: 1700000002, value: math.Pow(2, 70)},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := hex.DecodeString(data)
+			require.NoError(t, err)
+
+			samples, err := decodePickle(b)
+			require.NoError(t, err)
+			require.Equal(t, expect, samples)
+		})
+	}
+}
+
+func TestDecodePickle_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"truncated":          "80025d71",
+		"not a list":         "80024b2a2e",
+		"unsupported opcode": "80027d2e",
+		"invalid sample":     "80025d4b2a612e",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := hex.DecodeString(data)
+			require.NoError(t, err)
+
+			_, err = decodePickle(b)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package haproxy_exporter //nolint:golint
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fieldMetric is a metric with the value of a field of the CSV statistics.
+type fieldMetric struct {
+	field     string
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	// scale is multiplied with the value of the field, to convert
+	// milliseconds to seconds for example.
+	scale float64
+}
+
+func newFieldMetrics(subsystem string, labels []string, defs []fieldMetricDef) []fieldMetric {
+	metrics := make([]fieldMetric, 0, len(defs))
+	for _, d := range defs {
+		scale := d.scale
+		if scale == 0 {
+			scale = 1
+		}
+		metrics = append(metrics, fieldMetric{
+			field:     d.field,
+			desc:      prometheus.NewDesc(prometheus.BuildFQName("haproxy", subsystem, d.name), d.help, labels, nil),
+			valueType: d.valueType,
+			scale:     scale,
+		})
+	}
+	return metrics
+}
+
+type fieldMetricDef struct {
+	field     string
+	name      string
+	help      string
+	valueType prometheus.ValueType
+	scale     float64
+}
+
+// Fields shared by frontends, backends, and servers.
+var commonFields = []fieldMetricDef{
+	{"scur", "current_sessions", "Current number of active sessions.", prometheus.GaugeValue, 0},
+	{"smax", "max_sessions", "Maximum observed number of active sessions.", prometheus.GaugeValue, 0},
+	{"stot", "sessions_total", "Total number of sessions.", prometheus.CounterValue, 0},
+	{"bin", "bytes_in_total", "Total number of bytes received.", prometheus.CounterValue, 0},
+	{"bout", "bytes_out_total", "Total number of bytes sent.", prometheus.CounterValue, 0},
+}
+
+var (
+	frontendMetrics = newFieldMetrics("frontend", []string{"frontend"}, append([]fieldMetricDef{
+		{"slim", "limit_sessions", "Configured session limit.", prometheus.GaugeValue, 0},
+		{"dreq", "requests_denied_total", "Total number of denied requests.", prometheus.CounterValue, 0},
+		{"ereq", "request_errors_total", "Total number of request errors.", prometheus.CounterValue, 0},
+		{"req_tot", "http_requests_total", "Total number of HTTP requests received.", prometheus.CounterValue, 0},
+		{"conn_tot", "connections_total", "Total number of connections.", prometheus.CounterValue, 0},
+	}, commonFields...))
+
+	backendMetrics = newFieldMetrics("backend", []string{"backend"}, append([]fieldMetricDef{
+		{"qcur", "current_queue", "Current number of queued requests not assigned to any server.", prometheus.GaugeValue, 0},
+		{"econ", "connection_errors_total", "Total number of connection errors.", prometheus.CounterValue, 0},
+		{"eresp", "response_errors_total", "Total number of response errors.", prometheus.CounterValue, 0},
+		{"wretr", "retry_warnings_total", "Total number of retry warnings.", prometheus.CounterValue, 0},
+		{"wredis", "redispatch_warnings_total", "Total number of redispatch warnings.", prometheus.CounterValue, 0},
+		{"weight", "weight", "Total weight of the servers of the backend.", prometheus.GaugeValue, 0},
+		{"act", "active_servers", "Number of active servers.", prometheus.GaugeValue, 0},
+		{"bck", "backup_servers", "Number of backup servers.", prometheus.GaugeValue, 0},
+		{"rtime", "response_time_average_seconds", "Average response time over the last 1024 requests.", prometheus.GaugeValue, 0.001},
+		{"ttime", "total_time_average_seconds", "Average total session time over the last 1024 requests.", prometheus.GaugeValue, 0.001},
+	}, commonFields...))
+
+	serverMetrics = newFieldMetrics("server", []string{"backend", "server"}, append([]fieldMetricDef{
+		{"qcur", "current_queue", "Current number of queued requests assigned to the server.", prometheus.GaugeValue, 0},
+		{"econ", "connection_errors_total", "Total number of connection errors.", prometheus.CounterValue, 0},
+		{"eresp", "response_errors_total", "Total number of response errors.", prometheus.CounterValue, 0},
+		{"wretr", "retry_warnings_total", "Total number of retry warnings.", prometheus.CounterValue, 0},
+		{"wredis", "redispatch_warnings_total", "Total number of redispatch warnings.", prometheus.CounterValue, 0},
+		{"weight", "weight", "Weight of the server.", prometheus.GaugeValue, 0},
+		{"chkfail", "check_failures_total", "Total number of failed health checks.", prometheus.CounterValue, 0},
+		{"downtime", "downtime_seconds_total", "Total downtime of the server.", prometheus.CounterValue, 0},
+		{"rtime", "response_time_average_seconds", "Average response time over the last 1024 requests.", prometheus.GaugeValue, 0.001},
+		{"ttime", "total_time_average_seconds", "Average total session time over the last 1024 requests.", prometheus.GaugeValue, 0.001},
+	}, commonFields...))
+
+	upDesc                    = prometheus.NewDesc("haproxy_up", "Whether the last scrape of HAProxy was successful.", nil, nil)
+	backendUpDesc             = prometheus.NewDesc("haproxy_backend_up", "Whether the backend is up.", []string{"backend"}, nil)
+	serverUpDesc              = prometheus.NewDesc("haproxy_server_up", "Whether the server is up.", []string{"backend", "server"}, nil)
+	frontendHTTPResponsesDesc = prometheus.NewDesc("haproxy_frontend_http_responses_total", "Total number of HTTP responses by class of status code.", []string{"frontend", "code"}, nil)
+	backendHTTPResponsesDesc  = prometheus.NewDesc("haproxy_backend_http_responses_total", "Total number of HTTP responses by class of status code.", []string{"backend", "code"}, nil)
+	serverHTTPResponsesDesc   = prometheus.NewDesc("haproxy_server_http_responses_total", "Total number of HTTP responses by class of status code.", []string{"backend", "server", "code"}, nil)
+)
+
+// httpResponseFields maps the fields of the HTTP responses to the values of
+// the code label.
+var httpResponseFields = []struct{ field, code string }{
+	{"hrsp_1xx", "1xx"},
+	{"hrsp_2xx", "2xx"},
+	{"hrsp_3xx", "3xx"},
+	{"hrsp_4xx", "4xx"},
+	{"hrsp_5xx", "5xx"},
+	{"hrsp_other", "other"},
+}
+
+// collector collects metrics from the CSV statistics of HAProxy on every
+// scrape.
+type collector struct {
+	log           log.Logger
+	fetch         fetchFunc
+	filter        *filter
+	serverMetrics bool
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+func newCollector(l log.Logger, fetch fetchFunc, f *filter, serverMetrics bool) *collector {
+	return &collector{log: l, fetch: fetch, filter: f, serverMetrics: serverMetrics}
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- backendUpDesc
+	ch <- frontendHTTPResponsesDesc
+	ch <- backendHTTPResponsesDesc
+	for _, m := range frontendMetrics {
+		ch <- m.desc
+	}
+	for _, m := range backendMetrics {
+		ch <- m.desc
+	}
+	if c.serverMetrics {
+		ch <- serverUpDesc
+		ch <- serverHTTPResponsesDesc
+		for _, m := range serverMetrics {
+			ch <- m.desc
+		}
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	rows, err := c.stats(context.Background())
+	if err != nil {
+		level.Error(c.log).Log("msg", "failed to collect HAProxy statistics", "err", err)
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1)
+
+	for _, row := range rows {
+		proxy, server := row["pxname"], row["svname"]
+
+		switch row["type"] {
+		case typeFrontend:
+			if !c.filter.frontend(proxy) {
+				continue
+			}
+			collectFields(ch, row, frontendMetrics, proxy)
+			collectHTTPResponses(ch, row, frontendHTTPResponsesDesc, proxy)
+
+		case typeBackend:
+			if !c.filter.backend(proxy) {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(backendUpDesc, prometheus.GaugeValue, boolToFloat(statusUp(row["status"])), proxy)
+			collectFields(ch, row, backendMetrics, proxy)
+			collectHTTPResponses(ch, row, backendHTTPResponsesDesc, proxy)
+
+		case typeServer:
+			if !c.serverMetrics || !c.filter.backend(proxy) {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(serverUpDesc, prometheus.GaugeValue, boolToFloat(statusUp(row["status"])), proxy, server)
+			collectFields(ch, row, serverMetrics, proxy, server)
+			collectHTTPResponses(ch, row, serverHTTPResponsesDesc, proxy, server)
+		}
+	}
+}
+
+func (c *collector) stats(ctx context.Context) ([]statsRow, error) {
+	r, err := c.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return parseStats(r)
+}
+
+// collectFields sends the metrics of the fields of row. Empty fields, which
+// don't apply to the type of the row or the mode of the proxy, are skipped.
+func collectFields(ch chan<- prometheus.Metric, row statsRow, metrics []fieldMetric, labelValues ...string) {
+	for _, m := range metrics {
+		value, ok := parseField(row, m.field)
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, value*m.scale, labelValues...)
+	}
+}
+
+func collectHTTPResponses(ch chan<- prometheus.Metric, row statsRow, desc *prometheus.Desc, labelValues ...string) {
+	for _, f := range httpResponseFields {
+		value, ok := parseField(row, f.field)
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, append(labelValues, f.code)...)
+	}
+}
+
+func parseField(row statsRow, field string) (float64, bool) {
+	value, err := strconv.ParseFloat(row[field], 64)
+	return value, err == nil
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
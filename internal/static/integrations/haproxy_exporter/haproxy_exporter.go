@@ -0,0 +1,152 @@
+// Package haproxy_exporter collects metrics of HAProxy from the CSV
+// statistics of its stats socket or HTTP stats page.
+package haproxy_exporter //nolint:golint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/static/integrations"
+	config_util "github.com/prometheus/common/config"
+)
+
+// DefaultConfig is the default config for the haproxy integration.
+var DefaultConfig = Config{
+	ScrapeURI:            "http://localhost/;csv",
+	Timeout:              5 * time.Second,
+	FrontendIncludeRegex: ".*",
+	BackendIncludeRegex:  ".*",
+}
+
+// Config controls the haproxy integration.
+type Config struct {
+	// ScrapeURI is the URI of the CSV statistics, either of the HTTP stats
+	// page or of the stats socket with the unix scheme.
+	ScrapeURI string `yaml:"scrape_uri,omitempty"`
+
+	// Timeout of the requests to HAProxy during a scrape.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// TLSConfig is used to connect to the HTTP stats page.
+	TLSConfig *config_util.TLSConfig `yaml:"tls_config,omitempty"`
+
+	// Regular expressions selecting the frontends and backends to collect
+	// metrics from. Exclude regular expressions take precedence, and are
+	// ignored when empty.
+	FrontendIncludeRegex string `yaml:"frontend_include_regex,omitempty"`
+	FrontendExcludeRegex string `yaml:"frontend_exclude_regex,omitempty"`
+	BackendIncludeRegex  string `yaml:"backend_include_regex,omitempty"`
+	BackendExcludeRegex  string `yaml:"backend_exclude_regex,omitempty"`
+
+	// DisableServerMetrics disables the metrics of the servers of backends.
+	DisableServerMetrics bool `yaml:"disable_server_metrics,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "haproxy"
+}
+
+// InstanceKey returns the host of the HTTP stats page, or the path of the
+// stats socket.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	u, err := url.Parse(c.ScrapeURI)
+	if err != nil {
+		return "", fmt.Errorf("invalid scrape URI: %w", err)
+	}
+	if u.Scheme == "unix" {
+		return u.Path, nil
+	}
+	return u.Host, nil
+}
+
+// NewIntegration creates a new haproxy integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// New creates a new haproxy integration. Metrics are collected from HAProxy
+// on every scrape.
+func New(l log.Logger, c *Config) (integrations.Integration, error) {
+	u, err := url.Parse(c.ScrapeURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scrape URI: %w", err)
+	}
+
+	var fetch fetchFunc
+	switch u.Scheme {
+	case "http", "https":
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if c.TLSConfig != nil {
+			tlsConfig, err := config_util.NewTLSConfig(c.TLSConfig)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tls_config: %w", err)
+			}
+			transport.TLSClientConfig = tlsConfig
+		}
+		fetch = fetchHTTP(&http.Client{Transport: transport, Timeout: c.Timeout}, c.ScrapeURI)
+	case "unix":
+		fetch = fetchUnix(u.Path, c.Timeout)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q of scrape URI, must be http, https, or unix", u.Scheme)
+	}
+
+	f, err := newFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(newCollector(l, fetch, f, !c.DisableServerMetrics)),
+	), nil
+}
+
+// filter selects the frontends and backends to collect metrics from.
+type filter struct {
+	frontendInclude, frontendExclude *regexp.Regexp
+	backendInclude, backendExclude   *regexp.Regexp
+}
+
+func newFilter(c *Config) (*filter, error) {
+	var f filter
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{"frontend include", c.FrontendIncludeRegex, &f.frontendInclude},
+		{"frontend exclude", c.FrontendExcludeRegex, &f.frontendExclude},
+		{"backend include", c.BackendIncludeRegex, &f.backendInclude},
+		{"backend exclude", c.BackendExcludeRegex, &f.backendExclude},
+	} {
+		if re.expr == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("^(?:" + re.expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s regex %q: %w", re.name, re.expr, err)
+		}
+		*re.dst = compiled
+	}
+	return &f, nil
+}
+
+func (f *filter) frontend(name string) bool {
+	return matches(name, f.frontendInclude, f.frontendExclude)
+}
+
+func (f *filter) backend(name string) bool {
+	return matches(name, f.backendInclude, f.backendExclude)
+}
+
+func matches(name string, include, exclude *regexp.Regexp) bool {
+	if exclude != nil && exclude.MatchString(name) {
+		return false
+	}
+	return include == nil || include.MatchString(name)
+}
@@ -0,0 +1,145 @@
+package haproxy_exporter //nolint:golint
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testStats = `# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,dreq,dresp,ereq,econ,eresp,wretr,wredis,status,weight,act,bck,chkfail,chkdown,lastchg,downtime,qlimit,pid,iid,sid,throttle,lbtot,tracked,type,rate,rate_lim,rate_max,check_status,check_code,check_duration,hrsp_1xx,hrsp_2xx,hrsp_3xx,hrsp_4xx,hrsp_5xx,hrsp_other,hanafail,req_rate,req_rate_max,req_tot,cli_abrt,srv_abrt,comp_in,comp_out,comp_byp,comp_rsp,lastsess,last_chk,last_agt,qtime,ctime,rtime,ttime,
+www,FRONTEND,,,3,10,2000,120,5000,90000,1,0,2,,,,,OPEN,,,,,,,,,1,2,0,,,,0,1,0,5,,,,0,110,4,5,1,0,,1,5,120,,,0,0,0,0,,,,,,,,
+admin,FRONTEND,,,0,1,2000,3,100,200,0,0,0,,,,,OPEN,,,,,,,,,1,3,0,,,,0,0,0,1,,,,0,3,0,0,0,0,,0,1,3,,,0,0,0,0,,,,,,,,
+app,web1,0,0,1,5,,60,2500,45000,,0,,0,0,0,0,UP,1,1,0,0,0,100,0,,1,4,1,,60,,2,0,,3,L7OK,200,1,0,55,2,2,1,0,,,,,0,0,,,,,5,,,0,1,12,40,
+app,web2,0,0,0,4,,58,2400,44000,,0,,3,1,2,1,DOWN,1,1,0,7,1,50,30,,1,4,2,,58,,2,0,,3,L4CON,,0,0,55,2,2,1,0,,,,,0,0,,,,,50,,,0,0,0,0,
+app,BACKEND,0,0,1,5,200,118,4900,89000,0,0,,3,1,2,1,UP,1,1,0,,0,100,0,,1,4,0,,118,,1,0,,5,,,,0,110,4,4,2,0,,,,,0,0,0,0,0,0,5,,,0,1,12,40,
+`
+
+func TestParseStats(t *testing.T) {
+	rows, err := parseStats(strings.NewReader(testStats))
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	require.Equal(t, "www", rows[0]["pxname"])
+	require.Equal(t, "FRONTEND", rows[0]["svname"])
+	require.Equal(t, "110", rows[0]["hrsp_2xx"])
+	require.Equal(t, "DOWN", rows[3]["status"])
+
+	_, err = parseStats(strings.NewReader("<html>Statistics Report</html>\n"))
+	require.EqualError(t, err, `invalid statistics header "<html>Statistics Report</html>"`)
+}
+
+const expectedMetrics = `
+# HELP haproxy_up Whether the last scrape of HAProxy was successful.
+# TYPE haproxy_up gauge
+haproxy_up 1
+# HELP haproxy_frontend_current_sessions Current number of active sessions.
+# TYPE haproxy_frontend_current_sessions gauge
+haproxy_frontend_current_sessions{frontend="www"} 3
+# HELP haproxy_frontend_http_responses_total Total number of HTTP responses by class of status code.
+# TYPE haproxy_frontend_http_responses_total counter
+haproxy_frontend_http_responses_total{code="1xx",frontend="www"} 0
+haproxy_frontend_http_responses_total{code="2xx",frontend="www"} 110
+haproxy_frontend_http_responses_total{code="3xx",frontend="www"} 4
+haproxy_frontend_http_responses_total{code="4xx",frontend="www"} 5
+haproxy_frontend_http_responses_total{code="5xx",frontend="www"} 1
+haproxy_frontend_http_responses_total{code="other",frontend="www"} 0
+# HELP haproxy_backend_up Whether the backend is up.
+# TYPE haproxy_backend_up gauge
+haproxy_backend_up{backend="app"} 1
+# HELP haproxy_backend_total_time_average_seconds Average total session time over the last 1024 requests.
+# TYPE haproxy_backend_total_time_average_seconds gauge
+haproxy_backend_total_time_average_seconds{backend="app"} 0.04
+# HELP haproxy_server_up Whether the server is up.
+# TYPE haproxy_server_up gauge
+haproxy_server_up{backend="app",server="web1"} 1
+haproxy_server_up{backend="app",server="web2"} 0
+# HELP haproxy_server_check_failures_total Total number of failed health checks.
+# TYPE haproxy_server_check_failures_total counter
+haproxy_server_check_failures_total{backend="app",server="web1"} 0
+haproxy_server_check_failures_total{backend="app",server="web2"} 7
+`
+
+var expectedMetricNames = []string{
+	"haproxy_up",
+	"haproxy_frontend_current_sessions",
+	"haproxy_frontend_http_responses_total",
+	"haproxy_backend_up",
+	"haproxy_backend_total_time_average_seconds",
+	"haproxy_server_up",
+	"haproxy_server_check_failures_total",
+}
+
+func TestCollector_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testStats))
+	}))
+	defer srv.Close()
+
+	f, err := newFilter(&Config{FrontendExcludeRegex: "admin"})
+	require.NoError(t, err)
+	col := newCollector(log.NewNopLogger(), fetchHTTP(http.DefaultClient, srv.URL+"/;csv"), f, true)
+
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expectedMetrics), expectedMetricNames...))
+}
+
+func TestCollector_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "haproxy.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			cmd, _ := bufio.NewReader(conn).ReadString('\n')
+			if cmd == "show stat\n" {
+				_, _ = io.WriteString(conn, testStats)
+			}
+			conn.Close()
+		}
+	}()
+
+	f, err := newFilter(&Config{FrontendExcludeRegex: "admin"})
+	require.NoError(t, err)
+	col := newCollector(log.NewNopLogger(), fetchUnix(path, time.Second), f, true)
+
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expectedMetrics), expectedMetricNames...))
+}
+
+func TestCollector_Filters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testStats))
+	}))
+	defer srv.Close()
+
+	f, err := newFilter(&Config{FrontendIncludeRegex: "admin", BackendExcludeRegex: "app"})
+	require.NoError(t, err)
+	col := newCollector(log.NewNopLogger(), fetchHTTP(http.DefaultClient, srv.URL), f, false)
+
+	expected := `
+# HELP haproxy_frontend_current_sessions Current number of active sessions.
+# TYPE haproxy_frontend_current_sessions gauge
+haproxy_frontend_current_sessions{frontend="admin"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"haproxy_frontend_current_sessions", "haproxy_backend_up", "haproxy_server_up"))
+}
+
+func TestNew_UnsupportedScheme(t *testing.T) {
+	c := DefaultConfig
+	c.ScrapeURI = "tcp://localhost:9999"
+	_, err := New(log.NewNopLogger(), &c)
+	require.EqualError(t, err, `unsupported scheme "tcp" of scrape URI, must be http, https, or unix`)
+}
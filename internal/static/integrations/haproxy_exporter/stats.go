@@ -0,0 +1,112 @@
+package haproxy_exporter //nolint:golint
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Types of the rows of the CSV statistics.
+const (
+	typeFrontend = "0"
+	typeBackend  = "1"
+	typeServer   = "2"
+)
+
+// fetchFunc returns the CSV statistics of HAProxy. The caller must close the
+// returned reader.
+type fetchFunc func(ctx context.Context) (io.ReadCloser, error)
+
+func fetchHTTP(cl *http.Client, uri string) fetchFunc {
+	return func(ctx context.Context) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := cl.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return resp.Body, nil
+	}
+}
+
+func fetchUnix(path string, timeout time.Duration) fetchFunc {
+	return func(ctx context.Context) (io.ReadCloser, error) {
+		d := net.Dialer{Timeout: timeout}
+		conn, err := d.DialContext(ctx, "unix", path)
+		if err != nil {
+			return nil, err
+		}
+		if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if _, err := io.WriteString(conn, "show stat\n"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// statsRow is a row of the CSV statistics, mapping the names of the fields
+// to their values.
+type statsRow map[string]string
+
+// parseStats parses the CSV statistics of HAProxy. The first line is the
+// header, prefixed with "# ".
+func parseStats(r io.Reader) ([]statsRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty statistics")
+	} else if err != nil {
+		return nil, err
+	}
+	if len(header) == 0 || !strings.HasPrefix(header[0], "# ") {
+		return nil, fmt.Errorf("invalid statistics header %q", strings.Join(header, ","))
+	}
+	header[0] = strings.TrimPrefix(header[0], "# ")
+
+	var rows []statsRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		row := make(statsRow, len(header))
+		for i, value := range record {
+			if i < len(header) && header[i] != "" {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// statusUp returns whether the status of a frontend, backend, or server
+// means it's up.
+func statusUp(status string) bool {
+	switch {
+	case strings.HasPrefix(status, "UP"), status == "OPEN", status == "no check", status == "DRAIN":
+		return true
+	default:
+		return false
+	}
+}
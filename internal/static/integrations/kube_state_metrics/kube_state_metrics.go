@@ -0,0 +1,270 @@
+// Package kube_state_metrics embeds kube-state-metrics, which generates
+// metrics about the state of the objects of a Kubernetes cluster.
+//
+// kube-state-metrics is used as a library rather than reimplemented, so that
+// the metrics keep the names and labels of a kube-state-metrics deployment.
+// Only its builder, store, and option packages are imported; they depend on
+// the Kubernetes client libraries which Alloy already uses.
+package kube_state_metrics //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	"k8s.io/kube-state-metrics/v2/pkg/builder"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/metricsstore"
+	"k8s.io/kube-state-metrics/v2/pkg/optin"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// DefaultResources are the resources kube-state-metrics generates metrics for
+// by default.
+var DefaultResources = []string{
+	"certificatesigningrequests",
+	"configmaps",
+	"cronjobs",
+	"daemonsets",
+	"deployments",
+	"endpoints",
+	"horizontalpodautoscalers",
+	"ingresses",
+	"jobs",
+	"leases",
+	"limitranges",
+	"mutatingwebhookconfigurations",
+	"namespaces",
+	"networkpolicies",
+	"nodes",
+	"persistentvolumeclaims",
+	"persistentvolumes",
+	"poddisruptionbudgets",
+	"pods",
+	"replicasets",
+	"replicationcontrollers",
+	"resourcequotas",
+	"secrets",
+	"services",
+	"statefulsets",
+	"storageclasses",
+	"validatingwebhookconfigurations",
+	"volumeattachments",
+}
+
+// DefaultConfig is the default config for the kube_state_metrics integration.
+var DefaultConfig = Config{
+	Resources:   DefaultResources,
+	Shard:       0,
+	TotalShards: 1,
+}
+
+// Config controls the kube_state_metrics integration.
+type Config struct {
+	// Resources to generate metrics for.
+	Resources []string `yaml:"resources,omitempty"`
+
+	// Namespaces to generate metrics for. All namespaces are used when empty.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+
+	// NamespacesDenylist lists the namespaces to exclude.
+	NamespacesDenylist []string `yaml:"namespaces_denylist,omitempty"`
+
+	// MetricAllowlist and MetricDenylist are regular expressions filtering the
+	// generated metric families. They're mutually exclusive.
+	MetricAllowlist []string `yaml:"metric_allowlist,omitempty"`
+	MetricDenylist  []string `yaml:"metric_denylist,omitempty"`
+
+	// MetricOptIn lists the opt-in metric families to generate.
+	MetricOptIn []string `yaml:"metric_opt_in,omitempty"`
+
+	// MetricLabelsAllowlist and MetricAnnotationsAllowlist map resources to the
+	// Kubernetes labels and annotations added to their _labels and
+	// _annotations metrics.
+	MetricLabelsAllowlist      map[string][]string `yaml:"metric_labels_allowlist,omitempty"`
+	MetricAnnotationsAllowlist map[string][]string `yaml:"metric_annotations_allowlist,omitempty"`
+
+	// Shard is the index of the shard of objects to generate metrics for, out
+	// of TotalShards.
+	Shard       int32 `yaml:"shard,omitempty"`
+	TotalShards int   `yaml:"total_shards,omitempty"`
+
+	// RESTConfig is used to connect to the Kubernetes API server.
+	RESTConfig *rest.Config `yaml:"-"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "kube_state_metrics"
+}
+
+// InstanceKey returns the agentKey, as the integration generates metrics for
+// the whole Kubernetes cluster it's connected to.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	return agentKey, nil
+}
+
+// NewIntegration creates a new kube_state_metrics integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// Integration is the kube_state_metrics integration. Metrics are only
+// generated while the integration runs.
+type Integration struct {
+	log       log.Logger
+	cfg       *Config
+	client    kubernetes.Interface
+	famFilter generator.FamilyGeneratorFilter
+	fieldSel  string
+
+	mut     sync.RWMutex
+	writers metricsstore.MetricsWriterList
+}
+
+var _ integrations.Integration = (*Integration)(nil)
+
+// New creates a new kube_state_metrics integration.
+func New(l log.Logger, c *Config) (*Integration, error) {
+	if c.RESTConfig == nil {
+		return nil, fmt.Errorf("no Kubernetes client configuration provided")
+	}
+	if c.TotalShards < 1 || c.Shard < 0 || int(c.Shard) >= c.TotalShards {
+		return nil, fmt.Errorf("invalid shard %d out of %d total shards", c.Shard, c.TotalShards)
+	}
+
+	client, err := kubernetes.NewForConfig(c.RESTConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building Kubernetes client: %w", err)
+	}
+
+	famFilter, err := newFamilyGeneratorFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integration{
+		log:       l,
+		cfg:       c,
+		client:    client,
+		famFilter: famFilter,
+		fieldSel:  excludeNamespacesSelector(c.NamespacesDenylist),
+	}, nil
+}
+
+func newFamilyGeneratorFilter(c *Config) (generator.FamilyGeneratorFilter, error) {
+	allowDenyList, err := allowdenylist.New(toSet(c.MetricAllowlist), toSet(c.MetricDenylist))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric allowlist or denylist: %w", err)
+	}
+	if err := allowDenyList.Parse(); err != nil {
+		return nil, fmt.Errorf("invalid metric allowlist or denylist: %w", err)
+	}
+
+	optInFilter, err := optin.NewMetricFamilyFilter(toSet(c.MetricOptIn))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric opt-in list: %w", err)
+	}
+
+	return generator.NewCompositeFamilyGeneratorFilter(allowDenyList, optInFilter), nil
+}
+
+// excludeNamespacesSelector returns the field selector excluding the objects
+// of the namespaces.
+func excludeNamespacesSelector(namespaces []string) string {
+	if len(namespaces) == 0 {
+		return ""
+	}
+	selectors := make([]fields.Selector, 0, len(namespaces))
+	for _, ns := range namespaces {
+		selectors = append(selectors, fields.OneTermNotEqualSelector("metadata.namespace", ns))
+	}
+	return fields.AndSelectors(selectors...).String()
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// MetricsHandler implements integrations.Integration.
+func (i *Integration) MetricsHandler() (http.Handler, error) {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		i.mut.RLock()
+		defer i.mut.RUnlock()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, writer := range i.writers {
+			if err := writer.WriteAll(w); err != nil {
+				level.Error(i.log).Log("msg", "failed to write metrics", "err", err)
+				return
+			}
+		}
+	}), nil
+}
+
+// ScrapeConfigs implements integrations.Integration.
+func (i *Integration) ScrapeConfigs() []config.ScrapeConfig {
+	return []config.ScrapeConfig{{
+		JobName:     i.cfg.Name(),
+		MetricsPath: "/metrics",
+	}}
+}
+
+// Run implements integrations.Integration. The informers watching the
+// Kubernetes objects run until ctx is canceled.
+func (i *Integration) Run(ctx context.Context) error {
+	b := builder.NewBuilder()
+	b.WithContext(ctx)
+	b.WithKubeClient(i.client)
+	// The metrics of the informers aren't exposed, but they must be registered
+	// to a fresh registry each time the integration runs.
+	b.WithMetrics(prometheus.NewRegistry())
+	b.WithSharding(i.cfg.Shard, i.cfg.TotalShards)
+	b.WithFamilyGeneratorFilter(i.famFilter)
+	b.WithFieldSelectorFilter(i.fieldSel)
+	b.WithGenerateStoresFunc(b.DefaultGenerateStoresFunc())
+	b.WithGenerateCustomResourceStoresFunc(b.DefaultGenerateCustomResourceStoresFunc())
+
+	namespaces := options.DefaultNamespaces
+	if len(i.cfg.Namespaces) > 0 {
+		namespaces = options.NamespaceList(i.cfg.Namespaces)
+	}
+	b.WithNamespaces(namespaces)
+
+	if err := b.WithEnabledResources(i.cfg.Resources); err != nil {
+		return fmt.Errorf("invalid resources: %w", err)
+	}
+	if err := b.WithAllowLabels(i.cfg.MetricLabelsAllowlist); err != nil {
+		return fmt.Errorf("invalid metric labels allowlist: %w", err)
+	}
+	if err := b.WithAllowAnnotations(i.cfg.MetricAnnotationsAllowlist); err != nil {
+		return fmt.Errorf("invalid metric annotations allowlist: %w", err)
+	}
+
+	level.Info(i.log).Log("msg", "starting kube-state-metrics", "shard", i.cfg.Shard, "total_shards", i.cfg.TotalShards)
+
+	i.mut.Lock()
+	i.writers = b.Build()
+	i.mut.Unlock()
+
+	<-ctx.Done()
+
+	i.mut.Lock()
+	i.writers = nil
+	i.mut.Unlock()
+	return nil
+}
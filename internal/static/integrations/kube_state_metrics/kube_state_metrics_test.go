@@ -0,0 +1,35 @@
+package kube_state_metrics
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/util"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestNew_InvalidShard(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.RESTConfig = &rest.Config{Host: "https://localhost:6443"}
+	cfg.Shard, cfg.TotalShards = 2, 2
+
+	_, err := New(util.TestLogger(t), &cfg)
+	require.EqualError(t, err, "invalid shard 2 out of 2 total shards")
+}
+
+func TestNew_InvalidMetricAllowlist(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.RESTConfig = &rest.Config{Host: "https://localhost:6443"}
+	cfg.MetricAllowlist = []string{"kube_pod_(info"}
+
+	_, err := New(util.TestLogger(t), &cfg)
+	require.ErrorContains(t, err, "invalid metric allowlist or denylist")
+}
+
+func TestExcludeNamespacesSelector(t *testing.T) {
+	require.Equal(t, "", excludeNamespacesSelector(nil))
+	require.Equal(t,
+		"metadata.namespace!=kube-system,metadata.namespace!=kube-public",
+		excludeNamespacesSelector([]string{"kube-system", "kube-public"}),
+	)
+}
@@ -0,0 +1,210 @@
+package mongodb_atlas_exporter //nolint:golint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// itemsPerPage is the maximum number of items the Atlas Admin API returns
+// per page.
+const itemsPerPage = 500
+
+// client is a minimal client of the Atlas Admin API v1.0.
+type client struct {
+	baseURL *url.URL
+	http    *http.Client
+}
+
+func newClient(baseURL string, publicKey, privateKey string, timeout time.Duration) (*client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/atlas/v1.0/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	return &client{
+		baseURL: u,
+		http: &http.Client{
+			Transport: newDigestTransport(publicKey, privateKey, http.DefaultTransport),
+			Timeout:   timeout,
+		},
+	}, nil
+}
+
+type project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type cluster struct {
+	Name     string `json:"name"`
+	MongoURI string `json:"mongoURI"`
+}
+
+type process struct {
+	ID             string `json:"id"`
+	Hostname       string `json:"hostname"`
+	Port           int    `json:"port"`
+	TypeName       string `json:"typeName"`
+	ReplicaSetName string `json:"replicaSetName"`
+}
+
+type measurement struct {
+	Name       string `json:"name"`
+	Units      string `json:"units"`
+	DataPoints []struct {
+		Timestamp time.Time `json:"timestamp"`
+		Value     *float64  `json:"value"`
+	} `json:"dataPoints"`
+}
+
+// latest returns the value of the most recent data point of m which has a
+// value.
+func (m *measurement) latest() (float64, bool) {
+	var (
+		value float64
+		ts    time.Time
+		found bool
+	)
+	for _, dp := range m.DataPoints {
+		if dp.Value != nil && (!found || dp.Timestamp.After(ts)) {
+			value, ts, found = *dp.Value, dp.Timestamp, true
+		}
+	}
+	return value, found
+}
+
+func (c *client) projects(ctx context.Context) ([]project, error) {
+	return list[project](ctx, c, "groups")
+}
+
+func (c *client) project(ctx context.Context, id string) (project, error) {
+	var p project
+	err := c.get(ctx, "groups/"+url.PathEscape(id), nil, &p)
+	return p, err
+}
+
+func (c *client) clusters(ctx context.Context, projectID string) ([]cluster, error) {
+	return list[cluster](ctx, c, "groups/"+url.PathEscape(projectID)+"/clusters")
+}
+
+func (c *client) processes(ctx context.Context, projectID string) ([]process, error) {
+	return list[process](ctx, c, "groups/"+url.PathEscape(projectID)+"/processes")
+}
+
+func (c *client) disks(ctx context.Context, projectID, processID string) ([]string, error) {
+	type disk struct {
+		PartitionName string `json:"partitionName"`
+	}
+	disks, err := list[disk](ctx, c, "groups/"+url.PathEscape(projectID)+"/processes/"+url.PathEscape(processID)+"/disks")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(disks))
+	for _, d := range disks {
+		names = append(names, d.PartitionName)
+	}
+	return names, nil
+}
+
+func (c *client) databases(ctx context.Context, projectID, processID string) ([]string, error) {
+	type database struct {
+		DatabaseName string `json:"databaseName"`
+	}
+	databases, err := list[database](ctx, c, "groups/"+url.PathEscape(projectID)+"/processes/"+url.PathEscape(processID)+"/databases")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(databases))
+	for _, d := range databases {
+		names = append(names, d.DatabaseName)
+	}
+	return names, nil
+}
+
+// measurements returns the measurements of the resource at path, which is
+// relative to the process, over the last three periods of granularity.
+func (c *client) measurements(ctx context.Context, projectID, processID, path string, granularity time.Duration) ([]measurement, error) {
+	end := time.Now().UTC()
+	query := url.Values{
+		"granularity": []string{isoDuration(granularity)},
+		"start":       []string{end.Add(-3 * granularity).Format(time.RFC3339)},
+		"end":         []string{end.Format(time.RFC3339)},
+	}
+
+	var resp struct {
+		Measurements []measurement `json:"measurements"`
+	}
+	p := "groups/" + url.PathEscape(projectID) + "/processes/" + url.PathEscape(processID) + path + "/measurements"
+	if err := c.get(ctx, p, query, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Measurements, nil
+}
+
+// list returns all the results of the paginated resource at path.
+func list[T any](ctx context.Context, c *client, path string) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		query := url.Values{
+			"itemsPerPage": []string{strconv.Itoa(itemsPerPage)},
+			"pageNum":      []string{strconv.Itoa(page)},
+		}
+
+		var resp struct {
+			Results    []T `json:"results"`
+			TotalCount int `json:"totalCount"`
+		}
+		if err := c.get(ctx, path, query, &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Results...)
+
+		if len(resp.Results) == 0 || len(all) >= resp.TotalCount {
+			return all, nil
+		}
+	}
+}
+
+func (c *client) get(ctx context.Context, path string, query url.Values, out any) error {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GET %s: unexpected status %s: %s", u.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isoDuration formats a granularity supported by the Atlas Admin API as an
+// ISO 8601 duration.
+func isoDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("P%dD", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("PT%dH", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("PT%dM", d/time.Minute)
+	default:
+		return fmt.Sprintf("PT%dS", d/time.Second)
+	}
+}
@@ -0,0 +1,200 @@
+package mongodb_atlas_exporter //nolint:golint
+
+import (
+	"crypto/md5" //nolint:gosec // Digest authentication requires MD5.
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// digestTransport authenticates requests to the Atlas Admin API with HTTP
+// digest authentication, which is how Atlas API keys are used.
+//
+// Requests are first sent without credentials. When the API responds with a
+// digest challenge, the request is sent again with the credentials, and the
+// challenge is reused for the following requests until it's rejected.
+type digestTransport struct {
+	username string
+	password string
+	next     http.RoundTripper
+
+	mut       sync.Mutex
+	challenge *digestChallenge
+	nc        int
+}
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+func newDigestTransport(username, password string, next http.RoundTripper) *digestTransport {
+	return &digestTransport{
+		username: username,
+		password: password,
+		next:     next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper. Requests must not have a body, as
+// they may be sent twice.
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if auth, ok := t.authorization(req); ok {
+		resp, err := t.next.RoundTrip(withAuthorization(req, auth))
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+		drain(resp)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge, err := parseDigestChallenge(resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		// Let the caller handle the unauthorized response.
+		return resp, nil
+	}
+	drain(resp)
+
+	t.mut.Lock()
+	t.challenge = challenge
+	t.nc = 0
+	t.mut.Unlock()
+
+	auth, _ := t.authorization(req)
+	return t.next.RoundTrip(withAuthorization(req, auth))
+}
+
+// authorization returns the Authorization header of req for the last digest
+// challenge, if any.
+func (t *digestTransport) authorization(req *http.Request) (string, bool) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	c := t.challenge
+	if c == nil {
+		return "", false
+	}
+	t.nc++
+
+	var (
+		uri    = req.URL.RequestURI()
+		nc     = fmt.Sprintf("%08x", t.nc)
+		cnonce = newCnonce()
+		ha1    = md5Hex(t.username + ":" + c.realm + ":" + t.password)
+		ha2    = md5Hex(req.Method + ":" + uri)
+	)
+	if strings.EqualFold(c.algorithm, "MD5-sess") {
+		ha1 = md5Hex(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+
+	var response string
+	if c.qop == "" {
+		response = md5Hex(ha1 + ":" + c.nonce + ":" + ha2)
+	} else {
+		response = md5Hex(ha1 + ":" + c.nonce + ":" + nc + ":" + cnonce + ":auth:" + ha2)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`, t.username, c.realm, c.nonce, uri, response)
+	if c.algorithm != "" {
+		fmt.Fprintf(&sb, `, algorithm=%s`, c.algorithm)
+	}
+	if c.opaque != "" {
+		fmt.Fprintf(&sb, `, opaque="%s"`, c.opaque)
+	}
+	if c.qop != "" {
+		fmt.Fprintf(&sb, `, qop=auth, nc=%s, cnonce="%s"`, nc, cnonce)
+	}
+	return sb.String(), true
+}
+
+// parseDigestChallenge parses the value of a WWW-Authenticate header with a
+// digest challenge. Only the auth quality of protection is supported.
+func parseDigestChallenge(header string) (*digestChallenge, error) {
+	scheme, params, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Digest") {
+		return nil, fmt.Errorf("not a digest challenge: %q", header)
+	}
+
+	var c digestChallenge
+	for _, param := range splitParams(params) {
+		key, value, _ := strings.Cut(param, "=")
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "realm":
+			c.realm = value
+		case "nonce":
+			c.nonce = value
+		case "opaque":
+			c.opaque = value
+		case "algorithm":
+			c.algorithm = value
+		case "qop":
+			for _, qop := range strings.Split(value, ",") {
+				if strings.TrimSpace(qop) == "auth" {
+					c.qop = "auth"
+				}
+			}
+			if c.qop == "" {
+				return nil, fmt.Errorf("unsupported digest qop %q", value)
+			}
+		}
+	}
+	if c.nonce == "" {
+		return nil, fmt.Errorf("digest challenge without nonce: %q", header)
+	}
+	return &c, nil
+}
+
+// splitParams splits the comma-separated parameters of a challenge, ignoring
+// commas in quoted values.
+func splitParams(s string) []string {
+	var (
+		params []string
+		quoted bool
+		start  int
+	)
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			params = append(params, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(params, strings.TrimSpace(s[start:]))
+}
+
+func withAuthorization(req *http.Request, auth string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", auth)
+	return req
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func newCnonce() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
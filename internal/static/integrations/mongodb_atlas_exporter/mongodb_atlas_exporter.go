@@ -0,0 +1,339 @@
+// Package mongodb_atlas_exporter collects metrics of MongoDB Atlas clusters
+// from the Atlas Admin API.
+package mongodb_atlas_exporter //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+)
+
+// requestTimeout is the timeout of a single request to the Atlas Admin API.
+const requestTimeout = 30 * time.Second
+
+// DefaultConfig is the default config for the mongodb_atlas integration.
+var DefaultConfig = Config{
+	BaseURL:         "https://cloud.mongodb.com",
+	Granularity:     time.Minute,
+	RefreshInterval: time.Minute,
+}
+
+// Config controls the mongodb_atlas integration.
+type Config struct {
+	// BaseURL of the Atlas Admin API.
+	BaseURL string `yaml:"base_url,omitempty"`
+
+	// PublicKey and PrivateKey of the Atlas API key.
+	PublicKey  string             `yaml:"public_key,omitempty"`
+	PrivateKey config_util.Secret `yaml:"private_key,omitempty"`
+
+	// Projects are the IDs of the projects to collect metrics from. All the
+	// projects the API key has access to are used when empty.
+	Projects []string `yaml:"projects,omitempty"`
+
+	// Clusters are the names of the clusters to collect metrics from. All the
+	// clusters of the projects are used when empty.
+	Clusters []string `yaml:"clusters,omitempty"`
+
+	// Granularity of the measurements requested from the Atlas Admin API.
+	Granularity time.Duration `yaml:"granularity,omitempty"`
+
+	// RefreshInterval is how often measurements are requested.
+	RefreshInterval time.Duration `yaml:"refresh_interval,omitempty"`
+
+	DisableDiskMetrics     bool `yaml:"disable_disk_metrics,omitempty"`
+	DisableDatabaseMetrics bool `yaml:"disable_database_metrics,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "mongodb_atlas"
+}
+
+// InstanceKey returns the host of the Atlas Admin API.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	return u.Host, nil
+}
+
+// NewIntegration creates a new mongodb_atlas integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	if c.PublicKey == "" || c.PrivateKey == "" {
+		return nil, fmt.Errorf("an Atlas API public and private key are required")
+	}
+	if c.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("refresh interval must be greater than 0")
+	}
+
+	cl, err := newClient(c.BaseURL, c.PublicKey, string(c.PrivateKey), requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	col := newAtlasCollector(l, c, cl)
+
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(col),
+		integrations.WithRunner(func(ctx context.Context) error {
+			return col.Run(ctx, c.RefreshInterval)
+		}),
+	), nil
+}
+
+var (
+	processLabels = []string{"project_id", "project", "cluster", "process", "process_type", "replica_set"}
+
+	projectUpDesc = prometheus.NewDesc(
+		"mongodb_atlas_project_up",
+		"Whether the processes of the Atlas project could be listed.",
+		[]string{"project_id", "project"}, nil,
+	)
+)
+
+// atlasCollector periodically requests the measurements of the processes of
+// Atlas clusters, and exposes the latest values of the measurements.
+//
+// Requesting the measurements at scrape time would quickly exhaust the rate
+// limits of the Atlas Admin API, so they're cached between refreshes.
+type atlasCollector struct {
+	log    log.Logger
+	cfg    *Config
+	client *client
+
+	mut     sync.RWMutex
+	metrics []prometheus.Metric
+}
+
+var _ prometheus.Collector = (*atlasCollector)(nil)
+
+func newAtlasCollector(l log.Logger, c *Config, cl *client) *atlasCollector {
+	return &atlasCollector{
+		log:    l,
+		cfg:    c,
+		client: cl,
+	}
+}
+
+// Run refreshes the measurements every interval until ctx is canceled.
+func (c *atlasCollector) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *atlasCollector) refresh(ctx context.Context) {
+	projects, err := c.projects(ctx)
+	if err != nil {
+		level.Warn(c.log).Log("msg", "failed to list Atlas projects, keeping the previous measurements", "err", err)
+		return
+	}
+
+	mb := newMetricsBuilder()
+	for _, p := range projects {
+		if err := c.collectProject(ctx, mb, p); err != nil {
+			level.Warn(c.log).Log("msg", "failed to collect the measurements of an Atlas project", "project_id", p.ID, "err", err)
+			mb.add(projectUpDesc, 0, p.ID, p.Name)
+			continue
+		}
+		mb.add(projectUpDesc, 1, p.ID, p.Name)
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.metrics = mb.metrics
+}
+
+// projects returns the configured projects, or all the projects the API key
+// has access to.
+func (c *atlasCollector) projects(ctx context.Context) ([]project, error) {
+	if len(c.cfg.Projects) == 0 {
+		return c.client.projects(ctx)
+	}
+
+	projects := make([]project, 0, len(c.cfg.Projects))
+	for _, id := range c.cfg.Projects {
+		p, err := c.client.project(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("project %q: %w", id, err)
+		}
+		projects = append(projects, p)
+	}
+	return projects, nil
+}
+
+func (c *atlasCollector) collectProject(ctx context.Context, mb *metricsBuilder, p project) error {
+	clusters, err := c.client.clusters(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	processes, err := c.client.processes(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+
+	clusterNames := make(map[string]string)
+	for _, cl := range clusters {
+		for _, host := range mongoURIHosts(cl.MongoURI) {
+			clusterNames[clusterKey(host)] = cl.Name
+		}
+	}
+
+	for _, proc := range processes {
+		clusterName := clusterNames[clusterKey(proc.Hostname)]
+		if len(c.cfg.Clusters) > 0 && !slices.Contains(c.cfg.Clusters, clusterName) {
+			continue
+		}
+
+		labels := []string{p.ID, p.Name, clusterName, proc.ID, proc.TypeName, proc.ReplicaSetName}
+		if err := c.collectProcess(ctx, mb, p.ID, proc.ID, labels); err != nil {
+			level.Warn(c.log).Log("msg", "failed to collect the measurements of an Atlas process", "project_id", p.ID, "process", proc.ID, "err", err)
+		}
+	}
+	return nil
+}
+
+func (c *atlasCollector) collectProcess(ctx context.Context, mb *metricsBuilder, projectID, processID string, labels []string) error {
+	measurements, err := c.client.measurements(ctx, projectID, processID, "", c.cfg.Granularity)
+	if err != nil {
+		return err
+	}
+	mb.addMeasurements(measurements, processLabels, labels)
+
+	if !c.cfg.DisableDiskMetrics {
+		partitions, err := c.client.disks(ctx, projectID, processID)
+		if err != nil {
+			return fmt.Errorf("listing disks: %w", err)
+		}
+		for _, partition := range partitions {
+			measurements, err := c.client.measurements(ctx, projectID, processID, "/disks/"+url.PathEscape(partition), c.cfg.Granularity)
+			if err != nil {
+				return fmt.Errorf("disk %q: %w", partition, err)
+			}
+			mb.addMeasurements(measurements, append(processLabels, "partition"), append(labels, partition))
+		}
+	}
+
+	if !c.cfg.DisableDatabaseMetrics {
+		databases, err := c.client.databases(ctx, projectID, processID)
+		if err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+		for _, database := range databases {
+			measurements, err := c.client.measurements(ctx, projectID, processID, "/databases/"+url.PathEscape(database), c.cfg.Granularity)
+			if err != nil {
+				return fmt.Errorf("database %q: %w", database, err)
+			}
+			mb.addMeasurements(measurements, append(processLabels, "database"), append(labels, database))
+		}
+	}
+	return nil
+}
+
+// Describe implements prometheus.Collector. The metrics depend on the
+// measurements returned by the Atlas Admin API, so the collector is
+// unchecked.
+func (c *atlasCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *atlasCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
+
+// metricsBuilder builds the metrics of a refresh.
+type metricsBuilder struct {
+	descs   map[string]*prometheus.Desc
+	metrics []prometheus.Metric
+}
+
+func newMetricsBuilder() *metricsBuilder {
+	return &metricsBuilder{descs: make(map[string]*prometheus.Desc)}
+}
+
+func (mb *metricsBuilder) add(desc *prometheus.Desc, value float64, labelValues ...string) {
+	mb.metrics = append(mb.metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...))
+}
+
+// addMeasurements adds a metric with the latest value of each measurement.
+// Measurements without values are skipped.
+func (mb *metricsBuilder) addMeasurements(measurements []measurement, labelNames, labelValues []string) {
+	for _, m := range measurements {
+		value, ok := m.latest()
+		if !ok {
+			continue
+		}
+
+		name := "mongodb_atlas_" + strings.ToLower(m.Name)
+		key := name + "/" + strings.Join(labelNames, ",")
+		desc, ok := mb.descs[key]
+		if !ok {
+			help := fmt.Sprintf("Latest value of the %s Atlas measurement, in %s.", m.Name, strings.ToLower(m.Units))
+			desc = prometheus.NewDesc(name, help, labelNames, nil)
+			mb.descs[key] = desc
+		}
+		mb.add(desc, value, labelValues...)
+	}
+}
+
+// mongoURIHosts returns the hosts of a mongodb:// connection string, without
+// their ports.
+func mongoURIHosts(uri string) []string {
+	rest, ok := strings.CutPrefix(uri, "mongodb://")
+	if !ok {
+		return nil
+	}
+	rest, _, _ = strings.Cut(rest, "/")
+	if _, hosts, ok := strings.Cut(rest, "@"); ok {
+		rest = hosts
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(rest, ",") {
+		host, _, _ = strings.Cut(host, ":")
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// clusterKey identifies the cluster of an Atlas hostname. The hostnames of
+// the shards and config servers of a cluster share the same prefix, for
+// example cluster0-shard-00-00.abcde.mongodb.net and
+// cluster0-config-00-00.abcde.mongodb.net.
+func clusterKey(hostname string) string {
+	label, domain, _ := strings.Cut(hostname, ".")
+	for _, sep := range []string{"-shard-", "-config-"} {
+		if i := strings.LastIndex(label, sep); i >= 0 {
+			label = label[:i]
+			break
+		}
+	}
+	return label + "." + domain
+}
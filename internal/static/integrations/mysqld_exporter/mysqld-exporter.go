@@ -4,7 +4,10 @@ package mysqld_exporter //nolint:golint
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
 
 	config_util "github.com/prometheus/common/config"
 
@@ -12,8 +15,10 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/go-sql-driver/mysql"
 	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/grafana/alloy/internal/static/integrations/config"
 	integrations_v2 "github.com/grafana/alloy/internal/static/integrations/v2"
 	"github.com/grafana/alloy/internal/static/integrations/v2/metricsutils"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/mysqld_exporter/collector"
 )
 
@@ -68,6 +73,19 @@ type Config struct {
 	HeartbeatTable                       string `yaml:"heartbeat_table,omitempty"`
 	HeartbeatUTC                         bool   `yaml:"heartbeat_utc,omitempty"`
 	MySQLUserPrivileges                  bool   `yaml:"mysql_user_privileges,omitempty"`
+
+	//-- The fields below were not available in Grafana Agent Static. --
+
+	// Instances are the MySQL servers to collect metrics from, instead of
+	// DataSourceName. The metrics of an instance are collected when its name
+	// is passed in the target query parameter.
+	Instances []Instance `yaml:"-"`
+}
+
+// Instance is a MySQL server to collect metrics from.
+type Instance struct {
+	Name           string
+	DataSourceName config_util.Secret
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler for Config.
@@ -83,9 +101,19 @@ func (c *Config) Name() string {
 	return "mysqld_exporter"
 }
 
-// InstanceKey returns network(hostname:port)/dbname of the MySQL server.
-func (c *Config) InstanceKey(_ string) (string, error) {
-	m, err := mysql.ParseDSN(string(c.DataSourceName))
+// InstanceKey returns network(hostname:port)/dbname of the MySQL server, or
+// agentKey if the integration collects metrics from multiple instances.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	if len(c.Instances) > 0 {
+		return agentKey, nil
+	}
+	return InstanceKeyForDSN(string(c.DataSourceName))
+}
+
+// InstanceKeyForDSN returns network(hostname:port)/dbname of the MySQL server
+// of dsn.
+func InstanceKeyForDSN(dsn string) (string, error) {
+	m, err := mysql.ParseDSN(dsn)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse DSN: %w", err)
 	}
@@ -113,6 +141,10 @@ func init() {
 // New creates a new mysqld_exporter integration. The integration scrapes
 // metrics from a mysqld process.
 func New(log log.Logger, c *Config) (integrations.Integration, error) {
+	if len(c.Instances) > 0 {
+		return newMultiInstance(log, c)
+	}
+
 	dsn := c.DataSourceName
 	if len(dsn) == 0 {
 		dsn = config_util.Secret(os.Getenv("MYSQLD_EXPORTER_DATA_SOURCE_NAME"))
@@ -121,8 +153,15 @@ func New(log log.Logger, c *Config) (integrations.Integration, error) {
 		return nil, fmt.Errorf("cannot create mysqld_exporter; neither mysqld_exporter.data_source_name or $MYSQLD_EXPORTER_DATA_SOURCE_NAME is set")
 	}
 
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(newExporter(log, c, string(dsn))),
+	), nil
+}
+
+func newExporter(log log.Logger, c *Config, dsn string) prometheus.Collector {
 	scrapers := GetScrapers(c)
-	exporter := collector.New(context.Background(), string(dsn), scrapers, log, collector.Config{
+	exporter := collector.New(context.Background(), dsn, scrapers, log, collector.Config{
 		LockTimeout:   c.LockWaitTimeout,
 		SlowLogFilter: c.LogSlowFilter,
 	})
@@ -131,11 +170,79 @@ func New(log log.Logger, c *Config) (integrations.Integration, error) {
 	for _, scraper := range scrapers {
 		level.Debug(log).Log("scraper", scraper.Name())
 	}
+	return exporter
+}
 
-	return integrations.NewCollectorIntegration(
-		c.Name(),
-		integrations.WithCollectors(exporter),
-	), nil
+// multiInstanceIntegration collects metrics from the instance whose name is
+// passed in the target query parameter.
+type multiInstanceIntegration struct {
+	name     string
+	handlers map[string]http.Handler
+}
+
+func newMultiInstance(log log.Logger, c *Config) (integrations.Integration, error) {
+	i := &multiInstanceIntegration{
+		name:     c.Name(),
+		handlers: make(map[string]http.Handler, len(c.Instances)),
+	}
+	for _, instance := range c.Instances {
+		if _, ok := i.handlers[instance.Name]; ok {
+			return nil, fmt.Errorf("instance %q is defined more than once", instance.Name)
+		}
+
+		ci := integrations.NewCollectorIntegration(
+			c.Name(),
+			integrations.WithCollectors(newExporter(log, c, string(instance.DataSourceName))),
+		)
+		h, err := ci.MetricsHandler()
+		if err != nil {
+			return nil, fmt.Errorf("instance %q: %w", instance.Name, err)
+		}
+		i.handlers[instance.Name] = h
+	}
+	return i, nil
+}
+
+// MetricsHandler implements integrations.Integration.
+func (i *multiInstanceIntegration) MetricsHandler() (http.Handler, error) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		if target == "" {
+			http.Error(w, "'target' parameter must be specified", http.StatusBadRequest)
+			return
+		}
+		h, ok := i.handlers[target]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown target %q", target), http.StatusNotFound)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}), nil
+}
+
+// ScrapeConfigs implements integrations.Integration.
+func (i *multiInstanceIntegration) ScrapeConfigs() []config.ScrapeConfig {
+	names := make([]string, 0, len(i.handlers))
+	for name := range i.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]config.ScrapeConfig, 0, len(names))
+	for _, name := range names {
+		res = append(res, config.ScrapeConfig{
+			JobName:     i.name + "/" + name,
+			MetricsPath: "/metrics",
+			QueryParams: url.Values{"target": []string{name}},
+		})
+	}
+	return res
+}
+
+// Run implements integrations.Integration.
+func (i *multiInstanceIntegration) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
 }
 
 // GetScrapers returns the set of *enabled* scrapers from the config.
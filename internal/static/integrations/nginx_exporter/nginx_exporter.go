@@ -0,0 +1,152 @@
+// Package nginx_exporter collects metrics of NGINX from its stub_status page,
+// or of NGINX Plus from its API.
+package nginx_exporter //nolint:golint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/static/integrations"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+)
+
+// DefaultConfig is the default config for the nginx integration.
+var DefaultConfig = Config{
+	URL:                    "http://localhost:8080/stub_status",
+	PlusAPIVersion:         8,
+	Timeout:                5 * time.Second,
+	ServerZoneIncludeRegex: ".*",
+	UpstreamIncludeRegex:   ".*",
+}
+
+// Config controls the nginx integration.
+type Config struct {
+	// URL of the stub_status page, or of the NGINX Plus API when Plus is set.
+	URL string `yaml:"url,omitempty"`
+
+	// Plus collects metrics from the NGINX Plus API instead of stub_status.
+	Plus bool `yaml:"plus,omitempty"`
+
+	// PlusAPIVersion is the version of the NGINX Plus API.
+	PlusAPIVersion int `yaml:"plus_api_version,omitempty"`
+
+	// Timeout of the requests to NGINX during a scrape.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// TLSConfig is used to connect to NGINX, including client certificates.
+	TLSConfig *config_util.TLSConfig `yaml:"tls_config,omitempty"`
+
+	// Regular expressions selecting the server zones and upstreams of NGINX
+	// Plus to collect metrics from. Exclude regular expressions take
+	// precedence, and are ignored when empty.
+	ServerZoneIncludeRegex string `yaml:"server_zone_include_regex,omitempty"`
+	ServerZoneExcludeRegex string `yaml:"server_zone_exclude_regex,omitempty"`
+	UpstreamIncludeRegex   string `yaml:"upstream_include_regex,omitempty"`
+	UpstreamExcludeRegex   string `yaml:"upstream_exclude_regex,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "nginx"
+}
+
+// InstanceKey returns the host of the NGINX URL.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	return u.Host, nil
+}
+
+// NewIntegration creates a new nginx integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// New creates a new nginx integration. Metrics are collected from NGINX on
+// every scrape.
+func New(l log.Logger, c *Config) (integrations.Integration, error) {
+	if _, err := url.ParseRequestURI(c.URL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.TLSConfig != nil {
+		tlsConfig, err := config_util.NewTLSConfig(c.TLSConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls_config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	httpClient := &http.Client{Transport: transport, Timeout: c.Timeout}
+
+	var col prometheus.Collector
+	if c.Plus {
+		f, err := newFilter(c)
+		if err != nil {
+			return nil, err
+		}
+		apiURL := fmt.Sprintf("%s/%d", strings.TrimSuffix(c.URL, "/"), c.PlusAPIVersion)
+		col = newPlusCollector(l, httpClient, apiURL, f)
+	} else {
+		col = newStubStatusCollector(l, httpClient, c.URL)
+	}
+
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(col),
+	), nil
+}
+
+// filter selects the server zones and upstreams of NGINX Plus to collect
+// metrics from.
+type filter struct {
+	serverZoneInclude, serverZoneExclude *regexp.Regexp
+	upstreamInclude, upstreamExclude     *regexp.Regexp
+}
+
+func newFilter(c *Config) (*filter, error) {
+	var f filter
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{"server zone include", c.ServerZoneIncludeRegex, &f.serverZoneInclude},
+		{"server zone exclude", c.ServerZoneExcludeRegex, &f.serverZoneExclude},
+		{"upstream include", c.UpstreamIncludeRegex, &f.upstreamInclude},
+		{"upstream exclude", c.UpstreamExcludeRegex, &f.upstreamExclude},
+	} {
+		if re.expr == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("^(?:" + re.expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s regex %q: %w", re.name, re.expr, err)
+		}
+		*re.dst = compiled
+	}
+	return &f, nil
+}
+
+func (f *filter) serverZone(name string) bool {
+	return matches(name, f.serverZoneInclude, f.serverZoneExclude)
+}
+
+func (f *filter) upstream(name string) bool {
+	return matches(name, f.upstreamInclude, f.upstreamExclude)
+}
+
+func matches(name string, include, exclude *regexp.Regexp) bool {
+	if exclude != nil && exclude.MatchString(name) {
+		return false
+	}
+	return include == nil || include.MatchString(name)
+}
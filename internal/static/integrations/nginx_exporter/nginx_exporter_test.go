@@ -0,0 +1,127 @@
+package nginx_exporter //nolint:golint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testStubStatus = `Active connections: 291
+server accepts handled requests
+ 16630948 16630948 31070465
+Reading: 6 Writing: 179 Waiting: 106
+`
+
+func TestParseStubStatus(t *testing.T) {
+	s, err := parseStubStatus(strings.NewReader(testStubStatus))
+	require.NoError(t, err)
+	require.Equal(t, &stubStatus{
+		active:   291,
+		accepted: 16630948,
+		handled:  16630948,
+		requests: 31070465,
+		reading:  6,
+		writing:  179,
+		waiting:  106,
+	}, s)
+
+	_, err = parseStubStatus(strings.NewReader("<html>Welcome to nginx!</html>"))
+	require.EqualError(t, err, "invalid stub_status: expected 4 lines, got 1")
+}
+
+func TestStubStatusCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testStubStatus))
+	}))
+	defer srv.Close()
+
+	col := newStubStatusCollector(log.NewNopLogger(), http.DefaultClient, srv.URL)
+	expected := `
+# HELP nginx_up Whether the last scrape of NGINX was successful.
+# TYPE nginx_up gauge
+nginx_up 1
+# HELP nginx_connections_active Number of active client connections.
+# TYPE nginx_connections_active gauge
+nginx_connections_active 291
+# HELP nginx_http_requests_total Total number of client requests.
+# TYPE nginx_http_requests_total counter
+nginx_http_requests_total 3.1070465e+07
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected), "nginx_up", "nginx_connections_active", "nginx_http_requests_total"))
+}
+
+func TestPlusCollector(t *testing.T) {
+	responses := map[string]string{
+		"/api/8/connections":   `{"accepted": 100, "dropped": 1, "active": 5, "idle": 2}`,
+		"/api/8/http/requests": `{"total": 500, "current": 3}`,
+		"/api/8/http/server_zones": `{
+			"shop":     {"processing": 1, "requests": 400, "responses": {"1xx": 0, "2xx": 390, "3xx": 0, "4xx": 8, "5xx": 2, "total": 400}, "discarded": 0, "received": 1000, "sent": 9000},
+			"internal": {"processing": 0, "requests": 100, "responses": {"2xx": 100}, "discarded": 0, "received": 10, "sent": 90}
+		}`,
+		"/api/8/http/upstreams": `{
+			"backend": {"keepalive": 4, "zombies": 0, "peers": [
+				{"server": "10.0.0.1:8080", "state": "up", "active": 2, "requests": 300, "responses": {"2xx": 295, "5xx": 5}, "sent": 100, "received": 200,
+				 "fails": 1, "unavail": 0, "header_time": 12, "response_time": 20, "health_checks": {"checks": 60, "fails": 0}},
+				{"server": "10.0.0.2:8080", "state": "unhealthy", "active": 0, "requests": 0, "responses": {}, "health_checks": {"checks": 60, "fails": 60}}
+			]}
+		}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer srv.Close()
+
+	f, err := newFilter(&Config{ServerZoneExcludeRegex: "internal"})
+	require.NoError(t, err)
+	col := newPlusCollector(log.NewNopLogger(), http.DefaultClient, srv.URL+"/api/8", f)
+
+	expected := `
+# HELP nginxplus_up Whether the last scrape of the NGINX Plus API was successful.
+# TYPE nginxplus_up gauge
+nginxplus_up 1
+# HELP nginxplus_connections_accepted Total number of accepted client connections.
+# TYPE nginxplus_connections_accepted counter
+nginxplus_connections_accepted 100
+# HELP nginxplus_server_zone_requests Total number of client requests.
+# TYPE nginxplus_server_zone_requests counter
+nginxplus_server_zone_requests{server_zone="shop"} 400
+# HELP nginxplus_upstream_server_state State of the upstream server: 1 = up, 2 = draining, 3 = down, 4 = unavail, 5 = checking, 6 = unhealthy.
+# TYPE nginxplus_upstream_server_state gauge
+nginxplus_upstream_server_state{server="10.0.0.1:8080",upstream="backend"} 1
+nginxplus_upstream_server_state{server="10.0.0.2:8080",upstream="backend"} 6
+# HELP nginxplus_upstream_server_response_time Average time to get the full response from the upstream server, in milliseconds.
+# TYPE nginxplus_upstream_server_response_time gauge
+nginxplus_upstream_server_response_time{server="10.0.0.1:8080",upstream="backend"} 20
+`
+	err = testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"nginxplus_up",
+		"nginxplus_connections_accepted",
+		"nginxplus_server_zone_requests",
+		"nginxplus_upstream_server_state",
+		"nginxplus_upstream_server_response_time",
+	)
+	require.NoError(t, err)
+}
+
+func TestPlusCollector_Down(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	col := newPlusCollector(log.NewNopLogger(), http.DefaultClient, srv.URL+"/api/8", &filter{})
+	expected := `
+# HELP nginxplus_up Whether the last scrape of the NGINX Plus API was successful.
+# TYPE nginxplus_up gauge
+nginxplus_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected)))
+}
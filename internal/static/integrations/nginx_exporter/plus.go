@@ -0,0 +1,282 @@
+package nginx_exporter //nolint:golint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// responseCodes are the classes of response codes reported by the NGINX Plus
+// API.
+var responseCodes = []string{"1xx", "2xx", "3xx", "4xx", "5xx"}
+
+// upstreamServerStates maps the states of upstream servers to the values of
+// nginxplus_upstream_server_state.
+var upstreamServerStates = map[string]float64{
+	"up":        1,
+	"draining":  2,
+	"down":      3,
+	"unavail":   4,
+	"checking":  5,
+	"unhealthy": 6,
+}
+
+func plusDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc("nginxplus_"+name, help, labels, nil)
+}
+
+var (
+	plusUpDesc = plusDesc("up", "Whether the last scrape of the NGINX Plus API was successful.")
+
+	plusDescs = struct {
+		connectionsAccepted, connectionsDropped, connectionsActive, connectionsIdle *prometheus.Desc
+		httpRequestsTotal, httpRequestsCurrent                                      *prometheus.Desc
+
+		serverZoneProcessing, serverZoneRequests, serverZoneResponses *prometheus.Desc
+		serverZoneDiscarded, serverZoneReceived, serverZoneSent       *prometheus.Desc
+
+		upstreamKeepalive, upstreamZombies                                                     *prometheus.Desc
+		upstreamServerState, upstreamServerActive, upstreamServerRequests                      *prometheus.Desc
+		upstreamServerResponses, upstreamServerSent, upstreamServerReceived                    *prometheus.Desc
+		upstreamServerFails, upstreamServerUnavail, upstreamServerHeaderTime                   *prometheus.Desc
+		upstreamServerResponseTime, upstreamServerHealthChecks, upstreamServerHealthCheckFails *prometheus.Desc
+	}{
+		connectionsAccepted: plusDesc("connections_accepted", "Total number of accepted client connections."),
+		connectionsDropped:  plusDesc("connections_dropped", "Total number of dropped client connections."),
+		connectionsActive:   plusDesc("connections_active", "Number of active client connections."),
+		connectionsIdle:     plusDesc("connections_idle", "Number of idle client connections."),
+		httpRequestsTotal:   plusDesc("http_requests_total", "Total number of HTTP requests."),
+		httpRequestsCurrent: plusDesc("http_requests_current", "Number of HTTP requests being processed."),
+
+		serverZoneProcessing: plusDesc("server_zone_processing", "Number of client requests being processed.", "server_zone"),
+		serverZoneRequests:   plusDesc("server_zone_requests", "Total number of client requests.", "server_zone"),
+		serverZoneResponses:  plusDesc("server_zone_responses", "Total number of responses by class of status code.", "server_zone", "code"),
+		serverZoneDiscarded:  plusDesc("server_zone_discarded", "Total number of requests completed without sending a response.", "server_zone"),
+		serverZoneReceived:   plusDesc("server_zone_received", "Total bytes received from clients.", "server_zone"),
+		serverZoneSent:       plusDesc("server_zone_sent", "Total bytes sent to clients.", "server_zone"),
+
+		upstreamKeepalive:              plusDesc("upstream_keepalive", "Number of idle keepalive connections.", "upstream"),
+		upstreamZombies:                plusDesc("upstream_zombies", "Number of servers removed from the group but still processing requests.", "upstream"),
+		upstreamServerState:            plusDesc("upstream_server_state", "State of the upstream server: 1 = up, 2 = draining, 3 = down, 4 = unavail, 5 = checking, 6 = unhealthy.", "upstream", "server"),
+		upstreamServerActive:           plusDesc("upstream_server_active", "Number of active connections to the upstream server.", "upstream", "server"),
+		upstreamServerRequests:         plusDesc("upstream_server_requests", "Total number of client requests forwarded to the upstream server.", "upstream", "server"),
+		upstreamServerResponses:        plusDesc("upstream_server_responses", "Total number of responses of the upstream server by class of status code.", "upstream", "server", "code"),
+		upstreamServerSent:             plusDesc("upstream_server_sent", "Total bytes sent to the upstream server.", "upstream", "server"),
+		upstreamServerReceived:         plusDesc("upstream_server_received", "Total bytes received from the upstream server.", "upstream", "server"),
+		upstreamServerFails:            plusDesc("upstream_server_fails", "Total number of unsuccessful attempts to communicate with the upstream server.", "upstream", "server"),
+		upstreamServerUnavail:          plusDesc("upstream_server_unavail", "Total number of times the upstream server became unavailable.", "upstream", "server"),
+		upstreamServerHeaderTime:       plusDesc("upstream_server_header_time", "Average time to get the response header from the upstream server, in milliseconds.", "upstream", "server"),
+		upstreamServerResponseTime:     plusDesc("upstream_server_response_time", "Average time to get the full response from the upstream server, in milliseconds.", "upstream", "server"),
+		upstreamServerHealthChecks:     plusDesc("upstream_server_health_checks_checks", "Total number of health check requests made to the upstream server.", "upstream", "server"),
+		upstreamServerHealthCheckFails: plusDesc("upstream_server_health_checks_fails", "Total number of failed health checks of the upstream server.", "upstream", "server"),
+	}
+)
+
+type plusConnections struct {
+	Accepted float64 `json:"accepted"`
+	Dropped  float64 `json:"dropped"`
+	Active   float64 `json:"active"`
+	Idle     float64 `json:"idle"`
+}
+
+type plusHTTPRequests struct {
+	Total   float64 `json:"total"`
+	Current float64 `json:"current"`
+}
+
+type plusResponses map[string]float64
+
+type plusServerZone struct {
+	Processing float64       `json:"processing"`
+	Requests   float64       `json:"requests"`
+	Responses  plusResponses `json:"responses"`
+	Discarded  float64       `json:"discarded"`
+	Received   float64       `json:"received"`
+	Sent       float64       `json:"sent"`
+}
+
+type plusUpstream struct {
+	Keepalive float64 `json:"keepalive"`
+	Zombies   float64 `json:"zombies"`
+	Peers     []struct {
+		Server       string        `json:"server"`
+		State        string        `json:"state"`
+		Active       float64       `json:"active"`
+		Requests     float64       `json:"requests"`
+		Responses    plusResponses `json:"responses"`
+		Sent         float64       `json:"sent"`
+		Received     float64       `json:"received"`
+		Fails        float64       `json:"fails"`
+		Unavail      float64       `json:"unavail"`
+		HeaderTime   *float64      `json:"header_time"`
+		ResponseTime *float64      `json:"response_time"`
+		HealthChecks struct {
+			Checks float64 `json:"checks"`
+			Fails  float64 `json:"fails"`
+		} `json:"health_checks"`
+	} `json:"peers"`
+}
+
+// plusCollector collects metrics from the NGINX Plus API on every scrape.
+type plusCollector struct {
+	log    log.Logger
+	client *http.Client
+	apiURL string
+	filter *filter
+}
+
+var _ prometheus.Collector = (*plusCollector)(nil)
+
+func newPlusCollector(l log.Logger, cl *http.Client, apiURL string, f *filter) *plusCollector {
+	return &plusCollector{log: l, client: cl, apiURL: apiURL, filter: f}
+}
+
+// Describe implements prometheus.Collector.
+func (c *plusCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		plusUpDesc,
+		plusDescs.connectionsAccepted, plusDescs.connectionsDropped, plusDescs.connectionsActive, plusDescs.connectionsIdle,
+		plusDescs.httpRequestsTotal, plusDescs.httpRequestsCurrent,
+		plusDescs.serverZoneProcessing, plusDescs.serverZoneRequests, plusDescs.serverZoneResponses,
+		plusDescs.serverZoneDiscarded, plusDescs.serverZoneReceived, plusDescs.serverZoneSent,
+		plusDescs.upstreamKeepalive, plusDescs.upstreamZombies,
+		plusDescs.upstreamServerState, plusDescs.upstreamServerActive, plusDescs.upstreamServerRequests,
+		plusDescs.upstreamServerResponses, plusDescs.upstreamServerSent, plusDescs.upstreamServerReceived,
+		plusDescs.upstreamServerFails, plusDescs.upstreamServerUnavail, plusDescs.upstreamServerHeaderTime,
+		plusDescs.upstreamServerResponseTime, plusDescs.upstreamServerHealthChecks, plusDescs.upstreamServerHealthCheckFails,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *plusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	up := 1.0
+	for _, collect := range []func(context.Context, chan<- prometheus.Metric) error{
+		c.collectConnections,
+		c.collectHTTPRequests,
+		c.collectServerZones,
+		c.collectUpstreams,
+	} {
+		if err := collect(ctx, ch); err != nil {
+			level.Error(c.log).Log("msg", "failed to collect NGINX Plus metrics", "err", err)
+			up = 0
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(plusUpDesc, prometheus.GaugeValue, up)
+}
+
+func (c *plusCollector) collectConnections(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var conns plusConnections
+	if err := c.get(ctx, "/connections", &conns); err != nil {
+		return err
+	}
+	counter(ch, plusDescs.connectionsAccepted, conns.Accepted)
+	counter(ch, plusDescs.connectionsDropped, conns.Dropped)
+	gauge(ch, plusDescs.connectionsActive, conns.Active)
+	gauge(ch, plusDescs.connectionsIdle, conns.Idle)
+	return nil
+}
+
+func (c *plusCollector) collectHTTPRequests(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var reqs plusHTTPRequests
+	if err := c.get(ctx, "/http/requests", &reqs); err != nil {
+		return err
+	}
+	counter(ch, plusDescs.httpRequestsTotal, reqs.Total)
+	gauge(ch, plusDescs.httpRequestsCurrent, reqs.Current)
+	return nil
+}
+
+func (c *plusCollector) collectServerZones(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var zones map[string]plusServerZone
+	if err := c.get(ctx, "/http/server_zones", &zones); err != nil {
+		return err
+	}
+
+	for name, z := range zones {
+		if !c.filter.serverZone(name) {
+			continue
+		}
+		gauge(ch, plusDescs.serverZoneProcessing, z.Processing, name)
+		counter(ch, plusDescs.serverZoneRequests, z.Requests, name)
+		for _, code := range responseCodes {
+			counter(ch, plusDescs.serverZoneResponses, z.Responses[code], name, code)
+		}
+		counter(ch, plusDescs.serverZoneDiscarded, z.Discarded, name)
+		counter(ch, plusDescs.serverZoneReceived, z.Received, name)
+		counter(ch, plusDescs.serverZoneSent, z.Sent, name)
+	}
+	return nil
+}
+
+func (c *plusCollector) collectUpstreams(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var upstreams map[string]plusUpstream
+	if err := c.get(ctx, "/http/upstreams", &upstreams); err != nil {
+		return err
+	}
+
+	for name, u := range upstreams {
+		if !c.filter.upstream(name) {
+			continue
+		}
+		gauge(ch, plusDescs.upstreamKeepalive, u.Keepalive, name)
+		gauge(ch, plusDescs.upstreamZombies, u.Zombies, name)
+
+		for _, p := range u.Peers {
+			gauge(ch, plusDescs.upstreamServerState, upstreamServerStates[p.State], name, p.Server)
+			gauge(ch, plusDescs.upstreamServerActive, p.Active, name, p.Server)
+			counter(ch, plusDescs.upstreamServerRequests, p.Requests, name, p.Server)
+			for _, code := range responseCodes {
+				counter(ch, plusDescs.upstreamServerResponses, p.Responses[code], name, p.Server, code)
+			}
+			counter(ch, plusDescs.upstreamServerSent, p.Sent, name, p.Server)
+			counter(ch, plusDescs.upstreamServerReceived, p.Received, name, p.Server)
+			counter(ch, plusDescs.upstreamServerFails, p.Fails, name, p.Server)
+			counter(ch, plusDescs.upstreamServerUnavail, p.Unavail, name, p.Server)
+			// Response times are only reported once the server handled
+			// requests.
+			if p.HeaderTime != nil {
+				gauge(ch, plusDescs.upstreamServerHeaderTime, *p.HeaderTime, name, p.Server)
+			}
+			if p.ResponseTime != nil {
+				gauge(ch, plusDescs.upstreamServerResponseTime, *p.ResponseTime, name, p.Server)
+			}
+			counter(ch, plusDescs.upstreamServerHealthChecks, p.HealthChecks.Checks, name, p.Server)
+			counter(ch, plusDescs.upstreamServerHealthCheckFails, p.HealthChecks.Fails, name, p.Server)
+		}
+	}
+	return nil
+}
+
+func (c *plusCollector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func gauge(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labelValues ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)
+}
+
+func counter(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labelValues ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labelValues...)
+}
@@ -0,0 +1,158 @@
+package nginx_exporter //nolint:golint
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	upDesc = prometheus.NewDesc("nginx_up", "Whether the last scrape of NGINX was successful.", nil, nil)
+
+	stubStatusDescs = struct {
+		active, accepted, handled, reading, writing, waiting, requests *prometheus.Desc
+	}{
+		active:   prometheus.NewDesc("nginx_connections_active", "Number of active client connections.", nil, nil),
+		accepted: prometheus.NewDesc("nginx_connections_accepted", "Total number of accepted client connections.", nil, nil),
+		handled:  prometheus.NewDesc("nginx_connections_handled", "Total number of handled client connections.", nil, nil),
+		reading:  prometheus.NewDesc("nginx_connections_reading", "Number of connections where NGINX is reading the request header.", nil, nil),
+		writing:  prometheus.NewDesc("nginx_connections_writing", "Number of connections where NGINX is writing the response back to the client.", nil, nil),
+		waiting:  prometheus.NewDesc("nginx_connections_waiting", "Number of idle client connections waiting for a request.", nil, nil),
+		requests: prometheus.NewDesc("nginx_http_requests_total", "Total number of client requests.", nil, nil),
+	}
+)
+
+// stubStatus is the content of the stub_status page.
+type stubStatus struct {
+	active, accepted, handled, requests float64
+	reading, writing, waiting           float64
+}
+
+// stubStatusCollector collects metrics from the stub_status page of NGINX on
+// every scrape.
+type stubStatusCollector struct {
+	log    log.Logger
+	client *http.Client
+	url    string
+}
+
+var _ prometheus.Collector = (*stubStatusCollector)(nil)
+
+func newStubStatusCollector(l log.Logger, cl *http.Client, url string) *stubStatusCollector {
+	return &stubStatusCollector{log: l, client: cl, url: url}
+}
+
+// Describe implements prometheus.Collector.
+func (c *stubStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		upDesc, stubStatusDescs.active, stubStatusDescs.accepted, stubStatusDescs.handled,
+		stubStatusDescs.reading, stubStatusDescs.writing, stubStatusDescs.waiting, stubStatusDescs.requests,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *stubStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	s, err := c.fetch(context.Background())
+	if err != nil {
+		level.Error(c.log).Log("msg", "failed to collect the stub_status of NGINX", "err", err)
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.active, prometheus.GaugeValue, s.active)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.accepted, prometheus.CounterValue, s.accepted)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.handled, prometheus.CounterValue, s.handled)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.reading, prometheus.GaugeValue, s.reading)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.writing, prometheus.GaugeValue, s.writing)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.waiting, prometheus.GaugeValue, s.waiting)
+	ch <- prometheus.MustNewConstMetric(stubStatusDescs.requests, prometheus.CounterValue, s.requests)
+}
+
+func (c *stubStatusCollector) fetch(ctx context.Context) (*stubStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parseStubStatus(resp.Body)
+}
+
+// parseStubStatus parses the content of the stub_status page, which looks
+// like the following:
+//
+//	Active connections: 291
+//	server accepts handled requests
+//	 16630948 16630948 31070465
+//	Reading: 6 Writing: 179 Waiting: 106
+func parseStubStatus(r io.Reader) (*stubStatus, error) {
+	var (
+		s     stubStatus
+		lines []string
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) != 4 {
+		return nil, fmt.Errorf("invalid stub_status: expected 4 lines, got %d", len(lines))
+	}
+
+	active, ok := strings.CutPrefix(lines[0], "Active connections:")
+	if !ok {
+		return nil, fmt.Errorf("invalid stub_status: unexpected line %q", lines[0])
+	}
+	if err := parseFloats(strings.Fields(active), &s.active); err != nil {
+		return nil, err
+	}
+
+	if err := parseFloats(strings.Fields(lines[2]), &s.accepted, &s.handled, &s.requests); err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(lines[3])
+	if len(fields) != 6 || fields[0] != "Reading:" || fields[2] != "Writing:" || fields[4] != "Waiting:" {
+		return nil, fmt.Errorf("invalid stub_status: unexpected line %q", lines[3])
+	}
+	if err := parseFloats([]string{fields[1], fields[3], fields[5]}, &s.reading, &s.writing, &s.waiting); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func parseFloats(fields []string, dsts ...*float64) error {
+	if len(fields) != len(dsts) {
+		return fmt.Errorf("invalid stub_status: expected %d values, got %q", len(dsts), fields)
+	}
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return fmt.Errorf("invalid stub_status: %w", err)
+		}
+		*dsts[i] = v
+	}
+	return nil
+}
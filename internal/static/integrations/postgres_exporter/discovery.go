@@ -0,0 +1,211 @@
+package postgres_exporter //nolint:golint
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// databaseFilter selects the discovered databases to collect metrics from.
+type databaseFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// newDatabaseFilter builds a filter from the exact database names and the
+// regular expressions of c.
+func newDatabaseFilter(c *Config) (*databaseFilter, error) {
+	var f databaseFilter
+	for _, name := range c.IncludeDatabases {
+		f.include = append(f.include, regexp.MustCompile("^"+regexp.QuoteMeta(name)+"$"))
+	}
+	for _, name := range c.ExcludeDatabases {
+		f.exclude = append(f.exclude, regexp.MustCompile("^"+regexp.QuoteMeta(name)+"$"))
+	}
+	for _, expr := range c.IncludeDatabasesRegex {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid include database regex %q: %w", expr, err)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, expr := range c.ExcludeDatabasesRegex {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude database regex %q: %w", expr, err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return &f, nil
+}
+
+// match returns true if metrics must be collected from the database. Excluded
+// databases are never collected, and databases are only collected if they're
+// included when there's at least one include rule.
+func (f *databaseFilter) match(name string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// discoveringCollector periodically lists the databases of the server of the
+// first DSN, and collects metrics from the databases which match a filter on
+// top of the configured DSNs.
+//
+// The postgres_exporter can't update the databases it collects from, so the
+// exporter is replaced whenever the matching databases change.
+type discoveringCollector struct {
+	log           log.Logger
+	dsns          []string
+	filter        *databaseFilter
+	listDatabases func(ctx context.Context, dsn string) ([]string, error)
+	newExporter   func(dsns []string) prometheus.Collector
+
+	mut          sync.RWMutex
+	exporterDSNs []string
+	exporter     prometheus.Collector
+}
+
+var _ prometheus.Collector = (*discoveringCollector)(nil)
+
+func newDiscoveringCollector(l log.Logger, dsns []string, filter *databaseFilter, newExporter func(dsns []string) prometheus.Collector) *discoveringCollector {
+	return &discoveringCollector{
+		log:           l,
+		dsns:          dsns,
+		filter:        filter,
+		listDatabases: listDatabases,
+		newExporter:   newExporter,
+
+		exporterDSNs: dsns,
+		exporter:     newExporter(dsns),
+	}
+}
+
+// Run refreshes the discovered databases every interval until ctx is
+// canceled.
+func (c *discoveringCollector) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *discoveringCollector) refresh(ctx context.Context) {
+	names, err := c.listDatabases(ctx, c.dsns[0])
+	if err != nil {
+		level.Warn(c.log).Log("msg", "failed to discover databases, keeping the previously discovered databases", "err", err)
+		return
+	}
+
+	dsns := slices.Clone(c.dsns)
+	for _, name := range names {
+		if !c.filter.match(name) {
+			continue
+		}
+		dsn, err := dsnForDatabase(c.dsns[0], name)
+		if err != nil {
+			level.Warn(c.log).Log("msg", "failed to build the DSN of a discovered database", "database", name, "err", err)
+			continue
+		}
+		if !slices.Contains(dsns, dsn) {
+			dsns = append(dsns, dsn)
+		}
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if slices.Equal(dsns, c.exporterDSNs) {
+		return
+	}
+	level.Info(c.log).Log("msg", "discovered databases changed", "databases", len(dsns)-len(c.dsns))
+	c.exporterDSNs = dsns
+	c.exporter = c.newExporter(dsns)
+}
+
+// Describe implements prometheus.Collector. The metrics depend on the
+// discovered databases, so the collector is unchecked.
+func (c *discoveringCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *discoveringCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.RLock()
+	exporter := c.exporter
+	c.mut.RUnlock()
+
+	exporter.Collect(ch)
+}
+
+// listDatabases returns the names of the databases of the server of dsn which
+// accept connections.
+func listDatabases(ctx context.Context, dsn string) ([]string, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SELECT datname FROM pg_database WHERE datallowconn = true AND datistemplate = false ORDER BY datname")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// dsnForDatabase returns dsn, connecting to the database name instead of the
+// database of dsn. dsn can either be a URL or a keyword/value connection
+// string.
+func dsnForDatabase(dsn string, name string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", err
+		}
+		u.Path = "/" + name
+		u.RawPath = ""
+		return u.String(), nil
+	}
+
+	// The last occurrence of a keyword wins in keyword/value connection
+	// strings.
+	escaper := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return fmt.Sprintf("%s dbname='%s'", dsn, escaper.Replace(name)), nil
+}
@@ -2,9 +2,11 @@
 package postgres_exporter //nolint:golint
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/static/integrations"
@@ -13,9 +15,14 @@ import (
 	"github.com/lib/pq"
 	"github.com/prometheus-community/postgres_exporter/cmd/postgres_exporter"
 	"github.com/prometheus-community/postgres_exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
 	config_util "github.com/prometheus/common/config"
 )
 
+// defaultDiscoveryRefreshInterval is the interval at which databases are
+// discovered when DiscoveryRefreshInterval isn't set.
+const defaultDiscoveryRefreshInterval = time.Minute
+
 // Config controls the postgres_exporter integration.
 type Config struct {
 	// DataSourceNames to use to connect to Postgres.
@@ -37,6 +44,13 @@ type Config struct {
 	// this is only used for the first DSN provided and only some collectors can be enabled/disabled this way. See the
 	// user-facing docs for more information.
 	EnabledCollectors []string
+	// IncludeDatabasesRegex and ExcludeDatabasesRegex are regular expressions
+	// matching the names of the databases to autodiscover. When either is set,
+	// the databases of the first DSN are discovered every
+	// DiscoveryRefreshInterval instead of by the postgres_exporter.
+	IncludeDatabasesRegex    []string
+	ExcludeDatabasesRegex    []string
+	DiscoveryRefreshInterval time.Duration
 }
 
 // Name returns the name of the integration this config is for.
@@ -148,21 +162,49 @@ func New(log log.Logger, cfg *Config) (integrations.Integration, error) {
 		return nil, err
 	}
 
-	e := postgres_exporter.NewExporter(
-		dsns,
-		postgres_exporter.DisableDefaultMetrics(cfg.DisableDefaultMetrics),
-		postgres_exporter.WithUserQueriesPath(cfg.QueryPath),
-		postgres_exporter.DisableSettingsMetrics(cfg.DisableSettingsMetrics),
-		postgres_exporter.AutoDiscoverDatabases(cfg.AutodiscoverDatabases),
-		postgres_exporter.ExcludeDatabases(cfg.ExcludeDatabases),
-		postgres_exporter.IncludeDatabases(strings.Join(cfg.IncludeDatabases, ",")),
-		postgres_exporter.WithLogger(log),
-		postgres_exporter.WithMetricPrefix("pg"),
+	newExporter := func(dsns []string, autodiscover bool) *postgres_exporter.Exporter {
+		return postgres_exporter.NewExporter(
+			dsns,
+			postgres_exporter.DisableDefaultMetrics(cfg.DisableDefaultMetrics),
+			postgres_exporter.WithUserQueriesPath(cfg.QueryPath),
+			postgres_exporter.DisableSettingsMetrics(cfg.DisableSettingsMetrics),
+			postgres_exporter.AutoDiscoverDatabases(autodiscover),
+			postgres_exporter.ExcludeDatabases(cfg.ExcludeDatabases),
+			postgres_exporter.IncludeDatabases(strings.Join(cfg.IncludeDatabases, ",")),
+			postgres_exporter.WithLogger(log),
+			postgres_exporter.WithMetricPrefix("pg"),
+		)
+	}
+
+	var (
+		e       prometheus.Collector
+		options []integrations.CollectorIntegrationConfig
 	)
+	if cfg.AutodiscoverDatabases && (len(cfg.IncludeDatabasesRegex) > 0 || len(cfg.ExcludeDatabasesRegex) > 0) {
+		filter, err := newDatabaseFilter(cfg)
+		if err != nil {
+			return nil, err
+		}
+		interval := cfg.DiscoveryRefreshInterval
+		if interval <= 0 {
+			interval = defaultDiscoveryRefreshInterval
+		}
+
+		dc := newDiscoveringCollector(log, dsns, filter, func(dsns []string) prometheus.Collector {
+			return newExporter(dsns, false)
+		})
+		e = dc
+		options = append(options, integrations.WithRunner(func(ctx context.Context) error {
+			return dc.Run(ctx, interval)
+		}))
+	} else {
+		e = newExporter(dsns, cfg.AutodiscoverDatabases)
+	}
 
 	if cfg.DisableDefaultMetrics {
 		// Don't include the collector metrics if the default metrics are disabled.
-		return integrations.NewCollectorIntegration(cfg.Name(), integrations.WithCollectors(e)), nil
+		options = append(options, integrations.WithCollectors(e))
+		return integrations.NewCollectorIntegration(cfg.Name(), options...), nil
 	}
 
 	// On top of the exporter's metrics, the postgres exporter also has metrics exposed via collector package.
@@ -174,5 +216,6 @@ func New(log log.Logger, cfg *Config) (integrations.Integration, error) {
 		return nil, fmt.Errorf("failed to create postgres_exporter collector: %w", err)
 	}
 
-	return integrations.NewCollectorIntegration(cfg.Name(), integrations.WithCollectors(e, c)), nil
+	options = append(options, integrations.WithCollectors(e, c))
+	return integrations.NewCollectorIntegration(cfg.Name(), options...), nil
 }
@@ -0,0 +1,150 @@
+package rabbitmq_exporter //nolint:golint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// errNotFound is returned for resources the management API doesn't know
+// about, like health checks of older RabbitMQ versions.
+var errNotFound = errors.New("not found")
+
+// client is a minimal client of the RabbitMQ management API.
+type client struct {
+	url      string
+	username string
+	password string
+	http     *http.Client
+}
+
+type overview struct {
+	ClusterName     string `json:"cluster_name"`
+	RabbitMQVersion string `json:"rabbitmq_version"`
+	ErlangVersion   string `json:"erlang_version"`
+	ObjectTotals    struct {
+		Channels    float64 `json:"channels"`
+		Connections float64 `json:"connections"`
+		Consumers   float64 `json:"consumers"`
+		Exchanges   float64 `json:"exchanges"`
+		Queues      float64 `json:"queues"`
+	} `json:"object_totals"`
+	QueueTotals struct {
+		Messages               float64 `json:"messages"`
+		MessagesReady          float64 `json:"messages_ready"`
+		MessagesUnacknowledged float64 `json:"messages_unacknowledged"`
+	} `json:"queue_totals"`
+}
+
+type queue struct {
+	Name                   string  `json:"name"`
+	Vhost                  string  `json:"vhost"`
+	State                  string  `json:"state"`
+	Messages               float64 `json:"messages"`
+	MessagesReady          float64 `json:"messages_ready"`
+	MessagesUnacknowledged float64 `json:"messages_unacknowledged"`
+	Consumers              float64 `json:"consumers"`
+	Memory                 float64 `json:"memory"`
+	MessageStats           *struct {
+		Publish    float64 `json:"publish"`
+		DeliverGet float64 `json:"deliver_get"`
+		Ack        float64 `json:"ack"`
+		Redeliver  float64 `json:"redeliver"`
+	} `json:"message_stats"`
+}
+
+type exchange struct {
+	Name         string `json:"name"`
+	Vhost        string `json:"vhost"`
+	Type         string `json:"type"`
+	MessageStats *struct {
+		PublishIn  float64 `json:"publish_in"`
+		PublishOut float64 `json:"publish_out"`
+	} `json:"message_stats"`
+}
+
+type node struct {
+	Name          string   `json:"name"`
+	Running       bool     `json:"running"`
+	MemUsed       float64  `json:"mem_used"`
+	MemLimit      float64  `json:"mem_limit"`
+	MemAlarm      bool     `json:"mem_alarm"`
+	DiskFree      float64  `json:"disk_free"`
+	DiskFreeLimit float64  `json:"disk_free_limit"`
+	DiskFreeAlarm bool     `json:"disk_free_alarm"`
+	FDUsed        float64  `json:"fd_used"`
+	FDTotal       float64  `json:"fd_total"`
+	SocketsUsed   float64  `json:"sockets_used"`
+	SocketsTotal  float64  `json:"sockets_total"`
+	Uptime        float64  `json:"uptime"`
+	Partitions    []string `json:"partitions"`
+}
+
+func (c *client) overview(ctx context.Context) (overview, error) {
+	var o overview
+	_, err := c.get(ctx, "/api/overview", &o)
+	return o, err
+}
+
+func (c *client) queues(ctx context.Context) ([]queue, error) {
+	var qs []queue
+	_, err := c.get(ctx, "/api/queues", &qs)
+	return qs, err
+}
+
+func (c *client) exchanges(ctx context.Context) ([]exchange, error) {
+	var es []exchange
+	_, err := c.get(ctx, "/api/exchanges", &es)
+	return es, err
+}
+
+func (c *client) nodes(ctx context.Context) ([]node, error) {
+	var ns []node
+	_, err := c.get(ctx, "/api/nodes", &ns)
+	return ns, err
+}
+
+// healthCheck returns whether the health check passes. Failing health checks
+// are reported by the management API with a 503 status code.
+func (c *client) healthCheck(ctx context.Context, check string) (bool, error) {
+	var resp struct {
+		Status string `json:"status"`
+	}
+	status, err := c.get(ctx, "/api/health/checks/"+check, &resp)
+	if status == http.StatusServiceUnavailable {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Status == "ok", nil
+}
+
+func (c *client) get(ctx context.Context, path string, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.url, "/")+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusNotFound:
+		return resp.StatusCode, errNotFound
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("GET %s: unexpected status %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
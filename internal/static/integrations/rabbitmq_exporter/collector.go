@@ -0,0 +1,296 @@
+package rabbitmq_exporter //nolint:golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "rabbitmq"
+
+// healthChecks are the health checks of the management API which are
+// collected by the health collector.
+var healthChecks = []string{"alarms", "virtual-hosts", "node-is-quorum-critical"}
+
+func newDesc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+}
+
+var (
+	upDesc   = newDesc("", "up", "Whether the last scrape of the RabbitMQ management API was successful.")
+	infoDesc = newDesc("", "info", "Information about the RabbitMQ cluster.", "cluster", "rabbitmq_version", "erlang_version")
+
+	overviewDescs = struct {
+		channels, connections, consumers, exchanges, queues *prometheus.Desc
+		messages, messagesReady, messagesUnacked            *prometheus.Desc
+	}{
+		channels:        newDesc("", "channels", "Number of channels."),
+		connections:     newDesc("", "connections", "Number of connections."),
+		consumers:       newDesc("", "consumers", "Number of consumers."),
+		exchanges:       newDesc("", "exchanges", "Number of exchanges."),
+		queues:          newDesc("", "queues", "Number of queues."),
+		messages:        newDesc("", "messages", "Number of messages in all the queues."),
+		messagesReady:   newDesc("", "messages_ready", "Number of messages ready to be delivered in all the queues."),
+		messagesUnacked: newDesc("", "messages_unacknowledged", "Number of messages delivered but not yet acknowledged in all the queues."),
+	}
+
+	queueLabels = []string{"vhost", "queue"}
+	queueDescs  = struct {
+		messages, messagesReady, messagesUnacked, consumers, memory *prometheus.Desc
+		published, delivered, acked, redelivered                    *prometheus.Desc
+		state                                                       *prometheus.Desc
+	}{
+		messages:        newDesc("queue", "messages", "Number of messages in the queue.", queueLabels...),
+		messagesReady:   newDesc("queue", "messages_ready", "Number of messages ready to be delivered in the queue.", queueLabels...),
+		messagesUnacked: newDesc("queue", "messages_unacknowledged", "Number of messages delivered but not yet acknowledged in the queue.", queueLabels...),
+		consumers:       newDesc("queue", "consumers", "Number of consumers of the queue.", queueLabels...),
+		memory:          newDesc("queue", "memory_bytes", "Bytes of memory used by the queue.", queueLabels...),
+		published:       newDesc("queue", "messages_published_total", "Total number of messages published to the queue.", queueLabels...),
+		delivered:       newDesc("queue", "messages_delivered_total", "Total number of messages delivered from the queue.", queueLabels...),
+		acked:           newDesc("queue", "messages_acked_total", "Total number of messages acknowledged from the queue.", queueLabels...),
+		redelivered:     newDesc("queue", "messages_redelivered_total", "Total number of messages redelivered from the queue.", queueLabels...),
+		state:           newDesc("queue", "state", "State of the queue.", append(queueLabels, "state")...),
+	}
+
+	exchangeLabels = []string{"vhost", "exchange", "type"}
+	exchangeDescs  = struct {
+		publishedIn, publishedOut *prometheus.Desc
+	}{
+		publishedIn:  newDesc("exchange", "messages_published_in_total", "Total number of messages published to the exchange.", exchangeLabels...),
+		publishedOut: newDesc("exchange", "messages_published_out_total", "Total number of messages routed by the exchange.", exchangeLabels...),
+	}
+
+	nodeDescs = struct {
+		running, memUsed, memLimit, memAlarm, diskFree, diskFreeLimit, diskFreeAlarm *prometheus.Desc
+		fdUsed, fdTotal, socketsUsed, socketsTotal, partitions, uptime               *prometheus.Desc
+	}{
+		running:       newDesc("node", "running", "Whether the node is running.", "node"),
+		memUsed:       newDesc("node", "mem_used_bytes", "Bytes of memory used by the node.", "node"),
+		memLimit:      newDesc("node", "mem_limit_bytes", "Memory high watermark of the node, in bytes.", "node"),
+		memAlarm:      newDesc("node", "mem_alarm", "Whether the memory alarm of the node is raised.", "node"),
+		diskFree:      newDesc("node", "disk_free_bytes", "Bytes of free disk space of the node.", "node"),
+		diskFreeLimit: newDesc("node", "disk_free_limit_bytes", "Free disk space limit of the node, in bytes.", "node"),
+		diskFreeAlarm: newDesc("node", "disk_free_alarm", "Whether the free disk space alarm of the node is raised.", "node"),
+		fdUsed:        newDesc("node", "fd_used", "Number of file descriptors used by the node.", "node"),
+		fdTotal:       newDesc("node", "fd_total", "Maximum number of file descriptors of the node.", "node"),
+		socketsUsed:   newDesc("node", "sockets_used", "Number of sockets used by the node.", "node"),
+		socketsTotal:  newDesc("node", "sockets_total", "Maximum number of sockets of the node.", "node"),
+		partitions:    newDesc("node", "partitions", "Number of nodes the node is partitioned from.", "node"),
+		uptime:        newDesc("node", "uptime_seconds", "Uptime of the node, in seconds.", "node"),
+	}
+
+	healthCheckDesc = newDesc("health_check", "ok", "Whether the health check of the management API passes.", "check")
+)
+
+// collector collects metrics from the RabbitMQ management API on every
+// scrape.
+type collector struct {
+	log        log.Logger
+	client     *client
+	collectors []string
+	filter     *filter
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+func newCollector(l log.Logger, cl *client, collectors []string, f *filter) (*collector, error) {
+	for _, name := range collectors {
+		if !slices.Contains(AvailableCollectors, name) {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+	}
+	return &collector{
+		log:        l,
+		client:     cl,
+		collectors: collectors,
+		filter:     f,
+	}, nil
+}
+
+func (c *collector) enabled(name string) bool {
+	return slices.Contains(c.collectors, name)
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	if c.enabled(CollectorOverview) {
+		ch <- infoDesc
+		describeAll(ch, overviewDescs.channels, overviewDescs.connections, overviewDescs.consumers, overviewDescs.exchanges,
+			overviewDescs.queues, overviewDescs.messages, overviewDescs.messagesReady, overviewDescs.messagesUnacked)
+	}
+	if c.enabled(CollectorQueue) {
+		describeAll(ch, queueDescs.messages, queueDescs.messagesReady, queueDescs.messagesUnacked, queueDescs.consumers,
+			queueDescs.memory, queueDescs.published, queueDescs.delivered, queueDescs.acked, queueDescs.redelivered, queueDescs.state)
+	}
+	if c.enabled(CollectorExchange) {
+		describeAll(ch, exchangeDescs.publishedIn, exchangeDescs.publishedOut)
+	}
+	if c.enabled(CollectorNode) {
+		describeAll(ch, nodeDescs.running, nodeDescs.memUsed, nodeDescs.memLimit, nodeDescs.memAlarm, nodeDescs.diskFree,
+			nodeDescs.diskFreeLimit, nodeDescs.diskFreeAlarm, nodeDescs.fdUsed, nodeDescs.fdTotal, nodeDescs.socketsUsed,
+			nodeDescs.socketsTotal, nodeDescs.partitions, nodeDescs.uptime)
+	}
+	if c.enabled(CollectorHealth) {
+		ch <- healthCheckDesc
+	}
+}
+
+func describeAll(ch chan<- *prometheus.Desc, descs ...*prometheus.Desc) {
+	for _, d := range descs {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector. rabbitmq_up is 0 if any of the
+// enabled collectors failed.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	collectors := []struct {
+		name    string
+		collect func(context.Context, chan<- prometheus.Metric) error
+	}{
+		{CollectorOverview, c.collectOverview},
+		{CollectorQueue, c.collectQueues},
+		{CollectorExchange, c.collectExchanges},
+		{CollectorNode, c.collectNodes},
+		{CollectorHealth, c.collectHealth},
+	}
+
+	up := 1.0
+	for _, col := range collectors {
+		if !c.enabled(col.name) {
+			continue
+		}
+		if err := col.collect(ctx, ch); err != nil {
+			level.Error(c.log).Log("msg", "failed to collect RabbitMQ metrics", "collector", col.name, "err", err)
+			up = 0
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+}
+
+func (c *collector) collectOverview(ctx context.Context, ch chan<- prometheus.Metric) error {
+	o, err := c.client.overview(ctx)
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, o.ClusterName, o.RabbitMQVersion, o.ErlangVersion)
+	gauge(ch, overviewDescs.channels, o.ObjectTotals.Channels)
+	gauge(ch, overviewDescs.connections, o.ObjectTotals.Connections)
+	gauge(ch, overviewDescs.consumers, o.ObjectTotals.Consumers)
+	gauge(ch, overviewDescs.exchanges, o.ObjectTotals.Exchanges)
+	gauge(ch, overviewDescs.queues, o.ObjectTotals.Queues)
+	gauge(ch, overviewDescs.messages, o.QueueTotals.Messages)
+	gauge(ch, overviewDescs.messagesReady, o.QueueTotals.MessagesReady)
+	gauge(ch, overviewDescs.messagesUnacked, o.QueueTotals.MessagesUnacknowledged)
+	return nil
+}
+
+func (c *collector) collectQueues(ctx context.Context, ch chan<- prometheus.Metric) error {
+	queues, err := c.client.queues(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range queues {
+		if !c.filter.queue(q.Vhost, q.Name) {
+			continue
+		}
+
+		gauge(ch, queueDescs.messages, q.Messages, q.Vhost, q.Name)
+		gauge(ch, queueDescs.messagesReady, q.MessagesReady, q.Vhost, q.Name)
+		gauge(ch, queueDescs.messagesUnacked, q.MessagesUnacknowledged, q.Vhost, q.Name)
+		gauge(ch, queueDescs.consumers, q.Consumers, q.Vhost, q.Name)
+		gauge(ch, queueDescs.memory, q.Memory, q.Vhost, q.Name)
+		if q.State != "" {
+			gauge(ch, queueDescs.state, 1, q.Vhost, q.Name, q.State)
+		}
+		if s := q.MessageStats; s != nil {
+			counter(ch, queueDescs.published, s.Publish, q.Vhost, q.Name)
+			counter(ch, queueDescs.delivered, s.DeliverGet, q.Vhost, q.Name)
+			counter(ch, queueDescs.acked, s.Ack, q.Vhost, q.Name)
+			counter(ch, queueDescs.redelivered, s.Redeliver, q.Vhost, q.Name)
+		}
+	}
+	return nil
+}
+
+func (c *collector) collectExchanges(ctx context.Context, ch chan<- prometheus.Metric) error {
+	exchanges, err := c.client.exchanges(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range exchanges {
+		if !c.filter.vhost(e.Vhost) || e.MessageStats == nil {
+			continue
+		}
+		counter(ch, exchangeDescs.publishedIn, e.MessageStats.PublishIn, e.Vhost, e.Name, e.Type)
+		counter(ch, exchangeDescs.publishedOut, e.MessageStats.PublishOut, e.Vhost, e.Name, e.Type)
+	}
+	return nil
+}
+
+func (c *collector) collectNodes(ctx context.Context, ch chan<- prometheus.Metric) error {
+	nodes, err := c.client.nodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		gauge(ch, nodeDescs.running, boolToFloat(n.Running), n.Name)
+		if !n.Running {
+			continue
+		}
+		gauge(ch, nodeDescs.memUsed, n.MemUsed, n.Name)
+		gauge(ch, nodeDescs.memLimit, n.MemLimit, n.Name)
+		gauge(ch, nodeDescs.memAlarm, boolToFloat(n.MemAlarm), n.Name)
+		gauge(ch, nodeDescs.diskFree, n.DiskFree, n.Name)
+		gauge(ch, nodeDescs.diskFreeLimit, n.DiskFreeLimit, n.Name)
+		gauge(ch, nodeDescs.diskFreeAlarm, boolToFloat(n.DiskFreeAlarm), n.Name)
+		gauge(ch, nodeDescs.fdUsed, n.FDUsed, n.Name)
+		gauge(ch, nodeDescs.fdTotal, n.FDTotal, n.Name)
+		gauge(ch, nodeDescs.socketsUsed, n.SocketsUsed, n.Name)
+		gauge(ch, nodeDescs.socketsTotal, n.SocketsTotal, n.Name)
+		gauge(ch, nodeDescs.partitions, float64(len(n.Partitions)), n.Name)
+		gauge(ch, nodeDescs.uptime, n.Uptime/1000, n.Name)
+	}
+	return nil
+}
+
+func (c *collector) collectHealth(ctx context.Context, ch chan<- prometheus.Metric) error {
+	for _, check := range healthChecks {
+		ok, err := c.client.healthCheck(ctx, check)
+		if errors.Is(err, errNotFound) {
+			// The health check isn't supported by this version of RabbitMQ.
+			continue
+		} else if err != nil {
+			return fmt.Errorf("health check %q: %w", check, err)
+		}
+		gauge(ch, healthCheckDesc, boolToFloat(ok), check)
+	}
+	return nil
+}
+
+func gauge(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labelValues ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)
+}
+
+func counter(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labelValues ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labelValues...)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,173 @@
+// Package rabbitmq_exporter collects metrics of a RabbitMQ cluster from its
+// management API.
+package rabbitmq_exporter //nolint:golint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/static/integrations"
+	config_util "github.com/prometheus/common/config"
+)
+
+// Collectors of the rabbitmq integration.
+const (
+	CollectorExchange = "exchange"
+	CollectorHealth   = "health"
+	CollectorNode     = "node"
+	CollectorOverview = "overview"
+	CollectorQueue    = "queue"
+)
+
+// AvailableCollectors are the collectors of the rabbitmq integration.
+var AvailableCollectors = []string{
+	CollectorExchange,
+	CollectorHealth,
+	CollectorNode,
+	CollectorOverview,
+	CollectorQueue,
+}
+
+// DefaultConfig is the default config for the rabbitmq integration.
+var DefaultConfig = Config{
+	URL:               "http://localhost:15672",
+	Username:          "guest",
+	Password:          "guest",
+	Timeout:           10 * time.Second,
+	Collectors:        AvailableCollectors,
+	VhostIncludeRegex: ".*",
+	QueueIncludeRegex: ".*",
+}
+
+// Config controls the rabbitmq integration.
+type Config struct {
+	// URL of the RabbitMQ management API.
+	URL string `yaml:"url,omitempty"`
+
+	// Username and Password to authenticate to the management API with.
+	Username string             `yaml:"username,omitempty"`
+	Password config_util.Secret `yaml:"password,omitempty"`
+
+	// Timeout of the requests to the management API during a scrape.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// TLSConfig is used to connect to the management API.
+	TLSConfig *config_util.TLSConfig `yaml:"tls_config,omitempty"`
+
+	// Collectors to enable.
+	Collectors []string `yaml:"collectors,omitempty"`
+
+	// Regular expressions selecting the virtual hosts and queues to collect
+	// metrics from. Exclude regular expressions take precedence, and are
+	// ignored when empty.
+	VhostIncludeRegex string `yaml:"vhost_include_regex,omitempty"`
+	VhostExcludeRegex string `yaml:"vhost_exclude_regex,omitempty"`
+	QueueIncludeRegex string `yaml:"queue_include_regex,omitempty"`
+	QueueExcludeRegex string `yaml:"queue_exclude_regex,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "rabbitmq"
+}
+
+// InstanceKey returns the host of the management API.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	return u.Host, nil
+}
+
+// NewIntegration creates a new rabbitmq integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// New creates a new rabbitmq integration. Metrics are collected from the
+// management API on every scrape.
+func New(l log.Logger, c *Config) (integrations.Integration, error) {
+	if _, err := url.ParseRequestURI(c.URL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.TLSConfig != nil {
+		tlsConfig, err := config_util.NewTLSConfig(c.TLSConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls_config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	f, err := newFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := &client{
+		url:      c.URL,
+		username: c.Username,
+		password: string(c.Password),
+		http:     &http.Client{Transport: transport, Timeout: c.Timeout},
+	}
+	col, err := newCollector(l, cl, c.Collectors, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(col),
+	), nil
+}
+
+// filter selects the virtual hosts and queues to collect metrics from.
+type filter struct {
+	vhostInclude, vhostExclude *regexp.Regexp
+	queueInclude, queueExclude *regexp.Regexp
+}
+
+func newFilter(c *Config) (*filter, error) {
+	var f filter
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{"vhost include", c.VhostIncludeRegex, &f.vhostInclude},
+		{"vhost exclude", c.VhostExcludeRegex, &f.vhostExclude},
+		{"queue include", c.QueueIncludeRegex, &f.queueInclude},
+		{"queue exclude", c.QueueExcludeRegex, &f.queueExclude},
+	} {
+		if re.expr == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("^(?:" + re.expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s regex %q: %w", re.name, re.expr, err)
+		}
+		*re.dst = compiled
+	}
+	return &f, nil
+}
+
+func (f *filter) vhost(name string) bool {
+	return matches(name, f.vhostInclude, f.vhostExclude)
+}
+
+func (f *filter) queue(vhost, name string) bool {
+	return f.vhost(vhost) && matches(name, f.queueInclude, f.queueExclude)
+}
+
+func matches(name string, include, exclude *regexp.Regexp) bool {
+	if exclude != nil && exclude.MatchString(name) {
+		return false
+	}
+	return include == nil || include.MatchString(name)
+}
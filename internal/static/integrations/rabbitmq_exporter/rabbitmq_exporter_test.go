@@ -0,0 +1,156 @@
+package rabbitmq_exporter //nolint:golint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeManagementAPI(t *testing.T) *httptest.Server {
+	responses := map[string]string{
+		"/api/overview": `{
+			"cluster_name": "rabbit@prod", "rabbitmq_version": "3.13.1", "erlang_version": "26.2.3",
+			"object_totals": {"channels": 4, "connections": 2, "consumers": 3, "exchanges": 9, "queues": 3},
+			"queue_totals": {"messages": 15, "messages_ready": 10, "messages_unacknowledged": 5}
+		}`,
+		"/api/queues": `[
+			{"name": "orders", "vhost": "/", "state": "running", "messages": 12, "messages_ready": 10, "messages_unacknowledged": 2, "consumers": 1, "memory": 2048,
+			 "message_stats": {"publish": 100, "deliver_get": 88, "ack": 86, "redeliver": 1}},
+			{"name": "orders.tmp", "vhost": "/", "state": "running", "messages": 0},
+			{"name": "events", "vhost": "staging", "state": "running", "messages": 3}
+		]`,
+		"/api/exchanges": `[
+			{"name": "amq.direct", "vhost": "/", "type": "direct"},
+			{"name": "orders", "vhost": "/", "type": "topic", "message_stats": {"publish_in": 100, "publish_out": 100}}
+		]`,
+		"/api/nodes": `[
+			{"name": "rabbit@node-1", "running": true, "mem_used": 1000, "mem_limit": 4000, "mem_alarm": false,
+			 "disk_free": 5000, "disk_free_limit": 50, "disk_free_alarm": false, "fd_used": 30, "fd_total": 1024,
+			 "sockets_used": 2, "sockets_total": 900, "uptime": 60000, "partitions": []},
+			{"name": "rabbit@node-2", "running": false}
+		]`,
+		"/api/health/checks/alarms":        `{"status": "ok"}`,
+		"/api/health/checks/virtual-hosts": `{"status": "ok"}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "monitoring" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+}
+
+func newTestCollector(t *testing.T, c Config) *collector {
+	t.Helper()
+
+	f, err := newFilter(&c)
+	require.NoError(t, err)
+	cl := &client{url: c.URL, username: c.Username, password: string(c.Password), http: http.DefaultClient}
+	col, err := newCollector(log.NewNopLogger(), cl, c.Collectors, f)
+	require.NoError(t, err)
+	return col
+}
+
+func TestCollector(t *testing.T) {
+	srv := fakeManagementAPI(t)
+	defer srv.Close()
+
+	cfg := DefaultConfig
+	cfg.URL = srv.URL
+	cfg.Username = "monitoring"
+	cfg.Password = "secret"
+	cfg.VhostExcludeRegex = "staging"
+	cfg.QueueExcludeRegex = `.*\.tmp`
+	col := newTestCollector(t, cfg)
+
+	expected := `
+# HELP rabbitmq_up Whether the last scrape of the RabbitMQ management API was successful.
+# TYPE rabbitmq_up gauge
+rabbitmq_up 1
+# HELP rabbitmq_info Information about the RabbitMQ cluster.
+# TYPE rabbitmq_info gauge
+rabbitmq_info{cluster="rabbit@prod",erlang_version="26.2.3",rabbitmq_version="3.13.1"} 1
+# HELP rabbitmq_queue_messages Number of messages in the queue.
+# TYPE rabbitmq_queue_messages gauge
+rabbitmq_queue_messages{queue="orders",vhost="/"} 12
+# HELP rabbitmq_queue_messages_published_total Total number of messages published to the queue.
+# TYPE rabbitmq_queue_messages_published_total counter
+rabbitmq_queue_messages_published_total{queue="orders",vhost="/"} 100
+# HELP rabbitmq_exchange_messages_published_in_total Total number of messages published to the exchange.
+# TYPE rabbitmq_exchange_messages_published_in_total counter
+rabbitmq_exchange_messages_published_in_total{exchange="orders",type="topic",vhost="/"} 100
+# HELP rabbitmq_node_running Whether the node is running.
+# TYPE rabbitmq_node_running gauge
+rabbitmq_node_running{node="rabbit@node-1"} 1
+rabbitmq_node_running{node="rabbit@node-2"} 0
+# HELP rabbitmq_node_uptime_seconds Uptime of the node, in seconds.
+# TYPE rabbitmq_node_uptime_seconds gauge
+rabbitmq_node_uptime_seconds{node="rabbit@node-1"} 60
+# HELP rabbitmq_health_check_ok Whether the health check of the management API passes.
+# TYPE rabbitmq_health_check_ok gauge
+rabbitmq_health_check_ok{check="alarms"} 1
+rabbitmq_health_check_ok{check="virtual-hosts"} 1
+`
+	err := testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"rabbitmq_up",
+		"rabbitmq_info",
+		"rabbitmq_queue_messages",
+		"rabbitmq_queue_messages_published_total",
+		"rabbitmq_exchange_messages_published_in_total",
+		"rabbitmq_node_running",
+		"rabbitmq_node_uptime_seconds",
+		"rabbitmq_health_check_ok",
+	)
+	require.NoError(t, err)
+}
+
+func TestCollector_Unauthorized(t *testing.T) {
+	srv := fakeManagementAPI(t)
+	defer srv.Close()
+
+	cfg := DefaultConfig
+	cfg.URL = srv.URL
+	cfg.Collectors = []string{CollectorOverview}
+	col := newTestCollector(t, cfg)
+
+	expected := `
+# HELP rabbitmq_up Whether the last scrape of the RabbitMQ management API was successful.
+# TYPE rabbitmq_up gauge
+rabbitmq_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected)))
+}
+
+func TestNewCollector_UnknownCollector(t *testing.T) {
+	_, err := newCollector(log.NewNopLogger(), &client{}, []string{"queue", "shovel"}, &filter{})
+	require.EqualError(t, err, `unknown collector "shovel"`)
+}
+
+func TestFilter(t *testing.T) {
+	f, err := newFilter(&Config{
+		VhostIncludeRegex: "prod-.*",
+		QueueIncludeRegex: ".*",
+		QueueExcludeRegex: "amq\\..*",
+	})
+	require.NoError(t, err)
+
+	require.True(t, f.queue("prod-eu", "orders"))
+	require.False(t, f.queue("prod-eu", "amq.gen-1"))
+	require.False(t, f.queue("staging", "orders"))
+	require.False(t, f.vhost("my-prod-eu"), "regular expressions must be anchored")
+
+	_, err = newFilter(&Config{QueueExcludeRegex: "orders("})
+	require.ErrorContains(t, err, `invalid queue exclude regex "orders("`)
+}
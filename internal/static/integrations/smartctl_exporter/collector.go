@@ -0,0 +1,232 @@
+package smartctl_exporter //nolint:golint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	upDesc = prometheus.NewDesc("smartctl_up", "Whether smartctl could be run and the devices could be scanned.", nil, nil)
+
+	deviceLabels = []string{"device"}
+
+	exitStatusDesc      = prometheus.NewDesc("smartctl_device_exit_status", "Exit status of the last run of smartctl for the device.", deviceLabels, nil)
+	infoDesc            = prometheus.NewDesc("smartctl_device_info", "Information about the device.", []string{"device", "type", "protocol", "model_name", "serial_number", "firmware_version"}, nil)
+	capacityDesc        = prometheus.NewDesc("smartctl_device_capacity_bytes", "Capacity of the device.", deviceLabels, nil)
+	smartPassedDesc     = prometheus.NewDesc("smartctl_device_smart_passed", "Whether the overall SMART health self-assessment of the device passed.", deviceLabels, nil)
+	temperatureDesc     = prometheus.NewDesc("smartctl_device_temperature_celsius", "Current temperature of the device.", deviceLabels, nil)
+	powerOnDesc         = prometheus.NewDesc("smartctl_device_power_on_seconds_total", "Total time the device has been powered on.", deviceLabels, nil)
+	powerCyclesDesc     = prometheus.NewDesc("smartctl_device_power_cycles_total", "Total number of power cycles of the device.", deviceLabels, nil)
+	attributeDesc       = prometheus.NewDesc("smartctl_device_attribute", "Value of an ATA SMART attribute of the device.", []string{"device", "attribute_id", "attribute_name", "attribute_value_type"}, nil)
+	criticalWarningDesc = prometheus.NewDesc("smartctl_device_critical_warning", "Critical warning bits of the NVMe device.", deviceLabels, nil)
+	availableSpareDesc  = prometheus.NewDesc("smartctl_device_available_spare_ratio", "Ratio of the remaining spare capacity of the NVMe device.", deviceLabels, nil)
+	spareThresholdDesc  = prometheus.NewDesc("smartctl_device_available_spare_threshold_ratio", "Threshold of the available spare capacity of the NVMe device.", deviceLabels, nil)
+	enduranceUsedDesc   = prometheus.NewDesc("smartctl_device_endurance_used_ratio", "Estimated ratio of the life of the NVMe device which has been used.", deviceLabels, nil)
+	readBytesDesc       = prometheus.NewDesc("smartctl_device_read_bytes_total", "Total number of bytes read from the NVMe device.", deviceLabels, nil)
+	writtenBytesDesc    = prometheus.NewDesc("smartctl_device_written_bytes_total", "Total number of bytes written to the NVMe device.", deviceLabels, nil)
+	unsafeShutdownsDesc = prometheus.NewDesc("smartctl_device_unsafe_shutdowns_total", "Total number of unsafe shutdowns of the NVMe device.", deviceLabels, nil)
+	mediaErrorsDesc     = prometheus.NewDesc("smartctl_device_media_errors_total", "Total number of unrecovered data integrity errors of the NVMe device.", deviceLabels, nil)
+	errorLogEntriesDesc = prometheus.NewDesc("smartctl_device_error_log_entries_total", "Total number of error information log entries of the NVMe device.", deviceLabels, nil)
+)
+
+// collector periodically runs smartctl for each device, and exposes the
+// metrics of the last run.
+//
+// Reading the SMART attributes can take seconds per device, and may wake up
+// disks, so it isn't done at scrape time.
+type collector struct {
+	log    log.Logger
+	cfg    *Config
+	filter *filter
+	run    runFunc
+
+	mut     sync.RWMutex
+	metrics []prometheus.Metric
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+func newCollector(l log.Logger, c *Config, f *filter, run runFunc) *collector {
+	return &collector{log: l, cfg: c, filter: f, run: run}
+}
+
+// Run runs smartctl every interval until ctx is canceled.
+func (c *collector) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *collector) refresh(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var metrics []prometheus.Metric
+	devices, err := c.devices(ctx)
+	if err != nil {
+		level.Error(c.log).Log("msg", "failed to scan devices with smartctl", "err", err)
+		metrics = append(metrics, prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0))
+	} else {
+		metrics = append(metrics, prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1))
+	}
+
+	for _, d := range devices {
+		deviceMetrics, err := c.collectDevice(ctx, d)
+		if err != nil {
+			level.Warn(c.log).Log("msg", "failed to read the SMART attributes of a device", "device", d.Name, "err", err)
+		}
+		metrics = append(metrics, deviceMetrics...)
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.metrics = metrics
+}
+
+// devices returns the configured devices, or the devices scanned by
+// smartctl, which are selected by the filter.
+func (c *collector) devices(ctx context.Context) ([]device, error) {
+	var devices []device
+	if len(c.cfg.Devices) > 0 {
+		for _, name := range c.cfg.Devices {
+			devices = append(devices, device{Name: name})
+		}
+	} else {
+		out, _, err := c.run(ctx, c.cfg.SmartctlPath, "--scan", "--json")
+		if err != nil {
+			return nil, err
+		}
+		var scan scanOutput
+		if err := json.Unmarshal(out, &scan); err != nil {
+			return nil, fmt.Errorf("failed to parse the output of smartctl --scan: %w", err)
+		}
+		devices = scan.Devices
+	}
+
+	filtered := devices[:0]
+	for _, d := range devices {
+		if c.filter.device(d.Name) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered, nil
+}
+
+// collectDevice runs smartctl for a device. Devices in standby aren't woken
+// up, and only report their exit status.
+func (c *collector) collectDevice(ctx context.Context, d device) ([]prometheus.Metric, error) {
+	args := []string{"--json", "--info", "--health", "--attributes", "--nocheck=standby"}
+	if d.Type != "" {
+		args = append(args, "--device="+d.Type)
+	}
+	args = append(args, d.Name)
+
+	out, exitCode, err := c.run(ctx, c.cfg.SmartctlPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	metrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(exitStatusDesc, prometheus.GaugeValue, float64(exitCode), d.Name),
+	}
+	if exitCode&(exitCommandLineError|exitDeviceOpenFailed) != 0 {
+		return metrics, nil
+	}
+
+	var output deviceOutput
+	if err := json.Unmarshal(out, &output); err != nil {
+		return metrics, fmt.Errorf("failed to parse the output of smartctl: %w", err)
+	}
+	return append(metrics, deviceMetrics(d.Name, &output)...), nil
+}
+
+func deviceMetrics(name string, o *deviceOutput) []prometheus.Metric {
+	var metrics []prometheus.Metric
+	add := func(desc *prometheus.Desc, valueType prometheus.ValueType, value float64, labelValues ...string) {
+		metrics = append(metrics, prometheus.MustNewConstMetric(desc, valueType, value, append([]string{name}, labelValues...)...))
+	}
+
+	add(infoDesc, prometheus.GaugeValue, 1, o.Device.Type, o.Device.Protocol, o.ModelName, o.SerialNumber, o.FirmwareVersion)
+	if o.UserCapacity != nil {
+		add(capacityDesc, prometheus.GaugeValue, o.UserCapacity.Bytes)
+	}
+	if o.SmartStatus != nil {
+		add(smartPassedDesc, prometheus.GaugeValue, boolToFloat(o.SmartStatus.Passed))
+	}
+	if o.Temperature != nil {
+		add(temperatureDesc, prometheus.GaugeValue, o.Temperature.Current)
+	}
+	if o.PowerOnTime != nil {
+		add(powerOnDesc, prometheus.CounterValue, o.PowerOnTime.Hours*time.Hour.Seconds())
+	}
+	if o.PowerCycleCount != nil {
+		add(powerCyclesDesc, prometheus.CounterValue, *o.PowerCycleCount)
+	}
+
+	if o.ATASmartAttributes != nil {
+		for _, a := range o.ATASmartAttributes.Table {
+			id := strconv.Itoa(a.ID)
+			add(attributeDesc, prometheus.GaugeValue, a.Value, id, a.Name, "value")
+			add(attributeDesc, prometheus.GaugeValue, a.Worst, id, a.Name, "worst")
+			add(attributeDesc, prometheus.GaugeValue, a.Thresh, id, a.Name, "thresh")
+			add(attributeDesc, prometheus.GaugeValue, a.Raw.Value, id, a.Name, "raw")
+		}
+	}
+
+	if l := o.NVMeSmartHealthInformationLog; l != nil {
+		add(criticalWarningDesc, prometheus.GaugeValue, l.CriticalWarning)
+		add(availableSpareDesc, prometheus.GaugeValue, l.AvailableSpare/100)
+		add(spareThresholdDesc, prometheus.GaugeValue, l.AvailableSpareThreshold/100)
+		add(enduranceUsedDesc, prometheus.GaugeValue, l.PercentageUsed/100)
+		add(readBytesDesc, prometheus.CounterValue, l.DataUnitsRead*nvmeDataUnit)
+		add(writtenBytesDesc, prometheus.CounterValue, l.DataUnitsWritten*nvmeDataUnit)
+		add(unsafeShutdownsDesc, prometheus.CounterValue, l.UnsafeShutdowns)
+		add(mediaErrorsDesc, prometheus.CounterValue, l.MediaErrors)
+		add(errorLogEntriesDesc, prometheus.CounterValue, l.NumErrLogEntries)
+	}
+	return metrics
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		upDesc, exitStatusDesc, infoDesc, capacityDesc, smartPassedDesc, temperatureDesc, powerOnDesc, powerCyclesDesc, attributeDesc,
+		criticalWarningDesc, availableSpareDesc, spareThresholdDesc, enduranceUsedDesc, readBytesDesc, writtenBytesDesc,
+		unsafeShutdownsDesc, mediaErrorsDesc, errorLogEntriesDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package smartctl_exporter //nolint:golint
+
+// Exit status bits of smartctl which mean that the output doesn't contain
+// the SMART attributes of the device.
+const (
+	exitCommandLineError = 1 << 0
+	exitDeviceOpenFailed = 1 << 1
+)
+
+// nvmeDataUnit is the size of the data units reported by NVMe devices.
+const nvmeDataUnit = 512 * 1000
+
+// scanOutput is the output of smartctl --scan --json.
+type scanOutput struct {
+	Devices []device `json:"devices"`
+}
+
+type device struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Protocol string `json:"protocol"`
+}
+
+// deviceOutput is the output of smartctl --json for a device. Fields which
+// don't apply to the protocol of the device are missing.
+type deviceOutput struct {
+	Device          device `json:"device"`
+	ModelName       string `json:"model_name"`
+	SerialNumber    string `json:"serial_number"`
+	FirmwareVersion string `json:"firmware_version"`
+
+	UserCapacity *struct {
+		Bytes float64 `json:"bytes"`
+	} `json:"user_capacity"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours float64 `json:"hours"`
+	} `json:"power_on_time"`
+	PowerCycleCount *float64 `json:"power_cycle_count"`
+
+	ATASmartAttributes *struct {
+		Table []ataAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+
+	NVMeSmartHealthInformationLog *nvmeHealthLog `json:"nvme_smart_health_information_log"`
+}
+
+type ataAttribute struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Worst  float64 `json:"worst"`
+	Thresh float64 `json:"thresh"`
+	Raw    struct {
+		Value float64 `json:"value"`
+	} `json:"raw"`
+}
+
+type nvmeHealthLog struct {
+	CriticalWarning         float64 `json:"critical_warning"`
+	AvailableSpare          float64 `json:"available_spare"`
+	AvailableSpareThreshold float64 `json:"available_spare_threshold"`
+	PercentageUsed          float64 `json:"percentage_used"`
+	DataUnitsRead           float64 `json:"data_units_read"`
+	DataUnitsWritten        float64 `json:"data_units_written"`
+	UnsafeShutdowns         float64 `json:"unsafe_shutdowns"`
+	MediaErrors             float64 `json:"media_errors"`
+	NumErrLogEntries        float64 `json:"num_err_log_entries"`
+}
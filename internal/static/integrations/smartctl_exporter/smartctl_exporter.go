@@ -0,0 +1,125 @@
+// Package smartctl_exporter collects the SMART attributes of the disks of the
+// host from smartctl.
+package smartctl_exporter //nolint:golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/static/integrations"
+)
+
+// DefaultConfig is the default config for the smartctl integration.
+var DefaultConfig = Config{
+	SmartctlPath:       "smartctl",
+	ScanInterval:       time.Minute,
+	DeviceIncludeRegex: ".*",
+}
+
+// Config controls the smartctl integration.
+type Config struct {
+	// SmartctlPath is the path of the smartctl command.
+	SmartctlPath string `yaml:"smartctl_path,omitempty"`
+
+	// Devices to collect the SMART attributes of. The devices are scanned
+	// with smartctl when empty.
+	Devices []string `yaml:"devices,omitempty"`
+
+	// Regular expressions selecting the devices to collect the SMART
+	// attributes of. The exclude regular expression takes precedence, and is
+	// ignored when empty.
+	DeviceIncludeRegex string `yaml:"device_include_regex,omitempty"`
+	DeviceExcludeRegex string `yaml:"device_exclude_regex,omitempty"`
+
+	// ScanInterval is the interval between runs of smartctl.
+	ScanInterval time.Duration `yaml:"scan_interval,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "smartctl"
+}
+
+// InstanceKey returns the hostname of the machine.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	return agentKey, nil
+}
+
+// NewIntegration creates a new smartctl integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// New creates a new smartctl integration. smartctl runs every scan interval,
+// and scrapes return the metrics of the last run.
+func New(l log.Logger, c *Config) (integrations.Integration, error) {
+	if c.ScanInterval <= 0 {
+		return nil, fmt.Errorf("scan interval must be greater than 0")
+	}
+	f, err := newFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	col := newCollector(l, c, f, runCommand)
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(col),
+		integrations.WithRunner(func(ctx context.Context) error {
+			return col.Run(ctx, c.ScanInterval)
+		}),
+	), nil
+}
+
+// runFunc runs a command and returns its standard output and exit code.
+// smartctl reports the health of the device in the bits of its exit code,
+// so a non-zero exit code isn't an error.
+type runFunc func(ctx context.Context, name string, args ...string) ([]byte, int, error)
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, int, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return out, exitErr.ExitCode(), nil
+	}
+	return out, 0, err
+}
+
+// filter selects the devices to collect the SMART attributes of.
+type filter struct {
+	include, exclude *regexp.Regexp
+}
+
+func newFilter(c *Config) (*filter, error) {
+	var f filter
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{"device include", c.DeviceIncludeRegex, &f.include},
+		{"device exclude", c.DeviceExcludeRegex, &f.exclude},
+	} {
+		if re.expr == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("^(?:" + re.expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s regex %q: %w", re.name, re.expr, err)
+		}
+		*re.dst = compiled
+	}
+	return &f, nil
+}
+
+func (f *filter) device(name string) bool {
+	if f.exclude != nil && f.exclude.MatchString(name) {
+		return false
+	}
+	return f.include == nil || f.include.MatchString(name)
+}
@@ -0,0 +1,178 @@
+package smartctl_exporter //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testScanOutput = `{
+  "devices": [
+    {"name": "/dev/sda", "info_name": "/dev/sda [SAT]", "type": "sat", "protocol": "ATA"},
+    {"name": "/dev/sdb", "info_name": "/dev/sdb [SAT]", "type": "sat", "protocol": "ATA"},
+    {"name": "/dev/nvme0", "info_name": "/dev/nvme0", "type": "nvme", "protocol": "NVMe"}
+  ]
+}`
+
+const testATAOutput = `{
+  "smartctl": {"exit_status": 0},
+  "device": {"name": "/dev/sda", "type": "sat", "protocol": "ATA"},
+  "model_name": "ST4000NM0035",
+  "serial_number": "ZC1234",
+  "firmware_version": "TN04",
+  "user_capacity": {"blocks": 7814037168, "bytes": 4000787030016},
+  "smart_status": {"passed": true},
+  "ata_smart_attributes": {
+    "table": [
+      {"id": 5, "name": "Reallocated_Sector_Ct", "value": 100, "worst": 100, "thresh": 10, "raw": {"value": 8, "string": "8"}}
+    ]
+  },
+  "power_on_time": {"hours": 100},
+  "power_cycle_count": 12,
+  "temperature": {"current": 34}
+}`
+
+const testNVMeOutput = `{
+  "smartctl": {"exit_status": 0},
+  "device": {"name": "/dev/nvme0", "type": "nvme", "protocol": "NVMe"},
+  "model_name": "Samsung SSD 980 PRO 1TB",
+  "serial_number": "S5GX",
+  "firmware_version": "5B2QGXA7",
+  "smart_status": {"passed": true},
+  "nvme_smart_health_information_log": {
+    "critical_warning": 0,
+    "temperature": 41,
+    "available_spare": 100,
+    "available_spare_threshold": 10,
+    "percentage_used": 3,
+    "data_units_read": 1000,
+    "data_units_written": 2000,
+    "unsafe_shutdowns": 7,
+    "media_errors": 0,
+    "num_err_log_entries": 15
+  },
+  "temperature": {"current": 41}
+}`
+
+// fakeRun returns the canned outputs of smartctl, and records the arguments.
+type fakeRun struct {
+	calls [][]string
+	err   error
+}
+
+func (f *fakeRun) run(_ context.Context, name string, args ...string) ([]byte, int, error) {
+	f.calls = append(f.calls, args)
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	switch args[len(args)-1] {
+	case "--json":
+		return []byte(testScanOutput), 0, nil
+	case "/dev/sda":
+		return []byte(testATAOutput), 0, nil
+	case "/dev/nvme0":
+		return []byte(testNVMeOutput), 0, nil
+	case "/dev/sdc":
+		// The device is in standby.
+		return []byte(`{"smartctl": {"exit_status": 2}}`), 2, nil
+	}
+	return nil, 0, fmt.Errorf("unexpected arguments %v", args)
+}
+
+func TestCollector_Scan(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.DeviceExcludeRegex = "/dev/sdb"
+
+	f, err := newFilter(&cfg)
+	require.NoError(t, err)
+	run := &fakeRun{}
+	col := newCollector(log.NewNopLogger(), &cfg, f, run.run)
+	col.refresh(context.Background(), time.Second)
+
+	require.Equal(t, [][]string{
+		{"--scan", "--json"},
+		{"--json", "--info", "--health", "--attributes", "--nocheck=standby", "--device=sat", "/dev/sda"},
+		{"--json", "--info", "--health", "--attributes", "--nocheck=standby", "--device=nvme", "/dev/nvme0"},
+	}, run.calls)
+
+	expected := `
+# HELP smartctl_up Whether smartctl could be run and the devices could be scanned.
+# TYPE smartctl_up gauge
+smartctl_up 1
+# HELP smartctl_device_info Information about the device.
+# TYPE smartctl_device_info gauge
+smartctl_device_info{device="/dev/nvme0",firmware_version="5B2QGXA7",model_name="Samsung SSD 980 PRO 1TB",protocol="NVMe",serial_number="S5GX",type="nvme"} 1
+smartctl_device_info{device="/dev/sda",firmware_version="TN04",model_name="ST4000NM0035",protocol="ATA",serial_number="ZC1234",type="sat"} 1
+# HELP smartctl_device_attribute Value of an ATA SMART attribute of the device.
+# TYPE smartctl_device_attribute gauge
+smartctl_device_attribute{attribute_id="5",attribute_name="Reallocated_Sector_Ct",attribute_value_type="raw",device="/dev/sda"} 8
+smartctl_device_attribute{attribute_id="5",attribute_name="Reallocated_Sector_Ct",attribute_value_type="thresh",device="/dev/sda"} 10
+smartctl_device_attribute{attribute_id="5",attribute_name="Reallocated_Sector_Ct",attribute_value_type="value",device="/dev/sda"} 100
+smartctl_device_attribute{attribute_id="5",attribute_name="Reallocated_Sector_Ct",attribute_value_type="worst",device="/dev/sda"} 100
+# HELP smartctl_device_power_on_seconds_total Total time the device has been powered on.
+# TYPE smartctl_device_power_on_seconds_total counter
+smartctl_device_power_on_seconds_total{device="/dev/sda"} 360000
+# HELP smartctl_device_temperature_celsius Current temperature of the device.
+# TYPE smartctl_device_temperature_celsius gauge
+smartctl_device_temperature_celsius{device="/dev/nvme0"} 41
+smartctl_device_temperature_celsius{device="/dev/sda"} 34
+# HELP smartctl_device_endurance_used_ratio Estimated ratio of the life of the NVMe device which has been used.
+# TYPE smartctl_device_endurance_used_ratio gauge
+smartctl_device_endurance_used_ratio{device="/dev/nvme0"} 0.03
+# HELP smartctl_device_written_bytes_total Total number of bytes written to the NVMe device.
+# TYPE smartctl_device_written_bytes_total counter
+smartctl_device_written_bytes_total{device="/dev/nvme0"} 1.024e+09
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"smartctl_up", "smartctl_device_info", "smartctl_device_attribute", "smartctl_device_power_on_seconds_total",
+		"smartctl_device_temperature_celsius", "smartctl_device_endurance_used_ratio", "smartctl_device_written_bytes_total"))
+}
+
+func TestCollector_Devices(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.Devices = []string{"/dev/sda", "/dev/sdc"}
+
+	f, err := newFilter(&cfg)
+	require.NoError(t, err)
+	run := &fakeRun{}
+	col := newCollector(log.NewNopLogger(), &cfg, f, run.run)
+	col.refresh(context.Background(), time.Second)
+
+	require.Equal(t, [][]string{
+		{"--json", "--info", "--health", "--attributes", "--nocheck=standby", "/dev/sda"},
+		{"--json", "--info", "--health", "--attributes", "--nocheck=standby", "/dev/sdc"},
+	}, run.calls)
+
+	expected := `
+# HELP smartctl_device_exit_status Exit status of the last run of smartctl for the device.
+# TYPE smartctl_device_exit_status gauge
+smartctl_device_exit_status{device="/dev/sda"} 0
+smartctl_device_exit_status{device="/dev/sdc"} 2
+# HELP smartctl_device_smart_passed Whether the overall SMART health self-assessment of the device passed.
+# TYPE smartctl_device_smart_passed gauge
+smartctl_device_smart_passed{device="/dev/sda"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"smartctl_device_exit_status", "smartctl_device_smart_passed"))
+}
+
+func TestCollector_ScanFailure(t *testing.T) {
+	f, err := newFilter(&DefaultConfig)
+	require.NoError(t, err)
+	run := &fakeRun{err: fmt.Errorf(`exec: "smartctl": executable file not found in $PATH`)}
+	col := newCollector(log.NewNopLogger(), &DefaultConfig, f, run.run)
+	col.refresh(context.Background(), time.Second)
+
+	expected := `
+# HELP smartctl_up Whether smartctl could be run and the devices could be scanned.
+# TYPE smartctl_up gauge
+smartctl_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected)))
+}
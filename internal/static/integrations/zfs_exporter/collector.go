@@ -0,0 +1,226 @@
+package zfs_exporter //nolint:golint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolStates are the states of the health of a pool.
+var poolStates = []string{"ONLINE", "DEGRADED", "FAULTED", "OFFLINE", "UNAVAIL", "REMOVED", "SUSPENDED"}
+
+// Properties of pools and datasets, in the order of the columns requested
+// from the zpool and zfs commands. The -p flag reports exact values, with
+// percentages as integers and ratios as decimals.
+var (
+	poolProperties    = []string{"name", "size", "allocated", "free", "fragmentation", "capacity", "dedupratio", "health"}
+	datasetProperties = []string{"name", "type", "used", "available", "referenced", "compressratio", "written"}
+)
+
+var (
+	upDesc = prometheus.NewDesc("zfs_up", "Whether the last run of the zpool and zfs commands was successful.", nil, nil)
+
+	poolSizeDesc          = prometheus.NewDesc("zfs_pool_size_bytes", "Total size of the pool.", []string{"pool"}, nil)
+	poolAllocatedDesc     = prometheus.NewDesc("zfs_pool_allocated_bytes", "Amount of storage space used within the pool.", []string{"pool"}, nil)
+	poolFreeDesc          = prometheus.NewDesc("zfs_pool_free_bytes", "Amount of free space available in the pool.", []string{"pool"}, nil)
+	poolFragmentationDesc = prometheus.NewDesc("zfs_pool_fragmentation_ratio", "Fragmentation of the free space of the pool.", []string{"pool"}, nil)
+	poolCapacityDesc      = prometheus.NewDesc("zfs_pool_capacity_ratio", "Ratio of the space of the pool that is used.", []string{"pool"}, nil)
+	poolDedupDesc         = prometheus.NewDesc("zfs_pool_deduplication_ratio", "Deduplication ratio of the pool.", []string{"pool"}, nil)
+	poolStateDesc         = prometheus.NewDesc("zfs_pool_state", "Health of the pool, 1 for the current state.", []string{"pool", "state"}, nil)
+
+	datasetLabels          = []string{"pool", "dataset", "type"}
+	datasetUsedDesc        = prometheus.NewDesc("zfs_dataset_used_bytes", "Amount of space consumed by the dataset and its descendants.", datasetLabels, nil)
+	datasetAvailableDesc   = prometheus.NewDesc("zfs_dataset_available_bytes", "Amount of space available to the dataset and its children.", datasetLabels, nil)
+	datasetReferencedDesc  = prometheus.NewDesc("zfs_dataset_referenced_bytes", "Amount of data accessible by the dataset.", datasetLabels, nil)
+	datasetCompressionDesc = prometheus.NewDesc("zfs_dataset_compression_ratio", "Compression ratio achieved for the referenced space of the dataset.", datasetLabels, nil)
+	datasetWrittenDesc     = prometheus.NewDesc("zfs_dataset_written_bytes", "Amount of space written to the dataset since its previous snapshot.", datasetLabels, nil)
+)
+
+// collector periodically runs the zpool and zfs commands, and exposes the
+// metrics of their last run.
+type collector struct {
+	log    log.Logger
+	cfg    *Config
+	filter *filter
+	run    runFunc
+
+	mut     sync.RWMutex
+	metrics []prometheus.Metric
+}
+
+var _ prometheus.Collector = (*collector)(nil)
+
+func newCollector(l log.Logger, c *Config, f *filter, run runFunc) *collector {
+	return &collector{log: l, cfg: c, filter: f, run: run}
+}
+
+// Run runs the commands every interval until ctx is canceled.
+func (c *collector) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *collector) refresh(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	metrics, err := c.collect(ctx)
+	if err != nil {
+		level.Error(c.log).Log("msg", "failed to collect ZFS metrics", "err", err)
+		metrics = []prometheus.Metric{prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0)}
+	} else {
+		metrics = append(metrics, prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1))
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.metrics = metrics
+}
+
+func (c *collector) collect(ctx context.Context) ([]prometheus.Metric, error) {
+	out, err := c.run(ctx, c.cfg.ZpoolPath, "list", "-H", "-p", "-o", strings.Join(poolProperties, ","))
+	if err != nil {
+		return nil, err
+	}
+	var metrics []prometheus.Metric
+	var pools []string
+	err = parseTable(out, len(poolProperties), func(fields []string) {
+		if !c.filter.pool(fields[0]) {
+			return
+		}
+		pools = append(pools, fields[0])
+		metrics = append(metrics, poolMetrics(fields)...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the output of zpool: %w", err)
+	}
+
+	if c.cfg.DisableDatasetMetrics || len(pools) == 0 {
+		return metrics, nil
+	}
+
+	args := append([]string{"list", "-H", "-p", "-t", "filesystem,volume", "-o", strings.Join(datasetProperties, ","), "-r"}, pools...)
+	out, err = c.run(ctx, c.cfg.ZFSPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	err = parseTable(out, len(datasetProperties), func(fields []string) {
+		if !c.filter.dataset(fields[0]) {
+			return
+		}
+		metrics = append(metrics, datasetMetrics(fields)...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the output of zfs: %w", err)
+	}
+	return metrics, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		upDesc,
+		poolSizeDesc, poolAllocatedDesc, poolFreeDesc, poolFragmentationDesc, poolCapacityDesc, poolDedupDesc, poolStateDesc,
+		datasetUsedDesc, datasetAvailableDesc, datasetReferencedDesc, datasetCompressionDesc, datasetWrittenDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
+
+// parseTable parses the tab-separated output of the zpool and zfs commands
+// with the -H flag.
+func parseTable(out []byte, columns int, fn func(fields []string)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != columns {
+			return fmt.Errorf("expected %d columns, got %d in %q", columns, len(fields), scanner.Text())
+		}
+		fn(fields)
+	}
+	return scanner.Err()
+}
+
+func poolMetrics(fields []string) []prometheus.Metric {
+	pool := fields[0]
+
+	var metrics []prometheus.Metric
+	add := func(desc *prometheus.Desc, value string, scale float64) {
+		if v, ok := parseValue(value); ok {
+			metrics = append(metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v*scale, pool))
+		}
+	}
+	add(poolSizeDesc, fields[1], 1)
+	add(poolAllocatedDesc, fields[2], 1)
+	add(poolFreeDesc, fields[3], 1)
+	add(poolFragmentationDesc, fields[4], 0.01)
+	add(poolCapacityDesc, fields[5], 0.01)
+	add(poolDedupDesc, fields[6], 1)
+
+	for _, state := range poolStates {
+		var value float64
+		if fields[7] == state {
+			value = 1
+		}
+		metrics = append(metrics, prometheus.MustNewConstMetric(poolStateDesc, prometheus.GaugeValue, value, pool, state))
+	}
+	return metrics
+}
+
+func datasetMetrics(fields []string) []prometheus.Metric {
+	dataset, typ := fields[0], fields[1]
+	pool, _, _ := strings.Cut(dataset, "/")
+
+	var metrics []prometheus.Metric
+	add := func(desc *prometheus.Desc, value string) {
+		if v, ok := parseValue(value); ok {
+			metrics = append(metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, pool, dataset, typ))
+		}
+	}
+	add(datasetUsedDesc, fields[2])
+	add(datasetAvailableDesc, fields[3])
+	add(datasetReferencedDesc, fields[4])
+	add(datasetCompressionDesc, fields[5])
+	add(datasetWrittenDesc, fields[6])
+	return metrics
+}
+
+// parseValue parses the value of a property. Properties without a value,
+// such as the fragmentation of a pool with a single vdev type, are reported
+// as "-".
+func parseValue(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSuffix(s, "x"), "%"), 64)
+	return v, err == nil
+}
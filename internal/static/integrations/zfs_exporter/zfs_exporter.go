@@ -0,0 +1,136 @@
+// Package zfs_exporter collects metrics of the ZFS pools and datasets of the
+// host from the zpool and zfs commands.
+package zfs_exporter //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/static/integrations"
+)
+
+// DefaultConfig is the default config for the zfs integration.
+var DefaultConfig = Config{
+	ZpoolPath:           "zpool",
+	ZFSPath:             "zfs",
+	ScanInterval:        time.Minute,
+	PoolIncludeRegex:    ".*",
+	DatasetIncludeRegex: ".*",
+}
+
+// Config controls the zfs integration.
+type Config struct {
+	// Paths of the zpool and zfs commands.
+	ZpoolPath string `yaml:"zpool_path,omitempty"`
+	ZFSPath   string `yaml:"zfs_path,omitempty"`
+
+	// ScanInterval is the interval between runs of the commands.
+	ScanInterval time.Duration `yaml:"scan_interval,omitempty"`
+
+	// Regular expressions selecting the pools and datasets to collect
+	// metrics from. Exclude regular expressions take precedence, and are
+	// ignored when empty.
+	PoolIncludeRegex    string `yaml:"pool_include_regex,omitempty"`
+	PoolExcludeRegex    string `yaml:"pool_exclude_regex,omitempty"`
+	DatasetIncludeRegex string `yaml:"dataset_include_regex,omitempty"`
+	DatasetExcludeRegex string `yaml:"dataset_exclude_regex,omitempty"`
+
+	// DisableDatasetMetrics disables the metrics of the datasets.
+	DisableDatasetMetrics bool `yaml:"disable_dataset_metrics,omitempty"`
+}
+
+// Name returns the name of the integration this config is for.
+func (c *Config) Name() string {
+	return "zfs"
+}
+
+// InstanceKey returns the hostname of the machine.
+func (c *Config) InstanceKey(agentKey string) (string, error) {
+	return agentKey, nil
+}
+
+// NewIntegration creates a new zfs integration.
+func (c *Config) NewIntegration(l log.Logger) (integrations.Integration, error) {
+	return New(l, c)
+}
+
+// New creates a new zfs integration. The commands run every scan interval,
+// and scrapes return the metrics of the last run.
+func New(l log.Logger, c *Config) (integrations.Integration, error) {
+	if c.ScanInterval <= 0 {
+		return nil, fmt.Errorf("scan interval must be greater than 0")
+	}
+	f, err := newFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	col := newCollector(l, c, f, runCommand)
+	return integrations.NewCollectorIntegration(
+		c.Name(),
+		integrations.WithCollectors(col),
+		integrations.WithRunner(func(ctx context.Context) error {
+			return col.Run(ctx, c.ScanInterval)
+		}),
+	), nil
+}
+
+// runFunc runs a command and returns its standard output.
+type runFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("%s: %w: %s", name, err, exitErr.Stderr)
+	}
+	return out, err
+}
+
+// filter selects the pools and datasets to collect metrics from.
+type filter struct {
+	poolInclude, poolExclude       *regexp.Regexp
+	datasetInclude, datasetExclude *regexp.Regexp
+}
+
+func newFilter(c *Config) (*filter, error) {
+	var f filter
+	for _, re := range []struct {
+		name string
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{"pool include", c.PoolIncludeRegex, &f.poolInclude},
+		{"pool exclude", c.PoolExcludeRegex, &f.poolExclude},
+		{"dataset include", c.DatasetIncludeRegex, &f.datasetInclude},
+		{"dataset exclude", c.DatasetExcludeRegex, &f.datasetExclude},
+	} {
+		if re.expr == "" {
+			continue
+		}
+		compiled, err := regexp.Compile("^(?:" + re.expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s regex %q: %w", re.name, re.expr, err)
+		}
+		*re.dst = compiled
+	}
+	return &f, nil
+}
+
+func (f *filter) pool(name string) bool {
+	return matches(name, f.poolInclude, f.poolExclude)
+}
+
+func (f *filter) dataset(name string) bool {
+	return matches(name, f.datasetInclude, f.datasetExclude)
+}
+
+func matches(name string, include, exclude *regexp.Regexp) bool {
+	if exclude != nil && exclude.MatchString(name) {
+		return false
+	}
+	return include == nil || include.MatchString(name)
+}
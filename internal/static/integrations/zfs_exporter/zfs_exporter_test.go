@@ -0,0 +1,128 @@
+package zfs_exporter //nolint:golint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testZpoolOutput = "tank\t3985729650688\t1209462790144\t2776266860544\t12\t30\t1.00\tONLINE\n" +
+		"backup\t1992864825344\t996432412672\t996432412672\t-\t50\t1.25\tDEGRADED\n"
+
+	testZFSOutput = "tank\tfilesystem\t1209462790144\t2649173180416\t98304\t1.00\t0\n" +
+		"tank/home\tfilesystem\t1073741824\t2649173180416\t1073741824\t1.52\t4096\n" +
+		"tank/vm-100\tvolume\t34359738368\t2683532918784\t8589934592\t1.10\t8589934592\n"
+)
+
+// fakeRun returns the canned outputs of the commands, and records their
+// arguments.
+type fakeRun struct {
+	calls [][]string
+	err   error
+}
+
+func (f *fakeRun) run(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if f.err != nil {
+		return nil, f.err
+	}
+	switch name {
+	case "zpool":
+		return []byte(testZpoolOutput), nil
+	case "zfs":
+		return []byte(testZFSOutput), nil
+	}
+	return nil, fmt.Errorf("unexpected command %s", name)
+}
+
+func TestCollector(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.PoolExcludeRegex = "backup"
+	cfg.DatasetExcludeRegex = "tank/vm-.*"
+
+	f, err := newFilter(&cfg)
+	require.NoError(t, err)
+	run := &fakeRun{}
+	col := newCollector(log.NewNopLogger(), &cfg, f, run.run)
+	col.refresh(context.Background(), time.Second)
+
+	require.Equal(t, []string{"zfs", "list", "-H", "-p", "-t", "filesystem,volume", "-o", "name,type,used,available,referenced,compressratio,written", "-r", "tank"}, run.calls[1])
+
+	expected := `
+# HELP zfs_up Whether the last run of the zpool and zfs commands was successful.
+# TYPE zfs_up gauge
+zfs_up 1
+# HELP zfs_pool_size_bytes Total size of the pool.
+# TYPE zfs_pool_size_bytes gauge
+zfs_pool_size_bytes{pool="tank"} 3.985729650688e+12
+# HELP zfs_pool_capacity_ratio Ratio of the space of the pool that is used.
+# TYPE zfs_pool_capacity_ratio gauge
+zfs_pool_capacity_ratio{pool="tank"} 0.3
+# HELP zfs_pool_state Health of the pool, 1 for the current state.
+# TYPE zfs_pool_state gauge
+zfs_pool_state{pool="tank",state="DEGRADED"} 0
+zfs_pool_state{pool="tank",state="FAULTED"} 0
+zfs_pool_state{pool="tank",state="OFFLINE"} 0
+zfs_pool_state{pool="tank",state="ONLINE"} 1
+zfs_pool_state{pool="tank",state="REMOVED"} 0
+zfs_pool_state{pool="tank",state="SUSPENDED"} 0
+zfs_pool_state{pool="tank",state="UNAVAIL"} 0
+# HELP zfs_dataset_compression_ratio Compression ratio achieved for the referenced space of the dataset.
+# TYPE zfs_dataset_compression_ratio gauge
+zfs_dataset_compression_ratio{dataset="tank",pool="tank",type="filesystem"} 1
+zfs_dataset_compression_ratio{dataset="tank/home",pool="tank",type="filesystem"} 1.52
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"zfs_up", "zfs_pool_size_bytes", "zfs_pool_capacity_ratio", "zfs_pool_state", "zfs_dataset_compression_ratio"))
+}
+
+func TestCollector_MissingValues(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.PoolIncludeRegex = "backup"
+	cfg.DisableDatasetMetrics = true
+
+	f, err := newFilter(&cfg)
+	require.NoError(t, err)
+	run := &fakeRun{}
+	col := newCollector(log.NewNopLogger(), &cfg, f, run.run)
+	col.refresh(context.Background(), time.Second)
+
+	require.Len(t, run.calls, 1)
+
+	expected := `
+# HELP zfs_pool_fragmentation_ratio Fragmentation of the free space of the pool.
+# TYPE zfs_pool_fragmentation_ratio gauge
+# HELP zfs_pool_deduplication_ratio Deduplication ratio of the pool.
+# TYPE zfs_pool_deduplication_ratio gauge
+zfs_pool_deduplication_ratio{pool="backup"} 1.25
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected),
+		"zfs_pool_fragmentation_ratio", "zfs_pool_deduplication_ratio", "zfs_dataset_used_bytes"))
+}
+
+func TestCollector_CommandFailure(t *testing.T) {
+	f, err := newFilter(&DefaultConfig)
+	require.NoError(t, err)
+	run := &fakeRun{err: fmt.Errorf("exec: \"zpool\": executable file not found in $PATH")}
+	col := newCollector(log.NewNopLogger(), &DefaultConfig, f, run.run)
+	col.refresh(context.Background(), time.Second)
+
+	expected := `
+# HELP zfs_up Whether the last run of the zpool and zfs commands was successful.
+# TYPE zfs_up gauge
+zfs_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(col, strings.NewReader(expected)))
+}
+
+func TestParseTable_InvalidColumns(t *testing.T) {
+	err := parseTable([]byte("tank\t100\n"), len(poolProperties), func([]string) {})
+	require.EqualError(t, err, `expected 8 columns, got 2 in "tank\t100"`)
+}
@@ -145,8 +145,58 @@ type Storage struct {
 	notifier wlog.WriteNotified
 }
 
+// ReplayOptions configures how NewStorageWithOptions replays the WAL.
+type ReplayOptions struct {
+	// MaxDuration is the maximum time spent replaying the segments of the WAL.
+	// The segments which weren't replayed in time are skipped. Only their
+	// series records are read, which is much faster than replaying them, so
+	// that new series aren't given the references of their series. Zero means
+	// no limit.
+	MaxDuration time.Duration
+
+	// SkipCorruptSegments deletes a corrupted segment and the segments before
+	// it, and keeps replaying the newer segments. Otherwise, the WAL is
+	// repaired, which deletes the segments after the corrupted one.
+	SkipCorruptSegments bool
+
+	// OnProgress, if set, is called when the replay starts, after each
+	// segment, and when the replay is done.
+	OnProgress func(ReplayProgress)
+}
+
+// ReplayProgress is the progress of the replay of a WAL.
+type ReplayProgress struct {
+	Start            time.Time // When the replay started.
+	Segments         int       // Number of segments to replay.
+	ReplayedSegments int       // Number of segments replayed.
+	SkippedSegments  int       // Number of segments skipped.
+	Done             bool      // Whether the replay is done.
+}
+
+// RemainingSegments returns the number of segments left to replay.
+func (p ReplayProgress) RemainingSegments() int {
+	return p.Segments - p.ReplayedSegments - p.SkippedSegments
+}
+
+// ETA estimates the time left to replay the remaining segments, from the
+// time spent replaying the previous ones. ETA returns zero if it can't be
+// estimated yet.
+func (p ReplayProgress) ETA(now time.Time) time.Duration {
+	if p.Done || p.ReplayedSegments == 0 {
+		return 0
+	}
+	perSegment := now.Sub(p.Start) / time.Duration(p.ReplayedSegments)
+	return perSegment * time.Duration(p.RemainingSegments())
+}
+
 // NewStorage makes a new Storage.
 func NewStorage(logger log.Logger, registerer prometheus.Registerer, path string) (*Storage, error) {
+	return NewStorageWithOptions(context.Background(), logger, registerer, path, ReplayOptions{})
+}
+
+// NewStorageWithOptions makes a new Storage, replaying its WAL with opts.
+// The replay is stopped and an error is returned if ctx is canceled.
+func NewStorageWithOptions(ctx context.Context, logger log.Logger, registerer prometheus.Registerer, path string, opts ReplayOptions) (*Storage, error) {
 	w, err := wlog.NewSize(logger, registerer, SubDirectory(path), wlog.DefaultSegmentSize, wlog.CompressionSnappy)
 	if err != nil {
 		return nil, err
@@ -178,13 +228,14 @@ func NewStorage(logger log.Logger, registerer prometheus.Registerer, path string
 		}
 	}
 
-	if err := storage.replayWAL(); err != nil {
-		level.Warn(storage.logger).Log("msg", "encountered WAL read error, attempting repair", "err", err)
-
+	if err := storage.replayWAL(ctx, opts); err != nil {
 		var ce *wlog.CorruptionErr
 		if ok := errors.As(err, &ce); !ok {
+			_ = w.Close()
 			return nil, err
 		}
+
+		level.Warn(storage.logger).Log("msg", "encountered WAL read error, attempting repair", "err", err)
 		if err := w.Repair(ce); err != nil {
 			// if repair fails, truncate everything in WAL
 			level.Warn(storage.logger).Log("msg", "WAL repair failed, truncating!", "err", err)
@@ -202,7 +253,7 @@ func NewStorage(logger log.Logger, registerer prometheus.Registerer, path string
 	return storage, nil
 }
 
-func (w *Storage) replayWAL() error {
+func (w *Storage) replayWAL(ctx context.Context, opts ReplayOptions) error {
 	w.walMtx.RLock()
 	defer w.walMtx.RUnlock()
 
@@ -244,8 +295,43 @@ func (w *Storage) replayWAL() error {
 		return fmt.Errorf("finding WAL segments: %w", err)
 	}
 
+	progress := ReplayProgress{
+		Start:    time.Now(),
+		Segments: max(last-startFrom+1, 0),
+	}
+	report := func() {
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+	report()
+	defer func() {
+		progress.Done = true
+		report()
+	}()
+
 	// Backfill segments from the most recent checkpoint onwards.
 	for i := startFrom; i <= last; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.MaxDuration > 0 && time.Since(progress.Start) > opts.MaxDuration {
+			skipped := last - i + 1
+			level.Warn(w.logger).Log("msg", "WAL replay exceeded its maximum duration, skipping the remaining segments", "segment", i, "maxSegment", last, "max_duration", opts.MaxDuration)
+
+			// The skipped segments are still sent by the remote_write queues, so
+			// new series must not reuse the references of their series.
+			maxRef, err := w.maxSeriesRef(ctx, i, last)
+			if err != nil {
+				return err
+			}
+			if uint64(maxRef) > w.nextRef.Load() {
+				w.nextRef.Store(uint64(maxRef))
+			}
+			progress.SkippedSegments += skipped
+			return nil
+		}
+
 		s, err := wlog.OpenReadSegment(wlog.SegmentName(w.wal.Dir(), i))
 		if err != nil {
 			return fmt.Errorf("open WAL segment %d: %w", i, err)
@@ -256,15 +342,87 @@ func (w *Storage) replayWAL() error {
 		if err := sr.Close(); err != nil {
 			level.Warn(w.logger).Log("msg", "error while closing the wal segments reader", "err", err)
 		}
-		if err != nil {
+
+		var ce *wlog.CorruptionErr
+		switch {
+		case err != nil && opts.SkipCorruptSegments && errors.As(err, &ce):
+			level.Warn(w.logger).Log("msg", "deleting corrupted WAL segment and the segments before it", "segment", i, "err", err)
+			if err := w.dropSegments(i, multiRef); err != nil {
+				return err
+			}
+			progress.SkippedSegments++
+		case err != nil:
 			return err
+		default:
+			progress.ReplayedSegments++
+			level.Info(w.logger).Log("msg", "WAL segment loaded", "segment", i, "maxSegment", last)
 		}
-		level.Info(w.logger).Log("msg", "WAL segment loaded", "segment", i, "maxSegment", last)
+		report()
 	}
 
 	return nil
 }
 
+// maxSeriesRef returns the highest reference of the series records of the
+// segments from first to last. The other records aren't decoded.
+func (w *Storage) maxSeriesRef(ctx context.Context, first, last int) (chunks.HeadSeriesRef, error) {
+	sr, err := wlog.NewSegmentsRangeReader(wlog.SegmentRange{Dir: w.wal.Dir(), First: first, Last: last})
+	if err != nil {
+		return 0, fmt.Errorf("open WAL segments %d to %d: %w", first, last, err)
+	}
+	defer func() {
+		if err := sr.Close(); err != nil {
+			level.Warn(w.logger).Log("msg", "error while closing the wal segments reader", "err", err)
+		}
+	}()
+
+	var (
+		dec    record.Decoder
+		r      = wlog.NewReader(sr)
+		series []record.RefSeries
+		maxRef chunks.HeadSeriesRef
+	)
+	for r.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		rec := r.Record()
+		if dec.Type(rec) != record.Series {
+			continue
+		}
+		series, err = dec.Series(rec, series[:0])
+		if err != nil {
+			return 0, &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode series: %w", err),
+				Segment: r.Segment(),
+				Offset:  r.Offset(),
+			}
+		}
+		for _, s := range series {
+			maxRef = max(maxRef, s.Ref)
+		}
+	}
+	return maxRef, r.Err()
+}
+
+// dropSegments deletes the segments up to segment i and the checkpoints, and
+// forgets the series which were loaded from them. The series are created
+// again the next time they're appended to.
+func (w *Storage) dropSegments(i int, multiRef map[chunks.HeadSeriesRef]chunks.HeadSeriesRef) error {
+	if err := w.wal.Truncate(i + 1); err != nil {
+		return fmt.Errorf("delete WAL segments: %w", err)
+	}
+	if err := wlog.DeleteCheckpoints(w.wal.Dir(), i+1); err != nil {
+		return fmt.Errorf("delete WAL checkpoints: %w", err)
+	}
+
+	w.series = newStripeSeries(tsdb.DefaultStripeSize)
+	w.deleted = map[chunks.HeadSeriesRef]int{}
+	clear(multiRef)
+	w.metrics.numActiveSeries.Set(0)
+	return nil
+}
+
 func (w *Storage) loadWAL(r *wlog.Reader, multiRef map[chunks.HeadSeriesRef]chunks.HeadSeriesRef) (err error) {
 	var (
 		dec     record.Decoder
@@ -733,10 +891,16 @@ func (a *appender) getOrCreate(l labels.Labels) (series *memSeries, created bool
 	return series, true
 }
 
-func (a *appender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+func (a *appender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
 	readRef := chunks.HeadSeriesRef(ref)
 
 	s := a.w.series.GetByID(readRef)
+	if s == nil && len(l) > 0 {
+		// Callers which don't track the refs of the WAL, such as components
+		// forwarding exemplars from another component, may pass a stale or
+		// unset ref. Fall back to looking up the series by its labels.
+		s = a.w.series.GetByHash(l.Hash(), l)
+	}
 	if s == nil {
 		return 0, fmt.Errorf("unknown series ref when trying to add exemplar: %d", readRef)
 	}
@@ -779,7 +943,7 @@ func (a *appender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, e exem
 	a.w.series.SetLatestExemplar(s.ref, &e)
 
 	a.pendingExamplars = append(a.pendingExamplars, record.RefExemplar{
-		Ref:    readRef,
+		Ref:    s.ref,
 		T:      e.Ts,
 		V:      e.Value,
 		Labels: e.Labels,
@@ -182,6 +182,39 @@ func TestStorage_DuplicateExemplarsIgnored(t *testing.T) {
 	require.Equal(t, 4, len(collector.exemplars))
 }
 
+func TestStorage_ExemplarUnknownRef(t *testing.T) {
+	walDir := t.TempDir()
+
+	s, err := NewStorage(log.NewNopLogger(), nil, walDir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	app := s.Appender(context.Background())
+
+	lbls := labels.FromStrings("a", "1")
+	sRef, err := app.Append(0, lbls, 0, 0)
+	require.NoError(t, err)
+
+	// Exemplars with an unknown ref are appended to the series matching their
+	// labels.
+	e := exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "abc"), Value: 1, Ts: 10, HasTs: true}
+	ref, err := app.AppendExemplar(0, lbls, e)
+	require.NoError(t, err)
+	require.Equal(t, sRef, ref)
+
+	_, err = app.AppendExemplar(0, labels.FromStrings("a", "2"), e)
+	require.Error(t, err, "should reject exemplars of unknown series")
+
+	require.NoError(t, app.Commit())
+	collector := walDataCollector{}
+	replayer := walReplayer{w: &collector}
+	require.NoError(t, replayer.Replay(s.wal.Dir()))
+	require.Len(t, collector.exemplars, 1)
+	require.Equal(t, chunks.HeadSeriesRef(sRef), collector.exemplars[0].Ref)
+}
+
 func TestStorage_ExistingWAL(t *testing.T) {
 	walDir := t.TempDir()
 
@@ -417,6 +450,104 @@ func TestStorage_Corruption(t *testing.T) {
 	require.NoError(t, s.Close())
 }
 
+func TestStorage_SkipCorruptSegments(t *testing.T) {
+	walDir := t.TempDir()
+	writeSegments(t, walDir)
+
+	// Corrupt the first segment.
+	err := os.WriteFile(filepath.Join(walDir, "wal", "00000000"), []byte("hello world"), 0644)
+	require.NoError(t, err)
+
+	var progress []ReplayProgress
+	s, err := NewStorageWithOptions(context.Background(), log.NewNopLogger(), nil, walDir, ReplayOptions{
+		SkipCorruptSegments: true,
+		OnProgress:          func(p ReplayProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	last := progress[len(progress)-1]
+	require.True(t, last.Done)
+	require.Equal(t, 1, last.SkippedSegments)
+	require.Equal(t, last.Segments-1, last.ReplayedSegments)
+	require.Zero(t, last.RemainingSegments())
+	require.NoFileExists(t, filepath.Join(walDir, "wal", "00000000"))
+
+	// Only the series of the segments after the corrupted one are loaded.
+	require.Nil(t, s.series.GetByID(1))
+	require.Nil(t, s.series.GetByID(2))
+	require.NotNil(t, s.series.GetByID(3))
+}
+
+func TestStorage_MaxReplayDuration(t *testing.T) {
+	walDir := t.TempDir()
+	writeSegments(t, walDir)
+
+	var progress []ReplayProgress
+	s, err := NewStorageWithOptions(context.Background(), log.NewNopLogger(), nil, walDir, ReplayOptions{
+		MaxDuration: time.Nanosecond,
+		OnProgress:  func(p ReplayProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	last := progress[len(progress)-1]
+	require.True(t, last.Done)
+	require.Equal(t, last.Segments, last.SkippedSegments)
+	require.Nil(t, s.series.GetByID(1))
+
+	// New series get references above the ones of the skipped segments,
+	// which hold the series 1 to 3.
+	app := s.Appender(context.Background())
+	ref, err := app.Append(0, labels.FromStrings("__name__", "new"), 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, storage.SeriesRef(4), ref)
+	require.NoError(t, app.Commit())
+}
+
+func TestReplayProgress_ETA(t *testing.T) {
+	start := time.Now()
+	p := ReplayProgress{Start: start, Segments: 10}
+	require.Zero(t, p.ETA(start.Add(time.Minute)))
+
+	p.ReplayedSegments = 2
+	p.SkippedSegments = 2
+	require.Equal(t, 6, p.RemainingSegments())
+	require.Equal(t, 3*time.Minute, p.ETA(start.Add(time.Minute)))
+
+	p.Done = true
+	require.Zero(t, p.ETA(start.Add(time.Minute)))
+}
+
+// writeSegments writes the series foo and bar to the first segment of the
+// WAL in dir, and the series baz to the second one.
+func writeSegments(t *testing.T, dir string) {
+	t.Helper()
+
+	s, err := NewStorage(log.NewNopLogger(), nil, dir)
+	require.NoError(t, err)
+
+	app := s.Appender(context.Background())
+	for _, metric := range buildSeries([]string{"foo", "bar"}) {
+		metric.Write(t, app)
+	}
+	require.NoError(t, app.Commit())
+
+	// Truncate the WAL to force creation of a new segment.
+	require.NoError(t, s.Truncate(0))
+
+	app = s.Appender(context.Background())
+	for _, metric := range buildSeries([]string{"baz"}) {
+		metric.Write(t, app)
+	}
+	require.NoError(t, app.Commit())
+	require.NoError(t, s.Close())
+}
+
 func TestGlobalReferenceID_Normal(t *testing.T) {
 	walDir := t.TempDir()
 
@@ -0,0 +1,207 @@
+// Package rotatingfile implements files which are rotated by size or age.
+package rotatingfile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// backupTimeFormat is the format of the timestamp appended to the name of
+// rotated files. It sorts lexicographically.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// Options configures a File.
+type Options struct {
+	// Path of the file.
+	Path string
+	// Size in bytes after which the file is rotated. 0 disables size-based
+	// rotation.
+	MaxSize int64
+	// Age after which the file is rotated by RotateIfExpired. 0 disables
+	// time-based rotation.
+	MaxAge time.Duration
+	// Maximum number of rotated files to keep. 0 keeps all of them.
+	MaxFiles int
+	// Whether rotated files are compressed with gzip.
+	Compress bool
+	// OnRotate, if not nil, is called after every rotation.
+	OnRotate func()
+}
+
+// File is a file which is rotated once it reaches a maximum size or age.
+// Rotated files are renamed with a timestamp suffix, optionally compressed,
+// and pruned once there are more than MaxFiles of them.
+//
+// File isn't safe for concurrent use.
+type File struct {
+	opts Options
+
+	f        *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// New creates a new File. The file is opened on the first write.
+func New(opts Options) *File {
+	return &File{opts: opts}
+}
+
+// Write writes p to the file, rotating it first if writing p would make it
+// exceed its maximum size.
+func (r *File) Write(p []byte) (int, error) {
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.opts.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.opts.MaxSize {
+		if err := r.rotate(time.Now()); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// RotateIfExpired rotates the file if it's older than its maximum age and
+// isn't empty.
+func (r *File) RotateIfExpired(now time.Time) error {
+	if r.f == nil || r.opts.MaxAge <= 0 || r.size == 0 || now.Sub(r.openedAt) < r.opts.MaxAge {
+		return nil
+	}
+	return r.rotate(now)
+}
+
+func (r *File) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.opts.Path), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	r.f = f
+	r.size = info.Size()
+	r.openedAt = time.Now()
+	return nil
+}
+
+func (r *File) rotate(now time.Time) error {
+	if err := r.Close(); err != nil {
+		return err
+	}
+
+	backup := r.opts.Path + "." + now.UTC().Format(backupTimeFormat)
+	if err := os.Rename(r.opts.Path, backup); err != nil {
+		return err
+	}
+	if r.opts.OnRotate != nil {
+		r.opts.OnRotate()
+	}
+	if r.opts.Compress {
+		if err := compressFile(backup); err != nil {
+			return fmt.Errorf("failed to compress %s: %w", backup, err)
+		}
+	}
+	if err := r.prune(); err != nil {
+		return fmt.Errorf("failed to remove old files: %w", err)
+	}
+	return r.open()
+}
+
+// prune removes the oldest rotated files when there are more than maxFiles.
+func (r *File) prune() error {
+	if r.opts.MaxFiles <= 0 {
+		return nil
+	}
+
+	backups, err := r.Backups()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= r.opts.MaxFiles {
+		return nil
+	}
+	for _, name := range backups[:len(backups)-r.opts.MaxFiles] {
+		if err := os.Remove(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backups returns the names of the rotated files, oldest first.
+func (r *File) Backups() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(r.opts.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(r.opts.Path) + "."
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz")
+		if _, err := time.Parse(backupTimeFormat, ts); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(r.opts.Path), name))
+	}
+	slices.Sort(backups)
+	return backups, nil
+}
+
+// Close closes the current file. It's reopened on the next write.
+func (r *File) Close() error {
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	r.size = 0
+	return err
+}
+
+// compressFile replaces the file at path with a gzip-compressed copy.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,76 @@
+package rotatingfile
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var rotations int
+	r := New(Options{Path: path, MaxSize: 10, MaxFiles: 2, Compress: true, OnRotate: func() { rotations++ }})
+	defer r.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := r.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, rotations)
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "fourth\n", string(current))
+
+	// Only the two most recent rotated files are kept, compressed.
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	require.Equal(t, "second\n", readGzip(t, backups[0]))
+	require.Equal(t, "third\n", readGzip(t, backups[1]))
+}
+
+func TestRotateByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	r := New(Options{Path: path, MaxAge: time.Hour})
+	defer r.Close()
+
+	_, err := r.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, r.RotateIfExpired(time.Now()))
+	backups, err := r.Backups()
+	require.NoError(t, err)
+	require.Empty(t, backups)
+
+	require.NoError(t, r.RotateIfExpired(time.Now().Add(2*time.Hour)))
+	backups, err = r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	// Empty files aren't rotated.
+	require.NoError(t, r.RotateIfExpired(time.Now().Add(4*time.Hour)))
+	backups, err = r.Backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+}
+
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+	require.True(t, strings.HasSuffix(path, ".gz"))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	b, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,30 @@
+// Package adminauth authenticates requests to the HTTP endpoints which are
+// guarded by the admin token set with the --server.http.admin-token-file flag.
+package adminauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Require wraps next so that it's only called for requests which carry
+// token as a bearer token. When token is empty, the endpoint is disabled and
+// every request is rejected with a message naming feature.
+func Require(token, feature string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			msg := fmt.Sprintf("%s is disabled; set the --server.http.admin-token-file flag to enable it", feature)
+			http.Error(w, msg, http.StatusForbidden)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
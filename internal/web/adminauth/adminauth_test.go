@@ -0,0 +1,41 @@
+package adminauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequire(t *testing.T) {
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	tt := []struct {
+		name       string
+		token      string
+		header     string
+		expectCode int
+	}{
+		{name: "disabled", token: "", header: "Bearer ", expectCode: http.StatusForbidden},
+		{name: "missing header", token: "secret", expectCode: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "secret", header: "Basic secret", expectCode: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer other", expectCode: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", header: "Bearer secret", expectCode: http.StatusNoContent},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			Require(tc.token, "the test API", ok)(rec, req)
+			require.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectCode == http.StatusUnauthorized {
+				require.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -5,19 +5,25 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
 	"net/http"
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/secrets"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/internal/service/cluster"
 	"github.com/grafana/alloy/internal/service/livedebugging"
+	"github.com/grafana/alloy/internal/web/adminauth"
 	"github.com/prometheus/prometheus/util/httputil"
 )
 
@@ -25,11 +31,14 @@ import (
 type AlloyAPI struct {
 	alloy           service.Host
 	CallbackManager livedebugging.CallbackManager
+	// adminToken is the bearer token required to pause and resume components.
+	// Pausing components is disabled when it's empty.
+	adminToken string
 }
 
 // NewAlloyAPI instantiates a new Alloy API.
-func NewAlloyAPI(alloy service.Host, CallbackManager livedebugging.CallbackManager) *AlloyAPI {
-	return &AlloyAPI{alloy: alloy, CallbackManager: CallbackManager}
+func NewAlloyAPI(alloy service.Host, CallbackManager livedebugging.CallbackManager, adminToken string) *AlloyAPI {
+	return &AlloyAPI{alloy: alloy, CallbackManager: CallbackManager, adminToken: adminToken}
 }
 
 // RegisterRoutes registers all the API's routes.
@@ -40,9 +49,13 @@ func (a *AlloyAPI) RegisterRoutes(urlPrefix string, r *mux.Router) {
 
 	r.Handle(path.Join(urlPrefix, "/modules/{moduleID:.+}/components"), httputil.CompressionHandler{Handler: a.listComponentsHandler()})
 	r.Handle(path.Join(urlPrefix, "/components"), httputil.CompressionHandler{Handler: a.listComponentsHandler()})
+	r.Handle(path.Join(urlPrefix, "/components/{id:.+}/pause"), a.pauseComponentHandler(a.alloy.PauseComponent)).Methods(http.MethodPost)
+	r.Handle(path.Join(urlPrefix, "/components/{id:.+}/resume"), a.pauseComponentHandler(a.alloy.ResumeComponent)).Methods(http.MethodPost)
 	r.Handle(path.Join(urlPrefix, "/components/{id:.+}"), httputil.CompressionHandler{Handler: a.getComponentHandler()})
 	r.Handle(path.Join(urlPrefix, "/peers"), httputil.CompressionHandler{Handler: a.getClusteringPeersHandler()})
+	r.Handle(path.Join(urlPrefix, "/reload/report"), httputil.CompressionHandler{Handler: a.getReloadReportHandler()})
 	r.Handle(path.Join(urlPrefix, "/debug/{id:.+}"), a.liveDebugging())
+	r.Handle(path.Join(urlPrefix, "/tap/{id:.+}"), a.tap())
 }
 
 func (a *AlloyAPI) listComponentsHandler() http.HandlerFunc {
@@ -96,6 +109,40 @@ func (a *AlloyAPI) getComponentHandler() http.HandlerFunc {
 	}
 }
 
+// pauseComponentHandler returns a handler which calls fn with the ID of the
+// requested component, once the request was authenticated with the admin
+// token.
+func (a *AlloyAPI) pauseComponentHandler(fn func(id component.ID) error) http.HandlerFunc {
+	return adminauth.Require(a.adminToken, "pausing components", func(w http.ResponseWriter, r *http.Request) {
+		err := fn(component.ParseID(mux.Vars(r)["id"]))
+		switch {
+		case errors.Is(err, component.ErrComponentNotFound):
+			http.NotFound(w, r)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+func (a *AlloyAPI) getReloadReportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		report := a.alloy.GetReloadReport()
+		if report == nil {
+			http.Error(w, "no config was loaded yet", http.StatusNotFound)
+			return
+		}
+
+		bb, err := json.Marshal(report)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(bb)
+	}
+}
+
 func (a *AlloyAPI) getClusteringPeersHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
 		// TODO(@tpaschalis) Detect if clustering is disabled and propagate to
@@ -127,13 +174,22 @@ func (a *AlloyAPI) liveDebugging() http.HandlerFunc {
 
 		sampleProb := setSampleProb(w, r.URL.Query().Get("sampleProb"))
 
+		filter, err := livedebugging.ParseAttributeFilter(r.URL.Query().Get("attributes"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid attributes: %s", err), http.StatusBadRequest)
+			return
+		}
+
 		id := livedebugging.CallbackID(uuid.New().String())
 
-		err := a.CallbackManager.AddCallback(id, componentID, func(data string) {
+		err = a.CallbackManager.AddCallback(id, componentID, func(data string) {
 			select {
 			case <-ctx.Done():
 				return
 			default:
+				if !filter.Match(data) {
+					return
+				}
 				if sampleProb < 1 && rand.Float64() > sampleProb {
 					return
 				}
@@ -159,7 +215,7 @@ func (a *AlloyAPI) liveDebugging() http.HandlerFunc {
 			select {
 			case data := <-dataCh:
 				var builder strings.Builder
-				builder.WriteString(string(data))
+				builder.WriteString(secrets.Scrub(data))
 				// |;| delimiter is added at the end of every chunk
 				builder.WriteString("|;|")
 				_, writeErr := w.Write([]byte(builder.String()))
@@ -175,6 +231,107 @@ func (a *AlloyAPI) liveDebugging() http.HandlerFunc {
 	}
 }
 
+// Limits of the number of items and of the duration of a tap.
+const (
+	defaultTapCount   = 100
+	maxTapCount       = 10000
+	defaultTapTimeout = 30 * time.Second
+	maxTapTimeout     = 5 * time.Minute
+)
+
+// tap captures up to count items of live debugging data from a component, or
+// the items received until timeout, and returns them as a file to download.
+// Only the items matching the optional attributes filter are captured. Likely
+// secrets are redacted from the captured items.
+func (a *AlloyAPI) tap() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		componentID := livedebugging.ComponentID(vars["id"])
+
+		count, err := parseTapParam(r.URL.Query().Get("count"), defaultTapCount, maxTapCount, strconv.Atoi)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid count: %s", err), http.StatusBadRequest)
+			return
+		}
+		timeout, err := parseTapParam(r.URL.Query().Get("timeout"), defaultTapTimeout, maxTapTimeout, time.ParseDuration)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid timeout: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := livedebugging.ParseAttributeFilter(r.URL.Query().Get("attributes"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid attributes: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		// Items received once the buffer is full are dropped, so that the
+		// component is never blocked by the tap.
+		dataCh := make(chan string, count)
+		id := livedebugging.CallbackID(uuid.New().String())
+		err = a.CallbackManager.AddCallback(id, componentID, func(data string) {
+			if !filter.Match(data) {
+				return
+			}
+			select {
+			case dataCh <- data:
+			default:
+			}
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer a.CallbackManager.DeleteCallback(id, componentID)
+
+		items := make([]string, 0, count)
+	capture:
+		for len(items) < count {
+			select {
+			case data := <-dataCh:
+				items = append(items, livedebugging.Redact(data))
+			case <-ctx.Done():
+				break capture
+			}
+		}
+		if r.Context().Err() != nil {
+			// The client went away.
+			return
+		}
+
+		filename := fmt.Sprintf("tap-%s-%s.txt",
+			strings.NewReplacer("/", "_", ".", "_").Replace(string(componentID)),
+			time.Now().UTC().Format("20060102T150405Z"),
+		)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		for _, item := range items {
+			if _, err := fmt.Fprintln(w, strings.TrimRight(item, "\n")); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseTapParam parses a query parameter of a tap. An empty value is replaced
+// by def, and values must be positive and not exceed limit.
+func parseTapParam[T int | time.Duration](value string, def, limit T, parse func(string) (T, error)) (T, error) {
+	if value == "" {
+		return def, nil
+	}
+	v, err := parse(value)
+	if err != nil {
+		return 0, err
+	}
+	if v <= 0 || v > limit {
+		return 0, fmt.Errorf("must be greater than 0 and at most %v", limit)
+	}
+	return v, nil
+}
+
 func setSampleProb(w http.ResponseWriter, sampleProbParam string) (sampleProb float64) {
 	sampleProb = 1.0
 	if sampleProbParam != "" {
@@ -1,6 +1,6 @@
 import { FC, Fragment, ReactElement } from 'react';
 import { Link } from 'react-router-dom';
-import { faBug, faCubes, faLink } from '@fortawesome/free-solid-svg-icons';
+import { faBug, faCubes, faLink, faPause, faPlay } from '@fortawesome/free-solid-svg-icons';
 import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
 
 import { partitionBody } from '../../utils/partition';
@@ -27,6 +27,37 @@ export const ComponentView: FC<ComponentViewProps> = (props) => {
   const exportsPartition = props.component.exports && partitionBody(props.component.exports, 'Exports');
   const debugPartition = props.component.debugInfo && partitionBody(props.component.debugInfo, 'Debug info');
 
+  async function togglePaused(e: React.MouseEvent) {
+    e.preventDefault();
+
+    // The admin token is kept for the session, so that it's only asked once.
+    let token = sessionStorage.getItem('alloyAdminToken');
+    if (token === null) {
+      token = window.prompt('Admin token');
+      if (token === null) {
+        return;
+      }
+    }
+
+    const action = props.component.paused ? 'resume' : 'pause';
+    const id = pathJoin([props.component.moduleID, props.component.localID]);
+    // Request is relative to the <base> tag inside of <head>.
+    const resp = await fetch(`./api/v0/web/components/${id}/${action}`, {
+      method: 'POST',
+      credentials: 'same-origin',
+      headers: { Authorization: `Bearer ${token}` },
+    });
+    if (!resp.ok) {
+      if (resp.status === 401) {
+        sessionStorage.removeItem('alloyAdminToken');
+      }
+      window.alert(`Failed to ${action} the component: ${await resp.text()}`);
+      return;
+    }
+    sessionStorage.setItem('alloyAdminToken', token);
+    window.location.reload();
+  }
+
   function partitionTOC(partition: PartitionedBody): ReactElement {
     return (
       <li>
@@ -106,6 +137,20 @@ export const ComponentView: FC<ComponentViewProps> = (props) => {
           </a>
         </div>
 
+        <div className={styles.pauseLink}>
+          <a href="#" onClick={togglePaused}>
+            {props.component.paused ? (
+              <>
+                <FontAwesomeIcon icon={faPlay} /> Resume
+              </>
+            ) : (
+              <>
+                <FontAwesomeIcon icon={faPause} /> Pause
+              </>
+            )}
+          </a>
+        </div>
+
         {props.component.health.message && (
           <blockquote>
             <h1>
@@ -33,6 +33,12 @@ export interface ComponentInfo {
    */
   health: ComponentHealth;
 
+  /**
+   * Whether the component was paused through the API. Paused components don't
+   * run until they're resumed.
+   */
+  paused?: boolean;
+
   /**
    * IDs of components which are referencing this component.
    */
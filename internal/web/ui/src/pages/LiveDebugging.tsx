@@ -1,7 +1,7 @@
 import { useState } from 'react';
 import { useParams } from 'react-router-dom';
 import AutoScroll from '@brianmcallister/react-auto-scroll';
-import { faBroom, faBug, faCopy, faRoad, faStop } from '@fortawesome/free-solid-svg-icons';
+import { faBroom, faBug, faCopy, faDownload, faRoad, faStop } from '@fortawesome/free-solid-svg-icons';
 import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
 
 import { Field, Input, Slider } from '@grafana/ui';
@@ -18,7 +18,9 @@ function PageLiveDebugging() {
   const [sampleProb, setSampleProb] = useState(1);
   const [sliderProb, setSliderProb] = useState(100);
   const [filterValue, setFilterValue] = useState('');
-  const { loading, error } = useLiveDebugging(String(componentID), enabled, sampleProb, setData);
+  const [attributes, setAttributes] = useState('');
+  const captureCount = 1000;
+  const { loading, error } = useLiveDebugging(String(componentID), enabled, sampleProb, attributes, setData);
 
   const filteredData = data.filter((n) => n.toLowerCase().includes(filterValue.toLowerCase()));
 
@@ -63,6 +65,12 @@ function PageLiveDebugging() {
     }
   }
 
+  // Download the next items of debugging data with secrets redacted. The
+  // download starts once enough items are captured or the capture times out.
+  function downloadCapture() {
+    window.location.assign(`./api/v0/web/tap/${componentID}?count=${captureCount}&attributes=${encodeURIComponent(attributes)}`);
+  }
+
   const samplingControl = (
     <div className={styles.slider}>
       <span className={styles.sliderLabel}>Sample rate</span>
@@ -88,8 +96,30 @@ function PageLiveDebugging() {
     </Field>
   );
 
+  // The attributes filter is applied by Alloy, so that sampling only applies
+  // to the matching items. It's applied when the input loses focus or on
+  // Enter, to avoid reconnecting on every key stroke.
+  function applyAttributes(value: string) {
+    setAttributes(value.trim());
+  }
+
+  const attributesControl = (
+    <Field className={styles.filter}>
+      <Input
+        placeholder="Attributes, e.g. service.name=api,status=500"
+        onBlur={(event) => applyAttributes(event.currentTarget.value)}
+        onKeyDown={(event) => {
+          if (event.key === 'Enter') {
+            applyAttributes(event.currentTarget.value);
+          }
+        }}
+      />
+    </Field>
+  );
+
   const controls = (
     <>
+      {attributesControl}
       {filterControl}
       {samplingControl}
       {toggleEnableButton()}
@@ -103,6 +133,11 @@ function PageLiveDebugging() {
           <FontAwesomeIcon icon={faCopy} /> Copy
         </button>
       </div>
+      <div className={styles.debugLink}>
+        <button className={styles.captureButton} onClick={downloadCapture}>
+          <FontAwesomeIcon icon={faDownload} /> Capture
+        </button>
+      </div>
     </>
   );
 
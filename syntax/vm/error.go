@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -104,3 +105,68 @@ func makeDiagnostic(err error, assoc map[value.Value]ast.Node) error {
 	}
 	return d
 }
+
+// AttributeError can be returned by the Validate method of a block to report
+// an error about one of its attributes. The error is then reported at the
+// position of the attribute in the file rather than the position of the
+// block.
+type AttributeError struct {
+	// Name is the name of the attribute.
+	Name string
+	// Index is the index of the element of a list attribute the error is
+	// about, or -1 if the error is about the whole attribute.
+	Index int
+	Inner error
+}
+
+// Error implements error.
+func (ae AttributeError) Error() string {
+	if ae.Index >= 0 {
+		return fmt.Sprintf("%s[%d]: %s", ae.Name, ae.Index, ae.Inner)
+	}
+	return fmt.Sprintf("%s: %s", ae.Name, ae.Inner)
+}
+
+// Unwrap returns the underlying error.
+func (ae AttributeError) Unwrap() error { return ae.Inner }
+
+// decorateValidateError converts err into a diag.Diagnostic pointing at an
+// attribute of node if err is an AttributeError. Otherwise, err is returned
+// unmodified.
+func decorateValidateError(node ast.Node, err error) error {
+	var ae AttributeError
+	if !errors.As(err, &ae) {
+		return err
+	}
+
+	var body ast.Body
+	switch node := node.(type) {
+	case *ast.BlockStmt:
+		body = node.Body
+	case ast.Body:
+		body = node
+	default:
+		return err
+	}
+
+	for _, stmt := range body {
+		attr, ok := stmt.(*ast.AttributeStmt)
+		if !ok || attr.Name.Name != ae.Name {
+			continue
+		}
+
+		// Point at the element of the list if it's written as a literal.
+		var target ast.Node = attr.Value
+		if arr, ok := attr.Value.(*ast.ArrayExpr); ok && ae.Index >= 0 && ae.Index < len(arr.Elements) {
+			target = arr.Elements[ae.Index]
+		}
+
+		return diag.Diagnostic{
+			Severity: diag.SeverityLevelError,
+			StartPos: ast.StartPos(target).Position(),
+			EndPos:   ast.EndPos(target).Position(),
+			Message:  err.Error(),
+		}
+	}
+	return err
+}
@@ -96,7 +96,7 @@ func (vm *Evaluator) evaluateBlockOrBody(scope *Scope, assoc map[value.Value]ast
 
 	if ru, ok := rv.Interface().(value.Validator); ok {
 		if err := ru.Validate(); err != nil {
-			return err
+			return decorateValidateError(node, err)
 		}
 	}
 
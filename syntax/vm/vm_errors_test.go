@@ -1,6 +1,7 @@
 package vm_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/grafana/alloy/syntax/parser"
@@ -78,3 +79,80 @@ func TestVM_ExprErrors(t *testing.T) {
 		})
 	}
 }
+
+type validatedBlock struct {
+	Name  string   `alloy:"name,attr,optional"`
+	Items []string `alloy:"items,attr,optional"`
+}
+
+func (b *validatedBlock) Validate() error {
+	if b.Name == "invalid" {
+		return vm.AttributeError{Name: "name", Index: -1, Inner: errors.New("invalid name")}
+	}
+	for i, item := range b.Items {
+		if item == "invalid" {
+			return vm.AttributeError{Name: "items", Index: i, Inner: errors.New("invalid item")}
+		}
+	}
+	return nil
+}
+
+func TestVM_ValidateAttributeErrors(t *testing.T) {
+	type Target struct {
+		Block validatedBlock `alloy:"block,block"`
+	}
+
+	tt := []struct {
+		name   string
+		input  string
+		scope  *vm.Scope
+		expect string
+	}{
+		{
+			name: "attribute",
+			input: `
+				block {
+					name = "invalid"
+				}
+			`,
+			expect: `test:3:13: name: invalid name`,
+		},
+		{
+			name: "list element",
+			input: `
+				block {
+					items = [
+						"valid",
+						"invalid",
+					]
+				}
+			`,
+			expect: `test:5:7: items[1]: invalid item`,
+		},
+		{
+			name: "list from expression",
+			input: `
+				block {
+					items = values
+				}
+			`,
+			scope: &vm.Scope{
+				Variables: map[string]interface{}{
+					"values": []string{"invalid"},
+				},
+			},
+			expect: `test:3:14: items[0]: invalid item`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := parser.ParseFile("test", []byte(tc.input))
+			require.NoError(t, err)
+
+			eval := vm.New(res)
+			err = eval.Evaluate(tc.scope, &Target{})
+			require.EqualError(t, err, tc.expect)
+		})
+	}
+}